@@ -8,6 +8,7 @@ import (
 	"syscall"
 
 	appcli "github.com/jinford/dev-rag/internal/app/cli"
+	"github.com/jinford/dev-rag/internal/platform/config"
 	"github.com/jinford/dev-rag/internal/platform/logger"
 	"github.com/urfave/cli/v3"
 )
@@ -56,31 +57,1705 @@ func main() {
 						},
 						Action: appcli.ProductShowAction,
 					},
+					{
+						Name:  "set-recency-weight",
+						Usage: "検索スコアに鮮度（チャンクの最終更新日時）を加味する重みを設定",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "env",
+								Usage: "環境変数ファイルパス",
+								Value: ".env",
+							},
+							&cli.StringFlag{
+								Name:     "name",
+								Usage:    "プロダクト名",
+								Required: true,
+							},
+							&cli.FloatFlag{
+								Name:  "weight",
+								Usage: "鮮度の重み 0〜1（運用系の質問が多いプロダクトで大きくする）",
+							},
+							&cli.BoolFlag{
+								Name:  "unset",
+								Usage: "設定を解除して鮮度を加味しない",
+							},
+						},
+						Action: appcli.ProductSetRecencyWeightAction,
+					},
+					{
+						Name:  "config",
+						Usage: "プロダクトごとの設定（カバレッジアラートの閾値など）を管理",
+						Commands: []*cli.Command{
+							{
+								Name:  "set",
+								Usage: "設定を保存",
+								Flags: []cli.Flag{
+									&cli.StringFlag{
+										Name:  "env",
+										Usage: "環境変数ファイルパス",
+										Value: ".env",
+									},
+									&cli.StringFlag{
+										Name:     "name",
+										Usage:    "プロダクト名",
+										Required: true,
+									},
+									&cli.StringFlag{
+										Name:     "key",
+										Usage:    "設定キー（alert.min_coverage.<domain>, alert.max_unindexed_important_files）",
+										Required: true,
+									},
+									&cli.StringFlag{
+										Name:     "value",
+										Usage:    "設定値",
+										Required: true,
+									},
+								},
+								Action: appcli.ProductConfigSetAction,
+							},
+							{
+								Name:  "unset",
+								Usage: "設定を削除して既定値に戻す",
+								Flags: []cli.Flag{
+									&cli.StringFlag{
+										Name:  "env",
+										Usage: "環境変数ファイルパス",
+										Value: ".env",
+									},
+									&cli.StringFlag{
+										Name:     "name",
+										Usage:    "プロダクト名",
+										Required: true,
+									},
+									&cli.StringFlag{
+										Name:     "key",
+										Usage:    "設定キー（alert.min_coverage.<domain>, alert.max_unindexed_important_files）",
+										Required: true,
+									},
+								},
+								Action: appcli.ProductConfigUnsetAction,
+							},
+							{
+								Name:  "list",
+								Usage: "設定と設定可能なキーを表示",
+								Flags: []cli.Flag{
+									&cli.StringFlag{
+										Name:  "env",
+										Usage: "環境変数ファイルパス",
+										Value: ".env",
+									},
+									&cli.StringFlag{
+										Name:     "name",
+										Usage:    "プロダクト名",
+										Required: true,
+									},
+								},
+								Action: appcli.ProductConfigListAction,
+							},
+						},
+					},
+				},
+			},
+			{
+				Name:  "source",
+				Usage: "ソース管理コマンド",
+				Commands: []*cli.Command{
+					{
+						Name:  "list",
+						Usage: "ソース一覧を表示",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "env",
+								Usage: "環境変数ファイルパス",
+								Value: ".env",
+							},
+							&cli.StringFlag{
+								Name:  "product",
+								Usage: "プロダクト名（絞り込み）",
+							},
+						},
+						Action: appcli.SourceListAction,
+					},
+					{
+						Name:  "show",
+						Usage: "ソース詳細を表示",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "env",
+								Usage: "環境変数ファイルパス",
+								Value: ".env",
+							},
+							&cli.StringFlag{
+								Name:     "name",
+								Usage:    "ソース名",
+								Required: true,
+							},
+						},
+						Action: appcli.SourceShowAction,
+					},
+					{
+						Name:  "refs",
+						Usage: "ソースで追跡しているブランチ/タグとスナップショットを表示",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "env",
+								Usage: "環境変数ファイルパス",
+								Value: ".env",
+							},
+							&cli.StringFlag{
+								Name:     "name",
+								Usage:    "ソース名",
+								Required: true,
+							},
+						},
+						Action: appcli.SourceRefsAction,
+					},
+				},
+			},
+			{
+				Name:  "snapshot",
+				Usage: "スナップショット管理コマンド",
+				Commands: []*cli.Command{
+					{
+						Name:  "list",
+						Usage: "ソースのスナップショット一覧を表示",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "env",
+								Usage: "環境変数ファイルパス",
+								Value: ".env",
+							},
+							&cli.StringFlag{
+								Name:     "source",
+								Usage:    "ソース名",
+								Required: true,
+							},
+						},
+						Action: appcli.SnapshotListAction,
+					},
+					{
+						Name:  "show",
+						Usage: "スナップショット詳細を表示",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "env",
+								Usage: "環境変数ファイルパス",
+								Value: ".env",
+							},
+							&cli.StringFlag{
+								Name:     "id",
+								Usage:    "スナップショットID",
+								Required: true,
+							},
+						},
+						Action: appcli.SnapshotShowAction,
+					},
+					{
+						Name:  "diff",
+						Usage: "同じソースの2つのスナップショットのファイル・シンボルの差分を表示（保存済みのメタデータから比較）",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "env",
+								Usage: "環境変数ファイルパス",
+								Value: ".env",
+							},
+							&cli.StringFlag{
+								Name:     "source",
+								Usage:    "ソース名",
+								Required: true,
+							},
+							&cli.StringFlag{
+								Name:     "from",
+								Usage:    "比較元（ブランチ/タグ、スナップショットID、またはコミットハッシュ）",
+								Required: true,
+							},
+							&cli.StringFlag{
+								Name:     "to",
+								Usage:    "比較先（ブランチ/タグ、スナップショットID、またはコミットハッシュ）",
+								Required: true,
+							},
+							&cli.BoolFlag{
+								Name:  "json",
+								Usage: "結果をJSONで出力",
+							},
+						},
+						Action: appcli.SnapshotDiffAction,
+					},
+				},
+			},
+			{
+				Name:  "run",
+				Usage: "インデックス化の実行履歴コマンド",
+				Commands: []*cli.Command{
+					{
+						Name:  "list",
+						Usage: "インデックス化の実行履歴（所要時間・処理件数・失敗数・トークン消費）を新しい順に表示",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "env",
+								Usage: "環境変数ファイルパス",
+								Value: ".env",
+							},
+							&cli.StringFlag{
+								Name:  "product",
+								Usage: "プロダクト名（省略時は全プロダクト）",
+							},
+							&cli.StringFlag{
+								Name:  "source",
+								Usage: "ソース名で絞り込み",
+							},
+							&cli.StringFlag{
+								Name:  "status",
+								Usage: "実行状態で絞り込み（running | succeeded | failed）",
+							},
+							&cli.IntFlag{
+								Name:  "limit",
+								Usage: "表示する件数",
+								Value: 20,
+							},
+							&cli.BoolFlag{
+								Name:  "json",
+								Usage: "結果をJSONで出力",
+							},
+						},
+						Action: appcli.RunListAction,
+					},
+					{
+						Name:  "show",
+						Usage: "インデックス化の実行の詳細を表示",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "env",
+								Usage: "環境変数ファイルパス",
+								Value: ".env",
+							},
+							&cli.StringFlag{
+								Name:     "id",
+								Usage:    "実行ID",
+								Required: true,
+							},
+							&cli.BoolFlag{
+								Name:  "json",
+								Usage: "結果をJSONで出力",
+							},
+						},
+						Action: appcli.RunShowAction,
+					},
+				},
+			},
+			{
+				Name:  "failures",
+				Usage: "インデックス化に失敗したファイル・チャンクの記録コマンド",
+				Commands: []*cli.Command{
+					{
+						Name:  "list",
+						Usage: "インデックス化に失敗したファイルと Embedding を生成できなかったチャンクを失敗回数とエラーとともに表示",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "env",
+								Usage: "環境変数ファイルパス",
+								Value: ".env",
+							},
+							&cli.StringFlag{
+								Name:     "product",
+								Usage:    "プロダクト名",
+								Required: true,
+							},
+							&cli.BoolFlag{
+								Name:  "json",
+								Usage: "結果をJSONで出力",
+							},
+						},
+						Action: appcli.FailuresListAction,
+					},
+					{
+						Name:  "retry",
+						Usage: "インデックス化に失敗したファイルを記録した内容から再度インデックス化し、チャンクの Embedding を再生成",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "env",
+								Usage: "環境変数ファイルパス",
+								Value: ".env",
+							},
+							&cli.StringFlag{
+								Name:     "product",
+								Usage:    "プロダクト名",
+								Required: true,
+							},
+						},
+						Action: appcli.FailuresRetryAction,
+					},
+				},
+			},
+			{
+				Name:  "chunk",
+				Usage: "チャンク参照コマンド",
+				Commands: []*cli.Command{
+					{
+						Name:  "history",
+						Usage: "チャンクのスナップショットごとのバージョン履歴と差分を表示",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "env",
+								Usage: "環境変数ファイルパス",
+								Value: ".env",
+							},
+							&cli.StringFlag{
+								Name:     "chunk-key",
+								Usage:    "チャンクキー（{product}/{source}/{path}#L{start}-L{end}:{ordinal}@{commit}）",
+								Required: true,
+							},
+							&cli.BoolFlag{
+								Name:  "diff",
+								Usage: "前バージョンとの内容の差分を表示",
+								Value: true,
+							},
+						},
+						Action: appcli.ChunkHistoryAction,
+					},
+					{
+						Name:  "show",
+						Usage: "チャンクの内容・メタデータ・階層・依存関係・重要度・Embeddingモデルを表示",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "env",
+								Usage: "環境変数ファイルパス",
+								Value: ".env",
+							},
+							&cli.StringFlag{
+								Name:  "id",
+								Usage: "チャンクID（--chunk-key とどちらか一方を指定）",
+							},
+							&cli.StringFlag{
+								Name:  "chunk-key",
+								Usage: "チャンクキー（{product}/{source}/{path}#L{start}-L{end}:{ordinal}@{commit}）",
+							},
+							&cli.BoolFlag{
+								Name:  "content",
+								Usage: "チャンクの内容を表示",
+								Value: true,
+							},
+							&cli.IntFlag{
+								Name:  "context",
+								Usage: "保存したファイルの内容（原文）からチャンクの前後に表示する行数（0 の場合は表示しない）",
+							},
+						},
+						Action: appcli.ChunkShowAction,
+					},
+				},
+			},
+			{
+				Name:  "file",
+				Usage: "ファイル参照コマンド",
+				Commands: []*cli.Command{
+					{
+						Name:  "show",
+						Usage: "インデックス済みのファイルのメタデータとチャンクの一覧を表示",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "env",
+								Usage: "環境変数ファイルパス",
+								Value: ".env",
+							},
+							&cli.StringFlag{
+								Name:     "source",
+								Usage:    "ソース名",
+								Required: true,
+							},
+							&cli.StringFlag{
+								Name:     "path",
+								Usage:    "リポジトリ内のファイルパス",
+								Required: true,
+							},
+							&cli.StringFlag{
+								Name:  "version",
+								Usage: "スナップショットのバージョン（省略時は最新のインデックス済みスナップショット）",
+							},
+							&cli.BoolFlag{
+								Name:  "content",
+								Usage: "保存したファイルの内容（原文、PDF・Office文書は抽出したテキスト）を表示",
+							},
+						},
+						Action: appcli.FileShowAction,
+					},
+				},
+			},
+			{
+				Name:  "index",
+				Usage: "インデックス管理コマンド",
+				Commands: []*cli.Command{
+					{
+						Name:  "stats",
+						Usage: "プロダクトの各ソースの最新スナップショットのインデックス品質を診断",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "env",
+								Usage: "環境変数ファイルパス",
+								Value: ".env",
+							},
+							&cli.StringFlag{
+								Name:     "product",
+								Usage:    "プロダクト名",
+								Required: true,
+							},
+							&cli.IntFlag{
+								Name:  "token-limit",
+								Usage: "上限超過として数えるチャンクのトークン数（未指定時はチャンク化の最大トークン数）",
+							},
+						},
+						Action: appcli.IndexStatsAction,
+					},
+					{
+						Name:  "batch",
+						Usage: "ソース一覧ファイルに記載した複数のGitソースを並行してインデックス化",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "env",
+								Usage: "環境変数ファイルパス",
+								Value: ".env",
+							},
+							&cli.StringFlag{
+								Name:     "file",
+								Usage:    "インデックス対象のソース一覧（YAML）",
+								Required: true,
+							},
+							&cli.IntFlag{
+								Name:  "concurrency",
+								Usage: "同時にインデックス化するソース・参照の数",
+								Value: 2,
+							},
+							&cli.BoolFlag{
+								Name:  "generate-wiki",
+								Usage: "インデックス完了後にWikiを自動生成",
+							},
+						},
+						Action: appcli.IndexBatchAction,
+					},
+					{
+						Name:  "retry-embeddings",
+						Usage: "インデックス化で Embedding を生成できなかったチャンクの Embedding を再生成",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "env",
+								Usage: "環境変数ファイルパス",
+								Value: ".env",
+							},
+							&cli.StringFlag{
+								Name:     "product",
+								Usage:    "プロダクト名",
+								Required: true,
+							},
+						},
+						Action: appcli.IndexRetryEmbeddingsAction,
+					},
+					{
+						Name:  "reembed",
+						Usage: "Embedding モデル・次元数の変更後に、Embedding がない・別のモデルで生成したチャンクと要約の Embedding を再生成",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "env",
+								Usage: "環境変数ファイルパス",
+								Value: ".env",
+							},
+							&cli.StringFlag{
+								Name:     "product",
+								Usage:    "プロダクト名",
+								Required: true,
+							},
+						},
+						Action: appcli.IndexReembedAction,
+					},
+					{
+						Name:  "rechunk",
+						Usage: "チャンカーの変更後に、保存済みのファイルの内容を再チャンク化（内容が変わらないチャンクの Embedding は再利用）",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "env",
+								Usage: "環境変数ファイルパス",
+								Value: ".env",
+							},
+							&cli.StringFlag{
+								Name:     "product",
+								Usage:    "プロダクト名",
+								Required: true,
+							},
+							&cli.BoolFlag{
+								Name:  "outdated",
+								Usage: "現在のチャンカー以外（バージョンの記録がないものを含む）で作成したチャンクがあるファイルのみを再チャンク化",
+							},
+						},
+						Action: appcli.IndexRechunkAction,
+					},
+					{
+						Name:  "embed-models",
+						Usage: "追加の Embedding モデル（EMBEDDING_ADDITIONAL_MODELS）で、そのモデルの Embedding がないチャンクの Embedding を生成",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "env",
+								Usage: "環境変数ファイルパス",
+								Value: ".env",
+							},
+							&cli.StringFlag{
+								Name:     "product",
+								Usage:    "プロダクト名",
+								Required: true,
+							},
+							&cli.StringFlag{
+								Name:  "model",
+								Usage: "生成するモデル（省略時は追加のモデルすべて）",
+							},
+						},
+						Action: appcli.IndexEmbedModelsAction,
+					},
+					{
+						Name:  "git",
+						Usage: "Gitソースをインデックス化",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "env",
+								Usage: "環境変数ファイルパス",
+								Value: ".env",
+							},
+							&cli.StringFlag{
+								Name:     "url",
+								Usage:    "GitリポジトリURL",
+								Required: true,
+							},
+							&cli.StringFlag{
+								Name:     "product",
+								Usage:    "プロダクト名（存在しない場合は自動作成）",
+								Required: true,
+							},
+							&cli.StringSliceFlag{
+								Name:  "ref",
+								Usage: "ブランチ名またはタグ名（複数指定時は並行してインデックス化、省略時はリモートのdefault_branch）",
+							},
+							&cli.StringFlag{
+								Name:  "since-commit",
+								Usage: "インデックス化済みのコミットからの差分だけを取得してインデックス化（変更のないファイルは保存済みの内容と Embedding を引き継ぐ）",
+							},
+							&cli.StringFlag{
+								Name:  "subtree",
+								Usage: "インデックス化の対象をリポジトリ内のディレクトリ（モノレポのサービスなど）に限定し、別のソース（<ソース名>//<パス>）として取り込む",
+							},
+							&cli.BoolFlag{
+								Name:  "force-init",
+								Usage: "強制的にフルインデックスを実行",
+							},
+							&cli.StringFlag{
+								Name:  "coverage",
+								Usage: "インデックス化したスナップショットに取り込むテストのカバレッジレポート（Go の coverage.out・LCOV）",
+							},
+							&cli.BoolFlag{
+								Name:  "generate-wiki",
+								Usage: "インデックス完了後にWikiを自動生成",
+							},
+						},
+						Action: appcli.SourceIndexGitAction,
+					},
+					{
+						Name:  "dir",
+						Usage: "ローカルディレクトリをインデックス化（Gitリモート不要）",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "env",
+								Usage: "環境変数ファイルパス",
+								Value: ".env",
+							},
+							&cli.StringFlag{
+								Name:     "path",
+								Usage:    "インデックス対象のディレクトリ",
+								Required: true,
+							},
+							&cli.StringFlag{
+								Name:     "product",
+								Usage:    "プロダクト名（存在しない場合は自動作成）",
+								Required: true,
+							},
+							&cli.BoolFlag{
+								Name:  "force-init",
+								Usage: "強制的にフルインデックスを実行",
+							},
+							&cli.StringFlag{
+								Name:  "coverage",
+								Usage: "インデックス化したスナップショットに取り込むテストのカバレッジレポート（Go の coverage.out・LCOV）",
+							},
+							&cli.BoolFlag{
+								Name:  "generate-wiki",
+								Usage: "インデックス完了後にWikiを自動生成",
+							},
+						},
+						Action: appcli.SourceIndexDirAction,
+					},
+					{
+						Name:  "discussions",
+						Usage: "GitHub・GitLab の Issue・Pull Request の議論をインデックス化",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "env",
+								Usage: "環境変数ファイルパス",
+								Value: ".env",
+							},
+							&cli.StringFlag{
+								Name:     "url",
+								Usage:    "リポジトリのURL（例: https://github.com/owner/repo）",
+								Required: true,
+							},
+							&cli.StringFlag{
+								Name:     "product",
+								Usage:    "プロダクト名（存在しない場合は自動作成）",
+								Required: true,
+							},
+							&cli.StringFlag{
+								Name:  "forge",
+								Usage: "取得元のサービス（github | gitlab、省略時はURLのホストから判定）",
+							},
+							&cli.IntFlag{
+								Name:  "max-threads",
+								Usage: "取得する Issue・Pull Request の上限（最終更新日時の新しい順）",
+								Value: 500,
+							},
+							&cli.BoolFlag{
+								Name:  "force-init",
+								Usage: "強制的にフルインデックスを実行",
+							},
+						},
+						Action: appcli.SourceIndexDiscussionsAction,
+					},
+				},
+			},
+			{
+				Name:  "summaries",
+				Usage: "要約の管理",
+				Commands: []*cli.Command{
+					{
+						Name:  "refresh",
+						Usage: "内容が変わった・プロンプトのバージョンが古いファイルの要約のみを再生成",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "env",
+								Usage: "環境変数ファイルパス",
+								Value: ".env",
+							},
+							&cli.StringFlag{
+								Name:     "product",
+								Usage:    "プロダクト名（各ソースの最新のインデックス済みスナップショットを対象）",
+								Required: true,
+							},
+							&cli.IntFlag{
+								Name:  "concurrency",
+								Usage: "ファイル要約を並列に生成する数（省略時は5）",
+							},
+							&cli.FloatFlag{
+								Name:  "budget-usd",
+								Usage: "推定コストの上限（USD、LLM_BUDGET_USD を上書き、0 は無制限）",
+							},
+							&cli.BoolFlag{
+								Name:  "dry-run",
+								Usage: "再生成が必要なファイルを表示するのみ",
+							},
+							&cli.BoolFlag{
+								Name:  "files-only",
+								Usage: "ディレクトリ・アーキテクチャ要約を更新しない",
+							},
+						},
+						Action: appcli.SummariesRefreshAction,
+					},
+				},
+			},
+			{
+				Name:  "wiki",
+				Usage: "Wiki生成コマンド",
+				Commands: []*cli.Command{
+					{
+						Name:  "generate",
+						Usage: "プロダクト単位でWikiを生成",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "env",
+								Usage: "環境変数ファイルパス",
+								Value: ".env",
+							},
+							&cli.StringFlag{
+								Name:     "product",
+								Usage:    "プロダクト名",
+								Required: true,
+							},
+							&cli.StringFlag{
+								Name:  "out",
+								Usage: "出力ディレクトリ（省略時は /var/lib/dev-rag/wikis/<プロダクト名>）",
+							},
+							&cli.StringFlag{
+								Name:  "config",
+								Usage: "Wiki生成設定ファイル（省略時はデフォルト設定）",
+							},
+							&cli.StringSliceFlag{
+								Name:  "section",
+								Usage: "生成するセクションID（onboarding など、複数指定可、省略時は全セクション）",
+							},
+							&cli.BoolFlag{
+								Name:  "strict",
+								Usage: "解決できない参照（ページ間リンク・ファイルパス・行番号）が --max-broken を超えた場合に失敗する",
+							},
+							&cli.IntFlag{
+								Name:  "max-broken",
+								Usage: "--strict 指定時に許容する解決できない参照の数",
+								Value: 0,
+							},
+							&cli.IntFlag{
+								Name:  "concurrency",
+								Usage: "セクションを並行して生成する数（省略時は4、depends_on の依存先の完了を待って生成）",
+							},
+							&cli.StringSliceFlag{
+								Name:  "lang",
+								Usage: "生成する言語（ja,en のようにカンマ区切り、指定時は <出力ディレクトリ>/<言語>/ に出力、ja 以外は日本語のページを翻訳）",
+							},
+						},
+						Action: appcli.WikiGenerateAction,
+					},
+					{
+						Name:  "config",
+						Usage: "Wiki生成設定を表示（--config 指定時は検証して既定値を補完した結果を表示）",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "config",
+								Usage: "検証するWiki生成設定ファイル（省略時はデフォルト設定を表示）",
+							},
+						},
+						Action: appcli.WikiConfigAction,
+					},
+					{
+						Name:  "publish",
+						Usage: "生成済みのWikiを外部に公開",
+						Commands: []*cli.Command{
+							{
+								Name:  "confluence",
+								Usage: "Confluence のスペースにページを作成・更新（公開済みのページIDを記録して冪等に更新）",
+								Flags: []cli.Flag{
+									&cli.StringFlag{
+										Name:  "env",
+										Usage: "環境変数ファイルパス",
+										Value: ".env",
+									},
+									&cli.StringFlag{
+										Name:     "product",
+										Usage:    "プロダクト名",
+										Required: true,
+									},
+									&cli.StringFlag{
+										Name:     "space",
+										Usage:    "公開先のスペースキー",
+										Required: true,
+									},
+									&cli.StringFlag{
+										Name:  "dir",
+										Usage: "生成済みWikiのディレクトリ（省略時は WIKI_OUTPUT_DIR/<プロダクト名>、--lang 指定時は WIKI_OUTPUT_DIR/<プロダクト名>/<言語>）",
+									},
+									&cli.StringFlag{
+										Name:  "lang",
+										Usage: "公開するWikiの言語（wiki generate --lang で生成した言語、ja 以外はページタイトルに言語を付ける）",
+									},
+									&cli.StringFlag{
+										Name:  "parent-id",
+										Usage: "ルートページを配置する親ページのID（省略時はスペースの最上位）",
+									},
+									&cli.BoolFlag{
+										Name:  "prune",
+										Usage: "ローカルに存在しなくなったページを Confluence から削除",
+									},
+									&cli.BoolFlag{
+										Name:  "dry-run",
+										Usage: "Confluence とDBを変更せず、実行内容のみを表示",
+									},
+								},
+								Action: appcli.WikiPublishConfluenceAction,
+							},
+							{
+								Name:  "git",
+								Usage: "Gitリポジトリ（またはソースのリポジトリの Wiki）にコミット・プッシュ",
+								Flags: []cli.Flag{
+									&cli.StringFlag{
+										Name:  "env",
+										Usage: "環境変数ファイルパス",
+										Value: ".env",
+									},
+									&cli.StringFlag{
+										Name:     "product",
+										Usage:    "プロダクト名",
+										Required: true,
+									},
+									&cli.StringFlag{
+										Name:  "repo",
+										Usage: "公開先のリポジトリURL",
+									},
+									&cli.StringFlag{
+										Name:  "wiki-of",
+										Usage: "Wikiに公開するGitソース名（GitHub/GitLab の <リポジトリ>.wiki.git に公開）",
+									},
+									&cli.StringFlag{
+										Name:  "branch",
+										Usage: "公開先のブランチ（省略時はデフォルトブランチ、存在しない場合は作成）",
+									},
+									&cli.StringFlag{
+										Name:  "path",
+										Usage: "リポジトリ内の配置先ディレクトリ（省略時は --repo なら wiki/<プロダクト名>、--wiki-of ならルート、--lang 指定時は末尾に /<言語> を付ける）",
+									},
+									&cli.StringFlag{
+										Name:  "dir",
+										Usage: "生成済みWikiのディレクトリ（省略時は WIKI_OUTPUT_DIR/<プロダクト名>、--lang 指定時は WIKI_OUTPUT_DIR/<プロダクト名>/<言語>）",
+									},
+									&cli.StringFlag{
+										Name:  "lang",
+										Usage: "公開するWikiの言語（wiki generate --lang で生成した言語）",
+									},
+									&cli.BoolFlag{
+										Name:  "dry-run",
+										Usage: "コミット・プッシュせず、変更されるページのみを表示",
+									},
+								},
+								Action: appcli.WikiPublishGitAction,
+							},
+						},
+					},
+				},
+			},
+			{
+				Name:  "glossary",
+				Usage: "用語集管理コマンド",
+				Commands: []*cli.Command{
+					{
+						Name:  "extract",
+						Usage: "インデックス済みのドキュメント・コードから用語集を抽出",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "env",
+								Usage: "環境変数ファイルパス",
+								Value: ".env",
+							},
+							&cli.StringFlag{
+								Name:     "product",
+								Usage:    "プロダクト名",
+								Required: true,
+							},
+						},
+						Action: appcli.GlossaryExtractAction,
+					},
+					{
+						Name:  "list",
+						Usage: "用語集を表示",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "env",
+								Usage: "環境変数ファイルパス",
+								Value: ".env",
+							},
+							&cli.StringFlag{
+								Name:     "product",
+								Usage:    "プロダクト名",
+								Required: true,
+							},
+						},
+						Action: appcli.GlossaryListAction,
+					},
+				},
+			},
+			{
+				Name:  "release-notes",
+				Usage: "Gitソースの2つの ref の間の変更からリリースノートの下書きを生成",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "env",
+						Usage: "環境変数ファイルパス",
+						Value: ".env",
+					},
+					&cli.StringFlag{
+						Name:     "source",
+						Usage:    "ソース名",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:     "from",
+						Usage:    "比較元の ref（タグ・ブランチ・コミットハッシュ）",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:     "to",
+						Usage:    "比較先の ref（タグ・ブランチ・コミットハッシュ）",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:  "output",
+						Usage: "リリースノートを書き出すファイルパス（未指定の場合は標準出力）",
+					},
+					&cli.BoolFlag{
+						Name:  "wiki",
+						Usage: "プロダクトのリリースノートをWikiの出力ディレクトリの release-notes.md にまとめる",
+					},
+				},
+				Action: appcli.ReleaseNotesAction,
+			},
+			{
+				Name:  "quality",
+				Usage: "品質フィードバック管理コマンド",
+				Commands: []*cli.Command{
+					{
+						Name:  "note",
+						Usage: "RAG回答の品質ノートの管理",
+						Commands: []*cli.Command{
+							{
+								Name:  "add",
+								Usage: "品質ノートを記録",
+								Flags: []cli.Flag{
+									&cli.StringFlag{
+										Name:  "env",
+										Usage: "環境変数ファイルパス",
+										Value: ".env",
+									},
+									&cli.StringFlag{
+										Name:     "severity",
+										Usage:    "深刻度（critical/high/medium/low）",
+										Required: true,
+									},
+									&cli.StringFlag{
+										Name:     "text",
+										Usage:    "問題の内容",
+										Required: true,
+									},
+									&cli.StringFlag{
+										Name:     "reviewer",
+										Usage:    "レビュー者",
+										Required: true,
+									},
+									&cli.StringSliceFlag{
+										Name:  "file",
+										Usage: "関連ファイルパス（複数指定可）",
+									},
+									&cli.StringSliceFlag{
+										Name:  "chunk",
+										Usage: "関連チャンクID（複数指定可）",
+									},
+									&cli.StringFlag{
+										Name:  "id",
+										Usage: "品質ノートの識別子（省略時は QN-YYYY-NNN を採番）",
+									},
+								},
+								Action: appcli.QualityNoteAddAction,
+							},
+							{
+								Name:  "list",
+								Usage: "品質ノートを新しい順に表示",
+								Flags: []cli.Flag{
+									&cli.StringFlag{
+										Name:  "env",
+										Usage: "環境変数ファイルパス",
+										Value: ".env",
+									},
+									&cli.StringFlag{
+										Name:  "status",
+										Usage: "ステータスで絞り込み（open/resolved）",
+									},
+									&cli.StringFlag{
+										Name:  "severity",
+										Usage: "深刻度で絞り込み（critical/high/medium/low）",
+									},
+									&cli.IntFlag{
+										Name:  "limit",
+										Usage: "表示する最大件数",
+										Value: 100,
+									},
+								},
+								Action: appcli.QualityNoteListAction,
+							},
+						},
+					},
+					{
+						Name:  "actions",
+						Usage: "品質改善アクションのバックログの管理",
+						Commands: []*cli.Command{
+							{
+								Name:  "generate",
+								Usage: "対象週の未解決の品質ノートから品質改善アクションを生成してバックログに登録",
+								Flags: []cli.Flag{
+									&cli.StringFlag{
+										Name:  "env",
+										Usage: "環境変数ファイルパス",
+										Value: ".env",
+									},
+									&cli.StringFlag{
+										Name:  "week",
+										Usage: "対象週（2025-W02 などのISO週、または週に含まれる日付 YYYY-MM-DD、省略時は今週）",
+									},
+									&cli.StringFlag{
+										Name:  "product",
+										Usage: "CODEOWNERS と最近の変更を参照するプロダクト名（省略時は参照しない）",
+									},
+								},
+								Action: appcli.QualityActionsGenerateAction,
+							},
+							{
+								Name:  "list",
+								Usage: "品質改善アクションを優先度順に表示",
+								Flags: []cli.Flag{
+									&cli.StringFlag{
+										Name:  "env",
+										Usage: "環境変数ファイルパス",
+										Value: ".env",
+									},
+									&cli.StringFlag{
+										Name:  "status",
+										Usage: "ステータスで絞り込み（open/noop/completed）",
+									},
+									&cli.IntFlag{
+										Name:  "limit",
+										Usage: "表示する最大件数",
+										Value: 100,
+									},
+								},
+								Action: appcli.QualityActionsListAction,
+							},
+						},
+					},
+				},
+			},
+			{
+				Name:  "coverage",
+				Usage: "カバレッジ管理コマンド",
+				Commands: []*cli.Command{
+					{
+						Name:  "trend",
+						Usage: "スナップショットごとのドメイン別カバレッジの推移を表示",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "env",
+								Usage: "環境変数ファイルパス",
+								Value: ".env",
+							},
+							&cli.StringFlag{
+								Name:     "product",
+								Usage:    "プロダクト名",
+								Required: true,
+							},
+							&cli.StringFlag{
+								Name:  "domain",
+								Usage: "ドメインで絞り込み（code/tests/ops/infra/architecture/unknown）",
+							},
+							&cli.StringFlag{
+								Name:  "since",
+								Usage: "この日付（YYYY-MM-DD）以降の記録のみを表示",
+							},
+						},
+						Action: appcli.CoverageTrendAction,
+					},
+					{
+						Name:  "import",
+						Usage: "テストのカバレッジレポート（Go の coverage.out・LCOV）をスナップショットに取り込む",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "env",
+								Usage: "環境変数ファイルパス",
+								Value: ".env",
+							},
+							&cli.StringFlag{
+								Name:     "source",
+								Usage:    "ソース名",
+								Required: true,
+							},
+							&cli.StringFlag{
+								Name:     "file",
+								Usage:    "カバレッジレポートのファイルパス",
+								Required: true,
+							},
+							&cli.StringFlag{
+								Name:  "format",
+								Usage: "カバレッジレポートの形式（go | lcov、省略時は内容から判定）",
+							},
+							&cli.StringFlag{
+								Name:  "snapshot",
+								Usage: "取り込み先のスナップショット（ID・Git の参照・バージョン、省略時は最新のインデックス済みスナップショット）",
+							},
+							&cli.BoolFlag{
+								Name:  "json",
+								Usage: "取り込み結果を JSON で出力",
+							},
+						},
+						Action: appcli.CoverageImportAction,
+					},
+				},
+			},
+			{
+				Name:  "api",
+				Usage: "API定義（OpenAPI・Protocol Buffers）のエンドポイント参照コマンド",
+				Commands: []*cli.Command{
+					{
+						Name:  "list",
+						Usage: "インデックス化時に抽出したHTTP・gRPCのエンドポイントを表示",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "env",
+								Usage: "環境変数ファイルパス",
+								Value: ".env",
+							},
+							&cli.StringFlag{
+								Name:     "product",
+								Usage:    "プロダクト名",
+								Required: true,
+							},
+							&cli.StringFlag{
+								Name:  "kind",
+								Usage: "エンドポイントの種類で絞り込み（http | grpc）",
+							},
+							&cli.StringFlag{
+								Name:  "keyword",
+								Usage: "パス・サービス・operationId・概要の部分一致で絞り込み",
+							},
+						},
+						Action: appcli.APIListAction,
+					},
+				},
+			},
+			{
+				Name:  "deps",
+				Usage: "外部の依存関係（Go モジュール・npm・Python パッケージ・サブモジュール）の参照コマンド",
+				Commands: []*cli.Command{
+					{
+						Name:  "list",
+						Usage: "インデックス化時に依存関係のマニフェストから抽出した外部の依存関係を表示",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "env",
+								Usage: "環境変数ファイルパス",
+								Value: ".env",
+							},
+							&cli.StringFlag{
+								Name:     "product",
+								Usage:    "プロダクト名",
+								Required: true,
+							},
+							&cli.StringFlag{
+								Name:  "ecosystem",
+								Usage: "エコシステムで絞り込み（go | npm | pypi | submodule）",
+							},
+							&cli.BoolFlag{
+								Name:  "changes",
+								Usage: "直前のインデックス済みスナップショットからのバージョンの変化を表示",
+							},
+						},
+						Action: appcli.DepsListAction,
+					},
+				},
+			},
+			{
+				Name:  "services",
+				Usage: "モノレポのサービス（go.mod・package.json の workspaces・Bazel のターゲット）の参照・マッピングコマンド",
+				Commands: []*cli.Command{
+					{
+						Name:  "list",
+						Usage: "インデックス化時にモノレポから検出したサービスの境界と、マッピングしたプロダクトを表示",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "env",
+								Usage: "環境変数ファイルパス",
+								Value: ".env",
+							},
+							&cli.StringFlag{
+								Name:     "product",
+								Usage:    "プロダクト名",
+								Required: true,
+							},
+							&cli.StringFlag{
+								Name:  "kind",
+								Usage: "検出した方法で絞り込み（go | npm | bazel）",
+							},
+						},
+						Action: appcli.ServicesListAction,
+					},
+					{
+						Name:  "map",
+						Usage: "サービスのサブツリーをサービスごとのプロダクト（<プロダクト名>-<パス>）としてインデックス化し、ask・wiki をサービス単位で使えるようにする",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "env",
+								Usage: "環境変数ファイルパス",
+								Value: ".env",
+							},
+							&cli.StringFlag{
+								Name:     "product",
+								Usage:    "サービスを検出したプロダクト名",
+								Required: true,
+							},
+							&cli.StringSliceFlag{
+								Name:  "service",
+								Usage: "マッピングするサービス（名前またはディレクトリ、複数指定可、省略時はすべて）",
+							},
+							&cli.StringFlag{
+								Name:  "ref",
+								Usage: "ブランチ名またはタグ名（省略時はリモートのdefault_branch）",
+							},
+							&cli.BoolFlag{
+								Name:  "generate-wiki",
+								Usage: "インデックス完了後にサービスごとのWikiを自動生成",
+							},
+							&cli.BoolFlag{
+								Name:  "dry-run",
+								Usage: "インデックス化せずにマッピングするサービスとプロダクトを表示",
+							},
+						},
+						Action: appcli.ServicesMapAction,
+					},
+				},
+			},
+			{
+				Name:  "symbol",
+				Usage: "関数・メソッド・型などのシンボルの定義位置の参照コマンド",
+				Commands: []*cli.Command{
+					{
+						Name:      "find",
+						Usage:     "シンボル名の完全一致で定義位置を表示（ベクトル検索は使用しない）",
+						ArgsUsage: "<シンボル名（Type.Method の形式も可）>",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "env",
+								Usage: "環境変数ファイルパス",
+								Value: ".env",
+							},
+							&cli.StringFlag{
+								Name:     "product",
+								Usage:    "プロダクト名",
+								Required: true,
+							},
+							&cli.StringFlag{
+								Name:  "kind",
+								Usage: "シンボルの種類で絞り込み（function | method | struct | interface など）",
+							},
+							&cli.IntFlag{
+								Name:  "limit",
+								Usage: "表示する最大件数",
+								Value: 20,
+							},
+						},
+						Action: appcli.SymbolFindAction,
+					},
+				},
+			},
+			{
+				Name:  "graph",
+				Usage: "チャンクの依存関係グラフの操作コマンド",
+				Commands: []*cli.Command{
+					{
+						Name:  "export",
+						Usage: "依存関係グラフを Graphviz（DOT）・JSON・GraphML で出力",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "env",
+								Usage: "環境変数ファイルパス",
+								Value: ".env",
+							},
+							&cli.StringFlag{
+								Name:     "product",
+								Usage:    "プロダクト名",
+								Required: true,
+							},
+							&cli.StringFlag{
+								Name:  "format",
+								Usage: "出力形式（dot | json | graphml）",
+								Value: "dot",
+							},
+							&cli.StringFlag{
+								Name:  "path-prefix",
+								Usage: "ファイルパスのプレフィックスで絞り込み（依存元・依存先の両方が一致するエッジのみ）",
+							},
+							&cli.FloatFlag{
+								Name:  "min-importance",
+								Usage: "importance_score の下限で絞り込み（0〜1、依存元・依存先の両方が下限以上のエッジのみ）",
+							},
+							&cli.StringFlag{
+								Name:  "output",
+								Usage: "出力ファイルパス（省略時は標準出力）",
+							},
+						},
+						Action: appcli.GraphExportAction,
+					},
+				},
+			},
+			{
+				Name:  "report",
+				Usage: "プロダクトの分析レポートの出力コマンド",
+				Commands: []*cli.Command{
+					{
+						Name:  "hotspots",
+						Usage: "循環的複雑度・行数・編集頻度・コメント比率から変更のリスクが高い関数・ファイルを順位付けして出力",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "env",
+								Usage: "環境変数ファイルパス",
+								Value: ".env",
+							},
+							&cli.StringFlag{
+								Name:     "product",
+								Usage:    "プロダクト名",
+								Required: true,
+							},
+							&cli.StringFlag{
+								Name:  "level",
+								Usage: "順位付けの単位（function | file）",
+								Value: "function",
+							},
+							&cli.IntFlag{
+								Name:  "limit",
+								Usage: "出力する件数（0 は全件）",
+								Value: 20,
+							},
+							&cli.StringFlag{
+								Name:  "format",
+								Usage: "出力形式（table | json | markdown）",
+								Value: "table",
+							},
+							&cli.StringFlag{
+								Name:  "path-prefix",
+								Usage: "ファイルパスのプレフィックスで絞り込み",
+							},
+							&cli.BoolFlag{
+								Name:  "include-tests",
+								Usage: "テストのファイルを含める",
+							},
+							&cli.StringFlag{
+								Name:  "output",
+								Usage: "出力ファイルパス（省略時は標準出力）",
+							},
+						},
+						Action: appcli.ReportHotspotsAction,
+					},
+					{
+						Name:  "licenses",
+						Usage: "ライセンスファイル・ライセンスヘッダーから検出したライセンスを集計し、コピーレフト・判別できないライセンスのファイルを出力",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "env",
+								Usage: "環境変数ファイルパス",
+								Value: ".env",
+							},
+							&cli.StringFlag{
+								Name:     "product",
+								Usage:    "プロダクト名",
+								Required: true,
+							},
+							&cli.StringFlag{
+								Name:  "format",
+								Usage: "出力形式（table | json | markdown）",
+								Value: "table",
+							},
+							&cli.StringFlag{
+								Name:  "output",
+								Usage: "出力ファイルパス（省略時は標準出力）",
+							},
+						},
+						Action: appcli.ReportLicensesAction,
+					},
+				},
+			},
+			{
+				Name:  "ci",
+				Usage: "CIのパイプライン向けのチェックコマンド",
+				Commands: []*cli.Command{
+					{
+						Name:  "check",
+						Usage: "インデックスのドライラン・カバレッジの閾値・Wikiの参照・ドキュメントの鮮度を検証（終了コード 0: 問題なし、1: 失敗、2: 実行エラー）",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "env",
+								Usage: "環境変数ファイルパス",
+								Value: ".env",
+							},
+							&cli.StringFlag{
+								Name:     "product",
+								Usage:    "プロダクト名",
+								Required: true,
+							},
+							&cli.StringSliceFlag{
+								Name:  "check",
+								Usage: "実行するチェック（index_dry_run | coverage | wiki_links | stale_docs、複数指定可、省略時は全チェック）",
+							},
+							&cli.StringFlag{
+								Name:  "wiki-dir",
+								Usage: "生成済みWikiのディレクトリ（省略時は WIKI_OUTPUT_DIR/<プロダクト名>）",
+							},
+							&cli.IntFlag{
+								Name:  "max-broken",
+								Usage: "許容するWikiの解決できない参照の数",
+								Value: 0,
+							},
+							&cli.FloatFlag{
+								Name:  "min-test-coverage",
+								Usage: "テストカバレッジの下限（%、0 は判定しない）",
+							},
+							&cli.BoolFlag{
+								Name:  "json",
+								Usage: "結果をJSONで出力",
+							},
+							&cli.StringFlag{
+								Name:  "output",
+								Usage: "結果のJSONを書き出すファイルパス（CIの成果物用）",
+							},
+						},
+						Action: appcli.CICheckAction,
+					},
+				},
+			},
+			{
+				Name:  "ask",
+				Usage: "プロダクトに関する質問に回答",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "env",
+						Usage: "環境変数ファイルパス",
+						Value: ".env",
+					},
+					&cli.StringFlag{
+						Name:  "product",
+						Usage: "プロダクト名（省略時は質問をプロダクトの要約との類似度から振り分ける）",
+					},
+					&cli.BoolFlag{
+						Name:  "show-sources",
+						Usage: "参照したソースを表示",
+						Value: false,
+					},
+					&cli.BoolFlag{
+						Name:  "verify-citations",
+						Usage: "回答の引用が文を裏付けているかをLLMで検証し、確信度の低い引用に注記を付ける（引用ごとにLLMを呼び出す）",
+					},
+					&cli.BoolFlag{
+						Name:  "no-cache",
+						Usage: "キャッシュした回答を使わずに検索・回答を生成する（ASK_CACHE_ENABLED）",
+					},
+					&cli.StringFlag{
+						Name:  "model",
+						Usage: "回答生成に使用するLLMモデル（未指定時は LLM_ASK_MODEL）",
+					},
+					&cli.StringFlag{
+						Name:  "ref",
+						Usage: "検索対象をこのブランチ/タグのスナップショットに限定",
+					},
+					&cli.StringFlag{
+						Name:  "snapshot",
+						Usage: "検索対象を過去のスナップショット（IDまたはコミットハッシュ）に限定",
+					},
+					&cli.StringFlag{
+						Name:  "source",
+						Usage: "--ref/--snapshot を解決するソース名（プロダクトのソースが1件の場合は省略可）",
+					},
+					&cli.StringSliceFlag{
+						Name:  "language",
+						Usage: "検索対象をファイルの言語に限定（go / python / typescript など、複数指定可）",
+					},
+					&cli.StringSliceFlag{
+						Name:  "domain",
+						Usage: "検索対象をファイルのドメインに限定（code / tests / ops / infra / architecture、複数指定可）",
+					},
+					&cli.StringFlag{
+						Name:  "service",
+						Usage: "検索対象をモノレポから検出したサービス（名前またはディレクトリ、services list で確認）に限定",
+					},
+					&cli.StringFlag{
+						Name:  "strategy",
+						Usage: "チャンク検索の方式（flat | two-stage、未指定時は SEARCH_STRATEGY）。two-stage はファイル要約で候補ファイルを絞り込んでから検索",
+					},
+					&cli.StringFlag{
+						Name:  "embedding-model",
+						Usage: "チャンク検索に使用する追加の Embedding モデル（EMBEDDING_ADDITIONAL_MODELS のいずれか、未指定時は EMBEDDING_MODEL）",
+					},
+					&cli.IntFlag{
+						Name:  "expand-hops",
+						Usage: "検索結果から依存関係（呼び出し元・呼び出し先・型依存）を辿るホップ数（未指定時は ASK_DEPENDENCY_HOPS、0 で無効）",
+					},
+				},
+				ArgsUsage: "<質問文>",
+				Action:    appcli.AskAction,
+			},
+			{
+				Name:  "search",
+				Usage: "LLMを使わずに検索結果（チャンク・要約）をスコア順に表示",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "env",
+						Usage: "環境変数ファイルパス",
+						Value: ".env",
+					},
+					&cli.StringFlag{
+						Name:     "product",
+						Usage:    "プロダクト名",
+						Required: true,
+					},
+					&cli.IntFlag{
+						Name:  "limit",
+						Usage: "チャンクの取得件数",
+						Value: 10,
+					},
+					&cli.BoolFlag{
+						Name:  "hybrid",
+						Usage: "要約も含めたハイブリッド検索を行う",
+					},
+					&cli.IntFlag{
+						Name:  "summary-limit",
+						Usage: "要約の取得件数（--hybrid 指定時）",
+						Value: 5,
+					},
+					&cli.StringFlag{
+						Name:  "path-prefix",
+						Usage: "ファイルパスのプレフィックスで絞り込み",
+					},
+					&cli.StringFlag{
+						Name:  "content-type",
+						Usage: "コンテンツタイプで絞り込み",
+					},
+					&cli.StringSliceFlag{
+						Name:  "language",
+						Usage: "検索対象をファイルの言語に限定（go / python / typescript など、複数指定可）",
+					},
+					&cli.StringSliceFlag{
+						Name:  "domain",
+						Usage: "検索対象をファイルのドメインに限定（code / tests / ops / infra / architecture、複数指定可）",
+					},
+					&cli.BoolFlag{
+						Name:  "keep-overlaps",
+						Usage: "同一ファイル内で行範囲が重なるチャンク（関数とそのロジック単位など）を統合せずに表示",
+					},
+					&cli.StringFlag{
+						Name:  "strategy",
+						Usage: "チャンク検索の方式（flat | two-stage、未指定時は SEARCH_STRATEGY）。two-stage はファイル要約で候補ファイルを絞り込んでから検索",
+					},
+					&cli.StringFlag{
+						Name:  "embedding-model",
+						Usage: "チャンク検索に使用する追加の Embedding モデル（EMBEDDING_ADDITIONAL_MODELS のいずれか、未指定時は EMBEDDING_MODEL）",
+					},
+					&cli.FloatFlag{
+						Name:  "importance-weight",
+						Usage: "類似度に重要度（importance_score）を加味する重み 0〜1（未指定時は SEARCH_IMPORTANCE_WEIGHT、0 で類似度のみ）",
+					},
+					&cli.FloatFlag{
+						Name:  "recency-weight",
+						Usage: "チャンクの最終更新日時による鮮度を加味する重み 0〜1（未指定時はプロダクトの設定）",
+					},
+					&cli.StringFlag{
+						Name:  "ref",
+						Usage: "検索対象をこのブランチ/タグのスナップショットに限定",
+					},
+					&cli.StringFlag{
+						Name:  "snapshot",
+						Usage: "検索対象を過去のスナップショット（IDまたはコミットハッシュ）に限定",
+					},
+					&cli.StringFlag{
+						Name:  "source",
+						Usage: "--ref/--snapshot を解決するソース名（プロダクトのソースが1件の場合は省略可）",
+					},
+					&cli.BoolFlag{
+						Name:  "show-content",
+						Usage: "チャンク・要約の本文を表示",
+					},
+					&cli.BoolFlag{
+						Name:  "json",
+						Usage: "結果をJSONで出力",
+					},
 				},
+				ArgsUsage: "<検索クエリ>",
+				Action:    appcli.SearchAction,
 			},
 			{
-				Name:  "source",
-				Usage: "ソース管理コマンド",
+				Name:  "grep",
+				Usage: "インデックス済みのチャンクの内容を正規表現で検索し、一致した行を表示（リポジトリのクローン不要）",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "env",
+						Usage: "環境変数ファイルパス",
+						Value: ".env",
+					},
+					&cli.StringFlag{
+						Name:     "product",
+						Usage:    "プロダクト名",
+						Required: true,
+					},
+					&cli.BoolFlag{
+						Name:    "ignore-case",
+						Aliases: []string{"i"},
+						Usage:   "大文字小文字を区別しない",
+					},
+					&cli.BoolFlag{
+						Name:    "fixed-strings",
+						Aliases: []string{"F"},
+						Usage:   "パターンを正規表現ではなく文字列として扱う",
+					},
+					&cli.StringFlag{
+						Name:  "path-prefix",
+						Usage: "ファイルパスのプレフィックスで絞り込み",
+					},
+					&cli.StringFlag{
+						Name:  "glob",
+						Usage: "ファイルパス全体のグロブで絞り込み（例: '*.go'、'internal/**/*_test.go'。* は / にも一致）",
+					},
+					&cli.IntFlag{
+						Name:  "limit",
+						Usage: "表示する一致行の最大件数",
+						Value: 100,
+					},
+					&cli.StringFlag{
+						Name:  "ref",
+						Usage: "検索対象をこのブランチ/タグのスナップショットに限定",
+					},
+					&cli.StringFlag{
+						Name:  "snapshot",
+						Usage: "検索対象を過去のスナップショット（IDまたはコミットハッシュ）に限定",
+					},
+					&cli.StringFlag{
+						Name:  "source",
+						Usage: "--ref/--snapshot を解決するソース名（プロダクトのソースが1件の場合は省略可）",
+					},
+					&cli.BoolFlag{
+						Name:  "json",
+						Usage: "結果をJSONで出力",
+					},
+				},
+				ArgsUsage: "<パターン>",
+				Action:    appcli.GrepAction,
+			},
+			{
+				Name:  "prompt",
+				Usage: "LLMプロンプトテンプレートを管理",
 				Commands: []*cli.Command{
 					{
 						Name:  "list",
-						Usage: "ソース一覧を表示",
+						Usage: "テンプレートの一覧と有効なバージョン・取得元を表示",
 						Flags: []cli.Flag{
 							&cli.StringFlag{
 								Name:  "env",
 								Usage: "環境変数ファイルパス",
 								Value: ".env",
 							},
+						},
+						Action: appcli.PromptListAction,
+					},
+					{
+						Name:  "show",
+						Usage: "有効なテンプレートの本文を表示",
+						Flags: []cli.Flag{
 							&cli.StringFlag{
-								Name:  "product",
-								Usage: "プロダクト名（絞り込み）",
+								Name:  "env",
+								Usage: "環境変数ファイルパス",
+								Value: ".env",
+							},
+							&cli.StringFlag{
+								Name:     "name",
+								Usage:    "テンプレート名（summary.file, wiki.section, ask, quality.actions など）",
+								Required: true,
+							},
+							&cli.BoolFlag{
+								Name:  "builtin",
+								Usage: "上書きによらず組み込みのテンプレートを表示（上書きの雛形として使用）",
 							},
 						},
-						Action: appcli.SourceListAction,
+						Action: appcli.PromptShowAction,
 					},
 					{
-						Name:  "show",
-						Usage: "ソース詳細を表示",
+						Name:  "set",
+						Usage: "ファイルのテンプレートをデータベースに保存して上書き（見本の値で展開できることを検証）",
 						Flags: []cli.Flag{
 							&cli.StringFlag{
 								Name:  "env",
@@ -89,21 +1764,59 @@ func main() {
 							},
 							&cli.StringFlag{
 								Name:     "name",
-								Usage:    "ソース名",
+								Usage:    "テンプレート名（summary.file, wiki.section, ask, quality.actions など）",
 								Required: true,
 							},
+							&cli.StringFlag{
+								Name:     "file",
+								Usage:    "テンプレートファイルパス（text/template 形式）",
+								Required: true,
+							},
+							&cli.StringFlag{
+								Name:  "version",
+								Usage: "生成物に記録するバージョン（10文字以内、省略時は本文のハッシュ）",
+							},
 						},
-						Action: appcli.SourceShowAction,
+						Action: appcli.PromptSetAction,
+					},
+					{
+						Name:  "unset",
+						Usage: "データベースのテンプレートを削除して既定のテンプレートに戻す",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "env",
+								Usage: "環境変数ファイルパス",
+								Value: ".env",
+							},
+							&cli.StringFlag{
+								Name:     "name",
+								Usage:    "テンプレート名（summary.file, wiki.section, ask, quality.actions など）",
+								Required: true,
+							},
+						},
+						Action: appcli.PromptUnsetAction,
 					},
 				},
 			},
 			{
-				Name:  "index",
-				Usage: "インデックス管理コマンド",
+				Name:  "embedding",
+				Usage: "使用を許可するEmbeddingモデルの管理",
 				Commands: []*cli.Command{
 					{
-						Name:  "git",
-						Usage: "Gitソースをインデックス化",
+						Name:  "list",
+						Usage: "登録済みのEmbeddingモデルを次元数・生成済みのEmbeddingの数とともに表示（* は設定中のモデル）",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "env",
+								Usage: "環境変数ファイルパス",
+								Value: ".env",
+							},
+						},
+						Action: appcli.EmbeddingListAction,
+					},
+					{
+						Name:  "register",
+						Usage: "Embeddingモデルとその次元数を登録（登録済みの場合は更新）",
 						Flags: []cli.Flag{
 							&cli.StringFlag{
 								Name:  "env",
@@ -111,85 +1824,317 @@ func main() {
 								Value: ".env",
 							},
 							&cli.StringFlag{
-								Name:     "url",
-								Usage:    "GitリポジトリURL",
+								Name:     "model",
+								Usage:    "モデル名（Azure の場合はデプロイメント名）",
 								Required: true,
 							},
 							&cli.StringFlag{
-								Name:     "product",
-								Usage:    "プロダクト名（存在しない場合は自動作成）",
+								Name:  "provider",
+								Usage: "プロバイダー（openai, azure, vertex, ollama、省略時は EMBEDDING_PROVIDER）",
+							},
+							&cli.IntFlag{
+								Name:     "dimension",
+								Usage:    "モデルが出力するベクトルの次元数（--variable-dimension の場合は最大の次元数）",
 								Required: true,
 							},
+							&cli.BoolFlag{
+								Name:  "variable-dimension",
+								Usage: "--dimension 以下の任意の次元数を指定して出力できるモデル",
+							},
+						},
+						Action: appcli.EmbeddingRegisterAction,
+					},
+				},
+			},
+			{
+				Name:  "llm",
+				Usage: "LLM呼び出しの管理",
+				Commands: []*cli.Command{
+					{
+						Name:  "cache",
+						Usage: "LLMの応答のキャッシュを管理",
+						Commands: []*cli.Command{
+							{
+								Name:  "stats",
+								Usage: "キャッシュした応答をプロンプトテンプレート・モデルごとに集計して表示",
+								Flags: []cli.Flag{
+									&cli.StringFlag{
+										Name:  "env",
+										Usage: "環境変数ファイルパス",
+										Value: ".env",
+									},
+								},
+								Action: appcli.LLMCacheStatsAction,
+							},
+							{
+								Name:  "clear",
+								Usage: "キャッシュした応答を削除（次回の生成でLLMを呼び出す）",
+								Flags: []cli.Flag{
+									&cli.StringFlag{
+										Name:  "env",
+										Usage: "環境変数ファイルパス",
+										Value: ".env",
+									},
+									&cli.StringFlag{
+										Name:  "prompt",
+										Usage: "削除するプロンプトテンプレート名（summary.file, wiki.section など、省略時は全テンプレート）",
+									},
+									&cli.IntFlag{
+										Name:  "older-than-days",
+										Usage: "指定した日数より前に保存した応答のみ削除（0 は全て）",
+									},
+								},
+								Action: appcli.LLMCacheClearAction,
+							},
+						},
+					},
+				},
+			},
+			{
+				Name:  "db",
+				Usage: "データベース管理コマンド",
+				Commands: []*cli.Command{
+					{
+						Name:  "verify",
+						Usage: "インデックスの不整合（Embeddingのないチャンク・チャンクのないファイル・最新フラグの不一致など）を検出・修復",
+						Flags: []cli.Flag{
 							&cli.StringFlag{
-								Name:  "ref",
-								Usage: "ブランチ名またはタグ名（省略時はリモートのdefault_branch）",
+								Name:  "env",
+								Usage: "環境変数ファイルパス",
+								Value: ".env",
 							},
 							&cli.BoolFlag{
-								Name:  "force-init",
-								Usage: "強制的にフルインデックスを実行",
+								Name:  "fix",
+								Usage: "検出した不整合を修復（Embeddingの再生成・フラグの付け直し・不要な行の削除）",
+							},
+							&cli.IntFlag{
+								Name:  "samples",
+								Usage: "種類ごとに表示する不整合の件数",
+								Value: 5,
+							},
+						},
+						Action: appcli.DBVerifyAction,
+					},
+					{
+						Name:  "index-tune",
+						Usage: "pgvector のベクトルインデックス（HNSW/IVFFlat）を再構築し、再現率とレイテンシを計測",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "env",
+								Usage: "環境変数ファイルパス",
+								Value: ".env",
+							},
+							&cli.StringFlag{
+								Name:  "table",
+								Usage: "対象テーブル（embeddings, summary_embeddings）",
+								Value: "embeddings",
+							},
+							&cli.StringFlag{
+								Name:  "method",
+								Usage: "インデックス方式（hnsw, ivfflat）",
+								Value: "hnsw",
+							},
+							&cli.IntFlag{
+								Name:  "m",
+								Usage: "HNSW: ノードあたりの接続数",
+								Value: 16,
+							},
+							&cli.IntFlag{
+								Name:  "ef-construction",
+								Usage: "HNSW: 構築時の候補リストサイズ",
+								Value: 64,
+							},
+							&cli.IntFlag{
+								Name:  "lists",
+								Usage: "IVFFlat: クラスタ数（0 の場合は行数から自動決定）",
+								Value: 0,
 							},
 							&cli.BoolFlag{
-								Name:  "generate-wiki",
-								Usage: "インデックス完了後にWikiを自動生成",
+								Name:  "concurrently",
+								Usage: "書き込みをブロックせずにインデックスを作成（CONCURRENTLY）",
+								Value: false,
+							},
+							&cli.BoolFlag{
+								Name:  "benchmark-only",
+								Usage: "インデックスを再構築せず計測のみ行う",
+								Value: false,
+							},
+							&cli.IntFlag{
+								Name:  "samples",
+								Usage: "計測に使用するサンプルクエリ数",
+								Value: 20,
+							},
+							&cli.IntFlag{
+								Name:  "top-k",
+								Usage: "再現率を計測する上位件数",
+								Value: 10,
+							},
+							&cli.IntFlag{
+								Name:  "ef-search",
+								Usage: "HNSW: 検索時の候補リストサイズ（0 の場合はサーバ既定値）",
+								Value: 0,
+							},
+							&cli.IntFlag{
+								Name:  "probes",
+								Usage: "IVFFlat: 検索するクラスタ数（0 の場合はサーバ既定値）",
+								Value: 0,
 							},
 						},
-						Action: appcli.SourceIndexGitAction,
+						Action: appcli.DBIndexTuneAction,
+					},
+					{
+						Name:  "vector-storage",
+						Usage: "embeddings / summary_embeddings のベクトルの格納型を EMBEDDING_STORAGE（vector, halfvec）に合わせて変換し、インデックスを作り直す",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "env",
+								Usage: "環境変数ファイルパス",
+								Value: ".env",
+							},
+							&cli.BoolFlag{
+								Name:  "dry-run",
+								Usage: "変換せずに現在の格納型と変換内容を表示",
+								Value: false,
+							},
+							&cli.StringFlag{
+								Name:  "method",
+								Usage: "作り直すインデックスの方式（hnsw, ivfflat）",
+								Value: "hnsw",
+							},
+							&cli.IntFlag{
+								Name:  "m",
+								Usage: "HNSW: ノードあたりの接続数",
+								Value: 16,
+							},
+							&cli.IntFlag{
+								Name:  "ef-construction",
+								Usage: "HNSW: 構築時の候補リストサイズ",
+								Value: 64,
+							},
+							&cli.IntFlag{
+								Name:  "lists",
+								Usage: "IVFFlat: クラスタ数（0 の場合は行数から自動決定）",
+								Value: 0,
+							},
+						},
+						Action: appcli.DBVectorStorageAction,
+					},
+					{
+						Name:  "gc-blobs",
+						Usage: "参照するファイルがなくなったファイルの内容（file_blobs）を削除（スナップショット・プロダクトの削除後に実行）",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "env",
+								Usage: "環境変数ファイルパス",
+								Value: ".env",
+							},
+						},
+						Action: appcli.DBGCBlobsAction,
 					},
 				},
 			},
 			{
-				Name:  "wiki",
-				Usage: "Wiki生成コマンド",
+				Name:  "bot",
+				Usage: "チャットボット関連コマンド",
 				Commands: []*cli.Command{
 					{
-						Name:  "generate",
-						Usage: "プロダクト単位でWikiを生成",
+						Name:  "slack",
+						Usage: "Socket Mode で接続する Slack ボットを起動（チャンネルに対応付けたプロダクトについて /ask に回答し、👍/👎 を評価として記録）",
 						Flags: []cli.Flag{
 							&cli.StringFlag{
 								Name:  "env",
 								Usage: "環境変数ファイルパス",
 								Value: ".env",
 							},
+						},
+						Action: appcli.BotSlackAction,
+					},
+					{
+						Name:  "feedback",
+						Usage: "ボットの回答のうちリアクションで評価されたものを評価の低い順に表示",
+						Flags: []cli.Flag{
 							&cli.StringFlag{
 								Name:     "product",
 								Usage:    "プロダクト名",
 								Required: true,
 							},
-							&cli.StringFlag{
-								Name:  "out",
-								Usage: "出力ディレクトリ（省略時は /var/lib/dev-rag/wikis/<プロダクト名>）",
+							&cli.IntFlag{
+								Name:  "limit",
+								Usage: "表示する最大件数",
+								Value: 20,
 							},
 							&cli.StringFlag{
-								Name:  "config",
-								Usage: "Wiki生成設定ファイル（省略時はデフォルト設定）",
+								Name:  "env",
+								Usage: "環境変数ファイルパス",
+								Value: ".env",
 							},
 						},
-						Action: appcli.WikiGenerateAction,
+						Action: appcli.BotFeedbackAction,
 					},
 				},
 			},
 			{
-				Name:  "ask",
-				Usage: "プロダクトに関する質問に回答",
+				Name:  "doctor",
+				Usage: "データベース・pgvector・スキーマ・Embedding/LLMの認証情報・Git・クローン先の空き容量を診断",
 				Flags: []cli.Flag{
 					&cli.StringFlag{
 						Name:  "env",
 						Usage: "環境変数ファイルパス",
 						Value: ".env",
 					},
-					&cli.StringFlag{
-						Name:     "product",
-						Usage:    "プロダクト名",
-						Required: true,
+					&cli.BoolFlag{
+						Name:  "skip-api",
+						Usage: "Embedding・LLM の API を呼び出さない（設定からクライアントを生成できるかのみ確認）",
+					},
+					&cli.IntFlag{
+						Name:  "timeout",
+						Usage: "1項目あたりのタイムアウト（秒）",
+						Value: 30,
 					},
 					&cli.BoolFlag{
-						Name:  "show-sources",
-						Usage: "参照したソースを表示",
-						Value: false,
+						Name:  "json",
+						Usage: "結果をJSONで出力",
+					},
+				},
+				Action: appcli.DoctorAction,
+			},
+			{
+				Name:  "config",
+				Usage: "設定関連コマンド",
+				Commands: []*cli.Command{
+					{
+						Name:  "show",
+						Usage: "読み込んだ設定の値と取得元（env / file / default）を表示",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "env",
+								Usage: "環境変数ファイルパス",
+								Value: ".env",
+							},
+							&cli.StringFlag{
+								Name:  "config",
+								Usage: "設定ファイルパス（省略時は DEVRAG_CONFIG またはカレントディレクトリの dev-rag.yaml）",
+							},
+						},
+						Action: appcli.ConfigShowAction,
+					},
+					{
+						Name:  "validate",
+						Usage: "設定を検証（未知のキーや解釈できない値がある場合はエラー）",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "env",
+								Usage: "環境変数ファイルパス",
+								Value: ".env",
+							},
+							&cli.StringFlag{
+								Name:  "config",
+								Usage: "設定ファイルパス（省略時は DEVRAG_CONFIG またはカレントディレクトリの dev-rag.yaml）",
+							},
+						},
+						Action: appcli.ConfigValidateAction,
 					},
 				},
-				ArgsUsage: "<質問文>",
-				Action:    appcli.AskAction,
 			},
 			{
 				Name:  "server",
@@ -217,6 +2162,10 @@ func main() {
 		},
 	}
 
+	// 既定のプロダクト（DEVRAG_DEFAULT_PRODUCT）がある場合は --product を省略可能にする
+	// フラグの必須チェックは引数の解析時に行われるため、実行前にコマンドツリーへ反映する
+	appcli.ApplyDefaultProduct(app, config.DefaultProductName(".env"))
+
 	if err := app.Run(ctx, os.Args); err != nil {
 		log.Fatal(err)
 	}
@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"text/tabwriter"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+	"github.com/urfave/cli/v3"
+
+	"github.com/jinford/dev-rag/internal/core/apispec"
+)
+
+// APIListAction はプロダクトのAPI定義ファイル（OpenAPI・Protocol Buffers）から抽出したエンドポイントを表示するコマンドのアクション
+func APIListAction(ctx context.Context, cmd *cli.Command) error {
+	productName := cmd.String("product")
+	envFile := cmd.String("env")
+
+	filter := apispec.ListFilter{
+		Kind:    apispec.Kind(cmd.String("kind")),
+		Keyword: cmd.String("keyword"),
+	}
+	if filter.Kind != "" && !slices.Contains(apispec.Kinds, filter.Kind) {
+		return fmt.Errorf("--kind は http または grpc を指定してください: %s", filter.Kind)
+	}
+
+	// 共通コンテキストの初期化
+	appCtx, err := NewAppContext(ctx, envFile)
+	if err != nil {
+		return err
+	}
+	defer appCtx.Close()
+
+	productOpt, err := appCtx.Container.IngestionRepo.GetProductByName(ctx, productName)
+	if err != nil {
+		return fmt.Errorf("プロダクト取得に失敗: %w", err)
+	}
+	if productOpt.IsAbsent() {
+		return fmt.Errorf("プロダクトが見つかりません: %s", productName)
+	}
+	product := productOpt.MustGet()
+
+	endpoints, err := appCtx.Container.APIEndpoints.ListEndpoints(ctx, mo.Some(product.ID), mo.None[uuid.UUID](), filter)
+	if err != nil {
+		return fmt.Errorf("エンドポイントの取得に失敗しました: %w", err)
+	}
+	if len(endpoints) == 0 {
+		fmt.Println("エンドポイントが見つかりません（OpenAPI・Protocol Buffers の定義ファイルはインデックス化時に抽出されます）")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SOURCE\tKIND\tMETHOD\tPATH\tREQUEST\tRESPONSE\tSUMMARY\tFILE")
+	for _, ep := range endpoints {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			ep.SourceName,
+			ep.Kind,
+			ep.Method,
+			ep.Path,
+			valueOrDash(ep.RequestType),
+			valueOrDash(ep.ResponseType),
+			valueOrDash(ep.Summary),
+			ep.FilePath,
+		)
+	}
+	return w.Flush()
+}
+
+// valueOrDash は空文字の場合に "-" を返す
+func valueOrDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
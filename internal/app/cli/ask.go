@@ -4,10 +4,13 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"slices"
+	"strings"
 
 	"github.com/urfave/cli/v3"
 
 	coreask "github.com/jinford/dev-rag/internal/core/ask"
+	coresearch "github.com/jinford/dev-rag/internal/core/search"
 	"github.com/samber/mo"
 )
 
@@ -16,6 +19,8 @@ func AskAction(ctx context.Context, cmd *cli.Command) error {
 	// フラグの取得
 	product := cmd.String("product")
 	showSources := cmd.Bool("show-sources")
+	verifyCitations := cmd.Bool("verify-citations")
+	model := cmd.String("model")
 	envFile := cmd.String("env")
 
 	// 質問文の取得
@@ -23,10 +28,40 @@ func AskAction(ctx context.Context, cmd *cli.Command) error {
 	if question == "" {
 		return fmt.Errorf("質問文を指定してください")
 	}
+	scope, err := snapshotScopeFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+	if product == "" && (scope.Ref != "" || scope.Snapshot != "") {
+		return fmt.Errorf("--ref/--snapshot を指定する場合は --product も指定してください")
+	}
+	if product == "" && cmd.String("service") != "" {
+		return fmt.Errorf("--service を指定する場合は --product も指定してください")
+	}
+	domains, err := domainsFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+	strategy, err := strategyFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+	opts := askOptions{Model: model, Scope: scope, Domains: domains, Languages: languagesFromFlags(cmd), Service: cmd.String("service"), Strategy: strategy, VerifyCitations: verifyCitations, EmbeddingModel: cmd.String("embedding-model"), NoCache: cmd.Bool("no-cache")}
+	if cmd.IsSet("expand-hops") {
+		hops := int(cmd.Int("expand-hops"))
+		if hops < 0 {
+			return fmt.Errorf("--expand-hops には0以上の値を指定してください")
+		}
+		opts.DependencyHops = mo.Some(hops)
+	}
 
 	slog.Info("質問応答を開始",
 		"product", product,
 		"question", question,
+		"ref", scope.Ref,
+		"snapshot", scope.Snapshot,
+		"domains", domains,
+		"languages", opts.Languages,
 		"showSources", showSources,
 	)
 
@@ -37,36 +72,113 @@ func AskAction(ctx context.Context, cmd *cli.Command) error {
 	}
 	defer appCtx.Close()
 
+	// プロダクトの指定がない場合は、質問を最も関連するプロダクトに振り分ける
+	if product == "" {
+		product, err = routeProduct(ctx, appCtx, question)
+		if err != nil {
+			return err
+		}
+	}
+
 	// 質問応答処理を実行
-	result, err := executeAsk(ctx, appCtx, product, question)
+	result, err := executeAsk(ctx, appCtx, product, question, opts)
 	if err != nil {
 		slog.Error("質問応答に失敗しました", "error", err)
 		return err
 	}
 
-	// 結果出力
-	fmt.Println(result.Answer)
+	// 結果出力（引用を検証した場合は確信度の低い引用に注記を付ける）
+	if verifyCitations {
+		fmt.Println(coreask.AnnotateAnswer(result.Answer, result.Citations))
+	} else {
+		fmt.Println(result.Answer)
+	}
 
-	// --show-sourcesフラグが指定されている場合、参照ソースも出力
-	if showSources && len(result.Sources) > 0 {
+	// --show-sourcesフラグが指定されている場合と、回答の生成を打ち切った場合は参照ソースも出力
+	if (showSources || slices.Contains(result.Degraded, coreask.DegradedAnswer)) && len(result.Sources) > 0 {
 		fmt.Println("\n--- 参照ソース ---")
 		for i, source := range result.Sources {
-			fmt.Printf("[%d] %s (L%d-L%d) スコア: %.4f\n",
+			location := formatChunkLocation(source.StartLine, source.EndLine, source.Breadcrumb, source.PageStart, source.PageEnd)
+			owners := ""
+			if len(source.Owners) > 0 {
+				owners = " 担当: " + strings.Join(source.Owners, ", ")
+			}
+			fmt.Printf("[%d] %s (%s) スコア: %.4f%s\n",
 				i+1,
 				source.FilePath,
-				source.StartLine,
-				source.EndLine,
+				location,
 				source.Score,
+				owners,
 			)
 		}
 	}
 
-	slog.Info("質問応答が完了しました")
+	if verifyCitations && len(result.Citations) > 0 {
+		printCitationChecks(result.Citations)
+	}
+
+	if len(result.Degraded) > 0 {
+		slog.Warn("所要時間の予算を超えたため一部の処理を省略しました", "stages", result.Degraded)
+	}
+	slog.Info("質問応答が完了しました", "promptVersion", result.PromptVersion, "cached", result.Cached)
 	return nil
 }
 
+// routeProduct は質問を各プロダクトの要約との類似度から最も関連するプロダクトに振り分け、プロダクト名を返す
+// 特定できない場合は候補を示して --product での指定を促す
+func routeProduct(ctx context.Context, appCtx *AppContext, question string) (string, error) {
+	route, err := appCtx.Container.AskService.RouteProduct(ctx, question, nil)
+	if err != nil {
+		return "", fmt.Errorf("プロダクトの振り分けに失敗: %w", err)
+	}
+	if route.Ambiguous() {
+		if len(route.Candidates) == 0 {
+			return "", fmt.Errorf("質問に関連するプロダクトが見つかりません。--product で指定してください")
+		}
+		names := make([]string, 0, len(route.Candidates))
+		for _, candidate := range route.Candidates {
+			names = append(names, fmt.Sprintf("%s (%.2f)", candidate.ProductName, candidate.Score))
+		}
+		return "", fmt.Errorf("質問に関連するプロダクトを特定できません。--product で指定してください（候補: %s）", strings.Join(names, ", "))
+	}
+
+	slog.Info("質問をプロダクトに振り分けました", "product", route.Product.ProductName, "score", route.Product.Score)
+	return route.Product.ProductName, nil
+}
+
+// printCitationChecks は引用の検証結果を表示する
+func printCitationChecks(checks []coreask.CitationCheck) {
+	fmt.Println("\n--- 引用の検証 ---")
+	for i, check := range checks {
+		mark := "OK"
+		if check.LowConfidence() {
+			mark = "要確認"
+		}
+		fmt.Printf("[%d] %s %s（%s、確信度: %.2f）\n", i+1, mark, check.Location(), check.Verdict, check.Confidence)
+		if check.Reason != "" {
+			fmt.Printf("    %s\n", check.Reason)
+		}
+	}
+}
+
+// askOptions は質問応答の任意指定
+type askOptions struct {
+	Model     string                       // 回答生成に使用するモデル
+	Scope     snapshotScope                // 検索対象のスナップショットの指定
+	Domains   []string                     // 検索対象のドメイン
+	Languages []string                     // 検索対象の言語
+	Service   string                       // 検索対象をモノレポのサービス（名前またはディレクトリ）に限定
+	Strategy  coresearch.RetrievalStrategy // チャンク検索の方式（空の場合は SEARCH_STRATEGY）
+
+	EmbeddingModel string // チャンク検索に使用する追加のEmbeddingモデル（空の場合は主モデル）
+
+	DependencyHops  mo.Option[int] // 依存関係を辿るホップ数（None の場合は ASK_DEPENDENCY_HOPS）
+	VerifyCitations bool           // 回答の引用が文を裏付けているかをLLMで検証する
+	NoCache         bool           // 回答のキャッシュを参照しない
+}
+
 // executeAsk は質問応答処理を実行する
-func executeAsk(ctx context.Context, appCtx *AppContext, productName, question string) (*coreask.AskResult, error) {
+func executeAsk(ctx context.Context, appCtx *AppContext, productName, question string, opts askOptions) (*coreask.AskResult, error) {
 	repo := appCtx.Container.IngestionRepo
 
 	// 1. プロダクト名からプロダクトを取得
@@ -84,10 +196,36 @@ func executeAsk(ctx context.Context, appCtx *AppContext, productName, question s
 
 	// 2. AskParamsを構築
 	params := coreask.AskParams{
-		ProductID:    mo.Some(product.ID),
-		Query:        question,
-		ChunkLimit:   10, // デフォルト値
-		SummaryLimit: 5,  // デフォルト値
+		ProductID:      mo.Some(product.ID),
+		Query:          question,
+		ChunkLimit:     10, // デフォルト値
+		SummaryLimit:   5,  // デフォルト値
+		Model:          opts.Model,
+		Domains:        opts.Domains,
+		Languages:      opts.Languages,
+		Strategy:       opts.Strategy,
+		EmbeddingModel: opts.EmbeddingModel,
+		DependencyHops: opts.DependencyHops,
+
+		VerifyCitations: opts.VerifyCitations,
+		NoCache:         opts.NoCache,
+	}
+
+	// ref/snapshot 指定時は検索範囲をそのスナップショットに限定
+	params.SnapshotID, err = opts.Scope.resolve(ctx, repo, product.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// サービス指定時は検索範囲をサービスを検出したスナップショットのサービスのディレクトリに限定
+	if opts.Service != "" {
+		service, err := appCtx.Container.MonorepoServices.Find(ctx, mo.Some(product.ID), params.SnapshotID, opts.Service)
+		if err != nil {
+			return nil, fmt.Errorf("サービスの取得に失敗: %w", err)
+		}
+		params.SnapshotID = mo.Some(service.SnapshotID)
+		params.PathPrefix = service.PathPrefix()
+		slog.Info("検索範囲をサービスに限定します", "service", service.Name, "source", service.SourceName, "path", service.Path)
 	}
 
 	// 3. AskServiceで質問応答を実行
@@ -102,6 +240,10 @@ func executeAsk(ctx context.Context, appCtx *AppContext, productName, question s
 		return nil, fmt.Errorf("質問応答に失敗: %w", err)
 	}
 
+	if result.Refused {
+		slog.Warn("ガードレールにより回答を拒否しました", "reason", result.RefusalReason)
+	}
+
 	slog.Info("質問応答処理完了",
 		"productName", product.Name,
 		"answerLength", len(result.Answer),
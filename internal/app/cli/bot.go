@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/jinford/dev-rag/internal/app/slackbot"
+)
+
+// BotSlackAction は Socket Mode で接続する Slack ボットを起動するコマンドのアクション
+func BotSlackAction(ctx context.Context, cmd *cli.Command) error {
+	envFile := cmd.String("env")
+
+	// 共通コンテキストの初期化
+	appCtx, err := NewAppContext(ctx, envFile)
+	if err != nil {
+		return err
+	}
+	defer appCtx.Close()
+
+	bot, err := slackbot.NewBot(appCtx.Container, appCtx.Config.Slack,
+		slackbot.WithBotLogger(appCtx.Logger()),
+		slackbot.WithDefaultRef(appCtx.Config.Git.DefaultBranch),
+	)
+	if err != nil {
+		return fmt.Errorf("Slackボットの初期化に失敗: %w", err)
+	}
+
+	slog.Info("Slackボットを起動します", "channels", len(appCtx.Config.Slack.ChannelProducts))
+	if err := bot.Run(ctx); err != nil {
+		slog.Error("Slackボットの実行に失敗しました", "error", err)
+		return err
+	}
+
+	slog.Info("Slackボットを停止しました")
+	return nil
+}
+
+// BotFeedbackAction はリアクションで評価された回答を評価の低い順に表示するコマンドのアクション
+func BotFeedbackAction(ctx context.Context, cmd *cli.Command) error {
+	envFile := cmd.String("env")
+	productName := cmd.String("product")
+	limit := int(cmd.Int("limit"))
+	if limit <= 0 {
+		return fmt.Errorf("--limit には1以上の値を指定してください")
+	}
+
+	// 共通コンテキストの初期化
+	appCtx, err := NewAppContext(ctx, envFile)
+	if err != nil {
+		return err
+	}
+	defer appCtx.Close()
+
+	productOpt, err := appCtx.Container.IngestionRepo.GetProductByName(ctx, productName)
+	if err != nil {
+		return fmt.Errorf("プロダクト取得に失敗: %w", err)
+	}
+	if productOpt.IsAbsent() {
+		return fmt.Errorf("プロダクトが見つかりません: %s", productName)
+	}
+
+	answers, err := appCtx.Container.AskFeedback.ListRatedAnswers(ctx, productOpt.MustGet().ID, limit)
+	if err != nil {
+		return fmt.Errorf("評価された回答の取得に失敗: %w", err)
+	}
+	if len(answers) == 0 {
+		fmt.Println("評価された回答はありません")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ANSWERED AT\tUP\tDOWN\tPROMPT\tQUERY")
+	for _, a := range answers {
+		query := strings.ReplaceAll(a.Query, "\n", " ")
+		if r := []rune(query); len(r) > 60 {
+			query = string(r[:60]) + "…"
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%s\t%s\n", a.CreatedAt.Format("2006-01-02 15:04"), a.UpCount, a.DownCount, a.PromptVersion, query)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,379 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/samber/mo"
+	"github.com/urfave/cli/v3"
+
+	coreingestion "github.com/jinford/dev-rag/internal/core/ingestion"
+	coresearch "github.com/jinford/dev-rag/internal/core/search"
+)
+
+// ChunkHistoryAction はチャンクのスナップショットごとのバージョン履歴を表示するコマンドのアクション
+func ChunkHistoryAction(ctx context.Context, cmd *cli.Command) error {
+	chunkKey := cmd.String("chunk-key")
+	showDiff := cmd.Bool("diff")
+	envFile := cmd.String("env")
+
+	// 共通コンテキストの初期化
+	appCtx, err := NewAppContext(ctx, envFile)
+	if err != nil {
+		return err
+	}
+	defer appCtx.Close()
+
+	versions, err := appCtx.Container.IngestionRepo.ListChunkHistory(ctx, chunkKey)
+	if err != nil {
+		return fmt.Errorf("チャンク履歴の取得に失敗: %w", err)
+	}
+	if len(versions) == 0 {
+		return fmt.Errorf("チャンクが見つかりません: %s", chunkKey)
+	}
+
+	changes := 0
+	for i := 1; i < len(versions); i++ {
+		if versions[i].ContentHash != versions[i-1].ContentHash {
+			changes++
+		}
+	}
+
+	fmt.Printf("チャンク:     %s\n", chunkKey)
+	fmt.Printf("バージョン数: %d（内容の変更: %d 回）\n", len(versions), changes)
+
+	var prev *coreingestion.ChunkVersion
+	for i, v := range versions {
+		fmt.Printf("\n[%d] %s\n", i+1, formatChunkVersion(v))
+
+		switch {
+		case prev == nil:
+			fmt.Println("    （初版）")
+		case prev.ContentHash == v.ContentHash:
+			fmt.Println("    （変更なし）")
+		case showDiff:
+			diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+				A:        difflib.SplitLines(prev.Content),
+				B:        difflib.SplitLines(v.Content),
+				FromFile: prev.ChunkKey,
+				ToFile:   v.ChunkKey,
+				Context:  3,
+			})
+			if err != nil {
+				return fmt.Errorf("差分の生成に失敗: %w", err)
+			}
+			fmt.Print(diff)
+		default:
+			fmt.Println("    （変更あり）")
+		}
+		prev = v
+	}
+
+	return nil
+}
+
+// ChunkShowAction はチャンクの内容・メタデータ・階層・依存関係・重要度・Embeddingモデルを表示するコマンドのアクション
+func ChunkShowAction(ctx context.Context, cmd *cli.Command) error {
+	idStr := cmd.String("id")
+	chunkKey := cmd.String("chunk-key")
+	showContent := cmd.Bool("content")
+	contextLines := int(cmd.Int("context"))
+	envFile := cmd.String("env")
+
+	if (idStr == "") == (chunkKey == "") {
+		return fmt.Errorf("--id と --chunk-key のどちらか一方を指定してください")
+	}
+
+	// 共通コンテキストの初期化
+	appCtx, err := NewAppContext(ctx, envFile)
+	if err != nil {
+		return err
+	}
+	defer appCtx.Close()
+
+	repo := appCtx.Container.IngestionRepo
+
+	var chunkOpt mo.Option[*coreingestion.Chunk]
+	if idStr != "" {
+		chunkID, err := uuid.Parse(idStr)
+		if err != nil {
+			return fmt.Errorf("チャンクIDが不正です: %s", idStr)
+		}
+		chunkOpt, err = repo.GetChunkByID(ctx, chunkID)
+		if err != nil {
+			return fmt.Errorf("チャンクの取得に失敗: %w", err)
+		}
+	} else {
+		chunkOpt, err = repo.GetChunkByKey(ctx, chunkKey)
+		if err != nil {
+			return fmt.Errorf("チャンクの取得に失敗: %w", err)
+		}
+	}
+	chunk, ok := chunkOpt.Get()
+	if !ok {
+		return fmt.Errorf("チャンクが見つかりません: %s%s", idStr, chunkKey)
+	}
+
+	labels := newChunkLabeler(repo)
+	file, err := labels.file(ctx, chunk.FileID)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("ID:             %s\n", chunk.ID)
+	fmt.Printf("チャンクキー:   %s\n", chunk.ChunkKey)
+	fmt.Printf("ファイル:       %s#L%d-L%d（ordinal %d）\n", file.Path, chunk.StartLine, chunk.EndLine, chunk.Ordinal)
+	if snapshotOpt, err := repo.GetSnapshotByID(ctx, file.SnapshotID); err != nil {
+		return fmt.Errorf("スナップショットの取得に失敗: %w", err)
+	} else if snapshot, ok := snapshotOpt.Get(); ok {
+		fmt.Printf("スナップショット: %s（%s）\n", shortHash(snapshot.VersionIdentifier), snapshot.ID)
+	}
+	fmt.Printf("種類:           %s\n", formatOptionalString(chunk.Type))
+	fmt.Printf("名前:           %s\n", formatOptionalString(chunk.Name))
+	if chunk.ParentName != nil {
+		fmt.Printf("親の名前:       %s\n", *chunk.ParentName)
+	}
+	if chunk.Signature != nil {
+		fmt.Printf("シグネチャ:     %s\n", *chunk.Signature)
+	}
+	if chunk.Breadcrumb != nil {
+		fmt.Printf("見出し:         %s\n", *chunk.Breadcrumb)
+	}
+	if chunk.PageStart != nil {
+		fmt.Printf("ページ:         %s\n", coresearch.FormatPageRange(*chunk.PageStart, chunk.PageEnd))
+	}
+	fmt.Printf("レベル:         %d\n", chunk.Level)
+	fmt.Printf("トークン数:     %d\n", chunk.TokenCount)
+	if chunk.ChunkerVersion != "" {
+		fmt.Printf("チャンカー:     %s\n", chunk.ChunkerVersion)
+	}
+	if chunk.LinesOfCode != nil {
+		fmt.Printf("行数:           %d\n", *chunk.LinesOfCode)
+	}
+	if chunk.CyclomaticComplexity != nil {
+		fmt.Printf("循環的複雑度:   %d\n", *chunk.CyclomaticComplexity)
+	}
+	if chunk.CommentRatio != nil {
+		fmt.Printf("コメント比率:   %.2f\n", *chunk.CommentRatio)
+	}
+	commit := "-"
+	if chunk.GitCommitHash != nil {
+		commit = shortHash(*chunk.GitCommitHash)
+	}
+	fmt.Printf("コミット:       %s（author %s）\n", commit, formatOptionalString(chunk.Author))
+	fmt.Printf("最新:           %t\n", chunk.IsLatest)
+
+	// 重要度（内訳は chunk_importance に記録されている場合のみ）
+	fmt.Println("\n--- 重要度 ---")
+	importanceOpt, err := repo.GetChunkImportance(ctx, chunk.ID)
+	if err != nil {
+		return fmt.Errorf("重要度の取得に失敗: %w", err)
+	}
+	if imp, ok := importanceOpt.Get(); ok {
+		fmt.Printf("最終スコア:     %.4f\n", imp.FinalScore)
+		fmt.Printf("グラフスコア:   %.4f（%s）\n", imp.GraphScore, imp.Algorithm)
+		fmt.Printf("編集頻度スコア: %.4f（変更 %d 回）\n", imp.EditScore, imp.EditCount)
+		fmt.Printf("算出日時:       %s\n", imp.CalculatedAt.Format(time.DateTime))
+	} else if chunk.ImportanceScore != nil {
+		fmt.Printf("最終スコア:     %.4f（内訳なし）\n", *chunk.ImportanceScore)
+	} else {
+		fmt.Println("（未算出）")
+	}
+
+	fmt.Println("\n--- Embedding ---")
+	embeddingOpt, err := repo.GetEmbeddingInfo(ctx, chunk.ID)
+	if err != nil {
+		return fmt.Errorf("Embeddingの取得に失敗: %w", err)
+	}
+	if embedding, ok := embeddingOpt.Get(); ok {
+		fmt.Printf("モデル:         %s（%s）\n", embedding.Model, embedding.CreatedAt.Format(time.DateTime))
+	} else {
+		fmt.Println("（未作成）")
+	}
+	if chunk.EmbeddingTruncated && chunk.EmbeddingContext != nil {
+		fmt.Printf("入力:           最大入力トークン数に合わせて切り詰め（%d文字 / 本文 %d文字）\n", len([]rune(*chunk.EmbeddingContext)), len([]rune(chunk.Content)))
+	}
+
+	// 階層（親チャンクと子チャンク）
+	fmt.Println("\n--- 階層 ---")
+	parentOpt, err := repo.GetChunkParent(ctx, chunk.ID)
+	if err != nil {
+		return fmt.Errorf("親チャンクの取得に失敗: %w", err)
+	}
+	children, err := repo.GetChunkChildren(ctx, chunk.ID)
+	if err != nil {
+		return fmt.Errorf("子チャンクの取得に失敗: %w", err)
+	}
+	if parent, ok := parentOpt.Get(); ok {
+		label, err := labels.chunk(ctx, parent)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("親: %s\n", label)
+	}
+	for _, child := range children {
+		label, err := labels.chunk(ctx, child)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("子: %s\n", label)
+	}
+	if parentOpt.IsAbsent() && len(children) == 0 {
+		fmt.Println("（なし）")
+	}
+
+	// 依存関係（依存先と依存元）
+	fmt.Println("\n--- 依存関係 ---")
+	outgoing, err := repo.GetDependenciesByChunk(ctx, chunk.ID)
+	if err != nil {
+		return fmt.Errorf("依存先の取得に失敗: %w", err)
+	}
+	incoming, err := repo.GetIncomingDependenciesByChunk(ctx, chunk.ID)
+	if err != nil {
+		return fmt.Errorf("依存元の取得に失敗: %w", err)
+	}
+	for _, dep := range outgoing {
+		label, err := labels.chunkByID(ctx, dep.ToChunkID)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("-> [%s] %s  %s\n", dep.DepType, formatOptionalString(dep.Symbol), label)
+	}
+	for _, dep := range incoming {
+		label, err := labels.chunkByID(ctx, dep.FromChunkID)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("<- [%s] %s  %s\n", dep.DepType, formatOptionalString(dep.Symbol), label)
+	}
+	if len(outgoing) == 0 && len(incoming) == 0 {
+		fmt.Println("（なし）")
+	}
+
+	if chunk.DocComment != nil && strings.TrimSpace(*chunk.DocComment) != "" {
+		fmt.Println("\n--- ドキュメントコメント ---")
+		fmt.Println(strings.TrimSpace(*chunk.DocComment))
+	}
+
+	if showContent {
+		fmt.Println("\n--- 内容 ---")
+		fmt.Println(chunk.Content)
+	}
+
+	// 保存したファイルの内容からチャンクの前後の行を表示する（リポジトリを参照しない）
+	if contextLines > 0 {
+		fmt.Printf("\n--- 原文（前後 %d 行） ---\n", contextLines)
+		text, err := loadFileText(ctx, repo, file)
+		if err != nil {
+			return err
+		}
+		if text != "" {
+			printLineRange(text, chunk.StartLine, chunk.EndLine, contextLines)
+		}
+	}
+
+	return nil
+}
+
+// printLineRange はテキストの start〜end 行を前後 contextLines 行とともに行番号付きで表示する（チャンクの行に ">" を付ける）
+func printLineRange(text string, start, end, contextLines int) {
+	lines := strings.Split(text, "\n")
+	from := max(start-contextLines, 1)
+	to := min(end+contextLines, len(lines))
+	for n := from; n <= to; n++ {
+		marker := " "
+		if n >= start && n <= end {
+			marker = ">"
+		}
+		fmt.Printf("%s%5d  %s\n", marker, n, lines[n-1])
+	}
+}
+
+// chunkLabeler はチャンクを「パス#L開始-L終了 名前」の形式で表示するため、ファイルを取得・キャッシュする
+type chunkLabeler struct {
+	repo  coreingestion.Repository
+	files map[uuid.UUID]*coreingestion.File
+}
+
+func newChunkLabeler(repo coreingestion.Repository) *chunkLabeler {
+	return &chunkLabeler{repo: repo, files: make(map[uuid.UUID]*coreingestion.File)}
+}
+
+func (l *chunkLabeler) file(ctx context.Context, fileID uuid.UUID) (*coreingestion.File, error) {
+	if file, ok := l.files[fileID]; ok {
+		return file, nil
+	}
+	fileOpt, err := l.repo.GetFileByID(ctx, fileID)
+	if err != nil {
+		return nil, fmt.Errorf("ファイルの取得に失敗: %w", err)
+	}
+	file, ok := fileOpt.Get()
+	if !ok {
+		return nil, fmt.Errorf("ファイルが見つかりません: %s", fileID)
+	}
+	l.files[fileID] = file
+	return file, nil
+}
+
+func (l *chunkLabeler) chunk(ctx context.Context, c *coreingestion.Chunk) (string, error) {
+	file, err := l.file(ctx, c.FileID)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s#L%d-L%d %s (%s)", file.Path, c.StartLine, c.EndLine, formatOptionalString(c.Name), c.ID), nil
+}
+
+func (l *chunkLabeler) chunkByID(ctx context.Context, chunkID uuid.UUID) (string, error) {
+	chunkOpt, err := l.repo.GetChunkByID(ctx, chunkID)
+	if err != nil {
+		return "", fmt.Errorf("チャンクの取得に失敗: %w", err)
+	}
+	c, ok := chunkOpt.Get()
+	if !ok {
+		return chunkID.String(), nil
+	}
+	return l.chunk(ctx, c)
+}
+
+// formatOptionalString は未設定の文字列を "-" として表示する
+func formatOptionalString(s *string) string {
+	if s == nil || *s == "" {
+		return "-"
+	}
+	return *s
+}
+
+// formatChunkVersion はチャンクのバージョンを1行の見出しに整形する
+func formatChunkVersion(v *coreingestion.ChunkVersion) string {
+	commit := "-"
+	if v.GitCommitHash != nil {
+		commit = shortHash(*v.GitCommitHash)
+	}
+	author := "-"
+	if v.Author != nil {
+		author = *v.Author
+	}
+	updated := "-"
+	if v.UpdatedAt != nil {
+		updated = v.UpdatedAt.Format(time.DateTime)
+	}
+
+	line := fmt.Sprintf("snapshot %s  commit %s  author %s  updated %s  L%d-L%d",
+		shortHash(v.VersionIdentifier), commit, author, updated, v.StartLine, v.EndLine)
+	if v.IsLatest {
+		line += "  [latest]"
+	}
+	return line
+}
+
+// shortHash はコミットハッシュを短縮表示する
+func shortHash(hash string) string {
+	if len(hash) > 12 {
+		return hash[:12]
+	}
+	return hash
+}
@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/jinford/dev-rag/internal/core/cicheck"
+)
+
+// ciStatusLabels はチェックの結果の表示名
+var ciStatusLabels = map[cicheck.Status]string{
+	cicheck.StatusPass:  "PASS",
+	cicheck.StatusWarn:  "WARN",
+	cicheck.StatusFail:  "FAIL",
+	cicheck.StatusSkip:  "SKIP",
+	cicheck.StatusError: "ERROR",
+}
+
+// CICheckAction はCIのパイプラインでインデックスのドライラン・カバレッジの閾値・Wikiの参照・ドキュメントの鮮度を検証するコマンドのアクション
+// 終了コードは 0（問題なし）、1（失敗したチェックがある）、2（チェックを実行できなかった）
+func CICheckAction(ctx context.Context, cmd *cli.Command) error {
+	productName := cmd.String("product")
+	envFile := cmd.String("env")
+	asJSON := cmd.Bool("json")
+	outputPath := cmd.String("output")
+
+	params := cicheck.Params{
+		ProductName:     productName,
+		MaxBroken:       int(cmd.Int("max-broken")),
+		MinTestCoverage: cmd.Float("min-test-coverage"),
+	}
+	for _, name := range cmd.StringSlice("check") {
+		check := cicheck.CheckName(name)
+		if !slices.Contains(cicheck.CheckNames, check) {
+			return cli.Exit(fmt.Sprintf("--check は index_dry_run、coverage、wiki_links、stale_docs のいずれかを指定してください: %s", name), cicheck.ExitCodeError)
+		}
+		params.Checks = append(params.Checks, check)
+	}
+	if params.MaxBroken < 0 {
+		return cli.Exit(fmt.Sprintf("--max-broken は0以上で指定してください: %d", params.MaxBroken), cicheck.ExitCodeError)
+	}
+	if params.MinTestCoverage < 0 || params.MinTestCoverage > 100 {
+		return cli.Exit(fmt.Sprintf("--min-test-coverage は 0〜100 で指定してください: %v", params.MinTestCoverage), cicheck.ExitCodeError)
+	}
+
+	// 共通コンテキストの初期化
+	appCtx, err := NewAppContext(ctx, envFile)
+	if err != nil {
+		return cli.Exit(err.Error(), cicheck.ExitCodeError)
+	}
+	defer appCtx.Close()
+
+	productOpt, err := appCtx.Container.IngestionRepo.GetProductByName(ctx, productName)
+	if err != nil {
+		return cli.Exit(fmt.Sprintf("プロダクト取得に失敗: %v", err), cicheck.ExitCodeError)
+	}
+	if productOpt.IsAbsent() {
+		return cli.Exit(fmt.Sprintf("プロダクトが見つかりません: %s", productName), cicheck.ExitCodeError)
+	}
+	product := productOpt.MustGet()
+	params.ProductID = product.ID
+
+	params.WikiDir = cmd.String("wiki-dir")
+	if params.WikiDir == "" {
+		params.WikiDir = filepath.Join(appCtx.Config.WikiOutputDir, product.Name)
+	}
+
+	report, err := appCtx.Container.CIChecks.Run(ctx, params)
+	if err != nil {
+		return cli.Exit(fmt.Sprintf("チェックの実行に失敗しました: %v", err), cicheck.ExitCodeError)
+	}
+
+	if outputPath != "" {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return cli.Exit(fmt.Sprintf("チェック結果の変換に失敗しました: %v", err), cicheck.ExitCodeError)
+		}
+		if err := os.WriteFile(outputPath, append(data, '\n'), 0644); err != nil {
+			return cli.Exit(fmt.Sprintf("チェック結果の出力に失敗しました: %v", err), cicheck.ExitCodeError)
+		}
+	}
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return cli.Exit(fmt.Sprintf("チェック結果の出力に失敗しました: %v", err), cicheck.ExitCodeError)
+		}
+	} else {
+		printCIReport(report)
+	}
+
+	switch report.ExitCode {
+	case cicheck.ExitCodeFailed:
+		return cli.Exit("失敗したチェックがあります", report.ExitCode)
+	case cicheck.ExitCodeError:
+		return cli.Exit("実行できなかったチェックがあります", report.ExitCode)
+	}
+	return nil
+}
+
+// printCIReport はチェックの結果を表示する
+func printCIReport(report *cicheck.Report) {
+	fmt.Printf("プロダクト: %s\n", report.Product)
+	for _, check := range report.Checks {
+		fmt.Printf("[%s] %s: %s\n", ciStatusLabels[check.Status], check.Name, check.Message)
+		for _, detail := range check.Details {
+			fmt.Printf("  - %s\n", detail)
+		}
+	}
+	fmt.Printf("結果: %s（終了コード %d）\n", ciStatusLabels[report.Status], report.ExitCode)
+}
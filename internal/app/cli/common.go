@@ -13,10 +13,12 @@ import (
 // AppContext はコマンド実行に必要な共通コンテキストを保持する
 type AppContext struct {
 	Container *container.ServiceContainer // 新アーキテクチャ用コンテナ
+	Config    *config.Config              // 読み込み済みの設定
 }
 
 // NewAppContext は設定ファイルを読み込み、DBに接続して AppContext を作成する
-func NewAppContext(ctx context.Context, envFile string) (*AppContext, error) {
+// opts はロガーの設定の後にコンテナに渡す
+func NewAppContext(ctx context.Context, envFile string, opts ...container.ContainerOption) (*AppContext, error) {
 	// 設定の読み込み（platform層を使用）
 	cfg, err := config.Load(envFile)
 	if err != nil {
@@ -27,13 +29,15 @@ func NewAppContext(ctx context.Context, envFile string) (*AppContext, error) {
 	appLogger := logger.New(logger.DefaultConfig())
 
 	// コンテナの初期化（platform層を使用）
-	cont, err := container.NewContainer(ctx, cfg, container.WithContainerLogger(appLogger))
+	opts = append([]container.ContainerOption{container.WithContainerLogger(appLogger)}, opts...)
+	cont, err := container.NewContainer(ctx, cfg, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("コンテナの初期化に失敗: %w", err)
 	}
 
 	return &AppContext{
 		Container: cont,
+		Config:    cfg,
 	}, nil
 }
 
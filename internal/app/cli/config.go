@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/jinford/dev-rag/internal/platform/config"
+)
+
+// secretKeySegments はマスクして表示する設定項目のキーに含まれる単語（"_" 区切り）
+var secretKeySegments = []string{"KEY", "KEYS", "TOKEN", "PASSWORD", "SECRET"}
+
+// ConfigShowAction は読み込んだ設定項目の値と取得元を表示するコマンドのアクション
+func ConfigShowAction(ctx context.Context, cmd *cli.Command) error {
+	cfg, err := config.LoadWithFile(cmd.String("env"), cmd.String("config"))
+	if err != nil {
+		return fmt.Errorf("設定の読み込みに失敗: %w", err)
+	}
+
+	fmt.Printf("設定ファイル: %s\n\n", valueOrDash(cfg.File))
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KEY\tVALUE\tSOURCE")
+	for _, s := range cfg.Settings() {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", s.Key, valueOrDash(maskSetting(s)), s.Origin)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	for _, warning := range cfg.Warnings() {
+		fmt.Fprintf(os.Stderr, "警告: %s\n", warning)
+	}
+	return nil
+}
+
+// ConfigValidateAction は設定を読み込んで検証するコマンドのアクション
+// 読み込みに失敗した場合に加え、設定ファイルの未知のキーや解釈できない値がある場合もエラーとする
+func ConfigValidateAction(ctx context.Context, cmd *cli.Command) error {
+	cfg, err := config.LoadWithFile(cmd.String("env"), cmd.String("config"))
+	if err != nil {
+		return fmt.Errorf("設定が不正です: %w", err)
+	}
+
+	warnings := cfg.Warnings()
+	for _, warning := range warnings {
+		fmt.Fprintf(os.Stderr, "%s\n", warning)
+	}
+	if len(warnings) > 0 {
+		return fmt.Errorf("設定に %d 件の問題があります", len(warnings))
+	}
+
+	fmt.Printf("設定は有効です（設定ファイル: %s）\n", valueOrDash(cfg.File))
+	return nil
+}
+
+// maskSetting は秘密情報を含む設定項目の値をマスクして返す
+func maskSetting(s config.Setting) string {
+	if s.Value == "" {
+		return ""
+	}
+	for _, segment := range strings.Split(s.Key, "_") {
+		if slices.Contains(secretKeySegments, segment) {
+			return "********"
+		}
+	}
+	return s.Value
+}
+
+// ApplyDefaultProduct はコマンドツリーの必須の --product フラグに既定のプロダクトを設定し、省略可能にする
+// 絞り込みに使う省略可能な --product フラグは変更しない
+func ApplyDefaultProduct(root *cli.Command, product string) {
+	if product == "" {
+		return
+	}
+	for _, flag := range root.Flags {
+		if f, ok := flag.(*cli.StringFlag); ok && f.Name == "product" && f.Required {
+			f.Value = product
+			f.Required = false
+		}
+	}
+	for _, sub := range root.Commands {
+		ApplyDefaultProduct(sub, product)
+	}
+}
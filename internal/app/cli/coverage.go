@@ -0,0 +1,150 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/jinford/dev-rag/internal/core/coverage"
+	"github.com/jinford/dev-rag/internal/core/ingestion"
+	"github.com/jinford/dev-rag/internal/core/testcoverage"
+)
+
+// CoverageTrendAction はプロダクトのスナップショットごとのカバレッジの推移を表示するコマンドのアクション
+func CoverageTrendAction(ctx context.Context, cmd *cli.Command) error {
+	productName := cmd.String("product")
+	domain := cmd.String("domain")
+	envFile := cmd.String("env")
+
+	if domain != "" && domain != "unknown" && !ingestion.IsValidDomain(domain) {
+		return fmt.Errorf("不正なドメインです: %s", domain)
+	}
+	filter := coverage.TrendFilter{Domain: domain}
+	if s := cmd.String("since"); s != "" {
+		since, err := time.ParseInLocation(time.DateOnly, s, time.Local)
+		if err != nil {
+			return fmt.Errorf("--since は YYYY-MM-DD 形式で指定してください: %s", s)
+		}
+		filter.Since = since
+	}
+
+	// 共通コンテキストの初期化
+	appCtx, err := NewAppContext(ctx, envFile)
+	if err != nil {
+		return err
+	}
+	defer appCtx.Close()
+
+	product, err := findProduct(ctx, appCtx, productName)
+	if err != nil {
+		return err
+	}
+
+	series, err := appCtx.Container.CoverageTrend.Trend(ctx, product.ID, filter)
+	if err != nil {
+		return fmt.Errorf("カバレッジの推移の取得に失敗: %w", err)
+	}
+	if len(series) == 0 {
+		fmt.Println("カバレッジの履歴がありません（インデックス化の完了時に記録されます）")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for i, s := range series {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%s / %s: %.1f%% → %.1f%% (%+.1fpt)\n", s.SourceName, s.Domain, s.Points[0].CoverageRate, s.Latest().CoverageRate, s.Change)
+		fmt.Fprintln(w, "RECORDED\tVERSION\tFILES\tINDEXED\tCHUNKS\tCOVERAGE")
+		for _, p := range s.Points {
+			fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%.1f%%\n",
+				p.RecordedAt.Format(time.DateTime), shortHash(p.Version), p.TotalFiles, p.IndexedFiles, p.IndexedChunks, p.CoverageRate)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CoverageImportAction はテストのカバレッジレポート（Go の coverage.out・LCOV）をソースのスナップショットに取り込むコマンドのアクション
+func CoverageImportAction(ctx context.Context, cmd *cli.Command) error {
+	sourceName := cmd.String("source")
+	reportPath := cmd.String("file")
+	envFile := cmd.String("env")
+	asJSON := cmd.Bool("json")
+
+	format := testcoverage.Format(cmd.String("format"))
+	if format != "" && !slices.Contains(testcoverage.Formats, format) {
+		return fmt.Errorf("--format は go、lcov のいずれかを指定してください: %s", format)
+	}
+	profile, err := testcoverage.Load(reportPath, format)
+	if err != nil {
+		return fmt.Errorf("カバレッジレポートの読み込みに失敗: %w", err)
+	}
+
+	// 共通コンテキストの初期化
+	appCtx, err := NewAppContext(ctx, envFile)
+	if err != nil {
+		return err
+	}
+	defer appCtx.Close()
+
+	repo := appCtx.Container.IngestionRepo
+	sourceOpt, err := repo.GetSourceByName(ctx, sourceName)
+	if err != nil {
+		return fmt.Errorf("ソースの取得に失敗: %w", err)
+	}
+	source, ok := sourceOpt.Get()
+	if !ok {
+		return fmt.Errorf("ソースが見つかりません: %s", sourceName)
+	}
+
+	var snapshot *ingestion.SourceSnapshot
+	if ref := cmd.String("snapshot"); ref != "" {
+		if snapshot, err = ingestion.ResolveSourceSnapshot(ctx, repo, source, ref); err != nil {
+			return fmt.Errorf("スナップショットの解決に失敗: %w", err)
+		}
+	} else {
+		latest, err := repo.GetLatestIndexedSnapshot(ctx, source.ID)
+		if err != nil {
+			return fmt.Errorf("スナップショットの取得に失敗: %w", err)
+		}
+		if snapshot, ok = latest.Get(); !ok {
+			return fmt.Errorf("インデックス済みのスナップショットがありません: %s", sourceName)
+		}
+	}
+
+	result, err := appCtx.Container.TestCoverage.Import(ctx, snapshot.ID, profile)
+	if err != nil {
+		return fmt.Errorf("カバレッジレポートの取り込みに失敗: %w", err)
+	}
+
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	}
+
+	fmt.Printf("スナップショット: %s (%s)\n", snapshot.VersionIdentifier, snapshot.ID)
+	fmt.Printf("形式: %s\n", result.Format)
+	fmt.Printf("ファイル: %d / %d（レポートのファイルのうちスナップショットに対応付けたもの）\n", result.MatchedFiles, result.ReportFiles)
+	fmt.Printf("関数・クラス: %d\n", result.Chunks)
+	fmt.Printf("カバレッジ: %.1f%% (%d/%d)\n", result.Percent(), result.Covered, result.Statements)
+	if len(result.Unmatched) > 0 {
+		fmt.Printf("\n対応付けられなかったファイル（%d件）:\n", len(result.Unmatched))
+		for _, path := range result.Unmatched[:min(len(result.Unmatched), 20)] {
+			fmt.Printf("  %s\n", path)
+		}
+		if len(result.Unmatched) > 20 {
+			fmt.Printf("  ...ほか %d件\n", len(result.Unmatched)-20)
+		}
+	}
+	return nil
+}
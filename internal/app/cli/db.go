@@ -0,0 +1,271 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/jinford/dev-rag/internal/core/integrity"
+	"github.com/jinford/dev-rag/internal/platform/container"
+	"github.com/jinford/dev-rag/internal/platform/database"
+)
+
+// DBIndexTuneAction はベクトルインデックスの作成・再構築と性能計測を行うコマンドのアクション
+func DBIndexTuneAction(ctx context.Context, cmd *cli.Command) error {
+	envFile := cmd.String("env")
+	tableName := cmd.String("table")
+	method := database.VectorIndexMethod(strings.ToLower(cmd.String("method")))
+	benchmarkOnly := cmd.Bool("benchmark-only")
+
+	target, ok := database.VectorIndexTargets[tableName]
+	if !ok {
+		tables := make([]string, 0, len(database.VectorIndexTargets))
+		for name := range database.VectorIndexTargets {
+			tables = append(tables, name)
+		}
+		slices.Sort(tables)
+		return fmt.Errorf("対象テーブルが不正です: %s (指定可能: %s)", tableName, strings.Join(tables, ", "))
+	}
+
+	indexParams := database.VectorIndexParams{
+		Method:         method,
+		M:              int(cmd.Int("m")),
+		EfConstruction: int(cmd.Int("ef-construction")),
+		Lists:          int(cmd.Int("lists")),
+		Concurrently:   cmd.Bool("concurrently"),
+	}
+	benchParams := database.VectorIndexBenchmarkParams{
+		Samples:  int(cmd.Int("samples")),
+		TopK:     int(cmd.Int("top-k")),
+		EfSearch: int(cmd.Int("ef-search")),
+		Probes:   int(cmd.Int("probes")),
+	}
+
+	// 共通コンテキストの初期化
+	appCtx, err := NewAppContext(ctx, envFile)
+	if err != nil {
+		return err
+	}
+	defer appCtx.Close()
+
+	db := appCtx.Container.Database()
+
+	if !benchmarkOnly {
+		slog.Info("ベクトルインデックスを再構築します",
+			"table", target.Table,
+			"index", target.IndexName,
+			"method", method,
+			"m", indexParams.M,
+			"efConstruction", indexParams.EfConstruction,
+			"lists", indexParams.Lists,
+		)
+		if err := db.RebuildVectorIndex(ctx, target, indexParams); err != nil {
+			return fmt.Errorf("インデックスの再構築に失敗: %w", err)
+		}
+		slog.Info("ベクトルインデックスを再構築しました", "index", target.IndexName)
+	}
+
+	slog.Info("再現率とレイテンシを計測します", "samples", benchParams.Samples, "topK", benchParams.TopK)
+	bench, err := db.BenchmarkVectorIndex(ctx, target, benchParams)
+	if err != nil {
+		return fmt.Errorf("性能計測に失敗: %w", err)
+	}
+
+	fmt.Printf("テーブル:           %s.%s (%s)\n", target.Table, target.Column, target.IndexName)
+	fmt.Printf("サンプル数:         %d (top-%d)\n", bench.Samples, bench.TopK)
+	fmt.Printf("再現率:             %.3f\n", bench.Recall)
+	fmt.Printf("平均レイテンシ:     %s\n", bench.AvgLatency)
+	fmt.Printf("P95レイテンシ:      %s\n", bench.P95Latency)
+	fmt.Printf("厳密検索レイテンシ: %s\n", bench.ExactLatency)
+	fmt.Printf("インデックス使用:   %t\n", bench.UsesIndexScan)
+
+	if !bench.UsesIndexScan {
+		slog.Warn("実行計画でベクトルインデックスが使用されていません（シーケンシャルスキャン）", "index", target.IndexName)
+	}
+
+	return nil
+}
+
+// DBVectorStorageAction はベクトルカラムの格納型を EMBEDDING_STORAGE の設定に合わせて変換するコマンドのアクション
+func DBVectorStorageAction(ctx context.Context, cmd *cli.Command) error {
+	envFile := cmd.String("env")
+	dryRun := cmd.Bool("dry-run")
+
+	indexParams := database.VectorIndexParams{
+		Method:         database.VectorIndexMethod(strings.ToLower(cmd.String("method"))),
+		M:              int(cmd.Int("m")),
+		EfConstruction: int(cmd.Int("ef-construction")),
+		Lists:          int(cmd.Int("lists")),
+	}
+
+	// 変換前は格納型の検証に失敗するため、起動時の検証を省略する
+	appCtx, err := NewAppContext(ctx, envFile, container.WithoutEmbeddingModelCheck())
+	if err != nil {
+		return err
+	}
+	defer appCtx.Close()
+
+	storage, err := database.ParseVectorStorage(appCtx.Config.Embedding.Storage)
+	if err != nil {
+		return err
+	}
+	db := appCtx.Container.Database()
+
+	converted := 0
+	for _, table := range []string{"embeddings", "summary_embeddings"} {
+		target := database.VectorIndexTargets[table]
+		current, err := db.VectorStorageOf(ctx, target.Table, target.Column)
+		if err != nil {
+			return fmt.Errorf("格納型の取得に失敗: %w", err)
+		}
+		if current == storage {
+			fmt.Printf("%s.%s: %s（変換不要）\n", target.Table, target.Column, current)
+			continue
+		}
+		if dryRun {
+			fmt.Printf("%s.%s: %s -> %s（--dry-run のため変換しません）\n", target.Table, target.Column, current, storage)
+			continue
+		}
+
+		slog.Info("ベクトルの格納型を変換します", "table", target.Table, "from", current, "to", storage, "method", indexParams.Method)
+		if err := db.ConvertVectorStorage(ctx, target, storage, indexParams); err != nil {
+			return fmt.Errorf("格納型の変換に失敗: %w", err)
+		}
+		fmt.Printf("%s.%s: %s -> %s\n", target.Table, target.Column, current, storage)
+		converted++
+	}
+
+	if converted > 0 {
+		slog.Info("ベクトルの格納型を変換しました", "tables", converted, "storage", storage)
+	}
+	return nil
+}
+
+// integrityCheckLabels は不整合の種類の表示名
+var integrityCheckLabels = map[integrity.CheckKind]string{
+	integrity.CheckChunksWithoutEmbedding: "Embeddingのないチャンク",
+	integrity.CheckFilesWithoutChunks:     "チャンクのないファイル",
+	integrity.CheckSnapshotFileMismatch:   "snapshot_filesの不一致",
+	integrity.CheckStaleLatestFlags:       "最新フラグの不一致",
+	integrity.CheckDanglingDependencies:   "別スナップショットを結ぶ依存関係",
+}
+
+// DBVerifyAction はインデックスの不整合を検出し、--fix 指定時は修復するコマンドのアクション
+func DBVerifyAction(ctx context.Context, cmd *cli.Command) error {
+	envFile := cmd.String("env")
+	fix := cmd.Bool("fix")
+	samples := int(cmd.Int("samples"))
+
+	// 共通コンテキストの初期化
+	appCtx, err := NewAppContext(ctx, envFile)
+	if err != nil {
+		return err
+	}
+	defer appCtx.Close()
+
+	checker := appCtx.Container.Integrity
+
+	report, err := checker.Verify(ctx)
+	if err != nil {
+		return fmt.Errorf("不整合の検出に失敗: %w", err)
+	}
+
+	for _, kind := range integrity.CheckKinds {
+		fmt.Printf("%s: %d\n", integrityCheckLabels[kind], report.Count(kind))
+		for _, line := range integritySamples(report, kind, samples) {
+			fmt.Printf("  - %s\n", line)
+		}
+	}
+
+	if report.Healthy() {
+		fmt.Println("\n不整合は見つかりませんでした")
+		return nil
+	}
+	if !fix {
+		return fmt.Errorf("不整合が見つかりました（--fix で修復できます）")
+	}
+
+	slog.Info("不整合を修復します")
+	results, err := checker.Repair(ctx, report)
+	if err != nil {
+		return fmt.Errorf("不整合の修復に失敗: %w", err)
+	}
+
+	fmt.Println("\n--- 修復結果 ---")
+	failed := 0
+	for _, r := range results {
+		fmt.Printf("%s: 修復 %d / 失敗 %d\n", integrityCheckLabels[r.Kind], r.Repaired, r.Failed)
+		failed += r.Failed
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d 件の不整合を修復できませんでした", failed)
+	}
+	return nil
+}
+
+// integritySamples は不整合の種類ごとに先頭から limit 件の内容を表示用に整形する
+func integritySamples(report *integrity.Report, kind integrity.CheckKind, limit int) []string {
+	var lines []string
+	switch kind {
+	case integrity.CheckChunksWithoutEmbedding:
+		for _, c := range report.OrphanChunks {
+			lines = append(lines, fmt.Sprintf("%s#L%d-L%d (%s)", c.FilePath, c.StartLine, c.EndLine, c.ChunkID))
+		}
+	case integrity.CheckFilesWithoutChunks:
+		for _, f := range report.EmptyFiles {
+			lines = append(lines, fmt.Sprintf("%s (snapshot %s)", f.Path, f.SnapshotID))
+		}
+	case integrity.CheckSnapshotFileMismatch:
+		for _, m := range report.SnapshotFileMismatches {
+			lines = append(lines, fmt.Sprintf("%s (snapshot %s, indexed=%t, チャンク=%t)", m.FilePath, m.SnapshotID, m.Indexed, m.HasChunks))
+		}
+	case integrity.CheckStaleLatestFlags:
+		for _, s := range report.StaleLatestSources {
+			lines = append(lines, fmt.Sprintf("%s: %d チャンク", s.SourceName, s.StaleChunks))
+		}
+	case integrity.CheckDanglingDependencies:
+		for _, d := range report.DanglingDependencies {
+			lines = append(lines, fmt.Sprintf("[%s] %s: %s -> %s", d.DepType, d.Symbol, d.FromFilePath, d.ToFilePath))
+		}
+	}
+	if limit >= 0 && len(lines) > limit {
+		lines = append(lines[:limit], fmt.Sprintf("... 他 %d 件", len(lines)-limit))
+	}
+	return lines
+}
+
+// DBGCBlobsAction は参照するファイルがなくなったファイルの内容（file_blobs）を削除するコマンドのアクション
+// スナップショット・プロダクトを削除した後に実行する
+func DBGCBlobsAction(ctx context.Context, cmd *cli.Command) error {
+	envFile := cmd.String("env")
+
+	// 共通コンテキストの初期化
+	appCtx, err := NewAppContext(ctx, envFile)
+	if err != nil {
+		return err
+	}
+	defer appCtx.Close()
+
+	repo := appCtx.Container.IngestionRepo
+
+	before, err := repo.GetFileBlobStats(ctx)
+	if err != nil {
+		return fmt.Errorf("ファイルの内容の保存状況の取得に失敗: %w", err)
+	}
+	deleted, err := repo.DeleteUnreferencedFileBlobs(ctx)
+	if err != nil {
+		return fmt.Errorf("ファイルの内容の削除に失敗: %w", err)
+	}
+	after, err := repo.GetFileBlobStats(ctx)
+	if err != nil {
+		return fmt.Errorf("ファイルの内容の保存状況の取得に失敗: %w", err)
+	}
+
+	fmt.Printf("削除した内容: %d 件（%.1f MiB）\n", deleted, float64(before.TotalSize-after.TotalSize)/(1<<20))
+	fmt.Printf("保存中の内容: %d 件（%.1f MiB）\n", after.Count, float64(after.TotalSize)/(1<<20))
+	return nil
+}
@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"text/tabwriter"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+	"github.com/urfave/cli/v3"
+
+	"github.com/jinford/dev-rag/internal/core/drift"
+	"github.com/jinford/dev-rag/internal/core/thirdparty"
+)
+
+// DepsListAction はプロダクトの依存関係のマニフェスト・サブモジュールから抽出した外部の依存関係を表示するコマンドのアクション
+// --changes を指定した場合は、ソースごとに直前のインデックス済みスナップショットからのバージョンの変化を表示する
+func DepsListAction(ctx context.Context, cmd *cli.Command) error {
+	productName := cmd.String("product")
+	envFile := cmd.String("env")
+	ecosystem := thirdparty.Ecosystem(cmd.String("ecosystem"))
+	if ecosystem != "" && !slices.Contains(thirdparty.Ecosystems, ecosystem) {
+		return fmt.Errorf("--ecosystem は go・npm・pypi・submodule のいずれかを指定してください: %s", ecosystem)
+	}
+
+	// 共通コンテキストの初期化
+	appCtx, err := NewAppContext(ctx, envFile)
+	if err != nil {
+		return err
+	}
+	defer appCtx.Close()
+
+	productOpt, err := appCtx.Container.IngestionRepo.GetProductByName(ctx, productName)
+	if err != nil {
+		return fmt.Errorf("プロダクト取得に失敗: %w", err)
+	}
+	if productOpt.IsAbsent() {
+		return fmt.Errorf("プロダクトが見つかりません: %s", productName)
+	}
+	product := productOpt.MustGet()
+
+	report, err := appCtx.Container.ExternalDependencies.Report(ctx, mo.Some(product.ID), mo.None[uuid.UUID]())
+	if err != nil {
+		return fmt.Errorf("外部の依存関係の取得に失敗しました: %w", err)
+	}
+
+	if cmd.Bool("changes") {
+		return printDependencyChanges(report.Comparisons, ecosystem)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SOURCE\tVERSION\tECOSYSTEM\tNAME\tREQUIREMENT\tKIND\tVENDORED\tLOCATION\tMANIFEST")
+	count := 0
+	for _, d := range report.Dependencies {
+		if ecosystem != "" && d.Ecosystem != ecosystem {
+			continue
+		}
+		count++
+		vendored := "-"
+		if d.Vendored {
+			vendored = "yes"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			d.SourceName,
+			drift.ShortVersion(d.Version),
+			d.Ecosystem,
+			d.Name,
+			valueOrDash(d.Requirement),
+			d.Kind,
+			vendored,
+			valueOrDash(d.Location),
+			d.ManifestPath,
+		)
+	}
+	if count == 0 {
+		fmt.Println("外部の依存関係が見つかりません（go.mod・package.json・requirements*.txt・.gitmodules はインデックス化時に解析されます）")
+		return nil
+	}
+	return w.Flush()
+}
+
+// printDependencyChanges はソースごとに直前のスナップショットからの依存関係の変化を表示する
+func printDependencyChanges(comparisons []*thirdparty.Comparison, ecosystem thirdparty.Ecosystem) error {
+	if len(comparisons) == 0 {
+		fmt.Println("比較できる過去のスナップショットがありません（2回目以降のインデックス化の後に表示されます）")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SOURCE\tFROM\tTO\tCHANGE\tECOSYSTEM\tNAME\tBEFORE\tAFTER\tMANIFEST")
+	count := 0
+	for _, c := range comparisons {
+		for _, change := range c.Changes {
+			if ecosystem != "" && change.Ecosystem != ecosystem {
+				continue
+			}
+			count++
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				c.SourceName,
+				drift.ShortVersion(c.FromVersion),
+				drift.ShortVersion(c.ToVersion),
+				change.Kind,
+				change.Ecosystem,
+				change.Name,
+				valueOrDash(change.From),
+				valueOrDash(change.To),
+				change.ManifestPath,
+			)
+		}
+	}
+	if count == 0 {
+		fmt.Println("直前のスナップショットからの依存関係の変化はありません")
+		return nil
+	}
+	return w.Flush()
+}
@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/jinford/dev-rag/internal/platform/config"
+	"github.com/jinford/dev-rag/internal/platform/doctor"
+	"github.com/jinford/dev-rag/internal/platform/logger"
+)
+
+// doctorStatusLabels は診断結果の表示名
+var doctorStatusLabels = map[doctor.Status]string{
+	doctor.StatusOK:   "[OK]  ",
+	doctor.StatusWarn: "[WARN]",
+	doctor.StatusFail: "[FAIL]",
+	doctor.StatusSkip: "[SKIP]",
+}
+
+// DoctorAction はデータベース・外部API・Git・ディスクなど動作環境を診断するコマンドのアクション
+// 対処が必要な項目があれば終了コードをエラーにする
+func DoctorAction(ctx context.Context, cmd *cli.Command) error {
+	envFile := cmd.String("env")
+	asJSON := cmd.Bool("json")
+
+	cfg, err := config.Load(envFile)
+	if err != nil {
+		return fmt.Errorf("設定の読み込みに失敗: %w（`dev-rag config validate` で確認してください）", err)
+	}
+
+	report := doctor.New(cfg,
+		doctor.WithSkipAPI(cmd.Bool("skip-api")),
+		doctor.WithTimeout(time.Duration(cmd.Int("timeout"))*time.Second),
+		doctor.WithLogger(logger.New(logger.DefaultConfig())),
+	).Run(ctx)
+
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		for _, c := range report.Checks {
+			fmt.Printf("%s %s: %s\n", doctorStatusLabels[c.Status], c.Name, valueOrDash(c.Detail))
+			if c.Hint != "" {
+				fmt.Printf("       → %s\n", c.Hint)
+			}
+		}
+		fmt.Printf("\n成功 %d / 警告 %d / 失敗 %d / 省略 %d\n",
+			report.Count(doctor.StatusOK),
+			report.Count(doctor.StatusWarn),
+			report.Count(doctor.StatusFail),
+			report.Count(doctor.StatusSkip),
+		)
+	}
+
+	if failed := report.Count(doctor.StatusFail); failed > 0 {
+		return fmt.Errorf("%d 件の項目で対処が必要です", failed)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v3"
+
+	coreingestion "github.com/jinford/dev-rag/internal/core/ingestion"
+	"github.com/jinford/dev-rag/internal/infra/embedding"
+	"github.com/jinford/dev-rag/internal/platform/container"
+)
+
+// EmbeddingListAction は登録済みの Embedding モデルを次元数・生成済みの Embedding の数とともに表示するコマンドのアクション
+// 起動時の検証に失敗する場合でも確認できるよう、Embedding モデルの検証を省略する
+func EmbeddingListAction(ctx context.Context, cmd *cli.Command) error {
+	envFile := cmd.String("env")
+
+	// 共通コンテキストの初期化
+	appCtx, err := NewAppContext(ctx, envFile, container.WithoutEmbeddingModelCheck())
+	if err != nil {
+		return err
+	}
+	defer appCtx.Close()
+
+	models, err := appCtx.Container.EmbeddingModels.ListEmbeddingModels(ctx)
+	if err != nil {
+		return fmt.Errorf("Embeddingモデル一覧の取得に失敗: %w", err)
+	}
+	if len(models) == 0 {
+		fmt.Println("登録済みのEmbeddingモデルはありません")
+		return nil
+	}
+
+	configured := appCtx.Container.EmbeddingModelName()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "\tMODEL\tPROVIDER\tDIMENSION\tEMBEDDINGS")
+	for _, m := range models {
+		mark := ""
+		if m.Model == configured {
+			mark = "*"
+		}
+		dimension := fmt.Sprintf("%d", m.Dimension)
+		if m.VariableDimension {
+			dimension = fmt.Sprintf("<=%d", m.Dimension)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\n", mark, m.Model, m.Provider, dimension, m.EmbeddingCount)
+	}
+	return w.Flush()
+}
+
+// EmbeddingRegisterAction は Embedding モデルとその次元数を登録するコマンドのアクション
+// 未登録のモデルを設定すると起動できないため、Embedding モデルの検証を省略する
+func EmbeddingRegisterAction(ctx context.Context, cmd *cli.Command) error {
+	envFile := cmd.String("env")
+	model := &coreingestion.EmbeddingModel{
+		Model:             cmd.String("model"),
+		Provider:          cmd.String("provider"),
+		Dimension:         int(cmd.Int("dimension")),
+		VariableDimension: cmd.Bool("variable-dimension"),
+	}
+
+	if model.Dimension <= 0 {
+		return fmt.Errorf("--dimension には1以上の値を指定してください: %d", model.Dimension)
+	}
+
+	// 共通コンテキストの初期化
+	appCtx, err := NewAppContext(ctx, envFile, container.WithoutEmbeddingModelCheck())
+	if err != nil {
+		return err
+	}
+	defer appCtx.Close()
+
+	if model.Provider == "" {
+		model.Provider = appCtx.Config.Embedding.Provider
+	}
+	if _, err := embedding.Lookup(model.Provider); err != nil {
+		return fmt.Errorf("--provider が不正です: %w", err)
+	}
+
+	if err := appCtx.Container.EmbeddingModels.UpsertEmbeddingModel(ctx, model); err != nil {
+		return fmt.Errorf("Embeddingモデルの登録に失敗: %w", err)
+	}
+	slog.Info("Embeddingモデルを登録しました",
+		"model", model.Model,
+		"provider", model.Provider,
+		"dimension", model.Dimension,
+		"variableDimension", model.VariableDimension,
+	)
+	return nil
+}
@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/jinford/dev-rag/internal/core/usage"
+)
+
+// failureErrorWidth は一覧に表示するエラーの最大文字数
+const failureErrorWidth = 80
+
+// FailuresListAction はインデックス化に失敗したファイルと Embedding を生成できなかったチャンクを表示するコマンドのアクション
+func FailuresListAction(ctx context.Context, cmd *cli.Command) error {
+	productName := cmd.String("product")
+	envFile := cmd.String("env")
+	asJSON := cmd.Bool("json")
+
+	// 共通コンテキストの初期化
+	appCtx, err := NewAppContext(ctx, envFile)
+	if err != nil {
+		return err
+	}
+	defer appCtx.Close()
+
+	productOpt, err := appCtx.Container.IngestionRepo.GetProductByName(ctx, productName)
+	if err != nil {
+		return fmt.Errorf("プロダクト取得に失敗: %w", err)
+	}
+	product, ok := productOpt.Get()
+	if !ok {
+		return fmt.Errorf("プロダクトが見つかりません: %s", productName)
+	}
+
+	report, err := appCtx.Container.IndexService.ListFailures(ctx, product.ID)
+	if err != nil {
+		return err
+	}
+
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	if len(report.Files) == 0 && len(report.Chunks) == 0 {
+		slog.Info("失敗したファイル・チャンクはありません", "product", productName)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	if len(report.Files) > 0 {
+		fmt.Fprintf(w, "ファイル（%d件）\n", len(report.Files))
+		fmt.Fprintln(w, "SOURCE\tVERSION\tPATH\tSTAGE\tATTEMPTS\tLAST FAILED\tERROR")
+		for _, f := range report.Files {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\t%s\n",
+				f.SourceName,
+				shortHash(f.VersionIdentifier),
+				f.Path,
+				f.Stage,
+				f.Attempts,
+				f.LastFailedAt.Format(time.DateTime),
+				truncateFailureError(f.Error),
+			)
+		}
+	}
+	if len(report.Chunks) > 0 {
+		if len(report.Files) > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "チャンク（%d件）\n", len(report.Chunks))
+		fmt.Fprintln(w, "SOURCE\tPATH\tCHUNK\tATTEMPTS\tLAST FAILED\tERROR")
+		for _, c := range report.Chunks {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%s\n",
+				c.SourceName,
+				c.FilePath,
+				c.ChunkID,
+				c.Attempts,
+				c.LastFailedAt.Format(time.DateTime),
+				truncateFailureError(c.Error),
+			)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Printf("\n再試行: dev-rag failures retry --product %s\n", productName)
+	return nil
+}
+
+// FailuresRetryAction はインデックス化に失敗したファイルとチャンクを再試行するコマンドのアクション
+func FailuresRetryAction(ctx context.Context, cmd *cli.Command) error {
+	productName := cmd.String("product")
+	envFile := cmd.String("env")
+
+	// 共通コンテキストの初期化
+	appCtx, err := NewAppContext(ctx, envFile)
+	if err != nil {
+		return err
+	}
+	defer appCtx.Close()
+
+	productOpt, err := appCtx.Container.IngestionRepo.GetProductByName(ctx, productName)
+	if err != nil {
+		return fmt.Errorf("プロダクト取得に失敗: %w", err)
+	}
+	product, ok := productOpt.Get()
+	if !ok {
+		return fmt.Errorf("プロダクトが見つかりません: %s", productName)
+	}
+
+	// Embedding の使用量を計測し、予算を適用する
+	tracker := appCtx.Container.NewUsageTracker()
+	ctx = usage.WithTracker(ctx, tracker)
+
+	result, err := appCtx.Container.IndexService.RetryFailures(ctx, product.ID)
+	logUsage(tracker)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("ファイル: 対象 %d / 成功 %d / 失敗 %d\n", result.Files.Total, result.Files.Succeeded, result.Files.Failed)
+	fmt.Printf("チャンク: 対象 %d / 成功 %d / 失敗 %d\n", result.Chunks.Total, result.Chunks.Succeeded, result.Chunks.Failed)
+
+	if failed := result.Files.Failed + result.Chunks.Failed; failed > 0 {
+		return fmt.Errorf("%d件のファイル・チャンクが再び失敗しました（dev-rag failures list で確認できます）", failed)
+	}
+	return nil
+}
+
+// truncateFailureError はエラーを1行にまとめ、一覧に表示する長さに切り詰める
+func truncateFailureError(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if r := []rune(s); len(r) > failureErrorWidth {
+		return string(r[:failureErrorWidth-1]) + "…"
+	}
+	return s
+}
@@ -0,0 +1,168 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"unicode/utf8"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/jinford/dev-rag/internal/core/docextract"
+	coreingestion "github.com/jinford/dev-rag/internal/core/ingestion"
+)
+
+// FileShowAction はインデックス済みのファイルのメタデータとチャンクの一覧を表示するコマンドのアクション
+func FileShowAction(ctx context.Context, cmd *cli.Command) error {
+	sourceName := cmd.String("source")
+	path := cmd.String("path")
+	version := cmd.String("version")
+	showContent := cmd.Bool("content")
+	envFile := cmd.String("env")
+
+	// 共通コンテキストの初期化
+	appCtx, err := NewAppContext(ctx, envFile)
+	if err != nil {
+		return err
+	}
+	defer appCtx.Close()
+
+	repo := appCtx.Container.IngestionRepo
+
+	sourceOpt, err := repo.GetSourceByName(ctx, sourceName)
+	if err != nil {
+		return fmt.Errorf("ソースの取得に失敗: %w", err)
+	}
+	source, ok := sourceOpt.Get()
+	if !ok {
+		return fmt.Errorf("ソースが見つかりません: %s", sourceName)
+	}
+
+	// バージョン指定がなければ最新のインデックス済みスナップショットを対象にする
+	snapshotOpt, err := repo.GetLatestIndexedSnapshot(ctx, source.ID)
+	if version != "" {
+		snapshotOpt, err = repo.GetSnapshotByVersion(ctx, source.ID, version)
+	}
+	if err != nil {
+		return fmt.Errorf("スナップショットの取得に失敗: %w", err)
+	}
+	snapshot, ok := snapshotOpt.Get()
+	if !ok {
+		return fmt.Errorf("インデックス済みのスナップショットが見つかりません: %s", sourceName)
+	}
+
+	fileOpt, err := repo.GetFileByPath(ctx, snapshot.ID, path)
+	if err != nil {
+		return fmt.Errorf("ファイルの取得に失敗: %w", err)
+	}
+	file, ok := fileOpt.Get()
+	if !ok {
+		return fmt.Errorf("ファイルがインデックスされていません: %s（スナップショット %s）", path, shortHash(snapshot.VersionIdentifier))
+	}
+
+	chunks, err := repo.ListChunksByFile(ctx, file.ID)
+	if err != nil {
+		return fmt.Errorf("チャンク一覧の取得に失敗: %w", err)
+	}
+
+	fmt.Printf("ID:             %s\n", file.ID)
+	fmt.Printf("パス:           %s\n", file.Path)
+	fmt.Printf("ソース:         %s\n", source.Name)
+	fmt.Printf("スナップショット: %s（%s）\n", shortHash(snapshot.VersionIdentifier), snapshot.ID)
+	fmt.Printf("サイズ:         %d bytes\n", file.Size)
+	fmt.Printf("コンテンツ種別: %s\n", file.ContentType)
+	fmt.Printf("言語:           %s\n", formatOptionalString(file.Language))
+	fmt.Printf("ドメイン:       %s\n", formatOptionalString(file.Domain))
+	fmt.Printf("ハッシュ:       %s\n", file.ContentHash)
+	fmt.Printf("チャンク数:     %d\n", len(chunks))
+
+	if len(chunks) > 0 {
+		if err := printFileChunks(chunks); err != nil {
+			return err
+		}
+	}
+	if showContent {
+		return printFileContent(ctx, repo, file)
+	}
+	return nil
+}
+
+// printFileChunks はファイルのチャンクの一覧を表示する
+func printFileChunks(chunks []*coreingestion.Chunk) error {
+	// レベル（1: ファイル要約、2: 関数/クラス、3: ロジック単位）に応じて名前を字下げして階層を表す
+	fmt.Println()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ORD\tLINES\tTYPE\tNAME\tTOKENS\tIMPORTANCE\tID")
+	for _, c := range chunks {
+		importance := "-"
+		if c.ImportanceScore != nil {
+			importance = fmt.Sprintf("%.4f", *c.ImportanceScore)
+		}
+		indent := strings.Repeat("  ", max(c.Level-1, 0))
+		// Markdownのチャンクは名前の代わりに見出しの階層を表示する
+		name := formatOptionalString(c.Name)
+		if c.Name == nil && c.Breadcrumb != nil {
+			name = *c.Breadcrumb
+		}
+		fmt.Fprintf(w, "%d\tL%d-L%d\t%s\t%s%s\t%d\t%s\t%s\n",
+			c.Ordinal,
+			c.StartLine,
+			c.EndLine,
+			formatOptionalString(c.Type),
+			indent,
+			name,
+			c.TokenCount,
+			importance,
+			c.ID,
+		)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Println("\n各チャンクの詳細は `chunk show --id <ID>` で表示できます")
+	return nil
+}
+
+// printFileContent は保存したファイルの内容（原文）を表示する
+// PDF・Office文書は抽出したテキストを表示する
+func printFileContent(ctx context.Context, repo coreingestion.Repository, file *coreingestion.File) error {
+	fmt.Println("\n--- 内容 ---")
+	text, err := loadFileText(ctx, repo, file)
+	if err != nil {
+		return err
+	}
+	if text == "" {
+		return nil
+	}
+	fmt.Println(text)
+	return nil
+}
+
+// loadFileText は保存したファイルの内容をテキストとして取得する（チャンクの行番号と対応するテキスト）
+// 内容を保存していない場合・バイナリの場合は理由を表示して空文字列を返す
+func loadFileText(ctx context.Context, repo coreingestion.Repository, file *coreingestion.File) (string, error) {
+	blobOpt, err := repo.GetFileBlob(ctx, file.ContentHash)
+	if err != nil {
+		return "", fmt.Errorf("ファイルの内容の取得に失敗: %w", err)
+	}
+	content, ok := blobOpt.Get()
+	if !ok {
+		fmt.Println("（内容が保存されていません。PIPELINE_STORE_FILE_BLOBS=true で再インデックス化すると保存されます）")
+		return "", nil
+	}
+
+	if format, ok := docextract.DetectFormat(file.Path); ok {
+		document, err := docextract.Extract(format, content)
+		if err != nil {
+			return "", fmt.Errorf("テキストの抽出に失敗: %w", err)
+		}
+		return document.Text(), nil
+	}
+	if !utf8.Valid(content) {
+		fmt.Printf("（バイナリのため表示しません: %d bytes）\n", len(content))
+		return "", nil
+	}
+	return string(content), nil
+}
@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/jinford/dev-rag/internal/core/usage"
+)
+
+// GlossaryExtractAction はプロダクトのインデックス済みのドキュメント・コードから用語集を抽出するコマンドのアクション
+func GlossaryExtractAction(ctx context.Context, cmd *cli.Command) error {
+	productName := cmd.String("product")
+	envFile := cmd.String("env")
+
+	// 共通コンテキストの初期化
+	appCtx, err := NewAppContext(ctx, envFile)
+	if err != nil {
+		return err
+	}
+	defer appCtx.Close()
+
+	productOpt, err := appCtx.Container.IngestionRepo.GetProductByName(ctx, productName)
+	if err != nil {
+		return fmt.Errorf("プロダクト取得に失敗: %w", err)
+	}
+	if productOpt.IsAbsent() {
+		return fmt.Errorf("プロダクトが見つかりません: %s", productName)
+	}
+	product := productOpt.MustGet()
+
+	// LLM の使用量を計測し、予算を適用する
+	tracker := appCtx.Container.NewUsageTracker()
+	ctx = usage.WithTracker(ctx, tracker)
+
+	slog.Info("用語集の抽出を開始します", "product", product.Name)
+	result, err := appCtx.Container.GlossaryService.Extract(ctx, product.ID)
+	logUsage(tracker)
+	if err != nil {
+		return fmt.Errorf("用語集の抽出に失敗しました: %w", err)
+	}
+
+	slog.Info("用語集の抽出が完了しました",
+		"product", product.Name,
+		"sources", result.Sources,
+		"batches", result.Batches,
+		"failedBatches", result.FailedBatch,
+		"terms", result.Terms,
+		"deleted", result.DeletedTerms,
+	)
+	return nil
+}
+
+// GlossaryListAction はプロダクトの用語集を表示するコマンドのアクション
+func GlossaryListAction(ctx context.Context, cmd *cli.Command) error {
+	productName := cmd.String("product")
+	envFile := cmd.String("env")
+
+	// 共通コンテキストの初期化
+	appCtx, err := NewAppContext(ctx, envFile)
+	if err != nil {
+		return err
+	}
+	defer appCtx.Close()
+
+	productOpt, err := appCtx.Container.IngestionRepo.GetProductByName(ctx, productName)
+	if err != nil {
+		return fmt.Errorf("プロダクト取得に失敗: %w", err)
+	}
+	if productOpt.IsAbsent() {
+		return fmt.Errorf("プロダクトが見つかりません: %s", productName)
+	}
+	product := productOpt.MustGet()
+
+	terms, err := appCtx.Container.GlossaryService.ListTerms(ctx, product.ID)
+	if err != nil {
+		return fmt.Errorf("用語集の取得に失敗しました: %w", err)
+	}
+	if len(terms) == 0 {
+		fmt.Println("用語集が空です（dev-rag glossary extract で抽出してください）")
+		return nil
+	}
+
+	for _, term := range terms {
+		fmt.Printf("%s [%s]\n", term.Term, term.Kind)
+		fmt.Println(indent(term.Definition, "    "))
+		if len(term.Aliases) > 0 {
+			fmt.Printf("    別名: %s\n", strings.Join(term.Aliases, ", "))
+		}
+		if len(term.SourcePaths) > 0 {
+			fmt.Printf("    出典: %s\n", strings.Join(term.SourcePaths, ", "))
+		}
+	}
+	return nil
+}
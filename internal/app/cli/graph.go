@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+	"github.com/urfave/cli/v3"
+
+	"github.com/jinford/dev-rag/internal/core/graph"
+)
+
+// GraphExportAction はプロダクトのチャンクの依存関係グラフを Graphviz・Gephi などで可視化できる形式で出力するコマンドのアクション
+func GraphExportAction(ctx context.Context, cmd *cli.Command) error {
+	productName := cmd.String("product")
+	envFile := cmd.String("env")
+	outputPath := cmd.String("output")
+
+	format := graph.Format(cmd.String("format"))
+	if !slices.Contains(graph.Formats, format) {
+		return fmt.Errorf("--format は dot、json、graphml のいずれかを指定してください: %s", format)
+	}
+
+	filter := graph.Filter{PathPrefix: cmd.String("path-prefix")}
+	if cmd.IsSet("min-importance") {
+		minImportance := cmd.Float("min-importance")
+		if minImportance < 0 || minImportance > 1 {
+			return fmt.Errorf("--min-importance は 0〜1 の範囲で指定してください: %v", minImportance)
+		}
+		filter.MinImportance = &minImportance
+	}
+
+	// 共通コンテキストの初期化
+	appCtx, err := NewAppContext(ctx, envFile)
+	if err != nil {
+		return err
+	}
+	defer appCtx.Close()
+
+	productOpt, err := appCtx.Container.IngestionRepo.GetProductByName(ctx, productName)
+	if err != nil {
+		return fmt.Errorf("プロダクト取得に失敗: %w", err)
+	}
+	if productOpt.IsAbsent() {
+		return fmt.Errorf("プロダクトが見つかりません: %s", productName)
+	}
+	product := productOpt.MustGet()
+
+	g, err := appCtx.Container.DependencyGraph.ReadGraph(ctx, mo.Some(product.ID), mo.None[uuid.UUID](), filter)
+	if err != nil {
+		return fmt.Errorf("依存関係グラフの取得に失敗しました: %w", err)
+	}
+
+	var w io.Writer = os.Stdout
+	if outputPath != "" {
+		f, err := os.Create(outputPath)
+		if err != nil {
+			return fmt.Errorf("出力ファイルの作成に失敗しました: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := graph.Write(w, g, format); err != nil {
+		return fmt.Errorf("依存関係グラフの出力に失敗しました: %w", err)
+	}
+
+	if outputPath != "" {
+		appCtx.Logger().Info("依存関係グラフを出力しました",
+			"output", outputPath,
+			"nodes", len(g.Nodes),
+			"edges", len(g.Edges),
+		)
+	}
+	return nil
+}
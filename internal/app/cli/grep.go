@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/urfave/cli/v3"
+
+	coresearch "github.com/jinford/dev-rag/internal/core/search"
+)
+
+// GrepAction はインデックス済みのチャンクの内容を正規表現で検索し、一致した行を表示するコマンドのアクション
+// リポジトリをクローンせずに、インデックス済みのスナップショットに対して完全一致の検索を行うためのもの
+func GrepAction(ctx context.Context, cmd *cli.Command) error {
+	productName := cmd.String("product")
+	envFile := cmd.String("env")
+	limit := int(cmd.Int("limit"))
+	asJSON := cmd.Bool("json")
+
+	pattern := cmd.Args().First()
+	if pattern == "" {
+		return fmt.Errorf("検索パターンを指定してください")
+	}
+	if limit <= 0 {
+		return fmt.Errorf("--limit には1以上の値を指定してください")
+	}
+	scope, err := snapshotScopeFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+
+	// 共通コンテキストの初期化
+	appCtx, err := NewAppContext(ctx, envFile)
+	if err != nil {
+		return err
+	}
+	defer appCtx.Close()
+
+	repo := appCtx.Container.IngestionRepo
+
+	productOpt, err := repo.GetProductByName(ctx, productName)
+	if err != nil {
+		return fmt.Errorf("プロダクト取得に失敗: %w", err)
+	}
+	product, ok := productOpt.Get()
+	if !ok {
+		return fmt.Errorf("プロダクトが見つかりません: %s", productName)
+	}
+
+	snapshotID, err := scope.resolve(ctx, repo, product.ID)
+	if err != nil {
+		return err
+	}
+
+	result, err := appCtx.Container.SearchService.Grep(ctx, coresearch.GrepParams{
+		ProductID:  product.ID,
+		SnapshotID: snapshotID,
+		Pattern:    pattern,
+		IgnoreCase: cmd.Bool("ignore-case"),
+		Fixed:      cmd.Bool("fixed-strings"),
+		PathPrefix: cmd.String("path-prefix"),
+		PathGlob:   cmd.String("glob"),
+		Limit:      limit,
+	})
+	if err != nil {
+		return fmt.Errorf("検索に失敗: %w", err)
+	}
+
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	}
+
+	if len(result.Matches) == 0 {
+		fmt.Println("一致する行がありません")
+		return nil
+	}
+
+	// 同じパスのファイルが複数のソースにある場合に区別できるよう、ソースが複数のときはソース名を前置する
+	sources := make(map[string]bool)
+	for _, m := range result.Matches {
+		sources[m.SourceName] = true
+	}
+	for _, m := range result.Matches {
+		if len(sources) > 1 {
+			fmt.Printf("%s:", m.SourceName)
+		}
+		fmt.Printf("%s:%d:%s\n", m.FilePath, m.Line, m.Text)
+	}
+	if result.Truncated {
+		slog.Warn("一致する行が上限に達したため、表示していない行があります（--limit で上限を変更できます）", "limit", limit)
+	}
+	return nil
+}
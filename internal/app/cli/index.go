@@ -0,0 +1,497 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/urfave/cli/v3"
+	"gopkg.in/yaml.v3"
+
+	coreingestion "github.com/jinford/dev-rag/internal/core/ingestion"
+	"github.com/jinford/dev-rag/internal/core/ingestion/chunk"
+	"github.com/jinford/dev-rag/internal/core/usage"
+)
+
+// IndexStatsAction はプロダクトの各ソースの最新スナップショットについてインデックスの品質を診断するコマンドのアクション
+func IndexStatsAction(ctx context.Context, cmd *cli.Command) error {
+	productName := cmd.String("product")
+	tokenLimit := int(cmd.Int("token-limit"))
+	envFile := cmd.String("env")
+
+	if tokenLimit <= 0 {
+		tokenLimit = chunk.DefaultChunkerConfig().MaxTokens
+	}
+
+	// 共通コンテキストの初期化
+	appCtx, err := NewAppContext(ctx, envFile)
+	if err != nil {
+		return err
+	}
+	defer appCtx.Close()
+
+	repo := appCtx.Container.IngestionRepo
+
+	productOpt, err := repo.GetProductByName(ctx, productName)
+	if err != nil {
+		return fmt.Errorf("プロダクト取得に失敗: %w", err)
+	}
+	if productOpt.IsAbsent() {
+		return fmt.Errorf("プロダクトが見つかりません: %s", productName)
+	}
+	product := productOpt.MustGet()
+
+	sources, err := repo.ListSourcesByProductID(ctx, product.ID)
+	if err != nil {
+		return fmt.Errorf("ソース一覧の取得に失敗: %w", err)
+	}
+
+	reported := 0
+	for _, source := range sources {
+		snapshotOpt, err := repo.GetLatestIndexedSnapshot(ctx, source.ID)
+		if err != nil {
+			return fmt.Errorf("スナップショットの取得に失敗: %w", err)
+		}
+		snapshot, ok := snapshotOpt.Get()
+		if !ok {
+			continue
+		}
+
+		health, err := repo.GetSnapshotIndexHealth(ctx, snapshot.ID, tokenLimit)
+		if err != nil {
+			return fmt.Errorf("インデックス統計の取得に失敗: %w", err)
+		}
+
+		if reported > 0 {
+			fmt.Println()
+		}
+		printIndexHealth(source, snapshot, health)
+		reported++
+	}
+
+	if reported == 0 {
+		fmt.Printf("インデックス済みのスナップショットがありません: %s\n", productName)
+	}
+	return nil
+}
+
+// printIndexHealth はスナップショットの診断情報を表示する
+func printIndexHealth(source *coreingestion.Source, snapshot *coreingestion.SourceSnapshot, h *coreingestion.SnapshotIndexHealth) {
+	fmt.Printf("=== %s（スナップショット %s）===\n", source.Name, shortHash(snapshot.VersionIdentifier))
+	fmt.Printf("ファイル数:         %d\n", h.FileCount)
+	fmt.Printf("チャンク数:         %d\n", h.ChunkCount)
+	fmt.Printf("Embedding数:        %d\n", h.EmbeddingCount)
+	fmt.Printf("平均トークン数:     %.1f（最大 %d）\n", h.AvgTokens, h.MaxTokens)
+	fmt.Printf("Embeddingなし:      %d\n", h.OrphanChunkCount)
+	fmt.Printf("トークン上限超過:   %d（上限 %d）\n", h.OversizedChunkCount, h.TokenLimit)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	printChunkCounts(w, "レベル", h.ByLevel, h.ChunkCount)
+	printChunkCounts(w, "種類", h.ByType, h.ChunkCount)
+	printChunkCounts(w, "ドメイン", h.ByDomain, h.ChunkCount)
+	printChunkCounts(w, "Embeddingモデル", h.ByEmbeddingModel, h.EmbeddingCount)
+	_ = w.Flush()
+}
+
+// printChunkCounts は集計キーごとのチャンク数と割合を表形式で表示する
+func printChunkCounts(w *tabwriter.Writer, title string, counts []*coreingestion.ChunkCountByKey, total int) {
+	if len(counts) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\n--- %s別 ---\n", title)
+	fmt.Fprintln(w, "KEY\tCHUNKS\tRATIO")
+	for _, c := range counts {
+		ratio := 0.0
+		if total > 0 {
+			ratio = float64(c.ChunkCount) / float64(total) * 100
+		}
+		fmt.Fprintf(w, "%s\t%d\t%.1f%%\n", c.Key, c.ChunkCount, ratio)
+	}
+}
+
+// batchSourcesFile は index batch --file で指定するインデックス対象の一覧（YAML）
+type batchSourcesFile struct {
+	Sources []batchSource `yaml:"sources"`
+}
+
+// batchSource はインデックス対象の1ソース
+type batchSource struct {
+	URL       string   `yaml:"url"`        // GitリポジトリURL
+	Product   string   `yaml:"product"`    // プロダクト名（存在しない場合は自動作成）
+	Refs      []string `yaml:"refs"`       // ブランチ名またはタグ名（省略時はリモートのdefault_branch）
+	Subtree   string   `yaml:"subtree"`    // インデックス化の対象を限定するディレクトリ（モノレポのサービスなど）
+	ForceInit bool     `yaml:"force_init"` // 強制的にフルインデックスを実行
+}
+
+// batchJob はソースと参照の組ごとのインデックス化の単位
+type batchJob struct {
+	url       string
+	product   string
+	ref       string
+	subtree   string
+	forceInit bool
+}
+
+// label はログと結果表示に使うジョブの名前
+func (j batchJob) label() string {
+	label := j.url
+	if j.subtree != "" {
+		label += "//" + j.subtree
+	}
+	if j.ref == "" {
+		return label
+	}
+	return label + "@" + j.ref
+}
+
+// loadBatchJobs はインデックス対象の一覧を読み込み、ソースと参照の組ごとのジョブに展開する
+func loadBatchJobs(path string) ([]batchJob, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("ソース一覧ファイルのオープンに失敗: %w", err)
+	}
+	defer f.Close()
+
+	// 未知のキーは設定ミスとしてエラーにする
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+
+	var file batchSourcesFile
+	if err := dec.Decode(&file); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("ソース一覧ファイルが空です: %s", path)
+		}
+		return nil, fmt.Errorf("ソース一覧ファイルの解析に失敗: %w", err)
+	}
+
+	var jobs []batchJob
+	seen := make(map[string]bool)
+	for i, src := range file.Sources {
+		if src.URL == "" || src.Product == "" {
+			return nil, fmt.Errorf("sources[%d]: url と product は必須です", i)
+		}
+		refs := src.Refs
+		if len(refs) == 0 {
+			refs = []string{""}
+		}
+		for _, ref := range refs {
+			job := batchJob{url: src.URL, product: src.Product, ref: ref, subtree: cleanSubtree(src.Subtree), forceInit: src.ForceInit}
+			if seen[job.label()] {
+				return nil, fmt.Errorf("sources[%d]: 同じソース・参照が重複しています: %s", i, job.label())
+			}
+			seen[job.label()] = true
+			jobs = append(jobs, job)
+		}
+	}
+	if len(jobs) == 0 {
+		return nil, fmt.Errorf("インデックス対象のソースがありません: %s", path)
+	}
+	return jobs, nil
+}
+
+// IndexBatchAction はソース一覧ファイルに記載した複数のソースを並行してインデックス化するコマンドのアクション
+// 同じソース・参照のインデックス化はアドバイザリロックで直列化し、LLM/Embedding のレート制限はワーカー間で共有する
+func IndexBatchAction(ctx context.Context, cmd *cli.Command) error {
+	filePath := cmd.String("file")
+	concurrency := int(cmd.Int("concurrency"))
+	generateWiki := cmd.Bool("generate-wiki")
+	envFile := cmd.String("env")
+
+	if concurrency <= 0 {
+		return fmt.Errorf("--concurrency は1以上を指定してください: %d", concurrency)
+	}
+
+	jobs, err := loadBatchJobs(filePath)
+	if err != nil {
+		return err
+	}
+
+	// 共通コンテキストの初期化
+	appCtx, err := NewAppContext(ctx, envFile)
+	if err != nil {
+		return err
+	}
+	defer appCtx.Close()
+
+	// ロックの保持にワーカーごとに1接続を使うため、インデックス処理用の接続が残るよう並行数を制限する
+	if db := appCtx.Container.Database(); db != nil {
+		if limit := int(db.Pool.Config().MaxConns) / 2; limit > 0 && concurrency > limit {
+			slog.Warn("DB接続プールの上限に合わせて並行数を制限します", "requested", concurrency, "concurrency", limit)
+			concurrency = limit
+		}
+	}
+
+	slog.Info("一括インデックス処理を開始", "file", filePath, "jobs", len(jobs), "concurrency", concurrency)
+
+	// 並行実行時のプロダクト作成の競合を避けるため先に作成しておく
+	created := make(map[string]bool)
+	for _, job := range jobs {
+		if created[job.product] {
+			continue
+		}
+		if _, err := appCtx.Container.IngestionRepo.CreateProductIfNotExists(ctx, job.product, nil); err != nil {
+			return fmt.Errorf("プロダクトの取得/作成に失敗: %w", err)
+		}
+		created[job.product] = true
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	errs := make([]error, len(jobs))
+	durations := make([]time.Duration, len(jobs))
+	for i, job := range jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			start := time.Now()
+			if err := executeGitIndexing(ctx, appCtx, job.url, job.product, job.ref, job.subtree, "", job.forceInit, generateWiki, ""); err != nil {
+				slog.Error("Gitソースインデックス処理に失敗しました", "source", job.label(), "error", err)
+				errs[i] = fmt.Errorf("%s: %w", job.label(), err)
+			}
+			durations[i] = time.Since(start)
+		}()
+	}
+	wg.Wait()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SOURCE\tPRODUCT\tSTATUS\tDURATION")
+	for i, job := range jobs {
+		status := "成功"
+		if errs[i] != nil {
+			status = "失敗"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", job.label(), job.product, status, durations[i].Round(time.Second))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+	slog.Info("一括インデックス処理が完了しました", "jobs", len(jobs))
+	return nil
+}
+
+// IndexRetryEmbeddingsAction はインデックス化で Embedding を生成できなかったチャンクの Embedding を再生成するコマンドのアクション
+func IndexRetryEmbeddingsAction(ctx context.Context, cmd *cli.Command) error {
+	productName := cmd.String("product")
+	envFile := cmd.String("env")
+
+	// 共通コンテキストの初期化
+	appCtx, err := NewAppContext(ctx, envFile)
+	if err != nil {
+		return err
+	}
+	defer appCtx.Close()
+
+	productOpt, err := appCtx.Container.IngestionRepo.GetProductByName(ctx, productName)
+	if err != nil {
+		return fmt.Errorf("プロダクト取得に失敗: %w", err)
+	}
+	product, ok := productOpt.Get()
+	if !ok {
+		return fmt.Errorf("プロダクトが見つかりません: %s", productName)
+	}
+
+	// Embedding の使用量を計測し、予算を適用する
+	tracker := appCtx.Container.NewUsageTracker()
+	ctx = usage.WithTracker(ctx, tracker)
+
+	result, err := appCtx.Container.IndexService.RetryFailedEmbeddings(ctx, product.ID)
+	logUsage(tracker)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("対象チャンク数: %d\n", result.Total)
+	fmt.Printf("成功:           %d\n", result.Succeeded)
+	fmt.Printf("失敗:           %d\n", result.Failed)
+
+	if result.Failed > 0 {
+		return fmt.Errorf("%d件のチャンクで Embedding を生成できませんでした", result.Failed)
+	}
+	return nil
+}
+
+// IndexReembedAction は Embedding がない、または現在の Embedding モデル以外で生成したチャンクと要約の Embedding を再生成するコマンドのアクション
+// Embedding モデルやベクトルカラムの次元数を変更した後に実行する
+func IndexReembedAction(ctx context.Context, cmd *cli.Command) error {
+	productName := cmd.String("product")
+	envFile := cmd.String("env")
+
+	// 共通コンテキストの初期化
+	appCtx, err := NewAppContext(ctx, envFile)
+	if err != nil {
+		return err
+	}
+	defer appCtx.Close()
+
+	repo := appCtx.Container.IngestionRepo
+	productOpt, err := repo.GetProductByName(ctx, productName)
+	if err != nil {
+		return fmt.Errorf("プロダクト取得に失敗: %w", err)
+	}
+	product, ok := productOpt.Get()
+	if !ok {
+		return fmt.Errorf("プロダクトが見つかりません: %s", productName)
+	}
+
+	// Embedding の使用量を計測し、予算を適用する
+	tracker := appCtx.Container.NewUsageTracker()
+	ctx = usage.WithTracker(ctx, tracker)
+	defer logUsage(tracker)
+
+	result, err := appCtx.Container.IndexService.ReembedChunks(ctx, product.ID)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Embeddingモデル: %s\n", appCtx.Container.EmbeddingModelName())
+	fmt.Printf("チャンク:         %d（成功 %d、失敗 %d）\n", result.Total, result.Succeeded, result.Failed)
+
+	// 要約の Embedding は各ソースの最新スナップショットのみ再生成する
+	sources, err := repo.ListSourcesByProductID(ctx, product.ID)
+	if err != nil {
+		return fmt.Errorf("ソース一覧の取得に失敗: %w", err)
+	}
+	summaryFailed := 0
+	for _, source := range sources {
+		snapshotOpt, err := repo.GetLatestIndexedSnapshot(ctx, source.ID)
+		if err != nil {
+			return fmt.Errorf("スナップショットの取得に失敗: %w", err)
+		}
+		snapshot, ok := snapshotOpt.Get()
+		if !ok {
+			continue
+		}
+		summaries, err := appCtx.Container.SummaryService.Reembed(ctx, snapshot.ID)
+		if err != nil {
+			return fmt.Errorf("要約のEmbeddingの再生成に失敗しました (%s): %w", source.Name, err)
+		}
+		fmt.Printf("要約（%s）: %d（再生成 %d、失敗 %d）\n", source.Name, summaries.Total, summaries.Regenerated, summaries.Failed)
+		summaryFailed += summaries.Failed
+	}
+
+	if result.Failed > 0 || summaryFailed > 0 {
+		return fmt.Errorf("%d件のチャンク・%d件の要約で Embedding を生成できませんでした（再度実行すると失敗したものだけを再生成します）",
+			result.Failed, summaryFailed)
+	}
+	return nil
+}
+
+// IndexRechunkAction は保存済みのファイルの内容を現在のチャンカーで再チャンク化し、変わらないチャンクの Embedding を再利用するコマンドのアクション
+func IndexRechunkAction(ctx context.Context, cmd *cli.Command) error {
+	productName := cmd.String("product")
+	outdatedOnly := cmd.Bool("outdated")
+	envFile := cmd.String("env")
+
+	// 共通コンテキストの初期化
+	appCtx, err := NewAppContext(ctx, envFile)
+	if err != nil {
+		return err
+	}
+	defer appCtx.Close()
+
+	productOpt, err := appCtx.Container.IngestionRepo.GetProductByName(ctx, productName)
+	if err != nil {
+		return fmt.Errorf("プロダクト取得に失敗: %w", err)
+	}
+	product, ok := productOpt.Get()
+	if !ok {
+		return fmt.Errorf("プロダクトが見つかりません: %s", productName)
+	}
+
+	// Embedding の使用量を計測し、予算を適用する
+	tracker := appCtx.Container.NewUsageTracker()
+	ctx = usage.WithTracker(ctx, tracker)
+	defer logUsage(tracker)
+
+	result, err := appCtx.Container.IndexService.Rechunk(ctx, product.ID, outdatedOnly)
+	if result != nil {
+		fmt.Printf("ファイル:    %d（再チャンク化 %d、変更なし %d、内容未保存 %d、失敗 %d）\n",
+			result.Files, result.Rechunked, result.Unchanged, result.MissingContent, result.FailedFiles)
+		fmt.Printf("チャンク:    %d\n", result.Chunks)
+		fmt.Printf("Embedding:   再利用 %d、生成 %d、失敗 %d\n", result.ReusedEmbeddings, result.NewEmbeddings, result.FailedEmbeddings)
+	}
+	if err != nil {
+		return err
+	}
+
+	if result.MissingContent > 0 {
+		fmt.Println("内容が保存されていないファイルは、再度インデックス化すると再チャンク化の対象になります（PIPELINE_STORE_FILE_BLOBS）")
+	}
+	if result.FailedFiles > 0 || result.FailedEmbeddings > 0 {
+		return fmt.Errorf("%d件のファイルの再チャンク化、%d件のチャンクの Embedding の生成に失敗しました（failures retry・index retry-embeddings で再試行できます）",
+			result.FailedFiles, result.FailedEmbeddings)
+	}
+	return nil
+}
+
+// IndexEmbedModelsAction は追加のEmbeddingモデル（EMBEDDING_ADDITIONAL_MODELS）の Embedding がないチャンクの Embedding を生成するコマンドのアクション
+func IndexEmbedModelsAction(ctx context.Context, cmd *cli.Command) error {
+	productName := cmd.String("product")
+	modelName := cmd.String("model")
+	envFile := cmd.String("env")
+
+	// 共通コンテキストの初期化
+	appCtx, err := NewAppContext(ctx, envFile)
+	if err != nil {
+		return err
+	}
+	defer appCtx.Close()
+
+	embedders := appCtx.Container.AdditionalEmbedders
+	if len(embedders) == 0 {
+		return fmt.Errorf("追加のEmbeddingモデルが設定されていません（EMBEDDING_ADDITIONAL_MODELS を設定してください）")
+	}
+	models := make([]string, 0, len(embedders))
+	for model := range embedders {
+		models = append(models, model)
+	}
+	slices.Sort(models)
+	if modelName != "" {
+		if _, ok := embedders[modelName]; !ok {
+			return fmt.Errorf("追加のEmbeddingモデルに設定されていません: %s（設定済み: %v）", modelName, models)
+		}
+		models = []string{modelName}
+	}
+
+	productOpt, err := appCtx.Container.IngestionRepo.GetProductByName(ctx, productName)
+	if err != nil {
+		return fmt.Errorf("プロダクト取得に失敗: %w", err)
+	}
+	product, ok := productOpt.Get()
+	if !ok {
+		return fmt.Errorf("プロダクトが見つかりません: %s", productName)
+	}
+
+	// Embedding の使用量を計測し、予算を適用する
+	tracker := appCtx.Container.NewUsageTracker()
+	ctx = usage.WithTracker(ctx, tracker)
+	defer logUsage(tracker)
+
+	failed := 0
+	for _, model := range models {
+		result, err := appCtx.Container.IndexService.EmbedWithModel(ctx, product.ID, embedders[model])
+		if err != nil {
+			return fmt.Errorf("Embeddingの生成に失敗しました (%s): %w", model, err)
+		}
+		fmt.Printf("%s: %d（成功 %d、失敗 %d）\n", model, result.Total, result.Succeeded, result.Failed)
+		failed += result.Failed
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d件のチャンクで Embedding を生成できませんでした（再度実行すると生成できなかったものだけを生成します）", failed)
+	}
+	return nil
+}
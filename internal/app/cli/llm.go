@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/urfave/cli/v3"
+)
+
+// LLMCacheStatsAction はLLMの応答のキャッシュをプロンプトテンプレート・モデルごとに集計して表示するコマンドのアクション
+func LLMCacheStatsAction(ctx context.Context, cmd *cli.Command) error {
+	envFile := cmd.String("env")
+
+	// 共通コンテキストの初期化
+	appCtx, err := NewAppContext(ctx, envFile)
+	if err != nil {
+		return err
+	}
+	defer appCtx.Close()
+
+	stats, err := appCtx.Container.LLMCache.Stats(ctx)
+	if err != nil {
+		return fmt.Errorf("LLMキャッシュの集計に失敗: %w", err)
+	}
+	if len(stats) == 0 {
+		fmt.Println("キャッシュした応答はありません")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PROMPT\tVERSION\tMODEL\tENTRIES\tHITS\tSIZE(KB)\tLAST HIT")
+	for _, s := range stats {
+		lastHit := "-"
+		if s.LastHitAt != nil {
+			lastHit = s.LastHitAt.Format(time.DateTime)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\t%.1f\t%s\n",
+			s.PromptName, s.PromptVersion, s.Model, s.Entries, s.Hits, float64(s.ResponseBytes)/1024, lastHit)
+	}
+	return w.Flush()
+}
+
+// LLMCacheClearAction はLLMの応答のキャッシュを削除するコマンドのアクション
+func LLMCacheClearAction(ctx context.Context, cmd *cli.Command) error {
+	envFile := cmd.String("env")
+	promptName := cmd.String("prompt")
+	olderThanDays := cmd.Int("older-than-days")
+
+	if olderThanDays < 0 {
+		return fmt.Errorf("--older-than-days には0以上の日数を指定してください: %d", olderThanDays)
+	}
+
+	// 共通コンテキストの初期化
+	appCtx, err := NewAppContext(ctx, envFile)
+	if err != nil {
+		return err
+	}
+	defer appCtx.Close()
+
+	// 0 の場合は現在時刻より前に保存した全ての応答を削除する
+	createdBefore := time.Now().AddDate(0, 0, -int(olderThanDays))
+	deleted, err := appCtx.Container.LLMCache.Delete(ctx, promptName, createdBefore)
+	if err != nil {
+		return fmt.Errorf("LLMキャッシュの削除に失敗: %w", err)
+	}
+	slog.Info("LLMキャッシュを削除しました", "prompt", promptName, "olderThanDays", olderThanDays, "deleted", deleted)
+	return nil
+}
@@ -2,9 +2,16 @@ package cli
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
+	"os"
+	"text/tabwriter"
+	"time"
 
 	"github.com/urfave/cli/v3"
+
+	"github.com/jinford/dev-rag/internal/core/ingestion"
+	"github.com/jinford/dev-rag/internal/core/productconfig"
 )
 
 // ProductListAction はプロダクト一覧を表示するコマンドのアクション
@@ -45,3 +52,169 @@ func ProductShowAction(ctx context.Context, cmd *cli.Command) error {
 
 	return nil
 }
+
+// ProductSetRecencyWeightAction はプロダクトの検索で鮮度を加味する重みを設定するコマンドのアクション
+func ProductSetRecencyWeightAction(ctx context.Context, cmd *cli.Command) error {
+	name := cmd.String("name")
+	unset := cmd.Bool("unset")
+	envFile := cmd.String("env")
+
+	var weight *float64
+	switch {
+	case unset && cmd.IsSet("weight"):
+		return fmt.Errorf("--weight と --unset は同時に指定できません")
+	case unset:
+	case cmd.IsSet("weight"):
+		w := cmd.Float("weight")
+		if w < 0 || w > 1 {
+			return fmt.Errorf("--weight は 0〜1 の範囲で指定してください: %v", w)
+		}
+		weight = &w
+	default:
+		return fmt.Errorf("--weight または --unset を指定してください")
+	}
+
+	// 共通コンテキストの初期化
+	appCtx, err := NewAppContext(ctx, envFile)
+	if err != nil {
+		return err
+	}
+	defer appCtx.Close()
+
+	repo := appCtx.Container.IngestionRepo
+
+	productOpt, err := repo.GetProductByName(ctx, name)
+	if err != nil {
+		return fmt.Errorf("プロダクト取得に失敗: %w", err)
+	}
+	product, ok := productOpt.Get()
+	if !ok {
+		return fmt.Errorf("プロダクトが見つかりません: %s", name)
+	}
+
+	if _, err := repo.UpdateProductRecencyWeight(ctx, product.ID, weight); err != nil {
+		return fmt.Errorf("鮮度の重みの設定に失敗: %w", err)
+	}
+
+	if weight == nil {
+		slog.Info("鮮度の重みを解除しました", "product", name)
+	} else {
+		slog.Info("鮮度の重みを設定しました", "product", name, "weight", *weight)
+	}
+	return nil
+}
+
+// ProductConfigSetAction はプロダクトの設定（カバレッジアラートの閾値など）を保存するコマンドのアクション
+func ProductConfigSetAction(ctx context.Context, cmd *cli.Command) error {
+	name := cmd.String("name")
+	key := cmd.String("key")
+	envFile := cmd.String("env")
+
+	value, err := productconfig.Validate(key, cmd.String("value"))
+	if err != nil {
+		return fmt.Errorf("設定値が不正です: %w", err)
+	}
+
+	// 共通コンテキストの初期化
+	appCtx, err := NewAppContext(ctx, envFile)
+	if err != nil {
+		return err
+	}
+	defer appCtx.Close()
+
+	product, err := findProduct(ctx, appCtx, name)
+	if err != nil {
+		return err
+	}
+
+	if _, err := appCtx.Container.ProductConfig.Set(ctx, product.ID, key, value); err != nil {
+		return fmt.Errorf("設定の保存に失敗: %w", err)
+	}
+	slog.Info("設定を保存しました", "product", name, "key", key, "value", value)
+	return nil
+}
+
+// ProductConfigUnsetAction はプロダクトの設定を削除して既定値に戻すコマンドのアクション
+func ProductConfigUnsetAction(ctx context.Context, cmd *cli.Command) error {
+	name := cmd.String("name")
+	key := cmd.String("key")
+	envFile := cmd.String("env")
+
+	// 共通コンテキストの初期化
+	appCtx, err := NewAppContext(ctx, envFile)
+	if err != nil {
+		return err
+	}
+	defer appCtx.Close()
+
+	product, err := findProduct(ctx, appCtx, name)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := appCtx.Container.ProductConfig.Delete(ctx, product.ID, key)
+	if err != nil {
+		return fmt.Errorf("設定の削除に失敗: %w", err)
+	}
+	if !deleted {
+		slog.Info("設定されていないキーです", "product", name, "key", key)
+		return nil
+	}
+	slog.Info("設定を削除しました", "product", name, "key", key)
+	return nil
+}
+
+// ProductConfigListAction はプロダクトの設定と設定可能なキーを表示するコマンドのアクション
+func ProductConfigListAction(ctx context.Context, cmd *cli.Command) error {
+	name := cmd.String("name")
+	envFile := cmd.String("env")
+
+	// 共通コンテキストの初期化
+	appCtx, err := NewAppContext(ctx, envFile)
+	if err != nil {
+		return err
+	}
+	defer appCtx.Close()
+
+	product, err := findProduct(ctx, appCtx, name)
+	if err != nil {
+		return err
+	}
+
+	entries, err := appCtx.Container.ProductConfig.List(ctx, product.ID)
+	if err != nil {
+		return fmt.Errorf("設定の取得に失敗: %w", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	if len(entries) == 0 {
+		fmt.Println("設定がありません（すべて既定値を使用します）")
+	} else {
+		fmt.Fprintln(w, "KEY\tVALUE\tUPDATED")
+		for _, e := range entries {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", e.Key, e.Value, e.UpdatedAt.Format(time.DateTime))
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+
+	fmt.Println("\n--- 設定可能なキー ---")
+	for _, spec := range productconfig.KeySpecs() {
+		fmt.Fprintf(w, "%s\t%s\n", spec.Pattern(), spec.Description)
+	}
+	return w.Flush()
+}
+
+// findProduct はプロダクト名からプロダクトを取得する
+func findProduct(ctx context.Context, appCtx *AppContext, name string) (*ingestion.Product, error) {
+	productOpt, err := appCtx.Container.IngestionRepo.GetProductByName(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("プロダクト取得に失敗: %w", err)
+	}
+	product, ok := productOpt.Get()
+	if !ok {
+		return nil, fmt.Errorf("プロダクトが見つかりません: %s", name)
+	}
+	return product, nil
+}
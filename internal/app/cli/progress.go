@@ -0,0 +1,143 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	coreingestion "github.com/jinford/dev-rag/internal/core/ingestion"
+)
+
+const (
+	// progressBarWidth は進捗バーの幅（文字数）
+	progressBarWidth = 30
+	// progressRedrawInterval は端末の進捗バーを再描画する最短の間隔
+	progressRedrawInterval = 200 * time.Millisecond
+	// progressLogInterval は端末以外に出力する場合に進捗をログに出力する間隔
+	progressLogInterval = 15 * time.Second
+)
+
+// progressRenderer はインデックス化の進捗を表示する ProgressReporter
+// 標準エラー出力が端末の場合は進捗バーを描画し、それ以外（CI・リダイレクト）の場合は一定の間隔で進捗をログに出力する
+type progressRenderer struct {
+	mu       sync.Mutex
+	out      io.Writer
+	tty      bool
+	logger   *slog.Logger
+	interval time.Duration
+	started  time.Time
+	last     time.Time
+	stage    coreingestion.ProgressStage
+	drawn    bool // 進捗バーを描画した行が残っているか
+}
+
+var _ coreingestion.ProgressReporter = (*progressRenderer)(nil)
+
+// newProgressRenderer は標準エラー出力に応じた progressRenderer を返す
+func newProgressRenderer(logger *slog.Logger) *progressRenderer {
+	tty := isTerminal(os.Stderr)
+	interval := progressLogInterval
+	if tty {
+		interval = progressRedrawInterval
+	}
+	return &progressRenderer{
+		out:      os.Stderr,
+		tty:      tty,
+		logger:   logger,
+		interval: interval,
+		started:  time.Now(),
+	}
+}
+
+// ReportProgress は進捗を表示する（段階が変わった場合と完了時を除き、間隔を空けて表示する）
+func (r *progressRenderer) ReportProgress(p coreingestion.IndexProgress) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if p.Stage == r.stage && p.Stage != coreingestion.ProgressDone && now.Sub(r.last) < r.interval {
+		return
+	}
+	r.stage = p.Stage
+	r.last = now
+
+	if r.tty {
+		r.draw(p)
+		return
+	}
+	r.log(p)
+}
+
+// Finish は進捗バーの行を確定する（インデックス化の後にログを出力する前に呼び出す）
+func (r *progressRenderer) Finish() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.drawn {
+		fmt.Fprintln(r.out)
+		r.drawn = false
+	}
+}
+
+// draw は進捗バーを同じ行に上書きして描画する
+func (r *progressRenderer) draw(p coreingestion.IndexProgress) {
+	elapsed := time.Since(r.started).Round(time.Second)
+	var line string
+	if p.Stage == coreingestion.ProgressFetching {
+		line = fmt.Sprintf("ソースを取得中... %s", elapsed)
+	} else {
+		fraction := p.Fraction()
+		filled := int(fraction * progressBarWidth)
+		line = fmt.Sprintf("[%s%s] %3.0f%% ファイル %d/%d  チャンク %d/%d  %s",
+			strings.Repeat("#", filled),
+			strings.Repeat(".", progressBarWidth-filled),
+			fraction*100,
+			p.FilesDone(), p.FilesDiscovered,
+			p.ChunksDone(), p.ChunksCreated,
+			elapsed,
+		)
+		if failed := p.FilesFailed + p.ChunksFailed; failed > 0 {
+			line += fmt.Sprintf("  失敗 %d", failed)
+		}
+	}
+	// 前回の描画より短い行でも残らないよう行末まで消去する
+	fmt.Fprintf(r.out, "\r%s\033[K", line)
+	r.drawn = true
+	if p.Stage == coreingestion.ProgressDone {
+		fmt.Fprintln(r.out)
+		r.drawn = false
+	}
+}
+
+// log は進捗を構造化ログとして出力する
+func (r *progressRenderer) log(p coreingestion.IndexProgress) {
+	if p.Stage == coreingestion.ProgressFetching {
+		r.logger.Info("ソースからドキュメントを取得しています")
+		return
+	}
+	r.logger.Info("インデックス化の進捗",
+		"stage", p.Stage,
+		"percent", fmt.Sprintf("%.0f", p.Fraction()*100),
+		"filesDiscovered", p.FilesDiscovered,
+		"filesChunked", p.FilesChunked,
+		"filesFailed", p.FilesFailed,
+		"chunksCreated", p.ChunksCreated,
+		"chunksEmbedded", p.ChunksEmbedded,
+		"chunksPersisted", p.ChunksPersisted,
+		"chunksFailed", p.ChunksFailed,
+		"elapsed", time.Since(r.started).Round(time.Second),
+	)
+}
+
+// isTerminal はファイルが端末（キャラクタデバイス）かどうかを返す
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/jinford/dev-rag/internal/core/prompt"
+)
+
+// PromptListAction はプロンプトテンプレートの一覧と有効なバージョンを表示するコマンドのアクション
+func PromptListAction(ctx context.Context, cmd *cli.Command) error {
+	envFile := cmd.String("env")
+
+	// 共通コンテキストの初期化
+	appCtx, err := NewAppContext(ctx, envFile)
+	if err != nil {
+		return err
+	}
+	defer appCtx.Close()
+
+	templates, err := appCtx.Container.Prompts.List(ctx)
+	if err != nil {
+		return fmt.Errorf("プロンプトテンプレートの取得に失敗: %w", err)
+	}
+
+	builtinVersions := make(map[string]string)
+	descriptions := make(map[string]string)
+	for _, def := range prompt.Definitions() {
+		builtinVersions[def.Name] = def.Version
+		descriptions[def.Name] = def.Description
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tSOURCE\tVERSION\tBUILTIN\tDESCRIPTION")
+	for _, t := range templates {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", t.Name, t.Source, t.Version, builtinVersions[t.Name], descriptions[t.Name])
+	}
+	return w.Flush()
+}
+
+// PromptShowAction はプロンプトテンプレートの本文を表示するコマンドのアクション
+func PromptShowAction(ctx context.Context, cmd *cli.Command) error {
+	envFile := cmd.String("env")
+	name := cmd.String("name")
+
+	// 組み込みのテンプレートはデータベースに接続せずに表示する（上書きの雛形として使用する）
+	if cmd.Bool("builtin") {
+		t, err := prompt.Builtin(name)
+		if err != nil {
+			return fmt.Errorf("プロンプトテンプレートが見つかりません: %w", err)
+		}
+		printPromptTemplate(t)
+		return nil
+	}
+
+	// 共通コンテキストの初期化
+	appCtx, err := NewAppContext(ctx, envFile)
+	if err != nil {
+		return err
+	}
+	defer appCtx.Close()
+
+	t, err := appCtx.Container.Prompts.Resolve(ctx, name)
+	if err != nil {
+		return fmt.Errorf("プロンプトテンプレートの取得に失敗: %w", err)
+	}
+	printPromptTemplate(t)
+	return nil
+}
+
+// PromptSetAction はファイルのテンプレートをデータベースに保存して組み込みのテンプレートを上書きするコマンドのアクション
+func PromptSetAction(ctx context.Context, cmd *cli.Command) error {
+	envFile := cmd.String("env")
+	name := cmd.String("name")
+
+	body, err := os.ReadFile(cmd.String("file"))
+	if err != nil {
+		return fmt.Errorf("テンプレートファイルの読み込みに失敗: %w", err)
+	}
+
+	// 共通コンテキストの初期化
+	appCtx, err := NewAppContext(ctx, envFile)
+	if err != nil {
+		return err
+	}
+	defer appCtx.Close()
+
+	t, err := appCtx.Container.Prompts.Set(ctx, name, string(body), cmd.String("version"))
+	if err != nil {
+		return fmt.Errorf("プロンプトテンプレートの保存に失敗: %w", err)
+	}
+	slog.Info("プロンプトテンプレートを保存しました", "name", t.Name, "version", t.Version)
+	return nil
+}
+
+// PromptUnsetAction はデータベースに保存したテンプレートを削除して既定のテンプレートに戻すコマンドのアクション
+func PromptUnsetAction(ctx context.Context, cmd *cli.Command) error {
+	envFile := cmd.String("env")
+	name := cmd.String("name")
+
+	// 共通コンテキストの初期化
+	appCtx, err := NewAppContext(ctx, envFile)
+	if err != nil {
+		return err
+	}
+	defer appCtx.Close()
+
+	deleted, err := appCtx.Container.Prompts.Unset(ctx, name)
+	if err != nil {
+		return fmt.Errorf("プロンプトテンプレートの削除に失敗: %w", err)
+	}
+	if !deleted {
+		slog.Info("データベースに保存されていないテンプレートです", "name", name)
+		return nil
+	}
+	slog.Info("プロンプトテンプレートを削除しました", "name", name)
+	return nil
+}
+
+// printPromptTemplate はテンプレートの情報と本文を表示する
+func printPromptTemplate(t *prompt.Template) {
+	fmt.Printf("名前: %s\n", t.Name)
+	fmt.Printf("取得元: %s\n", t.Source)
+	fmt.Printf("バージョン: %s\n", t.Version)
+	if !t.UpdatedAt.IsZero() {
+		fmt.Printf("更新日時: %s\n", t.UpdatedAt.Format(time.DateTime))
+	}
+	fmt.Println("---")
+	fmt.Print(t.Body)
+}
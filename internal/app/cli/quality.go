@@ -0,0 +1,191 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+	"github.com/urfave/cli/v3"
+
+	"github.com/jinford/dev-rag/internal/core/quality"
+	"github.com/jinford/dev-rag/internal/core/usage"
+)
+
+// QualityNoteAddAction はRAG回答の品質ノートを記録するコマンドのアクション
+func QualityNoteAddAction(ctx context.Context, cmd *cli.Command) error {
+	envFile := cmd.String("env")
+
+	severity := quality.Severity(strings.ToLower(cmd.String("severity")))
+	if !slices.Contains(quality.Severities, severity) {
+		return fmt.Errorf("深刻度が不正です: %s（critical/high/medium/low のいずれかを指定してください）", cmd.String("severity"))
+	}
+	var chunkIDs []uuid.UUID
+	for _, idStr := range cmd.StringSlice("chunk") {
+		chunkID, err := uuid.Parse(idStr)
+		if err != nil {
+			return fmt.Errorf("チャンクIDが不正です: %s", idStr)
+		}
+		chunkIDs = append(chunkIDs, chunkID)
+	}
+
+	// 共通コンテキストの初期化
+	appCtx, err := NewAppContext(ctx, envFile)
+	if err != nil {
+		return err
+	}
+	defer appCtx.Close()
+
+	note, err := appCtx.Container.QualityService.AddNote(ctx, quality.AddNoteParams{
+		NoteID:       cmd.String("id"),
+		Severity:     severity,
+		NoteText:     cmd.String("text"),
+		LinkedFiles:  cmd.StringSlice("file"),
+		LinkedChunks: chunkIDs,
+		Reviewer:     cmd.String("reviewer"),
+	})
+	if err != nil {
+		return fmt.Errorf("品質ノートの記録に失敗しました: %w", err)
+	}
+
+	slog.Info("品質ノートを記録しました", "noteID", note.NoteID, "severity", note.Severity)
+	return nil
+}
+
+// QualityNoteListAction は品質ノートを新しい順に表示するコマンドのアクション
+func QualityNoteListAction(ctx context.Context, cmd *cli.Command) error {
+	envFile := cmd.String("env")
+
+	// 共通コンテキストの初期化
+	appCtx, err := NewAppContext(ctx, envFile)
+	if err != nil {
+		return err
+	}
+	defer appCtx.Close()
+
+	notes, err := appCtx.Container.QualityService.ListNotes(ctx, quality.NoteFilter{
+		Status:   quality.NoteStatus(strings.ToLower(cmd.String("status"))),
+		Severity: quality.Severity(strings.ToLower(cmd.String("severity"))),
+		Limit:    int(cmd.Int("limit")),
+	})
+	if err != nil {
+		return fmt.Errorf("品質ノートの取得に失敗しました: %w", err)
+	}
+	if len(notes) == 0 {
+		fmt.Println("品質ノートがありません")
+		return nil
+	}
+
+	for _, note := range notes {
+		fmt.Printf("%s [%s] %s %s (%s)\n", note.NoteID, note.Severity, note.Status, note.CreatedAt.Format(time.DateTime), note.Reviewer)
+		fmt.Println(indent(note.NoteText, "    "))
+		if len(note.LinkedFiles) > 0 {
+			fmt.Printf("    関連ファイル: %s\n", strings.Join(note.LinkedFiles, ", "))
+		}
+	}
+	return nil
+}
+
+// QualityActionsGenerateAction は対象週の品質ノートから品質改善アクションを生成してバックログに登録するコマンドのアクション
+func QualityActionsGenerateAction(ctx context.Context, cmd *cli.Command) error {
+	productName := cmd.String("product")
+	envFile := cmd.String("env")
+
+	weekStart, err := quality.ParseWeek(cmd.String("week"), time.Now())
+	if err != nil {
+		return fmt.Errorf("対象週の指定が不正です: %w", err)
+	}
+
+	// 共通コンテキストの初期化
+	appCtx, err := NewAppContext(ctx, envFile)
+	if err != nil {
+		return err
+	}
+	defer appCtx.Close()
+
+	productID := mo.None[uuid.UUID]()
+	if productName != "" {
+		productOpt, err := appCtx.Container.IngestionRepo.GetProductByName(ctx, productName)
+		if err != nil {
+			return fmt.Errorf("プロダクト取得に失敗: %w", err)
+		}
+		if productOpt.IsAbsent() {
+			return fmt.Errorf("プロダクトが見つかりません: %s", productName)
+		}
+		productID = mo.Some(productOpt.MustGet().ID)
+	}
+
+	// LLM の使用量を計測し、予算を適用する
+	tracker := appCtx.Container.NewUsageTracker()
+	ctx = usage.WithTracker(ctx, tracker)
+
+	slog.Info("品質改善アクションの生成を開始します", "week", weekStart.Format(time.DateOnly))
+	result, err := appCtx.Container.QualityService.GenerateActions(ctx, quality.GenerateActionsParams{
+		ProductID: productID,
+		WeekStart: weekStart,
+	})
+	logUsage(tracker)
+	if err != nil {
+		return fmt.Errorf("品質改善アクションの生成に失敗しました: %w", err)
+	}
+	if result.Notes == 0 {
+		fmt.Printf("%s 〜 %s に記録された未解決の品質ノートがありません\n", result.WeekStart.Format(time.DateOnly), result.WeekEnd.Format(time.DateOnly))
+		return nil
+	}
+
+	slog.Info("品質改善アクションの生成が完了しました",
+		"weekStart", result.WeekStart.Format(time.DateOnly),
+		"weekEnd", result.WeekEnd.Format(time.DateOnly),
+		"notes", result.Notes,
+		"actions", len(result.Actions),
+	)
+	printActions(result.Actions)
+	return nil
+}
+
+// QualityActionsListAction は品質改善アクションのバックログを表示するコマンドのアクション
+func QualityActionsListAction(ctx context.Context, cmd *cli.Command) error {
+	envFile := cmd.String("env")
+
+	// 共通コンテキストの初期化
+	appCtx, err := NewAppContext(ctx, envFile)
+	if err != nil {
+		return err
+	}
+	defer appCtx.Close()
+
+	actions, err := appCtx.Container.QualityService.ListActions(ctx, quality.ActionFilter{
+		Status: quality.ActionStatus(strings.ToLower(cmd.String("status"))),
+		Limit:  int(cmd.Int("limit")),
+	})
+	if err != nil {
+		return fmt.Errorf("品質改善アクションの取得に失敗しました: %w", err)
+	}
+	if len(actions) == 0 {
+		fmt.Println("品質改善アクションがありません")
+		return nil
+	}
+	printActions(actions)
+	return nil
+}
+
+// printActions は品質改善アクションを表示する
+func printActions(actions []*quality.Action) {
+	for _, action := range actions {
+		fmt.Printf("%s [%s/%s] %s %s\n", action.ActionID, action.Priority, action.ActionType, action.Status, action.Title)
+		fmt.Printf("    担当: %s\n", action.OwnerHint)
+		if action.Description != "" {
+			fmt.Println(indent(action.Description, "    "))
+		}
+		if len(action.LinkedFiles) > 0 {
+			fmt.Printf("    関連ファイル: %s\n", strings.Join(action.LinkedFiles, ", "))
+		}
+		if action.AcceptanceCriteria != "" {
+			fmt.Printf("    受入基準: %s\n", action.AcceptanceCriteria)
+		}
+	}
+}
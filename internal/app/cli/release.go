@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/urfave/cli/v3"
+
+	coreingestion "github.com/jinford/dev-rag/internal/core/ingestion"
+	"github.com/jinford/dev-rag/internal/core/release"
+	"github.com/jinford/dev-rag/internal/core/usage"
+)
+
+// ReleaseNotesAction はGitソースの2つの ref の間の変更からリリースノートの下書きを生成するコマンドのアクション
+func ReleaseNotesAction(ctx context.Context, cmd *cli.Command) error {
+	sourceName := cmd.String("source")
+	fromRef := cmd.String("from")
+	toRef := cmd.String("to")
+	envFile := cmd.String("env")
+
+	// 共通コンテキストの初期化
+	appCtx, err := NewAppContext(ctx, envFile)
+	if err != nil {
+		return err
+	}
+	defer appCtx.Close()
+
+	sourceOpt, err := appCtx.Container.IngestionRepo.GetSourceByName(ctx, sourceName)
+	if err != nil {
+		return fmt.Errorf("ソース取得に失敗: %w", err)
+	}
+	source, ok := sourceOpt.Get()
+	if !ok {
+		return fmt.Errorf("ソースが見つかりません: %s", sourceName)
+	}
+	if source.SourceType != coreingestion.SourceTypeGit {
+		return fmt.Errorf("Gitソースではありません: %s (%s)", sourceName, source.SourceType)
+	}
+	repoURL, _ := source.Metadata["url"].(string)
+	if repoURL == "" {
+		return fmt.Errorf("ソースのリポジトリURLが記録されていません: %s", sourceName)
+	}
+
+	// LLM の使用量を計測し、予算を適用する
+	tracker := appCtx.Container.NewUsageTracker()
+	ctx = usage.WithTracker(ctx, tracker)
+
+	slog.Info("リリースノートの生成を開始します", "source", source.Name, "from", fromRef, "to", toRef)
+	note, err := appCtx.Container.ReleaseNotes.Generate(ctx, release.GenerateParams{
+		SourceID:   source.ID,
+		SourceName: source.Name,
+		RepoURL:    repoURL,
+		FromRef:    fromRef,
+		ToRef:      toRef,
+	})
+	logUsage(tracker)
+	if err != nil {
+		return fmt.Errorf("リリースノートの生成に失敗しました: %w", err)
+	}
+
+	content := release.RenderNote(note)
+	if outputPath := cmd.String("output"); outputPath != "" {
+		if err := os.WriteFile(outputPath, []byte(content), 0644); err != nil {
+			return fmt.Errorf("リリースノートの出力に失敗しました: %w", err)
+		}
+		slog.Info("リリースノートを出力しました", "path", outputPath)
+	} else {
+		fmt.Print(content)
+	}
+
+	if cmd.Bool("wiki") {
+		path, err := writeReleaseNotesPage(ctx, appCtx, source)
+		if err != nil {
+			return err
+		}
+		slog.Info("Wikiのリリースノートのページを更新しました", "path", path)
+	}
+	return nil
+}
+
+// writeReleaseNotesPage はプロダクトの保存済みのリリースノートをWikiの出力ディレクトリのページに書き出す
+func writeReleaseNotesPage(ctx context.Context, appCtx *AppContext, source *coreingestion.Source) (string, error) {
+	productOpt, err := appCtx.Container.IngestionRepo.GetProductByID(ctx, source.ProductID)
+	if err != nil {
+		return "", fmt.Errorf("プロダクト取得に失敗: %w", err)
+	}
+	product, ok := productOpt.Get()
+	if !ok {
+		return "", fmt.Errorf("ソースのプロダクトが見つかりません: %s", source.Name)
+	}
+
+	notes, err := appCtx.Container.ReleaseNotes.ListNotes(ctx, product.ID)
+	if err != nil {
+		return "", fmt.Errorf("リリースノートの取得に失敗しました: %w", err)
+	}
+
+	wikiDir := filepath.Join(appCtx.Config.WikiOutputDir, product.Name)
+	if err := os.MkdirAll(wikiDir, 0755); err != nil {
+		return "", fmt.Errorf("Wiki出力ディレクトリの作成に失敗しました: %w", err)
+	}
+	path := filepath.Join(wikiDir, release.WikiPageFileName)
+	if err := os.WriteFile(path, []byte(release.RenderWikiPage(notes)), 0644); err != nil {
+		return "", fmt.Errorf("Wikiのリリースノートのページの書き出しに失敗しました: %w", err)
+	}
+	return path, nil
+}
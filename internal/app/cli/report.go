@@ -0,0 +1,144 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+	"github.com/urfave/cli/v3"
+
+	"github.com/jinford/dev-rag/internal/core/hotspot"
+	"github.com/jinford/dev-rag/internal/core/license"
+)
+
+// ReportHotspotsAction は循環的複雑度・行数・編集頻度・コメント比率から変更のリスクが高い関数・ファイルを順位付けして出力するコマンドのアクション
+func ReportHotspotsAction(ctx context.Context, cmd *cli.Command) error {
+	productName := cmd.String("product")
+	envFile := cmd.String("env")
+	outputPath := cmd.String("output")
+
+	format := hotspot.Format(cmd.String("format"))
+	if !slices.Contains(hotspot.Formats, format) {
+		return fmt.Errorf("--format は table、json、markdown のいずれかを指定してください: %s", format)
+	}
+	level := hotspot.Level(cmd.String("level"))
+	if !slices.Contains(hotspot.Levels, level) {
+		return fmt.Errorf("--level は function、file のいずれかを指定してください: %s", level)
+	}
+	limit := int(cmd.Int("limit"))
+	if limit < 0 {
+		return fmt.Errorf("--limit は 0 以上で指定してください: %d", limit)
+	}
+
+	// 共通コンテキストの初期化
+	appCtx, err := NewAppContext(ctx, envFile)
+	if err != nil {
+		return err
+	}
+	defer appCtx.Close()
+
+	productOpt, err := appCtx.Container.IngestionRepo.GetProductByName(ctx, productName)
+	if err != nil {
+		return fmt.Errorf("プロダクト取得に失敗: %w", err)
+	}
+	if productOpt.IsAbsent() {
+		return fmt.Errorf("プロダクトが見つかりません: %s", productName)
+	}
+	product := productOpt.MustGet()
+
+	report, err := appCtx.Container.Hotspots.Report(ctx, mo.Some(product.ID), mo.None[uuid.UUID](), hotspot.Query{
+		Level: level,
+		Limit: limit,
+		Filter: hotspot.Filter{
+			PathPrefix:   cmd.String("path-prefix"),
+			IncludeTests: cmd.Bool("include-tests"),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("ホットスポットの順位付けに失敗しました: %w", err)
+	}
+
+	var w io.Writer = os.Stdout
+	if outputPath != "" {
+		f, err := os.Create(outputPath)
+		if err != nil {
+			return fmt.Errorf("出力ファイルの作成に失敗しました: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := hotspot.Write(w, report, format); err != nil {
+		return fmt.Errorf("ホットスポットの出力に失敗しました: %w", err)
+	}
+
+	if outputPath != "" {
+		appCtx.Logger().Info("ホットスポットを出力しました",
+			"output", outputPath,
+			"hotspots", len(report.Hotspots),
+		)
+	}
+	return nil
+}
+
+// ReportLicensesAction はライセンスファイル・ライセンスヘッダーから検出したライセンスを集計し、確認が必要なファイルを出力するコマンドのアクション
+func ReportLicensesAction(ctx context.Context, cmd *cli.Command) error {
+	productName := cmd.String("product")
+	envFile := cmd.String("env")
+	outputPath := cmd.String("output")
+
+	format := license.Format(cmd.String("format"))
+	if !slices.Contains(license.Formats, format) {
+		return fmt.Errorf("--format は table、json、markdown のいずれかを指定してください: %s", format)
+	}
+
+	// 共通コンテキストの初期化
+	appCtx, err := NewAppContext(ctx, envFile)
+	if err != nil {
+		return err
+	}
+	defer appCtx.Close()
+
+	productOpt, err := appCtx.Container.IngestionRepo.GetProductByName(ctx, productName)
+	if err != nil {
+		return fmt.Errorf("プロダクト取得に失敗: %w", err)
+	}
+	if productOpt.IsAbsent() {
+		return fmt.Errorf("プロダクトが見つかりません: %s", productName)
+	}
+	product := productOpt.MustGet()
+
+	report, err := appCtx.Container.Licenses.Report(ctx, mo.Some(product.ID), mo.None[uuid.UUID]())
+	if err != nil {
+		return fmt.Errorf("ライセンスの集計に失敗しました: %w", err)
+	}
+	if len(report.Licenses) == 0 && format == license.FormatTable && outputPath == "" {
+		fmt.Println("ライセンスが見つかりません（ライセンスファイル・ライセンスヘッダーはインデックス化時に検出されます）")
+		return nil
+	}
+
+	var w io.Writer = os.Stdout
+	if outputPath != "" {
+		f, err := os.Create(outputPath)
+		if err != nil {
+			return fmt.Errorf("出力ファイルの作成に失敗しました: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := license.Write(w, report, format); err != nil {
+		return fmt.Errorf("ライセンスの出力に失敗しました: %w", err)
+	}
+
+	if outputPath != "" {
+		appCtx.Logger().Info("ライセンスの集計を出力しました",
+			"output", outputPath,
+			"licenses", len(report.Licenses),
+			"flagged", len(report.Flagged),
+		)
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/urfave/cli/v3"
+
+	coreingestion "github.com/jinford/dev-rag/internal/core/ingestion"
+)
+
+// RunListAction はインデックス化の実行履歴を表示するコマンドのアクション
+func RunListAction(ctx context.Context, cmd *cli.Command) error {
+	productName := cmd.String("product")
+	envFile := cmd.String("env")
+	asJSON := cmd.Bool("json")
+
+	filter := coreingestion.IndexRunFilter{
+		SourceName: cmd.String("source"),
+		Limit:      int(cmd.Int("limit")),
+	}
+	if filter.Limit <= 0 {
+		return fmt.Errorf("--limit は1以上で指定してください: %d", filter.Limit)
+	}
+	if s := cmd.String("status"); s != "" {
+		status, err := coreingestion.ParseIndexRunStatus(s)
+		if err != nil {
+			return fmt.Errorf("--status が不正です: %s（%w）", s, err)
+		}
+		filter.Status = status
+	}
+
+	// 共通コンテキストの初期化
+	appCtx, err := NewAppContext(ctx, envFile)
+	if err != nil {
+		return err
+	}
+	defer appCtx.Close()
+
+	if productName != "" {
+		productOpt, err := appCtx.Container.IngestionRepo.GetProductByName(ctx, productName)
+		if err != nil {
+			return fmt.Errorf("プロダクト取得に失敗: %w", err)
+		}
+		if productOpt.IsAbsent() {
+			return fmt.Errorf("プロダクトが見つかりません: %s", productName)
+		}
+		filter.ProductID = productOpt.MustGet().ID
+	}
+
+	runs, err := appCtx.Container.IndexRuns.ListIndexRuns(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("実行履歴の取得に失敗: %w", err)
+	}
+
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(runs)
+	}
+	if len(runs) == 0 {
+		slog.Info("実行履歴がありません")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tPRODUCT\tSOURCE\tREF\tSTATUS\tFILES\tCHUNKS\tFAILED\tCOST\tDURATION\tSTARTED")
+	for _, run := range runs {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d\t%d\t%d\t$%.4f\t%s\t%s\n",
+			run.ID,
+			run.ProductName,
+			run.SourceName,
+			valueOrDash(run.Ref),
+			run.Status,
+			run.FilesProcessed,
+			run.Chunks,
+			run.FailedFiles+run.FailedEmbeddings,
+			run.CostUSD,
+			formatRunDuration(run),
+			run.StartedAt.Format(time.DateTime),
+		)
+	}
+	return w.Flush()
+}
+
+// RunShowAction はインデックス化の実行の詳細を表示するコマンドのアクション
+func RunShowAction(ctx context.Context, cmd *cli.Command) error {
+	idStr := cmd.String("id")
+	envFile := cmd.String("env")
+	asJSON := cmd.Bool("json")
+
+	runID, err := uuid.Parse(idStr)
+	if err != nil {
+		return fmt.Errorf("実行IDが不正です: %s", idStr)
+	}
+
+	// 共通コンテキストの初期化
+	appCtx, err := NewAppContext(ctx, envFile)
+	if err != nil {
+		return err
+	}
+	defer appCtx.Close()
+
+	runOpt, err := appCtx.Container.IndexRuns.GetIndexRun(ctx, runID)
+	if err != nil {
+		return fmt.Errorf("実行履歴の取得に失敗: %w", err)
+	}
+	run, ok := runOpt.Get()
+	if !ok {
+		return fmt.Errorf("実行履歴が見つかりません: %s", runID)
+	}
+
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(run)
+	}
+
+	fmt.Printf("ID:               %s\n", run.ID)
+	fmt.Printf("プロダクト:       %s\n", run.ProductName)
+	fmt.Printf("ソース:           %s\n", run.SourceName)
+	fmt.Printf("参照:             %s\n", valueOrDash(run.Ref))
+	fmt.Printf("バージョン:       %s\n", valueOrDash(run.VersionIdentifier))
+	if run.SnapshotID != nil {
+		fmt.Printf("スナップショット: %s\n", *run.SnapshotID)
+	}
+	fmt.Printf("状態:             %s\n", run.Status)
+	fmt.Printf("強制再作成:       %t\n", run.ForceInit)
+	fmt.Printf("開始日時:         %s\n", run.StartedAt.Format(time.DateTime))
+	if run.FinishedAt != nil {
+		fmt.Printf("完了日時:         %s\n", run.FinishedAt.Format(time.DateTime))
+	}
+	fmt.Printf("所要時間:         %s\n", formatRunDuration(run))
+	fmt.Printf("処理ファイル数:   %d\n", run.FilesProcessed)
+	fmt.Printf("チャンク数:       %d\n", run.Chunks)
+	fmt.Printf("失敗ファイル数:   %d\n", run.FailedFiles)
+	fmt.Printf("Embedding失敗数:  %d\n", run.FailedEmbeddings)
+	fmt.Printf("入力トークン数:   %d\n", run.InputTokens)
+	fmt.Printf("出力トークン数:   %d\n", run.OutputTokens)
+	fmt.Printf("推定コスト:       $%.4f\n", run.CostUSD)
+	if run.Error != "" {
+		fmt.Printf("エラー:           %s\n", run.Error)
+	}
+	return nil
+}
+
+// formatRunDuration は実行の所要時間を表示用に整形する（実行中または中断した場合は "-"）
+func formatRunDuration(run *coreingestion.IndexRun) string {
+	if run.DurationMs == nil {
+		return "-"
+	}
+	return (time.Duration(*run.DurationMs) * time.Millisecond).Round(time.Second).String()
+}
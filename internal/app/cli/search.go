@@ -0,0 +1,269 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+	"github.com/urfave/cli/v3"
+
+	coreingestion "github.com/jinford/dev-rag/internal/core/ingestion"
+	coresearch "github.com/jinford/dev-rag/internal/core/search"
+)
+
+// SearchAction はLLMを使わずに検索結果のみを表示するコマンドのアクション
+// 検索品質の調査やスクリプトからの利用を想定し、LLMのコストは発生しない
+func SearchAction(ctx context.Context, cmd *cli.Command) error {
+	productName := cmd.String("product")
+	limit := int(cmd.Int("limit"))
+	hybrid := cmd.Bool("hybrid")
+	summaryLimit := int(cmd.Int("summary-limit"))
+	showContent := cmd.Bool("show-content")
+	asJSON := cmd.Bool("json")
+	envFile := cmd.String("env")
+
+	query := cmd.Args().First()
+	if query == "" {
+		return fmt.Errorf("検索クエリを指定してください")
+	}
+	scope, err := snapshotScopeFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+
+	domains, err := domainsFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+	strategy, err := strategyFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+
+	chunkFilter := coresearch.SearchFilter{
+		Domains:      domains,
+		Languages:    languagesFromFlags(cmd),
+		KeepOverlaps: cmd.Bool("keep-overlaps"),
+
+		EmbeddingModel: cmd.String("embedding-model"),
+	}
+	if v := cmd.String("path-prefix"); v != "" {
+		chunkFilter.PathPrefix = &v
+	}
+	if v := cmd.String("content-type"); v != "" {
+		chunkFilter.ContentType = &v
+	}
+	if cmd.IsSet("importance-weight") {
+		w := cmd.Float("importance-weight")
+		if w < 0 || w > 1 {
+			return fmt.Errorf("--importance-weight は 0〜1 の範囲で指定してください: %v", w)
+		}
+		chunkFilter.ImportanceWeight = &w
+	}
+	if cmd.IsSet("recency-weight") {
+		w := cmd.Float("recency-weight")
+		if w < 0 || w > 1 {
+			return fmt.Errorf("--recency-weight は 0〜1 の範囲で指定してください: %v", w)
+		}
+		chunkFilter.RecencyWeight = &w
+	}
+
+	// 共通コンテキストの初期化
+	appCtx, err := NewAppContext(ctx, envFile)
+	if err != nil {
+		return err
+	}
+	defer appCtx.Close()
+
+	repo := appCtx.Container.IngestionRepo
+
+	productOpt, err := repo.GetProductByName(ctx, productName)
+	if err != nil {
+		return fmt.Errorf("プロダクト取得に失敗: %w", err)
+	}
+	product, ok := productOpt.Get()
+	if !ok {
+		return fmt.Errorf("プロダクトが見つかりません: %s", productName)
+	}
+
+	snapshotID, err := scope.resolve(ctx, repo, product.ID)
+	if err != nil {
+		return err
+	}
+
+	searchService := appCtx.Container.SearchService
+	result := &coresearch.HybridSearchResult{}
+
+	if hybrid {
+		summaryFilter := coresearch.SummarySearchFilter{PathPrefix: chunkFilter.PathPrefix}
+		if id, ok := snapshotID.Get(); ok {
+			chunkFilter.SnapshotID = &id
+			summaryFilter.SnapshotID = &id
+		}
+		result, err = searchService.HybridSearch(ctx, coresearch.HybridSearchParams{
+			ProductID:     mo.Some(product.ID),
+			Query:         query,
+			ChunkLimit:    limit,
+			SummaryLimit:  summaryLimit,
+			ChunkFilter:   &chunkFilter,
+			SummaryFilter: &summaryFilter,
+			Strategy:      strategy,
+		})
+	} else {
+		result.Chunks, err = searchService.Search(ctx, coresearch.SearchParams{
+			ProductID:  mo.Some(product.ID),
+			SnapshotID: snapshotID,
+			Query:      query,
+			Limit:      limit,
+			Filter:     &chunkFilter,
+			Strategy:   strategy,
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("検索に失敗: %w", err)
+	}
+
+	slog.Debug("検索が完了しました", "chunks", len(result.Chunks), "summaries", len(result.Summaries))
+
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	}
+
+	printSearchResult(result, showContent)
+	return nil
+}
+
+// printSearchResult は検索結果をスコア順に表示する
+func printSearchResult(result *coresearch.HybridSearchResult, showContent bool) {
+	if len(result.Chunks) == 0 && len(result.Summaries) == 0 {
+		fmt.Println("該当する結果がありません")
+		return
+	}
+
+	for i, chunk := range result.Chunks {
+		location := fmt.Sprintf("%s:L%d-L%d", chunk.FilePath, chunk.StartLine, chunk.EndLine)
+		if chunk.Breadcrumb != nil || chunk.PageStart != nil {
+			location = fmt.Sprintf("%s (%s)", chunk.FilePath,
+				formatChunkLocation(chunk.StartLine, chunk.EndLine, chunk.Breadcrumb, chunk.PageStart, chunk.PageEnd))
+		}
+		fmt.Printf("[%d] %.4f  %s  (chunk %s)\n",
+			i+1, chunk.Score, location, chunk.ChunkID)
+		if showContent {
+			fmt.Println(indent(chunk.Content, "    "))
+		}
+	}
+
+	if len(result.Summaries) > 0 {
+		fmt.Println("\n--- 要約 ---")
+		for i, summary := range result.Summaries {
+			fmt.Printf("[%d] %.4f  %s  %s\n", i+1, summary.Score, summary.SummaryType, summary.TargetPath)
+			if showContent {
+				fmt.Println(indent(summary.Content, "    "))
+			}
+		}
+	}
+}
+
+// formatChunkLocation はチャンクの位置を表示用に整形する
+// Markdownのチャンクは見出しの階層、PDF・Office文書のチャンクはページ番号を行番号の代わりに使用する
+func formatChunkLocation(startLine, endLine int, breadcrumb *string, pageStart, pageEnd *int) string {
+	switch {
+	case breadcrumb != nil:
+		return *breadcrumb
+	case pageStart != nil:
+		return "p." + coresearch.FormatPageRange(*pageStart, pageEnd)
+	default:
+		return fmt.Sprintf("L%d-L%d", startLine, endLine)
+	}
+}
+
+// indent は各行の先頭にプレフィックスを付与する
+func indent(text, prefix string) string {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = prefix + line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// domainsFromFlags はコマンドフラグから検索対象のドメイン（--domain）を取得して検証する
+func domainsFromFlags(cmd *cli.Command) ([]string, error) {
+	domains := cmd.StringSlice("domain")
+	for _, domain := range domains {
+		if !coreingestion.IsValidDomain(domain) {
+			return nil, fmt.Errorf("不正なドメインです: %s（指定可能: %s）", domain, strings.Join(coreingestion.Domains, ", "))
+		}
+	}
+	return domains, nil
+}
+
+// languagesFromFlags はコマンドフラグから検索対象の言語（--language）を files.language の表記で取得する
+func languagesFromFlags(cmd *cli.Command) []string {
+	var languages []string
+	for _, language := range cmd.StringSlice("language") {
+		languages = append(languages, coreingestion.NormalizeLanguage(language))
+	}
+	return languages
+}
+
+// strategyFromFlags はコマンドフラグからチャンク検索の方式（--strategy）を取得する（未指定は空で SEARCH_STRATEGY に従う）
+func strategyFromFlags(cmd *cli.Command) (coresearch.RetrievalStrategy, error) {
+	value := cmd.String("strategy")
+	if value == "" {
+		return "", nil
+	}
+	strategy, err := coresearch.ParseRetrievalStrategy(value)
+	if err != nil {
+		return "", fmt.Errorf("不正な検索方式です: %s（指定可能: %s, %s）", value, coresearch.RetrievalFlat, coresearch.RetrievalTwoStage)
+	}
+	return strategy, nil
+}
+
+// snapshotScope は検索対象のスナップショットの指定（--source/--ref/--snapshot）
+type snapshotScope struct {
+	Source   string // Ref/Snapshot を解決するソース名（プロダクトのソースが1件の場合は省略可）
+	Ref      string // 参照（ブランチ・タグ）が指すスナップショットに限定する
+	Snapshot string // スナップショット（IDまたはバージョン識別子）に限定する
+}
+
+// snapshotScopeFromFlags はコマンドフラグから検索対象のスナップショットの指定を取得する
+func snapshotScopeFromFlags(cmd *cli.Command) (snapshotScope, error) {
+	scope := snapshotScope{
+		Source:   cmd.String("source"),
+		Ref:      cmd.String("ref"),
+		Snapshot: cmd.String("snapshot"),
+	}
+	if scope.Ref != "" && scope.Snapshot != "" {
+		return scope, fmt.Errorf("--ref と --snapshot は同時に指定できません")
+	}
+	return scope, nil
+}
+
+// resolve は指定に対応するスナップショットIDを返す（指定がない場合は None で最新スナップショットを検索）
+func (s snapshotScope) resolve(ctx context.Context, repo coreingestion.Repository, productID uuid.UUID) (mo.Option[uuid.UUID], error) {
+	switch {
+	case s.Ref != "":
+		gitRef, err := coreingestion.ResolveRefSnapshot(ctx, repo, productID, s.Source, s.Ref)
+		if err != nil {
+			return mo.None[uuid.UUID](), fmt.Errorf("参照の解決に失敗: %w", err)
+		}
+		slog.Info("参照のスナップショットに限定して検索します", "ref", gitRef.RefName, "snapshotID", gitRef.SnapshotID)
+		return mo.Some(gitRef.SnapshotID), nil
+	case s.Snapshot != "":
+		snapshot, err := coreingestion.ResolveSnapshot(ctx, repo, productID, s.Source, s.Snapshot)
+		if err != nil {
+			return mo.None[uuid.UUID](), fmt.Errorf("スナップショットの解決に失敗: %w", err)
+		}
+		slog.Info("指定スナップショットに限定して検索します", "snapshotID", snapshot.ID, "version", snapshot.VersionIdentifier)
+		return mo.Some(snapshot.ID), nil
+	default:
+		return mo.None[uuid.UUID](), nil
+	}
+}
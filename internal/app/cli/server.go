@@ -2,9 +2,12 @@ package cli
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 
 	"github.com/urfave/cli/v3"
+
+	"github.com/jinford/dev-rag/internal/app/server"
 )
 
 // ServerStartAction はHTTPサーバを起動するコマンドのアクション
@@ -18,8 +21,26 @@ func ServerStartAction(ctx context.Context, cmd *cli.Command) error {
 	}
 	defer appCtx.Close()
 
-	// TODO: HTTPサーバの起動
-	slog.Info("HTTPサーバは未実装です")
+	// ポートはフラグ指定を優先し、未指定なら環境変数（HTTP_PORT）を使用
+	port := appCtx.Config.HTTPPort
+	if cmd.IsSet("port") {
+		port = int(cmd.Int("port"))
+	}
+
+	// APIキー認証の初期化
+	auth, err := server.NewAPIKeyAuthenticator(appCtx.Config.APIToken, appCtx.Config.APIKeys)
+	if err != nil {
+		return fmt.Errorf("API認証の初期化に失敗: %w", err)
+	}
+
+	slog.Info("HTTPサーバを起動します", "port", port, "apiKeys", len(appCtx.Config.APIKeys))
+
+	srv := server.NewServer(appCtx.Container, auth, server.WithServerLogger(appCtx.Logger()))
+	if err := srv.ListenAndServe(ctx, fmt.Sprintf(":%d", port)); err != nil {
+		slog.Error("HTTPサーバの実行に失敗しました", "error", err)
+		return err
+	}
 
+	slog.Info("HTTPサーバを停止しました")
 	return nil
 }
@@ -0,0 +1,205 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+	"github.com/urfave/cli/v3"
+
+	"github.com/jinford/dev-rag/internal/core/drift"
+	coreingestion "github.com/jinford/dev-rag/internal/core/ingestion"
+	"github.com/jinford/dev-rag/internal/core/monorepo"
+)
+
+// ServicesListAction はインデックス化時にモノレポから検出したサービスの境界を表示するコマンドのアクション
+// サービスのサブツリーを別のプロダクトとしてインデックス化済み（services map）の場合は、そのプロダクトも表示する
+func ServicesListAction(ctx context.Context, cmd *cli.Command) error {
+	productName := cmd.String("product")
+	envFile := cmd.String("env")
+	kind := monorepo.Kind(cmd.String("kind"))
+	if kind != "" && !slices.Contains(monorepo.Kinds, kind) {
+		return fmt.Errorf("--kind は go・npm・bazel のいずれかを指定してください: %s", kind)
+	}
+
+	// 共通コンテキストの初期化
+	appCtx, err := NewAppContext(ctx, envFile)
+	if err != nil {
+		return err
+	}
+	defer appCtx.Close()
+
+	product, err := getProductByName(ctx, appCtx, productName)
+	if err != nil {
+		return err
+	}
+
+	services, err := appCtx.Container.MonorepoServices.List(ctx, mo.Some(product.ID), mo.None[uuid.UUID]())
+	if err != nil {
+		return fmt.Errorf("サービスの取得に失敗しました: %w", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SOURCE\tVERSION\tPATH\tNAME\tKIND\tTARGETS\tPRODUCT")
+	count := 0
+	for _, s := range services {
+		if kind != "" && s.Kind != kind {
+			continue
+		}
+		count++
+		mapped, err := mappedProductName(ctx, appCtx, s)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			s.SourceName,
+			drift.ShortVersion(s.Version),
+			s.Path,
+			s.Name,
+			s.Kind,
+			valueOrDash(strings.Join(s.Targets, ",")),
+			valueOrDash(mapped),
+		)
+	}
+	if count == 0 {
+		fmt.Println("サービスが見つかりません（go.mod・package.json の workspaces・Bazel の BUILD ファイルはインデックス化時に解析されます）")
+		return nil
+	}
+	return w.Flush()
+}
+
+// ServicesMapAction はモノレポから検出したサービスのサブツリーを、サービスごとのプロダクト（<プロダクト名>-<パス>）としてインデックス化するコマンドのアクション
+// マッピングしたプロダクトを ask・wiki generate の --product に指定すると、サービスに限定して質問・Wiki の生成ができる
+func ServicesMapAction(ctx context.Context, cmd *cli.Command) error {
+	productName := cmd.String("product")
+	selected := cmd.StringSlice("service")
+	ref := cmd.String("ref")
+	generateWiki := cmd.Bool("generate-wiki")
+	dryRun := cmd.Bool("dry-run")
+	envFile := cmd.String("env")
+
+	// 共通コンテキストの初期化
+	appCtx, err := NewAppContext(ctx, envFile)
+	if err != nil {
+		return err
+	}
+	defer appCtx.Close()
+
+	product, err := getProductByName(ctx, appCtx, productName)
+	if err != nil {
+		return err
+	}
+
+	services, err := appCtx.Container.MonorepoServices.List(ctx, mo.Some(product.ID), mo.None[uuid.UUID]())
+	if err != nil {
+		return fmt.Errorf("サービスの取得に失敗しました: %w", err)
+	}
+	targets, err := selectServices(services, selected)
+	if err != nil {
+		return err
+	}
+	if len(targets) == 0 {
+		fmt.Println("サービスが見つかりません（go.mod・package.json の workspaces・Bazel の BUILD ファイルはインデックス化時に解析されます）")
+		return nil
+	}
+
+	// サービスを検出したソースの取得元（Git の URL）を解決する
+	urls := make(map[string]string)
+	for _, s := range targets {
+		if _, ok := urls[s.SourceName]; ok {
+			continue
+		}
+		url, err := gitSourceURL(ctx, appCtx, product.ID, s.SourceName)
+		if err != nil {
+			return err
+		}
+		urls[s.SourceName] = url
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SERVICE\tPATH\tPRODUCT\tSOURCE\tSTATUS")
+	var errs []error
+	for _, s := range targets {
+		subProduct := s.ProductName(product.Name)
+		status := "未実行"
+		if !dryRun {
+			slog.Info("サービスのサブツリーをインデックス化", "service", s.Name, "path", s.Path, "product", subProduct)
+			status = "成功"
+			if err := executeGitIndexing(ctx, appCtx, urls[s.SourceName], subProduct, ref, s.Path, "", false, generateWiki, ""); err != nil {
+				slog.Error("サービスのインデックス化に失敗しました", "service", s.Name, "error", err)
+				errs = append(errs, fmt.Errorf("%s: %w", s.Path, err))
+				status = "失敗"
+			}
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", s.Name, s.Path, subProduct, coreingestion.SubtreeSourceName(s.SourceName, s.Path), status)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	return errors.Join(errs...)
+}
+
+// getProductByName はプロダクト名からプロダクトを取得する（存在しない場合はエラー）
+func getProductByName(ctx context.Context, appCtx *AppContext, productName string) (*coreingestion.Product, error) {
+	productOpt, err := appCtx.Container.IngestionRepo.GetProductByName(ctx, productName)
+	if err != nil {
+		return nil, fmt.Errorf("プロダクト取得に失敗: %w", err)
+	}
+	product, ok := productOpt.Get()
+	if !ok {
+		return nil, fmt.Errorf("プロダクトが見つかりません: %s", productName)
+	}
+	return product, nil
+}
+
+// selectServices は --service で指定したサービス（名前またはディレクトリ）を選択する（指定がない場合はすべて）
+func selectServices(services []*monorepo.Service, selected []string) ([]*monorepo.Service, error) {
+	if len(selected) == 0 {
+		return services, nil
+	}
+	var targets []*monorepo.Service
+	for _, nameOrPath := range selected {
+		found := false
+		for _, s := range services {
+			if !s.Matches(nameOrPath) {
+				continue
+			}
+			found = true
+			if !slices.Contains(targets, s) {
+				targets = append(targets, s)
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("サービスが見つかりません: %s", nameOrPath)
+		}
+	}
+	return targets, nil
+}
+
+// mappedProductName はサービスのサブツリーをインデックス化したソースが属するプロダクトの名前を返す（未マッピングの場合は空）
+func mappedProductName(ctx context.Context, appCtx *AppContext, s *monorepo.Service) (string, error) {
+	repo := appCtx.Container.IngestionRepo
+	sourceOpt, err := repo.GetSourceByName(ctx, coreingestion.SubtreeSourceName(s.SourceName, s.Path))
+	if err != nil {
+		return "", fmt.Errorf("ソースの取得に失敗: %w", err)
+	}
+	source, ok := sourceOpt.Get()
+	if !ok {
+		return "", nil
+	}
+	productOpt, err := repo.GetProductByID(ctx, source.ProductID)
+	if err != nil {
+		return "", fmt.Errorf("プロダクト取得に失敗: %w", err)
+	}
+	if product, ok := productOpt.Get(); ok {
+		return product.Name, nil
+	}
+	return "", nil
+}
@@ -0,0 +1,324 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/urfave/cli/v3"
+
+	coreingestion "github.com/jinford/dev-rag/internal/core/ingestion"
+)
+
+// snapshotStuckThreshold はインデックス化中のスナップショットが停止している可能性を表示するまでの経過時間
+const snapshotStuckThreshold = 2 * time.Hour
+
+// SnapshotListAction はソースのスナップショット一覧を表示するコマンドのアクション
+func SnapshotListAction(ctx context.Context, cmd *cli.Command) error {
+	sourceName := cmd.String("source")
+	envFile := cmd.String("env")
+
+	// 共通コンテキストの初期化
+	appCtx, err := NewAppContext(ctx, envFile)
+	if err != nil {
+		return err
+	}
+	defer appCtx.Close()
+
+	repo := appCtx.Container.IngestionRepo
+
+	sourceOpt, err := repo.GetSourceByName(ctx, sourceName)
+	if err != nil {
+		return fmt.Errorf("ソースの取得に失敗: %w", err)
+	}
+	source, ok := sourceOpt.Get()
+	if !ok {
+		return fmt.Errorf("ソースが見つかりません: %s", sourceName)
+	}
+
+	snapshots, err := repo.ListSnapshotsBySource(ctx, source.ID)
+	if err != nil {
+		return fmt.Errorf("スナップショット一覧の取得に失敗: %w", err)
+	}
+	if len(snapshots) == 0 {
+		slog.Info("スナップショットがありません", "source", sourceName)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tVERSION\tSTATUS\tFILES\tCHUNKS\tDURATION\tCREATED")
+	for _, snapshot := range snapshots {
+		stats, err := repo.GetSnapshotStats(ctx, snapshot.ID)
+		if err != nil {
+			return fmt.Errorf("スナップショット統計の取得に失敗: %w", err)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\t%s\t%s\n",
+			snapshot.ID,
+			snapshot.VersionIdentifier,
+			formatSnapshotStatus(snapshot),
+			stats.FileCount,
+			stats.ChunkCount,
+			formatSnapshotDuration(snapshot),
+			snapshot.CreatedAt.Format(time.DateTime),
+		)
+	}
+	return w.Flush()
+}
+
+// SnapshotShowAction はスナップショット詳細を表示するコマンドのアクション
+func SnapshotShowAction(ctx context.Context, cmd *cli.Command) error {
+	idStr := cmd.String("id")
+	envFile := cmd.String("env")
+
+	snapshotID, err := uuid.Parse(idStr)
+	if err != nil {
+		return fmt.Errorf("スナップショットIDが不正です: %s", idStr)
+	}
+
+	// 共通コンテキストの初期化
+	appCtx, err := NewAppContext(ctx, envFile)
+	if err != nil {
+		return err
+	}
+	defer appCtx.Close()
+
+	repo := appCtx.Container.IngestionRepo
+
+	snapshotOpt, err := repo.GetSnapshotByID(ctx, snapshotID)
+	if err != nil {
+		return fmt.Errorf("スナップショットの取得に失敗: %w", err)
+	}
+	snapshot, ok := snapshotOpt.Get()
+	if !ok {
+		return fmt.Errorf("スナップショットが見つかりません: %s", snapshotID)
+	}
+
+	stats, err := repo.GetSnapshotStats(ctx, snapshot.ID)
+	if err != nil {
+		return fmt.Errorf("スナップショット統計の取得に失敗: %w", err)
+	}
+
+	refs, err := repo.ListGitRefsBySource(ctx, snapshot.SourceID)
+	if err != nil {
+		return fmt.Errorf("Git参照の取得に失敗: %w", err)
+	}
+
+	usageRecords, err := repo.ListSnapshotUsage(ctx, snapshot.ID)
+	if err != nil {
+		return fmt.Errorf("使用量の取得に失敗: %w", err)
+	}
+
+	fmt.Printf("ID:             %s\n", snapshot.ID)
+	fmt.Printf("ソースID:       %s\n", snapshot.SourceID)
+	fmt.Printf("バージョン:     %s\n", snapshot.VersionIdentifier)
+	fmt.Printf("状態:           %s\n", formatSnapshotStatus(snapshot))
+	fmt.Printf("作成日時:       %s\n", snapshot.CreatedAt.Format(time.DateTime))
+	if snapshot.IndexingStartedAt != nil {
+		fmt.Printf("開始日時:       %s\n", snapshot.IndexingStartedAt.Format(time.DateTime))
+	}
+	if snapshot.IndexedAt != nil {
+		fmt.Printf("完了日時:       %s\n", snapshot.IndexedAt.Format(time.DateTime))
+	}
+	if snapshot.FailedAt != nil {
+		fmt.Printf("失敗日時:       %s\n", snapshot.FailedAt.Format(time.DateTime))
+	}
+	if snapshot.FailureReason != "" {
+		fmt.Printf("失敗理由:       %s\n", snapshot.FailureReason)
+	}
+	fmt.Printf("所要時間:       %s\n", formatSnapshotDuration(snapshot))
+	fmt.Printf("ファイル数:     %d\n", stats.FileCount)
+	fmt.Printf("チャンク数:     %d\n", stats.ChunkCount)
+	fmt.Printf("Embedding数:    %d\n", stats.EmbeddingCount)
+
+	for _, ref := range refs {
+		if ref.SnapshotID == snapshot.ID {
+			fmt.Printf("参照:           %s\n", ref.RefName)
+		}
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	// ドメインカバレッジ（snapshot_files が記録されている場合はカバレッジ率も表示）
+	coverages, err := repo.GetDomainCoverageStats(ctx, snapshot.ID)
+	if err != nil {
+		return fmt.Errorf("ドメインカバレッジの取得に失敗: %w", err)
+	}
+	if len(coverages) > 0 {
+		fmt.Println("\n--- ドメインカバレッジ ---")
+		fmt.Fprintln(w, "DOMAIN\tFILES\tINDEXED\tCHUNKS\tCOVERAGE")
+		for _, c := range coverages {
+			fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%.1f%%\n", c.Domain, c.TotalFiles, c.IndexedFiles, c.IndexedChunks, c.CoverageRate)
+		}
+	} else if len(stats.Domains) > 0 {
+		fmt.Println("\n--- ドメイン別件数 ---")
+		fmt.Fprintln(w, "DOMAIN\tFILES\tCHUNKS")
+		for _, d := range stats.Domains {
+			fmt.Fprintf(w, "%s\t%d\t%d\n", d.Domain, d.FileCount, d.ChunkCount)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	// カバレッジアラート（プロダクトの閾値と比較）
+	if len(coverages) > 0 {
+		sourceOpt, err := repo.GetSourceByID(ctx, snapshot.SourceID)
+		if err != nil {
+			return fmt.Errorf("ソースの取得に失敗: %w", err)
+		}
+		if source, ok := sourceOpt.Get(); ok {
+			alerts, err := appCtx.Container.CoverageAlerts.Generate(ctx, source.ProductID, snapshot.ID)
+			if err != nil {
+				return fmt.Errorf("カバレッジアラートの生成に失敗: %w", err)
+			}
+			if len(alerts) > 0 {
+				fmt.Println("\n--- カバレッジアラート ---")
+				for _, alert := range alerts {
+					fmt.Printf("[%s] %s\n", alert.Severity, alert.Message)
+				}
+			}
+		}
+	}
+
+	if len(usageRecords) > 0 {
+		fmt.Println("\n--- LLM使用量 ---")
+		var total float64
+		for _, rec := range usageRecords {
+			fmt.Fprintf(w, "%s\t%s\t%d req\t%d in\t%d out\t$%.4f\n",
+				rec.Kind, rec.Model, rec.Requests, rec.InputTokens, rec.OutputTokens, rec.CostUSD)
+			total += rec.CostUSD
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		fmt.Printf("推定コスト合計: $%.4f\n", total)
+	}
+
+	return nil
+}
+
+// SnapshotDiffAction は同じソースの2つのスナップショットの差分を表示するコマンドのアクション
+// Git の差分ではなく、インデックス化したファイルとチャンクのメタデータを比較する
+func SnapshotDiffAction(ctx context.Context, cmd *cli.Command) error {
+	sourceName := cmd.String("source")
+	envFile := cmd.String("env")
+	asJSON := cmd.Bool("json")
+
+	// 共通コンテキストの初期化
+	appCtx, err := NewAppContext(ctx, envFile)
+	if err != nil {
+		return err
+	}
+	defer appCtx.Close()
+
+	repo := appCtx.Container.IngestionRepo
+
+	sourceOpt, err := repo.GetSourceByName(ctx, sourceName)
+	if err != nil {
+		return fmt.Errorf("ソースの取得に失敗: %w", err)
+	}
+	source, ok := sourceOpt.Get()
+	if !ok {
+		return fmt.Errorf("ソースが見つかりません: %s", sourceName)
+	}
+
+	from, err := coreingestion.ResolveSourceSnapshot(ctx, repo, source, cmd.String("from"))
+	if err != nil {
+		return fmt.Errorf("比較元のスナップショットの解決に失敗: %w", err)
+	}
+	to, err := coreingestion.ResolveSourceSnapshot(ctx, repo, source, cmd.String("to"))
+	if err != nil {
+		return fmt.Errorf("比較先のスナップショットの解決に失敗: %w", err)
+	}
+
+	diff, err := coreingestion.DiffSnapshots(ctx, repo, from, to)
+	if err != nil {
+		return fmt.Errorf("スナップショットの比較に失敗: %w", err)
+	}
+
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(diff)
+	}
+
+	fmt.Printf("比較元: %s (%s)\n", from.VersionIdentifier, from.ID)
+	fmt.Printf("比較先: %s (%s)\n", to.VersionIdentifier, to.ID)
+	fmt.Printf("ファイル: 追加 %d / 削除 %d / 変更 %d / 変更なし %d\n",
+		diff.CountFiles(coreingestion.FileChangeAdded),
+		diff.CountFiles(coreingestion.FileChangeRemoved),
+		diff.CountFiles(coreingestion.FileChangeModified),
+		diff.UnchangedFiles,
+	)
+	fmt.Printf("循環的複雑度: %+d\n", diff.ComplexityDelta)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	if len(diff.Files) > 0 {
+		fmt.Println("\n--- ファイル ---")
+		fmt.Fprintln(w, "CHANGE\tPATH\tCHUNKS")
+		for _, f := range diff.Files {
+			fmt.Fprintf(w, "%s\t%s\t%d -> %d\n", f.Kind, f.Path, f.FromChunks, f.ToChunks)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+
+	if len(diff.Symbols) > 0 {
+		fmt.Println("\n--- シンボル ---")
+		fmt.Fprintln(w, "CHANGE\tTYPE\tNAME\tPATH\tCOMPLEXITY\tSIGNATURE")
+		for _, sym := range diff.Symbols {
+			signature := ""
+			if sym.SignatureChanged {
+				signature = "changed"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+				sym.Kind, sym.Type, sym.Name, sym.Path, formatComplexityChange(sym), signature)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// formatComplexityChange はシンボルの循環的複雑度の変化を「変更前 -> 変更後 (増減)」の形式で返す
+func formatComplexityChange(sym *coreingestion.SymbolChange) string {
+	if sym.FromComplexity == nil && sym.ToComplexity == nil {
+		return "-"
+	}
+	format := func(c *int) string {
+		if c == nil {
+			return "-"
+		}
+		return fmt.Sprintf("%d", *c)
+	}
+	return fmt.Sprintf("%s -> %s (%+d)", format(sym.FromComplexity), format(sym.ToComplexity), sym.ComplexityDelta())
+}
+
+// formatSnapshotStatus はスナップショットの状態を表示用に整形する
+// インデックス化を開始してから snapshotStuckThreshold を超えても indexing のままの場合は、
+// プロセスが強制終了して停止している可能性があるため経過時間を併記する
+func formatSnapshotStatus(snapshot *coreingestion.SourceSnapshot) string {
+	if snapshot.Status != coreingestion.SnapshotIndexing || snapshot.IndexingStartedAt == nil {
+		return string(snapshot.Status)
+	}
+	elapsed := time.Since(*snapshot.IndexingStartedAt)
+	if elapsed < snapshotStuckThreshold {
+		return string(snapshot.Status)
+	}
+	return fmt.Sprintf("%s（%s 経過・停止の可能性あり）", snapshot.Status, elapsed.Round(time.Minute))
+}
+
+// formatSnapshotDuration はスナップショット作成からインデックス完了までの所要時間を返す
+func formatSnapshotDuration(snapshot *coreingestion.SourceSnapshot) string {
+	if snapshot.IndexedAt == nil {
+		return "-"
+	}
+	return snapshot.IndexedAt.Sub(snapshot.CreatedAt).Round(time.Second).String()
+}
@@ -2,11 +2,21 @@ package cli
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
 
 	"github.com/urfave/cli/v3"
 
 	coreingestion "github.com/jinford/dev-rag/internal/core/ingestion"
+	"github.com/jinford/dev-rag/internal/core/usage"
+	"github.com/jinford/dev-rag/internal/platform/database"
 )
 
 // SourceListAction はソース一覧を表示するコマンドのアクション
@@ -49,15 +59,77 @@ func SourceShowAction(ctx context.Context, cmd *cli.Command) error {
 	return nil
 }
 
+// SourceRefsAction はソースで追跡しているGit参照（ブランチ・タグ）の一覧を表示するコマンドのアクション
+func SourceRefsAction(ctx context.Context, cmd *cli.Command) error {
+	name := cmd.String("name")
+	envFile := cmd.String("env")
+
+	// 共通コンテキストの初期化
+	appCtx, err := NewAppContext(ctx, envFile)
+	if err != nil {
+		return err
+	}
+	defer appCtx.Close()
+
+	repo := appCtx.Container.IngestionRepo
+
+	sourceOpt, err := repo.GetSourceByName(ctx, name)
+	if err != nil {
+		return fmt.Errorf("ソースの取得に失敗: %w", err)
+	}
+	source, ok := sourceOpt.Get()
+	if !ok {
+		return fmt.Errorf("ソースが見つかりません: %s", name)
+	}
+
+	refs, err := repo.ListGitRefsBySource(ctx, source.ID)
+	if err != nil {
+		return fmt.Errorf("Git参照一覧の取得に失敗: %w", err)
+	}
+	if len(refs) == 0 {
+		slog.Info("追跡しているGit参照がありません", "source", name)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "REF\tSNAPSHOT\tVERSION\tSTATUS\tUPDATED")
+	for _, ref := range refs {
+		version, status := "-", "-"
+		snapshotOpt, err := repo.GetSnapshotByID(ctx, ref.SnapshotID)
+		if err != nil {
+			return fmt.Errorf("スナップショットの取得に失敗: %w", err)
+		}
+		if snapshot, ok := snapshotOpt.Get(); ok {
+			version, status = snapshot.VersionIdentifier, formatSnapshotStatus(snapshot)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			ref.RefName,
+			ref.SnapshotID,
+			version,
+			status,
+			ref.UpdatedAt.Format(time.DateTime),
+		)
+	}
+	return w.Flush()
+}
+
 // SourceIndexGitAction はGitソースをインデックス化するコマンドのアクション
+// --ref を複数指定した場合は各 ref を並行してインデックス化する
 func SourceIndexGitAction(ctx context.Context, cmd *cli.Command) error {
 	repoURL := cmd.String("url")
 	product := cmd.String("product")
-	ref := cmd.String("ref")
+	refs := cmd.StringSlice("ref")
+	sinceCommit := cmd.String("since-commit")
+	subtree := cleanSubtree(cmd.String("subtree"))
 	forceInit := cmd.Bool("force-init")
 	generateWiki := cmd.Bool("generate-wiki")
+	coverageFile := cmd.String("coverage")
 	envFile := cmd.String("env")
 
+	if coverageFile != "" && len(refs) > 1 {
+		return fmt.Errorf("--coverage は --ref を1つだけ指定した場合に使用できます")
+	}
+
 	// 共通コンテキストの初期化
 	appCtx, err := NewAppContext(ctx, envFile)
 	if err != nil {
@@ -68,42 +140,113 @@ func SourceIndexGitAction(ctx context.Context, cmd *cli.Command) error {
 	slog.Info("Gitソースインデックス処理を開始",
 		"url", repoURL,
 		"product", product,
-		"ref", ref,
+		"refs", refs,
+		"sinceCommit", sinceCommit,
+		"subtree", subtree,
 		"forceInit", forceInit,
 	)
 
 	// Gitソースインデックス処理を実行
-	if err := executeGitIndexing(ctx, appCtx, repoURL, product, ref, forceInit, generateWiki); err != nil {
-		slog.Error("Gitソースインデックス処理に失敗しました", "error", err)
-		return err
+	if len(refs) <= 1 {
+		ref := ""
+		if len(refs) == 1 {
+			ref = refs[0]
+		}
+		if err := executeGitIndexing(ctx, appCtx, repoURL, product, ref, subtree, sinceCommit, forceInit, generateWiki, coverageFile); err != nil {
+			slog.Error("Gitソースインデックス処理に失敗しました", "error", err)
+			return err
+		}
+	} else {
+		// 並行実行時のプロダクト作成の競合を避けるため先に作成しておく
+		if _, err := appCtx.Container.IngestionRepo.CreateProductIfNotExists(ctx, product, nil); err != nil {
+			return fmt.Errorf("プロダクトの取得/作成に失敗: %w", err)
+		}
+
+		var wg sync.WaitGroup
+		errs := make([]error, len(refs))
+		for i, ref := range refs {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if err := executeGitIndexing(ctx, appCtx, repoURL, product, ref, subtree, sinceCommit, forceInit, generateWiki, coverageFile); err != nil {
+					slog.Error("Gitソースインデックス処理に失敗しました", "ref", ref, "error", err)
+					errs[i] = fmt.Errorf("ref %s: %w", ref, err)
+				}
+			}()
+		}
+		wg.Wait()
+		if err := errors.Join(errs...); err != nil {
+			return err
+		}
 	}
 
 	slog.Info("Gitソースインデックス処理が完了しました")
 	return nil
 }
 
+// cleanSubtree は --subtree で指定したパスの先頭の ./ と末尾の / を除く
+func cleanSubtree(subtree string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(subtree, "./"), "/")
+}
+
 // executeGitIndexing はGitリポジトリのインデックス化とWiki要約生成を実行する
-func executeGitIndexing(ctx context.Context, appCtx *AppContext, repoURL, productName, ref string, forceInit bool, generateWiki bool) error {
-	// 1. インデックス化を実行
-	slog.Info("インデックス化を開始します", "url", repoURL, "product", productName)
+// 同じリポジトリ・同じ参照のインデックス化は、アドバイザリロックでプロセスをまたいで直列化する
+// sinceCommit を指定した場合は、そのコミットから変更されたファイルだけを取得する差分インデックス化を行う
+func executeGitIndexing(ctx context.Context, appCtx *AppContext, repoURL, productName, ref, subtree, sinceCommit string, forceInit bool, generateWiki bool, coverageFile string) error {
+	db := appCtx.Container.Database()
+	if db == nil {
+		return indexGitSource(ctx, appCtx, repoURL, productName, ref, subtree, sinceCommit, forceInit, generateWiki, coverageFile)
+	}
+
+	slog.Debug("インデックス化のロックを取得します", "url", repoURL, "ref", ref, "subtree", subtree)
+	lockID := database.GenerateLockID("index", repoURL, ref, subtree)
+	return db.WithAdvisoryLock(ctx, lockID, func(ctx context.Context) error {
+		return indexGitSource(ctx, appCtx, repoURL, productName, ref, subtree, sinceCommit, forceInit, generateWiki, coverageFile)
+	})
+}
+
+// indexGitSource はロック取得後にインデックス化・要約生成・用語集の抽出を順に実行する
+func indexGitSource(ctx context.Context, appCtx *AppContext, repoURL, productName, ref, subtree, sinceCommit string, forceInit bool, generateWiki bool, coverageFile string) error {
+	slog.Info("インデックス化を開始します", "url", repoURL, "product", productName, "subtree", subtree)
 
 	params := coreingestion.IndexParams{
-		Identifier:  repoURL,
-		ProductName: productName,
-		ForceInit:   forceInit,
+		Identifier:   repoURL,
+		ProductName:  productName,
+		ForceInit:    forceInit,
+		SinceVersion: sinceCommit,
+		Subtree:      subtree,
 		Options: map[string]any{
 			"ref": ref,
 		},
 	}
+	return runIndexing(ctx, appCtx, appCtx.Container.IndexService, params, generateWiki, coverageFile)
+}
+
+// runIndexing はインデックス化・要約生成・用語集の抽出を順に実行する（ソースタイプ共通）
+// coverageFile を指定した場合は、インデックス化したスナップショットにテストのカバレッジレポートを取り込む
+func runIndexing(ctx context.Context, appCtx *AppContext, indexService *coreingestion.IndexService, params coreingestion.IndexParams, generateWiki bool, coverageFile string) error {
+	productName := params.ProductName
 
-	// Application層のIndexServiceを使用
-	result, err := appCtx.Container.IndexService.IndexSource(ctx, params)
+	// LLM/Embedding の使用量を計測し、予算を適用する
+	tracker := appCtx.Container.NewUsageTracker()
+	ctx = usage.WithTracker(ctx, tracker)
+
+	// 1. インデックス化を実行（端末では進捗バー、それ以外では一定の間隔のログで進捗を表示）
+	progress := newProgressRenderer(appCtx.Logger())
+	result, err := indexService.IndexSource(coreingestion.WithProgressReporter(ctx, progress), params)
+	progress.Finish()
 	if err != nil {
+		var incomplete *coreingestion.SnapshotIncompleteError
+		if errors.As(err, &incomplete) {
+			printIncompleteSnapshot(incomplete)
+		}
+		logUsage(tracker)
 		return err
 	}
 
 	slog.Info("インデックス化が完了しました",
 		"snapshotID", result.SnapshotID,
+		"ref", result.Ref,
 		"processedFiles", result.ProcessedFiles,
 		"totalChunks", result.TotalChunks,
 		"duration", result.Duration,
@@ -119,10 +262,188 @@ func executeGitIndexing(ctx context.Context, appCtx *AppContext, repoURL, produc
 		slog.Info("要約生成が完了しました", "snapshotID", result.SnapshotID)
 	}
 
-	// 3. Wiki生成（未実装スタブ）
+	// 3. プロダクト全体の要約の生成（説明の自動設定・質問の振り分けに使用）
+	if err := appCtx.Container.SummaryService.GenerateForProduct(ctx, productName); err != nil {
+		slog.Warn("プロダクト要約の生成に失敗しました（インデックス化は成功）", "product", productName, "error", err)
+	}
+
+	// 4. アーキテクチャのドリフトの検出（直前のスナップショットとの比較、大きな変化は品質ノートに記録）
+	if _, err := appCtx.Container.ArchitectureDrift.DetectForSnapshot(ctx, result.SnapshotID); err != nil {
+		slog.Warn("アーキテクチャのドリフトの検出に失敗しました（インデックス化は成功）", "snapshotID", result.SnapshotID, "error", err)
+	}
+
+	// 5. テストのカバレッジレポートの取り込み（ホットスポットのレポート・テストに関する質問に使用）
+	if coverageFile != "" {
+		if imported, err := appCtx.Container.TestCoverage.ImportFile(ctx, result.SnapshotID, coverageFile, ""); err != nil {
+			slog.Warn("カバレッジレポートの取り込みに失敗しました（インデックス化は成功）", "file", coverageFile, "error", err)
+		} else {
+			slog.Info("カバレッジレポートを取り込みました", "files", imported.MatchedFiles, "unmatched", len(imported.Unmatched), "coverage", fmt.Sprintf("%.1f%%", imported.Percent()))
+		}
+	}
+
+	// 6. 用語集の抽出（プロダクト単位）
+	if glossary, err := appCtx.Container.ExtractGlossaryOnIndex(ctx, productName); err != nil {
+		slog.Warn("用語集の抽出に失敗しました（インデックス化は成功）", "product", productName, "error", err)
+	} else if glossary != nil {
+		slog.Info("用語集の抽出が完了しました", "product", productName, "terms", glossary.Terms, "deleted", glossary.DeletedTerms)
+	}
+	if err := coreingestion.RecordSnapshotUsage(ctx, appCtx.Container.IngestionRepo, result.SnapshotID); err != nil {
+		slog.Warn("使用量の記録に失敗しました", "snapshotID", result.SnapshotID, "error", err)
+	}
+	logUsage(tracker)
+
+	// 7. Wiki生成（未実装スタブ）
 	if generateWiki {
 		slog.Warn("Wiki生成は新アーキテクチャでは未実装のためスキップします")
 	}
 
 	return nil
 }
+
+// SourceIndexDirAction はローカルディレクトリをインデックス化するコマンドのアクション
+// Git リモートを持たないディレクトリを対象とし、ファイル内容のハッシュで差分を検出する
+func SourceIndexDirAction(ctx context.Context, cmd *cli.Command) error {
+	path := cmd.String("path")
+	product := cmd.String("product")
+	forceInit := cmd.Bool("force-init")
+	generateWiki := cmd.Bool("generate-wiki")
+	coverageFile := cmd.String("coverage")
+	envFile := cmd.String("env")
+
+	dirPath, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("パスの解決に失敗: %w", err)
+	}
+	info, err := os.Stat(dirPath)
+	if err != nil {
+		return fmt.Errorf("ディレクトリの取得に失敗: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("ディレクトリではありません: %s", dirPath)
+	}
+
+	// 共通コンテキストの初期化
+	appCtx, err := NewAppContext(ctx, envFile)
+	if err != nil {
+		return err
+	}
+	defer appCtx.Close()
+
+	slog.Info("ローカルディレクトリのインデックス処理を開始",
+		"path", dirPath,
+		"product", product,
+		"forceInit", forceInit,
+	)
+
+	params := coreingestion.IndexParams{
+		Identifier:  dirPath,
+		ProductName: product,
+		ForceInit:   forceInit,
+	}
+	// 同じディレクトリのインデックス化は、アドバイザリロックでプロセスをまたいで直列化する
+	err = withIndexLock(ctx, appCtx, database.GenerateLockID("index", "local", dirPath), func(ctx context.Context) error {
+		return runIndexing(ctx, appCtx, appCtx.Container.LocalIndexService, params, generateWiki, coverageFile)
+	})
+	if err != nil {
+		slog.Error("ローカルディレクトリのインデックス処理に失敗しました", "error", err)
+		return err
+	}
+
+	slog.Info("ローカルディレクトリのインデックス処理が完了しました")
+	return nil
+}
+
+// SourceIndexDiscussionsAction は GitHub・GitLab の Issue・Pull Request の議論をインデックス化するコマンドのアクション
+// 議論ごとに1つの文書としてインデックス化し、議論で言及されたファイルパスを記録する
+func SourceIndexDiscussionsAction(ctx context.Context, cmd *cli.Command) error {
+	repoURL := cmd.String("url")
+	product := cmd.String("product")
+	forgeKind := cmd.String("forge")
+	maxThreads := cmd.Int("max-threads")
+	forceInit := cmd.Bool("force-init")
+	envFile := cmd.String("env")
+
+	// 共通コンテキストの初期化
+	appCtx, err := NewAppContext(ctx, envFile)
+	if err != nil {
+		return err
+	}
+	defer appCtx.Close()
+
+	slog.Info("議論のインデックス処理を開始",
+		"url", repoURL,
+		"product", product,
+		"forge", forgeKind,
+		"maxThreads", maxThreads,
+		"forceInit", forceInit,
+	)
+
+	params := coreingestion.IndexParams{
+		Identifier:  repoURL,
+		ProductName: product,
+		ForceInit:   forceInit,
+		Options: map[string]any{
+			"forge":       forgeKind,
+			"max_threads": int(maxThreads),
+		},
+	}
+	err = withIndexLock(ctx, appCtx, database.GenerateLockID("index", "discussions", repoURL), func(ctx context.Context) error {
+		return runIndexing(ctx, appCtx, appCtx.Container.DiscussionIndexService, params, false, "")
+	})
+	if err != nil {
+		slog.Error("議論のインデックス処理に失敗しました", "error", err)
+		return err
+	}
+
+	slog.Info("議論のインデックス処理が完了しました")
+	return nil
+}
+
+// withIndexLock はアドバイザリロックを取得して fn を実行する（DBを使用しない場合はそのまま実行する）
+func withIndexLock(ctx context.Context, appCtx *AppContext, lockID int64, fn func(ctx context.Context) error) error {
+	db := appCtx.Container.Database()
+	if db == nil {
+		return fn(ctx)
+	}
+	return db.WithAdvisoryLock(ctx, lockID, fn)
+}
+
+// printIncompleteSnapshot は失敗・中断したスナップショットの状態と再開の方法を表示する
+func printIncompleteSnapshot(e *coreingestion.SnapshotIncompleteError) {
+	if e.Interrupted() {
+		fmt.Println("インデックス化を中断しました")
+	} else {
+		fmt.Println("インデックス化に失敗しました")
+	}
+	fmt.Printf("  スナップショット: %s（バージョン %s、未完了として記録）\n", e.SnapshotID, e.VersionIdentifier)
+	if e.CompletedFiles > 0 {
+		fmt.Printf("  前回までに完了:   %d ファイル\n", e.CompletedFiles)
+	}
+	if e.Stats != nil {
+		fmt.Printf("  今回完了:         %d / %d ファイル（%d チャンク）\n", e.Stats.ProcessedFiles, e.Stats.TargetFiles, e.Stats.TotalChunks)
+		if e.Stats.FailedFiles > 0 || e.Stats.FailedEmbeddings > 0 {
+			fmt.Printf("  失敗:             %d ファイル、Embedding %d チャンク\n", e.Stats.FailedFiles, e.Stats.FailedEmbeddings)
+		}
+	}
+	fmt.Println("  同じコマンドを再実行すると、Embedding の保存まで完了したファイルをスキップして再開します")
+}
+
+// logUsage は実行中に消費したトークン数と推定コストを出力する
+func logUsage(tracker *usage.Tracker) {
+	for _, rec := range tracker.Records() {
+		slog.Info("LLM使用量",
+			"kind", rec.Kind,
+			"model", rec.Model,
+			"requests", rec.Requests,
+			"inputTokens", rec.InputTokens,
+			"outputTokens", rec.OutputTokens,
+			"costUSD", fmt.Sprintf("%.4f", rec.CostUSD),
+		)
+	}
+	budget := tracker.Budget()
+	slog.Info("推定コスト合計",
+		"costUSD", fmt.Sprintf("%.4f", tracker.TotalCost()),
+		"budgetUSD", budget.MaxCostUSD,
+		"exceeded", tracker.Exceeded(),
+	)
+}
@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v3"
+
+	coreingestion "github.com/jinford/dev-rag/internal/core/ingestion"
+	"github.com/jinford/dev-rag/internal/core/ingestion/summary"
+	"github.com/jinford/dev-rag/internal/core/usage"
+)
+
+// SummariesRefreshAction はプロダクトの各ソースの最新スナップショットについて、
+// 内容が変わった・プロンプトのバージョンが古いファイルの要約のみを再生成するコマンドのアクション
+func SummariesRefreshAction(ctx context.Context, cmd *cli.Command) error {
+	productName := cmd.String("product")
+	envFile := cmd.String("env")
+	opts := summary.RefreshOptions{
+		Concurrency: int(cmd.Int("concurrency")),
+		DryRun:      cmd.Bool("dry-run"),
+		FilesOnly:   cmd.Bool("files-only"),
+	}
+
+	if opts.Concurrency < 0 {
+		return fmt.Errorf("--concurrency には1以上の値を指定してください: %d", opts.Concurrency)
+	}
+	if cmd.IsSet("budget-usd") && cmd.Float("budget-usd") < 0 {
+		return fmt.Errorf("--budget-usd には0以上の値を指定してください: %v", cmd.Float("budget-usd"))
+	}
+
+	// 共通コンテキストの初期化
+	appCtx, err := NewAppContext(ctx, envFile)
+	if err != nil {
+		return err
+	}
+	defer appCtx.Close()
+
+	repo := appCtx.Container.IngestionRepo
+
+	productOpt, err := repo.GetProductByName(ctx, productName)
+	if err != nil {
+		return fmt.Errorf("プロダクト取得に失敗: %w", err)
+	}
+	if productOpt.IsAbsent() {
+		return fmt.Errorf("プロダクトが見つかりません: %s", productName)
+	}
+	product := productOpt.MustGet()
+
+	sources, err := repo.ListSourcesByProductID(ctx, product.ID)
+	if err != nil {
+		return fmt.Errorf("ソース一覧の取得に失敗: %w", err)
+	}
+
+	// LLM/Embedding の使用量を計測し、予算を適用する（--budget-usd で LLM_BUDGET_USD を上書き）
+	tracker := appCtx.Container.NewUsageTracker()
+	if cmd.IsSet("budget-usd") {
+		tracker = appCtx.Container.NewUsageTrackerWithMaxCost(cmd.Float("budget-usd"))
+	}
+	ctx = usage.WithTracker(ctx, tracker)
+
+	refreshed := 0
+	for _, source := range sources {
+		snapshotOpt, err := repo.GetLatestIndexedSnapshot(ctx, source.ID)
+		if err != nil {
+			return fmt.Errorf("スナップショットの取得に失敗: %w", err)
+		}
+		snapshot, ok := snapshotOpt.Get()
+		if !ok {
+			continue
+		}
+		refreshed++
+
+		result, err := appCtx.Container.SummaryService.Refresh(ctx, snapshot.ID, opts)
+		if recordErr := coreingestion.RecordSnapshotUsage(ctx, repo, snapshot.ID); recordErr != nil {
+			slog.Warn("使用量の記録に失敗しました", "snapshotID", snapshot.ID, "error", recordErr)
+		}
+		if result != nil {
+			printRefreshResult(source, snapshot, result, opts.DryRun)
+		}
+		if err != nil {
+			if errors.Is(err, usage.ErrBudgetExceeded) {
+				logUsage(tracker)
+				return fmt.Errorf("予算を超過したため要約の再生成を中断しました: %w", err)
+			}
+			return fmt.Errorf("要約の再生成に失敗しました (%s): %w", source.Name, err)
+		}
+	}
+
+	if refreshed == 0 {
+		fmt.Printf("インデックス済みのスナップショットがありません: %s\n", productName)
+		return nil
+	}
+	if !opts.DryRun {
+		logUsage(tracker)
+	}
+	return nil
+}
+
+// printRefreshResult は要約の再生成が必要なファイルと再生成の結果を表示する
+func printRefreshResult(source *coreingestion.Source, snapshot *coreingestion.SourceSnapshot, r *summary.RefreshResult, dryRun bool) {
+	fmt.Printf("=== %s（スナップショット %s）===\n", source.Name, shortHash(snapshot.VersionIdentifier))
+	fmt.Printf("ファイル数:         %d\n", r.TotalFiles)
+	fmt.Printf("再生成が必要:       %d（プロンプトのバージョン %s）\n", len(r.Stale), r.PromptVersion)
+	if !dryRun && len(r.Stale) > 0 {
+		fmt.Printf("再生成:             %d（失敗 %d、予算超過でスキップ %d）\n", r.Regenerated, r.Failed, r.Skipped)
+	}
+	if len(r.Stale) == 0 {
+		fmt.Println()
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PATH\tREASON\tPROMPT VERSION")
+	for _, f := range r.Stale {
+		version := f.PromptVersion
+		if version == "" {
+			version = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", f.File.Path, f.Reason, version)
+	}
+	_ = w.Flush()
+	fmt.Println()
+}
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+	"github.com/urfave/cli/v3"
+
+	"github.com/jinford/dev-rag/internal/core/symbol"
+)
+
+// SymbolFindAction はシンボル名の完全一致で関数・メソッド・型などの定義位置を表示するコマンドのアクション
+func SymbolFindAction(ctx context.Context, cmd *cli.Command) error {
+	name := cmd.Args().First()
+	productName := cmd.String("product")
+	envFile := cmd.String("env")
+	limit := int(cmd.Int("limit"))
+
+	if name == "" {
+		return fmt.Errorf("シンボル名を指定してください")
+	}
+	if limit <= 0 {
+		return fmt.Errorf("--limit には1以上の値を指定してください")
+	}
+
+	// 共通コンテキストの初期化
+	appCtx, err := NewAppContext(ctx, envFile)
+	if err != nil {
+		return err
+	}
+	defer appCtx.Close()
+
+	productOpt, err := appCtx.Container.IngestionRepo.GetProductByName(ctx, productName)
+	if err != nil {
+		return fmt.Errorf("プロダクト取得に失敗: %w", err)
+	}
+	if productOpt.IsAbsent() {
+		return fmt.Errorf("プロダクトが見つかりません: %s", productName)
+	}
+	product := productOpt.MustGet()
+
+	symbols, err := symbol.Find(ctx, appCtx.Container.Symbols, mo.Some(product.ID), mo.None[uuid.UUID](), name, cmd.String("kind"), limit)
+	if err != nil {
+		return fmt.Errorf("シンボルの検索に失敗しました: %w", err)
+	}
+	if len(symbols) == 0 {
+		fmt.Printf("シンボルが見つかりません: %s\n", name)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SOURCE\tKIND\tSYMBOL\tLOCATION")
+	for _, sym := range symbols {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s:%d-%d\n",
+			sym.SourceName,
+			sym.Kind,
+			sym.QualifiedName(),
+			sym.FilePath,
+			sym.StartLine,
+			sym.EndLine,
+		)
+	}
+	return w.Flush()
+}
@@ -4,10 +4,17 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"path"
+	"path/filepath"
+	"strings"
 
+	"github.com/google/uuid"
 	"github.com/urfave/cli/v3"
 
+	coreingestion "github.com/jinford/dev-rag/internal/core/ingestion"
 	corewiki "github.com/jinford/dev-rag/internal/core/wiki"
+	"github.com/jinford/dev-rag/internal/infra/confluence"
+	"github.com/jinford/dev-rag/internal/infra/git"
 	"github.com/samber/mo"
 )
 
@@ -15,11 +22,45 @@ import (
 func WikiGenerateAction(ctx context.Context, cmd *cli.Command) error {
 	product := cmd.String("product")
 	out := cmd.String("out")
+	configPath := cmd.String("config")
+	sections := cmd.StringSlice("section")
 	envFile := cmd.String("env")
+	strict := cmd.Bool("strict")
+	maxBroken := int(cmd.Int("max-broken"))
+	if maxBroken < 0 {
+		return fmt.Errorf("--max-broken は0以上で指定してください: %d", maxBroken)
+	}
+	concurrency := int(cmd.Int("concurrency"))
+	if concurrency < 0 {
+		return fmt.Errorf("--concurrency は1以上で指定してください: %d", concurrency)
+	}
+	var languages []corewiki.Language
+	if cmd.IsSet("lang") {
+		parsed, err := corewiki.ParseLanguages(cmd.StringSlice("lang"))
+		if err != nil {
+			return fmt.Errorf("--lang の指定が不正です: %w", err)
+		}
+		languages = parsed
+	}
+
+	wikiConfig, err := loadWikiConfig(configPath)
+	if err != nil {
+		return err
+	}
+	if len(sections) > 0 {
+		ids := make([]corewiki.WikiSection, len(sections))
+		for i, section := range sections {
+			ids[i] = corewiki.WikiSection(section)
+		}
+		if wikiConfig, err = wikiConfig.Select(ids); err != nil {
+			return fmt.Errorf("--section の指定が不正です: %w", err)
+		}
+	}
 
 	slog.Info("Wiki生成を開始",
 		"product", product,
 		"out", out,
+		"sections", len(wikiConfig.Sections),
 	)
 
 	// 共通コンテキストの初期化
@@ -38,27 +79,53 @@ func WikiGenerateAction(ctx context.Context, cmd *cli.Command) error {
 	}
 
 	// Wiki生成処理を実行
-	if err := executeWikiGeneration(ctx, appCtx, product, outputDir); err != nil {
+	results, err := executeWikiGeneration(ctx, appCtx, product, outputDir, wikiConfig, concurrency, languages)
+	if err != nil {
 		slog.Error("Wiki生成に失敗しました", "error", err)
 		return err
 	}
 
+	for _, result := range results {
+		if len(languages) > 0 {
+			fmt.Printf("[%s] %s\n", result.Language, result.OutputDir)
+		}
+		printLinkReport(result.LinkReport)
+	}
+	for _, result := range results {
+		if strict && result.LinkReport.Exceeds(maxBroken) {
+			return fmt.Errorf("解決できない参照が %d 件あり、許容数（%d 件）を超えています（%s）", result.LinkReport.BrokenCount(), maxBroken, result.OutputDir)
+		}
+	}
+
 	slog.Info("Wiki生成が完了しました")
 	return nil
 }
 
+// printLinkReport は生成ページの参照チェックの結果を表示する
+func printLinkReport(report *corewiki.LinkReport) {
+	fmt.Printf("参照チェック: %d ページ、%d 件の参照を検証し、%d 件が解決できませんでした\n",
+		report.CheckedPages, report.CheckedReferences, report.BrokenCount())
+	if !report.SourceFilesChecked {
+		fmt.Println("  （スナップショットのファイル一覧を取得できなかったため、ソースファイルの引用は検証していません）")
+	}
+	for _, broken := range report.Broken {
+		fmt.Printf("  %s:%d [%s] %s — %s\n", broken.Page, broken.Line, broken.Kind, broken.Target, broken.Reason)
+	}
+}
+
 // executeWikiGeneration はプロダクト単位でWikiページを生成する
-func executeWikiGeneration(ctx context.Context, appCtx *AppContext, productName, outputDir string) error {
+// languages を指定した場合は言語ごとに <outputDir>/<プロダクト名>/<言語>/ に出力する
+func executeWikiGeneration(ctx context.Context, appCtx *AppContext, productName, outputDir string, wikiConfig *corewiki.Config, concurrency int, languages []corewiki.Language) ([]*corewiki.GenerateResult, error) {
 	repo := appCtx.Container.IngestionRepo
 
 	// 1. プロダクト名からプロダクトを取得
 	slog.Info("プロダクトを取得します", "product", productName)
 	productOpt, err := repo.GetProductByName(ctx, productName)
 	if err != nil {
-		return fmt.Errorf("プロダクト取得に失敗: %w", err)
+		return nil, fmt.Errorf("プロダクト取得に失敗: %w", err)
 	}
 	if productOpt.IsAbsent() {
-		return fmt.Errorf("プロダクトが見つかりません: %s", productName)
+		return nil, fmt.Errorf("プロダクトが見つかりません: %s", productName)
 	}
 	product := productOpt.MustGet()
 
@@ -68,20 +135,272 @@ func executeWikiGeneration(ctx context.Context, appCtx *AppContext, productName,
 	productOutputDir := fmt.Sprintf("%s/%s", outputDir, product.Name)
 
 	params := corewiki.GenerateParams{
-		ProductID: mo.Some(product.ID),
-		OutputDir: productOutputDir,
+		ProductID:   mo.Some(product.ID),
+		OutputDir:   productOutputDir,
+		Config:      wikiConfig,
+		Concurrency: concurrency,
 	}
 
 	slog.Info("Wiki生成を開始します",
 		"productID", product.ID,
 		"productName", product.Name,
 		"outputDir", productOutputDir,
+		"languages", languages,
+	)
+
+	if len(languages) == 0 {
+		result, err := appCtx.Container.WikiService.Generate(ctx, params)
+		if err != nil {
+			return nil, fmt.Errorf("Wiki生成に失敗: %w", err)
+		}
+		slog.Info("Wiki生成処理完了", "productName", product.Name, "pages", result.Pages)
+		return []*corewiki.GenerateResult{result}, nil
+	}
+
+	results, err := appCtx.Container.WikiService.GenerateLanguages(ctx, params, languages)
+	if err != nil {
+		return nil, fmt.Errorf("Wiki生成に失敗: %w", err)
+	}
+	for _, result := range results {
+		slog.Info("Wiki生成処理完了", "productName", product.Name, "language", result.Language, "pages", result.Pages)
+	}
+	return results, nil
+}
+
+// WikiConfigAction はWiki生成設定を表示するコマンドのアクション
+// --config を指定した場合は設定ファイルを検証し、既定値を補完した結果を表示する
+// 省略した場合は既定の設定を表示する（設定ファイルの雛形として使用できる）
+func WikiConfigAction(ctx context.Context, cmd *cli.Command) error {
+	wikiConfig, err := loadWikiConfig(cmd.String("config"))
+	if err != nil {
+		return err
+	}
+
+	data, err := wikiConfig.Marshal()
+	if err != nil {
+		return fmt.Errorf("Wiki生成設定の出力に失敗: %w", err)
+	}
+	fmt.Print(string(data))
+	return nil
+}
+
+// loadWikiConfig はWiki生成設定ファイルを読み込んで検証する（パスが空の場合は既定の設定）
+func loadWikiConfig(path string) (*corewiki.Config, error) {
+	if path == "" {
+		return corewiki.DefaultConfig(), nil
+	}
+
+	wikiConfig, err := corewiki.LoadConfig(path)
+	if err != nil {
+		return nil, fmt.Errorf("Wiki生成設定の読み込みに失敗: %w", err)
+	}
+	if err := wikiConfig.CheckDomains(coreingestion.IsValidDomain); err != nil {
+		return nil, fmt.Errorf("Wiki生成設定の読み込みに失敗: %w（指定可能なドメイン: %s）", err, strings.Join(coreingestion.Domains, ", "))
+	}
+	slog.Info("Wiki生成設定を読み込みました", "config", path, "sections", len(wikiConfig.Sections))
+	return wikiConfig, nil
+}
+
+// WikiPublishConfluenceAction は生成済みのWikiを Confluence に公開するコマンドのアクション
+// 公開したページのIDはDBに記録し、再実行時は変更のあったページのみを更新する
+func WikiPublishConfluenceAction(ctx context.Context, cmd *cli.Command) error {
+	productName := cmd.String("product")
+	spaceKey := cmd.String("space")
+	envFile := cmd.String("env")
+
+	appCtx, err := NewAppContext(ctx, envFile)
+	if err != nil {
+		return err
+	}
+	defer appCtx.Close()
+
+	confluenceConfig := appCtx.Config.Confluence
+	if confluenceConfig.BaseURL == "" || confluenceConfig.APIToken == "" {
+		return fmt.Errorf("CONFLUENCE_BASE_URL と CONFLUENCE_API_TOKEN を設定してください")
+	}
+
+	productOpt, err := appCtx.Container.IngestionRepo.GetProductByName(ctx, productName)
+	if err != nil {
+		return fmt.Errorf("プロダクト取得に失敗: %w", err)
+	}
+	if productOpt.IsAbsent() {
+		return fmt.Errorf("プロダクトが見つかりません: %s", productName)
+	}
+	product := productOpt.MustGet()
+
+	language, err := publishLanguage(cmd)
+	if err != nil {
+		return err
+	}
+	wikiDir := publishWikiDir(cmd, appCtx, product.Name, language)
+
+	client := confluence.NewClient(confluenceConfig.BaseURL, confluenceConfig.User, confluenceConfig.APIToken)
+	publisher := corewiki.NewConfluencePublisher(client, appCtx.Container.WikiPublications,
+		corewiki.WithConfluencePublisherLogger(appCtx.Container.Logger()),
+	)
+
+	slog.Info("Confluenceへの公開を開始", "product", product.Name, "space", spaceKey, "dir", wikiDir, "language", language)
+	result, err := publisher.Publish(ctx, corewiki.PublishParams{
+		ProductID:    product.ID,
+		ProductName:  product.Name,
+		SpaceKey:     spaceKey,
+		WikiDir:      wikiDir,
+		Language:     language,
+		ParentPageID: cmd.String("parent-id"),
+		Prune:        cmd.Bool("prune"),
+		DryRun:       cmd.Bool("dry-run"),
+	})
+	if err != nil {
+		return fmt.Errorf("Confluenceへの公開に失敗: %w", err)
+	}
+
+	if cmd.Bool("dry-run") {
+		fmt.Println("（ドライラン: Confluence とDBは変更していません）")
+	}
+	printPublishedPages("作成", result.Created)
+	printPublishedPages("更新", result.Updated)
+	printPublishedPages("削除", result.Deleted)
+	printPublishedPages("ローカルに存在しない（--prune で削除）", result.Stale)
+	fmt.Printf("作成: %d 件 / 更新: %d 件 / 変更なし: %d 件 / 削除: %d 件\n",
+		len(result.Created), len(result.Updated), len(result.Unchanged), len(result.Deleted))
+	return nil
+}
+
+// WikiPublishGitAction は生成済みのWikiをGitリポジトリにコミットするコマンドのアクション
+// --repo を指定した場合は wiki/<プロダクト名>/ 配下、--wiki-of を指定した場合はソースのリポジトリの Wiki のルートに配置する
+func WikiPublishGitAction(ctx context.Context, cmd *cli.Command) error {
+	productName := cmd.String("product")
+	repoURL := cmd.String("repo")
+	wikiOf := cmd.String("wiki-of")
+	envFile := cmd.String("env")
+
+	if (repoURL == "") == (wikiOf == "") {
+		return fmt.Errorf("--repo と --wiki-of のどちらか一方を指定してください")
+	}
+
+	appCtx, err := NewAppContext(ctx, envFile)
+	if err != nil {
+		return err
+	}
+	defer appCtx.Close()
+
+	productOpt, err := appCtx.Container.IngestionRepo.GetProductByName(ctx, productName)
+	if err != nil {
+		return fmt.Errorf("プロダクト取得に失敗: %w", err)
+	}
+	if productOpt.IsAbsent() {
+		return fmt.Errorf("プロダクトが見つかりません: %s", productName)
+	}
+	product := productOpt.MustGet()
+
+	language, err := publishLanguage(cmd)
+	if err != nil {
+		return err
+	}
+
+	targetDir := cmd.String("path")
+	if wikiOf != "" {
+		sourceURL, err := gitSourceURL(ctx, appCtx, product.ID, wikiOf)
+		if err != nil {
+			return err
+		}
+		repoURL = git.WikiRepositoryURL(sourceURL)
+	} else if !cmd.IsSet("path") {
+		targetDir = "wiki/" + product.Name
+	}
+	if cmd.IsSet("lang") && !cmd.IsSet("path") {
+		targetDir = path.Join(targetDir, string(language))
+	}
+
+	wikiDir := publishWikiDir(cmd, appCtx, product.Name, language)
+
+	gitConfig := appCtx.Config.Git
+	client := git.NewClient(gitConfig.SSHKeyPath, gitConfig.SSHPassword,
+		git.WithHTTPToken(gitConfig.HTTPToken),
+		git.WithCommitAuthor(gitConfig.AuthorName, gitConfig.AuthorEmail),
 	)
+	publisher := corewiki.NewGitPublisher(client, corewiki.WithGitPublisherLogger(appCtx.Container.Logger()))
 
-	if err := appCtx.Container.WikiService.Generate(ctx, params); err != nil {
-		return fmt.Errorf("Wiki生成に失敗: %w", err)
+	slog.Info("Gitリポジトリへの公開を開始", "product", product.Name, "repo", repoURL, "branch", cmd.String("branch"), "path", targetDir)
+	result, err := publisher.Publish(ctx, corewiki.GitPublishParams{
+		ProductName: product.Name,
+		RepoURL:     repoURL,
+		Branch:      cmd.String("branch"),
+		WikiDir:     wikiDir,
+		TargetDir:   targetDir,
+		DryRun:      cmd.Bool("dry-run"),
+	})
+	if err != nil {
+		return fmt.Errorf("Gitリポジトリへの公開に失敗: %w", err)
 	}
 
-	slog.Info("Wiki生成処理完了", "productName", product.Name)
+	if !result.HasChanges() {
+		fmt.Printf("変更はありません（ブランチ: %s）\n", result.Branch)
+		return nil
+	}
+	if cmd.Bool("dry-run") {
+		fmt.Println("（ドライラン: コミット・プッシュしていません）")
+	}
+	printPublishedPages("追加", result.Added)
+	printPublishedPages("更新", result.Modified)
+	printPublishedPages("削除", result.Deleted)
+	if result.CommitHash != "" {
+		fmt.Printf("コミット: %s（ブランチ: %s）\n", result.CommitHash, result.Branch)
+	}
 	return nil
 }
+
+// publishLanguage は公開するWikiの言語を返す（--lang を省略した場合は DefaultLanguage）
+func publishLanguage(cmd *cli.Command) (corewiki.Language, error) {
+	if !cmd.IsSet("lang") {
+		return corewiki.DefaultLanguage, nil
+	}
+	languages, err := corewiki.ParseLanguages([]string{cmd.String("lang")})
+	if err != nil {
+		return "", fmt.Errorf("--lang の指定が不正です: %w", err)
+	}
+	return languages[0], nil
+}
+
+// publishWikiDir は公開する生成済みWikiのディレクトリを返す
+// --dir を省略した場合は WIKI_OUTPUT_DIR/<プロダクト名>（--lang 指定時はその下の言語のディレクトリ）
+func publishWikiDir(cmd *cli.Command, appCtx *AppContext, productName string, language corewiki.Language) string {
+	if dir := cmd.String("dir"); dir != "" {
+		return dir
+	}
+	wikiDir := filepath.Join(appCtx.Config.WikiOutputDir, productName)
+	if cmd.IsSet("lang") {
+		wikiDir = corewiki.LanguageDir(wikiDir, language)
+	}
+	return wikiDir
+}
+
+// gitSourceURL はプロダクトに属するGitソースのリポジトリURLを返す
+func gitSourceURL(ctx context.Context, appCtx *AppContext, productID uuid.UUID, sourceName string) (string, error) {
+	sourceOpt, err := appCtx.Container.IngestionRepo.GetSourceByName(ctx, sourceName)
+	if err != nil {
+		return "", fmt.Errorf("ソース取得に失敗: %w", err)
+	}
+	if sourceOpt.IsAbsent() || sourceOpt.MustGet().ProductID != productID {
+		return "", fmt.Errorf("プロダクトにソースが見つかりません: %s", sourceName)
+	}
+	source := sourceOpt.MustGet()
+	if source.SourceType != coreingestion.SourceTypeGit {
+		return "", fmt.Errorf("Gitソースではありません: %s (%s)", sourceName, source.SourceType)
+	}
+	url, _ := source.Metadata["url"].(string)
+	if url == "" {
+		return "", fmt.Errorf("ソースのリポジトリURLが記録されていません: %s", sourceName)
+	}
+	return url, nil
+}
+
+func printPublishedPages(label string, paths []string) {
+	if len(paths) == 0 {
+		return
+	}
+	fmt.Printf("%s:\n", label)
+	for _, p := range paths {
+		fmt.Printf("  %s\n", p)
+	}
+}
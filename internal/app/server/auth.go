@@ -0,0 +1,174 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/jinford/dev-rag/internal/platform/config"
+)
+
+// Scope はAPIキーに付与する権限を表す
+type Scope string
+
+const (
+	// ScopeRead は検索・質問応答・参照系APIの実行を許可する
+	ScopeRead Scope = "read"
+	// ScopeIndex はインデックス更新など書き込み系ジョブの実行を許可する
+	ScopeIndex Scope = "index"
+	// ScopeAdmin は全スコープと全プロダクトへのアクセスを許可する
+	ScopeAdmin Scope = "admin"
+)
+
+// Principal は認証済みの呼び出し元を表す
+type Principal struct {
+	Name     string   // キーの識別名
+	Scopes   []Scope  // 付与されたスコープ
+	Products []string // アクセス可能なプロダクト名（空なら全プロダクト）
+}
+
+// HasScope は指定スコープを持つかどうかを返す
+// admin スコープは全スコープを包含する
+func (p *Principal) HasScope(scope Scope) bool {
+	return slices.Contains(p.Scopes, ScopeAdmin) || slices.Contains(p.Scopes, scope)
+}
+
+// CanAccessProduct は指定プロダクトへのアクセスが許可されているかを返す
+func (p *Principal) CanAccessProduct(productName string) bool {
+	if len(p.Products) == 0 || slices.Contains(p.Scopes, ScopeAdmin) {
+		return true
+	}
+	return slices.Contains(p.Products, productName)
+}
+
+// Authenticator はリクエストの呼び出し元を認証する
+type Authenticator interface {
+	Authenticate(r *http.Request) (*Principal, error)
+}
+
+// apiKeyEntry はトークンのハッシュと対応する Principal の組
+type apiKeyEntry struct {
+	tokenHash [sha256.Size]byte
+	principal *Principal
+}
+
+// APIKeyAuthenticator は Bearer トークンとして渡されたAPIキーで認証する
+type APIKeyAuthenticator struct {
+	keys []apiKeyEntry
+}
+
+// NewAPIKeyAuthenticator は設定からAPIキー認証器を作成する
+// legacyToken（DEVRAG_API_TOKEN）が指定された場合は admin スコープのキーとして扱う
+func NewAPIKeyAuthenticator(legacyToken string, keys []config.APIKeyConfig) (*APIKeyAuthenticator, error) {
+	a := &APIKeyAuthenticator{}
+
+	if legacyToken != "" {
+		a.add(legacyToken, &Principal{Name: "default", Scopes: []Scope{ScopeAdmin}})
+	}
+
+	for _, key := range keys {
+		scopes := make([]Scope, 0, len(key.Scopes))
+		for _, s := range key.Scopes {
+			scope := Scope(strings.ToLower(s))
+			switch scope {
+			case ScopeRead, ScopeIndex, ScopeAdmin:
+				scopes = append(scopes, scope)
+			default:
+				return nil, fmt.Errorf("unknown scope %q for api key %s", s, key.Name)
+			}
+		}
+		a.add(key.Token, &Principal{
+			Name:     key.Name,
+			Scopes:   scopes,
+			Products: key.Products,
+		})
+	}
+
+	if len(a.keys) == 0 {
+		return nil, fmt.Errorf("no api keys configured: set DEVRAG_API_TOKEN or DEVRAG_API_KEYS")
+	}
+
+	return a, nil
+}
+
+func (a *APIKeyAuthenticator) add(token string, principal *Principal) {
+	a.keys = append(a.keys, apiKeyEntry{
+		tokenHash: sha256.Sum256([]byte(token)),
+		principal: principal,
+	})
+}
+
+// Authenticate は Authorization ヘッダーのトークンに対応する Principal を返す
+func (a *APIKeyAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	header := r.Header.Get("Authorization")
+	token, ok := strings.CutPrefix(header, "Bearer ")
+	if !ok || token == "" {
+		return nil, fmt.Errorf("missing bearer token")
+	}
+
+	// 長さに依存しない比較のためハッシュ同士を定数時間で比較する
+	hash := sha256.Sum256([]byte(token))
+	for _, entry := range a.keys {
+		if subtle.ConstantTimeCompare(hash[:], entry.tokenHash[:]) == 1 {
+			return entry.principal, nil
+		}
+	}
+
+	return nil, fmt.Errorf("invalid api key")
+}
+
+type principalContextKey struct{}
+
+// withPrincipal はコンテキストに Principal を格納する
+func withPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalContextKey{}, p)
+}
+
+// PrincipalFromContext はコンテキストから Principal を取り出す
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalContextKey{}).(*Principal)
+	return p, ok
+}
+
+// requireScope は認証と指定スコープの検査を行うミドルウェア
+func (s *Server) requireScope(scope Scope, next http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, err := s.auth.Authenticate(r)
+		if err != nil {
+			s.logger.Warn("authentication failed", "path", r.URL.Path, "error", err)
+			writeError(w, http.StatusUnauthorized, ErrCodeUnauthorized, "認証に失敗しました")
+			return
+		}
+
+		if !principal.HasScope(scope) {
+			s.logger.Warn("insufficient scope",
+				"key", principal.Name,
+				"path", r.URL.Path,
+				"required", string(scope),
+			)
+			writeError(w, http.StatusForbidden, ErrCodeForbidden, fmt.Sprintf("スコープ %s が必要です", scope))
+			return
+		}
+
+		next(w, r.WithContext(withPrincipal(r.Context(), principal)))
+	})
+}
+
+// authorizeProduct はプロダクトへのアクセス権を検査し、拒否した場合はレスポンスを書き込んで false を返す
+func (s *Server) authorizeProduct(w http.ResponseWriter, r *http.Request, productName string) bool {
+	principal, ok := PrincipalFromContext(r.Context())
+	if !ok || !principal.CanAccessProduct(productName) {
+		name := ""
+		if principal != nil {
+			name = principal.Name
+		}
+		s.logger.Warn("product access denied", "key", name, "product", productName)
+		writeError(w, http.StatusForbidden, ErrCodeForbidden, fmt.Sprintf("プロダクト %s へのアクセス権がありません", productName))
+		return false
+	}
+	return true
+}
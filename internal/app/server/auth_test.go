@@ -0,0 +1,109 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jinford/dev-rag/internal/platform/config"
+)
+
+func newTestServer(t *testing.T) *Server {
+	t.Helper()
+
+	auth, err := NewAPIKeyAuthenticator("admin-token", []config.APIKeyConfig{
+		{Name: "viewer", Token: "viewer-token", Scopes: []string{"read"}, Products: []string{"ecommerce"}},
+		{Name: "ci", Token: "ci-token", Scopes: []string{"index"}},
+	})
+	require.NoError(t, err)
+
+	return NewServer(nil, auth, WithServerLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+}
+
+func TestNewAPIKeyAuthenticator_Validation(t *testing.T) {
+	_, err := NewAPIKeyAuthenticator("", nil)
+	assert.Error(t, err, "at least one key must be configured")
+
+	_, err = NewAPIKeyAuthenticator("", []config.APIKeyConfig{
+		{Name: "bad", Token: "t", Scopes: []string{"write"}},
+	})
+	assert.Error(t, err, "unknown scope must be rejected")
+}
+
+func TestPrincipal_Permissions(t *testing.T) {
+	viewer := &Principal{Name: "viewer", Scopes: []Scope{ScopeRead}, Products: []string{"ecommerce"}}
+	assert.True(t, viewer.HasScope(ScopeRead))
+	assert.False(t, viewer.HasScope(ScopeIndex))
+	assert.True(t, viewer.CanAccessProduct("ecommerce"))
+	assert.False(t, viewer.CanAccessProduct("payments"))
+
+	admin := &Principal{Name: "admin", Scopes: []Scope{ScopeAdmin}, Products: []string{"ecommerce"}}
+	assert.True(t, admin.HasScope(ScopeIndex))
+	assert.True(t, admin.CanAccessProduct("payments"))
+}
+
+func TestRequireScope(t *testing.T) {
+	s := newTestServer(t)
+	handler := s.Handler()
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		status int
+	}{
+		{name: "missing token", method: http.MethodGet, path: "/api/v1/auth/whoami", token: "", status: http.StatusUnauthorized},
+		{name: "invalid token", method: http.MethodGet, path: "/api/v1/auth/whoami", token: "nope", status: http.StatusUnauthorized},
+		{name: "read scope allowed", method: http.MethodGet, path: "/api/v1/auth/whoami", token: "viewer-token", status: http.StatusOK},
+		{name: "index scope required", method: http.MethodPost, path: "/api/v1/index/git", token: "viewer-token", status: http.StatusForbidden},
+		{name: "read scope required", method: http.MethodGet, path: "/api/v1/auth/whoami", token: "ci-token", status: http.StatusForbidden},
+		{name: "admin has all scopes", method: http.MethodGet, path: "/api/v1/auth/whoami", token: "admin-token", status: http.StatusOK},
+		{name: "metrics require admin scope", method: http.MethodGet, path: "/api/v1/metrics", token: "viewer-token", status: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.status, rec.Code)
+		})
+	}
+}
+
+func TestAuthorizeProduct(t *testing.T) {
+	s := newTestServer(t)
+	handler := s.Handler()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/ask", jsonBody(t, askRequest{Product: "payments", Query: "決済の流れは？"}))
+	req.Header.Set("Authorization", "Bearer viewer-token")
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	var body errorResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+	assert.Equal(t, ErrCodeForbidden, body.Code)
+}
+
+func jsonBody(t *testing.T, v any) io.Reader {
+	t.Helper()
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	return bytes.NewReader(data)
+}
@@ -0,0 +1,728 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+
+	coreask "github.com/jinford/dev-rag/internal/core/ask"
+	"github.com/jinford/dev-rag/internal/core/coverage"
+	coreingestion "github.com/jinford/dev-rag/internal/core/ingestion"
+	coresearch "github.com/jinford/dev-rag/internal/core/search"
+	"github.com/jinford/dev-rag/internal/core/usage"
+	"github.com/jinford/dev-rag/internal/platform/database"
+)
+
+// whoAmIResponse は認証情報の確認レスポンス
+type whoAmIResponse struct {
+	Name     string   `json:"name"`
+	Scopes   []Scope  `json:"scopes"`
+	Products []string `json:"products"`
+}
+
+// handleWhoAmI は呼び出し元のキー情報を返す
+func (s *Server) handleWhoAmI(w http.ResponseWriter, r *http.Request) {
+	principal, _ := PrincipalFromContext(r.Context())
+	writeJSON(w, http.StatusOK, whoAmIResponse{
+		Name:     principal.Name,
+		Scopes:   principal.Scopes,
+		Products: principal.Products,
+	})
+}
+
+// handleListProducts はアクセス可能なプロダクトの一覧を返す
+func (s *Server) handleListProducts(w http.ResponseWriter, r *http.Request) {
+	principal, _ := PrincipalFromContext(r.Context())
+
+	products, err := s.container.IngestionRepo.ListProductsWithStats(r.Context())
+	if err != nil {
+		s.logger.Error("failed to list products", "error", err)
+		writeError(w, http.StatusInternalServerError, ErrCodeInternal, "プロダクト一覧の取得に失敗しました")
+		return
+	}
+
+	visible := make([]*coreingestion.ProductWithStats, 0, len(products))
+	for _, p := range products {
+		if principal.CanAccessProduct(p.Name) {
+			visible = append(visible, p)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, visible)
+}
+
+// askRequest は質問応答APIのリクエストボディ
+type askRequest struct {
+	Product      string   `json:"product"`
+	Query        string   `json:"query"`
+	ChunkLimit   int      `json:"chunkLimit"`
+	SummaryLimit int      `json:"summaryLimit"`
+	Model        string   `json:"model,omitempty"`
+	Source       string   `json:"source,omitempty"`
+	Ref          string   `json:"ref,omitempty"`
+	Snapshot     string   `json:"snapshot,omitempty"`
+	Domains      []string `json:"domains,omitempty"`
+	Languages    []string `json:"languages,omitempty"`
+	Strategy     string   `json:"strategy,omitempty"` // 省略時はサーバの既定値（SEARCH_STRATEGY）
+
+	EmbeddingModel  string `json:"embeddingModel,omitempty"`  // チャンク検索に使用する追加のEmbeddingモデル（省略時は EMBEDDING_MODEL）
+	DependencyHops  *int   `json:"dependencyHops,omitempty"`  // 省略時はサーバの既定値（ASK_DEPENDENCY_HOPS）
+	VerifyCitations bool   `json:"verifyCitations,omitempty"` // 回答の引用が文を裏付けているかをLLMで検証する
+	NoCache         bool   `json:"noCache,omitempty"`         // キャッシュした回答を使わずに検索・回答を生成する
+}
+
+// askResponse は質問応答APIのレスポンス
+type askResponse struct {
+	Answer        string                    `json:"answer"`
+	Sources       []coreask.SourceReference `json:"sources"`
+	Refused       bool                      `json:"refused"`
+	RefusalReason string                    `json:"refusalReason,omitempty"`
+	Citations     []coreask.CitationCheck   `json:"citations,omitempty"`     // verifyCitations 指定時のみ
+	PromptVersion string                    `json:"promptVersion,omitempty"` // 回答の生成に使用したプロンプトテンプレートのバージョン
+	Cached        bool                      `json:"cached,omitempty"`        // キャッシュした回答を返したかどうか
+	Degraded      []coreask.DegradedStage   `json:"degraded,omitempty"`      // 所要時間の予算を超えたために省略・打ち切った処理
+	Truncated     bool                      `json:"truncated,omitempty"`     // 回答の生成を予算で打ち切り、途中までの回答を返したかどうか
+	Product       string                    `json:"product"`                 // 回答したプロダクト（product を省略した場合は振り分け先）
+}
+
+// ambiguousProductResponse は product を省略した質問の振り分け先を特定できない場合のエラーレスポンス
+type ambiguousProductResponse struct {
+	errorResponse
+	Candidates []*coresearch.ProductCandidate `json:"candidates"` // 関連度の高い順の候補
+}
+
+// handleAsk はプロダクトに関する質問にRAGで回答する
+func (s *Server) handleAsk(w http.ResponseWriter, r *http.Request) {
+	var req askRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, fmt.Sprintf("リクエストが不正です: %v", err))
+		return
+	}
+	if strings.TrimSpace(req.Query) == "" {
+		writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, "query は必須です")
+		return
+	}
+	if strings.TrimSpace(req.Product) == "" {
+		if req.Ref != "" || req.Snapshot != "" {
+			writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, "ref・snapshot を指定する場合は product も指定してください")
+			return
+		}
+		var ok bool
+		if req.Product, ok = s.routeProduct(w, r, req.Query); !ok {
+			return
+		}
+	}
+	if !s.authorizeProduct(w, r, req.Product) {
+		return
+	}
+
+	product, ok := s.lookupProduct(w, r, req.Product)
+	if !ok {
+		return
+	}
+
+	params := coreask.AskParams{
+		ProductID:    mo.Some(product.ID),
+		Query:        req.Query,
+		ChunkLimit:   req.ChunkLimit,
+		SummaryLimit: req.SummaryLimit,
+		Model:        req.Model,
+		Domains:      req.Domains,
+
+		EmbeddingModel:  req.EmbeddingModel,
+		VerifyCitations: req.VerifyCitations,
+		NoCache:         req.NoCache,
+	}
+	for _, language := range req.Languages {
+		params.Languages = append(params.Languages, coreingestion.NormalizeLanguage(language))
+	}
+	for _, domain := range req.Domains {
+		if !coreingestion.IsValidDomain(domain) {
+			writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, fmt.Sprintf("不正なドメインです: %s", domain))
+			return
+		}
+	}
+	if req.Strategy != "" {
+		strategy, err := coresearch.ParseRetrievalStrategy(req.Strategy)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, fmt.Sprintf("不正な検索方式です: %s", req.Strategy))
+			return
+		}
+		params.Strategy = strategy
+	}
+	if !s.container.SearchService.SupportsEmbeddingModel(req.EmbeddingModel) {
+		writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, fmt.Sprintf("設定されていないEmbeddingモデルです: %s", req.EmbeddingModel))
+		return
+	}
+	if req.DependencyHops != nil {
+		if *req.DependencyHops < 0 {
+			writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, "dependencyHops には0以上の値を指定してください")
+			return
+		}
+		params.DependencyHops = mo.Some(*req.DependencyHops)
+	}
+	switch {
+	case req.Ref != "" && req.Snapshot != "":
+		writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, "ref と snapshot は同時に指定できません")
+		return
+	case req.Ref != "":
+		gitRef, err := coreingestion.ResolveRefSnapshot(r.Context(), s.container.IngestionRepo, product.ID, req.Source, req.Ref)
+		if err != nil {
+			s.writeResolveError(w, req, err)
+			return
+		}
+		params.SnapshotID = mo.Some(gitRef.SnapshotID)
+	case req.Snapshot != "":
+		snapshot, err := coreingestion.ResolveSnapshot(r.Context(), s.container.IngestionRepo, product.ID, req.Source, req.Snapshot)
+		if err != nil {
+			s.writeResolveError(w, req, err)
+			return
+		}
+		params.SnapshotID = mo.Some(snapshot.ID)
+	}
+
+	result, err := s.container.AskService.Ask(r.Context(), params)
+	if err != nil {
+		s.logger.Error("failed to answer question", "product", req.Product, "error", err)
+		writeError(w, http.StatusInternalServerError, ErrCodeInternal, "質問応答に失敗しました")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, askResponse{
+		Answer:        result.Answer,
+		Sources:       result.Sources,
+		Refused:       result.Refused,
+		RefusalReason: string(result.RefusalReason),
+		Citations:     result.Citations,
+		PromptVersion: result.PromptVersion,
+		Cached:        result.Cached,
+		Degraded:      result.Degraded,
+		Truncated:     result.Truncated,
+		Product:       product.Name,
+	})
+}
+
+// routeProduct は product を省略した質問を、呼び出し元がアクセスできるプロダクトのうち最も関連するものに振り分ける
+// 特定できない場合は候補を含むエラーレスポンスを書き込んで false を返す
+func (s *Server) routeProduct(w http.ResponseWriter, r *http.Request, query string) (string, bool) {
+	principal, _ := PrincipalFromContext(r.Context())
+	allowed := func(productName string) bool {
+		return principal != nil && principal.CanAccessProduct(productName)
+	}
+
+	route, err := s.container.AskService.RouteProduct(r.Context(), query, allowed)
+	if err != nil {
+		s.logger.Error("failed to route question", "error", err)
+		writeError(w, http.StatusInternalServerError, ErrCodeInternal, "プロダクトの振り分けに失敗しました")
+		return "", false
+	}
+	if route.Ambiguous() {
+		writeJSON(w, http.StatusConflict, ambiguousProductResponse{
+			errorResponse: errorResponse{
+				Error: "質問に関連するプロダクトを特定できません。product を指定してください",
+				Code:  ErrCodeAmbiguousProduct,
+			},
+			Candidates: route.Candidates,
+		})
+		return "", false
+	}
+	return route.Product.ProductName, true
+}
+
+// searchRequest はチャンク検索APIのリクエストボディ
+type searchRequest struct {
+	Product    string   `json:"product"`
+	Query      string   `json:"query"`
+	Limit      int      `json:"limit"`
+	PathPrefix string   `json:"pathPrefix,omitempty"`
+	Domains    []string `json:"domains,omitempty"`
+	Languages  []string `json:"languages,omitempty"`
+	Strategy   string   `json:"strategy,omitempty"` // 省略時はサーバの既定値（SEARCH_STRATEGY）
+
+	EmbeddingModel string `json:"embeddingModel,omitempty"` // チャンク検索に使用する追加のEmbeddingモデル（省略時は EMBEDDING_MODEL）
+}
+
+// searchResponse はチャンク検索APIのレスポンス
+type searchResponse struct {
+	Results []*coresearch.SearchResult `json:"results"`
+}
+
+// defaultSearchLimit は limit 省略時の検索結果の件数
+const defaultSearchLimit = 10
+
+// handleSearch はプロダクトの最新スナップショットからクエリに近いチャンクを返す
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	var req searchRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, fmt.Sprintf("リクエストが不正です: %v", err))
+		return
+	}
+	if strings.TrimSpace(req.Product) == "" || strings.TrimSpace(req.Query) == "" {
+		writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, "product と query は必須です")
+		return
+	}
+	if req.Limit < 0 {
+		writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, "limit には0以上の値を指定してください")
+		return
+	}
+	if !s.authorizeProduct(w, r, req.Product) {
+		return
+	}
+
+	params := coresearch.SearchParams{
+		Query:  req.Query,
+		Limit:  req.Limit,
+		Filter: &coresearch.SearchFilter{Domains: req.Domains, EmbeddingModel: req.EmbeddingModel},
+	}
+	if params.Limit == 0 {
+		params.Limit = defaultSearchLimit
+	}
+	if req.PathPrefix != "" {
+		params.Filter.PathPrefix = &req.PathPrefix
+	}
+	for _, language := range req.Languages {
+		params.Filter.Languages = append(params.Filter.Languages, coreingestion.NormalizeLanguage(language))
+	}
+	for _, domain := range req.Domains {
+		if !coreingestion.IsValidDomain(domain) {
+			writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, fmt.Sprintf("不正なドメインです: %s", domain))
+			return
+		}
+	}
+	if req.Strategy != "" {
+		strategy, err := coresearch.ParseRetrievalStrategy(req.Strategy)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, fmt.Sprintf("不正な検索方式です: %s", req.Strategy))
+			return
+		}
+		params.Strategy = strategy
+	}
+	if !s.container.SearchService.SupportsEmbeddingModel(req.EmbeddingModel) {
+		writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, fmt.Sprintf("設定されていないEmbeddingモデルです: %s", req.EmbeddingModel))
+		return
+	}
+
+	product, ok := s.lookupProduct(w, r, req.Product)
+	if !ok {
+		return
+	}
+	params.ProductID = mo.Some(product.ID)
+
+	results, err := s.container.SearchService.Search(r.Context(), params)
+	if err != nil {
+		s.logger.Error("failed to search", "product", req.Product, "error", err)
+		writeError(w, http.StatusInternalServerError, ErrCodeInternal, "検索に失敗しました")
+		return
+	}
+	if results == nil {
+		results = []*coresearch.SearchResult{}
+	}
+
+	writeJSON(w, http.StatusOK, searchResponse{Results: results})
+}
+
+// contextRequest はエディタのカーソル位置の文脈取得APIのリクエストボディ
+type contextRequest struct {
+	Product         string `json:"product"`
+	FilePath        string `json:"filePath"` // ソースのルートからの相対パス
+	Line            int    `json:"line"`     // カーソルの行（1始まり）
+	Question        string `json:"question,omitempty"`
+	Limit           int    `json:"limit"`
+	DependencyHops  *int   `json:"dependencyHops,omitempty"`
+	MaxDependencies int    `json:"maxDependencies"`
+}
+
+// contextResponse はエディタのカーソル位置の文脈取得APIのレスポンス
+type contextResponse struct {
+	Focus        *coresearch.SearchResult   `json:"focus"`
+	Related      []*coresearch.SearchResult `json:"related"`
+	Dependencies []*coresearch.SearchResult `json:"dependencies"`
+}
+
+const (
+	// defaultContextDependencyHops は dependencyHops 省略時にカーソル位置のチャンクから辿るホップ数
+	defaultContextDependencyHops = 1
+	// defaultContextMaxDependencies は maxDependencies 省略時の依存チャンクの件数
+	defaultContextMaxDependencies = 10
+)
+
+// handleContext はエディタのカーソル位置を含むチャンクと、質問に関連するチャンク・依存関係でつながるチャンクを返す
+// IDE拡張が補完や質問のプロンプトに添える文脈の取得に使う
+func (s *Server) handleContext(w http.ResponseWriter, r *http.Request) {
+	var req contextRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, fmt.Sprintf("リクエストが不正です: %v", err))
+		return
+	}
+	filePath := normalizeFilePath(req.FilePath)
+	if strings.TrimSpace(req.Product) == "" || filePath == "" {
+		writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, "product と filePath は必須です")
+		return
+	}
+	if req.Line <= 0 {
+		writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, "line には1以上の値（1始まりの行番号）を指定してください")
+		return
+	}
+	if req.Limit < 0 || req.MaxDependencies < 0 {
+		writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, "limit と maxDependencies には0以上の値を指定してください")
+		return
+	}
+	expansion := coresearch.DependencyExpansion{
+		MaxHops:   defaultContextDependencyHops,
+		MaxChunks: req.MaxDependencies,
+	}
+	if req.DependencyHops != nil {
+		if *req.DependencyHops < 0 {
+			writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, "dependencyHops には0以上の値を指定してください")
+			return
+		}
+		expansion.MaxHops = *req.DependencyHops
+	}
+	if expansion.MaxChunks == 0 {
+		expansion.MaxChunks = defaultContextMaxDependencies
+	}
+	if !s.authorizeProduct(w, r, req.Product) {
+		return
+	}
+
+	product, ok := s.lookupProduct(w, r, req.Product)
+	if !ok {
+		return
+	}
+
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultSearchLimit
+	}
+	result, err := s.container.SearchService.ContextAt(r.Context(), coresearch.LocationParams{
+		ProductID: product.ID,
+		FilePath:  filePath,
+		Line:      req.Line,
+		Question:  req.Question,
+		Limit:     limit,
+		Expansion: expansion,
+	})
+	if err != nil {
+		s.logger.Error("failed to resolve location context", "product", req.Product, "path", filePath, "line", req.Line, "error", err)
+		writeError(w, http.StatusInternalServerError, ErrCodeInternal, "文脈の取得に失敗しました")
+		return
+	}
+
+	resp := contextResponse{
+		Focus:        result.Focus,
+		Related:      result.Related,
+		Dependencies: result.Dependencies,
+	}
+	if resp.Related == nil {
+		resp.Related = []*coresearch.SearchResult{}
+	}
+	if resp.Dependencies == nil {
+		resp.Dependencies = []*coresearch.SearchResult{}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// normalizeFilePath はエディタから受け取ったパスをインデックスのファイルパスの形式（"/" 区切りの相対パス）に揃える
+func normalizeFilePath(p string) string {
+	p = strings.TrimSpace(strings.ReplaceAll(p, "\\", "/"))
+	if p == "" {
+		return ""
+	}
+	p = strings.TrimPrefix(path.Clean("/"+p), "/")
+	if p == "." {
+		return ""
+	}
+	return p
+}
+
+// writeResolveError は ref/snapshot の解決エラーをレスポンスに変換する
+func (s *Server) writeResolveError(w http.ResponseWriter, req askRequest, err error) {
+	switch {
+	case errors.Is(err, coreingestion.ErrSourceNotFound),
+		errors.Is(err, coreingestion.ErrRefNotFound),
+		errors.Is(err, coreingestion.ErrSnapshotNotFound):
+		writeError(w, http.StatusNotFound, ErrCodeSourceNotFound, err.Error())
+	case errors.Is(err, coreingestion.ErrAmbiguousSource):
+		writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, "ref/snapshot を指定する場合は source も指定してください")
+	case errors.Is(err, coreingestion.ErrAmbiguousVersion):
+		writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, err.Error())
+	default:
+		s.logger.Error("failed to resolve snapshot", "product", req.Product, "ref", req.Ref, "snapshot", req.Snapshot, "error", err)
+		writeError(w, http.StatusInternalServerError, ErrCodeInternal, "スナップショットの解決に失敗しました")
+	}
+}
+
+// indexGitRequest はGitソースインデックス更新APIのリクエストボディ
+type indexGitRequest struct {
+	URL          string `json:"url"`
+	Product      string `json:"product"`
+	Ref          string `json:"ref"`
+	SinceCommit  string `json:"sinceCommit"`
+	ForceInit    bool   `json:"forceInit"`
+	GenerateWiki bool   `json:"generateWiki"` // 未対応（true の場合は 400 を返す）
+}
+
+// jobAcceptedResponse は非同期ジョブ受付時のレスポンス
+type jobAcceptedResponse struct {
+	JobID  uuid.UUID `json:"jobID"`
+	Status JobStatus `json:"status"`
+}
+
+// handleIndexGit はGitソースのインデックス更新を非同期ジョブとして開始する
+func (s *Server) handleIndexGit(w http.ResponseWriter, r *http.Request) {
+	var req indexGitRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, fmt.Sprintf("リクエストが不正です: %v", err))
+		return
+	}
+	if strings.TrimSpace(req.URL) == "" || strings.TrimSpace(req.Product) == "" {
+		writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, "url と product は必須です")
+		return
+	}
+	// インデックス化後の Wiki 生成は未実装のため、受け付けて無視せずにエラーにする
+	if req.GenerateWiki {
+		writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, "generateWiki には対応していません（Wiki はインデックス化の完了後に dev-rag wiki generate で生成してください）")
+		return
+	}
+	if !s.authorizeProduct(w, r, req.Product) {
+		return
+	}
+
+	params := coreingestion.IndexParams{
+		Identifier:   req.URL,
+		ProductName:  req.Product,
+		ForceInit:    req.ForceInit,
+		SinceVersion: req.SinceCommit,
+		Options: map[string]any{
+			"ref": req.Ref,
+		},
+	}
+
+	// リクエスト終了後もジョブを継続させるためキャンセルを切り離す
+	jobCtx := context.WithoutCancel(r.Context())
+	job := s.jobs.Start(jobCtx, "index", req.Product, "source", req.URL, func(ctx context.Context) error {
+		tracker := s.container.NewUsageTracker()
+		ctx = usage.WithTracker(ctx, tracker)
+
+		result, err := s.container.IndexService.IndexSource(ctx, params)
+		if err != nil {
+			return err
+		}
+		if err := s.container.SummaryService.GenerateForSnapshot(ctx, result.SnapshotID); err != nil {
+			s.logger.Warn("要約生成に失敗しました（インデックス化は成功）", "error", err)
+		}
+		if err := s.container.SummaryService.GenerateForProduct(ctx, req.Product); err != nil {
+			s.logger.Warn("プロダクト要約の生成に失敗しました（インデックス化は成功）", "product", req.Product, "error", err)
+		}
+		if _, err := s.container.ArchitectureDrift.DetectForSnapshot(ctx, result.SnapshotID); err != nil {
+			s.logger.Warn("アーキテクチャのドリフトの検出に失敗しました（インデックス化は成功）", "snapshotID", result.SnapshotID, "error", err)
+		}
+		if _, err := s.container.ExtractGlossaryOnIndex(ctx, req.Product); err != nil {
+			s.logger.Warn("用語集の抽出に失敗しました（インデックス化は成功）", "product", req.Product, "error", err)
+		}
+		if err := coreingestion.RecordSnapshotUsage(ctx, s.container.IngestionRepo, result.SnapshotID); err != nil {
+			s.logger.Warn("使用量の記録に失敗しました", "snapshotID", result.SnapshotID, "error", err)
+		}
+		s.logger.Info("インデックス化の推定コスト",
+			"snapshotID", result.SnapshotID,
+			"costUSD", tracker.TotalCost(),
+			"budgetExceeded", tracker.Exceeded(),
+		)
+		return nil
+	})
+
+	writeJSON(w, http.StatusAccepted, jobAcceptedResponse{JobID: job.JobID, Status: job.Status})
+}
+
+// handleGetJob はジョブの状態を返す
+func (s *Server) handleGetJob(w http.ResponseWriter, r *http.Request) {
+	jobID, err := uuid.Parse(r.PathValue("jobID"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, "jobID が不正です")
+		return
+	}
+
+	job, ok := s.jobs.Get(jobID)
+	if !ok {
+		writeError(w, http.StatusNotFound, ErrCodeJobNotFound, "ジョブが見つかりません")
+		return
+	}
+	if !s.authorizeProduct(w, r, job.Product) {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, job)
+}
+
+// coverageTrendResponse はカバレッジの推移APIのレスポンス
+type coverageTrendResponse struct {
+	Product string             `json:"product"`
+	Series  []*coverage.Series `json:"series"`
+}
+
+// handleCoverageTrend はプロダクトのスナップショットごとのカバレッジの推移を返す
+// クエリパラメータ domain でドメイン、since（YYYY-MM-DD）で記録日時の下限を指定できる
+func (s *Server) handleCoverageTrend(w http.ResponseWriter, r *http.Request) {
+	productName := r.PathValue("product")
+	if !s.authorizeProduct(w, r, productName) {
+		return
+	}
+
+	query := r.URL.Query()
+	filter := coverage.TrendFilter{Domain: query.Get("domain")}
+	if filter.Domain != "" && filter.Domain != "unknown" && !coreingestion.IsValidDomain(filter.Domain) {
+		writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, fmt.Sprintf("不正なドメインです: %s", filter.Domain))
+		return
+	}
+	if since := query.Get("since"); since != "" {
+		t, err := time.Parse(time.DateOnly, since)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, "since は YYYY-MM-DD 形式で指定してください")
+			return
+		}
+		filter.Since = t
+	}
+
+	product, ok := s.lookupProduct(w, r, productName)
+	if !ok {
+		return
+	}
+
+	series, err := s.container.CoverageTrend.Trend(r.Context(), product.ID, filter)
+	if err != nil {
+		s.logger.Error("failed to get coverage trend", "product", productName, "error", err)
+		writeError(w, http.StatusInternalServerError, ErrCodeInternal, "カバレッジの推移の取得に失敗しました")
+		return
+	}
+	if series == nil {
+		series = []*coverage.Series{}
+	}
+
+	writeJSON(w, http.StatusOK, coverageTrendResponse{Product: product.Name, Series: series})
+}
+
+// defaultIndexRunLimit は limit 省略時の実行履歴の件数
+const defaultIndexRunLimit = 20
+
+// indexRunsResponse は実行履歴APIのレスポンス
+type indexRunsResponse struct {
+	Product string                    `json:"product"`
+	Runs    []*coreingestion.IndexRun `json:"runs"`
+}
+
+// handleListIndexRuns はプロダクトのインデックス化の実行履歴を開始日時の新しい順に返す
+// クエリパラメータ source でソース名、status で実行状態、limit で件数を指定できる
+func (s *Server) handleListIndexRuns(w http.ResponseWriter, r *http.Request) {
+	productName := r.PathValue("product")
+	if !s.authorizeProduct(w, r, productName) {
+		return
+	}
+
+	query := r.URL.Query()
+	filter := coreingestion.IndexRunFilter{
+		SourceName: query.Get("source"),
+		Limit:      defaultIndexRunLimit,
+	}
+	if status := query.Get("status"); status != "" {
+		parsed, err := coreingestion.ParseIndexRunStatus(status)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, err.Error())
+			return
+		}
+		filter.Status = parsed
+	}
+	if limit := query.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n <= 0 {
+			writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, "limit には1以上の値を指定してください")
+			return
+		}
+		filter.Limit = n
+	}
+
+	product, ok := s.lookupProduct(w, r, productName)
+	if !ok {
+		return
+	}
+	filter.ProductID = product.ID
+
+	runs, err := s.container.IndexRuns.ListIndexRuns(r.Context(), filter)
+	if err != nil {
+		s.logger.Error("failed to list index runs", "product", productName, "error", err)
+		writeError(w, http.StatusInternalServerError, ErrCodeInternal, "実行履歴の取得に失敗しました")
+		return
+	}
+	if runs == nil {
+		runs = []*coreingestion.IndexRun{}
+	}
+
+	writeJSON(w, http.StatusOK, indexRunsResponse{Product: product.Name, Runs: runs})
+}
+
+// handleGetIndexRun はインデックス化の実行の詳細を返す
+// 実行したプロダクトへのアクセス権がない場合は 403 を返す
+func (s *Server) handleGetIndexRun(w http.ResponseWriter, r *http.Request) {
+	runID, err := uuid.Parse(r.PathValue("runID"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, ErrCodeInvalidRequest, "runID が不正です")
+		return
+	}
+
+	runOpt, err := s.container.IndexRuns.GetIndexRun(r.Context(), runID)
+	if err != nil {
+		s.logger.Error("failed to get index run", "runID", runID, "error", err)
+		writeError(w, http.StatusInternalServerError, ErrCodeInternal, "実行履歴の取得に失敗しました")
+		return
+	}
+	run, ok := runOpt.Get()
+	if !ok {
+		writeError(w, http.StatusNotFound, ErrCodeRunNotFound, "実行履歴が見つかりません")
+		return
+	}
+	if !s.authorizeProduct(w, r, run.ProductName) {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, run)
+}
+
+// metricsResponse はメトリクスAPIのレスポンス
+type metricsResponse struct {
+	Pools     []database.PoolStats              `json:"pools"`
+	Guardrail map[coreask.GuardrailReason]int64 `json:"guardrail"` // 理由ごとのガードレールの作動回数
+}
+
+// handleMetrics はデータベースの接続プールの統計と、質問応答のガードレールの作動回数を返す
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	resp := metricsResponse{
+		Pools:     s.container.Database().PoolStats(),
+		Guardrail: map[coreask.GuardrailReason]int64{},
+	}
+	if guardrail := s.container.AskService.Guardrail(); guardrail != nil {
+		resp.Guardrail = guardrail.Stats().Snapshot()
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// lookupProduct はプロダクト名からプロダクトを取得し、見つからない場合はレスポンスを書き込んで false を返す
+func (s *Server) lookupProduct(w http.ResponseWriter, r *http.Request, name string) (*coreingestion.Product, bool) {
+	productOpt, err := s.container.IngestionRepo.GetProductByName(r.Context(), name)
+	if err != nil {
+		s.logger.Error("failed to get product", "product", name, "error", err)
+		writeError(w, http.StatusInternalServerError, ErrCodeInternal, "プロダクトの取得に失敗しました")
+		return nil, false
+	}
+	if productOpt.IsAbsent() {
+		writeError(w, http.StatusNotFound, ErrCodeProductNotFound, fmt.Sprintf("プロダクトが見つかりません: %s", name))
+		return nil, false
+	}
+	return productOpt.MustGet(), true
+}
@@ -0,0 +1,159 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleContext_Validation(t *testing.T) {
+	s := newTestServer(t)
+	handler := s.Handler()
+	hops := -1
+
+	tests := []struct {
+		name string
+		req  contextRequest
+	}{
+		{name: "missing file path", req: contextRequest{Product: "ecommerce", Line: 10}},
+		{name: "line is 1-based", req: contextRequest{Product: "ecommerce", FilePath: "main.go", Line: 0}},
+		{name: "negative hops", req: contextRequest{Product: "ecommerce", FilePath: "main.go", Line: 1, DependencyHops: &hops}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/context", jsonBody(t, tt.req))
+			req.Header.Set("Authorization", "Bearer viewer-token")
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		})
+	}
+}
+
+func TestHandleAsk_Validation(t *testing.T) {
+	s := newTestServer(t)
+	handler := s.Handler()
+
+	tests := []struct {
+		name string
+		req  askRequest
+	}{
+		{name: "missing query", req: askRequest{Product: "ecommerce"}},
+		{name: "ref without product", req: askRequest{Query: "決済のリトライ方針は？", Ref: "main"}},
+		{name: "snapshot without product", req: askRequest{Query: "決済のリトライ方針は？", Snapshot: "abc123"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/ask", jsonBody(t, tt.req))
+			req.Header.Set("Authorization", "Bearer viewer-token")
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		})
+	}
+}
+
+func TestHandleIndexGit_Validation(t *testing.T) {
+	s := newTestServer(t)
+	handler := s.Handler()
+
+	tests := []struct {
+		name string
+		req  indexGitRequest
+	}{
+		{name: "missing url", req: indexGitRequest{Product: "ecommerce"}},
+		{name: "generate wiki is not supported", req: indexGitRequest{URL: "git@example.com:shop/api.git", Product: "ecommerce", GenerateWiki: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/index/git", jsonBody(t, tt.req))
+			req.Header.Set("Authorization", "Bearer ci-token")
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		})
+	}
+}
+
+func TestHandleIndexRuns_Validation(t *testing.T) {
+	s := newTestServer(t)
+	handler := s.Handler()
+
+	tests := []struct {
+		name   string
+		path   string
+		status int
+	}{
+		{name: "unknown status", path: "/api/v1/products/ecommerce/runs?status=done", status: http.StatusBadRequest},
+		{name: "non-positive limit", path: "/api/v1/products/ecommerce/runs?limit=0", status: http.StatusBadRequest},
+		{name: "product not allowed", path: "/api/v1/products/payments/runs", status: http.StatusForbidden},
+		{name: "invalid run id", path: "/api/v1/runs/not-a-uuid", status: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set("Authorization", "Bearer viewer-token")
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.status, rec.Code)
+		})
+	}
+}
+
+func TestHandleGetJob_ProductRestriction(t *testing.T) {
+	s := newTestServer(t)
+	handler := s.Handler()
+	noop := func(context.Context) error { return nil }
+	allowed := s.jobs.Start(context.Background(), "index", "ecommerce", "source", "git@example.com:shop/api.git", noop)
+	denied := s.jobs.Start(context.Background(), "index", "payments", "source", "git@example.com:pay/api.git", noop)
+	require.NoError(t, s.jobs.Wait(context.Background()))
+
+	tests := []struct {
+		name   string
+		jobID  uuid.UUID
+		token  string
+		status int
+	}{
+		{name: "allowed product", jobID: allowed.JobID, token: "viewer-token", status: http.StatusOK},
+		{name: "product not allowed", jobID: denied.JobID, token: "viewer-token", status: http.StatusForbidden},
+		{name: "unrestricted key", jobID: denied.JobID, token: "admin-token", status: http.StatusOK},
+		{name: "unknown job", jobID: uuid.New(), token: "viewer-token", status: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/jobs/"+tt.jobID.String(), nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.status, rec.Code)
+		})
+	}
+}
+
+func TestNormalizeFilePath(t *testing.T) {
+	assert.Equal(t, "payment/retry.go", normalizeFilePath("./payment/retry.go"))
+	assert.Equal(t, "payment/retry.go", normalizeFilePath("/payment//retry.go"))
+	assert.Equal(t, "payment/retry.go", normalizeFilePath(`payment\retry.go`))
+	assert.Equal(t, "", normalizeFilePath("."))
+	assert.Equal(t, "", normalizeFilePath("  "))
+}
@@ -0,0 +1,126 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JobStatus はジョブの状態を表す
+type JobStatus string
+
+const (
+	JobStatusRunning   JobStatus = "running"
+	JobStatusCompleted JobStatus = "completed"
+	JobStatusFailed    JobStatus = "failed"
+)
+
+// jobRetention はジョブ情報を保持する期間
+const jobRetention = 24 * time.Hour
+
+// Job は非同期ジョブの状態を表す
+type Job struct {
+	JobID      uuid.UUID  `json:"jobID"`
+	Product    string     `json:"product"` // ジョブの対象のプロダクト（プロダクトを限定した API キーの参照の判定に使用する）
+	TargetType string     `json:"targetType"`
+	TargetName string     `json:"targetName"`
+	JobType    string     `json:"jobType"`
+	Status     JobStatus  `json:"status"`
+	StartedAt  time.Time  `json:"startedAt"`
+	EndedAt    *time.Time `json:"endedAt,omitempty"`
+	Error      string     `json:"error"`
+}
+
+// JobManager はメモリ内で非同期ジョブを管理する
+type JobManager struct {
+	jobs sync.Map // uuid.UUID -> *Job
+	mu   sync.Mutex
+	wg   sync.WaitGroup
+}
+
+// NewJobManager は新しい JobManager を作成する
+func NewJobManager() *JobManager {
+	return &JobManager{}
+}
+
+// Start はジョブを登録し、バックグラウンドで fn を実行する
+func (m *JobManager) Start(ctx context.Context, jobType, product, targetType, targetName string, fn func(ctx context.Context) error) *Job {
+	m.cleanup()
+
+	job := &Job{
+		JobID:      uuid.New(),
+		Product:    product,
+		TargetType: targetType,
+		TargetName: targetName,
+		JobType:    jobType,
+		Status:     JobStatusRunning,
+		StartedAt:  time.Now(),
+	}
+	m.jobs.Store(job.JobID, job)
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		err := fn(ctx)
+
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		now := time.Now()
+		job.EndedAt = &now
+		if err != nil {
+			job.Status = JobStatusFailed
+			job.Error = err.Error()
+		} else {
+			job.Status = JobStatusCompleted
+		}
+	}()
+
+	return m.snapshot(job)
+}
+
+// Get はジョブの現在の状態を返す
+func (m *JobManager) Get(id uuid.UUID) (*Job, bool) {
+	v, ok := m.jobs.Load(id)
+	if !ok {
+		return nil, false
+	}
+	return m.snapshot(v.(*Job)), true
+}
+
+// Wait は実行中のジョブの完了を待つ
+func (m *JobManager) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		m.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// snapshot はジョブの複製を返す
+func (m *JobManager) snapshot(job *Job) *Job {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	copied := *job
+	return &copied
+}
+
+// cleanup は保持期間を過ぎた完了済みジョブを削除する
+func (m *JobManager) cleanup() {
+	threshold := time.Now().Add(-jobRetention)
+	m.jobs.Range(func(key, value any) bool {
+		job := m.snapshot(value.(*Job))
+		if job.EndedAt != nil && job.EndedAt.Before(threshold) {
+			m.jobs.Delete(key)
+		}
+		return true
+	})
+}
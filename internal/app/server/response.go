@@ -0,0 +1,45 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// エラーコード（docs/api-interface.md 4.9 準拠）
+const (
+	ErrCodeProductNotFound = "PRODUCT_NOT_FOUND"
+	ErrCodeSourceNotFound  = "SOURCE_NOT_FOUND"
+	ErrCodeJobNotFound     = "JOB_NOT_FOUND"
+	ErrCodeRunNotFound     = "RUN_NOT_FOUND"
+	ErrCodeInvalidRequest  = "INVALID_REQUEST"
+	ErrCodeUnauthorized    = "UNAUTHORIZED"
+	ErrCodeForbidden       = "FORBIDDEN"
+	ErrCodeInternal        = "INTERNAL_ERROR"
+
+	ErrCodeAmbiguousProduct = "AMBIGUOUS_PRODUCT" // product を省略した質問の振り分け先を特定できない
+)
+
+// errorResponse はエラーレスポンスの形式
+type errorResponse struct {
+	Error string `json:"error"`
+	Code  string `json:"code"`
+}
+
+// writeJSON はJSONレスポンスを書き込む
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// writeError はエラーレスポンスを書き込む
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, errorResponse{Error: message, Code: code})
+}
+
+// decodeJSON はリクエストボディをJSONとしてデコードする
+func decodeJSON(r *http.Request, v any) error {
+	dec := json.NewDecoder(http.MaxBytesReader(nil, r.Body, 1<<20))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
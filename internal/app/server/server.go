@@ -0,0 +1,112 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/jinford/dev-rag/internal/platform/container"
+)
+
+// shutdownTimeout はシャットダウン時に実行中のジョブを待つ最大時間
+const shutdownTimeout = 30 * time.Second
+
+// Server は dev-rag の REST API を提供するHTTPサーバ
+type Server struct {
+	container *container.ServiceContainer
+	auth      Authenticator
+	jobs      *JobManager
+	logger    *slog.Logger
+}
+
+type ServerOption func(*Server)
+
+// WithServerLogger は Server にロガーを設定する
+func WithServerLogger(logger *slog.Logger) ServerOption {
+	return func(s *Server) {
+		s.logger = logger
+	}
+}
+
+// NewServer は新しい Server を作成する
+func NewServer(cont *container.ServiceContainer, auth Authenticator, opts ...ServerOption) *Server {
+	s := &Server{
+		container: cont,
+		auth:      auth,
+		jobs:      NewJobManager(),
+		logger:    slog.Default(),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if s.logger == nil {
+		s.logger = slog.Default()
+	}
+
+	return s
+}
+
+// Handler はルーティング済みの http.Handler を返す
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.Handle("GET /api/v1/auth/whoami", s.requireScope(ScopeRead, s.handleWhoAmI))
+	mux.Handle("GET /api/v1/products", s.requireScope(ScopeRead, s.handleListProducts))
+	mux.Handle("POST /api/v1/ask", s.requireScope(ScopeRead, s.handleAsk))
+	mux.Handle("POST /api/v1/search", s.requireScope(ScopeRead, s.handleSearch))
+	mux.Handle("POST /api/v1/context", s.requireScope(ScopeRead, s.handleContext))
+	mux.Handle("POST /api/v1/index/git", s.requireScope(ScopeIndex, s.handleIndexGit))
+	mux.Handle("GET /api/v1/jobs/{jobID}", s.requireScope(ScopeRead, s.handleGetJob))
+	mux.Handle("GET /api/v1/products/{product}/coverage/trend", s.requireScope(ScopeRead, s.handleCoverageTrend))
+	mux.Handle("GET /api/v1/products/{product}/runs", s.requireScope(ScopeRead, s.handleListIndexRuns))
+	mux.Handle("GET /api/v1/runs/{runID}", s.requireScope(ScopeRead, s.handleGetIndexRun))
+	mux.Handle("GET /api/v1/metrics", s.requireScope(ScopeAdmin, s.handleMetrics))
+
+	return mux
+}
+
+// ListenAndServe はサーバを起動し、ctx がキャンセルされるまで待機する
+// 終了時は新規リクエストの受付を停止し、実行中のジョブの完了を最大30秒待つ
+func (s *Server) ListenAndServe(ctx context.Context, addr string) error {
+	httpServer := &http.Server{
+		Addr:              addr,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		s.logger.Info("HTTPサーバを起動しました", "addr", addr)
+		if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+		}
+		close(errCh)
+	}()
+
+	select {
+	case err := <-errCh:
+		if err != nil {
+			return fmt.Errorf("failed to start http server: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+	}
+
+	s.logger.Info("HTTPサーバを停止します")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to shutdown http server: %w", err)
+	}
+	if err := s.jobs.Wait(shutdownCtx); err != nil {
+		s.logger.Warn("実行中のジョブの完了を待たずに終了します", "error", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,275 @@
+// Package slackbot は Slack の Socket Mode で質問に回答するボット
+//
+// チャンネルごとに対応付けたプロダクトについて /ask スラッシュコマンドの質問に回答し、
+// 回答のメッセージに付けられた 👍/👎 のリアクションを評価として記録する。
+package slackbot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"github.com/samber/mo"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+	"github.com/slack-go/slack/socketmode"
+
+	coreask "github.com/jinford/dev-rag/internal/core/ask"
+	"github.com/jinford/dev-rag/internal/platform/config"
+	"github.com/jinford/dev-rag/internal/platform/container"
+)
+
+// AskCommand は質問を受け付けるスラッシュコマンド
+const AskCommand = "/ask"
+
+// Bot は Socket Mode で接続する Slack ボット
+type Bot struct {
+	container  *container.ServiceContainer
+	api        *slack.Client
+	socket     *socketmode.Client
+	channels   map[string]string // チャンネルIDからプロダクト名への対応
+	defaultRef string            // Gitソースに ref の指定がない場合のリンク先のブランチ
+	logger     *slog.Logger
+
+	wg sync.WaitGroup // 回答中の質問
+}
+
+type BotOption func(*Bot)
+
+// WithBotLogger は Bot にロガーを設定する
+func WithBotLogger(logger *slog.Logger) BotOption {
+	return func(b *Bot) {
+		b.logger = logger
+	}
+}
+
+// WithDefaultRef は参照ソースのリンク先のブランチ（Gitソースに ref の指定がない場合）を設定する
+func WithDefaultRef(ref string) BotOption {
+	return func(b *Bot) {
+		b.defaultRef = ref
+	}
+}
+
+// NewBot は新しい Bot を作成する
+func NewBot(cont *container.ServiceContainer, cfg config.SlackConfig, opts ...BotOption) (*Bot, error) {
+	if !strings.HasPrefix(cfg.BotToken, "xoxb-") {
+		return nil, errors.New("SLACK_BOT_TOKEN must be a bot token (xoxb-...)")
+	}
+	if !strings.HasPrefix(cfg.AppToken, "xapp-") {
+		return nil, errors.New("SLACK_APP_TOKEN must be an app-level token (xapp-...)")
+	}
+	if len(cfg.ChannelProducts) == 0 {
+		return nil, errors.New("SLACK_CHANNEL_PRODUCTS must map at least one channel to a product")
+	}
+
+	api := slack.New(cfg.BotToken, slack.OptionAppLevelToken(cfg.AppToken))
+	b := &Bot{
+		container:  cont,
+		api:        api,
+		socket:     socketmode.New(api),
+		channels:   cfg.ChannelProducts,
+		defaultRef: "main",
+		logger:     slog.Default(),
+	}
+
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	if b.logger == nil {
+		b.logger = slog.Default()
+	}
+
+	return b, nil
+}
+
+// Run は Slack に接続し、ctx がキャンセルされるまでイベントを処理する
+// 終了時は回答中の質問の処理が終わるのを待つ（回答の生成は ctx のキャンセルで中断する）
+func (b *Bot) Run(ctx context.Context) error {
+	dispatchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go b.dispatch(dispatchCtx)
+	err := b.socket.RunContext(ctx)
+	cancel()
+	b.wg.Wait()
+
+	if ctx.Err() != nil && (err == nil || errors.Is(err, context.Canceled)) {
+		return nil
+	}
+	return fmt.Errorf("failed to run socket mode client: %w", err)
+}
+
+// dispatch は Socket Mode のイベントを種類ごとに処理する
+func (b *Bot) dispatch(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case evt, ok := <-b.socket.Events:
+			if !ok {
+				return
+			}
+			switch evt.Type {
+			case socketmode.EventTypeConnected:
+				b.logger.Info("Slackに接続しました", "channels", len(b.channels))
+			case socketmode.EventTypeConnectionError:
+				b.logger.Warn("Slackへの接続に失敗しました。再接続します", "data", evt.Data)
+			case socketmode.EventTypeSlashCommand:
+				b.handleSlashCommand(ctx, evt)
+			case socketmode.EventTypeEventsAPI:
+				b.handleEventsAPI(ctx, evt)
+			}
+		}
+	}
+}
+
+// handleSlashCommand は /ask の質問を受け付け、回答を非同期に投稿する
+// Slack は3秒以内の応答を求めるため、受付の通知だけを先に返す
+func (b *Bot) handleSlashCommand(ctx context.Context, evt socketmode.Event) {
+	cmd, ok := evt.Data.(slack.SlashCommand)
+	if !ok || evt.Request == nil {
+		return
+	}
+
+	reply := func(text string) {
+		b.socket.Ack(*evt.Request, map[string]any{"response_type": "ephemeral", "text": text})
+	}
+
+	if cmd.Command != AskCommand {
+		reply(fmt.Sprintf("未対応のコマンドです: %s", cmd.Command))
+		return
+	}
+	question := strings.TrimSpace(cmd.Text)
+	if question == "" {
+		reply(fmt.Sprintf("質問文を指定してください（例: %s 決済のリトライ方針は？）", AskCommand))
+		return
+	}
+	productName, ok := b.channels[cmd.ChannelID]
+	if !ok {
+		reply("このチャンネルはプロダクトに対応付けられていません（SLACK_CHANNEL_PRODUCTS で設定してください）")
+		return
+	}
+
+	reply(fmt.Sprintf("%s について回答を作成しています…", productName))
+
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		if err := b.answer(ctx, cmd, productName, question); err != nil {
+			b.logger.Error("質問への回答に失敗しました", "channel", cmd.ChannelID, "product", productName, "error", err)
+			b.postEphemeral(ctx, cmd.ChannelID, cmd.UserID, fmt.Sprintf("回答に失敗しました: %v", err))
+		}
+	}()
+}
+
+// answer はプロダクトについての質問に回答し、回答のメッセージを評価の対象として記録する
+func (b *Bot) answer(ctx context.Context, cmd slack.SlashCommand, productName, question string) error {
+	productOpt, err := b.container.IngestionRepo.GetProductByName(ctx, productName)
+	if err != nil {
+		return fmt.Errorf("プロダクト取得に失敗: %w", err)
+	}
+	if productOpt.IsAbsent() {
+		return fmt.Errorf("プロダクトが見つかりません: %s", productName)
+	}
+	product := productOpt.MustGet()
+
+	result, err := b.container.AskService.Ask(ctx, coreask.AskParams{
+		ProductID:    mo.Some(product.ID),
+		Query:        question,
+		ChunkLimit:   10,
+		SummaryLimit: 5,
+	})
+	if err != nil {
+		return fmt.Errorf("質問応答に失敗: %w", err)
+	}
+
+	// リンクは補助的な情報のため、ソースの取得に失敗した場合はリンクなしで回答する
+	var linker *sourceLinker
+	sources, err := b.container.IngestionRepo.ListSourcesByProductID(ctx, product.ID)
+	if err != nil {
+		b.logger.Warn("failed to list sources for citation links", "product", productName, "error", err)
+	} else {
+		linker = newSourceLinker(sources, b.defaultRef)
+	}
+
+	_, ts, err := b.api.PostMessageContext(ctx, cmd.ChannelID,
+		slack.MsgOptionText(formatAnswer(cmd.UserID, question, result, linker), false),
+		slack.MsgOptionDisableLinkUnfurl(),
+	)
+	if err != nil {
+		return fmt.Errorf("回答の投稿に失敗（ボットをチャンネルに追加してください）: %w", err)
+	}
+
+	record := coreask.NewRecordedAnswer(product.ID, coreask.AnswerOriginSlack, cmd.ChannelID, ts, cmd.UserID, question, result)
+	if _, err := b.container.AskFeedback.RecordAnswer(ctx, record); err != nil {
+		// 回答は投稿済みのため、記録できなかった場合は評価を受け付けないだけとする
+		b.logger.Warn("failed to record answer", "channel", cmd.ChannelID, "ts", ts, "error", err)
+	}
+
+	b.logger.Info("質問に回答しました",
+		"channel", cmd.ChannelID,
+		"product", productName,
+		"sources", len(result.Sources),
+		"refused", result.Refused,
+	)
+	return nil
+}
+
+// handleEventsAPI は回答のメッセージへのリアクションの追加・削除を評価として記録する
+func (b *Bot) handleEventsAPI(ctx context.Context, evt socketmode.Event) {
+	eventsAPIEvent, ok := evt.Data.(slackevents.EventsAPIEvent)
+	if !ok {
+		return
+	}
+	if evt.Request != nil {
+		b.socket.Ack(*evt.Request)
+	}
+
+	switch ev := eventsAPIEvent.InnerEvent.Data.(type) {
+	case *slackevents.ReactionAddedEvent:
+		b.handleReaction(ctx, ev.User, ev.Reaction, ev.Item, true)
+	case *slackevents.ReactionRemovedEvent:
+		b.handleReaction(ctx, ev.User, ev.Reaction, ev.Item, false)
+	}
+}
+
+// handleReaction は 👍/👎 のリアクションをユーザーの評価として保存・削除する
+// ボットが回答したメッセージ以外へのリアクションは無視する
+func (b *Bot) handleReaction(ctx context.Context, userID, reaction string, item slackevents.Item, added bool) {
+	rating, ok := ratingFromReaction(reaction)
+	if !ok || item.Type != "message" {
+		return
+	}
+
+	answerOpt, err := b.container.AskFeedback.GetAnswerByMessage(ctx, coreask.AnswerOriginSlack, item.Channel, item.Timestamp)
+	if err != nil {
+		b.logger.Warn("failed to look up answer for reaction", "channel", item.Channel, "ts", item.Timestamp, "error", err)
+		return
+	}
+	answer, ok := answerOpt.Get()
+	if !ok {
+		return
+	}
+
+	if added {
+		err = b.container.AskFeedback.SetRating(ctx, answer.ID, userID, rating)
+	} else {
+		err = b.container.AskFeedback.ClearRating(ctx, answer.ID, userID, rating)
+	}
+	if err != nil {
+		b.logger.Warn("failed to record feedback", "answerID", answer.ID, "error", err)
+		return
+	}
+	b.logger.Info("回答の評価を記録しました", "answerID", answer.ID, "rating", rating, "added", added)
+}
+
+// postEphemeral は質問したユーザーにだけ見えるメッセージを投稿する
+func (b *Bot) postEphemeral(ctx context.Context, channelID, userID, text string) {
+	if _, err := b.api.PostEphemeralContext(ctx, channelID, userID, slack.MsgOptionText(text, false)); err != nil {
+		b.logger.Warn("failed to post ephemeral message", "channel", channelID, "error", err)
+	}
+}
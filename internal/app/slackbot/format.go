@@ -0,0 +1,200 @@
+package slackbot
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	coreask "github.com/jinford/dev-rag/internal/core/ask"
+	"github.com/jinford/dev-rag/internal/core/ingestion"
+)
+
+// maxListedSources は回答のメッセージに列挙する参照ソースの上限
+const maxListedSources = 8
+
+// ratingFromReaction はリアクション名（+1、thumbsup、-1、thumbsdown、肌の色の指定付きを含む）を評価に変換する
+func ratingFromReaction(reaction string) (coreask.Rating, bool) {
+	name, _, _ := strings.Cut(reaction, "::")
+	switch name {
+	case "+1", "thumbsup":
+		return coreask.RatingUp, true
+	case "-1", "thumbsdown":
+		return coreask.RatingDown, true
+	}
+	return 0, false
+}
+
+// sourceLinker は参照ソースのファイルパスからリポジトリのWeb上のURLを組み立てる
+type sourceLinker struct {
+	webURL string // リポジトリのURL（例: https://github.com/org/repo）
+	ref    string
+	gitlab bool
+}
+
+// newSourceLinker はプロダクトのソースからリンクの組み立て方を決める
+// 参照ソースがどのソースのファイルかを区別できないため、Gitソースが1つだけの場合のみリンクを付ける
+func newSourceLinker(sources []*ingestion.Source, defaultRef string) *sourceLinker {
+	var gitSources []*ingestion.Source
+	for _, source := range sources {
+		if source.SourceType == ingestion.SourceTypeGit {
+			gitSources = append(gitSources, source)
+		}
+	}
+	if len(gitSources) != 1 {
+		return nil
+	}
+
+	source := gitSources[0]
+	rawURL, _ := source.Metadata["url"].(string)
+	webURL, ok := repositoryWebURL(rawURL)
+	if !ok {
+		return nil
+	}
+	ref := defaultRef
+	if r, ok := source.Metadata["default_ref"].(string); ok && r != "" {
+		ref = r
+	}
+	return &sourceLinker{
+		webURL: webURL,
+		ref:    ref,
+		gitlab: strings.Contains(webURL, "gitlab"),
+	}
+}
+
+// link はファイルの行範囲を指すURLを返す
+func (l *sourceLinker) link(filePath string, startLine, endLine int) string {
+	segments := strings.Split(filePath, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+
+	blob := "/blob/"
+	if l.gitlab {
+		blob = "/-/blob/"
+	}
+	u := l.webURL + blob + url.PathEscape(l.ref) + "/" + strings.Join(segments, "/")
+	if startLine <= 0 {
+		return u
+	}
+	if endLine <= startLine {
+		return fmt.Sprintf("%s#L%d", u, startLine)
+	}
+	if l.gitlab {
+		return fmt.Sprintf("%s#L%d-%d", u, startLine, endLine)
+	}
+	return fmt.Sprintf("%s#L%d-L%d", u, startLine, endLine)
+}
+
+// repositoryWebURL はリポジトリのURL（https://host/owner/repo.git、git@host:owner/repo.git）をWeb上のURLに変換する
+func repositoryWebURL(raw string) (string, bool) {
+	s := strings.TrimSpace(raw)
+	scheme := "https"
+	switch {
+	case strings.HasPrefix(s, "git@"):
+		host, path, ok := strings.Cut(strings.TrimPrefix(s, "git@"), ":")
+		if !ok {
+			return "", false
+		}
+		s = host + "/" + path
+	case strings.Contains(s, "://"):
+		u, err := url.Parse(s)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "ssh") {
+			return "", false
+		}
+		if u.Scheme == "http" {
+			scheme = "http"
+		}
+		s = u.Hostname() + u.Path
+	default:
+		// ローカルディレクトリのパスなどはWeb上のURLを持たない
+		return "", false
+	}
+
+	s = strings.TrimSuffix(strings.TrimSuffix(s, "/"), ".git")
+	host, path, ok := strings.Cut(s, "/")
+	if !ok || host == "" || !strings.Contains(path, "/") {
+		return "", false
+	}
+	return scheme + "://" + host + "/" + path, true
+}
+
+// formatAnswer は回答と参照ソースのリンクを Slack のメッセージに整形する
+func formatAnswer(userID, question string, result *coreask.AskResult, linker *sourceLinker) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "<@%s> の質問: %s\n\n", userID, escapeText(question))
+	b.WriteString(toMrkdwn(result.Answer))
+
+	if len(result.Sources) > 0 {
+		b.WriteString("\n\n*参照ソース*")
+		for i, source := range result.Sources {
+			if i == maxListedSources {
+				fmt.Fprintf(&b, "\n…ほか %d 件", len(result.Sources)-maxListedSources)
+				break
+			}
+			label := escapeText(source.FilePath + sourceLocation(source))
+			if linker != nil {
+				fmt.Fprintf(&b, "\n%d. <%s|%s>", i+1, linker.link(source.FilePath, source.StartLine, source.EndLine), label)
+			} else {
+				fmt.Fprintf(&b, "\n%d. `%s`", i+1, label)
+			}
+		}
+	}
+
+	if !result.Refused {
+		b.WriteString("\n\n_回答が役に立った場合は :+1:、役に立たなかった場合は :-1: のリアクションを付けてください_")
+	}
+	return b.String()
+}
+
+// sourceLocation は参照ソースの位置（行範囲・ページ・見出し）を表す接尾辞を返す
+func sourceLocation(source coreask.SourceReference) string {
+	switch {
+	case source.PageStart != nil:
+		if source.PageEnd != nil && *source.PageEnd != *source.PageStart {
+			return fmt.Sprintf(" p.%d-%d", *source.PageStart, *source.PageEnd)
+		}
+		return fmt.Sprintf(" p.%d", *source.PageStart)
+	case source.Breadcrumb != nil && *source.Breadcrumb != "":
+		return " > " + *source.Breadcrumb
+	case source.StartLine > 0:
+		return fmt.Sprintf(":%d-%d", source.StartLine, source.EndLine)
+	}
+	return ""
+}
+
+// escapeText は Slack のメッセージで制御文字として扱われる &, <, > をエスケープする
+func escapeText(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+var (
+	markdownHeading = regexp.MustCompile(`^#{1,6}\s+(.+)$`)
+	markdownBold    = regexp.MustCompile(`\*\*(.+?)\*\*`)
+	markdownLink    = regexp.MustCompile(`\[([^\]]+)\]\((https?://[^)\s]+)\)`)
+)
+
+// toMrkdwn はLLMが生成したMarkdownを Slack の mrkdwn に変換する（見出し・太字・リンクのみ、コードブロック内は変換しない）
+func toMrkdwn(markdown string) string {
+	lines := strings.Split(markdown, "\n")
+	inCode := false
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			lines[i] = escapeText(line)
+			continue
+		}
+
+		line = escapeText(line)
+		if m := markdownHeading.FindStringSubmatch(line); m != nil {
+			line = "*" + strings.Trim(m[1], "*") + "*"
+		} else {
+			line = markdownBold.ReplaceAllString(line, "*$1*")
+		}
+		lines[i] = markdownLink.ReplaceAllString(line, "<$2|$1>")
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,94 @@
+package slackbot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	coreask "github.com/jinford/dev-rag/internal/core/ask"
+	"github.com/jinford/dev-rag/internal/core/ingestion"
+)
+
+func TestRatingFromReaction(t *testing.T) {
+	tests := []struct {
+		reaction string
+		want     coreask.Rating
+		ok       bool
+	}{
+		{"+1", coreask.RatingUp, true},
+		{"thumbsup", coreask.RatingUp, true},
+		{"+1::skin-tone-3", coreask.RatingUp, true},
+		{"-1", coreask.RatingDown, true},
+		{"thumbsdown", coreask.RatingDown, true},
+		{"eyes", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.reaction, func(t *testing.T) {
+			got, ok := ratingFromReaction(tt.reaction)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNewSourceLinker(t *testing.T) {
+	t.Run("GitHub", func(t *testing.T) {
+		linker := newSourceLinker([]*ingestion.Source{
+			{SourceType: ingestion.SourceTypeGit, Metadata: ingestion.SourceMetadata{"url": "git@github.com:acme/shop.git"}},
+			{SourceType: ingestion.SourceTypeConfluence, Metadata: ingestion.SourceMetadata{"url": "https://acme.atlassian.net/wiki"}},
+		}, "main")
+		require.NotNil(t, linker)
+		assert.Equal(t, "https://github.com/acme/shop/blob/main/payment/retry.go#L10-L42", linker.link("payment/retry.go", 10, 42))
+	})
+
+	t.Run("GitLab with ref", func(t *testing.T) {
+		linker := newSourceLinker([]*ingestion.Source{
+			{SourceType: ingestion.SourceTypeGit, Metadata: ingestion.SourceMetadata{"url": "https://gitlab.example.com/acme/platform/shop.git", "default_ref": "develop"}},
+		}, "main")
+		require.NotNil(t, linker)
+		assert.Equal(t, "https://gitlab.example.com/acme/platform/shop/-/blob/develop/docs/my%20guide.md#L3-8", linker.link("docs/my guide.md", 3, 8))
+	})
+
+	t.Run("multiple git sources", func(t *testing.T) {
+		linker := newSourceLinker([]*ingestion.Source{
+			{SourceType: ingestion.SourceTypeGit, Metadata: ingestion.SourceMetadata{"url": "git@github.com:acme/shop.git"}},
+			{SourceType: ingestion.SourceTypeGit, Metadata: ingestion.SourceMetadata{"url": "git@github.com:acme/payments.git"}},
+		}, "main")
+		assert.Nil(t, linker)
+	})
+
+	t.Run("local directory", func(t *testing.T) {
+		linker := newSourceLinker([]*ingestion.Source{
+			{SourceType: ingestion.SourceTypeGit, Metadata: ingestion.SourceMetadata{"url": "/srv/repos/shop"}},
+		}, "main")
+		assert.Nil(t, linker)
+	})
+}
+
+func TestFormatAnswer(t *testing.T) {
+	linker := &sourceLinker{webURL: "https://github.com/acme/shop", ref: "main"}
+	result := &coreask.AskResult{
+		Answer: "## 概要\n決済は **3回** まで再試行します [1]\n```go\nif a < b {}\n```",
+		Sources: []coreask.SourceReference{
+			{FilePath: "payment/retry.go", StartLine: 10, EndLine: 42},
+		},
+	}
+
+	text := formatAnswer("U123", "決済の<リトライ>方針は？", result, linker)
+	assert.Contains(t, text, "<@U123> の質問: 決済の&lt;リトライ&gt;方針は？")
+	assert.Contains(t, text, "*概要*\n決済は *3回* まで再試行します")
+	assert.Contains(t, text, "if a &lt; b {}")
+	assert.Contains(t, text, "1. <https://github.com/acme/shop/blob/main/payment/retry.go#L10-L42|payment/retry.go:10-42>")
+	assert.Contains(t, text, ":+1:")
+
+	t.Run("without links and refused", func(t *testing.T) {
+		text := formatAnswer("U123", "q", &coreask.AskResult{
+			Answer:  "回答できません",
+			Sources: []coreask.SourceReference{{FilePath: "README.md", StartLine: 1, EndLine: 5}},
+			Refused: true,
+		}, nil)
+		assert.Contains(t, text, "1. `README.md:1-5`")
+		assert.NotContains(t, text, ":+1:")
+	})
+}
@@ -0,0 +1,40 @@
+// Package apispec はOpenAPI（Swagger）・Protocol Buffers の定義ファイルからAPIのエンドポイントを抽出する
+package apispec
+
+import (
+	"github.com/google/uuid"
+)
+
+// Kind はエンドポイントの種類
+type Kind string
+
+const (
+	KindHTTP Kind = "http" // OpenAPI・Swagger、または google.api.http で公開された gRPC メソッド
+	KindGRPC Kind = "grpc" // Protocol Buffers の service に定義された RPC
+)
+
+// Kinds はエンドポイントの種類の一覧（表示順）
+var Kinds = []Kind{KindHTTP, KindGRPC}
+
+// Endpoint はAPI定義ファイルから抽出したエンドポイント
+type Endpoint struct {
+	ID           uuid.UUID
+	SnapshotID   uuid.UUID
+	FileID       uuid.UUID
+	SourceName   string // 取得時のみ設定される
+	FilePath     string
+	Kind         Kind
+	Service      string // OpenAPI は最初のタグ、gRPC は <package>.<service>
+	Method       string // HTTP メソッド（GET など）または RPC 名
+	Path         string // HTTP のパス、または /<package>.<service>/<RPC名>
+	OperationID  string // OpenAPI の operationId、または RPC 名
+	Summary      string
+	RequestType  string // リクエストの型名（ストリーミングは "stream " を前置）
+	ResponseType string // レスポンスの型名（ストリーミングは "stream " を前置）
+}
+
+// ListFilter はエンドポイントの一覧の絞り込み条件
+type ListFilter struct {
+	Kind    Kind   // 空の場合はすべての種類
+	Keyword string // パス・サービス・operationId・概要の部分一致（大文字小文字を区別しない）
+}
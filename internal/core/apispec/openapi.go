@@ -0,0 +1,193 @@
+package apispec
+
+import (
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// openAPIMethods はパスアイテムのうちオペレーションを表すキー
+var openAPIMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// preferredMediaType はリクエスト・レスポンスの型を選ぶ際に優先するメディアタイプ
+const preferredMediaType = "application/json"
+
+// openAPIDocument はOpenAPI 3.x・Swagger 2.0 の定義のうちエンドポイントの抽出に使用する部分
+type openAPIDocument struct {
+	OpenAPI  string    `yaml:"openapi"`
+	Swagger  string    `yaml:"swagger"`
+	BasePath string    `yaml:"basePath"` // Swagger 2.0 のみ
+	Paths    yaml.Node `yaml:"paths"`
+}
+
+type openAPIOperation struct {
+	OperationID string                     `yaml:"operationId"`
+	Summary     string                     `yaml:"summary"`
+	Description string                     `yaml:"description"`
+	Tags        []string                   `yaml:"tags"`
+	Parameters  []openAPIParameter         `yaml:"parameters"`
+	RequestBody *openAPIBody               `yaml:"requestBody"`
+	Responses   map[string]openAPIResponse `yaml:"responses"`
+}
+
+type openAPIParameter struct {
+	In     string         `yaml:"in"`
+	Schema *openAPISchema `yaml:"schema"`
+}
+
+type openAPIBody struct {
+	Ref     string                      `yaml:"$ref"`
+	Content map[string]openAPIMediaType `yaml:"content"`
+}
+
+type openAPIResponse struct {
+	Ref     string                      `yaml:"$ref"`
+	Content map[string]openAPIMediaType `yaml:"content"`
+	Schema  *openAPISchema              `yaml:"schema"` // Swagger 2.0
+}
+
+type openAPIMediaType struct {
+	Schema *openAPISchema `yaml:"schema"`
+}
+
+type openAPISchema struct {
+	Ref   string         `yaml:"$ref"`
+	Type  string         `yaml:"type"`
+	Title string         `yaml:"title"`
+	Items *openAPISchema `yaml:"items"`
+}
+
+// ParseOpenAPI はOpenAPI 3.x・Swagger 2.0 の定義（YAML または JSON）からエンドポイントを抽出する
+// トップレベルに openapi・swagger のいずれもない場合は false を返す
+func ParseOpenAPI(content []byte) ([]*Endpoint, bool) {
+	var doc openAPIDocument
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, false
+	}
+	if doc.OpenAPI == "" && doc.Swagger == "" {
+		return nil, false
+	}
+
+	basePath := strings.TrimSuffix(doc.BasePath, "/")
+	var endpoints []*Endpoint
+	if doc.Paths.Kind != yaml.MappingNode {
+		return endpoints, true
+	}
+	for i := 0; i+1 < len(doc.Paths.Content); i += 2 {
+		apiPath := doc.Paths.Content[i].Value
+		item := doc.Paths.Content[i+1]
+		if item.Kind != yaml.MappingNode {
+			continue
+		}
+		for j := 0; j+1 < len(item.Content); j += 2 {
+			method := strings.ToLower(item.Content[j].Value)
+			if !slices.Contains(openAPIMethods, method) {
+				continue
+			}
+			var op openAPIOperation
+			if err := item.Content[j+1].Decode(&op); err != nil {
+				// 不正なオペレーションは読み飛ばし、他のエンドポイントの抽出は続ける
+				continue
+			}
+
+			ep := &Endpoint{
+				Kind:         KindHTTP,
+				Method:       strings.ToUpper(method),
+				Path:         basePath + apiPath,
+				OperationID:  op.OperationID,
+				Summary:      op.Summary,
+				RequestType:  op.requestType(),
+				ResponseType: op.responseType(),
+			}
+			if ep.Summary == "" {
+				ep.Summary = firstLine(op.Description)
+			}
+			if len(op.Tags) > 0 {
+				ep.Service = op.Tags[0]
+			}
+			endpoints = append(endpoints, ep)
+		}
+	}
+	return endpoints, true
+}
+
+// requestType はリクエストボディの型名を返す（Swagger 2.0 は in: body のパラメータ）
+func (op *openAPIOperation) requestType() string {
+	if op.RequestBody != nil {
+		if op.RequestBody.Ref != "" {
+			return refName(op.RequestBody.Ref)
+		}
+		return mediaTypeSchemaName(op.RequestBody.Content)
+	}
+	for _, param := range op.Parameters {
+		if param.In == "body" {
+			return schemaName(param.Schema)
+		}
+	}
+	return ""
+}
+
+// responseType は成功レスポンス（最小の 2xx、なければ default）の型名を返す
+func (op *openAPIOperation) responseType() string {
+	codes := make([]string, 0, len(op.Responses))
+	for code := range op.Responses {
+		if strings.HasPrefix(code, "2") {
+			codes = append(codes, code)
+		}
+	}
+	slices.Sort(codes)
+	if _, ok := op.Responses["default"]; ok {
+		codes = append(codes, "default")
+	}
+	if len(codes) == 0 {
+		return ""
+	}
+
+	resp := op.Responses[codes[0]]
+	switch {
+	case resp.Ref != "":
+		return refName(resp.Ref)
+	case resp.Schema != nil:
+		return schemaName(resp.Schema)
+	default:
+		return mediaTypeSchemaName(resp.Content)
+	}
+}
+
+// mediaTypeSchemaName は application/json（なければメディアタイプ順で最初）のスキーマの型名を返す
+func mediaTypeSchemaName(content map[string]openAPIMediaType) string {
+	if len(content) == 0 {
+		return ""
+	}
+	if media, ok := content[preferredMediaType]; ok {
+		return schemaName(media.Schema)
+	}
+	types := make([]string, 0, len(content))
+	for mediaType := range content {
+		types = append(types, mediaType)
+	}
+	slices.Sort(types)
+	return schemaName(content[types[0]].Schema)
+}
+
+// schemaName はスキーマの型名を返す（$ref は参照先の名前、配列は []<要素の型>）
+func schemaName(schema *openAPISchema) string {
+	switch {
+	case schema == nil:
+		return ""
+	case schema.Ref != "":
+		return refName(schema.Ref)
+	case schema.Type == "array" && schema.Items != nil:
+		return "[]" + schemaName(schema.Items)
+	case schema.Title != "":
+		return schema.Title
+	default:
+		return schema.Type
+	}
+}
+
+// refName は $ref（#/components/schemas/User など）の末尾の名前を返す
+func refName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
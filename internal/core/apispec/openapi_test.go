@@ -0,0 +1,126 @@
+package apispec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOpenAPI_V3(t *testing.T) {
+	content := `openapi: 3.0.3
+info:
+  title: User API
+paths:
+  /users/{id}:
+    parameters:
+      - name: id
+        in: path
+    get:
+      operationId: getUser
+      tags: [users]
+      summary: ユーザーを取得する
+      responses:
+        "404":
+          description: not found
+        200:
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/User'
+    delete:
+      operationId: deleteUser
+      tags: [users]
+      description: |
+        ユーザーを削除する。
+
+        削除は取り消せない。
+      responses:
+        "204":
+          description: deleted
+  /users:
+    post:
+      operationId: createUser
+      requestBody:
+        content:
+          application/xml:
+            schema:
+              type: object
+          application/json:
+            schema:
+              $ref: '#/components/schemas/CreateUserRequest'
+      responses:
+        "201":
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/User'
+`
+	endpoints, ok := ParseOpenAPI([]byte(content))
+	require.True(t, ok)
+	require.Len(t, endpoints, 3)
+
+	get := endpoints[0]
+	assert.Equal(t, KindHTTP, get.Kind)
+	assert.Equal(t, "GET", get.Method)
+	assert.Equal(t, "/users/{id}", get.Path)
+	assert.Equal(t, "users", get.Service)
+	assert.Equal(t, "getUser", get.OperationID)
+	assert.Equal(t, "ユーザーを取得する", get.Summary)
+	assert.Equal(t, "User", get.ResponseType)
+	assert.Empty(t, get.RequestType)
+
+	del := endpoints[1]
+	assert.Equal(t, "DELETE", del.Method)
+	assert.Equal(t, "ユーザーを削除する。", del.Summary)
+	assert.Empty(t, del.ResponseType)
+
+	post := endpoints[2]
+	assert.Equal(t, "POST", post.Method)
+	assert.Empty(t, post.Service)
+	assert.Equal(t, "CreateUserRequest", post.RequestType)
+	assert.Equal(t, "[]User", post.ResponseType)
+}
+
+func TestParseOpenAPI_Swagger2JSON(t *testing.T) {
+	content := `{
+  "swagger": "2.0",
+  "basePath": "/api/v1/",
+  "paths": {
+    "/orders": {
+      "post": {
+        "operationId": "createOrder",
+        "tags": ["orders"],
+        "parameters": [
+          {"name": "body", "in": "body", "schema": {"$ref": "#/definitions/Order"}}
+        ],
+        "responses": {
+          "default": {"schema": {"type": "string"}},
+          "200": {"schema": {"$ref": "#/definitions/OrderResult"}}
+        }
+      }
+    }
+  }
+}`
+	endpoints, ok := ParseOpenAPI([]byte(content))
+	require.True(t, ok)
+	require.Len(t, endpoints, 1)
+
+	assert.Equal(t, "/api/v1/orders", endpoints[0].Path)
+	assert.Equal(t, "Order", endpoints[0].RequestType)
+	assert.Equal(t, "OrderResult", endpoints[0].ResponseType)
+}
+
+func TestParseOpenAPI_NotSpec(t *testing.T) {
+	_, ok := ParseOpenAPI([]byte("apiVersion: v1\nkind: Service\n"))
+	assert.False(t, ok)
+
+	_, ok = ParseOpenAPI([]byte("not: [valid"))
+	assert.False(t, ok)
+
+	endpoints, ok := ParseOpenAPI([]byte("openapi: 3.1.0\ninfo:\n  title: empty\n"))
+	assert.True(t, ok)
+	assert.Empty(t, endpoints)
+}
@@ -0,0 +1,67 @@
+package apispec
+
+import (
+	"cmp"
+	"path"
+	"slices"
+	"strings"
+)
+
+// methodOrder はエンドポイントを並べる際のHTTPメソッドの順序
+var methodOrder = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS", "TRACE"}
+
+// Parse はファイルがOpenAPI（Swagger）または Protocol Buffers の定義であればエンドポイントを抽出する
+// API定義ファイルでない場合は false を返す（エンドポイントを1つも含まない定義ファイルは true と空のスライスを返す）
+// SnapshotID・FileID は呼び出し元で設定する
+func Parse(filePath, content string) ([]*Endpoint, bool) {
+	var endpoints []*Endpoint
+	switch strings.ToLower(path.Ext(filePath)) {
+	case ".proto":
+		endpoints = ParseProto(content)
+	case ".yaml", ".yml", ".json":
+		var ok bool
+		endpoints, ok = ParseOpenAPI([]byte(content))
+		if !ok {
+			return nil, false
+		}
+	default:
+		return nil, false
+	}
+
+	for _, ep := range endpoints {
+		ep.FilePath = filePath
+	}
+	SortEndpoints(endpoints)
+	return endpoints, true
+}
+
+// SortEndpoints はエンドポイントを種類・サービス・パス・メソッド順に並べる
+func SortEndpoints(endpoints []*Endpoint) {
+	slices.SortStableFunc(endpoints, func(a, b *Endpoint) int {
+		return cmp.Or(
+			cmp.Compare(slices.Index(Kinds, a.Kind), slices.Index(Kinds, b.Kind)),
+			cmp.Compare(a.Service, b.Service),
+			cmp.Compare(a.Path, b.Path),
+			cmp.Compare(methodRank(a.Method), methodRank(b.Method)),
+			cmp.Compare(a.Method, b.Method),
+		)
+	})
+}
+
+// methodRank はHTTPメソッドの並び順を返す（RPC名など HTTP メソッド以外は末尾）
+func methodRank(method string) int {
+	if i := slices.Index(methodOrder, method); i >= 0 {
+		return i
+	}
+	return len(methodOrder)
+}
+
+// firstLine は説明文の最初の空でない行を返す
+func firstLine(s string) string {
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
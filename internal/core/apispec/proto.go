@@ -0,0 +1,147 @@
+package apispec
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	protoPackagePattern  = regexp.MustCompile(`\bpackage\s+([\w.]+)\s*;`)
+	protoServicePattern  = regexp.MustCompile(`\bservice\s+(\w+)\s*\{`)
+	protoRPCPattern      = regexp.MustCompile(`\brpc\s+(\w+)\s*\(\s*(stream\s+)?([\w.]+)\s*\)\s*returns\s*\(\s*(stream\s+)?([\w.]+)\s*\)`)
+	protoHTTPPattern     = regexp.MustCompile(`option\s*\(\s*google\.api\.http\s*\)\s*=\s*\{`)
+	protoHTTPRulePattern = regexp.MustCompile(`\b(get|put|post|delete|patch)\s*:\s*"([^"]*)"`)
+)
+
+// ParseProto は Protocol Buffers の定義から service の RPC を抽出する
+// google.api.http オプションでHTTPに公開されている RPC は、HTTP のエンドポイントとしても抽出する
+// RPC の直前の行コメントを概要とする
+func ParseProto(content string) []*Endpoint {
+	code := blankProtoComments(content)
+	lines := strings.Split(content, "\n")
+
+	pkg := ""
+	if m := protoPackagePattern.FindStringSubmatch(code); m != nil {
+		pkg = m[1]
+	}
+
+	var endpoints []*Endpoint
+	for _, loc := range protoServicePattern.FindAllStringSubmatchIndex(code, -1) {
+		service := code[loc[2]:loc[3]]
+		if pkg != "" {
+			service = pkg + "." + service
+		}
+		bodyStart := loc[1]
+		bodyEnd := matchingBrace(code, bodyStart)
+		body := code[bodyStart:bodyEnd]
+
+		for _, rpc := range protoRPCPattern.FindAllStringSubmatchIndex(body, -1) {
+			name := body[rpc[2]:rpc[3]]
+			ep := &Endpoint{
+				Kind:         KindGRPC,
+				Service:      service,
+				Method:       name,
+				Path:         "/" + service + "/" + name,
+				OperationID:  name,
+				Summary:      leadingComment(lines, strings.Count(code[:bodyStart+rpc[0]], "\n")),
+				RequestType:  streamType(body, rpc[4], rpc[6], rpc[7]),
+				ResponseType: streamType(body, rpc[8], rpc[10], rpc[11]),
+			}
+			endpoints = append(endpoints, ep)
+
+			// rpc の本体（{ option ... }）に google.api.http があればHTTPのエンドポイントとしても登録する
+			rest := strings.TrimLeft(body[rpc[1]:], " \t\r\n")
+			if !strings.HasPrefix(rest, "{") {
+				continue
+			}
+			optStart := len(body) - len(rest)
+			options := body[optStart:matchingBrace(body, optStart+1)]
+			if opt := protoHTTPPattern.FindStringIndex(options); opt != nil {
+				rule := options[opt[1]:matchingBrace(options, opt[1])]
+				for _, m := range protoHTTPRulePattern.FindAllStringSubmatch(rule, -1) {
+					httpEP := *ep
+					httpEP.Kind = KindHTTP
+					httpEP.Method = strings.ToUpper(m[1])
+					httpEP.Path = m[2]
+					endpoints = append(endpoints, &httpEP)
+				}
+			}
+		}
+	}
+	return endpoints
+}
+
+// streamType は RPC の引数・戻り値の型名を返す（ストリーミングは "stream " を前置）
+func streamType(body string, streamStart, typeStart, typeEnd int) string {
+	typ := body[typeStart:typeEnd]
+	if streamStart >= 0 {
+		return "stream " + typ
+	}
+	return typ
+}
+
+// matchingBrace は start 以降で、start の直前の '{' に対応する '}' の位置を返す（見つからない場合は末尾）
+func matchingBrace(s string, start int) int {
+	depth := 1
+	for i := start; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(s)
+}
+
+// leadingComment は lineIdx 行目の直前に連続する行コメント（//）を連結して返す
+func leadingComment(lines []string, lineIdx int) string {
+	var comment []string
+	for i := lineIdx - 1; i >= 0; i-- {
+		line := strings.TrimSpace(lines[i])
+		if !strings.HasPrefix(line, "//") {
+			break
+		}
+		comment = append([]string{strings.TrimSpace(strings.TrimPrefix(line, "//"))}, comment...)
+	}
+	return strings.TrimSpace(strings.Join(comment, " "))
+}
+
+// blankProtoComments はコメントを空白に置き換える（位置と改行は保持し、文字列リテラル内は置き換えない）
+func blankProtoComments(src string) string {
+	out := []byte(src)
+	inString := byte(0)
+	for i := 0; i < len(out); i++ {
+		c := out[i]
+		switch {
+		case inString != 0:
+			if c == '\\' {
+				i++
+			} else if c == inString {
+				inString = 0
+			}
+		case c == '"' || c == '\'':
+			inString = c
+		case c == '/' && i+1 < len(out) && out[i+1] == '/':
+			for ; i < len(out) && out[i] != '\n'; i++ {
+				out[i] = ' '
+			}
+		case c == '/' && i+1 < len(out) && out[i+1] == '*':
+			out[i], out[i+1] = ' ', ' '
+			for i += 2; i < len(out); i++ {
+				if out[i] == '*' && i+1 < len(out) && out[i+1] == '/' {
+					out[i], out[i+1] = ' ', ' '
+					i++
+					break
+				}
+				if out[i] != '\n' {
+					out[i] = ' '
+				}
+			}
+		}
+	}
+	return string(out)
+}
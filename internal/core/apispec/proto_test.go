@@ -0,0 +1,84 @@
+package apispec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseProto(t *testing.T) {
+	content := `syntax = "proto3";
+
+package acme.user.v1;
+
+import "google/api/annotations.proto";
+
+/* UserService はユーザーを管理する
+   service Fake { rpc Nope(A) returns (B); } */
+service UserService {
+  // GetUser はユーザーを取得する
+  // 存在しない場合は NOT_FOUND を返す
+  rpc GetUser(GetUserRequest) returns (User) {
+    option (google.api.http) = {
+      get: "/v1/{name=users/*}"
+      additional_bindings { get: "/v1/users/{id}" }
+    };
+  }
+
+  rpc WatchUsers(WatchUsersRequest) returns (stream User); // 変更を購読する
+}
+
+service Admin {
+  rpc Import(stream google.protobuf.Any)
+      returns (ImportResult);
+}
+`
+	endpoints := ParseProto(content)
+	require.Len(t, endpoints, 5)
+
+	get := endpoints[0]
+	assert.Equal(t, KindGRPC, get.Kind)
+	assert.Equal(t, "acme.user.v1.UserService", get.Service)
+	assert.Equal(t, "GetUser", get.Method)
+	assert.Equal(t, "/acme.user.v1.UserService/GetUser", get.Path)
+	assert.Equal(t, "GetUser はユーザーを取得する 存在しない場合は NOT_FOUND を返す", get.Summary)
+	assert.Equal(t, "GetUserRequest", get.RequestType)
+	assert.Equal(t, "User", get.ResponseType)
+
+	assert.Equal(t, KindHTTP, endpoints[1].Kind)
+	assert.Equal(t, "GET", endpoints[1].Method)
+	assert.Equal(t, "/v1/{name=users/*}", endpoints[1].Path)
+	assert.Equal(t, "GetUser", endpoints[1].OperationID)
+	assert.Equal(t, "/v1/users/{id}", endpoints[2].Path)
+
+	watch := endpoints[3]
+	assert.Equal(t, "WatchUsers", watch.Method)
+	assert.Equal(t, "stream User", watch.ResponseType)
+	assert.Empty(t, watch.Summary)
+
+	imp := endpoints[4]
+	assert.Equal(t, "acme.user.v1.Admin", imp.Service)
+	assert.Equal(t, "stream google.protobuf.Any", imp.RequestType)
+	assert.Equal(t, "ImportResult", imp.ResponseType)
+}
+
+func TestParse(t *testing.T) {
+	endpoints, ok := Parse("api/user.proto", "package a;\nservice S { rpc B(X) returns (Y); rpc A(X) returns (Y) { option (google.api.http) = { post: \"/a\" }; } }\n")
+	require.True(t, ok)
+	require.Len(t, endpoints, 3)
+
+	// 種類・サービス・パス順に並べ、ファイルパスを設定する
+	assert.Equal(t, KindHTTP, endpoints[0].Kind)
+	assert.Equal(t, "/a.S/A", endpoints[1].Path)
+	assert.Equal(t, "/a.S/B", endpoints[2].Path)
+	for _, ep := range endpoints {
+		assert.Equal(t, "api/user.proto", ep.FilePath)
+	}
+
+	_, ok = Parse("deploy/values.yaml", "replicas: 3\n")
+	assert.False(t, ok)
+
+	_, ok = Parse("main.go", "package main\n")
+	assert.False(t, ok)
+}
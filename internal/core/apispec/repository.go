@@ -0,0 +1,15 @@
+package apispec
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+)
+
+// Reader はインデックス済みのエンドポイントを読み取るインターフェース
+type Reader interface {
+	// ListEndpoints はエンドポイントをソース名・種類・サービス・パス順に取得する
+	// product_id 指定時はプロダクトの各ソースの最新スナップショット、snapshot_id 指定時はそのスナップショットを対象とする
+	ListEndpoints(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID], filter ListFilter) ([]*Endpoint, error)
+}
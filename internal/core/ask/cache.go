@@ -0,0 +1,119 @@
+package ask
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+
+	"github.com/jinford/dev-rag/internal/core/prompt"
+	"github.com/jinford/dev-rag/internal/core/search"
+)
+
+// AnswerCacheEntry はキャッシュする質問応答の回答
+type AnswerCacheEntry struct {
+	Key           string               // answerCacheKey で生成したキー
+	ProductID     mo.Option[uuid.UUID] // 質問したプロダクト
+	SnapshotID    mo.Option[uuid.UUID] // ref/snapshot 指定時の検索対象のスナップショット
+	PromptVersion string
+	Question      string // 正規化した質問文
+	Result        *AskResult
+}
+
+// AnswerCacheStore は質問応答の回答を保存するストア
+type AnswerCacheStore interface {
+	// Get は notBefore 以降に保存した回答を返す（参照した回数を記録する）
+	// 保存後に検索対象のスナップショットが変わった（再インデックスされた）回答は返さない
+	Get(ctx context.Context, key string, notBefore time.Time) (mo.Option[*AskResult], error)
+	// Put は回答を保存する（同じキーの回答は置き換える）
+	Put(ctx context.Context, entry *AnswerCacheEntry) error
+}
+
+// WithAskAnswerCache は正規化した質問文・検索範囲・プロンプトテンプレートのバージョンが同じ質問に、
+// 保存した回答を返すためのストアを設定する（ttl が 0 の場合は期限なし）
+// 「テストの実行方法は？」のようなよくある質問で、検索とLLMの呼び出しを繰り返さないようにする
+func WithAskAnswerCache(store AnswerCacheStore, ttl time.Duration) AskServiceOption {
+	return func(s *AskService) {
+		s.answerCache = store
+		s.answerCacheTTL = ttl
+	}
+}
+
+// NormalizeQuestion はキャッシュのキーに使用するため質問文を正規化する
+// 小文字にそろえ、連続する空白（全角を含む）を1つにまとめ、末尾の疑問符・句読点を除く
+func NormalizeQuestion(question string) string {
+	normalized := strings.Join(strings.Fields(strings.ToLower(question)), " ")
+	return strings.TrimRightFunc(normalized, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsPunct(r)
+	})
+}
+
+// answerCacheKey は回答に影響する質問のパラメータからキャッシュのキーを生成する
+func answerCacheKey(params AskParams, question, promptVersion, systemPrompt string, chunkLimit, summaryLimit int, expansion search.DependencyExpansion) string {
+	domains := slices.Sorted(slices.Values(params.Domains))
+	languages := slices.Sorted(slices.Values(params.Languages))
+
+	h := sha256.New()
+	for _, field := range []string{
+		params.ProductID.OrEmpty().String(),
+		params.SnapshotID.OrEmpty().String(),
+		question,
+		promptVersion,
+		params.Model,
+		systemPrompt,
+		strings.Join(domains, ","),
+		strings.Join(languages, ","),
+		params.PathPrefix,
+		string(params.Strategy),
+		params.EmbeddingModel,
+		fmt.Sprintf("%d/%d/%d", chunkLimit, summaryLimit, expansion.MaxHops),
+		fmt.Sprintf("%t", params.VerifyCitations),
+	} {
+		h.Write([]byte(field))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// cachedAnswer はキャッシュした回答を返す
+// キャッシュは補助的な仕組みのため、読み込みに失敗した場合は警告を記録して回答を生成する
+func (s *AskService) cachedAnswer(ctx context.Context, key string) (*AskResult, bool) {
+	var notBefore time.Time
+	if s.answerCacheTTL > 0 {
+		notBefore = time.Now().Add(-s.answerCacheTTL)
+	}
+	cached, err := s.answerCache.Get(ctx, key, notBefore)
+	if err != nil {
+		s.logger.Warn("failed to read answer cache", "error", err)
+		return nil, false
+	}
+	result, ok := cached.Get()
+	if !ok {
+		return nil, false
+	}
+	result.Cached = true
+	return result, true
+}
+
+// storeAnswer は生成した回答をキャッシュに保存する（失敗した場合は警告のみ）
+func (s *AskService) storeAnswer(ctx context.Context, entry *AnswerCacheEntry) {
+	if err := s.answerCache.Put(ctx, entry); err != nil {
+		s.logger.Warn("failed to write answer cache", "error", err)
+	}
+}
+
+// askPromptVersion は回答の生成に使用するプロンプトテンプレートのバージョンを返す
+func (s *AskService) askPromptVersion(ctx context.Context) (string, error) {
+	tmpl, err := s.prompts.Resolve(ctx, prompt.NameAsk)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve prompt: %w", err)
+	}
+	return tmpl.Version, nil
+}
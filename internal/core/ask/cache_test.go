@@ -0,0 +1,86 @@
+package ask
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jinford/dev-rag/internal/core/search"
+)
+
+type stubAnswerCache struct {
+	results   map[string]*AskResult
+	keys      []string
+	notBefore time.Time
+}
+
+func (c *stubAnswerCache) Get(_ context.Context, key string, notBefore time.Time) (mo.Option[*AskResult], error) {
+	c.keys = append(c.keys, key)
+	c.notBefore = notBefore
+	if result, ok := c.results[key]; ok {
+		return mo.Some(result), nil
+	}
+	return mo.None[*AskResult](), nil
+}
+
+func (c *stubAnswerCache) Put(_ context.Context, entry *AnswerCacheEntry) error {
+	c.results[entry.Key] = entry.Result
+	return nil
+}
+
+func TestNormalizeQuestion(t *testing.T) {
+	assert.Equal(t, "how do i run tests", NormalizeQuestion("  How do I   run tests?  "))
+	assert.Equal(t, "テストの実行方法は", NormalizeQuestion("テストの実行方法は？"))
+	assert.Equal(t, "テストの 実行方法は", NormalizeQuestion("テストの　実行方法は。"))
+	assert.Equal(t, "what is foo.bar", NormalizeQuestion("What is foo.bar?!"))
+}
+
+func TestAskService_AnswerCache(t *testing.T) {
+	productID := uuid.New()
+	cache := &stubAnswerCache{results: map[string]*AskResult{}}
+	// キャッシュした回答がある場合は検索サービス・LLM を呼び出さない
+	svc := NewAskService(nil, nil, WithAskAnswerCache(cache, time.Hour))
+
+	params := AskParams{ProductID: mo.Some(productID), Query: "How do I run tests?"}
+	version, err := svc.askPromptVersion(context.Background())
+	require.NoError(t, err)
+	key := answerCacheKey(params, NormalizeQuestion(params.Query), version, "", 10, 5, svc.expansion)
+	cache.results[key] = &AskResult{Answer: "make test を実行します", Sources: []SourceReference{}, PromptVersion: version}
+
+	result, err := svc.Ask(context.Background(), AskParams{ProductID: mo.Some(productID), Query: "how do i run  tests"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "make test を実行します", result.Answer)
+	assert.True(t, result.Cached)
+	assert.Equal(t, []string{key}, cache.keys, "正規化した質問文が同じなら同じキー")
+	assert.WithinDuration(t, time.Now().Add(-time.Hour), cache.notBefore, time.Minute)
+}
+
+func TestAnswerCacheKey(t *testing.T) {
+	params := AskParams{ProductID: mo.Some(uuid.New()), Query: "q", Domains: []string{"code", "ops"}}
+	base := answerCacheKey(params, "q", "v1", "", 10, 5, search.DependencyExpansion{})
+
+	reordered := params
+	reordered.Domains = []string{"ops", "code"}
+	assert.Equal(t, base, answerCacheKey(reordered, "q", "v1", "", 10, 5, search.DependencyExpansion{}), "ドメインの順序は区別しない")
+
+	snapshot := params
+	snapshot.SnapshotID = mo.Some(uuid.New())
+	assert.NotEqual(t, base, answerCacheKey(snapshot, "q", "v1", "", 10, 5, search.DependencyExpansion{}))
+
+	service := params
+	service.PathPrefix = "services/api/"
+	assert.NotEqual(t, base, answerCacheKey(service, "q", "v1", "", 10, 5, search.DependencyExpansion{}), "サービスに限定した質問は別のキー")
+	assert.NotEqual(t, base, answerCacheKey(params, "q", "v2", "", 10, 5, search.DependencyExpansion{}), "プロンプトのバージョンが変わると別のキー")
+	assert.NotEqual(t, base, answerCacheKey(params, "q", "v1", "あなたは決済チームのアシスタントです", 10, 5, search.DependencyExpansion{}))
+	assert.NotEqual(t, base, answerCacheKey(params, "q", "v1", "", 10, 5, search.DependencyExpansion{MaxHops: 1}))
+
+	verify := params
+	verify.VerifyCitations = true
+	assert.NotEqual(t, base, answerCacheKey(verify, "q", "v1", "", 10, 5, search.DependencyExpansion{}))
+}
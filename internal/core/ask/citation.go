@@ -0,0 +1,295 @@
+package ask
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jinford/dev-rag/internal/core/llm"
+	"github.com/jinford/dev-rag/internal/core/prompt"
+	"github.com/jinford/dev-rag/internal/core/search"
+)
+
+// CitationVerdict は引用したコードが回答の文を裏付けているかの判定
+type CitationVerdict string
+
+const (
+	CitationSupported   CitationVerdict = "supported"   // 文の内容を裏付けている
+	CitationPartial     CitationVerdict = "partial"     // 一部のみ裏付けている
+	CitationUnsupported CitationVerdict = "unsupported" // 裏付けていない・無関係
+)
+
+const (
+	// maxVerifiedCitations は1回の回答で検証する引用の上限（LLM呼び出し回数を抑える）
+	maxVerifiedCitations = 10
+	// lowConfidenceThreshold はこの確信度未満の引用を要確認とする
+	lowConfidenceThreshold = 0.5
+	// maxEvidenceLength は検証のプロンプトに含める引用コードの最大文字数
+	maxEvidenceLength = 6000
+)
+
+// CitationCheck は回答の文に付けられた引用の検証結果を表す
+type CitationCheck struct {
+	Sentence   string          // 引用が付けられた回答の文
+	FilePath   string          // 引用されたファイルパス
+	StartLine  int             // 引用された開始行（行番号の指定がない場合は 0）
+	EndLine    int             // 引用された終了行（行番号の指定がない場合は 0）
+	Verdict    CitationVerdict // 裏付けの判定
+	Confidence float64         // 判定の確信度（0〜1）
+	Reason     string          // 判定の理由
+}
+
+// LowConfidence は引用が文を十分に裏付けていない（要確認）かを返す
+func (c CitationCheck) LowConfidence() bool {
+	return c.Verdict == CitationUnsupported || c.Confidence < lowConfidenceThreshold
+}
+
+// Location は引用の場所を表示用に整形する（path、path:12、path:12-34）
+func (c CitationCheck) Location() string {
+	switch {
+	case c.StartLine == 0:
+		return c.FilePath
+	case c.StartLine == c.EndLine:
+		return fmt.Sprintf("%s:%d", c.FilePath, c.StartLine)
+	default:
+		return fmt.Sprintf("%s:%d-%d", c.FilePath, c.StartLine, c.EndLine)
+	}
+}
+
+// citation は回答の文から読み取った引用と、その根拠としてプロンプトに含めたコード断片
+type citation struct {
+	sentence  string
+	filePath  string
+	startLine int
+	endLine   int
+	evidence  []*search.SearchResult
+}
+
+var (
+	sentenceBoundary = regexp.MustCompile(`[。！？]|[.!?](\s|$)|\n`)
+	// ファイルパスの直後の行番号（:12、:12-34、#L12-L34、 L12-L34、 (L12-L34)）
+	lineSuffixPattern = regexp.MustCompile("^`?(?::|#L|\\s*\\(?L)(\\d+)(?:\\s*[-〜~]\\s*L?(\\d+))?")
+)
+
+// splitSentences は回答を文に分割する（コードブロック内は分割しない）
+func splitSentences(answer string) []string {
+	var sentences []string
+	var inFence bool
+	var sb strings.Builder
+	flush := func() {
+		if s := strings.TrimSpace(sb.String()); s != "" {
+			sentences = append(sentences, s)
+		}
+		sb.Reset()
+	}
+
+	for _, line := range strings.SplitAfter(answer, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			flush()
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		rest := line
+		for {
+			loc := sentenceBoundary.FindStringIndex(rest)
+			if loc == nil {
+				sb.WriteString(rest)
+				break
+			}
+			sb.WriteString(rest[:loc[1]])
+			flush()
+			rest = rest[loc[1]:]
+		}
+	}
+	flush()
+	return sentences
+}
+
+// extractCitations は回答の各文から、プロンプトに含めたファイルへの言及（ファイルパスと任意の行番号）を取り出す
+// 行番号の指定がある場合は行範囲が重なる断片、ない場合はそのファイルの全断片を根拠とする
+func extractCitations(answer string, files []*search.FileContext) []citation {
+	// 長いパスを先に照合し、internal/a/b.go を b.go として重複して拾わないようにする
+	ordered := slices.Clone(files)
+	slices.SortStableFunc(ordered, func(a, b *search.FileContext) int {
+		return len(b.FilePath) - len(a.FilePath)
+	})
+
+	var citations []citation
+	for _, sentence := range splitSentences(answer) {
+		covered := make([]bool, len(sentence))
+		for _, file := range ordered {
+			if file.FilePath == "" || len(file.Chunks) == 0 {
+				continue
+			}
+			offset := 0
+			for {
+				idx := strings.Index(sentence[offset:], file.FilePath)
+				if idx < 0 {
+					break
+				}
+				start := offset + idx
+				end := start + len(file.FilePath)
+				offset = end
+				if covered[start] {
+					continue
+				}
+				for i := start; i < end; i++ {
+					covered[i] = true
+				}
+
+				c := citation{sentence: sentence, filePath: file.FilePath}
+				if m := lineSuffixPattern.FindStringSubmatch(sentence[end:]); m != nil {
+					c.startLine, _ = strconv.Atoi(m[1])
+					c.endLine = c.startLine
+					if m[2] != "" {
+						c.endLine, _ = strconv.Atoi(m[2])
+					}
+				}
+				for _, chunk := range file.Chunks {
+					if c.startLine == 0 || (chunk.StartLine <= c.endLine && c.startLine <= chunk.EndLine) {
+						c.evidence = append(c.evidence, chunk)
+					}
+				}
+				citations = append(citations, c)
+			}
+		}
+	}
+	return citations
+}
+
+// BuildCitationPrompt は引用したコードが回答の文を裏付けているかを判定するプロンプトを構築する
+func BuildCitationPrompt(sentence string, evidence []*search.SearchResult) string {
+	return prompt.MustRenderBuiltin(prompt.NameAskCitation, citationPromptData(sentence, evidence))
+}
+
+// citationPromptData は引用の検証のプロンプトテンプレートに渡す値を構築する
+func citationPromptData(sentence string, evidence []*search.SearchResult) prompt.CitationData {
+	var sentenceBlock strings.Builder
+	writeFenced(&sentenceBlock, sentence)
+
+	var sb strings.Builder
+	remaining := maxEvidenceLength
+	for _, chunk := range evidence {
+		if remaining <= 0 {
+			break
+		}
+		content := chunk.Content
+		if len(content) > remaining {
+			content = strings.ToValidUTF8(content[:remaining], "")
+		}
+		remaining -= len(content)
+		sb.WriteString(fmt.Sprintf("### %s (L%d-L%d)\n", chunk.FilePath, chunk.StartLine, chunk.EndLine))
+		writeFenced(&sb, content)
+	}
+	return prompt.CitationData{Sentence: sentenceBlock.String(), Evidence: sb.String()}
+}
+
+// citationJudgement はLLMが返す引用の判定のJSON表現
+type citationJudgement struct {
+	Verdict    string  `json:"verdict"`
+	Confidence float64 `json:"confidence"`
+	Reason     string  `json:"reason"`
+}
+
+// ParseCitationResponse はLLMの応答から引用の判定を取り出す
+// 不明な verdict は unsupported とし、confidence は 0〜1 に丸める
+func ParseCitationResponse(response string) (CitationVerdict, float64, string, error) {
+	start := strings.Index(response, "{")
+	end := strings.LastIndex(response, "}")
+	if start < 0 || end < start {
+		return "", 0, "", fmt.Errorf("no JSON object in response")
+	}
+
+	var judgement citationJudgement
+	if err := json.Unmarshal([]byte(response[start:end+1]), &judgement); err != nil {
+		return "", 0, "", fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	verdict := CitationVerdict(strings.ToLower(strings.TrimSpace(judgement.Verdict)))
+	switch verdict {
+	case CitationSupported, CitationPartial, CitationUnsupported:
+	default:
+		verdict = CitationUnsupported
+	}
+	return verdict, min(max(judgement.Confidence, 0), 1), strings.TrimSpace(judgement.Reason), nil
+}
+
+// verifyCitations は回答の文に付けられた引用ごとに、引用したコードが文を裏付けているかをLLMで判定する
+// 検証は補助的な処理のため、個々の判定に失敗した引用は結果から除いて続行する
+func (s *AskService) verifyCitations(ctx context.Context, model, answer string, files []*search.FileContext) []CitationCheck {
+	citations := extractCitations(answer, files)
+	if len(citations) > maxVerifiedCitations {
+		s.logger.Info("too many citations, verifying only the first ones", "citations", len(citations), "limit", maxVerifiedCitations)
+		citations = citations[:maxVerifiedCitations]
+	}
+
+	checks := make([]CitationCheck, 0, len(citations))
+	for _, c := range citations {
+		check := CitationCheck{
+			Sentence:  c.sentence,
+			FilePath:  c.filePath,
+			StartLine: c.startLine,
+			EndLine:   c.endLine,
+		}
+		if len(c.evidence) == 0 {
+			// 引用された行範囲がプロンプトに含めたコードに含まれない（根拠なしの引用）
+			check.Verdict = CitationUnsupported
+			check.Reason = "引用された行範囲はコンテキストに含まれていません"
+			checks = append(checks, check)
+			continue
+		}
+
+		rendered, err := s.prompts.Render(ctx, prompt.NameAskCitation, citationPromptData(c.sentence, c.evidence))
+		if err != nil {
+			s.logger.Warn("failed to build citation prompt", "filePath", c.filePath, "error", err)
+			continue
+		}
+		response, err := s.llm.GenerateCompletion(llm.WithModel(ctx, model), rendered.Text)
+		if err != nil {
+			s.logger.Warn("failed to verify citation", "filePath", c.filePath, "error", err)
+			continue
+		}
+		check.Verdict, check.Confidence, check.Reason, err = ParseCitationResponse(response)
+		if err != nil {
+			s.logger.Warn("failed to parse citation verification", "filePath", c.filePath, "error", err)
+			continue
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// AnnotateAnswer は確信度の低い引用が付けられた文の直後に要確認の注記を挿入した回答を返す
+func AnnotateAnswer(answer string, checks []CitationCheck) string {
+	notes := make(map[string][]string)
+	var order []string
+	for _, check := range checks {
+		if !check.LowConfidence() {
+			continue
+		}
+		if _, ok := notes[check.Sentence]; !ok {
+			order = append(order, check.Sentence)
+		}
+		notes[check.Sentence] = append(notes[check.Sentence], check.Location())
+	}
+
+	offset := 0
+	for _, sentence := range order {
+		idx := strings.Index(answer[offset:], sentence)
+		if idx < 0 {
+			continue
+		}
+		end := offset + idx + len(sentence)
+		note := fmt.Sprintf(" [要確認: %s は根拠として不十分な可能性があります]", strings.Join(notes[sentence], ", "))
+		answer = answer[:end] + note + answer[end:]
+		offset = end + len(note)
+	}
+	return answer
+}
@@ -0,0 +1,122 @@
+package ask
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jinford/dev-rag/internal/core/search"
+)
+
+// stubCitationLLM は引用の検証プロンプトに含まれるファイルパスに応じて判定を返す
+type stubCitationLLM struct {
+	responses map[string]string
+	prompts   []string
+}
+
+func (s *stubCitationLLM) GenerateCompletion(_ context.Context, prompt string) (string, error) {
+	s.prompts = append(s.prompts, prompt)
+	for path, response := range s.responses {
+		if strings.Contains(prompt, "### "+path) {
+			return response, nil
+		}
+	}
+	return "", errors.New("unexpected prompt")
+}
+
+func citationFiles() []*search.FileContext {
+	return []*search.FileContext{
+		{FilePath: "internal/server/router.go", Chunks: []*search.SearchResult{
+			{FilePath: "internal/server/router.go", StartLine: 10, EndLine: 30, Content: "func NewRouter() {}"},
+			{FilePath: "internal/server/router.go", StartLine: 50, EndLine: 70, Content: "func authMiddleware() {}"},
+		}},
+		{FilePath: "router.go", Chunks: []*search.SearchResult{
+			{FilePath: "router.go", StartLine: 1, EndLine: 5, Content: "package main"},
+		}},
+	}
+}
+
+func TestSplitSentences(t *testing.T) {
+	answer := "ルーターは `router.go` で定義されています。認証は別です。\n\n```go\nfunc a() { b(). c() }\n```\n- Items are listed. Next item\n"
+
+	assert.Equal(t, []string{
+		"ルーターは `router.go` で定義されています。",
+		"認証は別です。",
+		"- Items are listed.",
+		"Next item",
+	}, splitSentences(answer))
+}
+
+func TestExtractCitations(t *testing.T) {
+	answer := "ルーターは `internal/server/router.go:12-20` で生成されます。" +
+		"認証は internal/server/router.go L55-L60 のミドルウェアで行います。" +
+		"エントリポイントは `router.go` です。" +
+		"設定は `internal/server/router.go:100` にあります。"
+
+	citations := extractCitations(answer, citationFiles())
+
+	require.Len(t, citations, 4)
+	assert.Equal(t, "internal/server/router.go", citations[0].filePath)
+	assert.Equal(t, 12, citations[0].startLine)
+	assert.Equal(t, 20, citations[0].endLine)
+	require.Len(t, citations[0].evidence, 1)
+	assert.Equal(t, 10, citations[0].evidence[0].StartLine)
+
+	assert.Equal(t, 55, citations[1].startLine)
+	require.Len(t, citations[1].evidence, 1)
+	assert.Equal(t, 50, citations[1].evidence[0].StartLine)
+
+	// 長いパスの一部として現れる router.go は重複して拾わない
+	assert.Equal(t, "router.go", citations[2].filePath)
+	assert.Equal(t, "エントリポイントは `router.go` です。", citations[2].sentence)
+	assert.Equal(t, 0, citations[2].startLine)
+	assert.Len(t, citations[2].evidence, 1)
+
+	// コンテキストに含まれない行範囲の引用は根拠なし
+	assert.Equal(t, 100, citations[3].startLine)
+	assert.Empty(t, citations[3].evidence)
+}
+
+func TestParseCitationResponse(t *testing.T) {
+	verdict, confidence, reason, err := ParseCitationResponse("```json\n{\"verdict\": \"Partial\", \"confidence\": 1.5, \"reason\": \" 一部のみ \"}\n```")
+	require.NoError(t, err)
+	assert.Equal(t, CitationPartial, verdict)
+	assert.Equal(t, 1.0, confidence)
+	assert.Equal(t, "一部のみ", reason)
+
+	verdict, _, _, err = ParseCitationResponse(`{"verdict": "maybe", "confidence": 0.8}`)
+	require.NoError(t, err)
+	assert.Equal(t, CitationUnsupported, verdict)
+
+	_, _, _, err = ParseCitationResponse("判定できません")
+	assert.Error(t, err)
+}
+
+func TestVerifyCitations(t *testing.T) {
+	llm := &stubCitationLLM{responses: map[string]string{
+		"internal/server/router.go (L10-L30)": `{"verdict": "supported", "confidence": 0.9, "reason": "NewRouter で生成している"}`,
+		"internal/server/router.go (L50-L70)": `{"verdict": "partial", "confidence": 0.3, "reason": "認証の詳細は読み取れない"}`,
+	}}
+	svc := NewAskService(nil, llm)
+	answer := "ルーターは `internal/server/router.go:12-20` で生成されます。認証は `internal/server/router.go:55` で行います。設定は `internal/server/router.go:100` にあります。"
+
+	checks := svc.verifyCitations(context.Background(), "", answer, citationFiles())
+
+	require.Len(t, checks, 3)
+	assert.Equal(t, CitationSupported, checks[0].Verdict)
+	assert.False(t, checks[0].LowConfidence())
+	assert.Equal(t, CitationPartial, checks[1].Verdict)
+	assert.True(t, checks[1].LowConfidence())
+	assert.Equal(t, "internal/server/router.go:55", checks[1].Location())
+	assert.Equal(t, CitationUnsupported, checks[2].Verdict)
+	assert.Len(t, llm.prompts, 2, "根拠のない引用はLLMで判定しない")
+
+	annotated := AnnotateAnswer(answer, checks)
+	assert.Equal(t, "ルーターは `internal/server/router.go:12-20` で生成されます。"+
+		"認証は `internal/server/router.go:55` で行います。 [要確認: internal/server/router.go:55 は根拠として不十分な可能性があります]"+
+		"設定は `internal/server/router.go:100` にあります。 [要確認: internal/server/router.go:100 は根拠として不十分な可能性があります]", annotated)
+}
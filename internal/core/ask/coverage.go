@@ -0,0 +1,83 @@
+package ask
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+
+	"github.com/jinford/dev-rag/internal/core/search"
+	"github.com/jinford/dev-rag/internal/core/testcoverage"
+)
+
+// coverageFileLimit はプロンプトに含めるカバレッジの低いファイルの上限（関連コードのファイルは別に含める）
+const coverageFileLimit = 10
+
+// coverageKeywords はテストカバレッジに関する質問とみなすキーワード（小文字）
+var coverageKeywords = []string{"カバレッジ", "テスト", "coverage", "untested", "tested", "tests"}
+
+// CoverageLookup はカバレッジレポートから取り込んだファイルごとのテストカバレッジを取得するインターフェース
+type CoverageLookup interface {
+	ListFileCoverage(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID]) ([]*testcoverage.FileCoverage, error)
+}
+
+// WithAskCoverage はテストに関する質問にファイルごとのテストカバレッジをプロンプトに含めるための設定を行う
+func WithAskCoverage(lookup CoverageLookup) AskServiceOption {
+	return func(s *AskService) {
+		s.coverage = lookup
+	}
+}
+
+// isCoverageQuestion はテスト・カバレッジに関する質問かを判定する
+func isCoverageQuestion(query string) bool {
+	lower := strings.ToLower(query)
+	return slices.ContainsFunc(coverageKeywords, func(keyword string) bool {
+		return strings.Contains(lower, keyword)
+	})
+}
+
+// relevantCoverage はテストに関する質問の場合に、関連コードのファイルとカバレッジの低いファイルのテストカバレッジを取得する
+// カバレッジの低い順（同率は計測対象の多い順）に並べる。カバレッジは補助的な情報のため、取得に失敗した場合は含めずに続行する
+func (s *AskService) relevantCoverage(ctx context.Context, params AskParams, files []*search.FileContext) []*testcoverage.FileCoverage {
+	if s.coverage == nil || !isCoverageQuestion(params.Query) {
+		return nil
+	}
+
+	coverages, err := s.coverage.ListFileCoverage(ctx, params.ProductID, params.SnapshotID)
+	if err != nil {
+		s.logger.Warn("failed to look up test coverage", "error", err)
+		return nil
+	}
+	coverages = slices.DeleteFunc(coverages, func(c *testcoverage.FileCoverage) bool {
+		return c.Statements == 0
+	})
+	slices.SortStableFunc(coverages, func(a, b *testcoverage.FileCoverage) int {
+		if c := cmp.Compare(a.Percent(), b.Percent()); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.Statements, a.Statements)
+	})
+
+	related := make(map[uuid.UUID]bool, len(files))
+	for _, file := range files {
+		related[file.FileID] = true
+	}
+	var selected []*testcoverage.FileCoverage
+	lowest := 0
+	for _, c := range coverages {
+		switch {
+		case related[c.FileID]:
+			selected = append(selected, c)
+		case lowest < coverageFileLimit:
+			lowest++
+			selected = append(selected, c)
+		}
+	}
+	if len(selected) > 0 {
+		s.logger.Info("injected test coverage", "files", len(selected))
+	}
+	return selected
+}
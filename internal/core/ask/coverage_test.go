@@ -0,0 +1,57 @@
+package ask
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jinford/dev-rag/internal/core/search"
+	"github.com/jinford/dev-rag/internal/core/testcoverage"
+)
+
+type stubCoverageLookup struct {
+	coverages []*testcoverage.FileCoverage
+	calls     int
+}
+
+func (l *stubCoverageLookup) ListFileCoverage(_ context.Context, _, _ mo.Option[uuid.UUID]) ([]*testcoverage.FileCoverage, error) {
+	l.calls++
+	return l.coverages, nil
+}
+
+func TestAskService_RelevantCoverage(t *testing.T) {
+	related := &testcoverage.FileCoverage{FileID: uuid.New(), FilePath: "internal/user/service.go", Statements: 40, Covered: 38}
+	lookup := &stubCoverageLookup{coverages: []*testcoverage.FileCoverage{related}}
+	for i := range coverageFileLimit + 2 {
+		lookup.coverages = append(lookup.coverages, &testcoverage.FileCoverage{FileID: uuid.New(), FilePath: fmt.Sprintf("pkg/f%02d.go", i), Statements: 10, Covered: i})
+	}
+	lookup.coverages = append(lookup.coverages, &testcoverage.FileCoverage{FileID: uuid.New(), FilePath: "empty.go"})
+	svc := NewAskService(nil, nil, WithAskCoverage(lookup))
+	files := []*search.FileContext{{FileID: related.FileID, FilePath: related.FilePath}}
+
+	coverages := svc.relevantCoverage(context.Background(), AskParams{ProductID: mo.Some(uuid.New()), Query: "テストされていない箇所は？"}, files)
+
+	require.Len(t, coverages, coverageFileLimit+1, "関連コードのファイルはカバレッジの低いファイルの上限と別に含める")
+	assert.Equal(t, "pkg/f00.go", coverages[0].FilePath)
+	assert.Equal(t, related, coverages[len(coverages)-1])
+	assert.NotContains(t, coverages, lookup.coverages[len(lookup.coverages)-1], "計測対象のないファイルは含めない")
+
+	assert.Nil(t, svc.relevantCoverage(context.Background(), AskParams{Query: "How does login work?"}, files))
+	assert.Equal(t, 1, lookup.calls, "テストに関する質問でなければカバレッジを取得しない")
+}
+
+func TestBuildAskPrompt_Coverage(t *testing.T) {
+	prompt := BuildAskPrompt("untested areas?", nil, nil, nil, nil, nil, nil, []*testcoverage.FileCoverage{
+		{SourceName: "backend", FilePath: "internal/user/service.go", Statements: 16, Covered: 2},
+	}, "")
+	assert.Contains(t, prompt, "## コンテキスト: テストカバレッジ\n")
+	assert.Contains(t, prompt, "- internal/user/service.go（ソース: backend）: 12.5%（2/16）\n")
+
+	prompt = BuildAskPrompt("質問", nil, nil, nil, nil, nil, nil, nil, "")
+	assert.NotContains(t, prompt, "テストカバレッジ")
+}
@@ -0,0 +1,80 @@
+package ask
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+
+	"github.com/jinford/dev-rag/internal/core/decision"
+	"github.com/jinford/dev-rag/internal/core/search"
+)
+
+const (
+	// acceptedDecisionBoost は採用済みの設計判断の記録（ADR）のチャンクのスコアに掛ける係数
+	acceptedDecisionBoost = 1.2
+	// inactiveDecisionPenalty は却下・非推奨・置き換え済みの設計判断の記録のチャンクのスコアに掛ける係数
+	inactiveDecisionPenalty = 0.7
+)
+
+// DecisionLookup はファイルが設計判断の記録（ADR・RFC）であればそのステータスを取得するインターフェース
+type DecisionLookup interface {
+	// DecisionStatuses はファイルIDごとのステータスを返す（設計判断の記録でないファイルは含まれない）
+	DecisionStatuses(ctx context.Context, fileIDs []uuid.UUID) (map[uuid.UUID]decision.Status, error)
+}
+
+// BiasDecisionRecords は設計判断の記録のステータスに応じてチャンクのスコアを補正し、スコア順に並べ直す
+// 採用済みの決定を優先し、現在は有効でない決定（却下・非推奨・置き換え済み）を後ろに回す
+func BiasDecisionRecords(chunks []*search.SearchResult, statuses map[uuid.UUID]decision.Status) []*search.SearchResult {
+	if len(statuses) == 0 {
+		return chunks
+	}
+
+	biased := false
+	for _, chunk := range chunks {
+		status, ok := statuses[chunk.FileID]
+		if !ok {
+			continue
+		}
+		switch {
+		case status.Active():
+			chunk.Score *= acceptedDecisionBoost
+			biased = true
+		case status.Inactive():
+			chunk.Score *= inactiveDecisionPenalty
+			biased = true
+		}
+	}
+	if biased {
+		slices.SortStableFunc(chunks, func(a, b *search.SearchResult) int {
+			return cmp.Compare(b.Score, a.Score)
+		})
+	}
+	return chunks
+}
+
+// biasDecisionRecords は検索結果に含まれる設計判断の記録のステータスを取得してスコアを補正する
+// 補正は補助的な処理のため、取得に失敗した場合は検索結果をそのまま返す
+func (s *AskService) biasDecisionRecords(ctx context.Context, chunks []*search.SearchResult) []*search.SearchResult {
+	if s.decisions == nil || len(chunks) == 0 {
+		return chunks
+	}
+
+	fileIDs := make([]uuid.UUID, 0, len(chunks))
+	for _, chunk := range chunks {
+		if !slices.Contains(fileIDs, chunk.FileID) {
+			fileIDs = append(fileIDs, chunk.FileID)
+		}
+	}
+
+	statuses, err := s.decisions.DecisionStatuses(ctx, fileIDs)
+	if err != nil {
+		s.logger.Warn("failed to look up decision records", "error", err)
+		return chunks
+	}
+	if len(statuses) > 0 {
+		s.logger.Info("biased chunks by decision record status", "decisionFiles", len(statuses))
+	}
+	return BiasDecisionRecords(chunks, statuses)
+}
@@ -0,0 +1,54 @@
+package ask
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jinford/dev-rag/internal/core/decision"
+	"github.com/jinford/dev-rag/internal/core/search"
+)
+
+func TestBiasDecisionRecords(t *testing.T) {
+	code, accepted, superseded, proposed := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	chunks := []*search.SearchResult{
+		{FileID: superseded, FilePath: "docs/adr/0001-use-mysql.md", Score: 0.9},
+		{FileID: code, FilePath: "main.go", Score: 0.8},
+		{FileID: proposed, FilePath: "docs/adr/0003-use-grpc.md", Score: 0.75},
+		{FileID: accepted, FilePath: "docs/adr/0002-use-postgres.md", Score: 0.7},
+	}
+	statuses := map[uuid.UUID]decision.Status{
+		accepted:   decision.StatusAccepted,
+		superseded: decision.StatusSuperseded,
+		proposed:   decision.StatusProposed,
+	}
+
+	got := BiasDecisionRecords(chunks, statuses)
+
+	require.Len(t, got, 4)
+	paths := []string{got[0].FilePath, got[1].FilePath, got[2].FilePath, got[3].FilePath}
+	assert.Equal(t, []string{
+		"docs/adr/0002-use-postgres.md",
+		"main.go",
+		"docs/adr/0003-use-grpc.md",
+		"docs/adr/0001-use-mysql.md",
+	}, paths)
+	assert.InDelta(t, 0.84, got[0].Score, 1e-9)
+	assert.InDelta(t, 0.75, got[2].Score, 1e-9)
+	assert.InDelta(t, 0.63, got[3].Score, 1e-9)
+}
+
+func TestBiasDecisionRecords_NoDecisionRecords(t *testing.T) {
+	chunks := []*search.SearchResult{
+		{FileID: uuid.New(), Score: 0.5},
+		{FileID: uuid.New(), Score: 0.9},
+	}
+
+	got := BiasDecisionRecords(chunks, nil)
+
+	// ステータスがなければ順序もスコアも変えない
+	assert.Equal(t, 0.5, got[0].Score)
+	assert.Equal(t, 0.9, got[1].Score)
+}
@@ -0,0 +1,63 @@
+package ask
+
+import (
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+
+	"github.com/jinford/dev-rag/internal/core/discussion"
+	"github.com/jinford/dev-rag/internal/core/search"
+)
+
+// discussionLimit はプロンプトに含める議論（Issue・Pull Request）の上限
+const discussionLimit = 5
+
+// DiscussionLookup はファイルパスに言及した議論（Issue・Pull Request）を取得するインターフェース
+type DiscussionLookup interface {
+	// DiscussionsForPaths は質問の範囲のプロダクトで、paths のいずれかに言及した議論を新しい順に返す
+	DiscussionsForPaths(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID], paths []string, limit int) ([]*discussion.Record, error)
+}
+
+// WithAskDiscussions は関連コードのファイルに言及した議論（変更の経緯）をプロンプトに含めるための設定を行う
+func WithAskDiscussions(lookup DiscussionLookup) AskServiceOption {
+	return func(s *AskService) {
+		s.discussions = lookup
+	}
+}
+
+// relatedDiscussions はプロンプトに含める関連コードのファイルに言及した議論を取得する
+// 議論は補助的な情報のため、取得に失敗した場合やインジェクションを含む議論は除いて続行する
+func (s *AskService) relatedDiscussions(ctx context.Context, params AskParams, files []*search.FileContext) []*discussion.Record {
+	if s.discussions == nil || len(files) == 0 || (params.ProductID.IsAbsent() && params.SnapshotID.IsAbsent()) {
+		return nil
+	}
+
+	paths := make([]string, 0, len(files))
+	for _, file := range files {
+		paths = append(paths, file.FilePath)
+	}
+
+	records, err := s.discussions.DiscussionsForPaths(ctx, params.ProductID, params.SnapshotID, paths, discussionLimit)
+	if err != nil {
+		s.logger.Warn("failed to look up discussions", "error", err)
+		return nil
+	}
+	// 議論の文書そのものが検索結果に含まれる場合は関連コードとして示されるため除く
+	records = slices.DeleteFunc(records, func(r *discussion.Record) bool {
+		return slices.ContainsFunc(files, func(f *search.FileContext) bool {
+			return f.FileID == r.FileID
+		}) || (s.guardrail != nil && s.guardrail.DetectInjection(r.Title+"\n"+r.Summary))
+	})
+	// プロンプトには関連コードのファイルへの言及のみを示す
+	for _, r := range records {
+		r.Paths = slices.DeleteFunc(r.Paths, func(p string) bool {
+			return !slices.Contains(paths, p)
+		})
+	}
+	if len(records) > 0 {
+		s.logger.Info("injected related discussions", "discussions", len(records))
+	}
+	return records
+}
@@ -0,0 +1,65 @@
+package ask
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+)
+
+// AnswerOriginSlack は回答した経路（ask_answers.origin）としての Slack
+const AnswerOriginSlack = "slack"
+
+// Rating は回答へのユーザーの評価
+type Rating int
+
+const (
+	RatingDown Rating = -1 // 役に立たなかった（👎）
+	RatingUp   Rating = 1  // 役に立った（👍）
+)
+
+// RecordedAnswer はチャットで回答した質問（リアクションによる評価の対象）
+type RecordedAnswer struct {
+	ID            uuid.UUID
+	ProductID     uuid.UUID
+	Origin        string // 回答した経路（slack）
+	ChannelID     string // 回答を投稿したチャンネル
+	MessageTS     string // 回答のメッセージの識別子（Slack の ts）
+	UserID        string // 質問したユーザー
+	Query         string
+	Answer        string
+	Refused       bool
+	PromptVersion string
+	CreatedAt     time.Time
+
+	UpCount   int64 // 👍 の数（ListRatedAnswers のみ）
+	DownCount int64 // 👎 の数（ListRatedAnswers のみ）
+}
+
+// NewRecordedAnswer は質問応答の結果から記録する回答を作成する
+func NewRecordedAnswer(productID uuid.UUID, origin, channelID, messageTS, userID, query string, result *AskResult) *RecordedAnswer {
+	return &RecordedAnswer{
+		ProductID:     productID,
+		Origin:        origin,
+		ChannelID:     channelID,
+		MessageTS:     messageTS,
+		UserID:        userID,
+		Query:         query,
+		Answer:        result.Answer,
+		Refused:       result.Refused,
+		PromptVersion: result.PromptVersion,
+	}
+}
+
+// FeedbackStore は回答とリアクションによる評価を永続化するインターフェース
+type FeedbackStore interface {
+	RecordAnswer(ctx context.Context, answer *RecordedAnswer) (*RecordedAnswer, error)
+	GetAnswerByMessage(ctx context.Context, origin, channelID, messageTS string) (mo.Option[*RecordedAnswer], error)
+	// SetRating はユーザーの評価を保存する（同じユーザーの評価は上書きする）
+	SetRating(ctx context.Context, answerID uuid.UUID, userID string, rating Rating) error
+	// ClearRating はユーザーの評価が rating の場合に削除する
+	ClearRating(ctx context.Context, answerID uuid.UUID, userID string, rating Rating) error
+	// ListRatedAnswers は評価の付いた回答を評価の低い順に返す
+	ListRatedAnswers(ctx context.Context, productID uuid.UUID, limit int) ([]*RecordedAnswer, error)
+}
@@ -0,0 +1,317 @@
+package ask
+
+import (
+	"log/slog"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/jinford/dev-rag/internal/core/search"
+)
+
+// GuardrailReason はガードレールが作動した理由を表す
+type GuardrailReason string
+
+const (
+	// GuardrailReasonPromptInjection は質問文にプロンプトインジェクションが検出されたことを表す
+	GuardrailReasonPromptInjection GuardrailReason = "prompt_injection"
+	// GuardrailReasonOutOfScope はプロダクトに関係しない質問と判定されたことを表す
+	GuardrailReasonOutOfScope GuardrailReason = "out_of_scope"
+	// GuardrailReasonContextSanitized は検索結果に含まれる指示文を除去したことを表す
+	GuardrailReasonContextSanitized GuardrailReason = "context_sanitized"
+	// GuardrailReasonNotFound は検索結果の関連度がいずれも閾値に届かなかったことを表す
+	GuardrailReasonNotFound GuardrailReason = "not_found"
+)
+
+const (
+	// RefusalMessagePromptInjection はインジェクション検出時の回答文
+	RefusalMessagePromptInjection = "申し訳ありませんが、この質問にはお答えできません。システムへの指示を変更しようとする内容が含まれているため、質問を言い換えてお試しください。"
+	// RefusalMessageOutOfScope は範囲外の質問に対する回答文
+	RefusalMessageOutOfScope = "申し訳ありませんが、この質問は対象プロダクトのコードベースに関する情報から回答できません。プロダクトの実装や設計に関する質問をお試しください。"
+	// NoAnswerMessage はインデックス済みのソースに回答の根拠が見つからない場合の回答文
+	// 関連度が閾値に届かない場合の拒否と、プロンプトの回答のガイドラインの両方で使用する
+	NoAnswerMessage = "インデックス済みのソースには該当する情報が見つかりませんでした。"
+
+	// sanitizedPlaceholder は除去した指示文の代わりに埋め込む文字列
+	sanitizedPlaceholder = "[除去された指示文]"
+)
+
+// DefaultScopeMinScore は検索結果の関連度スコアの最大値がこの値に届かない質問を範囲外とみなす既定の閾値
+// 質問がプロダクトと無関係な場合も近傍のチャンクは見つかるため、件数ではなく関連度で判定する
+const DefaultScopeMinScore = 0.2
+
+// defaultInjectionPatterns はプロンプトインジェクションとみなす既定のパターン
+var defaultInjectionPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)\b(ignore|disregard|forget|override)\s+(all\s+|any\s+)?(the\s+|your\s+)?(previous|prior|above|earlier|preceding|system)\s+(instructions?|prompts?|rules|messages?|directions)`),
+	regexp.MustCompile(`(?i)\b(reveal|show|print|repeat|output)\s+(me\s+)?(your|the)\s+(system\s+prompt|hidden\s+instructions|initial\s+instructions)`),
+	regexp.MustCompile(`(?i)\byou\s+are\s+no\s+longer\b`),
+	regexp.MustCompile(`(?i)<\s*/?\s*(system|im_start|im_end)\s*>`),
+	regexp.MustCompile(`(以前|前|上記|これまで|先ほど)の(すべての|全ての)?(指示|命令|プロンプト|ルール)を(無視|忘れ|破棄)`),
+	regexp.MustCompile(`システムプロンプトを(表示|出力|教え|開示)`),
+}
+
+// roleMarkerPattern は会話の役割（system・assistant）を装う行のパターン
+// YAML・JSON・コードには同じ形の行（例: system: の設定キー）が正当に含まれるため、検索結果には適用せず質問文だけに適用する
+var roleMarkerPattern = regexp.MustCompile(`(?im)^\s*(system|assistant)\s*:\s*`)
+
+// GuardrailStats はガードレールの作動回数を集計する
+type GuardrailStats struct {
+	counts sync.Map // GuardrailReason -> *atomic.Int64
+}
+
+// record は作動回数を1加算する
+func (s *GuardrailStats) record(reason GuardrailReason) {
+	v, _ := s.counts.LoadOrStore(reason, new(atomic.Int64))
+	v.(*atomic.Int64).Add(1)
+}
+
+// Snapshot は現時点の作動回数を理由ごとに返す
+func (s *GuardrailStats) Snapshot() map[GuardrailReason]int64 {
+	result := make(map[GuardrailReason]int64)
+	s.counts.Range(func(key, value any) bool {
+		result[key.(GuardrailReason)] = value.(*atomic.Int64).Load()
+		return true
+	})
+	return result
+}
+
+// Guardrail は質問文と検索結果に対する入力ガードレールを提供する
+type Guardrail struct {
+	injectionPatterns []*regexp.Regexp
+	scopeMinScore     float64
+	stats             *GuardrailStats
+	logger            *slog.Logger
+}
+
+type GuardrailOption func(*Guardrail)
+
+// WithGuardrailLogger は Guardrail にロガーを設定する
+func WithGuardrailLogger(logger *slog.Logger) GuardrailOption {
+	return func(g *Guardrail) {
+		g.logger = logger
+	}
+}
+
+// WithGuardrailPatterns は既定パターンに追加のインジェクションパターンを登録する
+func WithGuardrailPatterns(patterns ...*regexp.Regexp) GuardrailOption {
+	return func(g *Guardrail) {
+		g.injectionPatterns = append(g.injectionPatterns, patterns...)
+	}
+}
+
+// WithGuardrailScopeMinScore は質問を範囲外とみなす検索結果の関連度スコアの閾値を設定する
+// 0 の場合は検索結果が1件もない場合のみ範囲外とみなす
+func WithGuardrailScopeMinScore(score float64) GuardrailOption {
+	return func(g *Guardrail) {
+		g.scopeMinScore = score
+	}
+}
+
+// NewGuardrail は既定パターンを持つ Guardrail を作成する
+func NewGuardrail(opts ...GuardrailOption) *Guardrail {
+	g := &Guardrail{
+		injectionPatterns: append([]*regexp.Regexp(nil), defaultInjectionPatterns...),
+		scopeMinScore:     DefaultScopeMinScore,
+		stats:             &GuardrailStats{},
+		logger:            slog.Default(),
+	}
+
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	if g.logger == nil {
+		g.logger = slog.Default()
+	}
+
+	return g
+}
+
+// Stats はガードレールの作動回数を返す
+func (g *Guardrail) Stats() *GuardrailStats {
+	return g.stats
+}
+
+// DetectInjection はテキストにプロンプトインジェクションのパターンが含まれるかを判定する
+func (g *Guardrail) DetectInjection(text string) bool {
+	for _, pattern := range g.injectionPatterns {
+		if pattern.MatchString(text) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckQuery は質問文を検査し、拒否すべき場合は理由を返す
+// 質問文では会話の役割を装う行もインジェクションとみなす
+func (g *Guardrail) CheckQuery(query string) (GuardrailReason, bool) {
+	if g.DetectInjection(query) || roleMarkerPattern.MatchString(query) {
+		g.activate(GuardrailReasonPromptInjection, "prompt injection detected in query")
+		return GuardrailReasonPromptInjection, true
+	}
+	return "", false
+}
+
+// CheckScope は検索結果から質問がプロダクトの範囲外かどうかを判定する
+// チャンクも要約も見つからない場合と、関連度スコアの最大値が範囲外の閾値に届かない場合は範囲外とみなす
+func (g *Guardrail) CheckScope(summaries []*search.SummarySearchResult, chunks []*search.SearchResult) (GuardrailReason, bool) {
+	if len(summaries) == 0 && len(chunks) == 0 {
+		g.activate(GuardrailReasonOutOfScope, "no relevant context found for query")
+		return GuardrailReasonOutOfScope, true
+	}
+	if best := bestScore(summaries, chunks); best < g.scopeMinScore {
+		g.activate(GuardrailReasonOutOfScope, "no retrieved context reached the scope threshold", "bestScore", best, "scopeMinScore", g.scopeMinScore)
+		return GuardrailReasonOutOfScope, true
+	}
+	return "", false
+}
+
+// CheckRelevance は検索結果の関連度スコアの最大値が minRelevance に届かない場合に拒否理由を返す
+// minRelevance が 0 以下の場合は判定しない
+func (g *Guardrail) CheckRelevance(summaries []*search.SummarySearchResult, chunks []*search.SearchResult, minRelevance float64) (GuardrailReason, bool) {
+	if minRelevance <= 0 {
+		return "", false
+	}
+	if best := bestScore(summaries, chunks); best < minRelevance {
+		g.activate(GuardrailReasonNotFound, "no retrieved context reached the relevance threshold", "bestScore", best, "minRelevance", minRelevance)
+		return GuardrailReasonNotFound, true
+	}
+	return "", false
+}
+
+// bestScore は検索結果（チャンク・要約）の関連度スコアの最大値を返す
+func bestScore(summaries []*search.SummarySearchResult, chunks []*search.SearchResult) float64 {
+	best := 0.0
+	for _, s := range summaries {
+		best = max(best, s.Score)
+	}
+	for _, c := range chunks {
+		best = max(best, c.Score)
+	}
+	return best
+}
+
+// SanitizeContext は検索結果に含まれる指示文を除去した複製を返す
+// 元の検索結果は変更しない
+func (g *Guardrail) SanitizeContext(
+	summaries []*search.SummarySearchResult,
+	chunks []*search.SearchResult,
+) ([]*search.SummarySearchResult, []*search.SearchResult) {
+	sanitized := 0
+
+	outSummaries := make([]*search.SummarySearchResult, 0, len(summaries))
+	for _, s := range summaries {
+		content, changed := g.sanitizeText(s.Content)
+		if changed {
+			sanitized++
+			copied := *s
+			copied.Content = content
+			s = &copied
+		}
+		outSummaries = append(outSummaries, s)
+	}
+
+	outChunks := make([]*search.SearchResult, 0, len(chunks))
+	for _, c := range chunks {
+		content, changed := g.sanitizeText(c.Content)
+		if changed {
+			sanitized++
+			copied := *c
+			copied.Content = content
+			c = &copied
+		}
+		outChunks = append(outChunks, c)
+	}
+
+	if sanitized > 0 {
+		g.activate(GuardrailReasonContextSanitized, "instructions removed from retrieved context", "items", sanitized)
+	}
+
+	return outSummaries, outChunks
+}
+
+// SanitizeFiles はファイル単位にまとめたコンテキストから指示文を除去した複製を返す
+// 元のコンテキストは変更しない
+func (g *Guardrail) SanitizeFiles(files []*search.FileContext) []*search.FileContext {
+	sanitized := 0
+
+	out := make([]*search.FileContext, 0, len(files))
+	for _, f := range files {
+		copied := *f
+		if f.FileSummary != nil {
+			if content, changed := g.sanitizeText(*f.FileSummary); changed {
+				sanitized++
+				copied.FileSummary = &content
+			}
+		}
+		copied.Chunks = make([]*search.SearchResult, 0, len(f.Chunks))
+		for _, c := range f.Chunks {
+			if content, changed := g.sanitizeText(c.Content); changed {
+				sanitized++
+				chunk := *c
+				chunk.Content = content
+				c = &chunk
+			}
+			copied.Chunks = append(copied.Chunks, c)
+		}
+		out = append(out, &copied)
+	}
+
+	if sanitized > 0 {
+		g.activate(GuardrailReasonContextSanitized, "instructions removed from retrieved context", "items", sanitized)
+	}
+
+	return out
+}
+
+// sanitizeText はテキスト中の指示文をプレースホルダーに置き換える
+func (g *Guardrail) sanitizeText(text string) (string, bool) {
+	changed := false
+	for _, pattern := range g.injectionPatterns {
+		if pattern.MatchString(text) {
+			text = pattern.ReplaceAllString(text, sanitizedPlaceholder)
+			changed = true
+		}
+	}
+	return text, changed
+}
+
+// activate はガードレールの作動を記録する
+func (g *Guardrail) activate(reason GuardrailReason, msg string, args ...any) {
+	g.stats.record(reason)
+	g.logger.Warn("guardrail activated: "+msg, append([]any{"reason", string(reason)}, args...)...)
+}
+
+// RefusalMessage は拒否理由に応じた回答文を返す
+func RefusalMessage(reason GuardrailReason) string {
+	switch reason {
+	case GuardrailReasonPromptInjection:
+		return RefusalMessagePromptInjection
+	case GuardrailReasonNotFound:
+		return NoAnswerMessage
+	default:
+		return RefusalMessageOutOfScope
+	}
+}
+
+// codeFence はコンテンツ内のバッククォート列より長いフェンスを返す
+// 検索結果がフェンスを閉じてプロンプト構造を崩すことを防ぐ
+func codeFence(content string) string {
+	longest := 0
+	current := 0
+	for _, r := range content {
+		if r == '`' {
+			current++
+			if current > longest {
+				longest = current
+			}
+			continue
+		}
+		current = 0
+	}
+	if longest < 3 {
+		return "```"
+	}
+	return strings.Repeat("`", longest+1)
+}
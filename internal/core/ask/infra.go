@@ -0,0 +1,50 @@
+package ask
+
+import (
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+
+	"github.com/jinford/dev-rag/internal/core/infraspec"
+)
+
+// infraResourceLimit はプロンプトに含めるデプロイ構成のリソースの上限
+const infraResourceLimit = 10
+
+// InfraLookup はインデックス化時に抽出したデプロイ構成のリソースを取得するインターフェース
+type InfraLookup interface {
+	ListInfraResources(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID]) ([]*infraspec.Resource, error)
+}
+
+// WithAskInfra はインフラに関する質問にデプロイ構成（イメージ・ポート・環境変数・リソース制限）をプロンプトに含めるための設定を行う
+func WithAskInfra(lookup InfraLookup) AskServiceOption {
+	return func(s *AskService) {
+		s.infra = lookup
+	}
+}
+
+// relevantInfraResources は質問の範囲のデプロイ構成から、質問に関連するリソースを取得する
+// デプロイ構成は補助的な情報のため、取得に失敗した場合やインジェクションを含むリソースは除いて続行する
+func (s *AskService) relevantInfraResources(ctx context.Context, params AskParams) []*infraspec.Resource {
+	if s.infra == nil || (params.ProductID.IsAbsent() && params.SnapshotID.IsAbsent()) {
+		return nil
+	}
+
+	resources, err := s.infra.ListInfraResources(ctx, params.ProductID, params.SnapshotID)
+	if err != nil {
+		s.logger.Warn("failed to look up infra resources", "error", err)
+		return nil
+	}
+	resources = infraspec.MatchResources(resources, params.Query, infraResourceLimit)
+	if s.guardrail != nil {
+		resources = slices.DeleteFunc(resources, func(r *infraspec.Resource) bool {
+			return s.guardrail.DetectInjection(infraspec.Describe(r))
+		})
+	}
+	if len(resources) > 0 {
+		s.logger.Info("injected infra resources", "resources", len(resources))
+	}
+	return resources
+}
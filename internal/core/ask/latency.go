@@ -0,0 +1,156 @@
+package ask
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jinford/dev-rag/internal/core/llm"
+)
+
+// DegradedStage は所要時間の予算を超えたために省略・打ち切った処理
+type DegradedStage string
+
+const (
+	DegradedRerank               DegradedStage = "rerank"                // 設計判断の記録のステータスによる検索結果の並べ替え
+	DegradedDependencyExpansion  DegradedStage = "dependency_expansion"  // 依存関係による検索結果の展開
+	DegradedContextEnrichment    DegradedStage = "context_enrichment"    // 用語・テーブル定義・デプロイ構成・議論・カバレッジの追加
+	DegradedAnswer               DegradedStage = "answer"                // 回答の生成（関連するソースのみを返す）
+	DegradedCitationVerification DegradedStage = "citation_verification" // 引用の検証
+)
+
+// AnswerTimeoutMessage は回答の生成が予算内に完了せず、途中までの回答もない場合の応答
+const AnswerTimeoutMessage = "回答の生成が制限時間内に完了しなかったため、質問に関連するソースのみを示します。"
+
+// AnswerTruncatedNote は回答の生成が予算内に完了しなかった場合に、途中までの回答の末尾に付ける注記
+const AnswerTruncatedNote = "（回答の生成が制限時間内に完了しなかったため、途中までの回答です。関連するソースを併せて確認してください。）"
+
+const (
+	// retrievalBudgetShare は検索に充てる予算の割合（超えた場合は補助的な処理を省略して回答の生成に残りを充てる）
+	retrievalBudgetShare = 0.5
+	// minAnswerBudgetShare は検索で予算を使い切った場合にも回答の生成に充てる予算の割合
+	minAnswerBudgetShare = 0.25
+)
+
+// WithAskLatencyBudget は質問応答の所要時間の予算を設定する（0 の場合は制限しない）
+// 検索が予算の半分を超えた場合は並べ替え・依存関係の展開・補助情報の追加を省略し、
+// 回答の生成が予算を超えた場合は生成を打ち切って途中までの回答（ない場合は関連するソースのみ）を返す
+func WithAskLatencyBudget(budget time.Duration) AskServiceOption {
+	return func(s *AskService) {
+		s.latencyBudget = budget
+	}
+}
+
+// latencyBudget は1回の質問応答の所要時間の予算
+type latencyBudget struct {
+	start time.Time
+	total time.Duration
+}
+
+func newLatencyBudget(total time.Duration) latencyBudget {
+	return latencyBudget{start: time.Now(), total: total}
+}
+
+// retrievalExceeded は検索までに予算の retrievalBudgetShare を超えたかを返す
+func (b latencyBudget) retrievalExceeded(now time.Time) bool {
+	return b.total > 0 && now.Sub(b.start) > time.Duration(float64(b.total)*retrievalBudgetShare)
+}
+
+// exceeded は予算を使い切ったかを返す
+func (b latencyBudget) exceeded(now time.Time) bool {
+	return b.total > 0 && now.Sub(b.start) > b.total
+}
+
+// answerDeadline は回答の生成の期限を返す（予算の残りが少ない場合も minAnswerBudgetShare は確保する）
+func (b latencyBudget) answerDeadline(now time.Time) time.Time {
+	deadline := b.start.Add(b.total)
+	if floor := now.Add(time.Duration(float64(b.total) * minAnswerBudgetShare)); floor.After(deadline) {
+		return floor
+	}
+	return deadline
+}
+
+// answerContext は回答の生成に期限を設定したコンテキストを返す（予算を設定していない場合は ctx のまま）
+func (b latencyBudget) answerContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if b.total <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithDeadline(ctx, b.answerDeadline(time.Now()))
+}
+
+// generatedAnswer は予算内で生成した回答
+type generatedAnswer struct {
+	Text      string
+	TimedOut  bool // 予算を超えて生成を打ち切ったか
+	Truncated bool // 打ち切った場合に、途中までの回答を返すか（false の場合は AnswerTimeoutMessage）
+}
+
+// generateAnswer は予算の残りを期限として回答を生成する
+// 期限を過ぎた場合は生成を打ち切り、ストリーミングで受け取った途中までの回答（ない場合は AnswerTimeoutMessage）を返す
+func (s *AskService) generateAnswer(ctx context.Context, budget latencyBudget, model, promptText string) (*generatedAnswer, error) {
+	answerCtx, cancel := budget.answerContext(ctx)
+	defer cancel()
+	var partial partialAnswer
+	if budget.total > 0 {
+		answerCtx = llm.WithPartialOutput(answerCtx, &partial)
+	}
+
+	text, err := s.llm.GenerateCompletion(llm.WithModel(answerCtx, model), promptText)
+	if answerTimedOut(ctx, answerCtx, err) {
+		generated := &generatedAnswer{TimedOut: true, Text: AnswerTimeoutMessage}
+		if truncated, ok := partial.truncated(); ok {
+			generated.Text, generated.Truncated = truncated, true
+		}
+		s.logger.Warn("answer generation exceeded latency budget",
+			"elapsed", time.Since(budget.start),
+			"budget", budget.total,
+			"truncated", generated.Truncated,
+		)
+		return generated, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate answer: %w", err)
+	}
+	return &generatedAnswer{Text: text}, nil
+}
+
+// partialAnswer は回答の生成中に受け取った途中までの出力を保持する
+type partialAnswer struct {
+	mu      sync.Mutex
+	content strings.Builder
+}
+
+var _ llm.PartialOutput = (*partialAnswer)(nil)
+
+// Write は生成中の出力を追加する
+func (p *partialAnswer) Write(delta string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.content.WriteString(delta)
+}
+
+// Reset は生成をやり直す前にそれまでの出力を破棄する
+func (p *partialAnswer) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.content.Reset()
+}
+
+// truncated は途中までの回答に打ち切ったことを示す注記を付けて返す（出力がない場合は ok が false）
+func (p *partialAnswer) truncated() (answer string, ok bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	content := strings.TrimRight(p.content.String(), " \t\n")
+	if strings.TrimSpace(content) == "" {
+		return "", false
+	}
+	return content + "\n\n" + AnswerTruncatedNote, true
+}
+
+// answerTimedOut は回答の生成の失敗が予算による打ち切りかを返す（呼び出し元のキャンセル・期限は含まない）
+func answerTimedOut(ctx, answerCtx context.Context, err error) bool {
+	return ctx.Err() == nil && errors.Is(answerCtx.Err(), context.DeadlineExceeded) && err != nil
+}
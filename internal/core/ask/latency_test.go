@@ -0,0 +1,101 @@
+package ask
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jinford/dev-rag/internal/core/llm"
+)
+
+func TestLatencyBudget(t *testing.T) {
+	start := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	budget := latencyBudget{start: start, total: 10 * time.Second}
+
+	assert.False(t, budget.retrievalExceeded(start.Add(4*time.Second)))
+	assert.True(t, budget.retrievalExceeded(start.Add(6*time.Second)), "検索が予算の半分を超えた")
+	assert.False(t, budget.exceeded(start.Add(9*time.Second)))
+	assert.True(t, budget.exceeded(start.Add(11*time.Second)))
+
+	// 予算の残りで回答を生成する
+	assert.Equal(t, start.Add(10*time.Second), budget.answerDeadline(start.Add(3*time.Second)))
+	// 残りが少ない場合も予算の 1/4 は確保する
+	assert.Equal(t, start.Add(11500*time.Millisecond), budget.answerDeadline(start.Add(9*time.Second)))
+
+	// 予算を設定していない場合は制限しない
+	unlimited := latencyBudget{start: start}
+	assert.False(t, unlimited.retrievalExceeded(start.Add(time.Hour)))
+	assert.False(t, unlimited.exceeded(start.Add(time.Hour)))
+}
+
+func TestAnswerTimedOut(t *testing.T) {
+	ctx := context.Background()
+	answerCtx, cancel := context.WithDeadline(ctx, time.Now().Add(-time.Second))
+	defer cancel()
+	assert.True(t, answerTimedOut(ctx, answerCtx, context.DeadlineExceeded))
+	assert.False(t, answerTimedOut(ctx, ctx, errors.New("rate limited")), "期限以外の失敗")
+
+	// 呼び出し元のキャンセルは予算による打ち切りとして扱わない
+	canceled, cancelParent := context.WithCancel(ctx)
+	cancelParent()
+	assert.False(t, answerTimedOut(canceled, canceled, context.Canceled))
+}
+
+// streamingLLM は回答の一部をストリーミングで渡したあと、期限まで生成を終えない LLM
+// interrupted は最後の試行の前に途中で失敗した試行の出力（レート制限で再試行する場合など）
+type streamingLLM struct {
+	interrupted [][]string
+	deltas      []string
+}
+
+func (l *streamingLLM) GenerateCompletion(ctx context.Context, _ string) (string, error) {
+	if out, ok := llm.PartialOutputFromContext(ctx); ok {
+		for _, attempt := range append(l.interrupted, l.deltas) {
+			out.Reset()
+			for _, delta := range attempt {
+				out.Write(delta)
+			}
+		}
+	}
+	<-ctx.Done()
+	return "", ctx.Err()
+}
+
+func TestAskService_GenerateAnswer_Truncated(t *testing.T) {
+	ctx := context.Background()
+	svc := NewAskService(nil, &streamingLLM{deltas: []string{"決済のリトライは", "指数バックオフで最大3回", "行います。\n"}})
+
+	generated, err := svc.generateAnswer(ctx, newLatencyBudget(20*time.Millisecond), "", "prompt")
+	require.NoError(t, err)
+	assert.True(t, generated.TimedOut)
+	assert.True(t, generated.Truncated)
+	assert.Equal(t, "決済のリトライは指数バックオフで最大3回行います。\n\n"+AnswerTruncatedNote, generated.Text, "予算を超えた場合は途中までの回答を返す")
+
+	// 再試行した場合は途中で失敗した試行の出力を含めない
+	svc = NewAskService(nil, &streamingLLM{
+		interrupted: [][]string{{"決済のリトライは"}, {"決済のリトライは", "指数"}},
+		deltas:      []string{"決済のリトライは", "指数バックオフで最大3回", "行います。\n"},
+	})
+	generated, err = svc.generateAnswer(ctx, newLatencyBudget(20*time.Millisecond), "", "prompt")
+	require.NoError(t, err)
+	assert.True(t, generated.Truncated)
+	assert.Equal(t, "決済のリトライは指数バックオフで最大3回行います。\n\n"+AnswerTruncatedNote, generated.Text)
+
+	// 途中までの回答もない場合は関連するソースのみを示す
+	svc = NewAskService(nil, &streamingLLM{})
+	generated, err = svc.generateAnswer(ctx, newLatencyBudget(20*time.Millisecond), "", "prompt")
+	require.NoError(t, err)
+	assert.True(t, generated.TimedOut)
+	assert.False(t, generated.Truncated)
+	assert.Equal(t, AnswerTimeoutMessage, generated.Text)
+
+	// 呼び出し元のキャンセルは予算による打ち切りとして扱わない
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = svc.generateAnswer(canceled, newLatencyBudget(time.Minute), "", "prompt")
+	assert.ErrorIs(t, err, context.Canceled)
+}
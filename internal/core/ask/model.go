@@ -3,26 +3,59 @@ package ask
 import (
 	"github.com/google/uuid"
 	"github.com/samber/mo"
+
+	"github.com/jinford/dev-rag/internal/core/search"
 )
 
 // AskParams は質問応答のパラメータを表す
 type AskParams struct {
 	ProductID    mo.Option[uuid.UUID] // プロダクトID
+	SnapshotID   mo.Option[uuid.UUID] // 指定時はこのスナップショットのみを検索（ref 指定時など）
 	Query        string               // ユーザーの質問文
 	ChunkLimit   int                  // チャンク検索の上限（デフォルト: 10）
 	SummaryLimit int                  // 要約検索の上限（デフォルト: 5）
+	Model        string               // 回答生成に使用するモデル（空の場合はクライアントの既定モデル）
+	Domains      []string             // チャンク検索をファイルのドメイン（code / tests / ops / infra / architecture）に限定（空なら全ドメイン）
+	Languages    []string             // チャンク検索をファイルの言語（go / python など）に限定（空なら全言語）
+	PathPrefix   string               // チャンク・要約の検索をパスのプレフィックス（モノレポのサービスのディレクトリなど）に限定（空なら全ファイル）
+
+	// チャンク検索の方式（空の場合は検索サービスの既定値、two-stage でファイル要約から候補ファイルを絞り込む）
+	Strategy search.RetrievalStrategy
+
+	// チャンク検索に使用する追加のEmbeddingモデル（空の場合は主モデル、検索精度の比較用）
+	EmbeddingModel string
+
+	// 依存関係を辿るホップ数（None の場合はサービスの既定値、0 で展開しない）
+	DependencyHops mo.Option[int]
+
+	// 回答の文に付けられた引用が文を裏付けているかをLLMで検証する（引用ごとにLLMを呼び出す）
+	VerifyCitations bool
+
+	// 回答のキャッシュを参照せずに検索・回答を生成する（生成した回答はキャッシュに保存しない）
+	NoCache bool
 }
 
 // AskResult は質問応答の結果を表す
 type AskResult struct {
-	Answer  string            // LLMによる回答
-	Sources []SourceReference // 参照したソース情報
+	Answer        string            // LLMによる回答
+	Sources       []SourceReference // 参照したソース情報
+	Refused       bool              // ガードレールにより回答を拒否したかどうか
+	RefusalReason GuardrailReason   // 拒否理由（Refused が true の場合のみ）
+	Citations     []CitationCheck   // 引用の検証結果（VerifyCitations 指定時のみ）
+	PromptVersion string            // 回答の生成に使用したプロンプトテンプレートのバージョン（拒否した場合は空）
+	Cached        bool              // キャッシュした回答を返したかどうか
+	Degraded      []DegradedStage   // 所要時間の予算を超えたために省略・打ち切った処理
+	Truncated     bool              // 回答の生成を予算で打ち切り、途中までの回答を返したかどうか
 }
 
 // SourceReference は回答の根拠となったソース参照を表す
 type SourceReference struct {
-	FilePath  string  // ファイルパス
-	StartLine int     // 開始行
-	EndLine   int     // 終了行
-	Score     float64 // 関連度スコア
+	FilePath   string   // ファイルパス
+	StartLine  int      // 開始行
+	EndLine    int      // 終了行
+	Breadcrumb *string  // Markdownの見出しの階層（例: Architecture > Deployment > Rollback）
+	PageStart  *int     // PDF・Office文書の開始ページ
+	PageEnd    *int     // PDF・Office文書の終了ページ
+	Owners     []string // CODEOWNERS で定義されたファイルの担当者
+	Score      float64  // 関連度スコア
 }
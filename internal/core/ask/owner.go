@@ -0,0 +1,41 @@
+package ask
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/jinford/dev-rag/internal/core/search"
+)
+
+// OwnerLookup はファイルの担当者（CODEOWNERS で定義されたチーム・ユーザー）を取得するインターフェース
+type OwnerLookup interface {
+	// OwnersForFiles はファイルIDから担当者へのマップを返す（担当者の定義がないファイルは含まない）
+	OwnersForFiles(ctx context.Context, fileIDs []uuid.UUID) (map[uuid.UUID][]string, error)
+}
+
+// WithAskOwners は関連コードのファイルの担当者をプロンプトと参照ソースに含めるための設定を行う
+func WithAskOwners(lookup OwnerLookup) AskServiceOption {
+	return func(s *AskService) {
+		s.owners = lookup
+	}
+}
+
+// fileOwners はプロンプトに含める関連コードのファイルの担当者を取得する
+// 担当者は補助的な情報のため、取得に失敗した場合は担当者なしで続行する
+func (s *AskService) fileOwners(ctx context.Context, files []*search.FileContext) map[uuid.UUID][]string {
+	if s.owners == nil || len(files) == 0 {
+		return nil
+	}
+
+	fileIDs := make([]uuid.UUID, 0, len(files))
+	for _, file := range files {
+		fileIDs = append(fileIDs, file.FileID)
+	}
+	owners, err := s.owners.OwnersForFiles(ctx, fileIDs)
+	if err != nil {
+		s.logger.Warn("failed to look up file owners", "error", err)
+		return nil
+	}
+	return owners
+}
@@ -0,0 +1,61 @@
+package ask
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/jinford/dev-rag/internal/core/productconfig"
+)
+
+// ProductConfigReader はプロダクトごとの設定（関連度の閾値・システムプロンプト）を取得するインターフェース
+type ProductConfigReader interface {
+	List(ctx context.Context, productID uuid.UUID) ([]*productconfig.Entry, error)
+}
+
+// WithAskMinRelevance は検索結果の関連度スコアの最大値がこの値に届かない場合に、回答を生成せず
+// 「インデックス済みのソースには該当する情報が見つかりませんでした」と応答する既定の閾値を設定する（0 の場合は判定しない）
+func WithAskMinRelevance(score float64) AskServiceOption {
+	return func(s *AskService) {
+		s.minRelevance = score
+	}
+}
+
+// WithAskProductConfig はプロダクトごとに関連度の閾値とシステムプロンプトを上書きするための設定を行う
+// プロダクトを指定した質問のみが対象（スナップショットのみの指定では既定値を使用する）
+func WithAskProductConfig(reader ProductConfigReader) AskServiceOption {
+	return func(s *AskService) {
+		s.productConfig = reader
+	}
+}
+
+// answerPolicy は質問に適用する回答の方針
+type answerPolicy struct {
+	minRelevance float64 // 関連度の閾値（0 の場合は判定しない）
+	systemPrompt string  // システムプロンプト（空の場合は既定）
+}
+
+// answerPolicy はサービスの既定値にプロダクトの設定を重ねた回答の方針を返す
+// 設定は補助的な情報のため、取得に失敗した場合は既定値で続行する
+func (s *AskService) answerPolicy(ctx context.Context, params AskParams) answerPolicy {
+	policy := answerPolicy{minRelevance: s.minRelevance}
+
+	productID, ok := params.ProductID.Get()
+	if s.productConfig == nil || !ok {
+		return policy
+	}
+	entries, err := s.productConfig.List(ctx, productID)
+	if err != nil {
+		s.logger.Warn("failed to load product config for ask", "error", err)
+		return policy
+	}
+
+	config := productconfig.NewConfig(entries)
+	if v, ok := config.Float(productconfig.KeyAskMinRelevance); ok {
+		policy.minRelevance = v
+	}
+	if v, ok := config[productconfig.KeyAskSystemPrompt]; ok {
+		policy.systemPrompt = v
+	}
+	return policy
+}
@@ -0,0 +1,44 @@
+package ask
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jinford/dev-rag/internal/core/productconfig"
+)
+
+type stubProductConfig struct {
+	entries []*productconfig.Entry
+	err     error
+}
+
+func (s *stubProductConfig) List(context.Context, uuid.UUID) ([]*productconfig.Entry, error) {
+	return s.entries, s.err
+}
+
+func TestAnswerPolicy(t *testing.T) {
+	productID := uuid.New()
+	reader := &stubProductConfig{entries: []*productconfig.Entry{
+		{Key: productconfig.KeyAskMinRelevance, Value: "0.4"},
+		{Key: productconfig.KeyAskSystemPrompt, Value: "あなたは決済基盤チームのアシスタントです。"},
+	}}
+	svc := NewAskService(nil, nil, WithAskMinRelevance(0.2), WithAskProductConfig(reader))
+
+	policy := svc.answerPolicy(context.Background(), AskParams{ProductID: mo.Some(productID)})
+	assert.Equal(t, 0.4, policy.minRelevance)
+	assert.Equal(t, "あなたは決済基盤チームのアシスタントです。", policy.systemPrompt)
+
+	// スナップショットのみの指定ではプロダクトの設定を参照しない
+	policy = svc.answerPolicy(context.Background(), AskParams{SnapshotID: mo.Some(uuid.New())})
+	assert.Equal(t, answerPolicy{minRelevance: 0.2}, policy)
+
+	// 取得に失敗した場合は既定値で続行する
+	reader.err = errors.New("db down")
+	policy = svc.answerPolicy(context.Background(), AskParams{ProductID: mo.Some(productID)})
+	assert.Equal(t, answerPolicy{minRelevance: 0.2}, policy)
+}
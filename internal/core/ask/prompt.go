@@ -4,65 +4,195 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/jinford/dev-rag/internal/core/discussion"
+	"github.com/jinford/dev-rag/internal/core/glossary"
+	"github.com/jinford/dev-rag/internal/core/infraspec"
+	"github.com/jinford/dev-rag/internal/core/prompt"
 	"github.com/jinford/dev-rag/internal/core/search"
+	"github.com/jinford/dev-rag/internal/core/sqlschema"
+	"github.com/jinford/dev-rag/internal/core/testcoverage"
 )
 
 // BuildAskPrompt はRAG質問応答用のプロンプトを構築する
+// コードはファイル単位に、ファイル要約とそのファイルで該当した断片を行番号順に並べて示す
+// terms には質問文に現れる用語集の用語を渡す（空の場合は用語集のセクションを含めない）
+// tables には質問文に名前が現れるスキーマカタログのテーブルを渡す（空の場合はデータベーススキーマのセクションを含めない）
+// resources には質問に関連するデプロイ構成のリソースを渡す（空の場合はデプロイ構成のセクションを含めない）
+// discussions には関連コードのファイルに言及した議論を渡す（空の場合は議論のセクションを含めない）
+// coverage にはテストに関する質問で参照するファイルのテストカバレッジを渡す（空の場合はテストカバレッジのセクションを含めない）
+// systemPrompt にはプロダクトごとに設定した役割の説明を渡す（空の場合は既定の説明、回答のガイドラインは常に含める）
+// 組み込みのテンプレートで展開する（AskService は上書きしたテンプレートを使用する）
 func BuildAskPrompt(
 	query string,
 	summaries []*search.SummarySearchResult,
-	chunks []*search.SearchResult,
+	files []*search.FileContext,
+	terms []*glossary.Term,
+	tables []*sqlschema.Table,
+	resources []*infraspec.Resource,
+	discussions []*discussion.Record,
+	coverage []*testcoverage.FileCoverage,
+	systemPrompt string,
 ) string {
+	return prompt.MustRenderBuiltin(prompt.NameAsk, askPromptData(query, summaries, files, terms, tables, resources, discussions, coverage, systemPrompt))
+}
+
+// askPromptData は質問応答のプロンプトテンプレートに渡す値を構築する
+// 役割の説明・回答のガイドラインはテンプレートに置き、件数や有無で構成が変わるコンテキストをここで整形する
+func askPromptData(
+	query string,
+	summaries []*search.SummarySearchResult,
+	files []*search.FileContext,
+	terms []*glossary.Term,
+	tables []*sqlschema.Table,
+	resources []*infraspec.Resource,
+	discussions []*discussion.Record,
+	coverage []*testcoverage.FileCoverage,
+	systemPrompt string,
+) prompt.AskData {
 	var sb strings.Builder
 
-	// システムプロンプトとガイドライン
-	sb.WriteString("あなたは社内リポジトリのコードベースに精通した技術アシスタントです。\n")
-	sb.WriteString("以下のコンテキスト情報を基に、ユーザーの質問に正確かつ簡潔に回答してください。\n\n")
+	// 用語集（質問文に現れる用語の定義）
+	if len(terms) > 0 {
+		sb.WriteString("## コンテキスト: 用語集\n")
+		for _, term := range terms {
+			sb.WriteString(formatGlossaryTerm(term))
+		}
+		sb.WriteString("\n")
+	}
+
+	// データベーススキーマ（マイグレーションから構築したテーブル定義）
+	if len(tables) > 0 {
+		sb.WriteString("## コンテキスト: データベーススキーマ\n")
+		for i, table := range tables {
+			sb.WriteString(fmt.Sprintf("### [テーブル %d] %s", i+1, table.Name))
+			if table.SourceName != "" {
+				sb.WriteString(fmt.Sprintf("（ソース: %s）", table.SourceName))
+			}
+			sb.WriteString("\n")
+			writeFenced(&sb, sqlschema.Format(table))
+		}
+	}
+
+	// デプロイ構成（Dockerfile・Docker Compose・Kubernetes マニフェストから抽出したリソース）
+	if len(resources) > 0 {
+		sb.WriteString("## コンテキスト: デプロイ構成\n")
+		for i, r := range resources {
+			sb.WriteString(fmt.Sprintf("### [リソース %d] %s", i+1, r.Kind))
+			if r.Format != infraspec.FormatDockerfile {
+				sb.WriteString(" " + r.Name)
+			}
+			if r.SourceName != "" {
+				sb.WriteString(fmt.Sprintf("（ソース: %s）", r.SourceName))
+			}
+			sb.WriteString("\n")
+			writeFenced(&sb, infraspec.Describe(r))
+		}
+	}
 
-	sb.WriteString("## 回答のガイドライン\n")
-	sb.WriteString("- コンテキストに含まれる情報のみを使用して回答してください\n")
-	sb.WriteString("- コードの具体的な場所(ファイルパス、行番号)を明示してください\n")
-	sb.WriteString("- 不明な点がある場合は、推測せずにその旨を述べてください\n\n")
+	// 関連する議論（関連コードのファイルに言及した Issue・Pull Request）
+	if len(discussions) > 0 {
+		sb.WriteString("## コンテキスト: 関連する議論（Issue・Pull Request）\n")
+		for i, d := range discussions {
+			sb.WriteString(fmt.Sprintf("### [議論 %d] %s #%d: %s", i+1, d.Kind.Label(), d.Number, d.Title))
+			if d.SourceName != "" {
+				sb.WriteString(fmt.Sprintf("（ソース: %s）", d.SourceName))
+			}
+			sb.WriteString("\n")
+			if d.State != "" {
+				sb.WriteString(fmt.Sprintf("状態: %s\n", d.State))
+			}
+			if d.URL != "" {
+				sb.WriteString(fmt.Sprintf("URL: %s\n", d.URL))
+			}
+			sb.WriteString(fmt.Sprintf("言及しているファイル: %s\n", strings.Join(d.Paths, ", ")))
+			if d.Summary != "" {
+				writeFenced(&sb, d.Summary)
+			} else {
+				sb.WriteString("\n")
+			}
+		}
+	}
+
+	// テストカバレッジ（取り込んだカバレッジレポートの計測結果）
+	if len(coverage) > 0 {
+		sb.WriteString("## コンテキスト: テストカバレッジ\n")
+		sb.WriteString("取り込んだカバレッジレポートで、テストで実行されたステートメントの割合です（カバレッジの低い順）。一覧にないファイルは計測されていません。\n")
+		for _, c := range coverage {
+			sb.WriteString(fmt.Sprintf("- %s", c.FilePath))
+			if c.SourceName != "" {
+				sb.WriteString(fmt.Sprintf("（ソース: %s）", c.SourceName))
+			}
+			sb.WriteString(fmt.Sprintf(": %.1f%%（%d/%d）\n", c.Percent(), c.Covered, c.Statements))
+		}
+		sb.WriteString("\n")
+	}
+
+	// 関連コードのファイルに添えるファイル要約は、要約セクションに重複して含めない
+	stitched := make(map[string]bool, len(files))
+	for _, file := range files {
+		if file.FileSummary != nil {
+			stitched[file.FilePath] = true
+		}
+	}
 
 	// アーキテクチャ・構造情報
 	sb.WriteString("## コンテキスト: アーキテクチャ・構造情報\n")
-	if len(summaries) > 0 {
-		for i, summary := range summaries {
-			sb.WriteString(fmt.Sprintf("### [要約 %d] ", i+1))
-			sb.WriteString(formatSummaryInfo(summary))
-			sb.WriteString("\n")
-			sb.WriteString(summary.Content)
-			sb.WriteString("\n\n")
+	written := 0
+	for _, summary := range summaries {
+		if summary.SummaryType == "file" && stitched[summary.TargetPath] {
+			continue
 		}
-	} else {
+		written++
+		sb.WriteString(fmt.Sprintf("### [要約 %d] ", written))
+		sb.WriteString(formatSummaryInfo(summary))
+		sb.WriteString("\n")
+		writeFenced(&sb, summary.Content)
+	}
+	if written == 0 {
 		sb.WriteString("(該当する要約情報はありません)\n\n")
 	}
 
-	// 関連コード
+	// 関連コード（ファイル単位）
 	sb.WriteString("## コンテキスト: 関連コード\n")
-	if len(chunks) > 0 {
-		for i, chunk := range chunks {
-			sb.WriteString(fmt.Sprintf("### [コード断片 %d]\n", i+1))
-			sb.WriteString(fmt.Sprintf("ファイルパス: %s\n", chunk.FilePath))
-			sb.WriteString(fmt.Sprintf("行番号: %d-%d\n", chunk.StartLine, chunk.EndLine))
-			sb.WriteString(fmt.Sprintf("関連度スコア: %.3f\n", chunk.Score))
-			sb.WriteString("```\n")
-			sb.WriteString(chunk.Content)
-			sb.WriteString("\n```\n\n")
+	if len(files) > 0 {
+		fragment := 0
+		for i, file := range files {
+			sb.WriteString(fmt.Sprintf("### [ファイル %d] %s\n", i+1, file.FilePath))
+			if len(file.Owners) > 0 {
+				sb.WriteString(fmt.Sprintf("担当: %s\n", strings.Join(file.Owners, ", ")))
+			}
+			if file.FileSummary != nil && *file.FileSummary != "" {
+				sb.WriteString("ファイル要約:\n")
+				writeFenced(&sb, *file.FileSummary)
+			}
+			for _, chunk := range file.Chunks {
+				fragment++
+				sb.WriteString(fmt.Sprintf("#### [コード断片 %d]\n", fragment))
+				sb.WriteString(fmt.Sprintf("ファイルパス: %s\n", chunk.FilePath))
+				sb.WriteString(fmt.Sprintf("行番号: %d-%d\n", chunk.StartLine, chunk.EndLine))
+				if chunk.Breadcrumb != nil {
+					sb.WriteString(fmt.Sprintf("見出し: %s\n", *chunk.Breadcrumb))
+				}
+				if chunk.PageStart != nil {
+					sb.WriteString(fmt.Sprintf("ページ: %s\n", search.FormatPageRange(*chunk.PageStart, chunk.PageEnd)))
+				}
+				sb.WriteString(fmt.Sprintf("関連度スコア: %.3f\n", chunk.Score))
+				if chunk.Relation != nil {
+					sb.WriteString(fmt.Sprintf("関係: %s\n", formatRelation(chunk.Relation)))
+				}
+				writeFenced(&sb, chunk.Content)
+			}
 		}
 	} else {
 		sb.WriteString("(該当するコード断片はありません)\n\n")
 	}
 
-	// ユーザーの質問
-	sb.WriteString("## ユーザーの質問\n")
-	sb.WriteString(query)
-	sb.WriteString("\n\n")
-
-	// 回答セクション
-	sb.WriteString("## 回答\n")
-
-	return sb.String()
+	return prompt.AskData{
+		SystemPrompt:    strings.TrimSpace(systemPrompt),
+		NoAnswerMessage: NoAnswerMessage,
+		Context:         sb.String(),
+		Query:           query,
+	}
 }
 
 // formatSummaryInfo は要約情報のヘッダー部分を整形する
@@ -96,3 +226,50 @@ func formatSummaryInfo(summary *search.SummarySearchResult) string {
 
 	return strings.Join(parts, " | ")
 }
+
+// formatGlossaryTerm は用語集の用語を1行に整形する
+func formatGlossaryTerm(term *glossary.Term) string {
+	name := term.Term
+	if len(term.Aliases) > 0 {
+		name += fmt.Sprintf("（別名: %s）", strings.Join(term.Aliases, ", "))
+	}
+	return fmt.Sprintf("- %s: %s\n", name, strings.Join(strings.Fields(term.Definition), " "))
+}
+
+// formatRelation は依存関係の展開で追加されたチャンクと起点チャンクの関係を整形する
+func formatRelation(rel *search.ChunkRelation) string {
+	var kind string
+	switch rel.DepType {
+	case "call":
+		kind = "呼び出し先"
+		if rel.Direction == search.DirectionIncoming {
+			kind = "呼び出し元"
+		}
+	case "type":
+		kind = "参照している型"
+		if rel.Direction == search.DirectionIncoming {
+			kind = "この型を参照している箇所"
+		}
+	case "import":
+		kind = "インポート先"
+		if rel.Direction == search.DirectionIncoming {
+			kind = "インポート元"
+		}
+	default:
+		kind = rel.DepType
+	}
+
+	label := fmt.Sprintf("%s:%d-%d の%s", rel.ViaFilePath, rel.ViaStartLine, rel.ViaEndLine, kind)
+	if rel.Symbol != nil && *rel.Symbol != "" {
+		label += fmt.Sprintf("（%s）", *rel.Symbol)
+	}
+	return label
+}
+
+// writeFenced はコンテンツをコードフェンスで囲んで書き込む
+func writeFenced(sb *strings.Builder, content string) {
+	fence := codeFence(content)
+	sb.WriteString(fence + "\n")
+	sb.WriteString(content)
+	sb.WriteString("\n" + fence + "\n\n")
+}
@@ -0,0 +1,92 @@
+package ask
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jinford/dev-rag/internal/core/search"
+)
+
+const (
+	// routingRankLimit はアクセス権で絞り込む前に取得するプロダクトの候補の上限
+	routingRankLimit = 20
+	// routingCandidateLimit は振り分け結果に含める候補の上限
+	routingCandidateLimit = 5
+)
+
+// RoutingPolicy はプロダクトを指定しない質問をプロダクトに振り分ける基準
+type RoutingPolicy struct {
+	MinScore float64 // 最も関連するプロダクトの類似度の下限（届かない場合は振り分けない）
+	Margin   float64 // 2番目の候補との類似度の差の下限（差が小さい場合は振り分けずにプロダクトを選ばせる）
+}
+
+// DefaultRoutingPolicy は既定の振り分けの基準
+var DefaultRoutingPolicy = RoutingPolicy{MinScore: 0.2, Margin: 0.05}
+
+// ProductRoute はプロダクトを指定しない質問の振り分け結果を表す
+type ProductRoute struct {
+	Product    *search.ProductCandidate   // 振り分け先（特定できない場合は nil）
+	Candidates []*search.ProductCandidate // 関連度の高い順の候補（特定できない場合に利用者に選ばせる）
+}
+
+// Ambiguous はプロダクトを特定できなかったかを返す
+func (r *ProductRoute) Ambiguous() bool {
+	return r.Product == nil
+}
+
+// WithAskRouting はプロダクトを指定しない質問をプロダクトに振り分ける基準を設定する
+func WithAskRouting(policy RoutingPolicy) AskServiceOption {
+	return func(s *AskService) {
+		s.routing = policy
+	}
+}
+
+// RouteProduct はプロダクトを指定しない質問を、各プロダクトの要約との類似度から最も関連するプロダクトに振り分ける
+// allowed を指定した場合は、allowed が true を返すプロダクトのみを候補とする（APIキーのアクセス権など）
+// 候補の類似度が近く特定できない場合は Product を nil とし、候補を返す
+func (s *AskService) RouteProduct(ctx context.Context, query string, allowed func(productName string) bool) (*ProductRoute, error) {
+	candidates, err := s.searchService.RankProducts(ctx, query, routingRankLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to route question to product: %w", err)
+	}
+
+	if allowed != nil {
+		filtered := make([]*search.ProductCandidate, 0, len(candidates))
+		for _, candidate := range candidates {
+			if allowed(candidate.ProductName) {
+				filtered = append(filtered, candidate)
+			}
+		}
+		candidates = filtered
+	}
+
+	route := SelectProduct(candidates, s.routing)
+	if route.Ambiguous() {
+		s.logger.Info("could not route question to a single product", "candidates", len(route.Candidates))
+	} else {
+		s.logger.Info("routed question to product", "product", route.Product.ProductName, "score", route.Product.Score)
+	}
+	return route, nil
+}
+
+// SelectProduct は関連度の高い順の候補から振り分け先を選ぶ
+// 最上位の候補の類似度が下限に届かない場合と、2番目の候補との差が小さい場合は振り分け先を選ばない
+func SelectProduct(candidates []*search.ProductCandidate, policy RoutingPolicy) *ProductRoute {
+	if len(candidates) > routingCandidateLimit {
+		candidates = candidates[:routingCandidateLimit]
+	}
+	route := &ProductRoute{Candidates: candidates}
+	if len(candidates) == 0 {
+		return route
+	}
+
+	top := candidates[0]
+	if top.Score < policy.MinScore {
+		return route
+	}
+	if len(candidates) > 1 && top.Score-candidates[1].Score < policy.Margin {
+		return route
+	}
+	route.Product = top
+	return route
+}
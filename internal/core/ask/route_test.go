@@ -0,0 +1,47 @@
+package ask
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jinford/dev-rag/internal/core/search"
+)
+
+func TestSelectProduct(t *testing.T) {
+	candidate := func(name string, score float64) *search.ProductCandidate {
+		return &search.ProductCandidate{ProductID: uuid.New(), ProductName: name, Score: score}
+	}
+	policy := RoutingPolicy{MinScore: 0.2, Margin: 0.05}
+
+	t.Run("2番目の候補と十分に差があれば振り分ける", func(t *testing.T) {
+		route := SelectProduct([]*search.ProductCandidate{candidate("payments", 0.61), candidate("ecommerce", 0.42)}, policy)
+		require.False(t, route.Ambiguous())
+		assert.Equal(t, "payments", route.Product.ProductName)
+	})
+
+	t.Run("候補が1件なら下限以上で振り分ける", func(t *testing.T) {
+		route := SelectProduct([]*search.ProductCandidate{candidate("payments", 0.3)}, policy)
+		require.False(t, route.Ambiguous())
+		assert.Equal(t, "payments", route.Product.ProductName)
+	})
+
+	t.Run("類似度が近い場合は候補を返す", func(t *testing.T) {
+		route := SelectProduct([]*search.ProductCandidate{candidate("payments", 0.52), candidate("billing", 0.50), candidate("ecommerce", 0.3)}, policy)
+		assert.True(t, route.Ambiguous())
+		assert.Len(t, route.Candidates, 3)
+	})
+
+	t.Run("下限に届かない場合は振り分けない", func(t *testing.T) {
+		route := SelectProduct([]*search.ProductCandidate{candidate("payments", 0.15)}, policy)
+		assert.True(t, route.Ambiguous())
+	})
+
+	t.Run("候補がない", func(t *testing.T) {
+		route := SelectProduct(nil, policy)
+		assert.True(t, route.Ambiguous())
+		assert.Empty(t, route.Candidates)
+	})
+}
@@ -0,0 +1,50 @@
+package ask
+
+import (
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+
+	"github.com/jinford/dev-rag/internal/core/sqlschema"
+)
+
+// schemaTableLimit はプロンプトに含めるテーブル定義の上限
+const schemaTableLimit = 5
+
+// SchemaLookup はインデックス化時に構築したスキーマカタログのテーブルを取得するインターフェース
+type SchemaLookup interface {
+	ListTables(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID]) ([]*sqlschema.Table, error)
+}
+
+// WithAskSchema は質問文に名前が現れるテーブルの定義（カラム・インデックス）をプロンプトに含めるためのスキーマカタログを設定する
+func WithAskSchema(lookup SchemaLookup) AskServiceOption {
+	return func(s *AskService) {
+		s.schema = lookup
+	}
+}
+
+// relevantTables は質問の範囲のスキーマカタログから、質問文に名前が現れるテーブルを取得する
+// テーブル定義は補助的な情報のため、取得に失敗した場合やインジェクションを含む定義は除いて続行する
+func (s *AskService) relevantTables(ctx context.Context, params AskParams) []*sqlschema.Table {
+	if s.schema == nil || (params.ProductID.IsAbsent() && params.SnapshotID.IsAbsent()) {
+		return nil
+	}
+
+	tables, err := s.schema.ListTables(ctx, params.ProductID, params.SnapshotID)
+	if err != nil {
+		s.logger.Warn("failed to look up schema tables", "error", err)
+		return nil
+	}
+	tables = sqlschema.MatchTables(tables, params.Query, schemaTableLimit)
+	if s.guardrail != nil {
+		tables = slices.DeleteFunc(tables, func(table *sqlschema.Table) bool {
+			return s.guardrail.DetectInjection(sqlschema.Format(table))
+		})
+	}
+	if len(tables) > 0 {
+		s.logger.Info("injected schema tables", "tables", len(tables))
+	}
+	return tables
+}
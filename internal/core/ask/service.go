@@ -1,11 +1,22 @@
 package ask
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"log/slog"
+	"slices"
+	"time"
 
+	"github.com/google/uuid"
+
+	"github.com/jinford/dev-rag/internal/core/discussion"
+	"github.com/jinford/dev-rag/internal/core/glossary"
+	"github.com/jinford/dev-rag/internal/core/infraspec"
+	"github.com/jinford/dev-rag/internal/core/prompt"
 	"github.com/jinford/dev-rag/internal/core/search"
+	"github.com/jinford/dev-rag/internal/core/sqlschema"
+	"github.com/jinford/dev-rag/internal/core/testcoverage"
 )
 
 // LLMClient はLLM通信インターフェース
@@ -13,11 +24,46 @@ type LLMClient interface {
 	GenerateCompletion(ctx context.Context, prompt string) (string, error)
 }
 
+// PromptRenderer はプロンプトテンプレートを展開するインターフェース
+// Resolve は回答のキャッシュのキーに使用するバージョンを、プロンプトを展開する前に取得するためのもの
+type PromptRenderer interface {
+	Resolve(ctx context.Context, name string) (*prompt.Template, error)
+	Render(ctx context.Context, name string, data any) (*prompt.Rendered, error)
+}
+
+// GlossaryLookup は質問文に現れる用語を用語集から取得するインターフェース
+type GlossaryLookup interface {
+	RelevantTerms(ctx context.Context, productID uuid.UUID, text string, limit int) ([]*glossary.Term, error)
+}
+
+// glossaryTermLimit はプロンプトに含める用語の定義の上限
+const glossaryTermLimit = 10
+
 // AskService は質問応答のビジネスロジックを提供する
 type AskService struct {
 	searchService *search.SearchService
 	llm           LLMClient
+	guardrail     *Guardrail
+	expansion     search.DependencyExpansion
+	glossary      GlossaryLookup
+	decisions     DecisionLookup
+	schema        SchemaLookup
+	infra         InfraLookup
+	discussions   DiscussionLookup
+	owners        OwnerLookup
+	symbols       SymbolLookup
+	coverage      CoverageLookup
+	productConfig ProductConfigReader
+	minRelevance  float64
+	prompts       PromptRenderer
 	logger        *slog.Logger
+
+	answerCache    AnswerCacheStore
+	answerCacheTTL time.Duration
+
+	routing RoutingPolicy
+
+	latencyBudget time.Duration
 }
 
 type AskServiceOption func(*AskService)
@@ -29,6 +75,45 @@ func WithAskLogger(logger *slog.Logger) AskServiceOption {
 	}
 }
 
+// WithAskGuardrail は AskService にガードレールを設定する
+// nil を指定するとガードレールを無効化する
+func WithAskGuardrail(guardrail *Guardrail) AskServiceOption {
+	return func(s *AskService) {
+		s.guardrail = guardrail
+	}
+}
+
+// WithAskDependencyExpansion は検索結果を依存関係で展開する既定の設定を指定する
+// MaxHops が 0 の場合（既定）は AskParams.DependencyHops を指定しない限り展開しない
+func WithAskDependencyExpansion(expansion search.DependencyExpansion) AskServiceOption {
+	return func(s *AskService) {
+		s.expansion = expansion
+	}
+}
+
+// WithAskGlossary は質問文に現れる用語の定義をプロンプトに含めるための用語集を設定する
+// プロダクトを指定した質問のみが対象（スナップショットのみの指定では用語集を参照しない）
+func WithAskGlossary(lookup GlossaryLookup) AskServiceOption {
+	return func(s *AskService) {
+		s.glossary = lookup
+	}
+}
+
+// WithAskDecisions は検索結果に含まれる設計判断の記録（ADR）のステータスを参照するための設定を行う
+// 採用済みの決定のチャンクを優先し、却下・非推奨・置き換え済みの決定のチャンクの順位を下げる
+func WithAskDecisions(lookup DecisionLookup) AskServiceOption {
+	return func(s *AskService) {
+		s.decisions = lookup
+	}
+}
+
+// WithAskPrompts は質問応答・引用の検証のプロンプトを展開するテンプレートを設定する（既定は組み込みのテンプレート）
+func WithAskPrompts(renderer PromptRenderer) AskServiceOption {
+	return func(s *AskService) {
+		s.prompts = renderer
+	}
+}
+
 // NewAskService は新しいAskServiceを作成する
 func NewAskService(
 	searchService *search.SearchService,
@@ -38,6 +123,9 @@ func NewAskService(
 	svc := &AskService{
 		searchService: searchService,
 		llm:           llm,
+		guardrail:     NewGuardrail(),
+		prompts:       prompt.Default(),
+		routing:       DefaultRoutingPolicy,
 		logger:        slog.Default(),
 	}
 
@@ -48,18 +136,38 @@ func NewAskService(
 	if svc.logger == nil {
 		svc.logger = slog.Default()
 	}
+	if svc.prompts == nil {
+		svc.prompts = prompt.Default()
+	}
 
 	return svc
 }
 
+// Guardrail は設定されているガードレールを返す（無効の場合は nil）
+func (s *AskService) Guardrail() *Guardrail {
+	return s.guardrail
+}
+
+// refuse はガードレールによる拒否結果を生成する
+func refuse(reason GuardrailReason) *AskResult {
+	return &AskResult{
+		Answer:        RefusalMessage(reason),
+		Sources:       []SourceReference{},
+		Refused:       true,
+		RefusalReason: reason,
+	}
+}
+
 // Ask は質問に対してRAGベースで回答を生成する
 func (s *AskService) Ask(ctx context.Context, params AskParams) (*AskResult, error) {
+	budget := newLatencyBudget(s.latencyBudget)
+
 	// 1. バリデーション
 	if params.Query == "" {
 		return nil, fmt.Errorf("query is required")
 	}
-	if params.ProductID.IsAbsent() {
-		return nil, fmt.Errorf("productID is required")
+	if params.ProductID.IsAbsent() && params.SnapshotID.IsAbsent() {
+		return nil, fmt.Errorf("productID or snapshotID is required")
 	}
 
 	// 2. デフォルト値の設定
@@ -72,16 +180,81 @@ func (s *AskService) Ask(ctx context.Context, params AskParams) (*AskResult, err
 		summaryLimit = 5
 	}
 
-	// 3. HybridSearch実行（ProductID指定でプロダクト横断検索）
+	// 3. 質問文のガードレール検査
+	if s.guardrail != nil {
+		if reason, blocked := s.guardrail.CheckQuery(params.Query); blocked {
+			s.logger.Warn("query refused by guardrail", "reason", string(reason))
+			return refuse(reason), nil
+		}
+	}
+
+	// 4. 定義位置だけを尋ねる質問は、シンボルの完全一致で見つかればベクトル検索を使わずに回答する
+	if result, ok := s.answerDefinition(ctx, params); ok {
+		return result, nil
+	}
+
+	// 5. 同じ質問の回答がキャッシュにあれば、検索とLLMの呼び出しを行わずに返す
+	policy := s.answerPolicy(ctx, params)
+	expansion := s.expansion
+	if hops, ok := params.DependencyHops.Get(); ok {
+		expansion.MaxHops = hops
+	}
+	var cacheEntry *AnswerCacheEntry
+	if s.answerCache != nil && !params.NoCache {
+		promptVersion, err := s.askPromptVersion(ctx)
+		if err != nil {
+			return nil, err
+		}
+		question := NormalizeQuestion(params.Query)
+		cacheEntry = &AnswerCacheEntry{
+			Key:           answerCacheKey(params, question, promptVersion, policy.systemPrompt, chunkLimit, summaryLimit, expansion),
+			ProductID:     params.ProductID,
+			SnapshotID:    params.SnapshotID,
+			PromptVersion: promptVersion,
+			Question:      question,
+		}
+		if result, ok := s.cachedAnswer(ctx, cacheEntry.Key); ok {
+			s.logger.Info("answer cache hit", "promptVersion", promptVersion)
+			return result, nil
+		}
+	}
+
+	// 6. HybridSearch実行（ProductID指定でプロダクト横断検索）
+	// SnapshotID 指定時は最新スナップショットの代わりにそのスナップショットを検索する
 	searchParams := search.HybridSearchParams{
 		ProductID:    params.ProductID,
 		Query:        params.Query,
 		ChunkLimit:   chunkLimit,
 		SummaryLimit: summaryLimit,
+		ChunkFilter:  &search.SearchFilter{Domains: params.Domains, Languages: params.Languages, EmbeddingModel: params.EmbeddingModel},
+		Strategy:     params.Strategy,
+	}
+	if snapshotID, ok := params.SnapshotID.Get(); ok {
+		if params.ProductID.IsPresent() {
+			// プロダクトに属さないスナップショットはフィルタで除外される
+			searchParams.ChunkFilter.SnapshotID = &snapshotID
+			searchParams.SummaryFilter = &search.SummarySearchFilter{SnapshotID: &snapshotID}
+		} else {
+			searchParams.SnapshotID = snapshotID
+		}
+	}
+	if params.PathPrefix != "" {
+		pathPrefix := params.PathPrefix
+		searchParams.ChunkFilter.PathPrefix = &pathPrefix
+		if searchParams.SummaryFilter == nil {
+			searchParams.SummaryFilter = &search.SummarySearchFilter{}
+		}
+		searchParams.SummaryFilter.PathPrefix = &pathPrefix
 	}
 
 	s.logger.Info("executing hybrid search",
-		"productID", params.ProductID.MustGet().String(),
+		"productID", params.ProductID.OrEmpty(),
+		"snapshotID", params.SnapshotID.OrEmpty(),
+		"domains", params.Domains,
+		"languages", params.Languages,
+		"pathPrefix", params.PathPrefix,
+		"strategy", params.Strategy,
+		"embeddingModel", params.EmbeddingModel,
 		"query", params.Query,
 		"chunkLimit", chunkLimit,
 		"summaryLimit", summaryLimit,
@@ -97,25 +270,150 @@ func (s *AskService) Ask(ctx context.Context, params AskParams) (*AskResult, err
 		"summaries", len(hybridResult.Summaries),
 	)
 
-	// 4. プロンプト構築
-	prompt := BuildAskPrompt(params.Query, hybridResult.Summaries, hybridResult.Chunks)
+	// 7. 範囲外判定（検索結果がない、または関連度がプロダクトの閾値に届かない場合は回答を生成しない）
+	summaries, chunks := hybridResult.Summaries, hybridResult.Chunks
+	if s.guardrail != nil {
+		if reason, blocked := s.guardrail.CheckScope(summaries, chunks); blocked {
+			s.logger.Warn("query refused by guardrail", "reason", string(reason))
+			return refuse(reason), nil
+		}
+		if reason, blocked := s.guardrail.CheckRelevance(summaries, chunks, policy.minRelevance); blocked {
+			s.logger.Warn("query refused by guardrail", "reason", string(reason))
+			return refuse(reason), nil
+		}
+	}
+
+	// 検索が予算の半分を超えた場合は、回答の生成に時間を残すため補助的な処理を省略する
+	var degraded []DegradedStage
+	skipOptional := budget.retrievalExceeded(time.Now())
+	if skipOptional {
+		s.logger.Warn("retrieval exceeded latency budget, skipping optional stages",
+			"elapsed", time.Since(budget.start),
+			"budget", budget.total,
+		)
+	}
+
+	// 8. 設計判断の記録のステータスによるスコアの補正
+	if skipOptional {
+		degraded = append(degraded, DegradedRerank)
+	} else {
+		chunks = s.biasDecisionRecords(ctx, chunks)
+	}
+
+	// 9. 依存関係による展開（呼び出し元・呼び出し先・型依存のチャンクを追加）
+	if expansion.Enabled() && skipOptional {
+		degraded = append(degraded, DegradedDependencyExpansion)
+	} else if expansion.Enabled() {
+		related, err := s.searchService.ExpandDependencies(ctx, chunks, expansion)
+		if err != nil {
+			// 展開は補助的な処理のため、失敗しても検索結果のみで回答を続行する
+			s.logger.Warn("dependency expansion failed", "error", err)
+		} else if len(related) > 0 {
+			s.logger.Info("expanded chunks along dependencies", "hops", expansion.MaxHops, "chunks", len(related))
+			chunks = append(chunks, related...)
+		}
+	}
 
-	// 5. LLMで回答生成
-	s.logger.Info("generating answer with LLM")
-	answer, err := s.llm.GenerateCompletion(ctx, prompt)
+	// 10. チャンク階層に沿ってファイル単位に組み立て（ファイル要約 + 関数チャンク、重複する行範囲は統合）
+	files, err := s.searchService.AssembleContext(ctx, chunks)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate answer: %w", err)
+		// 階層情報が取得できない場合もファイル単位の統合のみで続行する
+		s.logger.Warn("failed to assemble hierarchical context", "error", err)
+		files = search.StitchChunks(chunks, nil)
 	}
 
-	// 6. SourceReferenceを整形して返却
-	sources := make([]SourceReference, 0, len(hybridResult.Chunks))
-	for _, chunk := range hybridResult.Chunks {
-		sources = append(sources, SourceReference{
-			FilePath:  chunk.FilePath,
-			StartLine: chunk.StartLine,
-			EndLine:   chunk.EndLine,
-			Score:     chunk.Score,
-		})
+	// 11. 検索結果のサニタイズ
+	if s.guardrail != nil {
+		summaries, _ = s.guardrail.SanitizeContext(summaries, nil)
+		files = s.guardrail.SanitizeFiles(files)
+	}
+
+	// 12. 質問文に現れる用語の定義・テーブル定義と、質問に関連するデプロイ構成を取得
+	var (
+		terms       []*glossary.Term
+		tables      []*sqlschema.Table
+		resources   []*infraspec.Resource
+		discussions []*discussion.Record
+		coverage    []*testcoverage.FileCoverage
+	)
+	if skipOptional {
+		degraded = append(degraded, DegradedContextEnrichment)
+	} else {
+		terms = s.relevantTerms(ctx, params)
+		tables = s.relevantTables(ctx, params)
+		resources = s.relevantInfraResources(ctx, params)
+		// 13. 関連コードのファイルに言及した議論（Issue・Pull Request）を取得
+		discussions = s.relatedDiscussions(ctx, params, files)
+		// 14. テストに関する質問では、関連コードのファイルとカバレッジの低いファイルのテストカバレッジを取得
+		coverage = s.relevantCoverage(ctx, params, files)
+	}
+
+	// ファイルの担当者は参照ソースに含めるため、予算を超えた場合も取得する
+	owners := s.fileOwners(ctx, files)
+	for _, file := range files {
+		file.Owners = owners[file.FileID]
+	}
+
+	// 15. プロンプト構築
+	rendered, err := s.prompts.Render(ctx, prompt.NameAsk, askPromptData(params.Query, summaries, files, terms, tables, resources, discussions, coverage, policy.systemPrompt))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build prompt: %w", err)
+	}
+
+	// 16. LLMで回答生成（予算を超えた場合は打ち切り、途中までの回答（ない場合は関連するソースのみ）を返す）
+	s.logger.Info("generating answer with LLM", "promptVersion", rendered.Version)
+	generated, err := s.generateAnswer(ctx, budget, params.Model, rendered.Text)
+	if err != nil {
+		return nil, err
+	}
+	answer := generated.Text
+	if generated.TimedOut {
+		degraded = append(degraded, DegradedAnswer)
+	}
+
+	// 17. SourceReferenceを整形して返却（プロンプトに含めた断片をスコア順に並べる）
+	sources := make([]SourceReference, 0, len(chunks))
+	for _, file := range files {
+		for _, chunk := range file.Chunks {
+			sources = append(sources, SourceReference{
+				FilePath:   chunk.FilePath,
+				StartLine:  chunk.StartLine,
+				EndLine:    chunk.EndLine,
+				Breadcrumb: chunk.Breadcrumb,
+				PageStart:  chunk.PageStart,
+				PageEnd:    chunk.PageEnd,
+				Owners:     file.Owners,
+				Score:      chunk.Score,
+			})
+		}
+	}
+	slices.SortStableFunc(sources, func(a, b SourceReference) int {
+		return cmp.Compare(b.Score, a.Score)
+	})
+
+	result := &AskResult{
+		Answer:        answer,
+		Sources:       sources,
+		PromptVersion: rendered.Version,
+		Truncated:     generated.Truncated,
+	}
+
+	// 18. 引用の検証（回答の文ごとに、引用したコードが文を裏付けているかを判定）
+	if params.VerifyCitations {
+		if slices.Contains(degraded, DegradedAnswer) || budget.exceeded(time.Now()) {
+			degraded = append(degraded, DegradedCitationVerification)
+		} else {
+			result.Citations = s.verifyCitations(ctx, params.Model, answer, files)
+			s.logger.Info("verified citations", "citations", len(result.Citations))
+		}
+	}
+	result.Degraded = degraded
+
+	// 予算を超えて処理を省略した回答は、次回の質問で完全な回答を生成できるようキャッシュしない
+	if cacheEntry != nil && len(degraded) == 0 && result.PromptVersion == cacheEntry.PromptVersion {
+		// 検索とプロンプトの展開の間にテンプレートが更新された場合は、キーと異なるバージョンの回答になるため保存しない
+		cacheEntry.Result = result
+		s.storeAnswer(ctx, cacheEntry)
 	}
 
 	s.logger.Info("ask completed successfully",
@@ -123,8 +421,29 @@ func (s *AskService) Ask(ctx context.Context, params AskParams) (*AskResult, err
 		"sources", len(sources),
 	)
 
-	return &AskResult{
-		Answer:  answer,
-		Sources: sources,
-	}, nil
+	return result, nil
+}
+
+// relevantTerms は質問文に現れる用語を用語集から取得する
+// 用語集は補助的な情報のため、取得に失敗した場合やインジェクションを含む定義は除いて続行する
+func (s *AskService) relevantTerms(ctx context.Context, params AskParams) []*glossary.Term {
+	productID, ok := params.ProductID.Get()
+	if s.glossary == nil || !ok {
+		return nil
+	}
+
+	terms, err := s.glossary.RelevantTerms(ctx, productID, params.Query, glossaryTermLimit)
+	if err != nil {
+		s.logger.Warn("failed to look up glossary terms", "error", err)
+		return nil
+	}
+	if s.guardrail != nil {
+		terms = slices.DeleteFunc(terms, func(term *glossary.Term) bool {
+			return s.guardrail.DetectInjection(term.Definition)
+		})
+	}
+	if len(terms) > 0 {
+		s.logger.Info("injected glossary terms", "terms", len(terms))
+	}
+	return terms
 }
@@ -0,0 +1,79 @@
+package ask
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+
+	"github.com/jinford/dev-rag/internal/core/symbol"
+)
+
+// definitionSymbolLimit は定義位置の質問に回答するシンボルの上限
+const definitionSymbolLimit = 10
+
+// SymbolLookup はシンボルの定義位置を名前の完全一致で検索するインターフェース
+type SymbolLookup interface {
+	FindSymbols(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID], query symbol.Query) ([]*symbol.Symbol, error)
+}
+
+// WithAskSymbols は「X はどこで定義されていますか」のような定義位置の質問に、
+// ベクトル検索と LLM を使わずシンボルの完全一致で回答するための設定を行う
+func WithAskSymbols(lookup SymbolLookup) AskServiceOption {
+	return func(s *AskService) {
+		s.symbols = lookup
+	}
+}
+
+// answerDefinition は定義位置だけを尋ねる質問に、シンボルの完全一致で見つかった定義位置を回答する
+// 質問が定義位置の質問でない場合やシンボルが見つからない場合は false を返し、通常の検索で回答する
+func (s *AskService) answerDefinition(ctx context.Context, params AskParams) (*AskResult, bool) {
+	if s.symbols == nil {
+		return nil, false
+	}
+	name, ok := symbol.ParseDefinitionQuestion(params.Query)
+	if !ok {
+		return nil, false
+	}
+
+	symbols, err := symbol.Find(ctx, s.symbols, params.ProductID, params.SnapshotID, name, "", definitionSymbolLimit)
+	if err != nil {
+		// 完全一致の検索は補助的な経路のため、失敗した場合は通常の検索で回答する
+		s.logger.Warn("failed to look up symbols", "symbol", name, "error", err)
+		return nil, false
+	}
+	if len(symbols) == 0 {
+		s.logger.Info("no symbol matched definition question, falling back to search", "symbol", name)
+		return nil, false
+	}
+
+	s.logger.Info("answered definition question from symbol index", "symbol", name, "definitions", len(symbols))
+	return DefinitionAnswer(name, symbols), true
+}
+
+// DefinitionAnswer はシンボルの定義位置を列挙した回答を組み立てる
+func DefinitionAnswer(name string, symbols []*symbol.Symbol) *AskResult {
+	var b strings.Builder
+	fmt.Fprintf(&b, "`%s` は次の場所で定義されています。\n", strings.Trim(name, "`"))
+
+	sources := make([]SourceReference, 0, len(symbols))
+	for _, sym := range symbols {
+		fmt.Fprintf(&b, "\n- `%s`（%s）: %s:%d-%d", sym.QualifiedName(), sym.Kind, sym.FilePath, sym.StartLine, sym.EndLine)
+		if sym.SourceName != "" {
+			fmt.Fprintf(&b, "（ソース: %s）", sym.SourceName)
+		}
+		sources = append(sources, SourceReference{
+			FilePath:  sym.FilePath,
+			StartLine: sym.StartLine,
+			EndLine:   sym.EndLine,
+			Score:     1,
+		})
+	}
+
+	return &AskResult{
+		Answer:  b.String(),
+		Sources: sources,
+	}
+}
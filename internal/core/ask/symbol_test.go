@@ -0,0 +1,54 @@
+package ask
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jinford/dev-rag/internal/core/symbol"
+)
+
+type stubSymbolLookup struct {
+	symbols []*symbol.Symbol
+	query   symbol.Query
+}
+
+func (l *stubSymbolLookup) FindSymbols(_ context.Context, _, _ mo.Option[uuid.UUID], query symbol.Query) ([]*symbol.Symbol, error) {
+	l.query = query
+	return l.symbols, nil
+}
+
+func TestAskService_AnswerDefinition(t *testing.T) {
+	lookup := &stubSymbolLookup{symbols: []*symbol.Symbol{
+		{Name: "IndexSource", Kind: "method", ParentName: "IndexService", SourceName: "backend", FilePath: "internal/core/ingestion/service.go", StartLine: 135, EndLine: 320},
+	}}
+	// 定義位置の質問はシンボルの完全一致で回答し、検索サービス・LLM を呼び出さない
+	svc := NewAskService(nil, nil, WithAskSymbols(lookup))
+
+	result, err := svc.Ask(context.Background(), AskParams{ProductID: mo.Some(uuid.New()), Query: "IndexService.IndexSource はどこで定義されていますか？"})
+	require.NoError(t, err)
+
+	assert.Equal(t, symbol.Query{Name: "IndexSource", ParentName: "IndexService", Limit: definitionSymbolLimit}, lookup.query)
+	assert.Contains(t, result.Answer, "`IndexService.IndexSource`（method）: internal/core/ingestion/service.go:135-320（ソース: backend）")
+	require.Len(t, result.Sources, 1)
+	assert.Equal(t, SourceReference{FilePath: "internal/core/ingestion/service.go", StartLine: 135, EndLine: 320, Score: 1}, result.Sources[0])
+	assert.Empty(t, result.PromptVersion)
+}
+
+func TestAskService_AnswerDefinition_FallsBack(t *testing.T) {
+	lookup := &stubSymbolLookup{}
+	svc := NewAskService(nil, nil, WithAskSymbols(lookup))
+
+	_, ok := svc.answerDefinition(context.Background(), AskParams{Query: "where is Unknown defined?"})
+	assert.False(t, ok, "シンボルが見つからない場合は通常の検索で回答する")
+	assert.Equal(t, "Unknown", lookup.query.Name)
+
+	lookup.query = symbol.Query{}
+	_, ok = svc.answerDefinition(context.Background(), AskParams{Query: "how does IndexSource handle errors?"})
+	assert.False(t, ok)
+	assert.Empty(t, lookup.query.Name, "定義位置の質問でなければシンボルを検索しない")
+}
@@ -0,0 +1,96 @@
+// Package cicheck はCIのパイプラインでインデックス・カバレッジ・Wikiの状態を検証し、機械可読な結果と終了コードを返す
+package cicheck
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/jinford/dev-rag/internal/core/ingestion"
+	"github.com/jinford/dev-rag/internal/core/wiki"
+)
+
+// CheckName はチェックの種類
+type CheckName string
+
+const (
+	CheckIndexDryRun CheckName = "index_dry_run" // ソースを取得し、インデックス化した場合の変更内容を算出する
+	CheckCoverage    CheckName = "coverage"      // ドメイン別カバレッジとテストカバレッジを閾値と比較する
+	CheckWikiLinks   CheckName = "wiki_links"    // 生成済みのWikiの解決できない参照を検出する
+	CheckStaleDocs   CheckName = "stale_docs"    // インデックス化の後に更新されていないWiki・要約を検出する
+)
+
+// CheckNames はチェックの一覧（実行順）
+var CheckNames = []CheckName{CheckIndexDryRun, CheckCoverage, CheckWikiLinks, CheckStaleDocs}
+
+// Status はチェックの結果
+type Status string
+
+const (
+	StatusPass  Status = "pass"  // 問題なし
+	StatusWarn  Status = "warn"  // 注意が必要だが失敗とはしない
+	StatusFail  Status = "fail"  // 閾値などを満たさない
+	StatusSkip  Status = "skip"  // 対象がないため実行しなかった
+	StatusError Status = "error" // チェックを実行できなかった
+)
+
+// 終了コード
+const (
+	ExitCodePass   = 0 // すべてのチェックが pass・warn・skip
+	ExitCodeFailed = 1 // fail のチェックがある
+	ExitCodeError  = 2 // 実行できなかったチェックがある
+)
+
+// Params はチェックの実行条件
+type Params struct {
+	ProductID       uuid.UUID
+	ProductName     string
+	Checks          []CheckName // 空の場合はすべてのチェックを実行する
+	WikiDir         string      // 生成済みのWikiの出力ディレクトリ（空または存在しない場合は Wiki のチェックを省略する）
+	MaxBroken       int         // 許容する解決できない参照の数
+	MinTestCoverage float64     // テストカバレッジの下限（%、0 の場合は判定しない）
+}
+
+// CheckResult は1つのチェックの結果
+type CheckResult struct {
+	Name    CheckName `json:"name"`
+	Status  Status    `json:"status"`
+	Message string    `json:"message"`
+	Details []string  `json:"details,omitempty"`
+
+	IndexPlans       []*ingestion.IndexPlan `json:"indexPlans,omitempty"`       // index_dry_run のみ
+	Alerts           []*ingestion.Alert     `json:"alerts,omitempty"`           // coverage のみ
+	TestCoverage     *float64               `json:"testCoverage,omitempty"`     // coverage のみ: 取り込み済みのテストカバレッジ（%）
+	BrokenReferences []wiki.BrokenReference `json:"brokenReferences,omitempty"` // wiki_links のみ
+}
+
+// Report はチェックの実行結果
+type Report struct {
+	Product   string         `json:"product"`
+	Status    Status         `json:"status"` // 最も重い結果（error > fail > warn > pass）
+	ExitCode  int            `json:"exitCode"`
+	CheckedAt time.Time      `json:"checkedAt"`
+	Checks    []*CheckResult `json:"checks"`
+}
+
+// summarize はチェックの結果から全体の結果と終了コードを設定する
+func (r *Report) summarize() {
+	r.Status = StatusPass
+	r.ExitCode = ExitCodePass
+	for _, check := range r.Checks {
+		switch check.Status {
+		case StatusError:
+			r.Status = StatusError
+			r.ExitCode = ExitCodeError
+		case StatusFail:
+			if r.Status != StatusError {
+				r.Status = StatusFail
+				r.ExitCode = ExitCodeFailed
+			}
+		case StatusWarn:
+			if r.Status == StatusPass {
+				r.Status = StatusWarn
+			}
+		}
+	}
+}
@@ -0,0 +1,473 @@
+package cicheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+
+	"github.com/jinford/dev-rag/internal/core/ingestion"
+	"github.com/jinford/dev-rag/internal/core/ingestion/summary"
+	"github.com/jinford/dev-rag/internal/core/testcoverage"
+	"github.com/jinford/dev-rag/internal/core/wiki"
+)
+
+// SourceReader はプロダクトのソースと最新のインデックス済みスナップショットを取得するインターフェース
+type SourceReader interface {
+	ListSourcesByProductID(ctx context.Context, productID uuid.UUID) ([]*ingestion.Source, error)
+	GetLatestIndexedSnapshot(ctx context.Context, sourceID uuid.UUID) (mo.Option[*ingestion.SourceSnapshot], error)
+}
+
+// IndexPlanner はインデックス化した場合の変更内容を算出するインターフェース（ingestion.IndexService が満たす）
+type IndexPlanner interface {
+	Plan(ctx context.Context, params ingestion.IndexParams) (*ingestion.IndexPlan, error)
+}
+
+// CoverageAlerter はスナップショットのカバレッジをプロダクトの閾値と比較するインターフェース（coverage.AlertGenerator が満たす）
+type CoverageAlerter interface {
+	Generate(ctx context.Context, productID, snapshotID uuid.UUID) ([]*ingestion.Alert, error)
+}
+
+// TestCoverageLookup は取り込み済みのファイルごとのテストカバレッジを取得するインターフェース
+type TestCoverageLookup interface {
+	ListFileCoverage(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID]) ([]*testcoverage.FileCoverage, error)
+}
+
+// SourceFileLister はWikiの参照の検証に使用するファイルのパスと行数を取得するインターフェース
+type SourceFileLister interface {
+	ListSourceFileLines(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID]) ([]*wiki.SourceFileLines, error)
+}
+
+// SummaryRefresher はファイル要約の再生成が必要なファイルを検出するインターフェース（summary.SummaryService が満たす）
+// チェックでは DryRun を指定して検出のみ行う
+type SummaryRefresher interface {
+	Refresh(ctx context.Context, snapshotID uuid.UUID, opts summary.RefreshOptions) (*summary.RefreshResult, error)
+}
+
+// Service はCI向けのチェックを実行する
+type Service struct {
+	sources      SourceReader
+	planners     map[ingestion.SourceType]IndexPlanner
+	alerts       CoverageAlerter
+	testCoverage TestCoverageLookup
+	sourceFiles  SourceFileLister
+	summaries    SummaryRefresher
+	logger       *slog.Logger
+	now          func() time.Time
+}
+
+// ServiceOption は Service のオプション設定
+type ServiceOption func(*Service)
+
+// WithServiceLogger は Service にロガーを設定する
+func WithServiceLogger(logger *slog.Logger) ServiceOption {
+	return func(s *Service) {
+		s.logger = logger
+	}
+}
+
+// WithIndexPlanner はソースタイプのインデックス化のドライランに使用する IndexPlanner を設定する
+// 設定していないソースタイプ（再取得できない議論など）はドライランの対象外とする
+func WithIndexPlanner(sourceType ingestion.SourceType, planner IndexPlanner) ServiceOption {
+	return func(s *Service) {
+		s.planners[sourceType] = planner
+	}
+}
+
+// WithCoverageAlerter はドメイン別カバレッジの閾値の判定を設定する
+func WithCoverageAlerter(alerter CoverageAlerter) ServiceOption {
+	return func(s *Service) {
+		s.alerts = alerter
+	}
+}
+
+// WithTestCoverage はテストカバレッジの取得を設定する
+func WithTestCoverage(lookup TestCoverageLookup) ServiceOption {
+	return func(s *Service) {
+		s.testCoverage = lookup
+	}
+}
+
+// WithSourceFiles はWikiのソースファイルの引用の検証に使用するファイル一覧の取得を設定する（未設定の場合はWikiページ間のリンクのみ検証する）
+func WithSourceFiles(lister SourceFileLister) ServiceOption {
+	return func(s *Service) {
+		s.sourceFiles = lister
+	}
+}
+
+// WithSummaryRefresher はファイル要約の再生成が必要なファイルの検出を設定する
+func WithSummaryRefresher(refresher SummaryRefresher) ServiceOption {
+	return func(s *Service) {
+		s.summaries = refresher
+	}
+}
+
+// NewService は新しい Service を作成する
+func NewService(sources SourceReader, opts ...ServiceOption) *Service {
+	svc := &Service{
+		sources:  sources,
+		planners: make(map[ingestion.SourceType]IndexPlanner),
+		logger:   slog.Default(),
+		now:      time.Now,
+	}
+	for _, opt := range opts {
+		opt(svc)
+	}
+	if svc.logger == nil {
+		svc.logger = slog.Default()
+	}
+	return svc
+}
+
+// target はチェック対象のソースと最新のインデックス済みスナップショット（未インデックスの場合は nil）
+type target struct {
+	source   *ingestion.Source
+	snapshot *ingestion.SourceSnapshot
+}
+
+// Run はプロダクトのチェックを実行する
+// 個々のチェックを実行できなかった場合は、そのチェックの結果を error として残りのチェックを続ける
+func (s *Service) Run(ctx context.Context, params Params) (*Report, error) {
+	for _, name := range params.Checks {
+		if !slices.Contains(CheckNames, name) {
+			return nil, fmt.Errorf("unknown check: %s", name)
+		}
+	}
+	if params.MaxBroken < 0 {
+		return nil, fmt.Errorf("max broken must not be negative: %d", params.MaxBroken)
+	}
+
+	sources, err := s.sources.ListSourcesByProductID(ctx, params.ProductID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sources: %w", err)
+	}
+	targets := make([]*target, 0, len(sources))
+	for _, source := range sources {
+		snapshotOpt, err := s.sources.GetLatestIndexedSnapshot(ctx, source.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get latest indexed snapshot: %w", err)
+		}
+		targets = append(targets, &target{source: source, snapshot: snapshotOpt.OrEmpty()})
+	}
+
+	report := &Report{Product: params.ProductName, CheckedAt: s.now()}
+	for _, name := range CheckNames {
+		if len(params.Checks) > 0 && !slices.Contains(params.Checks, name) {
+			continue
+		}
+		var result *CheckResult
+		switch name {
+		case CheckIndexDryRun:
+			result = s.checkIndexDryRun(ctx, params, targets)
+		case CheckCoverage:
+			result = s.checkCoverage(ctx, params, targets)
+		case CheckWikiLinks:
+			result = s.checkWikiLinks(ctx, params)
+		case CheckStaleDocs:
+			result = s.checkStaleDocs(ctx, params, targets)
+		}
+		s.logger.Info("ci check finished", "check", name, "status", result.Status)
+		report.Checks = append(report.Checks, result)
+	}
+	report.summarize()
+	return report, nil
+}
+
+// checkIndexDryRun は再取得できる各ソースについて、インデックス化した場合の変更内容を算出する
+// 取得・除外ルールの適用に失敗したソースがあれば fail、インデックスが最新でないソースがあれば warn とする
+func (s *Service) checkIndexDryRun(ctx context.Context, params Params, targets []*target) *CheckResult {
+	result := &CheckResult{Name: CheckIndexDryRun}
+	var planned, behind, failed int
+	for _, t := range targets {
+		planner, ok := s.planners[t.source.SourceType]
+		if !ok {
+			continue
+		}
+		indexParams, ok := indexParamsFor(params.ProductName, t.source)
+		if !ok {
+			failed++
+			result.Details = append(result.Details, fmt.Sprintf("%s: ソースのメタデータに取得元が記録されていません", t.source.Name))
+			continue
+		}
+		plan, err := planner.Plan(ctx, indexParams)
+		if err != nil {
+			failed++
+			result.Details = append(result.Details, fmt.Sprintf("%s: %v", t.source.Name, err))
+			continue
+		}
+		planned++
+		result.IndexPlans = append(result.IndexPlans, plan)
+		switch {
+		case plan.AlreadyIndexed:
+			result.Details = append(result.Details, fmt.Sprintf("%s: %s はインデックス済み", t.source.Name, plan.VersionIdentifier))
+		case plan.HasChanges():
+			behind++
+			result.Details = append(result.Details, fmt.Sprintf("%s: 追加 %d・変更 %d・削除 %d（%s）",
+				t.source.Name, len(plan.Added), len(plan.Modified), len(plan.Removed), plan.VersionIdentifier))
+		default:
+			result.Details = append(result.Details, fmt.Sprintf("%s: ファイルの変更なし（%s）", t.source.Name, plan.VersionIdentifier))
+		}
+	}
+
+	switch {
+	case failed > 0:
+		result.Status = StatusFail
+		result.Message = fmt.Sprintf("%d 件のソースでインデックス化のドライランに失敗しました", failed)
+	case planned == 0:
+		result.Status = StatusSkip
+		result.Message = "再取得できるソースがありません"
+	case behind > 0:
+		result.Status = StatusWarn
+		result.Message = fmt.Sprintf("%d 件のソースのインデックスが最新ではありません", behind)
+	default:
+		result.Status = StatusPass
+		result.Message = fmt.Sprintf("%d 件のソースのインデックスは最新です", planned)
+	}
+	return result
+}
+
+// indexParamsFor はソースのメタデータからインデックス化のパラメータを組み立てる
+func indexParamsFor(productName string, source *ingestion.Source) (ingestion.IndexParams, bool) {
+	params := ingestion.IndexParams{ProductName: productName}
+	switch source.SourceType {
+	case ingestion.SourceTypeGit:
+		params.Identifier, _ = source.Metadata["url"].(string)
+		if ref, _ := source.Metadata["default_ref"].(string); ref != "" {
+			params.Options = map[string]any{"ref": ref}
+		}
+	case ingestion.SourceTypeLocal:
+		params.Identifier, _ = source.Metadata["path"].(string)
+	}
+	return params, params.Identifier != ""
+}
+
+// checkCoverage は各ソースの最新のスナップショットのドメイン別カバレッジをプロダクトの閾値と比較し、
+// 下限が指定されている場合は取り込み済みのテストカバレッジと比較する
+func (s *Service) checkCoverage(ctx context.Context, params Params, targets []*target) *CheckResult {
+	result := &CheckResult{Name: CheckCoverage}
+	checked := false
+
+	if s.alerts != nil {
+		for _, t := range targets {
+			if t.snapshot == nil {
+				continue
+			}
+			checked = true
+			alerts, err := s.alerts.Generate(ctx, params.ProductID, t.snapshot.ID)
+			if err != nil {
+				return errorResult(CheckCoverage, fmt.Errorf("%s: %w", t.source.Name, err))
+			}
+			for _, alert := range alerts {
+				result.Alerts = append(result.Alerts, alert)
+				result.Details = append(result.Details, fmt.Sprintf("%s: %s", t.source.Name, alert.Message))
+			}
+		}
+	}
+
+	testCoverageMissing, testCoverageLow := false, false
+	if params.MinTestCoverage > 0 && s.testCoverage != nil {
+		checked = true
+		coverages, err := s.testCoverage.ListFileCoverage(ctx, mo.Some(params.ProductID), mo.None[uuid.UUID]())
+		if err != nil {
+			return errorResult(CheckCoverage, err)
+		}
+		var statements, covered int
+		for _, c := range coverages {
+			statements += c.Statements
+			covered += c.Covered
+		}
+		if statements == 0 {
+			testCoverageMissing = true
+			result.Details = append(result.Details, "テストカバレッジが取り込まれていません（coverage import で取り込みます）")
+		} else {
+			percent := testcoverage.Percent(covered, statements)
+			result.TestCoverage = &percent
+			if percent < params.MinTestCoverage {
+				testCoverageLow = true
+				result.Details = append(result.Details, fmt.Sprintf("テストカバレッジ %.1f%% が下限 %.1f%% を下回っています", percent, params.MinTestCoverage))
+			}
+		}
+	}
+
+	switch {
+	case !checked:
+		result.Status = StatusSkip
+		result.Message = "インデックス済みのスナップショットがありません"
+	case len(result.Alerts) > 0 || testCoverageLow:
+		result.Status = StatusFail
+		result.Message = fmt.Sprintf("カバレッジが閾値を満たしていません（%d 件）", len(result.Details))
+	case testCoverageMissing:
+		result.Status = StatusWarn
+		result.Message = "テストカバレッジを判定できませんでした"
+	default:
+		result.Status = StatusPass
+		result.Message = "カバレッジは閾値を満たしています"
+	}
+	return result
+}
+
+// checkWikiLinks は生成済みのWikiのページ間のリンクとソースファイルの引用を、プロダクトの最新のスナップショットに対して検証する
+func (s *Service) checkWikiLinks(ctx context.Context, params Params) *CheckResult {
+	result := &CheckResult{Name: CheckWikiLinks}
+	if skip, ok := wikiDirMissing(params.WikiDir); ok {
+		result.Status = StatusSkip
+		result.Message = skip
+		return result
+	}
+
+	pages, err := wiki.LoadPages(params.WikiDir)
+	if err != nil {
+		return errorResult(CheckWikiLinks, err)
+	}
+	var files []*wiki.SourceFileLines
+	if s.sourceFiles != nil {
+		if files, err = s.sourceFiles.ListSourceFileLines(ctx, mo.Some(params.ProductID), mo.None[uuid.UUID]()); err != nil {
+			return errorResult(CheckWikiLinks, err)
+		}
+		if files == nil {
+			files = []*wiki.SourceFileLines{}
+		}
+	}
+
+	report := wiki.CheckReferences(pages, files)
+	result.BrokenReferences = report.Broken
+	for _, broken := range report.Broken {
+		result.Details = append(result.Details, fmt.Sprintf("%s:%d [%s] %s — %s", broken.Page, broken.Line, broken.Kind, broken.Target, broken.Reason))
+	}
+	result.Message = fmt.Sprintf("%d ページ、%d 件の参照を検証し、%d 件が解決できませんでした（許容 %d 件）",
+		report.CheckedPages, report.CheckedReferences, report.BrokenCount(), params.MaxBroken)
+	switch {
+	case report.Exceeds(params.MaxBroken):
+		result.Status = StatusFail
+	case report.BrokenCount() > 0:
+		result.Status = StatusWarn
+	default:
+		result.Status = StatusPass
+	}
+	return result
+}
+
+// checkStaleDocs はWikiの生成後にインデックス化されたソースと、内容の変更・プロンプトの更新に追従していないファイル要約を検出する
+// 要約のないファイルは要約生成を無効にしている場合もあるため warn とする
+func (s *Service) checkStaleDocs(ctx context.Context, params Params, targets []*target) *CheckResult {
+	result := &CheckResult{Name: CheckStaleDocs}
+	checked, staleWiki := false, false
+	var outdated, missing int
+
+	if _, ok := wikiDirMissing(params.WikiDir); !ok {
+		generatedAt, err := wikiGeneratedAt(params.WikiDir)
+		if err != nil {
+			return errorResult(CheckStaleDocs, err)
+		}
+		checked = true
+		for _, t := range targets {
+			if t.snapshot == nil || t.snapshot.IndexedAt == nil || !t.snapshot.IndexedAt.After(generatedAt) {
+				continue
+			}
+			staleWiki = true
+			result.Details = append(result.Details, fmt.Sprintf("Wiki（%s 生成）の生成後に %s がインデックス化されています（%s）",
+				generatedAt.Format(time.DateTime), t.source.Name, t.snapshot.IndexedAt.Format(time.DateTime)))
+		}
+	}
+
+	if s.summaries != nil {
+		for _, t := range targets {
+			if t.snapshot == nil {
+				continue
+			}
+			checked = true
+			refresh, err := s.summaries.Refresh(ctx, t.snapshot.ID, summary.RefreshOptions{DryRun: true})
+			if err != nil {
+				return errorResult(CheckStaleDocs, fmt.Errorf("%s: %w", t.source.Name, err))
+			}
+			var sourceOutdated, sourceMissing int
+			for _, f := range refresh.Stale {
+				if f.Reason == summary.StaleReasonMissing {
+					sourceMissing++
+				} else {
+					sourceOutdated++
+				}
+			}
+			if sourceOutdated > 0 {
+				result.Details = append(result.Details, fmt.Sprintf("%s: 内容の変更・プロンプトの更新に追従していないファイル要約が %d 件あります（summaries refresh で再生成します）", t.source.Name, sourceOutdated))
+			}
+			if sourceMissing > 0 {
+				result.Details = append(result.Details, fmt.Sprintf("%s: 要約のないファイルが %d 件あります", t.source.Name, sourceMissing))
+			}
+			outdated += sourceOutdated
+			missing += sourceMissing
+		}
+	}
+
+	switch {
+	case !checked:
+		result.Status = StatusSkip
+		result.Message = "生成済みのWiki・インデックス済みのスナップショットがありません"
+	case staleWiki || outdated > 0:
+		result.Status = StatusFail
+		result.Message = "インデックスの更新に追従していないドキュメントがあります"
+	case missing > 0:
+		result.Status = StatusWarn
+		result.Message = fmt.Sprintf("要約のないファイルが %d 件あります", missing)
+	default:
+		result.Status = StatusPass
+		result.Message = "ドキュメントはインデックスの更新に追従しています"
+	}
+	return result
+}
+
+// wikiDirMissing はWikiの出力ディレクトリが未指定・未生成の場合に省略の理由を返す
+func wikiDirMissing(dir string) (string, bool) {
+	if dir == "" {
+		return "Wikiの出力ディレクトリが指定されていません", true
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return fmt.Sprintf("Wikiが生成されていません: %s", dir), true
+	}
+	return "", false
+}
+
+// wikiGeneratedAt はWikiの生成日時（生成時に書き出す link-report.json の更新日時）を返す
+// link-report.json がない場合はページの更新日時の最新のものを使用する
+func wikiGeneratedAt(dir string) (time.Time, error) {
+	if info, err := os.Stat(filepath.Join(dir, wiki.LinkReportFileName)); err == nil {
+		return info.ModTime(), nil
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return time.Time{}, fmt.Errorf("failed to stat %s: %w", wiki.LinkReportFileName, err)
+	}
+
+	var latest time.Time
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(p) != ".md" {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+		return nil
+	})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to read wiki pages: %w", err)
+	}
+	return latest, nil
+}
+
+// errorResult は実行できなかったチェックの結果を返す
+func errorResult(name CheckName, err error) *CheckResult {
+	return &CheckResult{Name: name, Status: StatusError, Message: fmt.Sprintf("チェックを実行できませんでした: %v", err)}
+}
@@ -0,0 +1,342 @@
+package cicheck
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jinford/dev-rag/internal/core/ingestion"
+	"github.com/jinford/dev-rag/internal/core/ingestion/summary"
+	"github.com/jinford/dev-rag/internal/core/testcoverage"
+	"github.com/jinford/dev-rag/internal/core/wiki"
+)
+
+type fakeSources struct {
+	sources   []*ingestion.Source
+	snapshots map[uuid.UUID]*ingestion.SourceSnapshot
+}
+
+func (f *fakeSources) ListSourcesByProductID(ctx context.Context, productID uuid.UUID) ([]*ingestion.Source, error) {
+	return f.sources, nil
+}
+
+func (f *fakeSources) GetLatestIndexedSnapshot(ctx context.Context, sourceID uuid.UUID) (mo.Option[*ingestion.SourceSnapshot], error) {
+	if snapshot, ok := f.snapshots[sourceID]; ok {
+		return mo.Some(snapshot), nil
+	}
+	return mo.None[*ingestion.SourceSnapshot](), nil
+}
+
+type fakePlanner struct {
+	plans  map[string]*ingestion.IndexPlan
+	params []ingestion.IndexParams
+}
+
+func (f *fakePlanner) Plan(ctx context.Context, params ingestion.IndexParams) (*ingestion.IndexPlan, error) {
+	f.params = append(f.params, params)
+	plan, ok := f.plans[params.Identifier]
+	if !ok {
+		return nil, errors.New("repository not found")
+	}
+	return plan, nil
+}
+
+type fakeAlerter struct {
+	alerts map[uuid.UUID][]*ingestion.Alert
+}
+
+func (f *fakeAlerter) Generate(ctx context.Context, productID, snapshotID uuid.UUID) ([]*ingestion.Alert, error) {
+	return f.alerts[snapshotID], nil
+}
+
+type fakeTestCoverage struct {
+	coverages []*testcoverage.FileCoverage
+}
+
+func (f *fakeTestCoverage) ListFileCoverage(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID]) ([]*testcoverage.FileCoverage, error) {
+	return f.coverages, nil
+}
+
+type fakeSourceFiles struct {
+	files []*wiki.SourceFileLines
+}
+
+func (f *fakeSourceFiles) ListSourceFileLines(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID]) ([]*wiki.SourceFileLines, error) {
+	return f.files, nil
+}
+
+type fakeRefresher struct {
+	stale map[uuid.UUID][]*summary.StaleFile
+	err   error
+}
+
+func (f *fakeRefresher) Refresh(ctx context.Context, snapshotID uuid.UUID, opts summary.RefreshOptions) (*summary.RefreshResult, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	if !opts.DryRun {
+		return nil, errors.New("refresh must be a dry run")
+	}
+	return &summary.RefreshResult{SnapshotID: snapshotID, Stale: f.stale[snapshotID]}, nil
+}
+
+// fixture はGitソース・ローカルソース・議論のソースを持つプロダクト
+type fixture struct {
+	productID          uuid.UUID
+	api, docs, threads *ingestion.Source
+	apiSnapshot        *ingestion.SourceSnapshot
+	sources            *fakeSources
+}
+
+func newFixture(indexedAt time.Time) *fixture {
+	f := &fixture{productID: uuid.New()}
+	f.api = &ingestion.Source{ID: uuid.New(), Name: "api", SourceType: ingestion.SourceTypeGit,
+		Metadata: ingestion.SourceMetadata{"url": "git@example.com:org/api.git", "default_ref": "main"}}
+	f.docs = &ingestion.Source{ID: uuid.New(), Name: "/srv/docs", SourceType: ingestion.SourceTypeLocal,
+		Metadata: ingestion.SourceMetadata{"path": "/srv/docs"}}
+	f.threads = &ingestion.Source{ID: uuid.New(), Name: "example.com/org/api/discussions", SourceType: ingestion.SourceTypeDiscussion}
+	f.apiSnapshot = &ingestion.SourceSnapshot{ID: uuid.New(), SourceID: f.api.ID, Status: ingestion.SnapshotIndexed, IndexedAt: &indexedAt}
+	f.sources = &fakeSources{
+		sources:   []*ingestion.Source{f.api, f.docs, f.threads},
+		snapshots: map[uuid.UUID]*ingestion.SourceSnapshot{f.api.ID: f.apiSnapshot},
+	}
+	return f
+}
+
+func (f *fixture) params(checks ...CheckName) Params {
+	return Params{ProductID: f.productID, ProductName: "demo", Checks: checks}
+}
+
+func TestService_IndexDryRun(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(time.Now())
+
+	t.Run("インデックスが最新でないソースは warn", func(t *testing.T) {
+		planner := &fakePlanner{plans: map[string]*ingestion.IndexPlan{
+			"git@example.com:org/api.git": {SourceName: "api", VersionIdentifier: "abc", Added: []string{"new.go"}, Modified: []string{"main.go"}},
+			"/srv/docs":                   {SourceName: "/srv/docs", VersionIdentifier: "v1", AlreadyIndexed: true},
+		}}
+		svc := NewService(f.sources,
+			WithIndexPlanner(ingestion.SourceTypeGit, planner),
+			WithIndexPlanner(ingestion.SourceTypeLocal, planner),
+		)
+
+		report, err := svc.Run(ctx, f.params(CheckIndexDryRun))
+		require.NoError(t, err)
+
+		require.Len(t, report.Checks, 1)
+		check := report.Checks[0]
+		assert.Equal(t, StatusWarn, check.Status)
+		assert.Len(t, check.IndexPlans, 2)
+		assert.Equal(t, []string{"api: 追加 1・変更 1・削除 0（abc）", "/srv/docs: v1 はインデックス済み"}, check.Details)
+		// 参照名はソースのメタデータから引き継ぐ。議論のソースは対象外
+		require.Len(t, planner.params, 2)
+		assert.Equal(t, ingestion.IndexParams{ProductName: "demo", Identifier: "git@example.com:org/api.git", Options: map[string]any{"ref": "main"}}, planner.params[0])
+		assert.Equal(t, StatusWarn, report.Status)
+		assert.Equal(t, ExitCodePass, report.ExitCode)
+	})
+
+	t.Run("取得に失敗したソースがあれば fail", func(t *testing.T) {
+		planner := &fakePlanner{plans: map[string]*ingestion.IndexPlan{}}
+		svc := NewService(f.sources, WithIndexPlanner(ingestion.SourceTypeGit, planner))
+
+		report, err := svc.Run(ctx, f.params(CheckIndexDryRun))
+		require.NoError(t, err)
+
+		assert.Equal(t, StatusFail, report.Checks[0].Status)
+		assert.Equal(t, []string{"api: repository not found"}, report.Checks[0].Details)
+		assert.Equal(t, ExitCodeFailed, report.ExitCode)
+	})
+
+	t.Run("再取得できるソースがなければ skip", func(t *testing.T) {
+		report, err := NewService(f.sources).Run(ctx, f.params(CheckIndexDryRun))
+		require.NoError(t, err)
+
+		assert.Equal(t, StatusSkip, report.Checks[0].Status)
+		assert.Equal(t, StatusPass, report.Status)
+	})
+}
+
+func TestService_Coverage(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(time.Now())
+	alerter := &fakeAlerter{alerts: map[uuid.UUID][]*ingestion.Alert{
+		f.apiSnapshot.ID: {{Severity: ingestion.AlertSeverityWarning, Message: "tests のカバレッジ率 10.0% が閾値 20.0% を下回っています（1/10 ファイル）", Domain: "tests"}},
+	}}
+	tests := &fakeTestCoverage{coverages: []*testcoverage.FileCoverage{
+		{FilePath: "a.go", Statements: 10, Covered: 9},
+		{FilePath: "b.go", Statements: 10, Covered: 5},
+	}}
+
+	t.Run("閾値を下回るドメインとテストカバレッジは fail", func(t *testing.T) {
+		svc := NewService(f.sources, WithCoverageAlerter(alerter), WithTestCoverage(tests))
+		params := f.params(CheckCoverage)
+		params.MinTestCoverage = 80
+
+		report, err := svc.Run(ctx, params)
+		require.NoError(t, err)
+
+		check := report.Checks[0]
+		assert.Equal(t, StatusFail, check.Status)
+		assert.Len(t, check.Alerts, 1)
+		require.NotNil(t, check.TestCoverage)
+		assert.InDelta(t, 70.0, *check.TestCoverage, 0.001)
+		assert.Equal(t, []string{
+			"api: tests のカバレッジ率 10.0% が閾値 20.0% を下回っています（1/10 ファイル）",
+			"テストカバレッジ 70.0% が下限 80.0% を下回っています",
+		}, check.Details)
+	})
+
+	t.Run("テストカバレッジが未取り込みの場合は warn", func(t *testing.T) {
+		svc := NewService(f.sources, WithCoverageAlerter(&fakeAlerter{}), WithTestCoverage(&fakeTestCoverage{}))
+		params := f.params(CheckCoverage)
+		params.MinTestCoverage = 50
+
+		report, err := svc.Run(ctx, params)
+		require.NoError(t, err)
+
+		assert.Equal(t, StatusWarn, report.Checks[0].Status)
+		assert.Nil(t, report.Checks[0].TestCoverage)
+	})
+
+	t.Run("閾値を満たせば pass", func(t *testing.T) {
+		svc := NewService(f.sources, WithCoverageAlerter(&fakeAlerter{}), WithTestCoverage(tests))
+		params := f.params(CheckCoverage)
+		params.MinTestCoverage = 60
+
+		report, err := svc.Run(ctx, params)
+		require.NoError(t, err)
+
+		assert.Equal(t, StatusPass, report.Checks[0].Status)
+	})
+}
+
+func writeWiki(t *testing.T, pages map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range pages {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	return dir
+}
+
+func TestService_WikiLinks(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(time.Now())
+	dir := writeWiki(t, map[string]string{
+		"README.md":       "# demo\n\n- [アーキテクチャ](architecture.md)\n- [削除済み](old.md)\n",
+		"architecture.md": "`internal/api/handler.go:10-20` と `internal/api/removed.go`\n",
+	})
+	svc := NewService(f.sources, WithSourceFiles(&fakeSourceFiles{files: []*wiki.SourceFileLines{{Path: "internal/api/handler.go", LineCount: 50}}}))
+
+	params := f.params(CheckWikiLinks)
+	params.WikiDir = dir
+	params.MaxBroken = 1
+
+	report, err := svc.Run(ctx, params)
+	require.NoError(t, err)
+
+	check := report.Checks[0]
+	assert.Equal(t, StatusFail, check.Status)
+	require.Len(t, check.BrokenReferences, 2)
+	assert.Equal(t, "old.md", check.BrokenReferences[0].Target)
+	assert.Equal(t, "internal/api/removed.go", check.BrokenReferences[1].Target)
+	assert.Equal(t, "2 ページ、4 件の参照を検証し、2 件が解決できませんでした（許容 1 件）", check.Message)
+
+	params.MaxBroken = 2
+	report, err = svc.Run(ctx, params)
+	require.NoError(t, err)
+	assert.Equal(t, StatusWarn, report.Checks[0].Status)
+
+	params.WikiDir = filepath.Join(dir, "missing")
+	report, err = svc.Run(ctx, params)
+	require.NoError(t, err)
+	assert.Equal(t, StatusSkip, report.Checks[0].Status)
+}
+
+func TestService_StaleDocs(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Wikiの生成後にインデックス化されたソースと追従していない要約は fail", func(t *testing.T) {
+		dir := writeWiki(t, map[string]string{"README.md": "# demo\n", wiki.LinkReportFileName: "{}\n"})
+		generatedAt := time.Date(2026, 1, 1, 9, 0, 0, 0, time.Local)
+		require.NoError(t, os.Chtimes(filepath.Join(dir, wiki.LinkReportFileName), generatedAt, generatedAt))
+
+		f := newFixture(generatedAt.Add(time.Hour))
+		refresher := &fakeRefresher{stale: map[uuid.UUID][]*summary.StaleFile{
+			f.apiSnapshot.ID: {
+				{File: &ingestion.File{Path: "a.go"}, Reason: summary.StaleReasonContentChanged},
+				{File: &ingestion.File{Path: "b.go"}, Reason: summary.StaleReasonMissing},
+			},
+		}}
+		svc := NewService(f.sources, WithSummaryRefresher(refresher))
+		params := f.params(CheckStaleDocs)
+		params.WikiDir = dir
+
+		report, err := svc.Run(ctx, params)
+		require.NoError(t, err)
+
+		check := report.Checks[0]
+		assert.Equal(t, StatusFail, check.Status)
+		assert.Equal(t, []string{
+			"Wiki（2026-01-01 09:00:00 生成）の生成後に api がインデックス化されています（2026-01-01 10:00:00）",
+			"api: 内容の変更・プロンプトの更新に追従していないファイル要約が 1 件あります（summaries refresh で再生成します）",
+			"api: 要約のないファイルが 1 件あります",
+		}, check.Details)
+	})
+
+	t.Run("要約のないファイルのみの場合は warn", func(t *testing.T) {
+		f := newFixture(time.Now())
+		refresher := &fakeRefresher{stale: map[uuid.UUID][]*summary.StaleFile{
+			f.apiSnapshot.ID: {{File: &ingestion.File{Path: "b.go"}, Reason: summary.StaleReasonMissing}},
+		}}
+
+		report, err := NewService(f.sources, WithSummaryRefresher(refresher)).Run(ctx, f.params(CheckStaleDocs))
+		require.NoError(t, err)
+
+		assert.Equal(t, StatusWarn, report.Checks[0].Status)
+	})
+
+	t.Run("検出に失敗した場合は error", func(t *testing.T) {
+		f := newFixture(time.Now())
+
+		report, err := NewService(f.sources, WithSummaryRefresher(&fakeRefresher{err: errors.New("db down")})).Run(ctx, f.params(CheckStaleDocs))
+		require.NoError(t, err)
+
+		assert.Equal(t, StatusError, report.Checks[0].Status)
+		assert.Equal(t, StatusError, report.Status)
+		assert.Equal(t, ExitCodeError, report.ExitCode)
+	})
+}
+
+func TestService_Run(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(time.Now())
+
+	t.Run("既定ですべてのチェックを実行する", func(t *testing.T) {
+		report, err := NewService(f.sources).Run(ctx, f.params())
+		require.NoError(t, err)
+
+		var names []CheckName
+		for _, check := range report.Checks {
+			names = append(names, check.Name)
+		}
+		assert.Equal(t, CheckNames, names)
+		assert.Equal(t, "demo", report.Product)
+	})
+
+	t.Run("不明なチェックはエラー", func(t *testing.T) {
+		_, err := NewService(f.sources).Run(ctx, f.params("lint"))
+		assert.Error(t, err)
+	})
+}
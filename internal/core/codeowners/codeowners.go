@@ -0,0 +1,188 @@
+// Package codeowners はリポジトリの CODEOWNERS（GitHub・GitLab）の解析と、ファイルの担当者（チーム・ユーザー）の判定を提供する
+package codeowners
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Paths は CODEOWNERS の配置先（GitHub・GitLab が参照する優先順、最初に見つかったものを使用する）
+var Paths = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS", ".gitlab/CODEOWNERS"}
+
+// sectionHeader は GitLab のセクションの見出し（[Section]、^[Optional Section][2] @default-owner）
+var sectionHeader = regexp.MustCompile(`^\^?\[[^\]]+\](?:\[\d+\])?\s*(.*)$`)
+
+// rule はパターンと担当者の組
+type rule struct {
+	pattern *regexp.Regexp
+	owners  []string
+}
+
+// section は GitLab のセクション（セクションのない CODEOWNERS は1つの無名のセクションとして扱う）
+type section struct {
+	rules []rule
+}
+
+// Ruleset は解析済みの CODEOWNERS
+type Ruleset struct {
+	sections []*section
+}
+
+// Select は CODEOWNERS の配置先の優先順に、最初に見つかったファイルのパスと内容を返す
+// files はパスから内容へのマップ
+func Select(files map[string]string) (string, string, bool) {
+	for _, p := range Paths {
+		if content, ok := files[p]; ok {
+			return p, content, true
+		}
+	}
+	return "", "", false
+}
+
+// IsCodeownersPath はパスが CODEOWNERS の配置先かを判定する
+func IsCodeownersPath(p string) bool {
+	return slices.Contains(Paths, p)
+}
+
+// Parse は CODEOWNERS を解析する
+// ファイル内では後に書かれたルールが優先され、GitLab のセクションがある場合は各セクションで一致したルールの担当者を合わせる
+// 担当者のないルールは、セクションの既定の担当者（なければ担当者なし）とする
+func Parse(content string) *Ruleset {
+	current := &section{}
+	rs := &Ruleset{sections: []*section{current}}
+	var defaults []string
+
+	for _, line := range strings.Split(content, "\n") {
+		line = stripComment(strings.TrimSpace(line))
+		if line == "" {
+			continue
+		}
+		if m := sectionHeader.FindStringSubmatch(line); m != nil {
+			current = &section{}
+			rs.sections = append(rs.sections, current)
+			defaults = strings.Fields(m[1])
+			continue
+		}
+
+		fields := splitFields(line)
+		pattern, err := compilePattern(fields[0])
+		if err != nil {
+			continue
+		}
+		owners := fields[1:]
+		if len(owners) == 0 {
+			owners = defaults
+		}
+		current.rules = append(current.rules, rule{pattern: pattern, owners: owners})
+	}
+	return rs
+}
+
+// Owners はファイルの担当者を返す（一致するルールがない場合は nil）
+func (rs *Ruleset) Owners(filePath string) []string {
+	if rs == nil {
+		return nil
+	}
+	filePath = strings.TrimPrefix(strings.ReplaceAll(filePath, "\\", "/"), "/")
+
+	var owners []string
+	for _, sec := range rs.sections {
+		for i := len(sec.rules) - 1; i >= 0; i-- {
+			r := sec.rules[i]
+			if !r.pattern.MatchString(filePath) {
+				continue
+			}
+			for _, owner := range r.owners {
+				if !slices.Contains(owners, owner) {
+					owners = append(owners, owner)
+				}
+			}
+			break
+		}
+	}
+	return owners
+}
+
+// Empty はルールが1つもないかを返す
+func (rs *Ruleset) Empty() bool {
+	return rs == nil || !slices.ContainsFunc(rs.sections, func(s *section) bool {
+		return len(s.rules) > 0
+	})
+}
+
+// stripComment は行末のコメント（空白に続く #）を取り除く
+// パターン中の # は \# でエスケープされている前提とする
+func stripComment(line string) string {
+	if strings.HasPrefix(line, "#") {
+		return ""
+	}
+	for i := 1; i < len(line); i++ {
+		if line[i] == '#' && (line[i-1] == ' ' || line[i-1] == '\t') {
+			return strings.TrimSpace(line[:i])
+		}
+	}
+	return line
+}
+
+// splitFields は行をパターンと担当者に分割する（パターン中の "\ " は空白として扱う）
+func splitFields(line string) []string {
+	escaped := strings.ReplaceAll(line, `\ `, "\x00")
+	fields := strings.Fields(escaped)
+	for i, f := range fields {
+		fields[i] = strings.ReplaceAll(strings.ReplaceAll(f, "\x00", " "), `\#`, "#")
+	}
+	return fields
+}
+
+// compilePattern は gitignore 形式のパターンを正規表現に変換する
+//   - 先頭の / またはパターンの途中の / はリポジトリのルートからのパスに一致させる（それ以外は任意の深さのファイル名・ディレクトリ名に一致）
+//   - 末尾の / はディレクトリ配下のみに一致させる
+//   - 末尾の /* はディレクトリ直下のファイルのみに一致させる（GitHub の仕様）
+//   - * は / 以外の任意の文字列、** は任意の深さのディレクトリ、? は / 以外の1文字に一致する
+//   - ディレクトリに一致するパターンは配下のすべてのファイルに一致する
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	dirOnly := strings.HasSuffix(pattern, "/")
+	p := strings.Trim(pattern, "/")
+	if p == "" {
+		// "/" はすべてのファイルに一致させる
+		return regexp.Compile(`^.*$`)
+	}
+	anchored := strings.HasPrefix(pattern, "/") || strings.Contains(p, "/")
+	directChildren := strings.HasSuffix(p, "/*") && !strings.HasSuffix(p, "/**/*")
+
+	var sb strings.Builder
+	if anchored {
+		sb.WriteString("^")
+	} else {
+		sb.WriteString("^(?:.*/)?")
+	}
+	for i := 0; i < len(p); i++ {
+		switch {
+		case strings.HasPrefix(p[i:], "**/"):
+			sb.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(p[i:], "/**") && i+3 == len(p):
+			sb.WriteString("/.*")
+			i += 2
+		case strings.HasPrefix(p[i:], "**"):
+			sb.WriteString(".*")
+			i++
+		case p[i] == '*':
+			sb.WriteString("[^/]*")
+		case p[i] == '?':
+			sb.WriteString("[^/]")
+		default:
+			sb.WriteString(regexp.QuoteMeta(p[i : i+1]))
+		}
+	}
+	switch {
+	case directChildren:
+		sb.WriteString("$")
+	case dirOnly:
+		sb.WriteString("/.*$")
+	default:
+		sb.WriteString("(?:/.*)?$")
+	}
+	return regexp.Compile(sb.String())
+}
@@ -0,0 +1,79 @@
+package codeowners
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse_GitHub(t *testing.T) {
+	rs := Parse(`# 既定の担当
+*       @acme/platform
+
+# ドキュメント
+*.md    @acme/docs docs@example.com
+/docs/* @acme/writers
+
+apps/           @acme/apps
+/build/logs/    @acme/build   # ログ
+**/migrations   @acme/dba
+internal/core/ingestion/generated.go
+`)
+
+	assert.False(t, rs.Empty())
+	assert.Equal(t, []string{"@acme/platform"}, rs.Owners("main.go"))
+	assert.Equal(t, []string{"@acme/docs", "docs@example.com"}, rs.Owners("internal/README.md"))
+	// 後に書かれたルールが優先される
+	assert.Equal(t, []string{"@acme/writers"}, rs.Owners("docs/getting-started.md"))
+	// /docs/* は直下のファイルのみに一致する
+	assert.Equal(t, []string{"@acme/docs", "docs@example.com"}, rs.Owners("docs/build/troubleshooting.md"))
+	// 末尾の / のみのパターンは任意の深さのディレクトリに一致する
+	assert.Equal(t, []string{"@acme/apps"}, rs.Owners("apps/web/index.ts"))
+	assert.Equal(t, []string{"@acme/apps"}, rs.Owners("services/apps/main.go"))
+	// 先頭の / を含むパターンはルートからのパスに一致する
+	assert.Equal(t, []string{"@acme/build"}, rs.Owners("build/logs/2024/app.log"))
+	assert.Equal(t, []string{"@acme/platform"}, rs.Owners("services/build/logs/app.log"))
+	assert.Equal(t, []string{"@acme/dba"}, rs.Owners("services/api/migrations/001_init.sql"))
+	// 担当者のないルールは担当者なしとする
+	assert.Nil(t, rs.Owners("internal/core/ingestion/generated.go"))
+}
+
+func TestParse_UnanchoredName(t *testing.T) {
+	rs := Parse("docs @acme/docs\n")
+
+	assert.Equal(t, []string{"@acme/docs"}, rs.Owners("docs/a.md"))
+	assert.Equal(t, []string{"@acme/docs"}, rs.Owners("services/api/docs/b.md"))
+	assert.Nil(t, rs.Owners("documents/c.md"))
+}
+
+func TestParse_GitLabSections(t *testing.T) {
+	rs := Parse(`* @acme/platform
+
+[Backend] @acme/backend
+internal/
+internal/infra/postgres/ @acme/dba
+
+^[Security][2] @acme/security
+**/auth/**
+`)
+
+	assert.Equal(t, []string{"@acme/platform", "@acme/backend"}, rs.Owners("internal/core/ask/service.go"))
+	assert.Equal(t, []string{"@acme/platform", "@acme/dba"}, rs.Owners("internal/infra/postgres/repository.go"))
+	assert.Equal(t, []string{"@acme/platform", "@acme/backend", "@acme/security"}, rs.Owners("internal/auth/token.go"))
+	assert.Equal(t, []string{"@acme/platform"}, rs.Owners("cmd/main.go"))
+}
+
+func TestSelect(t *testing.T) {
+	path, content, ok := Select(map[string]string{
+		"CODEOWNERS":         "* @root",
+		".github/CODEOWNERS": "* @github",
+		"main.go":            "package main",
+	})
+	assert.True(t, ok)
+	assert.Equal(t, ".github/CODEOWNERS", path)
+	assert.Equal(t, "* @github", content)
+
+	_, _, ok = Select(map[string]string{"main.go": "package main"})
+	assert.False(t, ok)
+	assert.True(t, Parse("# comment only\n").Empty())
+}
@@ -0,0 +1,203 @@
+// Package coverage はスナップショットのカバレッジ（snapshot_files）をプロダクトの閾値と比較してアラートを生成する
+package coverage
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/jinford/dev-rag/internal/core/ingestion"
+	"github.com/jinford/dev-rag/internal/core/productconfig"
+)
+
+// Thresholds はカバレッジアラートの閾値
+type Thresholds struct {
+	MinCoverage                map[string]float64 // ドメインから最小カバレッジ率（%）へのマップ（ないドメインは判定しない）
+	MaxUnindexedImportantFiles int                // 許容する未インデックスの重要ファイル数
+}
+
+// DefaultThresholds はプロダクトで設定されていない場合の閾値を返す
+//   - テストコードのカバレッジ率が 20% 未満
+//   - 設計書・ADR などのドキュメントのカバレッジ率が 50% 未満
+//   - 重要ファイル（README・ADR など）が1件でも未インデックス
+func DefaultThresholds() Thresholds {
+	return Thresholds{
+		MinCoverage: map[string]float64{
+			ingestion.DomainTests:        20,
+			ingestion.DomainArchitecture: 50,
+		},
+		MaxUnindexedImportantFiles: 0,
+	}
+}
+
+// ThresholdsFromConfig はプロダクトの設定で既定の閾値を上書きした閾値を返す
+func ThresholdsFromConfig(cfg productconfig.Config) Thresholds {
+	return DefaultThresholds().Override(cfg)
+}
+
+// Override はプロダクトの設定で閾値を上書きした閾値を返す（元の閾値は変更しない）
+func (t Thresholds) Override(cfg productconfig.Config) Thresholds {
+	out := Thresholds{
+		MinCoverage:                make(map[string]float64, len(t.MinCoverage)),
+		MaxUnindexedImportantFiles: t.MaxUnindexedImportantFiles,
+	}
+	for domain, v := range t.MinCoverage {
+		out.MinCoverage[domain] = v
+	}
+	for domain := range cfg.WithPrefix(productconfig.KeyMinCoveragePrefix) {
+		if v, ok := cfg.Float(productconfig.KeyMinCoveragePrefix + domain); ok {
+			out.MinCoverage[domain] = v
+		}
+	}
+	if v, ok := cfg.Int(productconfig.KeyMaxUnindexedImportantFiles); ok {
+		out.MaxUnindexedImportantFiles = v
+	}
+	return out
+}
+
+// Repository はカバレッジの集計を取得するインターフェース
+type Repository interface {
+	GetDomainCoverageStats(ctx context.Context, snapshotID uuid.UUID) ([]*ingestion.DomainCoverage, error)
+	GetUnindexedImportantFiles(ctx context.Context, snapshotID uuid.UUID) ([]string, error)
+}
+
+// ConfigReader はプロダクトの設定を取得するインターフェース
+type ConfigReader interface {
+	List(ctx context.Context, productID uuid.UUID) ([]*productconfig.Entry, error)
+}
+
+// AlertGenerator はスナップショットのカバレッジをプロダクトの閾値と比較してアラートを生成する
+type AlertGenerator struct {
+	repo     Repository
+	configs  ConfigReader
+	defaults Thresholds
+	logger   *slog.Logger
+	now      func() time.Time
+}
+
+// AlertGeneratorOption は AlertGenerator のオプション設定
+type AlertGeneratorOption func(*AlertGenerator)
+
+// WithAlertGeneratorLogger は AlertGenerator にロガーを設定する
+func WithAlertGeneratorLogger(logger *slog.Logger) AlertGeneratorOption {
+	return func(g *AlertGenerator) {
+		g.logger = logger
+	}
+}
+
+// WithProductConfig はプロダクトごとの閾値を設定から読み込むための設定を行う（未設定の場合は既定の閾値）
+func WithProductConfig(configs ConfigReader) AlertGeneratorOption {
+	return func(g *AlertGenerator) {
+		g.configs = configs
+	}
+}
+
+// WithDefaultThresholds はプロダクトで設定されていない場合の閾値を設定する（未設定の場合は DefaultThresholds）
+func WithDefaultThresholds(t Thresholds) AlertGeneratorOption {
+	return func(g *AlertGenerator) {
+		g.defaults = t
+	}
+}
+
+// NewAlertGenerator は新しい AlertGenerator を作成する
+func NewAlertGenerator(repo Repository, opts ...AlertGeneratorOption) *AlertGenerator {
+	g := &AlertGenerator{
+		repo:     repo,
+		defaults: DefaultThresholds(),
+		logger:   slog.Default(),
+		now:      time.Now,
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	if g.logger == nil {
+		g.logger = slog.Default()
+	}
+	return g
+}
+
+// Thresholds はプロダクトのカバレッジアラートの閾値を返す
+func (g *AlertGenerator) Thresholds(ctx context.Context, productID uuid.UUID) (Thresholds, error) {
+	if g.configs == nil {
+		return g.defaults, nil
+	}
+	entries, err := g.configs.List(ctx, productID)
+	if err != nil {
+		return Thresholds{}, fmt.Errorf("failed to list product configs: %w", err)
+	}
+	return g.defaults.Override(productconfig.NewConfig(entries)), nil
+}
+
+// Generate はスナップショットのカバレッジをプロダクトの閾値と比較してアラートを返す
+// snapshot_files が記録されていないスナップショットではアラートを生成しない
+func (g *AlertGenerator) Generate(ctx context.Context, productID, snapshotID uuid.UUID) ([]*ingestion.Alert, error) {
+	thresholds, err := g.Thresholds(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	coverages, err := g.repo.GetDomainCoverageStats(ctx, snapshotID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get domain coverage stats: %w", err)
+	}
+	if len(coverages) == 0 {
+		return nil, nil
+	}
+	unindexed, err := g.repo.GetUnindexedImportantFiles(ctx, snapshotID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unindexed important files: %w", err)
+	}
+
+	alerts := Evaluate(coverages, unindexed, thresholds, g.now())
+	g.logger.Info("evaluated coverage alerts", "snapshotID", snapshotID, "alerts", len(alerts))
+	return alerts, nil
+}
+
+// Evaluate はドメイン別のカバレッジと未インデックスの重要ファイルを閾値と比較してアラートを返す
+//   - 最小カバレッジ率を下回るドメインは warning
+//   - 未インデックスの重要ファイルが上限を超える場合は error
+func Evaluate(coverages []*ingestion.DomainCoverage, unindexedImportant []string, thresholds Thresholds, now time.Time) []*ingestion.Alert {
+	var alerts []*ingestion.Alert
+
+	byDomain := make(map[string]*ingestion.DomainCoverage, len(coverages))
+	for _, c := range coverages {
+		byDomain[c.Domain] = c
+	}
+	for _, domain := range slices.Sorted(maps.Keys(thresholds.MinCoverage)) {
+		minRate := thresholds.MinCoverage[domain]
+		c, ok := byDomain[domain]
+		if !ok || c.TotalFiles == 0 || c.CoverageRate >= minRate {
+			continue
+		}
+		alerts = append(alerts, &ingestion.Alert{
+			Severity: ingestion.AlertSeverityWarning,
+			Message:  fmt.Sprintf("%s のカバレッジ率 %.1f%% が閾値 %.1f%% を下回っています（%d/%d ファイル）", domain, c.CoverageRate, minRate, c.IndexedFiles, c.TotalFiles),
+			Domain:   domain,
+			Details: map[string]any{
+				"coverageRate": c.CoverageRate,
+				"minCoverage":  minRate,
+				"indexedFiles": c.IndexedFiles,
+				"totalFiles":   c.TotalFiles,
+			},
+			GeneratedAt: now,
+		})
+	}
+
+	if len(unindexedImportant) > thresholds.MaxUnindexedImportantFiles {
+		alerts = append(alerts, &ingestion.Alert{
+			Severity: ingestion.AlertSeverityError,
+			Message:  fmt.Sprintf("未インデックスの重要ファイルが %d 件あります（上限 %d 件）", len(unindexedImportant), thresholds.MaxUnindexedImportantFiles),
+			Details: map[string]any{
+				"files":    unindexedImportant,
+				"maxFiles": thresholds.MaxUnindexedImportantFiles,
+			},
+			GeneratedAt: now,
+		})
+	}
+	return alerts
+}
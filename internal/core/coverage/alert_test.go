@@ -0,0 +1,117 @@
+package coverage
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jinford/dev-rag/internal/core/ingestion"
+	"github.com/jinford/dev-rag/internal/core/productconfig"
+)
+
+type stubCoverageRepo struct {
+	coverages []*ingestion.DomainCoverage
+	unindexed []string
+}
+
+func (r *stubCoverageRepo) GetDomainCoverageStats(ctx context.Context, snapshotID uuid.UUID) ([]*ingestion.DomainCoverage, error) {
+	return r.coverages, nil
+}
+
+func (r *stubCoverageRepo) GetUnindexedImportantFiles(ctx context.Context, snapshotID uuid.UUID) ([]string, error) {
+	return r.unindexed, nil
+}
+
+type stubConfigReader struct {
+	entries []*productconfig.Entry
+}
+
+func (r *stubConfigReader) List(ctx context.Context, productID uuid.UUID) ([]*productconfig.Entry, error) {
+	return r.entries, nil
+}
+
+func TestAlertGenerator_Generate(t *testing.T) {
+	repo := &stubCoverageRepo{
+		coverages: []*ingestion.DomainCoverage{
+			{Domain: "code", TotalFiles: 100, IndexedFiles: 55, CoverageRate: 55},
+			{Domain: "tests", TotalFiles: 40, IndexedFiles: 10, CoverageRate: 25},
+			{Domain: "architecture", TotalFiles: 10, IndexedFiles: 4, CoverageRate: 40},
+		},
+		unindexed: []string{"README.md", "docs/adr/ADR-001.md"},
+	}
+	now := time.Date(2025, 1, 8, 0, 0, 0, 0, time.UTC)
+
+	t.Run("既定の閾値", func(t *testing.T) {
+		g := NewAlertGenerator(repo, WithAlertGeneratorLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+		g.now = func() time.Time { return now }
+
+		alerts, err := g.Generate(context.Background(), uuid.New(), uuid.New())
+		require.NoError(t, err)
+		require.Len(t, alerts, 2)
+		assert.Equal(t, ingestion.AlertSeverityWarning, alerts[0].Severity)
+		assert.Equal(t, "architecture", alerts[0].Domain)
+		assert.Equal(t, ingestion.AlertSeverityError, alerts[1].Severity)
+		assert.Contains(t, alerts[1].Message, "2 件")
+		assert.Equal(t, now, alerts[1].GeneratedAt)
+	})
+
+	t.Run("プロダクトの閾値", func(t *testing.T) {
+		configs := &stubConfigReader{entries: []*productconfig.Entry{
+			{Key: "alert.min_coverage.code", Value: "60"},
+			{Key: "alert.min_coverage.tests", Value: "30"},
+			{Key: "alert.min_coverage.architecture", Value: "0"},
+			{Key: "alert.max_unindexed_important_files", Value: "2"},
+		}}
+		g := NewAlertGenerator(repo, WithProductConfig(configs), WithAlertGeneratorLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+
+		alerts, err := g.Generate(context.Background(), uuid.New(), uuid.New())
+		require.NoError(t, err)
+		require.Len(t, alerts, 2)
+		assert.Equal(t, "code", alerts[0].Domain)
+		assert.Equal(t, "tests", alerts[1].Domain)
+		assert.Contains(t, alerts[1].Message, "25.0% が閾値 30.0%")
+	})
+
+	t.Run("既定の閾値の上書き", func(t *testing.T) {
+		defaults := DefaultThresholds()
+		defaults.MinCoverage["architecture"] = 30
+		defaults.MaxUnindexedImportantFiles = 2
+		configs := &stubConfigReader{entries: []*productconfig.Entry{
+			{Key: "alert.min_coverage.tests", Value: "30"},
+		}}
+		g := NewAlertGenerator(repo, WithProductConfig(configs), WithDefaultThresholds(defaults), WithAlertGeneratorLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+
+		alerts, err := g.Generate(context.Background(), uuid.New(), uuid.New())
+		require.NoError(t, err)
+		require.Len(t, alerts, 1)
+		assert.Equal(t, "tests", alerts[0].Domain)
+
+		// プロダクトの設定で既定の閾値は変更されない
+		assert.Equal(t, 20.0, defaults.MinCoverage["tests"])
+	})
+}
+
+func TestAlertGenerator_NoSnapshotFiles(t *testing.T) {
+	g := NewAlertGenerator(&stubCoverageRepo{unindexed: []string{"README.md"}})
+	alerts, err := g.Generate(context.Background(), uuid.New(), uuid.New())
+	require.NoError(t, err)
+	assert.Empty(t, alerts)
+}
+
+func TestThresholdsFromConfig(t *testing.T) {
+	thresholds := ThresholdsFromConfig(productconfig.Config{
+		"alert.min_coverage.infra":            "10",
+		"alert.max_unindexed_important_files": "5",
+	})
+	assert.Equal(t, map[string]float64{"tests": 20, "architecture": 50, "infra": 10}, thresholds.MinCoverage)
+	assert.Equal(t, 5, thresholds.MaxUnindexedImportantFiles)
+
+	// 既定値は共有されない
+	assert.NotContains(t, DefaultThresholds().MinCoverage, "infra")
+}
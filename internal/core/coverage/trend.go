@@ -0,0 +1,87 @@
+package coverage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TrendPoint はスナップショットのドメイン別カバレッジの記録（coverage_history）
+type TrendPoint struct {
+	SnapshotID    uuid.UUID `json:"snapshotID"`
+	SourceName    string    `json:"sourceName"`
+	Version       string    `json:"version"`
+	Domain        string    `json:"domain"`
+	TotalFiles    int       `json:"totalFiles"`
+	IndexedFiles  int       `json:"indexedFiles"`
+	IndexedChunks int       `json:"indexedChunks"`
+	CoverageRate  float64   `json:"coverageRate"`
+	RecordedAt    time.Time `json:"recordedAt"`
+}
+
+// TrendFilter はカバレッジの推移の取得条件（ゼロ値の項目は絞り込まない）
+type TrendFilter struct {
+	Domain string
+	Since  time.Time
+}
+
+// HistoryRepository はカバレッジの履歴を取得するインターフェース
+type HistoryRepository interface {
+	// ListCoverageHistory はプロダクトの各ソースのカバレッジの履歴を記録日時の古い順に取得する
+	ListCoverageHistory(ctx context.Context, productID uuid.UUID, filter TrendFilter) ([]*TrendPoint, error)
+}
+
+// Series はソース・ドメインごとのカバレッジの推移
+type Series struct {
+	SourceName string        `json:"sourceName"`
+	Domain     string        `json:"domain"`
+	Points     []*TrendPoint `json:"points"`
+	Change     float64       `json:"change"` // 最初の記録から最新の記録までのカバレッジ率の変化（ポイント）
+}
+
+// Latest は最新の記録を返す
+func (s *Series) Latest() *TrendPoint {
+	return s.Points[len(s.Points)-1]
+}
+
+// TrendService はプロダクトのカバレッジの推移を提供する
+type TrendService struct {
+	repo HistoryRepository
+}
+
+// NewTrendService は新しい TrendService を作成する
+func NewTrendService(repo HistoryRepository) *TrendService {
+	return &TrendService{repo: repo}
+}
+
+// Trend はプロダクトのカバレッジの推移をソース・ドメインごとに返す
+func (s *TrendService) Trend(ctx context.Context, productID uuid.UUID, filter TrendFilter) ([]*Series, error) {
+	points, err := s.repo.ListCoverageHistory(ctx, productID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list coverage history: %w", err)
+	}
+	return BuildSeries(points), nil
+}
+
+// BuildSeries は記録日時の古い順の記録をソース・ドメインごとの推移にまとめる（最初に現れた順）
+func BuildSeries(points []*TrendPoint) []*Series {
+	type key struct{ source, domain string }
+	index := make(map[key]*Series)
+	var series []*Series
+	for _, p := range points {
+		k := key{p.SourceName, p.Domain}
+		s, ok := index[k]
+		if !ok {
+			s = &Series{SourceName: p.SourceName, Domain: p.Domain}
+			index[k] = s
+			series = append(series, s)
+		}
+		s.Points = append(s.Points, p)
+	}
+	for _, s := range series {
+		s.Change = s.Latest().CoverageRate - s.Points[0].CoverageRate
+	}
+	return series
+}
@@ -0,0 +1,55 @@
+package coverage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubHistoryRepo struct {
+	points []*TrendPoint
+	filter TrendFilter
+}
+
+func (r *stubHistoryRepo) ListCoverageHistory(ctx context.Context, productID uuid.UUID, filter TrendFilter) ([]*TrendPoint, error) {
+	r.filter = filter
+	return r.points, nil
+}
+
+func TestTrendService_Trend(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC) }
+	repo := &stubHistoryRepo{points: []*TrendPoint{
+		{SourceName: "api", Domain: "architecture", CoverageRate: 40, RecordedAt: day(1)},
+		{SourceName: "api", Domain: "tests", CoverageRate: 20, RecordedAt: day(1)},
+		{SourceName: "web", Domain: "architecture", CoverageRate: 70, RecordedAt: day(2)},
+		{SourceName: "api", Domain: "architecture", CoverageRate: 55.5, RecordedAt: day(8)},
+		{SourceName: "api", Domain: "tests", CoverageRate: 15, RecordedAt: day(8)},
+	}}
+
+	filter := TrendFilter{Domain: "architecture", Since: day(1)}
+	series, err := NewTrendService(repo).Trend(context.Background(), uuid.New(), filter)
+	require.NoError(t, err)
+	assert.Equal(t, filter, repo.filter)
+
+	require.Len(t, series, 3)
+	assert.Equal(t, "api", series[0].SourceName)
+	assert.Equal(t, "architecture", series[0].Domain)
+	assert.Len(t, series[0].Points, 2)
+	assert.InDelta(t, 15.5, series[0].Change, 1e-9)
+	assert.Equal(t, day(8), series[0].Latest().RecordedAt)
+
+	assert.Equal(t, "tests", series[1].Domain)
+	assert.InDelta(t, -5, series[1].Change, 1e-9)
+
+	// 記録が1件のみの場合は変化なし
+	assert.Equal(t, "web", series[2].SourceName)
+	assert.Zero(t, series[2].Change)
+}
+
+func TestBuildSeries_Empty(t *testing.T) {
+	assert.Nil(t, BuildSeries(nil))
+}
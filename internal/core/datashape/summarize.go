@@ -0,0 +1,186 @@
+package datashape
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Format は構造化データの形式
+type Format string
+
+const (
+	FormatCSV   Format = "CSV"
+	FormatTSV   Format = "TSV"
+	FormatJSON  Format = "JSON"
+	FormatJSONL Format = "JSON Lines"
+	FormatYAML  Format = "YAML"
+)
+
+const (
+	// maxFields は要約に含めるフィールド（列・キーのパス）の最大数
+	maxFields = 100
+	// maxExamples はフィールドごとに示す値の例の最大数
+	maxExamples = 3
+	// maxExampleLength は値の例の最大文字数
+	maxExampleLength = 40
+)
+
+// DetectFormat はファイルの拡張子から構造化データの形式を判定する（構造化データでない場合は false）
+func DetectFormat(filePath string) (Format, bool) {
+	switch strings.ToLower(path.Ext(filePath)) {
+	case ".csv":
+		return FormatCSV, true
+	case ".tsv":
+		return FormatTSV, true
+	case ".json":
+		return FormatJSON, true
+	case ".jsonl", ".ndjson":
+		return FormatJSONL, true
+	case ".yaml", ".yml":
+		return FormatYAML, true
+	default:
+		return "", false
+	}
+}
+
+// Summary は構造化データのスキーマの要約
+type Summary struct {
+	Format  Format
+	Records int      // 行数（CSV・TSV）・レコード数（JSON Lines）・ドキュメント数（YAML）、JSON は 0
+	Fields  []*Field // 列・キーのパスの出現順
+	Omitted int      // maxFields を超えて省略したフィールド数
+}
+
+// Field は列・キーのパスごとに集計した型と値の例
+type Field struct {
+	Path     string   // CSV・TSV は列名、JSON・YAML は $ から始まるキーのパス（配列の要素は []）
+	Types    []string // 出現した型（object, array, string, integer, number, boolean, null）
+	Examples []string // 代表的な値の例（重複なし）
+	MaxItems int      // 配列の最大要素数
+}
+
+// Summarize は構造化データのファイルからスキーマの要約（キー・型・代表的な値）を生成する
+// 構造化データでない場合や解析できない場合は false を返す
+func Summarize(filePath, content string) (*Summary, bool) {
+	format, ok := DetectFormat(filePath)
+	if !ok {
+		return nil, false
+	}
+
+	var summary *Summary
+	switch format {
+	case FormatCSV:
+		summary, ok = summarizeTable(content, ',')
+	case FormatTSV:
+		summary, ok = summarizeTable(content, '\t')
+	case FormatJSON:
+		summary, ok = summarizeJSON(content)
+	case FormatJSONL:
+		summary, ok = summarizeJSONLines(content)
+	case FormatYAML:
+		summary, ok = summarizeYAML(content)
+	}
+	if !ok || len(summary.Fields) == 0 {
+		return nil, false
+	}
+	summary.Format = format
+	return summary, true
+}
+
+// Text はインデックス化する要約のテキストを返す
+func (s *Summary) Text(filePath string, size int64) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Structured Data: %s\n", s.Format))
+	sb.WriteString(fmt.Sprintf("File: %s\n", filePath))
+	sb.WriteString(fmt.Sprintf("Size: %d bytes\n", size))
+	switch s.Format {
+	case FormatCSV, FormatTSV:
+		sb.WriteString(fmt.Sprintf("Rows: %d\n", s.Records))
+	case FormatJSONL:
+		sb.WriteString(fmt.Sprintf("Records: %d\n", s.Records))
+	case FormatYAML:
+		sb.WriteString(fmt.Sprintf("Documents: %d\n", s.Records))
+	}
+
+	if s.Format == FormatCSV || s.Format == FormatTSV {
+		sb.WriteString("\nColumns:\n")
+	} else {
+		sb.WriteString("\nFields:\n")
+	}
+	for _, f := range s.Fields {
+		sb.WriteString(fmt.Sprintf("- %s: %s", f.Path, strings.Join(f.Types, " | ")))
+		if f.MaxItems > 0 {
+			sb.WriteString(fmt.Sprintf(" (max %d items)", f.MaxItems))
+		}
+		if len(f.Examples) > 0 {
+			sb.WriteString(fmt.Sprintf(" (e.g. %s)", strings.Join(f.Examples, ", ")))
+		}
+		sb.WriteString("\n")
+	}
+	if s.Omitted > 0 {
+		sb.WriteString(fmt.Sprintf("... (%d more fields)\n", s.Omitted))
+	}
+	return sb.String()
+}
+
+// fieldSet は出現順を保ってフィールドを集計する
+type fieldSet struct {
+	fields  []*Field
+	index   map[string]*Field
+	omitted map[string]struct{}
+}
+
+func newFieldSet() *fieldSet {
+	return &fieldSet{
+		index:   make(map[string]*Field),
+		omitted: make(map[string]struct{}),
+	}
+}
+
+// get はフィールドを返す（maxFields を超えた新しいフィールドは省略として数え nil を返す）
+func (s *fieldSet) get(path string) *Field {
+	if f, ok := s.index[path]; ok {
+		return f
+	}
+	if len(s.fields) >= maxFields {
+		s.omitted[path] = struct{}{}
+		return nil
+	}
+	f := &Field{Path: path}
+	s.fields = append(s.fields, f)
+	s.index[path] = f
+	return f
+}
+
+func (s *fieldSet) summary(records int) *Summary {
+	return &Summary{
+		Records: records,
+		Fields:  s.fields,
+		Omitted: len(s.omitted),
+	}
+}
+
+func (f *Field) addType(typ string) {
+	for _, t := range f.Types {
+		if t == typ {
+			return
+		}
+	}
+	f.Types = append(f.Types, typ)
+}
+
+func (f *Field) addExample(value string) {
+	if len(f.Examples) >= maxExamples || value == "" {
+		return
+	}
+	if runes := []rune(value); len(runes) > maxExampleLength {
+		value = string(runes[:maxExampleLength]) + "..."
+	}
+	for _, e := range f.Examples {
+		if e == value {
+			return
+		}
+	}
+	f.Examples = append(f.Examples, value)
+}
@@ -0,0 +1,100 @@
+package datashape
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func findField(t *testing.T, summary *Summary, path string) *Field {
+	t.Helper()
+	for _, f := range summary.Fields {
+		if f.Path == path {
+			return f
+		}
+	}
+	t.Fatalf("field not found: %s", path)
+	return nil
+}
+
+func TestSummarize_CSV(t *testing.T) {
+	content := "id,name,price,active,note\n1,Widget,9.5,true,\n2,Gadget,10,false,\n3,\"Doohickey, large\",12.25,true,\n"
+
+	summary, ok := Summarize("data/products.csv", content)
+	require.True(t, ok)
+	assert.Equal(t, FormatCSV, summary.Format)
+	assert.Equal(t, 3, summary.Records)
+
+	assert.Equal(t, []string{"integer"}, findField(t, summary, "id").Types)
+	assert.Equal(t, []string{"number"}, findField(t, summary, "price").Types)
+	assert.Equal(t, []string{"boolean"}, findField(t, summary, "active").Types)
+	assert.Equal(t, []string{"null"}, findField(t, summary, "note").Types)
+
+	name := findField(t, summary, "name")
+	assert.Equal(t, []string{"string"}, name.Types)
+	assert.Equal(t, []string{"Widget", "Gadget", "Doohickey, large"}, name.Examples)
+
+	text := summary.Text("data/products.csv", int64(len(content)))
+	assert.Contains(t, text, "Structured Data: CSV")
+	assert.Contains(t, text, "Rows: 3")
+	assert.Contains(t, text, "- price: number (e.g. 9.5, 10, 12.25)")
+}
+
+func TestSummarize_JSON(t *testing.T) {
+	content := `{"version": 2, "items": [{"id": 1, "tags": ["a"], "meta": null}, {"id": 2.5, "tags": [], "owner": {"name": "alice"}}], "display name": "x"}`
+
+	summary, ok := Summarize("fixtures/catalog.json", content)
+	require.True(t, ok)
+
+	assert.Equal(t, []string{"object"}, findField(t, summary, "$").Types)
+	assert.Equal(t, 2, findField(t, summary, "$.items").MaxItems)
+	assert.Equal(t, []string{"integer", "number"}, findField(t, summary, "$.items[].id").Types)
+	assert.Equal(t, []string{"null"}, findField(t, summary, "$.items[].meta").Types)
+	assert.Equal(t, []string{`"alice"`}, findField(t, summary, "$.items[].owner.name").Examples)
+	assert.Equal(t, []string{"string"}, findField(t, summary, `$["display name"]`).Types)
+
+	assert.Contains(t, summary.Text("fixtures/catalog.json", int64(len(content))), "- $.items: array (max 2 items)")
+}
+
+func TestSummarize_YAMLDocuments(t *testing.T) {
+	content := "name: api\nreplicas: 3\n---\nname: worker\nports:\n  - 8080\n"
+
+	summary, ok := Summarize("deploy/values.yaml", content)
+	require.True(t, ok)
+	assert.Equal(t, FormatYAML, summary.Format)
+	assert.Equal(t, 2, summary.Records)
+	assert.Equal(t, []string{`"api"`, `"worker"`}, findField(t, summary, "$.name").Examples)
+	assert.Equal(t, []string{"integer"}, findField(t, summary, "$.ports[]").Types)
+}
+
+func TestSummarize_JSONLines(t *testing.T) {
+	summary, ok := Summarize("logs/events.jsonl", "{\"type\":\"click\"}\n\n{\"type\":\"view\",\"ms\":12}\n")
+	require.True(t, ok)
+	assert.Equal(t, 2, summary.Records)
+	assert.Equal(t, []string{"integer"}, findField(t, summary, "$.ms").Types)
+}
+
+func TestSummarize_Unsupported(t *testing.T) {
+	_, ok := Summarize("main.go", "package main")
+	assert.False(t, ok)
+
+	_, ok = Summarize("broken.json", `{"a":`)
+	assert.False(t, ok)
+}
+
+func TestSummarize_LimitsFields(t *testing.T) {
+	content := "{"
+	for i := range maxFields + 5 {
+		if i > 0 {
+			content += ","
+		}
+		content += `"k` + string(rune('a'+i%26)) + string(rune('a'+i/26)) + `": 1`
+	}
+	content += "}"
+
+	summary, ok := Summarize("wide.json", content)
+	require.True(t, ok)
+	assert.Len(t, summary.Fields, maxFields)
+	assert.Equal(t, 6, summary.Omitted) // ルートの $ を含めて maxFields 件
+}
@@ -0,0 +1,100 @@
+package datashape
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// maxTypeSampleRows は列の型の推定に使用する最大行数（行数は全行を数える）
+const maxTypeSampleRows = 1000
+
+// summarizeTable は先頭行を列名として CSV・TSV の列ごとの型と値の例を集計する
+func summarizeTable(content string, delimiter rune) (*Summary, bool) {
+	reader := csv.NewReader(strings.NewReader(content))
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err != nil || len(header) == 0 {
+		return nil, false
+	}
+	fields := newFieldSet()
+	columns := make([]*Field, len(header))
+	kinds := make([]string, len(header))
+	for i, name := range header {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			name = "column" + strconv.Itoa(i+1)
+		}
+		columns[i] = fields.get(name)
+	}
+
+	rows := 0
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, false
+		}
+		rows++
+		if rows > maxTypeSampleRows {
+			continue
+		}
+		for i, value := range record {
+			if i >= len(columns) || columns[i] == nil {
+				continue
+			}
+			value = strings.TrimSpace(value)
+			if value == "" {
+				continue
+			}
+			kinds[i] = mergeScalarKind(kinds[i], scalarKind(value))
+			columns[i].addExample(value)
+		}
+	}
+
+	for i, column := range columns {
+		if column == nil {
+			continue
+		}
+		if kinds[i] == "" {
+			kinds[i] = "null"
+		}
+		column.addType(kinds[i])
+	}
+	return fields.summary(rows), true
+}
+
+// scalarKind は CSV の値の型（integer, number, boolean, string）を推定する
+func scalarKind(value string) string {
+	if _, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return "integer"
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return "number"
+	}
+	switch strings.ToLower(value) {
+	case "true", "false":
+		return "boolean"
+	}
+	return "string"
+}
+
+// mergeScalarKind は列の型に新しい値の型を合成する（integer と number は number、それ以外の混在は string）
+func mergeScalarKind(current, next string) string {
+	switch {
+	case current == "" || current == next:
+		return next
+	case (current == "integer" && next == "number") || (current == "number" && next == "integer"):
+		return "number"
+	default:
+		return "string"
+	}
+}
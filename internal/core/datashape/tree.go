@@ -0,0 +1,168 @@
+package datashape
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// maxDepth は集計するキーのパスの最大の深さ
+	maxDepth = 8
+	// maxArraySamples は配列ごとに集計する最大要素数
+	maxArraySamples = 100
+	// maxRecordSamples は JSON Lines・YAML で集計する最大レコード（ドキュメント）数（レコード数は全件を数える）
+	maxRecordSamples = 1000
+)
+
+// summarizeJSON は JSON のキーのパスごとの型と値の例を集計する
+func summarizeJSON(content string) (*Summary, bool) {
+	dec := json.NewDecoder(strings.NewReader(content))
+	dec.UseNumber()
+	var value any
+	if err := dec.Decode(&value); err != nil {
+		return nil, false
+	}
+
+	fields := newFieldSet()
+	walk(fields, "$", value, 0)
+	return fields.summary(0), true
+}
+
+// summarizeJSONLines は JSON Lines の各レコードを集計する
+func summarizeJSONLines(content string) (*Summary, bool) {
+	fields := newFieldSet()
+	records := 0
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(content)+1)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		records++
+		if records > maxRecordSamples {
+			continue
+		}
+
+		dec := json.NewDecoder(bytes.NewReader(line))
+		dec.UseNumber()
+		var value any
+		if err := dec.Decode(&value); err != nil {
+			return nil, false
+		}
+		walk(fields, "$", value, 0)
+	}
+	if err := scanner.Err(); err != nil || records == 0 {
+		return nil, false
+	}
+	return fields.summary(records), true
+}
+
+// summarizeYAML は YAML の各ドキュメントを集計する
+func summarizeYAML(content string) (*Summary, bool) {
+	fields := newFieldSet()
+	documents := 0
+	dec := yaml.NewDecoder(strings.NewReader(content))
+	for {
+		var value any
+		err := dec.Decode(&value)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, false
+		}
+		documents++
+		if documents <= maxRecordSamples {
+			walk(fields, "$", value, 0)
+		}
+	}
+	if documents == 0 {
+		return nil, false
+	}
+	return fields.summary(documents), true
+}
+
+// walk は値の型と値の例をキーのパスごとに集計する
+func walk(fields *fieldSet, path string, value any, depth int) {
+	f := fields.get(path)
+	if f == nil {
+		return
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		f.addType("object")
+		if depth >= maxDepth {
+			return
+		}
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			walk(fields, childPath(path, key), v[key], depth+1)
+		}
+	case map[any]any:
+		// YAML で文字列以外のキーを含むマッピング
+		converted := make(map[string]any, len(v))
+		for key, child := range v {
+			converted[fmt.Sprint(key)] = child
+		}
+		walk(fields, path, converted, depth)
+	case []any:
+		f.addType("array")
+		f.MaxItems = max(f.MaxItems, len(v))
+		if depth >= maxDepth {
+			return
+		}
+		for _, item := range v[:min(len(v), maxArraySamples)] {
+			walk(fields, path+"[]", item, depth+1)
+		}
+	case nil:
+		f.addType("null")
+	case bool:
+		f.addType("boolean")
+		f.addExample(strconv.FormatBool(v))
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			f.addType("integer")
+		} else {
+			f.addType("number")
+		}
+		f.addExample(v.String())
+	case int, int64, uint64:
+		f.addType("integer")
+		f.addExample(fmt.Sprint(v))
+	case float64:
+		f.addType("number")
+		f.addExample(strconv.FormatFloat(v, 'g', -1, 64))
+	case string:
+		f.addType("string")
+		f.addExample(strconv.Quote(v))
+	default:
+		// YAML のタイムスタンプなど
+		f.addType("string")
+		f.addExample(fmt.Sprint(v))
+	}
+}
+
+// childPath はキーのパスを連結する（識別子として使えないキーは ["..."] で表す）
+func childPath(parent, key string) string {
+	if key != "" && strings.IndexFunc(key, func(r rune) bool {
+		return !(r == '_' || r == '-' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z')
+	}) < 0 {
+		return parent + "." + key
+	}
+	return parent + "[" + strconv.Quote(key) + "]"
+}
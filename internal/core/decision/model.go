@@ -0,0 +1,49 @@
+// Package decision はリポジトリ内の設計判断の記録（ADR・RFC）の検出とメタデータの抽出を提供する
+package decision
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Status は設計判断のステータス
+type Status string
+
+const (
+	StatusProposed   Status = "proposed"   // 提案中・レビュー中
+	StatusAccepted   Status = "accepted"   // 採用済み
+	StatusRejected   Status = "rejected"   // 却下
+	StatusDeprecated Status = "deprecated" // 非推奨
+	StatusSuperseded Status = "superseded" // 別の決定に置き換え済み
+	StatusUnknown    Status = "unknown"    // ステータスの記載がない、または判別できない
+)
+
+// Statuses はステータスの一覧（Wikiページの表示順）
+var Statuses = []Status{StatusAccepted, StatusProposed, StatusSuperseded, StatusDeprecated, StatusRejected, StatusUnknown}
+
+// Active は現在有効な決定（採用済み）かを返す
+func (s Status) Active() bool {
+	return s == StatusAccepted
+}
+
+// Inactive は参照すべきでない決定（却下・非推奨・置き換え済み）かを返す
+func (s Status) Inactive() bool {
+	return s == StatusRejected || s == StatusDeprecated || s == StatusSuperseded
+}
+
+// Record は設計判断の記録（ADR・RFC）から抽出したメタデータ
+type Record struct {
+	ID         uuid.UUID
+	SnapshotID uuid.UUID
+	FileID     uuid.UUID
+	SourceName string // 取得時のみ設定される
+	FilePath   string
+	Number     *int // ADR・RFC の番号（ファイル名またはタイトルから抽出）
+	Title      string
+	Status     Status
+	StatusText string     // 記載どおりのステータス（"Superseded by ADR-0007" など）
+	Date       *time.Time // 決定日（記載がない場合は nil）
+	Decision   string     // 決定内容のセクション（ない場合は概要のセクション）
+	CreatedAt  time.Time
+}
@@ -0,0 +1,317 @@
+package decision
+
+import (
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxDecisionRunes は保存する決定内容の最大文字数
+const maxDecisionRunes = 2000
+
+var (
+	// decisionDirPattern は設計判断の記録を配置するディレクトリ（docs/adr/ など）
+	decisionDirPattern = regexp.MustCompile(`(?i)(^|/)(adrs?|decisions|decision-records|architecture-decisions|rfcs?)/`)
+	headingPattern     = regexp.MustCompile(`^(#{1,6})\s+(.+?)\s*#*\s*$`)
+	fieldPattern       = regexp.MustCompile(`^\s*(?:[-*]\s+)?(?:\*\*|__)?([^:：*_]+?)(?:\*\*|__)?\s*[:：]\s*(?:\*\*|__)?\s*(.+)$`)
+	leadingNumber      = regexp.MustCompile(`^(\d+)`)
+	titleNumber        = regexp.MustCompile(`(?i)\b(?:adr|rfc)[-_ ]?(\d+)`)
+	titlePrefix        = regexp.MustCompile(`(?i)^(?:(?:adr|rfc)[-_ ]?\d+|\d+)\s*[:.：\-–]?\s+`)
+	datePattern        = regexp.MustCompile(`(\d{4})[-/.](\d{1,2})[-/.](\d{1,2})`)
+)
+
+// 見出し・フィールド名（小文字）
+var (
+	statusKeys   = []string{"status", "ステータス", "状態"}
+	dateKeys     = []string{"date", "decided", "日付", "決定日"}
+	decisionKeys = []string{"decision", "decision outcome", "decision made", "決定", "決定事項", "決定内容", "結論"}
+	summaryKeys  = []string{"summary", "abstract", "概要"}
+	// contextKeys はRFCテンプレートを判定するための本文の見出し
+	contextKeys = []string{"context", "context and problem statement", "motivation", "背景", "動機", "コンテキスト"}
+)
+
+// IsDecisionRecordPath はパスが設計判断の記録の配置先（docs/adr/ など）にあるMarkdownかを判定する
+// ディレクトリの README・索引・テンプレートは対象外とする
+func IsDecisionRecordPath(p string) bool {
+	p = strings.ReplaceAll(p, "\\", "/")
+	if !isMarkdown(p) || !decisionDirPattern.MatchString(p) {
+		return false
+	}
+	base := strings.ToLower(path.Base(p))
+	name := strings.TrimSuffix(base, path.Ext(base))
+	switch name {
+	case "readme", "index", "toc", "_index":
+		return false
+	}
+	return !strings.Contains(name, "template")
+}
+
+// Parse はMarkdownのADR・RFCからメタデータを抽出する
+// 配置先（IsDecisionRecordPath）またはテンプレートの構成（ステータスと、決定内容または背景の見出し）から設計判断の記録と判定できない場合は false を返す
+// SnapshotID・FileID は呼び出し元で設定する
+func Parse(filePath, content string) (*Record, bool) {
+	if !isMarkdown(filePath) {
+		return nil, false
+	}
+	doc := parseDocument(content)
+
+	statusText := doc.value(statusKeys)
+	if !IsDecisionRecordPath(filePath) {
+		if statusText == "" || (!doc.hasSection(decisionKeys) && !doc.hasSection(contextKeys)) {
+			return nil, false
+		}
+	}
+
+	record := &Record{
+		FilePath:   filePath,
+		Title:      doc.title,
+		Status:     NormalizeStatus(statusText),
+		StatusText: statusText,
+		Decision:   doc.section(decisionKeys),
+	}
+	if record.Decision == "" {
+		record.Decision = doc.section(summaryKeys)
+	}
+	record.Decision = truncateRunes(record.Decision, maxDecisionRunes)
+
+	if n, ok := parseNumber(filePath, record.Title); ok {
+		record.Number = &n
+	}
+	record.Title = strings.TrimSpace(titlePrefix.ReplaceAllString(record.Title, ""))
+	if record.Title == "" {
+		base := path.Base(filePath)
+		record.Title = strings.TrimSuffix(base, path.Ext(base))
+	}
+	if date, ok := parseDate(doc.value(dateKeys)); ok {
+		record.Date = &date
+	}
+
+	return record, true
+}
+
+// NormalizeStatus は記載されたステータスを Status に正規化する
+// "Superseded by ADR-0007" のような補足付きの記載や日本語の記載にも対応する
+func NormalizeStatus(text string) Status {
+	s := strings.ToLower(text)
+	switch {
+	case s == "":
+		return StatusUnknown
+	case containsAny(s, "supersed", "replaced", "置き換え", "置換"):
+		return StatusSuperseded
+	case containsAny(s, "deprecat", "obsolete", "非推奨", "廃止"):
+		return StatusDeprecated
+	case containsAny(s, "reject", "declin", "withdrawn", "abandon", "却下", "不採用", "取り下げ"):
+		return StatusRejected
+	case containsAny(s, "accept", "approv", "adopt", "agreed", "implemented", "final", "承認", "採用", "合意", "決定"):
+		return StatusAccepted
+	case containsAny(s, "propos", "draft", "review", "discuss", "open", "wip", "提案", "検討", "レビュー", "草案"):
+		return StatusProposed
+	default:
+		return StatusUnknown
+	}
+}
+
+// section は見出しとその本文
+type section struct {
+	level int
+	title string // 小文字
+	body  string
+}
+
+// document はMarkdownを見出し単位に分割したもの
+type document struct {
+	title       string
+	frontMatter map[string]string
+	fields      map[string]string // 本文中の "Status: Accepted" 形式の記載（最初に現れたもの）
+	sections    []section
+}
+
+func parseDocument(content string) *document {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	doc := &document{frontMatter: map[string]string{}, fields: map[string]string{}}
+
+	// YAML のフロントマター（キー: 値 の単純な形式のみ）
+	if len(lines) > 0 && strings.TrimSpace(lines[0]) == "---" {
+		for i := 1; i < len(lines); i++ {
+			line := strings.TrimSpace(lines[i])
+			if line == "---" {
+				lines = lines[i+1:]
+				break
+			}
+			if key, value, ok := strings.Cut(line, ":"); ok {
+				doc.frontMatter[strings.ToLower(strings.TrimSpace(key))] = strings.Trim(strings.TrimSpace(value), `"'`)
+			}
+		}
+	}
+	doc.title = doc.frontMatter["title"]
+
+	var current *section
+	var body []string
+	flush := func() {
+		if current != nil {
+			current.body = strings.TrimSpace(strings.Join(body, "\n"))
+			doc.sections = append(doc.sections, *current)
+		}
+		body = nil
+	}
+	inCode := false
+	for _, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inCode = !inCode
+		}
+		if !inCode {
+			if m := headingPattern.FindStringSubmatch(line); m != nil {
+				if doc.title == "" {
+					doc.title = m[2]
+				}
+				flush()
+				current = &section{level: len(m[1]), title: strings.ToLower(strings.TrimSpace(m[2]))}
+				continue
+			}
+			if m := fieldPattern.FindStringSubmatch(line); m != nil {
+				key := strings.ToLower(strings.TrimSpace(m[1]))
+				if _, ok := doc.fields[key]; !ok {
+					doc.fields[key] = cleanInline(m[2])
+				}
+			}
+		}
+		if current != nil {
+			body = append(body, line)
+		}
+	}
+	flush()
+	return doc
+}
+
+// value はフロントマター・本文中のフィールド・セクション本文の最初の行の順にキーの値を返す
+func (d *document) value(keys []string) string {
+	for _, key := range keys {
+		if v := d.frontMatter[key]; v != "" {
+			return v
+		}
+	}
+	for _, key := range keys {
+		if v := d.fields[key]; v != "" {
+			return v
+		}
+	}
+	for _, s := range d.sections {
+		if matchesKey(s.title, keys) {
+			for _, line := range strings.Split(s.body, "\n") {
+				if v := cleanInline(line); v != "" {
+					return v
+				}
+			}
+		}
+	}
+	return ""
+}
+
+func (d *document) hasSection(keys []string) bool {
+	for _, s := range d.sections {
+		if matchesKey(s.title, keys) {
+			return true
+		}
+	}
+	return false
+}
+
+// section はキーに一致する最初の見出しの本文を、配下の小見出しを含めて返す
+func (d *document) section(keys []string) string {
+	for i, s := range d.sections {
+		if !matchesKey(s.title, keys) {
+			continue
+		}
+		parts := []string{s.body}
+		for _, sub := range d.sections[i+1:] {
+			if sub.level <= s.level {
+				break
+			}
+			parts = append(parts, strings.Repeat("#", sub.level)+" "+sub.title, sub.body)
+		}
+		return strings.TrimSpace(strings.Join(parts, "\n\n"))
+	}
+	return ""
+}
+
+// matchesKey は見出しがキーのいずれかと一致するかを判定する（"3. Decision" のような番号付きの見出しも許容する）
+func matchesKey(title string, keys []string) bool {
+	title = strings.TrimSpace(strings.TrimLeft(title, "0123456789. "))
+	for _, key := range keys {
+		if title == key {
+			return true
+		}
+	}
+	return false
+}
+
+// parseNumber はファイル名の先頭、またはタイトルの "ADR-12" のような記載から番号を抽出する
+func parseNumber(filePath, title string) (int, bool) {
+	candidates := []string{}
+	if m := leadingNumber.FindStringSubmatch(path.Base(filePath)); m != nil {
+		candidates = append(candidates, m[1])
+	}
+	if m := titleNumber.FindStringSubmatch(title); m != nil {
+		candidates = append(candidates, m[1])
+	}
+	if m := leadingNumber.FindStringSubmatch(title); m != nil {
+		candidates = append(candidates, m[1])
+	}
+	for _, c := range candidates {
+		if n, err := strconv.Atoi(c); err == nil {
+			return n, true
+		}
+	}
+	return 0, false
+}
+
+// parseDate は記載から最初の日付（YYYY-MM-DD、YYYY/MM/DD 形式）を抽出する
+func parseDate(text string) (time.Time, bool) {
+	m := datePattern.FindStringSubmatch(text)
+	if m == nil {
+		return time.Time{}, false
+	}
+	year, _ := strconv.Atoi(m[1])
+	month, _ := strconv.Atoi(m[2])
+	day, _ := strconv.Atoi(m[3])
+	date := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	if date.Year() != year || int(date.Month()) != month || date.Day() != day {
+		return time.Time{}, false
+	}
+	return date, true
+}
+
+// cleanInline はリスト記号・強調・コードの記法を取り除く
+func cleanInline(s string) string {
+	s = strings.TrimSpace(s)
+	s = strings.TrimLeft(s, "-*+> ")
+	s = strings.NewReplacer("**", "", "__", "", "`", "").Replace(s)
+	return strings.TrimSpace(s)
+}
+
+func isMarkdown(p string) bool {
+	switch strings.ToLower(path.Ext(p)) {
+	case ".md", ".markdown":
+		return true
+	}
+	return false
+}
+
+func containsAny(s string, substrs ...string) bool {
+	for _, sub := range substrs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
+
+func truncateRunes(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max]) + "..."
+}
@@ -0,0 +1,183 @@
+package decision
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsDecisionRecordPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"docs/adr/0001-record-architecture-decisions.md", true},
+		{"doc/architecture/decisions/0002-use-postgres.md", true},
+		{"rfcs/0010-new-api.markdown", true},
+		{"ADR/12-cache.md", true},
+		{"docs/adr/README.md", false},
+		{"docs/adr/index.md", false},
+		{"docs/adr/template.md", false},
+		{"docs/adr/0000-adr-template.md", false},
+		{"docs/adr/0001-diagram.png", false},
+		{"docs/design.md", false},
+		{"internal/adrs.md", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsDecisionRecordPath(tt.path))
+		})
+	}
+}
+
+func TestNormalizeStatus(t *testing.T) {
+	tests := []struct {
+		text string
+		want Status
+	}{
+		{"Accepted", StatusAccepted},
+		{"approved", StatusAccepted},
+		{"Proposed", StatusProposed},
+		{"Draft", StatusProposed},
+		{"Superseded by [ADR-0007](0007-use-grpc.md)", StatusSuperseded},
+		{"Deprecated", StatusDeprecated},
+		{"Rejected", StatusRejected},
+		{"承認済み", StatusAccepted},
+		{"提案", StatusProposed},
+		{"却下", StatusRejected},
+		{"", StatusUnknown},
+		{"unclear", StatusUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			assert.Equal(t, tt.want, NormalizeStatus(tt.text))
+		})
+	}
+}
+
+func TestParse_Nygard(t *testing.T) {
+	content := `# 2. Use PostgreSQL for persistence
+
+Date: 2024-03-15
+
+## Status
+
+Accepted
+
+## Context
+
+We need a relational database.
+
+## Decision
+
+We will use PostgreSQL with pgvector.
+
+### Consequences of the choice
+
+Operations must manage extensions.
+
+## Consequences
+
+Backups are required.
+`
+	record, ok := Parse("docs/adr/0002-use-postgresql.md", content)
+	require.True(t, ok)
+
+	assert.Equal(t, "Use PostgreSQL for persistence", record.Title)
+	require.NotNil(t, record.Number)
+	assert.Equal(t, 2, *record.Number)
+	assert.Equal(t, StatusAccepted, record.Status)
+	assert.Equal(t, "Accepted", record.StatusText)
+	require.NotNil(t, record.Date)
+	assert.Equal(t, time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC), *record.Date)
+	assert.Contains(t, record.Decision, "We will use PostgreSQL with pgvector.")
+	assert.Contains(t, record.Decision, "Operations must manage extensions.")
+	assert.NotContains(t, record.Decision, "Backups are required.")
+}
+
+func TestParse_MADRFrontMatter(t *testing.T) {
+	content := `---
+status: superseded by ADR-0009
+date: 2023/11/02
+---
+# ADR-0005: Queue messages with Redis
+
+## Context and Problem Statement
+
+Jobs must be retried.
+
+## Decision Outcome
+
+Chosen option: "Redis Streams".
+`
+	record, ok := Parse("docs/decisions/adr-0005-queue.md", content)
+	require.True(t, ok)
+
+	assert.Equal(t, "Queue messages with Redis", record.Title)
+	require.NotNil(t, record.Number)
+	assert.Equal(t, 5, *record.Number)
+	assert.Equal(t, StatusSuperseded, record.Status)
+	require.NotNil(t, record.Date)
+	assert.Equal(t, time.Date(2023, 11, 2, 0, 0, 0, 0, time.UTC), *record.Date)
+	assert.Equal(t, `Chosen option: "Redis Streams".`, record.Decision)
+}
+
+func TestParse_RFCTemplateOutsideADRDirectory(t *testing.T) {
+	content := `# 検索APIの刷新
+
+- **ステータス**: 提案
+- 日付: 2025-01-20
+
+## 背景
+
+検索が遅い。
+
+## 決定
+
+ハイブリッド検索を導入する。
+`
+	record, ok := Parse("docs/design/search-api.md", content)
+	require.True(t, ok)
+
+	assert.Equal(t, "検索APIの刷新", record.Title)
+	assert.Nil(t, record.Number)
+	assert.Equal(t, StatusProposed, record.Status)
+	assert.Equal(t, "提案", record.StatusText)
+	require.NotNil(t, record.Date)
+	assert.Equal(t, "ハイブリッド検索を導入する。", record.Decision)
+}
+
+func TestParse_NotDecisionRecord(t *testing.T) {
+	// ステータスの記載がない通常のドキュメント
+	_, ok := Parse("docs/design.md", "# Design\n\n## Context\n\ntext\n")
+	assert.False(t, ok)
+
+	// Markdown 以外
+	_, ok = Parse("docs/adr/0001-x.txt", "# 1. X\n\n## Status\n\nAccepted\n")
+	assert.False(t, ok)
+}
+
+func TestParse_MissingMetadata(t *testing.T) {
+	content := "Some notes without headings.\n"
+	record, ok := Parse("docs/adr/0003-notes.md", content)
+	require.True(t, ok)
+
+	assert.Equal(t, "0003-notes", record.Title)
+	require.NotNil(t, record.Number)
+	assert.Equal(t, 3, *record.Number)
+	assert.Equal(t, StatusUnknown, record.Status)
+	assert.Nil(t, record.Date)
+	assert.Empty(t, record.Decision)
+}
+
+func TestParse_IgnoresHeadingsInCodeBlocks(t *testing.T) {
+	content := "# 4. Use Markdown\n\n## Status\n\nAccepted\n\n## Decision\n\n```md\n# Not a heading\n```\n\nUse it.\n"
+	record, ok := Parse("adr/0004-use-markdown.md", content)
+	require.True(t, ok)
+
+	assert.Equal(t, "Use Markdown", record.Title)
+	assert.Contains(t, record.Decision, "# Not a heading")
+	assert.Contains(t, record.Decision, "Use it.")
+}
@@ -0,0 +1,22 @@
+package deplink
+
+import (
+	"path"
+
+	"golang.org/x/mod/modfile"
+)
+
+// IsGoMod はファイルが go.mod かを判定する
+func IsGoMod(p string) bool {
+	return path.Base(p) == "go.mod"
+}
+
+// ParseGoMod は go.mod の module ディレクティブからモジュールを抽出する
+// module ディレクティブがない場合は false を返す
+func ParseGoMod(p, content string) (*Module, bool) {
+	modulePath := modfile.ModulePath([]byte(content))
+	if modulePath == "" {
+		return nil, false
+	}
+	return &Module{Path: modulePath, Dir: path.Dir(p)}, true
+}
@@ -0,0 +1,43 @@
+// Package deplink は Go のパッケージ・レシーバ型・インポートパスをもとにシンボルを解決し、チャンク間の依存関係を構築する
+// プロダクトに属する複数のソースのモジュールをまたぐインポートも解決する
+package deplink
+
+import (
+	"github.com/google/uuid"
+)
+
+// 依存関係の種類（chunk_dependencies.dep_type）
+const (
+	DepTypeCall   = "call"
+	DepTypeImport = "import"
+	DepTypeType   = "type"
+)
+
+// Module はスナップショットに含まれる Go モジュール（go.mod）
+type Module struct {
+	SnapshotID uuid.UUID
+	Path       string // module ディレクティブのモジュールパス
+	Dir        string // go.mod を置いたディレクトリ（リポジトリのルートは "."）
+}
+
+// Chunk は依存関係の解決に使用するチャンクの情報
+type Chunk struct {
+	ID               uuid.UUID
+	SnapshotID       uuid.UUID
+	FilePath         string
+	Type             string // function・method・struct・interface・package など
+	Name             string
+	ParentName       string   // メソッドのレシーバの型名
+	Imports          []string // ファイルのインポートパス
+	InternalCalls    []string // 同じパッケージへの呼び出し（"Func" または "Type.Method"）
+	ExternalCalls    []string // インポートしたパッケージへの呼び出し（"importpath.Func" または "importpath.Type.Method"）
+	TypeDependencies []string // 参照している型（"*pkg.Type" など）
+}
+
+// Dependency はチャンク間の依存関係
+type Dependency struct {
+	FromChunkID uuid.UUID
+	ToChunkID   uuid.UUID
+	DepType     string
+	Symbol      string // 依存先のシンボル（他パッケージはパッケージ名で修飾、import の場合はインポートパス）
+}
@@ -0,0 +1,225 @@
+package deplink
+
+import (
+	"cmp"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// packageKey はスナップショット内のパッケージ（ディレクトリ）を識別する
+type packageKey struct {
+	snapshotID uuid.UUID
+	dir        string
+}
+
+// Resolve はチャンクが参照するシンボルをパッケージ単位で解決し、チャンク間の依存関係を返す
+// 名前だけで照合すると共通の名前（Get・New など）を誤って結び付けるため、次の情報で依存先を特定する
+//   - call: 同じパッケージへの呼び出しはレシーバ型で修飾した名前（"Type.Method"）、
+//     他パッケージへの呼び出しはインポートパスで修飾した名前で、関数・メソッドのチャンクに解決する
+//   - type: 修飾なしの型は同じパッケージ、パッケージ名で修飾した型はインポートしたパッケージの型宣言に解決する
+//   - import: インポートしたパッケージのドキュメントコメント（package 句）のチャンクに解決する
+//
+// インポートパスはモジュール（go.mod）をもとに、同じソースまたはプロダクト内の他のソースのパッケージに解決する
+// テストファイルのチャンクは同じパッケージのテストファイルからのみ依存先とする
+func Resolve(modules []*Module, chunks []*Chunk) []*Dependency {
+	// 入れ子のモジュールを正しく解決するため、モジュールパスの長い順に照合する
+	modules = slices.Clone(modules)
+	slices.SortStableFunc(modules, func(a, b *Module) int {
+		return cmp.Compare(len(b.Path), len(a.Path))
+	})
+
+	packages := make(map[packageKey][]*Chunk)
+	for _, c := range chunks {
+		key := chunkPackage(c)
+		packages[key] = append(packages[key], c)
+	}
+
+	var deps []*Dependency
+	seen := make(map[Dependency]bool)
+	add := func(from, to *Chunk, depType, symbol string) {
+		dep := Dependency{FromChunkID: from.ID, ToChunkID: to.ID, DepType: depType, Symbol: symbol}
+		if from.ID == to.ID || seen[dep] {
+			return
+		}
+		seen[dep] = true
+		deps = append(deps, &dep)
+	}
+
+	for _, from := range chunks {
+		own := visibleChunks(packages[chunkPackage(from)], isTestFile(from.FilePath))
+
+		imported := make(map[string][]*Chunk, len(from.Imports))
+		for _, importPath := range from.Imports {
+			key, ok := resolvePackage(modules, importPath, from.SnapshotID)
+			if !ok || key == chunkPackage(from) {
+				continue
+			}
+			targets := visibleChunks(packages[key], false)
+			if len(targets) == 0 {
+				continue
+			}
+			imported[importPath] = targets
+
+			for _, t := range targets {
+				if t.Type == "package" {
+					add(from, t, DepTypeImport, importPath)
+					break
+				}
+			}
+		}
+
+		for _, call := range from.InternalCalls {
+			for _, to := range findSymbol(own, call) {
+				add(from, to, DepTypeCall, call)
+			}
+		}
+
+		for _, call := range from.ExternalCalls {
+			importPath, symbol, ok := splitImportPath(from.Imports, call)
+			if !ok || !isExported(symbol) {
+				continue
+			}
+			for _, to := range findSymbol(imported[importPath], symbol) {
+				add(from, to, DepTypeCall, packageName(importPath)+"."+symbol)
+			}
+		}
+
+		for _, dep := range from.TypeDependencies {
+			typeName := baseTypeName(dep)
+			qualifier, name, qualified := strings.Cut(typeName, ".")
+			if !qualified {
+				for _, to := range filterChunks(own, func(c *Chunk) bool { return isTypeDecl(c) && c.Name == typeName }) {
+					add(from, to, DepTypeType, typeName)
+				}
+				continue
+			}
+			if !isExported(name) {
+				continue
+			}
+			for _, importPath := range from.Imports {
+				if packageName(importPath) != qualifier {
+					continue
+				}
+				for _, to := range filterChunks(imported[importPath], func(c *Chunk) bool { return isTypeDecl(c) && c.Name == name }) {
+					add(from, to, DepTypeType, typeName)
+				}
+			}
+		}
+	}
+	return deps
+}
+
+// chunkPackage はチャンクが属するパッケージ（スナップショットとディレクトリ）を返す
+func chunkPackage(c *Chunk) packageKey {
+	return packageKey{snapshotID: c.SnapshotID, dir: path.Dir(c.FilePath)}
+}
+
+// visibleChunks はパッケージのチャンクのうち依存先にできるものを返す（テストファイルのチャンクは includeTests の場合のみ含める）
+func visibleChunks(chunks []*Chunk, includeTests bool) []*Chunk {
+	if includeTests {
+		return chunks
+	}
+	return filterChunks(chunks, func(c *Chunk) bool { return !isTestFile(c.FilePath) })
+}
+
+// findSymbol はパッケージ内の "Func" または "Type.Method" に一致する関数・メソッドのチャンクを返す
+func findSymbol(chunks []*Chunk, symbol string) []*Chunk {
+	if receiver, method, ok := strings.Cut(symbol, "."); ok {
+		return filterChunks(chunks, func(c *Chunk) bool {
+			return c.Type == "method" && c.Name == method && strings.TrimPrefix(c.ParentName, "*") == receiver
+		})
+	}
+	return filterChunks(chunks, func(c *Chunk) bool { return isFunction(c) && c.Name == symbol })
+}
+
+// splitImportPath はインポートパスで修飾した呼び出しを、最も長く一致したインポートパスとシンボルに分ける
+func splitImportPath(imports []string, call string) (string, string, bool) {
+	var importPath string
+	for _, p := range imports {
+		if strings.HasPrefix(call, p+".") && len(p) > len(importPath) {
+			importPath = p
+		}
+	}
+	if importPath == "" {
+		return "", "", false
+	}
+	return importPath, call[len(importPath)+1:], true
+}
+
+// resolvePackage はインポートパスをモジュールのディレクトリをもとにスナップショット内のパッケージに解決する
+// 同じ長さで一致するモジュールが複数のソースにある場合は、インポートしたチャンクと同じソースのモジュールを優先する
+func resolvePackage(modules []*Module, importPath string, fromSnapshotID uuid.UUID) (packageKey, bool) {
+	var (
+		matched *Module
+		rel     string
+	)
+	for _, m := range modules {
+		if matched != nil && len(m.Path) < len(matched.Path) {
+			break
+		}
+		r, ok := strings.CutPrefix(importPath, m.Path)
+		if !ok || (r != "" && !strings.HasPrefix(r, "/")) {
+			continue
+		}
+		if matched == nil || m.SnapshotID == fromSnapshotID {
+			matched, rel = m, r
+		}
+	}
+	if matched == nil {
+		return packageKey{}, false
+	}
+	return packageKey{snapshotID: matched.SnapshotID, dir: path.Join(matched.Dir, strings.TrimPrefix(rel, "/"))}, true
+}
+
+var majorVersionSuffix = regexp.MustCompile(`^v[0-9]+$`)
+
+// packageName はインポートパスから既定のパッケージ名を推定する（末尾のメジャーバージョン "v2" などは除く）
+func packageName(importPath string) string {
+	name := path.Base(importPath)
+	if majorVersionSuffix.MatchString(name) {
+		name = path.Base(path.Dir(importPath))
+	}
+	return strings.ReplaceAll(name, "-", "_")
+}
+
+// baseTypeName はポインタ・スライスの修飾を除いた型名を返す（"[]*pkg.Type" は "pkg.Type"）
+func baseTypeName(typeName string) string {
+	return strings.TrimLeft(typeName, "*[]")
+}
+
+func isExported(name string) bool {
+	r, _ := utf8.DecodeRuneInString(name)
+	return unicode.IsUpper(r)
+}
+
+func isTestFile(filePath string) bool {
+	return strings.HasSuffix(filePath, "_test.go")
+}
+
+func isFunction(c *Chunk) bool {
+	return c.Type == "function" && c.ParentName == ""
+}
+
+func isTypeDecl(c *Chunk) bool {
+	switch c.Type {
+	case "struct", "interface", "type":
+		return true
+	}
+	return false
+}
+
+func filterChunks(chunks []*Chunk, match func(*Chunk) bool) []*Chunk {
+	var result []*Chunk
+	for _, c := range chunks {
+		if match(c) {
+			result = append(result, c)
+		}
+	}
+	return result
+}
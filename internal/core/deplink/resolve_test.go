@@ -0,0 +1,97 @@
+package deplink
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGoMod(t *testing.T) {
+	m, ok := ParseGoMod("services/billing/go.mod", "// billing\nmodule github.com/acme/billing/v2\n\ngo 1.24\n")
+	require.True(t, ok)
+	assert.Equal(t, "github.com/acme/billing/v2", m.Path)
+	assert.Equal(t, "services/billing", m.Dir)
+
+	m, ok = ParseGoMod("go.mod", "module \"example.com/app\"\n")
+	require.True(t, ok)
+	assert.Equal(t, ".", m.Dir)
+
+	_, ok = ParseGoMod("go.mod", "go 1.24\n")
+	assert.False(t, ok)
+	assert.True(t, IsGoMod("tools/go.mod"))
+	assert.False(t, IsGoMod("go.sum"))
+}
+
+func TestResolve(t *testing.T) {
+	api, lib := uuid.New(), uuid.New()
+	modules := []*Module{
+		{SnapshotID: api, Path: "github.com/acme/api", Dir: "."},
+		{SnapshotID: lib, Path: "github.com/acme/lib", Dir: "."},
+		// lib の中の入れ子のモジュールは api のソースに含まれる（最長一致）
+		{SnapshotID: api, Path: "github.com/acme/lib/contrib", Dir: "third_party/contrib"},
+	}
+
+	pkgDoc := &Chunk{ID: uuid.New(), SnapshotID: lib, FilePath: "auth/doc.go", Type: "package", Name: "auth"}
+	verify := &Chunk{ID: uuid.New(), SnapshotID: lib, FilePath: "auth/token.go", Type: "function", Name: "Verify"}
+	claims := &Chunk{ID: uuid.New(), SnapshotID: lib, FilePath: "auth/token.go", Type: "struct", Name: "Claims"}
+	refresh := &Chunk{ID: uuid.New(), SnapshotID: lib, FilePath: "auth/client.go", Type: "method", Name: "Refresh", ParentName: "*Client"}
+	// 同名のメソッドでもレシーバ型が異なるものには解決しない
+	otherRefresh := &Chunk{ID: uuid.New(), SnapshotID: lib, FilePath: "auth/cache.go", Type: "method", Name: "Refresh", ParentName: "Cache"}
+	helper := &Chunk{ID: uuid.New(), SnapshotID: lib, FilePath: "auth/token_test.go", Type: "function", Name: "Verify"}
+	contrib := &Chunk{ID: uuid.New(), SnapshotID: api, FilePath: "third_party/contrib/x.go", Type: "function", Name: "Do"}
+	save := &Chunk{ID: uuid.New(), SnapshotID: api, FilePath: "internal/store/store.go", Type: "method", Name: "Save", ParentName: "*Store"}
+	parse := &Chunk{ID: uuid.New(), SnapshotID: api, FilePath: "internal/handler/parse.go", Type: "function", Name: "parse"}
+	request := &Chunk{ID: uuid.New(), SnapshotID: api, FilePath: "internal/handler/parse.go", Type: "struct", Name: "Request"}
+	// 別のパッケージの同名の関数には解決しない
+	otherParse := &Chunk{ID: uuid.New(), SnapshotID: api, FilePath: "internal/other/parse.go", Type: "function", Name: "parse"}
+	handler := &Chunk{
+		ID:               uuid.New(),
+		SnapshotID:       api,
+		FilePath:         "internal/handler/handler.go",
+		Type:             "function",
+		Name:             "Handle",
+		Imports:          []string{"context", "github.com/acme/lib/auth", "github.com/acme/lib/contrib", "github.com/acme/api/internal/store"},
+		InternalCalls:    []string{"parse"},
+		ExternalCalls:    []string{"github.com/acme/lib/auth.Verify", "github.com/acme/lib/auth.Client.Refresh", "github.com/acme/lib/contrib.Do", "github.com/acme/api/internal/store.Store.Save", "context.Background"},
+		TypeDependencies: []string{"*auth.Claims", "context.Context", "[]auth.Missing", "Request"},
+	}
+
+	deps := Resolve(modules, []*Chunk{pkgDoc, verify, claims, refresh, otherRefresh, helper, contrib, save, parse, request, otherParse, handler})
+	require.Len(t, deps, 8)
+
+	assert.Equal(t, Dependency{FromChunkID: handler.ID, ToChunkID: pkgDoc.ID, DepType: DepTypeImport, Symbol: "github.com/acme/lib/auth"}, *deps[0])
+	assert.Equal(t, Dependency{FromChunkID: handler.ID, ToChunkID: parse.ID, DepType: DepTypeCall, Symbol: "parse"}, *deps[1])
+	assert.Equal(t, Dependency{FromChunkID: handler.ID, ToChunkID: verify.ID, DepType: DepTypeCall, Symbol: "auth.Verify"}, *deps[2])
+	assert.Equal(t, Dependency{FromChunkID: handler.ID, ToChunkID: refresh.ID, DepType: DepTypeCall, Symbol: "auth.Client.Refresh"}, *deps[3])
+	// 同じソース内の他パッケージもモジュールから解決する
+	assert.Equal(t, Dependency{FromChunkID: handler.ID, ToChunkID: contrib.ID, DepType: DepTypeCall, Symbol: "contrib.Do"}, *deps[4])
+	assert.Equal(t, Dependency{FromChunkID: handler.ID, ToChunkID: save.ID, DepType: DepTypeCall, Symbol: "store.Store.Save"}, *deps[5])
+	assert.Equal(t, Dependency{FromChunkID: handler.ID, ToChunkID: claims.ID, DepType: DepTypeType, Symbol: "auth.Claims"}, *deps[6])
+	assert.Equal(t, Dependency{FromChunkID: handler.ID, ToChunkID: request.ID, DepType: DepTypeType, Symbol: "Request"}, *deps[7])
+}
+
+func TestResolve_TestFiles(t *testing.T) {
+	snapshot := uuid.New()
+	newClient := &Chunk{ID: uuid.New(), SnapshotID: snapshot, FilePath: "client.go", Type: "function", Name: "newClient"}
+	fixture := &Chunk{ID: uuid.New(), SnapshotID: snapshot, FilePath: "fixture_test.go", Type: "function", Name: "fixture"}
+	prod := &Chunk{ID: uuid.New(), SnapshotID: snapshot, FilePath: "main.go", Type: "function", Name: "run", InternalCalls: []string{"newClient", "fixture"}}
+	test := &Chunk{ID: uuid.New(), SnapshotID: snapshot, FilePath: "main_test.go", Type: "function", Name: "TestRun", InternalCalls: []string{"newClient", "fixture"}}
+
+	deps := Resolve(nil, []*Chunk{newClient, fixture, prod, test})
+	require.Len(t, deps, 3)
+
+	// テストファイルのチャンクはテストファイルからのみ依存先になる
+	assert.Equal(t, newClient.ID, deps[0].ToChunkID)
+	assert.Equal(t, prod.ID, deps[0].FromChunkID)
+	assert.Equal(t, newClient.ID, deps[1].ToChunkID)
+	assert.Equal(t, fixture.ID, deps[2].ToChunkID)
+	assert.Equal(t, test.ID, deps[2].FromChunkID)
+}
+
+func TestPackageName(t *testing.T) {
+	assert.Equal(t, "auth", packageName("github.com/acme/lib/auth"))
+	assert.Equal(t, "billing", packageName("github.com/acme/billing/v2"))
+	assert.Equal(t, "go_client", packageName("github.com/acme/go-client"))
+}
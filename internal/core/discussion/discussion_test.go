@@ -0,0 +1,68 @@
+package discussion
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractPaths(t *testing.T) {
+	text := "Moved the retry to `internal/core/ingestion/embedding_retry.go` (see ./docs/design.md, " +
+		"https://github.com/acme/app/blob/main/README.md) and internal/core/ingestion/embedding_retry.go:42."
+
+	assert.Equal(t, []string{
+		"internal/core/ingestion/embedding_retry.go",
+		"docs/design.md",
+	}, ExtractPaths(text))
+	assert.Empty(t, ExtractPaths("version 1.2.3 e.g. foo.bar"))
+}
+
+func TestRenderAndParse(t *testing.T) {
+	updated := time.Date(2024, 5, 2, 9, 30, 0, 0, time.UTC)
+	thread := &Thread{
+		Kind:   KindPullRequest,
+		Number: 128,
+		Title:  "Retry embedding batches\nwith backoff",
+		State:  "merged",
+		Author: "alice",
+		URL:    "https://github.com/acme/app/pull/128",
+		Body:   "The provider rate-limits us, so batches are retried.\nSee internal/infra/ratelimit/limiter.go.",
+		Labels: []string{"backend"},
+		Files:  []string{"internal/core/ingestion/embedding_retry.go"},
+		Comments: []*Comment{
+			{Author: "bob", Body: "Why not jitter?", Path: "internal/core/ingestion/embedding_retry.go"},
+			{Author: "alice", Body: "Added in cmd/dev-rag/main.go too."},
+		},
+		CreatedAt: updated.Add(-24 * time.Hour),
+		UpdatedAt: updated,
+	}
+
+	assert.Equal(t, "pulls/128.md", DocumentPath(thread))
+
+	content := Render(thread)
+	assert.Contains(t, content, "# Pull Request #128: Retry embedding batches with backoff")
+	assert.Contains(t, content, "### bob on internal/core/ingestion/embedding_retry.go\n")
+
+	record, ok := Parse("pulls/128.md", content)
+	require.True(t, ok)
+	assert.Equal(t, KindPullRequest, record.Kind)
+	assert.Equal(t, 128, record.Number)
+	assert.Equal(t, "Retry embedding batches with backoff", record.Title)
+	assert.Equal(t, "merged", record.State)
+	assert.Equal(t, thread.URL, record.URL)
+	require.NotNil(t, record.UpdatedAt)
+	assert.True(t, updated.Equal(*record.UpdatedAt))
+	assert.Equal(t, []string{
+		"internal/core/ingestion/embedding_retry.go",
+		"internal/infra/ratelimit/limiter.go",
+		"cmd/dev-rag/main.go",
+	}, record.Paths)
+	assert.Equal(t, thread.Body, record.Summary)
+}
+
+func TestParse_NotDiscussion(t *testing.T) {
+	_, ok := Parse("docs/design.md", "# Design\n\n- internal/app/cli/ask.go\n")
+	assert.False(t, ok)
+}
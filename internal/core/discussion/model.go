@@ -0,0 +1,66 @@
+// Package discussion は Issue・Pull Request（Merge Request）の議論の文書化と、議論で言及されたファイルパスの抽出を提供する
+package discussion
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Kind は議論の種類
+type Kind string
+
+const (
+	KindIssue       Kind = "issue"
+	KindPullRequest Kind = "pull_request" // GitLab の Merge Request を含む
+)
+
+// Label は表示用の種類名を返す
+func (k Kind) Label() string {
+	if k == KindPullRequest {
+		return "Pull Request"
+	}
+	return "Issue"
+}
+
+// Comment は議論のコメント（レビューコメントを含む）
+type Comment struct {
+	Author    string
+	Body      string
+	Path      string // レビューコメントの対象ファイル（通常のコメントは空）
+	CreatedAt time.Time
+}
+
+// Thread は GitHub・GitLab の API から取得した Issue・Pull Request の議論
+type Thread struct {
+	Kind      Kind
+	Number    int // Issue・Pull Request の番号（GitLab は iid）
+	Title     string
+	State     string // open / closed / merged
+	Author    string
+	URL       string
+	Body      string
+	Labels    []string
+	Files     []string // Pull Request の変更ファイル
+	Comments  []*Comment
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Record はインデックス化した議論の文書から抽出したメタデータ
+type Record struct {
+	ID         uuid.UUID
+	SnapshotID uuid.UUID
+	FileID     uuid.UUID
+	SourceName string // 取得時のみ設定される
+	FilePath   string
+	Kind       Kind
+	Number     int
+	Title      string
+	State      string
+	URL        string
+	Summary    string     // 説明文の冒頭
+	Paths      []string   // 議論で言及されたファイルパス
+	UpdatedAt  *time.Time // 議論の最終更新日時
+	CreatedAt  time.Time
+}
@@ -0,0 +1,87 @@
+package discussion
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxSummaryRunes は保存する説明文の冒頭の最大文字数
+const maxSummaryRunes = 500
+
+// Parse は Render で生成した議論の文書からメタデータを抽出する
+// 先頭行に議論のメタデータが埋め込まれていない文書は false を返す
+// SnapshotID・FileID は呼び出し元で設定する
+func Parse(filePath, content string) (*Record, bool) {
+	firstLine, rest, _ := strings.Cut(content, "\n")
+	if !strings.HasPrefix(firstLine, markerPrefix) || !strings.HasSuffix(firstLine, "-->") {
+		return nil, false
+	}
+
+	record := &Record{FilePath: filePath}
+	attrs := strings.TrimSuffix(strings.TrimPrefix(firstLine, markerPrefix), "-->")
+	for _, field := range strings.Fields(attrs) {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "kind":
+			record.Kind = Kind(value)
+		case "number":
+			record.Number, _ = strconv.Atoi(value)
+		case "state":
+			record.State = value
+		case "url":
+			record.URL = value
+		case "updated":
+			if t, err := time.Parse(time.RFC3339, value); err == nil {
+				record.UpdatedAt = &t
+			}
+		}
+	}
+	if record.Kind != KindIssue && record.Kind != KindPullRequest {
+		return nil, false
+	}
+
+	var section string
+	var summary []string
+	for _, line := range strings.Split(rest, "\n") {
+		switch {
+		case record.Title == "" && strings.HasPrefix(line, "# "):
+			// "# Pull Request #12: タイトル" からタイトルを取り出す
+			title := strings.TrimPrefix(line, "# ")
+			if _, after, ok := strings.Cut(title, ": "); ok {
+				title = after
+			}
+			record.Title = strings.TrimSpace(title)
+			continue
+		case line == relatedFilesHeading || line == descriptionHeading:
+			section = line
+			continue
+		case strings.HasPrefix(line, "## ") && section == relatedFilesHeading:
+			section = line
+		case line == "## Comments":
+			section = line
+		}
+
+		switch section {
+		case relatedFilesHeading:
+			if p, ok := strings.CutPrefix(line, "- "); ok && p != "" {
+				record.Paths = append(record.Paths, p)
+			}
+		case descriptionHeading:
+			summary = append(summary, line)
+		}
+	}
+	record.Summary = truncateRunes(strings.TrimSpace(strings.Join(summary, "\n")), maxSummaryRunes)
+	return record, true
+}
+
+func truncateRunes(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return strings.TrimSpace(string(runes[:limit])) + "…"
+}
@@ -0,0 +1,164 @@
+package discussion
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// maxPaths は1つの議論から抽出するファイルパスの上限
+	maxPaths = 50
+	// markerPrefix は議論の文書の先頭行に埋め込むメタデータの接頭辞
+	markerPrefix = "<!-- dev-rag:discussion "
+	// relatedFilesHeading は言及されたファイルパスを列挙するセクションの見出し
+	relatedFilesHeading = "## Related Files"
+	// descriptionHeading は説明文のセクションの見出し
+	descriptionHeading = "## Description"
+)
+
+// pathPattern は本文中のファイルパス（ディレクトリを1つ以上含み拡張子を持つもの）
+// URL の一部を拾わないよう、直前が空白・括弧・引用符・行頭の場合のみ一致させる
+var pathPattern = regexp.MustCompile("(?:^|[\\s(\\[`'\"])((?:\\.{0,2}/)?(?:[\\w.-]+/)+[\\w.-]*\\w\\.[A-Za-z0-9]+)")
+
+// DocumentPath は議論をインデックス化する際の文書のパスを返す
+func DocumentPath(t *Thread) string {
+	if t.Kind == KindPullRequest {
+		return fmt.Sprintf("pulls/%d.md", t.Number)
+	}
+	return fmt.Sprintf("issues/%d.md", t.Number)
+}
+
+// ExtractPaths は本文中に現れるファイルパスを出現順に重複なく返す
+func ExtractPaths(text string) []string {
+	var paths []string
+	seen := make(map[string]bool)
+	for _, m := range pathPattern.FindAllStringSubmatch(text, -1) {
+		p := normalizePath(m[1])
+		if p == "" || seen[p] {
+			continue
+		}
+		seen[p] = true
+		paths = append(paths, p)
+	}
+	return paths
+}
+
+// MentionedPaths は議論に関連するファイルパスを返す
+// Pull Request の変更ファイル、レビューコメントの対象ファイル、説明文とコメントに現れるパスの順に、最大 maxPaths 件
+func MentionedPaths(t *Thread) []string {
+	var paths []string
+	seen := make(map[string]bool)
+	add := func(p string) {
+		p = normalizePath(p)
+		if p == "" || seen[p] || len(paths) >= maxPaths {
+			return
+		}
+		seen[p] = true
+		paths = append(paths, p)
+	}
+
+	for _, f := range t.Files {
+		add(f)
+	}
+	for _, c := range t.Comments {
+		add(c.Path)
+	}
+	for _, p := range ExtractPaths(t.Body) {
+		add(p)
+	}
+	for _, c := range t.Comments {
+		for _, p := range ExtractPaths(c.Body) {
+			add(p)
+		}
+	}
+	return paths
+}
+
+// Render は議論を Markdown の文書に変換する
+// 先頭行にメタデータを埋め込み、インデックス化の際に Parse で取り出せるようにする
+func Render(t *Thread) string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "%skind=%s number=%d", markerPrefix, t.Kind, t.Number)
+	if t.State != "" {
+		fmt.Fprintf(&sb, " state=%s", t.State)
+	}
+	if !t.UpdatedAt.IsZero() {
+		fmt.Fprintf(&sb, " updated=%s", t.UpdatedAt.UTC().Format(time.RFC3339))
+	}
+	if t.URL != "" {
+		fmt.Fprintf(&sb, " url=%s", t.URL)
+	}
+	sb.WriteString(" -->\n")
+	fmt.Fprintf(&sb, "# %s #%d: %s\n\n", t.Kind.Label(), t.Number, oneLine(t.Title))
+
+	writeField := func(name, value string) {
+		if value != "" {
+			fmt.Fprintf(&sb, "- %s: %s\n", name, value)
+		}
+	}
+	writeField("URL", t.URL)
+	writeField("Author", t.Author)
+	writeField("State", t.State)
+	writeField("Created", formatDate(t.CreatedAt))
+	writeField("Updated", formatDate(t.UpdatedAt))
+	writeField("Labels", strings.Join(t.Labels, ", "))
+
+	if paths := MentionedPaths(t); len(paths) > 0 {
+		sb.WriteString("\n" + relatedFilesHeading + "\n")
+		for _, p := range paths {
+			fmt.Fprintf(&sb, "- %s\n", p)
+		}
+	}
+
+	if body := strings.TrimSpace(t.Body); body != "" {
+		sb.WriteString("\n" + descriptionHeading + "\n\n")
+		sb.WriteString(body)
+		sb.WriteString("\n")
+	}
+
+	if len(t.Comments) > 0 {
+		sb.WriteString("\n## Comments\n")
+		for _, c := range t.Comments {
+			body := strings.TrimSpace(c.Body)
+			if body == "" {
+				continue
+			}
+			header := c.Author
+			if header == "" {
+				header = "unknown"
+			}
+			if date := formatDate(c.CreatedAt); date != "" {
+				header += " (" + date + ")"
+			}
+			if c.Path != "" {
+				header += " on " + c.Path
+			}
+			fmt.Fprintf(&sb, "\n### %s\n\n%s\n", header, body)
+		}
+	}
+	return sb.String()
+}
+
+// normalizePath はパスの先頭の ./ や / を除き、末尾の句読点を取り除く
+func normalizePath(p string) string {
+	p = strings.TrimSpace(strings.ReplaceAll(p, "\\", "/"))
+	p = strings.TrimRight(p, ".,;:")
+	for strings.HasPrefix(p, "./") || strings.HasPrefix(p, "../") {
+		p = p[strings.Index(p, "/")+1:]
+	}
+	return strings.TrimPrefix(p, "/")
+}
+
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.DateOnly)
+}
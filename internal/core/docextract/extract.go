@@ -0,0 +1,126 @@
+package docextract
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Format は文書ファイルの形式
+type Format string
+
+const (
+	FormatPDF  Format = "pdf"
+	FormatDOCX Format = "docx"
+	FormatXLSX Format = "xlsx"
+)
+
+// DetectFormat はファイルの拡張子から文書ファイルの形式を判定する（文書ファイルでない場合は false）
+func DetectFormat(filePath string) (Format, bool) {
+	switch strings.ToLower(path.Ext(filePath)) {
+	case ".pdf":
+		return FormatPDF, true
+	case ".docx":
+		return FormatDOCX, true
+	case ".xlsx":
+		return FormatXLSX, true
+	default:
+		return "", false
+	}
+}
+
+// Document は文書ファイルから抽出したテキスト
+type Document struct {
+	Format Format
+	Pages  []*Page // テキストを含むページ（ページ番号順）
+}
+
+// Page は文書の1ページ分のテキスト
+// docx は保存時に記録された改ページ位置で区切り、xlsx はシートを1ページとして扱う
+type Page struct {
+	Number int    // ページ番号（1始まり、xlsx はシートの番号）
+	Name   string // シート名（xlsx のみ）
+	Text   string
+
+	startLine int // Text() での開始行（1始まり）
+	endLine   int // Text() での終了行
+}
+
+// Extract は文書ファイルの内容からページごとのテキストを抽出する
+func Extract(format Format, content []byte) (*Document, error) {
+	var pages []*Page
+	var err error
+	switch format {
+	case FormatPDF:
+		pages, err = extractPDF(content)
+	case FormatDOCX:
+		pages, err = extractDOCX(content)
+	case FormatXLSX:
+		pages, err = extractXLSX(content)
+	default:
+		return nil, fmt.Errorf("unsupported document format: %s", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return newDocument(format, pages), nil
+}
+
+// newDocument はテキストを含まないページを除き、各ページの Text() での行範囲を計算する
+func newDocument(format Format, pages []*Page) *Document {
+	doc := &Document{Format: format}
+	line := 1
+	for _, p := range pages {
+		p.Text = normalizeText(p.Text)
+		if p.Text == "" {
+			continue
+		}
+		p.startLine = line
+		p.endLine = line + strings.Count(p.Text, "\n")
+		line = p.endLine + 1
+		doc.Pages = append(doc.Pages, p)
+	}
+	return doc
+}
+
+// Text はページのテキストを改行で連結して返す
+func (d *Document) Text() string {
+	texts := make([]string, 0, len(d.Pages))
+	for _, p := range d.Pages {
+		texts = append(texts, p.Text)
+	}
+	return strings.Join(texts, "\n")
+}
+
+// PageAtLine は Text() の行番号（1始まり）を含むページを返す（範囲外の場合は nil）
+func (d *Document) PageAtLine(line int) *Page {
+	for _, p := range d.Pages {
+		if line >= p.startLine && line <= p.endLine {
+			return p
+		}
+	}
+	return nil
+}
+
+// normalizeText はデータベースに保存できない NUL 文字と行末の空白を除き、連続する空行を1行にまとめる
+func normalizeText(text string) string {
+	text = strings.ReplaceAll(text, "\x00", "")
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+
+	lines := strings.Split(text, "\n")
+	kept := make([]string, 0, len(lines))
+	blank := false
+	for _, line := range lines {
+		line = strings.TrimRight(line, " \t\r")
+		if line == "" {
+			if blank {
+				continue
+			}
+			blank = true
+		} else {
+			blank = false
+		}
+		kept = append(kept, line)
+	}
+	return strings.TrimSpace(strings.Join(kept, "\n"))
+}
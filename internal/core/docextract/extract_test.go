@@ -0,0 +1,131 @@
+package docextract
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func buildZip(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+// buildPDF はページごとに1行のテキストを含む最小限の PDF を生成する
+func buildPDF(pages ...string) []byte {
+	var objects []string
+	kids := make([]string, 0, len(pages))
+	for i := range pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", 4+i*2))
+	}
+	objects = append(objects,
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+	)
+	for i, text := range pages {
+		stream := fmt.Sprintf("BT /F1 12 Tf 72 720 Td (%s) Tj ET", text)
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>", 5+i*2),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(stream), stream),
+		)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return buf.Bytes()
+}
+
+func TestDetectFormat(t *testing.T) {
+	format, ok := DetectFormat("docs/Runbook.PDF")
+	assert.True(t, ok)
+	assert.Equal(t, FormatPDF, format)
+
+	_, ok = DetectFormat("docs/design.md")
+	assert.False(t, ok)
+}
+
+func TestExtract_PDF(t *testing.T) {
+	doc, err := Extract(FormatPDF, buildPDF("Deployment overview", "Rollback procedure"))
+	require.NoError(t, err)
+	require.Len(t, doc.Pages, 2)
+	assert.Equal(t, "Rollback procedure", doc.Pages[1].Text)
+
+	assert.Equal(t, "Deployment overview\nRollback procedure", doc.Text())
+	assert.Equal(t, 2, doc.PageAtLine(2).Number)
+	assert.Nil(t, doc.PageAtLine(3))
+}
+
+func TestExtract_DOCX(t *testing.T) {
+	body := `<?xml version="1.0" encoding="UTF-8"?>
+<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>
+<w:p><w:r><w:t>Runbook</w:t></w:r></w:p>
+<w:p><w:r><w:t xml:space="preserve">Step </w:t></w:r><w:r><w:tab/><w:t>one</w:t></w:r></w:p>
+<w:p><w:r><w:lastRenderedPageBreak/><w:t>Rollback</w:t></w:r></w:p>
+<w:p><w:r><w:br w:type="page"/><w:t>Contacts</w:t></w:r></w:p>
+</w:body></w:document>`
+	content := buildZip(t, map[string]string{"word/document.xml": body})
+
+	doc, err := Extract(FormatDOCX, content)
+	require.NoError(t, err)
+	require.Len(t, doc.Pages, 2)
+
+	// 保存時の改ページ位置がある場合は明示的な改ページを重複して数えない
+	assert.Equal(t, "Runbook\nStep \tone", doc.Pages[0].Text)
+	assert.Equal(t, 2, doc.Pages[1].Number)
+	assert.Equal(t, "Rollback\nContacts", doc.Pages[1].Text)
+}
+
+func TestExtract_XLSX(t *testing.T) {
+	content := buildZip(t, map[string]string{
+		"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="Servers" sheetId="1" r:id="rId1"/><sheet name="Empty" sheetId="2" r:id="rId2"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Target="worksheets/sheet1.xml"/><Relationship Id="rId2" Target="/xl/worksheets/sheet2.xml"/></Relationships>`,
+		"xl/sharedStrings.xml": `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><si><t>host</t></si><si><r><t>api-</t></r><r><t>01</t></r></si></sst>`,
+		"xl/worksheets/sheet1.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>
+<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="inlineStr"><is><t>cpu</t></is></c><c r="C1" t="inlineStr"><is><t>active</t></is></c></row>
+<row r="2"><c r="A2" t="s"><v>1</v></c><c r="B2"><v>4</v></c><c r="C2" t="b"><v>1</v></c></row>
+</sheetData></worksheet>`,
+		"xl/worksheets/sheet2.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData/></worksheet>`,
+	})
+
+	doc, err := Extract(FormatXLSX, content)
+	require.NoError(t, err)
+	require.Len(t, doc.Pages, 1)
+	assert.Equal(t, "Servers", doc.Pages[0].Name)
+	assert.Equal(t, "host\tcpu\tactive\napi-01\t4\tTRUE", doc.Pages[0].Text)
+}
+
+func TestExtract_Invalid(t *testing.T) {
+	_, err := Extract(FormatPDF, []byte("not a pdf"))
+	assert.Error(t, err)
+
+	_, err = Extract(FormatDOCX, []byte("not a zip"))
+	assert.Error(t, err)
+}
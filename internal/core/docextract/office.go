@@ -0,0 +1,246 @@
+package docextract
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// extractDOCX は docx の本文（word/document.xml）のテキストを段落単位で抽出する
+// Word が保存時に記録した改ページ位置（lastRenderedPageBreak）でページを区切り、記録がなければ明示的な改ページで区切る
+func extractDOCX(content []byte) ([]*Page, error) {
+	zr, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open docx: %w", err)
+	}
+	data, err := readZipFile(zr, "word/document.xml")
+	if err != nil {
+		return nil, err
+	}
+	useRenderedBreaks := bytes.Contains(data, []byte("lastRenderedPageBreak"))
+
+	var pages []*Page
+	var current strings.Builder
+	number := 1
+	flush := func() {
+		pages = append(pages, &Page{Number: number, Text: current.String()})
+		current.Reset()
+		number++
+	}
+
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	inText := false
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse docx: %w", err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "t":
+				inText = true
+			case "tab":
+				current.WriteString("\t")
+			case "br", "cr":
+				if attrValue(t, "type") == "page" {
+					if !useRenderedBreaks {
+						flush()
+					}
+					continue
+				}
+				current.WriteString("\n")
+			case "lastRenderedPageBreak":
+				flush()
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				current.WriteString("\n")
+			}
+		case xml.CharData:
+			if inText {
+				current.Write(t)
+			}
+		}
+	}
+	flush()
+	return pages, nil
+}
+
+// xlsxWorkbook は xl/workbook.xml のシート一覧
+type xlsxWorkbook struct {
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+		RID  string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+// xlsxRelationships は xl/_rels/workbook.xml.rels のリレーションシップ
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+// xlsxSharedStrings は xl/sharedStrings.xml の共有文字列
+type xlsxSharedStrings struct {
+	Items []struct {
+		Text string `xml:"t"`
+		Runs []struct {
+			Text string `xml:"t"`
+		} `xml:"r"`
+	} `xml:"si"`
+}
+
+// xlsxWorksheet はワークシートのセルの値
+type xlsxWorksheet struct {
+	Rows []struct {
+		Cells []struct {
+			Type   string `xml:"t,attr"`
+			Value  string `xml:"v"`
+			Inline string `xml:"is>t"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// extractXLSX は xlsx の各シートのセルの値を行ごとにタブ区切りで抽出する（シートを1ページとして扱う）
+func extractXLSX(content []byte) ([]*Page, error) {
+	zr, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open xlsx: %w", err)
+	}
+
+	var workbook xlsxWorkbook
+	if err := decodeZipXML(zr, "xl/workbook.xml", &workbook); err != nil {
+		return nil, err
+	}
+	var rels xlsxRelationships
+	if err := decodeZipXML(zr, "xl/_rels/workbook.xml.rels", &rels); err != nil {
+		return nil, err
+	}
+	targets := make(map[string]string, len(rels.Relationships))
+	for _, r := range rels.Relationships {
+		if strings.HasPrefix(r.Target, "/") {
+			targets[r.ID] = strings.TrimPrefix(r.Target, "/")
+		} else {
+			targets[r.ID] = path.Join("xl", r.Target)
+		}
+	}
+
+	// 共有文字列を使用しないブックもある
+	var sharedStrings []string
+	if zipFile(zr, "xl/sharedStrings.xml") != nil {
+		var sst xlsxSharedStrings
+		if err := decodeZipXML(zr, "xl/sharedStrings.xml", &sst); err != nil {
+			return nil, err
+		}
+		for _, item := range sst.Items {
+			text := item.Text
+			for _, run := range item.Runs {
+				text += run.Text
+			}
+			sharedStrings = append(sharedStrings, text)
+		}
+	}
+
+	pages := make([]*Page, 0, len(workbook.Sheets))
+	for i, sheet := range workbook.Sheets {
+		target, ok := targets[sheet.RID]
+		if !ok {
+			continue
+		}
+		var worksheet xlsxWorksheet
+		if err := decodeZipXML(zr, target, &worksheet); err != nil {
+			return nil, err
+		}
+
+		lines := make([]string, 0, len(worksheet.Rows))
+		for _, row := range worksheet.Rows {
+			values := make([]string, 0, len(row.Cells))
+			for _, c := range row.Cells {
+				value := c.Value
+				switch c.Type {
+				case "s":
+					if idx, err := strconv.Atoi(c.Value); err == nil && idx >= 0 && idx < len(sharedStrings) {
+						value = sharedStrings[idx]
+					}
+				case "inlineStr":
+					value = c.Inline
+				case "b":
+					value = strings.ToUpper(strconv.FormatBool(c.Value == "1"))
+				}
+				values = append(values, strings.ReplaceAll(value, "\n", " "))
+			}
+			if line := strings.Join(values, "\t"); strings.TrimSpace(line) != "" {
+				lines = append(lines, line)
+			}
+		}
+		pages = append(pages, &Page{
+			Number: i + 1,
+			Name:   sheet.Name,
+			Text:   strings.Join(lines, "\n"),
+		})
+	}
+	return pages, nil
+}
+
+func zipFile(zr *zip.Reader, name string) *zip.File {
+	for _, f := range zr.File {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+func readZipFile(zr *zip.Reader, name string) ([]byte, error) {
+	f := zipFile(zr, name)
+	if f == nil {
+		return nil, fmt.Errorf("%s not found", name)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", name, err)
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	return data, nil
+}
+
+func decodeZipXML(zr *zip.Reader, name string, v any) error {
+	data, err := readZipFile(zr, name)
+	if err != nil {
+		return err
+	}
+	if err := xml.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+	return nil
+}
+
+func attrValue(e xml.StartElement, local string) string {
+	for _, a := range e.Attr {
+		if a.Name.Local == local {
+			return a.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,51 @@
+package docextract
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/ledongthuc/pdf"
+)
+
+// extractPDF は PDF の各ページのテキストを行単位で抽出する
+// 暗号化された PDF や画像のみの PDF（スキャン）はテキストを抽出できない
+func extractPDF(content []byte) (pages []*Page, err error) {
+	// 不正な PDF で pdf パッケージが panic することがあるためエラーに変換する
+	defer func() {
+		if r := recover(); r != nil {
+			pages = nil
+			err = fmt.Errorf("failed to parse pdf: %v", r)
+		}
+	}()
+
+	reader, err := pdf.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open pdf: %w", err)
+	}
+
+	for num := 1; num <= reader.NumPage(); num++ {
+		page := reader.Page(num)
+		if page.V.IsNull() {
+			continue
+		}
+		rows, err := page.GetTextByRow()
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract text from page %d: %w", num, err)
+		}
+
+		lines := make([]string, 0, len(rows))
+		for _, row := range rows {
+			var sb strings.Builder
+			for _, text := range row.Content {
+				sb.WriteString(text.S)
+			}
+			lines = append(lines, sb.String())
+		}
+		pages = append(pages, &Page{
+			Number: num,
+			Text:   strings.Join(lines, "\n"),
+		})
+	}
+	return pages, nil
+}
@@ -0,0 +1,306 @@
+// Package drift は連続するスナップショットの依存関係グラフを比較し、アーキテクチャの大きな変化
+// （モジュール間の新しい依存・循環依存の発生・重要度の変動）を検出する
+package drift
+
+import (
+	"path"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/jinford/dev-rag/internal/core/graph"
+)
+
+// moduleExampleLimit はモジュール間の依存ごとに記録する依存の例の最大件数
+const moduleExampleLimit = 3
+
+// Policy はドリフトを大きな変化とみなす基準
+type Policy struct {
+	MinNewDependencies  int     // 大きな変化とみなすモジュール間の新しい依存の最小件数
+	MinImportanceShift  float64 // 変動として報告する重要度（importance_score）の増減の下限
+	MaxImportanceShifts int     // 報告する重要度の変動の最大件数（変動の大きい順）
+}
+
+// DefaultPolicy は既定の基準
+var DefaultPolicy = Policy{MinNewDependencies: 3, MinImportanceShift: 0.2, MaxImportanceShifts: 10}
+
+// ModuleDependency はモジュール（ディレクトリ）間の依存を表す
+type ModuleDependency struct {
+	From     string   `json:"from"`
+	To       string   `json:"to"`
+	Edges    int      `json:"edges"`    // モジュール間のチャンクの依存の件数
+	Examples []string `json:"examples"` // 依存の例（「依存元 -> 依存先」）
+}
+
+// ImportanceShift はチャンクの重要度の変動を表す
+type ImportanceShift struct {
+	FilePath string  `json:"path"`
+	Name     string  `json:"name"`
+	From     float64 `json:"from"`
+	To       float64 `json:"to"`
+}
+
+// Delta は重要度の増減を返す
+func (s *ImportanceShift) Delta() float64 {
+	return s.To - s.From
+}
+
+// Report は1つのソースの連続する2つのスナップショットのアーキテクチャの変化を表す
+type Report struct {
+	SourceID            uuid.UUID           `json:"sourceID"`
+	SourceName          string              `json:"sourceName"`
+	FromSnapshotID      uuid.UUID           `json:"fromSnapshotID"`
+	FromVersion         string              `json:"fromVersion"`
+	ToSnapshotID        uuid.UUID           `json:"toSnapshotID"`
+	ToVersion           string              `json:"toVersion"`
+	NewDependencies     []*ModuleDependency `json:"newDependencies"`
+	RemovedDependencies []*ModuleDependency `json:"removedDependencies"`
+	NewCycles           [][]string          `json:"newCycles"` // 新たに生じた（または範囲が変わった）モジュールの循環依存
+	ImportanceShifts    []*ImportanceShift  `json:"importanceShifts"`
+}
+
+// Significant は基準に照らして大きな変化かを返す
+// 循環依存の発生と重要度の変動は1件でも、モジュール間の新しい依存は MinNewDependencies 件以上で大きな変化とみなす
+func (r *Report) Significant(policy Policy) bool {
+	return len(r.NewCycles) > 0 ||
+		len(r.ImportanceShifts) > 0 ||
+		(len(r.NewDependencies) > 0 && len(r.NewDependencies) >= policy.MinNewDependencies)
+}
+
+// Empty は変化がないかを返す
+func (r *Report) Empty() bool {
+	return len(r.NewDependencies) == 0 && len(r.RemovedDependencies) == 0 && len(r.NewCycles) == 0 && len(r.ImportanceShifts) == 0
+}
+
+// LinkedFiles は変化に関係するファイル（新しい依存の依存元・重要度が変動したチャンクのファイル）を返す
+func (r *Report) LinkedFiles() []string {
+	var files []string
+	add := func(f string) {
+		if f != "" && !slices.Contains(files, f) {
+			files = append(files, f)
+		}
+	}
+	for _, dep := range r.NewDependencies {
+		for _, example := range dep.Examples {
+			from, _, _ := strings.Cut(example, " -> ")
+			add(from)
+		}
+	}
+	for _, shift := range r.ImportanceShifts {
+		add(shift.FilePath)
+	}
+	return files
+}
+
+// ModuleOf はファイルパスが属するモジュール（ディレクトリ）を返す（ルート直下は "."）
+func ModuleOf(filePath string) string {
+	return path.Dir(filePath)
+}
+
+// Compare は変更前後の依存関係グラフを比較する
+// モジュールはファイルのディレクトリとし、チャンクはファイルパスと名前で対応付ける
+func Compare(prev, curr *graph.Graph, policy Policy) *Report {
+	prevDeps := moduleDependencies(prev)
+	currDeps := moduleDependencies(curr)
+
+	report := &Report{
+		NewDependencies:     []*ModuleDependency{},
+		RemovedDependencies: []*ModuleDependency{},
+		NewCycles:           [][]string{},
+		ImportanceShifts:    []*ImportanceShift{},
+	}
+	for key, dep := range currDeps {
+		if _, ok := prevDeps[key]; !ok {
+			report.NewDependencies = append(report.NewDependencies, dep)
+		}
+	}
+	for key, dep := range prevDeps {
+		if _, ok := currDeps[key]; !ok {
+			report.RemovedDependencies = append(report.RemovedDependencies, dep)
+		}
+	}
+	sortDependencies(report.NewDependencies)
+	sortDependencies(report.RemovedDependencies)
+
+	prevCycles := make(map[string]bool)
+	for _, cycle := range moduleCycles(prevDeps) {
+		prevCycles[strings.Join(cycle, "\x00")] = true
+	}
+	for _, cycle := range moduleCycles(currDeps) {
+		if !prevCycles[strings.Join(cycle, "\x00")] {
+			report.NewCycles = append(report.NewCycles, cycle)
+		}
+	}
+
+	report.ImportanceShifts = importanceShifts(prev, curr, policy)
+	return report
+}
+
+// moduleDependencies はチャンクの依存をモジュール間の依存に集約する（同じモジュール内の依存は除く）
+func moduleDependencies(g *graph.Graph) map[[2]string]*ModuleDependency {
+	deps := make(map[[2]string]*ModuleDependency)
+	if g == nil {
+		return deps
+	}
+	nodes := make(map[uuid.UUID]*graph.Node, len(g.Nodes))
+	for _, n := range g.Nodes {
+		nodes[n.ID] = n
+	}
+	for _, e := range g.Edges {
+		from, to := nodes[e.From], nodes[e.To]
+		if from == nil || to == nil {
+			continue
+		}
+		fromModule, toModule := ModuleOf(from.FilePath), ModuleOf(to.FilePath)
+		if fromModule == toModule {
+			continue
+		}
+		key := [2]string{fromModule, toModule}
+		dep, ok := deps[key]
+		if !ok {
+			dep = &ModuleDependency{From: fromModule, To: toModule, Examples: []string{}}
+			deps[key] = dep
+		}
+		dep.Edges++
+		example := from.FilePath + " -> " + to.FilePath
+		if len(dep.Examples) < moduleExampleLimit && !slices.Contains(dep.Examples, example) {
+			dep.Examples = append(dep.Examples, example)
+		}
+	}
+	return deps
+}
+
+// sortDependencies は依存の件数の多い順（同数はモジュール名順）に並べ替える
+func sortDependencies(deps []*ModuleDependency) {
+	sort.Slice(deps, func(i, j int) bool {
+		if deps[i].Edges != deps[j].Edges {
+			return deps[i].Edges > deps[j].Edges
+		}
+		if deps[i].From != deps[j].From {
+			return deps[i].From < deps[j].From
+		}
+		return deps[i].To < deps[j].To
+	})
+}
+
+// moduleCycles はモジュール間の依存の強連結成分のうち、2つ以上のモジュールを含むもの（循環依存）を返す
+// 各循環はモジュール名順、循環の一覧は先頭のモジュール名順に並べる
+func moduleCycles(deps map[[2]string]*ModuleDependency) [][]string {
+	adjacency := make(map[string][]string)
+	var modules []string
+	for key := range deps {
+		for _, m := range key {
+			if _, ok := adjacency[m]; !ok {
+				adjacency[m] = nil
+				modules = append(modules, m)
+			}
+		}
+		adjacency[key[0]] = append(adjacency[key[0]], key[1])
+	}
+	sort.Strings(modules)
+	for _, m := range modules {
+		sort.Strings(adjacency[m])
+	}
+
+	// Tarjan の強連結成分分解
+	index := make(map[string]int)
+	lowlink := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+	var cycles [][]string
+	var visit func(m string)
+	visit = func(m string) {
+		index[m] = len(index)
+		lowlink[m] = index[m]
+		stack = append(stack, m)
+		onStack[m] = true
+		for _, next := range adjacency[m] {
+			if _, ok := index[next]; !ok {
+				visit(next)
+				lowlink[m] = min(lowlink[m], lowlink[next])
+			} else if onStack[next] {
+				lowlink[m] = min(lowlink[m], index[next])
+			}
+		}
+		if lowlink[m] != index[m] {
+			return
+		}
+		var component []string
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			component = append(component, top)
+			if top == m {
+				break
+			}
+		}
+		if len(component) > 1 {
+			sort.Strings(component)
+			cycles = append(cycles, component)
+		}
+	}
+	for _, m := range modules {
+		if _, ok := index[m]; !ok {
+			visit(m)
+		}
+	}
+	sort.Slice(cycles, func(i, j int) bool {
+		return cycles[i][0] < cycles[j][0]
+	})
+	return cycles
+}
+
+// importanceShifts は名前のあるチャンクの重要度の増減が基準以上のものを変動の大きい順に返す
+func importanceShifts(prev, curr *graph.Graph, policy Policy) []*ImportanceShift {
+	before := namedImportance(prev)
+	shifts := []*ImportanceShift{}
+	for key, to := range namedImportance(curr) {
+		from, ok := before[key]
+		if !ok {
+			continue
+		}
+		shift := &ImportanceShift{FilePath: key[0], Name: key[1], From: from, To: to}
+		if abs(shift.Delta()) >= policy.MinImportanceShift {
+			shifts = append(shifts, shift)
+		}
+	}
+	sort.Slice(shifts, func(i, j int) bool {
+		di, dj := abs(shifts[i].Delta()), abs(shifts[j].Delta())
+		if di != dj {
+			return di > dj
+		}
+		if shifts[i].FilePath != shifts[j].FilePath {
+			return shifts[i].FilePath < shifts[j].FilePath
+		}
+		return shifts[i].Name < shifts[j].Name
+	})
+	if policy.MaxImportanceShifts > 0 && len(shifts) > policy.MaxImportanceShifts {
+		shifts = shifts[:policy.MaxImportanceShifts]
+	}
+	return shifts
+}
+
+// namedImportance は重要度を算出済みの名前のあるチャンクの重要度を（ファイルパス, 名前）ごとに返す
+func namedImportance(g *graph.Graph) map[[2]string]float64 {
+	result := make(map[[2]string]float64)
+	if g == nil {
+		return result
+	}
+	for _, n := range g.Nodes {
+		if n.Name == "" || n.Importance == nil {
+			continue
+		}
+		result[[2]string{n.FilePath, n.Name}] = *n.Importance
+	}
+	return result
+}
+
+func abs(f float64) float64 {
+	if f < 0 {
+		return -f
+	}
+	return f
+}
@@ -0,0 +1,162 @@
+package drift
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jinford/dev-rag/internal/core/graph"
+	"github.com/jinford/dev-rag/internal/core/quality"
+)
+
+// graphBuilder はファイルパスと名前からテスト用の依存関係グラフを組み立てる
+type graphBuilder struct {
+	g     *graph.Graph
+	nodes map[string]*graph.Node
+}
+
+func newGraphBuilder() *graphBuilder {
+	return &graphBuilder{g: &graph.Graph{}, nodes: map[string]*graph.Node{}}
+}
+
+func (b *graphBuilder) node(path, name string, importance float64) *graph.Node {
+	key := path + "#" + name
+	if n, ok := b.nodes[key]; ok {
+		return n
+	}
+	n := &graph.Node{ID: uuid.New(), FilePath: path, Name: name, Importance: &importance}
+	b.nodes[key] = n
+	b.g.Nodes = append(b.g.Nodes, n)
+	return n
+}
+
+func (b *graphBuilder) edge(fromPath, toPath string) *graphBuilder {
+	from, to := b.node(fromPath, "F", 0.5), b.node(toPath, "F", 0.5)
+	b.g.Edges = append(b.g.Edges, &graph.Edge{From: from.ID, To: to.ID, DepType: "call"})
+	return b
+}
+
+func TestCompare(t *testing.T) {
+	prev := newGraphBuilder().
+		edge("api/handler.go", "service/user.go").
+		edge("service/user.go", "repo/user.go").
+		edge("service/user.go", "service/util.go").
+		edge("legacy/a.go", "repo/user.go")
+	prev.node("service/user.go", "Hot", 0.1)
+
+	curr := newGraphBuilder().
+		edge("api/handler.go", "service/user.go").
+		edge("service/user.go", "repo/user.go").
+		edge("repo/user.go", "service/user.go").
+		edge("repo/user.go", "api/handler.go").
+		edge("repo/cache.go", "api/handler.go")
+	curr.node("service/user.go", "Hot", 0.8)
+
+	report := Compare(prev.g, curr.g, DefaultPolicy)
+
+	require.Len(t, report.NewDependencies, 2)
+	assert.Equal(t, "repo", report.NewDependencies[0].From)
+	assert.Equal(t, "api", report.NewDependencies[0].To)
+	assert.Equal(t, 2, report.NewDependencies[0].Edges)
+	assert.Equal(t, "service", report.NewDependencies[1].To)
+
+	require.Len(t, report.RemovedDependencies, 1, "同じモジュール内の依存は含めない")
+	assert.Equal(t, "legacy", report.RemovedDependencies[0].From)
+
+	assert.Equal(t, [][]string{{"api", "repo", "service"}}, report.NewCycles)
+
+	require.Len(t, report.ImportanceShifts, 1)
+	assert.Equal(t, "Hot", report.ImportanceShifts[0].Name)
+	assert.InDelta(t, 0.7, report.ImportanceShifts[0].Delta(), 1e-9)
+
+	assert.True(t, report.Significant(DefaultPolicy))
+	assert.Contains(t, report.LinkedFiles(), "repo/user.go")
+	assert.Contains(t, report.LinkedFiles(), "service/user.go")
+
+	// 同じグラフ同士では変化はない
+	assert.True(t, Compare(curr.g, curr.g, DefaultPolicy).Empty())
+}
+
+func TestReport_Significant(t *testing.T) {
+	report := &Report{NewDependencies: []*ModuleDependency{{From: "a", To: "b"}, {From: "a", To: "c"}}}
+	assert.False(t, report.Significant(DefaultPolicy), "新しい依存が少ない場合は大きな変化とみなさない")
+	assert.True(t, report.Significant(Policy{MinNewDependencies: 2}))
+}
+
+type stubSnapshotReader struct {
+	snapshots []*Snapshot
+}
+
+func (r *stubSnapshotReader) GetSnapshot(ctx context.Context, id uuid.UUID) (mo.Option[*Snapshot], error) {
+	for _, s := range r.snapshots {
+		if s.ID == id {
+			return mo.Some(s), nil
+		}
+	}
+	return mo.None[*Snapshot](), nil
+}
+
+func (r *stubSnapshotReader) ListSnapshots(ctx context.Context, sourceID uuid.UUID) ([]*Snapshot, error) {
+	return r.snapshots, nil
+}
+
+func (r *stubSnapshotReader) ListSourceIDs(ctx context.Context, productID uuid.UUID) ([]uuid.UUID, error) {
+	return []uuid.UUID{r.snapshots[0].SourceID}, nil
+}
+
+type stubGraphReader struct {
+	graphs map[uuid.UUID]*graph.Graph
+}
+
+func (r *stubGraphReader) ReadGraph(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID], filter graph.Filter) (*graph.Graph, error) {
+	return r.graphs[snapshotID.MustGet()], nil
+}
+
+type stubNoteRecorder struct {
+	notes []quality.AddNoteParams
+}
+
+func (r *stubNoteRecorder) AddNote(ctx context.Context, params quality.AddNoteParams) (*quality.Note, error) {
+	r.notes = append(r.notes, params)
+	return &quality.Note{NoteID: "QN-2026-001"}, nil
+}
+
+func TestService_DetectForSnapshot(t *testing.T) {
+	sourceID := uuid.New()
+	now := time.Now()
+	first := &Snapshot{ID: uuid.New(), SourceID: sourceID, SourceName: "backend", Version: "aaa", Indexed: true, CreatedAt: now.Add(-2 * time.Hour)}
+	failed := &Snapshot{ID: uuid.New(), SourceID: sourceID, SourceName: "backend", Version: "bbb", CreatedAt: now.Add(-time.Hour)}
+	second := &Snapshot{ID: uuid.New(), SourceID: sourceID, SourceName: "backend", Version: "ccc", Indexed: true, CreatedAt: now}
+
+	graphs := &stubGraphReader{graphs: map[uuid.UUID]*graph.Graph{
+		first.ID:  newGraphBuilder().edge("a/x.go", "b/y.go").g,
+		second.ID: newGraphBuilder().edge("a/x.go", "b/y.go").edge("b/y.go", "a/x.go").g,
+	}}
+	notes := &stubNoteRecorder{}
+	svc := NewService(&stubSnapshotReader{snapshots: []*Snapshot{first, failed, second}}, graphs, WithNoteRecorder(notes))
+
+	report, err := svc.DetectForSnapshot(context.Background(), second.ID)
+	require.NoError(t, err)
+	require.True(t, report.IsPresent())
+	assert.Equal(t, first.ID, report.MustGet().FromSnapshotID, "インデックス済みでないスナップショットは比較しない")
+
+	require.Len(t, notes.notes, 1)
+	assert.Equal(t, quality.SeverityHigh, notes.notes[0].Severity)
+	assert.Equal(t, NoteReviewer, notes.notes[0].Reviewer)
+	assert.Contains(t, notes.notes[0].NoteText, "a <-> b")
+
+	// 最初のスナップショットは比較対象がない
+	report, err = svc.DetectForSnapshot(context.Background(), first.ID)
+	require.NoError(t, err)
+	assert.True(t, report.IsAbsent())
+
+	reports, err := svc.RecentChanges(context.Background(), mo.Some(uuid.New()), mo.None[uuid.UUID]())
+	require.NoError(t, err)
+	require.Len(t, reports, 1)
+	assert.Equal(t, second.ID, reports[0].ToSnapshotID)
+}
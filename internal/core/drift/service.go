@@ -0,0 +1,263 @@
+package drift
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+
+	"github.com/jinford/dev-rag/internal/core/graph"
+	"github.com/jinford/dev-rag/internal/core/quality"
+)
+
+// NoteReviewer はドリフトを記録した品質ノートの記録者
+const NoteReviewer = "architecture-drift"
+
+// noteItemLimit は品質ノートに列挙する変化の種類ごとの最大件数
+const noteItemLimit = 5
+
+// Snapshot は比較対象のスナップショット
+type Snapshot struct {
+	ID         uuid.UUID
+	SourceID   uuid.UUID
+	SourceName string
+	Version    string // バージョン識別子（コミットハッシュなど）
+	Indexed    bool
+	CreatedAt  time.Time
+}
+
+// SnapshotReader は比較対象のスナップショットを読み取るインターフェース
+// ingestion パッケージは wiki パッケージ経由でこのパッケージに依存するため、ingestion の型は使用しない
+type SnapshotReader interface {
+	// GetSnapshot はスナップショットを取得する
+	GetSnapshot(ctx context.Context, id uuid.UUID) (mo.Option[*Snapshot], error)
+	// ListSnapshots はソースのスナップショットを取得する
+	ListSnapshots(ctx context.Context, sourceID uuid.UUID) ([]*Snapshot, error)
+	// ListSourceIDs はプロダクトのソースのIDを取得する
+	ListSourceIDs(ctx context.Context, productID uuid.UUID) ([]uuid.UUID, error)
+}
+
+// NoteRecorder はドリフトを品質ノートとして記録するインターフェース（quality.Service が満たす）
+type NoteRecorder interface {
+	AddNote(ctx context.Context, params quality.AddNoteParams) (*quality.Note, error)
+}
+
+// Service はスナップショットのアーキテクチャのドリフトを検出する
+type Service struct {
+	snapshots SnapshotReader
+	graphs    graph.Reader
+	notes     NoteRecorder
+	policy    Policy
+	logger    *slog.Logger
+}
+
+// ServiceOption は Service のオプション設定
+type ServiceOption func(*Service)
+
+// WithServiceLogger は Service にロガーを設定する
+func WithServiceLogger(logger *slog.Logger) ServiceOption {
+	return func(s *Service) {
+		s.logger = logger
+	}
+}
+
+// WithPolicy はドリフトを大きな変化とみなす基準を設定する（既定は DefaultPolicy）
+func WithPolicy(policy Policy) ServiceOption {
+	return func(s *Service) {
+		s.policy = policy
+	}
+}
+
+// WithNoteRecorder は大きな変化を品質ノートとして記録する先を設定する（未設定の場合は記録しない）
+func WithNoteRecorder(recorder NoteRecorder) ServiceOption {
+	return func(s *Service) {
+		s.notes = recorder
+	}
+}
+
+// NewService は新しい Service を作成する
+func NewService(snapshots SnapshotReader, graphs graph.Reader, opts ...ServiceOption) *Service {
+	svc := &Service{
+		snapshots: snapshots,
+		graphs:    graphs,
+		policy:    DefaultPolicy,
+		logger:    slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(svc)
+	}
+	if svc.logger == nil {
+		svc.logger = slog.Default()
+	}
+	return svc
+}
+
+// DetectForSnapshot はスナップショットを同じソースの直前のインデックス済みスナップショットと比較する
+// 大きな変化の場合は品質ノートを記録する。直前のスナップショットがない場合は None を返す
+func (s *Service) DetectForSnapshot(ctx context.Context, snapshotID uuid.UUID) (mo.Option[*Report], error) {
+	report, err := s.compareWithPrevious(ctx, snapshotID)
+	if err != nil {
+		return mo.None[*Report](), err
+	}
+	r, ok := report.Get()
+	if !ok {
+		return report, nil
+	}
+
+	if r.Significant(s.policy) && s.notes != nil {
+		note, err := s.notes.AddNote(ctx, quality.AddNoteParams{
+			Severity:    noteSeverity(r),
+			NoteText:    noteText(r),
+			LinkedFiles: r.LinkedFiles(),
+			Reviewer:    NoteReviewer,
+		})
+		if err != nil {
+			return mo.None[*Report](), fmt.Errorf("failed to record drift note: %w", err)
+		}
+		s.logger.Info("recorded architecture drift", "source", r.SourceName, "note", note.NoteID,
+			"new_dependencies", len(r.NewDependencies), "new_cycles", len(r.NewCycles), "importance_shifts", len(r.ImportanceShifts))
+	}
+	return report, nil
+}
+
+// RecentChanges は各スナップショットを直前のインデックス済みスナップショットと比較した結果を返す
+// productID 指定時はプロダクトの各ソースの最新のインデックス済みスナップショット、snapshotID 指定時はそのスナップショットを対象とする
+// 直前のスナップショットがないソースは含めない
+func (s *Service) RecentChanges(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID]) ([]*Report, error) {
+	var targets []uuid.UUID
+	if id, ok := snapshotID.Get(); ok {
+		targets = append(targets, id)
+	} else if id, ok := productID.Get(); ok {
+		sourceIDs, err := s.snapshots.ListSourceIDs(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list sources: %w", err)
+		}
+		for _, sourceID := range sourceIDs {
+			snapshots, err := s.snapshots.ListSnapshots(ctx, sourceID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list snapshots: %w", err)
+			}
+			if latest, ok := latestIndexed(snapshots, nil).Get(); ok {
+				targets = append(targets, latest.ID)
+			}
+		}
+	}
+
+	reports := []*Report{}
+	for _, id := range targets {
+		report, err := s.compareWithPrevious(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if r, ok := report.Get(); ok {
+			reports = append(reports, r)
+		}
+	}
+	return reports, nil
+}
+
+// compareWithPrevious はスナップショットを同じソースの直前のインデックス済みスナップショットと比較する
+func (s *Service) compareWithPrevious(ctx context.Context, snapshotID uuid.UUID) (mo.Option[*Report], error) {
+	snapshotOpt, err := s.snapshots.GetSnapshot(ctx, snapshotID)
+	if err != nil {
+		return mo.None[*Report](), fmt.Errorf("failed to get snapshot: %w", err)
+	}
+	snapshot, ok := snapshotOpt.Get()
+	if !ok {
+		return mo.None[*Report](), fmt.Errorf("snapshot not found: %s", snapshotID)
+	}
+
+	snapshots, err := s.snapshots.ListSnapshots(ctx, snapshot.SourceID)
+	if err != nil {
+		return mo.None[*Report](), fmt.Errorf("failed to list snapshots: %w", err)
+	}
+	previous, ok := latestIndexed(snapshots, snapshot).Get()
+	if !ok {
+		s.logger.Debug("no previous snapshot to compare", "source", snapshot.SourceName, "snapshot_id", snapshot.ID)
+		return mo.None[*Report](), nil
+	}
+
+	prevGraph, err := s.graphs.ReadGraph(ctx, mo.None[uuid.UUID](), mo.Some(previous.ID), graph.Filter{})
+	if err != nil {
+		return mo.None[*Report](), fmt.Errorf("failed to read dependency graph: %w", err)
+	}
+	currGraph, err := s.graphs.ReadGraph(ctx, mo.None[uuid.UUID](), mo.Some(snapshot.ID), graph.Filter{})
+	if err != nil {
+		return mo.None[*Report](), fmt.Errorf("failed to read dependency graph: %w", err)
+	}
+
+	report := Compare(prevGraph, currGraph, s.policy)
+	report.SourceID = snapshot.SourceID
+	report.SourceName = snapshot.SourceName
+	report.FromSnapshotID = previous.ID
+	report.FromVersion = previous.Version
+	report.ToSnapshotID = snapshot.ID
+	report.ToVersion = snapshot.Version
+	return mo.Some(report), nil
+}
+
+// latestIndexed はインデックス済みのスナップショットのうち最も新しいものを返す
+// before を指定した場合は before より前に作成したものに限る
+func latestIndexed(snapshots []*Snapshot, before *Snapshot) mo.Option[*Snapshot] {
+	var latest *Snapshot
+	for _, snapshot := range snapshots {
+		if !snapshot.Indexed {
+			continue
+		}
+		if before != nil && (snapshot.ID == before.ID || !snapshot.CreatedAt.Before(before.CreatedAt)) {
+			continue
+		}
+		if latest == nil || snapshot.CreatedAt.After(latest.CreatedAt) {
+			latest = snapshot
+		}
+	}
+	if latest == nil {
+		return mo.None[*Snapshot]()
+	}
+	return mo.Some(latest)
+}
+
+// noteSeverity は変化の深刻度を返す（循環依存の発生は high、それ以外は medium）
+func noteSeverity(r *Report) quality.Severity {
+	if len(r.NewCycles) > 0 {
+		return quality.SeverityHigh
+	}
+	return quality.SeverityMedium
+}
+
+// noteText は品質ノートの本文を組み立てる
+func noteText(r *Report) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "アーキテクチャのドリフトを検出しました: %s (%s -> %s)", r.SourceName, ShortVersion(r.FromVersion), ShortVersion(r.ToVersion))
+	if len(r.NewCycles) > 0 {
+		fmt.Fprintf(&sb, "\n循環依存の発生 %d件:", len(r.NewCycles))
+		for _, cycle := range r.NewCycles[:min(len(r.NewCycles), noteItemLimit)] {
+			fmt.Fprintf(&sb, "\n- %s", strings.Join(cycle, " <-> "))
+		}
+	}
+	if len(r.NewDependencies) > 0 {
+		fmt.Fprintf(&sb, "\nモジュール間の新しい依存 %d件:", len(r.NewDependencies))
+		for _, dep := range r.NewDependencies[:min(len(r.NewDependencies), noteItemLimit)] {
+			fmt.Fprintf(&sb, "\n- %s -> %s (%d)", dep.From, dep.To, dep.Edges)
+		}
+	}
+	if len(r.ImportanceShifts) > 0 {
+		fmt.Fprintf(&sb, "\n重要度の変動 %d件:", len(r.ImportanceShifts))
+		for _, shift := range r.ImportanceShifts[:min(len(r.ImportanceShifts), noteItemLimit)] {
+			fmt.Fprintf(&sb, "\n- %s (%s): %.2f -> %.2f", shift.Name, shift.FilePath, shift.From, shift.To)
+		}
+	}
+	return sb.String()
+}
+
+// ShortVersion はバージョン識別子（コミットハッシュ）を表示用に短縮する
+func ShortVersion(version string) string {
+	if len(version) > 12 {
+		return version[:12]
+	}
+	return version
+}
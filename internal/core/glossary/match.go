@@ -0,0 +1,101 @@
+package glossary
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// MatchTerms はテキストに用語または別名が現れる用語を、テキスト中の出現位置順に最大 limit 件返す
+// 英数字の用語は単語の境界でのみ一致させる（"API" が "rapid" に一致しないように）
+func MatchTerms(terms []*Term, text string, limit int) []*Term {
+	lowered := strings.ToLower(text)
+
+	type match struct {
+		term *Term
+		pos  int
+	}
+	var matches []match
+	for _, term := range terms {
+		pos := -1
+		for _, name := range term.Names() {
+			if p := indexWord(lowered, strings.ToLower(name)); p >= 0 && (pos < 0 || p < pos) {
+				pos = p
+			}
+		}
+		if pos >= 0 {
+			matches = append(matches, match{term: term, pos: pos})
+		}
+	}
+
+	slices.SortStableFunc(matches, func(a, b match) int {
+		if c := cmp.Compare(a.pos, b.pos); c != 0 {
+			return c
+		}
+		// 同じ位置から始まる場合は長い用語（より具体的な用語）を優先する
+		return cmp.Compare(len(b.term.Term), len(a.term.Term))
+	})
+
+	result := make([]*Term, 0, min(len(matches), max(limit, 0)))
+	for _, m := range matches {
+		if len(result) == limit {
+			break
+		}
+		result = append(result, m.term)
+	}
+	return result
+}
+
+// indexWord は text 中で word が単語の境界に現れる最初の位置を返す（見つからない場合は -1）
+func indexWord(text, word string) int {
+	if word == "" {
+		return -1
+	}
+	for offset := 0; offset < len(text); {
+		i := strings.Index(text[offset:], word)
+		if i < 0 {
+			return -1
+		}
+		start := offset + i
+		end := start + len(word)
+		if isBoundary(text, start, word, true) && isBoundary(text, end, word, false) {
+			return start
+		}
+		_, size := utf8.DecodeRuneInString(text[start:])
+		offset = start + size
+	}
+	return -1
+}
+
+// isBoundary は用語の端（先頭または末尾）が単語の境界にあるかを返す
+// 用語の端の文字が英数字でない場合（日本語など）は境界を問わない
+func isBoundary(text string, pos int, word string, atStart bool) bool {
+	var edge rune
+	if atStart {
+		edge, _ = utf8.DecodeRuneInString(word)
+	} else {
+		edge, _ = utf8.DecodeLastRuneInString(word)
+	}
+	if !isWordChar(edge) {
+		return true
+	}
+
+	var neighbor rune
+	if atStart {
+		if pos == 0 {
+			return true
+		}
+		neighbor, _ = utf8.DecodeLastRuneInString(text[:pos])
+	} else {
+		if pos >= len(text) {
+			return true
+		}
+		neighbor, _ = utf8.DecodeRuneInString(text[pos:])
+	}
+	return !isWordChar(neighbor)
+}
+
+func isWordChar(r rune) bool {
+	return r == '_' || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9')
+}
@@ -0,0 +1,75 @@
+package glossary
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func termNames(terms []*Term) []string {
+	names := make([]string, 0, len(terms))
+	for _, term := range terms {
+		names = append(names, term.Term)
+	}
+	return names
+}
+
+func TestMatchTerms(t *testing.T) {
+	terms := []*Term{
+		{Term: "API"},
+		{Term: "SLO", Aliases: []string{"Service Level Objective"}},
+		{Term: "在庫引当"},
+		{Term: "Order"},
+		{Term: "Order Service"},
+	}
+
+	tests := []struct {
+		name  string
+		text  string
+		limit int
+		want  []string
+	}{
+		{
+			name:  "単語の境界でのみ一致する",
+			text:  "rapid な開発で api を使う",
+			limit: 10,
+			want:  []string{"API"},
+		},
+		{
+			name:  "別名で一致する",
+			text:  "service level objective の目標値は?",
+			limit: 10,
+			want:  []string{"SLO"},
+		},
+		{
+			name:  "日本語の用語は境界を問わない",
+			text:  "注文の在庫引当はいつ行われる?",
+			limit: 10,
+			want:  []string{"在庫引当"},
+		},
+		{
+			name:  "出現位置順で同じ位置では長い用語を優先する",
+			text:  "SLO と Order Service の関係",
+			limit: 10,
+			want:  []string{"SLO", "Order Service", "Order"},
+		},
+		{
+			name:  "上限件数で打ち切る",
+			text:  "SLO と Order Service の関係",
+			limit: 2,
+			want:  []string{"SLO", "Order Service"},
+		},
+		{
+			name:  "一致しない",
+			text:  "Orders の一覧",
+			limit: 10,
+			want:  []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, termNames(MatchTerms(terms, tt.text, tt.limit)))
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package glossary
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Kind は用語の種類
+type Kind string
+
+const (
+	KindTerm    Kind = "term"    // ドメイン用語
+	KindAcronym Kind = "acronym" // 略語
+	KindEntity  Kind = "entity"  // エンティティ（サービス・テーブル・主要な型など）
+)
+
+// Kinds は用語の種類の一覧（用語集ページの表示順）
+var Kinds = []Kind{KindTerm, KindAcronym, KindEntity}
+
+// Term は用語集の1項目
+type Term struct {
+	ID          uuid.UUID
+	ProductID   uuid.UUID
+	Term        string
+	Kind        Kind
+	Definition  string
+	Aliases     []string // 別名・略語の展開形
+	SourcePaths []string // 抽出元のファイルパス
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// Names は用語と別名を返す
+func (t *Term) Names() []string {
+	return append([]string{t.Term}, t.Aliases...)
+}
+
+// SourceText は用語抽出の入力となるテキスト（ドキュメントのチャンク、宣言のドキュメントコメント）
+type SourceText struct {
+	FilePath string
+	Text     string
+}
+
+// ExtractResult は用語抽出の結果
+type ExtractResult struct {
+	Sources      int // 入力としたテキスト数
+	Batches      int // LLM呼び出し回数
+	FailedBatch  int // 失敗したLLM呼び出し回数
+	Terms        int // 保存した用語数
+	DeletedTerms int // 見つからなくなったため削除した用語数
+}
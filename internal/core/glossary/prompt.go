@@ -0,0 +1,120 @@
+package glossary
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jinford/dev-rag/internal/core/prompt"
+)
+
+// maxTermLength は用語として受け付ける最大文字数（文章を用語として返した応答を除外する）
+const maxTermLength = 100
+
+// BuildExtractionPrompt はドキュメント・ドキュメントコメントから用語を抽出するプロンプトを構築する
+func BuildExtractionPrompt(texts []*SourceText) string {
+	return prompt.MustRenderBuiltin(prompt.NameGlossaryExtract, extractionPromptData(texts))
+}
+
+// extractionPromptData は用語抽出のプロンプトテンプレートに渡す値を構築する
+func extractionPromptData(texts []*SourceText) prompt.GlossaryData {
+	var sb strings.Builder
+	for i, text := range texts {
+		sb.WriteString(fmt.Sprintf("### [%d] %s\n", i+1, text.FilePath))
+		fence := codeFence(text.Text)
+		sb.WriteString(fence + "\n")
+		sb.WriteString(strings.TrimRight(text.Text, "\n"))
+		sb.WriteString("\n" + fence + "\n\n")
+	}
+	return prompt.GlossaryData{Inputs: sb.String()}
+}
+
+// extractedTerm はLLMが返す用語のJSON表現
+type extractedTerm struct {
+	Term       string   `json:"term"`
+	Kind       string   `json:"kind"`
+	Definition string   `json:"definition"`
+	Aliases    []string `json:"aliases"`
+	Sources    []string `json:"sources"`
+}
+
+// ParseExtractionResponse はLLMの応答から用語を取り出す
+// 用語・定義が空のもの、長すぎる用語は除外し、sources は入力に含まれるファイルパスのみを残す
+func ParseExtractionResponse(response string, texts []*SourceText) ([]*Term, error) {
+	start := strings.Index(response, "[")
+	end := strings.LastIndex(response, "]")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("no JSON array in response")
+	}
+
+	var extracted []extractedTerm
+	if err := json.Unmarshal([]byte(response[start:end+1]), &extracted); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	inputPaths := make(map[string]bool, len(texts))
+	for _, text := range texts {
+		inputPaths[text.FilePath] = true
+	}
+
+	terms := make([]*Term, 0, len(extracted))
+	for _, e := range extracted {
+		name := strings.Join(strings.Fields(e.Term), " ")
+		definition := strings.TrimSpace(e.Definition)
+		if name == "" || definition == "" || utf8.RuneCountInString(name) > maxTermLength {
+			continue
+		}
+
+		kind := Kind(strings.ToLower(strings.TrimSpace(e.Kind)))
+		if !slices.Contains(Kinds, kind) {
+			kind = KindTerm
+		}
+
+		term := &Term{Term: name, Kind: kind, Definition: definition}
+		for _, alias := range e.Aliases {
+			term.Aliases = appendUniqueName(term.Aliases, name, alias)
+		}
+		for _, source := range e.Sources {
+			if inputPaths[source] && !slices.Contains(term.SourcePaths, source) {
+				term.SourcePaths = append(term.SourcePaths, source)
+			}
+		}
+		terms = append(terms, term)
+	}
+	return terms, nil
+}
+
+// NormalizeTerm は用語の重複判定に使用する正規化した文字列を返す
+func NormalizeTerm(term string) string {
+	return strings.ToLower(strings.Join(strings.Fields(term), " "))
+}
+
+// appendUniqueName は用語自身や既存の別名と重複しない別名を追加する
+func appendUniqueName(aliases []string, term, alias string) []string {
+	alias = strings.Join(strings.Fields(alias), " ")
+	if alias == "" || utf8.RuneCountInString(alias) > maxTermLength || NormalizeTerm(alias) == NormalizeTerm(term) {
+		return aliases
+	}
+	for _, existing := range aliases {
+		if NormalizeTerm(existing) == NormalizeTerm(alias) {
+			return aliases
+		}
+	}
+	return append(aliases, alias)
+}
+
+// codeFence は内容に含まれるバッククォートの連続より長いコードフェンスを返す
+func codeFence(content string) string {
+	longest, run := 0, 0
+	for _, r := range content {
+		if r == '`' {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	return strings.Repeat("`", max(3, longest+1))
+}
@@ -0,0 +1,31 @@
+package glossary
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/jinford/dev-rag/internal/core/prompt"
+)
+
+// Repository は用語集のデータアクセスインターフェース
+type Repository interface {
+	// ListSourceTexts はプロダクトの各ソースの最新スナップショットから用語抽出の入力を重要度順に取得する
+	ListSourceTexts(ctx context.Context, productID uuid.UUID, maxChars, limit int) ([]*SourceText, error)
+	// ListTerms はプロダクトの用語集を用語順に取得する
+	ListTerms(ctx context.Context, productID uuid.UUID) ([]*Term, error)
+	// SaveTerm は用語を保存する（同じ用語が既にあれば更新する）
+	SaveTerm(ctx context.Context, term *Term) (*Term, error)
+	// DeleteTermsExcept は keep（正規化した用語）に含まれない用語を削除し、削除数を返す
+	DeleteTermsExcept(ctx context.Context, productID uuid.UUID, keep []string) (int, error)
+}
+
+// LLMClient はLLM呼び出しのインターフェース
+type LLMClient interface {
+	GenerateCompletion(ctx context.Context, prompt string) (string, error)
+}
+
+// PromptRenderer はプロンプトテンプレートを展開するインターフェース
+type PromptRenderer interface {
+	Render(ctx context.Context, name string, data any) (*prompt.Rendered, error)
+}
@@ -0,0 +1,217 @@
+package glossary
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"github.com/jinford/dev-rag/internal/core/prompt"
+	"github.com/jinford/dev-rag/internal/core/usage"
+)
+
+const (
+	// DefaultMaxSources は用語抽出の入力とするテキスト数の上限
+	DefaultMaxSources = 300
+	// DefaultSourceMaxChars は入力のテキスト1件あたりの最大文字数
+	DefaultSourceMaxChars = 1500
+	// DefaultBatchChars はLLM呼び出し1回あたりの入力の最大文字数
+	DefaultBatchChars = 12000
+	// DefaultMaxBatches はLLM呼び出し回数の上限
+	DefaultMaxBatches = 8
+)
+
+// GlossaryService は用語集の抽出と参照を提供する
+type GlossaryService struct {
+	repo       Repository
+	llm        LLMClient
+	maxBatches int
+	prompts    PromptRenderer
+	logger     *slog.Logger
+}
+
+// GlossaryServiceOption は GlossaryService のオプション設定
+type GlossaryServiceOption func(*GlossaryService)
+
+// WithGlossaryLogger は GlossaryService にロガーを設定する
+func WithGlossaryLogger(logger *slog.Logger) GlossaryServiceOption {
+	return func(s *GlossaryService) {
+		s.logger = logger
+	}
+}
+
+// WithGlossaryMaxBatches は1回の抽出でのLLM呼び出し回数の上限を設定する（0 以下の場合はデフォルト値）
+func WithGlossaryMaxBatches(n int) GlossaryServiceOption {
+	return func(s *GlossaryService) {
+		if n > 0 {
+			s.maxBatches = n
+		}
+	}
+}
+
+// WithGlossaryPrompts は用語抽出のプロンプトを展開するテンプレートを設定する（既定は組み込みのテンプレート）
+func WithGlossaryPrompts(renderer PromptRenderer) GlossaryServiceOption {
+	return func(s *GlossaryService) {
+		s.prompts = renderer
+	}
+}
+
+// NewGlossaryService は新しい GlossaryService を作成する
+func NewGlossaryService(repo Repository, llm LLMClient, opts ...GlossaryServiceOption) *GlossaryService {
+	svc := &GlossaryService{
+		repo:       repo,
+		llm:        llm,
+		maxBatches: DefaultMaxBatches,
+		prompts:    prompt.Default(),
+		logger:     slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(svc)
+	}
+	if svc.logger == nil {
+		svc.logger = slog.Default()
+	}
+	if svc.prompts == nil {
+		svc.prompts = prompt.Default()
+	}
+	return svc
+}
+
+// Extract はプロダクトのインデックス済みのドキュメント・コードから用語を抽出して用語集を更新する
+// 入力を重要度順に分割してLLMで抽出し、同じ用語は先に抽出した定義を採用して別名・抽出元をまとめる
+// すべての呼び出しが成功した場合のみ、今回見つからなかった用語を削除する
+func (s *GlossaryService) Extract(ctx context.Context, productID uuid.UUID) (*ExtractResult, error) {
+	texts, err := s.repo.ListSourceTexts(ctx, productID, DefaultSourceMaxChars, DefaultMaxSources)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list source texts: %w", err)
+	}
+
+	result := &ExtractResult{Sources: len(texts)}
+	if len(texts) == 0 {
+		s.logger.Info("no documents for glossary extraction", "productID", productID)
+		return result, nil
+	}
+
+	merged := make(map[string]*Term)
+	var order []string
+	complete := true
+	for _, batch := range splitBatches(texts, DefaultBatchChars, s.maxBatches) {
+		if tracker := usage.FromContext(ctx); tracker != nil && tracker.Exceeded() {
+			s.logger.Warn("budget exceeded, skipping remaining glossary extraction", "productID", productID)
+			complete = false
+			break
+		}
+
+		result.Batches++
+		rendered, err := s.prompts.Render(ctx, prompt.NameGlossaryExtract, extractionPromptData(batch))
+		if err != nil {
+			return nil, fmt.Errorf("failed to build prompt: %w", err)
+		}
+		response, err := s.llm.GenerateCompletion(ctx, rendered.Text)
+		if err != nil {
+			s.logger.Warn("failed to extract glossary terms", "productID", productID, "error", err)
+			result.FailedBatch++
+			complete = false
+			continue
+		}
+		terms, err := ParseExtractionResponse(response, batch)
+		if err != nil {
+			s.logger.Warn("failed to parse glossary terms", "productID", productID, "error", err)
+			result.FailedBatch++
+			complete = false
+			continue
+		}
+
+		for _, term := range terms {
+			key := NormalizeTerm(term.Term)
+			existing, ok := merged[key]
+			if !ok {
+				merged[key] = term
+				order = append(order, key)
+				continue
+			}
+			for _, alias := range term.Aliases {
+				existing.Aliases = appendUniqueName(existing.Aliases, existing.Term, alias)
+			}
+			for _, source := range term.SourcePaths {
+				if !slices.Contains(existing.SourcePaths, source) {
+					existing.SourcePaths = append(existing.SourcePaths, source)
+				}
+			}
+		}
+	}
+	if result.Batches > 0 && result.FailedBatch == result.Batches {
+		return result, fmt.Errorf("all glossary extraction batches failed")
+	}
+
+	for _, key := range order {
+		term := merged[key]
+		term.ProductID = productID
+		if _, err := s.repo.SaveTerm(ctx, term); err != nil {
+			return result, fmt.Errorf("failed to save glossary term %q: %w", term.Term, err)
+		}
+		result.Terms++
+	}
+
+	if complete {
+		deleted, err := s.repo.DeleteTermsExcept(ctx, productID, order)
+		if err != nil {
+			return result, fmt.Errorf("failed to delete stale glossary terms: %w", err)
+		}
+		result.DeletedTerms = deleted
+	}
+
+	s.logger.Info("glossary extracted",
+		"productID", productID,
+		"sources", result.Sources,
+		"batches", result.Batches,
+		"failedBatches", result.FailedBatch,
+		"terms", result.Terms,
+		"deleted", result.DeletedTerms,
+	)
+	return result, nil
+}
+
+// ListTerms はプロダクトの用語集を用語順に返す
+func (s *GlossaryService) ListTerms(ctx context.Context, productID uuid.UUID) ([]*Term, error) {
+	terms, err := s.repo.ListTerms(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list glossary terms: %w", err)
+	}
+	return terms, nil
+}
+
+// RelevantTerms はテキスト（質問文など）に現れる用語を最大 limit 件返す
+func (s *GlossaryService) RelevantTerms(ctx context.Context, productID uuid.UUID, text string, limit int) ([]*Term, error) {
+	terms, err := s.ListTerms(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	return MatchTerms(terms, text, limit), nil
+}
+
+// splitBatches は入力を1回のLLM呼び出しの文字数に収まるように分割する（最大 maxBatches 個）
+func splitBatches(texts []*SourceText, batchChars, maxBatches int) [][]*SourceText {
+	var batches [][]*SourceText
+	var current []*SourceText
+	size := 0
+	for _, text := range texts {
+		n := utf8.RuneCountInString(text.Text)
+		if len(current) > 0 && size+n > batchChars {
+			batches = append(batches, current)
+			if len(batches) == maxBatches {
+				return batches
+			}
+			current, size = nil, 0
+		}
+		current = append(current, text)
+		size += n
+	}
+	if len(current) > 0 {
+		batches = append(batches, current)
+	}
+	return batches
+}
@@ -0,0 +1,144 @@
+package glossary
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubGlossaryRepo struct {
+	texts   []*SourceText
+	saved   []*Term
+	kept    []string
+	deleted bool
+}
+
+func (r *stubGlossaryRepo) ListSourceTexts(ctx context.Context, productID uuid.UUID, maxChars, limit int) ([]*SourceText, error) {
+	return r.texts, nil
+}
+
+func (r *stubGlossaryRepo) ListTerms(ctx context.Context, productID uuid.UUID) ([]*Term, error) {
+	return r.saved, nil
+}
+
+func (r *stubGlossaryRepo) SaveTerm(ctx context.Context, term *Term) (*Term, error) {
+	r.saved = append(r.saved, term)
+	return term, nil
+}
+
+func (r *stubGlossaryRepo) DeleteTermsExcept(ctx context.Context, productID uuid.UUID, keep []string) (int, error) {
+	r.deleted = true
+	r.kept = keep
+	return 3, nil
+}
+
+// stubGlossaryLLM は呼び出し順に responses を返す（空文字の場合はエラーを返す）
+type stubGlossaryLLM struct {
+	responses []string
+	calls     int
+}
+
+func (l *stubGlossaryLLM) GenerateCompletion(ctx context.Context, prompt string) (string, error) {
+	response := l.responses[l.calls]
+	l.calls++
+	if response == "" {
+		return "", errors.New("llm error")
+	}
+	return response, nil
+}
+
+func newTestGlossaryService(repo Repository, llm LLMClient) *GlossaryService {
+	return NewGlossaryService(repo, llm, WithGlossaryLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+}
+
+// twoBatchTexts は2回のLLM呼び出しに分割される入力を返す
+func twoBatchTexts() []*SourceText {
+	return []*SourceText{
+		{FilePath: "docs/a.md", Text: strings.Repeat("a", DefaultBatchChars)},
+		{FilePath: "docs/b.md", Text: "b"},
+	}
+}
+
+func TestGlossaryService_Extract(t *testing.T) {
+	repo := &stubGlossaryRepo{texts: twoBatchTexts()}
+	llm := &stubGlossaryLLM{responses: []string{
+		`[{"term": "SLO", "kind": "acronym", "definition": "最初の定義。", "sources": ["docs/a.md"]}]`,
+		`[{"term": "slo", "kind": "acronym", "definition": "後の定義。", "aliases": ["Service Level Objective"], "sources": ["docs/b.md"]},
+		  {"term": "在庫引当", "kind": "term", "definition": "在庫を確保すること。"}]`,
+	}}
+
+	productID := uuid.New()
+	result, err := newTestGlossaryService(repo, llm).Extract(context.Background(), productID)
+	require.NoError(t, err)
+
+	assert.Equal(t, &ExtractResult{Sources: 2, Batches: 2, Terms: 2, DeletedTerms: 3}, result)
+	require.Len(t, repo.saved, 2)
+	assert.Equal(t, "SLO", repo.saved[0].Term)
+	assert.Equal(t, "最初の定義。", repo.saved[0].Definition)
+	assert.Equal(t, []string{"Service Level Objective"}, repo.saved[0].Aliases)
+	assert.Equal(t, []string{"docs/a.md", "docs/b.md"}, repo.saved[0].SourcePaths)
+	assert.Equal(t, productID, repo.saved[0].ProductID)
+	assert.Equal(t, []string{"slo", "在庫引当"}, repo.kept)
+}
+
+func TestGlossaryService_Extract_PartialFailure(t *testing.T) {
+	repo := &stubGlossaryRepo{texts: twoBatchTexts()}
+	llm := &stubGlossaryLLM{responses: []string{
+		"",
+		`[{"term": "SLO", "kind": "acronym", "definition": "サービスの信頼性目標。"}]`,
+	}}
+
+	result, err := newTestGlossaryService(repo, llm).Extract(context.Background(), uuid.New())
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, result.FailedBatch)
+	assert.Equal(t, 1, result.Terms)
+	assert.False(t, repo.deleted, "一部の抽出に失敗した場合は既存の用語を削除しない")
+}
+
+func TestGlossaryService_Extract_AllFailed(t *testing.T) {
+	repo := &stubGlossaryRepo{texts: twoBatchTexts()}
+	llm := &stubGlossaryLLM{responses: []string{"", "用語はありません"}}
+
+	_, err := newTestGlossaryService(repo, llm).Extract(context.Background(), uuid.New())
+	require.Error(t, err)
+	assert.Empty(t, repo.saved)
+	assert.False(t, repo.deleted)
+}
+
+func TestGlossaryService_Extract_NoSources(t *testing.T) {
+	repo := &stubGlossaryRepo{}
+	llm := &stubGlossaryLLM{}
+
+	result, err := newTestGlossaryService(repo, llm).Extract(context.Background(), uuid.New())
+	require.NoError(t, err)
+	assert.Equal(t, &ExtractResult{}, result)
+	assert.Zero(t, llm.calls)
+	assert.False(t, repo.deleted)
+}
+
+func TestSplitBatches(t *testing.T) {
+	texts := []*SourceText{
+		{Text: "あいう"},
+		{Text: "えお"},
+		{Text: "かきくけこ"},
+		{Text: "さ"},
+		{Text: "しすせそたち"},
+	}
+
+	batches := splitBatches(texts, 5, 10)
+	require.Len(t, batches, 4)
+	assert.Equal(t, []*SourceText{texts[0], texts[1]}, batches[0])
+	assert.Equal(t, []*SourceText{texts[2]}, batches[1])
+	assert.Equal(t, []*SourceText{texts[3]}, batches[2])
+	assert.Equal(t, []*SourceText{texts[4]}, batches[3], "上限を超えるテキストも単独のバッチにする")
+
+	assert.Len(t, splitBatches(texts, 5, 2), 2)
+}
@@ -0,0 +1,201 @@
+package graph
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Write はグラフを指定した形式で出力する
+func Write(w io.Writer, g *Graph, format Format) error {
+	switch format {
+	case FormatDOT:
+		return WriteDOT(w, g)
+	case FormatJSON:
+		return WriteJSON(w, g)
+	case FormatGraphML:
+		return WriteGraphML(w, g)
+	default:
+		return fmt.Errorf("unsupported graph format: %s", format)
+	}
+}
+
+// WriteDOT はグラフを Graphviz の DOT 形式で出力する
+// ノードはファイルごとの cluster にまとめ、エッジのラベルに依存関係の種類を表示する
+func WriteDOT(w io.Writer, g *Graph) error {
+	multiSource := hasMultipleSources(g.Nodes)
+
+	var sb strings.Builder
+	sb.WriteString("digraph dependencies {\n")
+	sb.WriteString("  rankdir=LR;\n")
+	sb.WriteString("  node [shape=box, fontsize=10];\n")
+	sb.WriteString("  edge [fontsize=8];\n")
+
+	var files []string
+	byFile := make(map[string][]*Node)
+	for _, n := range g.Nodes {
+		file := filePath(n, multiSource)
+		if _, ok := byFile[file]; !ok {
+			files = append(files, file)
+		}
+		byFile[file] = append(byFile[file], n)
+	}
+	for i, file := range files {
+		sb.WriteString(fmt.Sprintf("  subgraph cluster_%d {\n", i))
+		sb.WriteString(fmt.Sprintf("    label=%s;\n", dotQuote(file)))
+		for _, n := range byFile[file] {
+			label := fmt.Sprintf("%s\n%d-%d", n.Label(), n.StartLine, n.EndLine)
+			if n.Importance != nil {
+				label += fmt.Sprintf("\nimportance: %.2f", *n.Importance)
+			}
+			sb.WriteString(fmt.Sprintf("    %s [label=%s];\n", dotQuote(n.ID.String()), dotQuote(label)))
+		}
+		sb.WriteString("  }\n")
+	}
+
+	for _, e := range g.Edges {
+		sb.WriteString(fmt.Sprintf("  %s -> %s [label=%s];\n", dotQuote(e.From.String()), dotQuote(e.To.String()), dotQuote(e.DepType)))
+	}
+	sb.WriteString("}\n")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// dotQuote は DOT の文字列リテラルに変換する
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
+
+// WriteJSON はグラフをノードとエッジの一覧の JSON で出力する
+func WriteJSON(w io.Writer, g *Graph) error {
+	out := Graph{Nodes: g.Nodes, Edges: g.Edges}
+	if out.Nodes == nil {
+		out.Nodes = []*Node{}
+	}
+	if out.Edges == nil {
+		out.Edges = []*Edge{}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(out)
+}
+
+type graphML struct {
+	XMLName xml.Name     `xml:"graphml"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   struct {
+		EdgeDefault string        `xml:"edgedefault,attr"`
+		Nodes       []graphMLNode `xml:"node"`
+		Edges       []graphMLEdge `xml:"edge"`
+	} `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID       string `xml:"id,attr"`
+	For      string `xml:"for,attr"`
+	AttrName string `xml:"attr.name,attr"`
+	AttrType string `xml:"attr.type,attr"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// WriteGraphML はグラフを GraphML 形式で出力する
+// ノードは表示名・ソース・パス・行範囲・種類・重要度、エッジは依存関係の種類とシンボルを属性に持つ
+func WriteGraphML(w io.Writer, g *Graph) error {
+	doc := graphML{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []graphMLKey{
+			{ID: "label", For: "node", AttrName: "label", AttrType: "string"},
+			{ID: "source", For: "node", AttrName: "source", AttrType: "string"},
+			{ID: "path", For: "node", AttrName: "path", AttrType: "string"},
+			{ID: "lines", For: "node", AttrName: "lines", AttrType: "string"},
+			{ID: "type", For: "node", AttrName: "type", AttrType: "string"},
+			{ID: "importance", For: "node", AttrName: "importance", AttrType: "double"},
+			{ID: "dep_type", For: "edge", AttrName: "dep_type", AttrType: "string"},
+			{ID: "symbol", For: "edge", AttrName: "symbol", AttrType: "string"},
+		},
+	}
+	doc.Graph.EdgeDefault = "directed"
+
+	for _, n := range g.Nodes {
+		node := graphMLNode{
+			ID: n.ID.String(),
+			Data: []graphMLData{
+				{Key: "label", Value: n.Label()},
+				{Key: "source", Value: n.SourceName},
+				{Key: "path", Value: n.FilePath},
+				{Key: "lines", Value: fmt.Sprintf("%d-%d", n.StartLine, n.EndLine)},
+			},
+		}
+		if n.Type != "" {
+			node.Data = append(node.Data, graphMLData{Key: "type", Value: n.Type})
+		}
+		if n.Importance != nil {
+			node.Data = append(node.Data, graphMLData{Key: "importance", Value: strconv.FormatFloat(*n.Importance, 'f', -1, 64)})
+		}
+		doc.Graph.Nodes = append(doc.Graph.Nodes, node)
+	}
+	for _, e := range g.Edges {
+		edge := graphMLEdge{
+			Source: e.From.String(),
+			Target: e.To.String(),
+			Data:   []graphMLData{{Key: "dep_type", Value: e.DepType}},
+		}
+		if e.Symbol != "" {
+			edge.Data = append(edge.Data, graphMLData{Key: "symbol", Value: e.Symbol})
+		}
+		doc.Graph.Edges = append(doc.Graph.Edges, edge)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("failed to encode graphml: %w", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// hasMultipleSources はグラフのノードが複数のソースにまたがるかを判定する
+func hasMultipleSources(nodes []*Node) bool {
+	for _, n := range nodes {
+		if n.SourceName != nodes[0].SourceName {
+			return true
+		}
+	}
+	return false
+}
+
+// filePath はノードのファイルパスを返す（複数のソースにまたがる場合は <ソース名>/<パス>）
+func filePath(n *Node, multiSource bool) string {
+	if multiSource {
+		return n.SourceName + "/" + n.FilePath
+	}
+	return n.FilePath
+}
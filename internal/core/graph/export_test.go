@@ -0,0 +1,79 @@
+package graph
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testGraph() *Graph {
+	importance := 0.75
+	handler := &Node{ID: uuid.MustParse("00000000-0000-0000-0000-000000000001"), SourceName: "api", FilePath: "internal/handler.go", Name: "Server.Handle", Type: "method", StartLine: 10, EndLine: 40, Importance: &importance}
+	store := &Node{ID: uuid.MustParse("00000000-0000-0000-0000-000000000002"), SourceName: "api", FilePath: "internal/store.go", Name: "Save", Type: "function", StartLine: 5, EndLine: 20}
+	return &Graph{
+		Nodes: []*Node{handler, store},
+		Edges: []*Edge{{From: handler.ID, To: store.ID, DepType: "call", Symbol: "Save"}},
+	}
+}
+
+func TestWriteDOT(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, testGraph(), FormatDOT))
+
+	out := buf.String()
+	assert.Contains(t, out, "digraph dependencies {\n")
+	assert.Contains(t, out, "  subgraph cluster_0 {\n    label=\"internal/handler.go\";\n")
+	assert.Contains(t, out, `"00000000-0000-0000-0000-000000000001" [label="Server.Handle\n10-40\nimportance: 0.75"];`)
+	assert.Contains(t, out, `"00000000-0000-0000-0000-000000000001" -> "00000000-0000-0000-0000-000000000002" [label="call"];`)
+}
+
+func TestWriteDOT_MultipleSources(t *testing.T) {
+	g := testGraph()
+	g.Nodes[1].SourceName = "lib"
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteDOT(&buf, g))
+	assert.Contains(t, buf.String(), `label="lib/internal/store.go";`)
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, testGraph(), FormatJSON))
+
+	var decoded Graph
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	require.Len(t, decoded.Nodes, 2)
+	require.Len(t, decoded.Edges, 1)
+	assert.Equal(t, "Server.Handle", decoded.Nodes[0].Name)
+	assert.Nil(t, decoded.Nodes[1].Importance)
+	assert.Equal(t, "call", decoded.Edges[0].DepType)
+
+	// 空のグラフも nodes・edges を配列で出力する
+	buf.Reset()
+	require.NoError(t, WriteJSON(&buf, &Graph{}))
+	assert.JSONEq(t, `{"nodes": [], "edges": []}`, buf.String())
+}
+
+func TestWriteGraphML(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, testGraph(), FormatGraphML))
+
+	out := buf.String()
+	assert.Contains(t, out, `<graph edgedefault="directed">`)
+	assert.Contains(t, out, `<data key="importance">0.75</data>`)
+	assert.Contains(t, out, `<edge source="00000000-0000-0000-0000-000000000001" target="00000000-0000-0000-0000-000000000002">`)
+
+	var decoded graphML
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &decoded))
+	assert.Len(t, decoded.Graph.Nodes, 2)
+	assert.Len(t, decoded.Graph.Edges, 1)
+}
+
+func TestWrite_UnsupportedFormat(t *testing.T) {
+	assert.Error(t, Write(&bytes.Buffer{}, testGraph(), Format("svg")))
+}
@@ -0,0 +1,66 @@
+// Package graph はチャンク間の依存関係（chunk_dependencies）のグラフを可視化ツール向けの形式（DOT・JSON・GraphML）で出力する
+package graph
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// Format は出力形式
+type Format string
+
+const (
+	FormatDOT     Format = "dot"     // Graphviz
+	FormatJSON    Format = "json"    // ノードとエッジの一覧
+	FormatGraphML Format = "graphml" // Gephi・yEd など
+)
+
+// Formats は出力形式の一覧
+var Formats = []Format{FormatDOT, FormatJSON, FormatGraphML}
+
+// Node は依存関係グラフのノード（チャンク）
+type Node struct {
+	ID         uuid.UUID `json:"id"`
+	SourceName string    `json:"source"`
+	FilePath   string    `json:"path"`
+	Name       string    `json:"name,omitempty"` // メソッドはレシーバの型名で修飾する
+	Type       string    `json:"type,omitempty"`
+	StartLine  int       `json:"startLine"`
+	EndLine    int       `json:"endLine"`
+	Importance *float64  `json:"importance,omitempty"`
+}
+
+// Label はノードの表示名を返す（名前がないチャンクは <パス>:<開始行>-<終了行>）
+func (n *Node) Label() string {
+	if n.Name != "" {
+		return n.Name
+	}
+	return n.Location()
+}
+
+// Location はチャンクの位置（<パス>:<開始行>-<終了行>）を返す
+func (n *Node) Location() string {
+	return fmt.Sprintf("%s:%d-%d", n.FilePath, n.StartLine, n.EndLine)
+}
+
+// Edge は依存関係グラフのエッジ（依存元から依存先）
+type Edge struct {
+	From    uuid.UUID `json:"from"`
+	To      uuid.UUID `json:"to"`
+	DepType string    `json:"type"`
+	Symbol  string    `json:"symbol,omitempty"`
+}
+
+// Graph はチャンクの依存関係グラフ
+type Graph struct {
+	Nodes []*Node `json:"nodes"`
+	Edges []*Edge `json:"edges"`
+}
+
+// Filter はグラフに含めるチャンクの絞り込み条件
+// 依存元・依存先の両方が条件を満たすエッジのみを含める
+type Filter struct {
+	PathPrefix    string   // ファイルパスのプレフィックス
+	MinImportance *float64 // importance_score の下限（未算出のチャンクは除く）
+}
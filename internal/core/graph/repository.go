@@ -0,0 +1,15 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+)
+
+// Reader は依存関係グラフを読み取るインターフェース
+type Reader interface {
+	// ReadGraph は依存関係のあるチャンクとエッジを取得する
+	// product_id 指定時はプロダクトの各ソースの最新スナップショット、snapshot_id 指定時はそのスナップショットを対象とする
+	ReadGraph(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID], filter Filter) (*Graph, error)
+}
@@ -0,0 +1,89 @@
+package hotspot
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// Format は出力形式
+type Format string
+
+const (
+	FormatTable    Format = "table"    // 端末向けの表
+	FormatJSON     Format = "json"     // 順位付けの結果
+	FormatMarkdown Format = "markdown" // Markdown の表
+)
+
+// Formats は出力形式の一覧
+var Formats = []Format{FormatTable, FormatJSON, FormatMarkdown}
+
+// Write は順位付けの結果を指定の形式で出力する
+func Write(w io.Writer, report *Report, format Format) error {
+	switch format {
+	case FormatTable:
+		return writeTable(w, report)
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	case FormatMarkdown:
+		return writeMarkdown(w, report)
+	default:
+		return fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+func writeTable(w io.Writer, report *Report) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if report.Level == LevelFile {
+		fmt.Fprintln(tw, "RANK\tSCORE\tSOURCE\tFILE\tFUNCTIONS\tCOMPLEXITY\tLINES\tEDITS\tCOMMENT RATIO\tTEST COVERAGE")
+		for _, h := range report.Hotspots {
+			fmt.Fprintf(tw, "%d\t%.3f\t%s\t%s\t%d\t%d\t%d\t%d\t%.2f\t%s\n",
+				h.Rank, h.Score, h.SourceName, h.FilePath, h.Functions, h.Complexity, h.LinesOfCode, h.EditCount, h.CommentRatio, formatCoverage(h.TestCoverage))
+		}
+	} else {
+		fmt.Fprintln(tw, "RANK\tSCORE\tSOURCE\tNAME\tLOCATION\tCOMPLEXITY\tLINES\tEDITS\tCOMMENT RATIO\tTEST COVERAGE")
+		for _, h := range report.Hotspots {
+			fmt.Fprintf(tw, "%d\t%.3f\t%s\t%s\t%s:%d\t%d\t%d\t%d\t%.2f\t%s\n",
+				h.Rank, h.Score, h.SourceName, h.Name, h.FilePath, h.StartLine, h.Complexity, h.LinesOfCode, h.EditCount, h.CommentRatio, formatCoverage(h.TestCoverage))
+		}
+	}
+	return tw.Flush()
+}
+
+func writeMarkdown(w io.Writer, report *Report) error {
+	var sb strings.Builder
+	if report.Level == LevelFile {
+		sb.WriteString("| 順位 | スコア | ソース | ファイル | 関数数 | 複雑度 | 行数 | 編集回数 | コメント比率 | テストカバレッジ |\n")
+		sb.WriteString("|---|---|---|---|---|---|---|---|---|---|\n")
+		for _, h := range report.Hotspots {
+			sb.WriteString(fmt.Sprintf("| %d | %.3f | %s | `%s` | %d | %d | %d | %d | %.2f | %s |\n",
+				h.Rank, h.Score, escapeCell(h.SourceName), h.FilePath, h.Functions, h.Complexity, h.LinesOfCode, h.EditCount, h.CommentRatio, formatCoverage(h.TestCoverage)))
+		}
+	} else {
+		sb.WriteString("| 順位 | スコア | ソース | 名前 | ファイル | 複雑度 | 行数 | 編集回数 | コメント比率 | テストカバレッジ |\n")
+		sb.WriteString("|---|---|---|---|---|---|---|---|---|---|\n")
+		for _, h := range report.Hotspots {
+			sb.WriteString(fmt.Sprintf("| %d | %.3f | %s | %s | `%s:%d` | %d | %d | %d | %.2f | %s |\n",
+				h.Rank, h.Score, escapeCell(h.SourceName), escapeCell(h.Name), h.FilePath, h.StartLine, h.Complexity, h.LinesOfCode, h.EditCount, h.CommentRatio, formatCoverage(h.TestCoverage)))
+		}
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// formatCoverage はテストカバレッジを表示用に整形する（カバレッジ未取り込みの場合は "-"）
+func formatCoverage(coverage *float64) string {
+	if coverage == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", *coverage)
+}
+
+// escapeCell は Markdown の表のセルで区切り文字として解釈される文字をエスケープする
+func escapeCell(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "|", `\|`), "\n", " ")
+}
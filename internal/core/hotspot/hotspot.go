@@ -0,0 +1,209 @@
+// Package hotspot は循環的複雑度・行数・編集頻度・コメント比率を組み合わせて、変更のリスクが高い関数・ファイル（ホットスポット）を順位付けする
+package hotspot
+
+import (
+	"sort"
+)
+
+// Level は順位付けの単位
+type Level string
+
+const (
+	LevelFunction Level = "function" // 関数・クラス単位
+	LevelFile     Level = "file"     // ファイル単位（関数・クラスの指標を集約）
+)
+
+// Levels は順位付けの単位の一覧
+var Levels = []Level{LevelFunction, LevelFile}
+
+// Weights はスコアの算出に使用する各指標の重み
+type Weights struct {
+	Complexity    float64 `json:"complexity"`    // 循環的複雑度
+	LinesOfCode   float64 `json:"linesOfCode"`   // コード行数
+	EditFrequency float64 `json:"editFrequency"` // 編集回数（スナップショット間で内容が変更された回数）
+	LowComment    float64 `json:"lowComment"`    // コメントの少なさ（1 - コメント比率）
+}
+
+// DefaultWeights は既定の重み（合計 1.0）
+var DefaultWeights = Weights{Complexity: 0.35, LinesOfCode: 0.2, EditFrequency: 0.3, LowComment: 0.15}
+
+// Candidate はホットスポットの候補となる関数・クラス単位のチャンクの指標
+type Candidate struct {
+	SourceName     string
+	FilePath       string
+	Type           string
+	Name           string // 親を含む名前（例: UserService.Get）
+	StartLine      int
+	EndLine        int
+	Complexity     *int    // 循環的複雑度（未算出の言語では nil）
+	LinesOfCode    *int    // コード行数（未算出の言語では nil）
+	CommentRatio   float64 // コメント比率（0〜1、行数が未算出の場合は意味を持たない）
+	EditCount      int
+	TestStatements *int // テストカバレッジの計測対象のステートメント数（カバレッジ未取り込みの場合は nil）
+	TestCovered    int  // テストで実行されたステートメント数
+}
+
+// Filter は候補の絞り込み条件
+type Filter struct {
+	PathPrefix   string // ファイルパスのプレフィックス
+	IncludeTests bool   // テストのファイル（ドメイン tests）を含める
+}
+
+// Query はホットスポットの順位付けの条件
+type Query struct {
+	Level  Level
+	Limit  int // 返す件数（0 以下は全件）
+	Filter Filter
+}
+
+// Hotspot は順位付けした関数・ファイル
+type Hotspot struct {
+	Rank         int      `json:"rank"`
+	SourceName   string   `json:"sourceName"`
+	FilePath     string   `json:"path"`
+	Name         string   `json:"name,omitempty"`         // 関数・クラス単位のみ
+	Type         string   `json:"type,omitempty"`         // 関数・クラス単位のみ
+	StartLine    int      `json:"startLine,omitempty"`    // 関数・クラス単位のみ
+	EndLine      int      `json:"endLine,omitempty"`      // 関数・クラス単位のみ
+	Functions    int      `json:"functions,omitempty"`    // ファイル単位のみ: 集約した関数・クラスの数
+	Complexity   int      `json:"complexity"`             // ファイル単位は合計
+	LinesOfCode  int      `json:"linesOfCode"`            // ファイル単位は合計
+	CommentRatio float64  `json:"commentRatio"`           // ファイル単位は行数による加重平均
+	EditCount    int      `json:"editCount"`              // ファイル単位は最大
+	TestCoverage *float64 `json:"testCoverage,omitempty"` // テストカバレッジ（%、カバレッジ未取り込みの場合は省略）
+	Score        float64  `json:"score"`                  // 0〜1
+}
+
+// Report はホットスポットの順位付けの結果
+type Report struct {
+	Level    Level      `json:"level"`
+	Weights  Weights    `json:"weights"`
+	Hotspots []*Hotspot `json:"hotspots"`
+}
+
+// Rank は候補の指標からスコアを算出し、スコアの高い順に並べる
+// 各指標は候補の中の最大値で 0〜1 に正規化して重み付きで合算する。コメントの少なさは行数を算出済みのものに限り加味する
+func Rank(candidates []*Candidate, level Level, weights Weights, limit int) *Report {
+	hotspots := make([]*Hotspot, 0, len(candidates))
+	tested := make(testCoverage)
+	if level == LevelFile {
+		hotspots = aggregateFiles(candidates, tested)
+	} else {
+		for _, c := range candidates {
+			h := &Hotspot{
+				SourceName:   c.SourceName,
+				FilePath:     c.FilePath,
+				Name:         c.Name,
+				Type:         c.Type,
+				StartLine:    c.StartLine,
+				EndLine:      c.EndLine,
+				Complexity:   derefInt(c.Complexity),
+				LinesOfCode:  derefInt(c.LinesOfCode),
+				CommentRatio: c.CommentRatio,
+				EditCount:    c.EditCount,
+			}
+			tested.add(h, c)
+			hotspots = append(hotspots, h)
+		}
+	}
+	for h, counts := range tested {
+		if counts[0] > 0 {
+			percent := float64(counts[1]) / float64(counts[0]) * 100
+			h.TestCoverage = &percent
+		}
+	}
+
+	var maxComplexity, maxLines, maxEdits int
+	for _, h := range hotspots {
+		maxComplexity = max(maxComplexity, h.Complexity)
+		maxLines = max(maxLines, h.LinesOfCode)
+		maxEdits = max(maxEdits, h.EditCount)
+	}
+	for _, h := range hotspots {
+		h.Score = weights.Complexity*ratio(h.Complexity, maxComplexity) +
+			weights.LinesOfCode*ratio(h.LinesOfCode, maxLines) +
+			weights.EditFrequency*ratio(h.EditCount, maxEdits)
+		if h.LinesOfCode > 0 {
+			h.Score += weights.LowComment * (1 - h.CommentRatio)
+		}
+	}
+
+	sort.SliceStable(hotspots, func(i, j int) bool {
+		if hotspots[i].Score != hotspots[j].Score {
+			return hotspots[i].Score > hotspots[j].Score
+		}
+		if hotspots[i].FilePath != hotspots[j].FilePath {
+			return hotspots[i].FilePath < hotspots[j].FilePath
+		}
+		return hotspots[i].StartLine < hotspots[j].StartLine
+	})
+	if limit > 0 && len(hotspots) > limit {
+		hotspots = hotspots[:limit]
+	}
+	for i, h := range hotspots {
+		h.Rank = i + 1
+	}
+
+	return &Report{Level: level, Weights: weights, Hotspots: hotspots}
+}
+
+// aggregateFiles は関数・クラスの指標をファイルごとに集約する
+func aggregateFiles(candidates []*Candidate, tested testCoverage) []*Hotspot {
+	type fileKey struct{ source, path string }
+	files := make(map[fileKey]*Hotspot)
+	commented := make(map[fileKey]float64) // コメント比率 × 行数 の合計
+	var hotspots []*Hotspot
+	for _, c := range candidates {
+		key := fileKey{c.SourceName, c.FilePath}
+		h, ok := files[key]
+		if !ok {
+			h = &Hotspot{SourceName: c.SourceName, FilePath: c.FilePath}
+			files[key] = h
+			hotspots = append(hotspots, h)
+		}
+		lines := derefInt(c.LinesOfCode)
+		h.Functions++
+		h.Complexity += derefInt(c.Complexity)
+		h.LinesOfCode += lines
+		h.EditCount = max(h.EditCount, c.EditCount)
+		commented[key] += c.CommentRatio * float64(lines)
+		tested.add(h, c)
+	}
+	for key, h := range files {
+		if h.LinesOfCode > 0 {
+			h.CommentRatio = commented[key] / float64(h.LinesOfCode)
+		}
+	}
+	return hotspots
+}
+
+// testCoverage は順位付けの対象ごとのテストカバレッジの計測結果（計測対象のステートメント数・実行されたステートメント数）
+type testCoverage map[*Hotspot]*[2]int
+
+// add は候補のテストカバレッジの計測結果を加算する（カバレッジ未取り込みの候補は加算しない）
+func (t testCoverage) add(h *Hotspot, c *Candidate) {
+	if c.TestStatements == nil {
+		return
+	}
+	counts, ok := t[h]
+	if !ok {
+		counts = &[2]int{}
+		t[h] = counts
+	}
+	counts[0] += *c.TestStatements
+	counts[1] += c.TestCovered
+}
+
+func ratio(value, maxValue int) float64 {
+	if maxValue <= 0 {
+		return 0
+	}
+	return float64(value) / float64(maxValue)
+}
+
+func derefInt(v *int) int {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
@@ -0,0 +1,88 @@
+package hotspot
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func testCandidates() []*Candidate {
+	return []*Candidate{
+		{SourceName: "backend", FilePath: "service/user.go", Type: "method", Name: "UserService.Update", StartLine: 10, EndLine: 80,
+			Complexity: intPtr(20), LinesOfCode: intPtr(60), CommentRatio: 0.05, EditCount: 8, TestStatements: intPtr(30), TestCovered: 6},
+		{SourceName: "backend", FilePath: "service/user.go", Type: "method", Name: "UserService.Get", StartLine: 90, EndLine: 100,
+			Complexity: intPtr(2), LinesOfCode: intPtr(10), CommentRatio: 0.5, EditCount: 1, TestStatements: intPtr(10), TestCovered: 10},
+		{SourceName: "backend", FilePath: "util/strings.go", Type: "function", Name: "Pad", StartLine: 5, EndLine: 40,
+			Complexity: intPtr(10), LinesOfCode: intPtr(30), CommentRatio: 0.2, EditCount: 0},
+		{SourceName: "frontend", FilePath: "src/app.ts", Type: "function", Name: "render", StartLine: 1, EndLine: 3},
+	}
+}
+
+func TestRank_Function(t *testing.T) {
+	report := Rank(testCandidates(), LevelFunction, DefaultWeights, 0)
+
+	require.Len(t, report.Hotspots, 4)
+	top := report.Hotspots[0]
+	assert.Equal(t, 1, top.Rank)
+	assert.Equal(t, "UserService.Update", top.Name)
+	// 全指標が最大のため、コメントの少なさ以外の重みがそのまま加算される
+	assert.InDelta(t, 0.35+0.2+0.3+0.15*0.95, top.Score, 1e-9)
+	require.NotNil(t, top.TestCoverage)
+	assert.InDelta(t, 20.0, *top.TestCoverage, 1e-9)
+	assert.Equal(t, "Pad", report.Hotspots[1].Name)
+	assert.Nil(t, report.Hotspots[1].TestCoverage, "カバレッジ未取り込みの場合は nil")
+
+	last := report.Hotspots[3]
+	assert.Equal(t, "render", last.Name)
+	assert.Zero(t, last.Score, "行数が未算出の場合はコメントの少なさを加味しない")
+
+	limited := Rank(testCandidates(), LevelFunction, DefaultWeights, 2)
+	require.Len(t, limited.Hotspots, 2)
+	assert.Equal(t, 2, limited.Hotspots[1].Rank)
+}
+
+func TestRank_File(t *testing.T) {
+	report := Rank(testCandidates(), LevelFile, DefaultWeights, 0)
+
+	require.Len(t, report.Hotspots, 3)
+	top := report.Hotspots[0]
+	assert.Equal(t, "service/user.go", top.FilePath)
+	assert.Empty(t, top.Name)
+	assert.Equal(t, 2, top.Functions)
+	assert.Equal(t, 22, top.Complexity)
+	assert.Equal(t, 70, top.LinesOfCode)
+	assert.Equal(t, 8, top.EditCount, "編集回数はファイル内の最大とする")
+	assert.InDelta(t, (0.05*60+0.5*10)/70, top.CommentRatio, 1e-9)
+	require.NotNil(t, top.TestCoverage)
+	assert.InDelta(t, 40.0, *top.TestCoverage, 1e-9, "ステートメント数による加重平均")
+}
+
+func TestWrite(t *testing.T) {
+	report := Rank(testCandidates(), LevelFunction, DefaultWeights, 2)
+
+	var table bytes.Buffer
+	require.NoError(t, Write(&table, report, FormatTable))
+	assert.Contains(t, table.String(), "RANK")
+	assert.Contains(t, table.String(), "service/user.go:10")
+	assert.Contains(t, table.String(), "20.0%")
+
+	var markdown bytes.Buffer
+	require.NoError(t, Write(&markdown, report, FormatMarkdown))
+	assert.Contains(t, markdown.String(), "| 1 | 0.993 | backend | UserService.Update | `service/user.go:10` | 20 | 60 | 8 | 0.05 | 20.0% |")
+
+	var out bytes.Buffer
+	require.NoError(t, Write(&out, report, FormatJSON))
+	var decoded Report
+	require.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+	assert.Equal(t, LevelFunction, decoded.Level)
+	assert.Len(t, decoded.Hotspots, 2)
+
+	assert.Error(t, Write(&bytes.Buffer{}, report, Format("csv")))
+}
@@ -0,0 +1,58 @@
+package hotspot
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+)
+
+// Reader はホットスポットの候補を読み取るインターフェース
+type Reader interface {
+	// ListCandidates は関数・クラス単位のチャンクの指標を取得する
+	// product_id 指定時はプロダクトの各ソースの最新スナップショット、snapshot_id 指定時はそのスナップショットを対象とする
+	ListCandidates(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID], filter Filter) ([]*Candidate, error)
+}
+
+// Service はホットスポットを順位付けする
+type Service struct {
+	reader  Reader
+	weights Weights
+}
+
+// ServiceOption は Service のオプション設定
+type ServiceOption func(*Service)
+
+// WithWeights はスコアの算出に使用する重みを設定する（既定は DefaultWeights）
+func WithWeights(weights Weights) ServiceOption {
+	return func(s *Service) {
+		s.weights = weights
+	}
+}
+
+// NewService は新しい Service を作成する
+func NewService(reader Reader, opts ...ServiceOption) *Service {
+	svc := &Service{
+		reader:  reader,
+		weights: DefaultWeights,
+	}
+	for _, opt := range opts {
+		opt(svc)
+	}
+	return svc
+}
+
+// Report は対象のスナップショットの関数・ファイルをホットスポットとして順位付けする
+func (s *Service) Report(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID], query Query) (*Report, error) {
+	candidates, err := s.reader.ListCandidates(ctx, productID, snapshotID, query.Filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list hotspot candidates: %w", err)
+	}
+
+	level := query.Level
+	if level == "" {
+		level = LevelFunction
+	}
+	return Rank(candidates, level, s.weights, query.Limit), nil
+}
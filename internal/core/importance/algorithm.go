@@ -0,0 +1,222 @@
+// Package importance はチャンク間の依存関係グラフと編集頻度からチャンクの重要度（importance_score）を算出する
+package importance
+
+import (
+	"math"
+
+	"github.com/google/uuid"
+)
+
+// Algorithm は依存関係グラフから重要度（グラフスコア）を求めるアルゴリズム
+type Algorithm string
+
+const (
+	AlgorithmPageRank    Algorithm = "pagerank"    // 重要なチャンクから依存されるチャンクを重視する
+	AlgorithmBetweenness Algorithm = "betweenness" // 多くの依存の経路の中継点になるチャンクを重視する
+	AlgorithmInDegree    Algorithm = "in_degree"   // 依存の種類で重み付けした被依存数
+)
+
+// Algorithms はアルゴリズムの一覧
+var Algorithms = []Algorithm{AlgorithmPageRank, AlgorithmBetweenness, AlgorithmInDegree}
+
+// DefaultDepTypeWeights は依存の種類ごとのエッジの重み
+var DefaultDepTypeWeights = map[string]float64{
+	"call":   1.0,
+	"type":   0.7,
+	"import": 0.3,
+}
+
+const (
+	pageRankDamping       = 0.85
+	pageRankMaxIterations = 100
+	pageRankTolerance     = 1e-9
+)
+
+// Edge は依存関係グラフのエッジ（依存元から依存先）
+type Edge struct {
+	From    uuid.UUID
+	To      uuid.UUID
+	DepType string
+}
+
+// graphScores はアルゴリズムでノードごとのグラフスコアを求め、最大値が 1 になるよう正規化する
+func graphScores(algorithm Algorithm, nodes []uuid.UUID, edges []Edge, weights map[string]float64) map[uuid.UUID]float64 {
+	index := make(map[uuid.UUID]int, len(nodes))
+	for i, id := range nodes {
+		index[id] = i
+	}
+	// ノード外・自己ループのエッジは除き、同じノード間のエッジは重みを合算する
+	type pair struct{ from, to int }
+	merged := make(map[pair]float64)
+	var order []pair
+	for _, e := range edges {
+		from, ok := index[e.From]
+		if !ok {
+			continue
+		}
+		to, ok := index[e.To]
+		if !ok || from == to {
+			continue
+		}
+		w := weights[e.DepType]
+		if w <= 0 {
+			continue
+		}
+		p := pair{from, to}
+		if _, ok := merged[p]; !ok {
+			order = append(order, p)
+		}
+		merged[p] += w
+	}
+	out := make([][]weightedEdge, len(nodes))
+	for _, p := range order {
+		out[p.from] = append(out[p.from], weightedEdge{to: p.to, weight: merged[p]})
+	}
+
+	var raw []float64
+	switch algorithm {
+	case AlgorithmBetweenness:
+		raw = betweenness(out)
+	case AlgorithmInDegree:
+		raw = weightedInDegree(out)
+	default:
+		raw = pageRank(out)
+	}
+	return normalize(nodes, raw)
+}
+
+type weightedEdge struct {
+	to     int
+	weight float64
+}
+
+// pageRank は重み付き PageRank を反復計算する（依存先を持たないノードのスコアは全ノードに均等に配る）
+func pageRank(out [][]weightedEdge) []float64 {
+	n := len(out)
+	if n == 0 {
+		return nil
+	}
+	totals := make([]float64, n)
+	for i, edges := range out {
+		for _, e := range edges {
+			totals[i] += e.weight
+		}
+	}
+
+	rank := make([]float64, n)
+	for i := range rank {
+		rank[i] = 1 / float64(n)
+	}
+	next := make([]float64, n)
+	for range pageRankMaxIterations {
+		dangling := 0.0
+		for i := range out {
+			if totals[i] == 0 {
+				dangling += rank[i]
+			}
+		}
+		base := (1-pageRankDamping)/float64(n) + pageRankDamping*dangling/float64(n)
+		for i := range next {
+			next[i] = base
+		}
+		for i, edges := range out {
+			for _, e := range edges {
+				next[e.to] += pageRankDamping * rank[i] * e.weight / totals[i]
+			}
+		}
+
+		diff := 0.0
+		for i := range rank {
+			diff += math.Abs(next[i] - rank[i])
+		}
+		rank, next = next, rank
+		if diff < pageRankTolerance {
+			break
+		}
+	}
+	return rank
+}
+
+// betweenness は Brandes のアルゴリズムで媒介中心性を求める（エッジの重みは使わず、依存の向きに沿った最短経路を数える）
+func betweenness(out [][]weightedEdge) []float64 {
+	n := len(out)
+	centrality := make([]float64, n)
+	sigma := make([]float64, n)
+	dist := make([]int, n)
+	delta := make([]float64, n)
+	preds := make([][]int, n)
+
+	for s := range n {
+		for i := range n {
+			sigma[i] = 0
+			dist[i] = -1
+			delta[i] = 0
+			preds[i] = preds[i][:0]
+		}
+		sigma[s] = 1
+		dist[s] = 0
+
+		stack := make([]int, 0, n)
+		queue := []int{s}
+		for len(queue) > 0 {
+			v := queue[0]
+			queue = queue[1:]
+			stack = append(stack, v)
+			for _, e := range out[v] {
+				w := e.to
+				if dist[w] < 0 {
+					dist[w] = dist[v] + 1
+					queue = append(queue, w)
+				}
+				if dist[w] == dist[v]+1 {
+					sigma[w] += sigma[v]
+					preds[w] = append(preds[w], v)
+				}
+			}
+		}
+
+		for i := len(stack) - 1; i >= 0; i-- {
+			w := stack[i]
+			for _, v := range preds[w] {
+				delta[v] += sigma[v] / sigma[w] * (1 + delta[w])
+			}
+			if w != s {
+				centrality[w] += delta[w]
+			}
+		}
+	}
+	return centrality
+}
+
+// weightedInDegree は依存の種類で重み付けした被依存数を求める
+func weightedInDegree(out [][]weightedEdge) []float64 {
+	degree := make([]float64, len(out))
+	for _, edges := range out {
+		for _, e := range edges {
+			degree[e.to] += e.weight
+		}
+	}
+	return degree
+}
+
+// normalize はスコアを最大値で割って 0〜1 に正規化する（すべて同じ値の場合は 0）
+// PageRank の基底値のように全ノードに共通する最小値は差し引く
+func normalize(nodes []uuid.UUID, raw []float64) map[uuid.UUID]float64 {
+	scores := make(map[uuid.UUID]float64, len(nodes))
+	if len(raw) == 0 {
+		return scores
+	}
+	lo, hi := raw[0], raw[0]
+	for _, v := range raw {
+		lo = min(lo, v)
+		hi = max(hi, v)
+	}
+	for i, id := range nodes {
+		if hi > lo {
+			scores[id] = (raw[i] - lo) / (hi - lo)
+		} else {
+			scores[id] = 0
+		}
+	}
+	return scores
+}
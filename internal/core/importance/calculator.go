@@ -0,0 +1,169 @@
+package importance
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultEditWeight は最終スコアに占める編集頻度スコアの既定の重み
+	DefaultEditWeight = 0.2
+)
+
+// Node は重要度の算出対象のチャンク
+type Node struct {
+	ChunkID   uuid.UUID
+	EditCount int // スナップショット間で内容が変更された回数
+}
+
+// Score はチャンクの重要度の内訳
+type Score struct {
+	ChunkID    uuid.UUID
+	Algorithm  Algorithm
+	GraphScore float64 // 依存関係グラフから求めたスコア（0〜1）
+	EditScore  float64 // 編集頻度から求めたスコア（0〜1）
+	FinalScore float64 // importance_score として保存する最終スコア（0〜1）
+	EditCount  int
+}
+
+// Repository は重要度の算出に使用するグラフの読み取りとスコアの保存を行うインターフェース
+// テスト時のモック用に消費者側で定義
+type Repository interface {
+	// ListImportanceNodes はプロダクトの各ソースの最新スナップショットの名前を持つチャンクと編集回数を取得する
+	ListImportanceNodes(ctx context.Context, productID uuid.UUID) ([]Node, error)
+	// ListImportanceEdges はプロダクトの各ソースの最新スナップショットのチャンク間の依存関係を取得する
+	ListImportanceEdges(ctx context.Context, productID uuid.UUID) ([]Edge, error)
+	// SaveImportanceScores はスコアの内訳を保存し、チャンクの importance_score を最終スコアで更新する
+	SaveImportanceScores(ctx context.Context, scores []Score) error
+}
+
+// ImportanceCalculator は依存関係グラフと編集頻度からチャンクの重要度を算出する
+// 最終スコア = (1 - w) × グラフスコア + w × 編集頻度スコア（w は編集頻度の重み）
+type ImportanceCalculator struct {
+	repo           Repository
+	algorithm      Algorithm
+	editWeight     float64
+	depTypeWeights map[string]float64
+	logger         *slog.Logger
+}
+
+// ImportanceCalculatorOption は ImportanceCalculator のオプション設定
+type ImportanceCalculatorOption func(*ImportanceCalculator)
+
+// WithImportanceAlgorithm はグラフスコアのアルゴリズムを設定する
+func WithImportanceAlgorithm(algorithm Algorithm) ImportanceCalculatorOption {
+	return func(c *ImportanceCalculator) {
+		c.algorithm = algorithm
+	}
+}
+
+// WithImportanceEditWeight は最終スコアに占める編集頻度スコアの重み（0〜1）を設定する
+func WithImportanceEditWeight(weight float64) ImportanceCalculatorOption {
+	return func(c *ImportanceCalculator) {
+		c.editWeight = weight
+	}
+}
+
+// WithImportanceDepTypeWeights は依存の種類ごとのエッジの重みを設定する（含まれない種類のエッジは使わない）
+func WithImportanceDepTypeWeights(weights map[string]float64) ImportanceCalculatorOption {
+	return func(c *ImportanceCalculator) {
+		c.depTypeWeights = weights
+	}
+}
+
+// WithImportanceLogger は ImportanceCalculator にロガーを設定する
+func WithImportanceLogger(logger *slog.Logger) ImportanceCalculatorOption {
+	return func(c *ImportanceCalculator) {
+		c.logger = logger
+	}
+}
+
+// NewImportanceCalculator は新しい ImportanceCalculator を作成する
+func NewImportanceCalculator(repo Repository, opts ...ImportanceCalculatorOption) *ImportanceCalculator {
+	c := &ImportanceCalculator{
+		repo:           repo,
+		algorithm:      AlgorithmPageRank,
+		editWeight:     DefaultEditWeight,
+		depTypeWeights: DefaultDepTypeWeights,
+		logger:         slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.logger == nil {
+		c.logger = slog.Default()
+	}
+	if !slices.Contains(Algorithms, c.algorithm) {
+		c.algorithm = AlgorithmPageRank
+	}
+	c.editWeight = min(max(c.editWeight, 0), 1)
+	return c
+}
+
+// Algorithm は使用するアルゴリズムを返す
+func (c *ImportanceCalculator) Algorithm() Algorithm {
+	return c.algorithm
+}
+
+// Recalculate はプロダクト全体（各ソースの最新スナップショット）のチャンクの重要度を算出して保存する
+func (c *ImportanceCalculator) Recalculate(ctx context.Context, productID uuid.UUID) ([]Score, error) {
+	nodes, err := c.repo.ListImportanceNodes(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list importance nodes: %w", err)
+	}
+	if len(nodes) == 0 {
+		return nil, nil
+	}
+	edges, err := c.repo.ListImportanceEdges(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list importance edges: %w", err)
+	}
+
+	scores := c.Calculate(nodes, edges)
+	if err := c.repo.SaveImportanceScores(ctx, scores); err != nil {
+		return nil, fmt.Errorf("failed to save importance scores: %w", err)
+	}
+
+	c.logger.Info("重要度を算出",
+		"productID", productID,
+		"algorithm", c.algorithm,
+		"chunks", len(nodes),
+		"edges", len(edges),
+	)
+	return scores, nil
+}
+
+// Calculate はチャンクごとのスコアの内訳を算出する（結果は nodes と同じ順序）
+// 編集頻度スコアは log(1 + 編集回数) を最大値で正規化した値
+func (c *ImportanceCalculator) Calculate(nodes []Node, edges []Edge) []Score {
+	ids := make([]uuid.UUID, len(nodes))
+	maxEdits := 0
+	for i, n := range nodes {
+		ids[i] = n.ChunkID
+		maxEdits = max(maxEdits, n.EditCount)
+	}
+	graph := graphScores(c.algorithm, ids, edges, c.depTypeWeights)
+
+	scores := make([]Score, len(nodes))
+	for i, n := range nodes {
+		editScore := 0.0
+		if maxEdits > 0 {
+			editScore = math.Log1p(float64(max(n.EditCount, 0))) / math.Log1p(float64(maxEdits))
+		}
+		graphScore := graph[n.ChunkID]
+		scores[i] = Score{
+			ChunkID:    n.ChunkID,
+			Algorithm:  c.algorithm,
+			GraphScore: graphScore,
+			EditScore:  editScore,
+			FinalScore: (1-c.editWeight)*graphScore + c.editWeight*editScore,
+			EditCount:  n.EditCount,
+		}
+	}
+	return scores
+}
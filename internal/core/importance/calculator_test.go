@@ -0,0 +1,122 @@
+package importance
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// handler → service → repo、worker → service の依存関係（service が最も多く依存される）
+func testGraph() (handler, worker, service, repo uuid.UUID, edges []Edge) {
+	handler, worker, service, repo = uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	edges = []Edge{
+		{From: handler, To: service, DepType: "call"},
+		{From: worker, To: service, DepType: "call"},
+		{From: service, To: repo, DepType: "call"},
+		{From: handler, To: repo, DepType: "import"},
+	}
+	return
+}
+
+func TestCalculate_PageRank(t *testing.T) {
+	handler, worker, service, repo, edges := testGraph()
+	calc := NewImportanceCalculator(nil, WithImportanceEditWeight(0))
+
+	scores := calc.Calculate([]Node{{ChunkID: handler}, {ChunkID: worker}, {ChunkID: service}, {ChunkID: repo}}, edges)
+	require.Len(t, scores, 4)
+
+	// repo は service の依存先のため、重要な service からスコアが流れ込む
+	assert.InDelta(t, 1.0, scores[3].GraphScore, 1e-9)
+	assert.Greater(t, scores[2].GraphScore, scores[0].GraphScore)
+	assert.InDelta(t, 0.0, scores[0].GraphScore, 1e-9)
+	assert.Equal(t, scores[0].GraphScore, scores[1].GraphScore)
+	assert.Equal(t, AlgorithmPageRank, scores[0].Algorithm)
+	assert.Equal(t, scores[2].GraphScore, scores[2].FinalScore)
+}
+
+func TestCalculate_Betweenness(t *testing.T) {
+	handler, worker, service, repo, edges := testGraph()
+	calc := NewImportanceCalculator(nil, WithImportanceAlgorithm(AlgorithmBetweenness), WithImportanceEditWeight(0))
+
+	scores := calc.Calculate([]Node{{ChunkID: handler}, {ChunkID: worker}, {ChunkID: service}, {ChunkID: repo}}, edges)
+
+	// 経路の中継点になるのは service のみ（worker → repo の最短経路）
+	assert.InDelta(t, 1.0, scores[2].GraphScore, 1e-9)
+	assert.InDelta(t, 0.0, scores[0].GraphScore, 1e-9)
+	assert.InDelta(t, 0.0, scores[3].GraphScore, 1e-9)
+}
+
+func TestCalculate_InDegree(t *testing.T) {
+	handler, worker, service, repo, edges := testGraph()
+	calc := NewImportanceCalculator(nil, WithImportanceAlgorithm(AlgorithmInDegree), WithImportanceEditWeight(0))
+
+	scores := calc.Calculate([]Node{{ChunkID: handler}, {ChunkID: worker}, {ChunkID: service}, {ChunkID: repo}}, edges)
+
+	// service: call × 2 = 2.0、repo: call + import = 1.3
+	assert.InDelta(t, 1.0, scores[2].GraphScore, 1e-9)
+	assert.InDelta(t, 0.65, scores[3].GraphScore, 1e-9)
+	assert.InDelta(t, 0.0, scores[1].GraphScore, 1e-9)
+}
+
+func TestCalculate_EditFrequency(t *testing.T) {
+	handler, worker, service, repo, edges := testGraph()
+	calc := NewImportanceCalculator(nil, WithImportanceAlgorithm(AlgorithmInDegree), WithImportanceEditWeight(0.5))
+
+	scores := calc.Calculate([]Node{
+		{ChunkID: handler, EditCount: 7},
+		{ChunkID: worker},
+		{ChunkID: service, EditCount: 1},
+		{ChunkID: repo},
+	}, edges)
+
+	assert.InDelta(t, 1.0, scores[0].EditScore, 1e-9)
+	assert.InDelta(t, 0.5, scores[0].FinalScore, 1e-9)
+	assert.InDelta(t, 1.0/3, scores[2].EditScore, 1e-9) // log(2) / log(8)
+	assert.InDelta(t, 0.5+1.0/6, scores[2].FinalScore, 1e-9)
+	assert.Equal(t, 7, scores[0].EditCount)
+}
+
+func TestCalculate_NoEdges(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+	scores := NewImportanceCalculator(nil).Calculate([]Node{{ChunkID: a}, {ChunkID: b}}, nil)
+	assert.Zero(t, scores[0].FinalScore)
+	assert.Zero(t, scores[1].FinalScore)
+}
+
+type fakeRepository struct {
+	nodes []Node
+	edges []Edge
+	saved []Score
+}
+
+func (f *fakeRepository) ListImportanceNodes(context.Context, uuid.UUID) ([]Node, error) {
+	return f.nodes, nil
+}
+
+func (f *fakeRepository) ListImportanceEdges(context.Context, uuid.UUID) ([]Edge, error) {
+	return f.edges, nil
+}
+
+func (f *fakeRepository) SaveImportanceScores(_ context.Context, scores []Score) error {
+	f.saved = scores
+	return nil
+}
+
+func TestRecalculate(t *testing.T) {
+	handler, worker, service, repo, edges := testGraph()
+	fake := &fakeRepository{
+		nodes: []Node{{ChunkID: handler}, {ChunkID: worker}, {ChunkID: service}, {ChunkID: repo}},
+		edges: edges,
+	}
+
+	calc := NewImportanceCalculator(fake, WithImportanceAlgorithm("unknown"))
+	assert.Equal(t, AlgorithmPageRank, calc.Algorithm())
+
+	scores, err := calc.Recalculate(context.Background(), uuid.New())
+	require.NoError(t, err)
+	assert.Len(t, scores, 4)
+	assert.Equal(t, scores, fake.saved)
+}
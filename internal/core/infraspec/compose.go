@@ -0,0 +1,144 @@
+package infraspec
+
+import (
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// composeFile は Docker Compose の定義のうちリソースの抽出に使用する部分
+type composeFile struct {
+	Services yaml.Node `yaml:"services"`
+}
+
+type composeService struct {
+	Image       string      `yaml:"image"`
+	Build       yaml.Node   `yaml:"build"` // コンテキストのパス、または context を含むマッピング
+	Ports       []yaml.Node `yaml:"ports"` // 短縮形（"8080:80/tcp"）または target・published を含むマッピング
+	Environment yaml.Node   `yaml:"environment"`
+	Deploy      struct {
+		Replicas  *int `yaml:"replicas"`
+		Resources struct {
+			Limits       map[string]string `yaml:"limits"`
+			Reservations map[string]string `yaml:"reservations"`
+		} `yaml:"resources"`
+	} `yaml:"deploy"`
+}
+
+type composeLongPort struct {
+	Target    string `yaml:"target"`
+	Published string `yaml:"published"`
+	Protocol  string `yaml:"protocol"`
+	Name      string `yaml:"name"`
+}
+
+// ParseCompose は Docker Compose の定義からサービスごとのイメージ・公開ポート・環境変数・リソース制限を抽出する
+// トップレベルに services がない場合は false を返す
+func ParseCompose(content []byte) ([]*Resource, bool) {
+	var file composeFile
+	if err := yaml.Unmarshal(content, &file); err != nil {
+		return nil, false
+	}
+	if file.Services.Kind != yaml.MappingNode {
+		return nil, false
+	}
+
+	var resources []*Resource
+	for i := 0; i+1 < len(file.Services.Content); i += 2 {
+		name := file.Services.Content[i].Value
+		var svc composeService
+		if err := file.Services.Content[i+1].Decode(&svc); err != nil {
+			// 不正なサービスは読み飛ばし、他のサービスの抽出は続ける
+			continue
+		}
+
+		container := Container{
+			Name:     name,
+			Image:    svc.Image,
+			Env:      composeEnvironment(&svc.Environment),
+			Limits:   svc.Deploy.Resources.Limits,
+			Requests: svc.Deploy.Resources.Reservations,
+		}
+		if container.Image == "" {
+			if buildContext := composeBuildContext(&svc.Build); buildContext != "" {
+				container.Image = "build: " + buildContext
+			}
+		}
+		for j := range svc.Ports {
+			if port, ok := composePort(&svc.Ports[j]); ok {
+				container.Ports = append(container.Ports, port)
+			}
+		}
+
+		resources = append(resources, &Resource{
+			Format: FormatCompose,
+			Kind:   "service",
+			Name:   name,
+			Spec: Spec{
+				Replicas:   svc.Deploy.Replicas,
+				Containers: []Container{container},
+			},
+		})
+	}
+	return resources, true
+}
+
+// composeBuildContext は build の指定からビルドコンテキストを返す
+func composeBuildContext(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return node.Value
+	case yaml.MappingNode:
+		var build struct {
+			Context string `yaml:"context"`
+		}
+		if err := node.Decode(&build); err == nil {
+			return build.Context
+		}
+	}
+	return ""
+}
+
+// composePort は ports の要素を解析する
+// 短縮形は [IP:][ホスト側:]コンテナ側[/プロトコル]（コンテナ側のみの場合はホスト側のポートを空とする）
+func composePort(node *yaml.Node) (Port, bool) {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		spec, protocol, _ := strings.Cut(node.Value, "/")
+		parts := strings.Split(spec, ":")
+		port := Port{TargetPort: parts[len(parts)-1], Protocol: protocol}
+		if len(parts) >= 2 {
+			port.Port = parts[len(parts)-2]
+		}
+		return port, port.TargetPort != ""
+	case yaml.MappingNode:
+		var long composeLongPort
+		if err := node.Decode(&long); err != nil || long.Target == "" {
+			return Port{}, false
+		}
+		return Port{Name: long.Name, Port: long.Published, TargetPort: long.Target, Protocol: long.Protocol}, true
+	}
+	return Port{}, false
+}
+
+// composeEnvironment は environment（マッピング、または KEY=value のリスト）を解析する
+func composeEnvironment(node *yaml.Node) []EnvVar {
+	var env []EnvVar
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			value := node.Content[i+1]
+			v := value.Value
+			if value.Tag == "!!null" {
+				v = ""
+			}
+			env = append(env, EnvVar{Name: node.Content[i].Value, Value: v})
+		}
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			name, value, _ := strings.Cut(item.Value, "=")
+			env = append(env, EnvVar{Name: name, Value: value})
+		}
+	}
+	return env
+}
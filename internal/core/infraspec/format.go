@@ -0,0 +1,167 @@
+package infraspec
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// infraKeywords はデプロイ構成に関する質問と判定するキーワード
+// 英字のキーワードは単語の境界でのみ一致させる
+var infraKeywords = []string{
+	"docker", "dockerfile", "compose", "container", "containers", "kubernetes", "k8s", "helm", "manifest", "manifests",
+	"deploy", "deployment", "deployments", "image", "images", "port", "ports", "replica", "replicas", "env", "environment",
+	"cpu", "memory", "infra", "infrastructure", "pod", "pods",
+	"デプロイ", "コンテナ", "イメージ", "ポート", "レプリカ", "環境変数", "メモリ", "リソース制限", "インフラ", "マニフェスト",
+}
+
+// MatchResources は質問に関連するリソースを関連度順に最大 limit 件返す
+// 名前・イメージが質問文に現れるリソースを優先し、デプロイ構成に関する質問であればその他のリソースで補う
+func MatchResources(resources []*Resource, text string, limit int) []*Resource {
+	lower := strings.ToLower(text)
+	infraQuestion := slices.ContainsFunc(infraKeywords, func(keyword string) bool {
+		return containsWord(lower, keyword)
+	})
+
+	type scored struct {
+		resource *Resource
+		score    int
+	}
+	var matches []scored
+	for _, r := range resources {
+		score := 0
+		if containsWord(lower, strings.ToLower(r.Name)) {
+			score += 10
+		}
+		for _, image := range r.Images() {
+			if name := imageName(image); len(name) >= 3 && containsWord(lower, name) {
+				score += 5
+				break
+			}
+		}
+		if score == 0 && !infraQuestion {
+			continue
+		}
+		matches = append(matches, scored{resource: r, score: score})
+	}
+
+	slices.SortStableFunc(matches, func(a, b scored) int {
+		return cmp.Compare(b.score, a.score)
+	})
+	result := make([]*Resource, 0, min(len(matches), limit))
+	for _, m := range matches {
+		if len(result) >= limit {
+			break
+		}
+		result = append(result, m.resource)
+	}
+	return result
+}
+
+// imageName はイメージ参照（ghcr.io/acme/api:1.2 など）からレジストリ・タグを除いた名前（api）を返す
+func imageName(image string) string {
+	image = strings.TrimPrefix(image, "build: ")
+	image, _, _ = strings.Cut(image, "@")
+	name := image[strings.LastIndex(image, "/")+1:]
+	name, _, _ = strings.Cut(name, ":")
+	return strings.ToLower(name)
+}
+
+// containsWord は text に word が含まれるかを判定する（英数字のみからなる語は単語の境界でのみ一致させる）
+func containsWord(text, word string) bool {
+	if word == "" {
+		return false
+	}
+	if !asciiWord.MatchString(word) {
+		return strings.Contains(text, word)
+	}
+	pattern := regexp.MustCompile(`(^|[^a-z0-9_])` + regexp.QuoteMeta(word) + `($|[^a-z0-9_])`)
+	return pattern.MatchString(text)
+}
+
+var asciiWord = regexp.MustCompile(`^[a-z0-9_.\-]+$`)
+
+// Describe はリソースをプロンプトに埋め込むための要約（イメージ・ポート・環境変数・リソース制限）に整形する
+func Describe(r *Resource) string {
+	var sb strings.Builder
+	sb.WriteString(r.Kind)
+	if r.Format != FormatDockerfile {
+		sb.WriteString(" " + r.Name)
+	}
+	if r.Namespace != "" {
+		sb.WriteString(fmt.Sprintf("（namespace: %s）", r.Namespace))
+	}
+	sb.WriteString("\n定義: " + r.FilePath + "\n")
+
+	if r.Spec.Replicas != nil {
+		sb.WriteString(fmt.Sprintf("レプリカ数: %d\n", *r.Spec.Replicas))
+	}
+	if r.Spec.ServiceType != "" {
+		sb.WriteString("Service の type: " + r.Spec.ServiceType + "\n")
+	}
+	if len(r.Spec.Ports) > 0 {
+		sb.WriteString("ポート: " + joinPorts(r.Spec.Ports) + "\n")
+	}
+	for _, c := range r.Spec.Containers {
+		label := "コンテナ"
+		if r.Format == FormatDockerfile {
+			label = "ビルドステージ"
+		}
+		if c.Name != "" {
+			label += " " + c.Name
+		}
+		sb.WriteString(fmt.Sprintf("%s: %s\n", label, c.Image))
+		if len(c.Ports) > 0 {
+			sb.WriteString("  ポート: " + joinPorts(c.Ports) + "\n")
+		}
+		if len(c.Env) > 0 {
+			sb.WriteString("  環境変数: " + joinEnv(c.Env) + "\n")
+		}
+		if len(c.Limits) > 0 {
+			sb.WriteString("  リソース上限: " + joinQuantities(c.Limits) + "\n")
+		}
+		if len(c.Requests) > 0 {
+			sb.WriteString("  リソース要求: " + joinQuantities(c.Requests) + "\n")
+		}
+	}
+	if r.Spec.Command != "" {
+		sb.WriteString("起動コマンド: " + r.Spec.Command + "\n")
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+func joinPorts(ports []Port) string {
+	parts := make([]string, 0, len(ports))
+	for _, p := range ports {
+		parts = append(parts, p.String())
+	}
+	return strings.Join(parts, ", ")
+}
+
+// joinEnv は環境変数を "NAME=value" の形式で連結する（参照は "NAME（secret:db/password）"）
+func joinEnv(env []EnvVar) string {
+	parts := make([]string, 0, len(env))
+	for _, e := range env {
+		switch {
+		case e.From != "":
+			parts = append(parts, fmt.Sprintf("%s（%s）", e.Name, e.From))
+		case e.Value != "":
+			parts = append(parts, e.Name+"="+e.Value)
+		default:
+			parts = append(parts, e.Name)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// joinQuantities はリソース量を名前順に "cpu=500m, memory=512Mi" の形式で連結する
+func joinQuantities(quantities map[string]string) string {
+	parts := make([]string, 0, len(quantities))
+	for _, name := range slices.Sorted(maps.Keys(quantities)) {
+		parts = append(parts, name+"="+quantities[name])
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,156 @@
+package infraspec
+
+import (
+	"bytes"
+	"errors"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// k8sObject は Kubernetes のマニフェストのうちリソースの抽出に使用する部分
+type k8sObject struct {
+	APIVersion string `yaml:"apiVersion"`
+	Kind       string `yaml:"kind"`
+	Metadata   struct {
+		Name      string `yaml:"name"`
+		Namespace string `yaml:"namespace"`
+	} `yaml:"metadata"`
+	Spec k8sSpec `yaml:"spec"`
+}
+
+// k8sSpec はワークロード・Pod・Service の spec のうち抽出に使用する部分
+type k8sSpec struct {
+	Replicas    *int           `yaml:"replicas"`
+	Template    k8sPodTemplate `yaml:"template"` // Deployment・StatefulSet・DaemonSet・ReplicaSet・Job
+	JobTemplate struct {
+		Spec struct {
+			Template k8sPodTemplate `yaml:"template"`
+		} `yaml:"spec"`
+	} `yaml:"jobTemplate"` // CronJob
+	Containers []k8sContainer   `yaml:"containers"` // Pod
+	Type       string           `yaml:"type"`       // Service
+	Ports      []k8sServicePort `yaml:"ports"`      // Service
+}
+
+type k8sPodTemplate struct {
+	Spec struct {
+		Containers []k8sContainer `yaml:"containers"`
+	} `yaml:"spec"`
+}
+
+type k8sContainer struct {
+	Name  string `yaml:"name"`
+	Image string `yaml:"image"`
+	Ports []struct {
+		Name          string `yaml:"name"`
+		ContainerPort string `yaml:"containerPort"`
+		Protocol      string `yaml:"protocol"`
+	} `yaml:"ports"`
+	Env []struct {
+		Name      string `yaml:"name"`
+		Value     string `yaml:"value"`
+		ValueFrom struct {
+			SecretKeyRef    *k8sKeyRef `yaml:"secretKeyRef"`
+			ConfigMapKeyRef *k8sKeyRef `yaml:"configMapKeyRef"`
+			FieldRef        *struct {
+				FieldPath string `yaml:"fieldPath"`
+			} `yaml:"fieldRef"`
+		} `yaml:"valueFrom"`
+	} `yaml:"env"`
+	Resources struct {
+		Limits   map[string]string `yaml:"limits"`
+		Requests map[string]string `yaml:"requests"`
+	} `yaml:"resources"`
+}
+
+type k8sKeyRef struct {
+	Name string `yaml:"name"`
+	Key  string `yaml:"key"`
+}
+
+type k8sServicePort struct {
+	Name       string `yaml:"name"`
+	Port       string `yaml:"port"`
+	TargetPort string `yaml:"targetPort"`
+	Protocol   string `yaml:"protocol"`
+}
+
+// ParseKubernetes は Kubernetes のマニフェスト（複数ドキュメントを含むYAML）からオブジェクトを抽出する
+// ワークロードはレプリカ数とコンテナ（イメージ・ポート・環境変数・リソース制限）、Service は type とポートを抽出し、その他の種類は名前のみを記録する
+// Helm のテンプレートのようにYAMLとして解釈できない箇所以降は読み飛ばす
+func ParseKubernetes(content []byte) []*Resource {
+	var resources []*Resource
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	for {
+		var obj k8sObject
+		err := decoder.Decode(&obj)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var typeErr *yaml.TypeError
+			if !errors.As(err, &typeErr) {
+				break
+			}
+			// 想定外の型のフィールドがあっても、解釈できた部分は使用する
+		}
+		if obj.APIVersion == "" || obj.Kind == "" || obj.Metadata.Name == "" {
+			continue
+		}
+
+		resource := &Resource{
+			Format:    FormatKubernetes,
+			Kind:      obj.Kind,
+			Name:      obj.Metadata.Name,
+			Namespace: obj.Metadata.Namespace,
+		}
+		var containers []k8sContainer
+		switch obj.Kind {
+		case "Deployment", "StatefulSet", "ReplicaSet":
+			resource.Spec.Replicas = obj.Spec.Replicas
+			containers = obj.Spec.Template.Spec.Containers
+		case "DaemonSet", "Job":
+			containers = obj.Spec.Template.Spec.Containers
+		case "CronJob":
+			containers = obj.Spec.JobTemplate.Spec.Template.Spec.Containers
+		case "Pod":
+			containers = obj.Spec.Containers
+		case "Service":
+			resource.Spec.ServiceType = obj.Spec.Type
+			for _, p := range obj.Spec.Ports {
+				resource.Spec.Ports = append(resource.Spec.Ports, Port{Name: p.Name, Port: p.Port, TargetPort: p.TargetPort, Protocol: p.Protocol})
+			}
+		}
+		for _, c := range containers {
+			resource.Spec.Containers = append(resource.Spec.Containers, c.toContainer())
+		}
+		resources = append(resources, resource)
+	}
+	return resources
+}
+
+func (c *k8sContainer) toContainer() Container {
+	container := Container{
+		Name:     c.Name,
+		Image:    c.Image,
+		Limits:   c.Resources.Limits,
+		Requests: c.Resources.Requests,
+	}
+	for _, p := range c.Ports {
+		container.Ports = append(container.Ports, Port{Name: p.Name, Port: p.ContainerPort, Protocol: p.Protocol})
+	}
+	for _, e := range c.Env {
+		env := EnvVar{Name: e.Name, Value: e.Value}
+		switch from := e.ValueFrom; {
+		case from.SecretKeyRef != nil:
+			env.From = "secret:" + from.SecretKeyRef.Name + "/" + from.SecretKeyRef.Key
+		case from.ConfigMapKeyRef != nil:
+			env.From = "configmap:" + from.ConfigMapKeyRef.Name + "/" + from.ConfigMapKeyRef.Key
+		case from.FieldRef != nil:
+			env.From = "field:" + from.FieldRef.FieldPath
+		}
+		container.Env = append(container.Env, env)
+	}
+	return container
+}
@@ -0,0 +1,102 @@
+// Package infraspec は Dockerfile・Docker Compose・Kubernetes マニフェストからデプロイ構成（ベースイメージ・公開ポート・環境変数・リソース制限・ワークロード・サービス）を抽出する
+package infraspec
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Format は定義ファイルの形式
+type Format string
+
+const (
+	FormatDockerfile Format = "dockerfile"
+	FormatCompose    Format = "compose"
+	FormatKubernetes Format = "kubernetes"
+)
+
+// Formats は定義ファイルの形式の一覧（表示順）
+var Formats = []Format{FormatDockerfile, FormatCompose, FormatKubernetes}
+
+// Resource はインフラ定義ファイルから抽出したリソース
+// Dockerfile はファイル単位、Docker Compose はサービス単位、Kubernetes はマニフェストのオブジェクト単位で抽出する
+type Resource struct {
+	ID         uuid.UUID
+	SnapshotID uuid.UUID
+	FileID     uuid.UUID
+	SourceName string // 取得時のみ設定される
+	FilePath   string
+	Format     Format
+	Kind       string // Dockerfile、service（Docker Compose）、Deployment・Service など（Kubernetes）
+	Name       string // Dockerfile はファイルパス
+	Namespace  string // Kubernetes のみ
+	Spec       Spec
+}
+
+// Spec はリソースの構成
+type Spec struct {
+	Replicas    *int        `json:"replicas,omitempty"`
+	ServiceType string      `json:"service_type,omitempty"` // Kubernetes の Service の type（ClusterIP など）
+	Ports       []Port      `json:"ports,omitempty"`        // Kubernetes の Service が公開するポート
+	Containers  []Container `json:"containers,omitempty"`   // Dockerfile はビルドステージごと
+	Command     string      `json:"command,omitempty"`      // Dockerfile の ENTRYPOINT・CMD
+}
+
+// Container はコンテナ（Dockerfile のビルドステージ）の構成
+type Container struct {
+	Name     string            `json:"name,omitempty"`
+	Image    string            `json:"image,omitempty"` // Dockerfile はベースイメージ、Docker Compose でビルドする場合は build: <コンテキスト>
+	Ports    []Port            `json:"ports,omitempty"`
+	Env      []EnvVar          `json:"env,omitempty"`
+	Limits   map[string]string `json:"limits,omitempty"`   // リソースの上限（cpu・memory）
+	Requests map[string]string `json:"requests,omitempty"` // リソースの要求量（Docker Compose は reservations）
+}
+
+// Port は公開ポート
+type Port struct {
+	Name       string `json:"name,omitempty"`
+	Port       string `json:"port,omitempty"`        // 公開するポート（Docker Compose はホスト側、Kubernetes の Service は port）
+	TargetPort string `json:"target_port,omitempty"` // 転送先のポート（Docker Compose はコンテナ側）
+	Protocol   string `json:"protocol,omitempty"`
+}
+
+// EnvVar は環境変数
+// 値は定義ファイルに直接記載されたもののみ保持し、Secret・ConfigMap からの参照は参照先を From に記録する
+type EnvVar struct {
+	Name  string `json:"name"`
+	Value string `json:"value,omitempty"`
+	From  string `json:"from,omitempty"` // secret:<名前>/<キー>、configmap:<名前>/<キー> など
+}
+
+// String は公開ポートを "<ポート>:<転送先>/<プロトコル>" の形式に整形する
+func (p Port) String() string {
+	s := p.Port
+	switch {
+	case s == "":
+		s = p.TargetPort
+	case p.TargetPort != "" && p.TargetPort != p.Port:
+		s = fmt.Sprintf("%s:%s", p.Port, p.TargetPort)
+	}
+	if p.Protocol != "" {
+		s += "/" + strings.ToLower(p.Protocol)
+	}
+	if p.Name != "" {
+		s += fmt.Sprintf("（%s）", p.Name)
+	}
+	return s
+}
+
+// Images はリソースのコンテナイメージ（Dockerfile はベースイメージ）を重複を除いて返す
+func (r *Resource) Images() []string {
+	var images []string
+	seen := make(map[string]bool)
+	for _, c := range r.Spec.Containers {
+		if c.Image != "" && !seen[c.Image] {
+			seen[c.Image] = true
+			images = append(images, c.Image)
+		}
+	}
+	return images
+}
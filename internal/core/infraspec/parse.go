@@ -0,0 +1,203 @@
+package infraspec
+
+import (
+	"cmp"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var (
+	dockerfilePattern = regexp.MustCompile(`(?i)^(?:dockerfile|containerfile)(?:\..+)?$|\.dockerfile$`)
+	composePattern    = regexp.MustCompile(`(?i)^(?:docker-)?compose(?:[.-].+)?\.ya?ml$`)
+)
+
+// Parse はファイルが Dockerfile・Docker Compose・Kubernetes マニフェストであればリソースを抽出する
+// インフラ定義ファイルでない場合は false を返す（Kubernetes のオブジェクトを含まないYAMLも false）
+// SnapshotID・FileID は呼び出し元で設定する
+func Parse(filePath, content string) ([]*Resource, bool) {
+	base := path.Base(filePath)
+	var resources []*Resource
+	switch {
+	case dockerfilePattern.MatchString(base):
+		resource, ok := ParseDockerfile(filePath, content)
+		if !ok {
+			return nil, false
+		}
+		resources = []*Resource{resource}
+	case composePattern.MatchString(base):
+		var ok bool
+		resources, ok = ParseCompose([]byte(content))
+		if !ok {
+			return nil, false
+		}
+	case strings.HasSuffix(strings.ToLower(base), ".yaml") || strings.HasSuffix(strings.ToLower(base), ".yml"):
+		resources = ParseKubernetes([]byte(content))
+		if len(resources) == 0 {
+			return nil, false
+		}
+	default:
+		return nil, false
+	}
+
+	for _, r := range resources {
+		r.FilePath = filePath
+	}
+	SortResources(resources)
+	return resources, true
+}
+
+// SortResources はリソースを形式・種類・名前空間・名前順に並べる
+func SortResources(resources []*Resource) {
+	slices.SortStableFunc(resources, func(a, b *Resource) int {
+		return cmp.Or(
+			cmp.Compare(slices.Index(Formats, a.Format), slices.Index(Formats, b.Format)),
+			cmp.Compare(a.Kind, b.Kind),
+			cmp.Compare(a.Namespace, b.Namespace),
+			cmp.Compare(a.Name, b.Name),
+		)
+	})
+}
+
+// ParseDockerfile は Dockerfile からビルドステージごとのベースイメージ・公開ポート・環境変数と、起動コマンドを抽出する
+// FROM を含まない場合は false を返す
+func ParseDockerfile(filePath, content string) (*Resource, bool) {
+	resource := &Resource{Format: FormatDockerfile, Kind: "Dockerfile", Name: filePath}
+	var stage *Container
+	var entrypoint, cmdArgs string
+
+	for _, line := range dockerfileInstructions(content) {
+		instruction, args, _ := strings.Cut(line, " ")
+		args = strings.TrimSpace(args)
+		switch strings.ToUpper(instruction) {
+		case "FROM":
+			fields := strings.Fields(args)
+			fields = slices.DeleteFunc(fields, func(f string) bool { return strings.HasPrefix(f, "--") })
+			if len(fields) == 0 {
+				continue
+			}
+			resource.Spec.Containers = append(resource.Spec.Containers, Container{Image: fields[0]})
+			stage = &resource.Spec.Containers[len(resource.Spec.Containers)-1]
+			if len(fields) >= 3 && strings.EqualFold(fields[1], "as") {
+				stage.Name = fields[2]
+			}
+			entrypoint, cmdArgs = "", ""
+		case "EXPOSE":
+			if stage == nil {
+				continue
+			}
+			for _, field := range strings.Fields(args) {
+				port, protocol, _ := strings.Cut(field, "/")
+				stage.Ports = append(stage.Ports, Port{Port: port, Protocol: strings.ToLower(protocol)})
+			}
+		case "ENV":
+			if stage != nil {
+				stage.Env = append(stage.Env, parseDockerEnv(args)...)
+			}
+		case "ENTRYPOINT":
+			entrypoint = execForm(args)
+		case "CMD":
+			cmdArgs = execForm(args)
+		}
+	}
+	if len(resource.Spec.Containers) == 0 {
+		return nil, false
+	}
+	resource.Spec.Command = strings.TrimSpace(entrypoint + " " + cmdArgs)
+	return resource, true
+}
+
+// dockerfileInstructions は行継続（末尾の \）を連結し、コメントと空行を除いた命令の一覧を返す
+func dockerfileInstructions(content string) []string {
+	var instructions []string
+	var current strings.Builder
+	for _, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "#") || (trimmed == "" && current.Len() == 0) {
+			continue
+		}
+		if cont, ok := strings.CutSuffix(trimmed, "\\"); ok {
+			current.WriteString(cont + " ")
+			continue
+		}
+		current.WriteString(trimmed)
+		if s := strings.Join(strings.Fields(current.String()), " "); s != "" {
+			instructions = append(instructions, s)
+		}
+		current.Reset()
+	}
+	if s := strings.Join(strings.Fields(current.String()), " "); s != "" {
+		instructions = append(instructions, s)
+	}
+	return instructions
+}
+
+// parseDockerEnv は ENV の引数（KEY=value 形式、または旧形式の KEY value）を解析する
+func parseDockerEnv(args string) []EnvVar {
+	words := shellWords(args)
+	if len(words) == 0 {
+		return nil
+	}
+	if !strings.Contains(words[0], "=") {
+		name, value, _ := strings.Cut(args, " ")
+		return []EnvVar{{Name: name, Value: strings.TrimSpace(value)}}
+	}
+	var env []EnvVar
+	for _, word := range words {
+		if name, value, ok := strings.Cut(word, "="); ok {
+			env = append(env, EnvVar{Name: name, Value: value})
+		}
+	}
+	return env
+}
+
+// execForm は ENTRYPOINT・CMD の引数を1行のコマンドに整形する（exec 形式の JSON 配列は空白区切りにする）
+func execForm(args string) string {
+	if !strings.HasPrefix(args, "[") {
+		return args
+	}
+	words := shellWords(strings.NewReplacer("[", " ", "]", " ", ",", " ").Replace(args))
+	return strings.Join(words, " ")
+}
+
+// shellWords は空白区切りの文字列を引用符を考慮して分割する（引用符は取り除く）
+func shellWords(s string) []string {
+	var words []string
+	var current strings.Builder
+	inWord := false
+	quote := rune(0)
+	escaped := false
+	for _, r := range s {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inWord = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, current.String())
+				current.Reset()
+				inWord = false
+			}
+		default:
+			current.WriteRune(r)
+			inWord = true
+		}
+	}
+	if inWord {
+		words = append(words, current.String())
+	}
+	return words
+}
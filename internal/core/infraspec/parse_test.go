@@ -0,0 +1,245 @@
+package infraspec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDockerfile(t *testing.T) {
+	content := `# syntax=docker/dockerfile:1
+FROM --platform=$BUILDPLATFORM golang:1.24 AS builder
+ENV CGO_ENABLED=0 \
+    GOOS=linux
+RUN go build -o /app ./cmd/server
+
+FROM gcr.io/distroless/static
+ENV LOG_LEVEL info
+ENV GREETING="hello world" PORT=8080
+EXPOSE 8080 9090/udp
+ENTRYPOINT ["/app"]
+CMD ["serve", "--port", "8080"]
+`
+	resources, ok := Parse("build/Dockerfile", content)
+	require.True(t, ok)
+	require.Len(t, resources, 1)
+
+	r := resources[0]
+	assert.Equal(t, FormatDockerfile, r.Format)
+	assert.Equal(t, "Dockerfile", r.Kind)
+	assert.Equal(t, "build/Dockerfile", r.Name)
+	assert.Equal(t, "build/Dockerfile", r.FilePath)
+	assert.Equal(t, "/app serve --port 8080", r.Spec.Command)
+	assert.Equal(t, []string{"golang:1.24", "gcr.io/distroless/static"}, r.Images())
+
+	require.Len(t, r.Spec.Containers, 2)
+	builder := r.Spec.Containers[0]
+	assert.Equal(t, "builder", builder.Name)
+	assert.Equal(t, []EnvVar{{Name: "CGO_ENABLED", Value: "0"}, {Name: "GOOS", Value: "linux"}}, builder.Env)
+
+	final := r.Spec.Containers[1]
+	assert.Empty(t, final.Name)
+	assert.Equal(t, []Port{{Port: "8080"}, {Port: "9090", Protocol: "udp"}}, final.Ports)
+	assert.Equal(t, []EnvVar{{Name: "LOG_LEVEL", Value: "info"}, {Name: "GREETING", Value: "hello world"}, {Name: "PORT", Value: "8080"}}, final.Env)
+
+	_, ok = Parse("Dockerfile.dev", "# FROM のない Dockerfile\nRUN echo")
+	assert.False(t, ok)
+}
+
+func TestParseCompose(t *testing.T) {
+	content := `services:
+  api:
+    build: ./api
+    ports:
+      - "8080:80"
+      - "127.0.0.1:9090:9090/udp"
+      - target: 443
+        published: "8443"
+    environment:
+      DATABASE_URL: postgres://db:5432/app
+      DEBUG:
+    deploy:
+      replicas: 2
+      resources:
+        limits:
+          cpus: "0.5"
+          memory: 512M
+  db:
+    image: pgvector/pgvector:pg16
+    environment:
+      - POSTGRES_PASSWORD=secret
+`
+	resources, ok := Parse("docker-compose.yml", content)
+	require.True(t, ok)
+	require.Len(t, resources, 2)
+
+	api := resources[0]
+	assert.Equal(t, FormatCompose, api.Format)
+	assert.Equal(t, "service", api.Kind)
+	assert.Equal(t, "api", api.Name)
+	require.NotNil(t, api.Spec.Replicas)
+	assert.Equal(t, 2, *api.Spec.Replicas)
+
+	container := api.Spec.Containers[0]
+	assert.Equal(t, "build: ./api", container.Image)
+	assert.Equal(t, []Port{
+		{Port: "8080", TargetPort: "80"},
+		{Port: "9090", TargetPort: "9090", Protocol: "udp"},
+		{Port: "8443", TargetPort: "443"},
+	}, container.Ports)
+	assert.Equal(t, []EnvVar{{Name: "DATABASE_URL", Value: "postgres://db:5432/app"}, {Name: "DEBUG"}}, container.Env)
+	assert.Equal(t, map[string]string{"cpus": "0.5", "memory": "512M"}, container.Limits)
+
+	db := resources[1]
+	assert.Equal(t, "db", db.Name)
+	assert.Equal(t, []string{"pgvector/pgvector:pg16"}, db.Images())
+
+	_, ok = Parse("compose.yaml", "version: '3'\n")
+	assert.False(t, ok)
+}
+
+func TestParseKubernetes(t *testing.T) {
+	content := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+  namespace: prod
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+        - name: api
+          image: ghcr.io/acme/api:1.2.0
+          ports:
+            - name: http
+              containerPort: 8080
+          env:
+            - name: LOG_LEVEL
+              value: info
+            - name: DB_PASSWORD
+              valueFrom:
+                secretKeyRef:
+                  name: db
+                  key: password
+          resources:
+            limits:
+              cpu: 500m
+              memory: 512Mi
+            requests:
+              cpu: 1
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: api
+  namespace: prod
+spec:
+  type: ClusterIP
+  ports:
+    - port: 80
+      targetPort: http
+      protocol: TCP
+---
+apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: cleanup
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+            - name: cleanup
+              image: ghcr.io/acme/cleanup:latest
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: api-config
+`
+	resources, ok := Parse("deploy/k8s/api.yaml", content)
+	require.True(t, ok)
+	require.Len(t, resources, 4)
+
+	// 種類・名前空間・名前順
+	assert.Equal(t, "ConfigMap", resources[0].Kind)
+	assert.Equal(t, "CronJob", resources[1].Kind)
+	assert.Equal(t, []string{"ghcr.io/acme/cleanup:latest"}, resources[1].Images())
+
+	deploy := resources[2]
+	assert.Equal(t, FormatKubernetes, deploy.Format)
+	assert.Equal(t, "Deployment", deploy.Kind)
+	assert.Equal(t, "prod", deploy.Namespace)
+	require.NotNil(t, deploy.Spec.Replicas)
+	assert.Equal(t, 3, *deploy.Spec.Replicas)
+
+	container := deploy.Spec.Containers[0]
+	assert.Equal(t, []Port{{Name: "http", Port: "8080"}}, container.Ports)
+	assert.Equal(t, []EnvVar{{Name: "LOG_LEVEL", Value: "info"}, {Name: "DB_PASSWORD", From: "secret:db/password"}}, container.Env)
+	assert.Equal(t, map[string]string{"cpu": "500m", "memory": "512Mi"}, container.Limits)
+	assert.Equal(t, map[string]string{"cpu": "1"}, container.Requests)
+
+	svc := resources[3]
+	assert.Equal(t, "Service", svc.Kind)
+	assert.Equal(t, "ClusterIP", svc.Spec.ServiceType)
+	assert.Equal(t, []Port{{Port: "80", TargetPort: "http", Protocol: "TCP"}}, svc.Spec.Ports)
+
+	// Kubernetes のオブジェクトを含まないYAML・Helm のテンプレートは対象外
+	_, ok = Parse("deploy/values.yaml", "replicaCount: 2\nimage:\n  tag: latest\n")
+	assert.False(t, ok)
+	_, ok = Parse("deploy/chart/templates/svc.yaml", "apiVersion: v1\nkind: Service\nmetadata:\n  name: {{ .Release.Name }}\n")
+	assert.False(t, ok)
+}
+
+func TestMatchResources(t *testing.T) {
+	resources := []*Resource{
+		{Format: FormatKubernetes, Kind: "Deployment", Name: "api", Spec: Spec{Containers: []Container{{Image: "ghcr.io/acme/api:1.2"}}}},
+		{Format: FormatCompose, Kind: "service", Name: "db", Spec: Spec{Containers: []Container{{Image: "pgvector/pgvector:pg16"}}}},
+		{Format: FormatKubernetes, Kind: "ConfigMap", Name: "settings"},
+	}
+
+	// 名前・イメージが現れるリソースを優先する
+	matched := MatchResources(resources, "pgvector のコンテナのメモリ上限は？", 2)
+	require.Len(t, matched, 2)
+	assert.Equal(t, "db", matched[0].Name)
+
+	matched = MatchResources(resources, "api deployment の replicas は？", 5)
+	require.Len(t, matched, 3)
+	assert.Equal(t, "api", matched[0].Name)
+
+	// デプロイ構成と無関係な質問には含めない（support の port には一致しない）
+	assert.Empty(t, MatchResources(resources, "How do we support retries?", 5))
+}
+
+func TestDescribe(t *testing.T) {
+	replicas := 3
+	r := &Resource{
+		Format:    FormatKubernetes,
+		Kind:      "Deployment",
+		Name:      "api",
+		Namespace: "prod",
+		FilePath:  "deploy/api.yaml",
+		Spec: Spec{
+			Replicas: &replicas,
+			Containers: []Container{{
+				Name:   "api",
+				Image:  "ghcr.io/acme/api:1.2",
+				Ports:  []Port{{Name: "http", Port: "8080", Protocol: "TCP"}},
+				Env:    []EnvVar{{Name: "LOG_LEVEL", Value: "info"}, {Name: "DB_PASSWORD", From: "secret:db/password"}},
+				Limits: map[string]string{"memory": "512Mi", "cpu": "500m"},
+			}},
+		},
+	}
+
+	expected := `Deployment api（namespace: prod）
+定義: deploy/api.yaml
+レプリカ数: 3
+コンテナ api: ghcr.io/acme/api:1.2
+  ポート: 8080/tcp（http）
+  環境変数: LOG_LEVEL=info, DB_PASSWORD（secret:db/password）
+  リソース上限: cpu=500m, memory=512Mi`
+	assert.Equal(t, expected, Describe(r))
+}
@@ -5,6 +5,7 @@ import (
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"sort"
 	"strings"
 )
 
@@ -195,9 +196,10 @@ func (ac *ASTChunkerGo) extractPackageDoc(file *ast.File, lines []string, chunkC
 
 // ImportInfo はインポート情報の詳細を保持します
 type ImportInfo struct {
-	All      []string // 全インポート
-	Standard []string // 標準ライブラリ
-	External []string // 外部依存
+	All      []string          // 全インポート
+	Standard []string          // 標準ライブラリ
+	External []string          // 外部依存
+	Aliases  map[string]string // ファイル内で参照する名前（別名または既定のパッケージ名）からインポートパスへの対応
 }
 
 // extractImportsDetailed はインポート情報を詳細に抽出します
@@ -206,12 +208,22 @@ func (ac *ASTChunkerGo) extractImportsDetailed(file *ast.File) *ImportInfo {
 		All:      []string{},
 		Standard: []string{},
 		External: []string{},
+		Aliases:  map[string]string{},
 	}
 
 	for _, imp := range file.Imports {
 		path := strings.Trim(imp.Path.Value, `"`)
 		info.All = append(info.All, path)
 
+		// ブランクインポート・ドットインポートは修飾子を持たないため対応に含めない
+		alias := defaultPackageName(path)
+		if imp.Name != nil {
+			alias = imp.Name.Name
+		}
+		if alias != "_" && alias != "." {
+			info.Aliases[alias] = path
+		}
+
 		// 標準ライブラリの判定
 		// 1. ドットを含まない（例: "fmt", "net/http"）
 		// 2. golang.orgで始まる
@@ -295,6 +307,7 @@ func (ac *ASTChunkerGo) extractFunctionWithMetricsDetailed(fn *ast.FuncDecl, fil
 
 	// 関数内の呼び出しを抽出
 	calls := ac.extractFunctionCalls(fn)
+	internalCalls, externalCalls := ac.extractQualifiedCalls(fn, importInfo)
 
 	// 型依存を抽出
 	typeDeps := ac.extractTypeDependencies(fn)
@@ -330,6 +343,8 @@ func (ac *ASTChunkerGo) extractFunctionWithMetricsDetailed(fn *ast.FuncDecl, fil
 			// 詳細な依存関係情報
 			StandardImports:  importInfo.Standard,
 			ExternalImports:  importInfo.External,
+			InternalCalls:    internalCalls,
+			ExternalCalls:    externalCalls,
 			TypeDependencies: typeDeps,
 		},
 	}, false // 除外されていない
@@ -647,6 +662,148 @@ func (ac *ASTChunkerGo) extractFunctionCalls(fn *ast.FuncDecl) []string {
 	return result
 }
 
+// qualifiedType は呼び出しの解決に使用する変数の型（インポートパスが空の場合は同じパッケージの型）
+type qualifiedType struct {
+	importPath string
+	name       string
+}
+
+// extractQualifiedCalls は関数内の呼び出しをパッケージ・レシーバ型で修飾して抽出します
+// 同じパッケージへの呼び出しは InternalCalls（"Func" または "Type.Method"）、
+// インポートしたパッケージへの呼び出しは ExternalCalls（"importpath.Func" または "importpath.Type.Method"）として返します
+// 変数の型はレシーバ・引数・複合リテラルによる宣言から判定し、型が分からない変数のメソッド呼び出しは含めません
+func (ac *ASTChunkerGo) extractQualifiedCalls(fn *ast.FuncDecl, importInfo *ImportInfo) ([]string, []string) {
+	vars := make(map[string]qualifiedType)
+	addFields := func(fields *ast.FieldList) {
+		if fields == nil {
+			return
+		}
+		for _, field := range fields.List {
+			t, ok := ac.resolveQualifiedType(field.Type, importInfo)
+			if !ok {
+				continue
+			}
+			for _, name := range field.Names {
+				vars[name.Name] = t
+			}
+		}
+	}
+	addFields(fn.Recv)
+	addFields(fn.Type.Params)
+
+	internal := make(map[string]bool)
+	external := make(map[string]bool)
+	if fn.Body == nil {
+		return []string{}, []string{}
+	}
+
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			// x := T{} / x := &pkg.T{} 形式の宣言から変数の型を記録する
+			if node.Tok != token.DEFINE || len(node.Lhs) != len(node.Rhs) {
+				return true
+			}
+			for i, lhs := range node.Lhs {
+				ident, ok := lhs.(*ast.Ident)
+				if !ok {
+					continue
+				}
+				rhs := node.Rhs[i]
+				if unary, ok := rhs.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+					rhs = unary.X
+				}
+				if lit, ok := rhs.(*ast.CompositeLit); ok {
+					if t, ok := ac.resolveQualifiedType(lit.Type, importInfo); ok {
+						vars[ident.Name] = t
+					}
+				}
+			}
+		case *ast.CallExpr:
+			switch fun := node.Fun.(type) {
+			case *ast.Ident:
+				if _, isVar := vars[fun.Name]; !isVar && !isBuiltinFunc(fun.Name) {
+					internal[fun.Name] = true
+				}
+			case *ast.SelectorExpr:
+				x, ok := fun.X.(*ast.Ident)
+				if !ok {
+					return true
+				}
+				if t, ok := vars[x.Name]; ok {
+					if t.importPath == "" {
+						internal[t.name+"."+fun.Sel.Name] = true
+					} else {
+						external[t.importPath+"."+t.name+"."+fun.Sel.Name] = true
+					}
+				} else if path, ok := importInfo.Aliases[x.Name]; ok {
+					external[path+"."+fun.Sel.Name] = true
+				}
+			}
+		}
+		return true
+	})
+
+	return sortedKeys(internal), sortedKeys(external)
+}
+
+// resolveQualifiedType は型式からポインタ・型引数を除いた型名とインポートパスを求めます
+func (ac *ASTChunkerGo) resolveQualifiedType(expr ast.Expr, importInfo *ImportInfo) (qualifiedType, bool) {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if isBuiltinType(t.Name) {
+			return qualifiedType{}, false
+		}
+		return qualifiedType{name: t.Name}, true
+	case *ast.StarExpr:
+		return ac.resolveQualifiedType(t.X, importInfo)
+	case *ast.IndexExpr:
+		return ac.resolveQualifiedType(t.X, importInfo)
+	case *ast.IndexListExpr:
+		return ac.resolveQualifiedType(t.X, importInfo)
+	case *ast.SelectorExpr:
+		x, ok := t.X.(*ast.Ident)
+		if !ok {
+			return qualifiedType{}, false
+		}
+		path, ok := importInfo.Aliases[x.Name]
+		if !ok {
+			return qualifiedType{}, false
+		}
+		return qualifiedType{importPath: path, name: t.Sel.Name}, true
+	}
+	return qualifiedType{}, false
+}
+
+// defaultPackageName はインポートパスから既定のパッケージ名を推定します（末尾のメジャーバージョン "v2" などは除く）
+func defaultPackageName(path string) string {
+	elems := strings.Split(path, "/")
+	name := elems[len(elems)-1]
+	if len(elems) > 1 && len(name) > 1 && name[0] == 'v' && strings.Trim(name[1:], "0123456789") == "" {
+		name = elems[len(elems)-2]
+	}
+	return strings.ReplaceAll(name, "-", "_")
+}
+
+// isBuiltinFunc は組み込み関数かどうかを判定します
+func isBuiltinFunc(name string) bool {
+	switch name {
+	case "append", "cap", "clear", "close", "complex", "copy", "delete", "imag",
+		"len", "make", "max", "min", "new", "panic", "print", "println", "real", "recover":
+		return true
+	}
+	return false
+}
+
+func sortedKeys(set map[string]bool) []string {
+	result := make([]string, 0, len(set))
+	for key := range set {
+		result = append(result, key)
+	}
+	sort.Strings(result)
+	return result
+}
+
 // calculateLinesOfCode はコメント・空行を除外した行数を計算します
 func (ac *ASTChunkerGo) calculateLinesOfCode(content string) int {
 	lines := strings.Split(content, "\n")
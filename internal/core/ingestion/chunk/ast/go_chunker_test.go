@@ -0,0 +1,68 @@
+package ast_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jinford/dev-rag/internal/core/ingestion/chunk/ast"
+)
+
+// wordCounter は空白区切りの単語数をトークン数とみなすテスト用のカウンター
+type wordCounter struct{}
+
+func (wordCounter) CountTokens(s string) int { return len(strings.Fields(s)) }
+
+func (wordCounter) TrimToTokenLimit(s string, _ int) string { return s }
+
+func TestASTChunkerGo_QualifiedCalls(t *testing.T) {
+	testCode := `package handler
+
+import (
+	"context"
+
+	authv2 "github.com/acme/lib/auth/v2"
+	"github.com/acme/api/internal/store"
+)
+
+type Server struct {
+	repo *store.Repository
+}
+
+// Handle はリクエストを処理します
+func (s *Server) Handle(ctx context.Context, c *authv2.Client, q Query) error {
+	claims, err := authv2.Verify(ctx, "token")
+	if err != nil {
+		return err
+	}
+	c.Refresh(ctx)
+	q.Normalize()
+	w := &Writer{}
+	w.Flush()
+	s.audit(claims)
+	items := make([]string, 0, len(claims.Scopes))
+	_ = append(items, validate(claims))
+	return s.repo.Save(ctx, items)
+}
+`
+
+	result := ast.NewASTChunkerGo().ChunkWithMetrics(testCode, wordCounter{})
+	require.True(t, result.ParseSuccess)
+
+	var handle *ast.ChunkWithMetadata
+	for _, c := range result.Chunks {
+		if c.Metadata.Name != nil && *c.Metadata.Name == "Handle" {
+			handle = c
+		}
+	}
+	require.NotNil(t, handle)
+
+	// レシーバ・引数・複合リテラルの型で修飾し、組み込み関数は含めない
+	assert.Equal(t, []string{"Query.Normalize", "Server.audit", "Writer.Flush", "validate"}, handle.Metadata.InternalCalls)
+	// 別名でインポートしたパッケージもインポートパスで修飾する（型が分からないフィールド経由の呼び出しは含めない）
+	assert.Equal(t, []string{"github.com/acme/lib/auth/v2.Client.Refresh", "github.com/acme/lib/auth/v2.Verify"}, handle.Metadata.ExternalCalls)
+	// 従来の呼び出し名は修飾せずに保持する
+	assert.Contains(t, handle.Metadata.Calls, "Refresh")
+}
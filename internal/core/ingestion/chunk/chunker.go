@@ -19,6 +19,15 @@ type ChunkerFactory interface {
 	GetChunker(language string) (Chunker, error)
 }
 
+// チャンカーの実装とバージョン（各チャンクの chunks.chunker_version に記録します）
+// 分割の方法やメタデータの抽出を変更した場合はバージョンを上げ、古いバージョンのチャンクを index rechunk --outdated で再チャンク化します
+const (
+	ChunkerVersionGoAST    = "go-ast-v1"   // Go の AST 解析
+	ChunkerVersionMarkdown = "markdown-v1" // Markdown の見出し単位
+	ChunkerVersionCode     = "code-v1"     // Go 以外のソースコード（関数・クラス単位）
+	ChunkerVersionText     = "text-v1"     // プレーンテキスト（行単位）
+)
+
 // Chunk はチャンクを表します
 type Chunk struct {
 	Content   string
@@ -75,6 +84,16 @@ type ChunkMetadata struct {
 	// Embedding用コンテキスト
 	EmbeddingContext *string // Embedding生成時に使用する追加コンテキスト
 
+	// Markdownの見出しの階層（例: Architecture > Deployment > Rollback）
+	Breadcrumb *string
+
+	// PDF・Office文書のページ番号（xlsx はシートの番号）
+	PageStart *int
+	PageEnd   *int
+
+	// チャンカーの実装とバージョン（例: go-ast-v1, markdown-v1）
+	ChunkerVersion string
+
 	// トレーサビリティ
 	SourceSnapshotID *uuid.UUID // ソーススナップショットID
 	GitCommitHash    *string    // Gitコミットハッシュ
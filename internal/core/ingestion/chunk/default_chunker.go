@@ -22,6 +22,11 @@ type DefaultChunker struct {
 
 // NewDefaultChunker は新しいDefaultChunkerを作成します
 func NewDefaultChunker() (*DefaultChunker, error) {
+	return NewDefaultChunkerWithConfig(DefaultChunkerConfig())
+}
+
+// NewDefaultChunkerWithConfig はトークン数の設定を指定してDefaultChunkerを作成します
+func NewDefaultChunkerWithConfig(cfg *ChunkerConfig) (*DefaultChunker, error) {
 	// cl100k_baseエンコーダを使用（OpenAIのtext-embedding-3-smallと互換）
 	encoder, err := tiktoken.GetEncoding("cl100k_base")
 	if err != nil {
@@ -30,10 +35,10 @@ func NewDefaultChunker() (*DefaultChunker, error) {
 
 	return &DefaultChunker{
 		encoder:      encoder,
-		targetTokens: 800,
-		maxTokens:    1600,
-		minTokens:    100,
-		overlap:      200,
+		targetTokens: cfg.TargetTokens,
+		maxTokens:    cfg.MaxTokens,
+		minTokens:    cfg.MinTokens,
+		overlap:      cfg.Overlap,
 	}, nil
 }
 
@@ -65,14 +70,19 @@ func (c *DefaultChunker) ChunkWithMetadataAndMetrics(content, contentType string
 		return c.chunkGoSourceCodeWithMetrics(content, metricsCollector, logger)
 	}
 
-	// その他の場合は既存の方法でチャンク化（メタデータなし）
+	// Markdownの場合は見出しの階層をメタデータに記録
+	if contentType == "text/markdown" {
+		return c.chunkMarkdownWithMetadata(content)
+	}
+
+	// その他の場合は既存の方法でチャンク化（構造メタデータなし）
 	var chunks []*Chunk
 	var err error
+	version := ChunkerVersionText
 
-	if contentType == "text/markdown" {
-		chunks, err = c.chunkMarkdown(content)
-	} else if isSourceCodeType(contentType) {
+	if isSourceCodeType(contentType) {
 		chunks, err = c.chunkSourceCode(content)
+		version = ChunkerVersionCode
 	} else {
 		chunks, err = c.chunkPlainText(content)
 	}
@@ -81,12 +91,12 @@ func (c *DefaultChunker) ChunkWithMetadataAndMetrics(content, contentType string
 		return nil, err
 	}
 
-	// Chunkをメタデータなしで返す
+	// Chunkを構造メタデータなしで返す（チャンカーのバージョンのみ記録）
 	chunksWithMeta := make([]*ChunkWithMetadata, len(chunks))
 	for i, chunk := range chunks {
 		chunksWithMeta[i] = &ChunkWithMetadata{
 			Chunk:    chunk,
-			Metadata: nil, // メタデータなし
+			Metadata: &ChunkMetadata{Level: 2, ChunkerVersion: version},
 		}
 	}
 	return chunksWithMeta, nil
@@ -536,6 +546,7 @@ func convertASTChunks(astChunks []*ast.ChunkWithMetadata) []*ChunkWithMetadata {
 			},
 			Metadata: convertASTMetadata(ac.Metadata),
 		}
+		chunks[i].Metadata.ChunkerVersion = ChunkerVersionGoAST
 	}
 	return chunks
 }
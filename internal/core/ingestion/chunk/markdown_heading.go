@@ -0,0 +1,98 @@
+package chunk
+
+import (
+	"regexp"
+	"strings"
+)
+
+// BreadcrumbSeparator は見出しの階層を連結する区切り文字です
+const BreadcrumbSeparator = " > "
+
+// markdownSectionType はMarkdownの見出し配下のチャンクの種別です
+const markdownSectionType = "section"
+
+// markdownHeadingPattern はATX形式の見出し（閉じの # を含む）にマッチします
+var markdownHeadingPattern = regexp.MustCompile(`^(#{1,6})\s+(.*?)(?:\s+#+)?\s*$`)
+
+// markdownHeading はMarkdownの見出しを表します
+type markdownHeading struct {
+	line  int    // 見出しの行番号（1始まり）
+	level int    // 見出しレベル（# の数）
+	title string // 見出しのテキスト
+}
+
+// parseMarkdownHeadings はコードブロック外の見出しを出現順に返します
+func parseMarkdownHeadings(lines []string) []markdownHeading {
+	var headings []markdownHeading
+	inCodeBlock := false
+	for i, line := range lines {
+		trimmedLine := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmedLine, "```") {
+			inCodeBlock = !inCodeBlock
+			continue
+		}
+		if inCodeBlock {
+			continue
+		}
+
+		m := markdownHeadingPattern.FindStringSubmatch(trimmedLine)
+		if m == nil || m[2] == "" {
+			continue
+		}
+		headings = append(headings, markdownHeading{
+			line:  i + 1,
+			level: len(m[1]),
+			title: m[2],
+		})
+	}
+	return headings
+}
+
+// markdownBreadcrumb は指定行の時点で有効な見出しの階層（H1 > H2 > H3 の順）を返します
+// 上位の見出しより深いレベルの見出しだけを階層として積み、同じか浅いレベルの見出しが現れたら置き換えます
+func markdownBreadcrumb(headings []markdownHeading, line int) []string {
+	var stack []markdownHeading
+	for _, h := range headings {
+		if h.line > line {
+			break
+		}
+		for len(stack) > 0 && stack[len(stack)-1].level >= h.level {
+			stack = stack[:len(stack)-1]
+		}
+		stack = append(stack, h)
+	}
+
+	titles := make([]string, 0, len(stack))
+	for _, h := range stack {
+		titles = append(titles, h.title)
+	}
+	return titles
+}
+
+// chunkMarkdownWithMetadata はMarkdownを見出し単位でチャンク化し、各チャンクの見出しの階層をメタデータに記録します
+// 見出しの階層はEmbeddingの入力の先頭にも付与し、本文に現れない上位の見出しの文脈でも検索できるようにします
+func (c *DefaultChunker) chunkMarkdownWithMetadata(content string) ([]*ChunkWithMetadata, error) {
+	chunks, err := c.chunkMarkdown(content)
+	if err != nil {
+		return nil, err
+	}
+
+	headings := parseMarkdownHeadings(strings.Split(content, "\n"))
+	chunksWithMeta := make([]*ChunkWithMetadata, len(chunks))
+	for i, chunk := range chunks {
+		metadata := &ChunkMetadata{Level: 2, ChunkerVersion: ChunkerVersionMarkdown}
+		if titles := markdownBreadcrumb(headings, chunk.StartLine); len(titles) > 0 {
+			sectionType := markdownSectionType
+			breadcrumb := strings.Join(titles, BreadcrumbSeparator)
+			embeddingContext := breadcrumb + "\n\n" + chunk.Content
+			metadata.Type = &sectionType
+			metadata.Breadcrumb = &breadcrumb
+			metadata.EmbeddingContext = &embeddingContext
+		}
+		chunksWithMeta[i] = &ChunkWithMetadata{
+			Chunk:    chunk,
+			Metadata: metadata,
+		}
+	}
+	return chunksWithMeta, nil
+}
@@ -0,0 +1,54 @@
+package chunk
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarkdownBreadcrumb(t *testing.T) {
+	markdown := `はじめに
+
+# Architecture
+
+概要
+
+## Deployment ##
+
+### Rollback
+
+` + "```sh" + `
+# kubectl rollout undo
+` + "```" + `
+
+## Monitoring
+
+# FAQ
+`
+	headings := parseMarkdownHeadings(strings.Split(markdown, "\n"))
+
+	// コードブロック内のコメントは見出しとして扱わない
+	titles := make([]string, 0, len(headings))
+	for _, h := range headings {
+		titles = append(titles, h.title)
+	}
+	assert.Equal(t, []string{"Architecture", "Deployment", "Rollback", "Monitoring", "FAQ"}, titles)
+
+	tests := []struct {
+		name string
+		line int
+		want []string
+	}{
+		{name: "最初の見出しより前", line: 1, want: []string{}},
+		{name: "見出しの行", line: 3, want: []string{"Architecture"}},
+		{name: "入れ子の見出しの本文", line: 12, want: []string{"Architecture", "Deployment", "Rollback"}},
+		{name: "同じレベルの見出しで置き換わる", line: 15, want: []string{"Architecture", "Monitoring"}},
+		{name: "上位の見出しで階層がリセットされる", line: 17, want: []string{"FAQ"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, markdownBreadcrumb(headings, tt.line))
+		})
+	}
+}
@@ -18,6 +18,10 @@ func NewContentTypeDetector() *ContentTypeDetector {
 
 // DetectContentType はファイルパスと内容からMIMEタイプを判定する。
 func (d *ContentTypeDetector) DetectContentType(path string, content []byte) string {
+	if mime, ok := documentMimeTypes[strings.ToLower(filepath.Ext(path))]; ok {
+		return mime
+	}
+
 	filename := filepath.Base(path)
 	language := enry.GetLanguage(filename, content)
 
@@ -36,44 +40,90 @@ func (d *ContentTypeDetector) DetectContentType(path string, content []byte) str
 	return "text/plain"
 }
 
+// languageMimeTypes は go-enry の言語名とMIMEタイプの対応
+var languageMimeTypes = map[string]string{
+	"Go":              "text/x-go",
+	"JavaScript":      "text/javascript",
+	"TypeScript":      "text/x-typescript",
+	"Python":          "text/x-python",
+	"Java":            "text/x-java",
+	"C":               "text/x-c",
+	"C++":             "text/x-c++",
+	"C#":              "text/x-csharp",
+	"Ruby":            "text/x-ruby",
+	"PHP":             "text/x-php",
+	"Rust":            "text/x-rust",
+	"Swift":           "text/x-swift",
+	"Kotlin":          "text/x-kotlin",
+	"Scala":           "text/x-scala",
+	"Shell":           "text/x-shellscript",
+	"Bash":            "text/x-shellscript",
+	"Markdown":        "text/markdown",
+	"HTML":            "text/html",
+	"CSS":             "text/css",
+	"SCSS":            "text/x-scss",
+	"SASS":            "text/x-sass",
+	"Less":            "text/x-less",
+	"JSON":            "application/json",
+	"CSV":             "text/csv",
+	"TSV":             "text/tab-separated-values",
+	"YAML":            "text/x-yaml",
+	"XML":             "text/xml",
+	"SQL":             "text/x-sql",
+	"Dockerfile":      "text/x-dockerfile",
+	"Makefile":        "text/x-makefile",
+	"Protocol Buffer": "text/x-protobuf",
+	"Thrift":          "text/x-thrift",
+	"GraphQL":         "application/graphql",
+	"Terraform":       "text/x-terraform",
+	"HCL":             "text/x-hcl",
+}
+
+// documentMimeTypes は拡張子で判定する文書ファイル（go-enry では判定できない PDF・Office文書）のMIMEタイプ
+var documentMimeTypes = map[string]string{
+	".pdf":  "application/pdf",
+	".docx": "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	".xlsx": "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
 func languageToMimeType(language string) string {
-	mapping := map[string]string{
-		"Go":              "text/x-go",
-		"JavaScript":      "text/javascript",
-		"TypeScript":      "text/x-typescript",
-		"Python":          "text/x-python",
-		"Java":            "text/x-java",
-		"C":               "text/x-c",
-		"C++":             "text/x-c++",
-		"C#":              "text/x-csharp",
-		"Ruby":            "text/x-ruby",
-		"PHP":             "text/x-php",
-		"Rust":            "text/x-rust",
-		"Swift":           "text/x-swift",
-		"Kotlin":          "text/x-kotlin",
-		"Scala":           "text/x-scala",
-		"Shell":           "text/x-shellscript",
-		"Bash":            "text/x-shellscript",
-		"Markdown":        "text/markdown",
-		"HTML":            "text/html",
-		"CSS":             "text/css",
-		"SCSS":            "text/x-scss",
-		"SASS":            "text/x-sass",
-		"Less":            "text/x-less",
-		"JSON":            "application/json",
-		"YAML":            "text/x-yaml",
-		"XML":             "text/xml",
-		"SQL":             "text/x-sql",
-		"Dockerfile":      "text/x-dockerfile",
-		"Makefile":        "text/x-makefile",
-		"Protocol Buffer": "text/x-protobuf",
-		"Thrift":          "text/x-thrift",
-		"GraphQL":         "application/graphql",
-		"Terraform":       "text/x-terraform",
-		"HCL":             "text/x-hcl",
+	return languageMimeTypes[language]
+}
+
+// LanguageFromContentType はMIMEタイプから files.language に記録する言語名を返す
+// 言語名は go-enry の言語名を小文字化したもの（go, python, typescript, cpp など）で、
+// search/ask の言語絞り込み（--language）に使用する
+func LanguageFromContentType(contentType string) string {
+	if contentType == "text/plain" {
+		return "plaintext"
 	}
-	if mime, ok := mapping[language]; ok {
-		return mime
+	for ext, mime := range documentMimeTypes {
+		if mime == contentType {
+			return strings.TrimPrefix(ext, ".")
+		}
+	}
+	for language, mime := range languageMimeTypes {
+		if mime == contentType {
+			return NormalizeLanguage(language)
+		}
+	}
+	return "unknown"
+}
+
+// NormalizeLanguage は言語名を files.language の表記（小文字、C++ は cpp など）に正規化する
+func NormalizeLanguage(language string) string {
+	switch name := strings.ToLower(strings.TrimSpace(language)); name {
+	case "c++":
+		return "cpp"
+	case "c#":
+		return "csharp"
+	case "bash", "sh":
+		return "shell"
+	case "protocol buffer":
+		return "protobuf"
+	case "golang":
+		return "go"
+	default:
+		return strings.ReplaceAll(name, " ", "-")
 	}
-	return ""
 }
@@ -0,0 +1,37 @@
+package ingestion
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLanguageFromContentType(t *testing.T) {
+	tests := []struct {
+		contentType string
+		want        string
+	}{
+		{contentType: "text/x-go", want: "go"},
+		{contentType: "text/x-python", want: "python"},
+		{contentType: "text/x-c++", want: "cpp"},
+		{contentType: "text/x-csharp", want: "csharp"},
+		{contentType: "text/x-shellscript", want: "shell"},
+		{contentType: "text/x-protobuf", want: "protobuf"},
+		{contentType: "text/plain", want: "plaintext"},
+		{contentType: "application/pdf", want: "pdf"},
+		{contentType: "application/octet-stream", want: "unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.contentType, func(t *testing.T) {
+			assert.Equal(t, tt.want, LanguageFromContentType(tt.contentType))
+		})
+	}
+}
+
+func TestNormalizeLanguage(t *testing.T) {
+	assert.Equal(t, "go", NormalizeLanguage("Go"))
+	assert.Equal(t, "go", NormalizeLanguage("golang"))
+	assert.Equal(t, "cpp", NormalizeLanguage("C++"))
+	assert.Equal(t, "typescript", NormalizeLanguage(" TypeScript "))
+}
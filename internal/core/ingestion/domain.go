@@ -0,0 +1,85 @@
+package ingestion
+
+import (
+	"path"
+	"slices"
+	"strings"
+)
+
+// ファイルのドメイン分類（files.domain）
+const (
+	DomainCode         = "code"         // アプリケーションのソースコード
+	DomainTests        = "tests"        // テストコード・テストデータ
+	DomainOps          = "ops"          // CI/CD・運用スクリプト・Runbook
+	DomainInfra        = "infra"        // コンテナ・IaC・デプロイマニフェスト
+	DomainArchitecture = "architecture" // 設計書・ADR・README などのドキュメント
+)
+
+// Domains は分類可能なドメインの一覧
+var Domains = []string{DomainCode, DomainTests, DomainOps, DomainInfra, DomainArchitecture}
+
+// IsValidDomain はドメイン名が分類可能なドメインかを返す
+func IsValidDomain(domain string) bool {
+	return slices.Contains(Domains, domain)
+}
+
+// infraDirs はインフラ定義を置くディレクトリ名
+var infraDirs = map[string]bool{
+	"deploy": true, "deployments": true, "k8s": true, "kubernetes": true, "helm": true,
+	"charts": true, "terraform": true, "infra": true, "infrastructure": true, "manifests": true,
+}
+
+// opsDirs は運用スクリプト・CI設定を置くディレクトリ名
+var opsDirs = map[string]bool{
+	".github": true, ".gitlab": true, ".circleci": true, "scripts": true, "ops": true, "runbooks": true,
+}
+
+// docDirs は設計ドキュメントを置くディレクトリ名
+var docDirs = map[string]bool{
+	"docs": true, "doc": true, "adr": true, "adrs": true, "design": true,
+}
+
+// testDirs はテストを置くディレクトリ名
+var testDirs = map[string]bool{
+	"test": true, "tests": true, "__tests__": true, "testdata": true, "spec": true, "e2e": true,
+}
+
+// ClassifyDomain はファイルパスからドメインを判定する（ルールベース）
+// テスト → インフラ → 運用 → ドキュメントの順に判定し、いずれにも該当しない場合は code とする
+func ClassifyDomain(filePath string) string {
+	p := strings.ToLower(path.Clean(strings.ReplaceAll(filePath, "\\", "/")))
+	base := path.Base(p)
+	ext := path.Ext(base)
+	dirs := strings.Split(path.Dir(p), "/")
+
+	hasDir := func(names map[string]bool) bool {
+		for _, d := range dirs {
+			if names[d] {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch {
+	case strings.HasSuffix(base, "_test.go"),
+		strings.Contains(base, ".test."), strings.Contains(base, ".spec."),
+		strings.HasPrefix(base, "test_") && ext == ".py",
+		hasDir(testDirs):
+		return DomainTests
+	case base == "dockerfile", strings.HasPrefix(base, "dockerfile."), strings.HasSuffix(base, ".dockerfile"),
+		strings.HasPrefix(base, "docker-compose"), strings.HasPrefix(base, "compose.y"),
+		ext == ".tf", ext == ".tfvars", ext == ".hcl",
+		hasDir(infraDirs):
+		return DomainInfra
+	case base == "makefile", base == "jenkinsfile", base == ".gitlab-ci.yml", base == "procfile",
+		ext == ".sh", ext == ".bash",
+		hasDir(opsDirs):
+		return DomainOps
+	case ext == ".md", ext == ".mdx", ext == ".rst", ext == ".adoc", ext == ".txt",
+		hasDir(docDirs):
+		return DomainArchitecture
+	default:
+		return DomainCode
+	}
+}
@@ -0,0 +1,35 @@
+package ingestion
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifyDomain(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "internal/core/search/service.go", want: DomainCode},
+		{path: "internal/core/search/service_test.go", want: DomainTests},
+		{path: "web/src/App.test.tsx", want: DomainTests},
+		{path: "tests/integration/api.py", want: DomainTests},
+		{path: "Dockerfile", want: DomainInfra},
+		{path: "docker-compose.yml", want: DomainInfra},
+		{path: "deploy/k8s/deployment.yaml", want: DomainInfra},
+		{path: "terraform/main.tf", want: DomainInfra},
+		{path: ".github/workflows/ci.yml", want: DomainOps},
+		{path: "Makefile", want: DomainOps},
+		{path: "scripts/release.sh", want: DomainOps},
+		{path: "docs/design.md", want: DomainArchitecture},
+		{path: "README.md", want: DomainArchitecture},
+		{path: "docs/adr/0001-use-pgvector.md", want: DomainArchitecture},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.want, ClassifyDomain(tt.path))
+		})
+	}
+}
@@ -18,6 +18,9 @@ type Embedder interface {
 
 	// MaxBatchSize はバッチ処理の最大サイズを返す
 	MaxBatchSize() int
+
+	// MaxInputTokens は1テキストあたりの最大入力トークン数を返す（0以下の場合は制限なし）
+	MaxInputTokens() int
 }
 
 // Metadata は Embedder のメタデータを表す
@@ -0,0 +1,60 @@
+package ingestion
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// EmbedWithModel はプロダクト内で追加のEmbeddingモデル（embedder のモデル）の Embedding がないチャンクの Embedding を生成する
+// 主モデルの Embedding とは別に保存し、検索時にモデルを選択して検索精度を比較できるようにする（index embed-models）
+// 生成できなかったチャンクは記録せず、再度実行した場合に生成の対象となる
+func (s *IndexService) EmbedWithModel(ctx context.Context, productID uuid.UUID, embedder Embedder) (*RetryEmbeddingsResult, error) {
+	model := embedder.ModelName()
+	if model == s.embedder.ModelName() {
+		return nil, fmt.Errorf("%s は主モデルのため追加のEmbeddingモデルとして生成できません（index reembed を使用してください）", model)
+	}
+
+	chunks, err := s.repository.ListChunksWithoutModelEmbedding(ctx, productID, model)
+	if err != nil {
+		return nil, fmt.Errorf("Embeddingを生成するチャンクの取得に失敗: %w", err)
+	}
+
+	result := &RetryEmbeddingsResult{Total: len(chunks)}
+	if len(chunks) == 0 {
+		return result, nil
+	}
+
+	batch := newBatchEmbedder(embedder, s.pipelineConfig, s.logger)
+	batchSize := max(min(s.pipelineConfig.EmbeddingBatchSize, embedder.MaxBatchSize()), MinBatchSize)
+	for start := 0; start < len(chunks); start += batchSize {
+		chunkBatch := chunks[start:min(start+batchSize, len(chunks))]
+
+		outcome, err := batch.embed(ctx, chunkBatch)
+		if err != nil {
+			return result, fmt.Errorf("Embedding生成に失敗: %w", err)
+		}
+		if err := s.repository.BatchUpsertModelEmbeddings(ctx, outcome.Embeddings); err != nil {
+			return result, fmt.Errorf("Embeddingの保存に失敗: %w", err)
+		}
+
+		result.Succeeded += len(outcome.Embeddings)
+		result.Failed += len(outcome.Failures)
+		s.logger.Info("追加のモデルでEmbeddingを生成中",
+			"productID", productID,
+			"model", model,
+			"processed", start+len(chunkBatch),
+			"total", result.Total,
+		)
+	}
+
+	s.logger.Info("追加のモデルでのEmbedding生成が完了",
+		"productID", productID,
+		"model", model,
+		"total", result.Total,
+		"succeeded", result.Succeeded,
+		"failed", result.Failed,
+	)
+	return result, nil
+}
@@ -0,0 +1,46 @@
+package ingestion
+
+import (
+	"strings"
+
+	"github.com/jinford/dev-rag/internal/core/ingestion/chunk"
+)
+
+// embeddingInput は Embedding の生成に使用するチャンクのテキストを返す
+// EmbeddingContext（最大入力トークン数に合わせて切り詰めた入力など）があればそれを、なければ本文を使用する
+func embeddingInput(c *Chunk) string {
+	if c.EmbeddingContext != nil {
+		return *c.EmbeddingContext
+	}
+	return c.Content
+}
+
+// fitEmbeddingInput は Embedding の入力が最大入力トークン数に収まるように決定的に切り詰める
+// シグネチャとその前のドキュメントコメントを先頭に残し（最大入力トークン数の半分まで）、残りのトークン数で本体の先頭から切り出す
+// 切り詰めが不要な場合は入力をそのまま返し、false を返す
+func fitEmbeddingInput(text string, signature *string, counter chunk.TokenCounter, maxTokens int) (string, bool) {
+	if counter == nil || maxTokens <= 0 || counter.CountTokens(text) <= maxTokens {
+		return text, false
+	}
+
+	head, body := "", text
+	if signature != nil && *signature != "" {
+		if i := strings.Index(text, *signature); i >= 0 {
+			headBudget := maxTokens / 2
+			sig := counter.TrimToTokenLimit(*signature, headBudget)
+			doc := ""
+			if docBudget := headBudget - counter.CountTokens(sig); docBudget > 0 {
+				doc = counter.TrimToTokenLimit(text[:i], docBudget)
+			}
+			head = doc + sig
+			body = text[i+len(*signature):]
+		}
+	}
+
+	fitted := head + counter.TrimToTokenLimit(body, max(maxTokens-counter.CountTokens(head), 0))
+	// 結合の境界でトークンの区切りが変わり上限を超えた場合は全体を切り詰める
+	if counter.CountTokens(fitted) > maxTokens {
+		fitted = counter.TrimToTokenLimit(fitted, maxTokens)
+	}
+	return fitted, true
+}
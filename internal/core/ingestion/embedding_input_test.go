@@ -0,0 +1,66 @@
+package ingestion
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// wordCounter は空白区切りの単語を1トークンとみなすテスト用のカウンター
+type wordCounter struct{}
+
+func (wordCounter) CountTokens(s string) int { return len(strings.Fields(s)) }
+
+func (wordCounter) TrimToTokenLimit(s string, maxTokens int) string {
+	words := strings.Fields(s)
+	if len(words) <= maxTokens {
+		return s
+	}
+	return strings.Join(words[:maxTokens], " ") + " "
+}
+
+func TestFitEmbeddingInput(t *testing.T) {
+	signature := "func Handle(ctx context.Context) error {"
+	text := "// Handle は リクエスト を 処理 する\n" + signature + "\n" + strings.Repeat("step()\n", 20) + "}"
+
+	t.Run("上限以内はそのまま", func(t *testing.T) {
+		got, truncated := fitEmbeddingInput(text, &signature, wordCounter{}, 100)
+		assert.False(t, truncated)
+		assert.Equal(t, text, got)
+	})
+
+	t.Run("シグネチャとドキュメントコメントを残して本体を切り詰める", func(t *testing.T) {
+		got, truncated := fitEmbeddingInput(text, &signature, wordCounter{}, 16)
+		assert.True(t, truncated)
+		assert.LessOrEqual(t, wordCounter{}.CountTokens(got), 16)
+		assert.True(t, strings.HasPrefix(got, "// Handle"))
+		assert.Contains(t, got, signature)
+		assert.Contains(t, got, "step()")
+
+		// 同じ入力からは常に同じ結果になる
+		again, _ := fitEmbeddingInput(text, &signature, wordCounter{}, 16)
+		assert.Equal(t, got, again)
+	})
+
+	t.Run("長いドキュメントコメントは上限の半分まで", func(t *testing.T) {
+		longDoc := "// " + strings.Repeat("説明 ", 50) + "\n"
+		got, truncated := fitEmbeddingInput(longDoc+signature+"\nstep()\n}", &signature, wordCounter{}, 20)
+		assert.True(t, truncated)
+		assert.Contains(t, got, signature)
+		assert.Contains(t, got, "step()")
+	})
+
+	t.Run("シグネチャがなければ先頭から切り出す", func(t *testing.T) {
+		got, truncated := fitEmbeddingInput(text, nil, wordCounter{}, 5)
+		assert.True(t, truncated)
+		assert.Equal(t, 5, wordCounter{}.CountTokens(got))
+		assert.True(t, strings.HasPrefix(got, "// Handle"))
+	})
+
+	t.Run("上限が未設定なら切り詰めない", func(t *testing.T) {
+		got, truncated := fitEmbeddingInput(text, &signature, wordCounter{}, 0)
+		assert.False(t, truncated)
+		assert.Equal(t, text, got)
+	})
+}
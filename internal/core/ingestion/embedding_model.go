@@ -0,0 +1,83 @@
+package ingestion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/samber/mo"
+)
+
+var (
+	// ErrEmbeddingModelNotRegistered は設定した Embedding モデルが embedding_models に登録されていないことを表す
+	ErrEmbeddingModelNotRegistered = errors.New("Embeddingモデルが登録されていません")
+	// ErrEmbeddingDimensionMismatch は Embedding モデルが出力する次元数とベクトルカラムの次元数が一致しないことを表す
+	ErrEmbeddingDimensionMismatch = errors.New("Embeddingモデルとベクトルカラムの次元数が一致しません")
+)
+
+// EmbeddingModel は使用を許可する Embedding モデルと出力するベクトルの次元数
+type EmbeddingModel struct {
+	Model             string
+	Provider          string
+	Dimension         int  // 出力するベクトルの次元数（VariableDimension の場合は最大の次元数）
+	VariableDimension bool // Dimension 以下の任意の次元数を指定して出力できるか
+	EmbeddingCount    int64
+	CreatedAt         time.Time
+}
+
+// Supports は指定した次元数のベクトルを出力できるかどうかを返す
+func (m *EmbeddingModel) Supports(dimension int) bool {
+	if m.VariableDimension {
+		return dimension > 0 && dimension <= m.Dimension
+	}
+	return dimension == m.Dimension
+}
+
+// EmbeddingModelRepository は登録済みの Embedding モデルのデータアクセスインターフェース
+type EmbeddingModelRepository interface {
+	GetEmbeddingModel(ctx context.Context, model string) (mo.Option[*EmbeddingModel], error)
+	// ListEmbeddingModels は各モデルで生成したチャンクの Embedding の数とともに登録済みのモデルを返す
+	ListEmbeddingModels(ctx context.Context) ([]*EmbeddingModel, error)
+	UpsertEmbeddingModel(ctx context.Context, m *EmbeddingModel) error
+}
+
+// VectorColumn は Embedding を格納する pgvector のカラム
+type VectorColumn struct {
+	Table     string
+	Dimension int // 0 の場合は次元の指定なし
+}
+
+// ValidateEmbeddingModel は設定した Embedding モデルが登録済みで、dimension 次元のベクトルを出力でき、
+// その次元数がベクトルカラムの次元数と一致するかを検証する
+// 一致しないまま起動すると保存や検索が失敗するか、次元を合わせた不正なベクトルが保存されるため、起動前に検出する
+func ValidateEmbeddingModel(registered mo.Option[*EmbeddingModel], model string, dimension int, columns []VectorColumn) error {
+	m, ok := registered.Get()
+	if !ok {
+		return fmt.Errorf("%w: %s（`dev-rag embedding register --model %s --dimension <次元数>` で登録してください）",
+			ErrEmbeddingModelNotRegistered, model, model)
+	}
+
+	if !m.Supports(dimension) {
+		if m.VariableDimension {
+			return fmt.Errorf("%w: %s は最大 %d 次元のベクトルを出力しますが、%d 次元が設定されています。%s",
+				ErrEmbeddingDimensionMismatch, model, m.Dimension, dimension, reembedRemediation(m.Dimension))
+		}
+		return fmt.Errorf("%w: %s は %d 次元のベクトルを出力しますが、%d 次元が設定されています。%s",
+			ErrEmbeddingDimensionMismatch, model, m.Dimension, dimension, reembedRemediation(m.Dimension))
+	}
+
+	for _, column := range columns {
+		if column.Dimension > 0 && column.Dimension != dimension {
+			return fmt.Errorf("%w: %s.vector は VECTOR(%d) ですが、%s は %d 次元のベクトルを出力します。%s",
+				ErrEmbeddingDimensionMismatch, column.Table, column.Dimension, model, dimension, reembedRemediation(dimension))
+		}
+	}
+	return nil
+}
+
+// reembedRemediation はモデルとベクトルカラムの次元数が一致しない場合の対処方法を返す
+func reembedRemediation(dimension int) string {
+	return fmt.Sprintf("EMBEDDING_MODEL・EMBEDDING_DIMENSION を見直すか、既存のベクトルを削除してカラムを VECTOR(%d) に変更し、"+
+		"`dev-rag index reembed --product <プロダクト名>` で Embedding を再生成してください", dimension)
+}
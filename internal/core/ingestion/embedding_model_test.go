@@ -0,0 +1,114 @@
+package ingestion
+
+import (
+	"testing"
+
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmbeddingModel_Supports(t *testing.T) {
+	variable := &EmbeddingModel{Model: "text-embedding-3-small", Dimension: 1536, VariableDimension: true}
+	assert.True(t, variable.Supports(1536))
+	assert.True(t, variable.Supports(512))
+	assert.False(t, variable.Supports(3072))
+	assert.False(t, variable.Supports(0))
+
+	fixed := &EmbeddingModel{Model: "nomic-embed-text", Dimension: 768}
+	assert.True(t, fixed.Supports(768))
+	assert.False(t, fixed.Supports(512))
+}
+
+func TestValidateEmbeddingModel(t *testing.T) {
+	small := mo.Some(&EmbeddingModel{Model: "text-embedding-3-small", Dimension: 1536, VariableDimension: true})
+	nomic := mo.Some(&EmbeddingModel{Model: "nomic-embed-text", Dimension: 768})
+	columns := func(dimension int) []VectorColumn {
+		return []VectorColumn{
+			{Table: "embeddings", Dimension: dimension},
+			{Table: "summary_embeddings", Dimension: dimension},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		registered mo.Option[*EmbeddingModel]
+		model      string
+		dimension  int
+		columns    []VectorColumn
+		wantErr    error
+		wantMsg    string
+	}{
+		{
+			name:       "registered model matching the columns",
+			registered: small,
+			model:      "text-embedding-3-small",
+			dimension:  1536,
+			columns:    columns(1536),
+		},
+		{
+			name:       "variable dimension model with a shorter column",
+			registered: small,
+			model:      "text-embedding-3-small",
+			dimension:  512,
+			columns:    columns(512),
+		},
+		{
+			name:       "unconstrained columns",
+			registered: nomic,
+			model:      "nomic-embed-text",
+			dimension:  768,
+			columns:    columns(0),
+		},
+		{
+			name:       "unregistered model",
+			registered: mo.None[*EmbeddingModel](),
+			model:      "my-deployment",
+			dimension:  1536,
+			columns:    columns(1536),
+			wantErr:    ErrEmbeddingModelNotRegistered,
+			wantMsg:    "dev-rag embedding register --model my-deployment",
+		},
+		{
+			name:       "fixed dimension model on a wider column",
+			registered: nomic,
+			model:      "nomic-embed-text",
+			dimension:  1536,
+			columns:    columns(1536),
+			wantErr:    ErrEmbeddingDimensionMismatch,
+			wantMsg:    "VECTOR(768)",
+		},
+		{
+			name:       "variable dimension model above its maximum",
+			registered: small,
+			model:      "text-embedding-3-small",
+			dimension:  3072,
+			columns:    columns(3072),
+			wantErr:    ErrEmbeddingDimensionMismatch,
+			wantMsg:    "最大 1536 次元",
+		},
+		{
+			name:       "summary column left at the previous dimension",
+			registered: nomic,
+			model:      "nomic-embed-text",
+			dimension:  768,
+			columns:    []VectorColumn{{Table: "embeddings", Dimension: 768}, {Table: "summary_embeddings", Dimension: 1536}},
+			wantErr:    ErrEmbeddingDimensionMismatch,
+			wantMsg:    "summary_embeddings.vector は VECTOR(1536)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateEmbeddingModel(tt.registered, tt.model, tt.dimension, tt.columns)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Contains(t, err.Error(), tt.wantMsg)
+			if tt.wantErr == ErrEmbeddingDimensionMismatch {
+				assert.Contains(t, err.Error(), "dev-rag index reembed")
+			}
+		})
+	}
+}
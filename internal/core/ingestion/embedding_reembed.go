@@ -0,0 +1,57 @@
+package ingestion
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// ReembedChunks はプロダクト内で Embedding がない、または現在のモデル以外で生成したチャンクの Embedding を再生成する
+// Embedding モデルやベクトルカラムの次元数を変更した後に実行する（index reembed）
+// 生成できなかったチャンクは失敗として記録し、再度実行した場合も再生成の対象となる
+func (s *IndexService) ReembedChunks(ctx context.Context, productID uuid.UUID) (*RetryEmbeddingsResult, error) {
+	chunks, err := s.repository.ListChunksForReembed(ctx, productID, s.embedder.ModelName())
+	if err != nil {
+		return nil, fmt.Errorf("Embeddingを再生成するチャンクの取得に失敗: %w", err)
+	}
+
+	result := &RetryEmbeddingsResult{Total: len(chunks)}
+	if len(chunks) == 0 {
+		return result, nil
+	}
+
+	embedder := newBatchEmbedder(s.embedder, s.pipelineConfig, s.logger)
+	batchSize := max(min(s.pipelineConfig.EmbeddingBatchSize, s.embedder.MaxBatchSize()), MinBatchSize)
+	for start := 0; start < len(chunks); start += batchSize {
+		batch := chunks[start:min(start+batchSize, len(chunks))]
+
+		outcome, err := embedder.embed(ctx, batch)
+		if err != nil {
+			return result, fmt.Errorf("Embedding生成に失敗: %w", err)
+		}
+		if err := s.repository.BatchUpsertEmbeddings(ctx, outcome.Embeddings); err != nil {
+			return result, fmt.Errorf("Embeddingの保存に失敗: %w", err)
+		}
+		if err := s.repository.RecordEmbeddingFailures(ctx, outcome.Failures); err != nil {
+			return result, fmt.Errorf("Embedding生成の失敗の記録に失敗: %w", err)
+		}
+
+		result.Succeeded += len(outcome.Embeddings)
+		result.Failed += len(outcome.Failures)
+		s.logger.Info("Embeddingを再生成中",
+			"productID", productID,
+			"processed", start+len(batch),
+			"total", result.Total,
+		)
+	}
+
+	s.logger.Info("Embeddingの再生成が完了",
+		"productID", productID,
+		"model", s.embedder.ModelName(),
+		"total", result.Total,
+		"succeeded", result.Succeeded,
+		"failed", result.Failed,
+	)
+	return result, nil
+}
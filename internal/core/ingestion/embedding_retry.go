@@ -0,0 +1,259 @@
+package ingestion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jinford/dev-rag/internal/core/usage"
+)
+
+// errEmbeddingMismatch は入力と異なる数のベクトルが返されたことを表す
+var errEmbeddingMismatch = errors.New("Embeddingベクトル数が入力と一致しません")
+
+// EmbeddingFailure は Embedding を生成できなかったチャンク（index retry-embeddings で再試行する）
+type EmbeddingFailure struct {
+	ChunkID      uuid.UUID `json:"chunkID"`
+	Content      string    `json:"-"`                    // 再試行に使用するチャンクの本文（記録時は不要）
+	FilePath     string    `json:"filePath,omitempty"`   // チャンクのファイルのパス（記録時は不要）
+	SourceName   string    `json:"sourceName,omitempty"` // チャンクのソース名（記録時は不要）
+	Error        string    `json:"error"`
+	Attempts     int       `json:"attempts"` // 失敗として記録された回数
+	LastFailedAt time.Time `json:"lastFailedAt"`
+}
+
+// RetryEmbeddingsResult は失敗した Embedding の再試行の結果
+type RetryEmbeddingsResult struct {
+	Total     int // 再試行したチャンク数
+	Succeeded int // Embedding を生成できたチャンク数
+	Failed    int // 再び失敗したチャンク数
+}
+
+// RetryFailedEmbeddings はプロダクト内で Embedding を生成できなかったチャンクの Embedding を再生成する
+// 生成できたチャンクは記録を削除し、再び失敗したチャンクは失敗回数を加算して記録を残す
+func (s *IndexService) RetryFailedEmbeddings(ctx context.Context, productID uuid.UUID) (*RetryEmbeddingsResult, error) {
+	failures, err := s.repository.ListEmbeddingFailures(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("Embedding生成に失敗したチャンクの取得に失敗: %w", err)
+	}
+
+	result := &RetryEmbeddingsResult{Total: len(failures)}
+	if len(failures) == 0 {
+		return result, nil
+	}
+
+	embedder := newBatchEmbedder(s.embedder, s.pipelineConfig, s.logger)
+	batchSize := max(min(s.pipelineConfig.EmbeddingBatchSize, s.embedder.MaxBatchSize()), MinBatchSize)
+	for start := 0; start < len(failures); start += batchSize {
+		batch := failures[start:min(start+batchSize, len(failures))]
+		chunks := make([]*Chunk, 0, len(batch))
+		for _, f := range batch {
+			chunks = append(chunks, &Chunk{ID: f.ChunkID, Content: f.Content})
+		}
+
+		outcome, err := embedder.embed(ctx, chunks)
+		if err != nil {
+			return result, fmt.Errorf("Embedding生成に失敗: %w", err)
+		}
+		if err := s.repository.BatchCreateEmbeddings(ctx, outcome.Embeddings); err != nil {
+			return result, fmt.Errorf("Embeddingの保存に失敗: %w", err)
+		}
+
+		succeeded := make([]uuid.UUID, 0, len(outcome.Embeddings))
+		for _, e := range outcome.Embeddings {
+			succeeded = append(succeeded, e.ChunkID)
+		}
+		if err := s.repository.DeleteEmbeddingFailures(ctx, succeeded); err != nil {
+			return result, fmt.Errorf("Embedding生成の失敗の記録の削除に失敗: %w", err)
+		}
+		if err := s.repository.RecordEmbeddingFailures(ctx, outcome.Failures); err != nil {
+			return result, fmt.Errorf("Embedding生成の失敗の記録に失敗: %w", err)
+		}
+
+		result.Succeeded += len(outcome.Embeddings)
+		result.Failed += len(outcome.Failures)
+	}
+
+	s.logger.Info("Embedding生成の再試行が完了",
+		"productID", productID,
+		"total", result.Total,
+		"succeeded", result.Succeeded,
+		"failed", result.Failed,
+	)
+	return result, nil
+}
+
+// embedOutcome は入力不良の切り分けを含むバッチの Embedding 生成の結果
+type embedOutcome struct {
+	Embeddings []*Embedding
+	Failures   []*EmbeddingFailure
+	Mismatches int   // ベクトル数が入力と一致しなかった回数
+	Cause      error // 最初に失敗したチャンクの失敗の原因
+}
+
+// batchEmbedder は Embedding の生成をバックオフ付きで再試行し、失敗し続けるバッチを分割して
+// 不正な入力（長すぎるテキストなど）を含むチャンクだけを失敗として切り分ける
+type batchEmbedder struct {
+	embedder   Embedder
+	maxRetries int
+	backoff    time.Duration
+	maxBackoff time.Duration
+	logger     *slog.Logger
+}
+
+func newBatchEmbedder(embedder Embedder, config *PipelineConfig, logger *slog.Logger) *batchEmbedder {
+	return &batchEmbedder{
+		embedder:   embedder,
+		maxRetries: max(config.EmbeddingMaxRetries, 0),
+		backoff:    config.EmbeddingRetryBackoff,
+		maxBackoff: config.EmbeddingRetryMaxBackoff,
+		logger:     logger,
+	}
+}
+
+// embed はチャンクの Embedding を生成する
+// 予算超過とキャンセル以外のエラーは返さず、Embedding を生成できなかったチャンクを Failures に含める
+func (b *batchEmbedder) embed(ctx context.Context, chunks []*Chunk) (*embedOutcome, error) {
+	outcome := &embedOutcome{}
+	if len(chunks) == 0 {
+		return outcome, nil
+	}
+
+	vectors, err := b.embedWithRetry(ctx, chunks)
+	if err != nil && isFatalEmbeddingError(ctx, err) {
+		return outcome, err
+	}
+	if err == nil {
+		outcome.Embeddings = b.toEmbeddings(chunks, vectors)
+		return outcome, nil
+	}
+
+	if errors.Is(err, errEmbeddingMismatch) {
+		outcome.Mismatches++
+	}
+	if err := b.isolate(ctx, chunks, err, outcome); err != nil {
+		return outcome, err
+	}
+	return outcome, nil
+}
+
+// embedWithRetry はバッチの Embedding 生成を指数バックオフで再試行する
+func (b *batchEmbedder) embedWithRetry(ctx context.Context, chunks []*Chunk) ([][]float32, error) {
+	var lastErr error
+	for attempt := 0; attempt <= b.maxRetries; attempt++ {
+		if attempt > 0 {
+			delay := retryBackoff(attempt-1, b.backoff, b.maxBackoff)
+			b.logger.Warn("バッチEmbedding生成を再試行",
+				"batchSize", len(chunks),
+				"attempt", attempt,
+				"delay", delay,
+				"error", lastErr,
+			)
+
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return nil, ctx.Err()
+			case <-timer.C:
+			}
+		}
+
+		vectors, err := b.embedOnce(ctx, chunks)
+		if err == nil {
+			return vectors, nil
+		}
+		if isFatalEmbeddingError(ctx, err) {
+			return nil, err
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// isolate は失敗したバッチを二分して再度 Embedding を生成し、失敗の原因となったチャンクを特定する
+// 分割後のバッチは一時的な障害ではなく入力起因の失敗を切り分けるためのものなので再試行しない
+func (b *batchEmbedder) isolate(ctx context.Context, chunks []*Chunk, cause error, outcome *embedOutcome) error {
+	if len(chunks) == 1 {
+		b.logger.Warn("チャンクのEmbedding生成に失敗",
+			"chunkID", chunks[0].ID,
+			"error", cause,
+		)
+		if outcome.Cause == nil {
+			outcome.Cause = cause
+		}
+		outcome.Failures = append(outcome.Failures, &EmbeddingFailure{
+			ChunkID: chunks[0].ID,
+			Error:   cause.Error(),
+		})
+		return nil
+	}
+
+	mid := len(chunks) / 2
+	for _, part := range [][]*Chunk{chunks[:mid], chunks[mid:]} {
+		vectors, err := b.embedOnce(ctx, part)
+		if err == nil {
+			outcome.Embeddings = append(outcome.Embeddings, b.toEmbeddings(part, vectors)...)
+			continue
+		}
+		if isFatalEmbeddingError(ctx, err) {
+			return err
+		}
+		if errors.Is(err, errEmbeddingMismatch) {
+			outcome.Mismatches++
+		}
+		if err := b.isolate(ctx, part, err, outcome); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// embedOnce は Embedding を1回生成し、ベクトル数が入力と一致しない場合もエラーとする
+func (b *batchEmbedder) embedOnce(ctx context.Context, chunks []*Chunk) ([][]float32, error) {
+	texts := make([]string, 0, len(chunks))
+	for _, c := range chunks {
+		texts = append(texts, embeddingInput(c))
+	}
+
+	vectors, err := b.embedder.BatchEmbed(ctx, texts)
+	if err != nil {
+		return nil, err
+	}
+	if len(vectors) != len(texts) {
+		return nil, fmt.Errorf("%w (expected=%d, actual=%d)", errEmbeddingMismatch, len(texts), len(vectors))
+	}
+	return vectors, nil
+}
+
+func (b *batchEmbedder) toEmbeddings(chunks []*Chunk, vectors [][]float32) []*Embedding {
+	embeddings := make([]*Embedding, 0, len(chunks))
+	for i, c := range chunks {
+		embeddings = append(embeddings, &Embedding{
+			ChunkID: c.ID,
+			Vector:  vectors[i],
+			Model:   b.embedder.ModelName(),
+		})
+	}
+	return embeddings
+}
+
+// isFatalEmbeddingError は再試行や分割をせずに処理を中断すべきエラー（予算超過・キャンセル）かどうかを判定する
+func isFatalEmbeddingError(ctx context.Context, err error) bool {
+	return errors.Is(err, usage.ErrBudgetExceeded) || ctx.Err() != nil
+}
+
+// retryBackoff は attempt 回目（0始まり）の再試行までの待機時間を返す（base の 2^attempt 倍、上限 maxDelay）
+func retryBackoff(attempt int, base, maxDelay time.Duration) time.Duration {
+	if base <= 0 {
+		return 0
+	}
+	delay := base << min(attempt, 30)
+	if delay <= 0 || (maxDelay > 0 && delay > maxDelay) {
+		return maxDelay
+	}
+	return delay
+}
@@ -0,0 +1,115 @@
+package ingestion
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jinford/dev-rag/internal/core/usage"
+)
+
+// flakyEmbedder は最初の failFirst 回の呼び出しと "poison" を含むテキストを含むバッチで失敗するテスト用の Embedder
+type flakyEmbedder struct {
+	failFirst int
+	err       error
+	calls     int
+}
+
+func (e *flakyEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	vectors, err := e.BatchEmbed(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	return vectors[0], nil
+}
+
+func (e *flakyEmbedder) BatchEmbed(_ context.Context, texts []string) ([][]float32, error) {
+	e.calls++
+	if e.calls <= e.failFirst {
+		return nil, e.err
+	}
+	vectors := make([][]float32, 0, len(texts))
+	for _, text := range texts {
+		if strings.Contains(text, "poison") {
+			return nil, errors.New("invalid input")
+		}
+		vectors = append(vectors, []float32{float32(len(text))})
+	}
+	return vectors, nil
+}
+
+func (e *flakyEmbedder) ModelName() string   { return "fake" }
+func (e *flakyEmbedder) Dimension() int      { return 1 }
+func (e *flakyEmbedder) MaxBatchSize() int   { return 100 }
+func (e *flakyEmbedder) MaxInputTokens() int { return 0 }
+
+func newTestBatchEmbedder(embedder Embedder) *batchEmbedder {
+	config := DefaultPipelineConfig()
+	config.EmbeddingRetryBackoff = time.Millisecond
+	return newBatchEmbedder(embedder, config, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func testChunks(contents ...string) []*Chunk {
+	chunks := make([]*Chunk, 0, len(contents))
+	for _, content := range contents {
+		chunks = append(chunks, &Chunk{ID: uuid.New(), Content: content})
+	}
+	return chunks
+}
+
+func TestBatchEmbedder_RetriesTransientErrors(t *testing.T) {
+	embedder := &flakyEmbedder{failFirst: 2, err: errors.New("503 service unavailable")}
+	chunks := testChunks("a", "bb", "ccc")
+
+	outcome, err := newTestBatchEmbedder(embedder).embed(context.Background(), chunks)
+	require.NoError(t, err)
+
+	assert.Len(t, outcome.Embeddings, 3)
+	assert.Empty(t, outcome.Failures)
+	assert.Equal(t, 3, embedder.calls)
+	assert.Equal(t, chunks[1].ID, outcome.Embeddings[1].ChunkID)
+	assert.Equal(t, "fake", outcome.Embeddings[1].Model)
+}
+
+func TestBatchEmbedder_IsolatesPoisonInputs(t *testing.T) {
+	embedder := &flakyEmbedder{}
+	chunks := testChunks("a", "bb", "poison", "dddd", "eeeee")
+
+	outcome, err := newTestBatchEmbedder(embedder).embed(context.Background(), chunks)
+	require.NoError(t, err)
+
+	// 不正な入力を含むチャンクだけが失敗し、残りのチャンクの Embedding は生成される
+	require.Len(t, outcome.Failures, 1)
+	assert.Equal(t, chunks[2].ID, outcome.Failures[0].ChunkID)
+	assert.Equal(t, "invalid input", outcome.Failures[0].Error)
+	assert.EqualError(t, outcome.Cause, "invalid input")
+
+	ids := make([]uuid.UUID, 0, len(outcome.Embeddings))
+	for _, e := range outcome.Embeddings {
+		ids = append(ids, e.ChunkID)
+	}
+	assert.ElementsMatch(t, []uuid.UUID{chunks[0].ID, chunks[1].ID, chunks[3].ID, chunks[4].ID}, ids)
+}
+
+func TestBatchEmbedder_BudgetExceededIsFatal(t *testing.T) {
+	embedder := &flakyEmbedder{failFirst: 1, err: usage.ErrBudgetExceeded}
+
+	_, err := newTestBatchEmbedder(embedder).embed(context.Background(), testChunks("a", "bb"))
+	require.ErrorIs(t, err, usage.ErrBudgetExceeded)
+	assert.Equal(t, 1, embedder.calls)
+}
+
+func TestRetryBackoff(t *testing.T) {
+	assert.Equal(t, 2*time.Second, retryBackoff(0, 2*time.Second, 30*time.Second))
+	assert.Equal(t, 8*time.Second, retryBackoff(2, 2*time.Second, 30*time.Second))
+	assert.Equal(t, 30*time.Second, retryBackoff(10, 2*time.Second, 30*time.Second))
+	assert.Zero(t, retryBackoff(3, 0, 30*time.Second))
+}
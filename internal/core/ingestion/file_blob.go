@@ -0,0 +1,8 @@
+package ingestion
+
+// FileBlobStats はファイルの内容（file_blobs）の保存状況
+// 内容は content_hash ごとに1回だけ保存するため、スナップショット間で変更のないファイルは容量を消費しない
+type FileBlobStats struct {
+	Count     int   // 保存している内容の数
+	TotalSize int64 // 保存している内容の合計サイズ（バイト）
+}
@@ -0,0 +1,172 @@
+package ingestion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FileFailureStage はファイルのインデックス化に失敗した段階
+type FileFailureStage string
+
+const (
+	FileFailureCreate FileFailureStage = "create" // ファイルの保存
+	FileFailureChunk  FileFailureStage = "chunk"  // チャンク化
+	FileFailureStore  FileFailureStage = "store"  // チャンクの保存
+)
+
+// FileFailure はインデックス化に失敗したファイル（failures retry で再試行する）
+type FileFailure struct {
+	SnapshotID        uuid.UUID        `json:"snapshotID"`
+	ProductName       string           `json:"productName,omitempty"`
+	SourceName        string           `json:"sourceName,omitempty"`
+	VersionIdentifier string           `json:"versionIdentifier,omitempty"`
+	Path              string           `json:"path"`
+	Stage             FileFailureStage `json:"stage"`
+	Error             string           `json:"error"`
+	Attempts          int              `json:"attempts"` // 失敗として記録された回数
+	FirstFailedAt     time.Time        `json:"firstFailedAt"`
+	LastFailedAt      time.Time        `json:"lastFailedAt"`
+	Document          *SourceDocument  `json:"-"` // 再試行に使用するファイルの内容
+}
+
+// FailureReport はプロダクト内で失敗したまま残っているファイルとチャンク
+type FailureReport struct {
+	Files  []*FileFailure      `json:"files"`
+	Chunks []*EmbeddingFailure `json:"chunks"`
+}
+
+// RetryFilesResult は失敗したファイルの再試行の結果
+type RetryFilesResult struct {
+	Total     int // 再試行したファイル数
+	Succeeded int // インデックス化できたファイル数
+	Failed    int // 再び失敗したファイル数
+}
+
+// RetryFailuresResult は失敗したファイルとチャンクの再試行の結果
+type RetryFailuresResult struct {
+	Files  *RetryFilesResult
+	Chunks *RetryEmbeddingsResult
+}
+
+// ListFailures はプロダクト内で失敗したまま残っているファイルとチャンクを返す
+func (s *IndexService) ListFailures(ctx context.Context, productID uuid.UUID) (*FailureReport, error) {
+	files, err := s.repository.ListFileFailures(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("インデックス化に失敗したファイルの取得に失敗: %w", err)
+	}
+	chunks, err := s.repository.ListEmbeddingFailures(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("Embedding生成に失敗したチャンクの取得に失敗: %w", err)
+	}
+	return &FailureReport{Files: files, Chunks: chunks}, nil
+}
+
+// RetryFailures はプロダクト内で失敗したファイルを記録した内容から再度インデックス化し、
+// その後に Embedding を生成できなかったチャンク（再試行したファイルで新たに失敗したものを含む）の Embedding を再生成する
+// 成功したファイルの記録は削除し、再び失敗したファイルは失敗回数を加算して記録を残す
+func (s *IndexService) RetryFailures(ctx context.Context, productID uuid.UUID) (*RetryFailuresResult, error) {
+	failures, err := s.repository.ListFileFailures(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("インデックス化に失敗したファイルの取得に失敗: %w", err)
+	}
+
+	result := &RetryFailuresResult{Files: &RetryFilesResult{Total: len(failures)}}
+	for _, group := range groupFileFailuresBySnapshot(failures) {
+		failed, err := s.retrySnapshotFiles(ctx, group)
+		if err != nil {
+			return result, err
+		}
+		result.Files.Failed += failed
+		result.Files.Succeeded += len(group) - failed
+	}
+
+	chunks, err := s.RetryFailedEmbeddings(ctx, productID)
+	result.Chunks = chunks
+	if err != nil {
+		return result, err
+	}
+
+	s.logger.Info("失敗したファイルの再試行が完了",
+		"productID", productID,
+		"total", result.Files.Total,
+		"succeeded", result.Files.Succeeded,
+		"failed", result.Files.Failed,
+	)
+	return result, nil
+}
+
+// retrySnapshotFiles は同じスナップショットで失敗したファイルを再度インデックス化し、再び失敗したファイル数を返す
+func (s *IndexService) retrySnapshotFiles(ctx context.Context, failures []*FileFailure) (int, error) {
+	snapshotID := failures[0].SnapshotID
+	documents := make([]*SourceDocument, 0, len(failures))
+	for _, f := range failures {
+		documents = append(documents, f.Document)
+	}
+
+	docCtx := indexDocumentContext{
+		ProductName:       failures[0].ProductName,
+		SourceName:        failures[0].SourceName,
+		VersionIdentifier: failures[0].VersionIdentifier,
+	}
+	pipeline := NewIndexPipeline(
+		s.repository,
+		s.embedder,
+		s.chunkerFactory,
+		s.languageDetect,
+		s.tokenCounter,
+		s.pipelineConfig,
+		s.logger,
+	)
+	// 途中まで保存したファイルは、決定的な ID で前回保存したチャンクを置き換えて作り直す
+	pipeline.upsert = true
+	stats, err := pipeline.ProcessDocumentsWithStats(ctx, snapshotID, DocumentsFromSlice(documents), docCtx, func(*SourceDocument) bool { return false })
+	if err != nil {
+		return 0, fmt.Errorf("失敗したファイルの再試行に失敗: %w", err)
+	}
+
+	if _, err := s.repository.DeleteResolvedFileFailures(ctx, snapshotID, stats.FailedPaths); err != nil {
+		return 0, fmt.Errorf("インデックス化に失敗したファイルの記録の削除に失敗: %w", err)
+	}
+	return len(stats.FailedPaths), nil
+}
+
+// groupFileFailuresBySnapshot は失敗したファイルをスナップショットごとにまとめる（最初に現れた順）
+func groupFileFailuresBySnapshot(failures []*FileFailure) [][]*FileFailure {
+	var groups [][]*FileFailure
+	index := make(map[uuid.UUID]int)
+	for _, f := range failures {
+		i, ok := index[f.SnapshotID]
+		if !ok {
+			i = len(groups)
+			index[f.SnapshotID] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], f)
+	}
+	return groups
+}
+
+// recordFileFailure はインデックス化に失敗したファイルを記録する（記録に失敗してもインデックス化は継続）
+// キャンセルによる失敗はファイルの問題ではないため記録しない
+func (p *IndexPipeline) recordFileFailure(ctx context.Context, snapshotID uuid.UUID, doc *SourceDocument, stage FileFailureStage, cause error) {
+	if ctx.Err() != nil || errors.Is(cause, context.Canceled) {
+		return
+	}
+	err := p.repository.RecordFileFailure(ctx, &FileFailure{
+		SnapshotID: snapshotID,
+		Path:       doc.Path,
+		Stage:      stage,
+		Error:      cause.Error(),
+		Document:   doc,
+	})
+	if err != nil {
+		p.logger.Warn("インデックス化に失敗したファイルの記録に失敗",
+			"path", doc.Path,
+			"error", err,
+		)
+	}
+}
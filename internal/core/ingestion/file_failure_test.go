@@ -0,0 +1,28 @@
+package ingestion
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupFileFailuresBySnapshot(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+	failures := []*FileFailure{
+		{SnapshotID: a, Path: "a/1.go"},
+		{SnapshotID: b, Path: "b/1.go"},
+		{SnapshotID: a, Path: "a/2.go"},
+	}
+
+	groups := groupFileFailuresBySnapshot(failures)
+	require.Len(t, groups, 2)
+
+	assert.Equal(t, []*FileFailure{failures[0], failures[2]}, groups[0])
+	assert.Equal(t, []*FileFailure{failures[1]}, groups[1])
+}
+
+func TestGroupFileFailuresBySnapshot_Empty(t *testing.T) {
+	assert.Empty(t, groupFileFailuresBySnapshot(nil))
+}
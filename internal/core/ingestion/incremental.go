@@ -0,0 +1,180 @@
+package ingestion
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// ChangeFetcher は基準のバージョンからの変更だけを取得できる SourceProvider が実装するインターフェース
+// 実装している場合、IndexParams.SinceVersion を指定するとソース全体を走査せずにインデックス化できる
+type ChangeFetcher interface {
+	// FetchChanges は基準のバージョンから追加・変更されたドキュメントと削除されたパスを取得する
+	FetchChanges(ctx context.Context, params IndexParams, baseVersion string) (*DocumentChanges, error)
+}
+
+// DocumentChanges は基準のバージョンからのソースの変更を表す
+type DocumentChanges struct {
+	BaseVersion       string           // 基準のバージョン識別子（短縮したコミットハッシュなどを解決したもの）
+	VersionIdentifier string           // 変更後のバージョン識別子
+	Documents         DocumentIterator // 追加・変更されたドキュメント
+	RemovedPaths      []string         // 削除されたパス（名前を変更したファイルの変更前のパスを含む）
+}
+
+// fetchChanges は基準のスナップショットから変更されたドキュメントを取得し、
+// 続けて変更のないファイルを基準のスナップショットから引き継ぐイテレータを返す
+func (s *IndexService) fetchChanges(ctx context.Context, sourceID uuid.UUID, params IndexParams) (DocumentIterator, string, *SourceSnapshot, error) {
+	fetcher, ok := s.sourceProvider.(ChangeFetcher)
+	if !ok {
+		return nil, "", nil, fmt.Errorf("%s ソースは差分インデックス化に対応していません", s.sourceProvider.GetSourceType())
+	}
+
+	changes, err := fetcher.FetchChanges(ctx, params, params.SinceVersion)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("変更されたドキュメントの取得に失敗: %w", err)
+	}
+
+	baseOpt, err := s.repository.GetSnapshotByVersion(ctx, sourceID, changes.BaseVersion)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("基準のスナップショットの取得に失敗: %w", err)
+	}
+	base, ok := baseOpt.Get()
+	if !ok || !base.IsIndexed() {
+		return nil, "", nil, fmt.Errorf("基準のバージョンがインデックス化されていません: %s", changes.BaseVersion)
+	}
+
+	baseFiles, err := s.repository.ListFilesBySnapshot(ctx, base.ID)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("基準のスナップショットのファイルの取得に失敗: %w", err)
+	}
+	// 除外パターンに一致したファイルは files に含まれないため、全ファイル（snapshot_files）から引き継ぐ
+	snapshotFiles, err := s.repository.GetSnapshotFiles(ctx, base.ID)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("基準のスナップショットの全ファイルの取得に失敗: %w", err)
+	}
+	indexed := make(map[string]bool, len(baseFiles))
+	for _, file := range baseFiles {
+		indexed[file.Path] = true
+	}
+	var skippedFiles []*SnapshotFile
+	for _, file := range snapshotFiles {
+		if file.SkipReason != nil && !indexed[file.FilePath] {
+			skippedFiles = append(skippedFiles, file)
+		}
+	}
+
+	s.logger.Info("基準のスナップショットからの変更を取得",
+		"baseSnapshotID", base.ID,
+		"baseVersion", base.VersionIdentifier,
+		"baseFiles", len(baseFiles),
+		"skippedFiles", len(skippedFiles),
+		"removedPaths", len(changes.RemovedPaths),
+	)
+	return s.withUnchangedFiles(ctx, changes, baseFiles, skippedFiles), changes.VersionIdentifier, base, nil
+}
+
+// withUnchangedFiles は変更されたドキュメントに続けて、基準のスナップショットのファイルのうち
+// 変更・削除されていないものを保存済みの内容（file_blobs）と前回記録したコミット情報から返す
+// 変更のないファイルもパイプラインで処理し直し、ファイルに紐づくメタデータ（APIエンドポイント・デプロイ構成など）を新しいスナップショットに作成する
+// 基準のスナップショットで除外したファイル（skippedFiles）は内容が保存されていないため、全ファイルの一覧に記録するためのパスとサイズだけを返す
+func (s *IndexService) withUnchangedFiles(ctx context.Context, changes *DocumentChanges, baseFiles []*File, skippedFiles []*SnapshotFile) DocumentIterator {
+	return func(yield func(*SourceDocument, error) bool) {
+		changed := make(map[string]bool, len(changes.RemovedPaths))
+		for _, path := range changes.RemovedPaths {
+			changed[path] = true
+		}
+		for doc, err := range changes.Documents {
+			if err == nil {
+				changed[doc.Path] = true
+			}
+			if !yield(doc, err) || err != nil {
+				return
+			}
+		}
+
+		for _, file := range baseFiles {
+			if changed[file.Path] {
+				continue
+			}
+			blobOpt, err := s.repository.GetFileBlob(ctx, file.ContentHash)
+			if err != nil {
+				yield(nil, fmt.Errorf("変更のないファイルの内容の取得に失敗: %s: %w", file.Path, err))
+				return
+			}
+			content, ok := blobOpt.Get()
+			if !ok {
+				yield(nil, fmt.Errorf("変更のないファイルの内容が保存されていません（差分インデックス化には PIPELINE_STORE_FILE_BLOBS が必要です）: %s", file.Path))
+				return
+			}
+			doc := &SourceDocument{
+				Path:        file.Path,
+				Content:     string(content),
+				Size:        file.Size,
+				ContentHash: file.ContentHash,
+			}
+			chunks, err := s.repository.ListChunksByFile(ctx, file.ID)
+			if err != nil {
+				yield(nil, fmt.Errorf("変更のないファイルのチャンクの取得に失敗: %s: %w", file.Path, err))
+				return
+			}
+			withChunkCommit(doc, chunks)
+			if !yield(doc, nil) {
+				return
+			}
+		}
+
+		for _, file := range skippedFiles {
+			if changed[file.FilePath] {
+				continue
+			}
+			doc := &SourceDocument{Path: file.FilePath, Size: file.FileSize}
+			// 内容がないため、除外パターンが変わりインデックス化の対象になったファイルは引き継げない
+			if !s.sourceProvider.ShouldIgnore(doc) {
+				yield(nil, fmt.Errorf("基準のスナップショットで除外したファイルが除外されなくなりました（ソース全体をインデックス化してください）: %s", file.FilePath))
+				return
+			}
+			if !yield(doc, nil) {
+				return
+			}
+		}
+	}
+}
+
+// withChunkCommit はファイルのチャンクに記録したコミット情報（コミットハッシュ・最終更新者・最終更新日時）をドキュメントに設定する
+// コミット情報はファイル単位で記録するため、先頭のチャンクから取得する
+func withChunkCommit(doc *SourceDocument, chunks []*Chunk) {
+	if len(chunks) == 0 {
+		return
+	}
+	c := chunks[0]
+	if c.GitCommitHash != nil {
+		doc.CommitHash = *c.GitCommitHash
+	}
+	if c.Author != nil {
+		doc.Author = *c.Author
+	}
+	if c.UpdatedAt != nil {
+		doc.UpdatedAt = *c.UpdatedAt
+	}
+}
+
+// baseEmbeddings は基準のスナップショットの同じパスのファイルのチャンクの Embedding を、内容のハッシュと Embedding の入力ごとに返す関数を作成する
+// パイプラインは内容と Embedding の入力が変わらないチャンクの Embedding を生成せずに再利用する
+func (s *IndexService) baseEmbeddings(baseSnapshotID uuid.UUID) func(ctx context.Context, path string) (map[string][]float32, error) {
+	return func(ctx context.Context, path string) (map[string][]float32, error) {
+		fileOpt, err := s.repository.GetFileByPath(ctx, baseSnapshotID, path)
+		if err != nil {
+			return nil, fmt.Errorf("基準のスナップショットのファイルの取得に失敗: %w", err)
+		}
+		file, ok := fileOpt.Get()
+		if !ok {
+			return nil, nil
+		}
+		oldChunks, err := s.repository.ListChunksByFile(ctx, file.ID)
+		if err != nil {
+			return nil, fmt.Errorf("基準のスナップショットのチャンクの取得に失敗: %w", err)
+		}
+		return s.reusableEmbeddings(ctx, oldChunks)
+	}
+}
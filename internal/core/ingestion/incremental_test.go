@@ -0,0 +1,217 @@
+package ingestion
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeIncrementalRepository は差分インデックス化で参照するスナップショット・ファイル・内容の取得のみを実装する
+type fakeIncrementalRepository struct {
+	Repository
+	snapshots     map[string]*SourceSnapshot
+	files         map[uuid.UUID][]*File
+	snapshotFiles map[uuid.UUID][]*SnapshotFile
+	chunks        map[uuid.UUID][]*Chunk
+	blobs         map[string]string
+}
+
+func (r *fakeIncrementalRepository) GetSnapshotByVersion(ctx context.Context, sourceID uuid.UUID, versionIdentifier string) (mo.Option[*SourceSnapshot], error) {
+	if snapshot, ok := r.snapshots[versionIdentifier]; ok {
+		return mo.Some(snapshot), nil
+	}
+	return mo.None[*SourceSnapshot](), nil
+}
+
+func (r *fakeIncrementalRepository) ListFilesBySnapshot(ctx context.Context, snapshotID uuid.UUID) ([]*File, error) {
+	return r.files[snapshotID], nil
+}
+
+func (r *fakeIncrementalRepository) GetSnapshotFiles(ctx context.Context, snapshotID uuid.UUID) ([]*SnapshotFile, error) {
+	return r.snapshotFiles[snapshotID], nil
+}
+
+func (r *fakeIncrementalRepository) ListChunksByFile(ctx context.Context, fileID uuid.UUID) ([]*Chunk, error) {
+	return r.chunks[fileID], nil
+}
+
+func (r *fakeIncrementalRepository) GetFileBlob(ctx context.Context, contentHash string) (mo.Option[[]byte], error) {
+	if content, ok := r.blobs[contentHash]; ok {
+		return mo.Some([]byte(content)), nil
+	}
+	return mo.None[[]byte](), nil
+}
+
+// fakeChangeFetcher は固定の変更を返す
+type fakeChangeFetcher struct {
+	SourceProvider
+	changes *DocumentChanges
+	ignored []string
+}
+
+func (f *fakeChangeFetcher) GetSourceType() SourceType { return SourceTypeGit }
+
+func (f *fakeChangeFetcher) ShouldIgnore(doc *SourceDocument) bool {
+	return slices.Contains(f.ignored, doc.Path)
+}
+
+func (f *fakeChangeFetcher) FetchChanges(ctx context.Context, params IndexParams, baseVersion string) (*DocumentChanges, error) {
+	return f.changes, nil
+}
+
+func collectPaths(t *testing.T, documents DocumentIterator) []string {
+	t.Helper()
+	var paths []string
+	for doc, err := range documents {
+		require.NoError(t, err)
+		paths = append(paths, doc.Path)
+	}
+	return paths
+}
+
+func TestFetchChanges(t *testing.T) {
+	base := &SourceSnapshot{ID: uuid.New(), VersionIdentifier: "a1b2c3", Status: SnapshotIndexed}
+	repo := &fakeIncrementalRepository{
+		snapshots: map[string]*SourceSnapshot{"a1b2c3": base},
+		files: map[uuid.UUID][]*File{base.ID: {
+			{Path: "README.md", ContentHash: "h-readme", Size: 6},
+			{Path: "legacy.go", ContentHash: "h-legacy"},
+			{Path: "main.go", ContentHash: "h-main-old"},
+			{Path: "old/name.go", ContentHash: "h-name"},
+		}},
+		blobs: map[string]string{"h-readme": "# demo"},
+	}
+	changes := &DocumentChanges{
+		BaseVersion:       "a1b2c3",
+		VersionIdentifier: "d4e5f6",
+		Documents: DocumentsFromSlice([]*SourceDocument{
+			{Path: "main.go", Content: "package main", ContentHash: "h-main-new"},
+			{Path: "new/name.go", Content: "package name", ContentHash: "h-name"},
+		}),
+		RemovedPaths: []string{"legacy.go", "old/name.go"},
+	}
+	s := &IndexService{repository: repo, sourceProvider: &fakeChangeFetcher{changes: changes}, logger: slog.New(slog.DiscardHandler)}
+
+	documents, version, got, err := s.fetchChanges(context.Background(), uuid.New(), IndexParams{SinceVersion: "a1b2c3"})
+	require.NoError(t, err)
+	assert.Equal(t, "d4e5f6", version)
+	assert.Equal(t, base, got)
+
+	// 変更されたファイルに続けて、削除・変更されていないファイルを保存済みの内容から返す
+	var readme *SourceDocument
+	var paths []string
+	for doc, err := range documents {
+		require.NoError(t, err)
+		paths = append(paths, doc.Path)
+		if doc.Path == "README.md" {
+			readme = doc
+		}
+	}
+	assert.Equal(t, []string{"main.go", "new/name.go", "README.md"}, paths)
+	require.NotNil(t, readme)
+	assert.Equal(t, &SourceDocument{Path: "README.md", Content: "# demo", Size: 6, ContentHash: "h-readme"}, readme)
+}
+
+func TestFetchChanges_UnchangedFiles(t *testing.T) {
+	base := &SourceSnapshot{ID: uuid.New(), VersionIdentifier: "a1b2c3", Status: SnapshotIndexed}
+	readme := &File{ID: uuid.New(), Path: "README.md", ContentHash: "h-readme", Size: 6}
+	commit, author, updatedAt := "0a1b2c3", "Hanako", time.Date(2026, 4, 1, 9, 0, 0, 0, time.UTC)
+	ignored := SkipReasonIgnored
+	repo := &fakeIncrementalRepository{
+		snapshots: map[string]*SourceSnapshot{"a1b2c3": base},
+		files:     map[uuid.UUID][]*File{base.ID: {readme}},
+		snapshotFiles: map[uuid.UUID][]*SnapshotFile{base.ID: {
+			{FilePath: "README.md", FileSize: 6, Indexed: true},
+			{FilePath: "CODEOWNERS", FileSize: 24, SkipReason: &ignored},
+			{FilePath: "dist/app.js", FileSize: 512, SkipReason: &ignored},
+		}},
+		chunks: map[uuid.UUID][]*Chunk{readme.ID: {{GitCommitHash: &commit, Author: &author, UpdatedAt: &updatedAt}}},
+		blobs:  map[string]string{"h-readme": "# demo"},
+	}
+	changes := &DocumentChanges{
+		BaseVersion:       "a1b2c3",
+		VersionIdentifier: "d4e5f6",
+		Documents:         DocumentsFromSlice([]*SourceDocument{{Path: "dist/app.js", Size: 640, ContentHash: "h-app-new"}}),
+	}
+	fetcher := &fakeChangeFetcher{changes: changes, ignored: []string{"CODEOWNERS", "dist/app.js"}}
+	s := &IndexService{repository: repo, sourceProvider: fetcher, logger: slog.New(slog.DiscardHandler)}
+
+	documents, _, _, err := s.fetchChanges(context.Background(), uuid.New(), IndexParams{SinceVersion: "a1b2c3"})
+	require.NoError(t, err)
+	var docs []*SourceDocument
+	for doc, err := range documents {
+		require.NoError(t, err)
+		docs = append(docs, doc)
+	}
+	require.Len(t, docs, 3)
+	assert.Equal(t, "dist/app.js", docs[0].Path)
+	assert.Equal(t, &SourceDocument{
+		Path:        "README.md",
+		Content:     "# demo",
+		Size:        6,
+		ContentHash: "h-readme",
+		CommitHash:  commit,
+		Author:      author,
+		UpdatedAt:   updatedAt,
+	}, docs[1], "変更のないファイルは前回記録したコミット情報を引き継ぐ")
+	assert.Equal(t, &SourceDocument{Path: "CODEOWNERS", Size: 24}, docs[2], "除外したファイルも全ファイルの一覧に記録するため引き継ぐ")
+
+	// 除外パターンが変わり、内容のないファイルがインデックス化の対象になった場合は引き継げない
+	fetcher.ignored = []string{"dist/app.js"}
+	changes.Documents = DocumentsFromSlice(nil)
+	documents, _, _, err = s.fetchChanges(context.Background(), uuid.New(), IndexParams{SinceVersion: "a1b2c3"})
+	require.NoError(t, err)
+	var lastErr error
+	for _, err := range documents {
+		lastErr = err
+	}
+	assert.ErrorContains(t, lastErr, "CODEOWNERS")
+}
+
+func TestFetchChanges_BaseNotIndexed(t *testing.T) {
+	pending := &SourceSnapshot{ID: uuid.New(), VersionIdentifier: "a1b2c3", Status: SnapshotIndexing}
+	repo := &fakeIncrementalRepository{snapshots: map[string]*SourceSnapshot{"a1b2c3": pending}}
+	changes := &DocumentChanges{BaseVersion: "a1b2c3", VersionIdentifier: "d4e5f6", Documents: DocumentsFromSlice(nil)}
+	s := &IndexService{repository: repo, sourceProvider: &fakeChangeFetcher{changes: changes}, logger: slog.New(slog.DiscardHandler)}
+
+	_, _, _, err := s.fetchChanges(context.Background(), uuid.New(), IndexParams{SinceVersion: "a1b2c3"})
+	assert.ErrorContains(t, err, "基準のバージョンがインデックス化されていません")
+
+	// 差分の取得に対応していないソース
+	s.sourceProvider = &fakeSourceProvider{}
+	_, _, _, err = s.fetchChanges(context.Background(), uuid.New(), IndexParams{SinceVersion: "a1b2c3"})
+	assert.ErrorContains(t, err, "差分インデックス化に対応していません")
+}
+
+// fakeSourceProvider は ChangeFetcher を実装しない SourceProvider
+type fakeSourceProvider struct {
+	SourceProvider
+}
+
+func (f *fakeSourceProvider) GetSourceType() SourceType { return SourceTypeLocal }
+
+func TestWithUnchangedFiles_MissingContent(t *testing.T) {
+	repo := &fakeIncrementalRepository{}
+	s := &IndexService{repository: repo, logger: slog.New(slog.DiscardHandler)}
+	changes := &DocumentChanges{Documents: DocumentsFromSlice([]*SourceDocument{{Path: "main.go"}})}
+
+	var paths []string
+	var lastErr error
+	for doc, err := range s.withUnchangedFiles(context.Background(), changes, []*File{{Path: "README.md", ContentHash: "h-readme"}}, nil) {
+		if err != nil {
+			lastErr = err
+			break
+		}
+		paths = append(paths, doc.Path)
+	}
+	assert.Equal(t, []string{"main.go"}, paths)
+	assert.ErrorContains(t, lastErr, "PIPELINE_STORE_FILE_BLOBS")
+	assert.Empty(t, collectPaths(t, s.withUnchangedFiles(context.Background(), &DocumentChanges{Documents: DocumentsFromSlice(nil)}, nil, nil)))
+}
@@ -0,0 +1,101 @@
+package ingestion
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+
+	"github.com/jinford/dev-rag/internal/core/usage"
+)
+
+// IndexRunStatus はインデックス化の実行状態
+type IndexRunStatus string
+
+const (
+	IndexRunRunning   IndexRunStatus = "running"   // 実行中（完了を記録できずに中断した実行も含む）
+	IndexRunSucceeded IndexRunStatus = "succeeded" // 成功
+	IndexRunFailed    IndexRunStatus = "failed"    // 失敗
+)
+
+// ErrInvalidIndexRunStatus は未知の実行状態が指定されたことを表す
+var ErrInvalidIndexRunStatus = errors.New("実行状態は running、succeeded、failed のいずれかを指定してください")
+
+// ParseIndexRunStatus は文字列を実行状態に変換する
+func ParseIndexRunStatus(s string) (IndexRunStatus, error) {
+	switch status := IndexRunStatus(s); status {
+	case IndexRunRunning, IndexRunSucceeded, IndexRunFailed:
+		return status, nil
+	}
+	return "", ErrInvalidIndexRunStatus
+}
+
+// IndexRun はインデックス化の1回の実行の記録
+type IndexRun struct {
+	ID                uuid.UUID      `json:"id"`
+	ProductID         uuid.UUID      `json:"productID"`
+	ProductName       string         `json:"productName"`
+	SourceID          uuid.UUID      `json:"sourceID"`
+	SourceName        string         `json:"sourceName"`
+	SnapshotID        *uuid.UUID     `json:"snapshotID,omitempty"`
+	Ref               string         `json:"ref,omitempty"`
+	VersionIdentifier string         `json:"versionIdentifier,omitempty"`
+	Status            IndexRunStatus `json:"status"`
+	ForceInit         bool           `json:"forceInit"`
+	FilesProcessed    int            `json:"filesProcessed"`
+	Chunks            int            `json:"chunks"`
+	FailedFiles       int            `json:"failedFiles"`
+	FailedEmbeddings  int            `json:"failedEmbeddings"`
+	InputTokens       int64          `json:"inputTokens"`
+	OutputTokens      int64          `json:"outputTokens"`
+	CostUSD           float64        `json:"costUSD"`
+	Error             string         `json:"error,omitempty"`
+	StartedAt         time.Time      `json:"startedAt"`
+	FinishedAt        *time.Time     `json:"finishedAt,omitempty"`
+	DurationMs        *int64         `json:"durationMs,omitempty"`
+}
+
+// IndexRunResult はインデックス化の実行の結果（完了時に記録する内容）
+type IndexRunResult struct {
+	Status            IndexRunStatus
+	SnapshotID        uuid.UUID // スナップショットを作成する前に失敗した場合は uuid.Nil
+	Ref               string
+	VersionIdentifier string
+	FilesProcessed    int
+	Chunks            int
+	FailedFiles       int
+	FailedEmbeddings  int
+	InputTokens       int64
+	OutputTokens      int64
+	CostUSD           float64
+	Error             string
+}
+
+// addUsage は使用量の記録をトークン数と推定コストに加算する
+func (r *IndexRunResult) addUsage(records []usage.Record) {
+	for _, rec := range records {
+		r.InputTokens += int64(rec.InputTokens)
+		r.OutputTokens += int64(rec.OutputTokens)
+		r.CostUSD += rec.CostUSD
+	}
+}
+
+// IndexRunFilter は実行履歴の絞り込み条件
+type IndexRunFilter struct {
+	ProductID  uuid.UUID      // uuid.Nil の場合はすべてのプロダクト
+	SourceName string         // 空の場合はすべてのソース
+	Status     IndexRunStatus // 空の場合はすべての実行状態
+	Limit      int
+}
+
+// IndexRunRepository はインデックス化の実行履歴のデータアクセスインターフェース
+type IndexRunRepository interface {
+	// StartIndexRun は実行中の実行を記録し、その ID を返す
+	StartIndexRun(ctx context.Context, productID, sourceID uuid.UUID, ref string, forceInit bool) (uuid.UUID, error)
+	FinishIndexRun(ctx context.Context, runID uuid.UUID, result IndexRunResult) error
+	GetIndexRun(ctx context.Context, runID uuid.UUID) (mo.Option[*IndexRun], error)
+	// ListIndexRuns は開始日時の新しい順に実行履歴を返す
+	ListIndexRuns(ctx context.Context, filter IndexRunFilter) ([]*IndexRun, error)
+}
@@ -0,0 +1,32 @@
+package ingestion
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jinford/dev-rag/internal/core/usage"
+)
+
+func TestParseIndexRunStatus(t *testing.T) {
+	status, err := ParseIndexRunStatus("failed")
+	require.NoError(t, err)
+	assert.Equal(t, IndexRunFailed, status)
+
+	_, err = ParseIndexRunStatus("done")
+	assert.ErrorIs(t, err, ErrInvalidIndexRunStatus)
+}
+
+func TestIndexRunResultAddUsage(t *testing.T) {
+	var result IndexRunResult
+	result.addUsage([]usage.Record{
+		{Kind: usage.KindEmbedding, Model: "text-embedding-3-small", InputTokens: 1200, CostUSD: 0.5},
+		{Kind: usage.KindSummary, Model: "gpt-4o-mini", InputTokens: 300, OutputTokens: 40, CostUSD: 0.25},
+	})
+	result.addUsage(nil)
+
+	assert.Equal(t, int64(1500), result.InputTokens)
+	assert.Equal(t, int64(40), result.OutputTokens)
+	assert.InDelta(t, 0.75, result.CostUSD, 1e-9)
+}
@@ -17,6 +17,9 @@ type Product struct {
 	Description *string   `json:"description,omitempty"`
 	CreatedAt   time.Time `json:"createdAt"`
 	UpdatedAt   time.Time `json:"updatedAt"`
+
+	// RecencyWeight は検索スコアに鮮度（チャンクの最終更新日時）を加味する重み（0〜1、nil は加味しない）
+	RecencyWeight *float64 `json:"recencyWeight,omitempty"`
 }
 
 // ProductWithStats はプロダクトと統計情報を含む構造体
@@ -29,6 +32,7 @@ type ProductWithStats struct {
 	SourceCount     int        `json:"sourceCount"`
 	LastIndexedAt   *time.Time `json:"lastIndexedAt,omitempty"`
 	WikiGeneratedAt *time.Time `json:"wikiGeneratedAt,omitempty"`
+	Summary         *string    `json:"summary,omitempty"` // インデックスから生成したプロダクト全体の要約
 }
 
 // === Source ===
@@ -52,6 +56,7 @@ const (
 	SourceTypeConfluence SourceType = "confluence"
 	SourceTypeRedmine    SourceType = "redmine"
 	SourceTypeLocal      SourceType = "local"
+	SourceTypeDiscussion SourceType = "discussion" // GitHub・GitLab の Issue・Pull Request
 )
 
 // SourceMetadata はソースタイプ固有のメタデータを表す
@@ -69,14 +74,69 @@ func (m *SourceMetadata) Scan(value any) error {
 	return json.Unmarshal(b, m)
 }
 
+// SnapshotStatus はスナップショットのインデックス化の状態
+type SnapshotStatus string
+
+const (
+	SnapshotPending  SnapshotStatus = "pending"  // 作成直後でインデックス化を開始していない
+	SnapshotIndexing SnapshotStatus = "indexing" // インデックス化中（プロセスが強制終了した場合はこの状態のまま残る）
+	SnapshotIndexed  SnapshotStatus = "indexed"  // インデックス化が完了
+	SnapshotFailed   SnapshotStatus = "failed"   // インデックス化に失敗・中断（次回の実行で再開する）
+)
+
 // SourceSnapshot はソースの特定バージョン時点のスナップショットを表す
 type SourceSnapshot struct {
-	ID                uuid.UUID  `json:"id"`
-	SourceID          uuid.UUID  `json:"sourceID"`
-	VersionIdentifier string     `json:"versionIdentifier"`
-	Indexed           bool       `json:"indexed"`
-	IndexedAt         *time.Time `json:"indexedAt,omitempty"`
-	CreatedAt         time.Time  `json:"createdAt"`
+	ID                uuid.UUID      `json:"id"`
+	SourceID          uuid.UUID      `json:"sourceID"`
+	VersionIdentifier string         `json:"versionIdentifier"`
+	Status            SnapshotStatus `json:"status"`
+	IndexedAt         *time.Time     `json:"indexedAt,omitempty"`
+	CreatedAt         time.Time      `json:"createdAt"`
+	FailedAt          *time.Time     `json:"failedAt,omitempty"`          // インデックス化に失敗・中断した日時（完了時は nil）
+	FailureReason     string         `json:"failureReason,omitempty"`     // インデックス化に失敗・中断した理由
+	IndexingStartedAt *time.Time     `json:"indexingStartedAt,omitempty"` // 最後にインデックス化を開始した日時
+}
+
+// IsIndexed はインデックス化が完了しているかを返す
+func (s *SourceSnapshot) IsIndexed() bool {
+	return s.Status == SnapshotIndexed
+}
+
+// SnapshotStats はスナップショットに含まれるファイル・チャンク・Embeddingの件数を表す
+type SnapshotStats struct {
+	FileCount      int                    `json:"fileCount"`
+	ChunkCount     int                    `json:"chunkCount"`
+	EmbeddingCount int                    `json:"embeddingCount"`
+	Domains        []*SnapshotDomainStats `json:"domains,omitempty"`
+}
+
+// SnapshotDomainStats はスナップショット内のドメイン別の件数を表す
+type SnapshotDomainStats struct {
+	Domain     string `json:"domain"`
+	FileCount  int    `json:"fileCount"`
+	ChunkCount int    `json:"chunkCount"`
+}
+
+// SnapshotIndexHealth はスナップショットのインデックス品質の診断情報を表す
+type SnapshotIndexHealth struct {
+	FileCount           int                `json:"fileCount"`
+	ChunkCount          int                `json:"chunkCount"`
+	EmbeddingCount      int                `json:"embeddingCount"`
+	AvgTokens           float64            `json:"avgTokens"`
+	MaxTokens           int                `json:"maxTokens"`
+	OrphanChunkCount    int                `json:"orphanChunkCount"`    // Embedding を持たないチャンク数
+	OversizedChunkCount int                `json:"oversizedChunkCount"` // トークン数が上限を超えるチャンク数
+	TokenLimit          int                `json:"tokenLimit"`
+	ByLevel             []*ChunkCountByKey `json:"byLevel"`
+	ByType              []*ChunkCountByKey `json:"byType"`
+	ByDomain            []*ChunkCountByKey `json:"byDomain"`
+	ByEmbeddingModel    []*ChunkCountByKey `json:"byEmbeddingModel"`
+}
+
+// ChunkCountByKey は集計キー（レベル・種類・ドメイン・Embeddingモデル）ごとのチャンク数を表す
+type ChunkCountByKey struct {
+	Key        string `json:"key"`
+	ChunkCount int    `json:"chunkCount"`
 }
 
 // GitRef はGit専用の参照(ブランチ、タグ)を表す
@@ -128,6 +188,10 @@ type Chunk struct {
 	CommentRatio         *float64 `json:"commentRatio,omitempty"`
 	CyclomaticComplexity *int     `json:"cyclomaticComplexity,omitempty"`
 	EmbeddingContext     *string  `json:"embeddingContext,omitempty"`
+	EmbeddingTruncated   bool     `json:"embeddingTruncated,omitempty"` // Embeddingモデルの最大入力トークン数に合わせて EmbeddingContext を切り詰めたか
+	Breadcrumb           *string  `json:"breadcrumb,omitempty"`         // Markdownの見出しの階層（例: Architecture > Deployment > Rollback）
+	PageStart            *int     `json:"pageStart,omitempty"`          // PDF・Office文書の開始ページ（xlsx はシートの番号）
+	PageEnd              *int     `json:"pageEnd,omitempty"`            // PDF・Office文書の終了ページ
 
 	// 階層関係と重要度
 	Level           int      `json:"level"`
@@ -151,6 +215,37 @@ type Chunk struct {
 
 	// 決定的な識別子
 	ChunkKey string `json:"chunkKey"`
+
+	// チャンカーの実装とバージョン（例: go-ast-v1、記録前に作成したチャンクは空）
+	ChunkerVersion string `json:"chunkerVersion,omitempty"`
+}
+
+// ChunkVersion はスナップショットごとのチャンクのバージョン（系譜の1要素）を表す
+type ChunkVersion struct {
+	ChunkID           uuid.UUID  `json:"chunkID"`
+	ChunkKey          string     `json:"chunkKey"`
+	SnapshotID        uuid.UUID  `json:"snapshotID"`
+	VersionIdentifier string     `json:"versionIdentifier"`
+	StartLine         int        `json:"startLine"`
+	EndLine           int        `json:"endLine"`
+	Content           string     `json:"content"`
+	ContentHash       string     `json:"contentHash"`
+	GitCommitHash     *string    `json:"gitCommitHash,omitempty"`
+	Author            *string    `json:"author,omitempty"`
+	UpdatedAt         *time.Time `json:"updatedAt,omitempty"`
+	IndexedAt         time.Time  `json:"indexedAt"`
+	IsLatest          bool       `json:"isLatest"`
+}
+
+// ChunkImportance はチャンクの重要度（importance_score）の内訳を表す
+type ChunkImportance struct {
+	ChunkID      uuid.UUID `json:"chunkID"`
+	Algorithm    string    `json:"algorithm"`
+	GraphScore   float64   `json:"graphScore"`
+	EditScore    float64   `json:"editScore"`
+	FinalScore   float64   `json:"finalScore"`
+	EditCount    int       `json:"editCount"`
+	CalculatedAt time.Time `json:"calculatedAt"`
 }
 
 // ChunkMetadata はチャンク作成時のメタデータを表す
@@ -166,6 +261,10 @@ type ChunkMetadata struct {
 	CommentRatio         *float64
 	CyclomaticComplexity *int
 	EmbeddingContext     *string
+	EmbeddingTruncated   bool
+	Breadcrumb           *string
+	PageStart            *int
+	PageEnd              *int
 	Level                int
 	ImportanceScore      *float64
 	StandardImports      []string
@@ -180,6 +279,7 @@ type ChunkMetadata struct {
 	FileVersion          *string
 	IsLatest             bool
 	ChunkKey             string
+	ChunkerVersion       string
 }
 
 // Embedding はチャンクのEmbeddingベクトルを表す
@@ -190,6 +290,12 @@ type Embedding struct {
 	CreatedAt time.Time `json:"createdAt"`
 }
 
+// EmbeddingInfo はベクトルを除いた Embedding の情報を表す
+type EmbeddingInfo struct {
+	Model     string    `json:"model"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
 // ChunkDependency はチャンク間の依存関係を表す
 type ChunkDependency struct {
 	ID          uuid.UUID `json:"id"`
@@ -214,6 +320,9 @@ type SnapshotFile struct {
 	CreatedAt  time.Time `json:"createdAt"`
 }
 
+// SkipReasonIgnored は除外パターンに一致してインデックスしなかったファイルの理由（snapshot_files.skip_reason）
+const SkipReasonIgnored = "除外パターン"
+
 // DomainCoverage はドメイン別のカバレッジ情報を表す
 type DomainCoverage struct {
 	Domain                  string   `json:"domain"`
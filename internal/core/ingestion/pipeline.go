@@ -3,13 +3,21 @@ package ingestion
 import (
 	"context"
 	"crypto/sha256"
-	"errors"
 	"fmt"
 	"log/slog"
+	"path"
+	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jinford/dev-rag/internal/core/apispec"
+	"github.com/jinford/dev-rag/internal/core/datashape"
+	"github.com/jinford/dev-rag/internal/core/decision"
+	"github.com/jinford/dev-rag/internal/core/discussion"
+	"github.com/jinford/dev-rag/internal/core/docextract"
+	"github.com/jinford/dev-rag/internal/core/infraspec"
 	"github.com/jinford/dev-rag/internal/core/ingestion/chunk"
 )
 
@@ -24,8 +32,39 @@ const (
 	DefaultFailOnEmbeddingError = false
 	// MinBatchSize は最小バッチサイズ（MaxBatchSize()が0を返した場合のフォールバック）
 	MinBatchSize = 1
+	// DefaultEmbeddingMaxRetries はEmbedding生成に失敗したバッチのデフォルト再試行回数
+	DefaultEmbeddingMaxRetries = 3
+	// DefaultEmbeddingRetryBackoff はEmbedding生成の再試行までのデフォルトの基底待機時間
+	DefaultEmbeddingRetryBackoff = 2 * time.Second
+	// DefaultEmbeddingRetryMaxBackoff はEmbedding生成の再試行までのデフォルトの最大待機時間
+	DefaultEmbeddingRetryMaxBackoff = 30 * time.Second
+	// DefaultStructuredDataSummaryThreshold はスキーマの要約でインデックス化する構造化データのデフォルトの最小サイズ（バイト）
+	DefaultStructuredDataSummaryThreshold = 256 * 1024
+	// DefaultStoreFileBlobs はファイルの内容を保存するかのデフォルト値
+	DefaultStoreFileBlobs = true
 )
 
+const (
+	// structuredDataChunkType は構造化データのスキーマの要約のチャンクの種別
+	structuredDataChunkType = "data_schema"
+	// extractedTextContentType は PDF・Office文書から抽出したテキストのチャンク化に使用するコンテンツ種別
+	extractedTextContentType = "text/plain"
+	// structuredDataChunkerVersion は構造化データのスキーマの要約のチャンカーのバージョン
+	structuredDataChunkerVersion = "datashape-v1"
+	// extractedTextChunkerVersion は PDF・Office文書から抽出したテキストのチャンカーのバージョン
+	extractedTextChunkerVersion = "docextract-v1"
+)
+
+// currentChunkerVersions は現在のチャンカーが記録するバージョン（これ以外のチャンクは古いチャンカーで作成したもの）
+var currentChunkerVersions = []string{
+	chunk.ChunkerVersionGoAST,
+	chunk.ChunkerVersionMarkdown,
+	chunk.ChunkerVersionCode,
+	chunk.ChunkerVersionText,
+	structuredDataChunkerVersion,
+	extractedTextChunkerVersion,
+}
+
 // PipelineConfig はパイプライン処理の設定
 type PipelineConfig struct {
 	// ChunkWorkerCount はチャンク分割ワーカー数（CPU バウンド処理用）
@@ -36,27 +75,47 @@ type PipelineConfig struct {
 	EmbeddingBatchSize int
 	// FailOnEmbeddingError はEmbeddingエラー時にパイプラインを停止するかどうか
 	FailOnEmbeddingError bool
+	// EmbeddingMaxRetries はEmbedding生成に失敗したバッチを分割する前に再試行する回数
+	EmbeddingMaxRetries int
+	// EmbeddingRetryBackoff は再試行までの基底待機時間（再試行ごとに2倍）
+	EmbeddingRetryBackoff time.Duration
+	// EmbeddingRetryMaxBackoff は再試行までの最大待機時間
+	EmbeddingRetryMaxBackoff time.Duration
+	// StructuredDataSummaryThreshold はこのサイズ（バイト）以上の CSV・JSON・YAML を生のテキストの代わりに
+	// スキーマの要約でインデックス化する（0以下なら要約しない）
+	StructuredDataSummaryThreshold int64
+	// StoreFileBlobs はファイルの内容を content_hash ごとに保存するかどうか
+	// 保存した内容は chunk show・file show での原文の表示と、リポジトリを再クローンしない再チャンク化に使用する
+	StoreFileBlobs bool
 }
 
 // DefaultPipelineConfig はデフォルトのパイプライン設定を返す
 func DefaultPipelineConfig() *PipelineConfig {
 	return &PipelineConfig{
-		ChunkWorkerCount:     DefaultChunkWorkerCount,
-		EmbeddingWorkerCount: DefaultEmbeddingWorkerCount,
-		EmbeddingBatchSize:   DefaultEmbeddingBatchSize,
-		FailOnEmbeddingError: DefaultFailOnEmbeddingError,
+		ChunkWorkerCount:               DefaultChunkWorkerCount,
+		EmbeddingWorkerCount:           DefaultEmbeddingWorkerCount,
+		EmbeddingBatchSize:             DefaultEmbeddingBatchSize,
+		FailOnEmbeddingError:           DefaultFailOnEmbeddingError,
+		EmbeddingMaxRetries:            DefaultEmbeddingMaxRetries,
+		EmbeddingRetryBackoff:          DefaultEmbeddingRetryBackoff,
+		EmbeddingRetryMaxBackoff:       DefaultEmbeddingRetryMaxBackoff,
+		StructuredDataSummaryThreshold: DefaultStructuredDataSummaryThreshold,
+		StoreFileBlobs:                 DefaultStoreFileBlobs,
 	}
 }
 
 // PipelineStats はパイプライン処理の統計情報
 type PipelineStats struct {
-	ProcessedFiles      int // 正常に処理されたファイル数
-	TotalChunks         int // 正常に作成されたチャンク数
-	ExpectedChunks      int // チャンク化で生成された期待チャンク数
-	FailedFiles         int // 失敗したファイル数
-	FailedChunks        int // CreateChunk失敗数
-	FailedEmbeddings    int // Embedding生成/保存失敗数
-	EmbeddingMismatches int // ベクトル数不一致の回数
+	TargetFiles         int      // 除外ルールを適用した後の対象ファイル数
+	ProcessedFiles      int      // 正常に処理されたファイル数
+	TotalChunks         int      // 正常に作成されたチャンク数
+	ExpectedChunks      int      // チャンク化で生成された期待チャンク数
+	FailedFiles         int      // 失敗したファイル数
+	FailedChunks        int      // CreateChunk失敗数
+	FailedEmbeddings    int      // Embedding生成/保存失敗数
+	EmbeddingMismatches int      // ベクトル数不一致の回数
+	TruncatedChunks     int      // Embeddingの入力を最大入力トークン数に合わせて切り詰めたチャンク数
+	FailedPaths         []string // 失敗したファイルのパス（file_failures に記録したもの）
 }
 
 // documentTask はドキュメント処理タスク
@@ -71,6 +130,7 @@ type fileResult struct {
 	ChunkCount     int // 成功したチャンク数
 	ExpectedChunks int // 期待されたチャンク数
 	FailedChunks   int // 失敗したチャンク数
+	Truncated      int // Embeddingの入力を切り詰めたチャンク数
 	Err            error
 }
 
@@ -80,11 +140,19 @@ type IndexPipeline struct {
 	embedder       Embedder
 	chunkerFactory chunk.ChunkerFactory
 	languageDetect chunk.LanguageDetector
+	tokenCounter   chunk.TokenCounter // Embeddingの入力の切り詰めに使用（nil の場合は切り詰めない）
 	config         *PipelineConfig
 	logger         *slog.Logger
 
 	// 実際に使用するバッチサイズ（Embedder.MaxBatchSize()でクリップ済み）
 	effectiveBatchSize int
+
+	// 登録済みのチャンク・Embedding を置き換えて保存するか（スナップショットの再実行・失敗したファイルの再試行）
+	upsert bool
+
+	// reusableEmbeddings はパスのファイルで再利用できる Embedding を内容のハッシュと Embedding の入力ごとに返す
+	// 差分インデックス化で基準のスナップショットの Embedding を再利用する場合に設定する（nil の場合はすべて生成する）
+	reusableEmbeddings func(ctx context.Context, path string) (map[string][]float32, error)
 }
 
 // NewIndexPipeline は新しいIndexPipelineを作成する
@@ -93,6 +161,7 @@ func NewIndexPipeline(
 	embedder Embedder,
 	chunkerFactory chunk.ChunkerFactory,
 	languageDetect chunk.LanguageDetector,
+	tokenCounter chunk.TokenCounter,
 	config *PipelineConfig,
 	logger *slog.Logger,
 ) *IndexPipeline {
@@ -134,6 +203,7 @@ func NewIndexPipeline(
 		embedder:           embedder,
 		chunkerFactory:     chunkerFactory,
 		languageDetect:     languageDetect,
+		tokenCounter:       tokenCounter,
 		config:             config,
 		logger:             logger,
 		effectiveBatchSize: effectiveBatchSize,
@@ -144,7 +214,7 @@ func NewIndexPipeline(
 func (p *IndexPipeline) ProcessDocuments(
 	ctx context.Context,
 	snapshotID uuid.UUID,
-	documents []*SourceDocument,
+	documents DocumentIterator,
 	docCtx indexDocumentContext,
 	shouldIgnore func(*SourceDocument) bool,
 ) (processedFiles int, totalChunks int, err error) {
@@ -159,35 +229,52 @@ func (p *IndexPipeline) ProcessDocuments(
 func (p *IndexPipeline) ProcessDocumentsWithStats(
 	ctx context.Context,
 	snapshotID uuid.UUID,
-	documents []*SourceDocument,
+	documents DocumentIterator,
 	docCtx indexDocumentContext,
 	shouldIgnore func(*SourceDocument) bool,
 ) (*PipelineStats, error) {
 	// Stage 1: ドキュメントチャネル（入力）
-	docChan := make(chan *documentTask, len(documents))
+	// ドキュメントはソースから読み込んだ順に投入し、チャンク分割ワーカーが処理できる分だけ先読みする
+	docChan := make(chan *documentTask, p.config.ChunkWorkerCount)
 
 	// Stage 2: チャンクチャネル（Embedding生成用）
 	chunkChan := make(chan *Chunk, p.config.EmbeddingWorkerCount*p.effectiveBatchSize)
 
-	// 結果チャネル
-	resultChan := make(chan *fileResult, len(documents))
+	// 結果チャネル（結果は集計ループで逐次受け取る）
+	resultChan := make(chan *fileResult, p.config.ChunkWorkerCount)
 
 	// エラー追跡用
 	var pipelineErr atomic.Value
 	var failedEmbeddings atomic.Int64
 	var embeddingMismatches atomic.Int64
 
+	// 進捗の通知（コンテキストに ProgressReporter が設定されている場合のみ）
+	progress := newProgressCounter(progressReporterFromContext(ctx))
+
+	// 呼び出し元のキャンセル（SIGINT など）はワーカーの停止によるキャンセルと区別して判定する
+	parentCtx := ctx
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	// Stage 1: ドキュメントをチャネルに投入
+	// Stage 1: ソースからドキュメントを読み込み、除外ルールを適用してチャネルに投入
+	// 読み込みに失敗した場合はパイプラインを停止する
+	var targetFiles int
+	feedDone := make(chan struct{})
 	go func() {
+		defer close(feedDone)
 		defer close(docChan)
-		for _, doc := range documents {
+		for doc, err := range documents {
+			if err != nil {
+				pipelineErr.CompareAndSwap(nil, fmt.Errorf("ドキュメントの読み込みに失敗: %w", err))
+				cancel()
+				return
+			}
 			if shouldIgnore(doc) {
 				p.logger.Debug("ドキュメントを除外", "path", doc.Path)
 				continue
 			}
+			targetFiles++
+			progress.discovered(1)
 			select {
 			case docChan <- &documentTask{Document: doc, Context: docCtx}:
 			case <-ctx.Done():
@@ -202,7 +289,7 @@ func (p *IndexPipeline) ProcessDocumentsWithStats(
 	for i := 0; i < p.config.ChunkWorkerCount; i++ {
 		go func() {
 			defer chunkWg.Done()
-			p.chunkWorker(ctx, snapshotID, docChan, chunkChan, resultChan)
+			p.chunkWorker(ctx, snapshotID, docChan, chunkChan, resultChan, progress)
 		}()
 	}
 
@@ -218,7 +305,7 @@ func (p *IndexPipeline) ProcessDocumentsWithStats(
 	for i := 0; i < p.config.EmbeddingWorkerCount; i++ {
 		go func() {
 			defer embeddingWg.Done()
-			p.embeddingWorker(ctx, cancel, chunkChan, &pipelineErr, &failedEmbeddings, &embeddingMismatches)
+			p.embeddingWorker(ctx, cancel, chunkChan, &pipelineErr, &failedEmbeddings, &embeddingMismatches, progress)
 		}()
 	}
 
@@ -237,14 +324,19 @@ func (p *IndexPipeline) ProcessDocumentsWithStats(
 				"error", result.Err,
 			)
 			stats.FailedFiles++
+			stats.FailedPaths = append(stats.FailedPaths, result.FilePath)
 			continue
 		}
 		stats.ProcessedFiles++
 		stats.TotalChunks += result.ChunkCount
 		stats.ExpectedChunks += result.ExpectedChunks
 		stats.FailedChunks += result.FailedChunks
+		stats.TruncatedChunks += result.Truncated
 	}
 
+	// 読み込みを終えてから対象ファイル数を確定する（呼び出し元がイテレータで受け取った内容を参照できるよう、読み込みの完了を待って返す）
+	<-feedDone
+	stats.TargetFiles = targetFiles
 	stats.FailedEmbeddings = int(failedEmbeddings.Load())
 	stats.EmbeddingMismatches = int(embeddingMismatches.Load())
 
@@ -254,6 +346,10 @@ func (p *IndexPipeline) ProcessDocumentsWithStats(
 			return stats, fmt.Errorf("パイプライン処理中に致命的エラー: %w", pipeErr)
 		}
 	}
+	// ワーカーはキャンセルされるとエラーを返さずに終了するため、処理していないファイルを残して中断したことを返す
+	if err := parentCtx.Err(); err != nil {
+		return stats, fmt.Errorf("パイプライン処理が中断されました: %w", err)
+	}
 
 	// 統計情報をログ出力
 	if stats.FailedFiles > 0 || stats.FailedChunks > 0 || stats.FailedEmbeddings > 0 || stats.EmbeddingMismatches > 0 {
@@ -267,7 +363,14 @@ func (p *IndexPipeline) ProcessDocumentsWithStats(
 			"embeddingMismatches", stats.EmbeddingMismatches,
 		)
 	}
+	if stats.TruncatedChunks > 0 {
+		p.logger.Info("Embeddingの入力を最大入力トークン数に合わせて切り詰めたチャンクがあります",
+			"truncatedChunks", stats.TruncatedChunks,
+			"maxInputTokens", p.embedder.MaxInputTokens(),
+		)
+	}
 
+	progress.done()
 	return stats, nil
 }
 
@@ -278,6 +381,7 @@ func (p *IndexPipeline) chunkWorker(
 	docChan <-chan *documentTask,
 	chunkChan chan<- *Chunk,
 	resultChan chan<- *fileResult,
+	progress *progressCounter,
 ) {
 	for task := range docChan {
 		select {
@@ -288,15 +392,19 @@ func (p *IndexPipeline) chunkWorker(
 
 		doc := task.Document
 
-		// 言語を検出
-		language, err := p.languageDetect.DetectLanguage(doc.Path, []byte(doc.Content))
+		// 言語を検出（検出結果はMIMEタイプ、files.language には言語名を記録する）
+		contentType, err := p.languageDetect.DetectLanguage(doc.Path, []byte(doc.Content))
 		if err != nil {
 			p.logger.Debug("言語検出に失敗、デフォルト処理を続行",
 				"path", doc.Path,
 				"error", err,
 			)
-			language = "unknown"
+			contentType = "text/plain"
 		}
+		language := LanguageFromContentType(contentType)
+
+		// ドメインを判定（ask/search のドメイン絞り込みに使用）
+		domain := ClassifyDomain(doc.Path)
 
 		// ファイルを作成
 		file, err := p.repository.CreateFile(
@@ -304,16 +412,18 @@ func (p *IndexPipeline) chunkWorker(
 			snapshotID,
 			doc.Path,
 			doc.Size,
-			"text/plain",
+			contentType,
 			doc.ContentHash,
 			&language,
-			nil,
+			&domain,
 		)
 		if err != nil {
 			p.logger.Warn("ファイルの作成に失敗",
 				"path", doc.Path,
 				"error", err,
 			)
+			p.recordFileFailure(ctx, snapshotID, doc, FileFailureCreate, err)
+			progress.fileFailed()
 			select {
 			case resultChan <- &fileResult{FilePath: doc.Path, Err: err}:
 			case <-ctx.Done():
@@ -321,27 +431,80 @@ func (p *IndexPipeline) chunkWorker(
 			continue
 		}
 
-		// チャンカーを取得
-		chunker, err := p.chunkerFactory.GetChunker(language)
-		if err != nil {
-			p.logger.Warn("チャンカーの取得に失敗",
-				"path", doc.Path,
-				"error", err,
-			)
-			select {
-			case resultChan <- &fileResult{FilePath: doc.Path, Err: err}:
-			case <-ctx.Done():
+		// ファイルの内容を保存（同じ内容は1回だけ保存する。失敗してもインデックス化は継続）
+		// 参照する files の行を作成した後に保存し、db gc-blobs で削除されないようにする
+		if p.config.StoreFileBlobs {
+			if err := p.repository.SaveFileBlob(ctx, doc.ContentHash, []byte(doc.Content)); err != nil {
+				p.logger.Warn("ファイルの内容の保存に失敗",
+					"path", doc.Path,
+					"error", err,
+				)
+			}
+		}
+
+		// 設計判断の記録（ADR・RFC）であればメタデータを保存（失敗してもインデックス化は継続）
+		if record, ok := decision.Parse(doc.Path, doc.Content); ok {
+			record.SnapshotID = snapshotID
+			record.FileID = file.ID
+			if err := p.repository.UpsertDecisionRecord(ctx, record); err != nil {
+				p.logger.Warn("設計判断の記録の保存に失敗",
+					"path", doc.Path,
+					"error", err,
+				)
+			}
+		}
+
+		// Issue・Pull Request の議論であれば言及されたファイルパスを保存（失敗してもインデックス化は継続）
+		if record, ok := discussion.Parse(doc.Path, doc.Content); ok {
+			record.SnapshotID = snapshotID
+			record.FileID = file.ID
+			if err := p.repository.UpsertDiscussion(ctx, record); err != nil {
+				p.logger.Warn("議論のメタデータの保存に失敗",
+					"path", doc.Path,
+					"error", err,
+				)
+			}
+		}
+
+		// OpenAPI・Protocol Buffers の定義であればエンドポイントを保存（失敗してもインデックス化は継続）
+		if endpoints, ok := apispec.Parse(doc.Path, doc.Content); ok {
+			for _, ep := range endpoints {
+				ep.SnapshotID = snapshotID
+				ep.FileID = file.ID
+			}
+			if err := p.repository.ReplaceAPIEndpoints(ctx, file.ID, endpoints); err != nil {
+				p.logger.Warn("APIエンドポイントの保存に失敗",
+					"path", doc.Path,
+					"error", err,
+				)
+			}
+		}
+
+		// インフラのドメインの Dockerfile・Docker Compose・Kubernetes マニフェストであればデプロイ構成を保存（失敗してもインデックス化は継続）
+		if domain == DomainInfra {
+			if resources, ok := infraspec.Parse(doc.Path, doc.Content); ok {
+				for _, r := range resources {
+					r.SnapshotID = snapshotID
+					r.FileID = file.ID
+				}
+				if err := p.repository.ReplaceInfraResources(ctx, file.ID, resources); err != nil {
+					p.logger.Warn("デプロイ構成の保存に失敗",
+						"path", doc.Path,
+						"error", err,
+					)
+				}
 			}
-			continue
 		}
 
 		// チャンク化
-		chunkResults, err := chunker.Chunk(ctx, doc.Path, doc.Content)
+		chunkResults, err := p.chunkDocument(ctx, doc, contentType)
 		if err != nil {
 			p.logger.Warn("チャンク化に失敗",
 				"path", doc.Path,
 				"error", err,
 			)
+			p.recordFileFailure(ctx, snapshotID, doc, FileFailureChunk, err)
+			progress.fileFailed()
 			select {
 			case resultChan <- &fileResult{FilePath: doc.Path, Err: err}:
 			case <-ctx.Done():
@@ -352,56 +515,16 @@ func (p *IndexPipeline) chunkWorker(
 		expectedChunks := len(chunkResults)
 		fileChunkCount := 0
 		failedChunkCount := 0
-
-		chunkInputs := make([]*Chunk, 0, len(chunkResults))
-		for i, result := range chunkResults {
-			metadata := convertChunkMetadata(result.Metadata)
-			chunkKey := generateChunkKey(task.Context, doc.Path, result.StartLine, result.EndLine, i)
-			metadata.ChunkKey = chunkKey
-
-			chunkInputs = append(chunkInputs, &Chunk{
-				ID:                   uuid.New(),
-				FileID:               file.ID,
-				Ordinal:              i,
-				StartLine:            result.StartLine,
-				EndLine:              result.EndLine,
-				Content:              result.Content,
-				ContentHash:          computeContentHash(result.Content),
-				TokenCount:           result.Tokens,
-				Type:                 metadata.Type,
-				Name:                 metadata.Name,
-				ParentName:           metadata.ParentName,
-				Signature:            metadata.Signature,
-				DocComment:           metadata.DocComment,
-				Imports:              metadata.Imports,
-				Calls:                metadata.Calls,
-				LinesOfCode:          metadata.LinesOfCode,
-				CommentRatio:         metadata.CommentRatio,
-				CyclomaticComplexity: metadata.CyclomaticComplexity,
-				EmbeddingContext:     metadata.EmbeddingContext,
-				Level:                metadata.Level,
-				ImportanceScore:      metadata.ImportanceScore,
-				StandardImports:      metadata.StandardImports,
-				ExternalImports:      metadata.ExternalImports,
-				InternalCalls:        metadata.InternalCalls,
-				ExternalCalls:        metadata.ExternalCalls,
-				TypeDependencies:     metadata.TypeDependencies,
-				SourceSnapshotID:     metadata.SourceSnapshotID,
-				GitCommitHash:        metadata.GitCommitHash,
-				Author:               metadata.Author,
-				UpdatedAt:            metadata.UpdatedAt,
-				FileVersion:          metadata.FileVersion,
-				IsLatest:             metadata.IsLatest,
-				ChunkKey:             metadata.ChunkKey,
-			})
-		}
+		chunkInputs, truncatedCount := p.buildChunks(file.ID, doc, task.Context, chunkResults)
 
 		// バッチ作成
-		if err := p.repository.BatchCreateChunks(ctx, chunkInputs); err != nil {
+		if err := p.saveChunks(ctx, file.ID, chunkInputs); err != nil {
 			p.logger.Warn("チャンクのバッチ作成に失敗",
 				"path", doc.Path,
 				"error", err,
 			)
+			p.recordFileFailure(ctx, snapshotID, doc, FileFailureStore, err)
+			progress.fileFailed()
 			select {
 			case resultChan <- &fileResult{FilePath: doc.Path, Err: err}:
 			case <-ctx.Done():
@@ -409,8 +532,17 @@ func (p *IndexPipeline) chunkWorker(
 			continue
 		}
 
+		progress.fileChunked(len(chunkInputs))
+
+		// 再利用できる Embedding を保存し、残りのチャンクだけを Embedding 側へ送る
+		pending := chunkInputs
+		if p.reusableEmbeddings != nil {
+			pending = p.saveReusedEmbeddings(ctx, doc.Path, chunkInputs, progress)
+			fileChunkCount += len(chunkInputs) - len(pending)
+		}
+
 		// 生成済み ID をそのまま Embedding 側へ送る
-		for _, ch := range chunkInputs {
+		for _, ch := range pending {
 			select {
 			case chunkChan <- ch:
 			case <-ctx.Done():
@@ -426,6 +558,7 @@ func (p *IndexPipeline) chunkWorker(
 			ChunkCount:     fileChunkCount,
 			ExpectedChunks: expectedChunks,
 			FailedChunks:   failedChunkCount,
+			Truncated:      truncatedCount,
 		}:
 		case <-ctx.Done():
 			return
@@ -441,79 +574,69 @@ func (p *IndexPipeline) embeddingWorker(
 	pipelineErr *atomic.Value,
 	failedEmbeddings *atomic.Int64,
 	embeddingMismatches *atomic.Int64,
+	progress *progressCounter,
 ) {
 	// Chunk のみを保持（テキストは chunk.Content を利用）
 	pendingItems := make([]*Chunk, 0, p.effectiveBatchSize)
+	embedder := newBatchEmbedder(p.embedder, p.config, p.logger)
 
 	processBatch := func() bool {
 		if len(pendingItems) == 0 {
 			return true
 		}
 
-		texts := make([]string, 0, len(pendingItems))
-		for _, it := range pendingItems {
-			texts = append(texts, it.Content)
-		}
-
-		vectors, err := p.embedder.BatchEmbed(ctx, texts)
+		outcome, err := embedder.embed(ctx, pendingItems)
 		if err != nil {
 			p.logger.Error("バッチEmbedding生成に失敗",
-				"batchSize", len(texts),
+				"batchSize", len(pendingItems),
 				"error", err,
 			)
 			failedEmbeddings.Add(int64(len(pendingItems)))
+			progress.embeddingFailed(len(pendingItems))
 
-			if p.config.FailOnEmbeddingError {
-				pipelineErr.Store(fmt.Errorf("embedding生成失敗: %w", err))
-				cancel()
-				return false
-			}
-			pendingItems = pendingItems[:0]
-			return true
+			// 予算超過・キャンセルによる中断は設定に関わらずパイプラインを停止する
+			// 他のワーカーの停止によるキャンセルで最初のエラーを上書きしない
+			pipelineErr.CompareAndSwap(nil, fmt.Errorf("embedding生成失敗: %w", err))
+			cancel()
+			return false
 		}
-
-		if len(vectors) != len(pendingItems) {
-			p.logger.Error("Embeddingベクトル数が不一致",
-				"expected", len(pendingItems),
-				"actual", len(vectors),
-			)
-			embeddingMismatches.Add(1)
-
-			diff := len(vectors) - len(pendingItems)
-			if diff < 0 {
-				diff = -diff
+		embeddingMismatches.Add(int64(outcome.Mismatches))
+
+		// 再試行と分割でも Embedding を生成できなかったチャンクは index retry-embeddings 用に記録する
+		if len(outcome.Failures) > 0 {
+			failedEmbeddings.Add(int64(len(outcome.Failures)))
+			progress.embeddingFailed(len(outcome.Failures))
+			if err := p.repository.RecordEmbeddingFailures(ctx, outcome.Failures); err != nil {
+				p.logger.Warn("Embedding生成に失敗したチャンクの記録に失敗",
+					"count", len(outcome.Failures),
+					"error", err,
+				)
 			}
-			failedEmbeddings.Add(int64(diff))
 
 			if p.config.FailOnEmbeddingError {
-				pipelineErr.Store(errors.New("Embeddingベクトル数が入力と一致しません"))
+				pipelineErr.Store(fmt.Errorf("embedding生成失敗: %d件のチャンクでEmbeddingを生成できません: %w", len(outcome.Failures), outcome.Cause))
 				cancel()
 				return false
 			}
 		}
 
-		limit := min(len(vectors), len(pendingItems))
-		embeddings := make([]*Embedding, 0, limit)
-		for i := range limit {
-			embeddings = append(embeddings, &Embedding{
-				ChunkID: pendingItems[i].ID,
-				Vector:  vectors[i],
-				Model:   p.embedder.ModelName(),
-			})
-		}
-
-		if err := p.repository.BatchCreateEmbeddings(ctx, embeddings); err != nil {
+		embeddings := outcome.Embeddings
+		progress.embedded(len(embeddings))
+		if err := p.saveEmbeddings(ctx, embeddings); err != nil {
 			p.logger.Error("バッチembedding保存に失敗",
 				"count", len(embeddings),
 				"error", err,
 			)
 			failedEmbeddings.Add(int64(len(embeddings)))
+			progress.embeddingFailed(len(embeddings))
 
 			if p.config.FailOnEmbeddingError {
 				pipelineErr.Store(fmt.Errorf("embedding保存失敗: %w", err))
 				cancel()
 				return false
 			}
+		} else {
+			progress.persisted(len(embeddings))
 		}
 
 		pendingItems = pendingItems[:0]
@@ -541,6 +664,245 @@ func (p *IndexPipeline) embeddingWorker(
 	}
 }
 
+// saveChunks はファイルのチャンクを保存する
+// 再実行では同じ chunk_key のチャンクが同じ ID になるため、前回保存したチャンクを置き換え、今回作成しなかったチャンクは削除する
+func (p *IndexPipeline) saveChunks(ctx context.Context, fileID uuid.UUID, chunks []*Chunk) error {
+	if p.upsert {
+		return p.repository.UpsertFileChunks(ctx, fileID, chunks)
+	}
+	return p.repository.BatchCreateChunks(ctx, chunks)
+}
+
+// saveReusedEmbeddings は内容と Embedding の入力が変わらないチャンクに保存済みの Embedding を保存し、Embedding の生成が必要なチャンクを返す
+// 再利用に失敗した場合はすべてのチャンクの Embedding を生成する
+func (p *IndexPipeline) saveReusedEmbeddings(ctx context.Context, path string, chunks []*Chunk, progress *progressCounter) []*Chunk {
+	vectors, err := p.reusableEmbeddings(ctx, path)
+	if err != nil {
+		p.logger.Warn("再利用するEmbeddingの取得に失敗",
+			"path", path,
+			"error", err,
+		)
+		return chunks
+	}
+	if len(vectors) == 0 {
+		return chunks
+	}
+
+	model := p.embedder.ModelName()
+	embeddings := make([]*Embedding, 0, len(chunks))
+	pending := make([]*Chunk, 0, len(chunks))
+	for _, c := range chunks {
+		if vector, ok := vectors[reuseKey(c)]; ok {
+			embeddings = append(embeddings, &Embedding{ChunkID: c.ID, Vector: vector, Model: model})
+			continue
+		}
+		pending = append(pending, c)
+	}
+	if len(embeddings) == 0 {
+		return chunks
+	}
+	if err := p.saveEmbeddings(ctx, embeddings); err != nil {
+		p.logger.Warn("再利用するEmbeddingの保存に失敗",
+			"path", path,
+			"error", err,
+		)
+		return chunks
+	}
+	progress.embedded(len(embeddings))
+	progress.persisted(len(embeddings))
+	return pending
+}
+
+// saveEmbeddings は Embedding を保存する（再実行では前回保存した Embedding を置き換える）
+func (p *IndexPipeline) saveEmbeddings(ctx context.Context, embeddings []*Embedding) error {
+	if p.upsert {
+		return p.repository.BatchUpsertEmbeddings(ctx, embeddings)
+	}
+	return p.repository.BatchCreateEmbeddings(ctx, embeddings)
+}
+
+// buildChunks はチャンク化の結果から、チャンクキーと全メタデータを設定した保存用のチャンクを作成する
+// Embeddingモデルの最大入力トークン数を超える入力は切り詰め、切り詰めたチャンク数を返す
+func (p *IndexPipeline) buildChunks(fileID uuid.UUID, doc *SourceDocument, docCtx indexDocumentContext, chunkResults []*chunk.ChunkResult) ([]*Chunk, int) {
+	truncatedCount := 0
+	maxInputTokens := p.embedder.MaxInputTokens()
+
+	chunkInputs := make([]*Chunk, 0, len(chunkResults))
+	for i, result := range chunkResults {
+		metadata := convertChunkMetadata(result.Metadata)
+		// チャンクの変更履歴（chunk history）の表示に使用するため、ファイルを最後に変更したコミットと作成者を記録する
+		if doc.CommitHash != "" {
+			commitHash := doc.CommitHash
+			metadata.GitCommitHash = &commitHash
+		}
+		if doc.Author != "" {
+			author := doc.Author
+			metadata.Author = &author
+		}
+		// 検索の鮮度の重み付けに使用するため、ファイルの最終更新日時（Git ではファイルの最終コミット、取得できない場合は ref のコミットの日時）を記録する
+		if !doc.UpdatedAt.IsZero() {
+			updatedAt := doc.UpdatedAt
+			metadata.UpdatedAt = &updatedAt
+		}
+		chunkKey := generateChunkKey(docCtx, doc.Path, result.StartLine, result.EndLine, i)
+		metadata.ChunkKey = chunkKey
+
+		// Embeddingモデルの最大入力トークン数を超える入力はバッチ全体の失敗を避けるため事前に切り詰める
+		input := result.Content
+		if metadata.EmbeddingContext != nil {
+			input = *metadata.EmbeddingContext
+		}
+		if fitted, truncated := fitEmbeddingInput(input, metadata.Signature, p.tokenCounter, maxInputTokens); truncated {
+			metadata.EmbeddingContext = &fitted
+			metadata.EmbeddingTruncated = true
+			truncatedCount++
+		}
+
+		chunkInputs = append(chunkInputs, &Chunk{
+			ID:                   chunkIDFromKey(chunkKey),
+			FileID:               fileID,
+			Ordinal:              i,
+			StartLine:            result.StartLine,
+			EndLine:              result.EndLine,
+			Content:              result.Content,
+			ContentHash:          computeContentHash(result.Content),
+			TokenCount:           result.Tokens,
+			Type:                 metadata.Type,
+			Name:                 metadata.Name,
+			ParentName:           metadata.ParentName,
+			Signature:            metadata.Signature,
+			DocComment:           metadata.DocComment,
+			Imports:              metadata.Imports,
+			Calls:                metadata.Calls,
+			LinesOfCode:          metadata.LinesOfCode,
+			CommentRatio:         metadata.CommentRatio,
+			CyclomaticComplexity: metadata.CyclomaticComplexity,
+			EmbeddingContext:     metadata.EmbeddingContext,
+			EmbeddingTruncated:   metadata.EmbeddingTruncated,
+			Breadcrumb:           metadata.Breadcrumb,
+			PageStart:            metadata.PageStart,
+			PageEnd:              metadata.PageEnd,
+			Level:                metadata.Level,
+			ImportanceScore:      metadata.ImportanceScore,
+			StandardImports:      metadata.StandardImports,
+			ExternalImports:      metadata.ExternalImports,
+			InternalCalls:        metadata.InternalCalls,
+			ExternalCalls:        metadata.ExternalCalls,
+			TypeDependencies:     metadata.TypeDependencies,
+			SourceSnapshotID:     metadata.SourceSnapshotID,
+			GitCommitHash:        metadata.GitCommitHash,
+			Author:               metadata.Author,
+			UpdatedAt:            metadata.UpdatedAt,
+			FileVersion:          metadata.FileVersion,
+			IsLatest:             metadata.IsLatest,
+			ChunkKey:             metadata.ChunkKey,
+			ChunkerVersion:       metadata.ChunkerVersion,
+		})
+	}
+	return chunkInputs, truncatedCount
+}
+
+// chunkDocument はドキュメントをチャンク化する
+// 大きな構造化データ（CSV・JSON・YAML）は生のテキストの代わりにスキーマの要約を、
+// PDF・Office文書は抽出したテキストをチャンク化する
+func (p *IndexPipeline) chunkDocument(ctx context.Context, doc *SourceDocument, contentType string) ([]*chunk.ChunkResult, error) {
+	if results, ok := p.summarizeStructuredData(doc); ok {
+		return results, nil
+	}
+	if format, ok := docextract.DetectFormat(doc.Path); ok {
+		return p.chunkExtractedDocument(ctx, doc, format)
+	}
+
+	chunker, err := p.chunkerFactory.GetChunker(contentType)
+	if err != nil {
+		return nil, fmt.Errorf("チャンカーの取得に失敗: %w", err)
+	}
+	return chunker.Chunk(ctx, doc.Path, doc.Content)
+}
+
+// chunkExtractedDocument は PDF・Office文書から抽出したテキストをチャンク化し、各チャンクのページ番号
+// （xlsx はシートの番号とシート名）をメタデータに記録する。チャンクの行番号は抽出したテキストでの行番号になる
+func (p *IndexPipeline) chunkExtractedDocument(ctx context.Context, doc *SourceDocument, format docextract.Format) ([]*chunk.ChunkResult, error) {
+	document, err := docextract.Extract(format, []byte(doc.Content))
+	if err != nil {
+		return nil, fmt.Errorf("テキストの抽出に失敗: %w", err)
+	}
+	if len(document.Pages) == 0 {
+		p.logger.Debug("文書からテキストを抽出できませんでした（画像のみのPDFなど）",
+			"path", doc.Path,
+			"format", format,
+		)
+		return nil, nil
+	}
+
+	chunker, err := p.chunkerFactory.GetChunker(extractedTextContentType)
+	if err != nil {
+		return nil, fmt.Errorf("チャンカーの取得に失敗: %w", err)
+	}
+	results, err := chunker.Chunk(ctx, doc.Path, document.Text())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range results {
+		if r.Metadata == nil {
+			r.Metadata = &chunk.ChunkMetadata{Level: 2}
+		}
+		r.Metadata.ChunkerVersion = extractedTextChunkerVersion
+		if page := document.PageAtLine(r.StartLine); page != nil {
+			r.Metadata.PageStart = &page.Number
+			if page.Name != "" {
+				r.Metadata.Breadcrumb = &page.Name
+			}
+		}
+		if page := document.PageAtLine(r.EndLine); page != nil {
+			r.Metadata.PageEnd = &page.Number
+		}
+	}
+	return results, nil
+}
+
+// summarizeStructuredData は StructuredDataSummaryThreshold 以上の構造化データのファイルを
+// スキーマの要約（キー・型・代表的な値）の1チャンクに置き換える
+// 対象外のファイルや解析できないファイルは false を返し、通常どおりチャンク化する
+func (p *IndexPipeline) summarizeStructuredData(doc *SourceDocument) ([]*chunk.ChunkResult, bool) {
+	threshold := p.config.StructuredDataSummaryThreshold
+	if threshold <= 0 || doc.Size < threshold {
+		return nil, false
+	}
+	summary, ok := datashape.Summarize(doc.Path, doc.Content)
+	if !ok {
+		return nil, false
+	}
+
+	text := summary.Text(doc.Path, doc.Size)
+	tokens := 0
+	if p.tokenCounter != nil {
+		tokens = p.tokenCounter.CountTokens(text)
+	}
+	chunkType := structuredDataChunkType
+	name := path.Base(doc.Path)
+
+	p.logger.Debug("構造化データをスキーマの要約としてインデックス化",
+		"path", doc.Path,
+		"format", summary.Format,
+		"size", doc.Size,
+		"fields", len(summary.Fields),
+	)
+	return []*chunk.ChunkResult{{
+		Content:   text,
+		StartLine: 1,
+		EndLine:   strings.Count(doc.Content, "\n") + 1,
+		Tokens:    tokens,
+		Metadata: &chunk.ChunkMetadata{
+			Type:           &chunkType,
+			Name:           &name,
+			Level:          1,
+			ChunkerVersion: structuredDataChunkerVersion,
+		},
+	}}, true
+}
+
 // convertChunkMetadata は chunk.ChunkMetadata を ingestion.ChunkMetadata に変換する。
 func convertChunkMetadata(meta *chunk.ChunkMetadata) *ChunkMetadata {
 	return &ChunkMetadata{
@@ -555,6 +917,9 @@ func convertChunkMetadata(meta *chunk.ChunkMetadata) *ChunkMetadata {
 		CommentRatio:         meta.CommentRatio,
 		CyclomaticComplexity: meta.CyclomaticComplexity,
 		EmbeddingContext:     meta.EmbeddingContext,
+		Breadcrumb:           meta.Breadcrumb,
+		PageStart:            meta.PageStart,
+		PageEnd:              meta.PageEnd,
 		Level:                meta.Level,
 		ImportanceScore:      meta.ImportanceScore,
 		StandardImports:      meta.StandardImports,
@@ -569,6 +934,7 @@ func convertChunkMetadata(meta *chunk.ChunkMetadata) *ChunkMetadata {
 		FileVersion:          meta.FileVersion,
 		IsLatest:             meta.IsLatest,
 		ChunkKey:             meta.ChunkKey,
+		ChunkerVersion:       meta.ChunkerVersion,
 	}
 }
 
@@ -0,0 +1,66 @@
+package ingestion
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jinford/dev-rag/internal/core/ingestion/chunk"
+	"github.com/jinford/dev-rag/internal/core/search"
+)
+
+// recencyScore は検索クエリ（SearchChunksByProduct など）と同じ式で、類似度を鮮度で重み付けしたスコアを計算する
+func recencyScore(similarity, recencyWeight float64, updatedAt *time.Time, now time.Time) float64 {
+	decay := 0.5 // 最終更新日時がない場合
+	if updatedAt != nil {
+		ageDays := math.Max(now.Sub(*updatedAt).Hours()/24, 0)
+		decay = math.Pow(0.5, ageDays/search.DefaultRecencyHalfLifeDays)
+	}
+	return similarity * (1 - recencyWeight + recencyWeight*decay)
+}
+
+func TestBuildChunks_UpdatedAt(t *testing.T) {
+	pipeline := NewIndexPipeline(nil, &flakyEmbedder{}, nil, nil, nil, nil, nil)
+	docCtx := indexDocumentContext{ProductName: "shop", SourceName: "api", VersionIdentifier: "abc123"}
+	results := []*chunk.ChunkResult{{Content: "func Handle() {}", StartLine: 1, EndLine: 1, Tokens: 4, Metadata: &chunk.ChunkMetadata{}}}
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	newer, _ := pipeline.buildChunks(uuid.New(), &SourceDocument{Path: "new.go", UpdatedAt: now.AddDate(0, 0, -7)}, docCtx, results)
+	older, _ := pipeline.buildChunks(uuid.New(), &SourceDocument{Path: "old.go", UpdatedAt: now.AddDate(-1, 0, 0)}, docCtx, results)
+	unknown, _ := pipeline.buildChunks(uuid.New(), &SourceDocument{Path: "unknown.go"}, docCtx, results)
+
+	require.NotNil(t, newer[0].UpdatedAt)
+	require.NotNil(t, older[0].UpdatedAt)
+	assert.Equal(t, now.AddDate(0, 0, -7), *newer[0].UpdatedAt)
+	assert.Nil(t, unknown[0].UpdatedAt, "最終更新日時がないドキュメントは記録しない")
+
+	// 類似度が同じ場合は新しいチャンクが上位になる
+	assert.Greater(t, recencyScore(0.8, 0.3, newer[0].UpdatedAt, now), recencyScore(0.8, 0.3, older[0].UpdatedAt, now))
+	assert.Greater(t, recencyScore(0.8, 0.3, newer[0].UpdatedAt, now), recencyScore(0.8, 0.3, unknown[0].UpdatedAt, now))
+}
+
+func TestBuildChunks_CommitMetadata(t *testing.T) {
+	pipeline := NewIndexPipeline(nil, &flakyEmbedder{}, nil, nil, nil, nil, nil)
+	docCtx := indexDocumentContext{ProductName: "shop", SourceName: "api", VersionIdentifier: "abc123"}
+	results := []*chunk.ChunkResult{
+		{Content: "func A() {}", StartLine: 1, EndLine: 1, Tokens: 4, Metadata: &chunk.ChunkMetadata{}},
+		{Content: "func B() {}", StartLine: 3, EndLine: 3, Tokens: 4, Metadata: &chunk.ChunkMetadata{}},
+	}
+
+	chunks, _ := pipeline.buildChunks(uuid.New(), &SourceDocument{Path: "handler.go", CommitHash: "def456", Author: "Hanako"}, docCtx, results)
+	require.Len(t, chunks, 2)
+	for _, c := range chunks {
+		require.NotNil(t, c.GitCommitHash)
+		require.NotNil(t, c.Author)
+		assert.Equal(t, "def456", *c.GitCommitHash, "ref のコミットではなくファイルを最後に変更したコミットを記録する")
+		assert.Equal(t, "Hanako", *c.Author)
+	}
+
+	chunks, _ = pipeline.buildChunks(uuid.New(), &SourceDocument{Path: "notes.md"}, docCtx, results)
+	assert.Nil(t, chunks[0].GitCommitHash, "コミット情報がないドキュメントは記録しない")
+	assert.Nil(t, chunks[0].Author)
+}
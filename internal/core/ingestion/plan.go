@@ -0,0 +1,133 @@
+package ingestion
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// IndexPlan はインデックス化を実行した場合の変更内容（ドライランの結果）を表す
+// 変更の有無はソースの直近のインデックス済みスナップショットのファイルの content_hash と比較して判定する
+type IndexPlan struct {
+	SourceName        string    `json:"sourceName"`
+	VersionIdentifier string    `json:"versionIdentifier"`
+	AlreadyIndexed    bool      `json:"alreadyIndexed"`           // 同じバージョンがインデックス済み（インデックス化しても変更なし）
+	BaseSnapshotID    uuid.UUID `json:"baseSnapshotID,omitempty"` // 比較したスナップショット（未インデックスのソースでは uuid.Nil）
+	Documents         int       `json:"documents"`                // 取得したドキュメント数
+	Ignored           int       `json:"ignored"`                  // 除外ルールで対象外としたドキュメント数
+	Added             []string  `json:"added"`
+	Modified          []string  `json:"modified"`
+	Removed           []string  `json:"removed"`
+	Unchanged         int       `json:"unchanged"`
+}
+
+// HasChanges はインデックス化によってファイルが追加・変更・削除されるかを返す
+func (p *IndexPlan) HasChanges() bool {
+	return len(p.Added)+len(p.Modified)+len(p.Removed) > 0
+}
+
+// Plan はソースからドキュメントを取得し、インデックス化を実行した場合の変更内容を返す
+// プロダクト・ソース・スナップショットの作成やチャンク化・Embedding生成は行わない
+func (s *IndexService) Plan(ctx context.Context, params IndexParams) (*IndexPlan, error) {
+	if err := s.validateParams(params); err != nil {
+		return nil, fmt.Errorf("パラメータのバリデーションエラー: %w", err)
+	}
+
+	sourceName := s.sourceProvider.ExtractSourceName(params.Identifier)
+	stream, versionIdentifier, err := s.sourceProvider.FetchDocuments(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("ドキュメントの取得に失敗: %w", err)
+	}
+	// 変更内容の算出にはパスとハッシュのみ使用するため、内容は保持しない
+	var documents []*SourceDocument
+	for doc, err := range stream {
+		if err != nil {
+			return nil, fmt.Errorf("ドキュメントの取得に失敗: %w", err)
+		}
+		header := *doc
+		header.Content = ""
+		documents = append(documents, &header)
+	}
+
+	var baseSnapshotID uuid.UUID
+	var baseHashes map[string]string
+	alreadyIndexed := false
+	sourceOpt, err := s.repository.GetSourceByName(ctx, sourceName)
+	if err != nil {
+		return nil, fmt.Errorf("ソースの取得に失敗: %w", err)
+	}
+	if source, ok := sourceOpt.Get(); ok {
+		existingOpt, err := s.repository.GetSnapshotByVersion(ctx, source.ID, versionIdentifier)
+		if err != nil {
+			return nil, fmt.Errorf("スナップショットの取得に失敗: %w", err)
+		}
+		if existing, ok := existingOpt.Get(); ok && existing.IsIndexed() {
+			alreadyIndexed = true
+		}
+
+		latestOpt, err := s.repository.GetLatestIndexedSnapshot(ctx, source.ID)
+		if err != nil {
+			return nil, fmt.Errorf("最新のスナップショットの取得に失敗: %w", err)
+		}
+		if latest, ok := latestOpt.Get(); ok {
+			baseSnapshotID = latest.ID
+			if baseHashes, err = s.repository.GetFileHashesBySnapshot(ctx, latest.ID); err != nil {
+				return nil, fmt.Errorf("ファイルのハッシュの取得に失敗: %w", err)
+			}
+		}
+	}
+
+	plan := planChanges(documents, s.sourceProvider.ShouldIgnore, baseHashes)
+	plan.SourceName = sourceName
+	plan.VersionIdentifier = versionIdentifier
+	plan.AlreadyIndexed = alreadyIndexed
+	plan.BaseSnapshotID = baseSnapshotID
+
+	s.logger.Info("インデックス化の変更内容を算出",
+		"source", sourceName,
+		"version", versionIdentifier,
+		"alreadyIndexed", alreadyIndexed,
+		"added", len(plan.Added),
+		"modified", len(plan.Modified),
+		"removed", len(plan.Removed),
+	)
+	return plan, nil
+}
+
+// planChanges は除外ルールを適用したドキュメントを前回のファイルの content_hash と比較し、追加・変更・削除されるファイルをパス順に返す
+func planChanges(documents []*SourceDocument, shouldIgnore func(*SourceDocument) bool, baseHashes map[string]string) *IndexPlan {
+	plan := &IndexPlan{
+		Documents: len(documents),
+		Added:     []string{},
+		Modified:  []string{},
+		Removed:   []string{},
+	}
+	seen := make(map[string]struct{}, len(documents))
+	for _, doc := range documents {
+		if shouldIgnore(doc) {
+			plan.Ignored++
+			continue
+		}
+		seen[doc.Path] = struct{}{}
+		hash, ok := baseHashes[doc.Path]
+		switch {
+		case !ok:
+			plan.Added = append(plan.Added, doc.Path)
+		case hash != doc.ContentHash:
+			plan.Modified = append(plan.Modified, doc.Path)
+		default:
+			plan.Unchanged++
+		}
+	}
+	for path := range baseHashes {
+		if _, ok := seen[path]; !ok {
+			plan.Removed = append(plan.Removed, path)
+		}
+	}
+	sort.Strings(plan.Added)
+	sort.Strings(plan.Modified)
+	sort.Strings(plan.Removed)
+	return plan
+}
@@ -0,0 +1,50 @@
+package ingestion
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlanChanges(t *testing.T) {
+	documents := []*SourceDocument{
+		{Path: "same.go", ContentHash: "s"},
+		{Path: "main.go", ContentHash: "m2"},
+		{Path: "handler.go", ContentHash: "h"},
+		{Path: "vendor/lib.go", ContentHash: "v"},
+	}
+	ignoreVendor := func(doc *SourceDocument) bool { return strings.HasPrefix(doc.Path, "vendor/") }
+
+	t.Run("前回のスナップショットと比較する", func(t *testing.T) {
+		base := map[string]string{"same.go": "s", "main.go": "m1", "legacy.go": "l", "vendor/lib.go": "v"}
+
+		plan := planChanges(documents, ignoreVendor, base)
+
+		assert.Equal(t, 4, plan.Documents)
+		assert.Equal(t, 1, plan.Ignored)
+		assert.Equal(t, []string{"handler.go"}, plan.Added)
+		assert.Equal(t, []string{"main.go"}, plan.Modified)
+		// 除外ルールで対象外になったファイルも削除として扱う
+		assert.Equal(t, []string{"legacy.go", "vendor/lib.go"}, plan.Removed)
+		assert.Equal(t, 1, plan.Unchanged)
+		assert.True(t, plan.HasChanges())
+	})
+
+	t.Run("未インデックスのソースは全ファイルを追加とする", func(t *testing.T) {
+		plan := planChanges(documents, ignoreVendor, nil)
+
+		assert.Equal(t, []string{"handler.go", "main.go", "same.go"}, plan.Added)
+		assert.Empty(t, plan.Modified)
+		assert.Empty(t, plan.Removed)
+	})
+
+	t.Run("変更がない", func(t *testing.T) {
+		base := map[string]string{"same.go": "s", "main.go": "m2", "handler.go": "h"}
+
+		plan := planChanges(documents, ignoreVendor, base)
+
+		assert.False(t, plan.HasChanges())
+		assert.Equal(t, 3, plan.Unchanged)
+	})
+}
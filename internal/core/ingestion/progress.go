@@ -0,0 +1,158 @@
+package ingestion
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// ProgressStage はインデックス化の進捗の段階
+type ProgressStage string
+
+const (
+	ProgressFetching ProgressStage = "fetching" // ソースからドキュメントを取得中
+	ProgressIndexing ProgressStage = "indexing" // チャンク化・Embedding生成・保存中
+	ProgressDone     ProgressStage = "done"     // パイプライン処理が完了
+)
+
+// IndexProgress はインデックス化の進捗（ファイルの検出 → チャンク化 → Embedding生成 → 保存）
+type IndexProgress struct {
+	Stage           ProgressStage
+	FilesDiscovered int // 除外ルールを適用した後のインデックス化の対象ファイル数
+	FilesChunked    int // チャンク化・保存が完了したファイル数
+	FilesFailed     int // チャンク化・保存に失敗したファイル数
+	ChunksCreated   int // 保存したチャンク数（Embedding生成の対象）
+	ChunksEmbedded  int // Embeddingを生成したチャンク数
+	ChunksPersisted int // Embeddingを保存したチャンク数
+	ChunksFailed    int // Embeddingの生成・保存に失敗したチャンク数
+}
+
+// FilesDone はチャンク化が完了または失敗したファイル数を返す
+func (p IndexProgress) FilesDone() int {
+	return p.FilesChunked + p.FilesFailed
+}
+
+// ChunksDone はEmbeddingの保存が完了または失敗したチャンク数を返す
+func (p IndexProgress) ChunksDone() int {
+	return p.ChunksPersisted + p.ChunksFailed
+}
+
+// Fraction は進捗の割合（0〜1）を返す
+// チャンクの総数はすべてのファイルをチャンク化するまで確定しないため、チャンク化したファイルの割合と、
+// それに比例させた Embedding の保存の割合を等しく重み付けして推定する
+func (p IndexProgress) Fraction() float64 {
+	if p.Stage == ProgressDone {
+		return 1
+	}
+	if p.FilesDiscovered == 0 {
+		return 0
+	}
+	files := min(float64(p.FilesDone())/float64(p.FilesDiscovered), 1)
+	var chunks float64
+	if p.ChunksCreated > 0 {
+		chunks = min(float64(p.ChunksDone())/float64(p.ChunksCreated), 1) * files
+	}
+	return (files + chunks) / 2
+}
+
+// ProgressReporter はインデックス化の進捗の通知先
+// パイプラインの複数のワーカーから同時に呼び出されるため、スレッドセーフに実装する
+type ProgressReporter interface {
+	ReportProgress(p IndexProgress)
+}
+
+type progressReporterKey struct{}
+
+// WithProgressReporter はコンテキストに ProgressReporter を設定する
+func WithProgressReporter(ctx context.Context, r ProgressReporter) context.Context {
+	return context.WithValue(ctx, progressReporterKey{}, r)
+}
+
+// progressReporterFromContext はコンテキストに設定された ProgressReporter を返す（未設定の場合は nil）
+func progressReporterFromContext(ctx context.Context) ProgressReporter {
+	r, _ := ctx.Value(progressReporterKey{}).(ProgressReporter)
+	return r
+}
+
+// reportStage はコンテキストの ProgressReporter に段階のみの進捗を通知する
+func reportStage(ctx context.Context, stage ProgressStage) {
+	if r := progressReporterFromContext(ctx); r != nil {
+		r.ReportProgress(IndexProgress{Stage: stage})
+	}
+}
+
+// progressCounter はパイプラインの各ステージの処理件数を集計し、変化のたびに ProgressReporter に通知する
+// reporter が nil の場合は何もしない
+type progressCounter struct {
+	reporter ProgressReporter
+
+	filesDiscovered atomic.Int64
+	filesChunked    atomic.Int64
+	filesFailed     atomic.Int64
+	chunksCreated   atomic.Int64
+	chunksEmbedded  atomic.Int64
+	chunksPersisted atomic.Int64
+	chunksFailed    atomic.Int64
+}
+
+func newProgressCounter(reporter ProgressReporter) *progressCounter {
+	return &progressCounter{reporter: reporter}
+}
+
+func (c *progressCounter) discovered(files int) {
+	c.add(&c.filesDiscovered, files)
+}
+
+func (c *progressCounter) fileChunked(chunks int) {
+	if c.reporter == nil {
+		return
+	}
+	c.chunksCreated.Add(int64(chunks))
+	c.add(&c.filesChunked, 1)
+}
+
+func (c *progressCounter) fileFailed() {
+	c.add(&c.filesFailed, 1)
+}
+
+func (c *progressCounter) embedded(chunks int) {
+	c.add(&c.chunksEmbedded, chunks)
+}
+
+func (c *progressCounter) persisted(chunks int) {
+	c.add(&c.chunksPersisted, chunks)
+}
+
+func (c *progressCounter) embeddingFailed(chunks int) {
+	c.add(&c.chunksFailed, chunks)
+}
+
+// done はパイプライン処理の完了を通知する
+func (c *progressCounter) done() {
+	if c.reporter == nil {
+		return
+	}
+	p := c.snapshot()
+	p.Stage = ProgressDone
+	c.reporter.ReportProgress(p)
+}
+
+func (c *progressCounter) add(v *atomic.Int64, n int) {
+	if c.reporter == nil || n == 0 {
+		return
+	}
+	v.Add(int64(n))
+	c.reporter.ReportProgress(c.snapshot())
+}
+
+func (c *progressCounter) snapshot() IndexProgress {
+	return IndexProgress{
+		Stage:           ProgressIndexing,
+		FilesDiscovered: int(c.filesDiscovered.Load()),
+		FilesChunked:    int(c.filesChunked.Load()),
+		FilesFailed:     int(c.filesFailed.Load()),
+		ChunksCreated:   int(c.chunksCreated.Load()),
+		ChunksEmbedded:  int(c.chunksEmbedded.Load()),
+		ChunksPersisted: int(c.chunksPersisted.Load()),
+		ChunksFailed:    int(c.chunksFailed.Load()),
+	}
+}
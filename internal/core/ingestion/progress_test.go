@@ -0,0 +1,77 @@
+package ingestion
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingReporter struct {
+	mu     sync.Mutex
+	events []IndexProgress
+}
+
+func (r *recordingReporter) ReportProgress(p IndexProgress) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, p)
+}
+
+func TestIndexProgressFraction(t *testing.T) {
+	tests := []struct {
+		name     string
+		progress IndexProgress
+		want     float64
+	}{
+		{name: "ファイル未検出", progress: IndexProgress{Stage: ProgressFetching}, want: 0},
+		{name: "チャンク化のみ半分完了", progress: IndexProgress{Stage: ProgressIndexing, FilesDiscovered: 10, FilesChunked: 4, FilesFailed: 1, ChunksCreated: 50}, want: 0.25},
+		{name: "チャンク化完了・Embeddingの保存が半分", progress: IndexProgress{Stage: ProgressIndexing, FilesDiscovered: 10, FilesChunked: 10, ChunksCreated: 100, ChunksPersisted: 40, ChunksFailed: 10}, want: 0.75},
+		{name: "完了", progress: IndexProgress{Stage: ProgressDone}, want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, tt.progress.Fraction(), 1e-9)
+		})
+	}
+}
+
+func TestProgressCounter(t *testing.T) {
+	t.Run("各ステージの件数を通知する", func(t *testing.T) {
+		reporter := &recordingReporter{}
+		counter := newProgressCounter(reporter)
+
+		counter.discovered(3)
+		counter.fileChunked(5)
+		counter.fileFailed()
+		counter.embedded(4)
+		counter.persisted(4)
+		counter.embeddingFailed(1)
+		counter.done()
+
+		require.Len(t, reporter.events, 7)
+		assert.Equal(t, IndexProgress{Stage: ProgressIndexing, FilesDiscovered: 3}, reporter.events[0])
+		assert.Equal(t, IndexProgress{
+			Stage:           ProgressDone,
+			FilesDiscovered: 3,
+			FilesChunked:    1,
+			FilesFailed:     1,
+			ChunksCreated:   5,
+			ChunksEmbedded:  4,
+			ChunksPersisted: 4,
+			ChunksFailed:    1,
+		}, reporter.events[6])
+	})
+
+	t.Run("通知先がない場合は何もしない", func(t *testing.T) {
+		counter := newProgressCounter(nil)
+
+		counter.discovered(3)
+		counter.fileChunked(5)
+		counter.done()
+
+		assert.Equal(t, int64(0), counter.filesDiscovered.Load())
+	})
+}
@@ -2,6 +2,7 @@ package ingestion
 
 import (
 	"context"
+	"iter"
 	"time"
 )
 
@@ -11,6 +12,14 @@ type IndexParams struct {
 	Identifier  string         // ソース識別子（GitならURL、ConfluenceならSpaceKey等）
 	Options     map[string]any // ソースタイプ固有のオプション
 	ForceInit   bool           // 強制初期化（既存データを削除）
+
+	// SinceVersion は差分インデックス化の基準のバージョン（Git ではコミット）
+	// 指定した場合は基準のバージョンから変更されたドキュメントだけを取得し、変更のないファイルは基準のスナップショットから引き継ぐ
+	SinceVersion string
+
+	// Subtree はインデックス化の対象をソース内のディレクトリ（モノレポのサービスなど）に限定する場合のパス
+	// 指定した場合はソース名に「//<パス>」を付けた別のソースとしてインデックス化し、配下のドキュメントだけを取り込む
+	Subtree string
 }
 
 // SourceDocument はソースから取得されたドキュメントを表す
@@ -26,6 +35,35 @@ type SourceDocument struct {
 	UpdatedAt  time.Time // ファイル最終更新日時
 }
 
+// DocumentIterator はソースのドキュメントを1件ずつ返すイテレータ
+// ドキュメントの内容は読み込んだ順に渡し、全件をメモリに保持しない（巨大なモノレポでも使用するメモリを抑える）
+// エラーを返した場合はそこで取得を打ち切る。イテレーションは1回のみ行う
+type DocumentIterator iter.Seq2[*SourceDocument, error]
+
+// DocumentsFromSlice はメモリ上のドキュメントを返す DocumentIterator を作成する
+func DocumentsFromSlice(documents []*SourceDocument) DocumentIterator {
+	return func(yield func(*SourceDocument, error) bool) {
+		for _, doc := range documents {
+			if !yield(doc, nil) {
+				return
+			}
+		}
+	}
+}
+
+// CollectDocuments は DocumentIterator の全ドキュメントを読み込む
+// 全件の内容をメモリに保持するため、テストや件数の少ないソースでのみ使用する
+func CollectDocuments(documents DocumentIterator) ([]*SourceDocument, error) {
+	var collected []*SourceDocument
+	for doc, err := range documents {
+		if err != nil {
+			return nil, err
+		}
+		collected = append(collected, doc)
+	}
+	return collected, nil
+}
+
 // SourceProvider はソースタイプごとの具体的な実装を提供するインターフェース
 // Git、Confluence、Redmine など複数のソースタイプに対応するための拡張ポイント
 type SourceProvider interface {
@@ -35,9 +73,10 @@ type SourceProvider interface {
 	// ExtractSourceName はソース識別子からソース名を抽出する
 	ExtractSourceName(identifier string) string
 
-	// FetchDocuments はソースからドキュメント一覧を取得する
-	// 戻り値: ドキュメント一覧, バージョン識別子, エラー
-	FetchDocuments(ctx context.Context, params IndexParams) ([]*SourceDocument, string, error)
+	// FetchDocuments はソースのバージョン識別子を確定し、ドキュメントを順に読み込むイテレータを返す
+	// ドキュメントの内容はイテレーション中に読み込むため、既にインデックス済みのバージョンでは読み込まずに済む
+	// 戻り値: ドキュメントのイテレータ, バージョン識別子, エラー
+	FetchDocuments(ctx context.Context, params IndexParams) (DocumentIterator, string, error)
 
 	// CreateMetadata はソースメタデータを作成する
 	CreateMetadata(params IndexParams) SourceMetadata
@@ -0,0 +1,41 @@
+package ingestion
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectDocuments(t *testing.T) {
+	documents := []*SourceDocument{{Path: "a.go"}, {Path: "b.go"}}
+	collected, err := CollectDocuments(DocumentsFromSlice(documents))
+	require.NoError(t, err)
+	assert.Equal(t, documents, collected)
+
+	// エラーを返した時点で読み込みを打ち切る
+	readErr := errors.New("read failed")
+	failing := func(yield func(*SourceDocument, error) bool) {
+		if !yield(&SourceDocument{Path: "a.go"}, nil) {
+			return
+		}
+		yield(nil, readErr)
+	}
+	_, err = CollectDocuments(failing)
+	assert.ErrorIs(t, err, readErr)
+}
+
+func TestRetainDocument(t *testing.T) {
+	code := &SourceDocument{Path: "main.go", Content: "package main", Size: 12, ContentHash: "h1"}
+	retained := retainDocument(code)
+	assert.Empty(t, retained.Content)
+	assert.Equal(t, int64(12), retained.Size)
+	assert.Equal(t, "h1", retained.ContentHash)
+	assert.Equal(t, "package main", code.Content, "元のドキュメントは変更しない")
+
+	// 後処理で内容を使用するファイルは内容を残す
+	for _, path := range []string{"go.mod", ".github/CODEOWNERS", "db/schema.sql", "web/package.json", ".gitmodules", "services/api/BUILD.bazel"} {
+		assert.NotEmpty(t, retainDocument(&SourceDocument{Path: path, Content: "x"}).Content, path)
+	}
+}
@@ -0,0 +1,285 @@
+package ingestion
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// RechunkResult は保存済みのファイルの内容からの再チャンク化の結果
+type RechunkResult struct {
+	Files            int // 対象のファイル数
+	Rechunked        int // チャンクを置き換えたファイル数
+	Unchanged        int // チャンクが変わらなかったファイル数
+	MissingContent   int // 内容が保存されていないため再チャンク化できなかったファイル数
+	FailedFiles      int // 再チャンク化に失敗したファイル数
+	Chunks           int // 置き換えたチャンク数
+	ReusedEmbeddings int // 保存済みの Embedding を再利用したチャンク数
+	NewEmbeddings    int // Embedding を生成したチャンク数
+	FailedEmbeddings int // Embedding を生成できなかったチャンク数
+}
+
+// Rechunk はプロダクトの各ソースの最新スナップショットのファイルを、保存済みの内容（file_blobs）から現在のチャンカーで再チャンク化する（index rechunk）
+// チャンクが変わらないファイルはそのまま残し、内容と Embedding の入力が変わらないチャンクは保存済みの Embedding を再利用する
+// ソースを再取得せず、変わったチャンクの Embedding だけを生成するため、チャンカーの改善を安価に反映できる
+// outdatedOnly の場合は、現在のチャンカー以外（バージョンの記録がないものを含む）で作成したチャンクがあるファイルだけを対象にする
+func (s *IndexService) Rechunk(ctx context.Context, productID uuid.UUID, outdatedOnly bool) (*RechunkResult, error) {
+	productOpt, err := s.repository.GetProductByID(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("プロダクトの取得に失敗: %w", err)
+	}
+	if productOpt.IsAbsent() {
+		return nil, fmt.Errorf("プロダクトが見つかりません: %s", productID)
+	}
+	product := productOpt.MustGet()
+
+	sources, err := s.repository.ListSourcesByProductID(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("ソースの取得に失敗: %w", err)
+	}
+
+	pipeline := NewIndexPipeline(
+		s.repository,
+		s.embedder,
+		s.chunkerFactory,
+		s.languageDetect,
+		s.tokenCounter,
+		s.pipelineConfig,
+		s.logger,
+	)
+	embedder := newBatchEmbedder(s.embedder, s.pipelineConfig, s.logger)
+
+	result := &RechunkResult{}
+	for _, source := range sources {
+		snapshotOpt, err := s.repository.GetLatestIndexedSnapshot(ctx, source.ID)
+		if err != nil {
+			return result, fmt.Errorf("最新スナップショットの取得に失敗: %w", err)
+		}
+		if snapshotOpt.IsAbsent() {
+			continue
+		}
+		snapshot := snapshotOpt.MustGet()
+
+		files, err := s.repository.ListFilesBySnapshot(ctx, snapshot.ID)
+		if err != nil {
+			return result, fmt.Errorf("ファイルの取得に失敗: %w", err)
+		}
+		if outdatedOnly {
+			files, err = s.filesWithOutdatedChunker(ctx, snapshot.ID, files)
+			if err != nil {
+				return result, err
+			}
+		}
+
+		docCtx := indexDocumentContext{
+			ProductName:       product.Name,
+			SourceName:        source.Name,
+			VersionIdentifier: snapshot.VersionIdentifier,
+		}
+		rechunked := result.Rechunked
+		for _, file := range files {
+			if err := ctx.Err(); err != nil {
+				return result, err
+			}
+			result.Files++
+			if err := s.rechunkFile(ctx, pipeline, embedder, snapshot.ID, docCtx, file, result); err != nil {
+				s.logger.Warn("再チャンク化に失敗",
+					"path", file.Path,
+					"error", err,
+				)
+				result.FailedFiles++
+			}
+		}
+		if result.Rechunked == rechunked {
+			continue
+		}
+
+		// チャンクのメタデータからシンボルの定義位置を記録し直す
+		s.recordSymbols(ctx, snapshot.ID)
+
+		if err := s.repository.RefreshLatestChunks(ctx, source.ID); err != nil {
+			return result, fmt.Errorf("最新チャンクの更新に失敗: %w", err)
+		}
+	}
+
+	if result.Rechunked > 0 {
+		// チャンクを置き換えると依存関係と重要度も失われるため、プロダクト全体で構築し直す
+		s.linkProductDependencies(ctx, productID)
+		s.recalculateImportance(ctx, productID)
+	}
+
+	s.logger.Info("再チャンク化が完了",
+		"productID", productID,
+		"files", result.Files,
+		"rechunked", result.Rechunked,
+		"unchanged", result.Unchanged,
+		"missingContent", result.MissingContent,
+		"failedFiles", result.FailedFiles,
+		"reusedEmbeddings", result.ReusedEmbeddings,
+		"newEmbeddings", result.NewEmbeddings,
+	)
+	return result, nil
+}
+
+// rechunkFile はファイルを保存済みの内容から再チャンク化し、チャンクが変わった場合はチャンクと Embedding を置き換える
+func (s *IndexService) rechunkFile(
+	ctx context.Context,
+	pipeline *IndexPipeline,
+	embedder *batchEmbedder,
+	snapshotID uuid.UUID,
+	docCtx indexDocumentContext,
+	file *File,
+	result *RechunkResult,
+) error {
+	blobOpt, err := s.repository.GetFileBlob(ctx, file.ContentHash)
+	if err != nil {
+		return fmt.Errorf("ファイルの内容の取得に失敗: %w", err)
+	}
+	if blobOpt.IsAbsent() {
+		result.MissingContent++
+		return nil
+	}
+	oldChunks, err := s.repository.ListChunksByFile(ctx, file.ID)
+	if err != nil {
+		return fmt.Errorf("チャンクの取得に失敗: %w", err)
+	}
+	doc := &SourceDocument{
+		Path:        file.Path,
+		Content:     string(blobOpt.MustGet()),
+		Size:        file.Size,
+		ContentHash: file.ContentHash,
+	}
+	// 再チャンク化したチャンクにも元のチャンクのコミット情報を引き継ぐ
+	withChunkCommit(doc, oldChunks)
+
+	chunkResults, err := pipeline.chunkDocument(ctx, doc, file.ContentType)
+	if err != nil {
+		pipeline.recordFileFailure(ctx, snapshotID, doc, FileFailureChunk, err)
+		return fmt.Errorf("チャンク化に失敗: %w", err)
+	}
+	chunks, _ := pipeline.buildChunks(file.ID, doc, docCtx, chunkResults)
+
+	if sameChunks(oldChunks, chunks) {
+		result.Unchanged++
+		return nil
+	}
+
+	// 内容と Embedding の入力が変わらないチャンクは保存済みの Embedding を再利用する
+	reusable, err := s.reusableEmbeddings(ctx, oldChunks)
+	if err != nil {
+		return err
+	}
+	embeddings := make([]*Embedding, 0, len(chunks))
+	pending := make([]*Chunk, 0, len(chunks))
+	for _, c := range chunks {
+		if vector, ok := reusable[reuseKey(c)]; ok {
+			embeddings = append(embeddings, &Embedding{ChunkID: c.ID, Vector: vector, Model: s.embedder.ModelName()})
+			continue
+		}
+		pending = append(pending, c)
+	}
+	reused := len(embeddings)
+
+	// 古いチャンクを削除する前に Embedding を生成し、生成の中断で古いチャンクが失われないようにする
+	var failures []*EmbeddingFailure
+	batchSize := max(min(s.pipelineConfig.EmbeddingBatchSize, s.embedder.MaxBatchSize()), MinBatchSize)
+	for start := 0; start < len(pending); start += batchSize {
+		outcome, err := embedder.embed(ctx, pending[start:min(start+batchSize, len(pending))])
+		if err != nil {
+			return fmt.Errorf("Embedding生成に失敗: %w", err)
+		}
+		embeddings = append(embeddings, outcome.Embeddings...)
+		failures = append(failures, outcome.Failures...)
+	}
+
+	// 保存の途中で失敗しても古いチャンクが残るように、1つのトランザクションで置き換える
+	if err := s.repository.ReplaceFileChunks(ctx, file.ID, chunks, embeddings, failures); err != nil {
+		err = fmt.Errorf("チャンクの置き換えに失敗: %w", err)
+		pipeline.recordFileFailure(ctx, snapshotID, doc, FileFailureStore, err)
+		return err
+	}
+
+	result.Rechunked++
+	result.Chunks += len(chunks)
+	result.ReusedEmbeddings += reused
+	result.NewEmbeddings += len(embeddings) - reused
+	result.FailedEmbeddings += len(failures)
+	return nil
+}
+
+// filesWithOutdatedChunker はファイルのうち、現在のチャンカー以外で作成したチャンクがあるものを返す
+func (s *IndexService) filesWithOutdatedChunker(ctx context.Context, snapshotID uuid.UUID, files []*File) ([]*File, error) {
+	ids, err := s.repository.ListFileIDsWithOutdatedChunker(ctx, snapshotID, currentChunkerVersions)
+	if err != nil {
+		return nil, fmt.Errorf("古いチャンカーで作成したファイルの取得に失敗: %w", err)
+	}
+
+	outdated := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		outdated[id] = true
+	}
+	filtered := make([]*File, 0, len(ids))
+	for _, file := range files {
+		if outdated[file.ID] {
+			filtered = append(filtered, file)
+		}
+	}
+	return filtered, nil
+}
+
+// reusableEmbeddings は古いチャンクのうち現在のモデルで生成した Embedding があるものを、内容のハッシュと Embedding の入力ごとに返す
+func (s *IndexService) reusableEmbeddings(ctx context.Context, oldChunks []*Chunk) (map[string][]float32, error) {
+	ids := make([]uuid.UUID, 0, len(oldChunks))
+	keys := make(map[uuid.UUID]string, len(oldChunks))
+	for _, c := range oldChunks {
+		ids = append(ids, c.ID)
+		keys[c.ID] = reuseKey(c)
+	}
+
+	embeddings, err := s.repository.ListEmbeddingsByChunkIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("保存済みのEmbeddingの取得に失敗: %w", err)
+	}
+
+	model := s.embedder.ModelName()
+	vectors := make(map[string][]float32, len(embeddings))
+	for _, e := range embeddings {
+		if e.Model != model {
+			continue
+		}
+		vectors[keys[e.ChunkID]] = e.Vector
+	}
+	return vectors, nil
+}
+
+// reuseKey は Embedding を再利用できるチャンクを判定するキー（内容のハッシュと Embedding の入力）を返す
+// 内容が同じでも見出しの階層などで Embedding の入力が変わった場合は再利用しない
+func reuseKey(c *Chunk) string {
+	return c.ContentHash + "\x00" + embeddingInput(c)
+}
+
+// sameChunks は再チャンク化したチャンクが保存済みのチャンクと同じ分割・メタデータかを返す
+// チャンカーのバージョンだけが異なる場合も置き換え、バージョンの記録を更新する（Embedding はすべて再利用する）
+func sameChunks(oldChunks, newChunks []*Chunk) bool {
+	if len(oldChunks) != len(newChunks) {
+		return false
+	}
+	for i, o := range oldChunks {
+		n := newChunks[i]
+		if o.Ordinal != n.Ordinal ||
+			o.StartLine != n.StartLine ||
+			o.EndLine != n.EndLine ||
+			o.Level != n.Level ||
+			o.ChunkerVersion != n.ChunkerVersion ||
+			reuseKey(o) != reuseKey(n) ||
+			derefString(o.Type) != derefString(n.Type) ||
+			derefString(o.Name) != derefString(n.Name) ||
+			derefString(o.ParentName) != derefString(n.ParentName) ||
+			derefString(o.Signature) != derefString(n.Signature) ||
+			derefString(o.Breadcrumb) != derefString(n.Breadcrumb) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,136 @@
+package ingestion
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jinford/dev-rag/internal/core/ingestion/chunk"
+)
+
+// fakeRechunkRepository は再チャンク化で参照する内容・チャンクの取得と、チャンクの置き換えのみを実装する
+// チャンクの削除と保存を別々に呼び出した場合は埋め込んだ Repository が nil のため panic する
+type fakeRechunkRepository struct {
+	Repository
+	blobs      map[string]string
+	chunks     map[uuid.UUID][]*Chunk
+	replaceErr error
+	failures   []*FileFailure
+}
+
+func (r *fakeRechunkRepository) GetFileBlob(ctx context.Context, contentHash string) (mo.Option[[]byte], error) {
+	if content, ok := r.blobs[contentHash]; ok {
+		return mo.Some([]byte(content)), nil
+	}
+	return mo.None[[]byte](), nil
+}
+
+func (r *fakeRechunkRepository) ListChunksByFile(ctx context.Context, fileID uuid.UUID) ([]*Chunk, error) {
+	return r.chunks[fileID], nil
+}
+
+func (r *fakeRechunkRepository) ListEmbeddingsByChunkIDs(ctx context.Context, chunkIDs []uuid.UUID) ([]*Embedding, error) {
+	return nil, nil
+}
+
+// ReplaceFileChunks は1つのトランザクションでの置き換えを模し、失敗した場合は古いチャンクを残す
+func (r *fakeRechunkRepository) ReplaceFileChunks(ctx context.Context, fileID uuid.UUID, chunks []*Chunk, embeddings []*Embedding, failures []*EmbeddingFailure) error {
+	if r.replaceErr != nil {
+		return r.replaceErr
+	}
+	r.chunks[fileID] = chunks
+	return nil
+}
+
+func (r *fakeRechunkRepository) RecordFileFailure(ctx context.Context, failure *FileFailure) error {
+	r.failures = append(r.failures, failure)
+	return nil
+}
+
+// fakeChunkerFactory はファイルの内容を1つのチャンクにするチャンカーを返す
+type fakeChunkerFactory struct{}
+
+func (fakeChunkerFactory) GetChunker(language string) (chunk.Chunker, error) {
+	return fakeChunker{}, nil
+}
+
+type fakeChunker struct{}
+
+func (fakeChunker) Chunk(ctx context.Context, path string, content string) ([]*chunk.ChunkResult, error) {
+	return []*chunk.ChunkResult{{Content: content, StartLine: 1, EndLine: 1, Tokens: 4, Metadata: &chunk.ChunkMetadata{}}}, nil
+}
+
+func TestRechunkFile_ReplaceFailureKeepsOldChunks(t *testing.T) {
+	file := &File{ID: uuid.New(), Path: "handler.go", ContentType: "go", ContentHash: "h1"}
+	oldChunks := []*Chunk{{ID: uuid.New(), FileID: file.ID, StartLine: 1, EndLine: 3, Content: "func Old() {}", ContentHash: "old"}}
+	repo := &fakeRechunkRepository{
+		blobs:      map[string]string{"h1": "func Handle() {}"},
+		chunks:     map[uuid.UUID][]*Chunk{file.ID: oldChunks},
+		replaceErr: errors.New("connection reset"),
+	}
+	logger := slog.New(slog.DiscardHandler)
+	s := &IndexService{repository: repo, embedder: &flakyEmbedder{}, pipelineConfig: DefaultPipelineConfig(), logger: logger}
+	pipeline := NewIndexPipeline(repo, s.embedder, fakeChunkerFactory{}, nil, nil, s.pipelineConfig, logger)
+	docCtx := indexDocumentContext{ProductName: "shop", SourceName: "api", VersionIdentifier: "abc123"}
+	ctx := context.Background()
+
+	result := &RechunkResult{}
+	err := s.rechunkFile(ctx, pipeline, newTestBatchEmbedder(s.embedder), uuid.New(), docCtx, file, result)
+	require.Error(t, err)
+	assert.Equal(t, oldChunks, repo.chunks[file.ID], "置き換えに失敗した場合は古いチャンクが残る")
+	assert.Zero(t, result.Rechunked)
+	require.Len(t, repo.failures, 1)
+	assert.Equal(t, FileFailureStore, repo.failures[0].Stage)
+
+	repo.replaceErr = nil
+	require.NoError(t, s.rechunkFile(ctx, pipeline, newTestBatchEmbedder(s.embedder), uuid.New(), docCtx, file, result))
+	require.Len(t, repo.chunks[file.ID], 1)
+	assert.Equal(t, "func Handle() {}", repo.chunks[file.ID][0].Content)
+	assert.Equal(t, 1, result.Rechunked)
+	assert.Equal(t, 1, result.NewEmbeddings)
+}
+
+func TestSameChunks(t *testing.T) {
+	name := "Handler"
+	newChunk := func() *Chunk {
+		return &Chunk{Ordinal: 0, StartLine: 1, EndLine: 10, Content: "func Handler() {}", ContentHash: "h1", Name: &name, ChunkerVersion: "go-ast-v1"}
+	}
+
+	assert.True(t, sameChunks([]*Chunk{newChunk()}, []*Chunk{newChunk()}))
+	assert.False(t, sameChunks([]*Chunk{newChunk()}, []*Chunk{newChunk(), newChunk()}), "チャンク数が異なる")
+
+	moved := newChunk()
+	moved.EndLine = 12
+	assert.False(t, sameChunks([]*Chunk{newChunk()}, []*Chunk{moved}), "行範囲が異なる")
+
+	renamed := newChunk()
+	other := "Serve"
+	renamed.Name = &other
+	assert.False(t, sameChunks([]*Chunk{newChunk()}, []*Chunk{renamed}), "メタデータが異なる")
+
+	upgraded := newChunk()
+	upgraded.ChunkerVersion = "go-ast-v2"
+	assert.False(t, sameChunks([]*Chunk{newChunk()}, []*Chunk{upgraded}), "チャンカーのバージョンが異なる")
+
+	withContext := newChunk()
+	embeddingContext := "// Package api\nfunc Handler() {}"
+	withContext.EmbeddingContext = &embeddingContext
+	assert.False(t, sameChunks([]*Chunk{newChunk()}, []*Chunk{withContext}), "Embedding の入力が異なる")
+}
+
+func TestReuseKey(t *testing.T) {
+	a := &Chunk{Content: "body", ContentHash: "h1"}
+	b := &Chunk{Content: "body", ContentHash: "h1"}
+	assert.Equal(t, reuseKey(a), reuseKey(b))
+
+	// 内容が同じでも Embedding の入力が変わった場合は再利用しない
+	breadcrumb := "Guide > Setup\n\nbody"
+	b.EmbeddingContext = &breadcrumb
+	assert.NotEqual(t, reuseKey(a), reuseKey(b))
+}
@@ -0,0 +1,173 @@
+package ingestion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrSourceNotFound はソースが見つからない場合のエラー
+	ErrSourceNotFound = errors.New("source not found")
+	// ErrRefNotFound はソースに指定の参照（ブランチ・タグ）が記録されていない場合のエラー
+	ErrRefNotFound = errors.New("ref not found")
+	// ErrSnapshotNotFound は指定のスナップショットが見つからない（または未インデックスの）場合のエラー
+	ErrSnapshotNotFound = errors.New("snapshot not found")
+	// ErrAmbiguousSource はソースを特定できない場合のエラー
+	ErrAmbiguousSource = errors.New("source is ambiguous")
+	// ErrAmbiguousVersion はバージョン識別子の前方一致で複数のスナップショットが該当する場合のエラー
+	ErrAmbiguousVersion = errors.New("version is ambiguous")
+)
+
+// RefResolver はインデックス化対象の参照名を解決できる SourceProvider が実装するインターフェース
+// 実装している場合、IndexService はインデックス化したスナップショットを参照名に紐付ける
+type RefResolver interface {
+	// ResolveRef はパラメータから参照名を返す（省略時はプロバイダーの既定値）
+	ResolveRef(params IndexParams) string
+}
+
+// ResolveRefSnapshot はプロダクト内のソースの参照が指すスナップショットを返す
+// sourceName が空の場合、プロダクトに属するソースが1件のときのみそのソースを対象とする
+func ResolveRefSnapshot(ctx context.Context, repo Repository, productID uuid.UUID, sourceName, ref string) (*GitRef, error) {
+	source, err := resolveProductSource(ctx, repo, productID, sourceName)
+	if err != nil {
+		return nil, err
+	}
+
+	refOpt, err := repo.GetGitRefByName(ctx, source.ID, ref)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ref: %w", err)
+	}
+	gitRef, ok := refOpt.Get()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s@%s", ErrRefNotFound, source.Name, ref)
+	}
+	return gitRef, nil
+}
+
+// ResolveSnapshot はスナップショットID、またはバージョン識別子（コミットハッシュ、前方一致可）から
+// プロダクトに属するインデックス済みのスナップショットを返す
+// バージョン識別子での検索は sourceName が空の場合、プロダクトのソースが1件のときのみ行う
+func ResolveSnapshot(ctx context.Context, repo Repository, productID uuid.UUID, sourceName, idOrVersion string) (*SourceSnapshot, error) {
+	if id, err := uuid.Parse(idOrVersion); err == nil {
+		snapshotOpt, err := repo.GetSnapshotByID(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get snapshot: %w", err)
+		}
+		snapshot, ok := snapshotOpt.Get()
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrSnapshotNotFound, idOrVersion)
+		}
+		sourceOpt, err := repo.GetSourceByID(ctx, snapshot.SourceID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get source: %w", err)
+		}
+		if source, ok := sourceOpt.Get(); !ok || source.ProductID != productID {
+			return nil, fmt.Errorf("%w: %s", ErrSnapshotNotFound, idOrVersion)
+		}
+		return requireIndexed(snapshot)
+	}
+
+	source, err := resolveProductSource(ctx, repo, productID, sourceName)
+	if err != nil {
+		return nil, err
+	}
+	return findSnapshotByVersion(ctx, repo, source, idOrVersion)
+}
+
+// ResolveSourceSnapshot はソースの参照名（ブランチ・タグ）、スナップショットID、またはバージョン識別子
+// （コミットハッシュ、前方一致可）からインデックス済みのスナップショットを返す
+// 参照名とバージョン識別子の両方に該当する場合は参照名を優先する
+func ResolveSourceSnapshot(ctx context.Context, repo Repository, source *Source, refOrVersion string) (*SourceSnapshot, error) {
+	if id, err := uuid.Parse(refOrVersion); err == nil {
+		snapshotOpt, err := repo.GetSnapshotByID(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get snapshot: %w", err)
+		}
+		snapshot, ok := snapshotOpt.Get()
+		if !ok || snapshot.SourceID != source.ID {
+			return nil, fmt.Errorf("%w: %s", ErrSnapshotNotFound, refOrVersion)
+		}
+		return requireIndexed(snapshot)
+	}
+
+	refOpt, err := repo.GetGitRefByName(ctx, source.ID, refOrVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ref: %w", err)
+	}
+	if gitRef, ok := refOpt.Get(); ok {
+		snapshotOpt, err := repo.GetSnapshotByID(ctx, gitRef.SnapshotID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get snapshot: %w", err)
+		}
+		snapshot, ok := snapshotOpt.Get()
+		if !ok {
+			return nil, fmt.Errorf("%w: %s@%s", ErrSnapshotNotFound, source.Name, refOrVersion)
+		}
+		return requireIndexed(snapshot)
+	}
+
+	return findSnapshotByVersion(ctx, repo, source, refOrVersion)
+}
+
+// findSnapshotByVersion はバージョン識別子（完全一致を優先し、前方一致可）でソースのスナップショットを探す
+func findSnapshotByVersion(ctx context.Context, repo Repository, source *Source, idOrVersion string) (*SourceSnapshot, error) {
+	snapshots, err := repo.ListSnapshotsBySource(ctx, source.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshots: %w", err)
+	}
+
+	var matched *SourceSnapshot
+	for _, snapshot := range snapshots {
+		if snapshot.VersionIdentifier == idOrVersion {
+			return requireIndexed(snapshot)
+		}
+		if strings.HasPrefix(snapshot.VersionIdentifier, idOrVersion) {
+			if matched != nil {
+				return nil, fmt.Errorf("%w: %s matches multiple snapshots", ErrAmbiguousVersion, idOrVersion)
+			}
+			matched = snapshot
+		}
+	}
+	if matched == nil {
+		return nil, fmt.Errorf("%w: %s@%s", ErrSnapshotNotFound, source.Name, idOrVersion)
+	}
+	return requireIndexed(matched)
+}
+
+func requireIndexed(snapshot *SourceSnapshot) (*SourceSnapshot, error) {
+	if !snapshot.IsIndexed() {
+		return nil, fmt.Errorf("%w: %s is not indexed yet", ErrSnapshotNotFound, snapshot.ID)
+	}
+	return snapshot, nil
+}
+
+func resolveProductSource(ctx context.Context, repo Repository, productID uuid.UUID, sourceName string) (*Source, error) {
+	if sourceName != "" {
+		sourceOpt, err := repo.GetSourceByName(ctx, sourceName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get source: %w", err)
+		}
+		source, ok := sourceOpt.Get()
+		if !ok || source.ProductID != productID {
+			return nil, fmt.Errorf("%w: %s", ErrSourceNotFound, sourceName)
+		}
+		return source, nil
+	}
+
+	sources, err := repo.ListSourcesByProductID(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sources: %w", err)
+	}
+	switch len(sources) {
+	case 0:
+		return nil, fmt.Errorf("%w: product has no sources", ErrSourceNotFound)
+	case 1:
+		return sources[0], nil
+	default:
+		return nil, fmt.Errorf("%w: product has %d sources, specify one", ErrAmbiguousSource, len(sources))
+	}
+}
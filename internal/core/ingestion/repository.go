@@ -5,6 +5,16 @@ import (
 	"errors"
 
 	"github.com/google/uuid"
+	"github.com/jinford/dev-rag/internal/core/apispec"
+	"github.com/jinford/dev-rag/internal/core/decision"
+	"github.com/jinford/dev-rag/internal/core/deplink"
+	"github.com/jinford/dev-rag/internal/core/discussion"
+	"github.com/jinford/dev-rag/internal/core/infraspec"
+	"github.com/jinford/dev-rag/internal/core/license"
+	"github.com/jinford/dev-rag/internal/core/monorepo"
+	"github.com/jinford/dev-rag/internal/core/sqlschema"
+	"github.com/jinford/dev-rag/internal/core/thirdparty"
+	"github.com/jinford/dev-rag/internal/core/usage"
 	"github.com/samber/mo"
 )
 
@@ -21,6 +31,7 @@ type Repository interface {
 	ListProductsWithStats(ctx context.Context) ([]*ProductWithStats, error)
 	CreateProductIfNotExists(ctx context.Context, name string, description *string) (*Product, error)
 	UpdateProduct(ctx context.Context, id uuid.UUID, name string, description *string) (*Product, error)
+	UpdateProductRecencyWeight(ctx context.Context, id uuid.UUID, weight *float64) (*Product, error)
 	DeleteProduct(ctx context.Context, id uuid.UUID) error
 
 	// Source
@@ -30,11 +41,20 @@ type Repository interface {
 	CreateSourceIfNotExists(ctx context.Context, name string, sourceType SourceType, productID uuid.UUID, metadata SourceMetadata) (*Source, error)
 
 	// SourceSnapshot
+	GetSnapshotByID(ctx context.Context, id uuid.UUID) (mo.Option[*SourceSnapshot], error)
 	GetSnapshotByVersion(ctx context.Context, sourceID uuid.UUID, versionIdentifier string) (mo.Option[*SourceSnapshot], error)
 	GetLatestIndexedSnapshot(ctx context.Context, sourceID uuid.UUID) (mo.Option[*SourceSnapshot], error)
 	ListSnapshotsBySource(ctx context.Context, sourceID uuid.UUID) ([]*SourceSnapshot, error)
 	CreateSnapshot(ctx context.Context, sourceID uuid.UUID, versionIdentifier string) (*SourceSnapshot, error)
+	// MarkSnapshotIndexing はスナップショットのインデックス化の開始を記録する（前回の失敗の記録は消去する）
+	MarkSnapshotIndexing(ctx context.Context, snapshotID uuid.UUID) error
 	MarkSnapshotIndexed(ctx context.Context, snapshotID uuid.UUID) error
+	// MarkSnapshotFailed はインデックス化に失敗・中断したスナップショットの日時と理由を記録する（インデックス済みのものは変更しない）
+	MarkSnapshotFailed(ctx context.Context, snapshotID uuid.UUID, reason string) error
+	// DeleteIncompleteSnapshotFiles は Embedding の保存が完了していないファイルを削除し、削除した件数を返す（中断したスナップショットの再開用）
+	DeleteIncompleteSnapshotFiles(ctx context.Context, snapshotID uuid.UUID) (int, error)
+	GetSnapshotStats(ctx context.Context, snapshotID uuid.UUID) (*SnapshotStats, error)
+	GetSnapshotIndexHealth(ctx context.Context, snapshotID uuid.UUID, tokenLimit int) (*SnapshotIndexHealth, error)
 
 	// GitRef
 	GetGitRefByName(ctx context.Context, sourceID uuid.UUID, refName string) (mo.Option[*GitRef], error)
@@ -43,6 +63,7 @@ type Repository interface {
 
 	// File
 	GetFileByID(ctx context.Context, id uuid.UUID) (mo.Option[*File], error)
+	GetFileByPath(ctx context.Context, snapshotID uuid.UUID, path string) (mo.Option[*File], error)
 	ListFilesBySnapshot(ctx context.Context, snapshotID uuid.UUID) ([]*File, error)
 	GetFileHashesBySnapshot(ctx context.Context, snapshotID uuid.UUID) (map[string]string, error)
 	GetFilesByDomain(ctx context.Context, snapshotID uuid.UUID, domain string) ([]*File, error)
@@ -50,23 +71,62 @@ type Repository interface {
 	DeleteFileByID(ctx context.Context, id uuid.UUID) error
 	DeleteFilesByPaths(ctx context.Context, snapshotID uuid.UUID, paths []string) error
 
+	// FileBlob
+	// SaveFileBlob はファイルの内容を content_hash ごとに1回だけ保存する（保存済みの場合は何もしない）
+	SaveFileBlob(ctx context.Context, contentHash string, content []byte) error
+	// GetFileBlob は content_hash のファイルの内容を取得する（保存を無効にしていた場合などは None）
+	GetFileBlob(ctx context.Context, contentHash string) (mo.Option[[]byte], error)
+	// DeleteUnreferencedFileBlobs は参照するファイルがなくなった内容を削除し、削除した件数を返す
+	DeleteUnreferencedFileBlobs(ctx context.Context) (int, error)
+	GetFileBlobStats(ctx context.Context) (*FileBlobStats, error)
+
 	// Chunk
 	GetChunkByID(ctx context.Context, id uuid.UUID) (mo.Option[*Chunk], error)
+	GetChunkByKey(ctx context.Context, chunkKey string) (mo.Option[*Chunk], error)
 	ListChunksByFile(ctx context.Context, fileID uuid.UUID) ([]*Chunk, error)
+	// ListFileIDsWithOutdatedChunker はスナップショットで currentVersions 以外のチャンカー（記録がないものを含む）で作成したチャンクがあるファイルの ID を返す
+	ListFileIDsWithOutdatedChunker(ctx context.Context, snapshotID uuid.UUID, currentVersions []string) ([]uuid.UUID, error)
 	GetChunkContext(ctx context.Context, chunkID uuid.UUID, beforeCount int, afterCount int) ([]*Chunk, error)
 	GetChunkChildren(ctx context.Context, parentID uuid.UUID) ([]*Chunk, error)
 	GetChunkParent(ctx context.Context, chunkID uuid.UUID) (mo.Option[*Chunk], error)
 	GetChunkTree(ctx context.Context, rootID uuid.UUID, maxDepth int) ([]*Chunk, error)
 	CreateChunk(ctx context.Context, fileID uuid.UUID, ordinal int, startLine int, endLine int, content string, contentHash string, tokenCount int, metadata *ChunkMetadata) (*Chunk, error)
 	BatchCreateChunks(ctx context.Context, chunks []*Chunk) error
+	// UpsertFileChunks はファイルのチャンクを保存し、同じ ID の保存済みのチャンクは置き換え、chunks にないチャンクは削除する（スナップショットの再実行用）
+	UpsertFileChunks(ctx context.Context, fileID uuid.UUID, chunks []*Chunk) error
 	DeleteChunksByFileID(ctx context.Context, fileID uuid.UUID) error
+	// ReplaceFileChunks はファイルのチャンクを1つのトランザクションで置き換える（古いチャンクを削除し、新しいチャンク・Embedding・Embedding生成の失敗を保存する）
+	ReplaceFileChunks(ctx context.Context, fileID uuid.UUID, chunks []*Chunk, embeddings []*Embedding, failures []*EmbeddingFailure) error
 	AddChunkRelation(ctx context.Context, parentID, childID uuid.UUID, ordinal int) error
 	UpdateChunkImportanceScore(ctx context.Context, chunkID uuid.UUID, score float64) error
 	BatchUpdateChunkImportanceScores(ctx context.Context, scores map[uuid.UUID]float64) error
+	ListChunkHistory(ctx context.Context, chunkKey string) ([]*ChunkVersion, error)
+	GetChunkImportance(ctx context.Context, chunkID uuid.UUID) (mo.Option[*ChunkImportance], error)
+	RefreshLatestChunks(ctx context.Context, sourceID uuid.UUID) error
 
 	// Embedding
 	CreateEmbedding(ctx context.Context, chunkID uuid.UUID, vector []float32, model string) error
 	BatchCreateEmbeddings(ctx context.Context, embeddings []*Embedding) error
+	GetEmbeddingInfo(ctx context.Context, chunkID uuid.UUID) (mo.Option[*EmbeddingInfo], error)
+	RecordEmbeddingFailures(ctx context.Context, failures []*EmbeddingFailure) error
+	ListEmbeddingFailures(ctx context.Context, productID uuid.UUID) ([]*EmbeddingFailure, error)
+	DeleteEmbeddingFailures(ctx context.Context, chunkIDs []uuid.UUID) error
+	// RecordFileFailure はインデックス化に失敗したファイルを内容とともに記録する（記録済みの場合は失敗回数を加算する）
+	RecordFileFailure(ctx context.Context, failure *FileFailure) error
+	// ListFileFailures はプロダクト内でインデックス化に失敗したファイルの記録を内容とともに取得する
+	ListFileFailures(ctx context.Context, productID uuid.UUID) ([]*FileFailure, error)
+	// DeleteResolvedFileFailures はスナップショットの記録のうち failedPaths 以外を削除し、削除した件数を返す
+	DeleteResolvedFileFailures(ctx context.Context, snapshotID uuid.UUID, failedPaths []string) (int, error)
+	// ListChunksForReembed は Embedding がない、または model 以外で生成したチャンクを取得する（Content は Embedding の入力）
+	ListChunksForReembed(ctx context.Context, productID uuid.UUID, model string) ([]*Chunk, error)
+	// ListEmbeddingsByChunkIDs はチャンクの Embedding をまとめて取得する（Embedding がないチャンクは含まない）
+	ListEmbeddingsByChunkIDs(ctx context.Context, chunkIDs []uuid.UUID) ([]*Embedding, error)
+	// BatchUpsertEmbeddings は Embedding を保存する（保存済みの場合は置き換える）
+	BatchUpsertEmbeddings(ctx context.Context, embeddings []*Embedding) error
+	// ListChunksWithoutModelEmbedding は追加のEmbeddingモデル model の Embedding がないチャンクを取得する（Content は Embedding の入力）
+	ListChunksWithoutModelEmbedding(ctx context.Context, productID uuid.UUID, model string) ([]*Chunk, error)
+	// BatchUpsertModelEmbeddings は追加のEmbeddingモデルで生成した Embedding を主モデルの Embedding とは別に保存する
+	BatchUpsertModelEmbeddings(ctx context.Context, embeddings []*Embedding) error
 
 	// ChunkDependency
 	GetDependenciesByChunk(ctx context.Context, chunkID uuid.UUID) ([]*ChunkDependency, error)
@@ -77,6 +137,51 @@ type Repository interface {
 	// SnapshotFile
 	GetSnapshotFiles(ctx context.Context, snapshotID uuid.UUID) ([]*SnapshotFile, error)
 	GetDomainCoverageStats(ctx context.Context, snapshotID uuid.UUID) ([]*DomainCoverage, error)
+	GetUnindexedImportantFiles(ctx context.Context, snapshotID uuid.UUID) ([]string, error)
+	// RecordSnapshotFiles はスナップショットの全ファイルを一括で記録する（インデックス済みかは files テーブルから判定する）
+	RecordSnapshotFiles(ctx context.Context, snapshotID uuid.UUID, files []*SnapshotFile) error
+	// RecordCoverageHistory はスナップショットのドメイン別カバレッジを履歴に記録する
+	RecordCoverageHistory(ctx context.Context, snapshotID uuid.UUID) error
 	CreateSnapshotFile(ctx context.Context, snapshotID uuid.UUID, filePath string, fileSize int64, domain *string, indexed bool, skipReason *string) (*SnapshotFile, error)
 	UpdateSnapshotFileIndexed(ctx context.Context, snapshotID uuid.UUID, filePath string, indexed bool) error
+
+	// SnapshotUsage
+	AddSnapshotUsage(ctx context.Context, snapshotID uuid.UUID, records []usage.Record) error
+	ListSnapshotUsage(ctx context.Context, snapshotID uuid.UUID) ([]usage.Record, error)
+
+	// FileOwner
+	UpdateFileOwners(ctx context.Context, snapshotID uuid.UUID, owners map[string][]string) error
+
+	// DecisionRecord
+	UpsertDecisionRecord(ctx context.Context, record *decision.Record) error
+
+	// Discussion
+	UpsertDiscussion(ctx context.Context, record *discussion.Record) error
+
+	// APIEndpoint
+	ReplaceAPIEndpoints(ctx context.Context, fileID uuid.UUID, endpoints []*apispec.Endpoint) error
+
+	// InfraResource
+	ReplaceInfraResources(ctx context.Context, fileID uuid.UUID, resources []*infraspec.Resource) error
+
+	// SchemaTable
+	ReplaceSchemaTables(ctx context.Context, snapshotID uuid.UUID, tables []*sqlschema.Table) error
+
+	// Symbol
+	// RebuildSymbols はスナップショットのシンボル（関数・メソッド・型の定義位置）をチャンクのメタデータから作り直す
+	RebuildSymbols(ctx context.Context, snapshotID uuid.UUID) (int, error)
+
+	// GoModule
+	ReplaceGoModules(ctx context.Context, snapshotID uuid.UUID, modules []*deplink.Module) error
+	ListProductGoModules(ctx context.Context, productID uuid.UUID) ([]*deplink.Module, error)
+	ListProductGoLinkChunks(ctx context.Context, productID uuid.UUID) ([]*deplink.Chunk, error)
+
+	// ExternalDependency
+	ReplaceExternalDependencies(ctx context.Context, snapshotID uuid.UUID, deps []*thirdparty.Dependency) error
+
+	// LicenseFinding
+	ReplaceLicenseFindings(ctx context.Context, snapshotID uuid.UUID, findings []*license.Finding) error
+
+	// MonorepoService
+	ReplaceMonorepoServices(ctx context.Context, snapshotID uuid.UUID, services []*monorepo.Service) error
 }
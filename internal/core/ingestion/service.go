@@ -8,7 +8,15 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jinford/dev-rag/internal/core/codeowners"
+	"github.com/jinford/dev-rag/internal/core/deplink"
+	"github.com/jinford/dev-rag/internal/core/importance"
 	"github.com/jinford/dev-rag/internal/core/ingestion/chunk"
+	"github.com/jinford/dev-rag/internal/core/license"
+	"github.com/jinford/dev-rag/internal/core/monorepo"
+	"github.com/jinford/dev-rag/internal/core/sqlschema"
+	"github.com/jinford/dev-rag/internal/core/thirdparty"
+	"github.com/jinford/dev-rag/internal/core/usage"
 	"github.com/jinford/dev-rag/internal/core/wiki"
 )
 
@@ -16,11 +24,35 @@ import (
 type IndexResult struct {
 	SnapshotID        uuid.UUID
 	VersionIdentifier string
+	Ref               string // スナップショットを紐付けた参照名（RefResolver 非対応のソースでは空）
 	ProcessedFiles    int
 	TotalChunks       int
 	Duration          time.Duration
 }
 
+// SnapshotIncompleteError はパイプライン処理の失敗・中断によりスナップショットのインデックス化が完了しなかったことを表す
+// 処理済みのファイルはスナップショットに残り、同じバージョンを再度インデックス化すると完了済みのファイルをスキップして再開する
+type SnapshotIncompleteError struct {
+	SnapshotID        uuid.UUID
+	VersionIdentifier string
+	CompletedFiles    int            // 再開前に完了していたためスキップしたファイル数
+	Stats             *PipelineStats // 今回のパイプライン処理の統計（nil の場合あり）
+	Err               error
+}
+
+func (e *SnapshotIncompleteError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *SnapshotIncompleteError) Unwrap() error {
+	return e.Err
+}
+
+// Interrupted はキャンセル（SIGINT など）またはタイムアウトによる中断かどうかを返す
+func (e *SnapshotIncompleteError) Interrupted() bool {
+	return errors.Is(e.Err, context.Canceled) || errors.Is(e.Err, context.DeadlineExceeded)
+}
+
 // IndexService はインデックス化のユースケースを提供する
 type IndexService struct {
 	repository     Repository
@@ -32,6 +64,8 @@ type IndexService struct {
 	tokenCounter   chunk.TokenCounter
 	chunkerConfig  *chunk.ChunkerConfig
 	pipelineConfig *PipelineConfig
+	importance     *importance.ImportanceCalculator // オプショナル
+	runs           IndexRunRepository               // オプショナル
 	logger         *slog.Logger
 }
 
@@ -39,6 +73,8 @@ type indexServiceOptions struct {
 	llmClient      wiki.LLMClient
 	chunkerConfig  *chunk.ChunkerConfig
 	pipelineConfig *PipelineConfig
+	importance     *importance.ImportanceCalculator
+	runs           IndexRunRepository
 	logger         *slog.Logger
 }
 
@@ -73,6 +109,20 @@ func WithIndexPipelineConfig(cfg *PipelineConfig) IndexServiceOption {
 	}
 }
 
+// WithIndexImportanceCalculator はインデックス化の後にチャンクの重要度を算出する ImportanceCalculator を設定する
+func WithIndexImportanceCalculator(calc *importance.ImportanceCalculator) IndexServiceOption {
+	return func(o *indexServiceOptions) {
+		o.importance = calc
+	}
+}
+
+// WithIndexRunRepository はインデックス化の実行履歴（所要時間・処理件数・失敗数・トークン消費）を記録するリポジトリを設定する
+func WithIndexRunRepository(repo IndexRunRepository) IndexServiceOption {
+	return func(o *indexServiceOptions) {
+		o.runs = repo
+	}
+}
+
 // NewIndexService は新しいIndexServiceを作成する
 func NewIndexService(
 	repo Repository,
@@ -111,12 +161,15 @@ func NewIndexService(
 		tokenCounter:   tokenCounter,
 		chunkerConfig:  options.chunkerConfig,
 		pipelineConfig: options.pipelineConfig,
+		importance:     options.importance,
+		runs:           options.runs,
 		logger:         options.logger,
 	}
 }
 
 // IndexSource はソースをインデックス化する
-func (s *IndexService) IndexSource(ctx context.Context, params IndexParams) (*IndexResult, error) {
+// IndexRunRepository を設定している場合は、ソースの取得/作成の後の成功・失敗を実行履歴に記録する
+func (s *IndexService) IndexSource(ctx context.Context, params IndexParams) (result *IndexResult, err error) {
 	startTime := time.Now()
 
 	s.logger.Info("インデックス化を開始",
@@ -124,6 +177,7 @@ func (s *IndexService) IndexSource(ctx context.Context, params IndexParams) (*In
 		"identifier", params.Identifier,
 		"product", params.ProductName,
 		"forceInit", params.ForceInit,
+		"sinceVersion", params.SinceVersion,
 	)
 
 	// パラメータのバリデーション
@@ -138,8 +192,12 @@ func (s *IndexService) IndexSource(ctx context.Context, params IndexParams) (*In
 	}
 
 	// Source を取得または作成
-	sourceName := s.sourceProvider.ExtractSourceName(params.Identifier)
+	// サブツリーを指定した場合は、ソース全体とは別のソースとして記録する
+	sourceName := SubtreeSourceName(s.sourceProvider.ExtractSourceName(params.Identifier), params.Subtree)
 	sourceMetadata := s.sourceProvider.CreateMetadata(params)
+	if params.Subtree != "" {
+		sourceMetadata["subtree"] = params.Subtree
+	}
 	source, err := s.repository.CreateSourceIfNotExists(
 		ctx,
 		sourceName,
@@ -151,29 +209,58 @@ func (s *IndexService) IndexSource(ctx context.Context, params IndexParams) (*In
 		return nil, fmt.Errorf("ソースの取得/作成に失敗: %w", err)
 	}
 
+	// 実行履歴を記録
+	var runResult IndexRunResult
+	runID := s.startRun(ctx, product.ID, source.ID, params)
+	defer func() {
+		s.finishRun(ctx, runID, &runResult, err)
+	}()
+
 	// ソースからドキュメントを取得
-	documents, versionIdentifier, err := s.sourceProvider.FetchDocuments(ctx, params)
-	if err != nil {
-		return nil, fmt.Errorf("ドキュメントの取得に失敗: %w", err)
+	// SinceVersion を指定した場合は基準のスナップショットからの変更だけを取得する
+	reportStage(ctx, ProgressFetching)
+	var stream DocumentIterator
+	var versionIdentifier string
+	var base *SourceSnapshot
+	if params.SinceVersion != "" {
+		stream, versionIdentifier, base, err = s.fetchChanges(ctx, source.ID, params)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		stream, versionIdentifier, err = s.sourceProvider.FetchDocuments(ctx, params)
+		if err != nil {
+			return nil, fmt.Errorf("ドキュメントの取得に失敗: %w", err)
+		}
+	}
+	runResult.VersionIdentifier = versionIdentifier
+	if params.Subtree != "" {
+		stream = withinSubtree(stream, params.Subtree)
 	}
 
-	s.logger.Info("ドキュメントを取得",
-		"count", len(documents),
+	s.logger.Info("ソースのバージョンを取得",
 		"version", versionIdentifier,
 	)
 
 	// 既存のスナップショットをチェック
 	if !params.ForceInit {
 		existingSnapshotOpt, err := s.repository.GetSnapshotByVersion(ctx, source.ID, versionIdentifier)
-		if err == nil && existingSnapshotOpt.IsPresent() && existingSnapshotOpt.MustGet().Indexed {
+		if err == nil && existingSnapshotOpt.IsPresent() && existingSnapshotOpt.MustGet().IsIndexed() {
 			existingSnapshot := existingSnapshotOpt.MustGet()
 			s.logger.Info("既にインデックス済みのバージョン",
 				"snapshotID", existingSnapshot.ID,
 				"version", versionIdentifier,
 			)
+			runResult.SnapshotID = existingSnapshot.ID
+			ref, err := s.publishSnapshot(ctx, source.ID, params, existingSnapshot.ID)
+			if err != nil {
+				return nil, err
+			}
+			runResult.Ref = ref
 			return &IndexResult{
 				SnapshotID:        existingSnapshot.ID,
 				VersionIdentifier: versionIdentifier,
+				Ref:               ref,
 				ProcessedFiles:    0,
 				TotalChunks:       0,
 				Duration:          time.Since(startTime),
@@ -182,36 +269,48 @@ func (s *IndexService) IndexSource(ctx context.Context, params IndexParams) (*In
 	}
 
 	// 新しいスナップショットを作成
+	var completed map[string]string // 再開するスナップショットで完了済みのファイルのパスと content_hash
+	rerun := false                  // 既存のスナップショットを再実行するか（チャンク・Embedding をアップサートで保存する）
 	snapshot, err := s.repository.CreateSnapshot(ctx, source.ID, versionIdentifier)
 	if err != nil {
-		// 重複エラーの場合、既存スナップショットを取得して再利用
-		if errors.Is(err, ErrSnapshotVersionConflict) {
-			s.logger.Info("スナップショットが既に存在します。既存のスナップショットを再利用",
+		if !errors.Is(err, ErrSnapshotVersionConflict) {
+			return nil, fmt.Errorf("スナップショットの作成に失敗: %w", err)
+		}
+		// 重複エラーの場合、既存スナップショットを取得して再実行する
+		// チャンクの ID は chunk_key から決定的に生成するため、同じ内容の再実行は前回保存した行を置き換えるだけになる
+		existingSnapshotOpt, getErr := s.repository.GetSnapshotByVersion(ctx, source.ID, versionIdentifier)
+		if getErr != nil {
+			return nil, fmt.Errorf("既存スナップショットの取得に失敗: %w", getErr)
+		}
+		if existingSnapshotOpt.IsAbsent() {
+			return nil, fmt.Errorf("既存スナップショットが見つかりませんでした: %s", versionIdentifier)
+		}
+		snapshot = existingSnapshotOpt.MustGet()
+		rerun = true
+		if snapshot.IsIndexed() {
+			// インデックス済み（--force-init）の場合は全ファイルをインデックス化し直す
+			s.logger.Info("インデックス済みのスナップショットを再実行",
+				"snapshotID", snapshot.ID,
 				"version", versionIdentifier,
 			)
-			existingSnapshotOpt, getErr := s.repository.GetSnapshotByVersion(ctx, source.ID, versionIdentifier)
-			if getErr != nil {
-				return nil, fmt.Errorf("既存スナップショットの取得に失敗: %w", getErr)
-			}
-			if existingSnapshotOpt.IsAbsent() {
-				return nil, fmt.Errorf("既存スナップショットが見つかりませんでした: %s", versionIdentifier)
-			}
-			existingSnapshot := existingSnapshotOpt.MustGet()
-			// 既にインデックス済みの場合はそのまま返す
-			if existingSnapshot.Indexed {
-				return &IndexResult{
-					SnapshotID:        existingSnapshot.ID,
-					VersionIdentifier: versionIdentifier,
-					ProcessedFiles:    0,
-					TotalChunks:       0,
-					Duration:          time.Since(startTime),
-				}, nil
-			}
-			// インデックス未完了の場合は再利用してインデックス処理を継続
-			snapshot = existingSnapshot
 		} else {
-			return nil, fmt.Errorf("スナップショットの作成に失敗: %w", err)
+			// インデックス未完了（失敗・中断）の場合は完了済みのファイルをスキップして再開する
+			completed, err = s.prepareResume(ctx, snapshot)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	runResult.SnapshotID = snapshot.ID
+
+	// 以降の失敗・中断はスナップショットに記録する（再実行時は完了済みのファイルをスキップして再開する）
+	defer func() {
+		if err != nil {
+			s.markSnapshotFailed(ctx, snapshot.ID, err)
 		}
+	}()
+	if err := s.repository.MarkSnapshotIndexing(ctx, snapshot.ID); err != nil {
+		return nil, fmt.Errorf("スナップショットの状態の更新に失敗: %w", err)
 	}
 
 	// インデックス化コンテキストを作成
@@ -227,30 +326,127 @@ func (s *IndexService) IndexSource(ctx context.Context, params IndexParams) (*In
 		s.embedder,
 		s.chunkerFactory,
 		s.languageDetect,
+		s.tokenCounter,
 		s.pipelineConfig,
 		s.logger,
 	)
+	pipeline.upsert = rerun
+	if base != nil {
+		pipeline.reusableEmbeddings = s.baseEmbeddings(base.ID)
+	}
 
-	processedFiles, totalChunks, err := pipeline.ProcessDocuments(
+	shouldIgnore := s.sourceProvider.ShouldIgnore
+	if len(completed) > 0 {
+		shouldIgnore = func(doc *SourceDocument) bool {
+			if hash, ok := completed[doc.Path]; ok && hash == doc.ContentHash {
+				return true
+			}
+			return s.sourceProvider.ShouldIgnore(doc)
+		}
+	}
+	// 後処理（スキーマカタログ・Go モジュール・外部の依存関係・サービスの境界・担当者・カバレッジ）に必要な情報だけをパイプラインでの読み込みと同時に保持する
+	// ライセンスは全ファイルの冒頭を調べるため、内容を保持せずに読み込みと同時に検出する
+	var documents []*SourceDocument
+	var licenses []*license.Finding
+	observed := func(yield func(*SourceDocument, error) bool) {
+		for doc, err := range stream {
+			if err == nil {
+				documents = append(documents, retainDocument(doc))
+				if !s.sourceProvider.ShouldIgnore(doc) {
+					if finding, ok := license.Detect(doc.Path, doc.Content); ok {
+						licenses = append(licenses, finding)
+					}
+				}
+			}
+			if !yield(doc, err) {
+				return
+			}
+		}
+	}
+	stats, err := pipeline.ProcessDocumentsWithStats(
 		ctx,
 		snapshot.ID,
-		documents,
+		observed,
 		docCtx,
-		s.sourceProvider.ShouldIgnore,
+		shouldIgnore,
 	)
+	if stats != nil {
+		runResult.FilesProcessed = stats.ProcessedFiles
+		runResult.Chunks = stats.TotalChunks
+		runResult.FailedFiles = stats.FailedFiles
+		runResult.FailedEmbeddings = stats.FailedEmbeddings
+	}
+	// 失敗時も消費したトークンはスナップショットと実行履歴に記録する
+	runResult.addUsage(s.recordUsage(ctx, snapshot.ID))
 	if err != nil {
-		return nil, fmt.Errorf("パイプライン処理に失敗: %w", err)
+		return nil, &SnapshotIncompleteError{
+			SnapshotID:        snapshot.ID,
+			VersionIdentifier: versionIdentifier,
+			CompletedFiles:    len(completed),
+			Stats:             stats,
+			Err:               fmt.Errorf("パイプライン処理に失敗: %w", err),
+		}
+	}
+	processedFiles, totalChunks := stats.ProcessedFiles, stats.TotalChunks
+	s.logger.Info("ドキュメントを取得",
+		"count", len(documents),
+		"version", versionIdentifier,
+	)
+
+	// 再開したスナップショットでは、前回失敗して今回インデックス化できたファイルの記録を削除する
+	if completed != nil {
+		if _, err := s.repository.DeleteResolvedFileFailures(ctx, snapshot.ID, stats.FailedPaths); err != nil {
+			s.logger.Warn("インデックス化に失敗したファイルの記録の削除に失敗", "snapshotID", snapshot.ID, "error", err)
+		}
 	}
 
+	// SQLのスキーマ定義・マイグレーションからスキーマカタログを構築
+	s.buildSchemaCatalog(ctx, snapshot.ID, documents)
+
+	// チャンクのメタデータからシンボルの定義位置を記録
+	s.recordSymbols(ctx, snapshot.ID)
+
+	// go.mod から Go モジュールを記録
+	s.recordGoModules(ctx, snapshot.ID, documents)
+
+	// 依存関係のマニフェストとサブモジュールから外部の依存関係を記録
+	s.recordExternalDependencies(ctx, snapshot.ID, documents)
+
+	// ライセンスファイル・ライセンスヘッダーから検出したライセンスを記録
+	s.recordLicenses(ctx, snapshot.ID, licenses)
+
+	// go.mod・package.json の workspaces・Bazel のターゲットからモノレポのサービスの境界を記録
+	s.recordMonorepoServices(ctx, snapshot.ID, documents, params.Subtree)
+
+	// CODEOWNERS からファイルの担当者を記録
+	s.assignOwners(ctx, snapshot.ID, documents)
+
+	// 全ファイルとドメイン別カバレッジの履歴を記録
+	s.recordCoverage(ctx, snapshot.ID, documents)
+
 	// スナップショットを完了としてマーク
 	if err := s.repository.MarkSnapshotIndexed(ctx, snapshot.ID); err != nil {
 		return nil, fmt.Errorf("スナップショットのマークに失敗: %w", err)
 	}
 
+	// シンボルを解決してプロダクト内のチャンク間の依存関係を構築
+	s.linkProductDependencies(ctx, product.ID)
+
+	// 依存関係グラフと編集頻度からプロダクト全体のチャンクの重要度を再計算
+	s.recalculateImportance(ctx, product.ID)
+
+	// 参照（ブランチ・タグ）と最新フラグを新しいスナップショットに向ける
+	ref, err := s.publishSnapshot(ctx, source.ID, params, snapshot.ID)
+	if err != nil {
+		return nil, err
+	}
+	runResult.Ref = ref
+
 	duration := time.Since(startTime)
 
 	s.logger.Info("インデックス化が完了",
 		"snapshotID", snapshot.ID,
+		"ref", ref,
 		"processedFiles", processedFiles,
 		"totalChunks", totalChunks,
 		"duration", duration,
@@ -259,12 +455,359 @@ func (s *IndexService) IndexSource(ctx context.Context, params IndexParams) (*In
 	return &IndexResult{
 		SnapshotID:        snapshot.ID,
 		VersionIdentifier: versionIdentifier,
+		Ref:               ref,
 		ProcessedFiles:    processedFiles,
 		TotalChunks:       totalChunks,
 		Duration:          duration,
 	}, nil
 }
 
+// publishSnapshot はスナップショットを参照名に紐付け、チャンクの最新フラグ（is_latest）を更新する
+// 参照名の紐付けはソースプロバイダーが RefResolver を実装している場合のみ行う
+func (s *IndexService) publishSnapshot(ctx context.Context, sourceID uuid.UUID, params IndexParams, snapshotID uuid.UUID) (string, error) {
+	var ref string
+	if resolver, ok := s.sourceProvider.(RefResolver); ok {
+		ref = resolver.ResolveRef(params)
+	}
+	if ref != "" {
+		if _, err := s.repository.UpsertGitRef(ctx, sourceID, ref, snapshotID); err != nil {
+			return "", fmt.Errorf("参照の更新に失敗: %w", err)
+		}
+	}
+
+	// 最新フラグの更新に失敗しても検索結果には影響しないため警告に留める
+	if err := s.repository.RefreshLatestChunks(ctx, sourceID); err != nil {
+		s.logger.Warn("チャンクの最新フラグの更新に失敗", "sourceID", sourceID, "error", err)
+	}
+	return ref, nil
+}
+
+// recordUsage はコンテキストの Tracker に蓄積された使用量をスナップショットに記録し、記録した使用量を返す
+// 記録の失敗はインデックス化の結果に影響させない
+func (s *IndexService) recordUsage(ctx context.Context, snapshotID uuid.UUID) []usage.Record {
+	records, err := flushSnapshotUsage(ctx, s.repository, snapshotID)
+	if err != nil {
+		s.logger.Warn("使用量の記録に失敗", "snapshotID", snapshotID, "error", err)
+	}
+	return records
+}
+
+// prepareResume は失敗・中断したスナップショットを再開するため、Embedding の保存が完了していないファイルを削除し、
+// 完了済みのファイルのパスと content_hash を返す
+func (s *IndexService) prepareResume(ctx context.Context, snapshot *SourceSnapshot) (map[string]string, error) {
+	deleted, err := s.repository.DeleteIncompleteSnapshotFiles(ctx, snapshot.ID)
+	if err != nil {
+		return nil, fmt.Errorf("未完了のファイルの削除に失敗: %w", err)
+	}
+	completed, err := s.repository.GetFileHashesBySnapshot(ctx, snapshot.ID)
+	if err != nil {
+		return nil, fmt.Errorf("完了済みのファイルの取得に失敗: %w", err)
+	}
+	s.logger.Info("未完了のスナップショットのインデックス化を再開",
+		"snapshotID", snapshot.ID,
+		"completedFiles", len(completed),
+		"deletedIncompleteFiles", deleted,
+		"previousStatus", snapshot.Status,
+		"previousFailure", snapshot.FailureReason,
+	)
+	return completed, nil
+}
+
+// markSnapshotFailed はインデックス化に失敗・中断したスナップショットの日時と理由を記録する
+func (s *IndexService) markSnapshotFailed(ctx context.Context, snapshotID uuid.UUID, cause error) {
+	// 中断時でも記録できるようキャンセルを切り離す
+	if err := s.repository.MarkSnapshotFailed(context.WithoutCancel(ctx), snapshotID, cause.Error()); err != nil {
+		s.logger.Warn("スナップショットの失敗の記録に失敗", "snapshotID", snapshotID, "error", err)
+	}
+}
+
+// startRun は実行中のインデックス化を実行履歴に記録し、その ID を返す
+// 実行履歴は補助的な情報のため、記録の失敗はインデックス化の結果に影響させない（uuid.Nil を返す）
+func (s *IndexService) startRun(ctx context.Context, productID, sourceID uuid.UUID, params IndexParams) uuid.UUID {
+	if s.runs == nil {
+		return uuid.Nil
+	}
+	var ref string
+	if resolver, ok := s.sourceProvider.(RefResolver); ok {
+		ref = resolver.ResolveRef(params)
+	}
+	runID, err := s.runs.StartIndexRun(ctx, productID, sourceID, ref, params.ForceInit)
+	if err != nil {
+		s.logger.Warn("実行履歴の記録に失敗", "sourceID", sourceID, "error", err)
+		return uuid.Nil
+	}
+	return runID
+}
+
+// finishRun はインデックス化の結果を実行履歴に記録する
+func (s *IndexService) finishRun(ctx context.Context, runID uuid.UUID, result *IndexRunResult, runErr error) {
+	if s.runs == nil || runID == uuid.Nil {
+		return
+	}
+	result.Status = IndexRunSucceeded
+	if runErr != nil {
+		result.Status = IndexRunFailed
+		result.Error = runErr.Error()
+	}
+	// 中断時でも記録できるようキャンセルを切り離す
+	if err := s.runs.FinishIndexRun(context.WithoutCancel(ctx), runID, *result); err != nil {
+		s.logger.Warn("実行履歴の記録に失敗", "runID", runID, "error", err)
+	}
+}
+
+// retainDocument はインデックス化の後処理のために保持するドキュメントのコピーを返す
+// 内容はスキーマ定義・go.mod・CODEOWNERS・依存関係のマニフェスト・Bazel の BUILD ファイルのみ残し、それ以外はパスとサイズ・ハッシュだけを保持する
+func retainDocument(doc *SourceDocument) *SourceDocument {
+	retained := *doc
+	if !sqlschema.IsSchemaFile(doc.Path) && !deplink.IsGoMod(doc.Path) && !codeowners.IsCodeownersPath(doc.Path) && !thirdparty.IsManifest(doc.Path) && !monorepo.IsBoundaryFile(doc.Path) {
+		retained.Content = ""
+	}
+	return &retained
+}
+
+// buildSchemaCatalog はSQLのスキーマ定義・マイグレーションファイルをパス順に適用してスキーマカタログを構築・保存する
+// カタログは ask の補助的な情報のため、保存の失敗はインデックス化の結果に影響させない
+func (s *IndexService) buildSchemaCatalog(ctx context.Context, snapshotID uuid.UUID, documents []*SourceDocument) {
+	files := make(map[string]string)
+	for _, doc := range documents {
+		if sqlschema.IsSchemaFile(doc.Path) && !s.sourceProvider.ShouldIgnore(doc) {
+			files[doc.Path] = doc.Content
+		}
+	}
+
+	tables := sqlschema.Build(files)
+	if err := s.repository.ReplaceSchemaTables(ctx, snapshotID, tables); err != nil {
+		s.logger.Warn("スキーマカタログの保存に失敗", "snapshotID", snapshotID, "error", err)
+		return
+	}
+	if len(tables) > 0 {
+		s.logger.Info("スキーマカタログを構築", "files", len(files), "tables", len(tables))
+	}
+}
+
+// recordSymbols はスナップショットのチャンクから関数・メソッド・型などのシンボルの定義位置を抽出して保存する
+// 定義位置の完全一致の検索にのみ使用するため、保存の失敗はインデックス化の結果に影響させない
+func (s *IndexService) recordSymbols(ctx context.Context, snapshotID uuid.UUID) {
+	count, err := s.repository.RebuildSymbols(ctx, snapshotID)
+	if err != nil {
+		s.logger.Warn("シンボルの保存に失敗", "snapshotID", snapshotID, "error", err)
+		return
+	}
+	s.logger.Info("シンボルを記録", "symbols", count)
+}
+
+// recordGoModules は go.mod から Go モジュールを抽出して保存する
+// ソースをまたぐ依存関係の解決にのみ使用するため、保存の失敗はインデックス化の結果に影響させない
+func (s *IndexService) recordGoModules(ctx context.Context, snapshotID uuid.UUID, documents []*SourceDocument) {
+	var modules []*deplink.Module
+	for _, doc := range documents {
+		if !deplink.IsGoMod(doc.Path) || s.sourceProvider.ShouldIgnore(doc) {
+			continue
+		}
+		if module, ok := deplink.ParseGoMod(doc.Path, doc.Content); ok {
+			modules = append(modules, module)
+		}
+	}
+
+	if err := s.repository.ReplaceGoModules(ctx, snapshotID, modules); err != nil {
+		s.logger.Warn("Go モジュールの保存に失敗", "snapshotID", snapshotID, "error", err)
+	}
+}
+
+// recordExternalDependencies は依存関係のマニフェストと .gitmodules から外部の依存関係を抽出して保存する
+// .gitmodules・vendor/modules.txt はデフォルトでインデックス化の対象外のため、除外の判定は依存関係を記述したファイルにのみ行う
+// 依存関係は補助的な情報のため、解析・保存の失敗はインデックス化の結果に影響させない
+func (s *IndexService) recordExternalDependencies(ctx context.Context, snapshotID uuid.UUID, documents []*SourceDocument) {
+	files := make(map[string]string)
+	for _, doc := range documents {
+		if !thirdparty.IsManifest(doc.Path) {
+			continue
+		}
+		if doc.Path != ".gitmodules" && !thirdparty.IsVendorModules(doc.Path) && s.sourceProvider.ShouldIgnore(doc) {
+			continue
+		}
+		files[doc.Path] = doc.Content
+	}
+
+	deps, err := thirdparty.Collect(files)
+	if err != nil {
+		s.logger.Warn("依存関係のマニフェストの解析に失敗", "snapshotID", snapshotID, "error", err)
+	}
+	if err := s.repository.ReplaceExternalDependencies(ctx, snapshotID, deps); err != nil {
+		s.logger.Warn("外部の依存関係の保存に失敗", "snapshotID", snapshotID, "error", err)
+		return
+	}
+	if len(deps) > 0 {
+		s.logger.Info("外部の依存関係を記録", "manifests", len(files), "dependencies", len(deps))
+	}
+}
+
+// recordLicenses はインデックス化の対象のファイルから検出したライセンスを保存する
+// ライセンスは補助的な情報のため、保存の失敗はインデックス化の結果に影響させない
+func (s *IndexService) recordLicenses(ctx context.Context, snapshotID uuid.UUID, findings []*license.Finding) {
+	if err := s.repository.ReplaceLicenseFindings(ctx, snapshotID, findings); err != nil {
+		s.logger.Warn("ライセンスの保存に失敗", "snapshotID", snapshotID, "error", err)
+		return
+	}
+	if len(findings) > 0 {
+		s.logger.Info("ライセンスを記録", "files", len(findings))
+	}
+}
+
+// recordMonorepoServices は go.mod・package.json・BUILD ファイルからモノレポのサービスの境界を検出して保存する
+// サブツリーを指定したインデックス化では、サブツリー自体はサービスとして記録しない
+// サービスの境界は補助的な情報のため、解析・保存の失敗はインデックス化の結果に影響させない
+func (s *IndexService) recordMonorepoServices(ctx context.Context, snapshotID uuid.UUID, documents []*SourceDocument, subtree string) {
+	files := make(map[string]string)
+	for _, doc := range documents {
+		if monorepo.IsBoundaryFile(doc.Path) && !s.sourceProvider.ShouldIgnore(doc) {
+			files[doc.Path] = doc.Content
+		}
+	}
+
+	detected, err := monorepo.Detect(files)
+	if err != nil {
+		s.logger.Warn("サービスの境界の検出に使用するファイルの解析に失敗", "snapshotID", snapshotID, "error", err)
+	}
+	services := make([]*monorepo.Service, 0, len(detected))
+	for _, svc := range detected {
+		if svc.Path != subtree {
+			services = append(services, svc)
+		}
+	}
+	if err := s.repository.ReplaceMonorepoServices(ctx, snapshotID, services); err != nil {
+		s.logger.Warn("モノレポのサービスの保存に失敗", "snapshotID", snapshotID, "error", err)
+		return
+	}
+	if len(services) > 0 {
+		s.logger.Info("モノレポのサービスを記録", "services", len(services))
+	}
+}
+
+// assignOwners は CODEOWNERS（.github/CODEOWNERS など）からファイルの担当者を判定して保存する
+// CODEOWNERS 自体がインデックス化の対象外でも担当者の判定には使用する
+// 担当者は補助的な情報のため、保存の失敗はインデックス化の結果に影響させない
+func (s *IndexService) assignOwners(ctx context.Context, snapshotID uuid.UUID, documents []*SourceDocument) {
+	candidates := make(map[string]string)
+	for _, doc := range documents {
+		if codeowners.IsCodeownersPath(doc.Path) {
+			candidates[doc.Path] = doc.Content
+		}
+	}
+	path, content, ok := codeowners.Select(candidates)
+	if !ok {
+		return
+	}
+	rules := codeowners.Parse(content)
+	if rules.Empty() {
+		return
+	}
+
+	owners := make(map[string][]string)
+	for _, doc := range documents {
+		if o := rules.Owners(doc.Path); len(o) > 0 {
+			owners[doc.Path] = o
+		}
+	}
+	if err := s.repository.UpdateFileOwners(ctx, snapshotID, owners); err != nil {
+		s.logger.Warn("ファイルの担当者の保存に失敗", "snapshotID", snapshotID, "error", err)
+		return
+	}
+	s.logger.Info("CODEOWNERS からファイルの担当者を記録", "codeowners", path, "files", len(owners))
+}
+
+// recordCoverage はインデックス化の対象外を含むスナップショットの全ファイル（snapshot_files）と、そのドメイン別カバレッジの履歴を記録する
+// カバレッジは補助的な情報のため、記録の失敗はインデックス化の結果に影響させない
+func (s *IndexService) recordCoverage(ctx context.Context, snapshotID uuid.UUID, documents []*SourceDocument) {
+	if len(documents) == 0 {
+		return
+	}
+
+	files := make([]*SnapshotFile, 0, len(documents))
+	for _, doc := range documents {
+		domain := ClassifyDomain(doc.Path)
+		file := &SnapshotFile{
+			SnapshotID: snapshotID,
+			FilePath:   doc.Path,
+			FileSize:   doc.Size,
+			Domain:     &domain,
+		}
+		if s.sourceProvider.ShouldIgnore(doc) {
+			reason := SkipReasonIgnored
+			file.SkipReason = &reason
+		}
+		files = append(files, file)
+	}
+	if err := s.repository.RecordSnapshotFiles(ctx, snapshotID, files); err != nil {
+		s.logger.Warn("スナップショットのファイル一覧の記録に失敗", "snapshotID", snapshotID, "error", err)
+		return
+	}
+	if err := s.repository.RecordCoverageHistory(ctx, snapshotID); err != nil {
+		s.logger.Warn("カバレッジの履歴の記録に失敗", "snapshotID", snapshotID, "error", err)
+	}
+}
+
+// linkProductDependencies はプロダクトの各ソースの最新スナップショットの Go のシンボルを解決し、チャンク間の依存関係を作成する
+// 同じパッケージ・同じソースの他パッケージ・他のソースへの依存を、パッケージとレシーバ型で修飾した呼び出しから解決する
+// インデックス化したソースが依存する側・依存される側のどちらの場合も辿れるよう、プロダクト全体を対象に再構築する（既存の依存関係は重複させない）
+// 依存関係の構築の失敗はインデックス化の結果に影響させない
+func (s *IndexService) linkProductDependencies(ctx context.Context, productID uuid.UUID) {
+	modules, err := s.repository.ListProductGoModules(ctx, productID)
+	if err != nil {
+		s.logger.Warn("Go モジュールの取得に失敗", "productID", productID, "error", err)
+		return
+	}
+
+	chunks, err := s.repository.ListProductGoLinkChunks(ctx, productID)
+	if err != nil {
+		s.logger.Warn("依存関係の解決に使用するチャンクの取得に失敗", "productID", productID, "error", err)
+		return
+	}
+
+	deps := deplink.Resolve(modules, chunks)
+	for _, dep := range deps {
+		if err := s.repository.CreateDependency(ctx, dep.FromChunkID, dep.ToChunkID, dep.DepType, dep.Symbol); err != nil {
+			s.logger.Warn("依存関係の作成に失敗", "productID", productID, "error", err)
+			return
+		}
+	}
+	if len(deps) > 0 {
+		s.logger.Info("依存関係を作成", "modules", len(modules), "dependencies", len(deps))
+	}
+}
+
+// recalculateImportance はプロダクト全体のチャンクの重要度を算出する（ImportanceCalculator が未設定の場合は何もしない）
+// 重要度は検索の並び替えなどの補助的な情報のため、算出の失敗はインデックス化の結果に影響させない
+func (s *IndexService) recalculateImportance(ctx context.Context, productID uuid.UUID) {
+	if s.importance == nil {
+		return
+	}
+	if _, err := s.importance.Recalculate(ctx, productID); err != nil {
+		s.logger.Warn("重要度の算出に失敗", "productID", productID, "error", err)
+	}
+}
+
+// RecordSnapshotUsage はコンテキストの Tracker に前回記録以降に蓄積された使用量をスナップショットに追記する
+// Tracker が設定されていない場合は何もしない
+func RecordSnapshotUsage(ctx context.Context, repo Repository, snapshotID uuid.UUID) error {
+	_, err := flushSnapshotUsage(ctx, repo, snapshotID)
+	return err
+}
+
+// flushSnapshotUsage はコンテキストの Tracker に蓄積された使用量をスナップショットに記録し、記録した使用量を返す
+func flushSnapshotUsage(ctx context.Context, repo Repository, snapshotID uuid.UUID) ([]usage.Record, error) {
+	tracker := usage.FromContext(ctx)
+	if tracker == nil {
+		return nil, nil
+	}
+	records := tracker.Flush()
+	if len(records) == 0 {
+		return nil, nil
+	}
+	// 中断時でも記録できるようキャンセルを切り離す
+	return records, repo.AddSnapshotUsage(context.WithoutCancel(ctx), snapshotID, records)
+}
+
 // validateParams はインデックス化パラメータをバリデートする
 func (s *IndexService) validateParams(params IndexParams) error {
 	if params.Identifier == "" {
@@ -273,7 +816,7 @@ func (s *IndexService) validateParams(params IndexParams) error {
 	if params.ProductName == "" {
 		return fmt.Errorf("product name は必須です")
 	}
-	return nil
+	return validateSubtree(params.Subtree)
 }
 
 // indexDocumentContext はドキュメントインデックス化のコンテキスト情報
@@ -283,6 +826,15 @@ type indexDocumentContext struct {
 	VersionIdentifier string // commit hash や version など
 }
 
+// chunkIDNamespace はチャンクキーからチャンクの ID を生成するための名前空間
+var chunkIDNamespace = uuid.NewSHA1(uuid.NameSpaceURL, []byte("https://github.com/jinford/dev-rag/chunks"))
+
+// chunkIDFromKey はチャンクキーからチャンクの ID（UUID v5）を決定的に生成する
+// 同じ内容を再度インデックス化すると同じ ID になるため、再実行をアップサートで冪等にできる
+func chunkIDFromKey(chunkKey string) uuid.UUID {
+	return uuid.NewSHA1(chunkIDNamespace, []byte(chunkKey))
+}
+
 // generateChunkKey はチャンクのユニークキーを生成する
 // 形式: {product_name}/{source_name}/{file_path}#L{start}-L{end}:{ordinal}@{commit_hash}
 func generateChunkKey(ctx indexDocumentContext, filePath string, startLine, endLine, ordinal int) string {
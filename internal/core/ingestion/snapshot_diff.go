@@ -0,0 +1,235 @@
+package ingestion
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// FileChangeKind はスナップショット間のファイルの変更の種類を表す
+type FileChangeKind string
+
+const (
+	FileChangeAdded    FileChangeKind = "added"
+	FileChangeRemoved  FileChangeKind = "removed"
+	FileChangeModified FileChangeKind = "modified"
+)
+
+// SymbolChangeKind はスナップショット間のシンボル（関数・型などのチャンク）の変更の種類を表す
+type SymbolChangeKind string
+
+const (
+	SymbolChangeAdded    SymbolChangeKind = "added"
+	SymbolChangeRemoved  SymbolChangeKind = "removed"
+	SymbolChangeModified SymbolChangeKind = "modified"
+)
+
+// FileChange はスナップショット間のファイルの変更を表す
+type FileChange struct {
+	Path       string         `json:"path"`
+	Kind       FileChangeKind `json:"kind"`
+	FromChunks int            `json:"fromChunks"`
+	ToChunks   int            `json:"toChunks"`
+}
+
+// SymbolChange はスナップショット間のシンボルの変更を表す
+// 変更の有無は保存済みのチャンクのメタデータ（content_hash・シグネチャ・循環的複雑度）から判定する
+type SymbolChange struct {
+	Path             string           `json:"path"`
+	Kind             SymbolChangeKind `json:"kind"`
+	Type             string           `json:"type"`
+	Name             string           `json:"name"` // 親がある場合は「親.名前」
+	Signature        string           `json:"signature,omitempty"`
+	SignatureChanged bool             `json:"signatureChanged,omitempty"`
+	FromComplexity   *int             `json:"fromComplexity,omitempty"`
+	ToComplexity     *int             `json:"toComplexity,omitempty"`
+}
+
+// ComplexityDelta は循環的複雑度の増減を返す（追加・削除の場合は追加・削除したシンボルの複雑度）
+func (c *SymbolChange) ComplexityDelta() int {
+	var from, to int
+	if c.FromComplexity != nil {
+		from = *c.FromComplexity
+	}
+	if c.ToComplexity != nil {
+		to = *c.ToComplexity
+	}
+	return to - from
+}
+
+// SnapshotDiff は同じソースの2つのスナップショットの差分を表す
+type SnapshotDiff struct {
+	From            *SourceSnapshot `json:"from"`
+	To              *SourceSnapshot `json:"to"`
+	Files           []*FileChange   `json:"files"`
+	Symbols         []*SymbolChange `json:"symbols"`
+	UnchangedFiles  int             `json:"unchangedFiles"`
+	ComplexityDelta int             `json:"complexityDelta"` // 変更したファイルのシンボルの循環的複雑度の増減の合計
+}
+
+// CountFiles は種類ごとのファイルの変更数を返す
+func (d *SnapshotDiff) CountFiles(kind FileChangeKind) int {
+	count := 0
+	for _, f := range d.Files {
+		if f.Kind == kind {
+			count++
+		}
+	}
+	return count
+}
+
+// DiffSnapshots は同じソースの2つのスナップショットを、保存済みのファイルとチャンクのメタデータから比較する
+// Git の差分ではなく、インデックス化したファイルの content_hash とチャンクの構造メタデータを比較するため、
+// Git 以外のソースや、クローンを保持していない環境でも比較できる
+func DiffSnapshots(ctx context.Context, repo Repository, from, to *SourceSnapshot) (*SnapshotDiff, error) {
+	if from.SourceID != to.SourceID {
+		return nil, fmt.Errorf("snapshots belong to different sources: %s, %s", from.ID, to.ID)
+	}
+
+	fromFiles, err := repo.ListFilesBySnapshot(ctx, from.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+	toFiles, err := repo.ListFilesBySnapshot(ctx, to.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+
+	fromByPath := make(map[string]*File, len(fromFiles))
+	for _, f := range fromFiles {
+		fromByPath[f.Path] = f
+	}
+	toByPath := make(map[string]*File, len(toFiles))
+	for _, f := range toFiles {
+		toByPath[f.Path] = f
+	}
+
+	paths := make([]string, 0, len(fromByPath)+len(toByPath))
+	for path := range fromByPath {
+		paths = append(paths, path)
+	}
+	for path := range toByPath {
+		if _, ok := fromByPath[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	diff := &SnapshotDiff{From: from, To: to, Files: []*FileChange{}, Symbols: []*SymbolChange{}}
+	for _, path := range paths {
+		fromFile, toFile := fromByPath[path], toByPath[path]
+		if fromFile != nil && toFile != nil && fromFile.ContentHash == toFile.ContentHash {
+			diff.UnchangedFiles++
+			continue
+		}
+
+		fromChunks, err := listFileChunks(ctx, repo, fromFile)
+		if err != nil {
+			return nil, err
+		}
+		toChunks, err := listFileChunks(ctx, repo, toFile)
+		if err != nil {
+			return nil, err
+		}
+
+		change := &FileChange{Path: path, Kind: FileChangeModified, FromChunks: len(fromChunks), ToChunks: len(toChunks)}
+		switch {
+		case fromFile == nil:
+			change.Kind = FileChangeAdded
+		case toFile == nil:
+			change.Kind = FileChangeRemoved
+		}
+		diff.Files = append(diff.Files, change)
+
+		for _, symbol := range diffSymbols(path, fromChunks, toChunks) {
+			diff.Symbols = append(diff.Symbols, symbol)
+			diff.ComplexityDelta += symbol.ComplexityDelta()
+		}
+	}
+	return diff, nil
+}
+
+func listFileChunks(ctx context.Context, repo Repository, file *File) ([]*Chunk, error) {
+	if file == nil {
+		return nil, nil
+	}
+	chunks, err := repo.ListChunksByFile(ctx, file.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list chunks of %s: %w", file.Path, err)
+	}
+	return chunks, nil
+}
+
+// diffSymbols は1ファイルの変更前後のチャンクから、名前のあるチャンク（関数・型など）の追加・削除・変更を求める
+// 同じ種類・名前のチャンクが複数ある場合は最初のチャンクで比較する
+func diffSymbols(path string, fromChunks, toChunks []*Chunk) []*SymbolChange {
+	fromSymbols, fromKeys := indexSymbols(fromChunks)
+	toSymbols, toKeys := indexSymbols(toChunks)
+
+	var changes []*SymbolChange
+	for _, key := range fromKeys {
+		before := fromSymbols[key]
+		after, ok := toSymbols[key]
+		if !ok {
+			changes = append(changes, newSymbolChange(path, SymbolChangeRemoved, before, before.CyclomaticComplexity, nil))
+			continue
+		}
+		if before.ContentHash == after.ContentHash {
+			continue
+		}
+		change := newSymbolChange(path, SymbolChangeModified, after, before.CyclomaticComplexity, after.CyclomaticComplexity)
+		change.SignatureChanged = derefString(before.Signature) != derefString(after.Signature)
+		changes = append(changes, change)
+	}
+	for _, key := range toKeys {
+		if _, ok := fromSymbols[key]; !ok {
+			after := toSymbols[key]
+			changes = append(changes, newSymbolChange(path, SymbolChangeAdded, after, nil, after.CyclomaticComplexity))
+		}
+	}
+	return changes
+}
+
+// indexSymbols は名前のあるチャンクを「種類/親/名前」のキーで索引化する（キーは出現順）
+func indexSymbols(chunks []*Chunk) (map[string]*Chunk, []string) {
+	symbols := make(map[string]*Chunk)
+	var keys []string
+	for _, c := range chunks {
+		if c.Name == nil || *c.Name == "" {
+			continue
+		}
+		key := derefString(c.Type) + "/" + qualifiedSymbolName(c)
+		if _, ok := symbols[key]; ok {
+			continue
+		}
+		symbols[key] = c
+		keys = append(keys, key)
+	}
+	return symbols, keys
+}
+
+func newSymbolChange(path string, kind SymbolChangeKind, c *Chunk, fromComplexity, toComplexity *int) *SymbolChange {
+	return &SymbolChange{
+		Path:           path,
+		Kind:           kind,
+		Type:           derefString(c.Type),
+		Name:           qualifiedSymbolName(c),
+		Signature:      derefString(c.Signature),
+		FromComplexity: fromComplexity,
+		ToComplexity:   toComplexity,
+	}
+}
+
+func qualifiedSymbolName(c *Chunk) string {
+	if parent := derefString(c.ParentName); parent != "" {
+		return parent + "." + derefString(c.Name)
+	}
+	return derefString(c.Name)
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,80 @@
+package ingestion
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDiffRepository はファイル・チャンクの取得のみを実装する
+type fakeDiffRepository struct {
+	Repository
+	files  map[uuid.UUID][]*File
+	chunks map[uuid.UUID][]*Chunk
+}
+
+func (r *fakeDiffRepository) ListFilesBySnapshot(ctx context.Context, snapshotID uuid.UUID) ([]*File, error) {
+	return r.files[snapshotID], nil
+}
+
+func (r *fakeDiffRepository) ListChunksByFile(ctx context.Context, fileID uuid.UUID) ([]*Chunk, error) {
+	return r.chunks[fileID], nil
+}
+
+func symbolChunk(typ, name, hash string, complexity int) *Chunk {
+	return &Chunk{Type: &typ, Name: &name, ContentHash: hash, CyclomaticComplexity: &complexity}
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	sourceID := uuid.New()
+	from := &SourceSnapshot{ID: uuid.New(), SourceID: sourceID}
+	to := &SourceSnapshot{ID: uuid.New(), SourceID: sourceID}
+
+	same1, same2 := &File{ID: uuid.New(), Path: "same.go", ContentHash: "s"}, &File{ID: uuid.New(), Path: "same.go", ContentHash: "s"}
+	oldMain, newMain := &File{ID: uuid.New(), Path: "main.go", ContentHash: "m1"}, &File{ID: uuid.New(), Path: "main.go", ContentHash: "m2"}
+	removed := &File{ID: uuid.New(), Path: "legacy.go", ContentHash: "l"}
+	added := &File{ID: uuid.New(), Path: "handler.go", ContentHash: "h"}
+
+	signature := "func Run(ctx context.Context) error"
+	run := symbolChunk("function", "Run", "r2", 7)
+	run.Signature = &signature
+
+	repo := &fakeDiffRepository{
+		files: map[uuid.UUID][]*File{
+			from.ID: {same1, oldMain, removed},
+			to.ID:   {same2, newMain, added},
+		},
+		chunks: map[uuid.UUID][]*Chunk{
+			oldMain.ID: {symbolChunk("function", "Run", "r1", 3), symbolChunk("function", "helper", "hp", 2)},
+			newMain.ID: {run, symbolChunk("function", "helper", "hp", 2)},
+			removed.ID: {symbolChunk("struct", "Legacy", "lg", 1)},
+			added.ID:   {symbolChunk("function", "Handle", "hd", 4), {ContentHash: "x"}},
+		},
+	}
+
+	diff, err := DiffSnapshots(context.Background(), repo, from, to)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, diff.UnchangedFiles)
+	assert.Equal(t, 1, diff.CountFiles(FileChangeAdded))
+	assert.Equal(t, 1, diff.CountFiles(FileChangeRemoved))
+	assert.Equal(t, 1, diff.CountFiles(FileChangeModified))
+
+	symbols := make(map[string]*SymbolChange)
+	for _, s := range diff.Symbols {
+		symbols[s.Name] = s
+	}
+	require.Len(t, symbols, 3, "内容が変わらないシンボルと名前のないチャンクは含まない")
+	assert.Equal(t, SymbolChangeModified, symbols["Run"].Kind)
+	assert.True(t, symbols["Run"].SignatureChanged)
+	assert.Equal(t, 4, symbols["Run"].ComplexityDelta())
+	assert.Equal(t, SymbolChangeRemoved, symbols["Legacy"].Kind)
+	assert.Equal(t, SymbolChangeAdded, symbols["Handle"].Kind)
+	assert.Equal(t, 4-1+4, diff.ComplexityDelta)
+
+	_, err = DiffSnapshots(context.Background(), repo, from, &SourceSnapshot{ID: uuid.New(), SourceID: uuid.New()})
+	assert.Error(t, err)
+}
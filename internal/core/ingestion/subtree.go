@@ -0,0 +1,42 @@
+package ingestion
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// SubtreeSourceName はソース内のディレクトリに限定してインデックス化するソースの名前を返す
+// 例: github.com/acme/mono と services/api -> github.com/acme/mono//services/api
+func SubtreeSourceName(sourceName, subtree string) string {
+	if subtree == "" {
+		return sourceName
+	}
+	return sourceName + "//" + subtree
+}
+
+// validateSubtree はサブツリーのパスがソースのルートからの正規化した相対パスかを検証する
+func validateSubtree(subtree string) error {
+	if subtree == "" {
+		return nil
+	}
+	if path.Clean(subtree) != subtree || path.IsAbs(subtree) || subtree == "." || subtree == ".." || strings.HasPrefix(subtree, "../") {
+		return fmt.Errorf("subtree はソースのルートからの相対パスを指定してください: %s", subtree)
+	}
+	return nil
+}
+
+// withinSubtree はサブツリー配下のドキュメントだけを返すイテレータを作成する
+func withinSubtree(documents DocumentIterator, subtree string) DocumentIterator {
+	prefix := subtree + "/"
+	return func(yield func(*SourceDocument, error) bool) {
+		for doc, err := range documents {
+			if err == nil && !strings.HasPrefix(doc.Path, prefix) {
+				continue
+			}
+			if !yield(doc, err) {
+				return
+			}
+		}
+	}
+}
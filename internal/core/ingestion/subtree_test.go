@@ -0,0 +1,32 @@
+package ingestion
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubtreeSourceName(t *testing.T) {
+	assert.Equal(t, "github.com/acme/mono", SubtreeSourceName("github.com/acme/mono", ""))
+	assert.Equal(t, "github.com/acme/mono//services/api", SubtreeSourceName("github.com/acme/mono", "services/api"))
+}
+
+func TestValidateSubtree(t *testing.T) {
+	assert.NoError(t, validateSubtree(""))
+	assert.NoError(t, validateSubtree("services/api"))
+
+	for _, subtree := range []string{".", "/services/api", "services/api/", "../other", "services/../api"} {
+		assert.Error(t, validateSubtree(subtree), subtree)
+	}
+}
+
+func TestWithinSubtree(t *testing.T) {
+	documents := DocumentsFromSlice([]*SourceDocument{
+		{Path: "go.mod"},
+		{Path: "services/api/main.go"},
+		{Path: "services/api-gateway/main.go"},
+		{Path: "services/api/internal/handler.go"},
+	})
+
+	assert.Equal(t, []string{"services/api/main.go", "services/api/internal/handler.go"}, collectPaths(t, withinSubtree(documents, "services/api")))
+}
@@ -8,6 +8,9 @@ import (
 	"strings"
 
 	"github.com/google/uuid"
+
+	"github.com/jinford/dev-rag/internal/core/llm"
+	"github.com/jinford/dev-rag/internal/core/prompt"
 )
 
 // ArchitectureSummarizer はアーキテクチャ全体の要約を生成する
@@ -16,6 +19,7 @@ type ArchitectureSummarizer struct {
 	llm         LLMClient
 	embedder    Embedder
 	hasher      *Hasher
+	prompts     PromptRenderer
 	logger      *slog.Logger
 }
 
@@ -31,6 +35,7 @@ func NewArchitectureSummarizer(
 		llm:         llm,
 		embedder:    embedder,
 		hasher:      NewHasher(),
+		prompts:     prompt.Default(),
 		logger:      logger,
 	}
 }
@@ -103,10 +108,13 @@ func (s *ArchitectureSummarizer) generateOne(
 	sourceHash string,
 ) error {
 	// 1. プロンプトを構築
-	prompt := s.buildPrompt(archType, dirSummaries)
+	rendered, err := s.prompts.Render(ctx, prompt.NameArchitectureSummary, s.promptData(archType, dirSummaries))
+	if err != nil {
+		return fmt.Errorf("failed to build prompt: %w", err)
+	}
 
-	// 2. LLMで要約を生成
-	summaryContent, err := s.llm.GenerateCompletion(ctx, prompt)
+	// 2. LLMで要約を生成（入力が同じ場合はキャッシュした応答を使用）
+	summaryContent, err := s.llm.GenerateCompletion(llm.WithCacheablePrompt(ctx, rendered.Name, rendered.Version), rendered.Text)
 	if err != nil {
 		return fmt.Errorf("failed to generate summary: %w", err)
 	}
@@ -123,6 +131,7 @@ func (s *ArchitectureSummarizer) generateOne(
 	// 5. メタデータを構築
 	metadata := map[string]any{
 		"directory_count": len(dirSummaries),
+		"prompt_version":  rendered.Version,
 	}
 
 	// 6. 要約を作成
@@ -178,8 +187,9 @@ func (s *ArchitectureSummarizer) generateOne(
 	return nil
 }
 
-// buildPrompt はアーキテクチャ要約用のプロンプトを構築
-func (s *ArchitectureSummarizer) buildPrompt(archType ArchType, dirSummaries []*Summary) string {
+// promptData はアーキテクチャ要約用のプロンプトテンプレートに渡す値を構築
+// 種類ごとの指示はテンプレートで ArchType により切り替える
+func (s *ArchitectureSummarizer) promptData(archType ArchType, dirSummaries []*Summary) prompt.ArchitectureSummaryData {
 	// ディレクトリ要約をテキスト化
 	var dirTexts []string
 	for _, ds := range dirSummaries {
@@ -190,7 +200,6 @@ func (s *ArchitectureSummarizer) buildPrompt(archType ArchType, dirSummaries []*
 		dirTexts = append(dirTexts, fmt.Sprintf("- %s: %s", path, ds.Content))
 	}
 	sort.Strings(dirTexts)
-	dirSummaryText := strings.Join(dirTexts, "\n")
 
 	// 統計情報
 	fileCount := 0
@@ -200,66 +209,10 @@ func (s *ArchitectureSummarizer) buildPrompt(archType ArchType, dirSummaries []*
 		}
 	}
 
-	// 種類ごとのプロンプト
-	switch archType {
-	case ArchTypeOverview:
-		return fmt.Sprintf(`以下のリポジトリの概要を作成してください。
-
-統計:
-- ディレクトリ数: %d
-- 総ファイル数: %d
-
-ディレクトリ要約:
-%s
-
-要件:
-- システムの目的を説明
-- 主要機能を3-5点列挙
-- 日本語、500字以内`, len(dirSummaries), fileCount, dirSummaryText)
-
-	case ArchTypeTechStack:
-		return fmt.Sprintf(`以下のリポジトリの技術スタックをまとめてください。
-
-ディレクトリ要約:
-%s
-
-要件:
-- 使用言語
-- フレームワーク・ライブラリ
-- データベース・ストレージ
-- 外部サービス
-- 日本語、400字以内`, dirSummaryText)
-
-	case ArchTypeDataFlow:
-		return fmt.Sprintf(`以下のリポジトリのデータフローをまとめてください。
-
-ディレクトリ要約:
-%s
-
-要件:
-- エントリーポイント
-- 処理フロー（3-5ステップ）
-- データの永続化方法
-- 日本語、400字以内`, dirSummaryText)
-
-	case ArchTypeComponents:
-		return fmt.Sprintf(`以下のリポジトリの主要コンポーネントをまとめてください。
-
-ディレクトリ要約:
-%s
-
-要件:
-- 主要コンポーネント（3-6個）
-- 各コンポーネントの役割
-- コンポーネント間の関係
-- 日本語、500字以内`, dirSummaryText)
-
-	default:
-		return fmt.Sprintf(`以下のリポジトリの%sについてまとめてください。
-
-ディレクトリ要約:
-%s
-
-日本語、400字以内`, archType, dirSummaryText)
+	return prompt.ArchitectureSummaryData{
+		ArchType:           string(archType),
+		DirectoryCount:     len(dirSummaries),
+		FileCount:          fileCount,
+		DirectorySummaries: strings.Join(dirTexts, "\n"),
 	}
 }
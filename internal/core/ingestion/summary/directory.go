@@ -11,6 +11,8 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jinford/dev-rag/internal/core/ingestion"
+	"github.com/jinford/dev-rag/internal/core/llm"
+	"github.com/jinford/dev-rag/internal/core/prompt"
 )
 
 // DirectorySummarizer はディレクトリ単位の要約を生成する
@@ -20,6 +22,7 @@ type DirectorySummarizer struct {
 	llm           LLMClient
 	embedder      Embedder
 	hasher        *Hasher
+	prompts       PromptRenderer
 	logger        *slog.Logger
 	concurrency   int
 }
@@ -38,6 +41,7 @@ func NewDirectorySummarizer(
 		llm:           llm,
 		embedder:      embedder,
 		hasher:        NewHasher(),
+		prompts:       prompt.Default(),
 		logger:        logger,
 		concurrency:   5,
 	}
@@ -309,10 +313,13 @@ func (s *DirectorySummarizer) Generate(
 	}
 
 	// 3. プロンプトを構築
-	prompt := s.buildPrompt(dir, fileSummaries, subdirSummaries)
+	rendered, err := s.prompts.Render(ctx, prompt.NameDirectorySummary, s.promptData(dir, fileSummaries, subdirSummaries))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build prompt: %w", err)
+	}
 
-	// 4. LLMで要約を生成
-	summaryContent, err := s.llm.GenerateCompletion(ctx, prompt)
+	// 4. LLMで要約を生成（入力が同じ場合はキャッシュした応答を使用）
+	summaryContent, err := s.llm.GenerateCompletion(llm.WithCacheablePrompt(ctx, rendered.Name, rendered.Version), rendered.Text)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate summary: %w", err)
 	}
@@ -328,8 +335,9 @@ func (s *DirectorySummarizer) Generate(
 
 	// 7. メタデータを構築
 	metadata := map[string]any{
-		"file_count":   len(dir.Files),
-		"subdir_count": len(dir.Subdirectories),
+		"file_count":     len(dir.Files),
+		"subdir_count":   len(dir.Subdirectories),
+		"prompt_version": rendered.Version,
 	}
 
 	// 8. 要約を作成
@@ -388,8 +396,8 @@ func (s *DirectorySummarizer) Generate(
 	return saved, nil
 }
 
-// buildPrompt はディレクトリ要約用のプロンプトを構築
-func (s *DirectorySummarizer) buildPrompt(dir *DirectoryInfo, fileSummaries, subdirSummaries []string) string {
+// promptData はディレクトリ要約用のプロンプトテンプレートに渡す値を構築
+func (s *DirectorySummarizer) promptData(dir *DirectoryInfo, fileSummaries, subdirSummaries []string) prompt.DirectorySummaryData {
 	path := dir.Path
 	if path == "" {
 		path = "(root)"
@@ -405,21 +413,12 @@ func (s *DirectorySummarizer) buildPrompt(dir *DirectoryInfo, fileSummaries, sub
 		subdirSummaryText = strings.Join(subdirSummaries, "\n")
 	}
 
-	return fmt.Sprintf(`以下のディレクトリの要約を作成してください。
-
-パス: %s
-深さ: %d
-ファイル数: %d
-サブディレクトリ数: %d
-
-ファイル要約:
-%s
-
-サブディレクトリ要約:
-%s
-
-要件:
-- このディレクトリの責務を説明
-- 主要な機能を列挙
-- 日本語、300字以内`, path, dir.Depth, len(dir.Files), len(dir.Subdirectories), fileSummaryText, subdirSummaryText)
+	return prompt.DirectorySummaryData{
+		Path:                  path,
+		Depth:                 dir.Depth,
+		FileCount:             len(dir.Files),
+		SubdirectoryCount:     len(dir.Subdirectories),
+		FileSummaries:         fileSummaryText,
+		SubdirectorySummaries: subdirSummaryText,
+	}
 }
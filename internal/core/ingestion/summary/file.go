@@ -9,6 +9,9 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jinford/dev-rag/internal/core/ingestion"
+	"github.com/jinford/dev-rag/internal/core/llm"
+	"github.com/jinford/dev-rag/internal/core/prompt"
+	"github.com/jinford/dev-rag/internal/core/usage"
 )
 
 // FileSummarizer はファイル単位の要約を生成する
@@ -18,6 +21,7 @@ type FileSummarizer struct {
 	llm           LLMClient
 	embedder      Embedder
 	hasher        *Hasher
+	prompts       PromptRenderer
 	logger        *slog.Logger
 	concurrency   int
 }
@@ -36,6 +40,7 @@ func NewFileSummarizer(
 		llm:           llm,
 		embedder:      embedder,
 		hasher:        NewHasher(),
+		prompts:       prompt.Default(),
 		logger:        logger,
 		concurrency:   5, // 並列度
 	}
@@ -77,6 +82,10 @@ func (s *FileSummarizer) GenerateForSnapshot(ctx context.Context, snapshotID uui
 		go func() {
 			defer wg.Done()
 			for t := range taskCh {
+				// 予算超過（downgrade）以降の残りのファイル要約はスキップする
+				if usage.ShouldDowngrade(ctx) {
+					continue
+				}
 				summary, changed, err := s.GenerateIfChanged(ctx, snapshotID, t.file)
 				mu.Lock()
 				if err != nil {
@@ -169,10 +178,13 @@ func (s *FileSummarizer) Generate(ctx context.Context, snapshotID uuid.UUID, fil
 	content := builder.String()
 
 	// 3. プロンプトを構築
-	prompt := s.buildPrompt(file, content)
+	rendered, err := s.prompts.Render(ctx, prompt.NameFileSummary, s.promptData(file, content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build prompt: %w", err)
+	}
 
-	// 4. LLMで要約を生成
-	summaryContent, err := s.llm.GenerateCompletion(ctx, prompt)
+	// 4. LLMで要約を生成（入力が同じ場合はキャッシュした応答を使用）
+	summaryContent, err := s.llm.GenerateCompletion(llm.WithCacheablePrompt(ctx, rendered.Name, rendered.Version), rendered.Text)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate summary: %w", err)
 	}
@@ -192,6 +204,7 @@ func (s *FileSummarizer) Generate(ctx context.Context, snapshotID uuid.UUID, fil
 		"llm_model":      "default",
 		"embedder_model": s.embedder.ModelName(),
 		"chunk_count":    len(chunks),
+		"prompt_version": rendered.Version,
 	}
 	if file.Language != nil {
 		metadata["language"] = *file.Language
@@ -248,8 +261,8 @@ func (s *FileSummarizer) Generate(ctx context.Context, snapshotID uuid.UUID, fil
 	return saved, nil
 }
 
-// buildPrompt はファイル要約用のプロンプトを構築
-func (s *FileSummarizer) buildPrompt(file *ingestion.File, content string) string {
+// promptData はファイル要約用のプロンプトテンプレートに渡す値を構築
+func (s *FileSummarizer) promptData(file *ingestion.File, content string) prompt.FileSummaryData {
 	language := "unknown"
 	if file.Language != nil {
 		language = *file.Language
@@ -261,16 +274,5 @@ func (s *FileSummarizer) buildPrompt(file *ingestion.File, content string) strin
 		content = content[:maxContentLen] + "\n... (truncated)"
 	}
 
-	return fmt.Sprintf(`以下のファイルの要約を作成してください。
-
-パス: %s
-言語: %s
-
-内容:
-%s
-
-要件:
-- 2-3文で目的を説明
-- 主要な関数/型を列挙
-- 日本語、200字以内`, file.Path, language, content)
+	return prompt.FileSummaryData{Path: file.Path, Language: language, Content: content}
 }
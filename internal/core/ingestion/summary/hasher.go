@@ -49,6 +49,16 @@ func (h *Hasher) HashArchitectureSource(dirSummaryHashes []string) string {
 	return h.HashString(combined)
 }
 
+// HashProductSource はプロダクト全体の要約のsource_hashを計算
+// 入力: 各ソースのアーキテクチャ要約の「ソース名:種類:content_hash」のリスト
+func (h *Hasher) HashProductSource(archSummaryKeys []string) string {
+	sorted := make([]string, len(archSummaryKeys))
+	copy(sorted, archSummaryKeys)
+	sort.Strings(sorted)
+	combined := strings.Join(sorted, "\n")
+	return h.HashString(combined)
+}
+
 // HashContent は要約内容のcontent_hashを計算
 func (h *Hasher) HashContent(content string) string {
 	return h.HashString(content)
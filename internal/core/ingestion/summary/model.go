@@ -50,6 +50,26 @@ type SummaryEmbedding struct {
 	CreatedAt time.Time
 }
 
+// ProductSummary はプロダクト全体の要約
+type ProductSummary struct {
+	ProductID     uuid.UUID
+	Content       string
+	Description   string // 要約の冒頭の1文（products.description の自動設定に使用）
+	SourceHash    string // 入力としたアーキテクチャ要約の content_hash から計算したハッシュ
+	PromptVersion string
+	Vector        []float32
+	Model         string
+	UpdatedAt     time.Time
+}
+
+// SourceArchitectureSummary はプロダクト全体の要約の入力とするソースのアーキテクチャ要約
+type SourceArchitectureSummary struct {
+	SourceName  string
+	ArchType    ArchType
+	Content     string
+	ContentHash string
+}
+
 // FileInfo はファイル情報（要約生成用）
 type FileInfo struct {
 	ID          uuid.UUID
@@ -0,0 +1,173 @@
+package summary
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"github.com/jinford/dev-rag/internal/core/llm"
+	"github.com/jinford/dev-rag/internal/core/prompt"
+)
+
+// productDescriptionMaxRunes はプロダクトの説明として設定する1文の最大文字数
+const productDescriptionMaxRunes = 200
+
+// productSummaryArchTypes はプロダクト全体の要約の入力とするアーキテクチャ要約の種類
+var productSummaryArchTypes = map[ArchType]bool{
+	ArchTypeOverview:   true,
+	ArchTypeTechStack:  true,
+	ArchTypeComponents: true,
+}
+
+// ProductSummarizer はプロダクト全体の要約（目的・技術スタック・主要なサービス）を生成する
+// 各ソースの最新スナップショットのアーキテクチャ要約を入力とし、質問のプロダクトへの振り分けと
+// プロダクトの説明の自動設定に使用する
+type ProductSummarizer struct {
+	summaryRepo Repository
+	llm         LLMClient
+	embedder    Embedder
+	hasher      *Hasher
+	prompts     PromptRenderer
+	logger      *slog.Logger
+}
+
+// NewProductSummarizer は新しいProductSummarizerを作成
+func NewProductSummarizer(
+	summaryRepo Repository,
+	llm LLMClient,
+	embedder Embedder,
+	logger *slog.Logger,
+) *ProductSummarizer {
+	return &ProductSummarizer{
+		summaryRepo: summaryRepo,
+		llm:         llm,
+		embedder:    embedder,
+		hasher:      NewHasher(),
+		prompts:     prompt.Default(),
+		logger:      logger,
+	}
+}
+
+// Generate はプロダクト全体の要約を生成する（差分更新）
+// 入力のアーキテクチャ要約・プロンプトテンプレートのバージョン・Embeddingモデルが前回と同じ場合は生成しない
+func (s *ProductSummarizer) Generate(ctx context.Context, productID uuid.UUID, productName string) error {
+	// 1. 各ソースのアーキテクチャ要約を取得
+	archSummaries, err := s.summaryRepo.ListLatestArchitectureSummariesByProduct(ctx, productID)
+	if err != nil {
+		return fmt.Errorf("failed to list architecture summaries: %w", err)
+	}
+
+	var inputs []*SourceArchitectureSummary
+	var keys []string
+	sources := make(map[string]bool)
+	for _, as := range archSummaries {
+		if !productSummaryArchTypes[as.ArchType] {
+			continue
+		}
+		inputs = append(inputs, as)
+		keys = append(keys, fmt.Sprintf("%s:%s:%s", as.SourceName, as.ArchType, as.ContentHash))
+		sources[as.SourceName] = true
+	}
+	if len(inputs) == 0 {
+		s.logger.Warn("no architecture summaries found", "product", productName)
+		return nil
+	}
+
+	// 2. source_hashを計算し、既存の要約を確認
+	sourceHash := s.hasher.HashProductSource(keys)
+	tmpl, err := s.prompts.Resolve(ctx, prompt.NameProductSummary)
+	if err != nil {
+		return fmt.Errorf("failed to resolve prompt: %w", err)
+	}
+
+	existingOpt, err := s.summaryRepo.GetProductSummary(ctx, productID)
+	if err != nil {
+		return fmt.Errorf("failed to get product summary: %w", err)
+	}
+	var previousDescription string
+	if existing, ok := existingOpt.Get(); ok {
+		if existing.SourceHash == sourceHash && existing.PromptVersion == tmpl.Version && existing.Model == s.embedder.ModelName() {
+			s.logger.Info("product summary unchanged", "product", productName)
+			return nil
+		}
+		previousDescription = existing.Description
+	}
+
+	// 3. LLMで要約を生成（入力が同じ場合はキャッシュした応答を使用）
+	rendered, err := s.prompts.Render(ctx, prompt.NameProductSummary, s.promptData(productName, len(sources), inputs))
+	if err != nil {
+		return fmt.Errorf("failed to build prompt: %w", err)
+	}
+	content, err := s.llm.GenerateCompletion(llm.WithCacheablePrompt(ctx, rendered.Name, rendered.Version), rendered.Text)
+	if err != nil {
+		return fmt.Errorf("failed to generate summary: %w", err)
+	}
+	content = strings.TrimSpace(content)
+
+	// 4. Embeddingを生成
+	embedding, err := s.embedder.Embed(ctx, content)
+	if err != nil {
+		return fmt.Errorf("failed to generate embedding: %w", err)
+	}
+
+	// 5. 要約を保存
+	description := ParseProductDescription(content)
+	if err := s.summaryRepo.UpsertProductSummary(ctx, &ProductSummary{
+		ProductID:     productID,
+		Content:       content,
+		Description:   description,
+		SourceHash:    sourceHash,
+		PromptVersion: rendered.Version,
+		Vector:        embedding,
+		Model:         s.embedder.ModelName(),
+	}); err != nil {
+		return fmt.Errorf("failed to save product summary: %w", err)
+	}
+
+	// 6. プロダクトの説明を設定（利用者が設定した説明は上書きしない）
+	if description != "" {
+		filled, err := s.summaryRepo.FillProductDescription(ctx, productID, description, previousDescription)
+		if err != nil {
+			return fmt.Errorf("failed to fill product description: %w", err)
+		}
+		if filled {
+			s.logger.Info("filled product description", "product", productName)
+		}
+	}
+
+	s.logger.Info("generated product summary", "product", productName, "sources", len(sources))
+	return nil
+}
+
+// promptData はプロダクト全体の要約用のプロンプトテンプレートに渡す値を構築
+func (s *ProductSummarizer) promptData(productName string, sourceCount int, inputs []*SourceArchitectureSummary) prompt.ProductSummaryData {
+	var sections []string
+	for _, as := range inputs {
+		sections = append(sections, fmt.Sprintf("### %s (%s)\n%s", as.SourceName, as.ArchType, strings.TrimSpace(as.Content)))
+	}
+	return prompt.ProductSummaryData{
+		ProductName:           productName,
+		SourceCount:           sourceCount,
+		ArchitectureSummaries: strings.Join(sections, "\n\n"),
+	}
+}
+
+// ParseProductDescription はプロダクト全体の要約の1行目（目的の1文）をプロダクトの説明として取り出す
+// 見出し・箇条書きの記号は除き、長すぎる場合は切り詰める
+func ParseProductDescription(content string) string {
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "#-* "))
+		if line == "" {
+			continue
+		}
+		if utf8.RuneCountInString(line) > productDescriptionMaxRunes {
+			line = string([]rune(line)[:productDescriptionMaxRunes])
+		}
+		return line
+	}
+	return ""
+}
@@ -0,0 +1,85 @@
+package summary
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeProductSummaryRepository はプロダクト全体の要約の読み書きのみを実装する
+type fakeProductSummaryRepository struct {
+	Repository
+	archSummaries []*SourceArchitectureSummary
+	saved         *ProductSummary
+	description   string
+}
+
+func (r *fakeProductSummaryRepository) ListLatestArchitectureSummariesByProduct(ctx context.Context, productID uuid.UUID) ([]*SourceArchitectureSummary, error) {
+	return r.archSummaries, nil
+}
+
+func (r *fakeProductSummaryRepository) GetProductSummary(ctx context.Context, productID uuid.UUID) (mo.Option[*ProductSummary], error) {
+	if r.saved == nil {
+		return mo.None[*ProductSummary](), nil
+	}
+	return mo.Some(r.saved), nil
+}
+
+func (r *fakeProductSummaryRepository) UpsertProductSummary(ctx context.Context, s *ProductSummary) error {
+	r.saved = s
+	return nil
+}
+
+func (r *fakeProductSummaryRepository) FillProductDescription(ctx context.Context, productID uuid.UUID, description, previous string) (bool, error) {
+	if r.description != "" && r.description != previous {
+		return false, nil
+	}
+	r.description = description
+	return true, nil
+}
+
+func TestParseProductDescription(t *testing.T) {
+	assert.Equal(t, "決済を処理するバックエンド", ParseProductDescription("\n# 決済を処理するバックエンド\n\n## 目的\n..."))
+	assert.Equal(t, "社内向けの検索基盤", ParseProductDescription("- 社内向けの検索基盤"))
+	assert.Empty(t, ParseProductDescription("  \n"))
+}
+
+func TestProductSummarizer_Generate(t *testing.T) {
+	ctx := context.Background()
+	repo := &fakeProductSummaryRepository{archSummaries: []*SourceArchitectureSummary{
+		{SourceName: "api", ArchType: ArchTypeOverview, Content: "API サーバー", ContentHash: "h1"},
+		{SourceName: "api", ArchType: ArchTypeDataFlow, Content: "データフロー", ContentHash: "h2"},
+		{SourceName: "web", ArchType: ArchTypeTechStack, Content: "React", ContentHash: "h3"},
+	}}
+	llm := &countingLLM{}
+	summarizer := NewProductSummarizer(repo, llm, fakeEmbedder{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	require.NoError(t, summarizer.Generate(ctx, uuid.New(), "shop"))
+	require.NotNil(t, repo.saved)
+	assert.Equal(t, "要約", repo.saved.Description)
+	assert.Equal(t, "test-embedding", repo.saved.Model)
+	assert.Equal(t, "要約", repo.description)
+	assert.Equal(t, 1, llm.calls)
+
+	// 入力のアーキテクチャ要約が変わらない場合は再生成しない
+	require.NoError(t, summarizer.Generate(ctx, uuid.New(), "shop"))
+	assert.Equal(t, 1, llm.calls)
+
+	// 振り分けに使用しない種類（データフロー）の変更では再生成しない
+	repo.archSummaries[1].ContentHash = "h2-new"
+	require.NoError(t, summarizer.Generate(ctx, uuid.New(), "shop"))
+	assert.Equal(t, 1, llm.calls)
+
+	// 利用者が設定した説明は上書きしない
+	repo.archSummaries[0].ContentHash = "h1-new"
+	repo.description = "利用者が設定した説明"
+	require.NoError(t, summarizer.Generate(ctx, uuid.New(), "shop"))
+	assert.Equal(t, 2, llm.calls)
+	assert.Equal(t, "利用者が設定した説明", repo.description)
+}
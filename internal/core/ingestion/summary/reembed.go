@@ -0,0 +1,76 @@
+package summary
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/jinford/dev-rag/internal/core/usage"
+)
+
+// ReembedResult は要約の Embedding の再生成の結果
+type ReembedResult struct {
+	Total       int // スナップショットの要約数
+	Regenerated int // Embedding を再生成した要約数
+	Failed      int
+}
+
+// Reembed はスナップショットの要約のうち、Embedding がない、または現在のモデル以外で生成したものの Embedding を再生成する
+// 要約本文は再生成しないため、LLM は呼び出さない
+func (s *SummaryService) Reembed(ctx context.Context, snapshotID uuid.UUID) (*ReembedResult, error) {
+	summaryRepo := s.fileSummarizer.summaryRepo
+	embedder := s.fileSummarizer.embedder
+
+	var summaries []*Summary
+	for _, list := range []func(context.Context, uuid.UUID) ([]*Summary, error){
+		summaryRepo.ListFileSummariesBySnapshot,
+		summaryRepo.ListDirectorySummariesBySnapshot,
+		summaryRepo.ListArchitectureSummariesBySnapshot,
+	} {
+		found, err := list(ctx, snapshotID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list summaries: %w", err)
+		}
+		summaries = append(summaries, found...)
+	}
+
+	result := &ReembedResult{Total: len(summaries)}
+	model := embedder.ModelName()
+	for _, summary := range summaries {
+		existing, err := summaryRepo.GetSummaryEmbedding(ctx, summary.ID)
+		if err != nil {
+			return result, fmt.Errorf("failed to get summary embedding: %w", err)
+		}
+		if e, ok := existing.Get(); ok && e.Model == model {
+			continue
+		}
+
+		vector, err := embedder.Embed(ctx, summary.Content)
+		if err != nil {
+			// 予算超過・キャンセルの場合は残りの要約を処理しない
+			if errors.Is(err, usage.ErrBudgetExceeded) || ctx.Err() != nil {
+				return result, err
+			}
+			result.Failed++
+			s.logger.Warn("failed to reembed summary", "path", summary.TargetPath, "error", err)
+			continue
+		}
+		if err := summaryRepo.UpsertSummaryEmbedding(ctx, &SummaryEmbedding{
+			SummaryID: summary.ID,
+			Vector:    vector,
+			Model:     model,
+		}); err != nil {
+			return result, fmt.Errorf("failed to upsert summary embedding: %w", err)
+		}
+		result.Regenerated++
+	}
+
+	s.logger.Info("completed summary reembed",
+		"snapshot_id", snapshotID,
+		"total", result.Total,
+		"regenerated", result.Regenerated,
+		"failed", result.Failed)
+	return result, nil
+}
@@ -0,0 +1,177 @@
+package summary
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/jinford/dev-rag/internal/core/ingestion"
+	"github.com/jinford/dev-rag/internal/core/prompt"
+	"github.com/jinford/dev-rag/internal/core/usage"
+)
+
+// StaleReason はファイル要約を再生成する理由
+type StaleReason string
+
+const (
+	StaleReasonMissing        StaleReason = "missing"         // 要約がない
+	StaleReasonContentChanged StaleReason = "content_changed" // 要約の生成後にファイルの内容が変わった
+	StaleReasonPromptOutdated StaleReason = "prompt_outdated" // 要約の生成に使ったプロンプトのバージョンが現在と異なる
+)
+
+// StaleFile は再生成が必要なファイル要約
+type StaleFile struct {
+	File          *ingestion.File
+	Reason        StaleReason
+	PromptVersion string // 要約の生成に使ったプロンプトのバージョン（記録がない場合は空）
+}
+
+// RefreshOptions はファイル要約の再生成の設定
+type RefreshOptions struct {
+	Concurrency int  // 並列度（0 以下の場合は既定値）
+	DryRun      bool // 再生成が必要なファイルの検出のみ行う
+	FilesOnly   bool // ディレクトリ・アーキテクチャ要約を更新しない
+}
+
+// RefreshResult はファイル要約の再生成の結果
+type RefreshResult struct {
+	SnapshotID    uuid.UUID
+	TotalFiles    int
+	PromptVersion string // 現在のファイル要約のプロンプトのバージョン
+	Stale         []*StaleFile
+	Regenerated   int
+	Failed        int
+	Skipped       int // 予算超過でスキップしたファイル数
+}
+
+// FindStale はスナップショットのファイルのうち、要約がない・内容が変わった・プロンプトのバージョンが古いものを検出する
+// 戻り値は検出結果のみを設定した RefreshResult（再生成の件数は 0）
+func (s *FileSummarizer) FindStale(ctx context.Context, snapshotID uuid.UUID) (*RefreshResult, error) {
+	current, err := s.prompts.Resolve(ctx, prompt.NameFileSummary)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve prompt template: %w", err)
+	}
+
+	files, err := s.ingestionRepo.ListFilesBySnapshot(ctx, snapshotID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+	summaries, err := s.summaryRepo.ListFileSummariesBySnapshot(ctx, snapshotID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list file summaries: %w", err)
+	}
+	byPath := make(map[string]*Summary, len(summaries))
+	for _, summary := range summaries {
+		byPath[summary.TargetPath] = summary
+	}
+
+	var stale []*StaleFile
+	for _, file := range files {
+		existing, ok := byPath[file.Path]
+		if !ok {
+			stale = append(stale, &StaleFile{File: file, Reason: StaleReasonMissing})
+			continue
+		}
+		version, _ := existing.Metadata["prompt_version"].(string)
+		switch {
+		case existing.SourceHash != s.hasher.HashFileSource(file.ContentHash):
+			stale = append(stale, &StaleFile{File: file, Reason: StaleReasonContentChanged, PromptVersion: version})
+		case version != current.Version:
+			// バージョンを記録する前に生成した要約も古いものとして扱う
+			stale = append(stale, &StaleFile{File: file, Reason: StaleReasonPromptOutdated, PromptVersion: version})
+		}
+	}
+	return &RefreshResult{
+		SnapshotID:    snapshotID,
+		TotalFiles:    len(files),
+		PromptVersion: current.Version,
+		Stale:         stale,
+	}, nil
+}
+
+// Refresh は再生成が必要なファイル要約のみを再生成し、影響するディレクトリ・アーキテクチャ要約を差分更新する
+// 予算を超過した場合は残りのファイルをスキップし、abort の場合は usage.ErrBudgetExceeded を返す
+func (s *SummaryService) Refresh(ctx context.Context, snapshotID uuid.UUID, opts RefreshOptions) (*RefreshResult, error) {
+	result, err := s.fileSummarizer.FindStale(ctx, snapshotID)
+	if err != nil {
+		return nil, err
+	}
+	stale := result.Stale
+	if opts.DryRun || len(stale) == 0 {
+		return result, nil
+	}
+
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = s.fileSummarizer.concurrency
+	}
+
+	s.logger.Info("refreshing stale file summaries",
+		"snapshot_id", snapshotID,
+		"stale", len(stale),
+		"concurrency", concurrency)
+
+	taskCh := make(chan *StaleFile, len(stale))
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	for range concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for t := range taskCh {
+				// 予算超過以降の残りのファイルはスキップする（downgrade・abort 共通）
+				if tracker := usage.FromContext(ctx); tracker != nil && tracker.Exceeded() {
+					mu.Lock()
+					result.Skipped++
+					mu.Unlock()
+					continue
+				}
+				_, err := s.fileSummarizer.Generate(ctx, snapshotID, t.File)
+				mu.Lock()
+				switch {
+				case errors.Is(err, usage.ErrBudgetExceeded):
+					result.Skipped++
+				case err != nil:
+					result.Failed++
+					s.logger.Warn("failed to refresh file summary", "path", t.File.Path, "reason", t.Reason, "error", err)
+				default:
+					result.Regenerated++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, t := range stale {
+		taskCh <- t
+	}
+	close(taskCh)
+	wg.Wait()
+
+	s.logger.Info("completed file summary refresh",
+		"snapshot_id", snapshotID,
+		"regenerated", result.Regenerated,
+		"failed", result.Failed,
+		"skipped", result.Skipped)
+
+	if result.Regenerated == 0 || opts.FilesOnly {
+		return result, usage.Check(ctx)
+	}
+
+	// ファイル要約が変わったディレクトリとアーキテクチャ要約を差分更新する
+	if s.skipForBudget(ctx, "directory") {
+		return result, usage.Check(ctx)
+	}
+	if err := s.dirSummarizer.GenerateForSnapshot(ctx, snapshotID); err != nil {
+		return result, fmt.Errorf("failed to generate directory summaries: %w", err)
+	}
+	if s.skipForBudget(ctx, "architecture") {
+		return result, usage.Check(ctx)
+	}
+	if err := s.archSummarizer.Generate(ctx, snapshotID); err != nil {
+		return result, fmt.Errorf("failed to generate architecture summaries: %w", err)
+	}
+	return result, nil
+}
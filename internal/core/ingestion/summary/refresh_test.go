@@ -0,0 +1,187 @@
+package summary
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jinford/dev-rag/internal/core/ingestion"
+	"github.com/jinford/dev-rag/internal/core/prompt"
+	"github.com/jinford/dev-rag/internal/core/usage"
+)
+
+// fakeIngestionRepository はファイル・チャンクの取得のみを実装する（他のメソッドは呼び出されない前提）
+type fakeIngestionRepository struct {
+	ingestion.Repository
+	files []*ingestion.File
+}
+
+func (r *fakeIngestionRepository) ListFilesBySnapshot(ctx context.Context, snapshotID uuid.UUID) ([]*ingestion.File, error) {
+	return r.files, nil
+}
+
+func (r *fakeIngestionRepository) ListChunksByFile(ctx context.Context, fileID uuid.UUID) ([]*ingestion.Chunk, error) {
+	return []*ingestion.Chunk{{FileID: fileID, Content: "package main"}}, nil
+}
+
+// fakeSummaryRepository はファイル要約の読み書きのみを実装する
+type fakeSummaryRepository struct {
+	Repository
+	mu        sync.Mutex
+	summaries map[string]*Summary
+}
+
+func (r *fakeSummaryRepository) ListFileSummariesBySnapshot(ctx context.Context, snapshotID uuid.UUID) ([]*Summary, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var summaries []*Summary
+	for _, s := range r.summaries {
+		summaries = append(summaries, s)
+	}
+	return summaries, nil
+}
+
+func (r *fakeSummaryRepository) GetFileSummary(ctx context.Context, snapshotID uuid.UUID, path string) (mo.Option[*Summary], error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if s, ok := r.summaries[path]; ok {
+		return mo.Some(s), nil
+	}
+	return mo.None[*Summary](), nil
+}
+
+func (r *fakeSummaryRepository) CreateSummary(ctx context.Context, s *Summary) (*Summary, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.summaries[s.TargetPath] = s
+	return s, nil
+}
+
+func (r *fakeSummaryRepository) UpdateSummary(ctx context.Context, s *Summary) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.summaries[s.TargetPath] = s
+	return nil
+}
+
+func (r *fakeSummaryRepository) UpsertSummaryEmbedding(ctx context.Context, e *SummaryEmbedding) error {
+	return nil
+}
+
+type countingLLM struct {
+	mu    sync.Mutex
+	calls int
+}
+
+func (l *countingLLM) GenerateCompletion(ctx context.Context, prompt string) (string, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.calls++
+	return "要約", nil
+}
+
+type fakeEmbedder struct{}
+
+func (fakeEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	return []float32{0.1}, nil
+}
+
+func (fakeEmbedder) ModelName() string { return "test-embedding" }
+
+func newRefreshFixture(t *testing.T) (*SummaryService, *countingLLM, *fakeSummaryRepository) {
+	t.Helper()
+	current, err := prompt.Builtin(prompt.NameFileSummary)
+	require.NoError(t, err)
+
+	files := []*ingestion.File{
+		{ID: uuid.New(), Path: "fresh.go", ContentHash: "h-fresh"},
+		{ID: uuid.New(), Path: "changed.go", ContentHash: "h-new"},
+		{ID: uuid.New(), Path: "outdated.go", ContentHash: "h-outdated"},
+		{ID: uuid.New(), Path: "unversioned.go", ContentHash: "h-unversioned"},
+		{ID: uuid.New(), Path: "missing.go", ContentHash: "h-missing"},
+	}
+	summaryRepo := &fakeSummaryRepository{summaries: map[string]*Summary{
+		"fresh.go":       {ID: uuid.New(), TargetPath: "fresh.go", SourceHash: "h-fresh", Metadata: map[string]any{"prompt_version": current.Version}},
+		"changed.go":     {ID: uuid.New(), TargetPath: "changed.go", SourceHash: "h-old", Metadata: map[string]any{"prompt_version": current.Version}},
+		"outdated.go":    {ID: uuid.New(), TargetPath: "outdated.go", SourceHash: "h-outdated", Metadata: map[string]any{"prompt_version": "0.9"}},
+		"unversioned.go": {ID: uuid.New(), TargetPath: "unversioned.go", SourceHash: "h-unversioned", Metadata: map[string]any{}},
+	}}
+
+	llm := &countingLLM{}
+	svc := NewSummaryService(&fakeIngestionRepository{files: files}, summaryRepo, llm, fakeEmbedder{},
+		WithSummaryLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+	)
+	return svc, llm, summaryRepo
+}
+
+func TestFileSummarizer_FindStale(t *testing.T) {
+	svc, _, _ := newRefreshFixture(t)
+
+	result, err := svc.fileSummarizer.FindStale(context.Background(), uuid.New())
+	require.NoError(t, err)
+	assert.Equal(t, 5, result.TotalFiles)
+	assert.Equal(t, "1.0", result.PromptVersion)
+
+	reasons := make(map[string]StaleReason)
+	for _, f := range result.Stale {
+		reasons[f.File.Path] = f.Reason
+	}
+	assert.Equal(t, map[string]StaleReason{
+		"changed.go":     StaleReasonContentChanged,
+		"outdated.go":    StaleReasonPromptOutdated,
+		"unversioned.go": StaleReasonPromptOutdated,
+		"missing.go":     StaleReasonMissing,
+	}, reasons)
+}
+
+func TestSummaryService_Refresh(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("dry run does not call the LLM", func(t *testing.T) {
+		svc, llm, _ := newRefreshFixture(t)
+		result, err := svc.Refresh(ctx, uuid.New(), RefreshOptions{DryRun: true})
+		require.NoError(t, err)
+		assert.Len(t, result.Stale, 4)
+		assert.Zero(t, result.Regenerated)
+		assert.Zero(t, llm.calls)
+	})
+
+	t.Run("regenerates only stale files", func(t *testing.T) {
+		svc, llm, repo := newRefreshFixture(t)
+		result, err := svc.Refresh(ctx, uuid.New(), RefreshOptions{Concurrency: 2, FilesOnly: true})
+		require.NoError(t, err)
+		assert.Equal(t, 4, result.Regenerated)
+		assert.Zero(t, result.Failed)
+		assert.Equal(t, 4, llm.calls)
+
+		for path, s := range repo.summaries {
+			assert.Equal(t, "1.0", s.Metadata["prompt_version"], path)
+		}
+		assert.Equal(t, "h-new", repo.summaries["changed.go"].SourceHash)
+
+		// 再生成後は再生成が必要なファイルはない
+		again, err := svc.Refresh(ctx, uuid.New(), RefreshOptions{FilesOnly: true})
+		require.NoError(t, err)
+		assert.Empty(t, again.Stale)
+		assert.Equal(t, 4, llm.calls)
+	})
+
+	t.Run("skips remaining files once the budget is exceeded", func(t *testing.T) {
+		svc, llm, _ := newRefreshFixture(t)
+		tracker := usage.NewTracker(usage.DefaultPricing(), usage.Budget{MaxCostUSD: 0.01, Action: usage.BudgetActionDowngrade})
+		tracker.Add(usage.KindSummary, "gpt-4o", 10_000_000, 0)
+
+		result, err := svc.Refresh(usage.WithTracker(ctx, tracker), uuid.New(), RefreshOptions{FilesOnly: true})
+		require.NoError(t, err)
+		assert.Equal(t, 4, result.Skipped)
+		assert.Zero(t, result.Regenerated)
+		assert.Zero(t, llm.calls)
+	})
+}
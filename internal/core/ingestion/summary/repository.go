@@ -5,6 +5,8 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/samber/mo"
+
+	"github.com/jinford/dev-rag/internal/core/prompt"
 )
 
 // Repository は要約のデータアクセスインターフェース
@@ -31,6 +33,13 @@ type Repository interface {
 	CreateSummaryEmbedding(ctx context.Context, e *SummaryEmbedding) error
 	UpsertSummaryEmbedding(ctx context.Context, e *SummaryEmbedding) error
 	GetSummaryEmbedding(ctx context.Context, summaryID uuid.UUID) (mo.Option[*SummaryEmbedding], error)
+
+	// プロダクト全体の要約
+	ListLatestArchitectureSummariesByProduct(ctx context.Context, productID uuid.UUID) ([]*SourceArchitectureSummary, error)
+	GetProductSummary(ctx context.Context, productID uuid.UUID) (mo.Option[*ProductSummary], error)
+	UpsertProductSummary(ctx context.Context, s *ProductSummary) error
+	// FillProductDescription はプロダクトの説明が未設定、または previous（前回自動で設定した説明）のままの場合に説明を設定する
+	FillProductDescription(ctx context.Context, productID uuid.UUID, description, previous string) (bool, error)
 }
 
 // LLMClient はLLM呼び出しのインターフェース
@@ -38,6 +47,13 @@ type LLMClient interface {
 	GenerateCompletion(ctx context.Context, prompt string) (string, error)
 }
 
+// PromptRenderer はプロンプトテンプレートを展開するインターフェース
+// Resolve は要約の再生成で、記録したバージョンと現在のバージョンを比較するために使用する
+type PromptRenderer interface {
+	Render(ctx context.Context, name string, data any) (*prompt.Rendered, error)
+	Resolve(ctx context.Context, name string) (*prompt.Template, error)
+}
+
 // Embedder はEmbedding生成のインターフェース
 type Embedder interface {
 	Embed(ctx context.Context, text string) ([]float32, error)
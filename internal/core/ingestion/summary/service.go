@@ -7,6 +7,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jinford/dev-rag/internal/core/ingestion"
+	"github.com/jinford/dev-rag/internal/core/usage"
 )
 
 // SummaryService は階層的要約生成のオーケストレーター
@@ -14,6 +15,9 @@ type SummaryService struct {
 	fileSummarizer *FileSummarizer
 	dirSummarizer  *DirectorySummarizer
 	archSummarizer *ArchitectureSummarizer
+	prodSummarizer *ProductSummarizer
+	ingestionRepo  ingestion.Repository
+	prompts        PromptRenderer
 	logger         *slog.Logger
 }
 
@@ -27,6 +31,14 @@ func WithSummaryLogger(logger *slog.Logger) SummaryServiceOption {
 	}
 }
 
+// WithSummaryPrompts は要約のプロンプトを展開するテンプレートを設定する（既定は組み込みのテンプレート）
+// 展開したテンプレートのバージョンは要約のメタデータ（prompt_version）に記録する
+func WithSummaryPrompts(renderer PromptRenderer) SummaryServiceOption {
+	return func(s *SummaryService) {
+		s.prompts = renderer
+	}
+}
+
 // NewSummaryService は新しいSummaryServiceを作成
 func NewSummaryService(
 	ingestionRepo ingestion.Repository,
@@ -36,7 +48,8 @@ func NewSummaryService(
 	opts ...SummaryServiceOption,
 ) *SummaryService {
 	svc := &SummaryService{
-		logger: slog.Default(),
+		ingestionRepo: ingestionRepo,
+		logger:        slog.Default(),
 	}
 
 	for _, opt := range opts {
@@ -49,6 +62,13 @@ func NewSummaryService(
 	svc.fileSummarizer = NewFileSummarizer(ingestionRepo, summaryRepo, llm, embedder, svc.logger)
 	svc.dirSummarizer = NewDirectorySummarizer(ingestionRepo, summaryRepo, llm, embedder, svc.logger)
 	svc.archSummarizer = NewArchitectureSummarizer(summaryRepo, llm, embedder, svc.logger)
+	svc.prodSummarizer = NewProductSummarizer(summaryRepo, llm, embedder, svc.logger)
+	if svc.prompts != nil {
+		svc.fileSummarizer.prompts = svc.prompts
+		svc.dirSummarizer.prompts = svc.prompts
+		svc.archSummarizer.prompts = svc.prompts
+		svc.prodSummarizer.prompts = svc.prompts
+	}
 
 	return svc
 }
@@ -59,18 +79,27 @@ func (s *SummaryService) GenerateForSnapshot(ctx context.Context, snapshotID uui
 	s.logger.Info("starting summary generation", "snapshot_id", snapshotID)
 
 	// 1. ファイル要約を生成
+	if s.skipForBudget(ctx, "file") {
+		return usage.Check(ctx)
+	}
 	s.logger.Info("generating file summaries...")
 	if err := s.fileSummarizer.GenerateForSnapshot(ctx, snapshotID); err != nil {
 		return fmt.Errorf("failed to generate file summaries: %w", err)
 	}
 
 	// 2. ディレクトリ要約を生成（深い階層から順に）
+	if s.skipForBudget(ctx, "directory") {
+		return usage.Check(ctx)
+	}
 	s.logger.Info("generating directory summaries...")
 	if err := s.dirSummarizer.GenerateForSnapshot(ctx, snapshotID); err != nil {
 		return fmt.Errorf("failed to generate directory summaries: %w", err)
 	}
 
 	// 3. アーキテクチャ要約を生成
+	if s.skipForBudget(ctx, "architecture") {
+		return usage.Check(ctx)
+	}
 	s.logger.Info("generating architecture summaries...")
 	if err := s.archSummarizer.Generate(ctx, snapshotID); err != nil {
 		return fmt.Errorf("failed to generate architecture summaries: %w", err)
@@ -80,6 +109,42 @@ func (s *SummaryService) GenerateForSnapshot(ctx context.Context, snapshotID uui
 	return nil
 }
 
+// GenerateForProduct はプロダクト全体の要約を生成（差分更新）
+// インデックス後に GenerateForSnapshot でアーキテクチャ要約を更新してから呼び出す
+func (s *SummaryService) GenerateForProduct(ctx context.Context, productName string) error {
+	if s.skipForBudget(ctx, "product") {
+		return usage.Check(ctx)
+	}
+
+	productOpt, err := s.ingestionRepo.GetProductByName(ctx, productName)
+	if err != nil {
+		return fmt.Errorf("failed to get product: %w", err)
+	}
+	product, ok := productOpt.Get()
+	if !ok {
+		return fmt.Errorf("product not found: %s", productName)
+	}
+
+	if err := s.prodSummarizer.Generate(ctx, product.ID, product.Name); err != nil {
+		return fmt.Errorf("failed to generate product summary: %w", err)
+	}
+	return nil
+}
+
+// skipForBudget は予算超過時に以降の要約生成をスキップすべきかを判定する
+func (s *SummaryService) skipForBudget(ctx context.Context, stage string) bool {
+	tracker := usage.FromContext(ctx)
+	if tracker == nil || !tracker.Exceeded() {
+		return false
+	}
+	s.logger.Warn("budget exceeded, skipping remaining summaries",
+		"stage", stage,
+		"estimated_cost_usd", tracker.TotalCost(),
+		"budget_usd", tracker.Budget().MaxCostUSD,
+		"action", tracker.Budget().Action)
+	return true
+}
+
 // GenerateFileSummaries はファイル要約のみを生成
 func (s *SummaryService) GenerateFileSummaries(ctx context.Context, snapshotID uuid.UUID) error {
 	return s.fileSummarizer.GenerateForSnapshot(ctx, snapshotID)
@@ -0,0 +1,206 @@
+package integrity
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// defaultEmbedBatchSize は Embedder が最大バッチサイズを返さない場合の再生成のバッチサイズ
+	defaultEmbedBatchSize = 100
+)
+
+// Embedding は再生成した Embedding
+type Embedding struct {
+	ChunkID uuid.UUID
+	Vector  []float32
+	Model   string
+}
+
+// Repository は不整合の検出と修復に使用するデータアクセスのインターフェース
+// テスト時のモック用に消費者側で定義
+type Repository interface {
+	ListOrphanChunks(ctx context.Context) ([]*OrphanChunk, error)
+	ListEmptyFiles(ctx context.Context) ([]*EmptyFile, error)
+	ListSnapshotFileMismatches(ctx context.Context) ([]*SnapshotFileMismatch, error)
+	ListStaleLatestSources(ctx context.Context) ([]*StaleLatestSource, error)
+	ListDanglingDependencies(ctx context.Context) ([]*DanglingDependency, error)
+
+	SaveEmbeddings(ctx context.Context, embeddings []*Embedding) error
+	DeleteFile(ctx context.Context, fileID uuid.UUID) error
+	SetSnapshotFileIndexed(ctx context.Context, snapshotID uuid.UUID, filePath string, indexed bool) error
+	RefreshLatestChunks(ctx context.Context, sourceID uuid.UUID) error
+	DeleteDependency(ctx context.Context, id uuid.UUID) error
+}
+
+// Embedder は Embedding のないチャンクの再生成に使用するインターフェース
+type Embedder interface {
+	BatchEmbed(ctx context.Context, texts []string) ([][]float32, error)
+	ModelName() string
+	MaxBatchSize() int
+}
+
+// Checker はインデックスの不整合を検出・修復する
+type Checker struct {
+	repo     Repository
+	embedder Embedder
+	logger   *slog.Logger
+}
+
+// CheckerOption は Checker のオプション設定
+type CheckerOption func(*Checker)
+
+// WithCheckerLogger は Checker にロガーを設定する
+func WithCheckerLogger(logger *slog.Logger) CheckerOption {
+	return func(c *Checker) {
+		c.logger = logger
+	}
+}
+
+// NewChecker は新しい Checker を作成する
+func NewChecker(repo Repository, embedder Embedder, opts ...CheckerOption) *Checker {
+	c := &Checker{
+		repo:     repo,
+		embedder: embedder,
+		logger:   slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.logger == nil {
+		c.logger = slog.Default()
+	}
+	return c
+}
+
+// Verify はすべての種類の不整合を検出する
+func (c *Checker) Verify(ctx context.Context) (*Report, error) {
+	orphans, err := c.repo.ListOrphanChunks(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list chunks without embedding: %w", err)
+	}
+	emptyFiles, err := c.repo.ListEmptyFiles(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files without chunks: %w", err)
+	}
+	mismatches, err := c.repo.ListSnapshotFileMismatches(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshot file mismatches: %w", err)
+	}
+	staleSources, err := c.repo.ListStaleLatestSources(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stale latest flags: %w", err)
+	}
+	dangling, err := c.repo.ListDanglingDependencies(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dangling dependencies: %w", err)
+	}
+
+	return &Report{
+		OrphanChunks:           orphans,
+		EmptyFiles:             emptyFiles,
+		SnapshotFileMismatches: mismatches,
+		StaleLatestSources:     staleSources,
+		DanglingDependencies:   dangling,
+	}, nil
+}
+
+// Repair は検出した不整合を修復する
+//   - Embedding のないチャンク: Embedding を再生成する
+//   - チャンクのないファイル: ファイルを削除する
+//   - snapshot_files の不一致: チャンクを持つファイルの有無でインデックス済みフラグを付け直す
+//   - 最新フラグの不一致: ソースのチャンクの最新フラグを更新する
+//   - 別のスナップショットを結ぶ依存関係: 削除する
+//
+// 個々の修復の失敗は件数に含めて処理を続け、コンテキストのキャンセルのみエラーとして返す
+func (c *Checker) Repair(ctx context.Context, report *Report) ([]*RepairResult, error) {
+	results := make([]*RepairResult, 0, len(CheckKinds))
+
+	results = append(results, c.reembed(ctx, report.OrphanChunks))
+
+	files := &RepairResult{Kind: CheckFilesWithoutChunks}
+	for _, f := range report.EmptyFiles {
+		c.record(files, c.repo.DeleteFile(ctx, f.FileID), "path", f.Path)
+	}
+	results = append(results, files)
+
+	mismatches := &RepairResult{Kind: CheckSnapshotFileMismatch}
+	for _, m := range report.SnapshotFileMismatches {
+		c.record(mismatches, c.repo.SetSnapshotFileIndexed(ctx, m.SnapshotID, m.FilePath, m.HasChunks), "path", m.FilePath)
+	}
+	results = append(results, mismatches)
+
+	latest := &RepairResult{Kind: CheckStaleLatestFlags}
+	for _, s := range report.StaleLatestSources {
+		if err := c.repo.RefreshLatestChunks(ctx, s.SourceID); err != nil {
+			c.logger.Warn("最新フラグの更新に失敗", "source", s.SourceName, "error", err)
+			latest.Failed += s.StaleChunks
+			continue
+		}
+		latest.Repaired += s.StaleChunks
+	}
+	results = append(results, latest)
+
+	deps := &RepairResult{Kind: CheckDanglingDependencies}
+	for _, d := range report.DanglingDependencies {
+		c.record(deps, c.repo.DeleteDependency(ctx, d.ID), "dependencyID", d.ID)
+	}
+	results = append(results, deps)
+
+	if err := ctx.Err(); err != nil {
+		return results, err
+	}
+	return results, nil
+}
+
+// reembed は Embedding のないチャンクの Embedding をバッチで再生成して保存する
+func (c *Checker) reembed(ctx context.Context, chunks []*OrphanChunk) *RepairResult {
+	result := &RepairResult{Kind: CheckChunksWithoutEmbedding}
+	batchSize := c.embedder.MaxBatchSize()
+	if batchSize <= 0 {
+		batchSize = defaultEmbedBatchSize
+	}
+
+	for start := 0; start < len(chunks); start += batchSize {
+		batch := chunks[start:min(start+batchSize, len(chunks))]
+		texts := make([]string, len(batch))
+		for i, chunk := range batch {
+			texts[i] = chunk.Content
+		}
+
+		vectors, err := c.embedder.BatchEmbed(ctx, texts)
+		if err == nil && len(vectors) != len(batch) {
+			err = fmt.Errorf("embedding count mismatch: expected %d, got %d", len(batch), len(vectors))
+		}
+		if err != nil {
+			c.logger.Warn("Embeddingの再生成に失敗", "batchSize", len(batch), "error", err)
+			result.Failed += len(batch)
+			continue
+		}
+
+		embeddings := make([]*Embedding, len(batch))
+		for i, chunk := range batch {
+			embeddings[i] = &Embedding{ChunkID: chunk.ChunkID, Vector: vectors[i], Model: c.embedder.ModelName()}
+		}
+		if err := c.repo.SaveEmbeddings(ctx, embeddings); err != nil {
+			c.logger.Warn("Embeddingの保存に失敗", "batchSize", len(batch), "error", err)
+			result.Failed += len(batch)
+			continue
+		}
+		result.Repaired += len(batch)
+	}
+	return result
+}
+
+// record は1件の修復の結果を集計する（失敗は警告ログに記録する）
+func (c *Checker) record(result *RepairResult, err error, key string, value any) {
+	if err != nil {
+		c.logger.Warn("不整合の修復に失敗", "kind", result.Kind, key, value, "error", err)
+		result.Failed++
+		return
+	}
+	result.Repaired++
+}
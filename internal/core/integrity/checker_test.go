@@ -0,0 +1,153 @@
+package integrity
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRepository struct {
+	report *Report
+
+	saved         []*Embedding
+	deletedFiles  []uuid.UUID
+	marked        map[string]bool
+	refreshed     []uuid.UUID
+	deletedDeps   []uuid.UUID
+	deleteFileErr error
+}
+
+func (f *fakeRepository) ListOrphanChunks(context.Context) ([]*OrphanChunk, error) {
+	return f.report.OrphanChunks, nil
+}
+
+func (f *fakeRepository) ListEmptyFiles(context.Context) ([]*EmptyFile, error) {
+	return f.report.EmptyFiles, nil
+}
+
+func (f *fakeRepository) ListSnapshotFileMismatches(context.Context) ([]*SnapshotFileMismatch, error) {
+	return f.report.SnapshotFileMismatches, nil
+}
+
+func (f *fakeRepository) ListStaleLatestSources(context.Context) ([]*StaleLatestSource, error) {
+	return f.report.StaleLatestSources, nil
+}
+
+func (f *fakeRepository) ListDanglingDependencies(context.Context) ([]*DanglingDependency, error) {
+	return f.report.DanglingDependencies, nil
+}
+
+func (f *fakeRepository) SaveEmbeddings(_ context.Context, embeddings []*Embedding) error {
+	f.saved = append(f.saved, embeddings...)
+	return nil
+}
+
+func (f *fakeRepository) DeleteFile(_ context.Context, fileID uuid.UUID) error {
+	if f.deleteFileErr != nil {
+		return f.deleteFileErr
+	}
+	f.deletedFiles = append(f.deletedFiles, fileID)
+	return nil
+}
+
+func (f *fakeRepository) SetSnapshotFileIndexed(_ context.Context, _ uuid.UUID, filePath string, indexed bool) error {
+	if f.marked == nil {
+		f.marked = make(map[string]bool)
+	}
+	f.marked[filePath] = indexed
+	return nil
+}
+
+func (f *fakeRepository) RefreshLatestChunks(_ context.Context, sourceID uuid.UUID) error {
+	f.refreshed = append(f.refreshed, sourceID)
+	return nil
+}
+
+func (f *fakeRepository) DeleteDependency(_ context.Context, id uuid.UUID) error {
+	f.deletedDeps = append(f.deletedDeps, id)
+	return nil
+}
+
+type fakeEmbedder struct {
+	batches int
+}
+
+func (e *fakeEmbedder) BatchEmbed(_ context.Context, texts []string) ([][]float32, error) {
+	e.batches++
+	vectors := make([][]float32, len(texts))
+	for i := range texts {
+		vectors[i] = []float32{float32(len(texts[i]))}
+	}
+	return vectors, nil
+}
+
+func (e *fakeEmbedder) ModelName() string { return "test-embedding" }
+
+func (e *fakeEmbedder) MaxBatchSize() int { return 2 }
+
+func testReport() *Report {
+	return &Report{
+		OrphanChunks: []*OrphanChunk{
+			{ChunkID: uuid.New(), FilePath: "a.go", Content: "a"},
+			{ChunkID: uuid.New(), FilePath: "a.go", Content: "bb"},
+			{ChunkID: uuid.New(), FilePath: "b.go", Content: "ccc"},
+		},
+		EmptyFiles:             []*EmptyFile{{FileID: uuid.New(), Path: "empty.go"}},
+		SnapshotFileMismatches: []*SnapshotFileMismatch{{SnapshotID: uuid.New(), FilePath: "empty.go", Indexed: true, HasChunks: false}},
+		StaleLatestSources:     []*StaleLatestSource{{SourceID: uuid.New(), SourceName: "api", StaleChunks: 4}},
+		DanglingDependencies:   []*DanglingDependency{{ID: uuid.New(), DepType: "call"}},
+	}
+}
+
+func TestVerify(t *testing.T) {
+	repo := &fakeRepository{report: testReport()}
+	report, err := NewChecker(repo, &fakeEmbedder{}).Verify(context.Background())
+	require.NoError(t, err)
+
+	assert.False(t, report.Healthy())
+	assert.Equal(t, 3, report.Count(CheckChunksWithoutEmbedding))
+	assert.Equal(t, 4, report.Count(CheckStaleLatestFlags))
+	assert.True(t, (&Report{}).Healthy())
+}
+
+func TestRepair(t *testing.T) {
+	report := testReport()
+	repo := &fakeRepository{report: report}
+	embedder := &fakeEmbedder{}
+
+	results, err := NewChecker(repo, embedder).Repair(context.Background(), report)
+	require.NoError(t, err)
+	require.Len(t, results, len(CheckKinds))
+
+	// 最大バッチサイズごとに再生成する
+	assert.Equal(t, 2, embedder.batches)
+	require.Len(t, repo.saved, 3)
+	assert.Equal(t, report.OrphanChunks[2].ChunkID, repo.saved[2].ChunkID)
+	assert.Equal(t, "test-embedding", repo.saved[2].Model)
+
+	assert.Equal(t, []uuid.UUID{report.EmptyFiles[0].FileID}, repo.deletedFiles)
+	assert.Equal(t, map[string]bool{"empty.go": false}, repo.marked)
+	assert.Equal(t, []uuid.UUID{report.StaleLatestSources[0].SourceID}, repo.refreshed)
+	assert.Equal(t, []uuid.UUID{report.DanglingDependencies[0].ID}, repo.deletedDeps)
+
+	for _, r := range results {
+		assert.Equal(t, report.Count(r.Kind), r.Repaired, r.Kind)
+		assert.Zero(t, r.Failed, r.Kind)
+	}
+}
+
+func TestRepair_ContinuesOnFailure(t *testing.T) {
+	report := testReport()
+	repo := &fakeRepository{report: report, deleteFileErr: errors.New("boom")}
+
+	results, err := NewChecker(repo, &fakeEmbedder{}).Repair(context.Background(), report)
+	require.NoError(t, err)
+
+	assert.Equal(t, CheckFilesWithoutChunks, results[1].Kind)
+	assert.Equal(t, 1, results[1].Failed)
+	assert.Len(t, repo.deletedDeps, 1)
+}
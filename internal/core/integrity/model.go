@@ -0,0 +1,113 @@
+// Package integrity はインデックスのデータの不整合（Embedding のないチャンク・チャンクのないファイルなど）を検出し、修復する
+package integrity
+
+import (
+	"github.com/google/uuid"
+)
+
+// CheckKind は不整合の種類
+type CheckKind string
+
+const (
+	CheckChunksWithoutEmbedding CheckKind = "chunks_without_embedding" // Embedding のないチャンク（検索にヒットしない）
+	CheckFilesWithoutChunks     CheckKind = "files_without_chunks"     // チャンクのないファイル
+	CheckSnapshotFileMismatch   CheckKind = "snapshot_file_mismatch"   // snapshot_files のインデックス済みフラグと files の不一致
+	CheckStaleLatestFlags       CheckKind = "stale_latest_flags"       // 最新フラグ（is_latest）が最新のスナップショットと一致しないチャンク
+	CheckDanglingDependencies   CheckKind = "dangling_dependencies"    // 同じソースの別のスナップショットのチャンクを結ぶ依存関係
+)
+
+// CheckKinds は検査の種類の一覧（表示順）
+var CheckKinds = []CheckKind{
+	CheckChunksWithoutEmbedding,
+	CheckFilesWithoutChunks,
+	CheckSnapshotFileMismatch,
+	CheckStaleLatestFlags,
+	CheckDanglingDependencies,
+}
+
+// OrphanChunk は Embedding のないチャンク
+type OrphanChunk struct {
+	ChunkID   uuid.UUID
+	FilePath  string
+	StartLine int
+	EndLine   int
+	Content   string
+}
+
+// EmptyFile はチャンクのないファイル
+type EmptyFile struct {
+	FileID     uuid.UUID
+	SnapshotID uuid.UUID
+	Path       string
+}
+
+// SnapshotFileMismatch は snapshot_files のインデックス済みフラグがチャンクを持つファイルの有無と一致しない行
+type SnapshotFileMismatch struct {
+	SnapshotID uuid.UUID
+	FilePath   string
+	Indexed    bool // snapshot_files に記録されたフラグ
+	HasChunks  bool // チャンクを持つファイルがあるか（修復後のフラグ）
+}
+
+// StaleLatestSource は最新フラグが一致しないチャンクを持つソース
+type StaleLatestSource struct {
+	SourceID    uuid.UUID
+	SourceName  string
+	StaleChunks int
+}
+
+// DanglingDependency は同じソースの別のスナップショットのチャンクを結ぶ依存関係
+type DanglingDependency struct {
+	ID           uuid.UUID
+	DepType      string
+	Symbol       string
+	FromFilePath string
+	ToFilePath   string
+}
+
+// Report は検査の結果
+type Report struct {
+	OrphanChunks           []*OrphanChunk
+	EmptyFiles             []*EmptyFile
+	SnapshotFileMismatches []*SnapshotFileMismatch
+	StaleLatestSources     []*StaleLatestSource
+	DanglingDependencies   []*DanglingDependency
+}
+
+// Count は種類ごとの不整合の件数を返す（最新フラグはチャンク数）
+func (r *Report) Count(kind CheckKind) int {
+	switch kind {
+	case CheckChunksWithoutEmbedding:
+		return len(r.OrphanChunks)
+	case CheckFilesWithoutChunks:
+		return len(r.EmptyFiles)
+	case CheckSnapshotFileMismatch:
+		return len(r.SnapshotFileMismatches)
+	case CheckStaleLatestFlags:
+		total := 0
+		for _, s := range r.StaleLatestSources {
+			total += s.StaleChunks
+		}
+		return total
+	case CheckDanglingDependencies:
+		return len(r.DanglingDependencies)
+	}
+	return 0
+}
+
+// Healthy は不整合が1件もないかを返す
+func (r *Report) Healthy() bool {
+	for _, kind := range CheckKinds {
+		if r.Count(kind) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// RepairResult は種類ごとの修復の結果
+type RepairResult struct {
+	Kind     CheckKind
+	Repaired int
+	Failed   int
+}
@@ -0,0 +1,105 @@
+package license
+
+import "strings"
+
+// knownCategories は SPDX のライセンス識別子（-only・-or-later を除いたもの）ごとの分類
+var knownCategories = map[string]Category{
+	"MIT":                CategoryPermissive,
+	"MIT-0":              CategoryPermissive,
+	"Apache-1.1":         CategoryPermissive,
+	"Apache-2.0":         CategoryPermissive,
+	"BSD-2-Clause":       CategoryPermissive,
+	"BSD-3-Clause":       CategoryPermissive,
+	"BSD-3-Clause-Clear": CategoryPermissive,
+	"0BSD":               CategoryPermissive,
+	"ISC":                CategoryPermissive,
+	"Zlib":               CategoryPermissive,
+	"BSL-1.0":            CategoryPermissive,
+	"PSF-2.0":            CategoryPermissive,
+	"Python-2.0":         CategoryPermissive,
+	"PostgreSQL":         CategoryPermissive,
+	"X11":                CategoryPermissive,
+	"Unlicense":          CategoryPermissive,
+	"CC0-1.0":            CategoryPermissive,
+	"CC-BY-4.0":          CategoryPermissive,
+	"WTFPL":              CategoryPermissive,
+	"LGPL-2.0":           CategoryWeakCopyleft,
+	"LGPL-2.1":           CategoryWeakCopyleft,
+	"LGPL-3.0":           CategoryWeakCopyleft,
+	"MPL-1.1":            CategoryWeakCopyleft,
+	"MPL-2.0":            CategoryWeakCopyleft,
+	"EPL-1.0":            CategoryWeakCopyleft,
+	"EPL-2.0":            CategoryWeakCopyleft,
+	"CDDL-1.0":           CategoryWeakCopyleft,
+	"CDDL-1.1":           CategoryWeakCopyleft,
+	"GPL-2.0":            CategoryCopyleft,
+	"GPL-3.0":            CategoryCopyleft,
+	"AGPL-3.0":           CategoryCopyleft,
+	"EUPL-1.1":           CategoryCopyleft,
+	"EUPL-1.2":           CategoryCopyleft,
+	"OSL-3.0":            CategoryCopyleft,
+	"SSPL-1.0":           CategoryCopyleft,
+	"CC-BY-SA-4.0":       CategoryCopyleft,
+}
+
+// familyCategories はバージョンを判別できなかったライセンス（GPL など）のファミリーごとの分類
+var familyCategories = map[string]Category{
+	"GPL":  CategoryCopyleft,
+	"AGPL": CategoryCopyleft,
+	"LGPL": CategoryWeakCopyleft,
+	"MPL":  CategoryWeakCopyleft,
+	"EPL":  CategoryWeakCopyleft,
+}
+
+// Classify は SPDX のライセンス識別子・式を分類する
+// OR は最も制約の弱い選択肢、AND は最も制約の強いものを分類とし、WITH の例外条項は考慮しない
+func Classify(expression string) Category {
+	expression = strings.NewReplacer("(", " ", ")", " ").Replace(expression)
+	result := CategoryUnknown
+	for i, alternative := range splitOperator(expression, "OR") {
+		category := CategoryPermissive
+		for _, id := range splitOperator(alternative, "AND") {
+			if c := classifyID(id); c.severity() > category.severity() {
+				category = c
+			}
+		}
+		if i == 0 || category.severity() < result.severity() {
+			result = category
+		}
+	}
+	return result
+}
+
+// splitOperator は SPDX の式を演算子（大文字小文字を区別しない）で分割する
+func splitOperator(expression, operator string) []string {
+	var parts []string
+	var current []string
+	for _, token := range strings.Fields(expression) {
+		if strings.EqualFold(token, operator) {
+			parts = append(parts, strings.Join(current, " "))
+			current = nil
+			continue
+		}
+		current = append(current, token)
+	}
+	return append(parts, strings.Join(current, " "))
+}
+
+// classifyID は単一のライセンス識別子（WITH の例外条項を含む場合がある）を分類する
+func classifyID(id string) Category {
+	fields := strings.Fields(id)
+	if len(fields) == 0 {
+		return CategoryUnknown
+	}
+	id = strings.TrimSuffix(fields[0], "+")
+	id = strings.TrimSuffix(strings.TrimSuffix(id, "-only"), "-or-later")
+	for known, category := range knownCategories {
+		if strings.EqualFold(known, id) {
+			return category
+		}
+	}
+	if category, ok := familyCategories[strings.ToUpper(id)]; ok {
+		return category
+	}
+	return CategoryUnknown
+}
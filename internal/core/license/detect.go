@@ -0,0 +1,169 @@
+package license
+
+import (
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+const (
+	// headerMaxLines はライセンスヘッダーを探すファイル冒頭の行数
+	headerMaxLines = 30
+	// headerMaxBytes はライセンスヘッダーを探すファイル冒頭のバイト数
+	headerMaxBytes = 4096
+	// evidenceMaxLen は検出の根拠として記録する行の最大文字数
+	evidenceMaxLen = 200
+)
+
+// licenseFileNames はライセンスファイルの名前（拡張子を除き大文字にしたもの）
+var licenseFileNames = []string{"LICENSE", "LICENCE", "COPYING", "COPYING.LESSER", "UNLICENSE"}
+
+// headerSkipExts はライセンスヘッダーを探さない文書の拡張子（ライセンスについての記述をヘッダーと誤検出しないため）
+var headerSkipExts = []string{".md", ".markdown", ".rst", ".adoc", ".txt"}
+
+// IsLicenseFile はライセンスファイル（LICENSE・LICENCE・COPYING・UNLICENSE と、LICENSE-MIT などの派生）かを判定する
+func IsLicenseFile(p string) bool {
+	base := strings.ToUpper(path.Base(p))
+	switch path.Ext(base) {
+	case ".MD", ".TXT", ".RST":
+		base = strings.TrimSuffix(base, path.Ext(base))
+	}
+	if slices.Contains(licenseFileNames, base) {
+		return true
+	}
+	return strings.HasPrefix(base, "LICENSE-") || strings.HasPrefix(base, "LICENCE-")
+}
+
+// Detect はライセンスファイルの本文、またはソースファイル冒頭のライセンスヘッダーからライセンスを検出する
+// ライセンスファイルは本文から判別できない場合も分類を不明として返す。ヘッダーは SPDX-License-Identifier か既知のライセンスの文言がある場合のみ返す
+func Detect(p, content string) (*Finding, bool) {
+	if content == "" || strings.ContainsRune(content[:min(len(content), headerMaxBytes)], 0) {
+		return nil, false
+	}
+
+	if IsLicenseFile(p) {
+		finding := &Finding{FilePath: p, Kind: KindFile, Category: CategoryUnknown}
+		if id, evidence, ok := identify(content); ok {
+			finding.LicenseID = id
+			finding.Category = Classify(id)
+			finding.Evidence = evidence
+		} else {
+			finding.Evidence = firstLine(content)
+		}
+		return finding, true
+	}
+
+	if slices.Contains(headerSkipExts, strings.ToLower(path.Ext(p))) {
+		return nil, false
+	}
+	header := fileHeader(content)
+	if m := spdxPattern.FindStringSubmatch(header); m != nil {
+		id := strings.TrimSpace(m[1])
+		return &Finding{FilePath: p, Kind: KindHeader, LicenseID: id, Category: Classify(id), Evidence: truncate(strings.TrimSpace(m[0]))}, true
+	}
+	if id, evidence, ok := identify(header); ok {
+		return &Finding{FilePath: p, Kind: KindHeader, LicenseID: id, Category: Classify(id), Evidence: evidence}, true
+	}
+	return nil, false
+}
+
+// spdxPattern は SPDX-License-Identifier の行にマッチする（コメントの終端は含めない）
+var spdxPattern = regexp.MustCompile(`SPDX-License-Identifier:\s*([A-Za-z0-9.+\-() ]+)`)
+
+// licenseRule はライセンスの本文・ヘッダーの文言とライセンス識別子の対応
+type licenseRule struct {
+	id      string // GNU のライセンスはファミリー名（バージョンは文言の後から判別する）
+	pattern *regexp.Regexp
+}
+
+// licenseRules は判別するライセンスの文言（同じ位置にマッチした場合は先の規則を優先する）
+var licenseRules = []licenseRule{
+	{"AGPL", regexp.MustCompile(`(?i)GNU\s+AFFERO\s+GENERAL\s+PUBLIC\s+LICENSE`)},
+	{"LGPL", regexp.MustCompile(`(?i)GNU\s+(?:LESSER|LIBRARY)\s+GENERAL\s+PUBLIC\s+LICENSE`)},
+	{"GPL", regexp.MustCompile(`(?i)GNU\s+GENERAL\s+PUBLIC\s+LICENSE`)},
+	{"MPL-2.0", regexp.MustCompile(`(?i)Mozilla\s+Public\s+License,?\s+(?:v\.|Version)\s*2\.0`)},
+	{"EPL-2.0", regexp.MustCompile(`(?i)Eclipse\s+Public\s+License\s*[-,]?\s*(?:v\.?|Version)?\s*2\.0`)},
+	{"EPL-1.0", regexp.MustCompile(`(?i)Eclipse\s+Public\s+License`)},
+	{"Apache-2.0", regexp.MustCompile(`(?i)Apache\s+License,?\s+Version\s+2\.0`)},
+	{"BSL-1.0", regexp.MustCompile(`(?i)Boost\s+Software\s+License`)},
+	{"ISC", regexp.MustCompile(`(?i)Permission\s+to\s+use,\s+copy,\s+modify,\s+and/?or\s+distribute\s+this\s+software\s+for\s+any\s+purpose`)},
+	{"MIT", regexp.MustCompile(`(?i)Permission\s+is\s+hereby\s+granted,\s+free\s+of\s+charge|\bMIT\s+License\b|Licensed\s+under\s+the\s+MIT\b`)},
+	{"BSD-3-Clause", regexp.MustCompile(`(?i)Redistribution\s+and\s+use\s+in\s+source\s+and\s+binary\s+forms[\s\S]*Neither\s+the\s+name`)},
+	{"BSD-2-Clause", regexp.MustCompile(`(?i)Redistribution\s+and\s+use\s+in\s+source\s+and\s+binary\s+forms`)},
+	{"Unlicense", regexp.MustCompile(`(?i)This\s+is\s+free\s+and\s+unencumbered\s+software\s+released\s+into\s+the\s+public\s+domain`)},
+	{"CC0-1.0", regexp.MustCompile(`(?i)CC0\s+1\.0\s+Universal`)},
+}
+
+// gnuVersionPattern は GNU のライセンス名の後のバージョンの記載にマッチする
+var gnuVersionPattern = regexp.MustCompile(`(?i)version\s+(\d(?:\.\d)?)`)
+
+// identify は文書の最も前にある既知のライセンスの文言からライセンス識別子を判別する
+// GPL のライセンス本文は末尾で LGPL に言及するため、規則の順序ではなく文言の位置を優先する
+func identify(text string) (id, evidence string, ok bool) {
+	best := -1
+	var bestRule licenseRule
+	var bestEnd int
+	for _, rule := range licenseRules {
+		loc := rule.pattern.FindStringIndex(text)
+		if loc == nil || (best >= 0 && loc[0] >= best) {
+			continue
+		}
+		best, bestRule, bestEnd = loc[0], rule, loc[1]
+	}
+	if best < 0 {
+		return "", "", false
+	}
+
+	id = bestRule.id
+	switch id {
+	case "AGPL":
+		id = "AGPL-3.0"
+	case "LGPL", "GPL":
+		rest := text[bestEnd:min(len(text), bestEnd+2000)]
+		if m := gnuVersionPattern.FindStringSubmatch(rest); m != nil {
+			version := m[1]
+			if !strings.Contains(version, ".") {
+				version += ".0"
+			}
+			id += "-" + version
+		}
+	}
+	return id, lineAt(text, best), true
+}
+
+// fileHeader はファイル冒頭の headerMaxLines 行（最大 headerMaxBytes バイト）を返す
+func fileHeader(content string) string {
+	header := content[:min(len(content), headerMaxBytes)]
+	lines := strings.SplitN(header, "\n", headerMaxLines+1)
+	return strings.Join(lines[:min(len(lines), headerMaxLines)], "\n")
+}
+
+// lineAt は offset を含む行を前後の空白とコメント記号を除いて返す
+func lineAt(text string, offset int) string {
+	start := strings.LastIndex(text[:offset], "\n") + 1
+	end := strings.Index(text[offset:], "\n")
+	if end < 0 {
+		end = len(text)
+	} else {
+		end += offset
+	}
+	return truncate(strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(text[start:end]), "/*#;-!<>")))
+}
+
+// firstLine は空でない最初の行を返す
+func firstLine(text string) string {
+	for _, line := range strings.Split(text[:min(len(text), headerMaxBytes)], "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return truncate(line)
+		}
+	}
+	return ""
+}
+
+func truncate(s string) string {
+	if r := []rune(s); len(r) > evidenceMaxLen {
+		return string(r[:evidenceMaxLen]) + "…"
+	}
+	return s
+}
@@ -0,0 +1,87 @@
+package license
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsLicenseFile(t *testing.T) {
+	for _, p := range []string{"LICENSE", "LICENSE.md", "licence.txt", "COPYING", "COPYING.LESSER", "third_party/lib/LICENSE-MIT", "UNLICENSE"} {
+		assert.True(t, IsLicenseFile(p), p)
+	}
+	for _, p := range []string{"license.go", "docs/licensing.md", "LICENSES/README.md"} {
+		assert.False(t, IsLicenseFile(p), p)
+	}
+}
+
+func TestDetect_LicenseFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		id       string
+		category Category
+	}{
+		{"MIT", "MIT License\n\nCopyright (c) 2024 Acme\n\nPermission is hereby granted, free of charge, to any person", "MIT", CategoryPermissive},
+		{"Apache", "\n                                 Apache License\n                           Version 2.0, January 2004\n", "Apache-2.0", CategoryPermissive},
+		{"BSD-3-Clause", "Redistribution and use in source and binary forms, with or without\nmodification...\n3. Neither the name of the copyright holder", "BSD-3-Clause", CategoryPermissive},
+		{"GPL-3.0", "                    GNU GENERAL PUBLIC LICENSE\n                       Version 3, 29 June 2007\n...use the GNU Lesser General Public License instead of this License.", "GPL-3.0", CategoryCopyleft},
+		{"LGPL-2.1", "                  GNU LESSER GENERAL PUBLIC LICENSE\n                       Version 2.1, February 1999\n", "LGPL-2.1", CategoryWeakCopyleft},
+		{"AGPL", "GNU AFFERO GENERAL PUBLIC LICENSE\nVersion 3, 19 November 2007", "AGPL-3.0", CategoryCopyleft},
+		{"MPL", "Mozilla Public License Version 2.0\n==================================", "MPL-2.0", CategoryWeakCopyleft},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			finding, ok := Detect("LICENSE", tt.content)
+			require.True(t, ok)
+			assert.Equal(t, KindFile, finding.Kind)
+			assert.Equal(t, tt.id, finding.LicenseID)
+			assert.Equal(t, tt.category, finding.Category)
+			assert.NotEmpty(t, finding.Evidence)
+		})
+	}
+
+	// 判別できないライセンスファイルは分類を不明として返す
+	finding, ok := Detect("LICENSE.txt", "Copyright (c) 2024 Acme Corp.\nAll rights reserved.\n")
+	require.True(t, ok)
+	assert.Empty(t, finding.LicenseID)
+	assert.Equal(t, CategoryUnknown, finding.Category)
+	assert.Equal(t, "Copyright (c) 2024 Acme Corp.", finding.Evidence)
+}
+
+func TestDetect_Header(t *testing.T) {
+	finding, ok := Detect("pkg/lib.go", "// Copyright 2024 Acme\n// SPDX-License-Identifier: GPL-2.0-only OR MIT\n\npackage lib\n")
+	require.True(t, ok)
+	assert.Equal(t, &Finding{FilePath: "pkg/lib.go", Kind: KindHeader, LicenseID: "GPL-2.0-only OR MIT", Category: CategoryPermissive, Evidence: "SPDX-License-Identifier: GPL-2.0-only OR MIT"}, finding)
+
+	finding, ok = Detect("src/main.c", "/*\n * This program is free software; you can redistribute it and/or modify\n * it under the terms of the GNU General Public License as published by\n * the Free Software Foundation; either version 2 of the License, or\n */\n")
+	require.True(t, ok)
+	assert.Equal(t, "GPL-2.0", finding.LicenseID)
+	assert.Equal(t, CategoryCopyleft, finding.Category)
+	assert.Equal(t, "it under the terms of the GNU General Public License as published by", finding.Evidence)
+
+	_, ok = Detect("main.go", "package main\n")
+	assert.False(t, ok)
+	_, ok = Detect("docs/licensing.md", "本プロダクトは GNU General Public License を使用しない")
+	assert.False(t, ok, "文書はヘッダーを探さない")
+	_, ok = Detect("main.go", "package main\n"+strings.Repeat("\n", headerMaxLines)+"// SPDX-License-Identifier: MIT\n")
+	assert.False(t, ok, "冒頭の行以降は探さない")
+	_, ok = Detect("LICENSE", "\x00\x01binary")
+	assert.False(t, ok)
+}
+
+func TestClassify(t *testing.T) {
+	assert.Equal(t, CategoryPermissive, Classify("Apache-2.0"))
+	assert.Equal(t, CategoryPermissive, Classify("mit"))
+	assert.Equal(t, CategoryCopyleft, Classify("GPL-3.0-or-later"))
+	assert.Equal(t, CategoryCopyleft, Classify("GPL-2.0+"))
+	assert.Equal(t, CategoryCopyleft, Classify("GPL"), "バージョンのないファミリー名")
+	assert.Equal(t, CategoryWeakCopyleft, Classify("(MIT AND LGPL-2.1-only)"))
+	assert.Equal(t, CategoryPermissive, Classify("LGPL-2.1-only OR BSD-3-Clause"))
+	assert.Equal(t, CategoryCopyleft, Classify("GPL-2.0-only WITH Classpath-exception-2.0"))
+	assert.Equal(t, CategoryUnknown, Classify("LicenseRef-Proprietary"))
+	assert.Equal(t, CategoryUnknown, Classify("MIT AND LicenseRef-Proprietary"))
+	assert.Equal(t, CategoryUnknown, Classify(""))
+}
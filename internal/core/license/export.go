@@ -0,0 +1,95 @@
+package license
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// Format は出力形式
+type Format string
+
+const (
+	FormatTable    Format = "table"    // 端末向けの表
+	FormatJSON     Format = "json"     // 集計の結果
+	FormatMarkdown Format = "markdown" // Markdown の表
+)
+
+// Formats は出力形式の一覧
+var Formats = []Format{FormatTable, FormatJSON, FormatMarkdown}
+
+// Write はライセンスの集計を指定の形式で出力する
+// 表はライセンスごとの集計と、確認が必要なファイル（コピーレフト・判別できないライセンス）の2つを出力する
+func Write(w io.Writer, report *Report, format Format) error {
+	switch format {
+	case FormatTable:
+		return writeTable(w, report)
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	case FormatMarkdown:
+		return writeMarkdown(w, report)
+	default:
+		return fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+func writeTable(w io.Writer, report *Report) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "LICENSE\tCATEGORY\tSOURCES\tLICENSE FILES\tHEADERS")
+	for _, s := range report.Licenses {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%d\n",
+			licenseIDOrUnknown(s.LicenseID), s.Category.Label(), strings.Join(s.Sources, ","), s.LicenseFiles, s.HeaderFiles)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "\n要確認のファイル: %d件\n", len(report.Flagged))
+	if len(report.Flagged) == 0 {
+		return nil
+	}
+	tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CATEGORY\tLICENSE\tSOURCE\tFILE\tDETECTED BY\tEVIDENCE")
+	for _, f := range report.Flagged {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			f.Category.Label(), licenseIDOrUnknown(f.LicenseID), f.SourceName, f.FilePath, f.Kind.Label(), f.Evidence)
+	}
+	return tw.Flush()
+}
+
+func writeMarkdown(w io.Writer, report *Report) error {
+	var sb strings.Builder
+	sb.WriteString("| ライセンス | 分類 | ソース | ライセンスファイル | ヘッダー |\n")
+	sb.WriteString("|---|---|---|---|---|\n")
+	for _, s := range report.Licenses {
+		sb.WriteString(fmt.Sprintf("| %s | %s | %s | %d | %d |\n",
+			escapeCell(licenseIDOrUnknown(s.LicenseID)), s.Category.Label(), escapeCell(strings.Join(s.Sources, ", ")), s.LicenseFiles, s.HeaderFiles))
+	}
+	if len(report.Flagged) > 0 {
+		sb.WriteString("\n| 分類 | ライセンス | ソース | ファイル | 検出 | 根拠 |\n")
+		sb.WriteString("|---|---|---|---|---|---|\n")
+		for _, f := range report.Flagged {
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s | `%s` | %s | %s |\n",
+				f.Category.Label(), escapeCell(licenseIDOrUnknown(f.LicenseID)), escapeCell(f.SourceName), f.FilePath, f.Kind.Label(), escapeCell(f.Evidence)))
+		}
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// licenseIDOrUnknown は判別できないライセンスを "不明" として返す
+func licenseIDOrUnknown(id string) string {
+	if id == "" {
+		return "不明"
+	}
+	return id
+}
+
+// escapeCell は Markdown の表のセルで区切り文字として解釈される文字をエスケープする
+func escapeCell(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "|", `\|`), "\n", " ")
+}
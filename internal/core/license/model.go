@@ -0,0 +1,89 @@
+package license
+
+import "github.com/google/uuid"
+
+// Kind はライセンスを検出した場所の種類
+type Kind string
+
+const (
+	KindFile   Kind = "file"   // ライセンスファイル（LICENSE・COPYING など）
+	KindHeader Kind = "header" // ソースファイル冒頭のライセンスヘッダー（SPDX-License-Identifier を含む）
+)
+
+// Label は検出した場所の種類の表示名を返す
+func (k Kind) Label() string {
+	if k == KindFile {
+		return "ライセンスファイル"
+	}
+	return "ヘッダー"
+}
+
+// Category はライセンスの分類
+type Category string
+
+const (
+	CategoryPermissive   Category = "permissive"    // MIT・Apache-2.0・BSD など
+	CategoryWeakCopyleft Category = "weak_copyleft" // LGPL・MPL・EPL など（ファイル・ライブラリ単位のコピーレフト）
+	CategoryCopyleft     Category = "copyleft"      // GPL・AGPL など
+	CategoryUnknown      Category = "unknown"       // 判別できないライセンス
+)
+
+// Label はライセンスの分類の表示名を返す
+func (c Category) Label() string {
+	switch c {
+	case CategoryPermissive:
+		return "パーミッシブ"
+	case CategoryWeakCopyleft:
+		return "弱いコピーレフト"
+	case CategoryCopyleft:
+		return "コピーレフト"
+	default:
+		return "不明"
+	}
+}
+
+// Flagged はコンプライアンス上の確認が必要な分類（コピーレフト・判別できないライセンス）かを返す
+func (c Category) Flagged() bool {
+	return c != CategoryPermissive
+}
+
+// severity は確認の優先度（大きいほど優先して確認する）を返す
+func (c Category) severity() int {
+	switch c {
+	case CategoryPermissive:
+		return 0
+	case CategoryWeakCopyleft:
+		return 1
+	case CategoryCopyleft:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// Finding はファイルから検出したライセンス
+type Finding struct {
+	SnapshotID uuid.UUID
+	SourceName string // 取得時のみ設定する
+	FilePath   string
+	Kind       Kind
+	LicenseID  string // SPDX のライセンス識別子・式（判別できない場合は空）
+	Category   Category
+	Evidence   string // 検出の根拠とした行
+}
+
+// Summary はライセンスごとの検出の集計
+type Summary struct {
+	LicenseID    string // 空は判別できないライセンス
+	Category     Category
+	Sources      []string // 検出したソース名（名前順）
+	LicenseFiles int      // ライセンスファイルの数
+	HeaderFiles  int      // ライセンスヘッダーを持つファイルの数
+}
+
+// Report はプロダクトのライセンスの集計
+type Report struct {
+	Licenses []*Summary // 確認の優先度（不明・コピーレフト・弱いコピーレフト・パーミッシブ）・ライセンス識別子順
+	Declared []*Finding // ライセンスファイル（ソース名・パス順）
+	Flagged  []*Finding // コピーレフト・判別できないライセンスを検出したファイル（確認の優先度・ソース名・パス順）
+}
@@ -0,0 +1,87 @@
+package license
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+)
+
+// Reader はインデックス時に検出したライセンスを読み取るインターフェース
+type Reader interface {
+	// ListFindings は検出したライセンスをソース名・ファイルパス順に取得する
+	// product_id 指定時はプロダクトの各ソースの最新のインデックス済みスナップショット、snapshot_id 指定時はそのスナップショットを対象とする
+	ListFindings(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID]) ([]*Finding, error)
+}
+
+// Service はプロダクトのライセンスを集計する
+type Service struct {
+	reader Reader
+}
+
+// NewService は新しい Service を作成する
+func NewService(reader Reader) *Service {
+	return &Service{reader: reader}
+}
+
+// Report は対象スナップショットで検出したライセンスを集計する
+func (s *Service) Report(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID]) (*Report, error) {
+	findings, err := s.reader.ListFindings(ctx, productID, snapshotID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list license findings: %w", err)
+	}
+	return Summarize(findings), nil
+}
+
+// Summarize は検出したライセンスをライセンスごとに集計し、ライセンスファイルと確認が必要なファイルを抽出する
+func Summarize(findings []*Finding) *Report {
+	report := &Report{}
+	summaries := make(map[string]*Summary)
+	for _, f := range findings {
+		summary, ok := summaries[f.LicenseID]
+		if !ok {
+			summary = &Summary{LicenseID: f.LicenseID, Category: f.Category}
+			summaries[f.LicenseID] = summary
+			report.Licenses = append(report.Licenses, summary)
+		}
+		if !slices.Contains(summary.Sources, f.SourceName) {
+			summary.Sources = append(summary.Sources, f.SourceName)
+		}
+		if f.Kind == KindFile {
+			summary.LicenseFiles++
+			report.Declared = append(report.Declared, f)
+		} else {
+			summary.HeaderFiles++
+		}
+		if f.Category.Flagged() {
+			report.Flagged = append(report.Flagged, f)
+		}
+	}
+
+	for _, summary := range report.Licenses {
+		slices.Sort(summary.Sources)
+	}
+	slices.SortFunc(report.Licenses, func(a, b *Summary) int {
+		return cmp.Or(
+			cmp.Compare(b.Category.severity(), a.Category.severity()),
+			cmp.Compare(a.LicenseID, b.LicenseID),
+		)
+	})
+	sortFindings(report.Declared, false)
+	sortFindings(report.Flagged, true)
+	return report
+}
+
+// sortFindings はソース名・ファイルパス順に並べる（bySeverity 指定時は確認の優先度順を優先する）
+func sortFindings(findings []*Finding, bySeverity bool) {
+	slices.SortStableFunc(findings, func(a, b *Finding) int {
+		severity := 0
+		if bySeverity {
+			severity = cmp.Compare(b.Category.severity(), a.Category.severity())
+		}
+		return cmp.Or(severity, cmp.Compare(a.SourceName, b.SourceName), cmp.Compare(a.FilePath, b.FilePath))
+	})
+}
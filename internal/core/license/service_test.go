@@ -0,0 +1,50 @@
+package license
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummarize(t *testing.T) {
+	report := Summarize([]*Finding{
+		{SourceName: "api", FilePath: "LICENSE", Kind: KindFile, LicenseID: "MIT", Category: CategoryPermissive},
+		{SourceName: "api", FilePath: "pkg/a.go", Kind: KindHeader, LicenseID: "MIT", Category: CategoryPermissive},
+		{SourceName: "api", FilePath: "third_party/x/x.c", Kind: KindHeader, LicenseID: "GPL-2.0", Category: CategoryCopyleft},
+		{SourceName: "web", FilePath: "LICENSE", Kind: KindFile, Category: CategoryUnknown},
+		{SourceName: "web", FilePath: "src/a.ts", Kind: KindHeader, LicenseID: "MIT", Category: CategoryPermissive},
+	})
+
+	require.Len(t, report.Licenses, 3)
+	assert.Equal(t, &Summary{LicenseID: "", Category: CategoryUnknown, Sources: []string{"web"}, LicenseFiles: 1}, report.Licenses[0])
+	assert.Equal(t, "GPL-2.0", report.Licenses[1].LicenseID)
+	assert.Equal(t, &Summary{LicenseID: "MIT", Category: CategoryPermissive, Sources: []string{"api", "web"}, LicenseFiles: 1, HeaderFiles: 2}, report.Licenses[2])
+
+	require.Len(t, report.Declared, 2)
+	assert.Equal(t, "api", report.Declared[0].SourceName)
+	require.Len(t, report.Flagged, 2)
+	assert.Equal(t, "web", report.Flagged[0].SourceName, "判別できないライセンスを先に確認する")
+	assert.Equal(t, "third_party/x/x.c", report.Flagged[1].FilePath)
+}
+
+func TestWrite(t *testing.T) {
+	report := Summarize([]*Finding{
+		{SourceName: "api", FilePath: "LICENSE", Kind: KindFile, LicenseID: "MIT", Category: CategoryPermissive},
+		{SourceName: "api", FilePath: "lib/x.c", Kind: KindHeader, LicenseID: "GPL-2.0", Category: CategoryCopyleft, Evidence: "GNU General Public License"},
+	})
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, report, FormatMarkdown))
+	assert.Contains(t, buf.String(), "| GPL-2.0 | コピーレフト | api | 0 | 1 |\n")
+	assert.Contains(t, buf.String(), "| コピーレフト | GPL-2.0 | api | `lib/x.c` | ヘッダー | GNU General Public License |\n")
+
+	buf.Reset()
+	require.NoError(t, Write(&buf, report, FormatTable))
+	assert.Contains(t, buf.String(), "LICENSE ")
+	assert.Contains(t, buf.String(), "要確認のファイル: 1件")
+	assert.Contains(t, buf.String(), "DETECTED BY")
+
+	assert.Error(t, Write(&buf, report, Format("csv")))
+}
@@ -0,0 +1,150 @@
+package llm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/samber/mo"
+)
+
+// Completer はテキスト生成を行う LLM クライアント
+type Completer interface {
+	GenerateCompletion(ctx context.Context, prompt string) (string, error)
+}
+
+// CacheEntry はキャッシュした LLM の応答
+type CacheEntry struct {
+	Key           string // CacheKey で生成したキー
+	PromptName    string
+	PromptVersion string
+	Model         string
+	Response      string
+}
+
+// CacheStore は LLM の応答を保存するストア
+type CacheStore interface {
+	// Get は notBefore 以降に保存した応答を返す（参照した回数を記録する）
+	Get(ctx context.Context, key string, notBefore time.Time) (mo.Option[string], error)
+	// Put は応答を保存する（同じキーの応答は置き換える）
+	Put(ctx context.Context, entry *CacheEntry) error
+}
+
+// CacheStat はプロンプトテンプレート・モデルごとのキャッシュの集計
+type CacheStat struct {
+	PromptName    string
+	PromptVersion string
+	Model         string
+	Entries       int
+	Hits          int64 // キャッシュした応答を使用した回数の合計
+	ResponseBytes int64
+	LastHitAt     *time.Time
+}
+
+// CacheRepository はキャッシュの集計・削除を含むストア（`dev-rag llm cache` で使用）
+type CacheRepository interface {
+	CacheStore
+	Stats(ctx context.Context) ([]*CacheStat, error)
+	// Delete は createdBefore より前に保存した応答を削除する（promptName が空の場合は全テンプレート）
+	Delete(ctx context.Context, promptName string, createdBefore time.Time) (int64, error)
+}
+
+// CachedLLM は WithCacheablePrompt を設定した呼び出しの応答をキャッシュする LLM クライアント
+// キャッシュのキーはプロンプトテンプレートの名前・バージョン、モデル、最大出力トークン数、展開したプロンプトのハッシュ
+// 入力が変わらないファイルの再インデックスや Wiki の再生成で、同じ応答に再び課金されないようにする
+type CachedLLM struct {
+	inner  Completer
+	store  CacheStore
+	model  string
+	ttl    time.Duration
+	logger *slog.Logger
+}
+
+// CacheOption は CachedLLM のオプション設定
+type CacheOption func(*CachedLLM)
+
+// WithCacheTTL はキャッシュした応答の有効期間を設定する（0 の場合は期限なし）
+func WithCacheTTL(ttl time.Duration) CacheOption {
+	return func(c *CachedLLM) {
+		c.ttl = ttl
+	}
+}
+
+// WithCacheLogger は CachedLLM にロガーを設定する
+func WithCacheLogger(logger *slog.Logger) CacheOption {
+	return func(c *CachedLLM) {
+		c.logger = logger
+	}
+}
+
+// NewCachedLLM は応答をキャッシュする LLM クライアントを作成する
+// model はコンテキストでモデルを指定しない呼び出しのキーに使用するクライアントの既定モデル
+func NewCachedLLM(inner Completer, store CacheStore, model string, opts ...CacheOption) *CachedLLM {
+	c := &CachedLLM{
+		inner:  inner,
+		store:  store,
+		model:  model,
+		logger: slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.logger == nil {
+		c.logger = slog.Default()
+	}
+	return c
+}
+
+// GenerateCompletion はキャッシュした応答があればそれを返し、なければテキストを生成して保存する
+// ストアの読み書きに失敗した場合は警告を記録し、キャッシュを使わずに生成する
+func (c *CachedLLM) GenerateCompletion(ctx context.Context, prompt string) (string, error) {
+	name, version, ok := CacheablePromptFromContext(ctx)
+	if !ok {
+		return c.inner.GenerateCompletion(ctx, prompt)
+	}
+
+	model := c.model
+	if override, ok := ModelFromContext(ctx); ok {
+		model = override
+	}
+	maxTokens, _ := MaxTokensFromContext(ctx)
+	key := CacheKey(name, version, model, maxTokens, prompt)
+
+	var notBefore time.Time
+	if c.ttl > 0 {
+		notBefore = time.Now().Add(-c.ttl)
+	}
+	cached, err := c.store.Get(ctx, key, notBefore)
+	if err != nil {
+		c.logger.Warn("failed to read LLM response cache", "prompt", name, "error", err)
+	} else if response, ok := cached.Get(); ok {
+		c.logger.Debug("LLM response cache hit", "prompt", name, "version", version, "model", model)
+		return response, nil
+	}
+
+	response, err := c.inner.GenerateCompletion(ctx, prompt)
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(response) == "" {
+		return response, nil
+	}
+
+	entry := &CacheEntry{Key: key, PromptName: name, PromptVersion: version, Model: model, Response: response}
+	if err := c.store.Put(ctx, entry); err != nil {
+		c.logger.Warn("failed to write LLM response cache", "prompt", name, "error", err)
+	}
+	return response, nil
+}
+
+// CacheKey はキャッシュのキーを生成する
+func CacheKey(promptName, promptVersion, model string, maxTokens int, prompt string) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%s\x00%d\x00", promptName, promptVersion, model, maxTokens)
+	h.Write([]byte(prompt))
+	return hex.EncodeToString(h.Sum(nil))
+}
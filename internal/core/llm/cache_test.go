@@ -0,0 +1,156 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type countingCompleter struct {
+	calls    int
+	response string
+	err      error
+}
+
+func (c *countingCompleter) GenerateCompletion(ctx context.Context, prompt string) (string, error) {
+	c.calls++
+	if c.err != nil {
+		return "", c.err
+	}
+	return c.response, nil
+}
+
+type memoryCacheStore struct {
+	entries map[string]*CacheEntry
+	created map[string]time.Time
+	getErr  error
+}
+
+func newMemoryCacheStore() *memoryCacheStore {
+	return &memoryCacheStore{entries: make(map[string]*CacheEntry), created: make(map[string]time.Time)}
+}
+
+func (m *memoryCacheStore) Get(ctx context.Context, key string, notBefore time.Time) (mo.Option[string], error) {
+	if m.getErr != nil {
+		return mo.None[string](), m.getErr
+	}
+	entry, ok := m.entries[key]
+	if !ok || m.created[key].Before(notBefore) {
+		return mo.None[string](), nil
+	}
+	return mo.Some(entry.Response), nil
+}
+
+func (m *memoryCacheStore) Put(ctx context.Context, entry *CacheEntry) error {
+	m.entries[entry.Key] = entry
+	m.created[entry.Key] = time.Now()
+	return nil
+}
+
+func newTestCachedLLM(inner Completer, store CacheStore, opts ...CacheOption) *CachedLLM {
+	opts = append(opts, WithCacheLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+	return NewCachedLLM(inner, store, "gpt-4o-mini", opts...)
+}
+
+func TestCachedLLM_CachesCacheablePrompts(t *testing.T) {
+	inner := &countingCompleter{response: "要約"}
+	store := newMemoryCacheStore()
+	c := newTestCachedLLM(inner, store)
+	ctx := WithCacheablePrompt(context.Background(), "summary.file", "1.0")
+
+	for range 3 {
+		out, err := c.GenerateCompletion(ctx, "prompt")
+		require.NoError(t, err)
+		assert.Equal(t, "要約", out)
+	}
+	assert.Equal(t, 1, inner.calls)
+
+	require.Len(t, store.entries, 1)
+	for _, entry := range store.entries {
+		assert.Equal(t, "summary.file", entry.PromptName)
+		assert.Equal(t, "1.0", entry.PromptVersion)
+		assert.Equal(t, "gpt-4o-mini", entry.Model)
+	}
+}
+
+func TestCachedLLM_KeyChanges(t *testing.T) {
+	inner := &countingCompleter{response: "要約"}
+	c := newTestCachedLLM(inner, newMemoryCacheStore())
+	base := context.Background()
+
+	calls := []struct {
+		ctx    context.Context
+		prompt string
+	}{
+		{WithCacheablePrompt(base, "summary.file", "1.0"), "prompt"},
+		{WithCacheablePrompt(base, "summary.file", "1.1"), "prompt"},                      // バージョン
+		{WithCacheablePrompt(base, "summary.file", "1.0"), "changed prompt"},              // 入力
+		{WithModel(WithCacheablePrompt(base, "summary.file", "1.0"), "gpt-4o"), "prompt"}, // モデル
+		{WithMaxTokens(WithCacheablePrompt(base, "summary.file", "1.0"), 512), "prompt"},  // 最大出力トークン数
+	}
+	for _, call := range calls {
+		_, err := c.GenerateCompletion(call.ctx, call.prompt)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, len(calls), inner.calls)
+}
+
+func TestCachedLLM_SkipsUncacheableAndFailedCalls(t *testing.T) {
+	inner := &countingCompleter{response: "回答"}
+	store := newMemoryCacheStore()
+	c := newTestCachedLLM(inner, store)
+
+	// WithCacheablePrompt を設定しない呼び出し（質問応答など）はキャッシュしない
+	for range 2 {
+		_, err := c.GenerateCompletion(context.Background(), "prompt")
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, inner.calls)
+	assert.Empty(t, store.entries)
+
+	// 生成に失敗した呼び出し・空の応答は保存しない
+	ctx := WithCacheablePrompt(context.Background(), "wiki.section", "1.0")
+	inner.err = errors.New("rate limited")
+	_, err := c.GenerateCompletion(ctx, "prompt")
+	assert.Error(t, err)
+	inner.err = nil
+	inner.response = "  "
+	_, err = c.GenerateCompletion(ctx, "prompt")
+	require.NoError(t, err)
+	assert.Empty(t, store.entries)
+}
+
+func TestCachedLLM_FallsThroughOnStoreError(t *testing.T) {
+	inner := &countingCompleter{response: "要約"}
+	store := newMemoryCacheStore()
+	store.getErr = errors.New("connection refused")
+	c := newTestCachedLLM(inner, store)
+
+	out, err := c.GenerateCompletion(WithCacheablePrompt(context.Background(), "summary.file", "1.0"), "prompt")
+	require.NoError(t, err)
+	assert.Equal(t, "要約", out)
+	assert.Equal(t, 1, inner.calls)
+}
+
+func TestCachedLLM_TTL(t *testing.T) {
+	inner := &countingCompleter{response: "要約"}
+	store := newMemoryCacheStore()
+	c := newTestCachedLLM(inner, store, WithCacheTTL(time.Hour))
+	ctx := WithCacheablePrompt(context.Background(), "summary.file", "1.0")
+
+	_, err := c.GenerateCompletion(ctx, "prompt")
+	require.NoError(t, err)
+	for key := range store.created {
+		store.created[key] = time.Now().Add(-2 * time.Hour)
+	}
+	_, err = c.GenerateCompletion(ctx, "prompt")
+	require.NoError(t, err)
+	assert.Equal(t, 2, inner.calls)
+}
@@ -0,0 +1,85 @@
+// Package llm はユースケース横断で使用するLLM呼び出しの補助機能を提供する
+package llm
+
+import "context"
+
+type modelKey struct{}
+
+// WithModel は呼び出し単位で使用するモデル名をコンテキストに設定する
+// 空文字の場合はコンテキストを変更しない
+func WithModel(ctx context.Context, model string) context.Context {
+	if model == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, modelKey{}, model)
+}
+
+// ModelFromContext はコンテキストに設定されたモデル名を返す
+// 設定されていない場合は ok が false になり、クライアントの既定モデルを使用する
+func ModelFromContext(ctx context.Context) (model string, ok bool) {
+	model, ok = ctx.Value(modelKey{}).(string)
+	return model, ok && model != ""
+}
+
+type maxTokensKey struct{}
+
+// WithMaxTokens は呼び出し単位の最大出力トークン数をコンテキストに設定する
+// 0 以下の場合はコンテキストを変更しない
+func WithMaxTokens(ctx context.Context, maxTokens int) context.Context {
+	if maxTokens <= 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, maxTokensKey{}, maxTokens)
+}
+
+// MaxTokensFromContext はコンテキストに設定された最大出力トークン数を返す
+// 設定されていない場合は ok が false になり、上限を指定せずに呼び出す
+func MaxTokensFromContext(ctx context.Context) (maxTokens int, ok bool) {
+	maxTokens, ok = ctx.Value(maxTokensKey{}).(int)
+	return maxTokens, ok && maxTokens > 0
+}
+
+type cachePromptKey struct{}
+
+// cachePrompt はキャッシュのキーに含めるプロンプトテンプレートの名前とバージョン
+type cachePrompt struct {
+	name    string
+	version string
+}
+
+// WithCacheablePrompt は呼び出しを応答のキャッシュの対象としてコンテキストに設定する
+// 同じ入力から同じ出力を期待する呼び出し（要約・Wikiページの生成など）に使用し、
+// キャッシュのキーにはプロンプトテンプレートの名前とバージョンを含める
+func WithCacheablePrompt(ctx context.Context, name, version string) context.Context {
+	return context.WithValue(ctx, cachePromptKey{}, cachePrompt{name: name, version: version})
+}
+
+// CacheablePromptFromContext はコンテキストに設定されたプロンプトテンプレートの名前とバージョンを返す
+// 設定されていない場合は ok が false になり、応答をキャッシュしない
+func CacheablePromptFromContext(ctx context.Context) (name, version string, ok bool) {
+	p, ok := ctx.Value(cachePromptKey{}).(cachePrompt)
+	return p.name, p.version, ok
+}
+
+type partialOutputKey struct{}
+
+// PartialOutput は生成中の出力を受け取る
+// ストリーミングに対応したクライアントは生成した出力を Write で順に渡し、
+// 生成をやり直す（レート制限での再試行など）前に Reset でそれまでに渡した出力を破棄させる
+type PartialOutput interface {
+	Write(delta string)
+	Reset()
+}
+
+// WithPartialOutput は生成中の出力を受け取る PartialOutput をコンテキストに設定する
+// 期限で生成を打ち切った場合も途中までの出力を利用できる
+func WithPartialOutput(ctx context.Context, out PartialOutput) context.Context {
+	return context.WithValue(ctx, partialOutputKey{}, out)
+}
+
+// PartialOutputFromContext はコンテキストに設定された生成中の出力を受け取る PartialOutput を返す
+// 設定されていない場合は ok が false になり、ストリーミングせずに呼び出す
+func PartialOutputFromContext(ctx context.Context) (out PartialOutput, ok bool) {
+	out, ok = ctx.Value(partialOutputKey{}).(PartialOutput)
+	return out, ok && out != nil
+}
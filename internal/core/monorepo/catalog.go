@@ -0,0 +1,67 @@
+package monorepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+)
+
+var (
+	// ErrServiceNotFound は指定したサービスが検出されていないことを表す
+	ErrServiceNotFound = errors.New("service not found")
+	// ErrAmbiguousService は指定したサービスに一致するサービスが複数のソースにあることを表す
+	ErrAmbiguousService = errors.New("service is ambiguous")
+)
+
+// Repository は検出したサービスの読み取りインターフェース
+type Repository interface {
+	// ListServices は対象スナップショットのサービスをソース名・ディレクトリ順に取得する
+	// product_id 指定時はプロダクトの各ソースの最新のインデックス済みスナップショット、snapshot_id 指定時はそのスナップショットを対象とする
+	ListServices(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID]) ([]*Service, error)
+}
+
+// Catalog はプロダクトのモノレポから検出したサービスを参照する
+type Catalog struct {
+	repo Repository
+}
+
+// NewCatalog は新しい Catalog を作成する
+func NewCatalog(repo Repository) *Catalog {
+	return &Catalog{repo: repo}
+}
+
+// List は対象スナップショットのサービスをソース名・ディレクトリ順に返す
+func (c *Catalog) List(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID]) ([]*Service, error) {
+	services, err := c.repo.ListServices(ctx, productID, snapshotID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list services: %w", err)
+	}
+	return services, nil
+}
+
+// Find はサービス名またはサービスのディレクトリが一致するサービスを返す
+// 一致するサービスがない場合は ErrServiceNotFound、複数のソースにある場合は ErrAmbiguousService を返す
+func (c *Catalog) Find(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID], nameOrPath string) (*Service, error) {
+	services, err := c.List(ctx, productID, snapshotID)
+	if err != nil {
+		return nil, err
+	}
+
+	var found *Service
+	for _, s := range services {
+		if !s.Matches(nameOrPath) {
+			continue
+		}
+		if found != nil {
+			return nil, fmt.Errorf("%w: %s (%s, %s)", ErrAmbiguousService, nameOrPath, found.SourceName, s.SourceName)
+		}
+		found = s
+	}
+	if found == nil {
+		return nil, fmt.Errorf("%w: %s", ErrServiceNotFound, nameOrPath)
+	}
+	return found, nil
+}
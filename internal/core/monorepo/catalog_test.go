@@ -0,0 +1,43 @@
+package monorepo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRepository struct {
+	services []*Service
+}
+
+func (r *fakeRepository) ListServices(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID]) ([]*Service, error) {
+	return r.services, nil
+}
+
+func TestCatalogFind(t *testing.T) {
+	catalog := NewCatalog(&fakeRepository{services: []*Service{
+		{SourceName: "github.com/acme/mono", Path: "services/api", Name: "example.com/mono/services/api"},
+		{SourceName: "github.com/acme/mono", Path: "apps/web", Name: "@mono/web"},
+		{SourceName: "github.com/acme/tools", Path: "apps/web", Name: "@tools/web"},
+	}})
+	ctx := context.Background()
+	none := mo.None[uuid.UUID]()
+
+	found, err := catalog.Find(ctx, none, none, "services/api")
+	require.NoError(t, err)
+	assert.Equal(t, "example.com/mono/services/api", found.Name)
+
+	found, err = catalog.Find(ctx, none, none, "@tools/web")
+	require.NoError(t, err)
+	assert.Equal(t, "github.com/acme/tools", found.SourceName)
+
+	_, err = catalog.Find(ctx, none, none, "apps/web")
+	assert.ErrorIs(t, err, ErrAmbiguousService)
+
+	_, err = catalog.Find(ctx, none, none, "billing")
+	assert.ErrorIs(t, err, ErrServiceNotFound)
+}
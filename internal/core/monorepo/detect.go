@@ -0,0 +1,225 @@
+package monorepo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+// IsBoundaryFile はサービスの境界の検出に使用するファイル（go.mod・package.json・BUILD・BUILD.bazel）かを判定する
+func IsBoundaryFile(p string) bool {
+	switch path.Base(p) {
+	case "go.mod", "BUILD", "BUILD.bazel":
+		return true
+	case "package.json":
+		return !slices.Contains(strings.Split(p, "/"), "node_modules")
+	}
+	return false
+}
+
+// Detect はスナップショットの境界の検出に使用するファイル（パスと内容）からサービスを検出し、ディレクトリ順に返す
+//   - go.mod: リポジトリのルート以外の go.mod のディレクトリ（名前はモジュールパス）
+//   - package.json: workspaces のパターンに一致するディレクトリのパッケージ（名前は name）
+//   - BUILD・BUILD.bazel: 実行可能なターゲット（*_binary・*_image）を定義した Bazel パッケージ（名前は //path）
+//
+// リポジトリのルートはモノレポ全体を表すためサービスとしない
+// 同じディレクトリを複数の方法で検出した場合は Kinds の順に優先し、Bazel のターゲットは併せて記録する
+// 解析できなかった package.json はスキップし、エラーをまとめて返す
+func Detect(files map[string]string) ([]*Service, error) {
+	paths := make([]string, 0, len(files))
+	for p := range files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	var errs []error
+	packages := make(map[string]*packageJSON) // package.json のディレクトリ -> 内容
+	for _, p := range paths {
+		if path.Base(p) != "package.json" || !IsBoundaryFile(p) {
+			continue
+		}
+		pkg, err := parsePackageJSON(files[p])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", p, err))
+			continue
+		}
+		packages[path.Dir(p)] = pkg
+	}
+
+	byPath := make(map[string]*Service)
+	add := func(s *Service) {
+		existing, ok := byPath[s.Path]
+		if !ok {
+			byPath[s.Path] = s
+			return
+		}
+		if slices.Index(Kinds, s.Kind) < slices.Index(Kinds, existing.Kind) {
+			s.Targets = append(s.Targets, existing.Targets...)
+			byPath[s.Path] = s
+			return
+		}
+		existing.Targets = append(existing.Targets, s.Targets...)
+	}
+
+	for _, p := range paths {
+		dir := path.Dir(p)
+		if dir == "." {
+			continue
+		}
+		switch path.Base(p) {
+		case "go.mod":
+			name := modfile.ModulePath([]byte(files[p]))
+			if name == "" {
+				name = dir
+			}
+			add(&Service{Path: dir, Name: name, Kind: KindGo, ManifestPath: p})
+		case "BUILD", "BUILD.bazel":
+			if targets := bazelTargets(dir, files[p]); len(targets) > 0 {
+				add(&Service{Path: dir, Name: "//" + dir, Kind: KindBazel, ManifestPath: p, Targets: targets})
+			}
+		}
+	}
+
+	for root, pkg := range packages {
+		if len(pkg.Workspaces) == 0 {
+			continue
+		}
+		for dir, member := range packages {
+			if dir == "." || dir == root {
+				continue
+			}
+			rel, ok := relativeDir(root, dir)
+			if !ok || !matchWorkspaces(pkg.Workspaces, rel) {
+				continue
+			}
+			name := member.Name
+			if name == "" {
+				name = dir
+			}
+			add(&Service{Path: dir, Name: name, Kind: KindNPM, ManifestPath: path.Join(dir, "package.json")})
+		}
+	}
+
+	services := make([]*Service, 0, len(byPath))
+	for _, s := range byPath {
+		sort.Strings(s.Targets)
+		services = append(services, s)
+	}
+	sort.Slice(services, func(i, j int) bool { return services[i].Path < services[j].Path })
+	return services, errors.Join(errs...)
+}
+
+// packageJSON は package.json のうちサービスの検出に使用する項目
+type packageJSON struct {
+	Name       string
+	Workspaces []string
+}
+
+// parsePackageJSON は package.json から name と workspaces を抽出する
+// workspaces は配列と、Yarn のオブジェクト形式（{"packages": [...]}）の両方に対応する
+func parsePackageJSON(content string) (*packageJSON, error) {
+	var manifest struct {
+		Name       string          `json:"name"`
+		Workspaces json.RawMessage `json:"workspaces"`
+	}
+	if err := json.Unmarshal([]byte(content), &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse package.json: %w", err)
+	}
+
+	pkg := &packageJSON{Name: manifest.Name}
+	if len(manifest.Workspaces) == 0 {
+		return pkg, nil
+	}
+	if err := json.Unmarshal(manifest.Workspaces, &pkg.Workspaces); err == nil {
+		return pkg, nil
+	}
+	var object struct {
+		Packages []string `json:"packages"`
+	}
+	if err := json.Unmarshal(manifest.Workspaces, &object); err != nil {
+		return nil, fmt.Errorf("failed to parse workspaces: %w", err)
+	}
+	pkg.Workspaces = object.Packages
+	return pkg, nil
+}
+
+// relativeDir は root からの dir の相対パスを返す（dir が root の配下でない場合は false）
+func relativeDir(root, dir string) (string, bool) {
+	if root == "." {
+		return dir, true
+	}
+	return strings.CutPrefix(dir, root+"/")
+}
+
+// matchWorkspaces はディレクトリが workspaces のパターンのいずれかに一致し、除外のパターン（!）に一致しないかを判定する
+func matchWorkspaces(patterns []string, dir string) bool {
+	matched := false
+	for _, pattern := range patterns {
+		exclude := strings.HasPrefix(pattern, "!")
+		pattern = strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(pattern, "!"), "./"), "/")
+		if !matchGlob(strings.Split(pattern, "/"), strings.Split(dir, "/")) {
+			continue
+		}
+		if exclude {
+			return false
+		}
+		matched = true
+	}
+	return matched
+}
+
+// matchGlob はパスの要素をパターンの要素と照合する（** は0個以上の要素に一致する）
+func matchGlob(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchGlob(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	if ok, err := path.Match(pattern[0], segments[0]); err != nil || !ok {
+		return false
+	}
+	return matchGlob(pattern[1:], segments[1:])
+}
+
+var (
+	// bazelRulePattern は BUILD ファイルの行頭のルールの呼び出し（go_binary( など）にマッチする
+	bazelRulePattern = regexp.MustCompile(`(?m)^(\w+)\s*\(`)
+	// bazelNamePattern はルールの name 属性にマッチする
+	bazelNamePattern = regexp.MustCompile(`\bname\s*=\s*"([^"]+)"`)
+)
+
+// bazelTargets は BUILD ファイルから実行可能なターゲット（*_binary・*_image のルール）のラベルを抽出する
+func bazelTargets(dir, content string) []string {
+	matches := bazelRulePattern.FindAllStringSubmatchIndex(content, -1)
+	var targets []string
+	for i, m := range matches {
+		rule := content[m[2]:m[3]]
+		if !strings.HasSuffix(rule, "_binary") && !strings.HasSuffix(rule, "_image") {
+			continue
+		}
+		end := len(content)
+		if i+1 < len(matches) {
+			end = matches[i+1][0]
+		}
+		if name := bazelNamePattern.FindStringSubmatch(content[m[1]:end]); name != nil {
+			targets = append(targets, "//"+dir+":"+name[1])
+		}
+	}
+	return targets
+}
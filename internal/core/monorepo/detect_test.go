@@ -0,0 +1,101 @@
+package monorepo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsBoundaryFile(t *testing.T) {
+	assert.True(t, IsBoundaryFile("services/api/go.mod"))
+	assert.True(t, IsBoundaryFile("package.json"))
+	assert.True(t, IsBoundaryFile("tools/BUILD.bazel"))
+	assert.True(t, IsBoundaryFile("tools/BUILD"))
+
+	assert.False(t, IsBoundaryFile("web/node_modules/react/package.json"), "インストールしたパッケージは除く")
+	assert.False(t, IsBoundaryFile("go.sum"))
+}
+
+func TestDetect(t *testing.T) {
+	files := map[string]string{
+		"go.mod":                         "module example.com/mono\n",
+		"services/api/go.mod":            "module example.com/mono/services/api\n\ngo 1.22\n",
+		"services/worker/go.mod":         "not a go.mod",
+		"package.json":                   `{"name": "mono", "private": true, "workspaces": ["apps/*", "packages/**", "!packages/internal"]}`,
+		"apps/web/package.json":          `{"name": "@mono/web"}`,
+		"packages/ui/package.json":       `{"name": "@mono/ui"}`,
+		"packages/internal/package.json": `{"name": "@mono/internal"}`,
+		"docs/package.json":              `{"name": "docs"}`,
+		"services/api/BUILD.bazel": `load("@io_bazel_rules_go//go:def.bzl", "go_binary", "go_library")
+
+go_library(
+    name = "api_lib",
+    srcs = ["main.go"],
+)
+
+go_binary(
+    name = "api",
+    embed = [":api_lib"],
+)
+`,
+		"tools/gen/BUILD": `py_binary(name = "gen", srcs = ["gen.py"])
+oci_image(
+    name = "gen_image",
+    base = "@distroless",
+)
+`,
+		"lib/BUILD": `go_library(name = "lib")`,
+	}
+
+	services, err := Detect(files)
+	require.NoError(t, err)
+
+	paths := make([]string, 0, len(services))
+	for _, s := range services {
+		paths = append(paths, s.Path)
+	}
+	assert.Equal(t, []string{"apps/web", "packages/ui", "services/api", "services/worker", "tools/gen"}, paths,
+		"ルート・workspaces に含まれないパッケージ・実行可能なターゲットのない Bazel パッケージは除く")
+
+	assert.Equal(t, &Service{Path: "apps/web", Name: "@mono/web", Kind: KindNPM, ManifestPath: "apps/web/package.json"}, services[0])
+	assert.Equal(t, &Service{
+		Path:         "services/api",
+		Name:         "example.com/mono/services/api",
+		Kind:         KindGo,
+		ManifestPath: "services/api/go.mod",
+		Targets:      []string{"//services/api:api"},
+	}, services[2], "go.mod を優先し、Bazel のターゲットを併せて記録する")
+	assert.Equal(t, "services/worker", services[3].Name, "モジュールパスがない場合はディレクトリを名前とする")
+	assert.Equal(t, &Service{
+		Path:         "tools/gen",
+		Name:         "//tools/gen",
+		Kind:         KindBazel,
+		ManifestPath: "tools/gen/BUILD",
+		Targets:      []string{"//tools/gen:gen", "//tools/gen:gen_image"},
+	}, services[4])
+}
+
+func TestDetect_Workspaces(t *testing.T) {
+	files := map[string]string{
+		// Yarn のオブジェクト形式、ルート以外の package.json の workspaces
+		"frontend/package.json":            `{"workspaces": {"packages": ["./packages/*"]}}`,
+		"frontend/packages/a/package.json": `{}`,
+		"packages/b/package.json":          `{"name": "b"}`,
+		"broken/package.json":              `{`,
+	}
+
+	services, err := Detect(files)
+	assert.ErrorContains(t, err, "broken/package.json")
+	require.Len(t, services, 1)
+	assert.Equal(t, &Service{Path: "frontend/packages/a", Name: "frontend/packages/a", Kind: KindNPM, ManifestPath: "frontend/packages/a/package.json"}, services[0])
+}
+
+func TestService(t *testing.T) {
+	s := &Service{Path: "services/api", Name: "example.com/mono/services/api"}
+	assert.Equal(t, "services/api/", s.PathPrefix())
+	assert.Equal(t, "shop-services-api", s.ProductName("shop"))
+	assert.True(t, s.Matches("services/api/"))
+	assert.True(t, s.Matches("example.com/mono/services/api"))
+	assert.False(t, s.Matches("services"))
+}
@@ -0,0 +1,48 @@
+package monorepo
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Kind はサービスの境界を検出した方法
+type Kind string
+
+const (
+	KindGo    Kind = "go"    // go.mod（リポジトリのルート以外）
+	KindNPM   Kind = "npm"   // package.json の workspaces に含まれるパッケージ
+	KindBazel Kind = "bazel" // BUILD・BUILD.bazel の実行可能なターゲット
+)
+
+// Kinds は対応する検出方法の一覧（同じディレクトリで複数検出した場合の優先順）
+var Kinds = []Kind{KindGo, KindNPM, KindBazel}
+
+// Service はモノレポ内で独立してビルド・デプロイされる単位（サービス・パッケージ）の境界
+type Service struct {
+	SnapshotID   uuid.UUID
+	SourceName   string // 取得時のみ設定する
+	Version      string // スナップショットのバージョン識別子（取得時のみ設定する）
+	Path         string // サービスのルートディレクトリ（リポジトリのルートからのパス）
+	Name         string // go.mod のモジュールパス、package.json の name、Bazel パッケージのラベル（//path）
+	Kind         Kind
+	ManifestPath string   // 境界の根拠としたファイルのパス
+	Targets      []string // Bazel の実行可能なターゲットのラベル（//path:name）
+}
+
+// PathPrefix はサービスのファイルに一致するパスのプレフィックスを返す（検索の絞り込みに使用する）
+func (s *Service) PathPrefix() string {
+	return s.Path + "/"
+}
+
+// ProductName はサービスのサブツリーを別のプロダクトとしてインデックス化する場合のプロダクト名を返す
+// 例: プロダクト shop のサービス services/api -> shop-services-api
+func (s *Service) ProductName(parent string) string {
+	return parent + "-" + strings.ReplaceAll(s.Path, "/", "-")
+}
+
+// Matches はサービス名またはサービスのディレクトリが指定と一致するかを判定する
+func (s *Service) Matches(nameOrPath string) bool {
+	nameOrPath = strings.TrimSuffix(nameOrPath, "/")
+	return s.Name == nameOrPath || s.Path == nameOrPath
+}
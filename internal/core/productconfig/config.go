@@ -0,0 +1,188 @@
+// Package productconfig はプロダクトごとの設定（キーと値の組）の定義・検証・参照を提供する
+package productconfig
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"github.com/jinford/dev-rag/internal/core/ingestion"
+)
+
+// 設定キー
+const (
+	// KeyMinCoveragePrefix はドメインの最小カバレッジ率（%）のキーの接頭辞（例: alert.min_coverage.tests）
+	KeyMinCoveragePrefix = "alert.min_coverage."
+	// KeyMaxUnindexedImportantFiles は許容する未インデックスの重要ファイル数のキー
+	KeyMaxUnindexedImportantFiles = "alert.max_unindexed_important_files"
+	// KeyAskMinRelevance は質問応答で回答を生成する検索結果の関連度スコアの下限のキー
+	KeyAskMinRelevance = "ask.min_relevance"
+	// KeyAskSystemPrompt は質問応答のプロンプトの役割の説明を置き換えるシステムプロンプトのキー
+	KeyAskSystemPrompt = "ask.system_prompt"
+)
+
+// maxSystemPromptLength はシステムプロンプトとして保存できる最大文字数
+const maxSystemPromptLength = 4000
+
+// Entry はプロダクトの設定の1項目
+type Entry struct {
+	ProductID uuid.UUID
+	Key       string
+	Value     string
+	UpdatedAt time.Time
+}
+
+// Repository はプロダクトの設定のデータアクセスインターフェース
+type Repository interface {
+	// List はプロダクトの設定をキー順に取得する
+	List(ctx context.Context, productID uuid.UUID) ([]*Entry, error)
+	// Set は設定を保存する（同じキーが既にあれば上書きする）
+	Set(ctx context.Context, productID uuid.UUID, key, value string) (*Entry, error)
+	// Delete は設定を削除し、削除したかを返す
+	Delete(ctx context.Context, productID uuid.UUID, key string) (bool, error)
+}
+
+// KeySpec は設定キーの定義
+type KeySpec struct {
+	Key         string // キー（Prefix の場合は接頭辞）
+	Prefix      bool   // 接頭辞に続く部分を含めてキーとするか
+	Description string
+	validate    func(suffix, value string) (string, error)
+}
+
+// Pattern は表示用のキーの形式を返す
+func (s KeySpec) Pattern() string {
+	if s.Prefix {
+		return s.Key + "<domain>"
+	}
+	return s.Key
+}
+
+// keySpecs は設定可能なキーの一覧
+var keySpecs = []KeySpec{
+	{
+		Key:         KeyMinCoveragePrefix,
+		Prefix:      true,
+		Description: "ドメインのカバレッジ率（%）がこの値を下回るとアラートを発行する（0〜100）",
+		validate: func(domain, value string) (string, error) {
+			if !ingestion.IsValidDomain(domain) {
+				return "", fmt.Errorf("unknown domain %q: expected one of %s", domain, strings.Join(ingestion.Domains, ", "))
+			}
+			v, err := strconv.ParseFloat(value, 64)
+			if err != nil || v < 0 || v > 100 {
+				return "", fmt.Errorf("invalid coverage %q: expected a number between 0 and 100", value)
+			}
+			return strconv.FormatFloat(v, 'f', -1, 64), nil
+		},
+	},
+	{
+		Key:         KeyMaxUnindexedImportantFiles,
+		Description: "未インデックスの重要ファイル（README・ADR など）がこの数を超えるとアラートを発行する（0 以上）",
+		validate: func(_, value string) (string, error) {
+			v, err := strconv.Atoi(value)
+			if err != nil || v < 0 {
+				return "", fmt.Errorf("invalid file count %q: expected a non-negative integer", value)
+			}
+			return strconv.Itoa(v), nil
+		},
+	},
+	{
+		Key:         KeyAskMinRelevance,
+		Description: "質問応答で検索結果の関連度スコアの最大値がこの値を下回ると、回答を生成せず「該当する情報が見つからない」と応答する（0〜1、0 で無効）",
+		validate: func(_, value string) (string, error) {
+			v, err := strconv.ParseFloat(value, 64)
+			if err != nil || v < 0 || v > 1 {
+				return "", fmt.Errorf("invalid relevance %q: expected a number between 0 and 1", value)
+			}
+			return strconv.FormatFloat(v, 'f', -1, 64), nil
+		},
+	},
+	{
+		Key:         KeyAskSystemPrompt,
+		Description: "質問応答のプロンプト冒頭の役割の説明を置き換える（回答のガイドラインは常に適用される）",
+		validate: func(_, value string) (string, error) {
+			if value == "" {
+				return "", fmt.Errorf("system prompt must not be empty")
+			}
+			if n := utf8.RuneCountInString(value); n > maxSystemPromptLength {
+				return "", fmt.Errorf("system prompt is too long: %d characters (max %d)", n, maxSystemPromptLength)
+			}
+			return value, nil
+		},
+	},
+}
+
+// KeySpecs は設定可能なキーの定義の一覧を返す
+func KeySpecs() []KeySpec {
+	return slices.Clone(keySpecs)
+}
+
+// Validate はキーと値を検証し、正規化した値を返す
+func Validate(key, value string) (string, error) {
+	key = strings.TrimSpace(key)
+	value = strings.TrimSpace(value)
+	for _, spec := range keySpecs {
+		switch {
+		case spec.Prefix && strings.HasPrefix(key, spec.Key):
+			return spec.validate(strings.TrimPrefix(key, spec.Key), value)
+		case !spec.Prefix && key == spec.Key:
+			return spec.validate("", value)
+		}
+	}
+	return "", fmt.Errorf("unknown config key %q", key)
+}
+
+// Config はキーから値へのマップとしたプロダクトの設定
+type Config map[string]string
+
+// NewConfig は設定の項目から Config を作成する
+func NewConfig(entries []*Entry) Config {
+	c := make(Config, len(entries))
+	for _, e := range entries {
+		c[e.Key] = e.Value
+	}
+	return c
+}
+
+// Float はキーの値を数値として返す（未設定・不正な値の場合は false）
+func (c Config) Float(key string) (float64, bool) {
+	v, ok := c[key]
+	if !ok {
+		return 0, false
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0, false
+	}
+	return f, true
+}
+
+// Int はキーの値を整数として返す（未設定・不正な値の場合は false）
+func (c Config) Int(key string) (int, bool) {
+	v, ok := c[key]
+	if !ok {
+		return 0, false
+	}
+	i, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, false
+	}
+	return i, true
+}
+
+// WithPrefix は接頭辞で始まるキーについて、接頭辞を除いたキーから値へのマップを返す
+func (c Config) WithPrefix(prefix string) map[string]string {
+	m := make(map[string]string)
+	for k, v := range c {
+		if rest, ok := strings.CutPrefix(k, prefix); ok && rest != "" {
+			m[rest] = v
+		}
+	}
+	return m
+}
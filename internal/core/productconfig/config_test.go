@@ -0,0 +1,68 @@
+package productconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		key   string
+		value string
+		want  string
+	}{
+		{"alert.min_coverage.tests", " 35.50 ", "35.5"},
+		{"alert.min_coverage.architecture", "0", "0"},
+		{"alert.max_unindexed_important_files", "3", "3"},
+		{"ask.min_relevance", "0.35", "0.35"},
+		{"ask.system_prompt", " あなたは決済基盤チームのアシスタントです。\n", "あなたは決済基盤チームのアシスタントです。"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			got, err := Validate(tt.key, tt.value)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	invalid := []struct {
+		key   string
+		value string
+	}{
+		{"alert.min_coverage.tests", "120"},
+		{"alert.min_coverage.tests", "high"},
+		{"alert.min_coverage.frontend", "50"},
+		{"alert.min_coverage.", "50"},
+		{"alert.max_unindexed_important_files", "-1"},
+		{"alert.max_unindexed_important_files", "1.5"},
+		{"alert.unknown", "1"},
+		{"ask.min_relevance", "1.2"},
+		{"ask.system_prompt", "  "},
+	}
+	for _, tt := range invalid {
+		_, err := Validate(tt.key, tt.value)
+		assert.Error(t, err, tt.key+"="+tt.value)
+	}
+}
+
+func TestConfig(t *testing.T) {
+	cfg := NewConfig([]*Entry{
+		{Key: "alert.min_coverage.tests", Value: "35"},
+		{Key: "alert.min_coverage.code", Value: "60.5"},
+		{Key: "alert.max_unindexed_important_files", Value: "2"},
+	})
+
+	assert.Equal(t, map[string]string{"tests": "35", "code": "60.5"}, cfg.WithPrefix(KeyMinCoveragePrefix))
+	v, ok := cfg.Float("alert.min_coverage.code")
+	assert.True(t, ok)
+	assert.Equal(t, 60.5, v)
+	n, ok := cfg.Int(KeyMaxUnindexedImportantFiles)
+	assert.True(t, ok)
+	assert.Equal(t, 2, n)
+	_, ok = cfg.Int("alert.min_coverage.code")
+	assert.False(t, ok)
+	_, ok = cfg.Float("missing")
+	assert.False(t, ok)
+}
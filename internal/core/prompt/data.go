@@ -0,0 +1,111 @@
+package prompt
+
+// テンプレートに渡す値
+// コンテキストのように件数や有無で構成が変わる部分は呼び出し側で整形した文字列として渡し、
+// テンプレートにはタスクの説明と指示を置く
+
+// FileSummaryData は summary.file に渡す値
+type FileSummaryData struct {
+	Path     string
+	Language string // 不明な場合は unknown
+	Content  string // ファイルの内容（長すぎる場合は切り詰め済み）
+}
+
+// DirectorySummaryData は summary.directory に渡す値
+type DirectorySummaryData struct {
+	Path                  string // ルートは (root)
+	Depth                 int
+	FileCount             int
+	SubdirectoryCount     int
+	FileSummaries         string // 配下のファイル要約（1行1件、なければ「なし」）
+	SubdirectorySummaries string // サブディレクトリ要約（1行1件、なければ「なし」）
+}
+
+// ArchitectureSummaryData は summary.architecture に渡す値
+type ArchitectureSummaryData struct {
+	ArchType           string // overview, tech_stack, data_flow, components
+	DirectoryCount     int
+	FileCount          int
+	DirectorySummaries string // ディレクトリ要約（「- パス: 要約」の1行1件）
+}
+
+// ProductSummaryData は summary.product に渡す値
+type ProductSummaryData struct {
+	ProductName           string
+	SourceCount           int
+	ArchitectureSummaries string // ソースごとのアーキテクチャ要約（「### ソース名 (種類)」の見出しと本文）
+}
+
+// WikiSectionData は wiki.section と wiki.onboarding に渡す値
+type WikiSectionData struct {
+	Title        string
+	Description  string
+	Context      string // 「## コンテキスト: ...」の見出しで始まるコンテキスト（空の場合あり）
+	Instructions string // セクションの設定の指示テンプレートを展開したもの
+}
+
+// WikiFollowUpData は wiki.follow_up に渡す値
+type WikiFollowUpData struct {
+	Title          string
+	InitialContent string
+	Context        string // 追加のコンテキスト
+}
+
+// WikiAPIOverviewData は wiki.api_overview に渡す値
+type WikiAPIOverviewData struct {
+	Package      string
+	Doc          string // パッケージのドキュメント（空の場合あり）
+	Declarations string // 公開されている宣言（「- 種類 `宣言`: 説明」の1行1件）
+}
+
+// WikiTranslateData は wiki.translate に渡す値
+type WikiTranslateData struct {
+	Language string // 翻訳先の言語の名前（English など）
+	Content  string // 翻訳するMarkdownのページ
+}
+
+// WikiRunbookData は wiki.runbook に渡す値
+type WikiRunbookData struct {
+	Commands string // コマンド（「### [番号] `コマンド`（定義位置）」の見出しとコメント・定義のコードフェンスの組）
+}
+
+// AskData は ask に渡す値
+type AskData struct {
+	SystemPrompt    string // プロダクトごとに設定した役割の説明（空の場合は既定の説明）
+	NoAnswerMessage string // 答えが見つからない場合の定型の応答
+	Context         string // 用語集・スキーマ・デプロイ構成・議論・要約・関連コードのコンテキスト
+	Query           string
+}
+
+// CitationData は ask.citation に渡す値
+type CitationData struct {
+	Sentence string // コードフェンスで囲んだ回答の文
+	Evidence string // 引用されたコード（「### パス (L1-L2)」の見出しとコードフェンスの組）
+}
+
+// ActionData は quality.actions に渡す値
+type ActionData struct {
+	WeekStart      string // YYYY-MM-DD
+	WeekEnd        string // YYYY-MM-DD
+	Notes          string // 品質ノート
+	RecentChanges  string // 最近の変更（1行1件、なければ「（なし）」）
+	Owners         string // CODEOWNERS の担当者（1行1件、なければ「（なし）」）
+	MaxOpenActions int
+}
+
+// GlossaryData は glossary.extract に渡す値
+type GlossaryData struct {
+	Inputs string // ドキュメント・ドキュメントコメント（「### [番号] パス」の見出しとコードフェンスの組）
+}
+
+// ReleaseNotesData は release.notes に渡す値
+type ReleaseNotesData struct {
+	SourceName string
+	FromRef    string
+	ToRef      string
+	Commits    string // コミット（「- ハッシュ 件名 (作成者)」の1行1件）
+	Files      string // 変更されたファイル（「- 種類 パス」の1行1件）
+	Changes    string // 変更された宣言（「- 種類 型 `名前` (パス)」の1行1件、シグネチャ・ドキュメントコメントを続ける）
+	Summaries  string // 変更されたファイルの要約（「### パス」の見出しと要約の組）
+	Impacts    string // 依存関係の影響範囲（「- 変更されたファイル: 依存しているファイル」の1行1件）
+}
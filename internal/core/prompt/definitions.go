@@ -0,0 +1,157 @@
+package prompt
+
+import (
+	"embed"
+	"fmt"
+	"text/template"
+)
+
+//go:embed templates/*.tmpl
+var builtinFS embed.FS
+
+// Definition は組み込みのテンプレートの定義
+type Definition struct {
+	Name        string
+	Description string
+	Version     string // 組み込みのテンプレートのバージョン（本文を変更したら上げる）
+	// sample は上書きするテンプレートを検証するための値（テンプレートが参照するフィールドを持つ型）
+	sample any
+}
+
+// definitions は組み込みのテンプレートの一覧
+var definitions = []Definition{
+	{
+		Name:        NameFileSummary,
+		Description: "ファイル要約の生成",
+		Version:     "1.0",
+		sample:      FileSummaryData{Path: "internal/server/handler.go", Language: "go", Content: "package server"},
+	},
+	{
+		Name:        NameDirectorySummary,
+		Description: "ディレクトリ要約の生成（配下のファイル要約・サブディレクトリ要約から）",
+		Version:     "1.0",
+		sample:      DirectorySummaryData{Path: "internal/server", Depth: 2, FileCount: 1, FileSummaries: "- handler.go: HTTPハンドラ", SubdirectorySummaries: "なし"},
+	},
+	{
+		Name:        NameArchitectureSummary,
+		Description: "アーキテクチャ要約の生成（ArchType で概要・技術スタック・データフロー・コンポーネントを切り替える）",
+		Version:     "1.0",
+		sample:      ArchitectureSummaryData{ArchType: "overview", DirectoryCount: 1, FileCount: 1, DirectorySummaries: "- internal/server: HTTPサーバー"},
+	},
+	{
+		Name:        NameProductSummary,
+		Description: "プロダクト全体の要約の生成（各ソースのアーキテクチャ要約から、冒頭の1文はプロダクトの説明に使用）",
+		Version:     "1.0",
+		sample:      ProductSummaryData{ProductName: "ecommerce", SourceCount: 1, ArchitectureSummaries: "### backend (overview)\nECサイトのAPIサーバー\n"},
+	},
+	{
+		Name:        NameWikiSection,
+		Description: "Wikiセクションのページ生成",
+		Version:     "1.0",
+		sample:      WikiSectionData{Title: "概要", Description: "プロダクトの概要", Context: "## コンテキスト: 構造要約\n\n", Instructions: "1. **概要**"},
+	},
+	{
+		Name:        NameWikiFollowUp,
+		Description: "追加のコンテキストによるWikiセクションの改善",
+		Version:     "1.0",
+		sample:      WikiFollowUpData{Title: "概要", InitialContent: "## 概要", Context: "### コンテンツ 1: main.go (L1-L10)\n\n"},
+	},
+	{
+		Name:        NameWikiAPIOverview,
+		Description: "APIリファレンスのパッケージ概要の生成",
+		Version:     "1.0",
+		sample:      WikiAPIOverviewData{Package: "internal/server", Doc: "Package server", Declarations: "- func `func New() *Server`\n"},
+	},
+	{
+		Name:        NameWikiOnboarding,
+		Description: "オンボーディングガイドのページ生成",
+		Version:     "1.0",
+		sample:      WikiSectionData{Title: "オンボーディング", Description: "新メンバー向けガイド", Context: "## コンテキスト: リポジトリ構成（カバレッジマップ）\n\n", Instructions: "1. **はじめに**"},
+	},
+	{
+		Name:        NameWikiTranslate,
+		Description: "Wikiページの他の言語への翻訳（見出し・コードブロック・リンクの構成を保つ）",
+		Version:     "1.0",
+		sample:      WikiTranslateData{Language: "English", Content: "## 概要\n\n`internal/server/handler.go` でリクエストを処理する。\n"},
+	},
+	{
+		Name:        NameWikiRunbook,
+		Description: "Makefile・スクリプト・CI設定から検出した運用コマンドの説明の生成",
+		Version:     "1.0",
+		sample:      WikiRunbookData{Commands: "### [1] `make deploy`（Makefile:12）\n```\n./scripts/deploy.sh\n```\n\n"},
+	},
+	{
+		Name:        NameAsk,
+		Description: "質問応答（回答のガイドラインとコンテキスト）",
+		Version:     "1.0",
+		sample:      AskData{NoAnswerMessage: "該当する情報が見つかりませんでした。", Context: "## コンテキスト: 関連コード\n", Query: "認証の仕組みは？"},
+	},
+	{
+		Name:        NameAskCitation,
+		Description: "回答の引用が文を裏付けているかの判定",
+		Version:     "1.0",
+		sample:      CitationData{Sentence: "```\n認証は middleware.go で行う。\n```\n\n", Evidence: "### middleware.go (L1-L10)\n```\nfunc Auth() {}\n```\n\n"},
+	},
+	{
+		Name:        NameQualityActions,
+		Description: "品質ノート・最近の変更・CODEOWNERS からの改善アクションの生成",
+		Version:     "1.1",
+		sample:      ActionData{WeekStart: "2025-01-06", WeekEnd: "2025-01-12", Notes: "### [QN-1] severity: high / reviewer: alice\n\n", RecentChanges: "（なし）\n", Owners: "（なし）\n", MaxOpenActions: 5},
+	},
+	{
+		Name:        NameGlossaryExtract,
+		Description: "ドキュメント・ドキュメントコメントからの用語集の抽出",
+		Version:     "1.0",
+		sample:      GlossaryData{Inputs: "### [1] README.md\n```\nSLO は...\n```\n\n"},
+	},
+	{
+		Name:        NameReleaseNotes,
+		Description: "コミット・変更された宣言・ファイル要約・依存関係の影響範囲からのリリースノートの下書きの生成",
+		Version:     "1.0",
+		sample: ReleaseNotesData{
+			SourceName: "backend", FromRef: "v1.0.0", ToRef: "v1.1.0",
+			Commits:   "- a1b2c3d feat: 注文のキャンセルAPIを追加 (alice)\n",
+			Files:     "- modified internal/order/service.go\n",
+			Changes:   "- added method `OrderService.Cancel` (internal/order/service.go)\n",
+			Summaries: "### internal/order/service.go\n注文の作成・キャンセルを行う\n\n",
+			Impacts:   "- internal/order/service.go: internal/api/order.go\n",
+		},
+	},
+}
+
+// Definitions は組み込みのテンプレートの定義を返す
+func Definitions() []Definition {
+	return definitions
+}
+
+// lookupDefinition は名前に対応する組み込みのテンプレートの定義を返す
+func lookupDefinition(name string) (Definition, bool) {
+	for _, def := range definitions {
+		if def.Name == name {
+			return def, true
+		}
+	}
+	return Definition{}, false
+}
+
+// builtinBody は組み込みのテンプレートの本文を返す
+func builtinBody(name string) (string, error) {
+	data, err := builtinFS.ReadFile("templates/" + name + ".tmpl")
+	if err != nil {
+		return "", fmt.Errorf("failed to read builtin template %s: %w", name, err)
+	}
+	return string(data), nil
+}
+
+// parse はテンプレートの本文を解析する
+// テンプレートからは version 関数で自身のバージョンを参照できる（LLMに出力させるJSONに含める場合など）
+func parse(name, version, body string) (*template.Template, error) {
+	tmpl, err := template.New(name).
+		Option("missingkey=error").
+		Funcs(template.FuncMap{"version": func() string { return version }}).
+		Parse(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template %s: %w", name, err)
+	}
+	return tmpl, nil
+}
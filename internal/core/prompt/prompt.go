@@ -0,0 +1,69 @@
+// Package prompt はLLMプロンプトのテンプレート（組み込み・ファイル・データベースの上書き）の管理と展開を提供する
+package prompt
+
+import (
+	"context"
+	"time"
+
+	"github.com/samber/mo"
+)
+
+// テンプレート名
+const (
+	NameFileSummary         = "summary.file"         // ファイル要約
+	NameDirectorySummary    = "summary.directory"    // ディレクトリ要約
+	NameArchitectureSummary = "summary.architecture" // アーキテクチャ要約（概要・技術スタック・データフロー・コンポーネント）
+	NameProductSummary      = "summary.product"      // プロダクト全体の要約（各ソースのアーキテクチャ要約から）
+	NameWikiSection         = "wiki.section"         // Wikiセクションの生成
+	NameWikiFollowUp        = "wiki.follow_up"       // 追加のコンテキストによるWikiセクションの改善
+	NameWikiAPIOverview     = "wiki.api_overview"    // APIリファレンスのパッケージ概要
+	NameWikiOnboarding      = "wiki.onboarding"      // オンボーディングガイド
+	NameWikiTranslate       = "wiki.translate"       // Wikiページの他の言語への翻訳
+	NameWikiRunbook         = "wiki.runbook"         // 運用コマンドの説明
+	NameAsk                 = "ask"                  // 質問応答
+	NameAskCitation         = "ask.citation"         // 回答の引用の検証
+	NameQualityActions      = "quality.actions"      // 品質ノートからの改善アクションの生成
+	NameGlossaryExtract     = "glossary.extract"     // 用語集の抽出
+	NameReleaseNotes        = "release.notes"        // リリースノートの下書き
+)
+
+// MaxVersionLength はテンプレートのバージョンの最大文字数（生成物の prompt_version 列の長さ）
+const MaxVersionLength = 10
+
+// Source はテンプレートの取得元
+type Source string
+
+const (
+	SourceBuiltin Source = "builtin" // バイナリに組み込まれたテンプレート
+	SourceFile    Source = "file"    // PROMPT_TEMPLATE_DIR に置いたファイル（<name>.tmpl）
+	SourceDB      Source = "db"      // `dev-rag prompt set` でデータベースに保存したテンプレート
+)
+
+// Template はプロンプトのテンプレート
+type Template struct {
+	Name      string
+	Version   string
+	Body      string // text/template 形式の本文
+	Source    Source
+	UpdatedAt time.Time // データベースに保存したテンプレートのみ
+}
+
+// Rendered は展開したプロンプトと、生成物に記録するテンプレートの情報
+type Rendered struct {
+	Text    string
+	Name    string
+	Version string
+	Source  Source
+}
+
+// Repository はデータベースに保存したテンプレートのデータアクセスインターフェース
+type Repository interface {
+	// Get はテンプレートを取得する（保存されていない場合は None）
+	Get(ctx context.Context, name string) (mo.Option[*Template], error)
+	// List は保存したテンプレートを名前順に取得する
+	List(ctx context.Context) ([]*Template, error)
+	// Set はテンプレートを保存する（同じ名前が既にあれば上書きする）
+	Set(ctx context.Context, name, version, body string) (*Template, error)
+	// Delete はテンプレートを削除し、削除したかを返す
+	Delete(ctx context.Context, name string) (bool, error)
+}
@@ -0,0 +1,327 @@
+package prompt
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+)
+
+// cacheTTL はファイル・データベースから解決したテンプレートを再取得せずに使う期間
+// 要約の生成のようにファイルごとにプロンプトを展開する処理で、都度データベースを参照しないようにする
+const cacheTTL = time.Minute
+
+// Registry はテンプレートを解決して展開する
+// 解決の優先順位はデータベース（`dev-rag prompt set`）→ テンプレートディレクトリのファイル → 組み込み
+type Registry struct {
+	repo   Repository
+	dir    string
+	logger *slog.Logger
+
+	mu    sync.Mutex
+	cache map[string]cachedTemplate
+}
+
+// cachedTemplate は解決・解析済みのテンプレート
+type cachedTemplate struct {
+	template *Template
+	parsed   *template.Template
+	expires  time.Time
+}
+
+// RegistryOption は Registry のオプション設定
+type RegistryOption func(*Registry)
+
+// WithRepository はデータベースに保存したテンプレートを参照する
+func WithRepository(repo Repository) RegistryOption {
+	return func(r *Registry) {
+		r.repo = repo
+	}
+}
+
+// WithTemplateDir はテンプレートディレクトリの <name>.tmpl を組み込みのテンプレートより優先する
+func WithTemplateDir(dir string) RegistryOption {
+	return func(r *Registry) {
+		r.dir = dir
+	}
+}
+
+// WithRegistryLogger は Registry にロガーを設定する
+func WithRegistryLogger(logger *slog.Logger) RegistryOption {
+	return func(r *Registry) {
+		r.logger = logger
+	}
+}
+
+// NewRegistry は新しい Registry を作成する
+func NewRegistry(opts ...RegistryOption) *Registry {
+	r := &Registry{
+		logger: slog.Default(),
+		cache:  make(map[string]cachedTemplate),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	if r.logger == nil {
+		r.logger = slog.Default()
+	}
+	return r
+}
+
+var defaultRegistry = NewRegistry()
+
+// Default は組み込みのテンプレートのみを使う Registry を返す（テンプレートを設定しないサービスの既定値）
+func Default() *Registry {
+	return defaultRegistry
+}
+
+// Resolve は名前に対応する有効なテンプレートを返す
+func (r *Registry) Resolve(ctx context.Context, name string) (*Template, error) {
+	def, ok := lookupDefinition(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown prompt template %q", name)
+	}
+
+	if r.repo != nil {
+		stored, err := r.repo.Get(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get prompt template %s: %w", name, err)
+		}
+		if t, ok := stored.Get(); ok {
+			t.Source = SourceDB
+			return t, nil
+		}
+	}
+
+	if r.dir != "" {
+		body, err := os.ReadFile(filepath.Join(r.dir, name+".tmpl"))
+		switch {
+		case err == nil:
+			return &Template{Name: name, Version: contentVersion(string(body)), Body: string(body), Source: SourceFile}, nil
+		case !errors.Is(err, fs.ErrNotExist):
+			return nil, fmt.Errorf("failed to read prompt template file %s: %w", name, err)
+		}
+	}
+
+	body, err := builtinBody(name)
+	if err != nil {
+		return nil, err
+	}
+	return &Template{Name: name, Version: def.Version, Body: body, Source: SourceBuiltin}, nil
+}
+
+// List は全テンプレートの有効なテンプレートを定義順に返す
+func (r *Registry) List(ctx context.Context) ([]*Template, error) {
+	templates := make([]*Template, 0, len(definitions))
+	for _, def := range definitions {
+		t, err := r.Resolve(ctx, def.Name)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, t)
+	}
+	return templates, nil
+}
+
+// Render はテンプレートを展開する
+// 上書きしたテンプレートの取得・展開に失敗した場合は、警告を記録して組み込みのテンプレートで展開する
+func (r *Registry) Render(ctx context.Context, name string, data any) (*Rendered, error) {
+	entry, err := r.lookup(ctx, name)
+	if err == nil {
+		text, execErr := execute(entry.parsed, data)
+		if execErr == nil {
+			return &Rendered{Text: text, Name: name, Version: entry.template.Version, Source: entry.template.Source}, nil
+		}
+		if entry.template.Source == SourceBuiltin {
+			return nil, fmt.Errorf("failed to render prompt template %s: %w", name, execErr)
+		}
+		err = execErr
+	}
+	if _, ok := lookupDefinition(name); !ok {
+		return nil, err
+	}
+	r.logger.Warn("failed to use prompt template override, falling back to builtin", "name", name, "error", err)
+
+	builtin, err := builtinTemplate(name)
+	if err != nil {
+		return nil, err
+	}
+	text, err := execute(builtin.parsed, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render prompt template %s: %w", name, err)
+	}
+	return &Rendered{Text: text, Name: name, Version: builtin.template.Version, Source: SourceBuiltin}, nil
+}
+
+// Set はテンプレートをデータベースに保存する
+// version が空の場合は本文のハッシュから生成する。保存前に見本の値で展開できることを検証する
+func (r *Registry) Set(ctx context.Context, name, body, version string) (*Template, error) {
+	if r.repo == nil {
+		return nil, fmt.Errorf("prompt template repository is not configured")
+	}
+	def, ok := lookupDefinition(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown prompt template %q", name)
+	}
+
+	version = strings.TrimSpace(version)
+	if version == "" {
+		version = contentVersion(body)
+	}
+	if len(version) > MaxVersionLength {
+		return nil, fmt.Errorf("version %q is too long: at most %d characters", version, MaxVersionLength)
+	}
+	if strings.TrimSpace(body) == "" {
+		return nil, fmt.Errorf("template body is empty")
+	}
+	parsed, err := parse(name, version, body)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := execute(parsed, def.sample); err != nil {
+		return nil, fmt.Errorf("failed to render template %s with sample data: %w", name, err)
+	}
+
+	t, err := r.repo.Set(ctx, name, version, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save prompt template: %w", err)
+	}
+	t.Source = SourceDB
+	r.invalidate(name)
+	return t, nil
+}
+
+// Unset はデータベースに保存したテンプレートを削除し、削除したかを返す
+func (r *Registry) Unset(ctx context.Context, name string) (bool, error) {
+	if r.repo == nil {
+		return false, fmt.Errorf("prompt template repository is not configured")
+	}
+	if _, ok := lookupDefinition(name); !ok {
+		return false, fmt.Errorf("unknown prompt template %q", name)
+	}
+	deleted, err := r.repo.Delete(ctx, name)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete prompt template: %w", err)
+	}
+	r.invalidate(name)
+	return deleted, nil
+}
+
+// lookup はキャッシュから解析済みのテンプレートを返す（期限切れの場合は解決し直す）
+func (r *Registry) lookup(ctx context.Context, name string) (cachedTemplate, error) {
+	if r.repo == nil && r.dir == "" {
+		return builtinTemplate(name)
+	}
+
+	r.mu.Lock()
+	entry, ok := r.cache[name]
+	r.mu.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		return entry, nil
+	}
+
+	t, err := r.Resolve(ctx, name)
+	if err != nil {
+		return cachedTemplate{}, err
+	}
+	parsed, err := parse(name, t.Version, t.Body)
+	if err != nil {
+		return cachedTemplate{}, err
+	}
+	entry = cachedTemplate{template: t, parsed: parsed, expires: time.Now().Add(cacheTTL)}
+
+	r.mu.Lock()
+	r.cache[name] = entry
+	r.mu.Unlock()
+	return entry, nil
+}
+
+func (r *Registry) invalidate(name string) {
+	r.mu.Lock()
+	delete(r.cache, name)
+	r.mu.Unlock()
+}
+
+var (
+	builtinsOnce sync.Once
+	builtins     map[string]cachedTemplate
+	builtinsErr  error
+)
+
+// builtinTemplate は解析済みの組み込みのテンプレートを返す
+func builtinTemplate(name string) (cachedTemplate, error) {
+	builtinsOnce.Do(func() {
+		builtins = make(map[string]cachedTemplate, len(definitions))
+		for _, def := range definitions {
+			body, err := builtinBody(def.Name)
+			if err != nil {
+				builtinsErr = err
+				return
+			}
+			parsed, err := parse(def.Name, def.Version, body)
+			if err != nil {
+				builtinsErr = err
+				return
+			}
+			builtins[def.Name] = cachedTemplate{
+				template: &Template{Name: def.Name, Version: def.Version, Body: body, Source: SourceBuiltin},
+				parsed:   parsed,
+			}
+		}
+	})
+	if builtinsErr != nil {
+		return cachedTemplate{}, builtinsErr
+	}
+	entry, ok := builtins[name]
+	if !ok {
+		return cachedTemplate{}, fmt.Errorf("unknown prompt template %q", name)
+	}
+	return entry, nil
+}
+
+// Builtin は組み込みのテンプレートを返す（上書きするテンプレートの雛形として使用する）
+func Builtin(name string) (*Template, error) {
+	entry, err := builtinTemplate(name)
+	if err != nil {
+		return nil, err
+	}
+	t := *entry.template
+	return &t, nil
+}
+
+// MustRenderBuiltin は組み込みのテンプレートを展開する（上書きは参照しない）
+// 組み込みのテンプレートはテストで検証しているため、展開の失敗は呼び出し側の誤りとして panic する
+func MustRenderBuiltin(name string, data any) string {
+	entry, err := builtinTemplate(name)
+	if err != nil {
+		panic(err)
+	}
+	text, err := execute(entry.parsed, data)
+	if err != nil {
+		panic(fmt.Sprintf("failed to render builtin prompt template %s: %v", name, err))
+	}
+	return text
+}
+
+func execute(tmpl *template.Template, data any) (string, error) {
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// contentVersion は本文のハッシュからバージョンを生成する（ファイルのテンプレートとバージョン未指定の保存で使用）
+func contentVersion(body string) string {
+	sum := sha256.Sum256([]byte(body))
+	return hex.EncodeToString(sum[:])[:8]
+}
@@ -0,0 +1,184 @@
+package prompt
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryRepository struct {
+	templates map[string]*Template
+	gets      int
+}
+
+func newMemoryRepository() *memoryRepository {
+	return &memoryRepository{templates: make(map[string]*Template)}
+}
+
+func (m *memoryRepository) Get(ctx context.Context, name string) (mo.Option[*Template], error) {
+	m.gets++
+	t, ok := m.templates[name]
+	if !ok {
+		return mo.None[*Template](), nil
+	}
+	copied := *t
+	return mo.Some(&copied), nil
+}
+
+func (m *memoryRepository) List(ctx context.Context) ([]*Template, error) {
+	var templates []*Template
+	for _, t := range m.templates {
+		templates = append(templates, t)
+	}
+	return templates, nil
+}
+
+func (m *memoryRepository) Set(ctx context.Context, name, version, body string) (*Template, error) {
+	t := &Template{Name: name, Version: version, Body: body}
+	m.templates[name] = t
+	copied := *t
+	return &copied, nil
+}
+
+func (m *memoryRepository) Delete(ctx context.Context, name string) (bool, error) {
+	_, ok := m.templates[name]
+	delete(m.templates, name)
+	return ok, nil
+}
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func TestBuiltinTemplates_RenderWithSamples(t *testing.T) {
+	for _, def := range Definitions() {
+		t.Run(def.Name, func(t *testing.T) {
+			rendered, err := Default().Render(context.Background(), def.Name, def.sample)
+			require.NoError(t, err)
+			assert.NotEmpty(t, rendered.Text)
+			assert.Equal(t, def.Version, rendered.Version)
+			assert.Equal(t, SourceBuiltin, rendered.Source)
+			assert.LessOrEqual(t, len(def.Version), MaxVersionLength)
+		})
+	}
+}
+
+func TestBuiltinTemplates_ArchitectureSwitchesByType(t *testing.T) {
+	overview := MustRenderBuiltin(NameArchitectureSummary, ArchitectureSummaryData{ArchType: "overview", DirectoryCount: 2, FileCount: 7, DirectorySummaries: "- a: A"})
+	assert.Contains(t, overview, "以下のリポジトリの概要を作成してください。\n\n統計:\n- ディレクトリ数: 2\n- 総ファイル数: 7\n")
+
+	custom := MustRenderBuiltin(NameArchitectureSummary, ArchitectureSummaryData{ArchType: "security", DirectorySummaries: "- a: A"})
+	assert.Equal(t, "以下のリポジトリのsecurityについてまとめてください。\n\nディレクトリ要約:\n- a: A\n\n日本語、400字以内\n", custom)
+}
+
+func TestBuiltinTemplates_VersionFunc(t *testing.T) {
+	text := MustRenderBuiltin(NameQualityActions, ActionData{RecentChanges: "（なし）\n", Owners: "（なし）\n", MaxOpenActions: 5})
+	assert.Contains(t, text, `"prompt_version": "1.1"`)
+	assert.Contains(t, text, "最大5件")
+}
+
+func TestRegistry_ResolvePrecedence(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, NameFileSummary+".tmpl"), []byte("file: {{.Path}}\n"), 0o644))
+
+	repo := newMemoryRepository()
+	r := NewRegistry(WithRepository(repo), WithTemplateDir(dir), WithRegistryLogger(discardLogger()))
+
+	// テンプレートディレクトリのファイルは組み込みより優先し、バージョンは本文のハッシュ
+	resolved, err := r.Resolve(ctx, NameFileSummary)
+	require.NoError(t, err)
+	assert.Equal(t, SourceFile, resolved.Source)
+	assert.Len(t, resolved.Version, 8)
+
+	// ファイルのない名前は組み込みを使用する
+	resolved, err = r.Resolve(ctx, NameAsk)
+	require.NoError(t, err)
+	assert.Equal(t, SourceBuiltin, resolved.Source)
+	assert.Equal(t, "1.0", resolved.Version)
+
+	// データベースのテンプレートはファイルより優先する
+	_, err = r.Set(ctx, NameFileSummary, "db: {{.Path}}\n", "2.0")
+	require.NoError(t, err)
+	rendered, err := r.Render(ctx, NameFileSummary, FileSummaryData{Path: "main.go"})
+	require.NoError(t, err)
+	assert.Equal(t, "db: main.go\n", rendered.Text)
+	assert.Equal(t, "2.0", rendered.Version)
+	assert.Equal(t, SourceDB, rendered.Source)
+
+	// 削除するとファイルのテンプレートに戻る
+	deleted, err := r.Unset(ctx, NameFileSummary)
+	require.NoError(t, err)
+	assert.True(t, deleted)
+	rendered, err = r.Render(ctx, NameFileSummary, FileSummaryData{Path: "main.go"})
+	require.NoError(t, err)
+	assert.Equal(t, "file: main.go\n", rendered.Text)
+	assert.Equal(t, SourceFile, rendered.Source)
+
+	templates, err := r.List(ctx)
+	require.NoError(t, err)
+	assert.Len(t, templates, len(Definitions()))
+}
+
+func TestRegistry_RenderCachesOverrides(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemoryRepository()
+	r := NewRegistry(WithRepository(repo), WithRegistryLogger(discardLogger()))
+
+	for range 3 {
+		_, err := r.Render(ctx, NameGlossaryExtract, GlossaryData{})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 1, repo.gets)
+}
+
+func TestRegistry_RenderFallsBackToBuiltin(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	// 存在しないフィールドを参照するテンプレートは展開に失敗する
+	require.NoError(t, os.WriteFile(filepath.Join(dir, NameFileSummary+".tmpl"), []byte("{{.Missing}}"), 0o644))
+
+	r := NewRegistry(WithTemplateDir(dir), WithRegistryLogger(discardLogger()))
+	rendered, err := r.Render(ctx, NameFileSummary, FileSummaryData{Path: "main.go", Language: "go"})
+	require.NoError(t, err)
+	assert.Equal(t, SourceBuiltin, rendered.Source)
+	assert.Contains(t, rendered.Text, "パス: main.go\n言語: go\n")
+
+	_, err = r.Render(ctx, "unknown", nil)
+	assert.Error(t, err)
+}
+
+func TestRegistry_SetValidates(t *testing.T) {
+	ctx := context.Background()
+	r := NewRegistry(WithRepository(newMemoryRepository()), WithRegistryLogger(discardLogger()))
+
+	_, err := r.Set(ctx, "unknown", "text", "")
+	assert.ErrorContains(t, err, "unknown prompt template")
+
+	_, err = r.Set(ctx, NameAsk, "{{.Query", "")
+	assert.ErrorContains(t, err, "failed to parse template")
+
+	_, err = r.Set(ctx, NameAsk, "{{.Missing}}", "")
+	assert.ErrorContains(t, err, "sample data")
+
+	_, err = r.Set(ctx, NameAsk, "{{.Query}}", "12345678901")
+	assert.ErrorContains(t, err, "too long")
+
+	_, err = r.Set(ctx, NameAsk, "  \n", "")
+	assert.ErrorContains(t, err, "empty")
+
+	saved, err := r.Set(ctx, NameAsk, "{{.Query}}", "")
+	require.NoError(t, err)
+	assert.Equal(t, contentVersion("{{.Query}}"), saved.Version)
+	assert.Equal(t, SourceDB, saved.Source)
+
+	_, err = NewRegistry().Set(ctx, NameAsk, "{{.Query}}", "")
+	assert.ErrorContains(t, err, "not configured")
+}
@@ -0,0 +1,194 @@
+package quality
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+
+	"github.com/jinford/dev-rag/internal/core/prompt"
+)
+
+const (
+	// MaxOpenActionsPerWeek は1週間あたりに open とするアクションの上限（チームのキャパシティ）
+	MaxOpenActionsPerWeek = 5
+	// Unassigned は担当者のヒントがない場合の値
+	Unassigned = "unassigned"
+)
+
+// LLMClient はLLM通信インターフェース
+type LLMClient interface {
+	GenerateCompletion(ctx context.Context, prompt string) (string, error)
+}
+
+// PromptRenderer はプロンプトテンプレートを展開するインターフェース
+type PromptRenderer interface {
+	Render(ctx context.Context, name string, data any) (*prompt.Rendered, error)
+}
+
+// CodeownersLookup はファイルパスから CODEOWNERS で定義された担当者を取得するインターフェース
+type CodeownersLookup interface {
+	// OwnersForPaths はプロダクトの各ソースの最新スナップショットで、パスから担当者へのマップを返す（担当者の定義がないパスは含まない）
+	OwnersForPaths(ctx context.Context, productID uuid.UUID, paths []string) (map[string][]string, error)
+}
+
+// GenerateParams はアクション生成のパラメータ
+type GenerateParams struct {
+	ProductID     mo.Option[uuid.UUID] // 指定時はプロダクトの CODEOWNERS から担当者のヒントを設定する
+	WeekStart     time.Time            // 対象週の開始日
+	WeekEnd       time.Time            // 対象週の終了日
+	Notes         []*Note              // 対象の品質ノート
+	RecentChanges []*RecentChange      // 対処済みかの判定に使用する最近の変更
+}
+
+// ActionGenerator は品質ノートから優先度付きの品質改善アクションをLLMで生成する
+type ActionGenerator struct {
+	llm        LLMClient
+	codeowners CodeownersLookup
+	prompts    PromptRenderer
+	logger     *slog.Logger
+}
+
+// ActionGeneratorOption は ActionGenerator のオプション設定
+type ActionGeneratorOption func(*ActionGenerator)
+
+// WithActionGeneratorLogger は ActionGenerator にロガーを設定する
+func WithActionGeneratorLogger(logger *slog.Logger) ActionGeneratorOption {
+	return func(g *ActionGenerator) {
+		g.logger = logger
+	}
+}
+
+// WithCodeownersLookup は品質ノートの関連ファイルの担当者を、インデックス時に記録した CODEOWNERS から取得するための設定を行う
+func WithCodeownersLookup(lookup CodeownersLookup) ActionGeneratorOption {
+	return func(g *ActionGenerator) {
+		g.codeowners = lookup
+	}
+}
+
+// WithActionPrompts はアクション生成のプロンプトを展開するテンプレートを設定する（既定は組み込みのテンプレート）
+func WithActionPrompts(renderer PromptRenderer) ActionGeneratorOption {
+	return func(g *ActionGenerator) {
+		g.prompts = renderer
+	}
+}
+
+// NewActionGenerator は新しい ActionGenerator を作成する
+func NewActionGenerator(llm LLMClient, opts ...ActionGeneratorOption) *ActionGenerator {
+	g := &ActionGenerator{
+		llm:     llm,
+		prompts: prompt.Default(),
+		logger:  slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	if g.logger == nil {
+		g.logger = slog.Default()
+	}
+	if g.prompts == nil {
+		g.prompts = prompt.Default()
+	}
+	return g
+}
+
+// Generate は品質ノートから品質改善アクションを生成する（品質ノートがない場合は nil）
+// 担当者のヒントは CODEOWNERS の担当者を最優先とし、優先度の高い順に open のアクションを上限までとする
+func (g *ActionGenerator) Generate(ctx context.Context, params GenerateParams) ([]*Action, error) {
+	if len(params.Notes) == 0 {
+		return nil, nil
+	}
+
+	owners := g.lookupOwners(ctx, params)
+	rendered, err := g.prompts.Render(ctx, prompt.NameQualityActions, actionPromptData(params, owners))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build prompt: %w", err)
+	}
+	response, err := g.llm.GenerateCompletion(ctx, rendered.Text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate actions: %w", err)
+	}
+	actions, err := ParseActionResponse(response)
+	if err != nil {
+		return nil, err
+	}
+	// LLMの出力によらず、展開したテンプレートのバージョンを記録する
+	for _, action := range actions {
+		action.PromptVersion = rendered.Version
+	}
+
+	FinalizeActions(actions, owners)
+	g.logger.Info("generated quality actions", "notes", len(params.Notes), "actions", len(actions))
+	return actions, nil
+}
+
+// lookupOwners は品質ノートの関連ファイルの担当者を取得する
+// 担当者は補助的な情報のため、取得に失敗した場合は担当者なしで続行する
+func (g *ActionGenerator) lookupOwners(ctx context.Context, params GenerateParams) map[string][]string {
+	productID, ok := params.ProductID.Get()
+	if g.codeowners == nil || !ok {
+		return nil
+	}
+
+	var paths []string
+	for _, note := range params.Notes {
+		for _, f := range note.LinkedFiles {
+			if !slices.Contains(paths, f) {
+				paths = append(paths, f)
+			}
+		}
+	}
+	if len(paths) == 0 {
+		return nil
+	}
+
+	owners, err := g.codeowners.OwnersForPaths(ctx, productID, paths)
+	if err != nil {
+		g.logger.Warn("failed to look up codeowners", "error", err)
+		return nil
+	}
+	return owners
+}
+
+// FinalizeActions は生成したアクションに担当者のヒントと件数の上限を適用する
+//   - 関連ファイルに CODEOWNERS の担当者がいる場合は、LLMの出力によらずその担当者を担当者のヒントとする
+//   - 担当者のヒントがない場合は unassigned とする
+//   - 優先度の高い順に並べ、open のアクションが上限を超える分は noop とする
+func FinalizeActions(actions []*Action, owners map[string][]string) {
+	for _, action := range actions {
+		var hints []string
+		for _, f := range action.LinkedFiles {
+			for _, owner := range owners[f] {
+				if !slices.Contains(hints, owner) {
+					hints = append(hints, owner)
+				}
+			}
+		}
+		if len(hints) > 0 {
+			action.OwnerHint = strings.Join(hints, ", ")
+		}
+		if action.OwnerHint == "" {
+			action.OwnerHint = Unassigned
+		}
+	}
+
+	slices.SortStableFunc(actions, func(a, b *Action) int {
+		return cmp.Compare(a.Priority, b.Priority)
+	})
+	open := 0
+	for _, action := range actions {
+		if action.Status != ActionStatusOpen {
+			continue
+		}
+		open++
+		if open > MaxOpenActionsPerWeek {
+			action.Status = ActionStatusNoop
+		}
+	}
+}
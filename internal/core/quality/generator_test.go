@@ -0,0 +1,115 @@
+package quality
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubQualityLLM struct {
+	response string
+	prompt   string
+}
+
+func (l *stubQualityLLM) GenerateCompletion(ctx context.Context, prompt string) (string, error) {
+	l.prompt = prompt
+	return l.response, nil
+}
+
+type stubCodeowners struct {
+	owners map[string][]string
+	paths  []string
+}
+
+func (c *stubCodeowners) OwnersForPaths(ctx context.Context, productID uuid.UUID, paths []string) (map[string][]string, error) {
+	c.paths = paths
+	return c.owners, nil
+}
+
+func TestActionGenerator_Generate(t *testing.T) {
+	llm := &stubQualityLLM{response: "```json\n" + `[
+		{"prompt_version": "0.9", "priority": "P2", "action_type": "doc_fix", "title": "Document IndexSource params",
+		 "linked_files": ["pkg/indexer/README.md"], "owner_hint": "bob", "acceptance_criteria": "README lint passes", "status": "open"},
+		{"priority": "p1", "action_type": "reindex", "title": "Reindex ADR-005",
+		 "linked_files": ["docs/adr/ADR-005.md", "README.en.md"], "owner_hint": "alice", "acceptance_criteria": "chunks exist", "status": "open"},
+		{"priority": "P9", "action_type": "rewrite", "title": "Unknown cause", "status": "done"},
+		{"priority": "P1", "title": ""}
+	]` + "\n```"}
+	lookup := &stubCodeowners{owners: map[string][]string{
+		"docs/adr/ADR-005.md": {"@acme/architecture"},
+		"README.en.md":        {"@acme/docs", "@acme/architecture"},
+	}}
+	g := NewActionGenerator(llm,
+		WithCodeownersLookup(lookup),
+		WithActionGeneratorLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+	)
+
+	actions, err := g.Generate(context.Background(), GenerateParams{
+		ProductID: mo.Some(uuid.New()),
+		WeekStart: time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC),
+		WeekEnd:   time.Date(2025, 1, 12, 0, 0, 0, 0, time.UTC),
+		Notes: []*Note{
+			{NoteID: "QN-12", Severity: SeverityCritical, NoteText: "old version cited", LinkedFiles: []string{"docs/adr/ADR-005.md", "README.en.md"}, Reviewer: "alice"},
+			{NoteID: "QN-42", Severity: SeverityHigh, NoteText: "missing param docs", LinkedFiles: []string{"pkg/indexer/README.md"}, Reviewer: "bob"},
+		},
+		RecentChanges: []*RecentChange{{Hash: "9f2d3b4", FilesChanged: []string{"docs/adr/ADR-005.md"}, MergedAt: time.Date(2025, 1, 8, 0, 0, 0, 0, time.UTC)}},
+	})
+	require.NoError(t, err)
+	require.Len(t, actions, 3)
+
+	assert.Equal(t, []string{"docs/adr/ADR-005.md", "README.en.md", "pkg/indexer/README.md"}, lookup.paths)
+	assert.Contains(t, llm.prompt, "対象週: 2025-01-06 〜 2025-01-12")
+	assert.Contains(t, llm.prompt, "### [QN-12] severity: critical / reviewer: alice\n関連ファイル: docs/adr/ADR-005.md, README.en.md\n")
+	assert.Contains(t, llm.prompt, "- 9f2d3b4 (2025-01-08): docs/adr/ADR-005.md\n")
+	assert.Contains(t, llm.prompt, "- README.en.md: @acme/docs, @acme/architecture\n- docs/adr/ADR-005.md: @acme/architecture\n")
+
+	// 優先度の高い順に並び、CODEOWNERS の担当者が LLM の出力より優先される
+	assert.Equal(t, "Reindex ADR-005", actions[0].Title)
+	assert.Equal(t, PriorityP1, actions[0].Priority)
+	// LLMの出力によらず、展開したテンプレートのバージョンを記録する
+	assert.Equal(t, "1.1", actions[0].PromptVersion)
+	assert.Equal(t, "1.1", actions[1].PromptVersion)
+	assert.Equal(t, "@acme/architecture, @acme/docs", actions[0].OwnerHint)
+	// CODEOWNERS の担当者がいない場合は LLM の出力（reviewer）を使用する
+	assert.Equal(t, "bob", actions[1].OwnerHint)
+	// 不正な値は既定値に置き換える
+	assert.Equal(t, PriorityP3, actions[2].Priority)
+	assert.Equal(t, ActionInvestigate, actions[2].ActionType)
+	assert.Equal(t, ActionStatusOpen, actions[2].Status)
+	assert.Equal(t, Unassigned, actions[2].OwnerHint)
+}
+
+func TestFinalizeActions_CapsOpenActions(t *testing.T) {
+	var actions []*Action
+	for range MaxOpenActionsPerWeek + 2 {
+		actions = append(actions, &Action{Priority: PriorityP3, Status: ActionStatusOpen})
+	}
+	actions = append(actions, &Action{Priority: PriorityP1, Status: ActionStatusNoop})
+
+	FinalizeActions(actions, nil)
+
+	open := 0
+	for _, a := range actions {
+		if a.Status == ActionStatusOpen {
+			open++
+		}
+	}
+	assert.Equal(t, MaxOpenActionsPerWeek, open)
+	assert.Equal(t, PriorityP1, actions[0].Priority)
+	assert.Equal(t, ActionStatusNoop, actions[len(actions)-1].Status)
+}
+
+func TestActionGenerator_NoNotes(t *testing.T) {
+	llm := &stubQualityLLM{}
+	actions, err := NewActionGenerator(llm).Generate(context.Background(), GenerateParams{})
+	require.NoError(t, err)
+	assert.Nil(t, actions)
+	assert.Empty(t, llm.prompt)
+}
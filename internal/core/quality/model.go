@@ -0,0 +1,110 @@
+// Package quality はRAG回答の品質フィードバック（quality_notes）と、それを基にした品質改善アクションの生成を提供する
+package quality
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Severity は品質ノートの深刻度
+type Severity string
+
+const (
+	SeverityCritical Severity = "critical"
+	SeverityHigh     Severity = "high"
+	SeverityMedium   Severity = "medium"
+	SeverityLow      Severity = "low"
+)
+
+// Severities は深刻度の一覧（深刻な順）
+var Severities = []Severity{SeverityCritical, SeverityHigh, SeverityMedium, SeverityLow}
+
+// Priority は深刻度から算出するアクションの優先度を返す（critical→P1、high→P2、medium・low→P3）
+func (s Severity) Priority() Priority {
+	switch s {
+	case SeverityCritical:
+		return PriorityP1
+	case SeverityHigh:
+		return PriorityP2
+	default:
+		return PriorityP3
+	}
+}
+
+// NoteStatus は品質ノートのステータス
+type NoteStatus string
+
+const (
+	NoteStatusOpen     NoteStatus = "open"
+	NoteStatusResolved NoteStatus = "resolved"
+)
+
+// Note はRAG回答のレビューで記録した品質フィードバック
+type Note struct {
+	ID           uuid.UUID
+	NoteID       string // ビジネス識別子（例: QN-2024-001）
+	Severity     Severity
+	NoteText     string
+	LinkedFiles  []string
+	LinkedChunks []uuid.UUID
+	Reviewer     string
+	Status       NoteStatus
+	CreatedAt    time.Time
+	ResolvedAt   *time.Time
+}
+
+// Priority はアクションの優先度
+type Priority string
+
+const (
+	PriorityP1 Priority = "P1"
+	PriorityP2 Priority = "P2"
+	PriorityP3 Priority = "P3"
+)
+
+// ActionType はアクションの種別
+type ActionType string
+
+const (
+	ActionReindex     ActionType = "reindex"     // 再インデックス
+	ActionDocFix      ActionType = "doc_fix"     // ドキュメント修正
+	ActionTestUpdate  ActionType = "test_update" // テスト更新
+	ActionInvestigate ActionType = "investigate" // 原因不明の品質問題の調査
+)
+
+// ActionTypes はアクション種別の一覧
+var ActionTypes = []ActionType{ActionReindex, ActionDocFix, ActionTestUpdate, ActionInvestigate}
+
+// ActionStatus はアクションのステータス
+type ActionStatus string
+
+const (
+	ActionStatusOpen      ActionStatus = "open"
+	ActionStatusNoop      ActionStatus = "noop" // 実行不要（キャパシティ超過・対処済み）
+	ActionStatusCompleted ActionStatus = "completed"
+)
+
+// Action は品質ノートから生成した品質改善アクション
+type Action struct {
+	ID                 uuid.UUID
+	ActionID           string // ビジネス識別子（例: ACT-2025-001）
+	PromptVersion      string
+	Priority           Priority
+	ActionType         ActionType
+	Title              string
+	Description        string
+	LinkedFiles        []string
+	OwnerHint          string
+	AcceptanceCriteria string
+	Status             ActionStatus
+	CreatedAt          time.Time
+	CompletedAt        *time.Time
+}
+
+// RecentChange はアクションが対処済みかの判定に使用する最近のコミット
+type RecentChange struct {
+	Hash         string
+	FilesChanged []string
+	MergedAt     time.Time
+}
@@ -0,0 +1,148 @@
+package quality
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jinford/dev-rag/internal/core/prompt"
+)
+
+// BuildActionPrompt は品質ノート・最近の変更・CODEOWNERS から品質改善アクションを生成するプロンプトを構築する
+// owners は品質ノートの関連ファイルのパスから担当者へのマップ（codeowners_lookup）
+func BuildActionPrompt(params GenerateParams, owners map[string][]string) string {
+	return prompt.MustRenderBuiltin(prompt.NameQualityActions, actionPromptData(params, owners))
+}
+
+// actionPromptData はアクション生成のプロンプトテンプレートに渡す値を構築する
+func actionPromptData(params GenerateParams, owners map[string][]string) prompt.ActionData {
+	var notes strings.Builder
+	for _, note := range params.Notes {
+		notes.WriteString(fmt.Sprintf("### [%s] severity: %s / reviewer: %s\n", note.NoteID, note.Severity, note.Reviewer))
+		if len(note.LinkedFiles) > 0 {
+			notes.WriteString(fmt.Sprintf("関連ファイル: %s\n", strings.Join(note.LinkedFiles, ", ")))
+		}
+		fence := codeFence(note.NoteText)
+		notes.WriteString(fence + "\n")
+		notes.WriteString(strings.TrimRight(note.NoteText, "\n"))
+		notes.WriteString("\n" + fence + "\n\n")
+	}
+
+	var changes strings.Builder
+	if len(params.RecentChanges) == 0 {
+		changes.WriteString("（なし）\n")
+	}
+	for _, change := range params.RecentChanges {
+		changes.WriteString(fmt.Sprintf("- %s (%s): %s\n", change.Hash, formatDate(change.MergedAt), strings.Join(change.FilesChanged, ", ")))
+	}
+
+	var ownerLines strings.Builder
+	if len(owners) == 0 {
+		ownerLines.WriteString("（なし）\n")
+	}
+	paths := make([]string, 0, len(owners))
+	for p := range owners {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		ownerLines.WriteString(fmt.Sprintf("- %s: %s\n", p, strings.Join(owners[p], ", ")))
+	}
+
+	return prompt.ActionData{
+		WeekStart:      formatDate(params.WeekStart),
+		WeekEnd:        formatDate(params.WeekEnd),
+		Notes:          notes.String(),
+		RecentChanges:  changes.String(),
+		Owners:         ownerLines.String(),
+		MaxOpenActions: MaxOpenActionsPerWeek,
+	}
+}
+
+// generatedAction はLLMが返すアクションのJSON表現
+type generatedAction struct {
+	PromptVersion      string   `json:"prompt_version"`
+	Priority           string   `json:"priority"`
+	ActionType         string   `json:"action_type"`
+	Title              string   `json:"title"`
+	Description        string   `json:"description"`
+	LinkedFiles        []string `json:"linked_files"`
+	OwnerHint          string   `json:"owner_hint"`
+	AcceptanceCriteria string   `json:"acceptance_criteria"`
+	Status             string   `json:"status"`
+}
+
+// ParseActionResponse はLLMの応答からアクションを取り出す（prompt_version はLLMの出力をそのまま設定する）
+// タイトルのないアクションは除外し、不正な優先度は P3、種別は investigate、ステータスは open とする
+func ParseActionResponse(response string) ([]*Action, error) {
+	start := strings.Index(response, "[")
+	end := strings.LastIndex(response, "]")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("no JSON array in response")
+	}
+
+	var generated []generatedAction
+	if err := json.Unmarshal([]byte(response[start:end+1]), &generated); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	actions := make([]*Action, 0, len(generated))
+	for _, g := range generated {
+		title := strings.TrimSpace(g.Title)
+		if title == "" {
+			continue
+		}
+
+		action := &Action{
+			PromptVersion:      strings.TrimSpace(g.PromptVersion),
+			Priority:           Priority(strings.ToUpper(strings.TrimSpace(g.Priority))),
+			ActionType:         ActionType(strings.ToLower(strings.TrimSpace(g.ActionType))),
+			Title:              title,
+			Description:        strings.TrimSpace(g.Description),
+			OwnerHint:          strings.TrimSpace(g.OwnerHint),
+			AcceptanceCriteria: strings.TrimSpace(g.AcceptanceCriteria),
+			Status:             ActionStatus(strings.ToLower(strings.TrimSpace(g.Status))),
+		}
+		if !slices.Contains([]Priority{PriorityP1, PriorityP2, PriorityP3}, action.Priority) {
+			action.Priority = PriorityP3
+		}
+		if !slices.Contains(ActionTypes, action.ActionType) {
+			action.ActionType = ActionInvestigate
+		}
+		if action.Status != ActionStatusNoop {
+			action.Status = ActionStatusOpen
+		}
+		for _, f := range g.LinkedFiles {
+			if f = strings.TrimSpace(f); f != "" && !slices.Contains(action.LinkedFiles, f) {
+				action.LinkedFiles = append(action.LinkedFiles, f)
+			}
+		}
+		actions = append(actions, action)
+	}
+	return actions, nil
+}
+
+// formatDate は日付を YYYY-MM-DD 形式で返す（ゼロ値は空）
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
+
+// codeFence は内容に含まれるバッククォートの連続より長いコードフェンスを返す
+func codeFence(content string) string {
+	longest, run := 0, 0
+	for _, r := range content {
+		if r == '`' {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	return strings.Repeat("`", max(3, longest+1))
+}
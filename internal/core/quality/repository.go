@@ -0,0 +1,41 @@
+package quality
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NoteFilter は品質ノートの取得条件（ゼロ値の項目は絞り込まない）
+type NoteFilter struct {
+	Status      NoteStatus
+	Severity    Severity
+	CreatedFrom time.Time // 記録日時の下限（この日時を含む）
+	CreatedTo   time.Time // 記録日時の上限（この日時を含まない）
+	Limit       int
+}
+
+// ActionFilter は品質改善アクションの取得条件（ゼロ値の項目は絞り込まない）
+type ActionFilter struct {
+	Status ActionStatus
+	Limit  int
+}
+
+// Repository は品質ノートと品質改善アクションのデータアクセスインターフェース
+type Repository interface {
+	// CreateNote は品質ノートを保存する
+	CreateNote(ctx context.Context, note *Note) (*Note, error)
+	// ListNotes は品質ノートを新しい順に取得する
+	ListNotes(ctx context.Context, filter NoteFilter) ([]*Note, error)
+	// MaxNoteNumber は接頭辞で始まる品質ノートの識別子の末尾の番号の最大値を返す（ない場合は 0）
+	MaxNoteNumber(ctx context.Context, prefix string) (int, error)
+	// CreateAction は品質改善アクションを保存する
+	CreateAction(ctx context.Context, action *Action) (*Action, error)
+	// ListActions は品質改善アクションを優先度順に取得する
+	ListActions(ctx context.Context, filter ActionFilter) ([]*Action, error)
+	// MaxActionNumber は接頭辞で始まるアクションの識別子の末尾の番号の最大値を返す（ない場合は 0）
+	MaxActionNumber(ctx context.Context, prefix string) (int, error)
+	// ListRecentChanges はプロダクトの最新のチャンクから、paths のファイルを since 以降に変更したコミットを取得する
+	ListRecentChanges(ctx context.Context, productID uuid.UUID, paths []string, since time.Time) ([]*RecentChange, error)
+}
@@ -0,0 +1,254 @@
+package quality
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+)
+
+const (
+	// NoteIDPrefix は品質ノートの識別子の接頭辞（QN-YYYY-NNN）
+	NoteIDPrefix = "QN"
+	// ActionIDPrefix は品質改善アクションの識別子の接頭辞（ACT-YYYY-NNN）
+	ActionIDPrefix = "ACT"
+	// DefaultListLimit は一覧表示の既定の件数
+	DefaultListLimit = 100
+
+	// maxNotesPerWeek はアクション生成の入力とする品質ノートの上限
+	maxNotesPerWeek = 200
+	// maxRecentChanges はアクション生成の入力とする最近の変更の上限
+	maxRecentChanges = 50
+	// maxVarcharLen は action_backlog の VARCHAR(255) 列の最大文字数
+	maxVarcharLen = 255
+)
+
+// Service は品質ノートの記録と、品質ノートからの品質改善アクションの生成・参照を提供する
+type Service struct {
+	repo      Repository
+	generator *ActionGenerator
+	logger    *slog.Logger
+	now       func() time.Time
+}
+
+// ServiceOption は Service のオプション設定
+type ServiceOption func(*Service)
+
+// WithServiceLogger は Service にロガーを設定する
+func WithServiceLogger(logger *slog.Logger) ServiceOption {
+	return func(s *Service) {
+		s.logger = logger
+	}
+}
+
+// NewService は新しい Service を作成する
+func NewService(repo Repository, generator *ActionGenerator, opts ...ServiceOption) *Service {
+	svc := &Service{
+		repo:      repo,
+		generator: generator,
+		logger:    slog.Default(),
+		now:       time.Now,
+	}
+	for _, opt := range opts {
+		opt(svc)
+	}
+	if svc.logger == nil {
+		svc.logger = slog.Default()
+	}
+	return svc
+}
+
+// AddNoteParams は品質ノートの記録のパラメータ
+type AddNoteParams struct {
+	NoteID       string // 省略時は QN-YYYY-NNN を採番する
+	Severity     Severity
+	NoteText     string
+	LinkedFiles  []string
+	LinkedChunks []uuid.UUID
+	Reviewer     string
+}
+
+// AddNote は品質ノートを記録する
+func (s *Service) AddNote(ctx context.Context, params AddNoteParams) (*Note, error) {
+	if !slices.Contains(Severities, params.Severity) {
+		return nil, fmt.Errorf("invalid severity %q", params.Severity)
+	}
+	text := strings.TrimSpace(params.NoteText)
+	if text == "" {
+		return nil, fmt.Errorf("note text is required")
+	}
+	reviewer := strings.TrimSpace(params.Reviewer)
+	if reviewer == "" {
+		return nil, fmt.Errorf("reviewer is required")
+	}
+
+	noteID := strings.TrimSpace(params.NoteID)
+	if noteID == "" {
+		prefix := fmt.Sprintf("%s-%d-", NoteIDPrefix, s.now().Year())
+		n, err := s.repo.MaxNoteNumber(ctx, prefix)
+		if err != nil {
+			return nil, fmt.Errorf("failed to number quality note: %w", err)
+		}
+		noteID = formatID(prefix, n+1)
+	}
+
+	var files []string
+	for _, f := range params.LinkedFiles {
+		if f = strings.TrimSpace(f); f != "" && !slices.Contains(files, f) {
+			files = append(files, f)
+		}
+	}
+
+	note, err := s.repo.CreateNote(ctx, &Note{
+		NoteID:       noteID,
+		Severity:     params.Severity,
+		NoteText:     text,
+		LinkedFiles:  files,
+		LinkedChunks: params.LinkedChunks,
+		Reviewer:     reviewer,
+		Status:       NoteStatusOpen,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create quality note: %w", err)
+	}
+	return note, nil
+}
+
+// ListNotes は品質ノートを新しい順に返す（Limit が 0 以下の場合は既定の件数）
+func (s *Service) ListNotes(ctx context.Context, filter NoteFilter) ([]*Note, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultListLimit
+	}
+	notes, err := s.repo.ListNotes(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list quality notes: %w", err)
+	}
+	return notes, nil
+}
+
+// ListActions は品質改善アクションを優先度順に返す（Limit が 0 以下の場合は既定の件数）
+func (s *Service) ListActions(ctx context.Context, filter ActionFilter) ([]*Action, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultListLimit
+	}
+	actions, err := s.repo.ListActions(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list quality actions: %w", err)
+	}
+	return actions, nil
+}
+
+// GenerateActionsParams は品質改善アクションの生成のパラメータ
+type GenerateActionsParams struct {
+	ProductID mo.Option[uuid.UUID] // 指定時はプロダクトの CODEOWNERS と最近の変更を参照する
+	WeekStart time.Time            // 対象週の開始日（ParseWeek の戻り値）
+}
+
+// GenerateActionsResult は品質改善アクションの生成結果
+type GenerateActionsResult struct {
+	WeekStart time.Time
+	WeekEnd   time.Time
+	Notes     int
+	Actions   []*Action
+}
+
+// GenerateActions は対象週に記録された未解決の品質ノートから品質改善アクションを生成し、バックログに保存する
+func (s *Service) GenerateActions(ctx context.Context, params GenerateActionsParams) (*GenerateActionsResult, error) {
+	weekStart := WeekStart(params.WeekStart)
+	next := weekStart.AddDate(0, 0, 7)
+	result := &GenerateActionsResult{WeekStart: weekStart, WeekEnd: next.AddDate(0, 0, -1)}
+
+	notes, err := s.repo.ListNotes(ctx, NoteFilter{
+		Status:      NoteStatusOpen,
+		CreatedFrom: weekStart,
+		CreatedTo:   next,
+		Limit:       maxNotesPerWeek,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list quality notes: %w", err)
+	}
+	result.Notes = len(notes)
+	if len(notes) == 0 {
+		s.logger.Info("no open quality notes for the week", "weekStart", formatDate(weekStart))
+		return result, nil
+	}
+
+	actions, err := s.generator.Generate(ctx, GenerateParams{
+		ProductID:     params.ProductID,
+		WeekStart:     result.WeekStart,
+		WeekEnd:       result.WeekEnd,
+		Notes:         notes,
+		RecentChanges: s.recentChanges(ctx, params.ProductID, notes, weekStart),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := fmt.Sprintf("%s-%d-", ActionIDPrefix, weekStart.Year())
+	n, err := s.repo.MaxActionNumber(ctx, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to number quality actions: %w", err)
+	}
+	for _, action := range actions {
+		n++
+		action.ActionID = formatID(prefix, n)
+		action.Title = truncateRunes(action.Title, maxVarcharLen)
+		action.OwnerHint = truncateRunes(action.OwnerHint, maxVarcharLen)
+		saved, err := s.repo.CreateAction(ctx, action)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create quality action %s: %w", action.ActionID, err)
+		}
+		result.Actions = append(result.Actions, saved)
+	}
+	return result, nil
+}
+
+// recentChanges は品質ノートの関連ファイルを対象週の開始以降に変更したコミットを取得する
+// 対処済みかの判定は補助的な情報のため、取得に失敗した場合は最近の変更なしで続行する
+func (s *Service) recentChanges(ctx context.Context, productID mo.Option[uuid.UUID], notes []*Note, since time.Time) []*RecentChange {
+	id, ok := productID.Get()
+	if !ok {
+		return nil
+	}
+
+	var paths []string
+	for _, note := range notes {
+		for _, f := range note.LinkedFiles {
+			if !slices.Contains(paths, f) {
+				paths = append(paths, f)
+			}
+		}
+	}
+	if len(paths) == 0 {
+		return nil
+	}
+
+	changes, err := s.repo.ListRecentChanges(ctx, id, paths, since)
+	if err != nil {
+		s.logger.Warn("failed to list recent changes", "productID", id, "error", err)
+		return nil
+	}
+	if len(changes) > maxRecentChanges {
+		changes = changes[:maxRecentChanges]
+	}
+	return changes
+}
+
+// formatID は接頭辞と番号から識別子を返す（例: QN-2025-007）
+func formatID(prefix string, n int) string {
+	return fmt.Sprintf("%s%03d", prefix, n)
+}
+
+// truncateRunes は s を最大 n 文字に切り詰める
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
@@ -0,0 +1,176 @@
+package quality
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubQualityRepo struct {
+	notes        []*Note
+	noteFilter   NoteFilter
+	maxNote      int
+	maxAction    int
+	actionPrefix string
+	actions      []*Action
+	changes      []*RecentChange
+	changesErr   error
+	changePaths  []string
+	changesSince time.Time
+}
+
+func (r *stubQualityRepo) CreateNote(ctx context.Context, note *Note) (*Note, error) {
+	r.notes = append(r.notes, note)
+	return note, nil
+}
+
+func (r *stubQualityRepo) ListNotes(ctx context.Context, filter NoteFilter) ([]*Note, error) {
+	r.noteFilter = filter
+	return r.notes, nil
+}
+
+func (r *stubQualityRepo) MaxNoteNumber(ctx context.Context, prefix string) (int, error) {
+	return r.maxNote, nil
+}
+
+func (r *stubQualityRepo) CreateAction(ctx context.Context, action *Action) (*Action, error) {
+	r.actions = append(r.actions, action)
+	return action, nil
+}
+
+func (r *stubQualityRepo) ListActions(ctx context.Context, filter ActionFilter) ([]*Action, error) {
+	return r.actions, nil
+}
+
+func (r *stubQualityRepo) MaxActionNumber(ctx context.Context, prefix string) (int, error) {
+	r.actionPrefix = prefix
+	return r.maxAction, nil
+}
+
+func (r *stubQualityRepo) ListRecentChanges(ctx context.Context, productID uuid.UUID, paths []string, since time.Time) ([]*RecentChange, error) {
+	r.changePaths = paths
+	r.changesSince = since
+	return r.changes, r.changesErr
+}
+
+func newTestService(repo Repository, llm LLMClient) *Service {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	svc := NewService(repo, NewActionGenerator(llm, WithActionGeneratorLogger(logger)), WithServiceLogger(logger))
+	svc.now = func() time.Time { return time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC) }
+	return svc
+}
+
+func TestService_AddNote(t *testing.T) {
+	repo := &stubQualityRepo{maxNote: 6}
+	svc := newTestService(repo, &stubQualityLLM{})
+
+	note, err := svc.AddNote(context.Background(), AddNoteParams{
+		Severity:    SeverityHigh,
+		NoteText:    "  missing param docs\n",
+		LinkedFiles: []string{"pkg/indexer/README.md", " ", "pkg/indexer/README.md"},
+		Reviewer:    "bob",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "QN-2025-007", note.NoteID)
+	assert.Equal(t, "missing param docs", note.NoteText)
+	assert.Equal(t, []string{"pkg/indexer/README.md"}, note.LinkedFiles)
+	assert.Equal(t, NoteStatusOpen, note.Status)
+
+	// 識別子を指定した場合は採番しない
+	note, err = svc.AddNote(context.Background(), AddNoteParams{NoteID: "QN-12", Severity: SeverityLow, NoteText: "typo", Reviewer: "alice"})
+	require.NoError(t, err)
+	assert.Equal(t, "QN-12", note.NoteID)
+
+	_, err = svc.AddNote(context.Background(), AddNoteParams{Severity: "urgent", NoteText: "x", Reviewer: "bob"})
+	assert.Error(t, err)
+	_, err = svc.AddNote(context.Background(), AddNoteParams{Severity: SeverityLow, NoteText: " ", Reviewer: "bob"})
+	assert.Error(t, err)
+	_, err = svc.AddNote(context.Background(), AddNoteParams{Severity: SeverityLow, NoteText: "x"})
+	assert.Error(t, err)
+}
+
+func TestService_GenerateActions(t *testing.T) {
+	repo := &stubQualityRepo{
+		notes: []*Note{
+			{NoteID: "QN-2025-001", Severity: SeverityCritical, NoteText: "old version cited", LinkedFiles: []string{"docs/adr/ADR-005.md"}, Reviewer: "alice"},
+		},
+		maxAction:  2,
+		changesErr: errors.New("db error"),
+	}
+	llm := &stubQualityLLM{response: `[{"priority": "P1", "action_type": "reindex", "title": "Reindex ADR-005", "linked_files": ["docs/adr/ADR-005.md"], "owner_hint": "alice", "status": "open"},
+		{"priority": "P3", "action_type": "doc_fix", "title": "Fix typo", "status": "open"}]`}
+	svc := newTestService(repo, llm)
+
+	// 週の途中の日時を指定しても週の開始日（月曜日）から7日間を対象とする
+	result, err := svc.GenerateActions(context.Background(), GenerateActionsParams{
+		ProductID: mo.Some(uuid.New()),
+		WeekStart: time.Date(2025, 1, 8, 15, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, NoteStatusOpen, repo.noteFilter.Status)
+	assert.Equal(t, time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC), repo.noteFilter.CreatedFrom)
+	assert.Equal(t, time.Date(2025, 1, 13, 0, 0, 0, 0, time.UTC), repo.noteFilter.CreatedTo)
+	assert.Equal(t, time.Date(2025, 1, 12, 0, 0, 0, 0, time.UTC), result.WeekEnd)
+	assert.Equal(t, []string{"docs/adr/ADR-005.md"}, repo.changePaths)
+	assert.Equal(t, time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC), repo.changesSince)
+	// 最近の変更の取得に失敗しても生成は続行する
+	assert.Contains(t, llm.prompt, "## 入力: 最近の変更（recent_changes）\n\n（なし）\n")
+
+	assert.Equal(t, 1, result.Notes)
+	require.Len(t, result.Actions, 2)
+	assert.Equal(t, "ACT-2025-", repo.actionPrefix)
+	assert.Equal(t, "ACT-2025-003", result.Actions[0].ActionID)
+	assert.Equal(t, "ACT-2025-004", result.Actions[1].ActionID)
+	assert.Equal(t, Unassigned, result.Actions[1].OwnerHint)
+	assert.Len(t, repo.actions, 2)
+}
+
+func TestService_GenerateActions_NoNotes(t *testing.T) {
+	repo := &stubQualityRepo{}
+	llm := &stubQualityLLM{}
+	svc := newTestService(repo, llm)
+
+	result, err := svc.GenerateActions(context.Background(), GenerateActionsParams{WeekStart: time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)})
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.Notes)
+	assert.Empty(t, result.Actions)
+	assert.Empty(t, llm.prompt)
+	assert.Nil(t, repo.changePaths)
+}
+
+func TestParseWeek(t *testing.T) {
+	now := time.Date(2025, 1, 8, 15, 30, 0, 0, time.UTC) // 水曜日
+
+	tests := []struct {
+		input string
+		want  time.Time
+	}{
+		{"", time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)},
+		{"2025-W02", time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)},
+		{"2025-w01", time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC)},
+		{"2026-W53", time.Date(2026, 12, 28, 0, 0, 0, 0, time.UTC)},
+		{"2025-01-12", time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)},
+		{"2025-01-13", time.Date(2025, 1, 13, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseWeek(tt.input, now)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	for _, input := range []string{"2025-W00", "2025-W53", "next week", "2025/01/08"} {
+		_, err := ParseWeek(input, now)
+		assert.Error(t, err, input)
+	}
+}
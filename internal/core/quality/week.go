@@ -0,0 +1,42 @@
+package quality
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ParseWeek は対象週の指定から週の開始日（月曜日の 0 時）を返す
+// 指定は ISO 週（例: 2025-W02）または日付（例: 2025-01-08、その日を含む週）で、空の場合は now を含む週とする
+func ParseWeek(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return WeekStart(now), nil
+	}
+
+	var year, week int
+	if n, err := fmt.Sscanf(strings.ToUpper(s), "%d-W%d", &year, &week); err == nil && n == 2 {
+		if week < 1 || week > 53 {
+			return time.Time{}, fmt.Errorf("invalid week %q: week must be between 1 and 53", s)
+		}
+		// ISO 週の第1週は 1月4日を含む週
+		start := WeekStart(time.Date(year, time.January, 4, 0, 0, 0, 0, now.Location())).AddDate(0, 0, (week-1)*7)
+		if y, _ := start.ISOWeek(); y != year {
+			return time.Time{}, fmt.Errorf("invalid week %q: %d has no week %d", s, year, week)
+		}
+		return start, nil
+	}
+
+	date, err := time.ParseInLocation("2006-01-02", s, now.Location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid week %q: expected YYYY-Www or YYYY-MM-DD", s)
+	}
+	return WeekStart(date), nil
+}
+
+// WeekStart は t を含む週の開始日（月曜日の 0 時）を返す
+func WeekStart(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
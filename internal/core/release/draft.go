@@ -0,0 +1,239 @@
+package release
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jinford/dev-rag/internal/core/prompt"
+)
+
+const (
+	// maxPromptCommits はプロンプトに含めるコミット数の上限
+	maxPromptCommits = 200
+	// maxPromptFiles はプロンプトに含める変更されたファイル数の上限
+	maxPromptFiles = 200
+	// maxPromptSummaries はプロンプトに含めるファイル要約数の上限
+	maxPromptSummaries = 40
+	// summaryMaxChars はファイル要約1件あたりの最大文字数
+	summaryMaxChars = 400
+	// maxPromptImpacts はプロンプトに含める影響範囲のファイル数の上限
+	maxPromptImpacts = 30
+	// maxImpactDependents は影響範囲のファイル1件あたりに列挙する依存しているファイル数の上限
+	maxImpactDependents = 5
+	// shortHashLength はコミットハッシュの表示に使う文字数
+	shortHashLength = 7
+)
+
+// WikiPageFileName はWikiの出力ディレクトリに書き出すリリースノートのページのファイル名
+const WikiPageFileName = "release-notes.md"
+
+// chunkChangeLabels はプロンプトに載せる宣言の変更の種類
+var chunkChangeLabels = map[ChunkChangeKind]string{
+	ChunkAdded:    "追加",
+	ChunkModified: "変更",
+	ChunkRemoved:  "削除",
+	ChunkChanged:  "追加または変更",
+}
+
+// buildPromptData はリリースノートのプロンプトに渡す値を組み立てる
+func buildPromptData(params GenerateParams, history *History, changes []*ChunkChange, summaries []*FileSummary, impacts []*Impact) prompt.ReleaseNotesData {
+	return prompt.ReleaseNotesData{
+		SourceName: params.SourceName,
+		FromRef:    params.FromRef,
+		ToRef:      params.ToRef,
+		Commits:    formatCommits(history.Commits),
+		Files:      formatFiles(history.Files),
+		Changes:    formatChunkChanges(changes),
+		Summaries:  formatSummaries(summaries),
+		Impacts:    formatImpacts(impacts),
+	}
+}
+
+// formatCommits はコミットを1行1件で列挙する
+// マージコミットはマージしたコミットと内容が重なるため除く（マージコミットのみの場合は除かない）
+func formatCommits(commits []*Commit) string {
+	var listed []*Commit
+	for _, c := range commits {
+		if !c.Merge {
+			listed = append(listed, c)
+		}
+	}
+	if len(listed) == 0 {
+		listed = commits
+	}
+
+	var sb strings.Builder
+	for i, c := range listed {
+		if i == maxPromptCommits {
+			fmt.Fprintf(&sb, "- ほか %d 件\n", len(listed)-i)
+			break
+		}
+		fmt.Fprintf(&sb, "- %s %s (%s)\n", ShortHash(c.Hash), c.Subject, c.Author)
+	}
+	return orNone(sb.String())
+}
+
+// formatFiles は変更されたファイルを1行1件で列挙する
+func formatFiles(files []*FileChange) string {
+	var sb strings.Builder
+	for i, f := range files {
+		if i == maxPromptFiles {
+			fmt.Fprintf(&sb, "- ほか %d 件\n", len(files)-i)
+			break
+		}
+		if f.Kind == FileRenamed && f.OldPath != "" {
+			fmt.Fprintf(&sb, "- %s %s -> %s\n", f.Kind, f.OldPath, f.Path)
+			continue
+		}
+		fmt.Fprintf(&sb, "- %s %s\n", f.Kind, f.Path)
+	}
+	return orNone(sb.String())
+}
+
+// formatChunkChanges は変更された宣言を列挙し、シグネチャとドキュメントコメントの1行目を続ける
+func formatChunkChanges(changes []*ChunkChange) string {
+	var sb strings.Builder
+	for _, c := range changes {
+		kind := chunkChangeLabels[c.Kind]
+		if c.Type != "" {
+			kind += " " + c.Type
+		}
+		fmt.Fprintf(&sb, "- %s `%s` (%s)\n", kind, c.Name, c.Path)
+		if signature := firstLine(c.Signature); signature != "" {
+			fmt.Fprintf(&sb, "  - `%s`\n", signature)
+		}
+		if doc := firstLine(c.DocComment); doc != "" {
+			fmt.Fprintf(&sb, "  - %s\n", doc)
+		}
+	}
+	return orNone(sb.String())
+}
+
+// formatSummaries は変更されたファイルの要約を見出しと本文の組で列挙する
+func formatSummaries(summaries []*FileSummary) string {
+	if len(summaries) == 0 {
+		return "（なし）\n\n"
+	}
+	var sb strings.Builder
+	for i, s := range summaries {
+		if i == maxPromptSummaries {
+			break
+		}
+		fmt.Fprintf(&sb, "### %s\n%s\n\n", s.Path, truncate(strings.TrimSpace(s.Content), summaryMaxChars))
+	}
+	return sb.String()
+}
+
+// formatImpacts は変更されたファイルごとに依存しているファイルを列挙する
+func formatImpacts(impacts []*Impact) string {
+	var sb strings.Builder
+	for i, impact := range impacts {
+		if i == maxPromptImpacts {
+			fmt.Fprintf(&sb, "- ほか %d ファイル\n", len(impacts)-i)
+			break
+		}
+		dependents := impact.Dependents
+		rest := 0
+		if len(dependents) > maxImpactDependents {
+			rest = len(dependents) - maxImpactDependents
+			dependents = dependents[:maxImpactDependents]
+		}
+		line := strings.Join(dependents, ", ")
+		if rest > 0 {
+			line += fmt.Sprintf(", ほか %d ファイル", rest)
+		}
+		if len(impact.Symbols) > 0 {
+			line += fmt.Sprintf("（%s）", strings.Join(impact.Symbols, ", "))
+		}
+		fmt.Fprintf(&sb, "- %s: %s\n", impact.Path, line)
+	}
+	return orNone(sb.String())
+}
+
+// cleanContent はLLMの応答からコードフェンスを除いたリリースノートの本文を返す
+func cleanContent(response string) string {
+	content := strings.TrimSpace(response)
+	if strings.HasPrefix(content, "```") && strings.HasSuffix(content, "```") {
+		if i := strings.Index(content, "\n"); i >= 0 {
+			content = strings.TrimSpace(strings.TrimSuffix(content[i+1:], "```"))
+		}
+	}
+	return content
+}
+
+// RenderNote はリリースノートを「## ソース to（日付）」の見出しから始まるMarkdownにする
+func RenderNote(note *Note) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "## %s %s", note.SourceName, note.ToRef)
+	if !note.ReleasedAt.IsZero() {
+		fmt.Fprintf(&sb, "（%s）", note.ReleasedAt.Format("2006-01-02"))
+	}
+	fmt.Fprintf(&sb, "\n\n`%s..%s`（%s..%s）: コミット %d 件・ファイル %d 件\n\n",
+		note.FromRef, note.ToRef, ShortHash(note.FromCommit), ShortHash(note.ToCommit), note.CommitCount, note.FileCount)
+	sb.WriteString(strings.TrimSpace(note.Content))
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// RenderWikiPage はプロダクトのリリースノートを新しい順に並べたWikiのページにする
+func RenderWikiPage(notes []*Note) string {
+	var sb strings.Builder
+	sb.WriteString("# リリースノート\n\n")
+	if len(notes) == 0 {
+		sb.WriteString("リリースノートはまだありません（dev-rag release-notes で作成してください）。\n")
+		return sb.String()
+	}
+	for i, note := range notes {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(RenderNote(note))
+	}
+	return sb.String()
+}
+
+// ShortHash はコミットハッシュの先頭7文字を返す
+func ShortHash(hash string) string {
+	if len(hash) > shortHashLength {
+		return hash[:shortHashLength]
+	}
+	return hash
+}
+
+// changedPaths は変更されたファイルの変更後・変更前のパスを重複を除いて返す
+func changedPaths(files []*FileChange) []string {
+	seen := make(map[string]bool, len(files))
+	var paths []string
+	for _, f := range files {
+		for _, p := range []string{f.Path, f.OldPath} {
+			if p != "" && !seen[p] {
+				seen[p] = true
+				paths = append(paths, p)
+			}
+		}
+	}
+	return paths
+}
+
+func firstLine(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.Index(s, "\n"); i >= 0 {
+		s = strings.TrimSpace(s[:i])
+	}
+	return s
+}
+
+func truncate(s string, maxChars int) string {
+	if utf8.RuneCountInString(s) <= maxChars {
+		return s
+	}
+	return string([]rune(s)[:maxChars]) + "…"
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "（なし）\n"
+	}
+	return s
+}
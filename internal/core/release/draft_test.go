@@ -0,0 +1,70 @@
+package release
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatCommits(t *testing.T) {
+	commits := []*Commit{
+		{Hash: "a1b2c3d4e5f6", Author: "alice", Subject: "Merge pull request #12 from acme/cancel", Merge: true},
+		{Hash: "b2c3d4e5f6a1", Author: "bob", Subject: "feat: 注文のキャンセルAPIを追加"},
+	}
+	assert.Equal(t, "- b2c3d4e feat: 注文のキャンセルAPIを追加 (bob)\n", formatCommits(commits), "マージコミットは除く")
+
+	// マージコミットのみの場合は除かない
+	assert.Equal(t, "- a1b2c3d Merge pull request #12 from acme/cancel (alice)\n", formatCommits(commits[:1]))
+	assert.Equal(t, "（なし）\n", formatCommits(nil))
+}
+
+func TestFormatChunkChanges(t *testing.T) {
+	changes := []*ChunkChange{
+		{Path: "internal/order/service.go", Name: "OrderService.Cancel", Type: "method", Signature: "func (s *OrderService) Cancel(ctx context.Context, id string) error", DocComment: "Cancel は注文をキャンセルする\n在庫を戻す", Kind: ChunkAdded},
+		{Path: "internal/order/legacy.go", Name: "Refund", Type: "function", Kind: ChunkRemoved},
+	}
+	assert.Equal(t, "- 追加 method `OrderService.Cancel` (internal/order/service.go)\n"+
+		"  - `func (s *OrderService) Cancel(ctx context.Context, id string) error`\n"+
+		"  - Cancel は注文をキャンセルする\n"+
+		"- 削除 function `Refund` (internal/order/legacy.go)\n", formatChunkChanges(changes))
+}
+
+func TestFormatImpacts(t *testing.T) {
+	impacts := []*Impact{{
+		Path:       "internal/order/service.go",
+		Dependents: []string{"a.go", "b.go", "c.go", "d.go", "e.go", "f.go", "g.go"},
+		Symbols:    []string{"Cancel"},
+	}}
+	assert.Equal(t, "- internal/order/service.go: a.go, b.go, c.go, d.go, e.go, ほか 2 ファイル（Cancel）\n", formatImpacts(impacts))
+}
+
+func TestFormatFiles(t *testing.T) {
+	files := []*FileChange{
+		{Path: "internal/order/service.go", Kind: FileModified},
+		{Path: "internal/order/refund.go", OldPath: "internal/order/legacy.go", Kind: FileRenamed},
+	}
+	assert.Equal(t, "- modified internal/order/service.go\n- renamed internal/order/legacy.go -> internal/order/refund.go\n", formatFiles(files))
+	assert.Equal(t, []string{"internal/order/service.go", "internal/order/refund.go", "internal/order/legacy.go"}, changedPaths(files))
+}
+
+func TestCleanContent(t *testing.T) {
+	assert.Equal(t, "### 概要\n\n注文をキャンセルできるようになった。", cleanContent("```markdown\n### 概要\n\n注文をキャンセルできるようになった。\n```\n"))
+	assert.Equal(t, "### 概要", cleanContent("  ### 概要\n"))
+}
+
+func TestRenderWikiPage(t *testing.T) {
+	notes := []*Note{
+		{SourceName: "backend", FromRef: "v1.1.0", ToRef: "v1.2.0", FromCommit: "b2c3d4e5f6a1", ToCommit: "c3d4e5f6a1b2", ReleasedAt: time.Date(2025, 2, 3, 10, 0, 0, 0, time.UTC), CommitCount: 5, FileCount: 8, Content: "### 概要\n\n返金に対応した。\n"},
+		{SourceName: "backend", FromRef: "v1.0.0", ToRef: "v1.1.0", FromCommit: "a1b2c3d4e5f6", ToCommit: "b2c3d4e5f6a1", CommitCount: 12, FileCount: 30, Content: "### 概要\n\nキャンセルに対応した。"},
+	}
+
+	page := RenderWikiPage(notes)
+	require.True(t, strings.HasPrefix(page, "# リリースノート\n\n## backend v1.2.0（2025-02-03）\n\n`v1.1.0..v1.2.0`（b2c3d4e..c3d4e5f）: コミット 5 件・ファイル 8 件\n\n### 概要\n\n返金に対応した。\n"))
+	assert.Contains(t, page, "\n\n## backend v1.1.0\n\n", "日時がない場合は見出しに含めない")
+	assert.Less(t, strings.Index(page, "v1.2.0"), strings.Index(page, "## backend v1.1.0"))
+
+	assert.Contains(t, RenderWikiPage(nil), "まだありません")
+}
@@ -0,0 +1,104 @@
+// Package release はGitの履歴・変更された宣言・依存関係の影響範囲からリリースノートの下書きを生成する
+package release
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Commit は比較範囲のコミット
+type Commit struct {
+	Hash    string
+	Author  string
+	Date    time.Time
+	Subject string // コミットメッセージの1行目
+	Merge   bool   // マージコミット（親が複数）
+}
+
+// FileChangeKind はファイルの変更の種類
+type FileChangeKind string
+
+const (
+	FileAdded    FileChangeKind = "added"
+	FileModified FileChangeKind = "modified"
+	FileDeleted  FileChangeKind = "deleted"
+	FileRenamed  FileChangeKind = "renamed"
+)
+
+// FileChange は比較範囲で変更されたファイル
+type FileChange struct {
+	Path    string // 変更後のパス（削除の場合は削除前のパス）
+	OldPath string // 名前を変更した場合の変更前のパス
+	Kind    FileChangeKind
+}
+
+// History は2つの ref の間のGitの履歴
+type History struct {
+	FromCommit string
+	ToCommit   string
+	ToDate     time.Time     // to のコミット日時
+	Commits    []*Commit     // to から到達でき from から到達できないコミット（新しい順）
+	Files      []*FileChange // from と to のツリーの差分（パス順）
+}
+
+// ChunkChangeKind は宣言の変更の種類
+type ChunkChangeKind string
+
+const (
+	ChunkAdded    ChunkChangeKind = "added"
+	ChunkModified ChunkChangeKind = "modified"
+	ChunkRemoved  ChunkChangeKind = "removed"
+	// ChunkChanged は from のスナップショットがなく、追加か変更かを判定できない宣言
+	ChunkChanged ChunkChangeKind = "changed"
+)
+
+// ChunkChange は変更されたファイルの宣言（レベル2のチャンク）のうち、内容が変わったもの
+type ChunkChange struct {
+	Path       string
+	Name       string // 親の宣言がある場合は「親.名前」
+	Type       string // function / method / type など
+	Signature  string
+	DocComment string
+	Kind       ChunkChangeKind
+}
+
+// FileSummary は変更されたファイルの要約
+type FileSummary struct {
+	Path    string
+	Content string
+}
+
+// Impact は変更されたファイルに依存する他のファイル（依存関係による影響範囲）
+type Impact struct {
+	Path       string
+	Dependents []string // 依存しているファイル（依存の多い順）
+	Symbols    []string // 依存している宣言の名前
+}
+
+// Note は保存したリリースノート
+type Note struct {
+	ID            uuid.UUID
+	SourceID      uuid.UUID
+	SourceName    string
+	FromRef       string
+	ToRef         string
+	FromCommit    string
+	ToCommit      string
+	ReleasedAt    time.Time // to のコミット日時
+	CommitCount   int
+	FileCount     int
+	Content       string // リリースノートの本文（Markdown）
+	PromptVersion string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// GenerateParams はリリースノートの生成のパラメータ
+type GenerateParams struct {
+	SourceID   uuid.UUID
+	SourceName string
+	RepoURL    string // ソースのリポジトリURL
+	FromRef    string // 比較元の ref（タグ・ブランチ・コミットハッシュ）
+	ToRef      string // 比較先の ref
+}
@@ -0,0 +1,43 @@
+package release
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+
+	"github.com/jinford/dev-rag/internal/core/prompt"
+)
+
+// HistoryReader はリポジトリの2つの ref の間の履歴を取得するインターフェース
+type HistoryReader interface {
+	// History は repoURL のリポジトリを同期し、from から to までのコミットと変更されたファイルを取得する
+	History(ctx context.Context, repoURL, from, to string) (*History, error)
+}
+
+// Repository はリリースノートのデータアクセスインターフェース
+type Repository interface {
+	// FindSnapshot はソースのコミットのインデックス済みスナップショットを取得する
+	FindSnapshot(ctx context.Context, sourceID uuid.UUID, commit string) (mo.Option[uuid.UUID], error)
+	// ListChunkChanges は paths の宣言のうち from と to で内容が異なるものを重要度順に最大 limit 件取得する
+	// from が None の場合は to の宣言をすべて ChunkChanged として返す
+	ListChunkChanges(ctx context.Context, from mo.Option[uuid.UUID], to uuid.UUID, paths []string, limit int) ([]*ChunkChange, error)
+	// ListFileSummaries はスナップショットの paths のファイル要約を取得する
+	ListFileSummaries(ctx context.Context, snapshotID uuid.UUID, paths []string) ([]*FileSummary, error)
+	// ListImpacts はスナップショットで paths のファイルの宣言に依存する他のファイルを取得する
+	ListImpacts(ctx context.Context, snapshotID uuid.UUID, paths []string) ([]*Impact, error)
+	// SaveNote はリリースノートを保存する（同じソース・範囲のリリースノートがあれば更新する）
+	SaveNote(ctx context.Context, note *Note) (*Note, error)
+	// ListNotes はプロダクトのリリースノートを新しい順に取得する
+	ListNotes(ctx context.Context, productID uuid.UUID) ([]*Note, error)
+}
+
+// LLMClient はLLM呼び出しのインターフェース
+type LLMClient interface {
+	GenerateCompletion(ctx context.Context, prompt string) (string, error)
+}
+
+// PromptRenderer はプロンプトテンプレートを展開するインターフェース
+type PromptRenderer interface {
+	Render(ctx context.Context, name string, data any) (*prompt.Rendered, error)
+}
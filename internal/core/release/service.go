@@ -0,0 +1,180 @@
+package release
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"github.com/jinford/dev-rag/internal/core/llm"
+	"github.com/jinford/dev-rag/internal/core/prompt"
+)
+
+const (
+	// maxChunkChanges はプロンプトに含める変更された宣言数の上限
+	maxChunkChanges = 150
+	// notesMaxTokens はリリースノートの最大出力トークン数
+	notesMaxTokens = 4096
+)
+
+// Service はリリースノートの生成と参照を提供する
+type Service struct {
+	repo    Repository
+	history HistoryReader
+	llm     LLMClient
+	prompts PromptRenderer
+	logger  *slog.Logger
+}
+
+// ServiceOption は Service のオプション設定
+type ServiceOption func(*Service)
+
+// WithServiceLogger は Service にロガーを設定する
+func WithServiceLogger(logger *slog.Logger) ServiceOption {
+	return func(s *Service) {
+		s.logger = logger
+	}
+}
+
+// WithPrompts はリリースノートのプロンプトを展開するテンプレートを設定する（既定は組み込みのテンプレート）
+func WithPrompts(renderer PromptRenderer) ServiceOption {
+	return func(s *Service) {
+		s.prompts = renderer
+	}
+}
+
+// NewService は新しい Service を作成する
+func NewService(repo Repository, history HistoryReader, llm LLMClient, opts ...ServiceOption) *Service {
+	svc := &Service{
+		repo:    repo,
+		history: history,
+		llm:     llm,
+		prompts: prompt.Default(),
+		logger:  slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(svc)
+	}
+	if svc.logger == nil {
+		svc.logger = slog.Default()
+	}
+	if svc.prompts == nil {
+		svc.prompts = prompt.Default()
+	}
+	return svc
+}
+
+// Generate は from から to までのGitの履歴と、インデックス済みのスナップショットの変更された宣言・ファイル要約・依存関係の影響範囲から
+// リリースノートの下書きを生成して保存する
+// to のコミットがインデックス化されていない場合はGitの履歴のみから生成し、from のコミットがインデックス化されていない場合は宣言の追加・変更を区別しない
+func (s *Service) Generate(ctx context.Context, params GenerateParams) (*Note, error) {
+	if params.FromRef == "" || params.ToRef == "" {
+		return nil, fmt.Errorf("both from and to refs are required")
+	}
+
+	history, err := s.history.History(ctx, params.RepoURL, params.FromRef, params.ToRef)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read git history: %w", err)
+	}
+	if len(history.Commits) == 0 {
+		return nil, fmt.Errorf("no commits between %s and %s", params.FromRef, params.ToRef)
+	}
+
+	changes, summaries, impacts, err := s.readIndex(ctx, params, history)
+	if err != nil {
+		return nil, err
+	}
+
+	rendered, err := s.prompts.Render(ctx, prompt.NameReleaseNotes, buildPromptData(params, history, changes, summaries, impacts))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build prompt: %w", err)
+	}
+	response, err := s.llm.GenerateCompletion(llm.WithMaxTokens(ctx, notesMaxTokens), rendered.Text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate release notes: %w", err)
+	}
+	content := cleanContent(response)
+	if content == "" {
+		return nil, fmt.Errorf("LLM returned empty release notes")
+	}
+
+	note, err := s.repo.SaveNote(ctx, &Note{
+		SourceID:      params.SourceID,
+		SourceName:    params.SourceName,
+		FromRef:       params.FromRef,
+		ToRef:         params.ToRef,
+		FromCommit:    history.FromCommit,
+		ToCommit:      history.ToCommit,
+		ReleasedAt:    history.ToDate,
+		CommitCount:   len(history.Commits),
+		FileCount:     len(history.Files),
+		Content:       content,
+		PromptVersion: rendered.Version,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save release notes: %w", err)
+	}
+	note.SourceName = params.SourceName
+
+	s.logger.Info("release notes generated",
+		"source", params.SourceName,
+		"from", params.FromRef,
+		"to", params.ToRef,
+		"commits", note.CommitCount,
+		"files", note.FileCount,
+		"declarations", len(changes),
+		"impacts", len(impacts),
+	)
+	return note, nil
+}
+
+// readIndex は to のコミットのスナップショットから変更された宣言・ファイル要約・依存関係の影響範囲を取得する
+func (s *Service) readIndex(ctx context.Context, params GenerateParams, history *History) ([]*ChunkChange, []*FileSummary, []*Impact, error) {
+	paths := changedPaths(history.Files)
+	if len(paths) == 0 {
+		return nil, nil, nil, nil
+	}
+
+	toSnapshot, err := s.repo.FindSnapshot(ctx, params.SourceID, history.ToCommit)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to find snapshot: %w", err)
+	}
+	to, ok := toSnapshot.Get()
+	if !ok {
+		s.logger.Warn("target commit is not indexed, generating release notes from git history only",
+			"source", params.SourceName, "ref", params.ToRef, "commit", ShortHash(history.ToCommit))
+		return nil, nil, nil, nil
+	}
+	from, err := s.repo.FindSnapshot(ctx, params.SourceID, history.FromCommit)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to find snapshot: %w", err)
+	}
+	if from.IsAbsent() {
+		s.logger.Warn("base commit is not indexed, declarations are listed without distinguishing additions from modifications",
+			"source", params.SourceName, "ref", params.FromRef, "commit", ShortHash(history.FromCommit))
+	}
+
+	changes, err := s.repo.ListChunkChanges(ctx, from, to, paths, maxChunkChanges)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to list changed declarations: %w", err)
+	}
+	summaries, err := s.repo.ListFileSummaries(ctx, to, paths)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to list file summaries: %w", err)
+	}
+	impacts, err := s.repo.ListImpacts(ctx, to, paths)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to list dependency impacts: %w", err)
+	}
+	return changes, summaries, impacts, nil
+}
+
+// ListNotes はプロダクトのリリースノートを新しい順に返す
+func (s *Service) ListNotes(ctx context.Context, productID uuid.UUID) ([]*Note, error) {
+	notes, err := s.repo.ListNotes(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list release notes: %w", err)
+	}
+	return notes, nil
+}
@@ -0,0 +1,139 @@
+package release
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jinford/dev-rag/internal/core/prompt"
+)
+
+type historyStub struct {
+	history *History
+}
+
+func (h *historyStub) History(ctx context.Context, repoURL, from, to string) (*History, error) {
+	return h.history, nil
+}
+
+type repositoryStub struct {
+	snapshots map[string]uuid.UUID // コミットハッシュ -> スナップショット
+	changes   []*ChunkChange
+	impacts   []*Impact
+	saved     *Note
+
+	changesFrom mo.Option[uuid.UUID]
+}
+
+func (r *repositoryStub) FindSnapshot(ctx context.Context, sourceID uuid.UUID, commit string) (mo.Option[uuid.UUID], error) {
+	if id, ok := r.snapshots[commit]; ok {
+		return mo.Some(id), nil
+	}
+	return mo.None[uuid.UUID](), nil
+}
+
+func (r *repositoryStub) ListChunkChanges(ctx context.Context, from mo.Option[uuid.UUID], to uuid.UUID, paths []string, limit int) ([]*ChunkChange, error) {
+	r.changesFrom = from
+	return r.changes, nil
+}
+
+func (r *repositoryStub) ListFileSummaries(ctx context.Context, snapshotID uuid.UUID, paths []string) ([]*FileSummary, error) {
+	return []*FileSummary{{Path: "internal/order/service.go", Content: "注文の作成・キャンセルを行う"}}, nil
+}
+
+func (r *repositoryStub) ListImpacts(ctx context.Context, snapshotID uuid.UUID, paths []string) ([]*Impact, error) {
+	return r.impacts, nil
+}
+
+func (r *repositoryStub) SaveNote(ctx context.Context, note *Note) (*Note, error) {
+	saved := *note
+	saved.ID = uuid.New()
+	r.saved = &saved
+	return &saved, nil
+}
+
+func (r *repositoryStub) ListNotes(ctx context.Context, productID uuid.UUID) ([]*Note, error) {
+	return nil, nil
+}
+
+// promptLLM は受け取ったプロンプトを記録し、固定の応答を返す
+type promptLLM struct {
+	response string
+	prompt   string
+}
+
+func (l *promptLLM) GenerateCompletion(ctx context.Context, text string) (string, error) {
+	l.prompt = text
+	return l.response, nil
+}
+
+func newTestHistory() *History {
+	return &History{
+		FromCommit: "a1b2c3d4e5f6",
+		ToCommit:   "b2c3d4e5f6a1",
+		ToDate:     time.Date(2025, 2, 3, 10, 0, 0, 0, time.UTC),
+		Commits:    []*Commit{{Hash: "b2c3d4e5f6a1", Author: "bob", Subject: "feat: 注文のキャンセルAPIを追加"}},
+		Files:      []*FileChange{{Path: "internal/order/service.go", Kind: FileModified}},
+	}
+}
+
+func TestService_Generate(t *testing.T) {
+	to := uuid.New()
+	repo := &repositoryStub{
+		snapshots: map[string]uuid.UUID{"b2c3d4e5f6a1": to},
+		changes:   []*ChunkChange{{Path: "internal/order/service.go", Name: "OrderService.Cancel", Type: "method", Kind: ChunkChanged}},
+		impacts:   []*Impact{{Path: "internal/order/service.go", Dependents: []string{"internal/api/order.go"}}},
+	}
+	llm := &promptLLM{response: "### 概要\n\n注文をキャンセルできるようになった。\n"}
+	svc := NewService(repo, &historyStub{history: newTestHistory()}, llm, WithServiceLogger(slog.New(slog.DiscardHandler)))
+
+	note, err := svc.Generate(context.Background(), GenerateParams{SourceID: uuid.New(), SourceName: "backend", FromRef: "v1.0.0", ToRef: "v1.1.0"})
+	require.NoError(t, err)
+
+	assert.Contains(t, llm.prompt, "- b2c3d4e feat: 注文のキャンセルAPIを追加 (bob)")
+	assert.Contains(t, llm.prompt, "- 追加または変更 method `OrderService.Cancel` (internal/order/service.go)", "from がインデックス化されていない場合は追加・変更を区別しない")
+	assert.Contains(t, llm.prompt, "### internal/order/service.go\n注文の作成・キャンセルを行う")
+	assert.Contains(t, llm.prompt, "- internal/order/service.go: internal/api/order.go")
+	assert.True(t, repo.changesFrom.IsAbsent())
+
+	require.NotNil(t, repo.saved)
+	assert.Equal(t, "### 概要\n\n注文をキャンセルできるようになった。", note.Content)
+	assert.Equal(t, "backend", note.SourceName)
+	assert.Equal(t, 1, note.CommitCount)
+	assert.Equal(t, "b2c3d4e5f6a1", note.ToCommit)
+	assert.Equal(t, newTestHistory().ToDate, note.ReleasedAt)
+	def, ok := lookupPromptVersion(prompt.NameReleaseNotes)
+	require.True(t, ok)
+	assert.Equal(t, def, note.PromptVersion)
+}
+
+func TestService_Generate_NotIndexed(t *testing.T) {
+	// to のコミットがインデックス化されていない場合はGitの履歴のみから生成する
+	repo := &repositoryStub{changes: []*ChunkChange{{Path: "x.go", Name: "X", Kind: ChunkAdded}}}
+	llm := &promptLLM{response: "### 概要\n\n変更なし"}
+	svc := NewService(repo, &historyStub{history: newTestHistory()}, llm, WithServiceLogger(slog.New(slog.DiscardHandler)))
+
+	_, err := svc.Generate(context.Background(), GenerateParams{SourceName: "backend", FromRef: "v1.0.0", ToRef: "v1.1.0"})
+	require.NoError(t, err)
+	assert.Contains(t, llm.prompt, "## 変更された宣言\n\n（なし）\n")
+
+	// コミットがない範囲はエラー
+	svc = NewService(repo, &historyStub{history: &History{}}, llm)
+	_, err = svc.Generate(context.Background(), GenerateParams{FromRef: "v1.1.0", ToRef: "v1.1.0"})
+	assert.ErrorContains(t, err, "no commits")
+}
+
+func lookupPromptVersion(name string) (string, bool) {
+	for _, def := range prompt.Definitions() {
+		if def.Name == name {
+			return def.Version, true
+		}
+	}
+	return "", false
+}
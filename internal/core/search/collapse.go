@@ -0,0 +1,65 @@
+package search
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// CollapseOverlaps は同一ファイル内で行範囲が重なるチャンクを1件にまとめる
+// 関数チャンクとそのロジック単位のサブチャンクのように同じ行を含む結果が並ぶのを防ぐため、
+// 重なり合うチャンクの集まりごとにスコアが最も高いもの（同点の場合は上位レベル）を残す
+// 残したチャンクは元の並び順（スコア順）を保つ
+func CollapseOverlaps(results []*SearchResult) []*SearchResult {
+	if len(results) < 2 {
+		return results
+	}
+
+	byFile := make(map[string][]int)
+	for i, r := range results {
+		key := r.FilePath
+		if r.FileID != uuid.Nil {
+			key = r.FileID.String()
+		}
+		byFile[key] = append(byFile[key], i)
+	}
+
+	keep := make([]bool, len(results))
+	for _, indices := range byFile {
+		slices.SortFunc(indices, func(a, b int) int {
+			return cmp.Compare(results[a].StartLine, results[b].StartLine)
+		})
+
+		best, end := indices[0], results[indices[0]].EndLine
+		for _, i := range indices[1:] {
+			r := results[i]
+			if r.StartLine > end {
+				keep[best] = true
+				best, end = i, r.EndLine
+				continue
+			}
+			end = max(end, r.EndLine)
+			if better(r, results[best]) {
+				best = i
+			}
+		}
+		keep[best] = true
+	}
+
+	collapsed := make([]*SearchResult, 0, len(results))
+	for i, r := range results {
+		if keep[i] {
+			collapsed = append(collapsed, r)
+		}
+	}
+	return collapsed
+}
+
+// better は a が b より代表として適しているか（スコアが高い、同点なら上位レベル）を返す
+func better(a, b *SearchResult) bool {
+	if a.Score != b.Score {
+		return a.Score > b.Score
+	}
+	return a.Level < b.Level
+}
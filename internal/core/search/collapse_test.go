@@ -0,0 +1,65 @@
+package search
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollapseOverlaps(t *testing.T) {
+	fileID := uuid.New()
+	function := &SearchResult{ChunkID: uuid.New(), FileID: fileID, FilePath: "pay.go", StartLine: 10, EndLine: 40, Level: 2, Score: 0.7}
+	logic := &SearchResult{ChunkID: uuid.New(), FileID: fileID, FilePath: "pay.go", StartLine: 15, EndLine: 20, Level: 3, Score: 0.9}
+	apart := &SearchResult{ChunkID: uuid.New(), FileID: fileID, FilePath: "pay.go", StartLine: 50, EndLine: 60, Level: 2, Score: 0.6}
+	otherFile := &SearchResult{ChunkID: uuid.New(), FileID: uuid.New(), FilePath: "pay.go", StartLine: 10, EndLine: 40, Level: 2, Score: 0.5}
+
+	t.Run("重なるチャンクはスコアが最も高いものを残す", func(t *testing.T) {
+		collapsed := CollapseOverlaps([]*SearchResult{logic, function, apart, otherFile})
+
+		require.Len(t, collapsed, 3)
+		assert.Same(t, logic, collapsed[0])
+		assert.Same(t, apart, collapsed[1])
+		assert.Same(t, otherFile, collapsed[2], "同一パスでもファイルが異なれば統合しない")
+	})
+
+	t.Run("同点の場合は上位レベルのチャンクを残す", func(t *testing.T) {
+		sameScore := &SearchResult{ChunkID: uuid.New(), FileID: fileID, FilePath: "pay.go", StartLine: 15, EndLine: 20, Level: 3, Score: 0.7}
+
+		collapsed := CollapseOverlaps([]*SearchResult{sameScore, function})
+
+		require.Len(t, collapsed, 1)
+		assert.Same(t, function, collapsed[0])
+	})
+}
+
+func TestSearchService_SearchCollapsesOverlaps(t *testing.T) {
+	fileID := uuid.New()
+	results := []*SearchResult{
+		{ChunkID: uuid.New(), FileID: fileID, FilePath: "pay.go", StartLine: 15, EndLine: 20, Level: 3, Score: 0.9},
+		{ChunkID: uuid.New(), FileID: fileID, FilePath: "pay.go", StartLine: 10, EndLine: 40, Level: 2, Score: 0.7},
+	}
+
+	t.Run("既定では重なるチャンクを統合する", func(t *testing.T) {
+		svc := NewSearchService(&stubSearchRepo{results: results}, &stubEmbedder{})
+
+		got, err := svc.Search(context.Background(), SearchParams{ProductID: mo.Some(uuid.New()), Query: "hello"})
+		require.NoError(t, err)
+		assert.Len(t, got, 1)
+	})
+
+	t.Run("KeepOverlaps指定時は統合しない", func(t *testing.T) {
+		svc := NewSearchService(&stubSearchRepo{results: results}, &stubEmbedder{})
+
+		got, err := svc.Search(context.Background(), SearchParams{
+			ProductID: mo.Some(uuid.New()),
+			Query:     "hello",
+			Filter:    &SearchFilter{KeepOverlaps: true},
+		})
+		require.NoError(t, err)
+		assert.Len(t, got, 2)
+	})
+}
@@ -0,0 +1,115 @@
+package search
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+
+	"github.com/jinford/dev-rag/internal/core/usage"
+)
+
+// hopScoreDecay は展開したチャンクのスコアに1ホップごとに掛ける減衰率
+const hopScoreDecay = 0.5
+
+// DependencyExpansion は依存関係（chunk_dependencies）による検索結果の展開設定
+type DependencyExpansion struct {
+	MaxHops     int      // 辿るホップ数（0 以下なら展開しない）
+	MaxChunks   int      // 追加するチャンク数の上限（0 以下なら無制限）
+	TokenBudget int      // 追加するチャンクの合計トークン数の上限（0 以下なら無制限）
+	DepTypes    []string // 辿る依存の種類（"call" | "import" | "type"、空なら全種類）
+}
+
+// Enabled は展開が有効かを返す
+func (e DependencyExpansion) Enabled() bool {
+	return e.MaxHops > 0
+}
+
+// ExpandDependencies は検索結果のチャンクから依存関係（呼び出し元・呼び出し先・型依存）を辿り、
+// 直接つながるチャンクをホップ数の上限まで追加で取得する
+// スコアの高い起点に近いチャンクから順に、チャンク数とトークン数の上限に収まる範囲で採用する
+// 戻り値には検索結果に含まれていたチャンクは含まない
+func (s *SearchService) ExpandDependencies(ctx context.Context, seeds []*SearchResult, cfg DependencyExpansion) ([]*SearchResult, error) {
+	if !cfg.Enabled() || len(seeds) == 0 {
+		return nil, nil
+	}
+
+	visited := make(map[uuid.UUID]*SearchResult, len(seeds))
+	frontier := make([]uuid.UUID, 0, len(seeds))
+	for _, seed := range seeds {
+		if visited[seed.ChunkID] != nil {
+			continue
+		}
+		visited[seed.ChunkID] = seed
+		frontier = append(frontier, seed.ChunkID)
+	}
+
+	var expanded []*SearchResult
+	usedTokens := 0
+
+	for hop := 1; hop <= cfg.MaxHops && len(frontier) > 0; hop++ {
+		related, err := s.repo.GetRelatedChunks(ctx, frontier, cfg.DepTypes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get related chunks: %w", err)
+		}
+
+		// スコアの高い起点からつながるチャンクを優先する
+		slices.SortStableFunc(related, func(a, b *RelatedChunk) int {
+			return cmp.Compare(visited[b.ViaChunkID].Score, visited[a.ViaChunkID].Score)
+		})
+
+		next := make([]uuid.UUID, 0, len(related))
+		for _, rel := range related {
+			if visited[rel.ChunkID] != nil {
+				continue
+			}
+			if cfg.MaxChunks > 0 && len(expanded) >= cfg.MaxChunks {
+				break
+			}
+
+			tokens := usage.EstimateTokens(rel.Content)
+			if rel.TokenCount != nil {
+				tokens = *rel.TokenCount
+			}
+			// 予算を超えるチャンクは飛ばし、より小さいチャンクが収まる余地を残す
+			if cfg.TokenBudget > 0 && usedTokens+tokens > cfg.TokenBudget {
+				continue
+			}
+
+			via := visited[rel.ViaChunkID]
+			result := &SearchResult{
+				ChunkID:   rel.ChunkID,
+				FilePath:  rel.FilePath,
+				StartLine: rel.StartLine,
+				EndLine:   rel.EndLine,
+				Content:   rel.Content,
+				Score:     via.Score * hopScoreDecay,
+				Relation: &ChunkRelation{
+					ViaChunkID:   rel.ViaChunkID,
+					ViaFilePath:  via.FilePath,
+					ViaStartLine: via.StartLine,
+					ViaEndLine:   via.EndLine,
+					Direction:    rel.Direction,
+					DepType:      rel.DepType,
+					Symbol:       rel.Symbol,
+					Hop:          hop,
+				},
+			}
+			visited[rel.ChunkID] = result
+			usedTokens += tokens
+			next = append(next, rel.ChunkID)
+			expanded = append(expanded, result)
+		}
+		frontier = next
+	}
+
+	s.logger.Debug("dependency expansion completed",
+		"seeds", len(seeds),
+		"expanded", len(expanded),
+		"tokens", usedTokens,
+	)
+
+	return expanded, nil
+}
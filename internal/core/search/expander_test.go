@@ -0,0 +1,115 @@
+package search
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func relatedChunk(via uuid.UUID, direction, depType string, tokens int) *RelatedChunk {
+	return &RelatedChunk{
+		ViaChunkID: via,
+		Direction:  direction,
+		DepType:    depType,
+		ChunkID:    uuid.New(),
+		FilePath:   "pkg/" + depType + ".go",
+		StartLine:  1,
+		EndLine:    10,
+		Content:    strings.Repeat("x", tokens*4),
+		TokenCount: &tokens,
+	}
+}
+
+func TestSearchService_ExpandDependencies(t *testing.T) {
+	seed := &SearchResult{ChunkID: uuid.New(), Score: 0.8}
+
+	t.Run("ホップ数の上限まで辿りスコアを減衰させる", func(t *testing.T) {
+		callee := relatedChunk(seed.ChunkID, DirectionOutgoing, "call", 10)
+		caller := relatedChunk(seed.ChunkID, DirectionIncoming, "call", 10)
+		second := relatedChunk(callee.ChunkID, DirectionOutgoing, "type", 10)
+		third := relatedChunk(second.ChunkID, DirectionOutgoing, "type", 10)
+		repo := &stubSearchRepo{related: map[uuid.UUID][]*RelatedChunk{
+			seed.ChunkID:   {callee, caller},
+			callee.ChunkID: {second},
+			second.ChunkID: {third},
+		}}
+		svc := NewSearchService(repo, &stubEmbedder{})
+
+		expanded, err := svc.ExpandDependencies(context.Background(), []*SearchResult{seed}, DependencyExpansion{MaxHops: 2})
+		require.NoError(t, err)
+		require.Len(t, expanded, 3)
+
+		assert.Equal(t, callee.ChunkID, expanded[0].ChunkID)
+		assert.Equal(t, 1, expanded[0].Relation.Hop)
+		assert.InDelta(t, 0.4, expanded[0].Score, 1e-9)
+		assert.Equal(t, DirectionIncoming, expanded[1].Relation.Direction)
+		assert.Equal(t, second.ChunkID, expanded[2].ChunkID)
+		assert.Equal(t, 2, expanded[2].Relation.Hop)
+		assert.InDelta(t, 0.2, expanded[2].Score, 1e-9)
+	})
+
+	t.Run("検索結果に含まれるチャンクと重複するチャンクは追加しない", func(t *testing.T) {
+		other := &SearchResult{ChunkID: uuid.New(), Score: 0.5}
+		repo := &stubSearchRepo{related: map[uuid.UUID][]*RelatedChunk{
+			seed.ChunkID: {{ViaChunkID: seed.ChunkID, Direction: DirectionOutgoing, DepType: "call", ChunkID: other.ChunkID}},
+		}}
+		svc := NewSearchService(repo, &stubEmbedder{})
+
+		expanded, err := svc.ExpandDependencies(context.Background(), []*SearchResult{seed, other}, DependencyExpansion{MaxHops: 1})
+		require.NoError(t, err)
+		assert.Empty(t, expanded)
+	})
+
+	t.Run("トークン予算とチャンク数の上限に収まる範囲で採用する", func(t *testing.T) {
+		large := relatedChunk(seed.ChunkID, DirectionOutgoing, "call", 300)
+		small1 := relatedChunk(seed.ChunkID, DirectionOutgoing, "type", 50)
+		small2 := relatedChunk(seed.ChunkID, DirectionOutgoing, "import", 50)
+		small3 := relatedChunk(seed.ChunkID, DirectionIncoming, "call", 50)
+		repo := &stubSearchRepo{related: map[uuid.UUID][]*RelatedChunk{
+			seed.ChunkID: {large, small1, small2, small3},
+		}}
+		svc := NewSearchService(repo, &stubEmbedder{})
+
+		expanded, err := svc.ExpandDependencies(context.Background(), []*SearchResult{seed}, DependencyExpansion{
+			MaxHops:     1,
+			MaxChunks:   2,
+			TokenBudget: 200,
+		})
+		require.NoError(t, err)
+		require.Len(t, expanded, 2)
+		assert.Equal(t, small1.ChunkID, expanded[0].ChunkID)
+		assert.Equal(t, small2.ChunkID, expanded[1].ChunkID)
+	})
+
+	t.Run("依存の種類で絞り込む", func(t *testing.T) {
+		call := relatedChunk(seed.ChunkID, DirectionOutgoing, "call", 10)
+		imp := relatedChunk(seed.ChunkID, DirectionOutgoing, "import", 10)
+		repo := &stubSearchRepo{related: map[uuid.UUID][]*RelatedChunk{
+			seed.ChunkID: {call, imp},
+		}}
+		svc := NewSearchService(repo, &stubEmbedder{})
+
+		expanded, err := svc.ExpandDependencies(context.Background(), []*SearchResult{seed}, DependencyExpansion{
+			MaxHops:  1,
+			DepTypes: []string{"call"},
+		})
+		require.NoError(t, err)
+		require.Len(t, expanded, 1)
+		assert.Equal(t, call.ChunkID, expanded[0].ChunkID)
+	})
+
+	t.Run("ホップ数が0の場合は展開しない", func(t *testing.T) {
+		repo := &stubSearchRepo{related: map[uuid.UUID][]*RelatedChunk{
+			seed.ChunkID: {relatedChunk(seed.ChunkID, DirectionOutgoing, "call", 10)},
+		}}
+		svc := NewSearchService(repo, &stubEmbedder{})
+
+		expanded, err := svc.ExpandDependencies(context.Background(), []*SearchResult{seed}, DependencyExpansion{})
+		require.NoError(t, err)
+		assert.Empty(t, expanded)
+	})
+}
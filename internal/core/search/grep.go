@@ -0,0 +1,166 @@
+package search
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+)
+
+// defaultGrepLimit は正規表現検索で返す行の既定の上限
+const defaultGrepLimit = 100
+
+// GrepParams はインデックス済みのチャンクの内容に対する正規表現検索のパラメータを表す
+type GrepParams struct {
+	ProductID  uuid.UUID
+	SnapshotID mo.Option[uuid.UUID] // 指定時はこのスナップショットのみ（None の場合は各ソースの最新スナップショット）
+	Pattern    string
+	IgnoreCase bool   // 大文字小文字を区別しない
+	Fixed      bool   // パターンを正規表現ではなく文字列として扱う
+	PathPrefix string // ファイルパスの前方一致
+	PathGlob   string // ファイルパス全体のグロブ（* は / を含む任意の文字列、? は任意の1文字）
+	Limit      int    // 一致する行の上限（0 以下なら 100）
+}
+
+// GrepQuery はリポジトリに渡す正規表現検索の条件を表す
+type GrepQuery struct {
+	SnapshotID mo.Option[uuid.UUID]
+	Pattern    string // POSIX 正規表現
+	IgnoreCase bool
+	PathPrefix string
+	PathLike   string // LIKE のパターン（空の場合は絞り込まない）
+	MaxChunks  int
+}
+
+// GrepChunk は内容がパターンに一致したチャンクを表す
+type GrepChunk struct {
+	ChunkID    uuid.UUID
+	FileID     uuid.UUID
+	SourceName string
+	FilePath   string
+	StartLine  int
+	EndLine    int
+	Content    string
+}
+
+// GrepMatch はパターンに一致した行を表す
+type GrepMatch struct {
+	SourceName string    `json:"sourceName"`
+	FilePath   string    `json:"filePath"`
+	Line       int       `json:"line"`
+	Text       string    `json:"text"`
+	ChunkID    uuid.UUID `json:"chunkId"`
+}
+
+// GrepResult は正規表現検索の結果を表す
+type GrepResult struct {
+	Matches   []*GrepMatch `json:"matches"`
+	Truncated bool         `json:"truncated"` // 上限に達したため一致する行が他にもある可能性がある
+}
+
+// Grep はインデックス済みのチャンクの内容を正規表現で検索し、一致した行を返す
+// ベクトル検索を使わず、リポジトリをクローンせずにインデックス済みのスナップショットを完全一致で調べるためのもの
+func (s *SearchService) Grep(ctx context.Context, params GrepParams) (*GrepResult, error) {
+	if params.Pattern == "" {
+		return nil, fmt.Errorf("pattern is required")
+	}
+	pattern := params.Pattern
+	if params.Fixed {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+	expr := pattern
+	if params.IgnoreCase {
+		expr = "(?i)" + expr
+	}
+	// 一致した行の特定に使用する（PostgreSQL の正規表現と共通の構文のみを想定する）
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern: %w", err)
+	}
+	limit := params.Limit
+	if limit <= 0 {
+		limit = defaultGrepLimit
+	}
+
+	chunks, err := s.repo.GrepChunks(ctx, params.ProductID, GrepQuery{
+		SnapshotID: params.SnapshotID,
+		Pattern:    pattern,
+		IgnoreCase: params.IgnoreCase,
+		PathPrefix: params.PathPrefix,
+		PathLike:   GlobToLike(params.PathGlob),
+		MaxChunks:  limit + 1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to grep chunks: %w", err)
+	}
+
+	result := &GrepResult{Truncated: len(chunks) > limit}
+	// 関数とロジック単位のチャンクは行範囲が重なるため、ファイルと行で重複を除く
+	type lineKey struct {
+		fileID uuid.UUID
+		line   int
+	}
+	seen := make(map[lineKey]bool)
+	for _, chunk := range chunks {
+		for i, text := range strings.Split(chunk.Content, "\n") {
+			line := chunk.StartLine + i
+			if !re.MatchString(text) || seen[lineKey{chunk.FileID, line}] {
+				continue
+			}
+			seen[lineKey{chunk.FileID, line}] = true
+			result.Matches = append(result.Matches, &GrepMatch{
+				SourceName: chunk.SourceName,
+				FilePath:   chunk.FilePath,
+				Line:       line,
+				Text:       strings.TrimRight(text, "\r"),
+				ChunkID:    chunk.ChunkID,
+			})
+		}
+	}
+
+	slices.SortStableFunc(result.Matches, func(a, b *GrepMatch) int {
+		return cmp.Or(
+			cmp.Compare(a.SourceName, b.SourceName),
+			cmp.Compare(a.FilePath, b.FilePath),
+			cmp.Compare(a.Line, b.Line),
+		)
+	})
+	if len(result.Matches) > limit {
+		result.Matches = result.Matches[:limit]
+		result.Truncated = true
+	}
+
+	s.logger.Debug("grep completed", "pattern", params.Pattern, "chunks", len(chunks), "matches", len(result.Matches))
+	return result, nil
+}
+
+// GlobToLike はファイルパスのグロブを LIKE のパターンに変換する（空文字の場合は空文字を返す）
+// * と ** は / を含む任意の文字列、? は任意の1文字として扱う
+func GlobToLike(glob string) string {
+	if glob == "" {
+		return ""
+	}
+	var b strings.Builder
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			for i+1 < len(glob) && glob[i+1] == '*' {
+				i++
+			}
+			b.WriteByte('%')
+		case '?':
+			b.WriteByte('_')
+		case '%', '_', '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
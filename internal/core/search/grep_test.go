@@ -0,0 +1,66 @@
+package search
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchService_Grep(t *testing.T) {
+	fileID := uuid.New()
+	function := &GrepChunk{ChunkID: uuid.New(), FileID: fileID, SourceName: "backend", FilePath: "payment/retry.go", StartLine: 10, EndLine: 14,
+		Content: "func Retry() error {\n\tfor i := 0; i < MaxRetries; i++ {\n\t\t// retry\n\t}\n\treturn ErrRetry\n}"}
+	logic := &GrepChunk{ChunkID: uuid.New(), FileID: fileID, SourceName: "backend", FilePath: "payment/retry.go", StartLine: 11, EndLine: 13,
+		Content: "\tfor i := 0; i < MaxRetries; i++ {\n\t\t// retry\n\t}"}
+	config := &GrepChunk{ChunkID: uuid.New(), FileID: uuid.New(), SourceName: "backend", FilePath: "config/app.yaml", StartLine: 1, EndLine: 2,
+		Content: "payment:\n  max_retries: 3"}
+
+	t.Run("一致した行を重複なくパス・行順に返す", func(t *testing.T) {
+		repo := &stubSearchRepo{grepChunks: []*GrepChunk{config, function, logic}}
+		svc := NewSearchService(repo, &stubEmbedder{})
+
+		result, err := svc.Grep(context.Background(), GrepParams{ProductID: uuid.New(), Pattern: "max_?retries", IgnoreCase: true, PathGlob: "**/*.go"})
+		require.NoError(t, err)
+
+		assert.Equal(t, "max_?retries", repo.lastGrep.Pattern)
+		assert.True(t, repo.lastGrep.IgnoreCase)
+		assert.Equal(t, "%/%.go", repo.lastGrep.PathLike)
+		assert.Equal(t, defaultGrepLimit+1, repo.lastGrep.MaxChunks)
+
+		require.Len(t, result.Matches, 2)
+		assert.Equal(t, "config/app.yaml", result.Matches[0].FilePath)
+		assert.Equal(t, 2, result.Matches[0].Line)
+		assert.Equal(t, "payment/retry.go", result.Matches[1].FilePath)
+		assert.Equal(t, 11, result.Matches[1].Line, "関数とロジック単位のチャンクで重なる行は1回だけ返す")
+		assert.Equal(t, "\tfor i := 0; i < MaxRetries; i++ {", result.Matches[1].Text)
+		assert.False(t, result.Truncated)
+	})
+
+	t.Run("文字列として検索し上限で打ち切る", func(t *testing.T) {
+		repo := &stubSearchRepo{grepChunks: []*GrepChunk{function, logic}}
+		svc := NewSearchService(repo, &stubEmbedder{})
+
+		result, err := svc.Grep(context.Background(), GrepParams{ProductID: uuid.New(), Pattern: "i++", Fixed: true, Limit: 1})
+		require.NoError(t, err)
+
+		assert.Equal(t, `i\+\+`, repo.lastGrep.Pattern)
+		require.Len(t, result.Matches, 1)
+		assert.True(t, result.Truncated)
+	})
+
+	t.Run("不正なパターン", func(t *testing.T) {
+		svc := NewSearchService(&stubSearchRepo{}, &stubEmbedder{})
+		_, err := svc.Grep(context.Background(), GrepParams{ProductID: uuid.New(), Pattern: "(unclosed"})
+		assert.Error(t, err)
+	})
+}
+
+func TestGlobToLike(t *testing.T) {
+	assert.Equal(t, "", GlobToLike(""))
+	assert.Equal(t, "%.go", GlobToLike("*.go"))
+	assert.Equal(t, "internal/%/service_.go", GlobToLike("internal/**/service?.go"))
+	assert.Equal(t, `docs/100\%\_done.md`, GlobToLike("docs/100%_done.md"))
+}
@@ -0,0 +1,124 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+)
+
+// maxFocusQueryRunes は質問がない場合に検索クエリとして使うカーソル位置のチャンクの最大文字数
+const maxFocusQueryRunes = 4000
+
+// LocationParams はエディタのカーソル位置に関連するチャンクの取得パラメータを表す
+type LocationParams struct {
+	ProductID uuid.UUID
+	FilePath  string // ソースのルートからの相対パス
+	Line      int    // カーソルの行（1始まり）
+	Question  string // 質問（空の場合はカーソル位置のチャンクの内容で検索）
+	Limit     int    // 関連するチャンクの上限（0 以下なら 10）
+
+	// カーソル位置のチャンクから依存関係を辿る設定
+	Expansion DependencyExpansion
+}
+
+// LocationContext はカーソル位置に関連するチャンクを表す
+type LocationContext struct {
+	Focus        *SearchResult   // カーソル位置を含むチャンク（インデックスにない場合は nil）
+	Related      []*SearchResult // 質問（またはカーソル位置のチャンク）に意味的に近いチャンク（Focus と依存チャンクを除く）
+	Dependencies []*SearchResult // Focus と依存関係でつながるチャンク（Relation に辿った経路を設定）
+}
+
+// ContextAt はエディタのカーソル位置について、位置を含むチャンク・質問に関連するチャンク・依存関係でつながるチャンクを返す
+// IDE拡張がコード補完や質問のプロンプトに添える文脈を取得するためのもの
+func (s *SearchService) ContextAt(ctx context.Context, params LocationParams) (*LocationContext, error) {
+	if params.FilePath == "" {
+		return nil, fmt.Errorf("file path is required")
+	}
+	if params.Line <= 0 {
+		return nil, fmt.Errorf("line must be a positive number")
+	}
+
+	chunks, err := s.repo.GetChunksAtLine(ctx, params.ProductID, params.FilePath, params.Line)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chunks at line: %w", err)
+	}
+
+	result := &LocationContext{}
+	if len(chunks) > 0 {
+		result.Focus = chunks[0]
+	}
+
+	query := strings.TrimSpace(params.Question)
+	if query == "" && result.Focus != nil {
+		query = truncateRunes(result.Focus.Content, maxFocusQueryRunes)
+	}
+	if query == "" {
+		// 質問がなくカーソル位置もインデックスにない場合は、手がかりがないため空の結果を返す
+		return result, nil
+	}
+
+	if result.Focus != nil {
+		result.Dependencies, err = s.ExpandDependencies(ctx, []*SearchResult{result.Focus}, params.Expansion)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	limit := params.Limit
+	if limit <= 0 {
+		limit = 10
+	}
+	// カーソル位置のチャンクと重なるチャンクを除くため、除いた分だけ多めに取得する
+	related, err := s.Search(ctx, SearchParams{
+		ProductID: mo.Some(params.ProductID),
+		Query:     query,
+		Limit:     limit + len(chunks),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	exclude := make(map[uuid.UUID]bool, len(chunks)+len(result.Dependencies))
+	for _, chunk := range chunks {
+		exclude[chunk.ChunkID] = true
+	}
+	for _, dep := range result.Dependencies {
+		exclude[dep.ChunkID] = true
+	}
+	related = slices.DeleteFunc(related, func(r *SearchResult) bool {
+		return exclude[r.ChunkID] || (result.Focus != nil && overlapsFocus(r, result.Focus))
+	})
+	if len(related) > limit {
+		related = related[:limit]
+	}
+	result.Related = related
+
+	s.logger.Debug("location context resolved",
+		"path", params.FilePath,
+		"line", params.Line,
+		"focus", result.Focus != nil,
+		"related", len(result.Related),
+		"dependencies", len(result.Dependencies),
+	)
+
+	return result, nil
+}
+
+// overlapsFocus は検索結果がカーソル位置のチャンクと同じファイルで行範囲が重なるかを返す
+// エディタに表示中の範囲を関連するチャンクとして返しても役に立たないため除く
+func overlapsFocus(r, focus *SearchResult) bool {
+	return r.FileID == focus.FileID && r.StartLine <= focus.EndLine && focus.StartLine <= r.EndLine
+}
+
+// truncateRunes は文字列を最大 n 文字に切り詰める
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
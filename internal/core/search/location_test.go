@@ -0,0 +1,71 @@
+package search
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchService_ContextAt(t *testing.T) {
+	fileID := uuid.New()
+	function := &SearchResult{ChunkID: uuid.New(), FileID: fileID, FilePath: "payment/retry.go", StartLine: 10, EndLine: 42, Content: "func Retry() {}", Score: 1}
+	block := &SearchResult{ChunkID: uuid.New(), FileID: fileID, FilePath: "payment/retry.go", StartLine: 20, EndLine: 30, Content: "for i := 0; i < 3; i++ {}", Score: 1}
+
+	t.Run("カーソル位置のチャンクと依存チャンク・関連チャンクを返す", func(t *testing.T) {
+		callee := relatedChunk(block.ChunkID, DirectionOutgoing, "call", 10)
+		policy := &SearchResult{ChunkID: uuid.New(), FileID: uuid.New(), FilePath: "payment/policy.go", StartLine: 1, EndLine: 20, Score: 0.7}
+		repo := &stubSearchRepo{
+			chunksAt: []*SearchResult{block, function},
+			related:  map[uuid.UUID][]*RelatedChunk{block.ChunkID: {callee}},
+			results: []*SearchResult{
+				{ChunkID: block.ChunkID, FileID: fileID, FilePath: "payment/retry.go", StartLine: 20, EndLine: 30, Score: 0.95},
+				{ChunkID: function.ChunkID, FileID: fileID, FilePath: "payment/retry.go", StartLine: 10, EndLine: 42, Score: 0.9},
+				{ChunkID: uuid.New(), FileID: fileID, FilePath: "payment/retry.go", StartLine: 25, EndLine: 35, Score: 0.85},
+				{ChunkID: callee.ChunkID, FilePath: callee.FilePath, StartLine: 1, EndLine: 10, Score: 0.8},
+				policy,
+			},
+		}
+		embedder := &stubEmbedder{}
+		svc := NewSearchService(repo, embedder)
+
+		result, err := svc.ContextAt(context.Background(), LocationParams{
+			ProductID: uuid.New(),
+			FilePath:  "payment/retry.go",
+			Line:      25,
+			Limit:     5,
+			Expansion: DependencyExpansion{MaxHops: 1},
+		})
+		require.NoError(t, err)
+
+		require.NotNil(t, result.Focus)
+		assert.Equal(t, block.ChunkID, result.Focus.ChunkID, "最も内側のチャンクを選ぶ")
+		assert.True(t, embedder.called, "質問がない場合はカーソル位置のチャンクで検索する")
+
+		require.Len(t, result.Dependencies, 1)
+		assert.Equal(t, callee.ChunkID, result.Dependencies[0].ChunkID)
+
+		require.Len(t, result.Related, 1, "カーソル位置と重なるチャンク・依存チャンクは除く")
+		assert.Equal(t, policy.ChunkID, result.Related[0].ChunkID)
+		assert.Equal(t, 5+2, repo.lastLimit, "除く分だけ多めに取得する")
+	})
+
+	t.Run("インデックスにない位置で質問もない場合は空の結果", func(t *testing.T) {
+		embedder := &stubEmbedder{}
+		svc := NewSearchService(&stubSearchRepo{}, embedder)
+
+		result, err := svc.ContextAt(context.Background(), LocationParams{ProductID: uuid.New(), FilePath: "new.go", Line: 1})
+		require.NoError(t, err)
+		assert.Nil(t, result.Focus)
+		assert.Empty(t, result.Related)
+		assert.False(t, embedder.called)
+	})
+
+	t.Run("行は1以上", func(t *testing.T) {
+		svc := NewSearchService(&stubSearchRepo{}, &stubEmbedder{})
+		_, err := svc.ContextAt(context.Background(), LocationParams{ProductID: uuid.New(), FilePath: "main.go", Line: 0})
+		assert.Error(t, err)
+	})
+}
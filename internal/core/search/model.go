@@ -1,6 +1,8 @@
 package search
 
 import (
+	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
@@ -10,19 +12,90 @@ import (
 // SearchResult はベクトル検索の結果を表す
 type SearchResult struct {
 	ChunkID     uuid.UUID `json:"chunkID"`
+	FileID      uuid.UUID `json:"fileID"`
 	FilePath    string    `json:"filePath"`
 	StartLine   int       `json:"startLine"`
 	EndLine     int       `json:"endLine"`
+	Level       int       `json:"level,omitempty"`      // 1: ファイルサマリー, 2: 関数/クラス, 3: ロジック単位
+	Breadcrumb  *string   `json:"breadcrumb,omitempty"` // Markdownの見出しの階層（例: Architecture > Deployment > Rollback）
+	PageStart   *int      `json:"pageStart,omitempty"`  // PDF・Office文書の開始ページ（xlsx はシートの番号）
+	PageEnd     *int      `json:"pageEnd,omitempty"`    // PDF・Office文書の終了ページ
 	Content     string    `json:"content"`
 	Score       float64   `json:"score"`
 	PrevContent *string   `json:"prevContent,omitempty"`
 	NextContent *string   `json:"nextContent,omitempty"`
+
+	// 依存関係の展開で追加されたチャンクの場合のみ設定される
+	Relation *ChunkRelation `json:"relation,omitempty"`
+}
+
+// FormatPageRange はページ範囲を表示用に整形する（"3" または "3-4"）
+func FormatPageRange(start int, end *int) string {
+	if end == nil || *end <= start {
+		return strconv.Itoa(start)
+	}
+	return fmt.Sprintf("%d-%d", start, *end)
+}
+
+// 依存関係の方向
+const (
+	DirectionOutgoing = "outgoing" // 起点チャンクが依存する側（呼び出し先・参照する型など）
+	DirectionIncoming = "incoming" // 起点チャンクに依存する側（呼び出し元など）
+)
+
+// ChunkRelation は依存関係の展開で追加されたチャンクと起点チャンクの関係を表す
+type ChunkRelation struct {
+	ViaChunkID   uuid.UUID `json:"viaChunkID"`       // 起点となったチャンク
+	ViaFilePath  string    `json:"viaFilePath"`      // 起点チャンクのファイルパス
+	ViaStartLine int       `json:"viaStartLine"`     // 起点チャンクの開始行
+	ViaEndLine   int       `json:"viaEndLine"`       // 起点チャンクの終了行
+	Direction    string    `json:"direction"`        // DirectionOutgoing | DirectionIncoming
+	DepType      string    `json:"depType"`          // "call" | "import" | "type"
+	Symbol       *string   `json:"symbol,omitempty"` // 依存の対象シンボル
+	Hop          int       `json:"hop"`              // 検索結果からのホップ数（1始まり）
+}
+
+// RelatedChunk は依存関係で直接つながるチャンクを表す
+type RelatedChunk struct {
+	ViaChunkID uuid.UUID
+	Direction  string
+	DepType    string
+	Symbol     *string
+	ChunkID    uuid.UUID
+	FilePath   string
+	StartLine  int
+	EndLine    int
+	Content    string
+	TokenCount *int
 }
 
 // SearchFilter は検索時の任意フィルタを表す
 type SearchFilter struct {
 	PathPrefix  *string
 	ContentType *string
+	Domains     []string   // ファイルのドメイン（code / tests / ops / infra / architecture）で絞り込み（空なら全ドメイン）
+	Languages   []string   // ファイルの言語（go / python など files.language の表記）で絞り込み（空なら全言語）
+	SnapshotID  *uuid.UUID // 指定時は最新スナップショットの代わりにこのスナップショットを検索（過去リリース時点の検索用）
+
+	// ImportanceWeight は類似度に importance_score を加味する重み（0〜1、nil ならサービスの既定値）
+	// スコアは (1 - 重み) × 類似度 + 重み × 重要度 となり、重要度が未算出のチャンクは 0.5 として扱う
+	ImportanceWeight *float64
+
+	// RecencyWeight はチャンクの最終更新日時による鮮度を加味する重み（0〜1、nil ならプロダクトの設定）
+	// 更新から半減期（RecencyHalfLifeDays）が経過するごとに鮮度が半分になり、スコアは最大で重みの割合まで減衰する
+	RecencyWeight *float64
+	// RecencyHalfLifeDays は鮮度の半減期（日数、0 以下ならサービスの既定値）
+	RecencyHalfLifeDays int
+
+	// FileIDs を指定した場合はそのファイルのチャンクのみを検索する（2段階検索の2段目で使用）
+	FileIDs []uuid.UUID
+
+	// KeepOverlaps が true の場合、同一ファイル内で行範囲が重なるチャンク（関数とそのロジック単位など）を統合せずに返す
+	KeepOverlaps bool
+
+	// EmbeddingModel を指定した場合は追加のEmbeddingモデルで生成したチャンクの Embedding で検索する（空なら主モデル）
+	// 要約の検索と2段階検索の1段目は常に主モデルの Embedding を使用する
+	EmbeddingModel string
 }
 
 // ChunkContext はチャンクのコンテキスト情報を表す（階層検索用）
@@ -44,6 +117,33 @@ type ChunkContext struct {
 	Level int `json:"level"`
 }
 
+// ChunkHierarchy はプロンプトを階層的に組み立てるためのチャンクの所属情報を表す
+type ChunkHierarchy struct {
+	ChunkID     uuid.UUID
+	FileID      uuid.UUID
+	Level       int           // 1: ファイルサマリー, 2: 関数/クラス, 3: ロジック単位
+	Parent      *ChunkContext // ロジック単位のチャンクが属する関数/クラスのチャンク（ない場合は nil）
+	FileSummary *string       // 所属ファイルの要約（未生成の場合は nil）
+}
+
+// FileContext はファイル単位にまとめたプロンプト用のコンテキストを表す
+type FileContext struct {
+	FileID      uuid.UUID       `json:"fileID"`
+	FilePath    string          `json:"filePath"`
+	FileSummary *string         `json:"fileSummary,omitempty"`
+	Chunks      []*SearchResult `json:"chunks"`           // 行番号順、重複する行範囲は統合済み
+	Score       float64         `json:"score"`            // ファイル内のチャンクの最高スコア
+	Owners      []string        `json:"owners,omitempty"` // CODEOWNERS で定義された担当者（質問応答で設定される）
+}
+
+// FileSummaryResult はファイル要約の検索結果（2段階検索の候補ファイル）を表す
+type FileSummaryResult struct {
+	FileID   uuid.UUID `json:"fileID"`
+	FilePath string    `json:"filePath"`
+	Content  string    `json:"content"`
+	Score    float64   `json:"score"`
+}
+
 // SummarySearchResult は要約検索の結果を表す
 type SummarySearchResult struct {
 	SummaryID   uuid.UUID `json:"summaryID"`
@@ -56,8 +156,9 @@ type SummarySearchResult struct {
 
 // SummarySearchFilter は要約検索時のフィルタ
 type SummarySearchFilter struct {
-	SummaryTypes []string // フィルタする要約タイプ（空なら全て）
-	PathPrefix   *string  // パスプレフィックスでフィルタ
+	SummaryTypes []string   // フィルタする要約タイプ（空なら全て）
+	PathPrefix   *string    // パスプレフィックスでフィルタ
+	SnapshotID   *uuid.UUID // 指定時は最新スナップショットの代わりにこのスナップショットを検索
 }
 
 // HybridSearchResult はハイブリッド検索の結果
@@ -78,6 +179,7 @@ type HybridSearchParams struct {
 	SummaryLimit  int
 	ChunkFilter   *SearchFilter
 	SummaryFilter *SummarySearchFilter
+	Strategy      RetrievalStrategy // チャンク検索の方式（空ならサービスの既定値）
 }
 
 // SummarySearchParams は要約検索のパラメータ
@@ -27,6 +27,14 @@ type Repository interface {
 	// SearchSummariesByProduct はプロダクト横断で要約検索を実行する（HybridSearch用）
 	SearchSummariesByProduct(ctx context.Context, productID uuid.UUID, queryVector []float32, limit int, filters SummarySearchFilter) ([]*SummarySearchResult, error)
 
+	// SearchFileSummaries はファイル要約をベクトル検索して候補ファイルを取得する（2段階検索用）
+	// productID / sourceID / filters.SnapshotID のうち指定されたもので範囲を限定する
+	SearchFileSummaries(ctx context.Context, productID, sourceID mo.Option[uuid.UUID], queryVector []float32, limit int, filters SearchFilter) ([]*FileSummaryResult, error)
+
+	// RankProductsBySummaries は各プロダクトの最新スナップショットのアーキテクチャ要約・プロダクト全体の要約と質問の類似度の最大値で、プロダクトを順位付けする
+	// プロダクト全体の要約は model で生成した Embedding のみを対象とする
+	RankProductsBySummaries(ctx context.Context, queryVector []float32, model string, limit int) ([]*ProductCandidate, error)
+
 	// GetChunkContext は対象チャンクの前後コンテキストを取得する
 	GetChunkContext(ctx context.Context, chunkID uuid.UUID, beforeCount int, afterCount int) ([]*ChunkContext, error)
 
@@ -38,4 +46,16 @@ type Repository interface {
 
 	// GetChunkTree はルートチャンクから階層ツリーを取得する
 	GetChunkTree(ctx context.Context, rootID uuid.UUID, maxDepth int) ([]*ChunkContext, error)
+
+	// GetRelatedChunks は指定チャンク群と依存関係で直接つながるチャンクを取得する（depTypes が空なら全種類）
+	GetRelatedChunks(ctx context.Context, chunkIDs []uuid.UUID, depTypes []string) ([]*RelatedChunk, error)
+
+	// GetChunksAtLine はプロダクトの最新スナップショットでファイルの指定行を含むチャンクを内側（行範囲の狭いもの）から順に取得する
+	GetChunksAtLine(ctx context.Context, productID uuid.UUID, filePath string, line int) ([]*SearchResult, error)
+
+	// GrepChunks はプロダクトの最新スナップショット（query.SnapshotID 指定時はそのスナップショット）で、内容が正規表現に一致するチャンクをソース名・パス・開始行順に取得する
+	GrepChunks(ctx context.Context, productID uuid.UUID, query GrepQuery) ([]*GrepChunk, error)
+
+	// GetChunkHierarchies は指定チャンクの所属ファイル・親チャンク・ファイル要約を取得する
+	GetChunkHierarchies(ctx context.Context, chunkIDs []uuid.UUID) ([]*ChunkHierarchy, error)
 }
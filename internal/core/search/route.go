@@ -0,0 +1,40 @@
+package search
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// defaultProductCandidates はプロダクトの順位付けで返す候補の既定の上限
+const defaultProductCandidates = 5
+
+// ProductCandidate はプロダクトを指定しない質問の振り分け先の候補を表す
+type ProductCandidate struct {
+	ProductID   uuid.UUID `json:"productID"`
+	ProductName string    `json:"productName"`
+	Score       float64   `json:"score"` // 質問とプロダクトの要約の類似度の最大値
+}
+
+// RankProducts は質問と各プロダクトの要約（各ソースのアーキテクチャ要約・プロダクト全体の要約）の類似度で、プロダクトを関連度の高い順に返す
+// 要約のないプロダクト（アーキテクチャ要約を生成していないなど）は候補に含まれない
+func (s *SearchService) RankProducts(ctx context.Context, query string, limit int) ([]*ProductCandidate, error) {
+	if query == "" {
+		return nil, fmt.Errorf("query is required")
+	}
+	if limit <= 0 {
+		limit = defaultProductCandidates
+	}
+
+	queryVector, err := s.embedder.Embed(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed query: %w", err)
+	}
+
+	candidates, err := s.repo.RankProductsBySummaries(ctx, queryVector, s.primaryModel, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to rank products: %w", err)
+	}
+	return candidates, nil
+}
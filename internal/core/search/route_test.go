@@ -0,0 +1,30 @@
+package search
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchService_RankProducts(t *testing.T) {
+	repo := &stubSearchRepo{products: []*ProductCandidate{
+		{ProductID: uuid.New(), ProductName: "payments", Score: 0.72},
+		{ProductID: uuid.New(), ProductName: "ecommerce", Score: 0.41},
+	}}
+	embedder := &stubEmbedder{}
+	svc := NewSearchService(repo, embedder)
+
+	candidates, err := svc.RankProducts(context.Background(), "決済のリトライ方針は？", 0)
+	require.NoError(t, err)
+
+	require.Len(t, candidates, 2)
+	assert.Equal(t, "payments", candidates[0].ProductName)
+	assert.True(t, embedder.called)
+	assert.Equal(t, defaultProductCandidates, repo.lastLimit)
+
+	_, err = svc.RankProducts(context.Background(), "", 0)
+	assert.Error(t, err)
+}
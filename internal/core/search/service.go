@@ -2,6 +2,7 @@ package search
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 
@@ -15,15 +16,33 @@ type Embedder interface {
 	Embed(ctx context.Context, text string) ([]float32, error)
 }
 
+// ErrUnknownEmbeddingModel は検索に指定した Embedding モデルが設定されていないことを表す
+var ErrUnknownEmbeddingModel = errors.New("unknown embedding model")
+
 // SearchService は検索のビジネスロジックを提供する
 type SearchService struct {
-	repo     Repository
-	embedder Embedder
-	logger   *slog.Logger
+	repo             Repository
+	embedder         Embedder
+	primaryModel     string
+	modelEmbedders   map[string]Embedder
+	logger           *slog.Logger
+	importanceWeight float64
+	recencyHalfLife  int
+	strategy         RetrievalStrategy
+	candidateFiles   int
 }
 
+// DefaultRecencyHalfLifeDays は鮮度の重み付けで使用する既定の半減期（日数）
+const DefaultRecencyHalfLifeDays = 90
+
 type searchServiceOptions struct {
-	logger *slog.Logger
+	logger           *slog.Logger
+	primaryModel     string
+	modelEmbedders   map[string]Embedder
+	importanceWeight float64
+	recencyHalfLife  int
+	strategy         RetrievalStrategy
+	candidateFiles   int
 }
 
 // SearchServiceOption は SearchService のオプション設定
@@ -36,27 +55,88 @@ func WithSearchLogger(logger *slog.Logger) SearchServiceOption {
 	}
 }
 
+// WithSearchImportanceWeight はチャンク検索で類似度に importance_score を加味する既定の重みを設定する
+// 0 の場合は類似度のみで並べる（SearchFilter.ImportanceWeight の指定が優先される）
+func WithSearchImportanceWeight(weight float64) SearchServiceOption {
+	return func(opts *searchServiceOptions) {
+		opts.importanceWeight = weight
+	}
+}
+
+// WithSearchRecencyHalfLife は鮮度の重み付けで使用する半減期（日数）を設定する
+func WithSearchRecencyHalfLife(days int) SearchServiceOption {
+	return func(opts *searchServiceOptions) {
+		opts.recencyHalfLife = days
+	}
+}
+
+// WithSearchStrategy は既定のチャンク検索の方式を設定する
+func WithSearchStrategy(strategy RetrievalStrategy) SearchServiceOption {
+	return func(opts *searchServiceOptions) {
+		opts.strategy = strategy
+	}
+}
+
+// WithSearchCandidateFiles は2段階検索の1段目で選ぶ候補ファイル数を設定する
+func WithSearchCandidateFiles(n int) SearchServiceOption {
+	return func(opts *searchServiceOptions) {
+		opts.candidateFiles = n
+	}
+}
+
+// WithSearchPrimaryModel は主モデル（embeddings に保存する Embedding のモデル）の名前を設定する
+// SearchFilter.EmbeddingModel に主モデルを指定した場合は、指定しない場合と同じく主モデルで検索する
+func WithSearchPrimaryModel(model string) SearchServiceOption {
+	return func(opts *searchServiceOptions) {
+		opts.primaryModel = model
+	}
+}
+
+// WithSearchModelEmbedder は追加のEmbeddingモデルでクエリを変換する Embedder を設定する
+// SearchFilter.EmbeddingModel に model を指定した検索で使用する
+func WithSearchModelEmbedder(model string, embedder Embedder) SearchServiceOption {
+	return func(opts *searchServiceOptions) {
+		if opts.modelEmbedders == nil {
+			opts.modelEmbedders = make(map[string]Embedder)
+		}
+		opts.modelEmbedders[model] = embedder
+	}
+}
+
 // NewSearchService は新しいSearchServiceを作成する
 func NewSearchService(repo Repository, embedder Embedder, opts ...SearchServiceOption) *SearchService {
-	options := searchServiceOptions{logger: slog.Default()}
+	options := searchServiceOptions{
+		logger:          slog.Default(),
+		recencyHalfLife: DefaultRecencyHalfLifeDays,
+		strategy:        RetrievalFlat,
+		candidateFiles:  DefaultCandidateFiles,
+	}
 	for _, opt := range opts {
 		opt(&options)
 	}
 
 	return &SearchService{
-		repo:     repo,
-		embedder: embedder,
-		logger:   options.logger,
+		repo:             repo,
+		embedder:         embedder,
+		primaryModel:     options.primaryModel,
+		modelEmbedders:   options.modelEmbedders,
+		logger:           options.logger,
+		importanceWeight: options.importanceWeight,
+		recencyHalfLife:  options.recencyHalfLife,
+		strategy:         options.strategy,
+		candidateFiles:   options.candidateFiles,
 	}
 }
 
 // SearchParams は検索パラメータを表す
 type SearchParams struct {
-	ProductID mo.Option[uuid.UUID]
-	SourceID  mo.Option[uuid.UUID]
-	Query     string
-	Limit     int
-	Filter    *SearchFilter
+	ProductID  mo.Option[uuid.UUID]
+	SourceID   mo.Option[uuid.UUID]
+	SnapshotID mo.Option[uuid.UUID] // ProductID/SourceID と併用時はその範囲内のスナップショットに限定、単独指定時はスナップショットを直接検索
+	Query      string
+	Limit      int
+	Filter     *SearchFilter
+	Strategy   RetrievalStrategy // チャンク検索の方式（空ならサービスの既定値）
 }
 
 // Search はクエリに基づいてベクトル検索を実行する
@@ -65,14 +145,12 @@ func (s *SearchService) Search(ctx context.Context, params SearchParams) ([]*Sea
 	if params.Query == "" {
 		return nil, fmt.Errorf("query is required")
 	}
-	if params.ProductID.IsAbsent() && params.SourceID.IsAbsent() {
-		return nil, fmt.Errorf("either productID or sourceID is required")
+	if params.ProductID.IsAbsent() && params.SourceID.IsAbsent() && params.SnapshotID.IsAbsent() {
+		return nil, fmt.Errorf("one of productID, sourceID or snapshotID is required")
 	}
-
-	// クエリをEmbeddingに変換
-	queryVector, err := s.embedder.Embed(ctx, params.Query)
+	strategy, err := s.resolveStrategy(params.Strategy)
 	if err != nil {
-		return nil, fmt.Errorf("failed to embed query: %w", err)
+		return nil, err
 	}
 
 	// デフォルトのLimit設定
@@ -86,23 +164,113 @@ func (s *SearchService) Search(ctx context.Context, params SearchParams) ([]*Sea
 	if params.Filter != nil {
 		filter = *params.Filter
 	}
+	if err := s.applyRankingWeights(&filter); err != nil {
+		return nil, err
+	}
+
+	// クエリをEmbeddingに変換
+	queryVector, summaryVector, err := s.embedQuery(ctx, params.Query, &filter, strategy == RetrievalTwoStage)
+	if err != nil {
+		return nil, err
+	}
+
+	// スナップショット指定時は最新スナップショットの代わりに使用する
+	if snapshotID, ok := params.SnapshotID.Get(); ok {
+		filter.SnapshotID = &snapshotID
+	}
+
+	// 2段階検索の場合はファイル要約で候補ファイルを絞り込む
+	if strategy == RetrievalTwoStage {
+		filter, err = s.narrowToCandidateFiles(ctx, params.ProductID, params.SourceID, summaryVector, filter)
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	// ProductID または SourceID に基づいて検索
+	// ProductID、SourceID、SnapshotID の順に検索範囲を決定
 	var results []*SearchResult
 	switch {
 	case params.ProductID.IsPresent():
 		results, err = s.repo.SearchByProduct(ctx, params.ProductID.MustGet(), queryVector, limit, filter)
 	case params.SourceID.IsPresent():
 		results, err = s.repo.SearchBySource(ctx, params.SourceID.MustGet(), queryVector, limit, filter)
+	default:
+		results, err = s.repo.SearchChunksBySnapshot(ctx, params.SnapshotID.MustGet(), queryVector, limit, filter)
 	}
 
 	if err != nil {
 		return nil, fmt.Errorf("search failed: %w", err)
 	}
 
+	if !filter.KeepOverlaps {
+		results = CollapseOverlaps(results)
+	}
+
 	return results, nil
 }
 
+// SupportsEmbeddingModel はチャンク検索に model を指定できるか（主モデルまたは追加のモデルか）を返す（空は主モデル）
+func (s *SearchService) SupportsEmbeddingModel(model string) bool {
+	if model == "" || model == s.primaryModel {
+		return true
+	}
+	_, ok := s.modelEmbedders[model]
+	return ok
+}
+
+// embedQuery はクエリをチャンク検索に使用する Embedding に変換する
+// filter.EmbeddingModel に追加のモデルを指定した場合はそのモデルで変換し、withSummary の場合は要約の検索に使用する主モデルの Embedding も返す
+// 主モデルを指定した場合は filter.EmbeddingModel を空にし、主モデルの Embedding で検索させる
+func (s *SearchService) embedQuery(ctx context.Context, query string, filter *SearchFilter, withSummary bool) (chunkVector, summaryVector []float32, err error) {
+	if filter.EmbeddingModel == s.primaryModel {
+		filter.EmbeddingModel = ""
+	}
+	if filter.EmbeddingModel == "" {
+		vector, err := s.embedder.Embed(ctx, query)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to embed query: %w", err)
+		}
+		return vector, vector, nil
+	}
+
+	embedder, ok := s.modelEmbedders[filter.EmbeddingModel]
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %s", ErrUnknownEmbeddingModel, filter.EmbeddingModel)
+	}
+	chunkVector, err = embedder.Embed(ctx, query)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to embed query with %s: %w", filter.EmbeddingModel, err)
+	}
+	if withSummary {
+		summaryVector, err = s.embedder.Embed(ctx, query)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to embed query: %w", err)
+		}
+	}
+	return chunkVector, summaryVector, nil
+}
+
+// applyRankingWeights はフィルタに重要度の重み・鮮度の半減期が指定されていなければ既定値を設定し、範囲を検証する
+// 鮮度の重みは未指定の場合プロダクトの設定を使うため、ここでは補完しない
+func (s *SearchService) applyRankingWeights(filter *SearchFilter) error {
+	if filter.ImportanceWeight == nil {
+		weight := s.importanceWeight
+		filter.ImportanceWeight = &weight
+	}
+	if w := *filter.ImportanceWeight; w < 0 || w > 1 {
+		return fmt.Errorf("importance weight must be between 0 and 1: %v", w)
+	}
+	if filter.RecencyWeight != nil {
+		if w := *filter.RecencyWeight; w < 0 || w > 1 {
+			return fmt.Errorf("recency weight must be between 0 and 1: %v", w)
+		}
+	}
+	if filter.RecencyHalfLifeDays <= 0 {
+		filter.RecencyHalfLifeDays = s.recencyHalfLife
+	}
+	return nil
+}
+
 // GetChunkContext は指定されたチャンクの前後コンテキストを取得する
 func (s *SearchService) GetChunkContext(ctx context.Context, chunkID uuid.UUID, beforeCount, afterCount int) ([]*ChunkContext, error) {
 	if chunkID == uuid.Nil {
@@ -181,11 +349,9 @@ func (s *SearchService) HybridSearch(ctx context.Context, params HybridSearchPar
 	if params.ProductID.IsAbsent() && params.SnapshotID == uuid.Nil {
 		return nil, fmt.Errorf("either productID or snapshotID is required")
 	}
-
-	// クエリをEmbeddingに変換
-	queryVector, err := s.embedder.Embed(ctx, params.Query)
+	strategy, err := s.resolveStrategy(params.Strategy)
 	if err != nil {
-		return nil, fmt.Errorf("failed to embed query: %w", err)
+		return nil, err
 	}
 
 	// デフォルトのLimit設定
@@ -203,6 +369,27 @@ func (s *SearchService) HybridSearch(ctx context.Context, params HybridSearchPar
 	if params.ChunkFilter != nil {
 		chunkFilter = *params.ChunkFilter
 	}
+	if err := s.applyRankingWeights(&chunkFilter); err != nil {
+		return nil, err
+	}
+
+	// クエリをEmbeddingに変換（要約の検索には常に主モデルの Embedding を使用する）
+	queryVector, summaryVector, err := s.embedQuery(ctx, params.Query, &chunkFilter, true)
+	if err != nil {
+		return nil, err
+	}
+	if strategy == RetrievalTwoStage {
+		// スナップショット検索の場合は1段目もそのスナップショットに限定する
+		scope := chunkFilter
+		if params.ProductID.IsAbsent() {
+			scope.SnapshotID = &params.SnapshotID
+		}
+		narrowed, err := s.narrowToCandidateFiles(ctx, params.ProductID, mo.None[uuid.UUID](), summaryVector, scope)
+		if err != nil {
+			return nil, err
+		}
+		chunkFilter.FileIDs = narrowed.FileIDs
+	}
 	summaryFilter := SummarySearchFilter{}
 	if params.SummaryFilter != nil {
 		summaryFilter = *params.SummaryFilter
@@ -229,7 +416,7 @@ func (s *SearchService) HybridSearch(ctx context.Context, params HybridSearchPar
 		}()
 
 		go func() {
-			summaries, err := s.repo.SearchSummariesByProduct(ctx, params.ProductID.MustGet(), queryVector, summaryLimit, summaryFilter)
+			summaries, err := s.repo.SearchSummariesByProduct(ctx, params.ProductID.MustGet(), summaryVector, summaryLimit, summaryFilter)
 			summaryCh <- summaryResult{summaries: summaries, err: err}
 		}()
 	} else {
@@ -239,7 +426,7 @@ func (s *SearchService) HybridSearch(ctx context.Context, params HybridSearchPar
 		}()
 
 		go func() {
-			summaries, err := s.repo.SearchSummariesBySnapshot(ctx, params.SnapshotID, queryVector, summaryLimit, summaryFilter)
+			summaries, err := s.repo.SearchSummariesBySnapshot(ctx, params.SnapshotID, summaryVector, summaryLimit, summaryFilter)
 			summaryCh <- summaryResult{summaries: summaries, err: err}
 		}()
 	}
@@ -255,8 +442,13 @@ func (s *SearchService) HybridSearch(ctx context.Context, params HybridSearchPar
 		return nil, fmt.Errorf("summary search failed: %w", summaryRes.err)
 	}
 
+	chunks := chunkRes.chunks
+	if !chunkFilter.KeepOverlaps {
+		chunks = CollapseOverlaps(chunks)
+	}
+
 	return &HybridSearchResult{
-		Chunks:    chunkRes.chunks,
+		Chunks:    chunks,
 		Summaries: summaryRes.summaries,
 	}, nil
 }
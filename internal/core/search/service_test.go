@@ -4,6 +4,7 @@ import (
 	"context"
 	"io"
 	"log/slog"
+	"slices"
 	"testing"
 
 	"github.com/google/uuid"
@@ -20,12 +21,21 @@ func (e *stubEmbedder) Embed(ctx context.Context, text string) ([]float32, error
 }
 
 type stubSearchRepo struct {
-	results   []*SearchResult
-	lastLimit int
+	results        []*SearchResult
+	lastLimit      int
+	lastFilter     SearchFilter
+	lastSnapshotID uuid.UUID
+	related        map[uuid.UUID][]*RelatedChunk // 起点チャンクIDごとの依存チャンク
+	fileSummaries  []*FileSummaryResult
+	chunksAt       []*SearchResult // GetChunksAtLine で返すチャンク（内側から順）
+	grepChunks     []*GrepChunk
+	lastGrep       GrepQuery
+	products       []*ProductCandidate
 }
 
 func (r *stubSearchRepo) SearchByProduct(ctx context.Context, productID uuid.UUID, queryVector []float32, limit int, filters SearchFilter) ([]*SearchResult, error) {
 	r.lastLimit = limit
+	r.lastFilter = filters
 	return r.results, nil
 }
 
@@ -36,6 +46,7 @@ func (r *stubSearchRepo) SearchBySource(ctx context.Context, sourceID uuid.UUID,
 
 func (r *stubSearchRepo) SearchChunksBySnapshot(ctx context.Context, snapshotID uuid.UUID, queryVector []float32, limit int, filters SearchFilter) ([]*SearchResult, error) {
 	r.lastLimit = limit
+	r.lastSnapshotID = snapshotID
 	return r.results, nil
 }
 
@@ -44,6 +55,13 @@ func (r *stubSearchRepo) SearchChunksByProduct(ctx context.Context, productID uu
 	return r.results, nil
 }
 
+func (r *stubSearchRepo) SearchFileSummaries(ctx context.Context, productID, sourceID mo.Option[uuid.UUID], queryVector []float32, limit int, filters SearchFilter) ([]*FileSummaryResult, error) {
+	if len(r.fileSummaries) > limit {
+		return r.fileSummaries[:limit], nil
+	}
+	return r.fileSummaries, nil
+}
+
 func (r *stubSearchRepo) SearchSummariesBySnapshot(ctx context.Context, snapshotID uuid.UUID, queryVector []float32, limit int, filters SummarySearchFilter) ([]*SummarySearchResult, error) {
 	return nil, nil
 }
@@ -68,6 +86,45 @@ func (r *stubSearchRepo) GetChunkTree(ctx context.Context, rootID uuid.UUID, max
 	return nil, nil
 }
 
+func (r *stubSearchRepo) GetRelatedChunks(ctx context.Context, chunkIDs []uuid.UUID, depTypes []string) ([]*RelatedChunk, error) {
+	var results []*RelatedChunk
+	for _, id := range chunkIDs {
+		for _, rel := range r.related[id] {
+			if len(depTypes) == 0 || slices.Contains(depTypes, rel.DepType) {
+				results = append(results, rel)
+			}
+		}
+	}
+	return results, nil
+}
+
+func (r *stubSearchRepo) GrepChunks(ctx context.Context, productID uuid.UUID, query GrepQuery) ([]*GrepChunk, error) {
+	r.lastGrep = query
+	return r.grepChunks, nil
+}
+
+func (r *stubSearchRepo) RankProductsBySummaries(ctx context.Context, queryVector []float32, model string, limit int) ([]*ProductCandidate, error) {
+	r.lastLimit = limit
+	if len(r.products) > limit {
+		return r.products[:limit], nil
+	}
+	return r.products, nil
+}
+
+func (r *stubSearchRepo) GetChunksAtLine(ctx context.Context, productID uuid.UUID, filePath string, line int) ([]*SearchResult, error) {
+	var results []*SearchResult
+	for _, chunk := range r.chunksAt {
+		if chunk.FilePath == filePath && chunk.StartLine <= line && line <= chunk.EndLine {
+			results = append(results, chunk)
+		}
+	}
+	return results, nil
+}
+
+func (r *stubSearchRepo) GetChunkHierarchies(ctx context.Context, chunkIDs []uuid.UUID) ([]*ChunkHierarchy, error) {
+	return nil, nil
+}
+
 func TestSearchService_SearchUsesDefaultLimitAndEmbedder(t *testing.T) {
 	repo := &stubSearchRepo{
 		results: []*SearchResult{{
@@ -96,3 +153,250 @@ func TestSearchService_SearchUsesDefaultLimitAndEmbedder(t *testing.T) {
 	assert.Equal(t, 10, repo.lastLimit) // default value applied
 	assert.True(t, embedder.called)
 }
+
+func TestSearchService_SearchScopesToSnapshot(t *testing.T) {
+	t.Run("プロダクト指定時はフィルタでスナップショットを限定", func(t *testing.T) {
+		repo := &stubSearchRepo{}
+		svc := NewSearchService(repo, &stubEmbedder{})
+
+		snapshotID := uuid.New()
+		_, err := svc.Search(context.Background(), SearchParams{
+			ProductID:  mo.Some(uuid.New()),
+			SnapshotID: mo.Some(snapshotID),
+			Query:      "hello",
+		})
+		require.NoError(t, err)
+		require.NotNil(t, repo.lastFilter.SnapshotID)
+		assert.Equal(t, snapshotID, *repo.lastFilter.SnapshotID)
+	})
+
+	t.Run("スナップショット単独指定時は直接検索", func(t *testing.T) {
+		repo := &stubSearchRepo{}
+		svc := NewSearchService(repo, &stubEmbedder{})
+
+		snapshotID := uuid.New()
+		_, err := svc.Search(context.Background(), SearchParams{
+			SnapshotID: mo.Some(snapshotID),
+			Query:      "hello",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, snapshotID, repo.lastSnapshotID)
+	})
+}
+
+func TestSearchService_SearchImportanceWeight(t *testing.T) {
+	t.Run("未指定時はサービスの既定値を使用", func(t *testing.T) {
+		repo := &stubSearchRepo{}
+		svc := NewSearchService(repo, &stubEmbedder{}, WithSearchImportanceWeight(0.3))
+
+		_, err := svc.Search(context.Background(), SearchParams{
+			ProductID: mo.Some(uuid.New()),
+			Query:     "hello",
+		})
+		require.NoError(t, err)
+		require.NotNil(t, repo.lastFilter.ImportanceWeight)
+		assert.Equal(t, 0.3, *repo.lastFilter.ImportanceWeight)
+	})
+
+	t.Run("フィルタの指定が既定値より優先される", func(t *testing.T) {
+		repo := &stubSearchRepo{}
+		svc := NewSearchService(repo, &stubEmbedder{}, WithSearchImportanceWeight(0.3))
+
+		weight := 0.0
+		_, err := svc.Search(context.Background(), SearchParams{
+			ProductID: mo.Some(uuid.New()),
+			Query:     "hello",
+			Filter:    &SearchFilter{ImportanceWeight: &weight},
+		})
+		require.NoError(t, err)
+		require.NotNil(t, repo.lastFilter.ImportanceWeight)
+		assert.Equal(t, 0.0, *repo.lastFilter.ImportanceWeight)
+	})
+
+	t.Run("範囲外の重みはエラー", func(t *testing.T) {
+		svc := NewSearchService(&stubSearchRepo{}, &stubEmbedder{})
+
+		weight := 1.5
+		_, err := svc.Search(context.Background(), SearchParams{
+			ProductID: mo.Some(uuid.New()),
+			Query:     "hello",
+			Filter:    &SearchFilter{ImportanceWeight: &weight},
+		})
+		require.Error(t, err)
+	})
+}
+
+func TestSearchService_SearchRecencyWeight(t *testing.T) {
+	t.Run("未指定時はプロダクトの設定に委ね、半減期は既定値", func(t *testing.T) {
+		repo := &stubSearchRepo{}
+		svc := NewSearchService(repo, &stubEmbedder{})
+
+		_, err := svc.Search(context.Background(), SearchParams{
+			ProductID: mo.Some(uuid.New()),
+			Query:     "hello",
+		})
+		require.NoError(t, err)
+		assert.Nil(t, repo.lastFilter.RecencyWeight)
+		assert.Equal(t, DefaultRecencyHalfLifeDays, repo.lastFilter.RecencyHalfLifeDays)
+	})
+
+	t.Run("サービスの半減期を使用", func(t *testing.T) {
+		repo := &stubSearchRepo{}
+		svc := NewSearchService(repo, &stubEmbedder{}, WithSearchRecencyHalfLife(14))
+
+		weight := 0.4
+		_, err := svc.Search(context.Background(), SearchParams{
+			ProductID: mo.Some(uuid.New()),
+			Query:     "hello",
+			Filter:    &SearchFilter{RecencyWeight: &weight},
+		})
+		require.NoError(t, err)
+		require.NotNil(t, repo.lastFilter.RecencyWeight)
+		assert.Equal(t, 0.4, *repo.lastFilter.RecencyWeight)
+		assert.Equal(t, 14, repo.lastFilter.RecencyHalfLifeDays)
+	})
+
+	t.Run("範囲外の重みはエラー", func(t *testing.T) {
+		svc := NewSearchService(&stubSearchRepo{}, &stubEmbedder{})
+
+		weight := -0.1
+		_, err := svc.Search(context.Background(), SearchParams{
+			ProductID: mo.Some(uuid.New()),
+			Query:     "hello",
+			Filter:    &SearchFilter{RecencyWeight: &weight},
+		})
+		require.Error(t, err)
+	})
+}
+
+func TestSearchService_SearchTwoStage(t *testing.T) {
+	t.Run("ファイル要約で選んだ候補ファイルにチャンク検索を限定", func(t *testing.T) {
+		fileA, fileB, fileC := uuid.New(), uuid.New(), uuid.New()
+		repo := &stubSearchRepo{fileSummaries: []*FileSummaryResult{
+			{FileID: fileA, FilePath: "a.go", Score: 0.9},
+			{FileID: fileB, FilePath: "b.go", Score: 0.8},
+			{FileID: fileC, FilePath: "c.go", Score: 0.7},
+		}}
+		svc := NewSearchService(repo, &stubEmbedder{}, WithSearchCandidateFiles(2))
+
+		_, err := svc.Search(context.Background(), SearchParams{
+			ProductID: mo.Some(uuid.New()),
+			Query:     "hello",
+			Strategy:  RetrievalTwoStage,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{fileA, fileB}, repo.lastFilter.FileIDs)
+	})
+
+	t.Run("サービスの既定の方式を使用", func(t *testing.T) {
+		fileA := uuid.New()
+		repo := &stubSearchRepo{fileSummaries: []*FileSummaryResult{{FileID: fileA, FilePath: "a.go"}}}
+		svc := NewSearchService(repo, &stubEmbedder{}, WithSearchStrategy(RetrievalTwoStage))
+
+		_, err := svc.Search(context.Background(), SearchParams{
+			ProductID: mo.Some(uuid.New()),
+			Query:     "hello",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{fileA}, repo.lastFilter.FileIDs)
+	})
+
+	t.Run("ファイル要約がなければ全ファイルを検索", func(t *testing.T) {
+		repo := &stubSearchRepo{}
+		svc := NewSearchService(repo, &stubEmbedder{})
+
+		_, err := svc.Search(context.Background(), SearchParams{
+			ProductID: mo.Some(uuid.New()),
+			Query:     "hello",
+			Strategy:  RetrievalTwoStage,
+		})
+		require.NoError(t, err)
+		assert.Empty(t, repo.lastFilter.FileIDs)
+	})
+
+	t.Run("不明な方式はエラー", func(t *testing.T) {
+		svc := NewSearchService(&stubSearchRepo{}, &stubEmbedder{})
+
+		_, err := svc.Search(context.Background(), SearchParams{
+			ProductID: mo.Some(uuid.New()),
+			Query:     "hello",
+			Strategy:  RetrievalStrategy("unknown"),
+		})
+		require.Error(t, err)
+	})
+}
+
+func TestSearchService_SearchEmbeddingModel(t *testing.T) {
+	newService := func(repo *stubSearchRepo, primary, small *stubEmbedder, opts ...SearchServiceOption) *SearchService {
+		opts = append(opts,
+			WithSearchPrimaryModel("text-embedding-3-large"),
+			WithSearchModelEmbedder("text-embedding-3-small", small),
+		)
+		return NewSearchService(repo, primary, opts...)
+	}
+
+	t.Run("追加のモデルを指定するとそのモデルでクエリを変換する", func(t *testing.T) {
+		repo := &stubSearchRepo{}
+		primary, small := &stubEmbedder{}, &stubEmbedder{}
+		svc := newService(repo, primary, small)
+
+		_, err := svc.Search(context.Background(), SearchParams{
+			ProductID: mo.Some(uuid.New()),
+			Query:     "hello",
+			Filter:    &SearchFilter{EmbeddingModel: "text-embedding-3-small"},
+		})
+		require.NoError(t, err)
+		assert.True(t, small.called)
+		assert.False(t, primary.called, "要約を検索しない場合は主モデルで変換しない")
+		assert.Equal(t, "text-embedding-3-small", repo.lastFilter.EmbeddingModel)
+	})
+
+	t.Run("2段階検索の1段目は主モデルで検索する", func(t *testing.T) {
+		repo := &stubSearchRepo{fileSummaries: []*FileSummaryResult{{FileID: uuid.New(), FilePath: "a.go"}}}
+		primary, small := &stubEmbedder{}, &stubEmbedder{}
+		svc := newService(repo, primary, small)
+
+		_, err := svc.Search(context.Background(), SearchParams{
+			ProductID: mo.Some(uuid.New()),
+			Query:     "hello",
+			Filter:    &SearchFilter{EmbeddingModel: "text-embedding-3-small"},
+			Strategy:  RetrievalTwoStage,
+		})
+		require.NoError(t, err)
+		assert.True(t, small.called)
+		assert.True(t, primary.called)
+		assert.Len(t, repo.lastFilter.FileIDs, 1)
+	})
+
+	t.Run("主モデルを指定した場合は指定しない場合と同じ", func(t *testing.T) {
+		repo := &stubSearchRepo{}
+		primary, small := &stubEmbedder{}, &stubEmbedder{}
+		svc := newService(repo, primary, small)
+
+		_, err := svc.Search(context.Background(), SearchParams{
+			ProductID: mo.Some(uuid.New()),
+			Query:     "hello",
+			Filter:    &SearchFilter{EmbeddingModel: "text-embedding-3-large"},
+		})
+		require.NoError(t, err)
+		assert.True(t, primary.called)
+		assert.False(t, small.called)
+		assert.Empty(t, repo.lastFilter.EmbeddingModel)
+	})
+
+	t.Run("設定されていないモデルはエラー", func(t *testing.T) {
+		primary, small := &stubEmbedder{}, &stubEmbedder{}
+		svc := newService(&stubSearchRepo{}, primary, small)
+
+		assert.False(t, svc.SupportsEmbeddingModel("nomic-embed-text"))
+		assert.True(t, svc.SupportsEmbeddingModel(""))
+
+		_, err := svc.Search(context.Background(), SearchParams{
+			ProductID: mo.Some(uuid.New()),
+			Query:     "hello",
+			Filter:    &SearchFilter{EmbeddingModel: "nomic-embed-text"},
+		})
+		require.ErrorIs(t, err, ErrUnknownEmbeddingModel)
+		assert.False(t, primary.called)
+	})
+}
@@ -0,0 +1,150 @@
+package search
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// AssembleContext は検索結果のチャンクをチャンク階層に沿ってファイル単位に組み立てる
+// ロジック単位（レベル3）のチャンクは所属する関数/クラスのチャンクに置き換え、
+// ファイル要約を添えたうえで重複する行範囲を統合する
+func (s *SearchService) AssembleContext(ctx context.Context, chunks []*SearchResult) ([]*FileContext, error) {
+	if len(chunks) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(chunks))
+	for _, chunk := range chunks {
+		ids = append(ids, chunk.ChunkID)
+	}
+
+	hierarchies, err := s.repo.GetChunkHierarchies(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chunk hierarchies: %w", err)
+	}
+
+	return StitchChunks(chunks, hierarchies), nil
+}
+
+// StitchChunks はチャンク階層の情報を使って検索結果をファイル単位にまとめる
+// hierarchies が nil の場合はファイルパス単位にまとめて行範囲の統合のみを行う
+// ファイルはスコアの高い順、ファイル内のチャンクは行番号順に並べる
+func StitchChunks(chunks []*SearchResult, hierarchies []*ChunkHierarchy) []*FileContext {
+	byChunk := make(map[uuid.UUID]*ChunkHierarchy, len(hierarchies))
+	for _, h := range hierarchies {
+		byChunk[h.ChunkID] = h
+	}
+
+	files := make(map[string]*FileContext)
+	var order []*FileContext
+	for _, chunk := range chunks {
+		h := byChunk[chunk.ChunkID]
+
+		// 同一パスのファイルが複数ソースに存在しうるため、判明していればファイルIDでまとめる
+		key := "path:" + chunk.FilePath
+		if h != nil {
+			key = h.FileID.String()
+		}
+		file, ok := files[key]
+		if !ok {
+			file = &FileContext{FilePath: chunk.FilePath, Score: chunk.Score}
+			if h != nil {
+				file.FileID = h.FileID
+			}
+			files[key] = file
+			order = append(order, file)
+		}
+		if h != nil && file.FileSummary == nil {
+			file.FileSummary = h.FileSummary
+		}
+		file.Score = max(file.Score, chunk.Score)
+
+		// ロジック単位のチャンクは関数全体を示した方が文脈を理解しやすいため親チャンクに置き換える
+		if h != nil && h.Parent != nil {
+			parent := *chunk
+			parent.ChunkID = h.Parent.ID
+			parent.StartLine = h.Parent.StartLine
+			parent.EndLine = h.Parent.EndLine
+			parent.Level = h.Parent.Level
+			parent.Content = h.Parent.Content
+			parent.PrevContent = nil
+			parent.NextContent = nil
+			chunk = &parent
+		}
+		file.Chunks = append(file.Chunks, chunk)
+	}
+
+	for _, file := range order {
+		file.Chunks = mergeLineRanges(file.Chunks)
+	}
+	slices.SortStableFunc(order, func(a, b *FileContext) int {
+		return cmp.Compare(b.Score, a.Score)
+	})
+	return order
+}
+
+// mergeLineRanges は同一ファイル内のチャンクを行番号順に並べ、重複する行範囲を統合する
+// 他のチャンクに包含されるチャンクは取り除き、部分的に重なるチャンクは1つの断片に連結する
+func mergeLineRanges(chunks []*SearchResult) []*SearchResult {
+	sorted := slices.Clone(chunks)
+	slices.SortStableFunc(sorted, func(a, b *SearchResult) int {
+		if c := cmp.Compare(a.StartLine, b.StartLine); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.EndLine, a.EndLine)
+	})
+
+	merged := make([]*SearchResult, 0, len(sorted))
+	for _, chunk := range sorted {
+		if len(merged) == 0 {
+			merged = append(merged, chunk)
+			continue
+		}
+		last := merged[len(merged)-1]
+		switch {
+		case chunk.StartLine > last.EndLine:
+			merged = append(merged, chunk)
+		case chunk.EndLine <= last.EndLine:
+			// 包含されるチャンク（同一チャンクの重複を含む）はスコアのみ引き継ぐ
+			if chunk.Score > last.Score {
+				merged[len(merged)-1] = withScore(last, chunk.Score)
+			}
+		default:
+			if joined, ok := joinOverlapping(last, chunk); ok {
+				merged[len(merged)-1] = joined
+			} else {
+				merged = append(merged, chunk)
+			}
+		}
+	}
+	return merged
+}
+
+// joinOverlapping は部分的に重なる2つのチャンクを連結する
+// 内容の行数が行範囲と一致しない場合は安全に連結できないため false を返す
+func joinOverlapping(first, second *SearchResult) (*SearchResult, bool) {
+	firstLines := strings.Split(first.Content, "\n")
+	secondLines := strings.Split(second.Content, "\n")
+	if len(firstLines) != first.EndLine-first.StartLine+1 || len(secondLines) != second.EndLine-second.StartLine+1 {
+		return nil, false
+	}
+
+	joined := *first
+	overlap := first.EndLine - second.StartLine + 1
+	joined.Content = strings.Join(append(firstLines, secondLines[overlap:]...), "\n")
+	joined.EndLine = second.EndLine
+	joined.Score = max(first.Score, second.Score)
+	joined.NextContent = second.NextContent
+	return &joined, true
+}
+
+func withScore(chunk *SearchResult, score float64) *SearchResult {
+	copied := *chunk
+	copied.Score = score
+	return &copied
+}
@@ -0,0 +1,76 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStitchChunks(t *testing.T) {
+	t.Run("ロジック単位のチャンクを親チャンクに置き換えてファイル要約を添える", func(t *testing.T) {
+		fileID := uuid.New()
+		summary := "決済処理を提供するファイル"
+		logic := &SearchResult{ChunkID: uuid.New(), FilePath: "pay.go", StartLine: 12, EndLine: 14, Content: "l12\nl13\nl14", Score: 0.9}
+		other := &SearchResult{ChunkID: uuid.New(), FilePath: "pay.go", StartLine: 30, EndLine: 31, Content: "l30\nl31", Score: 0.7}
+		parent := &ChunkContext{ID: uuid.New(), StartLine: 10, EndLine: 20, Content: "func Pay() {...}"}
+
+		files := StitchChunks([]*SearchResult{logic, other}, []*ChunkHierarchy{
+			{ChunkID: logic.ChunkID, FileID: fileID, Level: 3, Parent: parent, FileSummary: &summary},
+			{ChunkID: other.ChunkID, FileID: fileID, Level: 2, FileSummary: &summary},
+		})
+
+		require.Len(t, files, 1)
+		assert.Equal(t, fileID, files[0].FileID)
+		assert.Equal(t, &summary, files[0].FileSummary)
+		require.Len(t, files[0].Chunks, 2)
+		assert.Equal(t, parent.ID, files[0].Chunks[0].ChunkID)
+		assert.Equal(t, 10, files[0].Chunks[0].StartLine)
+		assert.Equal(t, 0.9, files[0].Chunks[0].Score)
+		assert.Equal(t, other.ChunkID, files[0].Chunks[1].ChunkID)
+	})
+
+	t.Run("同じ親に属するチャンクは1つにまとめる", func(t *testing.T) {
+		fileID := uuid.New()
+		parent := &ChunkContext{ID: uuid.New(), StartLine: 1, EndLine: 50, Content: "func Run() {...}"}
+		a := &SearchResult{ChunkID: uuid.New(), FilePath: "run.go", StartLine: 5, EndLine: 10, Score: 0.6}
+		b := &SearchResult{ChunkID: uuid.New(), FilePath: "run.go", StartLine: 20, EndLine: 30, Score: 0.8}
+
+		files := StitchChunks([]*SearchResult{a, b}, []*ChunkHierarchy{
+			{ChunkID: a.ChunkID, FileID: fileID, Level: 3, Parent: parent},
+			{ChunkID: b.ChunkID, FileID: fileID, Level: 3, Parent: parent},
+		})
+
+		require.Len(t, files, 1)
+		require.Len(t, files[0].Chunks, 1)
+		assert.Equal(t, parent.ID, files[0].Chunks[0].ChunkID)
+		assert.Equal(t, 0.8, files[0].Chunks[0].Score)
+	})
+
+	t.Run("部分的に重なる行範囲は連結する", func(t *testing.T) {
+		a := &SearchResult{ChunkID: uuid.New(), FilePath: "doc.md", StartLine: 1, EndLine: 3, Content: "l1\nl2\nl3", Score: 0.5}
+		b := &SearchResult{ChunkID: uuid.New(), FilePath: "doc.md", StartLine: 3, EndLine: 5, Content: "l3\nl4\nl5", Score: 0.7}
+
+		files := StitchChunks([]*SearchResult{b, a}, nil)
+
+		require.Len(t, files, 1)
+		require.Len(t, files[0].Chunks, 1)
+		merged := files[0].Chunks[0]
+		assert.Equal(t, 1, merged.StartLine)
+		assert.Equal(t, 5, merged.EndLine)
+		assert.Equal(t, "l1\nl2\nl3\nl4\nl5", merged.Content)
+		assert.Equal(t, 0.7, merged.Score)
+	})
+
+	t.Run("ファイルはスコアの高い順に並べる", func(t *testing.T) {
+		low := &SearchResult{ChunkID: uuid.New(), FilePath: "a.go", StartLine: 1, EndLine: 1, Score: 0.3}
+		high := &SearchResult{ChunkID: uuid.New(), FilePath: "b.go", StartLine: 1, EndLine: 1, Score: 0.9}
+
+		files := StitchChunks([]*SearchResult{low, high}, nil)
+
+		require.Len(t, files, 2)
+		assert.Equal(t, "b.go", files[0].FilePath)
+		assert.Equal(t, "a.go", files[1].FilePath)
+	})
+}
@@ -0,0 +1,65 @@
+package search
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+)
+
+// RetrievalStrategy はチャンク検索の方式
+type RetrievalStrategy string
+
+const (
+	// RetrievalFlat はチャンクを直接ベクトル検索する（既定）
+	RetrievalFlat RetrievalStrategy = "flat"
+	// RetrievalTwoStage はファイル要約で候補ファイルを絞り込んでから、そのファイル内のチャンクを検索する
+	RetrievalTwoStage RetrievalStrategy = "two-stage"
+)
+
+// DefaultCandidateFiles は2段階検索の1段目で選ぶ候補ファイル数の既定値
+const DefaultCandidateFiles = 20
+
+// ParseRetrievalStrategy は文字列から検索方式を返す（空文字は RetrievalFlat）
+func ParseRetrievalStrategy(value string) (RetrievalStrategy, error) {
+	switch RetrievalStrategy(value) {
+	case "", RetrievalFlat:
+		return RetrievalFlat, nil
+	case RetrievalTwoStage:
+		return RetrievalTwoStage, nil
+	default:
+		return "", fmt.Errorf("unknown retrieval strategy %q: expected %s or %s", value, RetrievalFlat, RetrievalTwoStage)
+	}
+}
+
+// resolveStrategy は指定がなければサービスの既定の検索方式を返す
+func (s *SearchService) resolveStrategy(strategy RetrievalStrategy) (RetrievalStrategy, error) {
+	if strategy == "" {
+		return s.strategy, nil
+	}
+	return ParseRetrievalStrategy(string(strategy))
+}
+
+// narrowToCandidateFiles は2段階検索の1段目としてファイル要約を検索し、
+// 候補ファイルにチャンク検索を限定したフィルタを返す
+// ファイル要約が見つからない場合（要約未生成など）はフィルタをそのまま返し、全ファイルを対象にする
+func (s *SearchService) narrowToCandidateFiles(ctx context.Context, productID, sourceID mo.Option[uuid.UUID], queryVector []float32, filter SearchFilter) (SearchFilter, error) {
+	candidates, err := s.repo.SearchFileSummaries(ctx, productID, sourceID, queryVector, s.candidateFiles, filter)
+	if err != nil {
+		return filter, fmt.Errorf("failed to search file summaries: %w", err)
+	}
+	if len(candidates) == 0 {
+		s.logger.Debug("no file summaries matched, falling back to flat retrieval")
+		return filter, nil
+	}
+
+	fileIDs := make([]uuid.UUID, 0, len(candidates))
+	for _, candidate := range candidates {
+		fileIDs = append(fileIDs, candidate.FileID)
+	}
+	filter.FileIDs = fileIDs
+
+	s.logger.Debug("candidate files selected by file summaries", "files", len(fileIDs))
+	return filter, nil
+}
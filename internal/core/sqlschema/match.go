@@ -0,0 +1,133 @@
+package sqlschema
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// minColumnMatchLen はカラム名で照合する際の最小の長さ（id・name などの汎用的な名前を除く）
+const minColumnMatchLen = 5
+
+// MatchTables は質問文に名前が現れるテーブルを関連度順に最大 limit 件返す
+// テーブル名（単数形・アンダースコアを空白にした表記を含む）の一致を優先し、次にカラム名の一致で補う
+func MatchTables(tables []*Table, text string, limit int) []*Table {
+	lower := strings.ToLower(text)
+	type scored struct {
+		table *Table
+		score int
+	}
+	var matches []scored
+	for _, t := range tables {
+		score := 0
+		for _, name := range tableAliases(lastPart(t.Name)) {
+			if containsWord(lower, name) {
+				score = 10
+				break
+			}
+		}
+		for _, col := range t.Columns {
+			if len(col.Name) >= minColumnMatchLen && containsWord(lower, col.Name) {
+				score++
+			}
+		}
+		if score > 0 {
+			matches = append(matches, scored{table: t, score: score})
+		}
+	}
+
+	slices.SortStableFunc(matches, func(a, b scored) int {
+		return cmp.Or(cmp.Compare(b.score, a.score), cmp.Compare(a.table.Name, b.table.Name))
+	})
+	result := make([]*Table, 0, min(len(matches), limit))
+	for _, m := range matches {
+		if len(result) >= limit {
+			break
+		}
+		result = append(result, m.table)
+	}
+	return result
+}
+
+// tableAliases はテーブル名の照合に使用する表記（snapshot_files なら snapshot_file・snapshot files など）を返す
+func tableAliases(name string) []string {
+	aliases := []string{name}
+	if singular, ok := strings.CutSuffix(name, "s"); ok && len(singular) >= 3 {
+		aliases = append(aliases, singular)
+	}
+	for _, alias := range aliases {
+		if strings.Contains(alias, "_") {
+			aliases = append(aliases, strings.ReplaceAll(alias, "_", " "))
+		}
+	}
+	return aliases
+}
+
+// containsWord は text に word が単語として（前後が英数字・アンダースコア以外で）含まれるかを判定する
+func containsWord(text, word string) bool {
+	pattern := regexp.MustCompile(`(^|[^a-z0-9_])` + regexp.QuoteMeta(word) + `($|[^a-z0-9_])`)
+	return pattern.MatchString(text)
+}
+
+// Format はテーブルをプロンプトに埋め込むための要約（カラム・インデックスの一覧）に整形する
+func Format(t *Table) string {
+	var sb strings.Builder
+	sb.WriteString("テーブル " + t.Name)
+	if t.Comment != "" {
+		sb.WriteString("（" + t.Comment + "）")
+	}
+	sb.WriteString("\n")
+	if len(t.SourcePaths) > 0 {
+		sb.WriteString("定義: " + strings.Join(t.SourcePaths, ", ") + "\n")
+	}
+
+	sb.WriteString("カラム:\n")
+	for _, col := range t.Columns {
+		parts := []string{col.Name, col.Type}
+		if col.PrimaryKey {
+			parts = append(parts, "PRIMARY KEY")
+		} else if col.NotNull {
+			parts = append(parts, "NOT NULL")
+		}
+		if col.Default != "" {
+			parts = append(parts, "DEFAULT "+col.Default)
+		}
+		if col.References != "" {
+			parts = append(parts, "REFERENCES "+col.References)
+		}
+		line := "- " + strings.Join(parts, " ")
+		if col.Comment != "" {
+			line += " -- " + col.Comment
+		}
+		sb.WriteString(line + "\n")
+	}
+
+	if len(t.Indexes) > 0 {
+		sb.WriteString("インデックス:\n")
+		for _, idx := range t.Indexes {
+			sb.WriteString("- " + FormatIndex(idx) + "\n")
+		}
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// FormatIndex はインデックスを "[UNIQUE] <名前> USING <メソッド> (<対象>) WHERE <条件>" の形式に整形する
+func FormatIndex(idx Index) string {
+	var parts []string
+	if idx.Unique {
+		parts = append(parts, "UNIQUE")
+	}
+	if idx.Name != "" {
+		parts = append(parts, idx.Name)
+	}
+	if idx.Method != "" {
+		parts = append(parts, "USING "+idx.Method)
+	}
+	parts = append(parts, fmt.Sprintf("(%s)", idx.Definition))
+	if idx.Where != "" {
+		parts = append(parts, "WHERE "+idx.Where)
+	}
+	return strings.Join(parts, " ")
+}
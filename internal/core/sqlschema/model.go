@@ -0,0 +1,48 @@
+// Package sqlschema はSQLのスキーマ定義・マイグレーションファイルからテーブル・カラム・インデックスのカタログを構築する
+package sqlschema
+
+import (
+	"github.com/google/uuid"
+)
+
+// Table はスキーマカタログのテーブル
+type Table struct {
+	ID          uuid.UUID
+	SnapshotID  uuid.UUID
+	SourceName  string // 取得時のみ設定される
+	Name        string // スキーマ修飾付き（public スキーマは省略）
+	Comment     string
+	Columns     []Column
+	Indexes     []Index
+	SourcePaths []string // テーブルを定義・変更したファイル（適用順）
+}
+
+// Column はテーブルのカラム
+type Column struct {
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	NotNull    bool   `json:"not_null,omitempty"`
+	PrimaryKey bool   `json:"primary_key,omitempty"`
+	Default    string `json:"default,omitempty"`
+	References string `json:"references,omitempty"` // 外部キーの参照先（<テーブル>(<カラム>)）
+	Comment    string `json:"comment,omitempty"`
+}
+
+// Index はテーブルのインデックス（UNIQUE 制約を含む）
+type Index struct {
+	Name       string `json:"name,omitempty"`
+	Definition string `json:"definition"`       // インデックスの対象（カラム・式の一覧）
+	Method     string `json:"method,omitempty"` // USING で指定したアクセスメソッド（hnsw など）
+	Unique     bool   `json:"unique,omitempty"`
+	Where      string `json:"where,omitempty"` // 部分インデックスの条件
+}
+
+// Column は名前が一致するカラムを返す
+func (t *Table) Column(name string) (*Column, bool) {
+	for i := range t.Columns {
+		if t.Columns[i].Name == name {
+			return &t.Columns[i], true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,569 @@
+package sqlschema
+
+import (
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+const (
+	identPart = `(?:"[^"]+"|[\w$]+)`
+	// ident はスキーマ修飾を含む識別子（"public"."users" など）
+	ident = `(` + identPart + `(?:\s*\.\s*` + identPart + `){0,2})`
+)
+
+var (
+	identPartPattern   = regexp.MustCompile(identPart)
+	createTablePattern = regexp.MustCompile(`(?is)^create\s+(?:(?:global|local)\s+)?(?:(?:temporary|temp|unlogged)\s+)?table\s+(if\s+not\s+exists\s+)?` + ident + `\s*\(`)
+	alterTablePattern  = regexp.MustCompile(`(?is)^alter\s+table\s+(?:if\s+exists\s+)?(?:only\s+)?` + ident + `\s+(.*)$`)
+	createIndexPattern = regexp.MustCompile(`(?is)^create\s+(unique\s+)?index\s+(?:concurrently\s+)?(if\s+not\s+exists\s+)?(?:` + ident + `\s+)?on\s+(?:only\s+)?` + ident + `\s*(?:using\s+(\w+)\s*)?\(`)
+	dropTablePattern   = regexp.MustCompile(`(?is)^drop\s+table\s+(?:if\s+exists\s+)?(.+?)(?:\s+(?:cascade|restrict))?$`)
+	dropIndexPattern   = regexp.MustCompile(`(?is)^drop\s+index\s+(?:concurrently\s+)?(?:if\s+exists\s+)?(.+?)(?:\s+(?:cascade|restrict))?$`)
+	alterIndexPattern  = regexp.MustCompile(`(?is)^alter\s+index\s+(?:if\s+exists\s+)?` + ident + `\s+rename\s+to\s+` + ident + `$`)
+	commentPattern     = regexp.MustCompile(`(?is)^comment\s+on\s+(table|column)\s+` + ident + `\s+is\s+(.+)$`)
+	wherePattern       = regexp.MustCompile(`(?is)^\s*(?:include\s*\([^)]*\)\s*)?(?:with\s*\([^)]*\)\s*)?where\s+(.+)$`)
+
+	renameTablePattern  = regexp.MustCompile(`(?is)^rename\s+to\s+` + ident + `$`)
+	renameColumnPattern = regexp.MustCompile(`(?is)^rename\s+(?:column\s+)?` + ident + `\s+to\s+` + ident + `$`)
+	addConstraintPrefix = regexp.MustCompile(`(?is)^add\s+((?:constraint|primary|unique|foreign|check|exclude)\b.*)$`)
+	addColumnPattern    = regexp.MustCompile(`(?is)^add\s+(?:column\s+)?(if\s+not\s+exists\s+)?(.+)$`)
+	dropConstraintPat   = regexp.MustCompile(`(?is)^drop\s+constraint\s+(?:if\s+exists\s+)?` + ident)
+	dropColumnPattern   = regexp.MustCompile(`(?is)^drop\s+(?:column\s+)?(?:if\s+exists\s+)?` + ident)
+	alterColumnPattern  = regexp.MustCompile(`(?is)^alter\s+(?:column\s+)?` + ident + `\s+(.+)$`)
+	columnTypePattern   = regexp.MustCompile(`(?is)^(?:set\s+data\s+)?type\s+(.+?)(?:\s+(?:collate|using)\s+.*)?$`)
+	setDefaultPattern   = regexp.MustCompile(`(?is)^set\s+default\s+(.+)$`)
+
+	constraintNamePattern = regexp.MustCompile(`(?is)^constraint\s+` + ident + `\s+(.*)$`)
+	primaryKeyPattern     = regexp.MustCompile(`(?is)^primary\s+key\s*\(([^)]*)\)`)
+	uniquePattern         = regexp.MustCompile(`(?is)^unique(?:\s+nulls\s+(?:not\s+)?distinct)?\s*\(([^)]*)\)`)
+	foreignKeyPattern     = regexp.MustCompile(`(?is)^foreign\s+key\s*\(([^)]*)\)\s*references\s+` + ident + `\s*(?:\(([^)]*)\))?`)
+
+	notNullPattern     = regexp.MustCompile(`(?i)\bnot\s+null\b`)
+	inlinePKPattern    = regexp.MustCompile(`(?i)\bprimary\s+key\b`)
+	inlineUnique       = regexp.MustCompile(`(?i)\bunique\b`)
+	defaultPattern     = regexp.MustCompile(`(?is)\bdefault\s+(.+?)(?:\s+(?:not\s+null|null|primary\s+key|references|unique|check|constraint|generated|collate)\b|$)`)
+	referencesPattern  = regexp.MustCompile(`(?is)\breferences\s+` + ident + `\s*(?:\(([^)]*)\))?`)
+	columnKeywordStart = regexp.MustCompile(`(?i)\s(?:not\s+null|null|default|primary\s+key|references|unique|check|constraint|generated|collate)\b`)
+	whitespacePattern  = regexp.MustCompile(`\s+`)
+)
+
+// tableConstraintKeywords はテーブル定義の要素のうち、カラムではなく制約を表す先頭のキーワード
+var tableConstraintKeywords = []string{"constraint", "primary", "unique", "foreign", "check", "exclude", "like"}
+
+// IsSchemaFile はファイルがスキーマ定義・マイグレーションのSQLかを判定する（ロールバック用の down マイグレーションは除く）
+func IsSchemaFile(p string) bool {
+	base := strings.ToLower(path.Base(p))
+	if path.Ext(base) != ".sql" {
+		return false
+	}
+	return !strings.HasSuffix(base, ".down.sql") && !strings.HasSuffix(base, "_down.sql") && !strings.Contains(base, "rollback")
+}
+
+// Builder はSQLファイルを順に適用してスキーマカタログを構築する
+// CREATE TABLE・ALTER TABLE・CREATE INDEX・DROP・COMMENT ON を解釈し、それ以外の文は無視する
+type Builder struct {
+	tables map[string]*Table
+}
+
+// NewBuilder は空のカタログから始める Builder を作成する
+func NewBuilder() *Builder {
+	return &Builder{tables: make(map[string]*Table)}
+}
+
+// Build はファイルパス順（マイグレーションの番号順）にSQLを適用してスキーマカタログを構築する
+func Build(files map[string]string) []*Table {
+	paths := make([]string, 0, len(files))
+	for p := range files {
+		paths = append(paths, p)
+	}
+	slices.Sort(paths)
+
+	b := NewBuilder()
+	for _, p := range paths {
+		b.Apply(p, files[p])
+	}
+	return b.Tables()
+}
+
+// Tables はカタログのテーブルを名前順に返す
+func (b *Builder) Tables() []*Table {
+	tables := make([]*Table, 0, len(b.tables))
+	for _, t := range b.tables {
+		tables = append(tables, t)
+	}
+	slices.SortFunc(tables, func(a, b *Table) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return tables
+}
+
+// Apply はSQLファイルの各文をカタログに適用する
+func (b *Builder) Apply(filePath, content string) {
+	for _, stmt := range splitStatements(content) {
+		b.applyStatement(filePath, stmt)
+	}
+}
+
+func (b *Builder) applyStatement(filePath, stmt string) {
+	switch {
+	case createTablePattern.MatchString(stmt):
+		b.createTable(filePath, stmt)
+	case alterTablePattern.MatchString(stmt):
+		m := alterTablePattern.FindStringSubmatch(stmt)
+		t, ok := b.tables[normalizeName(m[1])]
+		if !ok {
+			return
+		}
+		for _, action := range splitTopLevel(m[2]) {
+			b.alterTable(t, action)
+		}
+		t.touch(filePath)
+	case createIndexPattern.MatchString(stmt):
+		b.createIndex(filePath, stmt)
+	case dropTablePattern.MatchString(stmt):
+		m := dropTablePattern.FindStringSubmatch(stmt)
+		for _, name := range splitTopLevel(m[1]) {
+			delete(b.tables, normalizeName(name))
+		}
+	case dropIndexPattern.MatchString(stmt):
+		m := dropIndexPattern.FindStringSubmatch(stmt)
+		for _, name := range splitTopLevel(m[1]) {
+			b.dropIndex(normalizeName(name))
+		}
+	case alterIndexPattern.MatchString(stmt):
+		m := alterIndexPattern.FindStringSubmatch(stmt)
+		if t, i, ok := b.findIndex(normalizeName(m[1])); ok {
+			t.Indexes[i].Name = lastPart(normalizeName(m[2]))
+		}
+	case commentPattern.MatchString(stmt):
+		b.comment(filePath, commentPattern.FindStringSubmatch(stmt))
+	}
+}
+
+func (b *Builder) createTable(filePath, stmt string) {
+	loc := createTablePattern.FindStringSubmatchIndex(stmt)
+	ifNotExists := loc[2] >= 0
+	name := normalizeName(stmt[loc[4]:loc[5]])
+	if _, exists := b.tables[name]; exists && ifNotExists {
+		return
+	}
+
+	body, _ := parenBody(stmt, loc[1])
+	t := &Table{Name: name}
+	for _, elem := range splitTopLevel(body) {
+		first := strings.ToLower(strings.Fields(elem)[0])
+		if slices.Contains(tableConstraintKeywords, first) {
+			t.addConstraint(elem)
+			continue
+		}
+		if col, unique, ok := parseColumn(elem); ok {
+			t.Columns = append(t.Columns, col)
+			if unique {
+				t.Indexes = append(t.Indexes, Index{Definition: col.Name, Unique: true})
+			}
+		}
+	}
+	t.touch(filePath)
+	b.tables[name] = t
+}
+
+func (b *Builder) alterTable(t *Table, action string) {
+	switch {
+	case renameTablePattern.MatchString(action):
+		m := renameTablePattern.FindStringSubmatch(action)
+		delete(b.tables, t.Name)
+		t.Name = normalizeName(m[1])
+		b.tables[t.Name] = t
+	case renameColumnPattern.MatchString(action):
+		m := renameColumnPattern.FindStringSubmatch(action)
+		if col, ok := t.Column(normalizeName(m[1])); ok {
+			col.Name = normalizeName(m[2])
+		}
+	case addConstraintPrefix.MatchString(action):
+		t.addConstraint(addConstraintPrefix.FindStringSubmatch(action)[1])
+	case addColumnPattern.MatchString(action):
+		m := addColumnPattern.FindStringSubmatch(action)
+		col, unique, ok := parseColumn(m[2])
+		if !ok {
+			return
+		}
+		if existing, exists := t.Column(col.Name); exists {
+			if m[1] == "" {
+				*existing = col
+			}
+			return
+		}
+		t.Columns = append(t.Columns, col)
+		if unique {
+			t.Indexes = append(t.Indexes, Index{Definition: col.Name, Unique: true})
+		}
+	case dropConstraintPat.MatchString(action):
+		name := lastPart(normalizeName(dropConstraintPat.FindStringSubmatch(action)[1]))
+		t.Indexes = slices.DeleteFunc(t.Indexes, func(idx Index) bool { return idx.Name == name })
+	case dropColumnPattern.MatchString(action):
+		name := normalizeName(dropColumnPattern.FindStringSubmatch(action)[1])
+		t.Columns = slices.DeleteFunc(t.Columns, func(c Column) bool { return c.Name == name })
+	case alterColumnPattern.MatchString(action):
+		m := alterColumnPattern.FindStringSubmatch(action)
+		col, ok := t.Column(normalizeName(m[1]))
+		if !ok {
+			return
+		}
+		change := strings.TrimSpace(m[2])
+		lower := strings.ToLower(whitespacePattern.ReplaceAllString(change, " "))
+		switch {
+		case columnTypePattern.MatchString(change):
+			col.Type = collapse(columnTypePattern.FindStringSubmatch(change)[1])
+		case lower == "set not null":
+			col.NotNull = true
+		case lower == "drop not null":
+			col.NotNull = false
+		case lower == "drop default":
+			col.Default = ""
+		case setDefaultPattern.MatchString(change):
+			col.Default = collapse(setDefaultPattern.FindStringSubmatch(change)[1])
+		}
+	}
+}
+
+func (b *Builder) createIndex(filePath, stmt string) {
+	loc := createIndexPattern.FindStringSubmatchIndex(stmt)
+	m := createIndexPattern.FindStringSubmatch(stmt)
+	t, ok := b.tables[normalizeName(m[4])]
+	if !ok {
+		return
+	}
+
+	body, end := parenBody(stmt, loc[1])
+	idx := Index{
+		Definition: collapse(body),
+		Method:     strings.ToLower(m[5]),
+		Unique:     m[1] != "",
+	}
+	if m[3] != "" {
+		idx.Name = lastPart(normalizeName(m[3]))
+	}
+	if w := wherePattern.FindStringSubmatch(stmt[end:]); w != nil {
+		idx.Where = collapse(w[1])
+	}
+
+	if i := slices.IndexFunc(t.Indexes, func(existing Index) bool { return idx.Name != "" && existing.Name == idx.Name }); i >= 0 {
+		if m[2] == "" {
+			t.Indexes[i] = idx
+		}
+	} else {
+		t.Indexes = append(t.Indexes, idx)
+	}
+	t.touch(filePath)
+}
+
+func (b *Builder) dropIndex(name string) {
+	if t, i, ok := b.findIndex(name); ok {
+		t.Indexes = slices.Delete(t.Indexes, i, i+1)
+	}
+}
+
+// findIndex は名前が一致するインデックスをすべてのテーブルから探す（インデックス名はスキーマ内で一意）
+func (b *Builder) findIndex(name string) (*Table, int, bool) {
+	name = lastPart(name)
+	for _, t := range b.tables {
+		for i, idx := range t.Indexes {
+			if idx.Name == name {
+				return t, i, true
+			}
+		}
+	}
+	return nil, 0, false
+}
+
+func (b *Builder) comment(filePath string, m []string) {
+	parts := identPartPattern.FindAllString(m[2], -1)
+	text := unquoteLiteral(strings.TrimSpace(m[3]))
+	if strings.EqualFold(m[1], "table") {
+		if t, ok := b.tables[normalizeName(m[2])]; ok {
+			t.Comment = text
+			t.touch(filePath)
+		}
+		return
+	}
+	if len(parts) < 2 {
+		return
+	}
+	t, ok := b.tables[normalizeName(strings.Join(parts[:len(parts)-1], "."))]
+	if !ok {
+		return
+	}
+	if col, ok := t.Column(normalizeName(parts[len(parts)-1])); ok {
+		col.Comment = text
+		t.touch(filePath)
+	}
+}
+
+// addConstraint はテーブル制約（PRIMARY KEY・UNIQUE・FOREIGN KEY）をカタログに反映する
+func (t *Table) addConstraint(elem string) {
+	name := ""
+	if m := constraintNamePattern.FindStringSubmatch(elem); m != nil {
+		name = lastPart(normalizeName(m[1]))
+		elem = m[2]
+	}
+
+	switch {
+	case primaryKeyPattern.MatchString(elem):
+		for _, colName := range splitColumnList(primaryKeyPattern.FindStringSubmatch(elem)[1]) {
+			if col, ok := t.Column(colName); ok {
+				col.PrimaryKey = true
+				col.NotNull = true
+			}
+		}
+	case uniquePattern.MatchString(elem):
+		cols := splitColumnList(uniquePattern.FindStringSubmatch(elem)[1])
+		t.Indexes = append(t.Indexes, Index{Name: name, Definition: strings.Join(cols, ", "), Unique: true})
+	case foreignKeyPattern.MatchString(elem):
+		m := foreignKeyPattern.FindStringSubmatch(elem)
+		cols := splitColumnList(m[1])
+		refCols := splitColumnList(m[3])
+		for i, colName := range cols {
+			col, ok := t.Column(colName)
+			if !ok {
+				continue
+			}
+			ref := normalizeName(m[2])
+			if i < len(refCols) {
+				ref += "(" + refCols[i] + ")"
+			}
+			col.References = ref
+		}
+	}
+}
+
+// touch はテーブルを定義・変更したファイルを記録する
+func (t *Table) touch(filePath string) {
+	if !slices.Contains(t.SourcePaths, filePath) {
+		t.SourcePaths = append(t.SourcePaths, filePath)
+	}
+}
+
+// parseColumn はカラム定義を解析する（インラインの UNIQUE 制約の有無も返す）
+func parseColumn(def string) (Column, bool, bool) {
+	def = strings.TrimSpace(def)
+	name := identPartPattern.FindString(def)
+	if name == "" {
+		return Column{}, false, false
+	}
+	rest := strings.TrimSpace(def[len(name):])
+	if rest == "" {
+		return Column{}, false, false
+	}
+
+	typ := rest
+	constraints := ""
+	if loc := columnKeywordStart.FindStringIndex(" " + rest); loc != nil {
+		typ = rest[:max(loc[0]-1, 0)]
+		constraints = rest[max(loc[0]-1, 0):]
+	}
+
+	col := Column{
+		Name:       normalizeName(name),
+		Type:       collapse(typ),
+		NotNull:    notNullPattern.MatchString(constraints),
+		PrimaryKey: inlinePKPattern.MatchString(constraints),
+	}
+	if col.PrimaryKey {
+		col.NotNull = true
+	}
+	if m := defaultPattern.FindStringSubmatch(constraints); m != nil {
+		col.Default = collapse(m[1])
+	}
+	if m := referencesPattern.FindStringSubmatch(constraints); m != nil {
+		col.References = normalizeName(m[1])
+		if cols := splitColumnList(m[2]); len(cols) > 0 {
+			col.References += "(" + cols[0] + ")"
+		}
+	}
+	return col, inlineUnique.MatchString(constraints), true
+}
+
+// splitStatements はSQLを文に分割する（コメントを除き、文字列・引用符付き識別子・ドル引用符内の ; では分割しない）
+func splitStatements(src string) []string {
+	var stmts []string
+	var sb strings.Builder
+	flush := func() {
+		if s := strings.TrimSpace(sb.String()); s != "" {
+			stmts = append(stmts, s)
+		}
+		sb.Reset()
+	}
+
+	for i := 0; i < len(src); i++ {
+		c := src[i]
+		switch {
+		case c == '-' && strings.HasPrefix(src[i:], "--"):
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+			sb.WriteByte('\n')
+		case c == '/' && strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				i = len(src)
+			} else {
+				i += end + 3
+			}
+			sb.WriteByte(' ')
+		case c == '\'' || c == '"':
+			end := i + 1
+			for end < len(src) {
+				if src[end] == c {
+					// '' は文字列内のエスケープ
+					if end+1 < len(src) && src[end+1] == c {
+						end += 2
+						continue
+					}
+					break
+				}
+				end++
+			}
+			sb.WriteString(src[i:min(end+1, len(src))])
+			i = end
+		case c == '$':
+			tag := dollarTag(src[i:])
+			if tag == "" {
+				sb.WriteByte(c)
+				continue
+			}
+			end := strings.Index(src[i+len(tag):], tag)
+			if end < 0 {
+				sb.WriteString(src[i:])
+				i = len(src)
+				continue
+			}
+			stop := i + len(tag) + end + len(tag)
+			sb.WriteString(src[i:stop])
+			i = stop - 1
+		case c == ';':
+			flush()
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	flush()
+	return stmts
+}
+
+// dollarTag は s の先頭がドル引用符（$$ または $tag$）であればそのタグを返す
+func dollarTag(s string) string {
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		if c == '$' {
+			return s[:i+1]
+		}
+		if !(c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || i > 1 && c >= '0' && c <= '9') {
+			return ""
+		}
+	}
+	return ""
+}
+
+// parenBody は open（'(' の直後の位置）から対応する ')' までの内容と、')' の直後の位置を返す
+func parenBody(s string, open int) (string, int) {
+	depth := 1
+	inQuote := byte(0)
+	for i := open; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case inQuote != 0:
+			if c == inQuote {
+				inQuote = 0
+			}
+		case c == '\'' || c == '"':
+			inQuote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+			if depth == 0 {
+				return s[open:i], i + 1
+			}
+		}
+	}
+	return s[open:], len(s)
+}
+
+// splitTopLevel は括弧・引用符の外側のカンマで分割する
+func splitTopLevel(s string) []string {
+	var parts []string
+	depth := 0
+	inQuote := byte(0)
+	start := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case inQuote != 0:
+			if c == inQuote {
+				inQuote = 0
+			}
+		case c == '\'' || c == '"':
+			inQuote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == ',' && depth == 0:
+			if part := strings.TrimSpace(s[start:i]); part != "" {
+				parts = append(parts, part)
+			}
+			start = i + 1
+		}
+	}
+	if part := strings.TrimSpace(s[start:]); part != "" {
+		parts = append(parts, part)
+	}
+	return parts
+}
+
+// splitColumnList はカラム名の一覧（a, "B"）を正規化して分割する
+func splitColumnList(s string) []string {
+	var cols []string
+	for _, part := range splitTopLevel(s) {
+		cols = append(cols, normalizeName(part))
+	}
+	return cols
+}
+
+// normalizeName は識別子を正規化する（引用符なしは小文字化、public スキーマの修飾は省略）
+func normalizeName(s string) string {
+	parts := identPartPattern.FindAllString(strings.TrimSpace(s), -1)
+	for i, p := range parts {
+		if strings.HasPrefix(p, `"`) {
+			parts[i] = strings.Trim(p, `"`)
+		} else {
+			parts[i] = strings.ToLower(p)
+		}
+	}
+	if len(parts) > 1 && parts[0] == "public" {
+		parts = parts[1:]
+	}
+	return strings.Join(parts, ".")
+}
+
+// lastPart はスキーマ修飾を除いた名前を返す
+func lastPart(name string) string {
+	return name[strings.LastIndex(name, ".")+1:]
+}
+
+// unquoteLiteral は文字列リテラル（'...'、E'...'）の内容を返す（NULL は空文字）
+func unquoteLiteral(s string) string {
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "E"), "e")
+	if len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'' {
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'")
+	}
+	if strings.EqualFold(s, "null") {
+		return ""
+	}
+	return s
+}
+
+func collapse(s string) string {
+	return strings.TrimSpace(whitespacePattern.ReplaceAllString(s, " "))
+}
@@ -0,0 +1,183 @@
+package sqlschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsSchemaFile(t *testing.T) {
+	assert.True(t, IsSchemaFile("schema/schema.sql"))
+	assert.True(t, IsSchemaFile("db/migrations/001_init.up.sql"))
+	assert.True(t, IsSchemaFile("db/migrations/V2__Add_Users.SQL"))
+	assert.False(t, IsSchemaFile("db/migrations/001_init.down.sql"))
+	assert.False(t, IsSchemaFile("db/migrations/002_users_down.sql"))
+	assert.False(t, IsSchemaFile("db/rollback_002.sql"))
+	assert.False(t, IsSchemaFile("docs/schema.md"))
+}
+
+func TestBuild(t *testing.T) {
+	files := map[string]string{
+		"migrations/001_init.up.sql": `
+-- チャンクのテーブル; コメント内の ; では分割しない
+CREATE TABLE IF NOT EXISTS public.chunks (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    file_id UUID NOT NULL REFERENCES files(id) ON DELETE CASCADE,
+    content TEXT NOT NULL,
+    "Ordinal" INTEGER,
+    price NUMERIC(10, 2) DEFAULT 0 NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_chunks_file_ordinal UNIQUE (file_id, "Ordinal")
+);
+
+CREATE TABLE embeddings (
+    chunk_id UUID NOT NULL,
+    vector vector(1536) NOT NULL,
+    model VARCHAR(100) NOT NULL,
+    PRIMARY KEY (chunk_id),
+    FOREIGN KEY (chunk_id) REFERENCES chunks (id)
+);
+
+/* HNSW インデックス */
+CREATE INDEX IF NOT EXISTS idx_embeddings_vector ON embeddings USING hnsw (vector vector_cosine_ops);
+
+CREATE OR REPLACE FUNCTION touch() RETURNS trigger AS $$
+BEGIN
+    NEW.updated_at = now();
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+COMMENT ON TABLE embeddings IS 'チャンクの埋め込みベクトル';
+COMMENT ON COLUMN public.embeddings.model IS 'モデル名（''text-embedding-3-small'' など）';
+`,
+		"migrations/002_alter.up.sql": `
+ALTER TABLE chunks ADD COLUMN IF NOT EXISTS token_count INTEGER, DROP COLUMN price;
+ALTER TABLE chunks ALTER COLUMN content SET DATA TYPE VARCHAR(4000) USING content::varchar;
+ALTER TABLE chunks RENAME COLUMN "Ordinal" TO position;
+CREATE UNIQUE INDEX idx_chunks_active ON chunks (file_id) WHERE is_latest = true;
+ALTER TABLE embeddings ALTER COLUMN model DROP NOT NULL;
+CREATE TABLE legacy (id int);
+DROP TABLE IF EXISTS legacy CASCADE;
+DROP INDEX IF EXISTS idx_missing;
+ALTER TABLE unknown_table ADD COLUMN x int;
+`,
+		// 既に作成済みのテーブルの IF NOT EXISTS は無視される
+		"schema/schema.sql": `CREATE TABLE IF NOT EXISTS embeddings (id int);`,
+	}
+
+	tables := Build(files)
+	require.Len(t, tables, 2)
+
+	chunks := tables[0]
+	assert.Equal(t, "chunks", chunks.Name)
+	assert.Equal(t, []string{"migrations/001_init.up.sql", "migrations/002_alter.up.sql"}, chunks.SourcePaths)
+
+	names := make([]string, 0, len(chunks.Columns))
+	for _, col := range chunks.Columns {
+		names = append(names, col.Name)
+	}
+	assert.Equal(t, []string{"id", "file_id", "content", "position", "created_at", "token_count"}, names)
+
+	id, ok := chunks.Column("id")
+	require.True(t, ok)
+	assert.Equal(t, Column{Name: "id", Type: "UUID", NotNull: true, PrimaryKey: true, Default: "gen_random_uuid()"}, *id)
+
+	fileID, _ := chunks.Column("file_id")
+	assert.Equal(t, "files(id)", fileID.References)
+	assert.True(t, fileID.NotNull)
+
+	content, _ := chunks.Column("content")
+	assert.Equal(t, "VARCHAR(4000)", content.Type)
+
+	createdAt, _ := chunks.Column("created_at")
+	assert.Equal(t, "TIMESTAMP WITH TIME ZONE", createdAt.Type)
+	assert.Equal(t, "CURRENT_TIMESTAMP", createdAt.Default)
+
+	require.Len(t, chunks.Indexes, 2)
+	assert.Equal(t, Index{Name: "uq_chunks_file_ordinal", Definition: "file_id, Ordinal", Unique: true}, chunks.Indexes[0])
+	assert.Equal(t, Index{Name: "idx_chunks_active", Definition: "file_id", Unique: true, Where: "is_latest = true"}, chunks.Indexes[1])
+
+	embeddings := tables[1]
+	assert.Equal(t, "embeddings", embeddings.Name)
+	assert.Equal(t, "チャンクの埋め込みベクトル", embeddings.Comment)
+
+	chunkID, _ := embeddings.Column("chunk_id")
+	assert.True(t, chunkID.PrimaryKey)
+	assert.Equal(t, "chunks(id)", chunkID.References)
+
+	vector, _ := embeddings.Column("vector")
+	assert.Equal(t, "vector(1536)", vector.Type)
+
+	model, _ := embeddings.Column("model")
+	assert.False(t, model.NotNull)
+	assert.Equal(t, "モデル名（'text-embedding-3-small' など）", model.Comment)
+
+	require.Len(t, embeddings.Indexes, 1)
+	assert.Equal(t, Index{Name: "idx_embeddings_vector", Definition: "vector vector_cosine_ops", Method: "hnsw"}, embeddings.Indexes[0])
+}
+
+func TestBuilder_RenameAndDrop(t *testing.T) {
+	b := NewBuilder()
+	b.Apply("001.sql", `
+CREATE TABLE "Users" (id int, email text UNIQUE);
+CREATE INDEX users_email_lower ON "Users" (lower(email));
+ALTER TABLE "Users" RENAME TO accounts;
+ALTER INDEX users_email_lower RENAME TO accounts_email_lower;
+`)
+	b.Apply("002.sql", `DROP INDEX accounts_email_lower; ALTER TABLE accounts ADD CONSTRAINT accounts_pkey PRIMARY KEY (id);`)
+
+	tables := b.Tables()
+	require.Len(t, tables, 1)
+	assert.Equal(t, "accounts", tables[0].Name)
+
+	id, _ := tables[0].Column("id")
+	assert.True(t, id.PrimaryKey)
+
+	require.Len(t, tables[0].Indexes, 1)
+	assert.Equal(t, Index{Definition: "email", Unique: true}, tables[0].Indexes[0])
+}
+
+func TestMatchTables(t *testing.T) {
+	tables := []*Table{
+		{Name: "chunks", Columns: []Column{{Name: "id"}, {Name: "content"}}},
+		{Name: "embeddings", Columns: []Column{{Name: "chunk_id"}, {Name: "vector"}}},
+		{Name: "snapshot_files", Columns: []Column{{Name: "file_path"}}},
+	}
+
+	matched := MatchTables(tables, "Which table stores embeddings and what are its indexes?", 5)
+	require.Len(t, matched, 1)
+	assert.Equal(t, "embeddings", matched[0].Name)
+
+	// 単数形・空白区切りの表記、カラム名でも一致する
+	matched = MatchTables(tables, "snapshot file の file_path と chunk の content はどこに保存される？", 5)
+	require.Len(t, matched, 2)
+	assert.Equal(t, "chunks", matched[0].Name)
+	assert.Equal(t, "snapshot_files", matched[1].Name)
+
+	assert.Len(t, MatchTables(tables, "chunks と embeddings", 1), 1)
+	assert.Empty(t, MatchTables(tables, "認証の仕組みは？", 5))
+}
+
+func TestFormat(t *testing.T) {
+	table := &Table{
+		Name:        "embeddings",
+		Comment:     "埋め込みベクトル",
+		SourcePaths: []string{"migrations/001_init.up.sql"},
+		Columns: []Column{
+			{Name: "chunk_id", Type: "UUID", NotNull: true, PrimaryKey: true, References: "chunks(id)"},
+			{Name: "model", Type: "VARCHAR(100)", Default: "'small'", Comment: "モデル名"},
+		},
+		Indexes: []Index{{Name: "idx_embeddings_vector", Definition: "vector vector_cosine_ops", Method: "hnsw"}},
+	}
+
+	expected := `テーブル embeddings（埋め込みベクトル）
+定義: migrations/001_init.up.sql
+カラム:
+- chunk_id UUID PRIMARY KEY REFERENCES chunks(id)
+- model VARCHAR(100) DEFAULT 'small' -- モデル名
+インデックス:
+- idx_embeddings_vector USING hnsw (vector vector_cosine_ops)`
+	assert.Equal(t, expected, Format(table))
+}
@@ -0,0 +1,68 @@
+package symbol
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+)
+
+// ParseQuery は検索する名前を検索条件に変換する
+// "IndexService.IndexSource"・"(*IndexService).IndexSource" の形式は所属する型名とシンボル名に分け、末尾の "()" は除く
+func ParseQuery(name string) Query {
+	name = strings.Trim(strings.TrimSpace(name), "`")
+	name = strings.TrimSuffix(name, "()")
+
+	var parent string
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		parent, name = name[:i], name[i+1:]
+		parent = strings.TrimLeft(strings.Trim(parent, "()"), "*")
+	}
+	return Query{Name: name, ParentName: parent}
+}
+
+// Find は名前でシンボルを検索する
+// "X.Y" の形式で所属する型名が一致するシンボルがない場合は、X をパッケージ名とみなしてシンボル名のみで検索し直す
+func Find(ctx context.Context, reader Reader, productID, snapshotID mo.Option[uuid.UUID], name, kind string, limit int) ([]*Symbol, error) {
+	query := ParseQuery(name)
+	query.Kind = kind
+	query.Limit = limit
+	if query.Name == "" {
+		return nil, nil
+	}
+
+	symbols, err := reader.FindSymbols(ctx, productID, snapshotID, query)
+	if err != nil || len(symbols) > 0 || query.ParentName == "" {
+		return symbols, err
+	}
+	query.ParentName = ""
+	return reader.FindSymbols(ctx, productID, snapshotID, query)
+}
+
+// definitionName はシンボル名として扱う文字列（"Type.Method"・"(*Type).Method"・"Func()" を含む）
+const definitionName = "`?" + `([A-Za-z_(*][\w.*()]*)` + "`?"
+
+// definitionKinds は質問文でシンボル名の前後に付く種類の表現
+const definitionKinds = `(?:function|func|method|type|struct|class|interface|constant|const|variable|var)`
+
+// definitionPatterns は定義位置を尋ねる質問のパターン（1番目のグループがシンボル名）
+var definitionPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)^\s*where\s+(?:is|are)\s+(?:the\s+)?(?:` + definitionKinds + `\s+)?` + definitionName + `(?:\s+` + definitionKinds + `)?\s+(?:defined|declared|implemented)\s*\??\s*$`),
+	regexp.MustCompile(`(?i)^\s*where\s+is\s+the\s+definition\s+of\s+(?:the\s+)?(?:` + definitionKinds + `\s+)?` + definitionName + `\s*\??\s*$`),
+	regexp.MustCompile(`^\s*` + definitionName + `\s*(?:関数|メソッド|型|構造体|クラス|インターフェース|定数|変数)?\s*(?:は|の定義は)\s*どこ(?:で定義されて(?:い)?(?:ます|る)か?|にあ(?:ります|る)か?|ですか?)?\s*[？?]?\s*$`),
+}
+
+// ParseDefinitionQuestion は「X はどこで定義されていますか」「where is X defined?」のような
+// 定義位置だけを尋ねる質問からシンボル名を取り出す
+func ParseDefinitionQuestion(question string) (string, bool) {
+	for _, pattern := range definitionPatterns {
+		if m := pattern.FindStringSubmatch(question); m != nil {
+			if q := ParseQuery(m[1]); q.Name != "" {
+				return m[1], true
+			}
+		}
+	}
+	return "", false
+}
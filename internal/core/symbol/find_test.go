@@ -0,0 +1,76 @@
+package symbol
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseQuery(t *testing.T) {
+	assert.Equal(t, Query{Name: "IndexSource"}, ParseQuery("IndexSource"))
+	assert.Equal(t, Query{Name: "IndexSource"}, ParseQuery("`IndexSource()`"))
+	assert.Equal(t, Query{Name: "IndexSource", ParentName: "IndexService"}, ParseQuery("IndexService.IndexSource"))
+	assert.Equal(t, Query{Name: "IndexSource", ParentName: "IndexService"}, ParseQuery("(*IndexService).IndexSource"))
+}
+
+func TestParseDefinitionQuestion(t *testing.T) {
+	tests := []struct {
+		question string
+		want     string
+		ok       bool
+	}{
+		{"where is IndexSource defined?", "IndexSource", true},
+		{"Where is the function NewIndexService defined", "NewIndexService", true},
+		{"where is the Chunker interface declared?", "Chunker", true},
+		{"where is the definition of IndexService.IndexSource?", "IndexService.IndexSource", true},
+		{"IndexSource はどこで定義されていますか？", "IndexSource", true},
+		{"`NewIndexService` 関数はどこにありますか", "NewIndexService", true},
+		{"IndexService.IndexSource の定義はどこ？", "IndexService.IndexSource", true},
+		{"how does IndexSource handle errors?", "", false},
+		{"where is the retry policy defined for payments?", "", false},
+		{"決済のリトライ方針はどこで定義されていますか？", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.question, func(t *testing.T) {
+			got, ok := ParseDefinitionQuestion(tt.question)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+type stubReader struct {
+	symbols map[string][]*Symbol
+	queries []Query
+}
+
+func (r *stubReader) FindSymbols(_ context.Context, _, _ mo.Option[uuid.UUID], query Query) ([]*Symbol, error) {
+	r.queries = append(r.queries, query)
+	return r.symbols[query.ParentName+"."+query.Name], nil
+}
+
+func TestFind(t *testing.T) {
+	newService := &Symbol{Name: "NewIndexService", Kind: "function"}
+	reader := &stubReader{symbols: map[string][]*Symbol{".NewIndexService": {newService}}}
+
+	t.Run("パッケージ名で修飾した名前はシンボル名のみで検索し直す", func(t *testing.T) {
+		symbols, err := Find(context.Background(), reader, mo.Some(uuid.New()), mo.None[uuid.UUID](), "ingestion.NewIndexService", "", 5)
+		require.NoError(t, err)
+		assert.Equal(t, []*Symbol{newService}, symbols)
+		require.Len(t, reader.queries, 2)
+		assert.Equal(t, Query{Name: "NewIndexService", ParentName: "ingestion", Limit: 5}, reader.queries[0])
+		assert.Equal(t, Query{Name: "NewIndexService", Limit: 5}, reader.queries[1])
+	})
+
+	t.Run("空の名前は検索しない", func(t *testing.T) {
+		reader.queries = nil
+		symbols, err := Find(context.Background(), reader, mo.None[uuid.UUID](), mo.None[uuid.UUID](), " () ", "", 5)
+		require.NoError(t, err)
+		assert.Empty(t, symbols)
+		assert.Empty(t, reader.queries)
+	})
+}
@@ -0,0 +1,40 @@
+// Package symbol はチャンクのメタデータから抽出したシンボル（関数・メソッド・型など）の定義位置を名前の完全一致で検索する
+package symbol
+
+import (
+	"github.com/google/uuid"
+)
+
+// DefaultLimit は検索するシンボルの既定の上限
+const DefaultLimit = 20
+
+// Symbol はインデックス化したチャンクから抽出したシンボルの定義位置
+type Symbol struct {
+	ID         uuid.UUID
+	SnapshotID uuid.UUID
+	ChunkID    uuid.UUID
+	FileID     uuid.UUID
+	SourceName string // 取得時のみ設定される
+	FilePath   string
+	Name       string
+	Kind       string // function / method / struct / interface など（チャンクの種類）
+	ParentName string // メソッドのレシーバの型名など（ポインタの * は除く）
+	StartLine  int
+	EndLine    int
+}
+
+// QualifiedName は所属する型名を付けたシンボル名を返す（例: IndexService.IndexSource）
+func (s *Symbol) QualifiedName() string {
+	if s.ParentName == "" {
+		return s.Name
+	}
+	return s.ParentName + "." + s.Name
+}
+
+// Query はシンボルの検索条件
+type Query struct {
+	Name       string // シンボル名（大文字小文字を区別しない完全一致、区別して一致するものを優先）
+	ParentName string // 所属する型名（空の場合は絞り込まない）
+	Kind       string // シンボルの種類（空の場合はすべての種類）
+	Limit      int    // 取得件数の上限（0 以下の場合は DefaultLimit）
+}
@@ -0,0 +1,15 @@
+package symbol
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+)
+
+// Reader はインデックス済みのシンボルを読み取るインターフェース
+type Reader interface {
+	// FindSymbols は名前の完全一致でシンボルを検索する
+	// product_id 指定時はプロダクトの各ソースの最新スナップショット、snapshot_id 指定時はそのスナップショットを対象とする
+	FindSymbols(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID], query Query) ([]*Symbol, error)
+}
@@ -0,0 +1,143 @@
+package testcoverage
+
+import (
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FileCoverage はスナップショットのファイルのテストカバレッジ
+type FileCoverage struct {
+	FileID     uuid.UUID `json:"fileID"`
+	SnapshotID uuid.UUID `json:"snapshotID"`
+	SourceName string    `json:"sourceName,omitempty"`
+	FilePath   string    `json:"path"`
+	Statements int       `json:"statements"` // 計測対象のステートメント数（LCOV は行数）
+	Covered    int       `json:"covered"`    // テストで実行されたステートメント数
+	Format     Format    `json:"format"`
+	ImportedAt time.Time `json:"importedAt"`
+}
+
+// Percent はカバレッジ率（%）を返す
+func (c *FileCoverage) Percent() float64 {
+	return Percent(c.Covered, c.Statements)
+}
+
+// ChunkCoverage は関数・クラス単位のチャンクのテストカバレッジ
+type ChunkCoverage struct {
+	ChunkID    uuid.UUID
+	Statements int
+	Covered    int
+}
+
+// TargetFile はカバレッジの取り込み先のスナップショットのファイル
+type TargetFile struct {
+	FileID uuid.UUID
+	Path   string
+	Chunks []TargetChunk // 関数・クラス単位のチャンク
+}
+
+// TargetChunk はカバレッジの取り込み先のチャンクの行範囲
+type TargetChunk struct {
+	ChunkID   uuid.UUID
+	StartLine int
+	EndLine   int
+}
+
+// Percent はカバレッジ率（%）を返す（計測対象がない場合は 0）
+func Percent(covered, statements int) float64 {
+	if statements <= 0 {
+		return 0
+	}
+	return float64(covered) / float64(statements) * 100
+}
+
+// Mapping はカバレッジレポートをスナップショットのファイルに対応付けた結果
+type Mapping struct {
+	Files     []*FileCoverage
+	Chunks    []*ChunkCoverage
+	Unmatched []string // スナップショットのファイルに対応付けられなかったレポートのパス
+}
+
+// Map はカバレッジレポートのパスをスナップショットのファイルに対応付け、ファイル・チャンクごとに集計する
+// レポートのパスはモジュールパス（Go）や絶対パス（LCOV）を含むため、末尾がファイルパスと一致するもののうち最も長く一致するファイルに対応付ける
+// チャンクには開始行がチャンクの行範囲に含まれる計測単位を集計する
+func Map(profile *Profile, targets []*TargetFile) *Mapping {
+	byBase := make(map[string][]*TargetFile)
+	for _, t := range targets {
+		base := path.Base(t.Path)
+		byBase[base] = append(byBase[base], t)
+	}
+
+	mapping := &Mapping{Files: []*FileCoverage{}, Chunks: []*ChunkCoverage{}, Unmatched: []string{}}
+	merged := make(map[uuid.UUID][]Block)
+	var matched []*TargetFile
+	for reportPath, blocks := range profile.Files {
+		target := matchTarget(normalizePath(reportPath), byBase)
+		if target == nil {
+			mapping.Unmatched = append(mapping.Unmatched, reportPath)
+			continue
+		}
+		if _, ok := merged[target.FileID]; !ok {
+			matched = append(matched, target)
+		}
+		merged[target.FileID] = append(merged[target.FileID], blocks...)
+	}
+	sort.Strings(mapping.Unmatched)
+	sort.Slice(matched, func(i, j int) bool {
+		return matched[i].Path < matched[j].Path
+	})
+
+	for _, target := range matched {
+		blocks := merged[target.FileID]
+		file := &FileCoverage{FileID: target.FileID, FilePath: target.Path, Format: profile.Format}
+		file.Statements, file.Covered = count(blocks, 0, 0)
+		mapping.Files = append(mapping.Files, file)
+
+		for _, chunk := range target.Chunks {
+			statements, covered := count(blocks, chunk.StartLine, chunk.EndLine)
+			if statements == 0 {
+				continue
+			}
+			mapping.Chunks = append(mapping.Chunks, &ChunkCoverage{ChunkID: chunk.ChunkID, Statements: statements, Covered: covered})
+		}
+	}
+	return mapping
+}
+
+// count は開始行が [startLine, endLine] に含まれる計測単位のステートメント数と実行されたステートメント数を返す（endLine が 0 の場合は全件）
+func count(blocks []Block, startLine, endLine int) (statements, covered int) {
+	for _, b := range blocks {
+		if endLine > 0 && (b.StartLine < startLine || b.StartLine > endLine) {
+			continue
+		}
+		statements += b.Statements
+		if b.Count > 0 {
+			covered += b.Statements
+		}
+	}
+	return statements, covered
+}
+
+// matchTarget はレポートのパスの末尾と一致するファイルのうち、最も長く一致するものを返す
+func matchTarget(reportPath string, byBase map[string][]*TargetFile) *TargetFile {
+	var best *TargetFile
+	for _, t := range byBase[path.Base(reportPath)] {
+		if reportPath != t.Path && !strings.HasSuffix(reportPath, "/"+t.Path) {
+			continue
+		}
+		if best == nil || len(t.Path) > len(best.Path) {
+			best = t
+		}
+	}
+	return best
+}
+
+// normalizePath はレポートのパスの区切り文字と先頭の "./" を正規化する
+func normalizePath(p string) string {
+	p = strings.ReplaceAll(p, `\`, "/")
+	return strings.TrimPrefix(p, "./")
+}
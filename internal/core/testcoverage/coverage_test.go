@@ -0,0 +1,89 @@
+package testcoverage
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMap(t *testing.T) {
+	profile, err := Parse(strings.NewReader(goProfile), FormatGo)
+	require.NoError(t, err)
+
+	getID, updateID := uuid.New(), uuid.New()
+	service := &TargetFile{FileID: uuid.New(), Path: "internal/user/service.go", Chunks: []TargetChunk{
+		{ChunkID: getID, StartLine: 9, EndLine: 12},
+		{ChunkID: updateID, StartLine: 13, EndLine: 20},
+		{ChunkID: uuid.New(), StartLine: 30, EndLine: 40},
+	}}
+	// 末尾がより短く一致するファイルには対応付けない
+	shorter := &TargetFile{FileID: uuid.New(), Path: "user/service.go"}
+
+	mapping := Map(profile, []*TargetFile{shorter, service})
+
+	require.Len(t, mapping.Files, 1)
+	assert.Equal(t, service.FileID, mapping.Files[0].FileID)
+	assert.Equal(t, 5, mapping.Files[0].Statements)
+	assert.Equal(t, 5, mapping.Files[0].Covered)
+	assert.Equal(t, FormatGo, mapping.Files[0].Format)
+
+	require.Len(t, mapping.Chunks, 2, "計測単位のないチャンクは記録しない")
+	assert.Equal(t, &ChunkCoverage{ChunkID: getID, Statements: 2, Covered: 2}, mapping.Chunks[0])
+	assert.Equal(t, &ChunkCoverage{ChunkID: updateID, Statements: 3, Covered: 3}, mapping.Chunks[1])
+
+	assert.Equal(t, []string{"github.com/acme/app/cmd/main.go"}, mapping.Unmatched)
+}
+
+type stubRepository struct {
+	targets []*TargetFile
+	files   []*FileCoverage
+	chunks  []*ChunkCoverage
+}
+
+func (r *stubRepository) ListCoverageTargets(ctx context.Context, snapshotID uuid.UUID) ([]*TargetFile, error) {
+	return r.targets, nil
+}
+
+func (r *stubRepository) ReplaceCoverage(ctx context.Context, snapshotID uuid.UUID, files []*FileCoverage, chunks []*ChunkCoverage) error {
+	r.files, r.chunks = files, chunks
+	return nil
+}
+
+func (r *stubRepository) ListFileCoverage(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID]) ([]*FileCoverage, error) {
+	return r.files, nil
+}
+
+func TestService_Import(t *testing.T) {
+	profile, err := Parse(strings.NewReader(lcovReport), FormatLCOV)
+	require.NoError(t, err)
+
+	snapshotID := uuid.New()
+	repo := &stubRepository{targets: []*TargetFile{
+		{FileID: uuid.New(), Path: "src/app.ts", Chunks: []TargetChunk{{ChunkID: uuid.New(), StartLine: 1, EndLine: 2}}},
+		{FileID: uuid.New(), Path: "src/util.ts"},
+	}}
+	svc := NewService(repo)
+
+	result, err := svc.Import(context.Background(), snapshotID, profile)
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.MatchedFiles)
+	assert.Equal(t, 1, result.Chunks)
+	assert.Equal(t, 4, result.Statements)
+	assert.Equal(t, 2, result.Covered)
+	assert.InDelta(t, 50.0, result.Percent(), 1e-9)
+	assert.Empty(t, result.Unmatched)
+
+	require.Len(t, repo.files, 2)
+	assert.Equal(t, snapshotID, repo.files[0].SnapshotID)
+
+	// 1件も対応付けられない場合は既存のカバレッジを残す
+	repo.targets = []*TargetFile{{FileID: uuid.New(), Path: "other.ts"}}
+	_, err = svc.Import(context.Background(), snapshotID, profile)
+	assert.Error(t, err)
+	assert.Len(t, repo.files, 2)
+}
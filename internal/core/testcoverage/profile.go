@@ -0,0 +1,201 @@
+// Package testcoverage はテストのカバレッジレポート（Go の coverage.out・LCOV）を解析し、
+// スナップショットのファイル・関数単位のチャンクのカバレッジとして取り込む
+package testcoverage
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Format はカバレッジレポートの形式
+type Format string
+
+const (
+	FormatGo   Format = "go"   // go test -coverprofile の出力
+	FormatLCOV Format = "lcov" // LCOV のトレースファイル（lcov.info）
+)
+
+// Formats はカバレッジレポートの形式の一覧
+var Formats = []Format{FormatGo, FormatLCOV}
+
+// Block はカバレッジの計測単位（Go はステートメントのブロック、LCOV は行）
+type Block struct {
+	StartLine  int
+	EndLine    int
+	Statements int // 計測単位に含まれるステートメント数（LCOV は 1）
+	Count      int // 実行回数
+}
+
+// Profile は解析したカバレッジレポート
+type Profile struct {
+	Format Format
+	Files  map[string][]Block // レポートに記載されたパスから計測単位へのマップ（開始行順）
+}
+
+// DetectFormat はカバレッジレポートの内容から形式を判定する
+func DetectFormat(data []byte) (Format, error) {
+	trimmed := bytes.TrimLeft(data, " \t\r\n")
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("mode:")):
+		return FormatGo, nil
+	case bytes.HasPrefix(trimmed, []byte("TN:")), bytes.HasPrefix(trimmed, []byte("SF:")):
+		return FormatLCOV, nil
+	default:
+		return "", fmt.Errorf("unknown coverage report format (expected go coverprofile or lcov)")
+	}
+}
+
+// Load はカバレッジレポートのファイルを読み込んで解析する（format が空の場合は内容から判定する）
+func Load(path string, format Format) (*Profile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read coverage report: %w", err)
+	}
+	if format == "" {
+		if format, err = DetectFormat(data); err != nil {
+			return nil, err
+		}
+	}
+	return Parse(bytes.NewReader(data), format)
+}
+
+// Parse はカバレッジレポートを解析する
+// 同じ計測単位が複数回現れる場合（複数のパッケージのプロファイルを結合した場合など）は実行回数の最大値を採用する
+func Parse(r io.Reader, format Format) (*Profile, error) {
+	var (
+		blocks map[string]map[[2]int]Block
+		err    error
+	)
+	switch format {
+	case FormatGo:
+		blocks, err = parseGoProfile(r)
+	case FormatLCOV:
+		blocks, err = parseLCOV(r)
+	default:
+		return nil, fmt.Errorf("unsupported coverage format: %s", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	profile := &Profile{Format: format, Files: make(map[string][]Block, len(blocks))}
+	for path, byRange := range blocks {
+		list := make([]Block, 0, len(byRange))
+		for _, b := range byRange {
+			list = append(list, b)
+		}
+		sort.Slice(list, func(i, j int) bool {
+			if list[i].StartLine != list[j].StartLine {
+				return list[i].StartLine < list[j].StartLine
+			}
+			return list[i].EndLine < list[j].EndLine
+		})
+		profile.Files[path] = list
+	}
+	return profile, nil
+}
+
+// parseGoProfile は go test -coverprofile の出力を解析する
+// 各行は「ファイル:開始行.開始列,終了行.終了列 ステートメント数 実行回数」
+func parseGoProfile(r io.Reader) (map[string]map[[2]int]Block, error) {
+	blocks := make(map[string]map[[2]int]Block)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "mode:") {
+			continue
+		}
+		colon := strings.LastIndex(line, ":")
+		if colon < 0 {
+			return nil, fmt.Errorf("line %d: invalid go coverprofile entry: %q", lineNo, line)
+		}
+		path := line[:colon]
+		fields := strings.Fields(line[colon+1:])
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("line %d: invalid go coverprofile entry: %q", lineNo, line)
+		}
+		start, end, ok := strings.Cut(fields[0], ",")
+		if !ok {
+			return nil, fmt.Errorf("line %d: invalid block range: %q", lineNo, fields[0])
+		}
+		startLine, err1 := lineOf(start)
+		endLine, err2 := lineOf(end)
+		statements, err3 := strconv.Atoi(fields[1])
+		count, err4 := strconv.Atoi(fields[2])
+		if err1 != nil || err2 != nil || err3 != nil || err4 != nil {
+			return nil, fmt.Errorf("line %d: invalid go coverprofile entry: %q", lineNo, line)
+		}
+		addBlock(blocks, path, Block{StartLine: startLine, EndLine: endLine, Statements: statements, Count: count})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read go coverprofile: %w", err)
+	}
+	return blocks, nil
+}
+
+// lineOf は「行.列」から行番号を取り出す
+func lineOf(position string) (int, error) {
+	line, _, _ := strings.Cut(position, ".")
+	return strconv.Atoi(line)
+}
+
+// parseLCOV は LCOV のトレースファイルを解析する（行の実行回数 DA のみを使用する）
+func parseLCOV(r io.Reader) (map[string]map[[2]int]Block, error) {
+	blocks := make(map[string]map[[2]int]Block)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	var current string
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "SF:"):
+			current = strings.TrimPrefix(line, "SF:")
+		case line == "end_of_record":
+			current = ""
+		case strings.HasPrefix(line, "DA:"):
+			if current == "" {
+				return nil, fmt.Errorf("line %d: DA record outside of a source file record", lineNo)
+			}
+			// DA:<行番号>,<実行回数>[,<チェックサム>]
+			parts := strings.Split(strings.TrimPrefix(line, "DA:"), ",")
+			if len(parts) < 2 {
+				return nil, fmt.Errorf("line %d: invalid DA record: %q", lineNo, line)
+			}
+			lineNumber, err1 := strconv.Atoi(parts[0])
+			count, err2 := strconv.Atoi(parts[1])
+			if err1 != nil || err2 != nil {
+				return nil, fmt.Errorf("line %d: invalid DA record: %q", lineNo, line)
+			}
+			addBlock(blocks, current, Block{StartLine: lineNumber, EndLine: lineNumber, Statements: 1, Count: count})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read lcov report: %w", err)
+	}
+	return blocks, nil
+}
+
+func addBlock(blocks map[string]map[[2]int]Block, path string, b Block) {
+	byRange, ok := blocks[path]
+	if !ok {
+		byRange = make(map[[2]int]Block)
+		blocks[path] = byRange
+	}
+	key := [2]int{b.StartLine, b.EndLine}
+	if existing, ok := byRange[key]; ok {
+		b.Count = max(b.Count, existing.Count)
+		b.Statements = max(b.Statements, existing.Statements)
+	}
+	byRange[key] = b
+}
@@ -0,0 +1,70 @@
+package testcoverage
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const goProfile = `mode: set
+github.com/acme/app/internal/user/service.go:10.30,12.2 2 1
+github.com/acme/app/internal/user/service.go:14.2,16.3 3 0
+github.com/acme/app/internal/user/service.go:14.2,16.3 3 1
+github.com/acme/app/cmd/main.go:5.13,7.2 1 0
+`
+
+const lcovReport = `TN:
+SF:/home/ci/app/src/app.ts
+DA:1,1
+DA:2,0
+DA:3,4
+end_of_record
+SF:src/util.ts
+DA:10,0
+end_of_record
+`
+
+func TestDetectFormat(t *testing.T) {
+	format, err := DetectFormat([]byte(goProfile))
+	require.NoError(t, err)
+	assert.Equal(t, FormatGo, format)
+
+	format, err = DetectFormat([]byte(lcovReport))
+	require.NoError(t, err)
+	assert.Equal(t, FormatLCOV, format)
+
+	_, err = DetectFormat([]byte("<coverage></coverage>"))
+	assert.Error(t, err)
+}
+
+func TestParse_Go(t *testing.T) {
+	profile, err := Parse(strings.NewReader(goProfile), FormatGo)
+	require.NoError(t, err)
+
+	assert.Equal(t, FormatGo, profile.Format)
+	require.Len(t, profile.Files, 2)
+	assert.Equal(t, []Block{
+		{StartLine: 10, EndLine: 12, Statements: 2, Count: 1},
+		{StartLine: 14, EndLine: 16, Statements: 3, Count: 1},
+	}, profile.Files["github.com/acme/app/internal/user/service.go"], "同じブロックは実行回数の最大値を採用する")
+
+	_, err = Parse(strings.NewReader("mode: set\nbroken line\n"), FormatGo)
+	assert.Error(t, err)
+}
+
+func TestParse_LCOV(t *testing.T) {
+	profile, err := Parse(strings.NewReader(lcovReport), FormatLCOV)
+	require.NoError(t, err)
+
+	require.Len(t, profile.Files, 2)
+	assert.Equal(t, []Block{
+		{StartLine: 1, EndLine: 1, Statements: 1, Count: 1},
+		{StartLine: 2, EndLine: 2, Statements: 1, Count: 0},
+		{StartLine: 3, EndLine: 3, Statements: 1, Count: 4},
+	}, profile.Files["/home/ci/app/src/app.ts"])
+
+	_, err = Parse(strings.NewReader("DA:1,1\n"), FormatLCOV)
+	assert.Error(t, err, "SF のない DA はエラー")
+}
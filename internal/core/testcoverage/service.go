@@ -0,0 +1,120 @@
+package testcoverage
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+)
+
+// Repository はテストカバレッジの永続化インターフェース
+type Repository interface {
+	// ListCoverageTargets はスナップショットのファイルと関数・クラス単位のチャンクの行範囲を取得する
+	ListCoverageTargets(ctx context.Context, snapshotID uuid.UUID) ([]*TargetFile, error)
+	// ReplaceCoverage はスナップショットのテストカバレッジを置き換える（既存のカバレッジは削除する）
+	ReplaceCoverage(ctx context.Context, snapshotID uuid.UUID, files []*FileCoverage, chunks []*ChunkCoverage) error
+	// ListFileCoverage はファイルごとのテストカバレッジをソース名・パス順に取得する
+	// product_id 指定時はプロダクトの各ソースの最新のインデックス済みスナップショット、snapshot_id 指定時はそのスナップショットを対象とする
+	ListFileCoverage(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID]) ([]*FileCoverage, error)
+}
+
+// ImportResult はカバレッジレポートの取り込み結果
+type ImportResult struct {
+	SnapshotID   uuid.UUID `json:"snapshotID"`
+	Format       Format    `json:"format"`
+	ReportFiles  int       `json:"reportFiles"`  // レポートに記載されたファイル数
+	MatchedFiles int       `json:"matchedFiles"` // スナップショットのファイルに対応付けたファイル数
+	Chunks       int       `json:"chunks"`       // カバレッジを記録したチャンク数
+	Statements   int       `json:"statements"`
+	Covered      int       `json:"covered"`
+	Unmatched    []string  `json:"unmatched"` // 対応付けられなかったレポートのパス
+}
+
+// Percent は対応付けたファイル全体のカバレッジ率（%）を返す
+func (r *ImportResult) Percent() float64 {
+	return Percent(r.Covered, r.Statements)
+}
+
+// Service はカバレッジレポートの取り込みを行う
+type Service struct {
+	repo   Repository
+	logger *slog.Logger
+}
+
+// ServiceOption は Service のオプション設定
+type ServiceOption func(*Service)
+
+// WithServiceLogger は Service にロガーを設定する
+func WithServiceLogger(logger *slog.Logger) ServiceOption {
+	return func(s *Service) {
+		s.logger = logger
+	}
+}
+
+// NewService は新しい Service を作成する
+func NewService(repo Repository, opts ...ServiceOption) *Service {
+	svc := &Service{
+		repo:   repo,
+		logger: slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(svc)
+	}
+	if svc.logger == nil {
+		svc.logger = slog.Default()
+	}
+	return svc
+}
+
+// Import はカバレッジレポートをスナップショットのファイル・チャンクに対応付けて記録する
+// スナップショットの既存のカバレッジは置き換える。1件も対応付けられない場合はエラーとし、既存のカバレッジを残す
+func (s *Service) Import(ctx context.Context, snapshotID uuid.UUID, profile *Profile) (*ImportResult, error) {
+	targets, err := s.repo.ListCoverageTargets(ctx, snapshotID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list coverage targets: %w", err)
+	}
+
+	mapping := Map(profile, targets)
+	if len(profile.Files) > 0 && len(mapping.Files) == 0 {
+		return nil, fmt.Errorf("no file in the coverage report matches the snapshot (e.g. %s)", mapping.Unmatched[0])
+	}
+	for _, f := range mapping.Files {
+		f.SnapshotID = snapshotID
+	}
+	if err := s.repo.ReplaceCoverage(ctx, snapshotID, mapping.Files, mapping.Chunks); err != nil {
+		return nil, fmt.Errorf("failed to replace test coverage: %w", err)
+	}
+
+	result := &ImportResult{
+		SnapshotID:   snapshotID,
+		Format:       profile.Format,
+		ReportFiles:  len(profile.Files),
+		MatchedFiles: len(mapping.Files),
+		Chunks:       len(mapping.Chunks),
+		Unmatched:    mapping.Unmatched,
+	}
+	for _, f := range mapping.Files {
+		result.Statements += f.Statements
+		result.Covered += f.Covered
+	}
+	s.logger.Info("imported test coverage",
+		"snapshot_id", snapshotID,
+		"format", profile.Format,
+		"files", result.MatchedFiles,
+		"unmatched", len(result.Unmatched),
+		"chunks", result.Chunks,
+		"coverage", fmt.Sprintf("%.1f%%", result.Percent()),
+	)
+	return result, nil
+}
+
+// ImportFile はカバレッジレポートのファイルを読み込んでスナップショットに記録する（format が空の場合は内容から判定する）
+func (s *Service) ImportFile(ctx context.Context, snapshotID uuid.UUID, path string, format Format) (*ImportResult, error) {
+	profile, err := Load(path, format)
+	if err != nil {
+		return nil, err
+	}
+	return s.Import(ctx, snapshotID, profile)
+}
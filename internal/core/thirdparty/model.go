@@ -0,0 +1,87 @@
+package thirdparty
+
+import "github.com/google/uuid"
+
+// Ecosystem は依存関係を管理する仕組み（パッケージマネージャ・サブモジュール）
+type Ecosystem string
+
+const (
+	EcosystemGo        Ecosystem = "go"        // go.mod
+	EcosystemNPM       Ecosystem = "npm"       // package.json
+	EcosystemPyPI      Ecosystem = "pypi"      // requirements.txt
+	EcosystemSubmodule Ecosystem = "submodule" // .gitmodules
+)
+
+// Ecosystems は対応するエコシステムの一覧（表示順）
+var Ecosystems = []Ecosystem{EcosystemGo, EcosystemNPM, EcosystemPyPI, EcosystemSubmodule}
+
+// Kind は依存関係の種類
+type Kind string
+
+const (
+	KindDirect   Kind = "direct"   // 直接の依存
+	KindIndirect Kind = "indirect" // 間接的な依存（go.mod の // indirect）
+	KindDev      Kind = "dev"      // 開発時のみの依存（package.json の devDependencies）
+)
+
+// Dependency はマニフェストに記述された外部の依存関係
+type Dependency struct {
+	SnapshotID   uuid.UUID
+	SourceName   string // 取得時のみ設定する
+	Version      string // スナップショットのバージョン識別子（取得時のみ設定する）
+	ManifestPath string // 依存関係を記述したファイルのパス
+	Ecosystem    Ecosystem
+	Name         string // モジュールパス・パッケージ名（サブモジュールは配置するパス）
+	Requirement  string // 要求するバージョン（サブモジュールは追跡するブランチ。指定がない場合は空）
+	Kind         Kind
+	Vendored     bool   // vendor/modules.txt でベンダリングしているか（Go のみ）
+	Location     string // 取得元（サブモジュールの URL、go.mod の replace の置き換え先）
+}
+
+// key はソース内でスナップショット間の同じ依存関係を対応付けるキーを返す
+func (d *Dependency) key() string {
+	return d.ManifestPath + "\x00" + string(d.Ecosystem) + "\x00" + d.Name
+}
+
+// ChangeKind はスナップショット間の依存関係の変化の種類
+type ChangeKind string
+
+const (
+	ChangeAdded   ChangeKind = "added"   // 追加
+	ChangeRemoved ChangeKind = "removed" // 削除
+	ChangeUpdated ChangeKind = "updated" // 要求するバージョンの変更
+)
+
+// Change は直前のインデックス済みスナップショットからの依存関係の変化
+type Change struct {
+	SourceName   string
+	ManifestPath string
+	Ecosystem    Ecosystem
+	Name         string
+	Kind         ChangeKind
+	From         string // 変更前の要求するバージョン（追加の場合は空）
+	To           string // 変更後の要求するバージョン（削除の場合は空）
+}
+
+// SnapshotPair は対象のスナップショットと、同じソースの直前のインデックス済みスナップショット
+type SnapshotPair struct {
+	SourceName     string
+	FromSnapshotID uuid.UUID
+	FromVersion    string
+	ToSnapshotID   uuid.UUID
+	ToVersion      string
+}
+
+// Comparison はソースの依存関係を直前のインデックス済みスナップショットと比較した結果
+type Comparison struct {
+	SourceName  string
+	FromVersion string // 直前のスナップショットのバージョン識別子
+	ToVersion   string // 対象のスナップショットのバージョン識別子
+	Changes     []*Change
+}
+
+// Report はプロダクトの外部の依存関係とバージョンの変化
+type Report struct {
+	Dependencies []*Dependency // ソース名・マニフェストのパス・名前順
+	Comparisons  []*Comparison // 直前のスナップショットがあるソースのみ（ソース名順）
+}
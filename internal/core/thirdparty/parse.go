@@ -0,0 +1,253 @@
+package thirdparty
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+// IsManifest は依存関係を記述したファイル（go.mod・package.json・requirements*.txt・.gitmodules）か、
+// Go のベンダリングの記録（vendor/modules.txt）かを判定する
+func IsManifest(p string) bool {
+	base := path.Base(p)
+	switch {
+	case base == "go.mod", p == ".gitmodules":
+		return true
+	case base == "package.json":
+		return !slices.Contains(strings.Split(p, "/"), "node_modules")
+	case strings.HasPrefix(base, "requirements") && strings.HasSuffix(base, ".txt"):
+		return true
+	}
+	return IsVendorModules(p)
+}
+
+// IsVendorModules は Go のベンダリングの記録（vendor/modules.txt）かを判定する
+func IsVendorModules(p string) bool {
+	return path.Base(p) == "modules.txt" && path.Base(path.Dir(p)) == "vendor"
+}
+
+// Parse はマニフェストから依存関係を抽出する（マニフェストでないファイル・vendor/modules.txt は空を返す）
+func Parse(p, content string) ([]*Dependency, error) {
+	base := path.Base(p)
+	switch {
+	case base == "go.mod":
+		return parseGoMod(p, content)
+	case p == ".gitmodules":
+		return parseGitmodules(p, content), nil
+	case base == "package.json":
+		return parsePackageJSON(p, content)
+	case strings.HasPrefix(base, "requirements") && strings.HasSuffix(base, ".txt"):
+		return parseRequirements(p, content), nil
+	}
+	return nil, nil
+}
+
+// Collect はスナップショットのマニフェスト（パスと内容）から依存関係を抽出し、マニフェストのパス・名前順に返す
+// vendor/modules.txt に記録されたモジュールは同じディレクトリの go.mod の依存関係をベンダリング済みとする
+// 解析できなかったマニフェストはスキップし、エラーをまとめて返す
+func Collect(files map[string]string) ([]*Dependency, error) {
+	vendored := make(map[string]map[string]bool) // go.mod のディレクトリ -> ベンダリングしたモジュール
+	var deps []*Dependency
+	var errs []error
+	for p, content := range files {
+		if IsVendorModules(p) {
+			vendored[path.Dir(path.Dir(p))] = parseVendorModules(content)
+			continue
+		}
+		parsed, err := Parse(p, content)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", p, err))
+			continue
+		}
+		deps = append(deps, parsed...)
+	}
+
+	for _, d := range deps {
+		if d.Ecosystem == EcosystemGo {
+			d.Vendored = vendored[path.Dir(d.ManifestPath)][d.Name]
+		}
+	}
+	sort.Slice(deps, func(i, j int) bool {
+		if deps[i].ManifestPath != deps[j].ManifestPath {
+			return deps[i].ManifestPath < deps[j].ManifestPath
+		}
+		return deps[i].Name < deps[j].Name
+	})
+	return deps, errors.Join(errs...)
+}
+
+// parseGoMod は go.mod の require から依存関係を抽出する（replace の置き換え先を取得元に記録する）
+func parseGoMod(p, content string) ([]*Dependency, error) {
+	file, err := modfile.Parse(p, []byte(content), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse go.mod: %w", err)
+	}
+
+	replaces := make(map[string]string, len(file.Replace))
+	for _, r := range file.Replace {
+		replaces[r.Old.Path] = strings.TrimSpace(r.New.Path + " " + r.New.Version)
+	}
+
+	deps := make([]*Dependency, 0, len(file.Require))
+	for _, r := range file.Require {
+		kind := KindDirect
+		if r.Indirect {
+			kind = KindIndirect
+		}
+		deps = append(deps, &Dependency{
+			ManifestPath: p,
+			Ecosystem:    EcosystemGo,
+			Name:         r.Mod.Path,
+			Requirement:  r.Mod.Version,
+			Kind:         kind,
+			Location:     replaces[r.Mod.Path],
+		})
+	}
+	return deps, nil
+}
+
+// parseVendorModules は vendor/modules.txt のモジュール行（# <module> <version>）からベンダリングしたモジュールを抽出する
+func parseVendorModules(content string) map[string]bool {
+	modules := make(map[string]bool)
+	for _, line := range strings.Split(content, "\n") {
+		if !strings.HasPrefix(line, "# ") {
+			continue
+		}
+		if fields := strings.Fields(strings.TrimPrefix(line, "# ")); len(fields) > 0 {
+			modules[fields[0]] = true
+		}
+	}
+	return modules
+}
+
+// parsePackageJSON は package.json の dependencies・optionalDependencies・peerDependencies・devDependencies から依存関係を抽出する
+// 複数に記述されたパッケージは先に記述した種類（devDependencies は最後）を使用する
+func parsePackageJSON(p, content string) ([]*Dependency, error) {
+	var manifest struct {
+		Dependencies         map[string]string `json:"dependencies"`
+		OptionalDependencies map[string]string `json:"optionalDependencies"`
+		PeerDependencies     map[string]string `json:"peerDependencies"`
+		DevDependencies      map[string]string `json:"devDependencies"`
+	}
+	if err := json.Unmarshal([]byte(content), &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse package.json: %w", err)
+	}
+
+	var deps []*Dependency
+	seen := make(map[string]bool)
+	add := func(packages map[string]string, kind Kind) {
+		names := make([]string, 0, len(packages))
+		for name := range packages {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			deps = append(deps, &Dependency{
+				ManifestPath: p,
+				Ecosystem:    EcosystemNPM,
+				Name:         name,
+				Requirement:  packages[name],
+				Kind:         kind,
+			})
+		}
+	}
+	add(manifest.Dependencies, KindDirect)
+	add(manifest.OptionalDependencies, KindDirect)
+	add(manifest.PeerDependencies, KindDirect)
+	add(manifest.DevDependencies, KindDev)
+	return deps, nil
+}
+
+// requirementPattern は requirements.txt の要求（名前・extras・バージョン指定）にマッチする
+var requirementPattern = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9._-]*)\s*(?:\[[^\]]*\])?\s*(.*)$`)
+
+// parseRequirements は requirements.txt の各行から依存関係を抽出する
+// オプション（-r・-e など）の行は除き、ファイル名に dev・test を含む場合は開発時のみの依存とする
+func parseRequirements(p, content string) []*Dependency {
+	kind := KindDirect
+	if base := path.Base(p); strings.Contains(base, "dev") || strings.Contains(base, "test") {
+		kind = KindDev
+	}
+
+	var deps []*Dependency
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, " #"); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "-") {
+			continue
+		}
+		// 環境マーカー（; python_version < "3.8" など）は除く
+		line, _, _ = strings.Cut(line, ";")
+
+		m := requirementPattern.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil || seen[m[1]] {
+			continue
+		}
+		seen[m[1]] = true
+
+		dep := &Dependency{ManifestPath: p, Ecosystem: EcosystemPyPI, Name: m[1], Kind: kind}
+		spec := strings.TrimSpace(m[2])
+		if url, ok := strings.CutPrefix(spec, "@"); ok {
+			dep.Location = strings.TrimSpace(url)
+		} else {
+			dep.Requirement = strings.TrimPrefix(strings.ReplaceAll(spec, " ", ""), "==")
+		}
+		deps = append(deps, dep)
+	}
+	return deps
+}
+
+// gitmodulesSectionPattern は .gitmodules のサブモジュールのセクション（[submodule "name"]）にマッチする
+var gitmodulesSectionPattern = regexp.MustCompile(`^\[submodule\s+"([^"]+)"\]$`)
+
+// parseGitmodules は .gitmodules からサブモジュールを抽出する（名前は配置するパス、取得元は url、要求するバージョンは branch）
+func parseGitmodules(p, content string) []*Dependency {
+	var deps []*Dependency
+	var current *Dependency
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if m := gitmodulesSectionPattern.FindStringSubmatch(line); m != nil {
+			current = &Dependency{ManifestPath: p, Ecosystem: EcosystemSubmodule, Name: m[1], Kind: KindDirect}
+			deps = append(deps, current)
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			current = nil
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || current == nil {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(key) {
+		case "path":
+			current.Name = value
+		case "url":
+			current.Location = value
+		case "branch":
+			current.Requirement = value
+		}
+	}
+	return deps
+}
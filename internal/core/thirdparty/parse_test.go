@@ -0,0 +1,73 @@
+package thirdparty
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsManifest(t *testing.T) {
+	assert.True(t, IsManifest("go.mod"))
+	assert.True(t, IsManifest("tools/go.mod"))
+	assert.True(t, IsManifest("vendor/modules.txt"))
+	assert.True(t, IsManifest("web/package.json"))
+	assert.True(t, IsManifest("requirements-dev.txt"))
+	assert.True(t, IsManifest(".gitmodules"))
+
+	assert.False(t, IsManifest("web/node_modules/react/package.json"), "インストールしたパッケージは除く")
+	assert.False(t, IsManifest("docs/modules.txt"))
+	assert.False(t, IsManifest("third_party/.gitmodules"), ".gitmodules はリポジトリのルートのみ")
+}
+
+func TestCollect(t *testing.T) {
+	files := map[string]string{
+		"go.mod": `module example.com/app
+
+go 1.22
+
+require (
+	github.com/google/uuid v1.6.0
+	golang.org/x/text v0.14.0 // indirect
+)
+
+replace github.com/google/uuid => ../uuid
+`,
+		"vendor/modules.txt": "# github.com/google/uuid v1.6.0 => ../uuid\n## explicit\ngithub.com/google/uuid\n",
+		"web/package.json":   `{"dependencies": {"react": "^18.2.0"}, "devDependencies": {"vite": "5.0.0", "react": "18.0.0"}}`,
+		"requirements-dev.txt": `# テスト用
+pytest==8.0.0  # 固定
+Django[argon2] >= 4.2, < 5 ; python_version >= "3.10"
+-r requirements.txt
+mylib @ https://example.com/mylib.tar.gz
+`,
+		".gitmodules": `[submodule "proto"]
+	path = third_party/proto
+	url = git@github.com:acme/proto.git
+	branch = main
+`,
+	}
+
+	deps, err := Collect(files)
+	require.NoError(t, err)
+	assert.Equal(t, []*Dependency{
+		{ManifestPath: ".gitmodules", Ecosystem: EcosystemSubmodule, Name: "third_party/proto", Requirement: "main", Kind: KindDirect, Location: "git@github.com:acme/proto.git"},
+		{ManifestPath: "go.mod", Ecosystem: EcosystemGo, Name: "github.com/google/uuid", Requirement: "v1.6.0", Kind: KindDirect, Vendored: true, Location: "../uuid"},
+		{ManifestPath: "go.mod", Ecosystem: EcosystemGo, Name: "golang.org/x/text", Requirement: "v0.14.0", Kind: KindIndirect},
+		{ManifestPath: "requirements-dev.txt", Ecosystem: EcosystemPyPI, Name: "Django", Requirement: ">=4.2,<5", Kind: KindDev},
+		{ManifestPath: "requirements-dev.txt", Ecosystem: EcosystemPyPI, Name: "mylib", Kind: KindDev, Location: "https://example.com/mylib.tar.gz"},
+		{ManifestPath: "requirements-dev.txt", Ecosystem: EcosystemPyPI, Name: "pytest", Requirement: "8.0.0", Kind: KindDev},
+		{ManifestPath: "web/package.json", Ecosystem: EcosystemNPM, Name: "react", Requirement: "^18.2.0", Kind: KindDirect},
+		{ManifestPath: "web/package.json", Ecosystem: EcosystemNPM, Name: "vite", Requirement: "5.0.0", Kind: KindDev},
+	}, deps)
+}
+
+func TestCollect_InvalidManifest(t *testing.T) {
+	deps, err := Collect(map[string]string{
+		"package.json":     "{",
+		"requirements.txt": "requests==2.31.0\n",
+	})
+	assert.ErrorContains(t, err, "package.json")
+	require.Len(t, deps, 1, "解析できたマニフェストの依存関係は返す")
+	assert.Equal(t, "requests", deps[0].Name)
+}
@@ -0,0 +1,115 @@
+package thirdparty
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+)
+
+// Repository は外部の依存関係の読み取りインターフェース
+type Repository interface {
+	// ListDependencies は対象スナップショットの依存関係をソース名・マニフェストのパス・名前順に取得する
+	// product_id 指定時はプロダクトの各ソースの最新のインデックス済みスナップショット、snapshot_id 指定時はそのスナップショットを対象とする
+	ListDependencies(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID]) ([]*Dependency, error)
+	// ListSnapshotPairs は対象スナップショットと同じソースの直前のインデックス済みスナップショットの組をソース名順に取得する
+	// 対象スナップショットの決め方は ListDependencies と同じ（直前のスナップショットがないソースは含めない）
+	ListSnapshotPairs(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID]) ([]*SnapshotPair, error)
+	// ListSnapshotDependencies はスナップショットの依存関係をマニフェストのパス・名前順に取得する
+	ListSnapshotDependencies(ctx context.Context, snapshotID uuid.UUID) ([]*Dependency, error)
+}
+
+// Service はプロダクトの外部の依存関係とバージョンの変化を集計する
+type Service struct {
+	repo Repository
+}
+
+// NewService は新しい Service を作成する
+func NewService(repo Repository) *Service {
+	return &Service{repo: repo}
+}
+
+// Report は対象スナップショットの依存関係と、各ソースの直前のインデックス済みスナップショットからの変化を返す
+func (s *Service) Report(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID]) (*Report, error) {
+	deps, err := s.repo.ListDependencies(ctx, productID, snapshotID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dependencies: %w", err)
+	}
+	pairs, err := s.repo.ListSnapshotPairs(ctx, productID, snapshotID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshot pairs: %w", err)
+	}
+
+	bySnapshot := make(map[uuid.UUID][]*Dependency)
+	for _, d := range deps {
+		bySnapshot[d.SnapshotID] = append(bySnapshot[d.SnapshotID], d)
+	}
+
+	report := &Report{Dependencies: deps, Comparisons: make([]*Comparison, 0, len(pairs))}
+	for _, pair := range pairs {
+		previous, err := s.repo.ListSnapshotDependencies(ctx, pair.FromSnapshotID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list previous dependencies: %w", err)
+		}
+		changes := Diff(previous, bySnapshot[pair.ToSnapshotID])
+		for _, c := range changes {
+			c.SourceName = pair.SourceName
+		}
+		report.Comparisons = append(report.Comparisons, &Comparison{
+			SourceName:  pair.SourceName,
+			FromVersion: pair.FromVersion,
+			ToVersion:   pair.ToVersion,
+			Changes:     changes,
+		})
+	}
+	return report, nil
+}
+
+// Diff は同じソースの2つのスナップショットの依存関係を比較し、追加・削除・要求するバージョンの変更をマニフェストのパス・名前順に返す
+func Diff(previous, current []*Dependency) []*Change {
+	before := make(map[string]*Dependency, len(previous))
+	for _, d := range previous {
+		before[d.key()] = d
+	}
+
+	var changes []*Change
+	seen := make(map[string]bool, len(current))
+	for _, d := range current {
+		key := d.key()
+		seen[key] = true
+		old, ok := before[key]
+		switch {
+		case !ok:
+			changes = append(changes, newChange(d, ChangeAdded, "", d.Requirement))
+		case old.Requirement != d.Requirement:
+			changes = append(changes, newChange(d, ChangeUpdated, old.Requirement, d.Requirement))
+		}
+	}
+	for _, d := range previous {
+		if !seen[d.key()] {
+			changes = append(changes, newChange(d, ChangeRemoved, d.Requirement, ""))
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].ManifestPath != changes[j].ManifestPath {
+			return changes[i].ManifestPath < changes[j].ManifestPath
+		}
+		return changes[i].Name < changes[j].Name
+	})
+	return changes
+}
+
+func newChange(d *Dependency, kind ChangeKind, from, to string) *Change {
+	return &Change{
+		SourceName:   d.SourceName,
+		ManifestPath: d.ManifestPath,
+		Ecosystem:    d.Ecosystem,
+		Name:         d.Name,
+		Kind:         kind,
+		From:         from,
+		To:           to,
+	}
+}
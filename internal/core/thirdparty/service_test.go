@@ -0,0 +1,71 @@
+package thirdparty
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type repositoryStub struct {
+	deps     []*Dependency
+	pairs    []*SnapshotPair
+	previous map[uuid.UUID][]*Dependency
+}
+
+func (r *repositoryStub) ListDependencies(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID]) ([]*Dependency, error) {
+	return r.deps, nil
+}
+
+func (r *repositoryStub) ListSnapshotPairs(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID]) ([]*SnapshotPair, error) {
+	return r.pairs, nil
+}
+
+func (r *repositoryStub) ListSnapshotDependencies(ctx context.Context, snapshotID uuid.UUID) ([]*Dependency, error) {
+	return r.previous[snapshotID], nil
+}
+
+func goDep(snapshotID uuid.UUID, name, requirement string) *Dependency {
+	return &Dependency{SnapshotID: snapshotID, SourceName: "backend", ManifestPath: "go.mod", Ecosystem: EcosystemGo, Name: name, Requirement: requirement, Kind: KindDirect}
+}
+
+func TestDiff(t *testing.T) {
+	prev, curr := uuid.New(), uuid.New()
+	changes := Diff(
+		[]*Dependency{goDep(prev, "github.com/a/a", "v1.0.0"), goDep(prev, "github.com/b/b", "v1.0.0"), goDep(prev, "github.com/c/c", "v0.1.0")},
+		[]*Dependency{goDep(curr, "github.com/a/a", "v1.1.0"), goDep(curr, "github.com/c/c", "v0.1.0"), goDep(curr, "github.com/d/d", "v2.0.0")},
+	)
+
+	require.Len(t, changes, 3)
+	assert.Equal(t, &Change{SourceName: "backend", ManifestPath: "go.mod", Ecosystem: EcosystemGo, Name: "github.com/a/a", Kind: ChangeUpdated, From: "v1.0.0", To: "v1.1.0"}, changes[0])
+	assert.Equal(t, ChangeRemoved, changes[1].Kind)
+	assert.Equal(t, "v1.0.0", changes[1].From)
+	assert.Equal(t, ChangeAdded, changes[2].Kind)
+	assert.Equal(t, "github.com/d/d", changes[2].Name)
+
+	assert.Empty(t, Diff(nil, nil))
+}
+
+func TestService_Report(t *testing.T) {
+	prev, curr, other := uuid.New(), uuid.New(), uuid.New()
+	repo := &repositoryStub{
+		deps: []*Dependency{goDep(curr, "github.com/a/a", "v1.1.0"), goDep(other, "github.com/x/x", "v0.1.0")},
+		pairs: []*SnapshotPair{
+			{SourceName: "backend", FromSnapshotID: prev, FromVersion: "a1b2c3", ToSnapshotID: curr, ToVersion: "d4e5f6"},
+		},
+		previous: map[uuid.UUID][]*Dependency{prev: {goDep(prev, "github.com/a/a", "v1.0.0")}},
+	}
+
+	report, err := NewService(repo).Report(context.Background(), mo.Some(uuid.New()), mo.None[uuid.UUID]())
+	require.NoError(t, err)
+	assert.Len(t, report.Dependencies, 2)
+	require.Len(t, report.Comparisons, 1)
+	comparison := report.Comparisons[0]
+	assert.Equal(t, "a1b2c3", comparison.FromVersion)
+	assert.Equal(t, "d4e5f6", comparison.ToVersion)
+	require.Len(t, comparison.Changes, 1, "他のソースの依存関係とは比較しない")
+	assert.Equal(t, ChangeUpdated, comparison.Changes[0].Kind)
+}
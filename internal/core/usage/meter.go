@@ -0,0 +1,115 @@
+package usage
+
+import (
+	"context"
+
+	"github.com/jinford/dev-rag/internal/core/llm"
+)
+
+// EstimateTokens は文字数からトークン数を概算する（英語で約4文字/トークン）
+// APIのレスポンスから実測値を取得できないクライアントでも使えるように概算とする
+func EstimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
+
+// Completer はテキスト生成を行う LLM クライアント
+type Completer interface {
+	GenerateCompletion(ctx context.Context, prompt string) (string, error)
+}
+
+// MeteredLLM はコンテキストの Tracker に使用量を記録する LLM クライアント
+// Tracker が設定されていない呼び出しでは何も記録しない
+type MeteredLLM struct {
+	inner Completer
+	kind  Kind
+	model string
+}
+
+// NewMeteredLLM は使用量を記録する LLM クライアントを作成する
+func NewMeteredLLM(inner Completer, kind Kind, model string) *MeteredLLM {
+	return &MeteredLLM{inner: inner, kind: kind, model: model}
+}
+
+// GenerateCompletion は予算を確認したうえでテキストを生成し、使用量を記録する
+func (m *MeteredLLM) GenerateCompletion(ctx context.Context, prompt string) (string, error) {
+	tracker := FromContext(ctx)
+	if tracker == nil {
+		return m.inner.GenerateCompletion(ctx, prompt)
+	}
+	if err := tracker.Check(); err != nil {
+		return "", err
+	}
+
+	out, err := m.inner.GenerateCompletion(ctx, prompt)
+	if err != nil {
+		return "", err
+	}
+
+	model := m.model
+	if override, ok := llm.ModelFromContext(ctx); ok {
+		model = override
+	}
+	tracker.Add(m.kind, model, EstimateTokens(prompt), EstimateTokens(out))
+	return out, nil
+}
+
+// Embedder は ingestion.Embedder と同じメソッドを持つインターフェース
+// （ingestion パッケージからの循環参照を避けるため消費者側で定義）
+type Embedder interface {
+	Embed(ctx context.Context, text string) ([]float32, error)
+	BatchEmbed(ctx context.Context, texts []string) ([][]float32, error)
+	ModelName() string
+	Dimension() int
+	MaxBatchSize() int
+	MaxInputTokens() int
+}
+
+// MeteredEmbedder はコンテキストの Tracker に使用量を記録する Embedder
+type MeteredEmbedder struct {
+	Embedder
+}
+
+// NewMeteredEmbedder は使用量を記録する Embedder を作成する
+func NewMeteredEmbedder(inner Embedder) *MeteredEmbedder {
+	return &MeteredEmbedder{Embedder: inner}
+}
+
+// Embed は予算を確認したうえで Embedding を生成し、使用量を記録する
+func (m *MeteredEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	tracker := FromContext(ctx)
+	if tracker == nil {
+		return m.Embedder.Embed(ctx, text)
+	}
+	if err := tracker.Check(); err != nil {
+		return nil, err
+	}
+
+	vector, err := m.Embedder.Embed(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+	tracker.Add(KindEmbedding, m.ModelName(), EstimateTokens(text), 0)
+	return vector, nil
+}
+
+// BatchEmbed は予算を確認したうえで Embedding を生成し、使用量を記録する
+func (m *MeteredEmbedder) BatchEmbed(ctx context.Context, texts []string) ([][]float32, error) {
+	tracker := FromContext(ctx)
+	if tracker == nil {
+		return m.Embedder.BatchEmbed(ctx, texts)
+	}
+	if err := tracker.Check(); err != nil {
+		return nil, err
+	}
+
+	vectors, err := m.Embedder.BatchEmbed(ctx, texts)
+	if err != nil {
+		return nil, err
+	}
+	tokens := 0
+	for _, text := range texts {
+		tokens += EstimateTokens(text)
+	}
+	tracker.Add(KindEmbedding, m.ModelName(), tokens, 0)
+	return vectors, nil
+}
@@ -0,0 +1,82 @@
+package usage
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Price は100万トークンあたりの単価（USD）
+type Price struct {
+	InputPerMillion  float64
+	OutputPerMillion float64
+}
+
+// Pricing はモデル名ごとの単価表
+// モデル名は "azure/" などのプロバイダー接頭辞を除いた名前でも照合する
+type Pricing map[string]Price
+
+// DefaultPricing は主要モデルの公開単価を返す
+// 単価は変動するため、正確な値は LLM_PRICING で上書きする
+func DefaultPricing() Pricing {
+	return Pricing{
+		"text-embedding-3-small": {InputPerMillion: 0.02},
+		"text-embedding-3-large": {InputPerMillion: 0.13},
+		"text-embedding-ada-002": {InputPerMillion: 0.10},
+		"gpt-4o-mini":            {InputPerMillion: 0.15, OutputPerMillion: 0.60},
+		"gpt-4o":                 {InputPerMillion: 2.50, OutputPerMillion: 10.00},
+		"gpt-4.1-mini":           {InputPerMillion: 0.40, OutputPerMillion: 1.60},
+		"gpt-4.1":                {InputPerMillion: 2.00, OutputPerMillion: 8.00},
+	}
+}
+
+// Lookup はモデルの単価を返す（未登録の場合は ok が false）
+func (p Pricing) Lookup(model string) (Price, bool) {
+	if price, ok := p[model]; ok {
+		return price, true
+	}
+	if _, name, found := strings.Cut(model, "/"); found {
+		price, ok := p[name]
+		return price, ok
+	}
+	return Price{}, false
+}
+
+// Cost は推定コスト（USD）を返す。未登録モデル（ローカルモデル等）は 0 とする
+func (p Pricing) Cost(model string, inputTokens, outputTokens int) float64 {
+	price, ok := p.Lookup(model)
+	if !ok {
+		return 0
+	}
+	return (float64(inputTokens)*price.InputPerMillion + float64(outputTokens)*price.OutputPerMillion) / 1_000_000
+}
+
+// ParsePricing は "model=input:output;model=input" 形式の単価設定を既定の単価表に上書きする
+func ParsePricing(value string) (Pricing, error) {
+	pricing := DefaultPricing()
+	for _, entry := range strings.Split(value, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		model, prices, ok := strings.Cut(entry, "=")
+		if !ok || strings.TrimSpace(model) == "" {
+			return nil, fmt.Errorf("invalid pricing entry %q: expected model=input[:output]", entry)
+		}
+
+		input, output, _ := strings.Cut(prices, ":")
+		var price Price
+		var err error
+		if price.InputPerMillion, err = strconv.ParseFloat(strings.TrimSpace(input), 64); err != nil {
+			return nil, fmt.Errorf("invalid input price in %q: %w", entry, err)
+		}
+		if strings.TrimSpace(output) != "" {
+			if price.OutputPerMillion, err = strconv.ParseFloat(strings.TrimSpace(output), 64); err != nil {
+				return nil, fmt.Errorf("invalid output price in %q: %w", entry, err)
+			}
+		}
+		pricing[strings.TrimSpace(model)] = price
+	}
+	return pricing, nil
+}
@@ -0,0 +1,202 @@
+// Package usage はLLM/Embedding呼び出しのトークン使用量とコストを集計し、予算超過を判定する
+package usage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// ErrBudgetExceeded は予算上限を超過して処理を中断した場合のエラー
+var ErrBudgetExceeded = errors.New("LLM budget exceeded")
+
+// Kind は使用量の用途
+type Kind string
+
+const (
+	KindEmbedding Kind = "embedding"
+	KindSummary   Kind = "summary"
+	KindWiki      Kind = "wiki"
+	KindAsk       Kind = "ask"
+)
+
+// BudgetAction は予算超過時の動作
+type BudgetAction string
+
+const (
+	// BudgetActionAbort は以降のLLM/Embedding呼び出しを ErrBudgetExceeded で失敗させる
+	BudgetActionAbort BudgetAction = "abort"
+	// BudgetActionDowngrade は残りのファイル要約の生成をスキップして継続する（Embedding などその他の呼び出しは継続する）
+	BudgetActionDowngrade BudgetAction = "downgrade"
+)
+
+// Budget は1回の実行あたりの予算設定
+type Budget struct {
+	MaxCostUSD float64      // 0 以下の場合は無制限
+	Action     BudgetAction // 超過時の動作
+}
+
+// Record はモデル・用途ごとの使用量
+type Record struct {
+	Kind         Kind
+	Model        string
+	Requests     int
+	InputTokens  int
+	OutputTokens int
+	CostUSD      float64
+}
+
+// Tracker は1回の実行（インデックス化など）の使用量を集計する
+// 複数のワーカーから同時に呼び出される前提でスレッドセーフに実装する
+type Tracker struct {
+	mu      sync.Mutex
+	pricing Pricing
+	budget  Budget
+	records map[recordKey]*Record
+	pending map[recordKey]*Record // 前回の Flush 以降の差分
+	total   float64
+}
+
+type recordKey struct {
+	kind  Kind
+	model string
+}
+
+// NewTracker は新しい Tracker を作成する
+func NewTracker(pricing Pricing, budget Budget) *Tracker {
+	if pricing == nil {
+		pricing = DefaultPricing()
+	}
+	if budget.Action == "" {
+		budget.Action = BudgetActionDowngrade
+	}
+	return &Tracker{
+		pricing: pricing,
+		budget:  budget,
+		records: make(map[recordKey]*Record),
+		pending: make(map[recordKey]*Record),
+	}
+}
+
+// Add は使用量を記録し、推定コストを返す
+func (t *Tracker) Add(kind Kind, model string, inputTokens, outputTokens int) float64 {
+	cost := t.pricing.Cost(model, inputTokens, outputTokens)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := recordKey{kind: kind, model: model}
+	for _, m := range []map[recordKey]*Record{t.records, t.pending} {
+		r, ok := m[key]
+		if !ok {
+			r = &Record{Kind: kind, Model: model}
+			m[key] = r
+		}
+		r.Requests++
+		r.InputTokens += inputTokens
+		r.OutputTokens += outputTokens
+		r.CostUSD += cost
+	}
+	t.total += cost
+
+	return cost
+}
+
+// TotalCost はこれまでの推定コスト（USD）を返す
+func (t *Tracker) TotalCost() float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.total
+}
+
+// Budget は予算設定を返す
+func (t *Tracker) Budget() Budget {
+	return t.budget
+}
+
+// Exceeded は予算上限を超過しているかを返す
+func (t *Tracker) Exceeded() bool {
+	if t.budget.MaxCostUSD <= 0 {
+		return false
+	}
+	return t.TotalCost() >= t.budget.MaxCostUSD
+}
+
+// Check は予算超過時に動作が abort であれば ErrBudgetExceeded を返す
+func (t *Tracker) Check() error {
+	if t.Exceeded() && t.budget.Action == BudgetActionAbort {
+		return fmt.Errorf("%w: estimated $%.4f >= budget $%.4f", ErrBudgetExceeded, t.TotalCost(), t.budget.MaxCostUSD)
+	}
+	return nil
+}
+
+// ShouldDowngrade は任意の処理をスキップすべきか（予算超過かつ downgrade）を返す
+func (t *Tracker) ShouldDowngrade() bool {
+	return t.Exceeded() && t.budget.Action == BudgetActionDowngrade
+}
+
+// Records は用途・モデル順に並べた累積の使用量を返す
+func (t *Tracker) Records() []Record {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return sortedRecords(t.records)
+}
+
+// Flush は前回の Flush 以降に追加された使用量を返し、差分をリセットする
+// 永続化を複数回に分けても二重計上しないために使用する（累積値と予算判定には影響しない）
+func (t *Tracker) Flush() []Record {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	records := sortedRecords(t.pending)
+	clear(t.pending)
+	return records
+}
+
+func sortedRecords(m map[recordKey]*Record) []Record {
+	keys := slices.SortedFunc(maps.Keys(m), func(a, b recordKey) int {
+		if c := strings.Compare(string(a.kind), string(b.kind)); c != 0 {
+			return c
+		}
+		return strings.Compare(a.model, b.model)
+	})
+
+	records := make([]Record, 0, len(keys))
+	for _, key := range keys {
+		records = append(records, *m[key])
+	}
+	return records
+}
+
+type trackerKey struct{}
+
+// WithTracker はコンテキストに Tracker を設定する
+func WithTracker(ctx context.Context, t *Tracker) context.Context {
+	return context.WithValue(ctx, trackerKey{}, t)
+}
+
+// FromContext はコンテキストに設定された Tracker を返す（未設定の場合は nil）
+func FromContext(ctx context.Context) *Tracker {
+	t, _ := ctx.Value(trackerKey{}).(*Tracker)
+	return t
+}
+
+// Check はコンテキストの Tracker で予算超過を判定する（Tracker 未設定時は常に nil）
+func Check(ctx context.Context) error {
+	if t := FromContext(ctx); t != nil {
+		return t.Check()
+	}
+	return nil
+}
+
+// ShouldDowngrade はコンテキストの Tracker で任意処理をスキップすべきかを返す
+func ShouldDowngrade(ctx context.Context) bool {
+	if t := FromContext(ctx); t != nil {
+		return t.ShouldDowngrade()
+	}
+	return false
+}
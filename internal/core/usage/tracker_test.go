@@ -0,0 +1,85 @@
+package usage
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracker_AddAndFlush(t *testing.T) {
+	tracker := NewTracker(Pricing{"gpt-4o-mini": {InputPerMillion: 0.15, OutputPerMillion: 0.60}}, Budget{})
+
+	cost := tracker.Add(KindSummary, "gpt-4o-mini", 1_000_000, 500_000)
+	assert.InDelta(t, 0.45, cost, 1e-9)
+	tracker.Add(KindEmbedding, "ollama/nomic-embed-text", 1000, 0)
+
+	flushed := tracker.Flush()
+	require.Len(t, flushed, 2)
+	assert.Equal(t, KindEmbedding, flushed[0].Kind)
+	assert.Zero(t, flushed[0].CostUSD)
+	assert.Empty(t, tracker.Flush())
+
+	// Flush 後も累積値は保持される
+	tracker.Add(KindSummary, "gpt-4o-mini", 1_000_000, 0)
+	assert.InDelta(t, 0.60, tracker.TotalCost(), 1e-9)
+	assert.Equal(t, 2, tracker.Records()[1].Requests)
+	assert.Equal(t, 1, tracker.Flush()[0].Requests)
+}
+
+func TestTracker_Budget(t *testing.T) {
+	pricing := Pricing{"gpt-4o": {InputPerMillion: 1_000_000}}
+
+	downgrade := NewTracker(pricing, Budget{MaxCostUSD: 1})
+	assert.False(t, downgrade.ShouldDowngrade())
+	downgrade.Add(KindWiki, "gpt-4o", 1, 0)
+	assert.True(t, downgrade.ShouldDowngrade())
+	assert.NoError(t, downgrade.Check())
+
+	abort := NewTracker(pricing, Budget{MaxCostUSD: 1, Action: BudgetActionAbort})
+	abort.Add(KindWiki, "gpt-4o", 1, 0)
+	assert.ErrorIs(t, abort.Check(), ErrBudgetExceeded)
+	assert.False(t, abort.ShouldDowngrade())
+}
+
+type stubCompleter struct{ calls int }
+
+func (s *stubCompleter) GenerateCompletion(ctx context.Context, prompt string) (string, error) {
+	s.calls++
+	return "answer", nil
+}
+
+func TestMeteredLLM_AbortsWhenBudgetExceeded(t *testing.T) {
+	inner := &stubCompleter{}
+	client := NewMeteredLLM(inner, KindSummary, "gpt-4o")
+	tracker := NewTracker(Pricing{"gpt-4o": {InputPerMillion: 1_000_000}}, Budget{MaxCostUSD: 1, Action: BudgetActionAbort})
+	ctx := WithTracker(context.Background(), tracker)
+
+	_, err := client.GenerateCompletion(ctx, "summarize this file")
+	require.NoError(t, err)
+
+	_, err = client.GenerateCompletion(ctx, "summarize this file")
+	assert.True(t, errors.Is(err, ErrBudgetExceeded))
+	assert.Equal(t, 1, inner.calls)
+
+	// Tracker のない呼び出しは計測しない
+	_, err = client.GenerateCompletion(context.Background(), "hello")
+	require.NoError(t, err)
+	assert.Equal(t, 2, inner.calls)
+}
+
+func TestParsePricing(t *testing.T) {
+	pricing, err := ParsePricing("my-model=1.5:3; gpt-4o-mini=0.1")
+	require.NoError(t, err)
+	assert.Equal(t, Price{InputPerMillion: 1.5, OutputPerMillion: 3}, pricing["my-model"])
+	assert.Equal(t, Price{InputPerMillion: 0.1}, pricing["gpt-4o-mini"])
+
+	price, ok := pricing.Lookup("azure/my-model")
+	assert.True(t, ok)
+	assert.Equal(t, 1.5, price.InputPerMillion)
+
+	_, err = ParsePricing("broken")
+	assert.Error(t, err)
+}
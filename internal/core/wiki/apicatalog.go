@@ -0,0 +1,94 @@
+package wiki
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jinford/dev-rag/internal/core/apispec"
+)
+
+// apiKindHeadings はエンドポイントの種類ごとの見出し
+var apiKindHeadings = map[apispec.Kind]string{
+	apispec.KindHTTP: "HTTP API",
+	apispec.KindGRPC: "gRPC",
+}
+
+// generateAPICatalogSection はインデックス時に抽出したエンドポイントからセクションのページを生成する
+func (s *WikiService) generateAPICatalogSection(ctx context.Context, params GenerateParams, config SectionConfig) (*WikiPage, error) {
+	if s.apiEndpoints == nil {
+		return nil, fmt.Errorf("api endpoint reader is not configured")
+	}
+
+	productID, snapshotID := params.scope()
+	endpoints, err := s.apiEndpoints.ListEndpoints(ctx, productID, snapshotID, apispec.ListFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api endpoints: %w", err)
+	}
+
+	return &WikiPage{
+		Section:  config.Section,
+		Title:    config.Title,
+		FileName: config.FileName,
+		Content:  RenderAPICatalog(config, endpoints),
+	}, nil
+}
+
+// RenderAPICatalog はAPIカタログのページを生成する
+// 種類（HTTP・gRPC）ごとに、サービス（OpenAPI のタグ、gRPC の service）単位の表にまとめる
+func RenderAPICatalog(config SectionConfig, endpoints []*apispec.Endpoint) string {
+	var sb strings.Builder
+	if config.Description != "" {
+		sb.WriteString(config.Description)
+		sb.WriteString("\n\n")
+	}
+
+	if len(endpoints) == 0 {
+		sb.WriteString("API定義ファイル（OpenAPI・Swagger・Protocol Buffers）は見つかりませんでした。\n")
+		return sb.String()
+	}
+
+	sorted := make([]*apispec.Endpoint, len(endpoints))
+	copy(sorted, endpoints)
+	apispec.SortEndpoints(sorted)
+
+	sourceNames := make([]string, 0, len(sorted))
+	for _, ep := range sorted {
+		sourceNames = append(sourceNames, ep.SourceName)
+	}
+	multiSource := hasMultipleSources(sourceNames)
+
+	for _, kind := range apispec.Kinds {
+		var members []*apispec.Endpoint
+		for _, ep := range sorted {
+			if ep.Kind == kind {
+				members = append(members, ep)
+			}
+		}
+		if len(members) == 0 {
+			continue
+		}
+
+		sb.WriteString(fmt.Sprintf("## %s（%d件）\n\n", apiKindHeadings[kind], len(members)))
+		for i, ep := range members {
+			if i == 0 || ep.Service != members[i-1].Service {
+				if i > 0 {
+					sb.WriteString("\n")
+				}
+				service := ep.Service
+				if service == "" {
+					service = "（タグなし）"
+				}
+				sb.WriteString(fmt.Sprintf("### %s\n\n", service))
+				sb.WriteString("| メソッド | パス | 概要 | リクエスト | レスポンス | 定義 |\n")
+				sb.WriteString("|---|---|---|---|---|---|\n")
+			}
+			sb.WriteString(fmt.Sprintf("| %s | `%s` | %s | %s | %s | `%s` |\n",
+				ep.Method, ep.Path, escapeTableCell(ep.Summary),
+				escapeTableCell(ep.RequestType), escapeTableCell(ep.ResponseType),
+				qualifiedFilePath(ep.SourceName, ep.FilePath, multiSource)))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,30 @@
+package wiki
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jinford/dev-rag/internal/core/apispec"
+)
+
+func TestRenderAPICatalog(t *testing.T) {
+	content := RenderAPICatalog(SectionConfig{Description: "エンドポイントの一覧"}, []*apispec.Endpoint{
+		{Kind: apispec.KindGRPC, Service: "acme.v1.UserService", Method: "GetUser", Path: "/acme.v1.UserService/GetUser", RequestType: "GetUserRequest", ResponseType: "User", FilePath: "proto/user.proto"},
+		{Kind: apispec.KindHTTP, Service: "users", Method: "POST", Path: "/users", Summary: "作成 | 登録", RequestType: "CreateUserRequest", ResponseType: "User", FilePath: "openapi.yaml"},
+		{Kind: apispec.KindHTTP, Service: "users", Method: "GET", Path: "/users", Summary: "一覧", ResponseType: "[]User", FilePath: "openapi.yaml"},
+		{Kind: apispec.KindHTTP, Method: "GET", Path: "/healthz", FilePath: "openapi.yaml"},
+	})
+
+	assert.Contains(t, content, "エンドポイントの一覧\n\n## HTTP API（3件）\n\n### （タグなし）\n\n")
+	assert.Contains(t, content, "| GET | `/healthz` | - | - | - | `openapi.yaml` |\n\n### users\n\n")
+	assert.Contains(t, content, "| GET | `/users` | 一覧 | - | []User | `openapi.yaml` |\n| POST | `/users` | 作成 \\| 登録 | CreateUserRequest | User | `openapi.yaml` |\n")
+	assert.Contains(t, content, "## gRPC（1件）\n\n### acme.v1.UserService\n\n")
+	assert.Less(t, strings.Index(content, "## HTTP API"), strings.Index(content, "## gRPC"))
+}
+
+func TestRenderAPICatalog_Empty(t *testing.T) {
+	content := RenderAPICatalog(SectionConfig{}, nil)
+	assert.Contains(t, content, "見つかりませんでした")
+}
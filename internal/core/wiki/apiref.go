@@ -0,0 +1,292 @@
+package wiki
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/jinford/dev-rag/internal/core/llm"
+	"github.com/jinford/dev-rag/internal/core/prompt"
+)
+
+// apiSymbolLimit はAPIリファレンスのために取得する宣言の上限
+const apiSymbolLimit = 5000
+
+// APIPackage はAPIリファレンスの1ページに対応するパッケージ（ディレクトリ）
+type APIPackage struct {
+	Path     string       // パッケージのディレクトリ（ルート直下は "."）
+	Doc      string       // パッケージのドキュメントコメント
+	Overview string       // LLMで生成した概要（llm_overview 有効時のみ）
+	Symbols  []*APISymbol // 公開されている宣言（ファイルパス・行番号順）
+}
+
+// apiKindGroups はAPIリファレンスの見出しと、それに含めるチャンクの種類
+var apiKindGroups = []struct {
+	heading string
+	kinds   []string
+}{
+	{"型", []string{"struct", "interface", "type", "class", "enum", "trait"}},
+	{"定数・変数", []string{"const", "var"}},
+	{"関数", []string{"function"}},
+	{"メソッド", []string{"method"}},
+}
+
+// generateAPIReferenceSection はチャンクのメタデータからパッケージごとのAPIリファレンスを生成する
+// セクションのページはパッケージ一覧とし、各パッケージのページは file_name の拡張子を除いたディレクトリに出力する
+// llm_overview が有効な場合のみ、パッケージごとの概要をLLMで生成して先頭に添える
+func (s *WikiService) generateAPIReferenceSection(ctx context.Context, params GenerateParams, config SectionConfig) ([]*WikiPage, error) {
+	if s.symbolReader == nil {
+		return nil, fmt.Errorf("api symbol reader is not configured")
+	}
+
+	productID, snapshotID := params.scope()
+	symbols, err := s.symbolReader.ListAPISymbols(ctx, productID, snapshotID, config.Domains, apiSymbolLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api symbols: %w", err)
+	}
+
+	packages := GroupAPIPackages(symbols)
+	overviewVersions := make(map[string]string)
+	if config.LLMOverview {
+		for _, pkg := range packages {
+			rendered, err := s.prompts.Render(ctx, prompt.NameWikiAPIOverview, apiOverviewPromptData(pkg))
+			if err != nil {
+				return nil, fmt.Errorf("failed to build prompt: %w", err)
+			}
+			llmCtx := llm.WithCacheablePrompt(llm.WithMaxTokens(ctx, config.MaxTokens), rendered.Name, rendered.Version)
+			overview, err := s.llm.GenerateCompletion(llmCtx, rendered.Text)
+			if err != nil {
+				// 概要がなくてもリファレンスとしては成立するため続行する
+				s.logger.Warn("failed to generate api overview", "package", pkg.Path, "error", err)
+				continue
+			}
+			pkg.Overview = strings.TrimSpace(overview)
+			overviewVersions[pkg.Path] = rendered.Version
+		}
+	}
+
+	pageDir := strings.TrimSuffix(config.FileName, path.Ext(config.FileName))
+	pages := []*WikiPage{{
+		Section:  config.Section,
+		Title:    config.Title,
+		FileName: config.FileName,
+		Content:  RenderAPIIndex(config, packages, path.Base(pageDir)),
+	}}
+	for _, pkg := range packages {
+		page := &WikiPage{
+			Section:  config.Section,
+			Title:    pkg.Path,
+			FileName: path.Join(pageDir, apiPageFileName(pkg.Path)),
+			Content:  RenderAPIPackage(pkg),
+		}
+		if version, ok := overviewVersions[pkg.Path]; ok {
+			page.PromptName, page.PromptVersion = prompt.NameWikiAPIOverview, version
+		}
+		pages = append(pages, page)
+	}
+	return pages, nil
+}
+
+// GroupAPIPackages は宣言をパッケージ（ディレクトリ）ごとにまとめる
+// 非公開の宣言は除き、パッケージ宣言のドキュメントコメントはパッケージの説明として扱う
+func GroupAPIPackages(symbols []*APISymbol) []*APIPackage {
+	byPath := make(map[string]*APIPackage)
+	var packages []*APIPackage
+	for _, symbol := range symbols {
+		dir := path.Dir(symbol.FilePath)
+		pkg, ok := byPath[dir]
+		if !ok {
+			pkg = &APIPackage{Path: dir}
+			byPath[dir] = pkg
+			packages = append(packages, pkg)
+		}
+
+		if symbol.Kind == "package" {
+			if pkg.Doc == "" && symbol.DocComment != nil {
+				pkg.Doc = strings.TrimSpace(*symbol.DocComment)
+			}
+			continue
+		}
+		if isPublicSymbol(symbol) {
+			pkg.Symbols = append(pkg.Symbols, symbol)
+		}
+	}
+
+	// 公開された宣言がないパッケージはページを作らない
+	filtered := packages[:0]
+	for _, pkg := range packages {
+		if len(pkg.Symbols) > 0 {
+			filtered = append(filtered, pkg)
+		}
+	}
+	return filtered
+}
+
+// RenderAPIIndex はパッケージ一覧のページを生成する
+func RenderAPIIndex(config SectionConfig, packages []*APIPackage, linkDir string) string {
+	var sb strings.Builder
+	if config.Description != "" {
+		sb.WriteString(config.Description)
+		sb.WriteString("\n\n")
+	}
+
+	sb.WriteString("## パッケージ一覧\n\n")
+	if len(packages) == 0 {
+		sb.WriteString("公開されている関数・型は見つかりませんでした。\n")
+		return sb.String()
+	}
+
+	sb.WriteString("| パッケージ | 宣言数 | 説明 |\n")
+	sb.WriteString("|---|---|---|\n")
+	for _, pkg := range packages {
+		summary := pkg.Overview
+		if summary == "" {
+			summary = pkg.Doc
+		}
+		sb.WriteString(fmt.Sprintf("| [%s](%s) | %d | %s |\n",
+			pkg.Path, path.Join(linkDir, apiPageFileName(pkg.Path)), len(pkg.Symbols), tableCell(firstSentence(summary))))
+	}
+	return sb.String()
+}
+
+// RenderAPIPackage はパッケージのAPIリファレンスのページを生成する
+func RenderAPIPackage(pkg *APIPackage) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("## パッケージ `%s`\n\n", pkg.Path))
+	if pkg.Overview != "" {
+		sb.WriteString(pkg.Overview)
+		sb.WriteString("\n\n")
+	}
+	if pkg.Doc != "" {
+		sb.WriteString(pkg.Doc)
+		sb.WriteString("\n\n")
+	}
+
+	grouped := make(map[string]bool)
+	for _, group := range apiKindGroups {
+		var members []*APISymbol
+		for _, symbol := range pkg.Symbols {
+			if slices.Contains(group.kinds, symbol.Kind) {
+				members = append(members, symbol)
+				grouped[symbolKey(symbol)] = true
+			}
+		}
+		writeAPIGroup(&sb, group.heading, members)
+	}
+
+	var others []*APISymbol
+	for _, symbol := range pkg.Symbols {
+		if !grouped[symbolKey(symbol)] {
+			others = append(others, symbol)
+		}
+	}
+	writeAPIGroup(&sb, "その他", others)
+
+	return sb.String()
+}
+
+// BuildAPIOverviewPrompt はパッケージの概要を生成するプロンプトを構築する
+func BuildAPIOverviewPrompt(pkg *APIPackage) string {
+	return prompt.MustRenderBuiltin(prompt.NameWikiAPIOverview, apiOverviewPromptData(pkg))
+}
+
+// apiOverviewPromptData はパッケージの概要のプロンプトテンプレートに渡す値を構築する
+func apiOverviewPromptData(pkg *APIPackage) prompt.WikiAPIOverviewData {
+	var sb strings.Builder
+	for _, symbol := range pkg.Symbols {
+		sb.WriteString(fmt.Sprintf("- %s `%s`", symbol.Kind, declarationOf(symbol)))
+		if symbol.DocComment != nil {
+			sb.WriteString(": ")
+			sb.WriteString(firstSentence(*symbol.DocComment))
+		}
+		sb.WriteString("\n")
+	}
+	return prompt.WikiAPIOverviewData{Package: pkg.Path, Doc: pkg.Doc, Declarations: sb.String()}
+}
+
+// writeAPIGroup は種類ごとの宣言を見出し付きで書き出す
+func writeAPIGroup(sb *strings.Builder, heading string, symbols []*APISymbol) {
+	if len(symbols) == 0 {
+		return
+	}
+	sb.WriteString(fmt.Sprintf("### %s\n\n", heading))
+	for _, symbol := range symbols {
+		name := symbol.Name
+		if symbol.Parent != "" {
+			name = strings.TrimPrefix(symbol.Parent, "*") + "." + symbol.Name
+		}
+		sb.WriteString(fmt.Sprintf("#### `%s`\n\n", name))
+		sb.WriteString(fmt.Sprintf("```%s\n%s\n```\n\n", strings.ToLower(symbol.Language), declarationOf(symbol)))
+		if symbol.DocComment != nil && strings.TrimSpace(*symbol.DocComment) != "" {
+			sb.WriteString(strings.TrimSpace(*symbol.DocComment))
+			sb.WriteString("\n\n")
+		}
+		sb.WriteString(fmt.Sprintf("定義: `%s` L%d-L%d\n\n", symbol.FilePath, symbol.StartLine, symbol.EndLine))
+	}
+}
+
+// declarationOf は宣言の表示用の1行を返す（シグネチャがなければ内容からコメント以外の最初の行を使う）
+func declarationOf(symbol *APISymbol) string {
+	if symbol.Signature != nil && strings.TrimSpace(*symbol.Signature) != "" {
+		return strings.TrimSpace(*symbol.Signature)
+	}
+	for _, line := range strings.Split(symbol.ContentHead, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "//") || strings.HasPrefix(trimmed, "#") ||
+			strings.HasPrefix(trimmed, "/*") || strings.HasPrefix(trimmed, "*") {
+			continue
+		}
+		return strings.TrimSpace(strings.TrimSuffix(trimmed, "{"))
+	}
+	return symbol.Name
+}
+
+// isPublicSymbol は宣言がパッケージ外に公開されているかを返す
+// Go は識別子の先頭が大文字（メソッドはレシーバの型も）、それ以外の言語は先頭が "_" でないものを公開とみなす
+func isPublicSymbol(symbol *APISymbol) bool {
+	if strings.EqualFold(symbol.Language, "go") {
+		if symbol.Parent != "" && !isExported(strings.TrimPrefix(symbol.Parent, "*")) {
+			return false
+		}
+		return isExported(symbol.Name)
+	}
+	return !strings.HasPrefix(symbol.Name, "_")
+}
+
+func isExported(name string) bool {
+	r, _ := utf8.DecodeRuneInString(name)
+	return unicode.IsUpper(r)
+}
+
+// apiPageFileName はパッケージのページのファイル名を返す（ディレクトリ区切りは "-" に置き換える）
+func apiPageFileName(pkgPath string) string {
+	if pkgPath == "." {
+		return "root.md"
+	}
+	return strings.ReplaceAll(pkgPath, "/", "-") + ".md"
+}
+
+// firstSentence は説明文の最初の文（改行または句点まで）を返す
+func firstSentence(text string) string {
+	text = strings.TrimSpace(text)
+	if i := strings.IndexAny(text, "\n。"); i >= 0 {
+		if text[i] == '\n' {
+			return text[:i]
+		}
+		return text[:i+len("。")]
+	}
+	return text
+}
+
+// tableCell はMarkdownの表のセルで解釈される文字をエスケープする
+func tableCell(text string) string {
+	return strings.ReplaceAll(text, "|", `\|`)
+}
+
+func symbolKey(symbol *APISymbol) string {
+	return fmt.Sprintf("%s:%d", symbol.FilePath, symbol.StartLine)
+}
@@ -0,0 +1,62 @@
+package wiki
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestGroupAPIPackages(t *testing.T) {
+	packages := GroupAPIPackages([]*APISymbol{
+		{FilePath: "search/doc.go", Language: "Go", Kind: "package", Name: "search", DocComment: strPtr("Package search はベクトル検索を提供する。\n")},
+		{FilePath: "search/service.go", Language: "Go", Kind: "struct", Name: "SearchService"},
+		{FilePath: "search/service.go", Language: "Go", Kind: "method", Name: "Search", Parent: "*SearchService"},
+		{FilePath: "search/service.go", Language: "Go", Kind: "function", Name: "helper"},
+		{FilePath: "search/service.go", Language: "Go", Kind: "method", Name: "Run", Parent: "*worker"},
+		{FilePath: "internal/only.go", Language: "Go", Kind: "function", Name: "private"},
+		{FilePath: "scripts/tool.py", Language: "Python", Kind: "function", Name: "_hidden"},
+		{FilePath: "scripts/tool.py", Language: "Python", Kind: "function", Name: "main"},
+	})
+
+	require.Len(t, packages, 2)
+	assert.Equal(t, "search", packages[0].Path)
+	assert.Equal(t, "Package search はベクトル検索を提供する。", packages[0].Doc)
+	require.Len(t, packages[0].Symbols, 2)
+	assert.Equal(t, "SearchService", packages[0].Symbols[0].Name)
+	assert.Equal(t, "Search", packages[0].Symbols[1].Name)
+
+	assert.Equal(t, "scripts", packages[1].Path)
+	require.Len(t, packages[1].Symbols, 1)
+	assert.Equal(t, "main", packages[1].Symbols[0].Name)
+}
+
+func TestRenderAPIPackage(t *testing.T) {
+	content := RenderAPIPackage(&APIPackage{
+		Path: "search",
+		Doc:  "ベクトル検索を提供する",
+		Symbols: []*APISymbol{
+			{FilePath: "search/service.go", Language: "Go", Kind: "struct", Name: "SearchService", ContentHead: "// SearchService は検索を提供する\ntype SearchService struct {\n\trepo Repository", StartLine: 10, EndLine: 20},
+			{FilePath: "search/service.go", Language: "Go", Kind: "method", Name: "Search", Parent: "*SearchService", Signature: strPtr("func (s *SearchService) Search(ctx context.Context) error"), DocComment: strPtr("Search は検索する\n"), StartLine: 30, EndLine: 40},
+		},
+	})
+
+	assert.Contains(t, content, "## パッケージ `search`\n\nベクトル検索を提供する\n\n")
+	assert.Contains(t, content, "### 型\n\n#### `SearchService`\n\n```go\ntype SearchService struct\n```\n")
+	assert.Contains(t, content, "### メソッド\n\n#### `SearchService.Search`\n\n```go\nfunc (s *SearchService) Search(ctx context.Context) error\n```\n\nSearch は検索する\n\n定義: `search/service.go` L30-L40\n")
+	assert.NotContains(t, content, "### 関数")
+}
+
+func TestRenderAPIIndex(t *testing.T) {
+	content := RenderAPIIndex(SectionConfig{}, []*APIPackage{
+		{Path: "internal/search", Doc: "検索を提供する。詳細は設計書を参照", Symbols: []*APISymbol{{}, {}}},
+		{Path: ".", Symbols: []*APISymbol{{}}},
+	}, "api-reference")
+
+	assert.Contains(t, content, "| [internal/search](api-reference/internal-search.md) | 2 | 検索を提供する。 |\n")
+	assert.Contains(t, content, "| [.](api-reference/root.md) | 1 |  |\n")
+}
@@ -0,0 +1,301 @@
+package wiki
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 既定の検索件数・最大トークン数（設定ファイルの defaults で上書き可能）
+const (
+	DefaultChunkLimit   = 10
+	DefaultSummaryLimit = 5
+	DefaultMaxTokens    = 2048
+)
+
+// sectionIDPattern はセクションIDに使用できる文字（英小文字・数字・ハイフン・アンダースコア）
+var sectionIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// Config は wiki generate --config で指定するWiki生成設定（YAML）
+// セクションの構成・検索対象・プロンプトをコードを変更せずにカスタマイズするために使用する
+type Config struct {
+	Defaults SectionDefaults `yaml:"defaults"`
+	Sections []SectionConfig `yaml:"sections"`
+}
+
+// SectionDefaults はセクションで省略した項目に適用する既定値
+type SectionDefaults struct {
+	ChunkLimit   int `yaml:"chunk_limit"`   // 検索するチャンク数
+	SummaryLimit int `yaml:"summary_limit"` // 検索する要約数
+	MaxTokens    int `yaml:"max_tokens"`    // LLMの最大出力トークン数
+}
+
+// DefaultConfig は既定のWiki生成設定を返す
+func DefaultConfig() *Config {
+	cfg := &Config{Sections: defaultSections()}
+	if err := cfg.Normalize(); err != nil {
+		panic(fmt.Sprintf("invalid default wiki config: %v", err))
+	}
+	return cfg
+}
+
+// LoadConfig はYAMLファイルからWiki生成設定を読み込み、既定値の補完と検証を行う
+func LoadConfig(path string) (*Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open wiki config: %w", err)
+	}
+	defer f.Close()
+
+	return ParseConfig(f)
+}
+
+// ParseConfig はYAMLからWiki生成設定を読み込み、既定値の補完と検証を行う
+// 未知のキーは設定ミスとしてエラーにする
+func ParseConfig(r io.Reader) (*Config, error) {
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+
+	var cfg Config
+	if err := dec.Decode(&cfg); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("wiki config is empty")
+		}
+		return nil, fmt.Errorf("failed to parse wiki config: %w", err)
+	}
+	if err := cfg.Normalize(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// Marshal は設定をYAMLに変換する（既定設定の雛形出力用）
+func (c *Config) Marshal() ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(c); err != nil {
+		return nil, fmt.Errorf("failed to marshal wiki config: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("failed to marshal wiki config: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Normalize は省略された項目に既定値を補完し、設定を検証する
+// セクションは order の昇順（同値は記述順）に並べ替える
+func (c *Config) Normalize() error {
+	if c.Defaults.ChunkLimit == 0 {
+		c.Defaults.ChunkLimit = DefaultChunkLimit
+	}
+	if c.Defaults.SummaryLimit == 0 {
+		c.Defaults.SummaryLimit = DefaultSummaryLimit
+	}
+	if c.Defaults.MaxTokens == 0 {
+		c.Defaults.MaxTokens = DefaultMaxTokens
+	}
+	if c.Defaults.ChunkLimit < 0 || c.Defaults.SummaryLimit < 0 || c.Defaults.MaxTokens < 0 {
+		return fmt.Errorf("invalid wiki config: defaults must not be negative")
+	}
+	if len(c.Sections) == 0 {
+		return fmt.Errorf("invalid wiki config: at least one section is required")
+	}
+
+	ids := make(map[WikiSection]bool, len(c.Sections))
+	fileNames := make(map[string]bool, len(c.Sections))
+	for i := range c.Sections {
+		section := &c.Sections[i]
+		if err := section.normalize(c.Defaults); err != nil {
+			return fmt.Errorf("invalid wiki config: sections[%d]: %w", i, err)
+		}
+		if ids[section.Section] {
+			return fmt.Errorf("invalid wiki config: duplicate section id %q", section.Section)
+		}
+		if fileNames[section.FileName] {
+			return fmt.Errorf("invalid wiki config: duplicate file_name %q", section.FileName)
+		}
+		ids[section.Section] = true
+		fileNames[section.FileName] = true
+	}
+	if err := checkDependencies(c.Sections); err != nil {
+		return fmt.Errorf("invalid wiki config: %w", err)
+	}
+
+	slices.SortStableFunc(c.Sections, func(a, b SectionConfig) int {
+		return a.Order - b.Order
+	})
+	return nil
+}
+
+// checkDependencies はセクションの depends_on が存在するセクションを指し、循環していないかを検証する
+func checkDependencies(sections []SectionConfig) error {
+	bySection := make(map[WikiSection]SectionConfig, len(sections))
+	for _, section := range sections {
+		bySection[section.Section] = section
+	}
+	for _, section := range sections {
+		if len(section.DependsOn) > 0 && section.Generator != GeneratorLLM {
+			return fmt.Errorf("section %q: depends_on is only supported for generator llm", section.Section)
+		}
+		for _, dep := range section.DependsOn {
+			if _, ok := bySection[dep]; !ok {
+				return fmt.Errorf("section %q: unknown section %q in depends_on", section.Section, dep)
+			}
+		}
+	}
+
+	// 深さ優先探索で探索中のセクションに戻った場合は循環している
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[WikiSection]int, len(sections))
+	var visit func(id WikiSection, path []WikiSection) error
+	visit = func(id WikiSection, path []WikiSection) error {
+		switch state[id] {
+		case visiting:
+			cycle := []string{string(id)}
+			for i := len(path) - 1; i >= 0 && path[i] != id; i-- {
+				cycle = append([]string{string(path[i])}, cycle...)
+			}
+			cycle = append([]string{string(id)}, cycle...)
+			return fmt.Errorf("circular depends_on: %s", strings.Join(cycle, " -> "))
+		case visited:
+			return nil
+		}
+		state[id] = visiting
+		for _, dep := range bySection[id].DependsOn {
+			if err := visit(dep, append(path, id)); err != nil {
+				return err
+			}
+		}
+		state[id] = visited
+		return nil
+	}
+	for _, section := range sections {
+		if err := visit(section.Section, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckDomains はセクションの検索対象ドメインを検証する
+// ドメインの定義は ingestion パッケージにあり循環参照になるため、判定関数を呼び出し側から渡す
+func (c *Config) CheckDomains(isValid func(domain string) bool) error {
+	for _, section := range c.Sections {
+		for _, domain := range section.Domains {
+			if !isValid(domain) {
+				return fmt.Errorf("invalid wiki config: section %q: unknown domain %q", section.Section, domain)
+			}
+		}
+	}
+	return nil
+}
+
+// Section は指定IDのセクション設定を返す
+func (c *Config) Section(id WikiSection) (SectionConfig, bool) {
+	for _, section := range c.Sections {
+		if section.Section == id {
+			return section, true
+		}
+	}
+	return SectionConfig{}, false
+}
+
+// Select は指定IDのセクションのみを含む設定を返す（出力順は元の設定の順）
+// 存在しないIDが含まれる場合はエラーにする
+func (c *Config) Select(ids []WikiSection) (*Config, error) {
+	selected := &Config{Defaults: c.Defaults}
+	for _, id := range ids {
+		if _, ok := c.Section(id); !ok {
+			available := make([]string, len(c.Sections))
+			for i, section := range c.Sections {
+				available[i] = string(section.Section)
+			}
+			return nil, fmt.Errorf("unknown section %q (available: %s)", id, strings.Join(available, ", "))
+		}
+	}
+	for _, section := range c.Sections {
+		if slices.Contains(ids, section.Section) {
+			selected.Sections = append(selected.Sections, section)
+		}
+	}
+	return selected, nil
+}
+
+// normalize はセクションの既定値を補完して検証する
+func (s *SectionConfig) normalize(defaults SectionDefaults) error {
+	if !sectionIDPattern.MatchString(string(s.Section)) {
+		return fmt.Errorf("id %q must consist of lowercase letters, digits, '-' or '_'", s.Section)
+	}
+	if strings.TrimSpace(s.Title) == "" {
+		return fmt.Errorf("section %q: title is required", s.Section)
+	}
+	if s.Generator == "" {
+		s.Generator = GeneratorLLM
+	}
+	if !slices.Contains(generators, s.Generator) {
+		names := make([]string, len(generators))
+		for i, g := range generators {
+			names[i] = string(g)
+		}
+		return fmt.Errorf("section %q: unknown generator %q (expected one of %s)", s.Section, s.Generator, strings.Join(names, ", "))
+	}
+	if s.Generator == GeneratorLLM && strings.TrimSpace(s.Query) == "" {
+		return fmt.Errorf("section %q: query is required", s.Section)
+	}
+
+	if s.FileName == "" {
+		s.FileName = string(s.Section) + ".md"
+	}
+	if path.IsAbs(s.FileName) || path.Clean(s.FileName) != s.FileName || strings.HasPrefix(s.FileName, "../") || path.Ext(s.FileName) != ".md" {
+		return fmt.Errorf("section %q: file_name %q must be a relative .md path inside the output directory", s.Section, s.FileName)
+	}
+
+	if s.ChunkLimit == 0 {
+		s.ChunkLimit = defaults.ChunkLimit
+	}
+	if s.SummaryLimit == 0 {
+		s.SummaryLimit = defaults.SummaryLimit
+	}
+	if s.MaxTokens == 0 {
+		s.MaxTokens = defaults.MaxTokens
+	}
+	if s.ChunkLimit < 0 || s.SummaryLimit < 0 || s.MaxTokens < 0 || s.MaxNodes < 0 {
+		return fmt.Errorf("section %q: chunk_limit, summary_limit, max_tokens and max_nodes must not be negative", s.Section)
+	}
+
+	switch s.Generator {
+	case GeneratorDiagram:
+		if s.MaxNodes == 0 {
+			s.MaxNodes = DefaultDiagramMaxNodes
+		}
+		return nil
+	case GeneratorAPIReference, GeneratorGlossary, GeneratorDecisions, GeneratorAPICatalog, GeneratorDeployment, GeneratorArchitectureChanges, GeneratorHotspots, GeneratorRunbook, GeneratorDependencies, GeneratorLicenses:
+		return nil
+	}
+	if strings.TrimSpace(s.Prompt) == "" {
+		s.Prompt = defaultPrompt(s.Section)
+	}
+	tmpl, err := template.New(string(s.Section)).Option("missingkey=error").Parse(s.Prompt)
+	if err != nil {
+		return fmt.Errorf("section %q: invalid prompt template: %w", s.Section, err)
+	}
+	// 存在しないフィールドの参照は実行時にしか検出できないため、ここで一度展開する
+	if err := tmpl.Execute(io.Discard, promptData{Title: s.Title, Description: s.Description, Section: s.Section}); err != nil {
+		return fmt.Errorf("section %q: invalid prompt template: %w", s.Section, err)
+	}
+	return nil
+}
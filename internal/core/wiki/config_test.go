@@ -0,0 +1,133 @@
+package wiki
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseConfig(t *testing.T) {
+	t.Run("省略した項目に既定値を補完して order 順に並べる", func(t *testing.T) {
+		cfg, err := ParseConfig(strings.NewReader(`
+defaults:
+  max_tokens: 1000
+sections:
+  - id: api
+    title: API
+    query: HTTPハンドラとエンドポイント
+    domains: [code]
+    chunk_limit: 20
+    order: 2
+  - id: overview
+    title: 概要
+    query: プロダクトの目的
+    file_name: README.md
+    order: 1
+`))
+		require.NoError(t, err)
+
+		require.Len(t, cfg.Sections, 2)
+		assert.Equal(t, WikiSection("overview"), cfg.Sections[0].Section)
+		assert.Equal(t, defaultPrompt(SectionOverview), cfg.Sections[0].Prompt)
+
+		api := cfg.Sections[1]
+		assert.Equal(t, "api.md", api.FileName)
+		assert.Equal(t, []string{"code"}, api.Domains)
+		assert.Equal(t, 20, api.ChunkLimit)
+		assert.Equal(t, DefaultSummaryLimit, api.SummaryLimit)
+		assert.Equal(t, 1000, api.MaxTokens)
+	})
+
+	t.Run("不正な設定はエラーになる", func(t *testing.T) {
+		cases := map[string]string{
+			"空":          ``,
+			"未知のキー":      "sections:\n  - id: a\n    title: A\n    query: q\n    unknown: 1\n",
+			"セクションなし":    "defaults:\n  chunk_limit: 5\n",
+			"IDが不正":      "sections:\n  - id: Bad ID\n    title: A\n    query: q\n",
+			"クエリなし":      "sections:\n  - id: a\n    title: A\n",
+			"ID重複":       "sections:\n  - id: a\n    title: A\n    query: q\n  - id: a\n    title: B\n    query: q\n",
+			"ファイル名重複":    "sections:\n  - id: a\n    title: A\n    query: q\n    file_name: x.md\n  - id: b\n    title: B\n    query: q\n    file_name: x.md\n",
+			"出力先の外":      "sections:\n  - id: a\n    title: A\n    query: q\n    file_name: ../a.md\n",
+			"負の値":        "sections:\n  - id: a\n    title: A\n    query: q\n    max_tokens: -1\n",
+			"テンプレート構文":   "sections:\n  - id: a\n    title: A\n    query: q\n    prompt: '{{.Title'\n",
+			"未知のテンプレート値": "sections:\n  - id: a\n    title: A\n    query: q\n    prompt: '{{.Unknown}}'\n",
+			"未知の生成方法":    "sections:\n  - id: a\n    title: A\n    query: q\n    generator: html\n",
+			"未知の依存先":     "sections:\n  - id: a\n    title: A\n    query: q\n    depends_on: [b]\n",
+			"依存の循環":      "sections:\n  - id: a\n    title: A\n    query: q\n    depends_on: [b]\n  - id: b\n    title: B\n    query: q\n    depends_on: [a]\n",
+			"LLM以外の依存":   "sections:\n  - id: a\n    title: A\n    generator: glossary\n    depends_on: [b]\n  - id: b\n    title: B\n    query: q\n",
+		}
+		for name, input := range cases {
+			t.Run(name, func(t *testing.T) {
+				_, err := ParseConfig(strings.NewReader(input))
+				assert.Error(t, err)
+			})
+		}
+	})
+}
+
+func TestDefaultConfig(t *testing.T) {
+	cfg := DefaultConfig()
+
+	require.Len(t, cfg.Sections, 15)
+	assert.Equal(t, "README.md", cfg.Sections[0].FileName)
+	assert.Equal(t, []WikiSection{SectionTechStack, SectionDataFlow, SectionComponents}, cfg.Sections[0].DependsOn)
+	assert.Equal(t, GeneratorDiagram, cfg.Sections[4].Generator)
+	assert.Equal(t, DefaultDiagramMaxNodes, cfg.Sections[4].MaxNodes)
+	assert.Equal(t, GeneratorAPIReference, cfg.Sections[5].Generator)
+	assert.Equal(t, GeneratorGlossary, cfg.Sections[6].Generator)
+	assert.Equal(t, GeneratorOnboarding, cfg.Sections[7].Generator)
+	assert.Contains(t, cfg.Sections[7].Prompt, "ビルド・テスト・起動")
+	assert.Equal(t, GeneratorDecisions, cfg.Sections[8].Generator)
+	assert.Empty(t, cfg.Sections[8].Prompt)
+	assert.Equal(t, GeneratorAPICatalog, cfg.Sections[9].Generator)
+	assert.Equal(t, GeneratorDeployment, cfg.Sections[10].Generator)
+	assert.Equal(t, GeneratorArchitectureChanges, cfg.Sections[11].Generator)
+	assert.Equal(t, GeneratorRunbook, cfg.Sections[12].Generator)
+	assert.Equal(t, GeneratorDependencies, cfg.Sections[13].Generator)
+	assert.Equal(t, GeneratorLicenses, cfg.Sections[14].Generator)
+
+	// 出力した雛形をそのまま読み込めること
+	data, err := cfg.Marshal()
+	require.NoError(t, err)
+	parsed, err := ParseConfig(strings.NewReader(string(data)))
+	require.NoError(t, err)
+	assert.Equal(t, cfg, parsed)
+}
+
+func TestConfig_Select(t *testing.T) {
+	cfg := DefaultConfig()
+
+	selected, err := cfg.Select([]WikiSection{SectionOnboarding, SectionOverview})
+	require.NoError(t, err)
+	require.Len(t, selected.Sections, 2)
+	assert.Equal(t, SectionOverview, selected.Sections[0].Section)
+	assert.Equal(t, SectionOnboarding, selected.Sections[1].Section)
+	assert.Equal(t, cfg.Defaults, selected.Defaults)
+
+	_, err = cfg.Select([]WikiSection{"unknown"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "onboarding")
+}
+
+func TestConfig_CheckDomains(t *testing.T) {
+	cfg := &Config{Sections: []SectionConfig{{Section: "a", Domains: []string{"code", "unknown"}}}}
+
+	err := cfg.CheckDomains(func(domain string) bool { return domain == "code" })
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown")
+}
+
+func TestBuildSectionPrompt_RendersTemplate(t *testing.T) {
+	config := SectionConfig{
+		Section:     "api",
+		Title:       "API",
+		Description: "公開エンドポイントの一覧",
+		Prompt:      "「{{.Title}}」として{{.Description}}を表形式で記述してください",
+	}
+
+	prompt := BuildSectionPrompt(config, nil, nil)
+
+	assert.Contains(t, prompt, "「API」として公開エンドポイントの一覧を表形式で記述してください")
+}
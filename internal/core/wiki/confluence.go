@@ -0,0 +1,144 @@
+package wiki
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/util"
+)
+
+// confluenceCodeLanguages はConfluenceのコードマクロが構文ハイライトに対応する言語
+// 対応していない言語（mermaid など）は言語を指定せず、タイトルに言語名を表示する
+var confluenceCodeLanguages = map[string]string{
+	"bash":       "bash",
+	"sh":         "bash",
+	"shell":      "bash",
+	"c#":         "csharp",
+	"csharp":     "csharp",
+	"cpp":        "cpp",
+	"c++":        "cpp",
+	"css":        "css",
+	"diff":       "diff",
+	"go":         "go",
+	"groovy":     "groovy",
+	"html":       "html",
+	"java":       "java",
+	"javascript": "javascript",
+	"js":         "javascript",
+	"json":       "json",
+	"kotlin":     "kotlin",
+	"php":        "php",
+	"powershell": "powershell",
+	"python":     "python",
+	"py":         "python",
+	"ruby":       "ruby",
+	"rust":       "rust",
+	"scala":      "scala",
+	"sql":        "sql",
+	"swift":      "swift",
+	"typescript": "typescript",
+	"ts":         "typescript",
+	"xml":        "xml",
+	"yaml":       "yaml",
+	"yml":        "yaml",
+}
+
+// ConvertToConfluence はMarkdownをConfluenceのストレージ形式（XHTML）に変換する
+// コードブロックはコードマクロに変換し、resolveLink がページタイトルを返すリンク（Wiki内の .md へのリンク）は
+// Confluenceのページリンクに変換する
+func ConvertToConfluence(markdown string, resolveLink func(dest string) (title string, ok bool)) (string, error) {
+	md := goldmark.New(
+		goldmark.WithExtensions(extension.Table, extension.Strikethrough, extension.Linkify),
+		goldmark.WithRendererOptions(
+			html.WithXHTML(),
+			// 既定のHTMLレンダラー（優先度 1000）より優先する
+			renderer.WithNodeRenderers(util.Prioritized(&confluenceRenderer{resolveLink: resolveLink}, 100)),
+		),
+	)
+
+	var buf bytes.Buffer
+	if err := md.Convert([]byte(markdown), &buf); err != nil {
+		return "", fmt.Errorf("failed to convert markdown: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// confluenceRenderer はConfluence固有の要素（マクロ・ページリンク）を出力するレンダラー
+type confluenceRenderer struct {
+	resolveLink func(dest string) (string, bool)
+}
+
+func (r *confluenceRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindFencedCodeBlock, r.renderCodeBlock)
+	reg.Register(ast.KindCodeBlock, r.renderCodeBlock)
+	reg.Register(ast.KindLink, r.renderLink)
+}
+
+func (r *confluenceRenderer) renderCodeBlock(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+
+	language := ""
+	if fenced, ok := node.(*ast.FencedCodeBlock); ok {
+		language = strings.ToLower(string(fenced.Language(source)))
+	}
+
+	var code strings.Builder
+	lines := node.Lines()
+	for i := 0; i < lines.Len(); i++ {
+		segment := lines.At(i)
+		code.Write(segment.Value(source))
+	}
+
+	_, _ = w.WriteString(`<ac:structured-macro ac:name="code">`)
+	if mapped, ok := confluenceCodeLanguages[language]; ok {
+		_, _ = w.WriteString(`<ac:parameter ac:name="language">` + mapped + `</ac:parameter>`)
+	} else if language != "" {
+		_, _ = w.WriteString(`<ac:parameter ac:name="title">` + escapeXML(language) + `</ac:parameter>`)
+	}
+	_, _ = w.WriteString(`<ac:plain-text-body>` + cdata(strings.TrimSuffix(code.String(), "\n")) + `</ac:plain-text-body>`)
+	_, _ = w.WriteString("</ac:structured-macro>\n")
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *confluenceRenderer) renderLink(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	link := node.(*ast.Link)
+	dest := string(link.Destination)
+
+	if r.resolveLink != nil {
+		if title, ok := r.resolveLink(dest); ok {
+			if entering {
+				_, _ = w.WriteString(`<ac:link><ri:page ri:content-title="` + escapeXML(title) + `" /><ac:link-body>`)
+			} else {
+				_, _ = w.WriteString(`</ac:link-body></ac:link>`)
+			}
+			return ast.WalkContinue, nil
+		}
+	}
+
+	if entering {
+		_, _ = w.WriteString(`<a href="`)
+		_, _ = w.Write(util.EscapeHTML(util.URLEscape(link.Destination, true)))
+		_, _ = w.WriteString(`">`)
+	} else {
+		_, _ = w.WriteString(`</a>`)
+	}
+	return ast.WalkContinue, nil
+}
+
+// cdata は文字列をCDATAセクションで囲む（終端記号 "]]>" を含む場合は分割する）
+func cdata(s string) string {
+	return "<![CDATA[" + strings.ReplaceAll(s, "]]>", "]]]]><![CDATA[>") + "]]>"
+}
+
+// escapeXML はXMLの属性値・テキストで解釈される文字をエスケープする
+func escapeXML(s string) string {
+	return string(util.EscapeHTML([]byte(s)))
+}
@@ -0,0 +1,41 @@
+package wiki
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertToConfluence_CodeBlock(t *testing.T) {
+	body, err := ConvertToConfluence("```go\nfunc main() {}\n```\n\n```mermaid\ngraph TD\n```\n\n```\nx := a[b[0]]>c\n```\n", nil)
+	require.NoError(t, err)
+
+	assert.Contains(t, body, `<ac:structured-macro ac:name="code"><ac:parameter ac:name="language">go</ac:parameter><ac:plain-text-body><![CDATA[func main() {}]]></ac:plain-text-body></ac:structured-macro>`)
+	assert.Contains(t, body, `<ac:parameter ac:name="title">mermaid</ac:parameter>`)
+	// CDATA の終端記号は分割される
+	assert.Contains(t, body, `<![CDATA[x := a[b[0]]]]><![CDATA[>c]]>`)
+}
+
+func TestConvertToConfluence_Links(t *testing.T) {
+	resolve := func(dest string) (string, bool) {
+		if dest == "architecture.md" {
+			return "demo / architecture", true
+		}
+		return "", false
+	}
+
+	body, err := ConvertToConfluence("[構成](architecture.md) と [外部](https://example.com/a?b=1&c=2)\n", resolve)
+	require.NoError(t, err)
+
+	assert.Contains(t, body, `<ac:link><ri:page ri:content-title="demo / architecture" /><ac:link-body>構成</ac:link-body></ac:link>`)
+	assert.Contains(t, body, `<a href="https://example.com/a?b=1&amp;c=2">外部</a>`)
+}
+
+func TestConvertToConfluence_Table(t *testing.T) {
+	body, err := ConvertToConfluence("| a | b |\n|---|---|\n| 1 | 2 |\n", nil)
+	require.NoError(t, err)
+
+	assert.Contains(t, body, "<table>")
+	assert.Contains(t, body, "<td>1</td>")
+}
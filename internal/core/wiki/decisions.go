@@ -0,0 +1,145 @@
+package wiki
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jinford/dev-rag/internal/core/decision"
+)
+
+// decisionSummaryRunes は採用済みの決定の一覧に載せる決定内容の最大文字数
+const decisionSummaryRunes = 600
+
+// decisionStatusLabels はステータスの表示名
+var decisionStatusLabels = map[decision.Status]string{
+	decision.StatusAccepted:   "採用",
+	decision.StatusProposed:   "提案中",
+	decision.StatusSuperseded: "置き換え済み",
+	decision.StatusDeprecated: "非推奨",
+	decision.StatusRejected:   "却下",
+	decision.StatusUnknown:    "不明",
+}
+
+// generateDecisionsSection はインデックス時に抽出した設計判断の記録からセクションのページを生成する
+func (s *WikiService) generateDecisionsSection(ctx context.Context, params GenerateParams, config SectionConfig) (*WikiPage, error) {
+	if s.decisions == nil {
+		return nil, fmt.Errorf("decision reader is not configured")
+	}
+
+	productID, snapshotID := params.scope()
+	records, err := s.decisions.ListDecisionRecords(ctx, productID, snapshotID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list decision records: %w", err)
+	}
+
+	return &WikiPage{
+		Section:  config.Section,
+		Title:    config.Title,
+		FileName: config.FileName,
+		Content:  RenderDecisions(config, records),
+	}, nil
+}
+
+// RenderDecisions は設計判断の記録のページを生成する
+// ステータスごとの一覧表（取得順）に続けて、採用済みの決定の内容を載せる
+func RenderDecisions(config SectionConfig, records []*decision.Record) string {
+	var sb strings.Builder
+	if config.Description != "" {
+		sb.WriteString(config.Description)
+		sb.WriteString("\n\n")
+	}
+
+	if len(records) == 0 {
+		sb.WriteString("設計判断の記録（`docs/adr/` 配下のADRなど）は見つかりませんでした。\n")
+		return sb.String()
+	}
+
+	sourceNames := make([]string, 0, len(records))
+	for _, r := range records {
+		sourceNames = append(sourceNames, r.SourceName)
+	}
+	multiSource := hasMultipleSources(sourceNames)
+
+	for _, status := range decision.Statuses {
+		var members []*decision.Record
+		for _, r := range records {
+			if r.Status == status {
+				members = append(members, r)
+			}
+		}
+		if len(members) == 0 {
+			continue
+		}
+
+		sb.WriteString(fmt.Sprintf("## %s（%d件）\n\n", decisionStatusLabels[status], len(members)))
+		sb.WriteString("| 番号 | タイトル | 決定日 | ステータスの記載 | ファイル |\n")
+		sb.WriteString("|---|---|---|---|---|\n")
+		for _, r := range members {
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | `%s` |\n",
+				decisionNumber(r), escapeTableCell(r.Title), decisionDate(r),
+				escapeTableCell(r.StatusText), qualifiedFilePath(r.SourceName, r.FilePath, multiSource)))
+		}
+		sb.WriteString("\n")
+	}
+
+	var accepted []*decision.Record
+	for _, r := range records {
+		if r.Status.Active() && strings.TrimSpace(r.Decision) != "" {
+			accepted = append(accepted, r)
+		}
+	}
+	if len(accepted) > 0 {
+		sb.WriteString("## 採用済みの決定の内容\n\n")
+		for _, r := range accepted {
+			if r.Number != nil {
+				sb.WriteString(fmt.Sprintf("### %d. %s\n\n", *r.Number, r.Title))
+			} else {
+				sb.WriteString(fmt.Sprintf("### %s\n\n", r.Title))
+			}
+			sb.WriteString(truncateRunes(strings.TrimSpace(r.Decision), decisionSummaryRunes))
+			sb.WriteString(fmt.Sprintf("\n\n- 出典: `%s`\n\n", qualifiedFilePath(r.SourceName, r.FilePath, multiSource)))
+		}
+	}
+	return sb.String()
+}
+
+func decisionNumber(r *decision.Record) string {
+	if r.Number == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%d", *r.Number)
+}
+
+func decisionDate(r *decision.Record) string {
+	if r.Date == nil {
+		return "-"
+	}
+	return r.Date.Format("2006-01-02")
+}
+
+// hasMultipleSources はソース名が2種類以上含まれるかを返す
+func hasMultipleSources(sourceNames []string) bool {
+	for _, name := range sourceNames {
+		if name != sourceNames[0] {
+			return true
+		}
+	}
+	return false
+}
+
+// qualifiedFilePath は複数ソースにまたがる場合にソース名を付けたファイルパスを返す
+func qualifiedFilePath(sourceName, filePath string, multiSource bool) string {
+	if multiSource && sourceName != "" {
+		return sourceName + ":" + filePath
+	}
+	return filePath
+}
+
+// escapeTableCell はMarkdownの表のセルに入れる文字列の区切り文字と改行を置き換える
+func escapeTableCell(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+}
@@ -0,0 +1,46 @@
+package wiki
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jinford/dev-rag/internal/core/decision"
+)
+
+func TestRenderDecisions(t *testing.T) {
+	one, two, three := 1, 2, 3
+	date := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)
+	content := RenderDecisions(SectionConfig{Description: "設計判断の一覧"}, []*decision.Record{
+		{Number: &one, Title: "Use MySQL", Status: decision.StatusSuperseded, StatusText: "Superseded by ADR-0002", FilePath: "docs/adr/0001-use-mysql.md", Decision: "Use MySQL."},
+		{Number: &two, Title: "Use PostgreSQL", Status: decision.StatusAccepted, StatusText: "Accepted", Date: &date, FilePath: "docs/adr/0002-use-postgresql.md", Decision: "Use PostgreSQL | pgvector."},
+		{Number: &three, Title: "Use gRPC", Status: decision.StatusProposed, StatusText: "Proposed", FilePath: "docs/adr/0003-use-grpc.md"},
+	})
+
+	assert.Contains(t, content, "設計判断の一覧\n\n## 採用（1件）\n\n")
+	assert.Contains(t, content, "| 2 | Use PostgreSQL | 2024-03-15 | Accepted | `docs/adr/0002-use-postgresql.md` |\n")
+	assert.Contains(t, content, "| 1 | Use MySQL | - | Superseded by ADR-0002 | `docs/adr/0001-use-mysql.md` |\n")
+	assert.Less(t, strings.Index(content, "## 採用"), strings.Index(content, "## 提案中"))
+	assert.Less(t, strings.Index(content, "## 提案中"), strings.Index(content, "## 置き換え済み"))
+
+	// 採用済みの決定のみ内容を載せる
+	assert.Contains(t, content, "## 採用済みの決定の内容\n\n### 2. Use PostgreSQL\n\nUse PostgreSQL | pgvector.\n\n- 出典: `docs/adr/0002-use-postgresql.md`\n")
+	assert.NotContains(t, content, "Use MySQL.")
+}
+
+func TestRenderDecisions_MultipleSources(t *testing.T) {
+	content := RenderDecisions(SectionConfig{}, []*decision.Record{
+		{SourceName: "api", Title: "A", Status: decision.StatusAccepted, FilePath: "adr/a.md"},
+		{SourceName: "web", Title: "B", Status: decision.StatusAccepted, FilePath: "adr/b.md"},
+	})
+
+	assert.Contains(t, content, "| - | A | - | - | `api:adr/a.md` |\n")
+	assert.Contains(t, content, "| - | B | - | - | `web:adr/b.md` |\n")
+}
+
+func TestRenderDecisions_Empty(t *testing.T) {
+	content := RenderDecisions(SectionConfig{}, nil)
+	assert.Contains(t, content, "見つかりませんでした")
+}
@@ -0,0 +1,150 @@
+package wiki
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jinford/dev-rag/internal/core/drift"
+	"github.com/jinford/dev-rag/internal/core/thirdparty"
+)
+
+// ecosystemHeadings は外部依存関係のページに載せるエコシステムの順序と見出し
+var ecosystemHeadings = []struct {
+	ecosystem thirdparty.Ecosystem
+	heading   string
+}{
+	{thirdparty.EcosystemGo, "Go モジュール"},
+	{thirdparty.EcosystemNPM, "npm パッケージ"},
+	{thirdparty.EcosystemPyPI, "Python パッケージ"},
+}
+
+// generateDependenciesSection はインデックス時に抽出した外部の依存関係からセクションのページを生成する
+func (s *WikiService) generateDependenciesSection(ctx context.Context, params GenerateParams, config SectionConfig) (*WikiPage, error) {
+	if s.dependencies == nil {
+		return nil, fmt.Errorf("external dependency reader is not configured")
+	}
+
+	productID, snapshotID := params.scope()
+	report, err := s.dependencies.Report(ctx, productID, snapshotID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list external dependencies: %w", err)
+	}
+
+	return &WikiPage{
+		Section:  config.Section,
+		Title:    config.Title,
+		FileName: config.FileName,
+		Content:  RenderExternalDependencies(config, report),
+	}, nil
+}
+
+// RenderExternalDependencies は外部依存関係のページを生成する
+// エコシステム（Go・npm・Python）ごとの依存関係、サブモジュール、ソースごとのバージョンの変化の順に表にまとめる
+func RenderExternalDependencies(config SectionConfig, report *thirdparty.Report) string {
+	var sb strings.Builder
+	if config.Description != "" {
+		sb.WriteString(config.Description)
+		sb.WriteString("\n\n")
+	}
+
+	if report == nil || len(report.Dependencies) == 0 {
+		sb.WriteString("依存関係のマニフェスト（go.mod・package.json・requirements*.txt・.gitmodules）は見つかりませんでした。\n")
+		return sb.String()
+	}
+
+	sourceNames := make([]string, 0, len(report.Dependencies))
+	byEcosystem := make(map[thirdparty.Ecosystem][]*thirdparty.Dependency)
+	for _, d := range report.Dependencies {
+		sourceNames = append(sourceNames, d.SourceName)
+		byEcosystem[d.Ecosystem] = append(byEcosystem[d.Ecosystem], d)
+	}
+	multiSource := hasMultipleSources(sourceNames)
+	location := func(sourceName, filePath string) string {
+		return "`" + qualifiedFilePath(sourceName, filePath, multiSource) + "`"
+	}
+
+	for _, h := range ecosystemHeadings {
+		deps := byEcosystem[h.ecosystem]
+		if len(deps) == 0 {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("## %s\n\n", h.heading))
+		sb.WriteString("| 名前 | バージョン | 種類 | 定義 | 備考 |\n")
+		sb.WriteString("|---|---|---|---|---|\n")
+		for _, d := range deps {
+			sb.WriteString(fmt.Sprintf("| `%s` | %s | %s | %s | %s |\n",
+				d.Name, codeOrDash(d.Requirement), dependencyKindLabel(d.Kind), location(d.SourceName, d.ManifestPath), dependencyNote(d)))
+		}
+		sb.WriteString("\n")
+	}
+
+	if submodules := byEcosystem[thirdparty.EcosystemSubmodule]; len(submodules) > 0 {
+		sb.WriteString("## サブモジュール\n\n")
+		sb.WriteString("| パス | 取得元 | ブランチ | 定義 |\n")
+		sb.WriteString("|---|---|---|---|\n")
+		for _, d := range submodules {
+			sb.WriteString(fmt.Sprintf("| `%s` | %s | %s | %s |\n",
+				d.Name, codeOrDash(d.Location), codeOrDash(d.Requirement), location(d.SourceName, d.ManifestPath)))
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("## バージョンの変化\n\n")
+	if len(report.Comparisons) == 0 {
+		sb.WriteString("比較できる過去のスナップショットがありません（2回目以降のインデックス化の後に生成されます）。\n")
+		return sb.String()
+	}
+	for _, c := range report.Comparisons {
+		sb.WriteString(fmt.Sprintf("### %s（`%s` → `%s`）\n\n", c.SourceName, drift.ShortVersion(c.FromVersion), drift.ShortVersion(c.ToVersion)))
+		if len(c.Changes) == 0 {
+			sb.WriteString("依存関係の変化はありません。\n\n")
+			continue
+		}
+		sb.WriteString("| 名前 | 変化 | 変更前 | 変更後 | 定義 |\n")
+		sb.WriteString("|---|---|---|---|---|\n")
+		for _, change := range c.Changes {
+			sb.WriteString(fmt.Sprintf("| `%s` | %s | %s | %s | `%s` |\n",
+				change.Name, changeKindLabel(change.Kind), codeOrDash(change.From), codeOrDash(change.To), change.ManifestPath))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+func dependencyKindLabel(kind thirdparty.Kind) string {
+	switch kind {
+	case thirdparty.KindIndirect:
+		return "間接"
+	case thirdparty.KindDev:
+		return "開発用"
+	default:
+		return "直接"
+	}
+}
+
+func changeKindLabel(kind thirdparty.ChangeKind) string {
+	switch kind {
+	case thirdparty.ChangeAdded:
+		return "追加"
+	case thirdparty.ChangeRemoved:
+		return "削除"
+	default:
+		return "更新"
+	}
+}
+
+// dependencyNote はベンダリングの有無と取得元の置き換えを表の備考にまとめる
+func dependencyNote(d *thirdparty.Dependency) string {
+	var notes []string
+	if d.Vendored {
+		notes = append(notes, "ベンダリング済み")
+	}
+	if d.Location != "" {
+		notes = append(notes, "取得元: "+codeOrDash(d.Location))
+	}
+	if len(notes) == 0 {
+		return "-"
+	}
+	return strings.Join(notes, "<br>")
+}
@@ -0,0 +1,51 @@
+package wiki
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jinford/dev-rag/internal/core/thirdparty"
+)
+
+func TestRenderExternalDependencies(t *testing.T) {
+	content := RenderExternalDependencies(SectionConfig{Description: "外部の依存関係の一覧"}, &thirdparty.Report{
+		Dependencies: []*thirdparty.Dependency{
+			{SourceName: "api", ManifestPath: ".gitmodules", Ecosystem: thirdparty.EcosystemSubmodule, Name: "third_party/proto", Kind: thirdparty.KindDirect, Location: "git@github.com:acme/proto.git"},
+			{SourceName: "api", ManifestPath: "go.mod", Ecosystem: thirdparty.EcosystemGo, Name: "github.com/google/uuid", Requirement: "v1.6.0", Kind: thirdparty.KindDirect, Vendored: true},
+			{SourceName: "api", ManifestPath: "go.mod", Ecosystem: thirdparty.EcosystemGo, Name: "golang.org/x/text", Requirement: "v0.14.0", Kind: thirdparty.KindIndirect, Location: "../text"},
+			{SourceName: "web", ManifestPath: "package.json", Ecosystem: thirdparty.EcosystemNPM, Name: "vite", Requirement: "^5.0.0", Kind: thirdparty.KindDev},
+		},
+		Comparisons: []*thirdparty.Comparison{
+			{SourceName: "api", FromVersion: "0123456789abcdef", ToVersion: "fedcba9876543210", Changes: []*thirdparty.Change{
+				{ManifestPath: "go.mod", Ecosystem: thirdparty.EcosystemGo, Name: "github.com/google/uuid", Kind: thirdparty.ChangeUpdated, From: "v1.5.0", To: "v1.6.0"},
+				{ManifestPath: "go.mod", Ecosystem: thirdparty.EcosystemGo, Name: "github.com/pkg/errors", Kind: thirdparty.ChangeRemoved, From: "v0.9.1"},
+			}},
+			{SourceName: "web", FromVersion: "aaa", ToVersion: "bbb"},
+		},
+	})
+
+	assert.Contains(t, content, "外部の依存関係の一覧\n\n## Go モジュール\n")
+	assert.Contains(t, content, "| `github.com/google/uuid` | `v1.6.0` | 直接 | `api:go.mod` | ベンダリング済み |\n")
+	assert.Contains(t, content, "| `golang.org/x/text` | `v0.14.0` | 間接 | `api:go.mod` | 取得元: `../text` |\n")
+	assert.Contains(t, content, "## npm パッケージ\n")
+	assert.Contains(t, content, "| `vite` | `^5.0.0` | 開発用 | `web:package.json` | - |\n")
+	assert.NotContains(t, content, "## Python パッケージ")
+	assert.Contains(t, content, "| `third_party/proto` | `git@github.com:acme/proto.git` | - | `api:.gitmodules` |\n")
+
+	assert.Contains(t, content, "### api（`0123456789ab` → `fedcba987654`）\n")
+	assert.Contains(t, content, "| `github.com/google/uuid` | 更新 | `v1.5.0` | `v1.6.0` | `go.mod` |\n")
+	assert.Contains(t, content, "| `github.com/pkg/errors` | 削除 | `v0.9.1` | - | `go.mod` |\n")
+	assert.Contains(t, content, "### web（`aaa` → `bbb`）\n\n依存関係の変化はありません。\n")
+}
+
+func TestRenderExternalDependencies_Empty(t *testing.T) {
+	content := RenderExternalDependencies(SectionConfig{}, &thirdparty.Report{})
+	assert.Contains(t, content, "依存関係のマニフェスト（go.mod・package.json・requirements*.txt・.gitmodules）は見つかりませんでした。")
+
+	content = RenderExternalDependencies(SectionConfig{}, &thirdparty.Report{Dependencies: []*thirdparty.Dependency{
+		{SourceName: "api", ManifestPath: "requirements.txt", Ecosystem: thirdparty.EcosystemPyPI, Name: "requests", Kind: thirdparty.KindDirect},
+	}})
+	assert.Contains(t, content, "| `requests` | - | 直接 | `requirements.txt` | - |\n")
+	assert.Contains(t, content, "比較できる過去のスナップショットがありません")
+}
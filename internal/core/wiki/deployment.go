@@ -0,0 +1,208 @@
+package wiki
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jinford/dev-rag/internal/core/infraspec"
+)
+
+// generateDeploymentSection はインデックス時に抽出したデプロイ構成からセクションのページを生成する
+func (s *WikiService) generateDeploymentSection(ctx context.Context, params GenerateParams, config SectionConfig) (*WikiPage, error) {
+	if s.infraResources == nil {
+		return nil, fmt.Errorf("infra resource reader is not configured")
+	}
+
+	productID, snapshotID := params.scope()
+	resources, err := s.infraResources.ListInfraResources(ctx, productID, snapshotID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list infra resources: %w", err)
+	}
+
+	return &WikiPage{
+		Section:  config.Section,
+		Title:    config.Title,
+		FileName: config.FileName,
+		Content:  RenderDeployment(config, resources),
+	}, nil
+}
+
+// RenderDeployment はデプロイ構成のページを生成する
+// Dockerfile のビルドステージ、ワークロード（Docker Compose のサービス・Kubernetes のワークロード）、Kubernetes の Service、環境変数、その他のリソースの順に表にまとめる
+func RenderDeployment(config SectionConfig, resources []*infraspec.Resource) string {
+	var sb strings.Builder
+	if config.Description != "" {
+		sb.WriteString(config.Description)
+		sb.WriteString("\n\n")
+	}
+
+	if len(resources) == 0 {
+		sb.WriteString("インフラ定義ファイル（Dockerfile・Docker Compose・Kubernetes マニフェスト）は見つかりませんでした。\n")
+		return sb.String()
+	}
+
+	sourceNames := make([]string, 0, len(resources))
+	for _, r := range resources {
+		sourceNames = append(sourceNames, r.SourceName)
+	}
+	multiSource := hasMultipleSources(sourceNames)
+	location := func(r *infraspec.Resource) string {
+		return "`" + qualifiedFilePath(r.SourceName, r.FilePath, multiSource) + "`"
+	}
+
+	var dockerfiles, workloads, services, others []*infraspec.Resource
+	for _, r := range resources {
+		switch {
+		case r.Format == infraspec.FormatDockerfile:
+			dockerfiles = append(dockerfiles, r)
+		case r.Kind == "Service" && r.Format == infraspec.FormatKubernetes:
+			services = append(services, r)
+		case len(r.Spec.Containers) > 0:
+			workloads = append(workloads, r)
+		default:
+			others = append(others, r)
+		}
+	}
+
+	if len(dockerfiles) > 0 {
+		sb.WriteString("## Dockerfile\n\n")
+		sb.WriteString("| 定義 | ビルドステージ | ベースイメージ | 公開ポート | 環境変数 | 起動コマンド |\n")
+		sb.WriteString("|---|---|---|---|---|---|\n")
+		for _, r := range dockerfiles {
+			for i, stage := range r.Spec.Containers {
+				command := ""
+				if i == len(r.Spec.Containers)-1 {
+					command = r.Spec.Command
+				}
+				sb.WriteString(fmt.Sprintf("| %s | %s | `%s` | %s | %s | %s |\n",
+					location(r), escapeTableCell(stage.Name), stage.Image, escapeTableCell(joinPortList(stage.Ports)),
+					escapeTableCell(joinEnvNames(stage.Env)), codeOrDash(command)))
+			}
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(workloads) > 0 {
+		sb.WriteString("## ワークロード\n\n")
+		sb.WriteString("| 名前 | 種類 | レプリカ数 | コンテナ | イメージ | ポート | リソース上限 | リソース要求 | 定義 |\n")
+		sb.WriteString("|---|---|---|---|---|---|---|---|---|\n")
+		for _, r := range workloads {
+			replicas := "-"
+			if r.Spec.Replicas != nil {
+				replicas = strconv.Itoa(*r.Spec.Replicas)
+			}
+			for _, c := range r.Spec.Containers {
+				sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s | %s | %s | %s |\n",
+					resourceName(r), r.Kind, replicas, escapeTableCell(c.Name), codeOrDash(c.Image),
+					escapeTableCell(joinPortList(c.Ports)), escapeTableCell(joinQuantityList(c.Limits)),
+					escapeTableCell(joinQuantityList(c.Requests)), location(r)))
+			}
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(services) > 0 {
+		sb.WriteString("## サービス\n\n")
+		sb.WriteString("| 名前 | type | ポート | 定義 |\n")
+		sb.WriteString("|---|---|---|---|\n")
+		for _, r := range services {
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n",
+				resourceName(r), escapeTableCell(r.Spec.ServiceType), escapeTableCell(joinPortList(r.Spec.Ports)), location(r)))
+		}
+		sb.WriteString("\n")
+	}
+
+	var envRows []string
+	for _, r := range slices.Concat(workloads, dockerfiles) {
+		for _, c := range r.Spec.Containers {
+			for _, env := range c.Env {
+				value := env.Value
+				if env.From != "" {
+					value = "参照: " + env.From
+				}
+				envRows = append(envRows, fmt.Sprintf("| `%s` | %s | %s | %s |\n",
+					env.Name, codeOrDash(value), envOwner(r, c), location(r)))
+			}
+		}
+	}
+	if len(envRows) > 0 {
+		sb.WriteString("## 環境変数\n\n")
+		sb.WriteString("| 変数 | 値 | 設定箇所 | 定義 |\n")
+		sb.WriteString("|---|---|---|---|\n")
+		for _, row := range envRows {
+			sb.WriteString(row)
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(others) > 0 {
+		sb.WriteString("## その他のリソース\n\n")
+		sb.WriteString("| 種類 | 名前 | 定義 |\n")
+		sb.WriteString("|---|---|---|\n")
+		for _, r := range others {
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s |\n", r.Kind, resourceName(r), location(r)))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// resourceName はリソース名を返す（名前空間がある場合は <名前空間>/<名前>）
+func resourceName(r *infraspec.Resource) string {
+	if r.Namespace != "" {
+		return escapeTableCell(r.Namespace + "/" + r.Name)
+	}
+	return escapeTableCell(r.Name)
+}
+
+// envOwner は環境変数を設定しているリソース・コンテナ（Dockerfile はビルドステージ）を返す
+func envOwner(r *infraspec.Resource, c infraspec.Container) string {
+	if r.Format == infraspec.FormatDockerfile {
+		if c.Name != "" {
+			return "ステージ " + escapeTableCell(c.Name)
+		}
+		return "Dockerfile"
+	}
+	owner := resourceName(r)
+	if c.Name != "" && c.Name != r.Name {
+		owner += " / " + escapeTableCell(c.Name)
+	}
+	return owner
+}
+
+func joinPortList(ports []infraspec.Port) string {
+	parts := make([]string, 0, len(ports))
+	for _, p := range ports {
+		parts = append(parts, p.String())
+	}
+	return strings.Join(parts, ", ")
+}
+
+func joinEnvNames(env []infraspec.EnvVar) string {
+	names := make([]string, 0, len(env))
+	for _, e := range env {
+		names = append(names, e.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// joinQuantityList はリソース量を名前順に "cpu=500m, memory=512Mi" の形式で連結する
+func joinQuantityList(quantities map[string]string) string {
+	parts := make([]string, 0, len(quantities))
+	for _, name := range slices.Sorted(maps.Keys(quantities)) {
+		parts = append(parts, name+"="+quantities[name])
+	}
+	return strings.Join(parts, ", ")
+}
+
+// codeOrDash は値をインラインコードにして返す（空の場合は "-"）
+func codeOrDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return "`" + strings.ReplaceAll(s, "|", `\|`) + "`"
+}
@@ -0,0 +1,57 @@
+package wiki
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jinford/dev-rag/internal/core/infraspec"
+)
+
+func TestRenderDeployment(t *testing.T) {
+	replicas := 3
+	content := RenderDeployment(SectionConfig{Description: "デプロイ構成の一覧"}, []*infraspec.Resource{
+		{
+			Format: infraspec.FormatDockerfile, Kind: "Dockerfile", Name: "Dockerfile", FilePath: "Dockerfile",
+			Spec: infraspec.Spec{
+				Command: "/app serve",
+				Containers: []infraspec.Container{
+					{Name: "builder", Image: "golang:1.24", Env: []infraspec.EnvVar{{Name: "CGO_ENABLED", Value: "0"}}},
+					{Image: "gcr.io/distroless/static", Ports: []infraspec.Port{{Port: "8080"}}},
+				},
+			},
+		},
+		{
+			Format: infraspec.FormatKubernetes, Kind: "Deployment", Name: "api", Namespace: "prod", FilePath: "deploy/api.yaml",
+			Spec: infraspec.Spec{
+				Replicas: &replicas,
+				Containers: []infraspec.Container{{
+					Name: "api", Image: "ghcr.io/acme/api:1.2",
+					Ports:  []infraspec.Port{{Name: "http", Port: "8080"}},
+					Env:    []infraspec.EnvVar{{Name: "DB_PASSWORD", From: "secret:db/password"}},
+					Limits: map[string]string{"memory": "512Mi", "cpu": "500m"},
+				}},
+			},
+		},
+		{
+			Format: infraspec.FormatKubernetes, Kind: "Service", Name: "api", Namespace: "prod", FilePath: "deploy/api.yaml",
+			Spec: infraspec.Spec{ServiceType: "ClusterIP", Ports: []infraspec.Port{{Port: "80", TargetPort: "http", Protocol: "TCP"}}},
+		},
+		{Format: infraspec.FormatKubernetes, Kind: "ConfigMap", Name: "api-config", FilePath: "deploy/api.yaml"},
+	})
+
+	assert.Contains(t, content, "デプロイ構成の一覧\n\n## Dockerfile\n\n")
+	assert.Contains(t, content, "| `Dockerfile` | builder | `golang:1.24` | - | CGO_ENABLED | - |\n| `Dockerfile` | - | `gcr.io/distroless/static` | 8080 | - | `/app serve` |\n")
+	assert.Contains(t, content, "| prod/api | Deployment | 3 | api | `ghcr.io/acme/api:1.2` | 8080（http） | cpu=500m, memory=512Mi | - | `deploy/api.yaml` |\n")
+	assert.Contains(t, content, "## サービス\n\n| 名前 | type | ポート | 定義 |\n|---|---|---|---|\n| prod/api | ClusterIP | 80:http/tcp | `deploy/api.yaml` |\n")
+	assert.Contains(t, content, "| `DB_PASSWORD` | `参照: secret:db/password` | prod/api | `deploy/api.yaml` |\n")
+	assert.Contains(t, content, "| `CGO_ENABLED` | `0` | ステージ builder | `Dockerfile` |\n")
+	assert.Contains(t, content, "## その他のリソース\n\n| 種類 | 名前 | 定義 |\n|---|---|---|\n| ConfigMap | api-config | `deploy/api.yaml` |\n")
+	assert.Less(t, strings.Index(content, "## ワークロード"), strings.Index(content, "## サービス"))
+}
+
+func TestRenderDeployment_Empty(t *testing.T) {
+	content := RenderDeployment(SectionConfig{}, nil)
+	assert.Contains(t, content, "見つかりませんでした")
+}
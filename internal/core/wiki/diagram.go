@@ -0,0 +1,206 @@
+package wiki
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+)
+
+// DefaultDiagramMaxNodes は図解1つあたりに描画するノード数の既定値
+// ノードが多すぎるとMermaidの図が読めなくなるため、依存の多いノードに絞る
+const DefaultDiagramMaxNodes = 30
+
+// fileDependencyLimit は図解のために取得するファイル間依存関係の上限
+const fileDependencyLimit = 2000
+
+// generateDiagramSection は依存グラフからMermaid図を構築してセクションのページを生成する（LLMは使用しない）
+func (s *WikiService) generateDiagramSection(ctx context.Context, params GenerateParams, config SectionConfig) (*WikiPage, error) {
+	if s.graphReader == nil {
+		return nil, fmt.Errorf("dependency graph reader is not configured")
+	}
+
+	productID, snapshotID := params.scope()
+	maxNodes := config.MaxNodes
+	if maxNodes <= 0 {
+		maxNodes = DefaultDiagramMaxNodes
+	}
+
+	fileDeps, err := s.graphReader.ListFileDependencies(ctx, productID, snapshotID, fileDependencyLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list file dependencies: %w", err)
+	}
+	callEdges, err := s.graphReader.ListCallGraph(ctx, productID, snapshotID, maxNodes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list call graph: %w", err)
+	}
+
+	var sb strings.Builder
+	if config.Description != "" {
+		sb.WriteString(config.Description)
+		sb.WriteString("\n\n")
+	}
+
+	sb.WriteString("## モジュール依存関係\n\n")
+	if diagram := BuildModuleDiagram(fileDeps, maxNodes); diagram != "" {
+		sb.WriteString("ディレクトリ（パッケージ）間の依存関係です。矢印のラベルは依存しているチャンク間の関係の数を表します。\n\n")
+		sb.WriteString(diagram)
+	} else {
+		sb.WriteString("ディレクトリ間の依存関係は検出されませんでした。\n")
+	}
+
+	sb.WriteString("\n## 主要な関数の呼び出し関係\n\n")
+	if diagram := BuildCallGraphDiagram(callEdges); diagram != "" {
+		sb.WriteString(fmt.Sprintf("重要度の高い上位 %d 件の関数/クラスのうち、相互に呼び出し関係があるものをファイルごとにまとめています。\n\n", maxNodes))
+		sb.WriteString(diagram)
+	} else {
+		sb.WriteString("重要度の高い関数/クラス間の呼び出し関係は検出されませんでした。\n")
+	}
+
+	return &WikiPage{
+		Section:  config.Section,
+		Title:    config.Title,
+		FileName: config.FileName,
+		Content:  sb.String(),
+	}, nil
+}
+
+// moduleEdge はディレクトリ間の依存関係
+type moduleEdge struct {
+	from, to string
+	count    int
+}
+
+// BuildModuleDiagram はファイル間の依存関係をディレクトリ単位に集約し、Mermaidのflowchartを構築する
+// 依存関係の多いディレクトリから maxNodes 件に絞り、依存関係がない場合は空文字を返す
+func BuildModuleDiagram(deps []*FileDependency, maxNodes int) string {
+	counts := make(map[[2]string]int)
+	degree := make(map[string]int)
+	for _, dep := range deps {
+		from, to := moduleOf(dep.FromPath), moduleOf(dep.ToPath)
+		if from == to {
+			continue
+		}
+		counts[[2]string{from, to}] += dep.Count
+		degree[from] += dep.Count
+		degree[to] += dep.Count
+	}
+	if len(counts) == 0 {
+		return ""
+	}
+
+	nodes := topNodes(degree, maxNodes)
+	included := make(map[string]bool, len(nodes))
+	for _, node := range nodes {
+		included[node] = true
+	}
+
+	edges := make([]moduleEdge, 0, len(counts))
+	for key, count := range counts {
+		if included[key[0]] && included[key[1]] {
+			edges = append(edges, moduleEdge{from: key[0], to: key[1], count: count})
+		}
+	}
+	if len(edges) == 0 {
+		return ""
+	}
+	slices.SortFunc(edges, func(a, b moduleEdge) int {
+		return cmp.Or(cmp.Compare(a.from, b.from), cmp.Compare(a.to, b.to))
+	})
+
+	ids := make(map[string]string, len(nodes))
+	var sb strings.Builder
+	sb.WriteString("```mermaid\nflowchart LR\n")
+	for i, node := range nodes {
+		ids[node] = fmt.Sprintf("m%d", i)
+		sb.WriteString(fmt.Sprintf("    %s[\"%s\"]\n", ids[node], mermaidLabel(node)))
+	}
+	for _, edge := range edges {
+		sb.WriteString(fmt.Sprintf("    %s -->|%d| %s\n", ids[edge.from], edge.count, ids[edge.to]))
+	}
+	sb.WriteString("```\n")
+	return sb.String()
+}
+
+// BuildCallGraphDiagram は関数/クラス間の呼び出し関係からMermaidのflowchartを構築する
+// 関数はファイルごとの subgraph にまとめ、呼び出し関係がない場合は空文字を返す
+func BuildCallGraphDiagram(edges []*CallEdge) string {
+	if len(edges) == 0 {
+		return ""
+	}
+
+	type node struct {
+		name, path string
+	}
+	nodes := make(map[string]node)
+	for _, edge := range edges {
+		nodes[edge.FromChunkID.String()] = node{name: edge.FromName, path: edge.FromPath}
+		nodes[edge.ToChunkID.String()] = node{name: edge.ToName, path: edge.ToPath}
+	}
+
+	keys := make([]string, 0, len(nodes))
+	for key := range nodes {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b string) int {
+		return cmp.Or(cmp.Compare(nodes[a].path, nodes[b].path), cmp.Compare(nodes[a].name, nodes[b].name), cmp.Compare(a, b))
+	})
+
+	ids := make(map[string]string, len(keys))
+	var sb strings.Builder
+	sb.WriteString("```mermaid\nflowchart LR\n")
+	subgraphs := 0
+	for i, key := range keys {
+		n := nodes[key]
+		if i == 0 || n.path != nodes[keys[i-1]].path {
+			if i > 0 {
+				sb.WriteString("    end\n")
+			}
+			sb.WriteString(fmt.Sprintf("    subgraph f%d[\"%s\"]\n", subgraphs, mermaidLabel(n.path)))
+			subgraphs++
+		}
+		ids[key] = fmt.Sprintf("c%d", i)
+		sb.WriteString(fmt.Sprintf("        %s[\"%s\"]\n", ids[key], mermaidLabel(n.name)))
+	}
+	sb.WriteString("    end\n")
+
+	seen := make(map[[2]string]bool, len(edges))
+	for _, edge := range edges {
+		from, to := ids[edge.FromChunkID.String()], ids[edge.ToChunkID.String()]
+		if seen[[2]string{from, to}] {
+			continue
+		}
+		seen[[2]string{from, to}] = true
+		sb.WriteString(fmt.Sprintf("    %s --> %s\n", from, to))
+	}
+	sb.WriteString("```\n")
+	return sb.String()
+}
+
+// moduleOf はファイルパスが属するディレクトリ（パッケージ）を返す（ルート直下は "."）
+func moduleOf(filePath string) string {
+	return path.Dir(filePath)
+}
+
+// topNodes は次数の大きい順（同値は名前順）に最大 limit 件のノードを返す
+func topNodes(degree map[string]int, limit int) []string {
+	nodes := make([]string, 0, len(degree))
+	for node := range degree {
+		nodes = append(nodes, node)
+	}
+	slices.SortFunc(nodes, func(a, b string) int {
+		return cmp.Or(cmp.Compare(degree[b], degree[a]), cmp.Compare(a, b))
+	})
+	if limit > 0 && len(nodes) > limit {
+		nodes = nodes[:limit]
+	}
+	slices.Sort(nodes)
+	return nodes
+}
+
+// mermaidLabel はMermaidのラベルで解釈される文字をエスケープする
+func mermaidLabel(s string) string {
+	return strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;").Replace(s)
+}
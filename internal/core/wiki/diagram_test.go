@@ -0,0 +1,59 @@
+package wiki
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildModuleDiagram(t *testing.T) {
+	t.Run("ファイル間の依存関係をディレクトリ単位に集約する", func(t *testing.T) {
+		diagram := BuildModuleDiagram([]*FileDependency{
+			{FromPath: "cmd/main.go", ToPath: "internal/search/service.go", DepType: "call", Count: 2},
+			{FromPath: "cmd/main.go", ToPath: "internal/search/repo.go", DepType: "type", Count: 1},
+			{FromPath: "internal/search/service.go", ToPath: "internal/search/repo.go", DepType: "call", Count: 5},
+		}, 10)
+
+		assert.Equal(t, "```mermaid\nflowchart LR\n"+
+			"    m0[\"cmd\"]\n"+
+			"    m1[\"internal/search\"]\n"+
+			"    m0 -->|3| m1\n"+
+			"```\n", diagram)
+	})
+
+	t.Run("依存の多いディレクトリに絞る", func(t *testing.T) {
+		diagram := BuildModuleDiagram([]*FileDependency{
+			{FromPath: "a/x.go", ToPath: "b/y.go", Count: 10},
+			{FromPath: "c/z.go", ToPath: "b/y.go", Count: 1},
+		}, 2)
+
+		assert.Contains(t, diagram, `["a"]`)
+		assert.Contains(t, diagram, `["b"]`)
+		assert.NotContains(t, diagram, `["c"]`)
+	})
+
+	t.Run("ディレクトリ間の依存がなければ空文字", func(t *testing.T) {
+		assert.Empty(t, BuildModuleDiagram([]*FileDependency{
+			{FromPath: "a/x.go", ToPath: "a/y.go", Count: 3},
+		}, 10))
+	})
+}
+
+func TestBuildCallGraphDiagram(t *testing.T) {
+	handler, search, embed := uuid.New(), uuid.New(), uuid.New()
+
+	diagram := BuildCallGraphDiagram([]*CallEdge{
+		{FromChunkID: handler, FromName: "Handler.Search", FromPath: "server/handler.go", ToChunkID: search, ToName: "Service.Search", ToPath: "search/service.go"},
+		{FromChunkID: search, FromName: "Service.Search", FromPath: "search/service.go", ToChunkID: embed, ToName: "Embed<T>", ToPath: "search/service.go"},
+	})
+
+	// ノードはファイルパス・名前の順に並ぶ（c0: Embed, c1: Service.Search, c2: Handler.Search）
+	assert.Equal(t, 2, strings.Count(diagram, "subgraph "))
+	assert.Contains(t, diagram, `subgraph f1["server/handler.go"]`)
+	assert.Contains(t, diagram, `c0["Embed#lt;T#gt;"]`)
+	assert.Contains(t, diagram, "    c2 --> c1\n")
+	assert.Contains(t, diagram, "    c1 --> c0\n")
+	assert.Empty(t, BuildCallGraphDiagram(nil))
+}
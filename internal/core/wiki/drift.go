@@ -0,0 +1,98 @@
+package wiki
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jinford/dev-rag/internal/core/drift"
+)
+
+// generateArchitectureChangesSection は依存関係グラフを直前のスナップショットと比較した結果からセクションのページを生成する
+func (s *WikiService) generateArchitectureChangesSection(ctx context.Context, params GenerateParams, config SectionConfig) (*WikiPage, error) {
+	if s.drift == nil {
+		return nil, fmt.Errorf("architecture drift reader is not configured")
+	}
+
+	productID, snapshotID := params.scope()
+	reports, err := s.drift.RecentChanges(ctx, productID, snapshotID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect architecture changes: %w", err)
+	}
+
+	return &WikiPage{
+		Section:  config.Section,
+		Title:    config.Title,
+		FileName: config.FileName,
+		Content:  RenderArchitectureChanges(config, reports),
+	}, nil
+}
+
+// RenderArchitectureChanges は最近のアーキテクチャの変更のページを生成する
+// ソースごとに、循環依存の発生・モジュール間の新しい依存・解消した依存・重要度の変動の順に載せる
+func RenderArchitectureChanges(config SectionConfig, reports []*drift.Report) string {
+	var sb strings.Builder
+	if config.Description != "" {
+		sb.WriteString(config.Description)
+		sb.WriteString("\n\n")
+	}
+
+	if len(reports) == 0 {
+		sb.WriteString("比較できる過去のスナップショットがありません（2回目以降のインデックス化の後に生成されます）。\n")
+		return sb.String()
+	}
+
+	for _, r := range reports {
+		sb.WriteString(fmt.Sprintf("## %s（`%s` → `%s`）\n\n", r.SourceName, drift.ShortVersion(r.FromVersion), drift.ShortVersion(r.ToVersion)))
+		if r.Empty() {
+			sb.WriteString("モジュール間の依存・重要度に大きな変化はありません。\n\n")
+			continue
+		}
+
+		if len(r.NewCycles) > 0 {
+			sb.WriteString("### 循環依存の発生\n\n")
+			for _, cycle := range r.NewCycles {
+				modules := make([]string, len(cycle))
+				for i, m := range cycle {
+					modules[i] = "`" + m + "`"
+				}
+				sb.WriteString("- " + strings.Join(modules, " ⇄ ") + "\n")
+			}
+			sb.WriteString("\n")
+		}
+
+		if len(r.NewDependencies) > 0 {
+			sb.WriteString("### モジュール間の新しい依存\n\n")
+			writeModuleDependencies(&sb, r.NewDependencies)
+		}
+		if len(r.RemovedDependencies) > 0 {
+			sb.WriteString("### 解消したモジュール間の依存\n\n")
+			writeModuleDependencies(&sb, r.RemovedDependencies)
+		}
+
+		if len(r.ImportanceShifts) > 0 {
+			sb.WriteString("### 重要度の変動\n\n")
+			sb.WriteString("| 名前 | ファイル | 変更前 | 変更後 | 増減 |\n")
+			sb.WriteString("|---|---|---|---|---|\n")
+			for _, shift := range r.ImportanceShifts {
+				sb.WriteString(fmt.Sprintf("| %s | `%s` | %.2f | %.2f | %+.2f |\n",
+					escapeTableCell(shift.Name), shift.FilePath, shift.From, shift.To, shift.Delta()))
+			}
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+func writeModuleDependencies(sb *strings.Builder, deps []*drift.ModuleDependency) {
+	sb.WriteString("| 依存元 | 依存先 | 依存数 | 例 |\n")
+	sb.WriteString("|---|---|---|---|\n")
+	for _, dep := range deps {
+		examples := make([]string, len(dep.Examples))
+		for i, example := range dep.Examples {
+			examples[i] = "`" + example + "`"
+		}
+		sb.WriteString(fmt.Sprintf("| `%s` | `%s` | %d | %s |\n", dep.From, dep.To, dep.Edges, strings.Join(examples, "<br>")))
+	}
+	sb.WriteString("\n")
+}
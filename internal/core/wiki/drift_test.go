@@ -0,0 +1,35 @@
+package wiki
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jinford/dev-rag/internal/core/drift"
+)
+
+func TestRenderArchitectureChanges(t *testing.T) {
+	content := RenderArchitectureChanges(SectionConfig{Description: "最近の変化"}, []*drift.Report{
+		{
+			SourceName:  "backend",
+			FromVersion: "0123456789abcdef",
+			ToVersion:   "fedcba9876543210",
+			NewDependencies: []*drift.ModuleDependency{
+				{From: "repo", To: "api", Edges: 2, Examples: []string{"repo/user.go -> api/handler.go"}},
+			},
+			NewCycles:        [][]string{{"api", "repo"}},
+			ImportanceShifts: []*drift.ImportanceShift{{FilePath: "service/user.go", Name: "UserService.Get", From: 0.1, To: 0.8}},
+		},
+		{SourceName: "frontend", FromVersion: "a", ToVersion: "b"},
+	})
+
+	assert.Contains(t, content, "最近の変化")
+	assert.Contains(t, content, "## backend（`0123456789ab` → `fedcba987654`）")
+	assert.Contains(t, content, "- `api` ⇄ `repo`")
+	assert.Contains(t, content, "| `repo` | `api` | 2 | `repo/user.go -> api/handler.go` |")
+	assert.Contains(t, content, "| UserService.Get | `service/user.go` | 0.10 | 0.80 | +0.70 |")
+	assert.Contains(t, content, "## frontend（`a` → `b`）\n\nモジュール間の依存・重要度に大きな変化はありません。")
+
+	empty := RenderArchitectureChanges(SectionConfig{}, nil)
+	assert.Contains(t, empty, "比較できる過去のスナップショットがありません")
+}
@@ -0,0 +1,279 @@
+package wiki
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// gitCommitMessageMaxFiles はコミットメッセージに列挙するページ数の上限（種類ごと）
+const gitCommitMessageMaxFiles = 50
+
+// GitWorkspace はWikiを公開するGitリポジトリを操作するインターフェース
+type GitWorkspace interface {
+	// Checkout はリポジトリを dir にクローンして branch をチェックアウトし、チェックアウトしたブランチ名を返す
+	// branch が空の場合はリモートのデフォルトブランチ、リモートに存在しない場合はデフォルトブランチから作成する
+	Checkout(ctx context.Context, url, branch, dir string) (string, error)
+	// Commit は作業ツリーの変更（追加・削除を含む）をすべてコミットし、コミットハッシュを返す
+	Commit(ctx context.Context, dir, message string) (string, error)
+	// Push はブランチをリモートにプッシュする
+	Push(ctx context.Context, dir, branch string) error
+}
+
+// GitPublishParams はGitリポジトリへのWiki公開のパラメータ
+type GitPublishParams struct {
+	ProductName string
+	RepoURL     string
+	Branch      string // 公開先のブランチ（空の場合はリモートのデフォルトブランチ）
+	WikiDir     string // 生成済みWikiのディレクトリ
+	TargetDir   string // リポジトリ内の配置先ディレクトリ（空の場合はリポジトリのルート）
+	DryRun      bool   // コミット・プッシュせず、変更内容のみを返す
+}
+
+// GitPublishResult はGitリポジトリへのWiki公開の結果（リポジトリ内のパス）
+type GitPublishResult struct {
+	Branch     string
+	CommitHash string // 変更がない場合・ドライランの場合は空
+	Added      []string
+	Modified   []string
+	Deleted    []string
+}
+
+// HasChanges は公開によってリポジトリの内容が変わるかを返す
+func (r *GitPublishResult) HasChanges() bool {
+	return len(r.Added)+len(r.Modified)+len(r.Deleted) > 0
+}
+
+// GitPublisher は生成済みのWikiをGitリポジトリにコミットする
+type GitPublisher struct {
+	workspace GitWorkspace
+	logger    *slog.Logger
+}
+
+// GitPublisherOption は GitPublisher のオプション設定
+type GitPublisherOption func(*GitPublisher)
+
+// WithGitPublisherLogger は GitPublisher にロガーを設定する
+func WithGitPublisherLogger(logger *slog.Logger) GitPublisherOption {
+	return func(p *GitPublisher) {
+		p.logger = logger
+	}
+}
+
+// NewGitPublisher は新しい GitPublisher を作成する
+func NewGitPublisher(workspace GitWorkspace, opts ...GitPublisherOption) *GitPublisher {
+	p := &GitPublisher{
+		workspace: workspace,
+		logger:    slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if p.logger == nil {
+		p.logger = slog.Default()
+	}
+	return p
+}
+
+// Publish はWikiディレクトリのMarkdownページをリポジトリの TargetDir 配下に同じ相対パスで配置してコミット・プッシュする
+// TargetDir 配下のMarkdownはWikiの内容と完全に一致させ（Wikiに存在しないページは削除する）、それ以外のファイルには触れない
+// 変更がない場合はコミットしない
+func (p *GitPublisher) Publish(ctx context.Context, params GitPublishParams) (*GitPublishResult, error) {
+	if params.RepoURL == "" {
+		return nil, fmt.Errorf("repoURL is required")
+	}
+	targetDir, err := cleanTargetDir(params.TargetDir)
+	if err != nil {
+		return nil, err
+	}
+
+	workDir, err := os.MkdirTemp("", "dev-rag-wiki-publish-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create work directory: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	branch, err := p.workspace.Checkout(ctx, params.RepoURL, params.Branch, workDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to checkout repository: %w", err)
+	}
+
+	result, err := syncWikiFiles(params.WikiDir, workDir, targetDir)
+	if err != nil {
+		return nil, err
+	}
+	result.Branch = branch
+
+	if !result.HasChanges() {
+		p.logger.Info("wiki is up to date", "repo", params.RepoURL, "branch", branch)
+		return result, nil
+	}
+	if params.DryRun {
+		return result, nil
+	}
+
+	hash, err := p.workspace.Commit(ctx, workDir, BuildGitCommitMessage(params.ProductName, result))
+	if err != nil {
+		return nil, fmt.Errorf("failed to commit wiki: %w", err)
+	}
+	if err := p.workspace.Push(ctx, workDir, branch); err != nil {
+		return nil, fmt.Errorf("failed to push wiki: %w", err)
+	}
+	result.CommitHash = hash
+
+	p.logger.Info("wiki published", "repo", params.RepoURL, "branch", branch, "commit", hash)
+	return result, nil
+}
+
+// BuildGitCommitMessage は変更されたページを要約したコミットメッセージを生成する
+func BuildGitCommitMessage(productName string, result *GitPublishResult) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Update %s wiki (%d added, %d modified, %d deleted)\n",
+		productName, len(result.Added), len(result.Modified), len(result.Deleted)))
+
+	for _, group := range []struct {
+		label string
+		paths []string
+	}{
+		{"Added", result.Added},
+		{"Modified", result.Modified},
+		{"Deleted", result.Deleted},
+	} {
+		if len(group.paths) == 0 {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("\n%s:\n", group.label))
+		for i, p := range group.paths {
+			if i == gitCommitMessageMaxFiles {
+				sb.WriteString(fmt.Sprintf("- ... and %d more\n", len(group.paths)-i))
+				break
+			}
+			sb.WriteString(fmt.Sprintf("- %s\n", p))
+		}
+	}
+
+	sb.WriteString("\nGenerated by dev-rag.\n")
+	return sb.String()
+}
+
+// syncWikiFiles はWikiディレクトリのMarkdownをリポジトリの targetDir 配下に書き込み、差分をパス順で返す
+func syncWikiFiles(wikiDir, repoDir, targetDir string) (*GitPublishResult, error) {
+	local, err := readMarkdownFiles(wikiDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read wiki directory: %w", err)
+	}
+	if len(local) == 0 {
+		return nil, fmt.Errorf("no markdown pages found in %s", wikiDir)
+	}
+
+	targetRoot := filepath.Join(repoDir, filepath.FromSlash(targetDir))
+	existing, err := readMarkdownFiles(targetRoot)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read target directory: %w", err)
+	}
+
+	result := &GitPublishResult{}
+	for _, rel := range sortedKeys(local) {
+		content := local[rel]
+		old, ok := existing[rel]
+		if ok && bytes.Equal(old, content) {
+			continue
+		}
+
+		dest := filepath.Join(targetRoot, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create directory: %w", err)
+		}
+		if err := os.WriteFile(dest, content, 0o644); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", rel, err)
+		}
+		if ok {
+			result.Modified = append(result.Modified, path.Join(targetDir, rel))
+		} else {
+			result.Added = append(result.Added, path.Join(targetDir, rel))
+		}
+	}
+
+	for _, rel := range sortedKeys(existing) {
+		if _, ok := local[rel]; ok {
+			continue
+		}
+		if err := os.Remove(filepath.Join(targetRoot, filepath.FromSlash(rel))); err != nil {
+			return nil, fmt.Errorf("failed to remove %s: %w", rel, err)
+		}
+		result.Deleted = append(result.Deleted, path.Join(targetDir, rel))
+	}
+
+	return result, nil
+}
+
+// readMarkdownFiles はディレクトリ配下の .md ファイルを相対パス（スラッシュ区切り）をキーに読み込む
+// ドットで始まるディレクトリ（.git など）は読み込まない
+func readMarkdownFiles(dir string) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p != dir && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(p) != ".md" {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		content, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = content
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// cleanTargetDir はリポジトリ内の配置先ディレクトリを検証して正規化する（ルートは空文字）
+func cleanTargetDir(dir string) (string, error) {
+	dir = strings.Trim(filepath.ToSlash(dir), "/")
+	if dir == "" {
+		return "", nil
+	}
+	cleaned := path.Clean(dir)
+	if cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", fmt.Errorf("target directory must be inside the repository: %s", dir)
+	}
+	if cleaned == "." {
+		return "", nil
+	}
+	for _, elem := range strings.Split(cleaned, "/") {
+		if strings.HasPrefix(elem, ".") {
+			return "", fmt.Errorf("target directory must not contain hidden directories: %s", dir)
+		}
+	}
+	return cleaned, nil
+}
+
+func sortedKeys(files map[string][]byte) []string {
+	keys := make([]string, 0, len(files))
+	for key := range files {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
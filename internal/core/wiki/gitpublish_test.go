@@ -0,0 +1,145 @@
+package wiki
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeGitWorkspace はリモートの内容（パス→内容）を作業ディレクトリに展開し、コミットされた内容を記録する
+type fakeGitWorkspace struct {
+	remote   map[string]string
+	messages []string
+	pushed   []string
+}
+
+func (w *fakeGitWorkspace) Checkout(ctx context.Context, url, branch, dir string) (string, error) {
+	for name, content := range w.remote {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			return "", err
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			return "", err
+		}
+	}
+	if branch == "" {
+		branch = "main"
+	}
+	return branch, nil
+}
+
+func (w *fakeGitWorkspace) Commit(ctx context.Context, dir, message string) (string, error) {
+	files, err := readMarkdownFiles(dir)
+	if err != nil {
+		return "", err
+	}
+	w.remote = make(map[string]string, len(files))
+	for name, content := range files {
+		w.remote[name] = string(content)
+	}
+	w.messages = append(w.messages, message)
+	return "abc123", nil
+}
+
+func (w *fakeGitWorkspace) Push(ctx context.Context, dir, branch string) error {
+	w.pushed = append(w.pushed, branch)
+	return nil
+}
+
+func TestGitPublisher_Publish(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	writeWikiFiles(t, dir, map[string]string{
+		"README.md":               "# demo\n",
+		"architecture.md":         "# アーキテクチャ\n",
+		"api-reference/search.md": "## パッケージ `search`\n",
+	})
+
+	workspace := &fakeGitWorkspace{remote: map[string]string{
+		"README.md":                 "# リポジトリ\n",
+		"wiki/demo/architecture.md": "# 古いアーキテクチャ\n",
+		"wiki/demo/removed.md":      "# 削除されたページ\n",
+		"wiki/other/README.md":      "# 別のプロダクト\n",
+	}}
+	publisher := NewGitPublisher(workspace)
+	params := GitPublishParams{ProductName: "demo", RepoURL: "git@example.com:org/docs.git", Branch: "docs", WikiDir: dir, TargetDir: "wiki/demo"}
+
+	result, err := publisher.Publish(ctx, params)
+	require.NoError(t, err)
+	assert.Equal(t, "docs", result.Branch)
+	assert.Equal(t, "abc123", result.CommitHash)
+	assert.Equal(t, []string{"wiki/demo/README.md", "wiki/demo/api-reference/search.md"}, result.Added)
+	assert.Equal(t, []string{"wiki/demo/architecture.md"}, result.Modified)
+	assert.Equal(t, []string{"wiki/demo/removed.md"}, result.Deleted)
+	assert.Equal(t, []string{"docs"}, workspace.pushed)
+
+	// 配置先以外のファイルには触れない
+	assert.Equal(t, "# リポジトリ\n", workspace.remote["README.md"])
+	assert.Equal(t, "# 別のプロダクト\n", workspace.remote["wiki/other/README.md"])
+	assert.Equal(t, "# アーキテクチャ\n", workspace.remote["wiki/demo/architecture.md"])
+
+	require.Len(t, workspace.messages, 1)
+	assert.Contains(t, workspace.messages[0], "Update demo wiki (2 added, 1 modified, 1 deleted)\n")
+	assert.Contains(t, workspace.messages[0], "\nDeleted:\n- wiki/demo/removed.md\n")
+
+	// 変更がなければコミットしない
+	result, err = publisher.Publish(ctx, params)
+	require.NoError(t, err)
+	assert.False(t, result.HasChanges())
+	assert.Empty(t, result.CommitHash)
+	assert.Len(t, workspace.messages, 1)
+}
+
+func TestGitPublisher_PublishDryRun(t *testing.T) {
+	dir := t.TempDir()
+	writeWikiFiles(t, dir, map[string]string{"README.md": "# demo\n"})
+
+	workspace := &fakeGitWorkspace{}
+	result, err := NewGitPublisher(workspace).Publish(context.Background(), GitPublishParams{
+		ProductName: "demo", RepoURL: "https://example.com/org/repo.wiki.git", WikiDir: dir, DryRun: true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "main", result.Branch)
+	assert.Equal(t, []string{"README.md"}, result.Added)
+	assert.Empty(t, workspace.messages)
+	assert.Empty(t, workspace.pushed)
+}
+
+func TestCleanTargetDir(t *testing.T) {
+	for _, tc := range []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "", want: ""},
+		{in: "/", want: ""},
+		{in: "docs/wiki/", want: "docs/wiki"},
+		{in: "docs/./wiki", want: "docs/wiki"},
+		{in: "../outside", wantErr: true},
+		{in: "docs/../../outside", wantErr: true},
+		{in: ".git/hooks", wantErr: true},
+	} {
+		got, err := cleanTargetDir(tc.in)
+		if tc.wantErr {
+			assert.Error(t, err, tc.in)
+			continue
+		}
+		require.NoError(t, err, tc.in)
+		assert.Equal(t, tc.want, got, tc.in)
+	}
+}
+
+func TestBuildGitCommitMessage_TruncatesLongLists(t *testing.T) {
+	result := &GitPublishResult{}
+	for i := 0; i < gitCommitMessageMaxFiles+3; i++ {
+		result.Added = append(result.Added, filepath.Join("wiki", string(rune('a'+i%26))+".md"))
+	}
+
+	message := BuildGitCommitMessage("demo", result)
+	assert.Contains(t, message, "- ... and 3 more\n")
+}
@@ -0,0 +1,83 @@
+package wiki
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jinford/dev-rag/internal/core/glossary"
+)
+
+// glossaryKindHeadings は用語の種類ごとの見出し
+var glossaryKindHeadings = map[glossary.Kind]string{
+	glossary.KindTerm:    "用語",
+	glossary.KindAcronym: "略語",
+	glossary.KindEntity:  "エンティティ",
+}
+
+// generateGlossarySection はプロダクトの用語集からセクションのページを生成する
+// 用語集はプロダクト単位で抽出するため、スナップショット単位の生成では使用できない
+func (s *WikiService) generateGlossarySection(ctx context.Context, params GenerateParams, config SectionConfig) (*WikiPage, error) {
+	if s.glossary == nil {
+		return nil, fmt.Errorf("glossary reader is not configured")
+	}
+	productID, ok := params.ProductID.Get()
+	if !ok {
+		return nil, fmt.Errorf("glossary section requires a product")
+	}
+
+	terms, err := s.glossary.ListTerms(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list glossary terms: %w", err)
+	}
+
+	return &WikiPage{
+		Section:  config.Section,
+		Title:    config.Title,
+		FileName: config.FileName,
+		Content:  RenderGlossary(config, terms),
+	}, nil
+}
+
+// RenderGlossary は用語集のページを生成する（種類ごとに用語順で並べる）
+func RenderGlossary(config SectionConfig, terms []*glossary.Term) string {
+	var sb strings.Builder
+	if config.Description != "" {
+		sb.WriteString(config.Description)
+		sb.WriteString("\n\n")
+	}
+
+	if len(terms) == 0 {
+		sb.WriteString("用語集はまだ作成されていません。`dev-rag glossary extract` で抽出してください。\n")
+		return sb.String()
+	}
+
+	for _, kind := range glossary.Kinds {
+		var members []*glossary.Term
+		for _, term := range terms {
+			if term.Kind == kind {
+				members = append(members, term)
+			}
+		}
+		if len(members) == 0 {
+			continue
+		}
+
+		sb.WriteString(fmt.Sprintf("## %s\n\n", glossaryKindHeadings[kind]))
+		for _, term := range members {
+			sb.WriteString(fmt.Sprintf("### %s\n\n", term.Term))
+			sb.WriteString(strings.TrimSpace(term.Definition))
+			sb.WriteString("\n\n")
+			if len(term.Aliases) > 0 {
+				sb.WriteString(fmt.Sprintf("- 別名: %s\n", strings.Join(term.Aliases, ", ")))
+			}
+			if len(term.SourcePaths) > 0 {
+				sb.WriteString(fmt.Sprintf("- 出典: `%s`\n", strings.Join(term.SourcePaths, "`, `")))
+			}
+			if len(term.Aliases) > 0 || len(term.SourcePaths) > 0 {
+				sb.WriteString("\n")
+			}
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,28 @@
+package wiki
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jinford/dev-rag/internal/core/glossary"
+)
+
+func TestRenderGlossary(t *testing.T) {
+	content := RenderGlossary(SectionConfig{Description: "用語の定義"}, []*glossary.Term{
+		{Term: "Order", Kind: glossary.KindEntity, Definition: "顧客の注文。", SourcePaths: []string{"docs/order.md", "order/model.go"}},
+		{Term: "SLO", Kind: glossary.KindAcronym, Definition: "サービスの信頼性目標。", Aliases: []string{"Service Level Objective"}},
+		{Term: "在庫引当", Kind: glossary.KindTerm, Definition: "注文に対して在庫を確保すること。"},
+	})
+
+	assert.Contains(t, content, "用語の定義\n\n## 用語\n\n### 在庫引当\n\n注文に対して在庫を確保すること。\n\n## 略語")
+	assert.Contains(t, content, "### SLO\n\nサービスの信頼性目標。\n\n- 別名: Service Level Objective\n")
+	assert.Contains(t, content, "- 出典: `docs/order.md`, `order/model.go`\n")
+	assert.Less(t, strings.Index(content, "## 略語"), strings.Index(content, "## エンティティ"))
+}
+
+func TestRenderGlossary_Empty(t *testing.T) {
+	content := RenderGlossary(SectionConfig{}, nil)
+	assert.Contains(t, content, "dev-rag glossary extract")
+}
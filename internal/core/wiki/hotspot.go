@@ -0,0 +1,51 @@
+package wiki
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jinford/dev-rag/internal/core/hotspot"
+)
+
+// generateHotspotsSection は関数の複雑度・行数・編集頻度・コメント比率から順位付けしたホットスポットからセクションのページを生成する
+// 掲載する件数は chunk_limit とする
+func (s *WikiService) generateHotspotsSection(ctx context.Context, params GenerateParams, config SectionConfig) (*WikiPage, error) {
+	if s.hotspots == nil {
+		return nil, fmt.Errorf("hotspot reader is not configured")
+	}
+
+	productID, snapshotID := params.scope()
+	report, err := s.hotspots.Report(ctx, productID, snapshotID, hotspot.Query{Level: hotspot.LevelFunction, Limit: config.ChunkLimit})
+	if err != nil {
+		return nil, fmt.Errorf("failed to rank hotspots: %w", err)
+	}
+
+	return &WikiPage{
+		Section:  config.Section,
+		Title:    config.Title,
+		FileName: config.FileName,
+		Content:  RenderHotspots(config, report),
+	}, nil
+}
+
+// RenderHotspots は変更のリスクが高い関数の一覧のページを生成する
+func RenderHotspots(config SectionConfig, report *hotspot.Report) string {
+	var sb strings.Builder
+	if config.Description != "" {
+		sb.WriteString(config.Description)
+		sb.WriteString("\n\n")
+	}
+
+	if len(report.Hotspots) == 0 {
+		sb.WriteString("順位付けの対象となる関数がありません。\n")
+		return sb.String()
+	}
+
+	w := report.Weights
+	sb.WriteString(fmt.Sprintf("スコアは循環的複雑度（%.2f）・コード行数（%.2f）・編集回数（%.2f）・コメントの少なさ（%.2f）を重み付けして合算した値（0〜1）です。\n\n",
+		w.Complexity, w.LinesOfCode, w.EditFrequency, w.LowComment))
+	// strings.Builder への書き込みは失敗しない
+	_ = hotspot.Write(&sb, report, hotspot.FormatMarkdown)
+	return sb.String()
+}
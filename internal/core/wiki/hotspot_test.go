@@ -0,0 +1,26 @@
+package wiki
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jinford/dev-rag/internal/core/hotspot"
+)
+
+func TestRenderHotspots(t *testing.T) {
+	content := RenderHotspots(SectionConfig{Description: "リスクの高い関数"}, &hotspot.Report{
+		Level:   hotspot.LevelFunction,
+		Weights: hotspot.DefaultWeights,
+		Hotspots: []*hotspot.Hotspot{
+			{Rank: 1, SourceName: "backend", FilePath: "service/user.go", Name: "UserService.Update", StartLine: 10, Complexity: 20, LinesOfCode: 60, EditCount: 8, CommentRatio: 0.05, Score: 0.9925},
+		},
+	})
+
+	assert.Contains(t, content, "リスクの高い関数")
+	assert.Contains(t, content, "循環的複雑度（0.35）")
+	assert.Contains(t, content, "| 1 | 0.993 | backend | UserService.Update | `service/user.go:10` | 20 | 60 | 8 | 0.05 |")
+
+	empty := RenderHotspots(SectionConfig{}, &hotspot.Report{Level: hotspot.LevelFunction})
+	assert.Contains(t, empty, "順位付けの対象となる関数がありません")
+}
@@ -0,0 +1,173 @@
+package wiki
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/jinford/dev-rag/internal/core/llm"
+	"github.com/jinford/dev-rag/internal/core/prompt"
+)
+
+// Language はWikiの言語（ISO 639-1 の言語コード）
+type Language string
+
+const (
+	LanguageJapanese Language = "ja"
+	LanguageEnglish  Language = "en"
+	LanguageChinese  Language = "zh"
+	LanguageKorean   Language = "ko"
+
+	// DefaultLanguage はセクションを生成する言語（他の言語のページはこの言語のページを翻訳して作成する）
+	DefaultLanguage = LanguageJapanese
+)
+
+// translateMaxTokens は1ページの翻訳の最大出力トークン数
+const translateMaxTokens = 8192
+
+// languageNames は翻訳のプロンプトに渡す言語の名前
+var languageNames = map[Language]string{
+	LanguageJapanese: "日本語",
+	LanguageEnglish:  "English",
+	LanguageChinese:  "简体中文",
+	LanguageKorean:   "한국어",
+}
+
+// Languages は対応している言語を返す
+func Languages() []Language {
+	return []Language{LanguageJapanese, LanguageEnglish, LanguageChinese, LanguageKorean}
+}
+
+// ParseLanguages は言語コードの一覧を検証し、重複を除いて指定された順に返す
+func ParseLanguages(codes []string) ([]Language, error) {
+	var languages []Language
+	for _, code := range codes {
+		lang := Language(strings.ToLower(strings.TrimSpace(code)))
+		if _, ok := languageNames[lang]; !ok {
+			supported := make([]string, 0, len(languageNames))
+			for _, l := range Languages() {
+				supported = append(supported, string(l))
+			}
+			return nil, fmt.Errorf("unsupported language %q (supported: %s)", code, strings.Join(supported, ", "))
+		}
+		if !slices.Contains(languages, lang) {
+			languages = append(languages, lang)
+		}
+	}
+	if len(languages) == 0 {
+		return nil, fmt.Errorf("at least one language is required")
+	}
+	return languages, nil
+}
+
+// LanguageDir は言語ごとのWikiの出力ディレクトリ（<wikiDir>/<言語>）を返す
+func LanguageDir(wikiDir string, lang Language) string {
+	return filepath.Join(wikiDir, string(lang))
+}
+
+// GenerateLanguages はセクションを DefaultLanguage で1回だけ生成し、言語ごとに <OutputDir>/<言語>/ へ書き出す
+// DefaultLanguage 以外の言語のページは生成したページを翻訳して作成するため、どの言語もページの構成（ファイル・見出し）が同じになる
+// 参照チェックの結果・prompt-versions.json・toc.json は言語ごとのディレクトリに書き出す
+func (s *WikiService) GenerateLanguages(ctx context.Context, params GenerateParams, languages []Language) ([]*GenerateResult, error) {
+	if len(languages) == 0 {
+		return nil, fmt.Errorf("at least one language is required")
+	}
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+
+	pages := s.generatePages(ctx, params)
+	results := make([]*GenerateResult, 0, len(languages))
+	for _, lang := range languages {
+		var localized []*WikiPage
+		if lang == DefaultLanguage {
+			for _, page := range pages {
+				original := *page
+				original.Language = lang
+				localized = append(localized, &original)
+			}
+		} else {
+			localized = s.translatePages(ctx, params, pages, lang)
+		}
+		result, err := s.writeWiki(ctx, params, LanguageDir(params.OutputDir, lang), lang, localized)
+		if err != nil {
+			return nil, fmt.Errorf("failed to write %s wiki: %w", lang, err)
+		}
+		result.Language = lang
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// translatePages はページを lang に翻訳する（最大 params.Concurrency 件ずつ並行して翻訳する）
+// 翻訳に失敗したページは構成を揃えるため原文のまま出力する
+func (s *WikiService) translatePages(ctx context.Context, params GenerateParams, pages []*WikiPage, lang Language) []*WikiPage {
+	concurrency := params.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+
+	translated := make([]*WikiPage, len(pages))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, page := range pages {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			var result *WikiPage
+			err := s.withRetry(ctx, "retrying wiki page translation", page.Section, func() error {
+				var err error
+				result, err = s.translatePage(ctx, page, lang)
+				return err
+			})
+			if err != nil {
+				s.logger.Warn("failed to translate wiki page, keeping the original",
+					"page", page.FileName,
+					"language", lang,
+					"error", err,
+				)
+				original := *page
+				original.Language = lang
+				result = &original
+			}
+			translated[i] = result
+		}()
+	}
+	wg.Wait()
+	return translated
+}
+
+// translatePage は1ページを lang に翻訳する
+// 原文が同じ場合はキャッシュした応答を使用するため、変更のないページは再翻訳しない
+func (s *WikiService) translatePage(ctx context.Context, page *WikiPage, lang Language) (*WikiPage, error) {
+	translated := *page
+	translated.Language = lang
+	if strings.TrimSpace(page.Content) == "" {
+		return &translated, nil
+	}
+
+	rendered, err := s.prompts.Render(ctx, prompt.NameWikiTranslate, prompt.WikiTranslateData{
+		Language: languageNames[lang],
+		Content:  page.Content,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to build prompt: %w", err)
+	}
+
+	llmCtx := llm.WithCacheablePrompt(llm.WithMaxTokens(ctx, translateMaxTokens), rendered.Name, rendered.Version)
+	content, err := s.llm.GenerateCompletion(llmCtx, rendered.Text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to translate: %w", err)
+	}
+
+	translated.Content = content
+	translated.PromptName = rendered.Name
+	translated.PromptVersion = rendered.Version
+	return &translated, nil
+}
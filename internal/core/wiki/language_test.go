@@ -0,0 +1,66 @@
+package wiki
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jinford/dev-rag/internal/core/prompt"
+)
+
+func TestParseLanguages(t *testing.T) {
+	languages, err := ParseLanguages([]string{"ja", " EN ", "ja"})
+	require.NoError(t, err)
+	assert.Equal(t, []Language{LanguageJapanese, LanguageEnglish}, languages)
+
+	_, err = ParseLanguages([]string{"ja", "fr"})
+	assert.ErrorContains(t, err, `unsupported language "fr"`)
+
+	_, err = ParseLanguages(nil)
+	assert.Error(t, err)
+}
+
+// translatingLLM は翻訳のプロンプトに含まれる原文を大文字にして返す（fail が true の場合は失敗する）
+type translatingLLM struct {
+	fail bool
+}
+
+func (l *translatingLLM) GenerateCompletion(ctx context.Context, text string) (string, error) {
+	if l.fail {
+		return "", errors.New("llm unavailable")
+	}
+	_, rest, _ := strings.Cut(text, "```markdown\n")
+	content, _, _ := strings.Cut(rest, "\n```")
+	return strings.ToUpper(content), nil
+}
+
+func TestTranslatePages(t *testing.T) {
+	pages := []*WikiPage{
+		{Section: SectionOverview, FileName: "README.md", Content: "## overview\n"},
+		{Section: SectionComponents, FileName: "components.md", Content: ""},
+	}
+
+	svc := &WikiService{llm: &translatingLLM{}, prompts: prompt.Default(), logger: slog.New(slog.DiscardHandler), sectionAttempts: 1}
+	translated := svc.translatePages(context.Background(), GenerateParams{}, pages, LanguageEnglish)
+	require.Len(t, translated, 2)
+	assert.Equal(t, "## OVERVIEW\n", translated[0].Content)
+	assert.Equal(t, LanguageEnglish, translated[0].Language)
+	assert.Equal(t, prompt.NameWikiTranslate, translated[0].PromptName)
+	assert.Equal(t, "README.md", translated[0].FileName)
+	// 空のページは翻訳しない
+	assert.Empty(t, translated[1].Content)
+	assert.Empty(t, translated[1].PromptName)
+	// 元のページは変更しない
+	assert.Empty(t, pages[0].Language)
+
+	// 翻訳に失敗したページは原文のまま出力する
+	svc.llm = &translatingLLM{fail: true}
+	translated = svc.translatePages(context.Background(), GenerateParams{}, pages, LanguageEnglish)
+	assert.Equal(t, "## overview\n", translated[0].Content)
+	assert.Equal(t, LanguageEnglish, translated[0].Language)
+}
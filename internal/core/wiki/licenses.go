@@ -0,0 +1,108 @@
+package wiki
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jinford/dev-rag/internal/core/license"
+)
+
+// licensesMaxFlagged は確認が必要なファイルとしてページに載せる件数の上限（超えた分は件数のみ掲載する）
+const licensesMaxFlagged = 100
+
+// generateLicensesSection はインデックス時に検出したライセンスからセクションのページを生成する
+func (s *WikiService) generateLicensesSection(ctx context.Context, params GenerateParams, config SectionConfig) (*WikiPage, error) {
+	if s.licenses == nil {
+		return nil, fmt.Errorf("license reader is not configured")
+	}
+
+	productID, snapshotID := params.scope()
+	report, err := s.licenses.Report(ctx, productID, snapshotID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize licenses: %w", err)
+	}
+
+	return &WikiPage{
+		Section:  config.Section,
+		Title:    config.Title,
+		FileName: config.FileName,
+		Content:  RenderLicenses(config, report),
+	}, nil
+}
+
+// RenderLicenses はライセンスとコンプライアンスのページを生成する
+// ライセンスごとの集計、ライセンスファイル、確認が必要なファイル（コピーレフト・判別できないライセンス）の順に表にまとめる
+func RenderLicenses(config SectionConfig, report *license.Report) string {
+	var sb strings.Builder
+	if config.Description != "" {
+		sb.WriteString(config.Description)
+		sb.WriteString("\n\n")
+	}
+
+	if report == nil || len(report.Licenses) == 0 {
+		sb.WriteString("ライセンスファイル（LICENSE・COPYING など）・ライセンスヘッダーは見つかりませんでした。\n")
+		return sb.String()
+	}
+
+	var sourceNames []string
+	for _, s := range report.Licenses {
+		sourceNames = append(sourceNames, s.Sources...)
+	}
+	multiSource := hasMultipleSources(sourceNames)
+	location := func(f *license.Finding) string {
+		return "`" + qualifiedFilePath(f.SourceName, f.FilePath, multiSource) + "`"
+	}
+
+	sb.WriteString("## ライセンスの一覧\n\n")
+	sb.WriteString("| ライセンス | 分類 | ライセンスファイル | ライセンスヘッダー | ソース |\n")
+	sb.WriteString("|---|---|---|---|---|\n")
+	for _, s := range report.Licenses {
+		sb.WriteString(fmt.Sprintf("| %s | %s | %d | %d | %s |\n",
+			licenseName(s.LicenseID), categoryLabel(s.Category), s.LicenseFiles, s.HeaderFiles, escapeTableCell(strings.Join(s.Sources, ", "))))
+	}
+	sb.WriteString("\n")
+
+	if len(report.Declared) > 0 {
+		sb.WriteString("## ライセンスファイル\n\n")
+		sb.WriteString("| ファイル | ライセンス | 分類 |\n")
+		sb.WriteString("|---|---|---|\n")
+		for _, f := range report.Declared {
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s |\n", location(f), licenseName(f.LicenseID), categoryLabel(f.Category)))
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("## 確認が必要なライセンス\n\n")
+	if len(report.Flagged) == 0 {
+		sb.WriteString("コピーレフト・判別できないライセンスは見つかりませんでした。\n")
+		return sb.String()
+	}
+	sb.WriteString("コピーレフトのライセンスは配布・提供の形態によってソースコードの開示などの義務が生じる場合があり、判別できないライセンスは利用条件を個別に確認する必要があります。\n\n")
+	sb.WriteString("| 分類 | ライセンス | ファイル | 検出 | 根拠 |\n")
+	sb.WriteString("|---|---|---|---|---|\n")
+	for _, f := range report.Flagged[:min(len(report.Flagged), licensesMaxFlagged)] {
+		sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n",
+			categoryLabel(f.Category), licenseName(f.LicenseID), location(f), f.Kind.Label(), escapeTableCell(f.Evidence)))
+	}
+	if rest := len(report.Flagged) - licensesMaxFlagged; rest > 0 {
+		sb.WriteString(fmt.Sprintf("\n他 %d 件（`dev-rag report licenses` で全件を確認できます）\n", rest))
+	}
+	return sb.String()
+}
+
+// licenseName はライセンス識別子を表のセルに載せる形式で返す（判別できない場合は "不明"）
+func licenseName(id string) string {
+	if id == "" {
+		return "不明"
+	}
+	return "`" + id + "`"
+}
+
+// categoryLabel は確認が必要な分類を強調したライセンスの分類の表示名を返す
+func categoryLabel(c license.Category) string {
+	if c.Flagged() {
+		return "**" + c.Label() + "**"
+	}
+	return c.Label()
+}
@@ -0,0 +1,48 @@
+package wiki
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jinford/dev-rag/internal/core/license"
+)
+
+func TestRenderLicenses(t *testing.T) {
+	report := license.Summarize([]*license.Finding{
+		{SourceName: "api", FilePath: "LICENSE", Kind: license.KindFile, LicenseID: "MIT", Category: license.CategoryPermissive, Evidence: "MIT License"},
+		{SourceName: "api", FilePath: "third_party/x.c", Kind: license.KindHeader, LicenseID: "GPL-2.0", Category: license.CategoryCopyleft, Evidence: "SPDX-License-Identifier: GPL-2.0"},
+		{SourceName: "web", FilePath: "LICENSE", Kind: license.KindFile, Category: license.CategoryUnknown, Evidence: "Copyright (c) Acme | All rights reserved"},
+	})
+	content := RenderLicenses(SectionConfig{Description: "ライセンスの一覧"}, report)
+
+	assert.Contains(t, content, "ライセンスの一覧\n\n## ライセンスの一覧\n")
+	assert.Contains(t, content, "| 不明 | **不明** | 1 | 0 | web |\n")
+	assert.Contains(t, content, "| `GPL-2.0` | **コピーレフト** | 0 | 1 | api |\n")
+	assert.Contains(t, content, "| `MIT` | パーミッシブ | 1 | 0 | api |\n")
+	assert.Contains(t, content, "| `api:LICENSE` | `MIT` | パーミッシブ |\n")
+	assert.Contains(t, content, "| **不明** | 不明 | `web:LICENSE` | ライセンスファイル | Copyright (c) Acme \\| All rights reserved |\n")
+	assert.Contains(t, content, "| **コピーレフト** | `GPL-2.0` | `api:third_party/x.c` | ヘッダー | SPDX-License-Identifier: GPL-2.0 |\n")
+}
+
+func TestRenderLicenses_NoFlagged(t *testing.T) {
+	assert.Contains(t, RenderLicenses(SectionConfig{}, &license.Report{}), "ライセンスファイル（LICENSE・COPYING など）・ライセンスヘッダーは見つかりませんでした。")
+
+	content := RenderLicenses(SectionConfig{}, license.Summarize([]*license.Finding{
+		{SourceName: "api", FilePath: "LICENSE", Kind: license.KindFile, LicenseID: "Apache-2.0", Category: license.CategoryPermissive},
+	}))
+	assert.Contains(t, content, "| `LICENSE` | `Apache-2.0` | パーミッシブ |\n")
+	assert.Contains(t, content, "コピーレフト・判別できないライセンスは見つかりませんでした。")
+}
+
+func TestRenderLicenses_TruncatesFlagged(t *testing.T) {
+	var findings []*license.Finding
+	for i := range licensesMaxFlagged + 5 {
+		findings = append(findings, &license.Finding{SourceName: "api", FilePath: fmt.Sprintf("src/%03d.c", i), Kind: license.KindHeader, LicenseID: "GPL-3.0", Category: license.CategoryCopyleft})
+	}
+	content := RenderLicenses(SectionConfig{}, license.Summarize(findings))
+	assert.Contains(t, content, "`src/099.c`")
+	assert.NotContains(t, content, "`src/100.c`")
+	assert.Contains(t, content, "他 5 件")
+}
@@ -0,0 +1,288 @@
+package wiki
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// LinkReportFileName は参照チェックの結果を書き出すファイル名（公開対象の .md と区別するため JSON で出力する）
+const LinkReportFileName = "link-report.json"
+
+// ReferenceKind は生成ページ内の参照の種類
+type ReferenceKind string
+
+const (
+	// ReferenceWikiLink はWikiページ間のリンク（[タイトル](other.md)）
+	ReferenceWikiLink ReferenceKind = "wiki_link"
+	// ReferenceFilePath はソースファイルのパスの引用（`internal/foo.go`）
+	ReferenceFilePath ReferenceKind = "file_path"
+	// ReferenceLineRange はソースファイルの行番号の引用（`internal/foo.go:12-34`、`internal/foo.go` L12-L34）
+	ReferenceLineRange ReferenceKind = "line_ref"
+)
+
+// SourceFileLines はスナップショットに含まれるファイルのパスと行数
+// LineCount はインデックス済みチャンクの最終行で、チャンクを持たないファイルは 0（行番号を検証しない）
+type SourceFileLines struct {
+	Path      string
+	LineCount int
+}
+
+// BrokenReference は解決できなかった参照
+type BrokenReference struct {
+	Page   string        `json:"page"`
+	Line   int           `json:"line"`
+	Kind   ReferenceKind `json:"kind"`
+	Target string        `json:"target"`
+	Reason string        `json:"reason"`
+}
+
+// LinkReport は生成ページの参照チェックの結果
+type LinkReport struct {
+	CheckedPages      int `json:"checked_pages"`
+	CheckedReferences int `json:"checked_references"`
+	// SourceFilesChecked が false の場合はスナップショットのファイル一覧を取得できず、Wikiページ間のリンクのみ検証した
+	SourceFilesChecked bool              `json:"source_files_checked"`
+	Broken             []BrokenReference `json:"broken"`
+}
+
+// BrokenCount は解決できなかった参照の数を返す
+func (r *LinkReport) BrokenCount() int {
+	if r == nil {
+		return 0
+	}
+	return len(r.Broken)
+}
+
+// Exceeds は解決できなかった参照が maxBroken 件を超えているかを返す
+func (r *LinkReport) Exceeds(maxBroken int) bool {
+	return r.BrokenCount() > maxBroken
+}
+
+var (
+	markdownLinkPattern = regexp.MustCompile(`\[[^\]]*\]\(([^)\s]+)(?:\s+"[^"]*")?\)`)
+	codeSpanPattern     = regexp.MustCompile("`([^`\n]+)`" + `(?:\s*\(?L(\d+)(?:-L?(\d+))?\)?)?`)
+	// source:path:start-end の形式（ソース名とパス以外は省略可能）
+	fileReferencePattern = regexp.MustCompile(`^(?:([A-Za-z0-9_.-]+):)?([A-Za-z0-9_.@+/-]*\.[A-Za-z0-9]+)(?::L?(\d+)(?:-L?(\d+))?)?$`)
+)
+
+// sourceFileExtensions はスラッシュを含まない引用をファイルパスとみなす拡張子
+// `fmt.Println` のような識別子をファイルパスと誤認しないよう、スラッシュを含まない場合はこれらの拡張子に限る
+var sourceFileExtensions = map[string]struct{}{
+	".go": {}, ".py": {}, ".rs": {}, ".java": {}, ".kt": {}, ".rb": {}, ".php": {}, ".c": {}, ".h": {}, ".cc": {}, ".cpp": {},
+	".js": {}, ".jsx": {}, ".ts": {}, ".tsx": {}, ".mjs": {}, ".swift": {}, ".sql": {}, ".proto": {}, ".sh": {},
+	".md": {}, ".yaml": {}, ".yml": {}, ".json": {}, ".toml": {}, ".tf": {}, ".mod": {},
+}
+
+// CheckReferences は生成ページ内のWikiページ間のリンク・ソースファイルのパス・行番号の引用を検証する
+// files が nil の場合はソースファイルの引用を検証せず、Wikiページ間のリンクのみ検証する
+// フェンスで囲まれたコードブロック内と外部URL・ページ内アンカーは対象外とする
+func CheckReferences(pages []*WikiPage, files []*SourceFileLines) *LinkReport {
+	report := &LinkReport{
+		CheckedPages:       len(pages),
+		SourceFilesChecked: files != nil,
+		Broken:             []BrokenReference{},
+	}
+
+	pageNames := make(map[string]struct{}, len(pages))
+	for _, page := range pages {
+		pageNames[path.Clean(page.FileName)] = struct{}{}
+	}
+
+	var lineCounts map[string]int
+	if files != nil {
+		lineCounts = make(map[string]int, len(files))
+		for _, f := range files {
+			// 複数ソースに同じパスがある場合は行数の多い方で検証する
+			if current, ok := lineCounts[f.Path]; !ok || f.LineCount > current {
+				lineCounts[f.Path] = f.LineCount
+			}
+		}
+	}
+
+	for _, page := range pages {
+		inFence := false
+		for i, line := range strings.Split(page.Content, "\n") {
+			if strings.HasPrefix(strings.TrimSpace(line), "```") {
+				inFence = !inFence
+				continue
+			}
+			if inFence {
+				continue
+			}
+
+			lineNo := i + 1
+			for _, m := range markdownLinkPattern.FindAllStringSubmatch(line, -1) {
+				target, ok := wikiLinkTarget(page.FileName, m[1])
+				if !ok {
+					continue
+				}
+				report.CheckedReferences++
+				if _, exists := pageNames[target]; !exists {
+					report.Broken = append(report.Broken, BrokenReference{
+						Page: page.FileName, Line: lineNo, Kind: ReferenceWikiLink, Target: m[1],
+						Reason: "リンク先のページが生成されていません",
+					})
+				}
+			}
+
+			if lineCounts == nil {
+				continue
+			}
+			for _, m := range codeSpanPattern.FindAllStringSubmatch(line, -1) {
+				ref, ok := parseFileReference(m[1], m[2], m[3])
+				if !ok {
+					continue
+				}
+				report.CheckedReferences++
+				if broken, ok := ref.check(lineCounts); !ok {
+					broken.Page = page.FileName
+					broken.Line = lineNo
+					report.Broken = append(report.Broken, broken)
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(report.Broken, func(i, j int) bool {
+		if report.Broken[i].Page != report.Broken[j].Page {
+			return report.Broken[i].Page < report.Broken[j].Page
+		}
+		return report.Broken[i].Line < report.Broken[j].Line
+	})
+	return report
+}
+
+// wikiLinkTarget はリンク先がWikiページ（.md）の場合に、出力ディレクトリからの相対パスを返す
+func wikiLinkTarget(pageFileName, href string) (string, bool) {
+	if strings.HasPrefix(href, "#") || strings.Contains(href, "://") || strings.HasPrefix(href, "mailto:") {
+		return "", false
+	}
+	if idx := strings.IndexAny(href, "#?"); idx >= 0 {
+		href = href[:idx]
+	}
+	if path.Ext(href) != ".md" {
+		return "", false
+	}
+	if strings.HasPrefix(href, "/") {
+		return path.Clean(strings.TrimPrefix(href, "/")), true
+	}
+	return path.Join(path.Dir(pageFileName), href), true
+}
+
+// fileReference はコードスパンから読み取ったソースファイルの引用
+type fileReference struct {
+	text      string
+	path      string
+	startLine int
+	endLine   int
+}
+
+// parseFileReference はコードスパンの内容がソースファイルの引用であれば解析する
+// lineStart・lineEnd はコードスパンの直後の L12-L34 の形式の行番号（なければ空文字）
+func parseFileReference(span, lineStart, lineEnd string) (fileReference, bool) {
+	span = strings.TrimSpace(span)
+	if strings.ContainsAny(span, " \t") || strings.Contains(span, "://") {
+		return fileReference{}, false
+	}
+	m := fileReferencePattern.FindStringSubmatch(span)
+	if m == nil {
+		return fileReference{}, false
+	}
+
+	filePath := strings.TrimPrefix(m[2], "./")
+	if !looksLikeFilePath(filePath) {
+		return fileReference{}, false
+	}
+
+	ref := fileReference{text: span, path: filePath}
+	start, end := m[3], m[4]
+	if start == "" {
+		start, end = lineStart, lineEnd
+		if start != "" {
+			ref.text = fmt.Sprintf("%s L%s", span, start)
+			if end != "" {
+				ref.text += "-L" + end
+			}
+		}
+	}
+	if start != "" {
+		ref.startLine, _ = strconv.Atoi(start)
+		ref.endLine = ref.startLine
+		if end != "" {
+			ref.endLine, _ = strconv.Atoi(end)
+		}
+	}
+	return ref, true
+}
+
+// looksLikeFilePath は文字列がソースファイルのパスらしいかを判定する
+func looksLikeFilePath(p string) bool {
+	if p == "" || strings.HasSuffix(p, "/") || strings.HasPrefix(p, "/") || strings.Contains(p, "..") {
+		return false
+	}
+	ext := strings.ToLower(path.Ext(p))
+	if ext == "" {
+		return false
+	}
+	if first, _, ok := strings.Cut(p, "/"); ok {
+		// github.com/... や gopkg.in/yaml.v3 のようなモジュールパスはファイルパスとみなさない
+		// .github のようなドットで始まるディレクトリはファイルパスとみなす
+		return !strings.Contains(strings.TrimPrefix(first, "."), ".")
+	}
+	_, ok := sourceFileExtensions[ext]
+	return ok
+}
+
+// check は引用がスナップショットのファイル・行数で解決できるかを検証する
+func (r fileReference) check(lineCounts map[string]int) (BrokenReference, bool) {
+	lineCount, exists := lineCounts[r.path]
+	if !exists {
+		return BrokenReference{Kind: ReferenceFilePath, Target: r.text, Reason: "スナップショットにファイルが存在しません"}, false
+	}
+	if r.startLine == 0 {
+		return BrokenReference{}, true
+	}
+	if r.startLine > r.endLine {
+		return BrokenReference{Kind: ReferenceLineRange, Target: r.text, Reason: "行範囲の開始が終了より後です"}, false
+	}
+	if lineCount > 0 && r.endLine > lineCount {
+		return BrokenReference{
+			Kind: ReferenceLineRange, Target: r.text,
+			Reason: fmt.Sprintf("行番号がファイルの行数（%d行）を超えています", lineCount),
+		}, false
+	}
+	return BrokenReference{}, true
+}
+
+// writeLinkReport は参照チェックの結果を出力ディレクトリに書き出す
+func writeLinkReport(outputDir string, report *LinkReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal link report: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(outputDir, LinkReportFileName), append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", LinkReportFileName, err)
+	}
+	return nil
+}
+
+// LoadPages は出力ディレクトリ配下の生成済みのWikiページ（.md）をファイル名順に読み込む
+// 生成済みのWikiの参照を後から検証するためのもので、ページのタイトル・セクションは設定しない
+func LoadPages(dir string) ([]*WikiPage, error) {
+	files, err := readMarkdownFiles(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read wiki pages: %w", err)
+	}
+	pages := make([]*WikiPage, 0, len(files))
+	for _, name := range sortedKeys(files) {
+		pages = append(pages, &WikiPage{FileName: name, Content: string(files[name])})
+	}
+	return pages, nil
+}
@@ -0,0 +1,99 @@
+package wiki
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckReferences(t *testing.T) {
+	pages := []*WikiPage{
+		{FileName: "README.md", Content: "# Top\n\n- [API](api/index.md)\n- [削除済み](missing.md)\n- [外部](https://example.com/a.md)\n- [見出し](#top)\n"},
+		{FileName: "api/index.md", Content: "| [core/wiki](core-wiki.md#a) | 1 |\n[戻る](../README.md)\n"},
+		{FileName: "api/core-wiki.md", Content: "定義: `internal/core/wiki/service.go` L10-L20\n" +
+			"範囲外: `internal/core/wiki/service.go:10-500`\n" +
+			"逆順: `internal/core/wiki/service.go:30-20`\n" +
+			"未インデックス: `scripts/gen.sh:999`\n" +
+			"存在しない: `internal/old.go`\n" +
+			"ソース名付き: `api:cmd/main.go:3`\n" +
+			"対象外: `fmt.Println` `github.com/samber/mo.go` `go build ./...` `https://example.com/a.go`\n" +
+			"```go\n// `internal/ignored.go`\n```\n"},
+	}
+	files := []*SourceFileLines{
+		{Path: "internal/core/wiki/service.go", LineCount: 200},
+		{Path: "scripts/gen.sh", LineCount: 0},
+		{Path: "cmd/main.go", LineCount: 20},
+	}
+
+	report := CheckReferences(pages, files)
+
+	assert.Equal(t, 3, report.CheckedPages)
+	assert.True(t, report.SourceFilesChecked)
+	assert.Equal(t, 10, report.CheckedReferences)
+	assert.Equal(t, []BrokenReference{
+		{Page: "README.md", Line: 4, Kind: ReferenceWikiLink, Target: "missing.md", Reason: "リンク先のページが生成されていません"},
+		{Page: "api/core-wiki.md", Line: 2, Kind: ReferenceLineRange, Target: "internal/core/wiki/service.go:10-500", Reason: "行番号がファイルの行数（200行）を超えています"},
+		{Page: "api/core-wiki.md", Line: 3, Kind: ReferenceLineRange, Target: "internal/core/wiki/service.go:30-20", Reason: "行範囲の開始が終了より後です"},
+		{Page: "api/core-wiki.md", Line: 5, Kind: ReferenceFilePath, Target: "internal/old.go", Reason: "スナップショットにファイルが存在しません"},
+	}, report.Broken)
+	assert.True(t, report.Exceeds(3))
+	assert.False(t, report.Exceeds(4))
+}
+
+func TestCheckReferences_WithoutSourceFiles(t *testing.T) {
+	report := CheckReferences([]*WikiPage{
+		{FileName: "README.md", Content: "[a](a.md) `internal/old.go`\n"},
+	}, nil)
+
+	assert.False(t, report.SourceFilesChecked)
+	assert.Equal(t, 1, report.CheckedReferences)
+	require.Len(t, report.Broken, 1)
+	assert.Equal(t, ReferenceWikiLink, report.Broken[0].Kind)
+}
+
+func TestParseFileReference(t *testing.T) {
+	ref, ok := parseFileReference(".github/workflows/ci.yml", "", "")
+	require.True(t, ok)
+	assert.Equal(t, ".github/workflows/ci.yml", ref.path)
+
+	ref, ok = parseFileReference("main.go:12", "", "")
+	require.True(t, ok)
+	assert.Equal(t, "main.go", ref.path)
+	assert.Equal(t, 12, ref.startLine)
+	assert.Equal(t, 12, ref.endLine)
+
+	_, ok = parseFileReference("gopkg.in/yaml.v3", "", "")
+	assert.False(t, ok)
+	_, ok = parseFileReference("../outside.go", "", "")
+	assert.False(t, ok)
+}
+
+func TestWriteLinkReport(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, writeLinkReport(dir, &LinkReport{Broken: []BrokenReference{}}))
+
+	data, err := os.ReadFile(filepath.Join(dir, LinkReportFileName))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"broken": []`)
+}
+
+func TestLoadPages(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "api"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".git"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Top\n[API](api/index.md)\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "api", "index.md"), []byte("# API\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, LinkReportFileName), []byte("{}\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".git", "HEAD.md"), []byte("ignored"), 0644))
+
+	pages, err := LoadPages(dir)
+	require.NoError(t, err)
+
+	require.Len(t, pages, 2)
+	assert.Equal(t, "README.md", pages[0].FileName)
+	assert.Equal(t, "api/index.md", pages[1].FileName)
+	assert.Equal(t, 0, CheckReferences(pages, nil).BrokenCount())
+}
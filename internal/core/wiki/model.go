@@ -1,6 +1,7 @@
 package wiki
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -76,10 +77,74 @@ type SnapshotInfo struct {
 
 // WikiPage はWikiページを表す
 type WikiPage struct {
-	Section  WikiSection // セクション識別子
-	Title    string      // ページタイトル
-	FileName string      // 出力ファイル名
-	Content  string      // Markdownコンテンツ
+	Section       WikiSection // セクション識別子
+	Title         string      // ページタイトル
+	FileName      string      // 出力ファイル名
+	Content       string      // Markdownコンテンツ
+	Language      Language    // ページの言語（空の場合は DefaultLanguage）
+	PromptName    string      // LLMでの生成に使用したプロンプトテンプレートの名前（LLMを使用しないページは空）
+	PromptVersion string      // LLMでの生成に使用したプロンプトテンプレートのバージョン
+}
+
+// FileDependency はファイル間の依存関係（チャンク間の依存関係をファイル単位に集約したもの）
+type FileDependency struct {
+	FromPath string // 依存元のファイルパス
+	ToPath   string // 依存先のファイルパス
+	DepType  string // 依存の種類（call / import / type）
+	Count    int    // 集約したチャンク間の依存関係の数
+}
+
+// CallEdge は関数/クラス間の呼び出し関係
+type CallEdge struct {
+	FromChunkID uuid.UUID
+	FromName    string // 呼び出し元の名前（メソッドの場合は "型名.メソッド名"）
+	FromPath    string
+	ToChunkID   uuid.UUID
+	ToName      string // 呼び出し先の名前（メソッドの場合は "型名.メソッド名"）
+	ToPath      string
+}
+
+// APISymbol はAPIリファレンスに掲載する関数・型などの宣言（チャンクのメタデータ）
+type APISymbol struct {
+	FilePath    string
+	Language    string  // ファイルの言語（files.language）
+	Kind        string  // チャンクの種類（function / method / struct / interface / package など）
+	Name        string  // シンボル名
+	Parent      string  // 所属する型（メソッドのレシーバなど、なければ空）
+	Signature   *string // 関数のシグネチャ
+	DocComment  *string // ドキュメントコメント
+	ContentHead string  // チャンク内容の先頭部分（シグネチャがない宣言の表示に使用）
+	StartLine   int
+	EndLine     int
+}
+
+// DirectoryCoverage はソースのトップレベルディレクトリ・ドメインごとのファイル数とインデックス状況（カバレッジマップ）
+type DirectoryCoverage struct {
+	SourceName   string
+	Directory    string // トップレベルのディレクトリ（ルート直下のファイルは空）
+	Domain       string
+	TotalFiles   int
+	IndexedFiles int
+}
+
+// KeyFile はオンボーディングガイドで紹介するエントリポイント・重要度の高いファイル
+type KeyFile struct {
+	SourceName string
+	Path       string
+	Language   string
+	Domain     string
+	EntryPoint bool     // main.go・index.ts などのエントリポイント
+	Importance float64  // ファイル内のチャンクの重要度の最大値
+	Owners     []string // CODEOWNERS で定義された担当者（定義がない場合は空）
+	Summary    string   // ファイル要約（未生成の場合は空）
+}
+
+// BuildFileChunk はビルド・テストのコマンドを検出する Makefile・CI設定ファイル（運用コマンドのページではスクリプトを含む）のチャンク
+type BuildFileChunk struct {
+	SourceName string
+	Path       string
+	StartLine  int
+	Content    string
 }
 
 // GenerateParams はWiki生成のパラメータ
@@ -90,4 +155,37 @@ type GenerateParams struct {
 	ProductID  mo.Option[uuid.UUID] // プロダクト単位Wiki生成（Noneの場合はSnapshotID使用）
 	SnapshotID uuid.UUID            // 単一スナップショットWiki生成
 	OutputDir  string
+	Config     *Config // Wiki生成設定（nil の場合は DefaultConfig）
+
+	// 並行して生成するセクション数の上限（0 の場合は DefaultConcurrency）
+	Concurrency int
+}
+
+// validate は生成のパラメータを検証する
+func (p GenerateParams) validate() error {
+	// ProductIDまたはSnapshotIDのいずれかが必須
+	if p.ProductID.IsAbsent() && p.SnapshotID == uuid.Nil {
+		return fmt.Errorf("either productID or snapshotID is required")
+	}
+	if p.OutputDir == "" {
+		return fmt.Errorf("outputDir is required")
+	}
+	return nil
+}
+
+// scope は依存グラフの取得対象（プロダクト横断または単一スナップショット）を返す
+func (p GenerateParams) scope() (productID, snapshotID mo.Option[uuid.UUID]) {
+	if p.ProductID.IsPresent() {
+		return p.ProductID, mo.None[uuid.UUID]()
+	}
+	return mo.None[uuid.UUID](), mo.Some(p.SnapshotID)
+}
+
+// GenerateResult はWiki生成の結果
+type GenerateResult struct {
+	Language   Language      // ページの言語
+	OutputDir  string        // ページを書き出したディレクトリ
+	Pages      int           // 書き出したページ数
+	LinkReport *LinkReport   // 生成ページの参照チェックの結果
+	Prompts    []*PagePrompt // LLMで生成したページと使用したプロンプトテンプレート
 }
@@ -0,0 +1,423 @@
+package wiki
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/jinford/dev-rag/internal/core/llm"
+	"github.com/jinford/dev-rag/internal/core/prompt"
+)
+
+const (
+	// onboardingKeyFileLimit はオンボーディングガイドで紹介するファイル数の上限
+	onboardingKeyFileLimit = 20
+	// onboardingSummaryMaxChars はプロンプトに含めるファイル要約1件あたりの最大文字数
+	onboardingSummaryMaxChars = 600
+	// onboardingMaxCommands はプロンプトに含めるコマンド数の上限
+	onboardingMaxCommands = 60
+)
+
+// CommandCategory はビルド・テストのコマンドの分類
+type CommandCategory string
+
+const (
+	CommandSetup CommandCategory = "setup" // 依存関係の取得・コード生成
+	CommandBuild CommandCategory = "build"
+	CommandTest  CommandCategory = "test"
+	CommandLint  CommandCategory = "lint" // 静的解析・フォーマット
+	CommandRun   CommandCategory = "run"  // ローカルでの起動
+	CommandOther CommandCategory = "other"
+)
+
+// commandCategories はプロンプトでのコマンドの表示順と見出し
+var commandCategories = []struct {
+	category CommandCategory
+	heading  string
+}{
+	{CommandSetup, "セットアップ"},
+	{CommandBuild, "ビルド"},
+	{CommandTest, "テスト"},
+	{CommandLint, "静的解析・フォーマット"},
+	{CommandRun, "起動"},
+	{CommandOther, "その他"},
+}
+
+// commandKeywords はコマンドの分類に使用する単語（先に一致した分類を採用する）
+var commandKeywords = []struct {
+	category CommandCategory
+	words    []string
+}{
+	{CommandTest, []string{"test", "tests", "pytest", "jest", "vitest", "spec", "e2e"}},
+	{CommandLint, []string{"lint", "golangci", "vet", "fmt", "gofmt", "format", "staticcheck", "eslint", "prettier", "ruff", "flake8", "mypy"}},
+	{CommandBuild, []string{"build", "compile", "dist", "package"}},
+	{CommandSetup, []string{"install", "deps", "download", "setup", "generate", "bootstrap", "tidy", "migrate"}},
+	{CommandRun, []string{"run", "serve", "server", "start", "dev", "up"}},
+}
+
+// BuildFile は Makefile・CI設定ファイルの内容（チャンクを連結したもの）
+type BuildFile struct {
+	SourceName string
+	Path       string
+	StartLine  int // Content の1行目の行番号
+	Content    string
+}
+
+// BuildCommand は Makefile・CI設定ファイルから検出したビルド・テストのコマンド
+type BuildCommand struct {
+	Category CommandCategory
+	Command  string
+	Name     string // Makefile のターゲット名、CIのジョブ名・ステップ名
+	Origin   string // 検出元（ソース名:ファイルパス）
+}
+
+// generateOnboardingSection はカバレッジマップ・重要度・ファイル要約と、CI設定・Makefile から検出したコマンドを基に
+// 新しく参加した開発者向けのガイドをLLMで生成する
+func (s *WikiService) generateOnboardingSection(ctx context.Context, params GenerateParams, config SectionConfig) (*WikiPage, error) {
+	if s.onboarding == nil {
+		return nil, fmt.Errorf("onboarding reader is not configured")
+	}
+
+	productID, snapshotID := params.scope()
+	coverage, err := s.onboarding.ListDirectoryCoverage(ctx, productID, snapshotID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list directory coverage: %w", err)
+	}
+	keyFiles, err := s.onboarding.ListKeyFiles(ctx, productID, snapshotID, onboardingKeyFileLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list key files: %w", err)
+	}
+	chunks, err := s.onboarding.ListBuildFileChunks(ctx, productID, snapshotID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list build files: %w", err)
+	}
+	commands := DetectBuildCommands(AssembleBuildFiles(chunks))
+
+	rendered, err := s.prompts.Render(ctx, prompt.NameWikiOnboarding, onboardingPromptData(config, coverage, keyFiles, commands))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build prompt: %w", err)
+	}
+	llmCtx := llm.WithCacheablePrompt(llm.WithMaxTokens(ctx, config.MaxTokens), rendered.Name, rendered.Version)
+	content, err := s.llm.GenerateCompletion(llmCtx, rendered.Text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate content: %w", err)
+	}
+
+	return &WikiPage{
+		Section:       config.Section,
+		Title:         config.Title,
+		FileName:      config.FileName,
+		Content:       content,
+		PromptName:    rendered.Name,
+		PromptVersion: rendered.Version,
+	}, nil
+}
+
+// AssembleBuildFiles はファイルパス・行番号順のチャンクをファイルごとに連結する
+// チャンク間で重なる行（オーバーラップ）は一度だけ含め、チャンクのない行は行番号を保つため空行で埋める
+func AssembleBuildFiles(chunks []*BuildFileChunk) []*BuildFile {
+	var files []*BuildFile
+	var current *BuildFile
+	var lines []string
+	nextLine := 0
+	flush := func() {
+		if current != nil {
+			current.Content = strings.Join(lines, "\n")
+			files = append(files, current)
+		}
+	}
+
+	for _, chunk := range chunks {
+		if current == nil || current.SourceName != chunk.SourceName || current.Path != chunk.Path {
+			flush()
+			current = &BuildFile{SourceName: chunk.SourceName, Path: chunk.Path, StartLine: chunk.StartLine}
+			lines = nil
+			nextLine = chunk.StartLine
+		}
+		for ; nextLine < chunk.StartLine; nextLine++ {
+			lines = append(lines, "")
+		}
+		for i, line := range strings.Split(strings.TrimSuffix(chunk.Content, "\n"), "\n") {
+			if chunk.StartLine+i < nextLine {
+				continue
+			}
+			lines = append(lines, line)
+			nextLine = chunk.StartLine + i + 1
+		}
+	}
+	flush()
+	return files
+}
+
+// DetectBuildCommands は Makefile のターゲット、GitHub Actions・GitLab CI の実行コマンドを検出する
+// 同じソースで同じコマンドは最初に検出したものだけを残す
+func DetectBuildCommands(files []*BuildFile) []*BuildCommand {
+	var commands []*BuildCommand
+	seen := make(map[string]bool)
+	for _, file := range files {
+		var detected []*BuildCommand
+		base := path.Base(file.Path)
+		switch {
+		case base == "Makefile" || base == "GNUmakefile" || base == "makefile" || path.Ext(base) == ".mk":
+			detected = detectMakeTargets(file.Content)
+		case strings.HasPrefix(file.Path, ".github/workflows/"):
+			detected = detectGitHubActionsCommands(file.Content)
+		case file.Path == ".gitlab-ci.yml":
+			detected = detectGitLabCICommands(file.Content)
+		}
+
+		origin := file.SourceName + ":" + file.Path
+		for _, cmd := range detected {
+			key := file.SourceName + "\x00" + cmd.Command
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			cmd.Origin = origin
+			commands = append(commands, cmd)
+		}
+	}
+	return commands
+}
+
+// makeTargetPattern は Makefile のターゲット定義行（変数代入の := や ::= は除く）
+// "build: deps ## バイナリをビルド" のようなヘルプコメントも取り出す
+var makeTargetPattern = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9_.-]*)\s*:(?:[^=:][^#]*|)(?:##\s*(.*))?$`)
+
+// detectMakeTargets は Makefile のターゲットを make コマンドとして返す
+func detectMakeTargets(content string) []*BuildCommand {
+	var commands []*BuildCommand
+	for _, line := range strings.Split(content, "\n") {
+		m := makeTargetPattern.FindStringSubmatch(strings.TrimRight(line, " \t\r"))
+		if m == nil {
+			continue
+		}
+		target := m[1]
+		name := target
+		if help := strings.TrimSpace(m[2]); help != "" {
+			name = target + "（" + help + "）"
+		}
+		commands = append(commands, &BuildCommand{
+			Category: classifyCommand(target),
+			Command:  "make " + target,
+			Name:     name,
+		})
+	}
+	return commands
+}
+
+// githubWorkflow は GitHub Actions のワークフローのうちコマンドの検出に必要な部分
+type githubWorkflow struct {
+	Jobs map[string]struct {
+		Name  string `yaml:"name"`
+		Steps []struct {
+			Name string `yaml:"name"`
+			Run  string `yaml:"run"`
+		} `yaml:"steps"`
+	} `yaml:"jobs"`
+}
+
+// detectGitHubActionsCommands は GitHub Actions のワークフローのステップ（run）のコマンドを返す
+func detectGitHubActionsCommands(content string) []*BuildCommand {
+	var workflow githubWorkflow
+	if err := yaml.Unmarshal([]byte(content), &workflow); err != nil {
+		return nil
+	}
+
+	jobIDs := make([]string, 0, len(workflow.Jobs))
+	for id := range workflow.Jobs {
+		jobIDs = append(jobIDs, id)
+	}
+	slices.Sort(jobIDs)
+
+	var commands []*BuildCommand
+	for _, id := range jobIDs {
+		job := workflow.Jobs[id]
+		jobName := job.Name
+		if jobName == "" {
+			jobName = id
+		}
+		for _, step := range job.Steps {
+			name := jobName
+			if step.Name != "" {
+				name = jobName + " / " + step.Name
+			}
+			commands = append(commands, scriptCommands(step.Run, name)...)
+		}
+	}
+	return commands
+}
+
+// gitlabReservedKeys は GitLab CI の設定でジョブではないトップレベルのキー
+var gitlabReservedKeys = []string{"stages", "variables", "default", "include", "workflow", "image", "services", "cache", "before_script", "after_script"}
+
+// detectGitLabCICommands は GitLab CI のジョブ（script）のコマンドを返す
+func detectGitLabCICommands(content string) []*BuildCommand {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(content), &doc); err != nil || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil
+	}
+
+	var commands []*BuildCommand
+	root := doc.Content[0]
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		name := root.Content[i].Value
+		if strings.HasPrefix(name, ".") || slices.Contains(gitlabReservedKeys, name) {
+			continue
+		}
+		var job struct {
+			Script yaml.Node `yaml:"script"`
+		}
+		if err := root.Content[i+1].Decode(&job); err != nil {
+			continue
+		}
+
+		var lines []string
+		switch job.Script.Kind {
+		case yaml.ScalarNode:
+			lines = []string{job.Script.Value}
+		case yaml.SequenceNode:
+			for _, item := range job.Script.Content {
+				lines = append(lines, item.Value)
+			}
+		}
+		for _, line := range lines {
+			commands = append(commands, scriptCommands(line, name)...)
+		}
+	}
+	return commands
+}
+
+// scriptCommands はシェルスクリプトを1行1コマンドに分割する（行末の \ による継続行は連結する）
+// 空行・コメント・echo など手順の把握に不要な行は除く
+func scriptCommands(script, name string) []*BuildCommand {
+	var commands []*BuildCommand
+	var pending string
+	for _, line := range strings.Split(script, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasSuffix(line, "\\") {
+			pending += strings.TrimSpace(strings.TrimSuffix(line, "\\")) + " "
+			continue
+		}
+		line = strings.TrimSpace(pending + line)
+		pending = ""
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "echo ") || strings.HasPrefix(line, "set ") {
+			continue
+		}
+		commands = append(commands, &BuildCommand{
+			Category: classifyCommand(line),
+			Command:  line,
+			Name:     name,
+		})
+	}
+	return commands
+}
+
+// classifyCommand はコマンド（またはターゲット名）に含まれる単語からコマンドを分類する
+func classifyCommand(command string) CommandCategory {
+	words := strings.FieldsFunc(strings.ToLower(command), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, group := range commandKeywords {
+		for _, word := range words {
+			if slices.Contains(group.words, word) {
+				return group.category
+			}
+		}
+	}
+	return CommandOther
+}
+
+// BuildOnboardingPrompt はオンボーディングガイドのプロンプトを構築する
+func BuildOnboardingPrompt(config SectionConfig, coverage []*DirectoryCoverage, keyFiles []*KeyFile, commands []*BuildCommand) string {
+	return prompt.MustRenderBuiltin(prompt.NameWikiOnboarding, onboardingPromptData(config, coverage, keyFiles, commands))
+}
+
+// onboardingPromptData はオンボーディングガイドのプロンプトテンプレートに渡す値を構築する
+func onboardingPromptData(config SectionConfig, coverage []*DirectoryCoverage, keyFiles []*KeyFile, commands []*BuildCommand) prompt.WikiSectionData {
+	var sb strings.Builder
+
+	sb.WriteString("## コンテキスト: リポジトリ構成（カバレッジマップ）\n\n")
+	if len(coverage) == 0 {
+		sb.WriteString("（ファイル構成の情報がありません）\n\n")
+	} else {
+		sb.WriteString("| ソース | ディレクトリ | ドメイン | ファイル数 | インデックス済み |\n")
+		sb.WriteString("|---|---|---|---|---|\n")
+		for _, c := range coverage {
+			dir := c.Directory + "/"
+			if c.Directory == "" {
+				dir = "（ルート直下）"
+			}
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s | %d | %d |\n", c.SourceName, dir, c.Domain, c.TotalFiles, c.IndexedFiles))
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("## コンテキスト: エントリポイントと重要なファイル\n\n")
+	if len(keyFiles) == 0 {
+		sb.WriteString("（ファイルの情報がありません）\n\n")
+	}
+	for i, f := range keyFiles {
+		label := fmt.Sprintf("重要度 %.2f", f.Importance)
+		if f.EntryPoint {
+			label = "エントリポイント, " + label
+		}
+		sb.WriteString(fmt.Sprintf("### %d. %s:%s (%s)\n", i+1, f.SourceName, f.Path, label))
+		if f.Language != "" {
+			sb.WriteString(fmt.Sprintf("言語: %s\n", f.Language))
+		}
+		if len(f.Owners) > 0 {
+			sb.WriteString(fmt.Sprintf("担当: %s\n", strings.Join(f.Owners, ", ")))
+		}
+		if f.Summary != "" {
+			sb.WriteString("\n")
+			sb.WriteString(truncateRunes(strings.TrimSpace(f.Summary), onboardingSummaryMaxChars))
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("## コンテキスト: CI設定・Makefile から検出したコマンド\n\n")
+	if len(commands) == 0 {
+		sb.WriteString("（検出されたコマンドはありません）\n\n")
+	}
+	written := 0
+	for _, group := range commandCategories {
+		var lines []string
+		for _, cmd := range commands {
+			if cmd.Category != group.category || written+len(lines) >= onboardingMaxCommands {
+				continue
+			}
+			lines = append(lines, fmt.Sprintf("- `%s` — %s（%s）\n", cmd.Command, cmd.Name, cmd.Origin))
+		}
+		if len(lines) == 0 {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("### %s\n", group.heading))
+		sb.WriteString(strings.Join(lines, ""))
+		sb.WriteString("\n")
+		written += len(lines)
+	}
+
+	return prompt.WikiSectionData{
+		Title:        config.Title,
+		Description:  config.Description,
+		Context:      sb.String(),
+		Instructions: strings.TrimRight(renderSectionPrompt(config), "\n"),
+	}
+}
+
+// truncateRunes は文字列を最大 n 文字に切り詰める
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n]) + "…"
+}
@@ -0,0 +1,152 @@
+package wiki
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssembleBuildFiles(t *testing.T) {
+	files := AssembleBuildFiles([]*BuildFileChunk{
+		{SourceName: "api", Path: "Makefile", StartLine: 1, Content: "build:\n\tgo build ./...\n"},
+		{SourceName: "api", Path: "Makefile", StartLine: 2, Content: "\tgo build ./...\ntest:\n\tgo test ./..."},
+		{SourceName: "web", Path: "Makefile", StartLine: 1, Content: "lint:"},
+		{SourceName: "web", Path: "Makefile", StartLine: 3, Content: "fmt:"},
+	})
+
+	require.Len(t, files, 2)
+	assert.Equal(t, "build:\n\tgo build ./...\ntest:\n\tgo test ./...", files[0].Content, "重なる行は一度だけ含める")
+	assert.Equal(t, 1, files[0].StartLine)
+	assert.Equal(t, "web", files[1].SourceName)
+	assert.Equal(t, "lint:\n\nfmt:", files[1].Content, "チャンクのない行は空行で埋める")
+}
+
+func commandSummary(commands []*BuildCommand) []string {
+	summary := make([]string, 0, len(commands))
+	for _, cmd := range commands {
+		summary = append(summary, string(cmd.Category)+": "+cmd.Command)
+	}
+	return summary
+}
+
+func TestDetectBuildCommands_Makefile(t *testing.T) {
+	makefile := `VERSION := 1.0
+.PHONY: build test
+
+build: deps ## バイナリをビルド
+	go build -o bin/app ./cmd/app
+
+test:
+	go test ./...
+
+lint-go:
+	golangci-lint run
+
+deps:
+	go mod download
+
+%.o: %.c
+	cc -c $<
+`
+	commands := DetectBuildCommands([]*BuildFile{{SourceName: "api", Path: "Makefile", Content: makefile}})
+
+	assert.Equal(t, []string{
+		"build: make build",
+		"test: make test",
+		"lint: make lint-go",
+		"setup: make deps",
+	}, commandSummary(commands))
+	assert.Equal(t, "build（バイナリをビルド）", commands[0].Name)
+	assert.Equal(t, "api:Makefile", commands[0].Origin)
+}
+
+func TestDetectBuildCommands_GitHubActions(t *testing.T) {
+	workflow := `name: CI
+on: [push]
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+      - name: Test
+        run: |
+          # ユニットテスト
+          echo "start"
+          go test -race \
+            ./...
+      - run: go vet ./...
+  build:
+    name: Build
+    steps:
+      - run: go build ./...
+`
+	commands := DetectBuildCommands([]*BuildFile{
+		{SourceName: "api", Path: ".github/workflows/ci.yml", Content: workflow},
+		{SourceName: "api", Path: "Makefile", Content: "vet:\n\tgo vet ./...\n"},
+	})
+
+	assert.Equal(t, []string{
+		"build: go build ./...",
+		"test: go test -race ./...",
+		"lint: go vet ./...",
+		"lint: make vet",
+	}, commandSummary(commands))
+	assert.Equal(t, "test / Test", commands[1].Name)
+}
+
+func TestDetectBuildCommands_GitLabCI(t *testing.T) {
+	ci := `stages: [test]
+variables:
+  GO_VERSION: "1.24"
+.template:
+  script: make template
+unit-test:
+  stage: test
+  script:
+    - go test ./...
+    - go test ./...
+deploy:
+  script: ./deploy.sh
+`
+	commands := DetectBuildCommands([]*BuildFile{{SourceName: "api", Path: ".gitlab-ci.yml", Content: ci}})
+
+	assert.Equal(t, []string{
+		"test: go test ./...",
+		"other: ./deploy.sh",
+	}, commandSummary(commands))
+	assert.Equal(t, "unit-test", commands[0].Name)
+}
+
+func TestDetectBuildCommands_InvalidYAML(t *testing.T) {
+	commands := DetectBuildCommands([]*BuildFile{{SourceName: "api", Path: ".github/workflows/ci.yml", Content: "jobs: ["}})
+	assert.Empty(t, commands)
+}
+
+func TestBuildOnboardingPrompt(t *testing.T) {
+	config, ok := DefaultConfig().Section(SectionOnboarding)
+	require.True(t, ok)
+
+	prompt := BuildOnboardingPrompt(config,
+		[]*DirectoryCoverage{
+			{SourceName: "api", Directory: "", Domain: "architecture", TotalFiles: 2, IndexedFiles: 2},
+			{SourceName: "api", Directory: "cmd", Domain: "code", TotalFiles: 3, IndexedFiles: 3},
+		},
+		[]*KeyFile{
+			{SourceName: "api", Path: "cmd/app/main.go", Language: "go", EntryPoint: true, Importance: 0.5, Summary: "アプリケーションの起動処理"},
+			{SourceName: "api", Path: "internal/order/service.go", Importance: 0.92, Owners: []string{"@acme/orders", "@alice"}},
+		},
+		[]*BuildCommand{
+			{Category: CommandTest, Command: "make test", Name: "test", Origin: "api:Makefile"},
+			{Category: CommandSetup, Command: "make deps", Name: "deps", Origin: "api:Makefile"},
+		},
+	)
+
+	assert.Contains(t, prompt, "| api | （ルート直下） | architecture | 2 | 2 |\n| api | cmd/ | code | 3 | 3 |")
+	assert.Contains(t, prompt, "### 1. api:cmd/app/main.go (エントリポイント, 重要度 0.50)\n言語: go\n\nアプリケーションの起動処理\n")
+	assert.Contains(t, prompt, "### 2. api:internal/order/service.go (重要度 0.92)\n担当: @acme/orders, @alice\n")
+	assert.Less(t, strings.Index(prompt, "### セットアップ"), strings.Index(prompt, "### テスト"))
+	assert.Contains(t, prompt, "- `make test` — test（api:Makefile）")
+	assert.Contains(t, prompt, "ビルド・テスト・起動")
+}
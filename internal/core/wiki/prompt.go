@@ -3,7 +3,9 @@ package wiki
 import (
 	"fmt"
 	"strings"
+	"text/template"
 
+	"github.com/jinford/dev-rag/internal/core/prompt"
 	"github.com/jinford/dev-rag/internal/core/search"
 )
 
@@ -11,23 +13,87 @@ import (
 type WikiSection string
 
 const (
-	SectionOverview   WikiSection = "overview"
-	SectionTechStack  WikiSection = "tech_stack"
-	SectionDataFlow   WikiSection = "data_flow"
-	SectionComponents WikiSection = "components"
+	SectionOverview            WikiSection = "overview"
+	SectionTechStack           WikiSection = "tech_stack"
+	SectionDataFlow            WikiSection = "data_flow"
+	SectionComponents          WikiSection = "components"
+	SectionDiagrams            WikiSection = "diagrams"
+	SectionAPI                 WikiSection = "api"
+	SectionGlossary            WikiSection = "glossary"
+	SectionOnboarding          WikiSection = "onboarding"
+	SectionDecisions           WikiSection = "decisions"
+	SectionAPICatalog          WikiSection = "api_catalog"
+	SectionDeployment          WikiSection = "deployment"
+	SectionArchitectureChanges WikiSection = "architecture_changes"
+	SectionOperations          WikiSection = "operations"
+	SectionDependencies        WikiSection = "external_dependencies"
+	SectionLicenses            WikiSection = "licenses"
 )
 
-// SectionConfig はセクション生成の設定
+// Generator はセクションのページの生成方法
+type Generator string
+
+const (
+	// GeneratorLLM は検索したコンテキストを基にLLMでページを生成する
+	GeneratorLLM Generator = "llm"
+	// GeneratorDiagram は依存グラフからMermaid図を構築してページを生成する（LLMは使用しない）
+	GeneratorDiagram Generator = "diagram"
+	// GeneratorAPIReference はチャンクのシグネチャとドキュメントコメントからパッケージごとのAPIリファレンスを生成する
+	GeneratorAPIReference Generator = "api_reference"
+	// GeneratorGlossary はインデックス時に抽出した用語集からページを生成する（LLMは使用しない）
+	GeneratorGlossary Generator = "glossary"
+	// GeneratorOnboarding はカバレッジマップ・重要度・ファイル要約とCI設定・Makefile のコマンドから新任者向けガイドをLLMで生成する
+	GeneratorOnboarding Generator = "onboarding"
+	// GeneratorDecisions はインデックス時に抽出した設計判断の記録（ADR・RFC）の一覧をステータスごとに生成する（LLMは使用しない）
+	GeneratorDecisions Generator = "decisions"
+	// GeneratorAPICatalog はインデックス時に OpenAPI・Protocol Buffers の定義から抽出したエンドポイントの一覧を生成する（LLMは使用しない）
+	GeneratorAPICatalog Generator = "api_catalog"
+	// GeneratorDeployment はインデックス時に Dockerfile・Docker Compose・Kubernetes マニフェストから抽出したデプロイ構成の一覧を生成する（LLMは使用しない）
+	GeneratorDeployment Generator = "deployment"
+	// GeneratorArchitectureChanges は依存関係グラフを直前のスナップショットと比較したアーキテクチャの変化の一覧を生成する（LLMは使用しない）
+	GeneratorArchitectureChanges Generator = "architecture_changes"
+	// GeneratorHotspots は複雑度・行数・編集頻度・コメント比率から順位付けした変更のリスクが高い関数の一覧を生成する（LLMは使用しない）
+	// 既定のセクションには含めないため、使用する場合は設定ファイルにセクションを追加する
+	GeneratorHotspots Generator = "hotspots"
+	// GeneratorRunbook は Makefile・scripts/ 配下のスクリプト・CI設定から検出したコマンドと環境変数の一覧を、LLMで要約したコマンドの内容とともに生成する
+	GeneratorRunbook Generator = "runbook"
+	// GeneratorDependencies はインデックス時に go.mod・package.json・requirements*.txt・.gitmodules から抽出した外部の依存関係と、直前のスナップショットからのバージョンの変化の一覧を生成する（LLMは使用しない）
+	GeneratorDependencies Generator = "dependencies"
+	// GeneratorLicenses はインデックス時にライセンスファイル・ライセンスヘッダーから検出したライセンスの集計と、コピーレフト・判別できないライセンスのファイルの一覧を生成する（LLMは使用しない）
+	GeneratorLicenses Generator = "licenses"
+)
+
+// generators は設定ファイルで指定できる生成方法の一覧
+var generators = []Generator{GeneratorLLM, GeneratorDiagram, GeneratorAPIReference, GeneratorGlossary, GeneratorOnboarding, GeneratorDecisions, GeneratorAPICatalog, GeneratorDeployment, GeneratorArchitectureChanges, GeneratorHotspots, GeneratorRunbook, GeneratorDependencies, GeneratorLicenses}
+
+// SectionConfig はセクション生成の設定（Wiki生成設定ファイルの sections の要素）
 type SectionConfig struct {
-	Section     WikiSection
-	Query       string
-	Title       string
-	Description string
-	FileName    string
+	Section      WikiSection `yaml:"id"`                      // セクションID（英小文字・数字・'-'・'_'）
+	Title        string      `yaml:"title"`                   // ページタイトル
+	Description  string      `yaml:"description,omitempty"`   // セクションの目的
+	Query        string      `yaml:"query,omitempty"`         // コンテキスト検索に使用するクエリ（generator: llm の場合は必須）
+	FileName     string      `yaml:"file_name,omitempty"`     // 出力ファイル名（省略時は <id>.md）
+	Domains      []string    `yaml:"domains,omitempty"`       // 検索対象のドメイン（空なら全ドメイン）
+	Prompt       string      `yaml:"prompt,omitempty"`        // 指示部分のテンプレート（省略時は既定の指示）
+	ChunkLimit   int         `yaml:"chunk_limit,omitempty"`   // 検索するチャンク数（省略時は defaults、generator: hotspots では掲載する件数）
+	SummaryLimit int         `yaml:"summary_limit,omitempty"` // 検索する要約数（省略時は defaults）
+	MaxTokens    int         `yaml:"max_tokens,omitempty"`    // LLMの最大出力トークン数（省略時は defaults）
+	Order        int         `yaml:"order,omitempty"`         // 出力順（昇順、同値は記述順）
+	Generator    Generator   `yaml:"generator,omitempty"`     // ページの生成方法（省略時は llm）
+	MaxNodes     int         `yaml:"max_nodes,omitempty"`     // 図解1つあたりのノード数（generator: diagram のみ）
+	LLMOverview  bool        `yaml:"llm_overview,omitempty"`  // パッケージの概要をLLMで生成する（generator: api_reference のみ）
+
+	// 生成したページをコンテキストに含めるセクション（依存先を先に生成する、generator: llm のみ）
+	DependsOn []WikiSection `yaml:"depends_on,omitempty"`
 }
 
-// GetSectionConfigs は全セクションの設定を返す
+// GetSectionConfigs は既定の全セクションの設定を返す
 func GetSectionConfigs() []SectionConfig {
+	return DefaultConfig().Sections
+}
+
+// defaultSections は既定のセクション構成を返す
+func defaultSections() []SectionConfig {
 	return []SectionConfig{
 		{
 			Section:     SectionOverview,
@@ -35,6 +101,8 @@ func GetSectionConfigs() []SectionConfig {
 			Title:       "概要",
 			Description: "プロダクトの目的、主要機能、全体構造の概要",
 			FileName:    "README.md",
+			// 技術スタック・処理フロー・構成要素のページを踏まえて全体をまとめる
+			DependsOn: []WikiSection{SectionTechStack, SectionDataFlow, SectionComponents},
 		},
 		{
 			Section:     SectionTechStack,
@@ -57,16 +125,175 @@ func GetSectionConfigs() []SectionConfig {
 			Description: "プロダクトを構成する主要な要素とその関係",
 			FileName:    "components.md",
 		},
+		{
+			Section:     SectionDiagrams,
+			Title:       "アーキテクチャ図",
+			Description: "インデックス時に抽出した依存関係から自動生成したモジュール依存関係と呼び出し関係の図",
+			FileName:    "architecture-diagrams.md",
+			Generator:   GeneratorDiagram,
+		},
+		{
+			Section:     SectionAPI,
+			Title:       "APIリファレンス",
+			Description: "インデックス時に抽出した関数・型のシグネチャとドキュメントコメントから自動生成したパッケージごとのAPIリファレンス",
+			FileName:    "api-reference.md",
+			Domains:     []string{"code"},
+			Generator:   GeneratorAPIReference,
+		},
+		{
+			Section:     SectionGlossary,
+			Title:       "用語集",
+			Description: "インデックス時にドキュメントとコードのドキュメントコメントから抽出した用語・略語・エンティティの定義",
+			FileName:    "glossary.md",
+			Generator:   GeneratorGlossary,
+		},
+		{
+			Section:     SectionOnboarding,
+			Title:       "新任者向けガイド",
+			Description: "新しく参加した開発者が最初に把握すべきリポジトリの構成、主要なエントリポイント、ビルド・テストの手順",
+			FileName:    "onboarding.md",
+			Generator:   GeneratorOnboarding,
+		},
+		{
+			Section:     SectionDecisions,
+			Title:       "アーキテクチャ決定記録",
+			Description: "インデックス時にADR・RFCから抽出した設計判断のステータス・決定日・決定内容の一覧",
+			FileName:    "decisions.md",
+			Generator:   GeneratorDecisions,
+		},
+		{
+			Section:     SectionAPICatalog,
+			Title:       "APIカタログ",
+			Description: "インデックス時に OpenAPI（Swagger）・Protocol Buffers の定義から抽出したHTTP・gRPCのエンドポイントの一覧",
+			FileName:    "api-catalog.md",
+			Generator:   GeneratorAPICatalog,
+		},
+		{
+			Section:     SectionDeployment,
+			Title:       "デプロイ構成",
+			Description: "インデックス時に Dockerfile・Docker Compose・Kubernetes マニフェストから抽出したベースイメージ・公開ポート・環境変数・リソース制限・ワークロード・サービスの一覧",
+			FileName:    "deployment.md",
+			Generator:   GeneratorDeployment,
+		},
+		{
+			Section:     SectionArchitectureChanges,
+			Title:       "最近のアーキテクチャの変更",
+			Description: "直前のインデックス済みスナップショットと比較した、モジュール間の依存・循環依存・重要度の変化",
+			FileName:    "architecture-changes.md",
+			Generator:   GeneratorArchitectureChanges,
+		},
+		{
+			Section:     SectionOperations,
+			Title:       "運用コマンド",
+			Description: "Makefile・scripts/ 配下のスクリプト・CI設定から検出したコマンドの内容と、実行に必要な環境変数の一覧",
+			FileName:    "operations.md",
+			Generator:   GeneratorRunbook,
+		},
+		{
+			Section:     SectionDependencies,
+			Title:       "外部依存関係",
+			Description: "インデックス時に go.mod・package.json・requirements*.txt・.gitmodules から抽出した外部のモジュール・パッケージ・サブモジュールと、直前のインデックス済みスナップショットからのバージョンの変化",
+			FileName:    "external-dependencies.md",
+			Generator:   GeneratorDependencies,
+		},
+		{
+			Section:     SectionLicenses,
+			Title:       "ライセンスとコンプライアンス",
+			Description: "インデックス時に LICENSE・COPYING などのライセンスファイルとソースファイル冒頭のライセンスヘッダーから検出したライセンスの集計と、確認が必要なコピーレフト・判別できないライセンスの一覧",
+			FileName:    "licenses.md",
+			Generator:   GeneratorLicenses,
+		},
+	}
+}
+
+// defaultPrompt はセクションの既定の指示テンプレートを返す
+// 既定セクション以外は目的（description）に沿って記述するよう指示する
+func defaultPrompt(section WikiSection) string {
+	switch section {
+	case SectionOverview:
+		return `1. **プロダクト概要**: プロダクトの目的と解決する課題
+2. **主要機能・提供価値**: 提供する主要な機能や価値
+3. **全体構造**: 高レベルの構造や構成の説明
+4. **構成の特徴**: 構造上の重要な特徴や設計方針
+`
+	case SectionTechStack:
+		return `1. **主要技術**: 使用している主要な技術やツール
+2. **フレームワーク・ライブラリ**: 使用しているフレームワークやライブラリ
+3. **プラットフォーム・インフラ**: 使用しているプラットフォームやインフラストラクチャ
+4. **開発・運用ツール**: 開発や運用で使用しているツール
+5. **依存関係**: 主要な外部依存関係
+`
+	case SectionOnboarding:
+		return `1. **はじめに**: プロダクトの概要と、このガイドの読み方
+2. **リポジトリ構成**: ソースとトップレベルディレクトリごとの役割（カバレッジマップを基に、インデックス対象外のディレクトリにも触れる）
+3. **主要なエントリポイント**: 処理の起点となるファイルと、最初に読むべき重要なファイル（理由と、担当が示されている場合は問い合わせ先として担当を添える）
+4. **ビルド・テスト・起動**: 検出したコマンドを手順順（セットアップ → ビルド → テスト → 静的解析 → 起動）に表で整理
+5. **次のステップ**: コードを読み進める順番の提案
+`
+	case SectionDataFlow:
+		return `1. **入力**: プロダクトへの情報やデータの入力
+2. **処理フロー**: 情報やデータがどのように処理されるか
+3. **変換・加工**: 情報やデータの変換や加工の詳細
+4. **出力**: 処理結果や成果物の出力
+5. **図解**: 可能であればMermaid図を含める
+`
+	case SectionComponents:
+		return `1. **構成要素一覧**: 主要な構成要素のリスト
+2. **各要素の説明**: 各構成要素の役割と責務
+3. **関係性**: 構成要素間の関係性や依存関係
+4. **図解**: 可能であればMermaid図を含める
+`
+	default:
+		return `「{{.Title}}」について、{{if .Description}}{{.Description}}を{{end}}コンテキストに基づいて見出しごとに整理して記述してください。
+`
 	}
 }
 
+// promptData はプロンプトテンプレートに渡す値
+type promptData struct {
+	Title       string
+	Description string
+	Section     WikiSection
+}
+
+// renderSectionPrompt はセクションの指示テンプレートを展開する
+// 検証済みの設定では失敗しないが、展開に失敗した場合はテンプレートをそのまま返す
+func renderSectionPrompt(config SectionConfig) string {
+	prompt := config.Prompt
+	if strings.TrimSpace(prompt) == "" {
+		prompt = defaultPrompt(config.Section)
+	}
+
+	tmpl, err := template.New(string(config.Section)).Option("missingkey=error").Parse(prompt)
+	if err != nil {
+		return prompt
+	}
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, promptData{Title: config.Title, Description: config.Description, Section: config.Section}); err != nil {
+		return prompt
+	}
+	return sb.String()
+}
+
 // BuildSectionPrompt はセクションのプロンプトを構築する
 func BuildSectionPrompt(config SectionConfig, summaries []*search.SummarySearchResult, chunks []*search.SearchResult) string {
+	return prompt.MustRenderBuiltin(prompt.NameWikiSection, sectionPromptData(config, summaries, chunks, nil))
+}
+
+// sectionPromptData はセクションのプロンプトテンプレートに渡す値を構築する
+// dependencies は depends_on のセクションで生成したページで、概要など他のページをまとめるセクションのコンテキストにする
+func sectionPromptData(config SectionConfig, summaries []*search.SummarySearchResult, chunks []*search.SearchResult, dependencies []*WikiPage) prompt.WikiSectionData {
 	var sb strings.Builder
 
-	// ヘッダー
-	sb.WriteString(fmt.Sprintf("# タスク: %sセクションのWikiページ生成\n\n", config.Title))
-	sb.WriteString(fmt.Sprintf("## 目的\n%s\n\n", config.Description))
+	// コンテキスト: 生成済みの関連ページ
+	if len(dependencies) > 0 {
+		sb.WriteString("## コンテキスト: 生成済みの関連ページ\n\n")
+		for _, page := range dependencies {
+			sb.WriteString(fmt.Sprintf("### ページ: %s (%s)\n\n", page.Title, page.FileName))
+			sb.WriteString(strings.TrimRight(page.Content, "\n"))
+			sb.WriteString("\n\n")
+		}
+	}
 
 	// コンテキスト: 構造要約
 	if len(summaries) > 0 {
@@ -86,89 +313,35 @@ func BuildSectionPrompt(config SectionConfig, summaries []*search.SummarySearchR
 	// コンテキスト: 詳細コンテンツ
 	if len(chunks) > 0 {
 		sb.WriteString("## コンテキスト: 関連コンテンツ\n\n")
-		for i, chunk := range chunks {
-			sb.WriteString(fmt.Sprintf("### コンテンツ %d: %s (L%d-L%d)\n", i+1, chunk.FilePath, chunk.StartLine, chunk.EndLine))
-			sb.WriteString(fmt.Sprintf("関連度: %.3f\n\n", chunk.Score))
-			sb.WriteString("```\n")
-			sb.WriteString(chunk.Content)
-			sb.WriteString("\n```\n\n")
-		}
+		writeChunkContext(&sb, chunks)
 	}
 
-	// 指示
-	sb.WriteString("## 指示\n\n")
-	sb.WriteString("上記のコンテキストを基に、以下の形式でMarkdownドキュメントを生成してください：\n\n")
-
-	switch config.Section {
-	case SectionOverview:
-		sb.WriteString(`1. **プロダクト概要**: プロダクトの目的と解決する課題
-2. **主要機能・提供価値**: 提供する主要な機能や価値
-3. **全体構造**: 高レベルの構造や構成の説明
-4. **構成の特徴**: 構造上の重要な特徴や設計方針
-
-`)
-	case SectionTechStack:
-		sb.WriteString(`1. **主要技術**: 使用している主要な技術やツール
-2. **フレームワーク・ライブラリ**: 使用しているフレームワークやライブラリ
-3. **プラットフォーム・インフラ**: 使用しているプラットフォームやインフラストラクチャ
-4. **開発・運用ツール**: 開発や運用で使用しているツール
-5. **依存関係**: 主要な外部依存関係
-
-`)
-	case SectionDataFlow:
-		sb.WriteString(`1. **入力**: プロダクトへの情報やデータの入力
-2. **処理フロー**: 情報やデータがどのように処理されるか
-3. **変換・加工**: 情報やデータの変換や加工の詳細
-4. **出力**: 処理結果や成果物の出力
-5. **図解**: 可能であればMermaid図を含める
-
-`)
-	case SectionComponents:
-		sb.WriteString(`1. **構成要素一覧**: 主要な構成要素のリスト
-2. **各要素の説明**: 各構成要素の役割と責務
-3. **関係性**: 構成要素間の関係性や依存関係
-4. **図解**: 可能であればMermaid図を含める
-
-`)
+	return prompt.WikiSectionData{
+		Title:        config.Title,
+		Description:  config.Description,
+		Context:      sb.String(),
+		Instructions: strings.TrimRight(renderSectionPrompt(config), "\n"),
 	}
-
-	sb.WriteString("## 注意事項\n\n")
-	sb.WriteString("- Markdown形式で出力してください\n")
-	sb.WriteString("- コンテキストに情報がない場合は、その旨を記載してください\n")
-	sb.WriteString("- 具体的な例や詳細情報がある場合は、適切にコードブロックや引用を使用してください\n")
-	sb.WriteString("- 正確で分かりやすい記述を心がけてください\n")
-	sb.WriteString("- 見出しは ## から始めてください（# は使用しないでください）\n\n")
-
-	sb.WriteString("## 出力\n\n")
-	sb.WriteString("Markdownドキュメント:\n")
-
-	return sb.String()
 }
 
 // BuildFollowUpPrompt は追加情報が必要な場合のフォローアッププロンプトを構築する
 func BuildFollowUpPrompt(config SectionConfig, initialContent string, additionalChunks []*search.SearchResult) string {
 	var sb strings.Builder
+	writeChunkContext(&sb, additionalChunks)
+	return prompt.MustRenderBuiltin(prompt.NameWikiFollowUp, prompt.WikiFollowUpData{
+		Title:          config.Title,
+		InitialContent: initialContent,
+		Context:        sb.String(),
+	})
+}
 
-	sb.WriteString(fmt.Sprintf("# タスク: %sセクションの追加情報による改善\n\n", config.Title))
-
-	sb.WriteString("## 既存のコンテンツ\n\n")
-	sb.WriteString("```markdown\n")
-	sb.WriteString(initialContent)
-	sb.WriteString("\n```\n\n")
-
-	sb.WriteString("## 追加のコンテキスト\n\n")
-	for i, chunk := range additionalChunks {
+// writeChunkContext はコンテキストのチャンクを関連度とともに書き出す
+func writeChunkContext(sb *strings.Builder, chunks []*search.SearchResult) {
+	for i, chunk := range chunks {
 		sb.WriteString(fmt.Sprintf("### コンテンツ %d: %s (L%d-L%d)\n", i+1, chunk.FilePath, chunk.StartLine, chunk.EndLine))
 		sb.WriteString(fmt.Sprintf("関連度: %.3f\n\n", chunk.Score))
 		sb.WriteString("```\n")
 		sb.WriteString(chunk.Content)
 		sb.WriteString("\n```\n\n")
 	}
-
-	sb.WriteString("## 指示\n\n")
-	sb.WriteString("追加のコンテキストを参考に、既存のコンテンツを改善してください。\n")
-	sb.WriteString("新しい情報があれば追加し、不正確な情報があれば修正してください。\n\n")
-	sb.WriteString("改善されたMarkdownドキュメント:\n")
-
-	return sb.String()
 }
@@ -0,0 +1,43 @@
+package wiki
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// PromptVersionsFileName はLLMで生成したページと使用したプロンプトテンプレートを書き出すファイル名
+const PromptVersionsFileName = "prompt-versions.json"
+
+// PagePrompt はLLMで生成したページと、その生成に使用したプロンプトテンプレート
+type PagePrompt struct {
+	Page     string `json:"page"`
+	Language string `json:"language,omitempty"` // ページの言語（--lang を指定せずに生成した場合は空）
+	Template string `json:"template"`
+	Version  string `json:"version"`
+}
+
+// collectPagePrompts はLLMで生成したページのプロンプトテンプレートをページの順に集める
+func collectPagePrompts(pages []*WikiPage) []*PagePrompt {
+	prompts := []*PagePrompt{}
+	for _, page := range pages {
+		if page.PromptName == "" {
+			continue
+		}
+		prompts = append(prompts, &PagePrompt{Page: page.FileName, Language: string(page.Language), Template: page.PromptName, Version: page.PromptVersion})
+	}
+	return prompts
+}
+
+// writePagePrompts はページごとのプロンプトテンプレートを出力ディレクトリに書き出す
+func writePagePrompts(outputDir string, prompts []*PagePrompt) error {
+	data, err := json.MarshalIndent(prompts, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal prompt versions: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(outputDir, PromptVersionsFileName), append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", PromptVersionsFileName, err)
+	}
+	return nil
+}
@@ -0,0 +1,432 @@
+package wiki
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PublishTargetConfluence は公開先の種類（wiki_publications.target）としてのConfluence
+const PublishTargetConfluence = "confluence"
+
+// Publication は生成したWikiページと公開先のリモートページの対応
+type Publication struct {
+	ID             uuid.UUID
+	ProductID      uuid.UUID
+	Target         string // 公開先の種類（confluence）
+	Destination    string // 公開先（Confluenceのスペースキーなど）
+	Language       Language
+	PagePath       string  // Wiki出力ディレクトリからの相対パス（ディレクトリのページは末尾 /、ルートは空文字）
+	RemoteID       string  // リモートのページID
+	RemoteVersion  int     // 最後に公開したリモートのページのバージョン
+	ParentRemoteID *string // 親ページのリモートID
+	Title          string
+	ContentHash    string
+	PublishedAt    time.Time
+}
+
+// PublicationStore はWikiの公開状況を永続化するインターフェース
+type PublicationStore interface {
+	ListPublications(ctx context.Context, productID uuid.UUID, target, destination string, language Language) ([]*Publication, error)
+	SavePublication(ctx context.Context, publication *Publication) (*Publication, error)
+	DeletePublication(ctx context.Context, id uuid.UUID) error
+}
+
+// RemotePage はConfluenceのページ
+type RemotePage struct {
+	ID      string
+	Title   string
+	Version int
+}
+
+// ConfluenceClient はConfluenceのページを操作するインターフェース
+type ConfluenceClient interface {
+	// GetPage はページを取得する（存在しない場合は ok が false）
+	GetPage(ctx context.Context, id string) (page *RemotePage, ok bool, err error)
+	// FindPageByTitle はスペース内のタイトルが一致するページを取得する（存在しない場合は ok が false）
+	FindPageByTitle(ctx context.Context, spaceKey, title string) (page *RemotePage, ok bool, err error)
+	// CreatePage はページを作成する（parentID が空の場合はスペースの最上位に作成する）
+	CreatePage(ctx context.Context, spaceKey, parentID, title, body string) (*RemotePage, error)
+	// UpdatePage はページのタイトル・親・本文を更新する（version は更新後のバージョン）
+	UpdatePage(ctx context.Context, id, parentID, title, body string, version int) (*RemotePage, error)
+	// DeletePage はページを削除する
+	DeletePage(ctx context.Context, id string) error
+}
+
+// PublishParams はWiki公開のパラメータ
+type PublishParams struct {
+	ProductID   uuid.UUID
+	ProductName string // ページタイトルの接頭辞（ルートページのタイトル）
+	SpaceKey    string
+	WikiDir     string // 生成済みWikiのディレクトリ
+	// Wikiの言語（空の場合は DefaultLanguage）
+	// 言語ごとに公開状況を記録し、DefaultLanguage 以外はページタイトルの接頭辞に言語を付ける（例: "ecommerce (en) / architecture"）
+	Language     Language
+	ParentPageID string // ルートページを配置する親ページ（空の場合はスペースの最上位）
+	Prune        bool   // ローカルに存在しなくなったページをリモートから削除する
+	DryRun       bool   // リモートとDBを変更せず、実行内容のみを返す
+}
+
+// PublishResult はWiki公開の結果（ページのパス）
+type PublishResult struct {
+	Created   []string
+	Updated   []string
+	Unchanged []string
+	Deleted   []string
+	Stale     []string // ローカルに存在しないが Prune しなかったページ
+}
+
+// localPage は公開対象のローカルのページ
+type localPage struct {
+	path     string // ページのパス（ルートは空文字、ディレクトリのページは末尾 /）
+	parent   string // 親ページのパス
+	title    string
+	markdown string
+	depth    int
+}
+
+// ConfluencePublisher は生成済みWikiをConfluenceに公開する
+// ローカルのページとリモートのページIDの対応を記録し、再公開時は同じページを更新する
+type ConfluencePublisher struct {
+	client ConfluenceClient
+	store  PublicationStore
+	logger *slog.Logger
+}
+
+// ConfluencePublisherOption は ConfluencePublisher のオプション設定
+type ConfluencePublisherOption func(*ConfluencePublisher)
+
+// WithConfluencePublisherLogger は ConfluencePublisher にロガーを設定する
+func WithConfluencePublisherLogger(logger *slog.Logger) ConfluencePublisherOption {
+	return func(p *ConfluencePublisher) {
+		p.logger = logger
+	}
+}
+
+// NewConfluencePublisher は新しい ConfluencePublisher を作成する
+func NewConfluencePublisher(client ConfluenceClient, store PublicationStore, opts ...ConfluencePublisherOption) *ConfluencePublisher {
+	p := &ConfluencePublisher{
+		client: client,
+		store:  store,
+		logger: slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if p.logger == nil {
+		p.logger = slog.Default()
+	}
+	return p
+}
+
+// Publish はWikiディレクトリのMarkdownページをConfluenceのページツリーとして公開する
+// ルートページ（README.md の内容）の下に各ページを配置し、サブディレクトリのページは同名の .md のページ
+// （なければディレクトリのページ）の子にする
+// 内容・タイトル・親が前回の公開から変わっていないページは更新しない
+func (p *ConfluencePublisher) Publish(ctx context.Context, params PublishParams) (*PublishResult, error) {
+	if params.SpaceKey == "" {
+		return nil, fmt.Errorf("spaceKey is required")
+	}
+	if params.ProductName == "" {
+		return nil, fmt.Errorf("productName is required")
+	}
+
+	if params.Language == "" {
+		params.Language = DefaultLanguage
+	}
+	titlePrefix := params.ProductName
+	if params.Language != DefaultLanguage {
+		titlePrefix = fmt.Sprintf("%s (%s)", params.ProductName, params.Language)
+	}
+
+	pages, err := collectLocalPages(params.WikiDir, titlePrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	publications, err := p.store.ListPublications(ctx, params.ProductID, PublishTargetConfluence, params.SpaceKey, params.Language)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list publications: %w", err)
+	}
+	tracked := make(map[string]*Publication, len(publications))
+	for _, publication := range publications {
+		tracked[publication.PagePath] = publication
+	}
+
+	titles := make(map[string]string, len(pages))
+	for _, page := range pages {
+		titles[page.path] = page.title
+	}
+
+	result := &PublishResult{}
+	remoteIDs := make(map[string]string, len(pages))
+	for _, page := range pages {
+		parentID := params.ParentPageID
+		if page.path != "" {
+			parentID = remoteIDs[page.parent]
+		}
+
+		body, err := ConvertToConfluence(page.markdown, pageLinkResolver(page.path, titles))
+		if err != nil {
+			return result, fmt.Errorf("failed to convert %s: %w", displayPath(page.path), err)
+		}
+		hash := publicationHash(page.title, parentID, body)
+
+		remote, action, err := p.publishPage(ctx, params, tracked[page.path], page, parentID, body, hash)
+		if err != nil {
+			return result, fmt.Errorf("failed to publish %s: %w", displayPath(page.path), err)
+		}
+		if remote != nil {
+			remoteIDs[page.path] = remote.ID
+		}
+
+		switch action {
+		case "create":
+			result.Created = append(result.Created, displayPath(page.path))
+		case "update":
+			result.Updated = append(result.Updated, displayPath(page.path))
+		default:
+			result.Unchanged = append(result.Unchanged, displayPath(page.path))
+		}
+	}
+
+	// ローカルに存在しなくなったページ（子ページから削除する）
+	var stale []*Publication
+	for _, publication := range publications {
+		if _, ok := titles[publication.PagePath]; !ok {
+			stale = append(stale, publication)
+		}
+	}
+	slices.SortFunc(stale, func(a, b *Publication) int {
+		return cmp.Compare(pageDepth(b.PagePath), pageDepth(a.PagePath))
+	})
+	for _, publication := range stale {
+		if !params.Prune {
+			result.Stale = append(result.Stale, displayPath(publication.PagePath))
+			continue
+		}
+		if !params.DryRun {
+			if err := p.client.DeletePage(ctx, publication.RemoteID); err != nil {
+				return result, fmt.Errorf("failed to delete %s: %w", displayPath(publication.PagePath), err)
+			}
+			if err := p.store.DeletePublication(ctx, publication.ID); err != nil {
+				return result, fmt.Errorf("failed to delete publication: %w", err)
+			}
+		}
+		result.Deleted = append(result.Deleted, displayPath(publication.PagePath))
+	}
+
+	return result, nil
+}
+
+// publishPage は1ページを作成または更新し、公開状況を記録する
+// 記録済みのページがリモートで削除されていた場合は作成し直し、記録がなくても同じタイトルのページがあれば引き継ぐ
+func (p *ConfluencePublisher) publishPage(
+	ctx context.Context,
+	params PublishParams,
+	publication *Publication,
+	page *localPage,
+	parentID, body, hash string,
+) (*RemotePage, string, error) {
+	var remote *RemotePage
+	if publication != nil {
+		existing, ok, err := p.client.GetPage(ctx, publication.RemoteID)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to get page: %w", err)
+		}
+		if ok {
+			remote = existing
+			// リモートで編集されていない（バージョンが同じ）かつ内容も同じなら更新しない
+			if publication.ContentHash == hash && existing.Version == publication.RemoteVersion {
+				return existing, "unchanged", nil
+			}
+		} else {
+			p.logger.Warn("published page was deleted remotely, recreating", "page", displayPath(page.path), "remoteID", publication.RemoteID)
+		}
+	}
+	if remote == nil {
+		existing, ok, err := p.client.FindPageByTitle(ctx, params.SpaceKey, page.title)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to find page: %w", err)
+		}
+		if ok {
+			remote = existing
+		}
+	}
+
+	if params.DryRun {
+		if remote == nil {
+			return nil, "create", nil
+		}
+		return remote, "update", nil
+	}
+
+	action := "update"
+	var err error
+	if remote == nil {
+		action = "create"
+		remote, err = p.client.CreatePage(ctx, params.SpaceKey, parentID, page.title, body)
+	} else {
+		remote, err = p.client.UpdatePage(ctx, remote.ID, parentID, page.title, body, remote.Version+1)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	saved := &Publication{
+		ProductID:     params.ProductID,
+		Target:        PublishTargetConfluence,
+		Destination:   params.SpaceKey,
+		Language:      params.Language,
+		PagePath:      page.path,
+		RemoteID:      remote.ID,
+		RemoteVersion: remote.Version,
+		Title:         page.title,
+		ContentHash:   hash,
+	}
+	if parentID != "" {
+		saved.ParentRemoteID = &parentID
+	}
+	if _, err := p.store.SavePublication(ctx, saved); err != nil {
+		return nil, "", fmt.Errorf("failed to save publication: %w", err)
+	}
+	return remote, action, nil
+}
+
+// collectLocalPages はWikiディレクトリのMarkdownファイルからページツリーを構築し、親から順に並べて返す
+func collectLocalPages(wikiDir, productName string) ([]*localPage, error) {
+	contents, err := readMarkdownFiles(wikiDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read wiki directory: %w", err)
+	}
+	files := make(map[string]string, len(contents))
+	for rel, content := range contents {
+		files[rel] = string(content)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no markdown pages found in %s", wikiDir)
+	}
+
+	// README.md はルートページの本文として扱う
+	pages := map[string]*localPage{
+		"": {path: "", title: productName, markdown: files["README.md"]},
+	}
+	delete(files, "README.md")
+
+	var parentOf func(pagePath string) string
+	parentOf = func(pagePath string) string {
+		dir := path.Dir(strings.TrimSuffix(pagePath, "/"))
+		if dir == "." {
+			return ""
+		}
+		if _, ok := files[dir+".md"]; ok {
+			return dir + ".md"
+		}
+		// 同名の .md がないディレクトリはページ一覧のみのページにする
+		dirPage := dir + "/"
+		if _, ok := pages[dirPage]; !ok {
+			pages[dirPage] = &localPage{path: dirPage, title: productName + " / " + dir}
+			pages[dirPage].parent = parentOf(dirPage)
+		}
+		return dirPage
+	}
+
+	for filePath, content := range files {
+		pages[filePath] = &localPage{
+			path:     filePath,
+			title:    productName + " / " + strings.TrimSuffix(filePath, ".md"),
+			markdown: content,
+		}
+	}
+	for filePath := range files {
+		pages[filePath].parent = parentOf(filePath)
+	}
+
+	// ディレクトリのページには子ページの一覧を載せる
+	for _, page := range pages {
+		if strings.HasSuffix(page.path, "/") {
+			var sb strings.Builder
+			for _, child := range sortedPages(pages) {
+				if child.parent == page.path {
+					sb.WriteString(fmt.Sprintf("- [%s](%s)\n", child.title, path.Base(strings.TrimSuffix(child.path, "/"))))
+				}
+			}
+			page.markdown = sb.String()
+		}
+	}
+
+	result := sortedPages(pages)
+	for _, page := range result {
+		for p := page; p.path != ""; p = pages[p.parent] {
+			page.depth++
+		}
+	}
+	slices.SortStableFunc(result, func(a, b *localPage) int {
+		return cmp.Compare(a.depth, b.depth)
+	})
+	return result, nil
+}
+
+// sortedPages はページをパス順に返す
+func sortedPages(pages map[string]*localPage) []*localPage {
+	sorted := make([]*localPage, 0, len(pages))
+	for _, page := range pages {
+		sorted = append(sorted, page)
+	}
+	slices.SortFunc(sorted, func(a, b *localPage) int {
+		return cmp.Compare(a.path, b.path)
+	})
+	return sorted
+}
+
+// pageLinkResolver はページ内の相対リンクをWiki内のページのタイトルに解決する関数を返す
+func pageLinkResolver(pagePath string, titles map[string]string) func(dest string) (string, bool) {
+	baseDir := path.Dir(strings.TrimSuffix(pagePath, "/"))
+	if strings.HasSuffix(pagePath, "/") {
+		baseDir = strings.TrimSuffix(pagePath, "/")
+	}
+	return func(dest string) (string, bool) {
+		if strings.Contains(dest, "://") || strings.HasPrefix(dest, "/") || strings.HasPrefix(dest, "#") {
+			return "", false
+		}
+		dest, _, _ = strings.Cut(dest, "#")
+		target := path.Clean(path.Join(baseDir, dest))
+		if target == "README.md" {
+			target = ""
+		}
+		if title, ok := titles[target]; ok {
+			return title, true
+		}
+		// ディレクトリのページへのリンク
+		title, ok := titles[target+"/"]
+		return title, ok
+	}
+}
+
+// publicationHash は公開する内容（タイトル・親・本文）のハッシュを返す
+func publicationHash(title, parentID, body string) string {
+	sum := sha256.Sum256([]byte(title + "\x00" + parentID + "\x00" + body))
+	return hex.EncodeToString(sum[:])
+}
+
+// pageDepth はページのパスのディレクトリの深さを返す（ディレクトリのページ "a/" はそのディレクトリと同じ深さ）
+func pageDepth(pagePath string) int {
+	return strings.Count(strings.TrimSuffix(pagePath, "/"), "/")
+}
+
+// displayPath はページのパスを表示用に返す（ルートは README.md）
+func displayPath(pagePath string) string {
+	if pagePath == "" {
+		return "README.md"
+	}
+	return pagePath
+}
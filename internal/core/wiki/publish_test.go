@@ -0,0 +1,275 @@
+package wiki
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeConfluenceClient struct {
+	pages  map[string]*fakeConfluencePage
+	nextID int
+}
+
+type fakeConfluencePage struct {
+	RemotePage
+	parentID string
+	body     string
+}
+
+func newFakeConfluenceClient() *fakeConfluenceClient {
+	return &fakeConfluenceClient{pages: make(map[string]*fakeConfluencePage)}
+}
+
+func (c *fakeConfluenceClient) GetPage(ctx context.Context, id string) (*RemotePage, bool, error) {
+	page, ok := c.pages[id]
+	if !ok {
+		return nil, false, nil
+	}
+	remote := page.RemotePage
+	return &remote, true, nil
+}
+
+func (c *fakeConfluenceClient) FindPageByTitle(ctx context.Context, spaceKey, title string) (*RemotePage, bool, error) {
+	for _, page := range c.pages {
+		if page.Title == title {
+			remote := page.RemotePage
+			return &remote, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+func (c *fakeConfluenceClient) CreatePage(ctx context.Context, spaceKey, parentID, title, body string) (*RemotePage, error) {
+	c.nextID++
+	page := &fakeConfluencePage{
+		RemotePage: RemotePage{ID: fmt.Sprintf("p%d", c.nextID), Title: title, Version: 1},
+		parentID:   parentID,
+		body:       body,
+	}
+	c.pages[page.ID] = page
+	remote := page.RemotePage
+	return &remote, nil
+}
+
+func (c *fakeConfluenceClient) UpdatePage(ctx context.Context, id, parentID, title, body string, version int) (*RemotePage, error) {
+	page, ok := c.pages[id]
+	if !ok {
+		return nil, fmt.Errorf("page %s not found", id)
+	}
+	if version != page.Version+1 {
+		return nil, fmt.Errorf("version conflict")
+	}
+	page.Title, page.parentID, page.body, page.Version = title, parentID, body, version
+	remote := page.RemotePage
+	return &remote, nil
+}
+
+func (c *fakeConfluenceClient) DeletePage(ctx context.Context, id string) error {
+	delete(c.pages, id)
+	return nil
+}
+
+func (c *fakeConfluenceClient) byTitle(title string) *fakeConfluencePage {
+	for _, page := range c.pages {
+		if page.Title == title {
+			return page
+		}
+	}
+	return nil
+}
+
+// fakePublicationStore は言語ごと・ページのパスごとに公開状況を保持する
+type fakePublicationStore struct {
+	publications map[Language]map[string]*Publication
+}
+
+func newFakePublicationStore() *fakePublicationStore {
+	return &fakePublicationStore{publications: make(map[Language]map[string]*Publication)}
+}
+
+func (s *fakePublicationStore) ListPublications(ctx context.Context, productID uuid.UUID, target, destination string, language Language) ([]*Publication, error) {
+	var result []*Publication
+	for _, publication := range s.publications[language] {
+		result = append(result, publication)
+	}
+	return result, nil
+}
+
+func (s *fakePublicationStore) SavePublication(ctx context.Context, publication *Publication) (*Publication, error) {
+	saved := *publication
+	pages, ok := s.publications[publication.Language]
+	if !ok {
+		pages = make(map[string]*Publication)
+		s.publications[publication.Language] = pages
+	}
+	if existing, ok := pages[publication.PagePath]; ok {
+		saved.ID = existing.ID
+	} else {
+		saved.ID = uuid.New()
+	}
+	pages[publication.PagePath] = &saved
+	return &saved, nil
+}
+
+func (s *fakePublicationStore) DeletePublication(ctx context.Context, id uuid.UUID) error {
+	for _, pages := range s.publications {
+		for key, publication := range pages {
+			if publication.ID == id {
+				delete(pages, key)
+			}
+		}
+	}
+	return nil
+}
+
+func writeWikiFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+		require.NoError(t, os.WriteFile(p, []byte(content), 0o644))
+	}
+}
+
+func TestConfluencePublisher_Publish(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	writeWikiFiles(t, dir, map[string]string{
+		"README.md":                "# demo\n\n- [API](api-reference.md)\n",
+		"architecture.md":          "# アーキテクチャ\n",
+		"api-reference.md":         "- [search](api-reference/search.md)\n",
+		"api-reference/search.md":  "## パッケージ `search`\n",
+		"guides/setup.md":          "# セットアップ\n",
+		".hidden/ignored.md":       "ignored",
+		"architecture-diagram.txt": "ignored",
+	})
+
+	client := newFakeConfluenceClient()
+	store := newFakePublicationStore()
+	publisher := NewConfluencePublisher(client, store)
+	params := PublishParams{ProductID: uuid.New(), ProductName: "demo", SpaceKey: "DEV", WikiDir: dir, ParentPageID: "home"}
+
+	result, err := publisher.Publish(ctx, params)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"README.md", "architecture.md", "api-reference.md", "api-reference/search.md", "guides/", "guides/setup.md"}, result.Created)
+	require.Len(t, client.pages, 6)
+
+	root := client.byTitle("demo")
+	require.NotNil(t, root)
+	assert.Equal(t, "home", root.parentID)
+	assert.Contains(t, root.body, `<ri:page ri:content-title="demo / api-reference" />`)
+	assert.Equal(t, root.ID, client.byTitle("demo / architecture").parentID)
+	assert.Equal(t, client.byTitle("demo / api-reference").ID, client.byTitle("demo / api-reference/search").parentID)
+	guides := client.byTitle("demo / guides")
+	require.NotNil(t, guides)
+	assert.Equal(t, guides.ID, client.byTitle("demo / guides/setup").parentID)
+	assert.Contains(t, guides.body, `<ri:page ri:content-title="demo / guides/setup" />`)
+
+	// 変更がなければ更新しない
+	result, err = publisher.Publish(ctx, params)
+	require.NoError(t, err)
+	assert.Empty(t, result.Created)
+	assert.Empty(t, result.Updated)
+	assert.Len(t, result.Unchanged, 6)
+
+	// 変更したページのみ更新し、削除したページは --prune 指定時のみ削除する
+	writeWikiFiles(t, dir, map[string]string{"architecture.md": "# アーキテクチャ（改訂）\n"})
+	require.NoError(t, os.Remove(filepath.Join(dir, "guides", "setup.md")))
+
+	result, err = publisher.Publish(ctx, params)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"architecture.md"}, result.Updated)
+	assert.ElementsMatch(t, []string{"guides/", "guides/setup.md"}, result.Stale)
+	assert.Equal(t, 2, client.byTitle("demo / architecture").Version)
+	assert.Len(t, client.pages, 6)
+
+	params.Prune = true
+	result, err = publisher.Publish(ctx, params)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"guides/setup.md", "guides/"}, result.Deleted)
+	assert.Len(t, client.pages, 4)
+	assert.Len(t, store.publications[DefaultLanguage], 4)
+}
+
+func TestConfluencePublisher_PublishAdoptsAndRecreatesPages(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	writeWikiFiles(t, dir, map[string]string{
+		"README.md":       "# demo\n",
+		"architecture.md": "# アーキテクチャ\n",
+	})
+
+	client := newFakeConfluenceClient()
+	store := newFakePublicationStore()
+	publisher := NewConfluencePublisher(client, store)
+	params := PublishParams{ProductID: uuid.New(), ProductName: "demo", SpaceKey: "DEV", WikiDir: dir}
+
+	// 記録がなくても同じタイトルのページがあれば引き継ぐ
+	existing, err := client.CreatePage(ctx, "DEV", "", "demo", "old")
+	require.NoError(t, err)
+
+	result, err := publisher.Publish(ctx, params)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"README.md"}, result.Updated)
+	assert.Equal(t, []string{"architecture.md"}, result.Created)
+	assert.Equal(t, existing.ID, store.publications[DefaultLanguage][""].RemoteID)
+
+	// リモートで削除されたページは作成し直す
+	architectureID := store.publications[DefaultLanguage]["architecture.md"].RemoteID
+	require.NoError(t, client.DeletePage(ctx, architectureID))
+
+	result, err = publisher.Publish(ctx, params)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"architecture.md"}, result.Created)
+	assert.NotEqual(t, architectureID, store.publications[DefaultLanguage]["architecture.md"].RemoteID)
+}
+
+func TestConfluencePublisher_PublishDryRun(t *testing.T) {
+	dir := t.TempDir()
+	writeWikiFiles(t, dir, map[string]string{"README.md": "# demo\n", "architecture.md": "# アーキテクチャ\n"})
+
+	client := newFakeConfluenceClient()
+	store := newFakePublicationStore()
+	publisher := NewConfluencePublisher(client, store)
+
+	result, err := publisher.Publish(context.Background(), PublishParams{
+		ProductID: uuid.New(), ProductName: "demo", SpaceKey: "DEV", WikiDir: dir, DryRun: true,
+	})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"README.md", "architecture.md"}, result.Created)
+	assert.Empty(t, client.pages)
+	assert.Empty(t, store.publications)
+}
+
+func TestConfluencePublisher_PublishLanguages(t *testing.T) {
+	ctx := context.Background()
+	jaDir, enDir := t.TempDir(), t.TempDir()
+	writeWikiFiles(t, jaDir, map[string]string{"README.md": "# demo\n", "architecture.md": "# アーキテクチャ\n"})
+	writeWikiFiles(t, enDir, map[string]string{"README.md": "# demo\n", "architecture.md": "# Architecture\n"})
+
+	client := newFakeConfluenceClient()
+	store := newFakePublicationStore()
+	publisher := NewConfluencePublisher(client, store)
+	productID := uuid.New()
+
+	_, err := publisher.Publish(ctx, PublishParams{ProductID: productID, ProductName: "demo", SpaceKey: "DEV", WikiDir: jaDir})
+	require.NoError(t, err)
+	result, err := publisher.Publish(ctx, PublishParams{ProductID: productID, ProductName: "demo", SpaceKey: "DEV", WikiDir: enDir, Language: LanguageEnglish})
+	require.NoError(t, err)
+
+	// 同じページのパスでも言語ごとに別のページとして記録し、タイトルの接頭辞に言語を付ける
+	assert.ElementsMatch(t, []string{"README.md", "architecture.md"}, result.Created)
+	assert.Empty(t, result.Stale)
+	require.Len(t, client.pages, 4)
+	assert.NotNil(t, client.byTitle("demo (en) / architecture"))
+	assert.Len(t, store.publications[DefaultLanguage], 2)
+	assert.Len(t, store.publications[LanguageEnglish], 2)
+	assert.Equal(t, client.byTitle("demo (en)").ID, store.publications[LanguageEnglish][""].RemoteID)
+}
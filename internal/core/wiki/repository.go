@@ -4,6 +4,16 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"github.com/samber/mo"
+
+	"github.com/jinford/dev-rag/internal/core/apispec"
+	"github.com/jinford/dev-rag/internal/core/decision"
+	"github.com/jinford/dev-rag/internal/core/drift"
+	"github.com/jinford/dev-rag/internal/core/glossary"
+	"github.com/jinford/dev-rag/internal/core/hotspot"
+	"github.com/jinford/dev-rag/internal/core/infraspec"
+	"github.com/jinford/dev-rag/internal/core/license"
+	"github.com/jinford/dev-rag/internal/core/thirdparty"
 )
 
 // Repository はWiki生成に必要なデータアクセスインターフェース
@@ -33,3 +43,106 @@ type FileReader interface {
 	// ReadFile は指定されたパスのファイル内容を読み取る
 	ReadFile(ctx context.Context, snapshotID uuid.UUID, filePath string) (string, error)
 }
+
+// DependencyGraphReader は図解の生成に使用する依存グラフを読み取るインターフェース
+// productID 指定時はプロダクトの各ソースの最新スナップショット、snapshotID 指定時はそのスナップショットを対象とする
+type DependencyGraphReader interface {
+	// ListFileDependencies はファイル単位に集約した依存関係を依存の多い順に最大 limit 件取得する
+	ListFileDependencies(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID], limit int) ([]*FileDependency, error)
+	// ListCallGraph は重要度上位 nodeLimit 件の関数/クラス同士の呼び出し関係を取得する
+	ListCallGraph(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID], nodeLimit int) ([]*CallEdge, error)
+}
+
+// APISymbolReader はAPIリファレンスの生成に使用する宣言のメタデータを読み取るインターフェース
+// 対象スナップショットの決め方は DependencyGraphReader と同じ
+type APISymbolReader interface {
+	// ListAPISymbols は関数・型などの宣言をファイルパス・行番号順に最大 limit 件取得する（domains が空なら全ドメイン）
+	ListAPISymbols(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID], domains []string, limit int) ([]*APISymbol, error)
+}
+
+// OnboardingReader はオンボーディングガイド（generator: onboarding）の生成に使用する情報の読み取りインターフェース
+// 対象スナップショットの決め方は DependencyGraphReader と同じ
+type OnboardingReader interface {
+	// ListDirectoryCoverage はソース・トップレベルディレクトリ・ドメインごとのファイル数とインデックス状況を取得する
+	ListDirectoryCoverage(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID]) ([]*DirectoryCoverage, error)
+	// ListKeyFiles はエントリポイントと重要度の高いファイルをファイル要約とともに最大 limit 件取得する
+	ListKeyFiles(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID], limit int) ([]*KeyFile, error)
+	// ListBuildFileChunks は Makefile・CI設定ファイルのチャンクをファイルパス・行番号順に取得する
+	ListBuildFileChunks(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID]) ([]*BuildFileChunk, error)
+}
+
+// RunbookReader は運用コマンドのページ（generator: runbook）の生成に使用する運用スクリプトの読み取りインターフェース
+// 対象スナップショットの決め方は DependencyGraphReader と同じ
+type RunbookReader interface {
+	// ListOpsFileChunks は Makefile・CI設定ファイル・scripts/ 配下のファイル・シェルスクリプトのチャンクをファイルパス・行番号順に取得する
+	ListOpsFileChunks(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID]) ([]*BuildFileChunk, error)
+}
+
+// GlossaryReader は用語集ページ（generator: glossary）の生成に使用する用語集の読み取りインターフェース
+type GlossaryReader interface {
+	// ListTerms はプロダクトの用語集を用語順に取得する
+	ListTerms(ctx context.Context, productID uuid.UUID) ([]*glossary.Term, error)
+}
+
+// DecisionReader は設計判断ページ（generator: decisions）の生成に使用する設計判断の記録の読み取りインターフェース
+// 対象スナップショットの決め方は DependencyGraphReader と同じ
+type DecisionReader interface {
+	// ListDecisionRecords は設計判断の記録をソース名・番号・ファイルパス順に取得する
+	ListDecisionRecords(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID]) ([]*decision.Record, error)
+}
+
+// APIEndpointReader はAPIカタログ（generator: api_catalog）の生成に使用するエンドポイントの読み取りインターフェース
+// 対象スナップショットの決め方は DependencyGraphReader と同じ
+type APIEndpointReader interface {
+	// ListEndpoints はエンドポイントをソース名・種類・サービス・パス順に取得する
+	ListEndpoints(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID], filter apispec.ListFilter) ([]*apispec.Endpoint, error)
+}
+
+// InfraResourceReader はデプロイ構成（generator: deployment）の生成に使用するリソースの読み取りインターフェース
+// 対象スナップショットの決め方は DependencyGraphReader と同じ
+type InfraResourceReader interface {
+	// ListInfraResources はリソースをソース名・ファイルパス・種類・名前順に取得する
+	ListInfraResources(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID]) ([]*infraspec.Resource, error)
+}
+
+// ArchitectureDriftReader は最近のアーキテクチャの変更ページ（generator: architecture_changes）の生成に使用するドリフトの読み取りインターフェース
+// 対象スナップショットの決め方は DependencyGraphReader と同じ（各スナップショットを直前のインデックス済みスナップショットと比較する）
+type ArchitectureDriftReader interface {
+	// RecentChanges はソースごとに直前のスナップショットと比較した結果を取得する
+	RecentChanges(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID]) ([]*drift.Report, error)
+}
+
+// HotspotReader は変更のリスクが高い関数の一覧ページ（generator: hotspots）の生成に使用するホットスポットの読み取りインターフェース
+// 対象スナップショットの決め方は DependencyGraphReader と同じ
+type HotspotReader interface {
+	// Report は関数・ファイルをホットスポットとして順位付けする
+	Report(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID], query hotspot.Query) (*hotspot.Report, error)
+}
+
+// ExternalDependencyReader は外部依存関係のページ（generator: dependencies）の生成に使用する外部の依存関係の読み取りインターフェース
+// 対象スナップショットの決め方は DependencyGraphReader と同じ（各スナップショットを直前のインデックス済みスナップショットと比較する）
+type ExternalDependencyReader interface {
+	// Report は依存関係と、ソースごとの直前のスナップショットからのバージョンの変化を取得する
+	Report(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID]) (*thirdparty.Report, error)
+}
+
+// LicenseReader はライセンスのページ（generator: licenses）の生成に使用するライセンスの読み取りインターフェース
+// 対象スナップショットの決め方は DependencyGraphReader と同じ
+type LicenseReader interface {
+	// Report は検出したライセンスをライセンスごとに集計する
+	Report(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID]) (*license.Report, error)
+}
+
+// SourceFileReader は生成ページの参照チェックに使用するスナップショットのファイル一覧の読み取りインターフェース
+// 対象スナップショットの決め方は DependencyGraphReader と同じ
+type SourceFileReader interface {
+	// ListSourceFileLines はインデックス対象外を含む全ファイルのパスと行数をパス順に取得する
+	ListSourceFileLines(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID]) ([]*SourceFileLines, error)
+}
+
+// SnapshotReader は目次（toc.json）に記録する、生成の対象としたスナップショットの読み取りインターフェース
+// 対象スナップショットの決め方は DependencyGraphReader と同じ
+type SnapshotReader interface {
+	// ListScopeSnapshots は対象スナップショットをソース名順に取得する
+	ListScopeSnapshots(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID]) ([]*TOCSnapshot, error)
+}
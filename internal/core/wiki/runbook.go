@@ -0,0 +1,717 @@
+package wiki
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/jinford/dev-rag/internal/core/llm"
+	"github.com/jinford/dev-rag/internal/core/prompt"
+)
+
+const (
+	// runbookMaxCommands はLLMで要約するコマンド数の上限（超えた分はコメントのみ掲載する）
+	runbookMaxCommands = 80
+	// runbookBodyMaxLines はプロンプトに含めるコマンドの定義の最大行数
+	runbookBodyMaxLines = 30
+	// runbookMaxLocations は環境変数ごとに掲載する参照箇所の上限
+	runbookMaxLocations = 3
+)
+
+// OpsCommandKind は運用コマンドの定義元の種類
+type OpsCommandKind string
+
+const (
+	OpsCommandMake   OpsCommandKind = "make"   // Makefile のターゲット
+	OpsCommandScript OpsCommandKind = "script" // scripts/ 配下のファイル・シェルスクリプト
+	OpsCommandCI     OpsCommandKind = "ci"     // GitHub Actions・GitLab CI のジョブ
+)
+
+// opsCommandKinds はページでのコマンドの表示順と見出し
+var opsCommandKinds = []struct {
+	kind    OpsCommandKind
+	heading string
+	column  string
+}{
+	{OpsCommandMake, "Makefile のターゲット", "コマンド"},
+	{OpsCommandScript, "スクリプト", "コマンド"},
+	{OpsCommandCI, "CIのジョブ", "ジョブ"},
+}
+
+// OpsLocation はコマンドの定義・環境変数の参照箇所
+type OpsLocation struct {
+	SourceName string
+	Path       string
+	Line       int
+}
+
+// OpsCommand は Makefile・スクリプト・CI設定から検出した運用コマンド
+type OpsCommand struct {
+	Kind     OpsCommandKind
+	Command  string   // 実行方法（make <ターゲット>、スクリプトのパス、CIのジョブID）
+	Comment  string   // 定義に付いたコメント（ヘルプコメント・直前のコメント・スクリプト冒頭のコメント・ジョブ名）
+	Body     string   // 定義の本文（レシピ・スクリプト・ジョブのコマンド）
+	Summary  string   // LLMで要約したコマンドの内容（要約していない場合は空）
+	EnvVars  []string // 定義の中で参照している環境変数（名前順）
+	Location OpsLocation
+	endLine  int // 定義の最終行（環境変数の参照を対応付けるために使用する）
+}
+
+// OpsEnvVar は運用コマンドが参照している環境変数
+// ファイル内で代入している変数と、シェル・make・CIが設定する変数は含めない
+type OpsEnvVar struct {
+	Name      string
+	Default   string // 既定値（${VAR:-default}・VAR ?= default、ない場合は空）
+	Required  bool   // ${VAR:?message} で未設定をエラーにしている
+	Secret    bool   // CIのシークレット（${{ secrets.VAR }}）
+	Locations []OpsLocation
+}
+
+// generateRunbookSection は Makefile・スクリプト・CI設定から検出したコマンドと環境変数からセクションのページを生成する
+// コマンドの内容はLLMで要約し、要約できなかった場合は定義に付いたコメントを掲載する
+func (s *WikiService) generateRunbookSection(ctx context.Context, params GenerateParams, config SectionConfig) (*WikiPage, error) {
+	if s.runbook == nil {
+		return nil, fmt.Errorf("runbook reader is not configured")
+	}
+
+	productID, snapshotID := params.scope()
+	chunks, err := s.runbook.ListOpsFileChunks(ctx, productID, snapshotID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ops files: %w", err)
+	}
+	commands, envVars := DetectOps(AssembleBuildFiles(chunks))
+
+	page := &WikiPage{
+		Section:  config.Section,
+		Title:    config.Title,
+		FileName: config.FileName,
+	}
+	if len(commands) > 0 {
+		rendered, err := s.summarizeOpsCommands(ctx, config, commands)
+		if err != nil {
+			// 要約がなくてもコメントと定義位置の一覧として成立するため続行する
+			s.logger.Warn("failed to summarize ops commands", "section", config.Section, "error", err)
+		} else {
+			page.PromptName, page.PromptVersion = rendered.Name, rendered.Version
+		}
+	}
+	page.Content = RenderRunbook(config, commands, envVars)
+	return page, nil
+}
+
+// summarizeOpsCommands は先頭の runbookMaxCommands 件のコマンドの内容をLLMで要約し、Summary に設定する
+func (s *WikiService) summarizeOpsCommands(ctx context.Context, config SectionConfig, commands []*OpsCommand) (*prompt.Rendered, error) {
+	targets := commands[:min(len(commands), runbookMaxCommands)]
+	rendered, err := s.prompts.Render(ctx, prompt.NameWikiRunbook, runbookPromptData(targets))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build prompt: %w", err)
+	}
+	llmCtx := llm.WithCacheablePrompt(llm.WithMaxTokens(ctx, config.MaxTokens), rendered.Name, rendered.Version)
+	response, err := s.llm.GenerateCompletion(llmCtx, rendered.Text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate summaries: %w", err)
+	}
+	summaries, err := parseRunbookSummaries(response)
+	if err != nil {
+		return nil, err
+	}
+	for i, cmd := range targets {
+		cmd.Summary = summaries[i+1]
+	}
+	return rendered, nil
+}
+
+// runbookPromptData は運用コマンドの説明のプロンプトテンプレートに渡す値を構築する
+func runbookPromptData(commands []*OpsCommand) prompt.WikiRunbookData {
+	var sb strings.Builder
+	for i, cmd := range commands {
+		sb.WriteString(fmt.Sprintf("### [%d] `%s`（%s:%s:%d）\n", i+1, cmd.Command, cmd.Location.SourceName, cmd.Location.Path, cmd.Location.Line))
+		if cmd.Comment != "" {
+			sb.WriteString(fmt.Sprintf("コメント: %s\n", cmd.Comment))
+		}
+		if len(cmd.EnvVars) > 0 {
+			sb.WriteString(fmt.Sprintf("環境変数: %s\n", strings.Join(cmd.EnvVars, ", ")))
+		}
+		lines := strings.Split(cmd.Body, "\n")
+		if len(lines) > runbookBodyMaxLines {
+			lines = append(lines[:runbookBodyMaxLines], "…")
+		}
+		sb.WriteString("```\n")
+		sb.WriteString(strings.Join(lines, "\n"))
+		sb.WriteString("\n```\n\n")
+	}
+	return prompt.WikiRunbookData{Commands: sb.String()}
+}
+
+// runbookSummary はLLMが返すコマンドの説明のJSON表現
+type runbookSummary struct {
+	ID      int    `json:"id"`
+	Summary string `json:"summary"`
+}
+
+// parseRunbookSummaries はLLMの応答からコマンドの番号ごとの説明を取り出す
+func parseRunbookSummaries(response string) (map[int]string, error) {
+	start := strings.Index(response, "[")
+	end := strings.LastIndex(response, "]")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("no JSON array in response")
+	}
+
+	var parsed []runbookSummary
+	if err := json.Unmarshal([]byte(response[start:end+1]), &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	summaries := make(map[int]string, len(parsed))
+	for _, p := range parsed {
+		if summary := strings.Join(strings.Fields(p.Summary), " "); summary != "" {
+			summaries[p.ID] = summary
+		}
+	}
+	return summaries, nil
+}
+
+// opsFormat は運用コマンドを検出するファイルの形式
+type opsFormat int
+
+const (
+	opsFormatMake opsFormat = iota
+	opsFormatGitHubActions
+	opsFormatGitLabCI
+	opsFormatShell
+	opsFormatProgram // シェル以外のスクリプト（Python・Node.js など）
+)
+
+// opsFileFormat はファイルパスから形式を判定する
+func opsFileFormat(filePath string) opsFormat {
+	base := path.Base(filePath)
+	switch {
+	case base == "Makefile" || base == "GNUmakefile" || base == "makefile" || path.Ext(base) == ".mk":
+		return opsFormatMake
+	case strings.HasPrefix(filePath, ".github/workflows/"):
+		return opsFormatGitHubActions
+	case filePath == ".gitlab-ci.yml":
+		return opsFormatGitLabCI
+	}
+	switch path.Ext(base) {
+	case "", ".sh", ".bash", ".zsh":
+		return opsFormatShell
+	}
+	return opsFormatProgram
+}
+
+// DetectOps は Makefile のターゲット・スクリプト・CIのジョブと、それらが参照している環境変数を検出する
+// コマンドはファイル順、環境変数は名前順に返す
+func DetectOps(files []*BuildFile) ([]*OpsCommand, []*OpsEnvVar) {
+	var commands []*OpsCommand
+	envByName := make(map[string]*OpsEnvVar)
+	for _, file := range files {
+		format := opsFileFormat(file.Path)
+		var detected []*OpsCommand
+		switch format {
+		case opsFormatMake:
+			detected = detectMakeOps(file)
+		case opsFormatGitHubActions:
+			detected = detectGitHubActionsOps(file)
+		case opsFormatGitLabCI:
+			detected = detectGitLabCIOps(file)
+		default:
+			detected = detectScriptOps(file)
+		}
+
+		refs := scanEnvRefs(format, file)
+		for _, cmd := range detected {
+			for _, ref := range refs {
+				if ref.line >= cmd.Location.Line && ref.line <= cmd.endLine && !slices.Contains(cmd.EnvVars, ref.name) {
+					cmd.EnvVars = append(cmd.EnvVars, ref.name)
+				}
+			}
+			slices.Sort(cmd.EnvVars)
+		}
+		commands = append(commands, detected...)
+
+		for _, ref := range refs {
+			v, ok := envByName[ref.name]
+			if !ok {
+				v = &OpsEnvVar{Name: ref.name}
+				envByName[ref.name] = v
+			}
+			if v.Default == "" {
+				v.Default = ref.defaultValue
+			}
+			v.Required = v.Required || ref.required
+			v.Secret = v.Secret || ref.secret
+			loc := OpsLocation{SourceName: file.SourceName, Path: file.Path, Line: ref.line}
+			if !slices.Contains(v.Locations, loc) {
+				v.Locations = append(v.Locations, loc)
+			}
+		}
+	}
+
+	envVars := make([]*OpsEnvVar, 0, len(envByName))
+	for _, v := range envByName {
+		envVars = append(envVars, v)
+	}
+	slices.SortFunc(envVars, func(a, b *OpsEnvVar) int { return strings.Compare(a.Name, b.Name) })
+	return commands, envVars
+}
+
+// detectMakeOps は Makefile のターゲットを検出する
+// 説明は "target: ## 説明" のヘルプコメント、なければ直前の行のコメントとする
+func detectMakeOps(file *BuildFile) []*OpsCommand {
+	lines := strings.Split(file.Content, "\n")
+	invocation := "make "
+	if base := path.Base(file.Path); path.Ext(base) != ".mk" && path.Dir(file.Path) != "." {
+		invocation = "make -C " + path.Dir(file.Path) + " "
+	}
+
+	var commands []*OpsCommand
+	for i, line := range lines {
+		m := makeTargetPattern.FindStringSubmatch(strings.TrimRight(line, " \t\r"))
+		if m == nil {
+			continue
+		}
+		comment := strings.TrimSpace(m[2])
+		if comment == "" {
+			comment = precedingComment(lines, i)
+		}
+		end := i
+		for end+1 < len(lines) && strings.HasPrefix(lines[end+1], "\t") {
+			end++
+		}
+		commands = append(commands, &OpsCommand{
+			Kind:     OpsCommandMake,
+			Command:  invocation + m[1],
+			Comment:  comment,
+			Body:     strings.Join(lines[i:end+1], "\n"),
+			Location: OpsLocation{SourceName: file.SourceName, Path: file.Path, Line: file.StartLine + i},
+			endLine:  file.StartLine + end,
+		})
+	}
+	return commands
+}
+
+// precedingComment は i 行目の直前に続くコメント行を連結して返す
+func precedingComment(lines []string, i int) string {
+	var comments []string
+	for j := i - 1; j >= 0; j-- {
+		trimmed := strings.TrimSpace(lines[j])
+		if !strings.HasPrefix(trimmed, "#") {
+			break
+		}
+		if text := strings.TrimSpace(strings.TrimLeft(trimmed, "#")); text != "" {
+			comments = append([]string{text}, comments...)
+		}
+	}
+	return strings.Join(comments, " ")
+}
+
+// detectScriptOps はスクリプトのファイルを1つのコマンドとして返す
+// 説明はシバン行の後に続く冒頭のコメントとする
+func detectScriptOps(file *BuildFile) []*OpsCommand {
+	lines := strings.Split(file.Content, "\n")
+	var comments []string
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if i == 0 && strings.HasPrefix(trimmed, "#!") {
+			continue
+		}
+		if trimmed == "" && len(comments) == 0 {
+			continue
+		}
+		if !strings.HasPrefix(trimmed, "#") {
+			break
+		}
+		text := strings.TrimSpace(strings.TrimLeft(trimmed, "#"))
+		// shellcheck のディレクティブやエディタの設定は説明に含めない
+		if text == "" || strings.HasPrefix(text, "shellcheck ") || strings.HasPrefix(text, "-*-") {
+			continue
+		}
+		comments = append(comments, text)
+	}
+
+	return []*OpsCommand{{
+		Kind:     OpsCommandScript,
+		Command:  file.Path,
+		Comment:  strings.Join(comments, " "),
+		Body:     file.Content,
+		Location: OpsLocation{SourceName: file.SourceName, Path: file.Path, Line: file.StartLine},
+		endLine:  file.StartLine + len(lines) - 1,
+	}}
+}
+
+// detectGitHubActionsOps は GitHub Actions のワークフローのジョブを検出する
+// 本文はステップの run を連結したもの、説明はジョブ名とする
+func detectGitHubActionsOps(file *BuildFile) []*OpsCommand {
+	root := parseYAMLMapping(file.Content)
+	if root == nil {
+		return nil
+	}
+	var jobs *yaml.Node
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == "jobs" && root.Content[i+1].Kind == yaml.MappingNode {
+			jobs = root.Content[i+1]
+		}
+	}
+	if jobs == nil {
+		return nil
+	}
+
+	var commands []*OpsCommand
+	for i := 0; i+1 < len(jobs.Content); i += 2 {
+		var job struct {
+			Name  string `yaml:"name"`
+			Steps []struct {
+				Name string `yaml:"name"`
+				Uses string `yaml:"uses"`
+				Run  string `yaml:"run"`
+			} `yaml:"steps"`
+		}
+		if err := jobs.Content[i+1].Decode(&job); err != nil {
+			continue
+		}
+		var body []string
+		for _, step := range job.Steps {
+			switch {
+			case step.Run != "":
+				if step.Name != "" {
+					body = append(body, "# "+step.Name)
+				}
+				body = append(body, strings.TrimRight(step.Run, "\n"))
+			case step.Uses != "":
+				body = append(body, "# uses: "+step.Uses)
+			}
+		}
+		commands = append(commands, yamlJobCommand(file, jobs.Content, i, job.Name, body))
+	}
+	return commands
+}
+
+// detectGitLabCIOps は GitLab CI のジョブを検出する（本文は script を連結したもの）
+func detectGitLabCIOps(file *BuildFile) []*OpsCommand {
+	root := parseYAMLMapping(file.Content)
+	if root == nil {
+		return nil
+	}
+
+	var commands []*OpsCommand
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		name := root.Content[i].Value
+		if strings.HasPrefix(name, ".") || slices.Contains(gitlabReservedKeys, name) {
+			continue
+		}
+		var job struct {
+			Stage  string    `yaml:"stage"`
+			Script yaml.Node `yaml:"script"`
+		}
+		if err := root.Content[i+1].Decode(&job); err != nil {
+			continue
+		}
+		var body []string
+		switch job.Script.Kind {
+		case yaml.ScalarNode:
+			body = []string{job.Script.Value}
+		case yaml.SequenceNode:
+			for _, item := range job.Script.Content {
+				body = append(body, item.Value)
+			}
+		default:
+			continue
+		}
+		comment := ""
+		if job.Stage != "" {
+			comment = "stage: " + job.Stage
+		}
+		commands = append(commands, yamlJobCommand(file, root.Content, i, comment, body))
+	}
+	return commands
+}
+
+// yamlJobCommand はマッピングの i 番目のキーのジョブをコマンドにする
+// 定義の範囲は次のキーの直前の行まで（最後のジョブはファイルの末尾まで）とする
+func yamlJobCommand(file *BuildFile, pairs []*yaml.Node, i int, comment string, body []string) *OpsCommand {
+	key := pairs[i]
+	end := file.StartLine + strings.Count(file.Content, "\n")
+	if i+2 < len(pairs) {
+		end = file.StartLine + pairs[i+2].Line - 2
+	}
+	return &OpsCommand{
+		Kind:     OpsCommandCI,
+		Command:  key.Value,
+		Comment:  comment,
+		Body:     strings.Join(body, "\n"),
+		Location: OpsLocation{SourceName: file.SourceName, Path: file.Path, Line: file.StartLine + key.Line - 1},
+		endLine:  end,
+	}
+}
+
+// parseYAMLMapping はYAMLを解析し、ルートがマッピングの場合はそのノードを返す（それ以外は nil）
+func parseYAMLMapping(content string) *yaml.Node {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(content), &doc); err != nil || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil
+	}
+	return doc.Content[0]
+}
+
+// envRef はファイル内の環境変数の参照
+type envRef struct {
+	name         string
+	line         int
+	defaultValue string
+	required     bool
+	secret       bool
+	external     bool // CIの設定変数・シークレットの参照（ファイル内の代入によらず対象とする）
+}
+
+var (
+	// shellRefPattern はシェル・make のレシピ・CIのスクリプトの変数参照（${VAR}、${VAR:-default}、${VAR:?message}、$VAR）
+	// 環境変数は大文字で命名する慣習のため、小文字を含む変数はローカル変数とみなして対象外とする
+	shellRefPattern = regexp.MustCompile(`\$\{([A-Z][A-Z0-9_]*)(?:(:?[-=?+])([^}]*))?\}|\$([A-Z][A-Z0-9_]*)`)
+	// makeRefPattern は make の変数参照 $(VAR)
+	makeRefPattern = regexp.MustCompile(`\$\(([A-Z][A-Z0-9_]*)\)`)
+	// makeAssignPattern は make の変数代入（?= は環境変数で上書きできる既定値とみなす）
+	makeAssignPattern = regexp.MustCompile(`^\s*(?:(?:export|override)\s+)?([A-Za-z_][A-Za-z0-9_]*)\s*(\?|::|:|\+|!)?=\s*(.*)$`)
+	// shellAssignPattern はシェルの変数代入（export・local などを含む、YAMLのリストの "- " は除いて判定する）
+	shellAssignPattern = regexp.MustCompile(`^\s*(?:-\s+)?(?:(?:export|local|readonly|declare(?:\s+-[A-Za-z]+)*)\s+)?([A-Za-z_][A-Za-z0-9_]*)=`)
+	// shellLoopPattern は for・read で値を設定する変数
+	shellLoopPattern = regexp.MustCompile(`\b(?:for|read(?:\s+-[A-Za-z]+)*)\s+([A-Za-z_][A-Za-z0-9_]*)\b`)
+	// githubExprPattern は GitHub Actions のシークレット・設定変数の参照（${{ secrets.VAR }}、${{ vars.VAR }}）
+	githubExprPattern = regexp.MustCompile(`\$\{\{\s*(secrets|vars)\.([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+	// programRefPattern はシェル以外のスクリプトの環境変数の参照（os.getenv("VAR")、os.environ["VAR"]、process.env.VAR）
+	programRefPattern = regexp.MustCompile(`(?:getenv|Getenv|environ\.get)\(\s*["']([A-Z][A-Z0-9_]*)["']|environ\[\s*["']([A-Z][A-Z0-9_]*)["']\s*\]|process\.env\.([A-Z][A-Z0-9_]*)`)
+)
+
+// wellKnownEnvVars はシェル・make・CIが設定するため、設定が必要な環境変数として掲載しない変数
+var wellKnownEnvVars = map[string]bool{
+	"HOME": true, "PATH": true, "PWD": true, "OLDPWD": true, "USER": true, "SHELL": true, "TERM": true, "LANG": true,
+	"TMPDIR": true, "IFS": true, "HOSTNAME": true, "UID": true, "EUID": true, "PPID": true, "RANDOM": true, "LINENO": true,
+	"SECONDS": true, "OSTYPE": true, "CI": true, "MAKE": true, "MAKEFLAGS": true, "MAKEFILE_LIST": true, "MAKECMDGOALS": true,
+	"CURDIR": true, "GITHUB_TOKEN": true,
+}
+
+// wellKnownEnvPrefixes は同様に掲載しない変数の接頭辞（GitHub Actions・GitLab CI の定義済み変数など）
+var wellKnownEnvPrefixes = []string{"GITHUB_", "RUNNER_", "CI_", "BASH_"}
+
+// isWellKnownEnvVar はシェル・make・CIが設定する変数かを返す
+func isWellKnownEnvVar(name string) bool {
+	if wellKnownEnvVars[name] {
+		return true
+	}
+	for _, prefix := range wellKnownEnvPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// scanEnvRefs はファイル内の環境変数の参照を行番号順に返す
+// ファイル内で代入している変数は、既定値・必須の指定（${VAR:-default}、${VAR:?message}）を伴う参照のみを対象とする
+func scanEnvRefs(format opsFormat, file *BuildFile) []envRef {
+	lines := strings.Split(file.Content, "\n")
+	assigned := make(map[string]bool)
+	switch format {
+	case opsFormatGitHubActions, opsFormatGitLabCI:
+		if root := parseYAMLMapping(file.Content); root != nil {
+			collectYAMLEnvKeys(root, assigned)
+		}
+	}
+	for _, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		switch format {
+		case opsFormatMake:
+			if m := makeAssignPattern.FindStringSubmatch(line); m != nil && m[2] != "?" && !strings.HasPrefix(line, "\t") {
+				assigned[m[1]] = true
+			}
+		case opsFormatProgram:
+		default:
+			if m := shellAssignPattern.FindStringSubmatch(line); m != nil {
+				assigned[m[1]] = true
+			}
+			for _, m := range shellLoopPattern.FindAllStringSubmatch(line, -1) {
+				assigned[m[1]] = true
+			}
+		}
+	}
+
+	var refs []envRef
+	add := func(ref envRef) {
+		if isWellKnownEnvVar(ref.name) {
+			return
+		}
+		if assigned[ref.name] && ref.defaultValue == "" && !ref.required && !ref.external {
+			return
+		}
+		refs = append(refs, ref)
+	}
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		lineNo := file.StartLine + i
+
+		if format == opsFormatProgram {
+			for _, m := range programRefPattern.FindAllStringSubmatch(line, -1) {
+				add(envRef{name: m[1] + m[2] + m[3], line: lineNo})
+			}
+			continue
+		}
+		if format == opsFormatMake && !strings.HasPrefix(line, "\t") {
+			if m := makeAssignPattern.FindStringSubmatch(line); m != nil && m[2] == "?" {
+				add(envRef{name: m[1], line: lineNo, defaultValue: strings.TrimSpace(m[3])})
+				continue
+			}
+		}
+		if format == opsFormatMake {
+			for _, m := range makeRefPattern.FindAllStringSubmatch(line, -1) {
+				add(envRef{name: m[1], line: lineNo})
+			}
+		}
+		if format == opsFormatGitHubActions {
+			for _, m := range githubExprPattern.FindAllStringSubmatch(line, -1) {
+				add(envRef{name: m[2], line: lineNo, secret: m[1] == "secrets", external: true})
+			}
+		}
+		for _, m := range shellRefPattern.FindAllStringSubmatch(line, -1) {
+			if m[4] != "" {
+				add(envRef{name: m[4], line: lineNo})
+				continue
+			}
+			ref := envRef{name: m[1], line: lineNo}
+			switch strings.TrimPrefix(m[2], ":") {
+			case "-", "=":
+				ref.defaultValue = strings.Trim(m[3], `"'`)
+			case "?":
+				ref.required = true
+			}
+			add(ref)
+		}
+	}
+	return refs
+}
+
+// collectYAMLEnvKeys はCI設定の env（GitHub Actions）・variables（GitLab CI）で設定している変数名を集める
+func collectYAMLEnvKeys(node *yaml.Node, names map[string]bool) {
+	if node.Kind != yaml.MappingNode {
+		for _, child := range node.Content {
+			collectYAMLEnvKeys(child, names)
+		}
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if (key.Value == "env" || key.Value == "variables") && value.Kind == yaml.MappingNode {
+			for j := 0; j+1 < len(value.Content); j += 2 {
+				names[value.Content[j].Value] = true
+			}
+		}
+		collectYAMLEnvKeys(value, names)
+	}
+}
+
+// RenderRunbook は運用コマンドのページを生成する
+// コマンドは定義元の種類ごと、環境変数は名前順の表にまとめ、定義・参照箇所をファイルパスと行番号で示す
+func RenderRunbook(config SectionConfig, commands []*OpsCommand, envVars []*OpsEnvVar) string {
+	var sb strings.Builder
+	if config.Description != "" {
+		sb.WriteString(config.Description)
+		sb.WriteString("\n\n")
+	}
+
+	if len(commands) == 0 && len(envVars) == 0 {
+		sb.WriteString("運用スクリプト・Makefile・CI設定ファイルは見つかりませんでした。\n")
+		return sb.String()
+	}
+
+	sourceNames := make([]string, 0, len(commands)+len(envVars))
+	for _, cmd := range commands {
+		sourceNames = append(sourceNames, cmd.Location.SourceName)
+	}
+	for _, v := range envVars {
+		for _, loc := range v.Locations {
+			sourceNames = append(sourceNames, loc.SourceName)
+		}
+	}
+	multiSource := hasMultipleSources(sourceNames)
+	location := func(loc OpsLocation) string {
+		return "`" + qualifiedFilePath(loc.SourceName, loc.Path, multiSource) + ":" + strconv.Itoa(loc.Line) + "`"
+	}
+
+	if len(commands) > 0 {
+		sb.WriteString("## コマンド\n\n")
+		for _, group := range opsCommandKinds {
+			var rows []string
+			for _, cmd := range commands {
+				if cmd.Kind != group.kind {
+					continue
+				}
+				description := cmd.Summary
+				if description == "" {
+					description = cmd.Comment
+				}
+				rows = append(rows, fmt.Sprintf("| `%s` | %s | %s | %s |\n",
+					cmd.Command, escapeTableCell(description), codeList(cmd.EnvVars), location(cmd.Location)))
+			}
+			if len(rows) == 0 {
+				continue
+			}
+			sb.WriteString(fmt.Sprintf("### %s\n\n", group.heading))
+			sb.WriteString(fmt.Sprintf("| %s | 内容 | 環境変数 | 定義 |\n", group.column))
+			sb.WriteString("|---|---|---|---|\n")
+			sb.WriteString(strings.Join(rows, ""))
+			sb.WriteString("\n")
+		}
+	}
+
+	if len(envVars) > 0 {
+		sb.WriteString("## 環境変数\n\n")
+		sb.WriteString("コマンドが参照している環境変数です（ファイル内で代入している変数と、シェル・CIが設定する変数を除く）。\n\n")
+		sb.WriteString("| 変数 | 既定値 | 必須 | 参照箇所 |\n")
+		sb.WriteString("|---|---|---|---|\n")
+		for _, v := range envVars {
+			required := "-"
+			switch {
+			case v.Secret:
+				required = "CIのシークレット"
+			case v.Required:
+				required = "必須"
+			}
+			defaultValue := "-"
+			if v.Default != "" {
+				defaultValue = "`" + strings.ReplaceAll(v.Default, "`", "") + "`"
+			}
+			locations := make([]string, 0, runbookMaxLocations)
+			for _, loc := range v.Locations[:min(len(v.Locations), runbookMaxLocations)] {
+				locations = append(locations, location(loc))
+			}
+			if rest := len(v.Locations) - runbookMaxLocations; rest > 0 {
+				locations = append(locations, fmt.Sprintf("ほか%d件", rest))
+			}
+			sb.WriteString(fmt.Sprintf("| `%s` | %s | %s | %s |\n", v.Name, escapeTableCell(defaultValue), required, strings.Join(locations, ", ")))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// codeList は名前をコードとして列挙する（空の場合は -）
+func codeList(names []string) string {
+	if len(names) == 0 {
+		return "-"
+	}
+	quoted := make([]string, 0, len(names))
+	for _, name := range names {
+		quoted = append(quoted, "`"+name+"`")
+	}
+	return strings.Join(quoted, ", ")
+}
@@ -0,0 +1,229 @@
+package wiki
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jinford/dev-rag/internal/core/prompt"
+)
+
+func findOpsCommand(commands []*OpsCommand, command string) *OpsCommand {
+	for _, cmd := range commands {
+		if cmd.Command == command {
+			return cmd
+		}
+	}
+	return nil
+}
+
+func findOpsEnvVar(envVars []*OpsEnvVar, name string) *OpsEnvVar {
+	for _, v := range envVars {
+		if v.Name == name {
+			return v
+		}
+	}
+	return nil
+}
+
+func TestDetectOps_Makefile(t *testing.T) {
+	makefile := `VERSION ?= dev
+BIN := bin/app
+
+# Docker イメージをビルドする
+image:
+	docker build -t $(REGISTRY)/app:$(VERSION) .
+
+deploy: image ## 本番環境にデプロイ
+	kubectl --context $${KUBE_CONTEXT:?} apply -f deploy/
+	./scripts/notify.sh $(BIN)
+`
+	commands, envVars := DetectOps([]*BuildFile{{SourceName: "api", Path: "Makefile", StartLine: 1, Content: makefile}})
+
+	require.Len(t, commands, 2)
+	image := commands[0]
+	assert.Equal(t, "make image", image.Command)
+	assert.Equal(t, "Docker イメージをビルドする", image.Comment, "直前のコメントを説明にする")
+	assert.Equal(t, 5, image.Location.Line)
+	assert.Equal(t, []string{"REGISTRY", "VERSION"}, image.EnvVars)
+
+	deploy := commands[1]
+	assert.Equal(t, "本番環境にデプロイ", deploy.Comment)
+	assert.Equal(t, []string{"KUBE_CONTEXT"}, deploy.EnvVars, "ファイル内で代入している BIN は除く")
+
+	version := findOpsEnvVar(envVars, "VERSION")
+	require.NotNil(t, version)
+	assert.Equal(t, "dev", version.Default)
+	assert.Equal(t, 1, version.Locations[0].Line)
+	assert.True(t, findOpsEnvVar(envVars, "KUBE_CONTEXT").Required)
+	assert.Nil(t, findOpsEnvVar(envVars, "BIN"))
+
+	// サブディレクトリの Makefile は -C で実行する
+	commands, _ = DetectOps([]*BuildFile{{SourceName: "api", Path: "web/Makefile", StartLine: 1, Content: "build:\n\tnpm run build\n"}})
+	assert.Equal(t, "make -C web build", commands[0].Command)
+}
+
+func TestDetectOps_Script(t *testing.T) {
+	script := `#!/usr/bin/env bash
+# shellcheck disable=SC2086
+# データベースをバックアップして S3 にアップロードする
+set -euo pipefail
+
+BUCKET="${BACKUP_BUCKET:-s3://backups}"
+stamp=$(date +%s)
+for FILE in dump/*.sql; do
+  aws s3 cp "$FILE" "$BUCKET/$stamp/" --profile "$AWS_PROFILE"
+done
+pg_dump "$DATABASE_URL" > "dump/$HOME.sql"
+`
+	commands, envVars := DetectOps([]*BuildFile{
+		{SourceName: "api", Path: "scripts/backup.sh", StartLine: 1, Content: script},
+		{SourceName: "api", Path: "scripts/seed.py", StartLine: 1, Content: "import os\nurl = os.environ[\"DATABASE_URL\"]\ntoken = os.getenv('SEED_TOKEN')\n"},
+	})
+
+	require.Len(t, commands, 2)
+	assert.Equal(t, OpsCommandScript, commands[0].Kind)
+	assert.Equal(t, "scripts/backup.sh", commands[0].Command)
+	assert.Equal(t, "データベースをバックアップして S3 にアップロードする", commands[0].Comment)
+	assert.Equal(t, []string{"AWS_PROFILE", "BACKUP_BUCKET", "DATABASE_URL"}, commands[0].EnvVars, "代入・for で設定する変数とシェルが設定する変数は除く")
+	assert.Equal(t, []string{"DATABASE_URL", "SEED_TOKEN"}, commands[1].EnvVars)
+
+	bucket := findOpsEnvVar(envVars, "BACKUP_BUCKET")
+	require.NotNil(t, bucket)
+	assert.Equal(t, "s3://backups", bucket.Default)
+	databaseURL := findOpsEnvVar(envVars, "DATABASE_URL")
+	require.Len(t, databaseURL.Locations, 2)
+	assert.Equal(t, OpsLocation{SourceName: "api", Path: "scripts/backup.sh", Line: 11}, databaseURL.Locations[0])
+	assert.Equal(t, OpsLocation{SourceName: "api", Path: "scripts/seed.py", Line: 2}, databaseURL.Locations[1])
+}
+
+func TestDetectOps_CI(t *testing.T) {
+	workflow := `name: release
+env:
+  IMAGE: app
+jobs:
+  release:
+    name: Release
+    steps:
+      - uses: actions/checkout@v4
+      - name: Push image
+        run: docker push "$REGISTRY/$IMAGE"
+        env:
+          REGISTRY: ${{ vars.REGISTRY }}
+          TOKEN: ${{ secrets.DEPLOY_TOKEN }}
+  notify:
+    steps:
+      - run: curl -X POST "$SLACK_WEBHOOK" -d "$GITHUB_SHA"
+`
+	gitlab := `stages: [deploy]
+deploy:
+  stage: deploy
+  script:
+    - helm upgrade app chart/ --set image.tag=$CI_COMMIT_SHA --kube-context $KUBE_CONTEXT
+`
+	commands, envVars := DetectOps([]*BuildFile{
+		{SourceName: "api", Path: ".github/workflows/release.yml", StartLine: 1, Content: workflow},
+		{SourceName: "api", Path: ".gitlab-ci.yml", StartLine: 1, Content: gitlab},
+	})
+
+	require.Len(t, commands, 3)
+	release := findOpsCommand(commands, "release")
+	require.NotNil(t, release)
+	assert.Equal(t, OpsCommandCI, release.Kind)
+	assert.Equal(t, "Release", release.Comment)
+	assert.Equal(t, 5, release.Location.Line)
+	assert.Contains(t, release.Body, "# uses: actions/checkout@v4\n# Push image\ndocker push")
+	assert.Equal(t, []string{"DEPLOY_TOKEN", "REGISTRY"}, release.EnvVars, "env で設定する IMAGE は除く")
+
+	notify := findOpsCommand(commands, "notify")
+	assert.Equal(t, []string{"SLACK_WEBHOOK"}, notify.EnvVars, "GitHub Actions の定義済み変数は除く")
+
+	deploy := findOpsCommand(commands, "deploy")
+	require.NotNil(t, deploy)
+	assert.Equal(t, "stage: deploy", deploy.Comment)
+	assert.Equal(t, []string{"KUBE_CONTEXT"}, deploy.EnvVars)
+
+	assert.True(t, findOpsEnvVar(envVars, "DEPLOY_TOKEN").Secret)
+	assert.False(t, findOpsEnvVar(envVars, "REGISTRY").Secret)
+}
+
+func TestRenderRunbook(t *testing.T) {
+	config := SectionConfig{Title: "運用コマンド", Description: "運用コマンドの一覧"}
+	commands := []*OpsCommand{
+		{Kind: OpsCommandCI, Command: "release", Comment: "Release", Location: OpsLocation{SourceName: "api", Path: ".github/workflows/release.yml", Line: 5}},
+		{Kind: OpsCommandMake, Command: "make deploy", Comment: "本番環境にデプロイ", Summary: "イメージをビルドして本番環境に適用する", EnvVars: []string{"KUBE_CONTEXT"}, Location: OpsLocation{SourceName: "api", Path: "Makefile", Line: 8}},
+		{Kind: OpsCommandScript, Command: "scripts/backup.sh", Location: OpsLocation{SourceName: "api", Path: "scripts/backup.sh", Line: 1}},
+	}
+	envVars := []*OpsEnvVar{
+		{Name: "BACKUP_BUCKET", Default: "s3://backups", Locations: []OpsLocation{{SourceName: "api", Path: "scripts/backup.sh", Line: 6}}},
+		{Name: "DEPLOY_TOKEN", Secret: true, Locations: []OpsLocation{{SourceName: "api", Path: ".github/workflows/release.yml", Line: 13}}},
+		{Name: "KUBE_CONTEXT", Required: true, Locations: []OpsLocation{
+			{SourceName: "api", Path: "Makefile", Line: 9}, {SourceName: "api", Path: "Makefile", Line: 12},
+			{SourceName: "api", Path: "Makefile", Line: 15}, {SourceName: "api", Path: ".gitlab-ci.yml", Line: 5},
+		}},
+	}
+
+	content := RenderRunbook(config, commands, envVars)
+	assert.Contains(t, content, "運用コマンドの一覧\n\n## コマンド\n\n### Makefile のターゲット\n")
+	assert.Contains(t, content, "| `make deploy` | イメージをビルドして本番環境に適用する | `KUBE_CONTEXT` | `Makefile:8` |", "要約を優先する")
+	assert.Contains(t, content, "| `scripts/backup.sh` | - | - | `scripts/backup.sh:1` |")
+	assert.Contains(t, content, "| ジョブ | 内容 | 環境変数 | 定義 |\n|---|---|---|---|\n| `release` | Release | - | `.github/workflows/release.yml:5` |", "要約がない場合はコメント")
+	assert.Contains(t, content, "| `BACKUP_BUCKET` | `s3://backups` | - | `scripts/backup.sh:6` |")
+	assert.Contains(t, content, "| `DEPLOY_TOKEN` | - | CIのシークレット |")
+	assert.Contains(t, content, "| `KUBE_CONTEXT` | - | 必須 | `Makefile:9`, `Makefile:12`, `Makefile:15`, ほか1件 |")
+	assert.Less(t, strings.Index(content, "### スクリプト"), strings.Index(content, "### CIのジョブ"))
+
+	assert.Contains(t, RenderRunbook(config, nil, nil), "見つかりませんでした")
+}
+
+type runbookReaderStub struct {
+	chunks []*BuildFileChunk
+}
+
+func (r *runbookReaderStub) ListOpsFileChunks(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID]) ([]*BuildFileChunk, error) {
+	return r.chunks, nil
+}
+
+// runbookLLM は固定の応答を返す（err が設定されている場合は失敗する）
+type runbookLLM struct {
+	response string
+	err      error
+}
+
+func (l *runbookLLM) GenerateCompletion(ctx context.Context, text string) (string, error) {
+	return l.response, l.err
+}
+
+func TestGenerateRunbookSection(t *testing.T) {
+	reader := &runbookReaderStub{chunks: []*BuildFileChunk{
+		{SourceName: "api", Path: "Makefile", StartLine: 1, Content: "test: ## テストを実行\n\tgo test ./...\n"},
+	}}
+	config, ok := DefaultConfig().Section(SectionOperations)
+	require.True(t, ok)
+
+	svc := &WikiService{
+		runbook: reader,
+		llm:     &runbookLLM{response: "```json\n[{\"id\": 1, \"summary\": \"全パッケージの  テストを実行する\"}]\n```"},
+		prompts: prompt.Default(),
+		logger:  slog.New(slog.DiscardHandler),
+	}
+	page, err := svc.generateRunbookSection(context.Background(), GenerateParams{SnapshotID: uuid.New()}, config)
+	require.NoError(t, err)
+	assert.Equal(t, "operations.md", page.FileName)
+	assert.Equal(t, prompt.NameWikiRunbook, page.PromptName)
+	assert.Contains(t, page.Content, "| `make test` | 全パッケージの テストを実行する | - | `Makefile:1` |")
+
+	// 要約に失敗した場合はコメントを掲載する
+	svc.llm = &runbookLLM{err: errors.New("llm unavailable")}
+	page, err = svc.generateRunbookSection(context.Background(), GenerateParams{SnapshotID: uuid.New()}, config)
+	require.NoError(t, err)
+	assert.Empty(t, page.PromptName)
+	assert.Contains(t, page.Content, "| `make test` | テストを実行 | - | `Makefile:1` |")
+}
@@ -0,0 +1,137 @@
+package wiki
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultConcurrency はセクションを並行して生成する既定の数
+	DefaultConcurrency = 4
+	// defaultSectionAttempts はセクションの生成に失敗した場合の既定の試行回数（初回を含む）
+	defaultSectionAttempts = 3
+	// defaultSectionRetryDelay はセクションの生成を再試行するまでの待機時間（試行ごとに倍にする）
+	defaultSectionRetryDelay = 2 * time.Second
+)
+
+// WithWikiSectionRetry はセクションの生成に失敗した場合の試行回数（初回を含む）と、再試行までの待機時間を設定する
+func WithWikiSectionRetry(attempts int, delay time.Duration) WikiServiceOption {
+	return func(s *WikiService) {
+		s.sectionAttempts = attempts
+		s.sectionRetryDelay = delay
+	}
+}
+
+// sectionOutcome はセクションの生成結果
+type sectionOutcome struct {
+	pages []*WikiPage
+	err   error
+}
+
+// generateSections はセクションを依存関係（depends_on）の順に生成し、設定と同じ順の結果を返す
+// 依存先がすべて完了したセクションから、最大 concurrency 件ずつ並行して生成する
+// 依存先のセクションが生成に失敗した場合も、そのページを除いて生成を続ける
+func (s *WikiService) generateSections(ctx context.Context, params GenerateParams, configs []SectionConfig) []sectionOutcome {
+	concurrency := params.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+
+	index := make(map[WikiSection]int, len(configs))
+	for i, config := range configs {
+		index[config.Section] = i
+	}
+
+	outcomes := make([]sectionOutcome, len(configs))
+	done := make([]chan struct{}, len(configs))
+	for i := range done {
+		done[i] = make(chan struct{})
+	}
+	sem := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+	for i, config := range configs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer close(done[i])
+
+			// 依存先の完了を待つ（--section で選択しなかった依存先は対象外）
+			var dependencies []*WikiPage
+			for _, dep := range config.DependsOn {
+				j, ok := index[dep]
+				if !ok {
+					continue
+				}
+				select {
+				case <-done[j]:
+				case <-ctx.Done():
+					outcomes[i] = sectionOutcome{err: ctx.Err()}
+					return
+				}
+				if outcomes[j].err == nil {
+					dependencies = append(dependencies, outcomes[j].pages...)
+				}
+			}
+
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				outcomes[i] = sectionOutcome{err: ctx.Err()}
+				return
+			}
+			defer func() { <-sem }()
+
+			pages, err := s.generateSectionWithRetry(ctx, params, config, dependencies)
+			outcomes[i] = sectionOutcome{pages: pages, err: err}
+		}()
+	}
+	wg.Wait()
+
+	return outcomes
+}
+
+// generateSectionWithRetry はセクションを生成し、失敗した場合は待機時間を倍にしながら再試行する
+func (s *WikiService) generateSectionWithRetry(ctx context.Context, params GenerateParams, config SectionConfig, dependencies []*WikiPage) ([]*WikiPage, error) {
+	var pages []*WikiPage
+	err := s.withRetry(ctx, "retrying wiki section generation", config.Section, func() error {
+		var err error
+		pages, err = s.generateSection(ctx, params, config, dependencies)
+		return err
+	})
+	return pages, err
+}
+
+// withRetry は fn を実行し、失敗した場合は待機時間を倍にしながら最大 sectionAttempts 回まで試行する
+// 再試行するたびに message とセクションをログに出力する
+func (s *WikiService) withRetry(ctx context.Context, message string, section WikiSection, fn func() error) error {
+	attempts := max(s.sectionAttempts, 1)
+	delay := s.sectionRetryDelay
+
+	var lastErr error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if attempt == attempts || ctx.Err() != nil {
+			break
+		}
+
+		s.logger.Warn(message,
+			"section", section,
+			"attempt", attempt,
+			"error", err,
+		)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		delay *= 2
+	}
+	return fmt.Errorf("failed after %d attempts: %w", attempts, lastErr)
+}
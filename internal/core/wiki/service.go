@@ -6,8 +6,11 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jinford/dev-rag/internal/core/llm"
+	"github.com/jinford/dev-rag/internal/core/prompt"
 	"github.com/jinford/dev-rag/internal/core/search"
 )
 
@@ -17,13 +20,36 @@ type LLMClient interface {
 	GenerateCompletion(ctx context.Context, prompt string) (string, error)
 }
 
+// PromptRenderer はプロンプトテンプレートを展開するインターフェース
+type PromptRenderer interface {
+	Render(ctx context.Context, name string, data any) (*prompt.Rendered, error)
+}
+
 // WikiService はWiki生成のビジネスロジックを提供する
 type WikiService struct {
-	searchService *search.SearchService
-	repo          Repository
-	llm           LLMClient
-	fileReader    FileReader
-	logger        *slog.Logger
+	searchService  *search.SearchService
+	repo           Repository
+	llm            LLMClient
+	fileReader     FileReader
+	graphReader    DependencyGraphReader
+	symbolReader   APISymbolReader
+	glossary       GlossaryReader
+	onboarding     OnboardingReader
+	runbook        RunbookReader
+	decisions      DecisionReader
+	apiEndpoints   APIEndpointReader
+	infraResources InfraResourceReader
+	drift          ArchitectureDriftReader
+	hotspots       HotspotReader
+	dependencies   ExternalDependencyReader
+	licenses       LicenseReader
+	sourceFiles    SourceFileReader
+	snapshots      SnapshotReader
+	prompts        PromptRenderer
+	logger         *slog.Logger
+
+	sectionAttempts   int
+	sectionRetryDelay time.Duration
 }
 
 // WikiServiceOption は WikiService のオプション設定
@@ -36,6 +62,113 @@ func WithWikiLogger(logger *slog.Logger) WikiServiceOption {
 	}
 }
 
+// WithWikiDependencyGraph は図解（generator: diagram）の生成に使用する依存グラフの読み取りを設定する
+func WithWikiDependencyGraph(reader DependencyGraphReader) WikiServiceOption {
+	return func(s *WikiService) {
+		s.graphReader = reader
+	}
+}
+
+// WithWikiAPISymbols はAPIリファレンス（generator: api_reference）の生成に使用する宣言の読み取りを設定する
+func WithWikiAPISymbols(reader APISymbolReader) WikiServiceOption {
+	return func(s *WikiService) {
+		s.symbolReader = reader
+	}
+}
+
+// WithWikiGlossary は用語集ページ（generator: glossary）の生成に使用する用語集の読み取りを設定する
+func WithWikiGlossary(reader GlossaryReader) WikiServiceOption {
+	return func(s *WikiService) {
+		s.glossary = reader
+	}
+}
+
+// WithWikiOnboarding はオンボーディングガイド（generator: onboarding）の生成に使用する情報の読み取りを設定する
+func WithWikiOnboarding(reader OnboardingReader) WikiServiceOption {
+	return func(s *WikiService) {
+		s.onboarding = reader
+	}
+}
+
+// WithWikiRunbook は運用コマンドのページ（generator: runbook）の生成に使用する運用スクリプトの読み取りを設定する
+func WithWikiRunbook(reader RunbookReader) WikiServiceOption {
+	return func(s *WikiService) {
+		s.runbook = reader
+	}
+}
+
+// WithWikiDecisions は設計判断ページ（generator: decisions）の生成に使用する設計判断の記録の読み取りを設定する
+func WithWikiDecisions(reader DecisionReader) WikiServiceOption {
+	return func(s *WikiService) {
+		s.decisions = reader
+	}
+}
+
+// WithWikiAPIEndpoints はAPIカタログ（generator: api_catalog）の生成に使用するエンドポイントの読み取りを設定する
+func WithWikiAPIEndpoints(reader APIEndpointReader) WikiServiceOption {
+	return func(s *WikiService) {
+		s.apiEndpoints = reader
+	}
+}
+
+// WithWikiInfraResources はデプロイ構成（generator: deployment）の生成に使用するリソースの読み取りを設定する
+func WithWikiInfraResources(reader InfraResourceReader) WikiServiceOption {
+	return func(s *WikiService) {
+		s.infraResources = reader
+	}
+}
+
+// WithWikiArchitectureDrift は最近のアーキテクチャの変更（generator: architecture_changes）の生成に使用するドリフトの読み取りを設定する
+func WithWikiArchitectureDrift(reader ArchitectureDriftReader) WikiServiceOption {
+	return func(s *WikiService) {
+		s.drift = reader
+	}
+}
+
+// WithWikiHotspots は変更のリスクが高い関数の一覧（generator: hotspots）の生成に使用するホットスポットの読み取りを設定する
+func WithWikiHotspots(reader HotspotReader) WikiServiceOption {
+	return func(s *WikiService) {
+		s.hotspots = reader
+	}
+}
+
+// WithWikiExternalDependencies は外部依存関係のページ（generator: dependencies）の生成に使用する外部の依存関係の読み取りを設定する
+func WithWikiExternalDependencies(reader ExternalDependencyReader) WikiServiceOption {
+	return func(s *WikiService) {
+		s.dependencies = reader
+	}
+}
+
+// WithWikiLicenses はライセンスのページ（generator: licenses）の生成に使用するライセンスの読み取りを設定する
+func WithWikiLicenses(reader LicenseReader) WikiServiceOption {
+	return func(s *WikiService) {
+		s.licenses = reader
+	}
+}
+
+// WithWikiSourceFiles は生成ページの参照チェックに使用するスナップショットのファイル一覧の読み取りを設定する
+// 未設定の場合はソースファイルの引用を検証せず、Wikiページ間のリンクのみ検証する
+func WithWikiSourceFiles(reader SourceFileReader) WikiServiceOption {
+	return func(s *WikiService) {
+		s.sourceFiles = reader
+	}
+}
+
+// WithWikiSnapshots は目次（toc.json）に記録する対象スナップショットの読み取りを設定する
+// 未設定の場合は目次にスナップショットを記録しない
+func WithWikiSnapshots(reader SnapshotReader) WikiServiceOption {
+	return func(s *WikiService) {
+		s.snapshots = reader
+	}
+}
+
+// WithWikiPrompts はセクション・オンボーディングガイド・APIの概要のプロンプトを展開するテンプレートを設定する（既定は組み込みのテンプレート）
+func WithWikiPrompts(renderer PromptRenderer) WikiServiceOption {
+	return func(s *WikiService) {
+		s.prompts = renderer
+	}
+}
+
 // NewWikiService は新しいWikiServiceを作成する
 func NewWikiService(
 	searchService *search.SearchService,
@@ -49,7 +182,11 @@ func NewWikiService(
 		repo:          repo,
 		llm:           llm,
 		fileReader:    fileReader,
+		prompts:       prompt.Default(),
 		logger:        slog.Default(),
+
+		sectionAttempts:   defaultSectionAttempts,
+		sectionRetryDelay: defaultSectionRetryDelay,
 	}
 
 	for _, opt := range opts {
@@ -59,31 +196,44 @@ func NewWikiService(
 	if svc.logger == nil {
 		svc.logger = slog.Default()
 	}
+	if svc.prompts == nil {
+		svc.prompts = prompt.Default()
+	}
 
 	return svc
 }
 
 // Generate はWikiを生成する
-func (s *WikiService) Generate(ctx context.Context, params GenerateParams) error {
-	// バリデーション: ProductIDまたはSnapshotIDのいずれかが必須
-	if params.ProductID.IsAbsent() && params.SnapshotID == uuid.Nil {
-		return fmt.Errorf("either productID or snapshotID is required")
-	}
-	if params.OutputDir == "" {
-		return fmt.Errorf("outputDir is required")
+// ページの書き出し後に参照チェックを行い、結果を出力ディレクトリの link-report.json に書き出す
+// LLMで生成したページと使用したプロンプトテンプレートのバージョンは prompt-versions.json に、
+// ページの階層・タイトル・対象スナップショット・生成日時は toc.json に書き出す
+func (s *WikiService) Generate(ctx context.Context, params GenerateParams) (*GenerateResult, error) {
+	if err := params.validate(); err != nil {
+		return nil, err
 	}
 
-	// OutputDirを作成
-	if err := os.MkdirAll(params.OutputDir, 0755); err != nil {
-		return fmt.Errorf("failed to create output directory: %w", err)
+	result, err := s.writeWiki(ctx, params, params.OutputDir, DefaultLanguage, s.generatePages(ctx, params))
+	if err != nil {
+		return nil, err
 	}
+	result.Language = DefaultLanguage
+	return result, nil
+}
 
-	// 各セクションを生成
-	configs := GetSectionConfigs()
+// generatePages は設定のセクションを生成し、設定の順にページを返す
+// 生成に失敗したセクションはエラーを記載したページにする
+func (s *WikiService) generatePages(ctx context.Context, params GenerateParams) []*WikiPage {
+	wikiConfig := params.Config
+	if wikiConfig == nil {
+		wikiConfig = DefaultConfig()
+	}
+	configs := wikiConfig.Sections
 	pages := make([]*WikiPage, 0, len(configs))
 
-	for _, config := range configs {
-		page, err := s.generateSection(ctx, params, config)
+	// 依存関係の順に、依存しないセクションは並行して生成する（出力順は設定の順）
+	outcomes := s.generateSections(ctx, params, configs)
+	for i, config := range configs {
+		sectionPages, err := outcomes[i].pages, outcomes[i].err
 		if err != nil {
 			// エラーが発生しても続行可能な範囲で続行
 			s.logger.Warn("failed to generate section",
@@ -91,71 +241,251 @@ func (s *WikiService) Generate(ctx context.Context, params GenerateParams) error
 				"error", err,
 			)
 			// 空のページを作成
-			page = &WikiPage{
+			sectionPages = []*WikiPage{{
 				Section:  config.Section,
 				Title:    config.Title,
 				FileName: config.FileName,
 				Content:  fmt.Sprintf("# %s\n\nエラーが発生したため、このセクションを生成できませんでした。\n\nエラー: %v\n", config.Title, err),
-			}
+			}}
 		}
-		pages = append(pages, page)
+		pages = append(pages, sectionPages...)
+	}
+	return pages
+}
+
+// writeWiki はページを outputDir に書き出し、参照チェックの結果・ページごとのプロンプトテンプレート・目次を書き出す
+func (s *WikiService) writeWiki(ctx context.Context, params GenerateParams, outputDir string, language Language, pages []*WikiPage) (*GenerateResult, error) {
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+	// 目次は今回生成しなかったページの情報を前回の目次から引き継ぐため、書き出す前に読み込む
+	previousTOC, err := ReadTOC(outputDir)
+	if err != nil {
+		s.logger.Warn("failed to read previous toc, rebuilding from generated pages", "error", err)
 	}
 
 	// ファイルに書き出し
 	for _, page := range pages {
-		outputPath := filepath.Join(params.OutputDir, page.FileName)
-		if err := os.WriteFile(outputPath, []byte(page.Content), 0644); err != nil {
-			return fmt.Errorf("failed to write %s: %w", page.FileName, err)
+		if err := writePage(outputDir, page); err != nil {
+			return nil, err
 		}
 	}
 
+	// 参照チェック
+	report := CheckReferences(pages, s.listSourceFiles(ctx, params))
+	if err := writeLinkReport(outputDir, report); err != nil {
+		return nil, err
+	}
+	if report.BrokenCount() > 0 {
+		s.logger.Warn("wiki pages contain broken references",
+			"broken", report.BrokenCount(),
+			"checked", report.CheckedReferences,
+		)
+	}
+
+	prompts := collectPagePrompts(pages)
+	if err := writePagePrompts(outputDir, prompts); err != nil {
+		return nil, err
+	}
+
+	toc := buildTOC(outputDir, language, pages, previousTOC, s.listSnapshots(ctx, params), time.Now())
+	if err := writeTOC(outputDir, toc); err != nil {
+		return nil, err
+	}
+
+	return &GenerateResult{OutputDir: outputDir, Pages: len(pages), LinkReport: report, Prompts: prompts}, nil
+}
+
+// listSourceFiles は参照チェックに使用するファイル一覧を取得する
+// 読み取りが未設定または失敗した場合は nil を返し、ソースファイルの引用の検証を省略する
+func (s *WikiService) listSourceFiles(ctx context.Context, params GenerateParams) []*SourceFileLines {
+	if s.sourceFiles == nil {
+		return nil
+	}
+	productID, snapshotID := params.scope()
+	files, err := s.sourceFiles.ListSourceFileLines(ctx, productID, snapshotID)
+	if err != nil {
+		s.logger.Warn("failed to list source files for reference check", "error", err)
+		return nil
+	}
+	if files == nil {
+		files = []*SourceFileLines{}
+	}
+	return files
+}
+
+// listSnapshots は目次に記録する対象スナップショットを取得する
+// 読み取りが未設定または失敗した場合は nil を返し、目次にスナップショットを記録しない
+func (s *WikiService) listSnapshots(ctx context.Context, params GenerateParams) []*TOCSnapshot {
+	if s.snapshots == nil {
+		return nil
+	}
+	productID, snapshotID := params.scope()
+	snapshots, err := s.snapshots.ListScopeSnapshots(ctx, productID, snapshotID)
+	if err != nil {
+		s.logger.Warn("failed to list snapshots for toc", "error", err)
+		return nil
+	}
+	return snapshots
+}
+
+// writePage はWikiページを出力ディレクトリに書き出す
+// ファイル名にサブディレクトリを含む場合はディレクトリも作成する
+func writePage(outputDir string, page *WikiPage) error {
+	outputPath := filepath.Join(outputDir, filepath.FromSlash(page.FileName))
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", page.FileName, err)
+	}
+	if err := os.WriteFile(outputPath, []byte(page.Content), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", page.FileName, err)
+	}
 	return nil
 }
 
-// generateSection は単一のセクションを生成する
-func (s *WikiService) generateSection(ctx context.Context, params GenerateParams, config SectionConfig) (*WikiPage, error) {
+// generateSection は単一のセクションを生成方法に応じて生成する
+// APIリファレンスのように1つのセクションから複数のページを生成する場合がある
+// dependencies は depends_on のセクションで生成したページ（LLMで生成するセクションのコンテキストに含める）
+func (s *WikiService) generateSection(ctx context.Context, params GenerateParams, config SectionConfig, dependencies []*WikiPage) ([]*WikiPage, error) {
+	switch config.Generator {
+	case GeneratorDiagram:
+		page, err := s.generateDiagramSection(ctx, params, config)
+		if err != nil {
+			return nil, err
+		}
+		return []*WikiPage{page}, nil
+	case GeneratorAPIReference:
+		return s.generateAPIReferenceSection(ctx, params, config)
+	case GeneratorGlossary:
+		page, err := s.generateGlossarySection(ctx, params, config)
+		if err != nil {
+			return nil, err
+		}
+		return []*WikiPage{page}, nil
+	case GeneratorOnboarding:
+		page, err := s.generateOnboardingSection(ctx, params, config)
+		if err != nil {
+			return nil, err
+		}
+		return []*WikiPage{page}, nil
+	case GeneratorDecisions:
+		page, err := s.generateDecisionsSection(ctx, params, config)
+		if err != nil {
+			return nil, err
+		}
+		return []*WikiPage{page}, nil
+	case GeneratorAPICatalog:
+		page, err := s.generateAPICatalogSection(ctx, params, config)
+		if err != nil {
+			return nil, err
+		}
+		return []*WikiPage{page}, nil
+	case GeneratorDeployment:
+		page, err := s.generateDeploymentSection(ctx, params, config)
+		if err != nil {
+			return nil, err
+		}
+		return []*WikiPage{page}, nil
+	case GeneratorArchitectureChanges:
+		page, err := s.generateArchitectureChangesSection(ctx, params, config)
+		if err != nil {
+			return nil, err
+		}
+		return []*WikiPage{page}, nil
+	case GeneratorHotspots:
+		page, err := s.generateHotspotsSection(ctx, params, config)
+		if err != nil {
+			return nil, err
+		}
+		return []*WikiPage{page}, nil
+	case GeneratorRunbook:
+		page, err := s.generateRunbookSection(ctx, params, config)
+		if err != nil {
+			return nil, err
+		}
+		return []*WikiPage{page}, nil
+	case GeneratorDependencies:
+		page, err := s.generateDependenciesSection(ctx, params, config)
+		if err != nil {
+			return nil, err
+		}
+		return []*WikiPage{page}, nil
+	case GeneratorLicenses:
+		page, err := s.generateLicensesSection(ctx, params, config)
+		if err != nil {
+			return nil, err
+		}
+		return []*WikiPage{page}, nil
+	default:
+		page, err := s.generateLLMSection(ctx, params, config, dependencies)
+		if err != nil {
+			return nil, err
+		}
+		return []*WikiPage{page}, nil
+	}
+}
+
+// generateLLMSection は検索したコンテキストを基にLLMでセクションのページを生成する
+func (s *WikiService) generateLLMSection(ctx context.Context, params GenerateParams, config SectionConfig, dependencies []*WikiPage) (*WikiPage, error) {
 	// 1. 事前定義クエリでSearchServiceを呼び出し
-	summaryResults, chunkResults, err := s.searchContext(ctx, params, config.Query)
+	summaryResults, chunkResults, err := s.searchContext(ctx, params, config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search context: %w", err)
 	}
 
 	// 2. プロンプト構築
-	prompt := BuildSectionPrompt(config, summaryResults, chunkResults)
+	rendered, err := s.prompts.Render(ctx, prompt.NameWikiSection, sectionPromptData(config, summaryResults, chunkResults, dependencies))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build prompt: %w", err)
+	}
 
-	// 3. LLMで生成
-	content, err := s.llm.GenerateCompletion(ctx, prompt)
+	// 3. LLMで生成（セクションの最大出力トークン数を適用、コンテキストが同じ場合はキャッシュした応答を使用）
+	llmCtx := llm.WithCacheablePrompt(llm.WithMaxTokens(ctx, config.MaxTokens), rendered.Name, rendered.Version)
+	content, err := s.llm.GenerateCompletion(llmCtx, rendered.Text)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate content: %w", err)
 	}
 
 	// 4. WikiPageを作成
 	page := &WikiPage{
-		Section:  config.Section,
-		Title:    config.Title,
-		FileName: config.FileName,
-		Content:  content,
+		Section:       config.Section,
+		Title:         config.Title,
+		FileName:      config.FileName,
+		Content:       content,
+		PromptName:    rendered.Name,
+		PromptVersion: rendered.Version,
 	}
 
 	return page, nil
 }
 
-// searchContext はクエリを使ってコンテキストを検索する
+// searchContext はセクションのクエリを使ってコンテキストを検索する
 func (s *WikiService) searchContext(
 	ctx context.Context,
 	params GenerateParams,
-	query string,
+	config SectionConfig,
 ) ([]*search.SummarySearchResult, []*search.SearchResult, error) {
+	chunkLimit := config.ChunkLimit
+	if chunkLimit <= 0 {
+		chunkLimit = DefaultChunkLimit
+	}
+	summaryLimit := config.SummaryLimit
+	if summaryLimit <= 0 {
+		summaryLimit = DefaultSummaryLimit
+	}
+
 	// ハイブリッド検索パラメータを構築
 	searchParams := search.HybridSearchParams{
-		Query:        query,
-		ChunkLimit:   10,
-		SummaryLimit: 5,
+		Query:        config.Query,
+		ChunkLimit:   chunkLimit,
+		SummaryLimit: summaryLimit,
 		SummaryFilter: &search.SummarySearchFilter{
 			// アーキテクチャ要約を優先
 			SummaryTypes: []string{"architecture", "directory", "file"},
 		},
 	}
+	if len(config.Domains) > 0 {
+		searchParams.ChunkFilter = &search.SearchFilter{Domains: config.Domains}
+	}
 
 	// ProductIDが指定されている場合はプロダクト横断検索、
 	// それ以外はSnapshotID検索
@@ -175,11 +505,13 @@ func (s *WikiService) searchContext(
 }
 
 // RegenerateSection は指定されたセクションのみを再生成する
+// config が nil の場合は既定のWiki生成設定からセクションを探す
 func (s *WikiService) RegenerateSection(
 	ctx context.Context,
 	snapshotID uuid.UUID,
 	outputDir string,
 	section WikiSection,
+	config *Config,
 ) error {
 	// バリデーション
 	if snapshotID == uuid.Nil {
@@ -190,16 +522,11 @@ func (s *WikiService) RegenerateSection(
 	}
 
 	// セクション設定を取得
-	configs := GetSectionConfigs()
-	var targetConfig *SectionConfig
-	for _, config := range configs {
-		if config.Section == section {
-			targetConfig = &config
-			break
-		}
+	if config == nil {
+		config = DefaultConfig()
 	}
-
-	if targetConfig == nil {
+	targetConfig, ok := config.Section(section)
+	if !ok {
 		return fmt.Errorf("unknown section: %s", section)
 	}
 
@@ -208,20 +535,43 @@ func (s *WikiService) RegenerateSection(
 		SnapshotID: snapshotID,
 		OutputDir:  outputDir,
 	}
-	page, err := s.generateSection(ctx, params, *targetConfig)
+	pages, err := s.generateSection(ctx, params, targetConfig, readDependencyPages(outputDir, config, targetConfig))
 	if err != nil {
 		return fmt.Errorf("failed to generate section: %w", err)
 	}
 
 	// ファイル書き出し
-	outputPath := filepath.Join(outputDir, page.FileName)
-	if err := os.WriteFile(outputPath, []byte(page.Content), 0644); err != nil {
-		return fmt.Errorf("failed to write file: %w", err)
+	for _, page := range pages {
+		if err := writePage(outputDir, page); err != nil {
+			return err
+		}
 	}
-
 	return nil
 }
 
+// readDependencyPages は depends_on のセクションの生成済みのページを出力ディレクトリから読み込む
+// 読み込めないページ（未生成など）は含めない
+func readDependencyPages(outputDir string, config *Config, target SectionConfig) []*WikiPage {
+	var pages []*WikiPage
+	for _, dep := range target.DependsOn {
+		depConfig, ok := config.Section(dep)
+		if !ok {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(outputDir, filepath.FromSlash(depConfig.FileName)))
+		if err != nil {
+			continue
+		}
+		pages = append(pages, &WikiPage{
+			Section:  depConfig.Section,
+			Title:    depConfig.Title,
+			FileName: depConfig.FileName,
+			Content:  string(content),
+		})
+	}
+	return pages
+}
+
 // ReadSourceFile はスナップショット内のソースファイルを読み取る
 func (s *WikiService) ReadSourceFile(ctx context.Context, snapshotID uuid.UUID, filePath string) (string, error) {
 	if s.fileReader == nil {
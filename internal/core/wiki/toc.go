@@ -0,0 +1,199 @@
+package wiki
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TOCFileName は目次（ページの階層・タイトル・対象スナップショット・生成日時）を書き出すファイル名
+// 描画側がMarkdownを解析せずにナビゲーションを組み立てるためのもの
+const TOCFileName = "toc.json"
+
+// TOC はWikiの目次
+type TOC struct {
+	Language    Language       `json:"language"`
+	GeneratedAt time.Time      `json:"generated_at"`
+	Snapshots   []*TOCSnapshot `json:"snapshots"`
+	Pages       []*TOCEntry    `json:"pages"`
+}
+
+// TOCSnapshot はWikiの生成の対象としたスナップショット
+type TOCSnapshot struct {
+	SourceName        string     `json:"source_name"`
+	SnapshotID        uuid.UUID  `json:"snapshot_id"`
+	VersionIdentifier string     `json:"version_identifier"`
+	IndexedAt         *time.Time `json:"indexed_at,omitempty"`
+}
+
+// TOCEntry は目次のページ
+// サブディレクトリのページは同名の .md のページ（なければディレクトリのエントリ）の子にする
+type TOCEntry struct {
+	Path        string      `json:"path"` // 出力ディレクトリからの相対パス（ディレクトリのエントリは末尾 /）
+	Title       string      `json:"title"`
+	Section     WikiSection `json:"section,omitempty"`
+	GeneratedAt *time.Time  `json:"generated_at,omitempty"` // ページを生成した日時（ディレクトリのエントリは空）
+	Children    []*TOCEntry `json:"children,omitempty"`
+}
+
+// buildTOC は書き出したページから目次を作成する
+// previous（前回の目次）のページのうち今回生成せず、出力ディレクトリに残っているもの（--section で選択しなかったセクションなど）は前回の情報を引き継ぐ
+func buildTOC(outputDir string, language Language, pages []*WikiPage, previous *TOC, snapshots []*TOCSnapshot, now time.Time) *TOC {
+	var entries []*TOCEntry
+	generated := make(map[string]bool, len(pages))
+	for _, page := range pages {
+		generatedAt := now
+		entries = append(entries, &TOCEntry{
+			Path:        page.FileName,
+			Title:       pageTitle(page),
+			Section:     page.Section,
+			GeneratedAt: &generatedAt,
+		})
+		generated[page.FileName] = true
+	}
+	if previous != nil {
+		for _, entry := range flattenTOC(previous.Pages) {
+			if entry.GeneratedAt == nil || generated[entry.Path] {
+				continue
+			}
+			if _, err := os.Stat(filepath.Join(outputDir, filepath.FromSlash(entry.Path))); err != nil {
+				continue
+			}
+			entries = append(entries, &TOCEntry{Path: entry.Path, Title: entry.Title, Section: entry.Section, GeneratedAt: entry.GeneratedAt})
+		}
+	}
+
+	if snapshots == nil {
+		snapshots = []*TOCSnapshot{}
+	}
+	return &TOC{
+		Language:    language,
+		GeneratedAt: now,
+		Snapshots:   snapshots,
+		Pages:       tocTree(entries),
+	}
+}
+
+// pageTitle は目次に載せるページのタイトルを返す
+// 翻訳したページは設定のタイトルが翻訳されていないため、本文の最初の見出しを使用する
+func pageTitle(page *WikiPage) string {
+	if page.Language != "" && page.Language != DefaultLanguage {
+		if heading := firstHeading(page.Content); heading != "" {
+			return heading
+		}
+	}
+	if page.Title != "" {
+		return page.Title
+	}
+	if heading := firstHeading(page.Content); heading != "" {
+		return heading
+	}
+	return strings.TrimSuffix(path.Base(page.FileName), ".md")
+}
+
+// firstHeading はMarkdownの最初の見出しの文字列を返す（見出しがない場合は空）
+func firstHeading(content string) string {
+	inCode := false
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inCode = !inCode
+			continue
+		}
+		if inCode || !strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if heading := strings.TrimSpace(strings.TrimLeft(trimmed, "#")); heading != "" {
+			return heading
+		}
+	}
+	return ""
+}
+
+// tocTree はページを階層にまとめる（各階層のページは entries の順）
+func tocTree(entries []*TOCEntry) []*TOCEntry {
+	byPath := make(map[string]*TOCEntry, len(entries))
+	for _, entry := range entries {
+		byPath[entry.Path] = entry
+	}
+
+	var roots []*TOCEntry
+	attach := func(parentPath string, entry *TOCEntry) {
+		if parentPath == "" {
+			roots = append(roots, entry)
+			return
+		}
+		parent := byPath[parentPath]
+		parent.Children = append(parent.Children, entry)
+	}
+
+	var parentOf func(entryPath string) string
+	parentOf = func(entryPath string) string {
+		dir := path.Dir(strings.TrimSuffix(entryPath, "/"))
+		if dir == "." {
+			return ""
+		}
+		if _, ok := byPath[dir+".md"]; ok {
+			return dir + ".md"
+		}
+		// 同名の .md がないディレクトリはディレクトリのエントリにまとめる
+		dirPath := dir + "/"
+		if _, ok := byPath[dirPath]; !ok {
+			dirEntry := &TOCEntry{Path: dirPath, Title: path.Base(dir)}
+			byPath[dirPath] = dirEntry
+			attach(parentOf(dirPath), dirEntry)
+		}
+		return dirPath
+	}
+
+	for _, entry := range entries {
+		attach(parentOf(entry.Path), entry)
+	}
+	return roots
+}
+
+// flattenTOC は目次のページを親から順に並べて返す
+func flattenTOC(entries []*TOCEntry) []*TOCEntry {
+	var flat []*TOCEntry
+	for _, entry := range entries {
+		flat = append(flat, entry)
+		flat = append(flat, flattenTOC(entry.Children)...)
+	}
+	return flat
+}
+
+// ReadTOC は出力ディレクトリの目次を読み込む（目次がない場合は nil）
+func ReadTOC(outputDir string) (*TOC, error) {
+	data, err := os.ReadFile(filepath.Join(outputDir, TOCFileName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", TOCFileName, err)
+	}
+	var toc TOC
+	if err := json.Unmarshal(data, &toc); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", TOCFileName, err)
+	}
+	return &toc, nil
+}
+
+// writeTOC は目次を出力ディレクトリに書き出す
+func writeTOC(outputDir string, toc *TOC) error {
+	data, err := json.MarshalIndent(toc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal toc: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(outputDir, TOCFileName), append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", TOCFileName, err)
+	}
+	return nil
+}
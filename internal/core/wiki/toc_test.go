@@ -0,0 +1,87 @@
+package wiki
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildTOC(t *testing.T) {
+	now := time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC)
+	pages := []*WikiPage{
+		{Section: SectionOverview, Title: "概要", FileName: "README.md"},
+		{Section: "api", Title: "APIリファレンス", FileName: "api-reference.md"},
+		{Section: "api", Title: "search", FileName: "api-reference/search.md"},
+		{Section: "guides", Title: "セットアップ", FileName: "guides/setup.md"},
+	}
+
+	toc := buildTOC(t.TempDir(), DefaultLanguage, pages, nil, nil, now)
+	assert.Equal(t, DefaultLanguage, toc.Language)
+	assert.Equal(t, now, toc.GeneratedAt)
+	assert.Empty(t, toc.Snapshots)
+	require.Len(t, toc.Pages, 3)
+
+	assert.Equal(t, "README.md", toc.Pages[0].Path)
+	assert.Equal(t, "概要", toc.Pages[0].Title)
+	assert.Equal(t, now, *toc.Pages[0].GeneratedAt)
+
+	// サブディレクトリのページは同名の .md のページの子にする
+	api := toc.Pages[1]
+	require.Len(t, api.Children, 1)
+	assert.Equal(t, "api-reference/search.md", api.Children[0].Path)
+
+	// 同名の .md がないディレクトリはディレクトリのエントリにまとめる
+	guides := toc.Pages[2]
+	assert.Equal(t, "guides/", guides.Path)
+	assert.Equal(t, "guides", guides.Title)
+	assert.Nil(t, guides.GeneratedAt)
+	require.Len(t, guides.Children, 1)
+	assert.Equal(t, "セットアップ", guides.Children[0].Title)
+}
+
+func TestBuildTOC_KeepsPreviousPages(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "architecture.md"), []byte("## アーキテクチャ\n"), 0o644))
+
+	before := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	previous := &TOC{Pages: []*TOCEntry{
+		{Path: "README.md", Title: "概要", Section: SectionOverview, GeneratedAt: &before},
+		{Path: "architecture.md", Title: "アーキテクチャ", GeneratedAt: &before},
+		{Path: "removed.md", Title: "削除済み", GeneratedAt: &before},
+	}}
+
+	now := before.Add(24 * time.Hour)
+	toc := buildTOC(dir, DefaultLanguage, []*WikiPage{{Section: SectionOverview, Title: "概要", FileName: "README.md"}}, previous, []*TOCSnapshot{{SourceName: "backend", VersionIdentifier: "abc123"}}, now)
+
+	// 今回生成したページは生成日時を更新し、生成しなかったページは出力ディレクトリに残っている場合のみ引き継ぐ
+	require.Len(t, toc.Pages, 2)
+	assert.Equal(t, now, *toc.Pages[0].GeneratedAt)
+	assert.Equal(t, "architecture.md", toc.Pages[1].Path)
+	assert.Equal(t, before, *toc.Pages[1].GeneratedAt)
+	assert.Equal(t, "backend", toc.Snapshots[0].SourceName)
+}
+
+func TestPageTitle(t *testing.T) {
+	assert.Equal(t, "概要", pageTitle(&WikiPage{Title: "概要", FileName: "README.md", Content: "## Overview\n"}))
+	// 翻訳したページは本文の最初の見出し（コードブロック内は除く）
+	translated := &WikiPage{Title: "概要", FileName: "README.md", Language: LanguageEnglish, Content: "```bash\n# install\n```\n\n## Overview\n"}
+	assert.Equal(t, "Overview", pageTitle(translated))
+	assert.Equal(t, "search", pageTitle(&WikiPage{FileName: "api-reference/search.md"}))
+}
+
+func TestReadTOC(t *testing.T) {
+	dir := t.TempDir()
+	toc, err := ReadTOC(dir)
+	require.NoError(t, err)
+	assert.Nil(t, toc)
+
+	written := buildTOC(dir, LanguageEnglish, []*WikiPage{{Title: "Overview", FileName: "README.md"}}, nil, nil, time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, writeTOC(dir, written))
+	toc, err = ReadTOC(dir)
+	require.NoError(t, err)
+	assert.Equal(t, written, toc)
+}
@@ -0,0 +1,136 @@
+package completion
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/jinford/dev-rag/internal/infra/openai"
+)
+
+// DefaultOllamaBaseURL は Ollama のOpenAI互換エンドポイント
+const DefaultOllamaBaseURL = "http://localhost:11434/v1"
+
+// Client はテキスト生成を行う LLM クライアント
+type Client interface {
+	GenerateCompletion(ctx context.Context, prompt string) (string, error)
+}
+
+// Config は LLM クライアントの生成に必要な設定
+type Config struct {
+	Provider        string
+	Model           string
+	APIKey          string
+	BaseURL         string
+	AzureAPIVersion string
+}
+
+// Factory は設定から LLM クライアントを生成する
+type Factory func(cfg Config) (Client, error)
+
+// Provider はレジストリに登録されるプロバイダー定義
+type Provider struct {
+	Factory   Factory
+	Retryable func(error) bool // レート制限などリトライすべきエラーの判定
+}
+
+var (
+	mu        sync.RWMutex
+	providers = map[string]Provider{
+		"openai": {Factory: newOpenAI, Retryable: openai.IsRateLimitError},
+		"azure":  {Factory: newAzure, Retryable: openai.IsRateLimitError},
+		"ollama": {Factory: newOllama, Retryable: openai.IsRateLimitError},
+	}
+)
+
+// Register はプロバイダーを登録する（同名の場合は上書き）
+func Register(name string, p Provider) {
+	mu.Lock()
+	defer mu.Unlock()
+	providers[name] = p
+}
+
+// Providers は登録済みのプロバイダー名を返す
+func Providers() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return providersLocked()
+}
+
+// Lookup はプロバイダー定義を返す
+func Lookup(name string) (Provider, error) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	p, ok := providers[name]
+	if !ok {
+		return Provider{}, fmt.Errorf("unknown LLM provider %q (available: %v)", name, providersLocked())
+	}
+	return p, nil
+}
+
+// New は設定されたプロバイダーの LLM クライアントを生成する
+func New(cfg Config) (Client, error) {
+	p, err := Lookup(cfg.Provider)
+	if err != nil {
+		return nil, err
+	}
+	return p.Factory(cfg)
+}
+
+func providersLocked() []string {
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func newOpenAI(cfg Config) (Client, error) {
+	model := cfg.Model
+	if model == "" {
+		model = openai.DefaultModel
+	}
+	var opts []openai.ClientOption
+	if cfg.BaseURL != "" {
+		opts = append(opts, openai.WithBaseURL(cfg.BaseURL))
+	}
+	return openai.NewClientWithAPIKey(cfg.APIKey, model, opts...)
+}
+
+func newAzure(cfg Config) (Client, error) {
+	if cfg.BaseURL == "" {
+		return nil, fmt.Errorf("azure LLM provider requires LLM_BASE_URL")
+	}
+	if cfg.APIKey == "" {
+		return nil, fmt.Errorf("azure LLM provider requires LLM_API_KEY")
+	}
+	if cfg.Model == "" {
+		return nil, fmt.Errorf("azure LLM provider requires LLM_MODEL (deployment name)")
+	}
+	return openai.NewClientWithAPIKey(cfg.APIKey, cfg.Model,
+		openai.WithClientAzureEndpoint(cfg.BaseURL, cfg.Model, cfg.AzureAPIVersion, cfg.APIKey),
+	)
+}
+
+func newOllama(cfg Config) (Client, error) {
+	if cfg.Model == "" {
+		return nil, fmt.Errorf("ollama LLM provider requires LLM_MODEL")
+	}
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = DefaultOllamaBaseURL
+	}
+	if !strings.HasSuffix(strings.TrimRight(baseURL, "/"), "/v1") {
+		baseURL = strings.TrimRight(baseURL, "/") + "/v1"
+	}
+	// Ollama はAPIキーを検証しないが、クライアントの必須項目のためダミー値を渡す
+	apiKey := cfg.APIKey
+	if apiKey == "" {
+		apiKey = "ollama"
+	}
+	return openai.NewClientWithAPIKey(apiKey, cfg.Model, openai.WithBaseURL(baseURL))
+}
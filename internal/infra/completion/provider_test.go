@@ -0,0 +1,29 @@
+package completion
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jinford/dev-rag/internal/infra/openai"
+)
+
+func TestNew(t *testing.T) {
+	c, err := New(Config{Provider: "openai", APIKey: "sk-test"})
+	require.NoError(t, err)
+	assert.Equal(t, openai.DefaultModel, c.(*openai.Client).ModelName())
+
+	c, err = New(Config{Provider: "ollama", Model: "llama3.1"})
+	require.NoError(t, err)
+	assert.Equal(t, "llama3.1", c.(*openai.Client).ModelName())
+
+	_, err = New(Config{Provider: "azure", Model: "gpt-4o", APIKey: "key"})
+	assert.ErrorContains(t, err, "LLM_BASE_URL")
+
+	_, err = New(Config{Provider: "openai"})
+	assert.ErrorIs(t, err, openai.ErrAPIKeyNotSet)
+
+	_, err = New(Config{Provider: "unknown"})
+	assert.ErrorContains(t, err, "unknown LLM provider")
+}
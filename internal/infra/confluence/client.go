@@ -0,0 +1,241 @@
+// Package confluence は Confluence の REST API（/rest/api/content）を使用してページを操作するクライアントを提供する
+package confluence
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jinford/dev-rag/internal/core/wiki"
+)
+
+// DefaultTimeout はAPI呼び出しのデフォルトタイムアウト
+const DefaultTimeout = 60 * time.Second
+
+// APIError は Confluence API がエラーステータスを返した場合のエラー
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("confluence API error (status %d): %s", e.StatusCode, e.Message)
+}
+
+// IsNotFound はエラーがページが存在しないこと（HTTP 404）によるものかを判定する
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// Client は Confluence のページを操作するクライアント
+// user を指定した場合は Basic 認証（Cloud の APIトークン）、省略した場合は Bearer 認証（Server/Data Center の個人用アクセストークン）を使用する
+type Client struct {
+	httpClient *http.Client
+	baseURL    string
+	user       string
+	token      string
+}
+
+var _ wiki.ConfluenceClient = (*Client)(nil)
+
+// ClientOption は Client のオプション設定
+type ClientOption func(*Client)
+
+// WithHTTPClient は HTTP クライアントを差し替える
+func WithHTTPClient(client *http.Client) ClientOption {
+	return func(c *Client) {
+		c.httpClient = client
+	}
+}
+
+// NewClient は新しい Client を作成する
+// baseURL は Confluence のURL（Cloud の場合は https://<site>.atlassian.net/wiki）
+func NewClient(baseURL, user, token string, opts ...ClientOption) *Client {
+	c := &Client{
+		httpClient: &http.Client{Timeout: DefaultTimeout},
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		user:       user,
+		token:      token,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+type content struct {
+	ID        string          `json:"id,omitempty"`
+	Type      string          `json:"type"`
+	Title     string          `json:"title"`
+	Space     *space          `json:"space,omitempty"`
+	Ancestors []ancestor      `json:"ancestors,omitempty"`
+	Body      *contentBody    `json:"body,omitempty"`
+	Version   *contentVersion `json:"version,omitempty"`
+}
+
+type space struct {
+	Key string `json:"key"`
+}
+
+type ancestor struct {
+	ID string `json:"id"`
+}
+
+type contentBody struct {
+	Storage storage `json:"storage"`
+}
+
+type storage struct {
+	Value          string `json:"value"`
+	Representation string `json:"representation"`
+}
+
+type contentVersion struct {
+	Number int `json:"number"`
+}
+
+type contentList struct {
+	Results []content `json:"results"`
+}
+
+// GetPage はページを取得する
+func (c *Client) GetPage(ctx context.Context, id string) (*wiki.RemotePage, bool, error) {
+	var page content
+	err := c.do(ctx, http.MethodGet, "/rest/api/content/"+url.PathEscape(id)+"?expand=version", nil, &page)
+	if IsNotFound(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get page: %w", err)
+	}
+	return toRemotePage(page), true, nil
+}
+
+// FindPageByTitle はスペース内のタイトルが一致するページを取得する
+func (c *Client) FindPageByTitle(ctx context.Context, spaceKey, title string) (*wiki.RemotePage, bool, error) {
+	query := url.Values{}
+	query.Set("spaceKey", spaceKey)
+	query.Set("title", title)
+	query.Set("type", "page")
+	query.Set("expand", "version")
+
+	var list contentList
+	if err := c.do(ctx, http.MethodGet, "/rest/api/content?"+query.Encode(), nil, &list); err != nil {
+		return nil, false, fmt.Errorf("failed to find page: %w", err)
+	}
+	if len(list.Results) == 0 {
+		return nil, false, nil
+	}
+	return toRemotePage(list.Results[0]), true, nil
+}
+
+// CreatePage はページを作成する
+func (c *Client) CreatePage(ctx context.Context, spaceKey, parentID, title, body string) (*wiki.RemotePage, error) {
+	req := content{
+		Type:      "page",
+		Title:     title,
+		Space:     &space{Key: spaceKey},
+		Ancestors: ancestors(parentID),
+		Body:      &contentBody{Storage: storage{Value: body, Representation: "storage"}},
+	}
+
+	var page content
+	if err := c.do(ctx, http.MethodPost, "/rest/api/content", req, &page); err != nil {
+		return nil, fmt.Errorf("failed to create page: %w", err)
+	}
+	return toRemotePage(page), nil
+}
+
+// UpdatePage はページのタイトル・親・本文を更新する
+func (c *Client) UpdatePage(ctx context.Context, id, parentID, title, body string, version int) (*wiki.RemotePage, error) {
+	req := content{
+		ID:        id,
+		Type:      "page",
+		Title:     title,
+		Ancestors: ancestors(parentID),
+		Body:      &contentBody{Storage: storage{Value: body, Representation: "storage"}},
+		Version:   &contentVersion{Number: version},
+	}
+
+	var page content
+	if err := c.do(ctx, http.MethodPut, "/rest/api/content/"+url.PathEscape(id), req, &page); err != nil {
+		return nil, fmt.Errorf("failed to update page: %w", err)
+	}
+	return toRemotePage(page), nil
+}
+
+// DeletePage はページを削除する（既に存在しない場合は成功とみなす）
+func (c *Client) DeletePage(ctx context.Context, id string) error {
+	err := c.do(ctx, http.MethodDelete, "/rest/api/content/"+url.PathEscape(id), nil, nil)
+	if err != nil && !IsNotFound(err) {
+		return fmt.Errorf("failed to delete page: %w", err)
+	}
+	return nil
+}
+
+// do はAPIを呼び出し、応答をJSONとして out にデコードする（out が nil の場合は読み捨てる）
+func (c *Client) do(ctx context.Context, method, path string, in, out any) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.user != "" {
+		req.SetBasicAuth(c.user, c.token)
+	} else {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return &APIError{StatusCode: resp.StatusCode, Message: string(msg)}
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+func ancestors(parentID string) []ancestor {
+	if parentID == "" {
+		return nil
+	}
+	return []ancestor{{ID: parentID}}
+}
+
+func toRemotePage(page content) *wiki.RemotePage {
+	remote := &wiki.RemotePage{ID: page.ID, Title: page.Title}
+	if page.Version != nil {
+		remote.Version = page.Version.Number
+	}
+	return remote
+}
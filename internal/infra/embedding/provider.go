@@ -0,0 +1,172 @@
+package embedding
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/jinford/dev-rag/internal/core/ingestion"
+	"github.com/jinford/dev-rag/internal/infra/ollama"
+	"github.com/jinford/dev-rag/internal/infra/openai"
+	"github.com/jinford/dev-rag/internal/infra/vertex"
+)
+
+// Config は Embedder の生成に必要な設定
+type Config struct {
+	Provider        string
+	Model           string
+	Dimension       int
+	APIKey          string
+	BaseURL         string
+	AzureAPIVersion string
+	VertexProject   string
+	VertexLocation  string
+	VertexToken     string
+	MaxInputTokens  int // 0 の場合はプロバイダーの既定値
+}
+
+// Factory は設定から Embedder を生成する
+type Factory func(cfg Config) (ingestion.Embedder, error)
+
+// Provider はレジストリに登録されるプロバイダー定義
+type Provider struct {
+	Factory   Factory
+	Retryable func(error) bool // レート制限などリトライすべきエラーの判定
+}
+
+var (
+	mu        sync.RWMutex
+	providers = map[string]Provider{
+		"openai": {Factory: newOpenAI, Retryable: openai.IsRateLimitError},
+		"azure":  {Factory: newAzure, Retryable: openai.IsRateLimitError},
+		"vertex": {Factory: newVertex, Retryable: vertex.IsRateLimitError},
+		"ollama": {Factory: newOllama, Retryable: ollama.IsRateLimitError},
+	}
+)
+
+// Register はプロバイダーを登録する（同名の場合は上書き）
+func Register(name string, p Provider) {
+	mu.Lock()
+	defer mu.Unlock()
+	providers[name] = p
+}
+
+// Providers は登録済みのプロバイダー名を返す
+func Providers() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return providersLocked()
+}
+
+// Lookup はプロバイダー定義を返す
+func Lookup(name string) (Provider, error) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	p, ok := providers[name]
+	if !ok {
+		return Provider{}, fmt.Errorf("unknown embedding provider %q (available: %v)", name, providersLocked())
+	}
+	return p, nil
+}
+
+// New は設定されたプロバイダーの Embedder を生成する
+func New(cfg Config) (ingestion.Embedder, error) {
+	p, err := Lookup(cfg.Provider)
+	if err != nil {
+		return nil, err
+	}
+	return p.Factory(cfg)
+}
+
+// ResolveDimension は設定値とDBのベクトルカラム次元から使用する次元を決定する
+// 設定値が 0 の場合はDBの次元を採用し、両方指定されていて一致しない場合はエラーとする
+func ResolveDimension(configured, stored int) (int, error) {
+	switch {
+	case configured <= 0 && stored <= 0:
+		return 0, fmt.Errorf("embedding dimension is not configured and could not be determined from the database")
+	case configured <= 0:
+		return stored, nil
+	case stored > 0 && configured != stored:
+		return 0, fmt.Errorf("embedding dimension mismatch: EMBEDDING_DIMENSION=%d but the database column is VECTOR(%d)", configured, stored)
+	default:
+		return configured, nil
+	}
+}
+
+func providersLocked() []string {
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func newOpenAI(cfg Config) (ingestion.Embedder, error) {
+	if cfg.APIKey == "" {
+		return nil, openai.ErrAPIKeyNotSet
+	}
+	opts := []openai.EmbedderOption{
+		openai.WithEmbeddingDimension(cfg.Dimension),
+	}
+	if cfg.Model != "" {
+		opts = append(opts, openai.WithEmbeddingModel(cfg.Model))
+	}
+	if cfg.MaxInputTokens > 0 {
+		opts = append(opts, openai.WithMaxInputTokens(cfg.MaxInputTokens))
+	}
+	return openai.NewEmbedder(cfg.APIKey, opts...), nil
+}
+
+func newAzure(cfg Config) (ingestion.Embedder, error) {
+	if cfg.BaseURL == "" {
+		return nil, fmt.Errorf("azure embedding provider requires EMBEDDING_BASE_URL")
+	}
+	if cfg.APIKey == "" {
+		return nil, fmt.Errorf("azure embedding provider requires EMBEDDING_API_KEY")
+	}
+	if cfg.Model == "" {
+		return nil, fmt.Errorf("azure embedding provider requires EMBEDDING_MODEL (deployment name)")
+	}
+	opts := []openai.EmbedderOption{
+		openai.WithEmbeddingDimension(cfg.Dimension),
+		openai.WithAzureEndpoint(cfg.BaseURL, cfg.Model, cfg.AzureAPIVersion, cfg.APIKey),
+	}
+	if cfg.MaxInputTokens > 0 {
+		opts = append(opts, openai.WithMaxInputTokens(cfg.MaxInputTokens))
+	}
+	return openai.NewEmbedder(cfg.APIKey, opts...), nil
+}
+
+func newVertex(cfg Config) (ingestion.Embedder, error) {
+	opts := []vertex.EmbedderOption{
+		vertex.WithEmbeddingDimension(cfg.Dimension),
+	}
+	if cfg.Model != "" {
+		opts = append(opts, vertex.WithEmbeddingModel(cfg.Model))
+	}
+	if cfg.VertexLocation != "" {
+		opts = append(opts, vertex.WithLocation(cfg.VertexLocation))
+	}
+	if cfg.MaxInputTokens > 0 {
+		opts = append(opts, vertex.WithMaxInputTokens(cfg.MaxInputTokens))
+	}
+	return vertex.NewEmbedder(cfg.VertexProject, cfg.VertexToken, opts...)
+}
+
+func newOllama(cfg Config) (ingestion.Embedder, error) {
+	opts := []ollama.EmbedderOption{
+		ollama.WithEmbeddingDimension(cfg.Dimension),
+	}
+	if cfg.Model != "" {
+		opts = append(opts, ollama.WithEmbeddingModel(cfg.Model))
+	}
+	if cfg.BaseURL != "" {
+		opts = append(opts, ollama.WithBaseURL(cfg.BaseURL))
+	}
+	if cfg.MaxInputTokens > 0 {
+		opts = append(opts, ollama.WithMaxInputTokens(cfg.MaxInputTokens))
+	}
+	return ollama.NewEmbedder(opts...), nil
+}
@@ -0,0 +1,53 @@
+package embedding
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveDimension(t *testing.T) {
+	tests := []struct {
+		name       string
+		configured int
+		stored     int
+		want       int
+		wantErr    bool
+	}{
+		{name: "一致", configured: 1536, stored: 1536, want: 1536},
+		{name: "設定なしはDBの次元を採用", configured: 0, stored: 768, want: 768},
+		{name: "DBの次元が不明なら設定値を採用", configured: 1024, stored: 0, want: 1024},
+		{name: "不一致はエラー", configured: 768, stored: 1536, wantErr: true},
+		{name: "どちらも不明はエラー", configured: 0, stored: 0, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveDimension(tt.configured, tt.stored)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	e, err := New(Config{Provider: "ollama", Model: "mxbai-embed-large", Dimension: 1024})
+	require.NoError(t, err)
+	assert.Equal(t, "ollama/mxbai-embed-large", e.ModelName())
+	assert.Equal(t, 1024, e.Dimension())
+
+	e, err = New(Config{Provider: "azure", Model: "embed-prod", Dimension: 1536, APIKey: "key", BaseURL: "https://example.openai.azure.com"})
+	require.NoError(t, err)
+	assert.Equal(t, "azure/embed-prod", e.ModelName())
+
+	_, err = New(Config{Provider: "vertex", Dimension: 768})
+	assert.Error(t, err)
+
+	_, err = New(Config{Provider: "unknown"})
+	assert.ErrorContains(t, err, "unknown embedding provider")
+}
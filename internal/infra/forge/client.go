@@ -0,0 +1,135 @@
+// Package forge は GitHub・GitLab の REST API から Issue・Pull Request（Merge Request）の議論を取得するソースプロバイダーを提供する
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultTimeout はAPI呼び出しのデフォルトタイムアウト
+	DefaultTimeout = 60 * time.Second
+	// perPage は一覧APIの1ページあたりの件数
+	perPage = 100
+	// maxDetailItems は1つの議論から取得するコメント・変更ファイルの上限
+	maxDetailItems = 300
+)
+
+// APIError は GitHub・GitLab の API がエラーステータスを返した場合のエラー
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("forge API error (status %d): %s", e.StatusCode, e.Message)
+}
+
+// apiClient は認証ヘッダーを付与して JSON の API を呼び出すクライアント
+type apiClient struct {
+	httpClient *http.Client
+	baseURL    string
+	headers    map[string]string
+}
+
+// getJSON は GET リクエストを送り、応答を JSON として out にデコードする
+func (c *apiClient) getJSON(ctx context.Context, path string, query url.Values, out any) error {
+	endpoint := c.baseURL + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	for k, v := range c.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return &APIError{StatusCode: resp.StatusCode, Message: string(msg)}
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// listPages はページ番号でページングする一覧APIを、最大 maxItems 件まで順に取得する
+func listPages[T any](ctx context.Context, c *apiClient, path string, query url.Values, maxItems int) ([]T, error) {
+	q := url.Values{}
+	for k, v := range query {
+		q[k] = v
+	}
+	q.Set("per_page", strconv.Itoa(perPage))
+
+	var items []T
+	for page := 1; len(items) < maxItems; page++ {
+		q.Set("page", strconv.Itoa(page))
+		var batch []T
+		if err := c.getJSON(ctx, path, q, &batch); err != nil {
+			return nil, err
+		}
+		items = append(items, batch...)
+		if len(batch) < perPage {
+			break
+		}
+	}
+	if len(items) > maxItems {
+		items = items[:maxItems]
+	}
+	return items, nil
+}
+
+// repository はリポジトリURLから取り出したホストとリポジトリのパス
+type repository struct {
+	Scheme string // https または http
+	Host   string
+	Path   string // owner/repo（GitLab はサブグループを含む）
+}
+
+// parseRepositoryURL はリポジトリURL（https://host/owner/repo、git@host:owner/repo.git、host/owner/repo）を解析する
+func parseRepositoryURL(raw string) (*repository, error) {
+	s := strings.TrimSpace(raw)
+	scheme := "https"
+	switch {
+	case strings.HasPrefix(s, "git@"):
+		// git@host:owner/repo.git
+		host, path, ok := strings.Cut(strings.TrimPrefix(s, "git@"), ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid repository URL: %s", raw)
+		}
+		s = host + "/" + path
+	case strings.Contains(s, "://"):
+		u, err := url.Parse(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid repository URL: %w", err)
+		}
+		if u.Scheme == "http" {
+			scheme = "http"
+		}
+		s = u.Host + u.Path
+	}
+
+	s = strings.TrimSuffix(strings.TrimSuffix(s, "/"), ".git")
+	host, path, ok := strings.Cut(s, "/")
+	if !ok || host == "" || !strings.Contains(path, "/") {
+		return nil, fmt.Errorf("invalid repository URL: %s", raw)
+	}
+	return &repository{Scheme: scheme, Host: host, Path: path}, nil
+}
@@ -0,0 +1,147 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jinford/dev-rag/internal/core/discussion"
+)
+
+type githubUser struct {
+	Login string `json:"login"`
+}
+
+type githubIssue struct {
+	Number    int        `json:"number"`
+	Title     string     `json:"title"`
+	State     string     `json:"state"`
+	HTMLURL   string     `json:"html_url"`
+	Body      string     `json:"body"`
+	User      githubUser `json:"user"`
+	Comments  int        `json:"comments"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	Labels    []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+	PullRequest *struct {
+		MergedAt *time.Time `json:"merged_at"`
+	} `json:"pull_request"`
+}
+
+type githubComment struct {
+	Body      string     `json:"body"`
+	Path      string     `json:"path"` // レビューコメントのみ
+	User      githubUser `json:"user"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type githubFile struct {
+	Filename string `json:"filename"`
+}
+
+// githubClient は GitHub の REST API から Issue・Pull Request を取得する
+// Issues API は Pull Request を含むため、一覧は Issues API から最終更新日時の新しい順に取得する
+type githubClient struct {
+	api *apiClient
+}
+
+func newGitHubClient(api *apiClient) *githubClient {
+	return &githubClient{api: api}
+}
+
+// listThreads は Issue・Pull Request を最大 maxThreads 件取得し、コメント・レビューコメント・変更ファイルを付与する
+func (c *githubClient) listThreads(ctx context.Context, repoPath string, maxThreads int) ([]*discussion.Thread, error) {
+	base := "/repos/" + repoPath
+	issues, err := listPages[githubIssue](ctx, c.api, base+"/issues", url.Values{
+		"state":     {"all"},
+		"sort":      {"updated"},
+		"direction": {"desc"},
+	}, maxThreads)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list issues: %w", err)
+	}
+
+	threads := make([]*discussion.Thread, 0, len(issues))
+	for _, issue := range issues {
+		thread := &discussion.Thread{
+			Kind:      discussion.KindIssue,
+			Number:    issue.Number,
+			Title:     issue.Title,
+			State:     issue.State,
+			Author:    issue.User.Login,
+			URL:       issue.HTMLURL,
+			Body:      issue.Body,
+			CreatedAt: issue.CreatedAt,
+			UpdatedAt: issue.UpdatedAt,
+		}
+		for _, label := range issue.Labels {
+			thread.Labels = append(thread.Labels, label.Name)
+		}
+
+		if issue.Comments > 0 {
+			comments, err := listPages[githubComment](ctx, c.api, fmt.Sprintf("%s/issues/%d/comments", base, issue.Number), nil, maxDetailItems)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list comments of #%d: %w", issue.Number, err)
+			}
+			thread.Comments = append(thread.Comments, toComments(comments)...)
+		}
+
+		if issue.PullRequest != nil {
+			thread.Kind = discussion.KindPullRequest
+			if issue.PullRequest.MergedAt != nil {
+				thread.State = "merged"
+			}
+
+			files, err := listPages[githubFile](ctx, c.api, fmt.Sprintf("%s/pulls/%d/files", base, issue.Number), nil, maxDetailItems)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list files of #%d: %w", issue.Number, err)
+			}
+			for _, f := range files {
+				thread.Files = append(thread.Files, f.Filename)
+			}
+
+			reviews, err := listPages[githubComment](ctx, c.api, fmt.Sprintf("%s/pulls/%d/comments", base, issue.Number), nil, maxDetailItems)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list review comments of #%d: %w", issue.Number, err)
+			}
+			thread.Comments = append(thread.Comments, toComments(reviews)...)
+		}
+
+		sortComments(thread.Comments)
+		threads = append(threads, thread)
+	}
+	return threads, nil
+}
+
+func toComments(comments []githubComment) []*discussion.Comment {
+	result := make([]*discussion.Comment, 0, len(comments))
+	for _, c := range comments {
+		result = append(result, &discussion.Comment{
+			Author:    c.User.Login,
+			Body:      c.Body,
+			Path:      c.Path,
+			CreatedAt: c.CreatedAt,
+		})
+	}
+	return result
+}
+
+// sortComments はコメントとレビューコメントを投稿日時順に並べる
+func sortComments(comments []*discussion.Comment) {
+	slices.SortStableFunc(comments, func(a, b *discussion.Comment) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+}
+
+// githubAPIURL はリポジトリのホストに対応する GitHub の API のURLを返す（GitHub Enterprise Server は /api/v3）
+func githubAPIURL(repo *repository) string {
+	if strings.EqualFold(repo.Host, "github.com") {
+		return "https://api.github.com"
+	}
+	return repo.Scheme + "://" + repo.Host + "/api/v3"
+}
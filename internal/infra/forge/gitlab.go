@@ -0,0 +1,167 @@
+package forge
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/jinford/dev-rag/internal/core/discussion"
+)
+
+type gitlabUser struct {
+	Username string `json:"username"`
+}
+
+type gitlabIssue struct {
+	IID            int        `json:"iid"`
+	Title          string     `json:"title"`
+	State          string     `json:"state"` // opened / closed / merged / locked
+	WebURL         string     `json:"web_url"`
+	Description    string     `json:"description"`
+	Author         gitlabUser `json:"author"`
+	Labels         []string   `json:"labels"`
+	UserNotesCount int        `json:"user_notes_count"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+type gitlabNote struct {
+	Body      string     `json:"body"`
+	Author    gitlabUser `json:"author"`
+	System    bool       `json:"system"` // ラベル変更などの自動生成ノート
+	CreatedAt time.Time  `json:"created_at"`
+	Position  *struct {
+		NewPath string `json:"new_path"`
+	} `json:"position"`
+}
+
+type gitlabDiff struct {
+	NewPath string `json:"new_path"`
+}
+
+// gitlabClient は GitLab の REST API（v4）から Issue・Merge Request を取得する
+type gitlabClient struct {
+	api *apiClient
+}
+
+func newGitLabClient(api *apiClient) *gitlabClient {
+	return &gitlabClient{api: api}
+}
+
+// listThreads は Issue・Merge Request をそれぞれ最終更新日時の新しい順に取得し、合わせて新しい順に最大 maxThreads 件を返す
+func (c *gitlabClient) listThreads(ctx context.Context, repoPath string, maxThreads int) ([]*discussion.Thread, error) {
+	base := "/projects/" + url.PathEscape(repoPath)
+	query := url.Values{
+		"order_by": {"updated_at"},
+		"sort":     {"desc"},
+	}
+
+	issues, err := listPages[gitlabIssue](ctx, c.api, base+"/issues", withValue(query, "scope", "all"), maxThreads)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list issues: %w", err)
+	}
+	mergeRequests, err := listPages[gitlabIssue](ctx, c.api, base+"/merge_requests", withValue(query, "state", "all"), maxThreads)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list merge requests: %w", err)
+	}
+
+	type item struct {
+		kind  discussion.Kind
+		issue gitlabIssue
+	}
+	items := make([]item, 0, len(issues)+len(mergeRequests))
+	for _, issue := range issues {
+		items = append(items, item{kind: discussion.KindIssue, issue: issue})
+	}
+	for _, mr := range mergeRequests {
+		items = append(items, item{kind: discussion.KindPullRequest, issue: mr})
+	}
+	slices.SortStableFunc(items, func(a, b item) int {
+		return cmp.Compare(b.issue.UpdatedAt.Unix(), a.issue.UpdatedAt.Unix())
+	})
+	if len(items) > maxThreads {
+		items = items[:maxThreads]
+	}
+
+	threads := make([]*discussion.Thread, 0, len(items))
+	for _, it := range items {
+		issue := it.issue
+		thread := &discussion.Thread{
+			Kind:      it.kind,
+			Number:    issue.IID,
+			Title:     issue.Title,
+			State:     normalizeGitLabState(issue.State),
+			Author:    issue.Author.Username,
+			URL:       issue.WebURL,
+			Body:      issue.Description,
+			Labels:    issue.Labels,
+			CreatedAt: issue.CreatedAt,
+			UpdatedAt: issue.UpdatedAt,
+		}
+
+		resource := "issues"
+		if it.kind == discussion.KindPullRequest {
+			resource = "merge_requests"
+			diffs, err := listPages[gitlabDiff](ctx, c.api, fmt.Sprintf("%s/merge_requests/%d/diffs", base, issue.IID), nil, maxDetailItems)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list diffs of !%d: %w", issue.IID, err)
+			}
+			for _, d := range diffs {
+				thread.Files = append(thread.Files, d.NewPath)
+			}
+		}
+
+		if issue.UserNotesCount > 0 {
+			notes, err := listPages[gitlabNote](ctx, c.api, fmt.Sprintf("%s/%s/%d/notes", base, resource, issue.IID), url.Values{
+				"order_by": {"created_at"},
+				"sort":     {"asc"},
+			}, maxDetailItems)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list notes of %s %d: %w", resource, issue.IID, err)
+			}
+			for _, note := range notes {
+				if note.System {
+					continue
+				}
+				comment := &discussion.Comment{
+					Author:    note.Author.Username,
+					Body:      note.Body,
+					CreatedAt: note.CreatedAt,
+				}
+				if note.Position != nil {
+					comment.Path = note.Position.NewPath
+				}
+				thread.Comments = append(thread.Comments, comment)
+			}
+		}
+		threads = append(threads, thread)
+	}
+	return threads, nil
+}
+
+// normalizeGitLabState は GitLab の状態を GitHub と同じ表記（open / closed / merged）に揃える
+func normalizeGitLabState(state string) string {
+	switch state {
+	case "opened":
+		return "open"
+	default:
+		return state
+	}
+}
+
+func withValue(query url.Values, key, value string) url.Values {
+	q := url.Values{}
+	for k, v := range query {
+		q[k] = v
+	}
+	q.Set(key, value)
+	return q
+}
+
+// gitlabAPIURL はリポジトリのホストに対応する GitLab の API のURLを返す
+func gitlabAPIURL(repo *repository) string {
+	return repo.Scheme + "://" + repo.Host + "/api/v4"
+}
@@ -0,0 +1,202 @@
+package forge
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/jinford/dev-rag/internal/core/discussion"
+	"github.com/jinford/dev-rag/internal/core/ingestion"
+)
+
+// DefaultMaxThreads は1回のインデックス化で取得する Issue・Pull Request の既定の上限（最終更新日時の新しい順）
+const DefaultMaxThreads = 500
+
+// Kind は議論の取得元のサービス
+type Kind string
+
+const (
+	KindGitHub Kind = "github"
+	KindGitLab Kind = "gitlab"
+)
+
+// Config は GitHub・GitLab の API の接続設定
+type Config struct {
+	GitHubToken  string // GitHub のアクセストークン（公開リポジトリは省略可）
+	GitHubAPIURL string // GitHub の API のURL（省略時はリポジトリのホストから決定）
+	GitLabToken  string // GitLab のアクセストークン（公開プロジェクトは省略可）
+}
+
+// threadLister は Issue・Pull Request の議論を取得するクライアント
+type threadLister interface {
+	listThreads(ctx context.Context, repoPath string, maxThreads int) ([]*discussion.Thread, error)
+}
+
+// Provider は GitHub・GitLab の Issue・Pull Request（Merge Request）の議論用の ingestion.SourceProvider 実装
+// 議論ごとに1つの Markdown 文書（issues/<番号>.md、pulls/<番号>.md）としてインデックス化する
+//
+// IndexParams.Options:
+//   - "forge": 取得元のサービス（"github" | "gitlab"、省略時はリポジトリのホストから判定）
+//   - "max_threads": 取得する議論の上限（省略時は DefaultMaxThreads）
+type Provider struct {
+	config     Config
+	httpClient *http.Client
+}
+
+// ProviderOption は Provider のオプション設定
+type ProviderOption func(*Provider)
+
+// WithHTTPClient は HTTP クライアントを差し替える
+func WithHTTPClient(client *http.Client) ProviderOption {
+	return func(p *Provider) {
+		p.httpClient = client
+	}
+}
+
+// NewProvider は新しい Provider を作成する
+func NewProvider(cfg Config, opts ...ProviderOption) *Provider {
+	p := &Provider{
+		config:     cfg,
+		httpClient: &http.Client{Timeout: DefaultTimeout},
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// GetSourceType は ingestion.SourceTypeDiscussion を返す
+func (p *Provider) GetSourceType() ingestion.SourceType {
+	return ingestion.SourceTypeDiscussion
+}
+
+// ExtractSourceName はリポジトリURLからソース名を抽出する
+// 同じリポジトリの Git ソースと区別するため末尾に /discussions を付ける
+// 例: https://github.com/user/repo.git -> github.com/user/repo/discussions
+func (p *Provider) ExtractSourceName(identifier string) string {
+	repo, err := parseRepositoryURL(identifier)
+	if err != nil {
+		return strings.TrimSuffix(identifier, ".git") + "/discussions"
+	}
+	return repo.Host + "/" + repo.Path + "/discussions"
+}
+
+// FetchDocuments は Issue・Pull Request を取得し、議論ごとの Markdown 文書に変換する
+// バージョン識別子は全文書のパスと内容のハッシュから算出するため、議論に変化がなければ同じ値になる
+// 取得件数は max_threads で制限しているため、変換した文書はメモリ上に保持して返す
+func (p *Provider) FetchDocuments(ctx context.Context, params ingestion.IndexParams) (ingestion.DocumentIterator, string, error) {
+	repo, err := parseRepositoryURL(params.Identifier)
+	if err != nil {
+		return nil, "", err
+	}
+	kind, err := p.resolveKind(params, repo)
+	if err != nil {
+		return nil, "", err
+	}
+	maxThreads := DefaultMaxThreads
+	if n, ok := params.Options["max_threads"].(int); ok && n > 0 {
+		maxThreads = n
+	}
+
+	threads, err := p.client(kind, repo).listThreads(ctx, repo.Path, maxThreads)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to fetch discussions from %s: %w", kind, err)
+	}
+
+	documents := make([]*ingestion.SourceDocument, 0, len(threads))
+	for _, thread := range threads {
+		content := discussion.Render(thread)
+		documents = append(documents, &ingestion.SourceDocument{
+			Path:        discussion.DocumentPath(thread),
+			Content:     content,
+			Size:        int64(len(content)),
+			ContentHash: fmt.Sprintf("%x", sha256.Sum256([]byte(content))),
+			Author:      thread.Author,
+			UpdatedAt:   thread.UpdatedAt,
+		})
+	}
+	return ingestion.DocumentsFromSlice(documents), versionIdentifier(documents), nil
+}
+
+// CreateMetadata は議論のソース用のメタデータを作成する
+func (p *Provider) CreateMetadata(params ingestion.IndexParams) ingestion.SourceMetadata {
+	metadata := ingestion.SourceMetadata{
+		"url": params.Identifier,
+	}
+	if repo, err := parseRepositoryURL(params.Identifier); err == nil {
+		if kind, err := p.resolveKind(params, repo); err == nil {
+			metadata["forge"] = string(kind)
+		}
+	}
+	return metadata
+}
+
+// ShouldIgnore は常に false を返す（取得した議論はすべて対象とする）
+func (p *Provider) ShouldIgnore(doc *ingestion.SourceDocument) bool {
+	return false
+}
+
+// resolveKind は取得元のサービスを判定する
+// オプションで指定がなければ、github.com または GitHub の API のURLと同じホストを GitHub、ホスト名に gitlab を含む場合を GitLab とする
+func (p *Provider) resolveKind(params ingestion.IndexParams, repo *repository) (Kind, error) {
+	if forge, ok := params.Options["forge"].(string); ok && forge != "" {
+		switch Kind(forge) {
+		case KindGitHub, KindGitLab:
+			return Kind(forge), nil
+		default:
+			return "", fmt.Errorf("unsupported forge: %s", forge)
+		}
+	}
+
+	host := strings.ToLower(repo.Host)
+	switch {
+	case host == "github.com" || (p.config.GitHubAPIURL != "" && strings.Contains(strings.ToLower(p.config.GitHubAPIURL), "://"+host)):
+		return KindGitHub, nil
+	case strings.Contains(host, "gitlab"):
+		return KindGitLab, nil
+	default:
+		return "", fmt.Errorf("cannot determine forge from host %s (specify github or gitlab)", repo.Host)
+	}
+}
+
+// client は取得元のサービスに対応するクライアントを作成する
+func (p *Provider) client(kind Kind, repo *repository) threadLister {
+	if kind == KindGitLab {
+		headers := map[string]string{}
+		if p.config.GitLabToken != "" {
+			headers["PRIVATE-TOKEN"] = p.config.GitLabToken
+		}
+		return newGitLabClient(&apiClient{httpClient: p.httpClient, baseURL: gitlabAPIURL(repo), headers: headers})
+	}
+
+	baseURL := strings.TrimRight(p.config.GitHubAPIURL, "/")
+	if baseURL == "" {
+		baseURL = githubAPIURL(repo)
+	}
+	headers := map[string]string{
+		"Accept":               "application/vnd.github+json",
+		"X-GitHub-Api-Version": "2022-11-28",
+	}
+	if p.config.GitHubToken != "" {
+		headers["Authorization"] = "Bearer " + p.config.GitHubToken
+	}
+	return newGitHubClient(&apiClient{httpClient: p.httpClient, baseURL: baseURL, headers: headers})
+}
+
+// versionIdentifier は文書のパスと内容のハッシュの組から議論全体のバージョン識別子を算出する
+func versionIdentifier(documents []*ingestion.SourceDocument) string {
+	sorted := make([]*ingestion.SourceDocument, len(documents))
+	copy(sorted, documents)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Path < sorted[j].Path
+	})
+
+	hash := sha256.New()
+	for _, doc := range sorted {
+		fmt.Fprintf(hash, "%s\x00%s\n", doc.Path, doc.ContentHash)
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil))
+}
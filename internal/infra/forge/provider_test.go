@@ -0,0 +1,115 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jinford/dev-rag/internal/core/discussion"
+	"github.com/jinford/dev-rag/internal/core/ingestion"
+)
+
+func TestParseRepositoryURL(t *testing.T) {
+	for _, raw := range []string{
+		"https://github.com/acme/app.git",
+		"git@github.com:acme/app.git",
+		"github.com/acme/app",
+	} {
+		repo, err := parseRepositoryURL(raw)
+		require.NoError(t, err, raw)
+		assert.Equal(t, "github.com", repo.Host)
+		assert.Equal(t, "acme/app", repo.Path)
+	}
+
+	repo, err := parseRepositoryURL("https://gitlab.example.com/platform/infra/terraform")
+	require.NoError(t, err)
+	assert.Equal(t, "platform/infra/terraform", repo.Path)
+
+	_, err = parseRepositoryURL("https://github.com/acme")
+	assert.Error(t, err)
+}
+
+func TestProvider_FetchDocuments_GitHub(t *testing.T) {
+	responses := map[string]any{
+		"/repos/acme/app/issues": []map[string]any{
+			{
+				"number": 12, "title": "Retry embeddings", "state": "closed", "html_url": "https://github.com/acme/app/pull/12",
+				"body": "Wraps internal/core/ingestion/embedder.go", "user": map[string]any{"login": "alice"},
+				"comments": 1, "created_at": "2024-05-01T00:00:00Z", "updated_at": "2024-05-03T00:00:00Z",
+				"pull_request": map[string]any{"merged_at": "2024-05-03T00:00:00Z"},
+			},
+			{
+				"number": 7, "title": "Search is slow", "state": "open", "html_url": "https://github.com/acme/app/issues/7",
+				"body": "", "user": map[string]any{"login": "bob"}, "comments": 0,
+				"created_at": "2024-04-01T00:00:00Z", "updated_at": "2024-04-02T00:00:00Z",
+			},
+		},
+		"/repos/acme/app/issues/12/comments": []map[string]any{
+			{"body": "LGTM", "user": map[string]any{"login": "bob"}, "created_at": "2024-05-02T00:00:00Z"},
+		},
+		"/repos/acme/app/pulls/12/files": []map[string]any{
+			{"filename": "internal/core/ingestion/embedding_retry.go"},
+		},
+		"/repos/acme/app/pulls/12/comments": []map[string]any{
+			{"body": "Add jitter?", "path": "internal/core/ingestion/embedding_retry.go", "user": map[string]any{"login": "carol"}, "created_at": "2024-05-01T12:00:00Z"},
+		},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+	defer server.Close()
+
+	p := NewProvider(Config{GitHubToken: "secret", GitHubAPIURL: server.URL}, WithHTTPClient(server.Client()))
+	params := ingestion.IndexParams{
+		Identifier: "https://github.com/acme/app",
+		Options:    map[string]any{"forge": "github"},
+	}
+	stream, version, err := p.FetchDocuments(context.Background(), params)
+	require.NoError(t, err)
+	docs, err := ingestion.CollectDocuments(stream)
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	assert.NotEmpty(t, version)
+	assert.Equal(t, "github.com/acme/app/discussions", p.ExtractSourceName(params.Identifier))
+
+	assert.Equal(t, "pulls/12.md", docs[0].Path)
+	record, ok := discussion.Parse(docs[0].Path, docs[0].Content)
+	require.True(t, ok)
+	assert.Equal(t, discussion.KindPullRequest, record.Kind)
+	assert.Equal(t, "merged", record.State)
+	assert.Equal(t, []string{
+		"internal/core/ingestion/embedding_retry.go",
+		"internal/core/ingestion/embedder.go",
+	}, record.Paths)
+	// レビューコメントと通常のコメントは投稿日時順に並ぶ
+	assert.Less(t, strings.Index(docs[0].Content, "Add jitter?"), strings.Index(docs[0].Content, "LGTM"))
+
+	assert.Equal(t, "issues/7.md", docs[1].Path)
+}
+
+func TestProvider_ResolveKind(t *testing.T) {
+	p := NewProvider(Config{})
+	repo, err := parseRepositoryURL("https://gitlab.example.com/platform/app")
+	require.NoError(t, err)
+
+	kind, err := p.resolveKind(ingestion.IndexParams{}, repo)
+	require.NoError(t, err)
+	assert.Equal(t, KindGitLab, kind)
+
+	repo, err = parseRepositoryURL("https://code.example.com/platform/app")
+	require.NoError(t, err)
+	_, err = p.resolveKind(ingestion.IndexParams{}, repo)
+	assert.Error(t, err)
+}
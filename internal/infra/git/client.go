@@ -21,14 +21,41 @@ import (
 type Client struct {
 	sshKeyPath  string
 	sshPassword string
+	httpToken   string
+	authorName  string
+	authorEmail string
+}
+
+// ClientOption は Client のオプション設定
+type ClientOption func(*Client)
+
+// WithHTTPToken は HTTPS のリポジトリへのプッシュに使用するアクセストークンを設定する
+func WithHTTPToken(token string) ClientOption {
+	return func(c *Client) {
+		c.httpToken = token
+	}
+}
+
+// WithCommitAuthor はコミットの作成者を設定する
+func WithCommitAuthor(name, email string) ClientOption {
+	return func(c *Client) {
+		c.authorName = name
+		c.authorEmail = email
+	}
 }
 
 // NewClient は新しい Client を作成する
-func NewClient(sshKeyPath, sshPassword string) *Client {
-	return &Client{
+func NewClient(sshKeyPath, sshPassword string, opts ...ClientOption) *Client {
+	c := &Client{
 		sshKeyPath:  sshKeyPath,
 		sshPassword: sshPassword,
+		authorName:  "dev-rag",
+		authorEmail: "dev-rag@localhost",
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+	return c
 }
 
 // CommitInfo はコミット情報を表す
@@ -451,6 +478,14 @@ func (c *Client) resolveRef(repo *git.Repository, ref string) (plumbing.Hash, er
 
 	tagRef, err := repo.Reference(plumbing.NewTagReferenceName(ref), true)
 	if err == nil {
+		// 注釈付きタグはタグオブジェクトのハッシュを指すため、タグが指すコミットを返す
+		if tag, err := repo.TagObject(tagRef.Hash()); err == nil {
+			commit, err := tag.Commit()
+			if err != nil {
+				return plumbing.ZeroHash, fmt.Errorf("failed to resolve tag %s: %w", ref, err)
+			}
+			return commit.Hash, nil
+		}
 		return tagRef.Hash(), nil
 	}
 
@@ -469,5 +504,16 @@ func (c *Client) resolveRef(repo *git.Repository, ref string) (plumbing.Hash, er
 		}
 	}
 
+	// 短縮したコミットハッシュ（--since-commit など）
+	if hash, err := repo.ResolveRevision(plumbing.Revision(ref)); err == nil {
+		return *hash, nil
+	}
+
 	return plumbing.ZeroHash, fmt.Errorf("failed to resolve ref: %s", ref)
 }
+
+// CheckSSHAuth は設定した SSH 鍵を読み込めるかを検証する（鍵が設定されていない・存在しない場合は false を返す）
+func (c *Client) CheckSSHAuth() (bool, error) {
+	auth, err := c.getSSHAuth()
+	return auth != nil, err
+}
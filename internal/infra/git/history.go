@@ -0,0 +1,195 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/utils/merkletrie"
+
+	"github.com/jinford/dev-rag/internal/core/release"
+)
+
+// ChangedFile はコミット間で変更されたファイルを表す
+type ChangedFile struct {
+	Path    string // 変更後のパス（削除の場合は削除前のパス）
+	OldPath string // 名前を変更した場合の変更前のパス
+	Action  string // added / modified / deleted / renamed
+}
+
+// ListCommitsBetween は to から到達でき from から到達できないコミットを新しい順に取得する
+func (c *Client) ListCommitsBetween(ctx context.Context, repoPath, from, to string) ([]*object.Commit, error) {
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	fromHash, err := c.resolveRef(repo, from)
+	if err != nil {
+		return nil, err
+	}
+	toHash, err := c.resolveRef(repo, to)
+	if err != nil {
+		return nil, err
+	}
+
+	// from の祖先をすべて集め、to からの走査で除く（マージで合流した from 以前のコミットも除くため）
+	excluded := make(map[plumbing.Hash]bool)
+	fromIter, err := repo.Log(&git.LogOptions{From: fromHash})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit log: %w", err)
+	}
+	err = fromIter.ForEach(func(commit *object.Commit) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		excluded[commit.Hash] = true
+		return nil
+	})
+	fromIter.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate commits: %w", err)
+	}
+
+	toCommit, err := repo.CommitObject(toHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit object: %w", err)
+	}
+	var commits []*object.Commit
+	err = object.NewCommitPreorderIter(toCommit, excluded, nil).ForEach(func(commit *object.Commit) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		commits = append(commits, commit)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate commits: %w", err)
+	}
+
+	sort.SliceStable(commits, func(i, j int) bool {
+		return commits[i].Committer.When.After(commits[j].Committer.When)
+	})
+	return commits, nil
+}
+
+// DiffFiles は from と to のコミットのツリーの差分をパス順に取得する
+func (c *Client) DiffFiles(ctx context.Context, repoPath, from, to string) ([]*ChangedFile, error) {
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	fromTree, err := c.commitTree(repo, from)
+	if err != nil {
+		return nil, err
+	}
+	toTree, err := c.commitTree(repo, to)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := object.DiffTreeWithOptions(ctx, fromTree, toTree, &object.DiffTreeOptions{DetectRenames: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff trees: %w", err)
+	}
+
+	files := make([]*ChangedFile, 0, len(changes))
+	for _, change := range changes {
+		action, err := change.Action()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get change action: %w", err)
+		}
+		switch {
+		case action == merkletrie.Insert:
+			files = append(files, &ChangedFile{Path: change.To.Name, Action: "added"})
+		case action == merkletrie.Delete:
+			files = append(files, &ChangedFile{Path: change.From.Name, Action: "deleted"})
+		case change.From.Name != change.To.Name:
+			files = append(files, &ChangedFile{Path: change.To.Name, OldPath: change.From.Name, Action: "renamed"})
+		default:
+			files = append(files, &ChangedFile{Path: change.To.Name, Action: "modified"})
+		}
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files, nil
+}
+
+// commitTree は ref のコミットのツリーを取得する
+func (c *Client) commitTree(repo *git.Repository, ref string) (*object.Tree, error) {
+	hash, err := c.resolveRef(repo, ref)
+	if err != nil {
+		return nil, err
+	}
+	commit, err := repo.CommitObject(hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit object: %w", err)
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tree for commit %s: %w", commit.Hash, err)
+	}
+	return tree, nil
+}
+
+// History はリポジトリをクローン/pull し、from から to までのコミットと変更されたファイルを取得する（release.HistoryReader の実装）
+// タグも参照できるよう、作業ディレクトリは既定ブランチで更新する
+func (p *Provider) History(ctx context.Context, repoURL, from, to string) (*release.History, error) {
+	dirName, err := p.client.URLToDirectoryName(repoURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate directory name from URL: %w", err)
+	}
+	repoPath := filepath.Join(p.gitCloneBaseDir, dirName)
+	if err := p.syncRepository(ctx, repoURL, repoPath, p.defaultBranch); err != nil {
+		return nil, err
+	}
+
+	fromCommit, err := p.client.GetCommitInfo(ctx, repoPath, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit info of %s: %w", from, err)
+	}
+	toCommit, err := p.client.GetCommitInfo(ctx, repoPath, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit info of %s: %w", to, err)
+	}
+
+	commits, err := p.client.ListCommitsBetween(ctx, repoPath, fromCommit.Hash, toCommit.Hash)
+	if err != nil {
+		return nil, err
+	}
+	files, err := p.client.DiffFiles(ctx, repoPath, fromCommit.Hash, toCommit.Hash)
+	if err != nil {
+		return nil, err
+	}
+
+	history := &release.History{
+		FromCommit: fromCommit.Hash,
+		ToCommit:   toCommit.Hash,
+		ToDate:     toCommit.Date,
+		Commits:    make([]*release.Commit, 0, len(commits)),
+		Files:      make([]*release.FileChange, 0, len(files)),
+	}
+	for _, commit := range commits {
+		subject, _, _ := strings.Cut(strings.TrimSpace(commit.Message), "\n")
+		history.Commits = append(history.Commits, &release.Commit{
+			Hash:    commit.Hash.String(),
+			Author:  commit.Author.Name,
+			Date:    commit.Author.When,
+			Subject: strings.TrimSpace(subject),
+			Merge:   commit.NumParents() > 1,
+		})
+	}
+	for _, file := range files {
+		history.Files = append(history.Files, &release.FileChange{
+			Path:    file.Path,
+			OldPath: file.OldPath,
+			Kind:    release.FileChangeKind(file.Action),
+		})
+	}
+	return history, nil
+}
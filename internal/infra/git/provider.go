@@ -2,20 +2,26 @@ package git
 
 import (
 	"context"
+	"crypto/sha256"
 	"fmt"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/jinford/dev-rag/internal/core/ingestion"
 	"github.com/jinford/dev-rag/internal/infra/git/filter"
 )
 
 // Provider は Git ソース用の ingestion.SourceProvider 実装
+// 同一リポジトリの複数の ref を並行してインデックス化できるよう、
+// 作業ディレクトリの更新（clone/pull）と除外フィルタの差し替えは排他制御する
 type Provider struct {
 	client          *Client
 	gitCloneBaseDir string
 	defaultBranch   string
-	ignoreFilter    *filter.IgnoreFilter
+
+	mu           sync.RWMutex
+	ignoreFilter *filter.IgnoreFilter
 }
 
 // NewProvider は新しい Git Provider を作成する
@@ -45,13 +51,10 @@ func (p *Provider) ExtractSourceName(identifier string) string {
 	return dirName
 }
 
-// FetchDocuments は Git リポジトリからドキュメント一覧を取得する
-func (p *Provider) FetchDocuments(ctx context.Context, params ingestion.IndexParams) ([]*ingestion.SourceDocument, string, error) {
-	// オプションから ref を取得
-	ref, ok := params.Options["ref"].(string)
-	if !ok || ref == "" {
-		ref = p.defaultBranch
-	}
+// FetchDocuments は Git リポジトリの ref のコミットを確定し、ファイルを順に読み込むイテレータを返す
+// ファイル一覧とコミット情報のみ先に取得し、ファイルの内容はイテレーション中に1件ずつ読み込む
+func (p *Provider) FetchDocuments(ctx context.Context, params ingestion.IndexParams) (ingestion.DocumentIterator, string, error) {
+	ref := p.ResolveRef(params)
 
 	// Git URL からディレクトリ名を生成
 	dirName, err := p.client.URLToDirectoryName(params.Identifier)
@@ -59,10 +62,11 @@ func (p *Provider) FetchDocuments(ctx context.Context, params ingestion.IndexPar
 		return nil, "", fmt.Errorf("failed to generate directory name from URL: %w", err)
 	}
 
-	// Git リポジトリのクローン/pull
+	// Git リポジトリのクローン/pull と除外フィルタの作成
+	// 以降の読み込みは ref のコミットツリーから行うため、作業ディレクトリの更新のみ排他制御する
 	repoPath := filepath.Join(p.gitCloneBaseDir, dirName)
-	if err := p.client.CloneOrPull(ctx, params.Identifier, repoPath, ref); err != nil {
-		return nil, "", fmt.Errorf("failed to clone/pull repository: %w", err)
+	if err := p.syncRepository(ctx, params.Identifier, repoPath, ref); err != nil {
+		return nil, "", err
 	}
 
 	// コミット情報を取得（バージョン識別子として使用）
@@ -83,43 +87,148 @@ func (p *Provider) FetchDocuments(ctx context.Context, params ingestion.IndexPar
 		return nil, "", fmt.Errorf("failed to list files: %w", err)
 	}
 
-	// 除外フィルタを作成
-	ignoreFilter, err := filter.NewIgnoreFilter(repoPath)
+	// ingestion.SourceDocument 形式に変換しながら読み込む
+	// ref ではなく確定したコミットから読み込み、イテレーション中に ref が進んでも同じバージョンの内容を返す
+	documents := func(yield func(*ingestion.SourceDocument, error) bool) {
+		for _, fileInfo := range files {
+			if err := ctx.Err(); err != nil {
+				yield(nil, err)
+				return
+			}
+
+			// ファイル内容を読み込み
+			content, err := p.client.ReadFile(ctx, repoPath, commitInfo.Hash, fileInfo.Path)
+			if err != nil {
+				// ファイル読み込みエラーはスキップ
+				continue
+			}
+
+			// マップから各ファイルのコミット情報を取得
+			fileCommit, ok := fileLastCommits[fileInfo.Path]
+			if !ok {
+				// コミット情報が取得できなかった場合はリポジトリ全体の最新コミット情報を使用（フォールバック）
+				fileCommit = commitInfo
+			}
+
+			doc := &ingestion.SourceDocument{
+				Path:        fileInfo.Path,
+				Content:     content,
+				Size:        fileInfo.Size,
+				ContentHash: fileInfo.ContentHash,
+				// ファイル固有のコミット情報を設定
+				CommitHash: fileCommit.Hash,
+				Author:     fileCommit.Author,
+				UpdatedAt:  fileCommit.Date,
+			}
+			if !yield(doc, nil) {
+				return
+			}
+		}
+	}
+
+	return documents, commitInfo.Hash, nil
+}
+
+// FetchChanges は基準のコミットから ref のコミットまでに追加・変更されたファイルを順に読み込むイテレータを返す（ingestion.ChangeFetcher の実装）
+// ツリー全体の走査と全ファイルの最終更新コミットの取得を行わないため、Webhook を契機とした更新を短時間で反映できる
+func (p *Provider) FetchChanges(ctx context.Context, params ingestion.IndexParams, baseVersion string) (*ingestion.DocumentChanges, error) {
+	ref := p.ResolveRef(params)
+
+	dirName, err := p.client.URLToDirectoryName(params.Identifier)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to create ignore filter: %w", err)
+		return nil, fmt.Errorf("failed to generate directory name from URL: %w", err)
+	}
+	repoPath := filepath.Join(p.gitCloneBaseDir, dirName)
+	if err := p.syncRepository(ctx, params.Identifier, repoPath, ref); err != nil {
+		return nil, err
 	}
-	p.ignoreFilter = ignoreFilter
 
-	// ingestion.SourceDocument 形式に変換
-	var documents []*ingestion.SourceDocument
-	for _, fileInfo := range files {
-		// ファイル内容を読み込み
-		content, err := p.client.ReadFile(ctx, repoPath, ref, fileInfo.Path)
-		if err != nil {
-			// ファイル読み込みエラーはスキップ
-			continue
+	baseCommit, err := p.client.GetCommitInfo(ctx, repoPath, baseVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit info of %s: %w", baseVersion, err)
+	}
+	commitInfo, err := p.client.GetCommitInfo(ctx, repoPath, ref)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit info: %w", err)
+	}
+
+	files, err := p.client.DiffFiles(ctx, repoPath, baseCommit.Hash, commitInfo.Hash)
+	if err != nil {
+		return nil, err
+	}
+	var paths, removed []string
+	for _, file := range files {
+		switch file.Action {
+		case "deleted":
+			removed = append(removed, file.Path)
+		case "renamed":
+			removed = append(removed, file.OldPath)
+			paths = append(paths, file.Path)
+		default:
+			paths = append(paths, file.Path)
 		}
+	}
+
+	// 変更されたファイルの最終更新コミットは範囲内のいずれかのため、ref のコミットの情報で代用する
+	documents := func(yield func(*ingestion.SourceDocument, error) bool) {
+		for _, path := range paths {
+			if err := ctx.Err(); err != nil {
+				yield(nil, err)
+				return
+			}
 
-		// マップから各ファイルのコミット情報を取得
-		fileCommit, ok := fileLastCommits[fileInfo.Path]
-		if !ok {
-			// コミット情報が取得できなかった場合はリポジトリ全体の最新コミット情報を使用（フォールバック）
-			fileCommit = commitInfo
+			content, err := p.client.ReadFile(ctx, repoPath, commitInfo.Hash, path)
+			if err != nil {
+				// ファイル読み込みエラー（サブモジュールなど）はスキップ
+				continue
+			}
+
+			doc := &ingestion.SourceDocument{
+				Path:        path,
+				Content:     content,
+				Size:        int64(len(content)),
+				ContentHash: fmt.Sprintf("%x", sha256.Sum256([]byte(content))),
+				CommitHash:  commitInfo.Hash,
+				Author:      commitInfo.Author,
+				UpdatedAt:   commitInfo.Date,
+			}
+			if !yield(doc, nil) {
+				return
+			}
 		}
+	}
 
-		documents = append(documents, &ingestion.SourceDocument{
-			Path:        fileInfo.Path,
-			Content:     content,
-			Size:        fileInfo.Size,
-			ContentHash: fileInfo.ContentHash,
-			// ファイル固有のコミット情報を設定
-			CommitHash: fileCommit.Hash,
-			Author:     fileCommit.Author,
-			UpdatedAt:  fileCommit.Date,
-		})
+	return &ingestion.DocumentChanges{
+		BaseVersion:       baseCommit.Hash,
+		VersionIdentifier: commitInfo.Hash,
+		Documents:         documents,
+		RemovedPaths:      removed,
+	}, nil
+}
+
+// ResolveRef はインデックス化対象の ref を返す（未指定の場合は既定ブランチ）
+func (p *Provider) ResolveRef(params ingestion.IndexParams) string {
+	if ref, ok := params.Options["ref"].(string); ok && ref != "" {
+		return ref
 	}
+	return p.defaultBranch
+}
 
-	return documents, commitInfo.Hash, nil
+// syncRepository はリポジトリをクローン/pull し、除外フィルタを作成する
+func (p *Provider) syncRepository(ctx context.Context, url, repoPath, ref string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.client.CloneOrPull(ctx, url, repoPath, ref); err != nil {
+		return fmt.Errorf("failed to clone/pull repository: %w", err)
+	}
+
+	ignoreFilter, err := filter.NewIgnoreFilter(repoPath)
+	if err != nil {
+		return fmt.Errorf("failed to create ignore filter: %w", err)
+	}
+	p.ignoreFilter = ignoreFilter
+	return nil
 }
 
 // CreateMetadata は Git ソース用のメタデータを作成する
@@ -145,6 +254,9 @@ func (p *Provider) CreateMetadata(params ingestion.IndexParams) ingestion.Source
 
 // ShouldIgnore はドキュメントを除外すべきかを判定する
 func (p *Provider) ShouldIgnore(doc *ingestion.SourceDocument) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
 	if p.ignoreFilter == nil {
 		return false
 	}
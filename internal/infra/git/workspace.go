@@ -0,0 +1,178 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+
+	"github.com/jinford/dev-rag/internal/core/wiki"
+)
+
+// defaultNewBranch は空のリモートリポジトリに作成するブランチ名
+const defaultNewBranch = "main"
+
+var _ wiki.GitWorkspace = (*Client)(nil)
+
+// WikiRepositoryURL はリポジトリのURLから、GitHub/GitLab の Wiki リポジトリのURLを返す
+// 例: git@github.com:org/repo.git → git@github.com:org/repo.wiki.git
+func WikiRepositoryURL(repoURL string) string {
+	base := strings.TrimSuffix(strings.TrimSuffix(repoURL, "/"), ".git")
+	if strings.HasSuffix(base, ".wiki") {
+		return base + ".git"
+	}
+	return base + ".wiki.git"
+}
+
+// Checkout はリポジトリを dir にクローンして branch をチェックアウトし、チェックアウトしたブランチ名を返す
+// branch が空の場合はリモートのデフォルトブランチ、リモートに存在しない場合はデフォルトブランチから作成する
+// リモートが空のリポジトリの場合は branch（空の場合は main）を新規に作成する
+func (c *Client) Checkout(ctx context.Context, url, branch, dir string) (string, error) {
+	auth, err := c.authFor(url)
+	if err != nil {
+		return "", err
+	}
+
+	repo, err := git.PlainCloneContext(ctx, dir, false, &git.CloneOptions{
+		URL:  url,
+		Auth: auth,
+	})
+	if errors.Is(err, transport.ErrEmptyRemoteRepository) {
+		return c.initEmpty(url, branch, dir)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to clone repository: %w", err)
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return "", fmt.Errorf("failed to get HEAD: %w", err)
+	}
+	if branch == "" || branch == head.Name().Short() {
+		return head.Name().Short(), nil
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return "", fmt.Errorf("failed to get worktree: %w", err)
+	}
+
+	// リモートにブランチがあればその先頭から、なければデフォルトブランチの先頭から作成する
+	from := head.Hash()
+	if remoteRef, err := repo.Reference(plumbing.NewRemoteReferenceName("origin", branch), true); err == nil {
+		from = remoteRef.Hash()
+	}
+	err = worktree.Checkout(&git.CheckoutOptions{
+		Branch: plumbing.NewBranchReferenceName(branch),
+		Hash:   from,
+		Create: true,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to checkout branch %s: %w", branch, err)
+	}
+	return branch, nil
+}
+
+// initEmpty は空のリモートリポジトリに対応する作業ディレクトリを作成する
+func (c *Client) initEmpty(url, branch, dir string) (string, error) {
+	if branch == "" {
+		branch = defaultNewBranch
+	}
+
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		return "", fmt.Errorf("failed to init repository: %w", err)
+	}
+	if _, err := repo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{url}}); err != nil {
+		return "", fmt.Errorf("failed to create remote: %w", err)
+	}
+	head := plumbing.NewSymbolicReference(plumbing.HEAD, plumbing.NewBranchReferenceName(branch))
+	if err := repo.Storer.SetReference(head); err != nil {
+		return "", fmt.Errorf("failed to set HEAD: %w", err)
+	}
+	return branch, nil
+}
+
+// Commit は作業ツリーの変更（追加・削除を含む）をすべてコミットし、コミットハッシュを返す
+func (c *Client) Commit(ctx context.Context, dir, message string) (string, error) {
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to open repository: %w", err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return "", fmt.Errorf("failed to get worktree: %w", err)
+	}
+
+	if err := worktree.AddWithOptions(&git.AddOptions{All: true}); err != nil {
+		return "", fmt.Errorf("failed to stage changes: %w", err)
+	}
+
+	hash, err := worktree.Commit(message, &git.CommitOptions{
+		Author: &object.Signature{
+			Name:  c.authorName,
+			Email: c.authorEmail,
+			When:  time.Now(),
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to commit: %w", err)
+	}
+	return hash.String(), nil
+}
+
+// Push はブランチをリモート（origin）にプッシュする
+func (c *Client) Push(ctx context.Context, dir, branch string) error {
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %w", err)
+	}
+	remote, err := repo.Remote("origin")
+	if err != nil {
+		return fmt.Errorf("failed to get remote: %w", err)
+	}
+
+	auth, err := c.authFor(remote.Config().URLs[0])
+	if err != nil {
+		return err
+	}
+
+	ref := plumbing.NewBranchReferenceName(branch)
+	err = remote.PushContext(ctx, &git.PushOptions{
+		RefSpecs: []config.RefSpec{config.RefSpec(ref + ":" + ref)},
+		Auth:     auth,
+	})
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return fmt.Errorf("failed to push: %w", err)
+	}
+	return nil
+}
+
+// authFor はURLのスキームに応じた認証方法を返す
+// HTTP(S) の場合はアクセストークン（未設定なら認証なし）、それ以外は SSH 鍵を使用する
+func (c *Client) authFor(url string) (transport.AuthMethod, error) {
+	if strings.HasPrefix(url, "https://") || strings.HasPrefix(url, "http://") {
+		if c.httpToken == "" {
+			return nil, nil
+		}
+		// GitHub/GitLab ともにユーザー名は任意の値で、パスワードとしてトークンを検証する
+		return &http.BasicAuth{Username: "dev-rag", Password: c.httpToken}, nil
+	}
+
+	auth, err := c.getSSHAuth()
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup SSH auth: %w", err)
+	}
+	if auth == nil {
+		return nil, nil
+	}
+	return auth, nil
+}
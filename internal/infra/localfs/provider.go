@@ -0,0 +1,191 @@
+package localfs
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/jinford/dev-rag/internal/core/ingestion"
+	"github.com/jinford/dev-rag/internal/infra/git/filter"
+)
+
+// Provider はローカルディレクトリ用の ingestion.SourceProvider 実装
+// Git リモートを持たないディレクトリ（他システムからエクスポートした文書など）をインデックス化する
+// ファイル内容のハッシュからバージョン識別子を算出し、ファイルの更新日時には mtime を使用する
+type Provider struct {
+	mu           sync.RWMutex
+	ignoreFilter *filter.IgnoreFilter
+}
+
+// NewProvider は新しいローカルディレクトリ Provider を作成する
+func NewProvider() *Provider {
+	return &Provider{}
+}
+
+// GetSourceType は ingestion.SourceTypeLocal を返す
+func (p *Provider) GetSourceType() ingestion.SourceType {
+	return ingestion.SourceTypeLocal
+}
+
+// ExtractSourceName はディレクトリのパスからソース名を抽出する
+// 同名のディレクトリを区別するため、絶対パスをソース名とする
+func (p *Provider) ExtractSourceName(identifier string) string {
+	absPath, err := filepath.Abs(identifier)
+	if err != nil {
+		return filepath.Clean(identifier)
+	}
+	return absPath
+}
+
+// FetchDocuments はディレクトリ配下のファイルを順に読み込むイテレータを返す
+// バージョン識別子は全ファイルのパスと内容のハッシュから算出するため、内容が変わらなければ同じ値になる
+// ハッシュの算出では内容を保持せず、イテレーション中に改めて1件ずつ読み込む
+func (p *Provider) FetchDocuments(ctx context.Context, params ingestion.IndexParams) (ingestion.DocumentIterator, string, error) {
+	root := p.ExtractSourceName(params.Identifier)
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to stat directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, "", fmt.Errorf("not a directory: %s", root)
+	}
+
+	ignoreFilter, err := filter.NewIgnoreFilter(root)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create ignore filter: %w", err)
+	}
+	p.mu.Lock()
+	p.ignoreFilter = ignoreFilter
+	p.mu.Unlock()
+
+	// 内容を含まないドキュメント（パス・サイズ・ハッシュ・更新日時）
+	var headers []*ingestion.SourceDocument
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if path == root {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(root, path)
+		if err != nil {
+			return fmt.Errorf("failed to get relative path of %s: %w", path, err)
+		}
+		relPath = filepath.ToSlash(relPath)
+
+		// 除外対象のディレクトリは配下を走査しない
+		if d.IsDir() {
+			if ignoreFilter.ShouldIgnore(relPath) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		// シンボリックリンクなど通常ファイル以外は対象外
+		if !d.Type().IsRegular() || ignoreFilter.ShouldIgnore(relPath) {
+			return nil
+		}
+
+		fileInfo, err := d.Info()
+		if err != nil {
+			return fmt.Errorf("failed to stat file %s: %w", relPath, err)
+		}
+		contentHash, err := hashFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read file %s: %w", relPath, err)
+		}
+
+		headers = append(headers, &ingestion.SourceDocument{
+			Path:        relPath,
+			Size:        fileInfo.Size(),
+			ContentHash: contentHash,
+			UpdatedAt:   fileInfo.ModTime(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to walk directory: %w", err)
+	}
+
+	documents := func(yield func(*ingestion.SourceDocument, error) bool) {
+		for _, header := range headers {
+			if err := ctx.Err(); err != nil {
+				yield(nil, err)
+				return
+			}
+			content, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(header.Path)))
+			if err != nil {
+				yield(nil, fmt.Errorf("failed to read file %s: %w", header.Path, err))
+				return
+			}
+
+			doc := *header
+			doc.Content = string(content)
+			if !yield(&doc, nil) {
+				return
+			}
+		}
+	}
+
+	return documents, versionIdentifier(headers), nil
+}
+
+// hashFile はファイルの内容を読み込みながら SHA-256 のハッシュを算出する
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
+
+// versionIdentifier はファイルのパスと内容のハッシュの組からディレクトリ全体のバージョン識別子を算出する
+// mtime はコピーやエクスポートのたびに変わるため含めない
+func versionIdentifier(documents []*ingestion.SourceDocument) string {
+	sorted := make([]*ingestion.SourceDocument, len(documents))
+	copy(sorted, documents)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Path < sorted[j].Path
+	})
+
+	hash := sha256.New()
+	for _, doc := range sorted {
+		fmt.Fprintf(hash, "%s\x00%s\n", doc.Path, doc.ContentHash)
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil))
+}
+
+// CreateMetadata はローカルディレクトリ用のメタデータを作成する
+func (p *Provider) CreateMetadata(params ingestion.IndexParams) ingestion.SourceMetadata {
+	root := p.ExtractSourceName(params.Identifier)
+	return ingestion.SourceMetadata{
+		"path":      root,
+		"localPath": root,
+	}
+}
+
+// ShouldIgnore はドキュメントを除外すべきかを判定する
+func (p *Provider) ShouldIgnore(doc *ingestion.SourceDocument) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.ignoreFilter == nil {
+		return false
+	}
+	return p.ignoreFilter.ShouldIgnore(doc.Path)
+}
@@ -0,0 +1,71 @@
+package localfs
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jinford/dev-rag/internal/core/ingestion"
+)
+
+func writeFile(t *testing.T, root, relPath, content string) {
+	t.Helper()
+	path := filepath.Join(root, relPath)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func TestProvider_FetchDocuments(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "guide/setup.md", "# Setup\n")
+	writeFile(t, root, "README.md", "# Docs\n")
+	writeFile(t, root, "node_modules/pkg/index.js", "module.exports = {}\n")
+	writeFile(t, root, "drafts/wip.md", "draft\n")
+	writeFile(t, root, ".devragignore", "drafts/\n")
+
+	p := NewProvider()
+	params := ingestion.IndexParams{Identifier: root}
+	stream, version, err := p.FetchDocuments(context.Background(), params)
+	require.NoError(t, err)
+	docs, err := ingestion.CollectDocuments(stream)
+	require.NoError(t, err)
+
+	paths := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		if !p.ShouldIgnore(doc) {
+			paths = append(paths, doc.Path)
+		}
+	}
+	assert.Equal(t, []string{".devragignore", "README.md", "guide/setup.md"}, paths)
+	assert.NotEmpty(t, version)
+	assert.Equal(t, ingestion.SourceTypeLocal, p.GetSourceType())
+	assert.Equal(t, root, p.ExtractSourceName(root))
+
+	// mtime だけが変わった場合はバージョン識別子が変わらない
+	future := time.Now().Add(time.Hour)
+	require.NoError(t, os.Chtimes(filepath.Join(root, "README.md"), future, future))
+	_, sameVersion, err := p.FetchDocuments(context.Background(), params)
+	require.NoError(t, err)
+	assert.Equal(t, version, sameVersion)
+
+	// 内容が変わった場合はバージョン識別子が変わる
+	writeFile(t, root, "README.md", "# Docs v2\n")
+	_, newVersion, err := p.FetchDocuments(context.Background(), params)
+	require.NoError(t, err)
+	assert.NotEqual(t, version, newVersion)
+}
+
+func TestProvider_FetchDocuments_NotDirectory(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "file.txt", "x")
+
+	_, _, err := NewProvider().FetchDocuments(context.Background(), ingestion.IndexParams{
+		Identifier: filepath.Join(root, "file.txt"),
+	})
+	assert.Error(t, err)
+}
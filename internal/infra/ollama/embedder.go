@@ -0,0 +1,225 @@
+package ollama
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jinford/dev-rag/internal/core/ingestion"
+)
+
+const (
+	// DefaultBaseURL はローカルで起動した Ollama サーバのURL
+	DefaultBaseURL = "http://localhost:11434"
+
+	// DefaultEmbeddingModel はモデル未指定時のデフォルトモデル
+	DefaultEmbeddingModel = "nomic-embed-text"
+
+	// DefaultMaxInputTokens はOllama の既定のコンテキスト長（num_ctx）
+	DefaultMaxInputTokens = 2048
+
+	// DefaultTimeout はAPI呼び出しのデフォルトタイムアウト
+	DefaultTimeout = 120 * time.Second
+
+	// maxBatchSize は1リクエストで送るテキストの上限
+	maxBatchSize = 64
+)
+
+// APIError は Ollama API がエラーステータスを返した場合のエラー
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("ollama API error (status %d): %s", e.StatusCode, e.Message)
+}
+
+// IsRateLimitError はエラーが過負荷（HTTP 429/503）によるものかを判定する
+func IsRateLimitError(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode == http.StatusServiceUnavailable
+	}
+	return false
+}
+
+// Embedder は Ollama の /api/embed を使用してテキストをベクトルに変換する
+// エアギャップ環境でローカルモデルを使うためのプロバイダー
+type Embedder struct {
+	httpClient     *http.Client
+	baseURL        string
+	model          string
+	dimension      int
+	maxInputTokens int
+}
+
+type embedderOptions struct {
+	baseURL        string
+	model          string
+	dimension      int
+	maxInputTokens int
+	httpClient     *http.Client
+}
+
+// EmbedderOption は Embedder のオプション設定
+type EmbedderOption func(*embedderOptions)
+
+// WithBaseURL は Ollama サーバのURLを上書きする
+func WithBaseURL(baseURL string) EmbedderOption {
+	return func(o *embedderOptions) {
+		o.baseURL = baseURL
+	}
+}
+
+// WithEmbeddingModel はモデル名を上書きする
+func WithEmbeddingModel(model string) EmbedderOption {
+	return func(o *embedderOptions) {
+		o.model = model
+	}
+}
+
+// WithEmbeddingDimension は期待するベクトル次元を設定する
+// Ollama は出力次元を指定できないため、応答の次元がこの値と一致するかを検証する
+func WithEmbeddingDimension(dimension int) EmbedderOption {
+	return func(o *embedderOptions) {
+		o.dimension = dimension
+	}
+}
+
+// WithMaxInputTokens は1テキストあたりの最大入力トークン数を上書きする
+func WithMaxInputTokens(tokens int) EmbedderOption {
+	return func(o *embedderOptions) {
+		o.maxInputTokens = tokens
+	}
+}
+
+// WithHTTPClient は HTTP クライアントを差し替える
+func WithHTTPClient(client *http.Client) EmbedderOption {
+	return func(o *embedderOptions) {
+		o.httpClient = client
+	}
+}
+
+// NewEmbedder は新しい Embedder を作成する
+func NewEmbedder(opts ...EmbedderOption) *Embedder {
+	options := embedderOptions{
+		baseURL:        DefaultBaseURL,
+		model:          DefaultEmbeddingModel,
+		maxInputTokens: DefaultMaxInputTokens,
+		httpClient:     &http.Client{Timeout: DefaultTimeout},
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return &Embedder{
+		httpClient:     options.httpClient,
+		baseURL:        strings.TrimRight(options.baseURL, "/"),
+		model:          options.model,
+		dimension:      options.dimension,
+		maxInputTokens: options.maxInputTokens,
+	}
+}
+
+type embedRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+type embedResponse struct {
+	Embeddings [][]float32 `json:"embeddings"`
+}
+
+// Embed は単一テキストの Embedding を生成する
+func (e *Embedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	embeddings, err := e.BatchEmbed(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(embeddings) == 0 {
+		return nil, fmt.Errorf("no embeddings generated")
+	}
+
+	return embeddings[0], nil
+}
+
+// BatchEmbed はバッチで Embedding を生成する
+func (e *Embedder) BatchEmbed(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return nil, fmt.Errorf("no texts provided")
+	}
+
+	if len(texts) > maxBatchSize {
+		return nil, fmt.Errorf("batch size exceeds maximum of %d", maxBatchSize)
+	}
+
+	body, err := json.Marshal(embedRequest{Model: e.model, Input: texts})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.baseURL+"/api/embed", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate embeddings: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("failed to generate embeddings: %w", &APIError{StatusCode: resp.StatusCode, Message: string(msg)})
+	}
+
+	var result embedResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if len(result.Embeddings) != len(texts) {
+		return nil, fmt.Errorf("embedding count mismatch: expected %d, got %d", len(texts), len(result.Embeddings))
+	}
+
+	for _, vector := range result.Embeddings {
+		if e.dimension > 0 && len(vector) != e.dimension {
+			return nil, fmt.Errorf("model %s returned %d-dimensional vectors but %d is configured", e.model, len(vector), e.dimension)
+		}
+	}
+
+	return result.Embeddings, nil
+}
+
+// ModelName はプロバイダーを含むモデル名を返す
+func (e *Embedder) ModelName() string {
+	return "ollama/" + e.model
+}
+
+// Dimension はベクトル次元数を返す
+func (e *Embedder) Dimension() int {
+	return e.dimension
+}
+
+// MaxBatchSize はバッチ処理の最大サイズを返す
+func (e *Embedder) MaxBatchSize() int {
+	return maxBatchSize
+}
+
+// MaxInputTokens は1テキストあたりの最大入力トークン数を返す
+func (e *Embedder) MaxInputTokens() int {
+	return e.maxInputTokens
+}
+
+// インターフェース実装の確認
+var _ ingestion.Embedder = (*Embedder)(nil)
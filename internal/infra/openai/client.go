@@ -6,8 +6,10 @@ import (
 	"fmt"
 	"math"
 	"os"
+	"strings"
 	"time"
 
+	"github.com/jinford/dev-rag/internal/core/llm"
 	"github.com/jinford/dev-rag/internal/core/wiki"
 	"github.com/openai/openai-go/v3"
 	"github.com/openai/openai-go/v3/option"
@@ -52,6 +54,34 @@ type Client struct {
 	timeout time.Duration
 }
 
+type clientOptions struct {
+	requestOptions []option.RequestOption
+}
+
+// ClientOption は Client のオプション設定
+type ClientOption func(*clientOptions)
+
+// WithBaseURL は接続先をOpenAI互換エンドポイント（Ollama の /v1 など）に切り替える
+func WithBaseURL(baseURL string) ClientOption {
+	return func(o *clientOptions) {
+		o.requestOptions = append(o.requestOptions, option.WithBaseURL(strings.TrimRight(baseURL, "/")+"/"))
+	}
+}
+
+// WithClientAzureEndpoint は Azure OpenAI のデプロイメントを接続先に設定する
+// Azure ではデプロイメントがURLに含まれるため、呼び出し単位のモデル上書きは無視される
+func WithClientAzureEndpoint(endpoint, deployment, apiVersion, apiKey string) ClientOption {
+	return func(o *clientOptions) {
+		baseURL := strings.TrimRight(endpoint, "/") + "/openai/deployments/" + deployment + "/"
+		o.requestOptions = append(o.requestOptions,
+			option.WithBaseURL(baseURL),
+			option.WithQuery("api-version", apiVersion),
+			option.WithHeaderDel("authorization"),
+			option.WithHeader("api-key", apiKey),
+		)
+	}
+}
+
 // NewClient は新しい Client を作成する
 // APIキーは環境変数 OPENAI_API_KEY から読み込む
 func NewClient() (*Client, error) {
@@ -80,12 +110,17 @@ func NewClientWithModel(model string) (*Client, error) {
 }
 
 // NewClientWithAPIKey はAPIキーとモデルを指定して Client を作成する
-func NewClientWithAPIKey(apiKey, model string) (*Client, error) {
+func NewClientWithAPIKey(apiKey, model string, opts ...ClientOption) (*Client, error) {
 	if apiKey == "" {
 		return nil, ErrAPIKeyNotSet
 	}
 
-	client := openai.NewClient(option.WithAPIKey(apiKey))
+	var options clientOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	client := openai.NewClient(append([]option.RequestOption{option.WithAPIKey(apiKey)}, options.requestOptions...)...)
 
 	return &Client{
 		client:  client,
@@ -105,11 +140,18 @@ func (c *Client) ModelName() string {
 }
 
 // GenerateCompletion は OpenAI API を使用してテキストを生成する
+// コンテキストに llm.WithModel でモデルが指定されている場合はそのモデルを使用し、
+// llm.WithMaxTokens で最大出力トークン数が指定されている場合は出力をその範囲に制限する
 func (c *Client) GenerateCompletion(ctx context.Context, prompt string) (string, error) {
+	model := c.model
+	if override, ok := llm.ModelFromContext(ctx); ok {
+		model = override
+	}
+
 	ctx, cancel := context.WithTimeout(ctx, c.timeout)
 	defer cancel()
 
-	content, err := c.generateWithRetry(ctx, c.model, prompt)
+	content, err := c.generateWithRetry(ctx, model, prompt)
 	if err != nil {
 		return "", err
 	}
@@ -138,12 +180,30 @@ func (c *Client) generateWithRetry(ctx context.Context, model string, prompt str
 				openai.UserMessage(prompt),
 			},
 		}
+		if maxTokens, ok := llm.MaxTokensFromContext(ctx); ok {
+			params.MaxCompletionTokens = openai.Int(int64(maxTokens))
+		}
+
+		// 生成中の出力を受け取る PartialOutput が設定されている場合はストリーミングで生成する
+		if out, ok := llm.PartialOutputFromContext(ctx); ok {
+			content, err := c.streamCompletion(ctx, params, out)
+			if err != nil {
+				lastErr = err
+
+				if IsRateLimitError(err) {
+					continue
+				}
+
+				return "", fmt.Errorf("OpenAI API call failed: %w", err)
+			}
+			return content, nil
+		}
 
 		completion, err := c.client.Chat.Completions.New(ctx, params)
 		if err != nil {
 			lastErr = err
 
-			if isRateLimitError(err) {
+			if IsRateLimitError(err) {
 				continue
 			}
 
@@ -162,7 +222,31 @@ func (c *Client) generateWithRetry(ctx context.Context, model string, prompt str
 	return "", fmt.Errorf("%w: %v", ErrMaxRetriesExceeded, lastErr)
 }
 
-func isRateLimitError(err error) bool {
+// streamCompletion はストリーミングでテキストを生成し、受け取った出力を順に out に渡す
+// 再試行で同じ出力を重ねて渡さないように、生成を始める前に out に渡した前回の試行の出力を破棄させる
+func (c *Client) streamCompletion(ctx context.Context, params openai.ChatCompletionNewParams, out llm.PartialOutput) (string, error) {
+	out.Reset()
+	stream := c.client.Chat.Completions.NewStreaming(ctx, params)
+	defer stream.Close()
+
+	var content strings.Builder
+	for stream.Next() {
+		chunk := stream.Current()
+		if len(chunk.Choices) == 0 || chunk.Choices[0].Delta.Content == "" {
+			continue
+		}
+		delta := chunk.Choices[0].Delta.Content
+		content.WriteString(delta)
+		out.Write(delta)
+	}
+	if err := stream.Err(); err != nil {
+		return "", err
+	}
+	return content.String(), nil
+}
+
+// IsRateLimitError はエラーがレート制限（HTTP 429）によるものかを判定する
+func IsRateLimitError(err error) bool {
 	if err == nil {
 		return false
 	}
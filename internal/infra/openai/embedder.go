@@ -3,6 +3,7 @@ package openai
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/jinford/dev-rag/internal/core/ingestion"
 	"github.com/openai/openai-go/v3"
@@ -14,6 +15,8 @@ type Embedder struct {
 	client    openai.Client
 	model     string
 	dimension int
+	provider  string // OpenAI互換プロバイダー名（空ならOpenAI本家）
+	maxTokens int    // 1テキストあたりの最大入力トークン数
 }
 
 const (
@@ -21,11 +24,16 @@ const (
 	DefaultEmbeddingModel = "text-embedding-3-small"
 	// DefaultEmbeddingDimension はOpenAI推奨のデフォルト次元
 	DefaultEmbeddingDimension = 1536
+	// DefaultMaxInputTokens は text-embedding-3 系モデルの最大入力トークン数
+	DefaultMaxInputTokens = 8191
 )
 
 type embedderOptions struct {
-	model     string
-	dimension int
+	model          string
+	dimension      int
+	maxInputTokens int
+	provider       string
+	requestOptions []option.RequestOption
 }
 
 // EmbedderOption は Embedder のオプション設定
@@ -45,22 +53,58 @@ func WithEmbeddingDimension(dimension int) EmbedderOption {
 	}
 }
 
+// WithMaxInputTokens は1テキストあたりの最大入力トークン数を上書きする
+func WithMaxInputTokens(tokens int) EmbedderOption {
+	return func(o *embedderOptions) {
+		o.maxInputTokens = tokens
+	}
+}
+
+// WithEmbeddingRequestOptions はクライアントに追加のリクエストオプションを渡す
+// Azure OpenAI などOpenAI互換エンドポイントの接続先切り替えに使用する
+func WithEmbeddingRequestOptions(opts ...option.RequestOption) EmbedderOption {
+	return func(o *embedderOptions) {
+		o.requestOptions = append(o.requestOptions, opts...)
+	}
+}
+
+// WithAzureEndpoint は Azure OpenAI のデプロイメントを接続先に設定する
+// Azure ではモデル名の代わりにデプロイメント名でモデルが決まる
+func WithAzureEndpoint(endpoint, deployment, apiVersion, apiKey string) EmbedderOption {
+	return func(o *embedderOptions) {
+		baseURL := strings.TrimRight(endpoint, "/") + "/openai/deployments/" + deployment + "/"
+		o.model = deployment
+		o.provider = "azure"
+		o.requestOptions = append(o.requestOptions,
+			option.WithBaseURL(baseURL),
+			option.WithQuery("api-version", apiVersion),
+			option.WithHeaderDel("authorization"),
+			option.WithHeader("api-key", apiKey),
+		)
+	}
+}
+
 // NewEmbedder は新しい Embedder を作成する
 func NewEmbedder(apiKey string, opts ...EmbedderOption) *Embedder {
 	options := embedderOptions{
-		model:     DefaultEmbeddingModel,
-		dimension: DefaultEmbeddingDimension,
+		model:          DefaultEmbeddingModel,
+		dimension:      DefaultEmbeddingDimension,
+		maxInputTokens: DefaultMaxInputTokens,
 	}
 	for _, opt := range opts {
 		opt(&options)
 	}
 
+	requestOptions := append([]option.RequestOption{option.WithAPIKey(apiKey)}, options.requestOptions...)
+
 	return &Embedder{
 		client: openai.NewClient(
-			option.WithAPIKey(apiKey),
+			requestOptions...,
 		),
 		model:     options.model,
 		dimension: options.dimension,
+		provider:  options.provider,
+		maxTokens: options.maxInputTokens,
 	}
 }
 
@@ -124,7 +168,11 @@ func (e *Embedder) BatchEmbed(ctx context.Context, texts []string) ([][]float32,
 }
 
 // ModelName はモデル名を返す
+// OpenAI互換プロバイダーの場合は "azure/<デプロイメント名>" のようにプロバイダー名を付与する
 func (e *Embedder) ModelName() string {
+	if e.provider != "" {
+		return e.provider + "/" + e.model
+	}
 	return e.model
 }
 
@@ -138,10 +186,15 @@ func (e *Embedder) MaxBatchSize() int {
 	return 100
 }
 
+// MaxInputTokens は1テキストあたりの最大入力トークン数を返す
+func (e *Embedder) MaxInputTokens() int {
+	return e.maxTokens
+}
+
 // Metadata はモデル情報を返す
 func (e *Embedder) Metadata() ingestion.Metadata {
 	return ingestion.Metadata{
-		ModelName: e.model,
+		ModelName: e.ModelName(),
 		Dimension: e.dimension,
 	}
 }
@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+
+	"github.com/jinford/dev-rag/internal/core/apispec"
+	"github.com/jinford/dev-rag/internal/core/wiki"
+	"github.com/jinford/dev-rag/internal/infra/postgres/sqlc"
+)
+
+// ReplaceAPIEndpoints はファイルのエンドポイントを置き換える（既存のエンドポイントは削除する）
+func (r *Repository) ReplaceAPIEndpoints(ctx context.Context, fileID uuid.UUID, endpoints []*apispec.Endpoint) error {
+	if err := r.q.DeleteAPIEndpointsByFile(ctx, UUIDToPgtype(fileID)); err != nil {
+		return fmt.Errorf("failed to delete api endpoints: %w", err)
+	}
+	for _, ep := range endpoints {
+		err := r.q.CreateAPIEndpoint(ctx, sqlc.CreateAPIEndpointParams{
+			SnapshotID:   UUIDToPgtype(ep.SnapshotID),
+			FileID:       UUIDToPgtype(fileID),
+			FilePath:     ep.FilePath,
+			Kind:         string(ep.Kind),
+			Service:      ep.Service,
+			Method:       ep.Method,
+			Path:         ep.Path,
+			OperationID:  ep.OperationID,
+			Summary:      ep.Summary,
+			RequestType:  ep.RequestType,
+			ResponseType: ep.ResponseType,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create api endpoint: %w", err)
+		}
+	}
+	return nil
+}
+
+// APIEndpointRepository は core/apispec.Reader と core/wiki.APIEndpointReader を実装する PostgreSQL リポジトリ。
+type APIEndpointRepository struct {
+	q sqlc.Querier
+}
+
+// NewAPIEndpointRepository は新しい APIEndpointRepository を返す。
+func NewAPIEndpointRepository(q sqlc.Querier) *APIEndpointRepository {
+	return &APIEndpointRepository{q: q}
+}
+
+var (
+	_ apispec.Reader         = (*APIEndpointRepository)(nil)
+	_ wiki.APIEndpointReader = (*APIEndpointRepository)(nil)
+)
+
+func (r *APIEndpointRepository) ListEndpoints(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID], filter apispec.ListFilter) ([]*apispec.Endpoint, error) {
+	rows, err := r.q.ListAPIEndpoints(ctx, sqlc.ListAPIEndpointsParams{
+		ProductID:  UUIDOptionToPgtype(productID),
+		SnapshotID: UUIDOptionToPgtype(snapshotID),
+		Kind:       StringToNullableText(string(filter.Kind)),
+		Keyword:    StringToNullableText(filter.Keyword),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api endpoints: %w", err)
+	}
+
+	endpoints := make([]*apispec.Endpoint, 0, len(rows))
+	for _, row := range rows {
+		endpoints = append(endpoints, &apispec.Endpoint{
+			ID:           PgtypeToUUID(row.ID),
+			SnapshotID:   PgtypeToUUID(row.SnapshotID),
+			FileID:       PgtypeToUUID(row.FileID),
+			SourceName:   row.SourceName,
+			FilePath:     row.FilePath,
+			Kind:         apispec.Kind(row.Kind),
+			Service:      row.Service,
+			Method:       row.Method,
+			Path:         row.Path,
+			OperationID:  row.OperationID,
+			Summary:      row.Summary,
+			RequestType:  row.RequestType,
+			ResponseType: row.ResponseType,
+		})
+	}
+	return endpoints, nil
+}
@@ -0,0 +1,53 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+
+	"github.com/jinford/dev-rag/internal/core/wiki"
+	"github.com/jinford/dev-rag/internal/infra/postgres/sqlc"
+)
+
+// APISymbolRepository は core/wiki.APISymbolReader を実装する PostgreSQL リポジトリ。
+type APISymbolRepository struct {
+	q sqlc.Querier
+}
+
+// NewAPISymbolRepository は新しい APISymbolRepository を返す。
+func NewAPISymbolRepository(q sqlc.Querier) *APISymbolRepository {
+	return &APISymbolRepository{q: q}
+}
+
+var _ wiki.APISymbolReader = (*APISymbolRepository)(nil)
+
+func (r *APISymbolRepository) ListAPISymbols(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID], domains []string, limit int) ([]*wiki.APISymbol, error) {
+	rows, err := r.q.ListAPISymbols(ctx, sqlc.ListAPISymbolsParams{
+		ProductID:  UUIDOptionToPgtype(productID),
+		SnapshotID: UUIDOptionToPgtype(snapshotID),
+		Domains:    stringsParam(domains),
+		RowLimit:   int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api symbols: %w", err)
+	}
+
+	symbols := make([]*wiki.APISymbol, 0, len(rows))
+	for _, row := range rows {
+		symbols = append(symbols, &wiki.APISymbol{
+			FilePath:    row.Path,
+			Language:    row.Language.String,
+			Kind:        row.ChunkType.String,
+			Name:        row.ChunkName,
+			Parent:      row.ParentName.String,
+			Signature:   PgtextToStringPtr(row.Signature),
+			DocComment:  PgtextToStringPtr(row.DocComment),
+			ContentHead: row.ContentHead,
+			StartLine:   int(row.StartLine),
+			EndLine:     int(row.EndLine),
+		})
+	}
+	return symbols, nil
+}
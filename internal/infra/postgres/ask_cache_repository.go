@@ -0,0 +1,64 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/samber/mo"
+
+	"github.com/jinford/dev-rag/internal/core/ask"
+	"github.com/jinford/dev-rag/internal/infra/postgres/sqlc"
+)
+
+// AskCacheRepository は core/ask.AnswerCacheStore を実装する PostgreSQL リポジトリ。
+type AskCacheRepository struct {
+	q sqlc.Querier
+}
+
+// NewAskCacheRepository は新しい AskCacheRepository を返す。
+func NewAskCacheRepository(q sqlc.Querier) *AskCacheRepository {
+	return &AskCacheRepository{q: q}
+}
+
+var _ ask.AnswerCacheStore = (*AskCacheRepository)(nil)
+
+func (r *AskCacheRepository) Get(ctx context.Context, key string, notBefore time.Time) (mo.Option[*ask.AskResult], error) {
+	data, err := r.q.HitAskCache(ctx, sqlc.HitAskCacheParams{
+		Key:       key,
+		NotBefore: TimeToPgtype(notBefore),
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows || err == sql.ErrNoRows {
+			return mo.None[*ask.AskResult](), nil
+		}
+		return mo.None[*ask.AskResult](), fmt.Errorf("failed to get ask cache: %w", err)
+	}
+
+	var result ask.AskResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return mo.None[*ask.AskResult](), fmt.Errorf("failed to unmarshal ask cache: %w", err)
+	}
+	return mo.Some(&result), nil
+}
+
+func (r *AskCacheRepository) Put(ctx context.Context, entry *ask.AnswerCacheEntry) error {
+	data, err := json.Marshal(entry.Result)
+	if err != nil {
+		return fmt.Errorf("failed to marshal ask cache: %w", err)
+	}
+	if err := r.q.UpsertAskCache(ctx, sqlc.UpsertAskCacheParams{
+		Key:           entry.Key,
+		ProductID:     UUIDOptionToPgtype(entry.ProductID),
+		SnapshotID:    UUIDOptionToPgtype(entry.SnapshotID),
+		PromptVersion: entry.PromptVersion,
+		Question:      entry.Question,
+		Result:        data,
+	}); err != nil {
+		return fmt.Errorf("failed to put ask cache: %w", err)
+	}
+	return nil
+}
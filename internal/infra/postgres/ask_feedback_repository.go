@@ -0,0 +1,128 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/samber/mo"
+
+	"github.com/jinford/dev-rag/internal/core/ask"
+	"github.com/jinford/dev-rag/internal/infra/postgres/sqlc"
+)
+
+// AskFeedbackRepository は core/ask.FeedbackStore を実装する PostgreSQL リポジトリ。
+type AskFeedbackRepository struct {
+	q sqlc.Querier
+}
+
+// NewAskFeedbackRepository は新しい AskFeedbackRepository を返す。
+func NewAskFeedbackRepository(q sqlc.Querier) *AskFeedbackRepository {
+	return &AskFeedbackRepository{q: q}
+}
+
+var _ ask.FeedbackStore = (*AskFeedbackRepository)(nil)
+
+func (r *AskFeedbackRepository) RecordAnswer(ctx context.Context, answer *ask.RecordedAnswer) (*ask.RecordedAnswer, error) {
+	row, err := r.q.CreateAskAnswer(ctx, sqlc.CreateAskAnswerParams{
+		ProductID:     UUIDToPgtype(answer.ProductID),
+		Origin:        answer.Origin,
+		ChannelID:     answer.ChannelID,
+		MessageTs:     answer.MessageTS,
+		UserID:        answer.UserID,
+		Query:         answer.Query,
+		Answer:        answer.Answer,
+		Refused:       answer.Refused,
+		PromptVersion: answer.PromptVersion,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to record ask answer: %w", err)
+	}
+	return convertAskAnswer(row), nil
+}
+
+func (r *AskFeedbackRepository) GetAnswerByMessage(ctx context.Context, origin, channelID, messageTS string) (mo.Option[*ask.RecordedAnswer], error) {
+	row, err := r.q.GetAskAnswerByMessage(ctx, sqlc.GetAskAnswerByMessageParams{
+		Origin:    origin,
+		ChannelID: channelID,
+		MessageTs: messageTS,
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows || err == sql.ErrNoRows {
+			return mo.None[*ask.RecordedAnswer](), nil
+		}
+		return mo.None[*ask.RecordedAnswer](), fmt.Errorf("failed to get ask answer: %w", err)
+	}
+	return mo.Some(convertAskAnswer(row)), nil
+}
+
+func (r *AskFeedbackRepository) SetRating(ctx context.Context, answerID uuid.UUID, userID string, rating ask.Rating) error {
+	if err := r.q.UpsertAskFeedback(ctx, sqlc.UpsertAskFeedbackParams{
+		AnswerID: UUIDToPgtype(answerID),
+		UserID:   userID,
+		Rating:   int16(rating),
+	}); err != nil {
+		return fmt.Errorf("failed to set ask feedback: %w", err)
+	}
+	return nil
+}
+
+func (r *AskFeedbackRepository) ClearRating(ctx context.Context, answerID uuid.UUID, userID string, rating ask.Rating) error {
+	if err := r.q.DeleteAskFeedback(ctx, sqlc.DeleteAskFeedbackParams{
+		AnswerID: UUIDToPgtype(answerID),
+		UserID:   userID,
+		Rating:   int16(rating),
+	}); err != nil {
+		return fmt.Errorf("failed to clear ask feedback: %w", err)
+	}
+	return nil
+}
+
+func (r *AskFeedbackRepository) ListRatedAnswers(ctx context.Context, productID uuid.UUID, limit int) ([]*ask.RecordedAnswer, error) {
+	rows, err := r.q.ListRatedAskAnswers(ctx, sqlc.ListRatedAskAnswersParams{
+		ProductID: UUIDToPgtype(productID),
+		Limit:     int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rated ask answers: %w", err)
+	}
+
+	answers := make([]*ask.RecordedAnswer, 0, len(rows))
+	for _, row := range rows {
+		answer := convertAskAnswer(sqlc.AskAnswer{
+			ID:            row.ID,
+			ProductID:     row.ProductID,
+			Origin:        row.Origin,
+			ChannelID:     row.ChannelID,
+			MessageTs:     row.MessageTs,
+			UserID:        row.UserID,
+			Query:         row.Query,
+			Answer:        row.Answer,
+			Refused:       row.Refused,
+			PromptVersion: row.PromptVersion,
+			CreatedAt:     row.CreatedAt,
+		})
+		answer.UpCount = row.UpCount
+		answer.DownCount = row.DownCount
+		answers = append(answers, answer)
+	}
+	return answers, nil
+}
+
+func convertAskAnswer(row sqlc.AskAnswer) *ask.RecordedAnswer {
+	return &ask.RecordedAnswer{
+		ID:            PgtypeToUUID(row.ID),
+		ProductID:     PgtypeToUUID(row.ProductID),
+		Origin:        row.Origin,
+		ChannelID:     row.ChannelID,
+		MessageTS:     row.MessageTs,
+		UserID:        row.UserID,
+		Query:         row.Query,
+		Answer:        row.Answer,
+		Refused:       row.Refused,
+		PromptVersion: row.PromptVersion,
+		CreatedAt:     PgtypeToTime(row.CreatedAt),
+	}
+}
@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/samber/mo"
+
+	"github.com/jinford/dev-rag/internal/core/ingestion"
+	"github.com/jinford/dev-rag/internal/infra/postgres/sqlc"
+)
+
+// GetFileByPath はスナップショット内のパスでファイルを取得する
+func (r *Repository) GetFileByPath(ctx context.Context, snapshotID uuid.UUID, path string) (mo.Option[*ingestion.File], error) {
+	file, err := r.q.GetFileByPath(ctx, sqlc.GetFileByPathParams{
+		SnapshotID: UUIDToPgtype(snapshotID),
+		Path:       path,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, sql.ErrNoRows) {
+			return mo.None[*ingestion.File](), nil
+		}
+		return mo.None[*ingestion.File](), fmt.Errorf("failed to get file by path: %w", err)
+	}
+
+	return mo.Some(&ingestion.File{
+		ID:          PgtypeToUUID(file.ID),
+		SnapshotID:  PgtypeToUUID(file.SnapshotID),
+		Path:        file.Path,
+		Size:        file.Size,
+		ContentType: file.ContentType,
+		ContentHash: file.ContentHash,
+		Language:    PgtextToStringPtr(file.Language),
+		Domain:      PgtextToStringPtr(file.Domain),
+		CreatedAt:   PgtypeToTime(file.CreatedAt),
+	}), nil
+}
+
+// GetChunkByKey はチャンクキーでチャンクを取得する
+func (r *Repository) GetChunkByKey(ctx context.Context, chunkKey string) (mo.Option[*ingestion.Chunk], error) {
+	chunk, err := r.q.GetChunkByKey(ctx, chunkKey)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, sql.ErrNoRows) {
+			return mo.None[*ingestion.Chunk](), nil
+		}
+		return mo.None[*ingestion.Chunk](), fmt.Errorf("failed to get chunk by key: %w", err)
+	}
+
+	return mo.Some(convertSQLCChunk(chunk)), nil
+}
+
+// GetChunkImportance はチャンクの重要度の内訳を取得する（未算出の場合は None）
+func (r *Repository) GetChunkImportance(ctx context.Context, chunkID uuid.UUID) (mo.Option[*ingestion.ChunkImportance], error) {
+	row, err := r.q.GetChunkImportance(ctx, UUIDToPgtype(chunkID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, sql.ErrNoRows) {
+			return mo.None[*ingestion.ChunkImportance](), nil
+		}
+		return mo.None[*ingestion.ChunkImportance](), fmt.Errorf("failed to get chunk importance: %w", err)
+	}
+
+	return mo.Some(&ingestion.ChunkImportance{
+		ChunkID:      PgtypeToUUID(row.ChunkID),
+		Algorithm:    row.Algorithm,
+		GraphScore:   row.GraphScore,
+		EditScore:    row.EditScore,
+		FinalScore:   row.FinalScore,
+		EditCount:    int(row.EditCount),
+		CalculatedAt: PgtypeToTime(row.CalculatedAt),
+	}), nil
+}
+
+// GetEmbeddingInfo はチャンクの Embedding のモデルと作成日時を取得する（未作成の場合は None）
+func (r *Repository) GetEmbeddingInfo(ctx context.Context, chunkID uuid.UUID) (mo.Option[*ingestion.EmbeddingInfo], error) {
+	row, err := r.q.GetEmbeddingModel(ctx, UUIDToPgtype(chunkID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, sql.ErrNoRows) {
+			return mo.None[*ingestion.EmbeddingInfo](), nil
+		}
+		return mo.None[*ingestion.EmbeddingInfo](), fmt.Errorf("failed to get embedding model: %w", err)
+	}
+
+	return mo.Some(&ingestion.EmbeddingInfo{
+		Model:     row.Model,
+		CreatedAt: PgtypeToTime(row.CreatedAt),
+	}), nil
+}
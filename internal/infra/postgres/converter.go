@@ -6,6 +6,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/samber/mo"
 )
 
 // UUIDToPgtype converts uuid.UUID to pgtype.UUID
@@ -26,6 +27,14 @@ func UUIDPtrToPgtype(id *uuid.UUID) pgtype.UUID {
 	return pgtype.UUID{Bytes: *id, Valid: true}
 }
 
+// UUIDOptionToPgtype converts mo.Option[uuid.UUID] to pgtype.UUID
+func UUIDOptionToPgtype(id mo.Option[uuid.UUID]) pgtype.UUID {
+	if v, ok := id.Get(); ok {
+		return pgtype.UUID{Bytes: v, Valid: true}
+	}
+	return pgtype.UUID{}
+}
+
 // PgtypeToUUIDPtr converts pgtype.UUID to *uuid.UUID
 func PgtypeToUUIDPtr(id pgtype.UUID) *uuid.UUID {
 	if !id.Valid {
@@ -142,6 +151,23 @@ func PgtypeToFloat64Ptr(n pgtype.Numeric) *float64 {
 	return &val
 }
 
+// Float64PtrToPgFloat8 converts *float64 to pgtype.Float8
+func Float64PtrToPgFloat8(f *float64) pgtype.Float8 {
+	if f == nil {
+		return pgtype.Float8{}
+	}
+	return pgtype.Float8{Float64: *f, Valid: true}
+}
+
+// PgFloat8ToFloat64Ptr converts pgtype.Float8 to *float64
+func PgFloat8ToFloat64Ptr(f pgtype.Float8) *float64 {
+	if !f.Valid {
+		return nil
+	}
+	val := f.Float64
+	return &val
+}
+
 // PgnumericToFloat64 converts pgtype.Numeric to float64
 func PgnumericToFloat64(n pgtype.Numeric) float64 {
 	if !n.Valid {
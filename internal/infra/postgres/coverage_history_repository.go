@@ -0,0 +1,50 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/jinford/dev-rag/internal/core/coverage"
+	"github.com/jinford/dev-rag/internal/infra/postgres/sqlc"
+)
+
+// CoverageHistoryRepository は core/coverage.HistoryRepository を実装する PostgreSQL リポジトリ。
+type CoverageHistoryRepository struct {
+	q sqlc.Querier
+}
+
+// NewCoverageHistoryRepository は新しい CoverageHistoryRepository を返す。
+func NewCoverageHistoryRepository(q sqlc.Querier) *CoverageHistoryRepository {
+	return &CoverageHistoryRepository{q: q}
+}
+
+var _ coverage.HistoryRepository = (*CoverageHistoryRepository)(nil)
+
+func (r *CoverageHistoryRepository) ListCoverageHistory(ctx context.Context, productID uuid.UUID, filter coverage.TrendFilter) ([]*coverage.TrendPoint, error) {
+	rows, err := r.q.ListCoverageHistoryByProduct(ctx, sqlc.ListCoverageHistoryByProductParams{
+		ProductID: UUIDToPgtype(productID),
+		Domain:    StringToNullableText(filter.Domain),
+		Since:     nullableTimestamp(filter.Since),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list coverage history: %w", err)
+	}
+
+	points := make([]*coverage.TrendPoint, 0, len(rows))
+	for _, row := range rows {
+		points = append(points, &coverage.TrendPoint{
+			SnapshotID:    PgtypeToUUID(row.SnapshotID),
+			SourceName:    row.SourceName,
+			Version:       row.VersionIdentifier,
+			Domain:        row.Domain,
+			TotalFiles:    int(row.TotalFiles),
+			IndexedFiles:  int(row.IndexedFiles),
+			IndexedChunks: int(row.IndexedChunks),
+			CoverageRate:  PgnumericToFloat64(row.CoverageRate),
+			RecordedAt:    PgtypeToTime(row.RecordedAt),
+		})
+	}
+	return points, nil
+}
@@ -0,0 +1,109 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/samber/mo"
+
+	"github.com/jinford/dev-rag/internal/core/ask"
+	"github.com/jinford/dev-rag/internal/core/decision"
+	"github.com/jinford/dev-rag/internal/core/wiki"
+	"github.com/jinford/dev-rag/internal/infra/postgres/sqlc"
+)
+
+// UpsertDecisionRecord は設計判断の記録のメタデータを保存する（同じファイルの記録は上書きする）
+func (r *Repository) UpsertDecisionRecord(ctx context.Context, record *decision.Record) error {
+	err := r.q.UpsertDecisionRecord(ctx, sqlc.UpsertDecisionRecordParams{
+		SnapshotID: UUIDToPgtype(record.SnapshotID),
+		FileID:     UUIDToPgtype(record.FileID),
+		FilePath:   record.FilePath,
+		Number:     IntPtrToPgInt4(record.Number),
+		Title:      record.Title,
+		Status:     string(record.Status),
+		StatusText: record.StatusText,
+		DecidedOn:  timePtrToPgDate(record.Date),
+		Decision:   record.Decision,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upsert decision record: %w", err)
+	}
+	return nil
+}
+
+// DecisionRepository は core/wiki.DecisionReader と core/ask.DecisionLookup を実装する PostgreSQL リポジトリ。
+type DecisionRepository struct {
+	q sqlc.Querier
+}
+
+// NewDecisionRepository は新しい DecisionRepository を返す。
+func NewDecisionRepository(q sqlc.Querier) *DecisionRepository {
+	return &DecisionRepository{q: q}
+}
+
+var (
+	_ wiki.DecisionReader = (*DecisionRepository)(nil)
+	_ ask.DecisionLookup  = (*DecisionRepository)(nil)
+)
+
+func (r *DecisionRepository) ListDecisionRecords(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID]) ([]*decision.Record, error) {
+	rows, err := r.q.ListDecisionRecords(ctx, sqlc.ListDecisionRecordsParams{
+		ProductID:  UUIDOptionToPgtype(productID),
+		SnapshotID: UUIDOptionToPgtype(snapshotID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list decision records: %w", err)
+	}
+
+	records := make([]*decision.Record, 0, len(rows))
+	for _, row := range rows {
+		records = append(records, &decision.Record{
+			ID:         PgtypeToUUID(row.ID),
+			SnapshotID: PgtypeToUUID(row.SnapshotID),
+			FileID:     PgtypeToUUID(row.FileID),
+			SourceName: row.SourceName,
+			FilePath:   row.FilePath,
+			Number:     PgtypeToIntPtr(row.Number),
+			Title:      row.Title,
+			Status:     decision.Status(row.Status),
+			StatusText: row.StatusText,
+			Date:       pgDateToTimePtr(row.DecidedOn),
+			Decision:   row.Decision,
+			CreatedAt:  PgtypeToTime(row.CreatedAt),
+		})
+	}
+	return records, nil
+}
+
+func (r *DecisionRepository) DecisionStatuses(ctx context.Context, fileIDs []uuid.UUID) (map[uuid.UUID]decision.Status, error) {
+	statuses := make(map[uuid.UUID]decision.Status)
+	if len(fileIDs) == 0 {
+		return statuses, nil
+	}
+
+	rows, err := r.q.ListDecisionStatusesByFileIDs(ctx, uuidsParam(fileIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list decision statuses: %w", err)
+	}
+	for _, row := range rows {
+		statuses[PgtypeToUUID(row.FileID)] = decision.Status(row.Status)
+	}
+	return statuses, nil
+}
+
+func timePtrToPgDate(t *time.Time) pgtype.Date {
+	if t == nil {
+		return pgtype.Date{Valid: false}
+	}
+	return pgtype.Date{Time: *t, Valid: true}
+}
+
+func pgDateToTimePtr(d pgtype.Date) *time.Time {
+	if !d.Valid {
+		return nil
+	}
+	return &d.Time
+}
@@ -0,0 +1,87 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+
+	"github.com/jinford/dev-rag/internal/core/ask"
+	"github.com/jinford/dev-rag/internal/core/discussion"
+	"github.com/jinford/dev-rag/internal/infra/postgres/sqlc"
+)
+
+// UpsertDiscussion は議論のメタデータを保存する（同じファイルの記録は上書きする）
+func (r *Repository) UpsertDiscussion(ctx context.Context, record *discussion.Record) error {
+	paths := record.Paths
+	if paths == nil {
+		paths = []string{}
+	}
+	err := r.q.UpsertDiscussion(ctx, sqlc.UpsertDiscussionParams{
+		SnapshotID:     UUIDToPgtype(record.SnapshotID),
+		FileID:         UUIDToPgtype(record.FileID),
+		FilePath:       record.FilePath,
+		Kind:           string(record.Kind),
+		Number:         int32(record.Number),
+		Title:          record.Title,
+		State:          record.State,
+		Url:            record.URL,
+		Summary:        record.Summary,
+		Paths:          paths,
+		LastActivityAt: TimePtrToPgtype(record.UpdatedAt),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upsert discussion: %w", err)
+	}
+	return nil
+}
+
+// DiscussionRepository は core/ask.DiscussionLookup を実装する PostgreSQL リポジトリ。
+type DiscussionRepository struct {
+	q sqlc.Querier
+}
+
+// NewDiscussionRepository は新しい DiscussionRepository を返す。
+func NewDiscussionRepository(q sqlc.Querier) *DiscussionRepository {
+	return &DiscussionRepository{q: q}
+}
+
+var _ ask.DiscussionLookup = (*DiscussionRepository)(nil)
+
+func (r *DiscussionRepository) DiscussionsForPaths(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID], paths []string, limit int) ([]*discussion.Record, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+
+	rows, err := r.q.ListDiscussionsByPaths(ctx, sqlc.ListDiscussionsByPathsParams{
+		ProductID:  UUIDOptionToPgtype(productID),
+		SnapshotID: UUIDOptionToPgtype(snapshotID),
+		Paths:      paths,
+		MaxResults: int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list discussions by paths: %w", err)
+	}
+
+	records := make([]*discussion.Record, 0, len(rows))
+	for _, row := range rows {
+		records = append(records, &discussion.Record{
+			ID:         PgtypeToUUID(row.ID),
+			SnapshotID: PgtypeToUUID(row.SnapshotID),
+			FileID:     PgtypeToUUID(row.FileID),
+			SourceName: row.SourceName,
+			FilePath:   row.FilePath,
+			Kind:       discussion.Kind(row.Kind),
+			Number:     int(row.Number),
+			Title:      row.Title,
+			State:      row.State,
+			URL:        row.Url,
+			Summary:    row.Summary,
+			Paths:      row.Paths,
+			UpdatedAt:  PgtypeToTimePtr(row.LastActivityAt),
+			CreatedAt:  PgtypeToTime(row.CreatedAt),
+		})
+	}
+	return records, nil
+}
@@ -0,0 +1,58 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/jinford/dev-rag/internal/core/ingestion"
+	"github.com/jinford/dev-rag/internal/infra/postgres/sqlc"
+)
+
+// RecordEmbeddingFailures は Embedding を生成できなかったチャンクを記録する（記録済みの場合は失敗回数を加算する）
+func (r *Repository) RecordEmbeddingFailures(ctx context.Context, failures []*ingestion.EmbeddingFailure) error {
+	for _, f := range failures {
+		err := r.q.UpsertEmbeddingFailure(ctx, sqlc.UpsertEmbeddingFailureParams{
+			ChunkID: UUIDToPgtype(f.ChunkID),
+			Error:   f.Error,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to upsert embedding failure: %w", err)
+		}
+	}
+	return nil
+}
+
+// ListEmbeddingFailures はプロダクト内で Embedding を生成できていないチャンクの記録を本文とともに取得する
+func (r *Repository) ListEmbeddingFailures(ctx context.Context, productID uuid.UUID) ([]*ingestion.EmbeddingFailure, error) {
+	rows, err := r.q.ListEmbeddingFailuresByProduct(ctx, UUIDToPgtype(productID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list embedding failures: %w", err)
+	}
+
+	failures := make([]*ingestion.EmbeddingFailure, 0, len(rows))
+	for _, row := range rows {
+		failures = append(failures, &ingestion.EmbeddingFailure{
+			ChunkID:      PgtypeToUUID(row.ChunkID),
+			Content:      row.Content,
+			FilePath:     row.FilePath,
+			SourceName:   row.SourceName,
+			Error:        row.Error,
+			Attempts:     int(row.Attempts),
+			LastFailedAt: PgtypeToTime(row.LastFailedAt),
+		})
+	}
+	return failures, nil
+}
+
+// DeleteEmbeddingFailures は Embedding を生成できたチャンクの記録を削除する
+func (r *Repository) DeleteEmbeddingFailures(ctx context.Context, chunkIDs []uuid.UUID) error {
+	if len(chunkIDs) == 0 {
+		return nil
+	}
+	if err := r.q.DeleteEmbeddingFailures(ctx, uuidsParam(chunkIDs)); err != nil {
+		return fmt.Errorf("failed to delete embedding failures: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/samber/mo"
+
+	"github.com/jinford/dev-rag/internal/core/ingestion"
+	"github.com/jinford/dev-rag/internal/infra/postgres/sqlc"
+)
+
+// EmbeddingModelRepository は core/ingestion.EmbeddingModelRepository を実装する PostgreSQL リポジトリ。
+type EmbeddingModelRepository struct {
+	q sqlc.Querier
+}
+
+// NewEmbeddingModelRepository は新しい EmbeddingModelRepository を返す。
+func NewEmbeddingModelRepository(q sqlc.Querier) *EmbeddingModelRepository {
+	return &EmbeddingModelRepository{q: q}
+}
+
+var _ ingestion.EmbeddingModelRepository = (*EmbeddingModelRepository)(nil)
+
+func (r *EmbeddingModelRepository) GetEmbeddingModel(ctx context.Context, model string) (mo.Option[*ingestion.EmbeddingModel], error) {
+	row, err := r.q.GetRegisteredEmbeddingModel(ctx, model)
+	if err != nil {
+		if err == pgx.ErrNoRows || err == sql.ErrNoRows {
+			return mo.None[*ingestion.EmbeddingModel](), nil
+		}
+		return mo.None[*ingestion.EmbeddingModel](), fmt.Errorf("failed to get embedding model: %w", err)
+	}
+	return mo.Some(&ingestion.EmbeddingModel{
+		Model:             row.Model,
+		Provider:          row.Provider,
+		Dimension:         int(row.Dimension),
+		VariableDimension: row.VariableDimension,
+		CreatedAt:         PgtypeToTime(row.CreatedAt),
+	}), nil
+}
+
+func (r *EmbeddingModelRepository) ListEmbeddingModels(ctx context.Context) ([]*ingestion.EmbeddingModel, error) {
+	rows, err := r.q.ListRegisteredEmbeddingModels(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list embedding models: %w", err)
+	}
+
+	models := make([]*ingestion.EmbeddingModel, 0, len(rows))
+	for _, row := range rows {
+		models = append(models, &ingestion.EmbeddingModel{
+			Model:             row.Model,
+			Provider:          row.Provider,
+			Dimension:         int(row.Dimension),
+			VariableDimension: row.VariableDimension,
+			EmbeddingCount:    row.EmbeddingCount,
+			CreatedAt:         PgtypeToTime(row.CreatedAt),
+		})
+	}
+	return models, nil
+}
+
+func (r *EmbeddingModelRepository) UpsertEmbeddingModel(ctx context.Context, m *ingestion.EmbeddingModel) error {
+	if err := r.q.UpsertRegisteredEmbeddingModel(ctx, sqlc.UpsertRegisteredEmbeddingModelParams{
+		Model:             m.Model,
+		Provider:          m.Provider,
+		Dimension:         int32(m.Dimension),
+		VariableDimension: m.VariableDimension,
+	}); err != nil {
+		return fmt.Errorf("failed to upsert embedding model: %w", err)
+	}
+	return nil
+}
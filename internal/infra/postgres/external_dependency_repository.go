@@ -0,0 +1,118 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+
+	"github.com/jinford/dev-rag/internal/core/thirdparty"
+	"github.com/jinford/dev-rag/internal/infra/postgres/sqlc"
+)
+
+// ReplaceExternalDependencies はスナップショットの外部の依存関係を置き換える（既存の依存関係は削除する）
+func (r *Repository) ReplaceExternalDependencies(ctx context.Context, snapshotID uuid.UUID, deps []*thirdparty.Dependency) error {
+	if err := r.q.DeleteExternalDependenciesBySnapshot(ctx, UUIDToPgtype(snapshotID)); err != nil {
+		return fmt.Errorf("failed to delete external dependencies: %w", err)
+	}
+	for _, d := range deps {
+		err := r.q.CreateExternalDependency(ctx, sqlc.CreateExternalDependencyParams{
+			SnapshotID:   UUIDToPgtype(snapshotID),
+			ManifestPath: d.ManifestPath,
+			Ecosystem:    string(d.Ecosystem),
+			Name:         d.Name,
+			Requirement:  d.Requirement,
+			Kind:         string(d.Kind),
+			Vendored:     d.Vendored,
+			Location:     d.Location,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create external dependency: %w", err)
+		}
+	}
+	return nil
+}
+
+// ExternalDependencyRepository は core/thirdparty.Repository を実装する PostgreSQL リポジトリ。
+type ExternalDependencyRepository struct {
+	q sqlc.Querier
+}
+
+// NewExternalDependencyRepository は新しい ExternalDependencyRepository を返す。
+func NewExternalDependencyRepository(q sqlc.Querier) *ExternalDependencyRepository {
+	return &ExternalDependencyRepository{q: q}
+}
+
+var _ thirdparty.Repository = (*ExternalDependencyRepository)(nil)
+
+func (r *ExternalDependencyRepository) ListDependencies(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID]) ([]*thirdparty.Dependency, error) {
+	rows, err := r.q.ListExternalDependencies(ctx, sqlc.ListExternalDependenciesParams{
+		ProductID:  UUIDOptionToPgtype(productID),
+		SnapshotID: UUIDOptionToPgtype(snapshotID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list external dependencies: %w", err)
+	}
+
+	deps := make([]*thirdparty.Dependency, 0, len(rows))
+	for _, row := range rows {
+		deps = append(deps, &thirdparty.Dependency{
+			SnapshotID:   PgtypeToUUID(row.SnapshotID),
+			SourceName:   row.SourceName,
+			Version:      row.VersionIdentifier,
+			ManifestPath: row.ManifestPath,
+			Ecosystem:    thirdparty.Ecosystem(row.Ecosystem),
+			Name:         row.Name,
+			Requirement:  row.Requirement,
+			Kind:         thirdparty.Kind(row.Kind),
+			Vendored:     row.Vendored,
+			Location:     row.Location,
+		})
+	}
+	return deps, nil
+}
+
+func (r *ExternalDependencyRepository) ListSnapshotPairs(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID]) ([]*thirdparty.SnapshotPair, error) {
+	rows, err := r.q.ListExternalDependencySnapshotPairs(ctx, sqlc.ListExternalDependencySnapshotPairsParams{
+		ProductID:  UUIDOptionToPgtype(productID),
+		SnapshotID: UUIDOptionToPgtype(snapshotID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshot pairs: %w", err)
+	}
+
+	pairs := make([]*thirdparty.SnapshotPair, 0, len(rows))
+	for _, row := range rows {
+		pairs = append(pairs, &thirdparty.SnapshotPair{
+			SourceName:     row.SourceName,
+			FromSnapshotID: PgtypeToUUID(row.FromSnapshotID),
+			FromVersion:    row.FromVersion,
+			ToSnapshotID:   PgtypeToUUID(row.ToSnapshotID),
+			ToVersion:      row.ToVersion,
+		})
+	}
+	return pairs, nil
+}
+
+func (r *ExternalDependencyRepository) ListSnapshotDependencies(ctx context.Context, snapshotID uuid.UUID) ([]*thirdparty.Dependency, error) {
+	rows, err := r.q.ListSnapshotExternalDependencies(ctx, UUIDToPgtype(snapshotID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshot external dependencies: %w", err)
+	}
+
+	deps := make([]*thirdparty.Dependency, 0, len(rows))
+	for _, row := range rows {
+		deps = append(deps, &thirdparty.Dependency{
+			SnapshotID:   PgtypeToUUID(row.SnapshotID),
+			ManifestPath: row.ManifestPath,
+			Ecosystem:    thirdparty.Ecosystem(row.Ecosystem),
+			Name:         row.Name,
+			Requirement:  row.Requirement,
+			Kind:         thirdparty.Kind(row.Kind),
+			Vendored:     row.Vendored,
+			Location:     row.Location,
+		})
+	}
+	return deps, nil
+}
@@ -0,0 +1,60 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/samber/mo"
+
+	"github.com/jinford/dev-rag/internal/core/ingestion"
+	"github.com/jinford/dev-rag/internal/infra/postgres/sqlc"
+)
+
+// SaveFileBlob はファイルの内容を content_hash ごとに1回だけ保存する（保存済みの場合は何もしない）
+func (r *Repository) SaveFileBlob(ctx context.Context, contentHash string, content []byte) error {
+	err := r.q.InsertFileBlob(ctx, sqlc.InsertFileBlobParams{
+		ContentHash: contentHash,
+		Content:     content,
+		Size:        int64(len(content)),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to insert file blob: %w", err)
+	}
+	return nil
+}
+
+// GetFileBlob は content_hash のファイルの内容を取得する
+func (r *Repository) GetFileBlob(ctx context.Context, contentHash string) (mo.Option[[]byte], error) {
+	content, err := r.q.GetFileBlob(ctx, contentHash)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, sql.ErrNoRows) {
+			return mo.None[[]byte](), nil
+		}
+		return mo.None[[]byte](), fmt.Errorf("failed to get file blob: %w", err)
+	}
+	return mo.Some(content), nil
+}
+
+// DeleteUnreferencedFileBlobs は参照するファイルがなくなった内容を削除し、削除した件数を返す
+func (r *Repository) DeleteUnreferencedFileBlobs(ctx context.Context) (int, error) {
+	deleted, err := r.q.DeleteUnreferencedFileBlobs(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete unreferenced file blobs: %w", err)
+	}
+	return int(deleted), nil
+}
+
+// GetFileBlobStats はファイルの内容の保存件数と合計サイズを返す
+func (r *Repository) GetFileBlobStats(ctx context.Context) (*ingestion.FileBlobStats, error) {
+	row, err := r.q.GetFileBlobStats(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file blob stats: %w", err)
+	}
+	return &ingestion.FileBlobStats{
+		Count:     int(row.BlobCount),
+		TotalSize: row.TotalSize,
+	}, nil
+}
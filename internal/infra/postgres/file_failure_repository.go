@@ -0,0 +1,74 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/jinford/dev-rag/internal/core/ingestion"
+	"github.com/jinford/dev-rag/internal/infra/postgres/sqlc"
+)
+
+// RecordFileFailure はインデックス化に失敗したファイルを内容とともに記録する（記録済みの場合は失敗回数を加算する）
+func (r *Repository) RecordFileFailure(ctx context.Context, failure *ingestion.FileFailure) error {
+	err := r.q.UpsertFileFailure(ctx, sqlc.UpsertFileFailureParams{
+		SnapshotID:  UUIDToPgtype(failure.SnapshotID),
+		FilePath:    failure.Path,
+		Stage:       string(failure.Stage),
+		Error:       failure.Error,
+		Content:     failure.Document.Content,
+		ContentHash: failure.Document.ContentHash,
+		Size:        failure.Document.Size,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upsert file failure: %w", err)
+	}
+	return nil
+}
+
+// ListFileFailures はプロダクト内でインデックス化に失敗したファイルの記録を内容とともに取得する
+func (r *Repository) ListFileFailures(ctx context.Context, productID uuid.UUID) ([]*ingestion.FileFailure, error) {
+	rows, err := r.q.ListFileFailuresByProduct(ctx, UUIDToPgtype(productID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list file failures: %w", err)
+	}
+
+	failures := make([]*ingestion.FileFailure, 0, len(rows))
+	for _, row := range rows {
+		failures = append(failures, &ingestion.FileFailure{
+			SnapshotID:        PgtypeToUUID(row.SnapshotID),
+			ProductName:       row.ProductName,
+			SourceName:        row.SourceName,
+			VersionIdentifier: row.VersionIdentifier,
+			Path:              row.FilePath,
+			Stage:             ingestion.FileFailureStage(row.Stage),
+			Error:             row.Error,
+			Attempts:          int(row.Attempts),
+			FirstFailedAt:     PgtypeToTime(row.FirstFailedAt),
+			LastFailedAt:      PgtypeToTime(row.LastFailedAt),
+			Document: &ingestion.SourceDocument{
+				Path:        row.FilePath,
+				Content:     row.Content,
+				Size:        row.Size,
+				ContentHash: row.ContentHash,
+			},
+		})
+	}
+	return failures, nil
+}
+
+// DeleteResolvedFileFailures はスナップショットの記録のうち failedPaths 以外を削除し、削除した件数を返す
+func (r *Repository) DeleteResolvedFileFailures(ctx context.Context, snapshotID uuid.UUID, failedPaths []string) (int, error) {
+	if failedPaths == nil {
+		failedPaths = []string{}
+	}
+	deleted, err := r.q.DeleteResolvedFileFailures(ctx, sqlc.DeleteResolvedFileFailuresParams{
+		SnapshotID:  UUIDToPgtype(snapshotID),
+		FailedPaths: failedPaths,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete resolved file failures: %w", err)
+	}
+	return int(deleted), nil
+}
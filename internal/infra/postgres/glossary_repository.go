@@ -0,0 +1,94 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/jinford/dev-rag/internal/core/glossary"
+	"github.com/jinford/dev-rag/internal/infra/postgres/sqlc"
+)
+
+// GlossaryRepository は core/glossary.Repository を実装する PostgreSQL リポジトリ。
+type GlossaryRepository struct {
+	q sqlc.Querier
+}
+
+// NewGlossaryRepository は新しい GlossaryRepository を返す。
+func NewGlossaryRepository(q sqlc.Querier) *GlossaryRepository {
+	return &GlossaryRepository{q: q}
+}
+
+var _ glossary.Repository = (*GlossaryRepository)(nil)
+
+func (r *GlossaryRepository) ListSourceTexts(ctx context.Context, productID uuid.UUID, maxChars, limit int) ([]*glossary.SourceText, error) {
+	rows, err := r.q.ListGlossarySourceChunks(ctx, sqlc.ListGlossarySourceChunksParams{
+		ProductID: UUIDToPgtype(productID),
+		MaxChars:  int32(maxChars),
+		RowLimit:  int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list glossary source chunks: %w", err)
+	}
+
+	texts := make([]*glossary.SourceText, 0, len(rows))
+	for _, row := range rows {
+		texts = append(texts, &glossary.SourceText{FilePath: row.Path, Text: row.Text})
+	}
+	return texts, nil
+}
+
+func (r *GlossaryRepository) ListTerms(ctx context.Context, productID uuid.UUID) ([]*glossary.Term, error) {
+	rows, err := r.q.ListGlossaryTerms(ctx, UUIDToPgtype(productID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list glossary terms: %w", err)
+	}
+
+	terms := make([]*glossary.Term, 0, len(rows))
+	for _, row := range rows {
+		terms = append(terms, convertGlossaryTerm(row))
+	}
+	return terms, nil
+}
+
+func (r *GlossaryRepository) SaveTerm(ctx context.Context, term *glossary.Term) (*glossary.Term, error) {
+	row, err := r.q.UpsertGlossaryTerm(ctx, sqlc.UpsertGlossaryTermParams{
+		ProductID:      UUIDToPgtype(term.ProductID),
+		Term:           term.Term,
+		NormalizedTerm: glossary.NormalizeTerm(term.Term),
+		Kind:           string(term.Kind),
+		Definition:     term.Definition,
+		Aliases:        stringsParam(term.Aliases),
+		SourcePaths:    stringsParam(term.SourcePaths),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save glossary term: %w", err)
+	}
+	return convertGlossaryTerm(row), nil
+}
+
+func (r *GlossaryRepository) DeleteTermsExcept(ctx context.Context, productID uuid.UUID, keep []string) (int, error) {
+	deleted, err := r.q.DeleteGlossaryTermsExcept(ctx, sqlc.DeleteGlossaryTermsExceptParams{
+		ProductID: UUIDToPgtype(productID),
+		Keep:      stringsParam(keep),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete glossary terms: %w", err)
+	}
+	return int(deleted), nil
+}
+
+func convertGlossaryTerm(row sqlc.Glossary) *glossary.Term {
+	return &glossary.Term{
+		ID:          PgtypeToUUID(row.ID),
+		ProductID:   PgtypeToUUID(row.ProductID),
+		Term:        row.Term,
+		Kind:        glossary.Kind(row.Kind),
+		Definition:  row.Definition,
+		Aliases:     row.Aliases,
+		SourcePaths: row.SourcePaths,
+		CreatedAt:   PgtypeToTime(row.CreatedAt),
+		UpdatedAt:   PgtypeToTime(row.UpdatedAt),
+	}
+}
@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/jinford/dev-rag/internal/core/deplink"
+	"github.com/jinford/dev-rag/internal/infra/postgres/sqlc"
+)
+
+// ReplaceGoModules はスナップショットの Go モジュールを置き換える（既存のモジュールは削除する）
+func (r *Repository) ReplaceGoModules(ctx context.Context, snapshotID uuid.UUID, modules []*deplink.Module) error {
+	if err := r.q.DeleteGoModulesBySnapshot(ctx, UUIDToPgtype(snapshotID)); err != nil {
+		return fmt.Errorf("failed to delete go modules: %w", err)
+	}
+	for _, m := range modules {
+		err := r.q.CreateGoModule(ctx, sqlc.CreateGoModuleParams{
+			SnapshotID: UUIDToPgtype(snapshotID),
+			ModulePath: m.Path,
+			Dir:        m.Dir,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create go module: %w", err)
+		}
+	}
+	return nil
+}
+
+// ListProductGoModules はプロダクトの各ソースの最新スナップショットに含まれる Go モジュールを取得する
+func (r *Repository) ListProductGoModules(ctx context.Context, productID uuid.UUID) ([]*deplink.Module, error) {
+	rows, err := r.q.ListProductGoModules(ctx, UUIDToPgtype(productID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list go modules: %w", err)
+	}
+
+	modules := make([]*deplink.Module, 0, len(rows))
+	for _, row := range rows {
+		modules = append(modules, &deplink.Module{
+			SnapshotID: PgtypeToUUID(row.SnapshotID),
+			Path:       row.ModulePath,
+			Dir:        row.Dir,
+		})
+	}
+	return modules, nil
+}
+
+// ListProductGoLinkChunks はプロダクトの各ソースの最新スナップショットから依存関係の解決に使用する Go のチャンクを取得する
+func (r *Repository) ListProductGoLinkChunks(ctx context.Context, productID uuid.UUID) ([]*deplink.Chunk, error) {
+	rows, err := r.q.ListProductGoLinkChunks(ctx, UUIDToPgtype(productID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list go chunks: %w", err)
+	}
+
+	chunks := make([]*deplink.Chunk, 0, len(rows))
+	for _, row := range rows {
+		chunks = append(chunks, &deplink.Chunk{
+			ID:               PgtypeToUUID(row.ID),
+			SnapshotID:       PgtypeToUUID(row.SnapshotID),
+			FilePath:         row.Path,
+			Type:             row.ChunkType.String,
+			Name:             row.ChunkName,
+			ParentName:       row.ParentName.String,
+			Imports:          StringSliceFromJSONB(row.Imports),
+			InternalCalls:    StringSliceFromJSONB(row.InternalCalls),
+			ExternalCalls:    StringSliceFromJSONB(row.ExternalCalls),
+			TypeDependencies: StringSliceFromJSONB(row.TypeDependencies),
+		})
+	}
+	return chunks, nil
+}
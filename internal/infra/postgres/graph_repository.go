@@ -0,0 +1,146 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/samber/mo"
+
+	"github.com/jinford/dev-rag/internal/core/graph"
+	"github.com/jinford/dev-rag/internal/core/wiki"
+	"github.com/jinford/dev-rag/internal/infra/postgres/sqlc"
+)
+
+// DependencyGraphRepository は core/wiki.DependencyGraphReader と core/graph.Reader を実装する PostgreSQL リポジトリ。
+type DependencyGraphRepository struct {
+	q sqlc.Querier
+}
+
+// NewDependencyGraphRepository は新しい DependencyGraphRepository を返す。
+func NewDependencyGraphRepository(q sqlc.Querier) *DependencyGraphRepository {
+	return &DependencyGraphRepository{q: q}
+}
+
+var (
+	_ wiki.DependencyGraphReader = (*DependencyGraphRepository)(nil)
+	_ graph.Reader               = (*DependencyGraphRepository)(nil)
+)
+
+func (r *DependencyGraphRepository) ListFileDependencies(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID], limit int) ([]*wiki.FileDependency, error) {
+	rows, err := r.q.ListFileDependencyEdges(ctx, sqlc.ListFileDependencyEdgesParams{
+		ProductID:  UUIDOptionToPgtype(productID),
+		SnapshotID: UUIDOptionToPgtype(snapshotID),
+		RowLimit:   int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list file dependencies: %w", err)
+	}
+
+	deps := make([]*wiki.FileDependency, 0, len(rows))
+	for _, row := range rows {
+		deps = append(deps, &wiki.FileDependency{
+			FromPath: row.FromPath,
+			ToPath:   row.ToPath,
+			DepType:  row.DepType,
+			Count:    int(row.EdgeCount),
+		})
+	}
+	return deps, nil
+}
+
+func (r *DependencyGraphRepository) ListCallGraph(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID], nodeLimit int) ([]*wiki.CallEdge, error) {
+	rows, err := r.q.ListTopChunkCallEdges(ctx, sqlc.ListTopChunkCallEdgesParams{
+		ProductID:  UUIDOptionToPgtype(productID),
+		SnapshotID: UUIDOptionToPgtype(snapshotID),
+		NodeLimit:  int32(nodeLimit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list call graph: %w", err)
+	}
+
+	edges := make([]*wiki.CallEdge, 0, len(rows))
+	for _, row := range rows {
+		edges = append(edges, &wiki.CallEdge{
+			FromChunkID: PgtypeToUUID(row.FromChunkID),
+			FromName:    qualifiedName(row.FromParent, row.FromName),
+			FromPath:    row.FromPath,
+			ToChunkID:   PgtypeToUUID(row.ToChunkID),
+			ToName:      qualifiedName(row.ToParent, row.ToName),
+			ToPath:      row.ToPath,
+		})
+	}
+	return edges, nil
+}
+
+func (r *DependencyGraphRepository) ReadGraph(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID], filter graph.Filter) (*graph.Graph, error) {
+	params := sqlc.ListDependencyGraphEdgesParams{
+		ProductID:  UUIDOptionToPgtype(productID),
+		SnapshotID: UUIDOptionToPgtype(snapshotID),
+		PathPrefix: StringToNullableText(filter.PathPrefix),
+	}
+	if filter.MinImportance != nil {
+		params.MinImportance = pgtype.Float8{Float64: *filter.MinImportance, Valid: true}
+	}
+	rows, err := r.q.ListDependencyGraphEdges(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dependency graph edges: %w", err)
+	}
+
+	g := &graph.Graph{}
+	seen := make(map[uuid.UUID]bool)
+	addNode := func(n *graph.Node) {
+		if !seen[n.ID] {
+			seen[n.ID] = true
+			g.Nodes = append(g.Nodes, n)
+		}
+	}
+	for _, row := range rows {
+		from := &graph.Node{
+			ID:         PgtypeToUUID(row.FromChunkID),
+			SourceName: row.FromSourceName,
+			FilePath:   row.FromPath,
+			Name:       graphNodeName(row.FromParent, row.FromName),
+			Type:       row.FromType.String,
+			StartLine:  int(row.FromStartLine),
+			EndLine:    int(row.FromEndLine),
+			Importance: PgtypeToFloat64Ptr(row.FromImportance),
+		}
+		to := &graph.Node{
+			ID:         PgtypeToUUID(row.ToChunkID),
+			SourceName: row.ToSourceName,
+			FilePath:   row.ToPath,
+			Name:       graphNodeName(row.ToParent, row.ToName),
+			Type:       row.ToType.String,
+			StartLine:  int(row.ToStartLine),
+			EndLine:    int(row.ToEndLine),
+			Importance: PgtypeToFloat64Ptr(row.ToImportance),
+		}
+		addNode(from)
+		addNode(to)
+		g.Edges = append(g.Edges, &graph.Edge{
+			From:    from.ID,
+			To:      to.ID,
+			DepType: row.DepType,
+			Symbol:  row.Symbol.String,
+		})
+	}
+	return g, nil
+}
+
+// graphNodeName はチャンク名（メソッドは型名で修飾）を返す（名前がないチャンクは空）
+func graphNodeName(parent, name pgtype.Text) string {
+	if !name.Valid {
+		return ""
+	}
+	return qualifiedName(parent, name.String)
+}
+
+// qualifiedName はメソッドの場合に型名を付けた名前を返す
+func qualifiedName(parent pgtype.Text, name string) string {
+	if parent.Valid && parent.String != "" {
+		return parent.String + "." + name
+	}
+	return name
+}
@@ -0,0 +1,55 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+
+	"github.com/jinford/dev-rag/internal/core/hotspot"
+	"github.com/jinford/dev-rag/internal/infra/postgres/sqlc"
+)
+
+// HotspotRepository は core/hotspot.Reader を実装する PostgreSQL リポジトリ。
+type HotspotRepository struct {
+	q sqlc.Querier
+}
+
+// NewHotspotRepository は新しい HotspotRepository を返す。
+func NewHotspotRepository(q sqlc.Querier) *HotspotRepository {
+	return &HotspotRepository{q: q}
+}
+
+var _ hotspot.Reader = (*HotspotRepository)(nil)
+
+func (r *HotspotRepository) ListCandidates(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID], filter hotspot.Filter) ([]*hotspot.Candidate, error) {
+	rows, err := r.q.ListHotspotCandidates(ctx, sqlc.ListHotspotCandidatesParams{
+		ProductID:    UUIDOptionToPgtype(productID),
+		SnapshotID:   UUIDOptionToPgtype(snapshotID),
+		PathPrefix:   StringToNullableText(filter.PathPrefix),
+		IncludeTests: filter.IncludeTests,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list hotspot candidates: %w", err)
+	}
+
+	candidates := make([]*hotspot.Candidate, 0, len(rows))
+	for _, row := range rows {
+		candidates = append(candidates, &hotspot.Candidate{
+			SourceName:     row.SourceName,
+			FilePath:       row.Path,
+			Type:           row.ChunkType.String,
+			Name:           qualifiedName(row.ParentName, row.ChunkName.String),
+			StartLine:      int(row.StartLine),
+			EndLine:        int(row.EndLine),
+			Complexity:     PgtypeToIntPtr(row.CyclomaticComplexity),
+			LinesOfCode:    PgtypeToIntPtr(row.LinesOfCode),
+			CommentRatio:   row.CommentRatio,
+			EditCount:      int(row.EditCount),
+			TestStatements: PgtypeToIntPtr(row.TestStatements),
+			TestCovered:    PgtypeToInt(row.TestCoveredStatements),
+		})
+	}
+	return candidates, nil
+}
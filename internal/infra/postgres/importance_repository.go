@@ -0,0 +1,97 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/jinford/dev-rag/internal/core/importance"
+	"github.com/jinford/dev-rag/internal/infra/postgres/sqlc"
+)
+
+// importanceBatchSize は重要度スコアを1回のクエリで保存するチャンク数
+const importanceBatchSize = 5000
+
+// ImportanceRepository は core/importance.Repository を実装する PostgreSQL リポジトリ。
+type ImportanceRepository struct {
+	q sqlc.Querier
+}
+
+// NewImportanceRepository は新しい ImportanceRepository を返す。
+func NewImportanceRepository(q sqlc.Querier) *ImportanceRepository {
+	return &ImportanceRepository{q: q}
+}
+
+var _ importance.Repository = (*ImportanceRepository)(nil)
+
+func (r *ImportanceRepository) ListImportanceNodes(ctx context.Context, productID uuid.UUID) ([]importance.Node, error) {
+	rows, err := r.q.ListImportanceNodes(ctx, UUIDToPgtype(productID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list importance nodes: %w", err)
+	}
+
+	nodes := make([]importance.Node, 0, len(rows))
+	for _, row := range rows {
+		nodes = append(nodes, importance.Node{
+			ChunkID:   PgtypeToUUID(row.ID),
+			EditCount: int(row.EditCount),
+		})
+	}
+	return nodes, nil
+}
+
+func (r *ImportanceRepository) ListImportanceEdges(ctx context.Context, productID uuid.UUID) ([]importance.Edge, error) {
+	rows, err := r.q.ListImportanceEdges(ctx, UUIDToPgtype(productID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list importance edges: %w", err)
+	}
+
+	edges := make([]importance.Edge, 0, len(rows))
+	for _, row := range rows {
+		edges = append(edges, importance.Edge{
+			From:    PgtypeToUUID(row.FromChunkID),
+			To:      PgtypeToUUID(row.ToChunkID),
+			DepType: row.DepType,
+		})
+	}
+	return edges, nil
+}
+
+// SaveImportanceScores はスコアの内訳とチャンクの importance_score を importanceBatchSize 件ごとにまとめて保存する
+// チャンクごとにクエリを発行すると大規模なプロダクト（数十万チャンク）で往復の回数が支配的になるため、配列で渡して1回のクエリで更新する
+func (r *ImportanceRepository) SaveImportanceScores(ctx context.Context, scores []importance.Score) error {
+	for start := 0; start < len(scores); start += importanceBatchSize {
+		batch := scores[start:min(start+importanceBatchSize, len(scores))]
+
+		params := sqlc.BatchUpsertChunkImportanceParams{
+			ChunkIds:    make([]pgtype.UUID, 0, len(batch)),
+			Algorithms:  make([]string, 0, len(batch)),
+			GraphScores: make([]float64, 0, len(batch)),
+			EditScores:  make([]float64, 0, len(batch)),
+			FinalScores: make([]float64, 0, len(batch)),
+			EditCounts:  make([]int32, 0, len(batch)),
+		}
+		for _, s := range batch {
+			params.ChunkIds = append(params.ChunkIds, UUIDToPgtype(s.ChunkID))
+			params.Algorithms = append(params.Algorithms, string(s.Algorithm))
+			params.GraphScores = append(params.GraphScores, s.GraphScore)
+			params.EditScores = append(params.EditScores, s.EditScore)
+			params.FinalScores = append(params.FinalScores, s.FinalScore)
+			params.EditCounts = append(params.EditCounts, int32(s.EditCount))
+		}
+		if err := r.q.BatchUpsertChunkImportance(ctx, params); err != nil {
+			return fmt.Errorf("failed to upsert chunk importance: %w", err)
+		}
+
+		err := r.q.BatchUpdateChunkImportanceScores(ctx, sqlc.BatchUpdateChunkImportanceScoresParams{
+			Ids:    params.ChunkIds,
+			Scores: params.FinalScores,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update chunk importance scores: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/jinford/dev-rag/internal/core/ingestion"
+	"github.com/jinford/dev-rag/internal/infra/postgres/sqlc"
+)
+
+// GetSnapshotIndexHealth はスナップショットの件数・トークン数とレベル・種類・ドメイン・Embeddingモデル別のチャンク数を集計する
+func (r *Repository) GetSnapshotIndexHealth(ctx context.Context, snapshotID uuid.UUID, tokenLimit int) (*ingestion.SnapshotIndexHealth, error) {
+	row, err := r.q.GetSnapshotIndexHealth(ctx, sqlc.GetSnapshotIndexHealthParams{
+		TokenLimit: int32(tokenLimit),
+		SnapshotID: UUIDToPgtype(snapshotID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get snapshot index health: %w", err)
+	}
+
+	breakdown, err := r.q.ListSnapshotChunkBreakdown(ctx, UUIDToPgtype(snapshotID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshot chunk breakdown: %w", err)
+	}
+
+	health := &ingestion.SnapshotIndexHealth{
+		FileCount:           int(row.FileCount),
+		ChunkCount:          int(row.ChunkCount),
+		EmbeddingCount:      int(row.EmbeddingCount),
+		AvgTokens:           row.AvgTokens,
+		MaxTokens:           int(row.MaxTokens),
+		OrphanChunkCount:    int(row.OrphanChunkCount),
+		OversizedChunkCount: int(row.OversizedChunkCount),
+		TokenLimit:          tokenLimit,
+		ByLevel:             []*ingestion.ChunkCountByKey{},
+		ByType:              []*ingestion.ChunkCountByKey{},
+		ByDomain:            []*ingestion.ChunkCountByKey{},
+		ByEmbeddingModel:    []*ingestion.ChunkCountByKey{},
+	}
+	for _, b := range breakdown {
+		count := &ingestion.ChunkCountByKey{Key: b.Key, ChunkCount: int(b.ChunkCount)}
+		switch b.Dimension {
+		case "level":
+			health.ByLevel = append(health.ByLevel, count)
+		case "type":
+			health.ByType = append(health.ByType, count)
+		case "domain":
+			health.ByDomain = append(health.ByDomain, count)
+		case "embedding_model":
+			health.ByEmbeddingModel = append(health.ByEmbeddingModel, count)
+		}
+	}
+	return health, nil
+}
@@ -0,0 +1,128 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/samber/mo"
+
+	"github.com/jinford/dev-rag/internal/core/ingestion"
+	"github.com/jinford/dev-rag/internal/infra/postgres/sqlc"
+)
+
+// IndexRunRepository は core/ingestion.IndexRunRepository を実装する PostgreSQL リポジトリ。
+type IndexRunRepository struct {
+	q sqlc.Querier
+}
+
+// NewIndexRunRepository は新しい IndexRunRepository を返す。
+func NewIndexRunRepository(q sqlc.Querier) *IndexRunRepository {
+	return &IndexRunRepository{q: q}
+}
+
+var _ ingestion.IndexRunRepository = (*IndexRunRepository)(nil)
+
+func (r *IndexRunRepository) StartIndexRun(ctx context.Context, productID, sourceID uuid.UUID, ref string, forceInit bool) (uuid.UUID, error) {
+	row, err := r.q.CreateIndexRun(ctx, sqlc.CreateIndexRunParams{
+		ProductID: UUIDToPgtype(productID),
+		SourceID:  UUIDToPgtype(sourceID),
+		Ref:       StringToNullableText(ref),
+		ForceInit: forceInit,
+	})
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to create index run: %w", err)
+	}
+	return PgtypeToUUID(row.ID), nil
+}
+
+func (r *IndexRunRepository) FinishIndexRun(ctx context.Context, runID uuid.UUID, result ingestion.IndexRunResult) error {
+	var snapshotID *uuid.UUID
+	if result.SnapshotID != uuid.Nil {
+		snapshotID = &result.SnapshotID
+	}
+	err := r.q.FinishIndexRun(ctx, sqlc.FinishIndexRunParams{
+		ID:                UUIDToPgtype(runID),
+		Status:            string(result.Status),
+		SnapshotID:        UUIDPtrToPgtype(snapshotID),
+		Ref:               StringToNullableText(result.Ref),
+		VersionIdentifier: StringToNullableText(result.VersionIdentifier),
+		FilesProcessed:    int32(result.FilesProcessed),
+		Chunks:            int32(result.Chunks),
+		FailedFiles:       int32(result.FailedFiles),
+		FailedEmbeddings:  int32(result.FailedEmbeddings),
+		InputTokens:       result.InputTokens,
+		OutputTokens:      result.OutputTokens,
+		CostUsd:           result.CostUSD,
+		Error:             StringToNullableText(result.Error),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to finish index run: %w", err)
+	}
+	return nil
+}
+
+func (r *IndexRunRepository) GetIndexRun(ctx context.Context, runID uuid.UUID) (mo.Option[*ingestion.IndexRun], error) {
+	row, err := r.q.GetIndexRun(ctx, UUIDToPgtype(runID))
+	if err != nil {
+		if err == pgx.ErrNoRows || err == sql.ErrNoRows {
+			return mo.None[*ingestion.IndexRun](), nil
+		}
+		return mo.None[*ingestion.IndexRun](), fmt.Errorf("failed to get index run: %w", err)
+	}
+	// GetIndexRun と ListIndexRuns は同じカラムを返す
+	return mo.Some(indexRunFromRow(sqlc.ListIndexRunsRow(row))), nil
+}
+
+func (r *IndexRunRepository) ListIndexRuns(ctx context.Context, filter ingestion.IndexRunFilter) ([]*ingestion.IndexRun, error) {
+	var productID *uuid.UUID
+	if filter.ProductID != uuid.Nil {
+		productID = &filter.ProductID
+	}
+	rows, err := r.q.ListIndexRuns(ctx, sqlc.ListIndexRunsParams{
+		ProductID:  UUIDPtrToPgtype(productID),
+		SourceName: StringToNullableText(filter.SourceName),
+		Status:     StringToNullableText(string(filter.Status)),
+		RowLimit:   int32(filter.Limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list index runs: %w", err)
+	}
+
+	runs := make([]*ingestion.IndexRun, 0, len(rows))
+	for _, row := range rows {
+		runs = append(runs, indexRunFromRow(row))
+	}
+	return runs, nil
+}
+
+func indexRunFromRow(row sqlc.ListIndexRunsRow) *ingestion.IndexRun {
+	run := &ingestion.IndexRun{
+		ID:                PgtypeToUUID(row.ID),
+		ProductID:         PgtypeToUUID(row.ProductID),
+		ProductName:       row.ProductName,
+		SourceID:          PgtypeToUUID(row.SourceID),
+		SourceName:        row.SourceName,
+		SnapshotID:        PgtypeToUUIDPtr(row.SnapshotID),
+		Ref:               row.Ref.String,
+		VersionIdentifier: row.VersionIdentifier.String,
+		Status:            ingestion.IndexRunStatus(row.Status),
+		ForceInit:         row.ForceInit,
+		FilesProcessed:    int(row.FilesProcessed),
+		Chunks:            int(row.Chunks),
+		FailedFiles:       int(row.FailedFiles),
+		FailedEmbeddings:  int(row.FailedEmbeddings),
+		InputTokens:       row.InputTokens,
+		OutputTokens:      row.OutputTokens,
+		CostUSD:           row.CostUsd,
+		Error:             row.Error.String,
+		StartedAt:         PgtypeToTime(row.StartedAt),
+		FinishedAt:        PgtypeToTimePtr(row.FinishedAt),
+	}
+	if row.DurationMs.Valid {
+		run.DurationMs = &row.DurationMs.Int64
+	}
+	return run
+}
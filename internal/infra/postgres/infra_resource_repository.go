@@ -0,0 +1,87 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+
+	"github.com/jinford/dev-rag/internal/core/ask"
+	"github.com/jinford/dev-rag/internal/core/infraspec"
+	"github.com/jinford/dev-rag/internal/core/wiki"
+	"github.com/jinford/dev-rag/internal/infra/postgres/sqlc"
+)
+
+// ReplaceInfraResources はファイルのデプロイ構成のリソースを置き換える（既存のリソースは削除する）
+func (r *Repository) ReplaceInfraResources(ctx context.Context, fileID uuid.UUID, resources []*infraspec.Resource) error {
+	if err := r.q.DeleteInfraResourcesByFile(ctx, UUIDToPgtype(fileID)); err != nil {
+		return fmt.Errorf("failed to delete infra resources: %w", err)
+	}
+	for _, resource := range resources {
+		spec, err := json.Marshal(resource.Spec)
+		if err != nil {
+			return fmt.Errorf("failed to marshal infra resource spec: %w", err)
+		}
+		err = r.q.CreateInfraResource(ctx, sqlc.CreateInfraResourceParams{
+			SnapshotID: UUIDToPgtype(resource.SnapshotID),
+			FileID:     UUIDToPgtype(fileID),
+			FilePath:   resource.FilePath,
+			Format:     string(resource.Format),
+			Kind:       resource.Kind,
+			Name:       resource.Name,
+			Namespace:  resource.Namespace,
+			Spec:       spec,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create infra resource: %w", err)
+		}
+	}
+	return nil
+}
+
+// InfraResourceRepository は core/wiki.InfraResourceReader と core/ask.InfraLookup を実装する PostgreSQL リポジトリ。
+type InfraResourceRepository struct {
+	q sqlc.Querier
+}
+
+// NewInfraResourceRepository は新しい InfraResourceRepository を返す。
+func NewInfraResourceRepository(q sqlc.Querier) *InfraResourceRepository {
+	return &InfraResourceRepository{q: q}
+}
+
+var (
+	_ wiki.InfraResourceReader = (*InfraResourceRepository)(nil)
+	_ ask.InfraLookup          = (*InfraResourceRepository)(nil)
+)
+
+func (r *InfraResourceRepository) ListInfraResources(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID]) ([]*infraspec.Resource, error) {
+	rows, err := r.q.ListInfraResources(ctx, sqlc.ListInfraResourcesParams{
+		ProductID:  UUIDOptionToPgtype(productID),
+		SnapshotID: UUIDOptionToPgtype(snapshotID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list infra resources: %w", err)
+	}
+
+	resources := make([]*infraspec.Resource, 0, len(rows))
+	for _, row := range rows {
+		resource := &infraspec.Resource{
+			ID:         PgtypeToUUID(row.ID),
+			SnapshotID: PgtypeToUUID(row.SnapshotID),
+			FileID:     PgtypeToUUID(row.FileID),
+			SourceName: row.SourceName,
+			FilePath:   row.FilePath,
+			Format:     infraspec.Format(row.Format),
+			Kind:       row.Kind,
+			Name:       row.Name,
+			Namespace:  row.Namespace,
+		}
+		if err := json.Unmarshal(row.Spec, &resource.Spec); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal infra resource spec: %w", err)
+		}
+		resources = append(resources, resource)
+	}
+	return resources, nil
+}
@@ -0,0 +1,173 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	pgvector "github.com/pgvector/pgvector-go"
+
+	"github.com/jinford/dev-rag/internal/core/integrity"
+	"github.com/jinford/dev-rag/internal/infra/postgres/sqlc"
+)
+
+// IntegrityRepository は core/integrity.Repository を実装する PostgreSQL リポジトリ。
+type IntegrityRepository struct {
+	q sqlc.Querier
+}
+
+// NewIntegrityRepository は新しい IntegrityRepository を返す。
+func NewIntegrityRepository(q sqlc.Querier) *IntegrityRepository {
+	return &IntegrityRepository{q: q}
+}
+
+var _ integrity.Repository = (*IntegrityRepository)(nil)
+
+func (r *IntegrityRepository) ListOrphanChunks(ctx context.Context) ([]*integrity.OrphanChunk, error) {
+	rows, err := r.q.ListOrphanChunks(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list orphan chunks: %w", err)
+	}
+
+	chunks := make([]*integrity.OrphanChunk, 0, len(rows))
+	for _, row := range rows {
+		chunks = append(chunks, &integrity.OrphanChunk{
+			ChunkID:   PgtypeToUUID(row.ID),
+			FilePath:  row.Path,
+			StartLine: int(row.StartLine),
+			EndLine:   int(row.EndLine),
+			Content:   row.Content,
+		})
+	}
+	return chunks, nil
+}
+
+func (r *IntegrityRepository) ListEmptyFiles(ctx context.Context) ([]*integrity.EmptyFile, error) {
+	rows, err := r.q.ListEmptyFiles(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list empty files: %w", err)
+	}
+
+	files := make([]*integrity.EmptyFile, 0, len(rows))
+	for _, row := range rows {
+		files = append(files, &integrity.EmptyFile{
+			FileID:     PgtypeToUUID(row.ID),
+			SnapshotID: PgtypeToUUID(row.SnapshotID),
+			Path:       row.Path,
+		})
+	}
+	return files, nil
+}
+
+func (r *IntegrityRepository) ListSnapshotFileMismatches(ctx context.Context) ([]*integrity.SnapshotFileMismatch, error) {
+	rows, err := r.q.ListSnapshotFileMismatches(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshot file mismatches: %w", err)
+	}
+
+	mismatches := make([]*integrity.SnapshotFileMismatch, 0, len(rows))
+	for _, row := range rows {
+		mismatches = append(mismatches, &integrity.SnapshotFileMismatch{
+			SnapshotID: PgtypeToUUID(row.SnapshotID),
+			FilePath:   row.FilePath,
+			Indexed:    row.Indexed,
+			HasChunks:  row.HasChunks,
+		})
+	}
+	return mismatches, nil
+}
+
+func (r *IntegrityRepository) ListStaleLatestSources(ctx context.Context) ([]*integrity.StaleLatestSource, error) {
+	rows, err := r.q.ListStaleLatestSources(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stale latest sources: %w", err)
+	}
+
+	sources := make([]*integrity.StaleLatestSource, 0, len(rows))
+	for _, row := range rows {
+		sources = append(sources, &integrity.StaleLatestSource{
+			SourceID:    PgtypeToUUID(row.SourceID),
+			SourceName:  row.SourceName,
+			StaleChunks: int(row.StaleChunks),
+		})
+	}
+	return sources, nil
+}
+
+func (r *IntegrityRepository) ListDanglingDependencies(ctx context.Context) ([]*integrity.DanglingDependency, error) {
+	rows, err := r.q.ListDanglingDependencies(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dangling dependencies: %w", err)
+	}
+
+	deps := make([]*integrity.DanglingDependency, 0, len(rows))
+	for _, row := range rows {
+		deps = append(deps, &integrity.DanglingDependency{
+			ID:           PgtypeToUUID(row.ID),
+			DepType:      row.DepType,
+			Symbol:       row.Symbol,
+			FromFilePath: row.FromPath,
+			ToFilePath:   row.ToPath,
+		})
+	}
+	return deps, nil
+}
+
+func (r *IntegrityRepository) SaveEmbeddings(ctx context.Context, embeddings []*integrity.Embedding) error {
+	if len(embeddings) == 0 {
+		return nil
+	}
+
+	rows := make([]sqlc.CreateEmbeddingBatchParams, 0, len(embeddings))
+	for _, e := range embeddings {
+		rows = append(rows, sqlc.CreateEmbeddingBatchParams{
+			ChunkID: UUIDToPgtype(e.ChunkID),
+			Vector:  pgvector.NewVector(e.Vector),
+			Model:   e.Model,
+		})
+	}
+
+	var batchErr error
+	r.q.CreateEmbeddingBatch(ctx, rows).Exec(func(i int, err error) {
+		if err != nil && batchErr == nil {
+			batchErr = fmt.Errorf("failed to insert embedding at index %d: %w", i, err)
+		}
+	})
+	if batchErr != nil {
+		return fmt.Errorf("failed to save embeddings: %w", batchErr)
+	}
+	return nil
+}
+
+func (r *IntegrityRepository) DeleteFile(ctx context.Context, fileID uuid.UUID) error {
+	if err := r.q.DeleteFile(ctx, UUIDToPgtype(fileID)); err != nil {
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+	return nil
+}
+
+func (r *IntegrityRepository) SetSnapshotFileIndexed(ctx context.Context, snapshotID uuid.UUID, filePath string, indexed bool) error {
+	err := r.q.UpdateSnapshotFileIndexed(ctx, sqlc.UpdateSnapshotFileIndexedParams{
+		SnapshotID: UUIDToPgtype(snapshotID),
+		FilePath:   filePath,
+		Indexed:    indexed,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update snapshot file indexed status: %w", err)
+	}
+	return nil
+}
+
+func (r *IntegrityRepository) RefreshLatestChunks(ctx context.Context, sourceID uuid.UUID) error {
+	if err := r.q.RefreshLatestChunks(ctx, UUIDToPgtype(sourceID)); err != nil {
+		return fmt.Errorf("failed to refresh latest chunks: %w", err)
+	}
+	return nil
+}
+
+func (r *IntegrityRepository) DeleteDependency(ctx context.Context, id uuid.UUID) error {
+	if err := r.q.DeleteDependency(ctx, UUIDToPgtype(id)); err != nil {
+		return fmt.Errorf("failed to delete dependency: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+
+	"github.com/jinford/dev-rag/internal/core/license"
+	"github.com/jinford/dev-rag/internal/infra/postgres/sqlc"
+)
+
+// ReplaceLicenseFindings はスナップショットで検出したライセンスを置き換える（既存の検出結果は削除する）
+func (r *Repository) ReplaceLicenseFindings(ctx context.Context, snapshotID uuid.UUID, findings []*license.Finding) error {
+	if err := r.q.DeleteLicenseFindingsBySnapshot(ctx, UUIDToPgtype(snapshotID)); err != nil {
+		return fmt.Errorf("failed to delete license findings: %w", err)
+	}
+	if len(findings) == 0 {
+		return nil
+	}
+
+	rows := make([]sqlc.CreateLicenseFindingsParams, 0, len(findings))
+	for _, f := range findings {
+		rows = append(rows, sqlc.CreateLicenseFindingsParams{
+			SnapshotID: UUIDToPgtype(snapshotID),
+			FilePath:   f.FilePath,
+			Kind:       string(f.Kind),
+			LicenseID:  f.LicenseID,
+			Category:   string(f.Category),
+			Evidence:   f.Evidence,
+		})
+	}
+	if _, err := r.q.CreateLicenseFindings(ctx, rows); err != nil {
+		return fmt.Errorf("failed to create license findings: %w", err)
+	}
+	return nil
+}
+
+// LicenseRepository は core/license.Reader を実装する PostgreSQL リポジトリ。
+type LicenseRepository struct {
+	q sqlc.Querier
+}
+
+// NewLicenseRepository は新しい LicenseRepository を返す。
+func NewLicenseRepository(q sqlc.Querier) *LicenseRepository {
+	return &LicenseRepository{q: q}
+}
+
+var _ license.Reader = (*LicenseRepository)(nil)
+
+func (r *LicenseRepository) ListFindings(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID]) ([]*license.Finding, error) {
+	rows, err := r.q.ListLicenseFindings(ctx, sqlc.ListLicenseFindingsParams{
+		ProductID:  UUIDOptionToPgtype(productID),
+		SnapshotID: UUIDOptionToPgtype(snapshotID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list license findings: %w", err)
+	}
+
+	findings := make([]*license.Finding, 0, len(rows))
+	for _, row := range rows {
+		findings = append(findings, &license.Finding{
+			SnapshotID: PgtypeToUUID(row.SnapshotID),
+			SourceName: row.SourceName,
+			FilePath:   row.FilePath,
+			Kind:       license.Kind(row.Kind),
+			LicenseID:  row.LicenseID,
+			Category:   license.Category(row.Category),
+			Evidence:   row.Evidence,
+		})
+	}
+	return findings, nil
+}
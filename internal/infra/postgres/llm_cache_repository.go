@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/samber/mo"
+
+	"github.com/jinford/dev-rag/internal/core/llm"
+	"github.com/jinford/dev-rag/internal/infra/postgres/sqlc"
+)
+
+// LLMCacheRepository は core/llm.CacheRepository を実装する PostgreSQL リポジトリ。
+type LLMCacheRepository struct {
+	q sqlc.Querier
+}
+
+// NewLLMCacheRepository は新しい LLMCacheRepository を返す。
+func NewLLMCacheRepository(q sqlc.Querier) *LLMCacheRepository {
+	return &LLMCacheRepository{q: q}
+}
+
+var _ llm.CacheRepository = (*LLMCacheRepository)(nil)
+
+func (r *LLMCacheRepository) Get(ctx context.Context, key string, notBefore time.Time) (mo.Option[string], error) {
+	response, err := r.q.HitLLMCache(ctx, sqlc.HitLLMCacheParams{
+		Key:       key,
+		NotBefore: TimeToPgtype(notBefore),
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows || err == sql.ErrNoRows {
+			return mo.None[string](), nil
+		}
+		return mo.None[string](), fmt.Errorf("failed to get llm cache: %w", err)
+	}
+	return mo.Some(response), nil
+}
+
+func (r *LLMCacheRepository) Put(ctx context.Context, entry *llm.CacheEntry) error {
+	if err := r.q.UpsertLLMCache(ctx, sqlc.UpsertLLMCacheParams{
+		Key:           entry.Key,
+		PromptName:    entry.PromptName,
+		PromptVersion: entry.PromptVersion,
+		Model:         entry.Model,
+		Response:      entry.Response,
+	}); err != nil {
+		return fmt.Errorf("failed to put llm cache: %w", err)
+	}
+	return nil
+}
+
+func (r *LLMCacheRepository) Stats(ctx context.Context) ([]*llm.CacheStat, error) {
+	rows, err := r.q.ListLLMCacheStats(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list llm cache stats: %w", err)
+	}
+
+	stats := make([]*llm.CacheStat, 0, len(rows))
+	for _, row := range rows {
+		stats = append(stats, &llm.CacheStat{
+			PromptName:    row.PromptName,
+			PromptVersion: row.PromptVersion,
+			Model:         row.Model,
+			Entries:       int(row.Entries),
+			Hits:          row.Hits,
+			ResponseBytes: row.ResponseBytes,
+			LastHitAt:     PgtypeToTimePtr(row.LastHitAt),
+		})
+	}
+	return stats, nil
+}
+
+func (r *LLMCacheRepository) Delete(ctx context.Context, promptName string, createdBefore time.Time) (int64, error) {
+	deleted, err := r.q.DeleteLLMCache(ctx, sqlc.DeleteLLMCacheParams{
+		PromptName:    pgtype.Text{String: promptName, Valid: promptName != ""},
+		CreatedBefore: TimeToPgtype(createdBefore),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete llm cache: %w", err)
+	}
+	return deleted, nil
+}
@@ -0,0 +1,75 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+
+	"github.com/jinford/dev-rag/internal/core/monorepo"
+	"github.com/jinford/dev-rag/internal/infra/postgres/sqlc"
+)
+
+// ReplaceMonorepoServices はスナップショットから検出したサービスを置き換える（既存のサービスは削除する）
+func (r *Repository) ReplaceMonorepoServices(ctx context.Context, snapshotID uuid.UUID, services []*monorepo.Service) error {
+	if err := r.q.DeleteMonorepoServicesBySnapshot(ctx, UUIDToPgtype(snapshotID)); err != nil {
+		return fmt.Errorf("failed to delete monorepo services: %w", err)
+	}
+	if len(services) == 0 {
+		return nil
+	}
+
+	rows := make([]sqlc.CreateMonorepoServicesParams, 0, len(services))
+	for _, s := range services {
+		rows = append(rows, sqlc.CreateMonorepoServicesParams{
+			SnapshotID:   UUIDToPgtype(snapshotID),
+			Path:         s.Path,
+			Name:         s.Name,
+			Kind:         string(s.Kind),
+			ManifestPath: s.ManifestPath,
+			Targets:      append([]string{}, s.Targets...),
+		})
+	}
+	if _, err := r.q.CreateMonorepoServices(ctx, rows); err != nil {
+		return fmt.Errorf("failed to create monorepo services: %w", err)
+	}
+	return nil
+}
+
+// MonorepoRepository は core/monorepo.Repository を実装する PostgreSQL リポジトリ。
+type MonorepoRepository struct {
+	q sqlc.Querier
+}
+
+// NewMonorepoRepository は新しい MonorepoRepository を返す。
+func NewMonorepoRepository(q sqlc.Querier) *MonorepoRepository {
+	return &MonorepoRepository{q: q}
+}
+
+var _ monorepo.Repository = (*MonorepoRepository)(nil)
+
+func (r *MonorepoRepository) ListServices(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID]) ([]*monorepo.Service, error) {
+	rows, err := r.q.ListMonorepoServices(ctx, sqlc.ListMonorepoServicesParams{
+		ProductID:  UUIDOptionToPgtype(productID),
+		SnapshotID: UUIDOptionToPgtype(snapshotID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list monorepo services: %w", err)
+	}
+
+	services := make([]*monorepo.Service, 0, len(rows))
+	for _, row := range rows {
+		services = append(services, &monorepo.Service{
+			SnapshotID:   PgtypeToUUID(row.SnapshotID),
+			SourceName:   row.SourceName,
+			Version:      row.VersionIdentifier,
+			Path:         row.Path,
+			Name:         row.Name,
+			Kind:         monorepo.Kind(row.Kind),
+			ManifestPath: row.ManifestPath,
+			Targets:      row.Targets,
+		})
+	}
+	return services, nil
+}
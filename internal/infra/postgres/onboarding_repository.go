@@ -0,0 +1,117 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+
+	"github.com/jinford/dev-rag/internal/core/wiki"
+	"github.com/jinford/dev-rag/internal/infra/postgres/sqlc"
+)
+
+// OnboardingRepository は core/wiki.OnboardingReader と core/wiki.RunbookReader を実装する PostgreSQL リポジトリ。
+type OnboardingRepository struct {
+	q sqlc.Querier
+}
+
+// NewOnboardingRepository は新しい OnboardingRepository を返す。
+func NewOnboardingRepository(q sqlc.Querier) *OnboardingRepository {
+	return &OnboardingRepository{q: q}
+}
+
+var (
+	_ wiki.OnboardingReader = (*OnboardingRepository)(nil)
+	_ wiki.RunbookReader    = (*OnboardingRepository)(nil)
+)
+
+func (r *OnboardingRepository) ListDirectoryCoverage(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID]) ([]*wiki.DirectoryCoverage, error) {
+	rows, err := r.q.ListDirectoryCoverage(ctx, sqlc.ListDirectoryCoverageParams{
+		ProductID:  UUIDOptionToPgtype(productID),
+		SnapshotID: UUIDOptionToPgtype(snapshotID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list directory coverage: %w", err)
+	}
+
+	coverage := make([]*wiki.DirectoryCoverage, 0, len(rows))
+	for _, row := range rows {
+		coverage = append(coverage, &wiki.DirectoryCoverage{
+			SourceName:   row.SourceName,
+			Directory:    row.Directory,
+			Domain:       row.Domain,
+			TotalFiles:   int(row.TotalFiles),
+			IndexedFiles: int(row.IndexedFiles),
+		})
+	}
+	return coverage, nil
+}
+
+func (r *OnboardingRepository) ListKeyFiles(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID], limit int) ([]*wiki.KeyFile, error) {
+	rows, err := r.q.ListKeyFiles(ctx, sqlc.ListKeyFilesParams{
+		ProductID:  UUIDOptionToPgtype(productID),
+		SnapshotID: UUIDOptionToPgtype(snapshotID),
+		RowLimit:   int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list key files: %w", err)
+	}
+
+	files := make([]*wiki.KeyFile, 0, len(rows))
+	for _, row := range rows {
+		files = append(files, &wiki.KeyFile{
+			SourceName: row.SourceName,
+			Path:       row.Path,
+			Language:   row.Language.String,
+			Domain:     row.Domain.String,
+			EntryPoint: row.IsEntryPoint,
+			Importance: row.Importance,
+			Owners:     row.Owners,
+			Summary:    row.Summary,
+		})
+	}
+	return files, nil
+}
+
+func (r *OnboardingRepository) ListBuildFileChunks(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID]) ([]*wiki.BuildFileChunk, error) {
+	rows, err := r.q.ListBuildFileChunks(ctx, sqlc.ListBuildFileChunksParams{
+		ProductID:  UUIDOptionToPgtype(productID),
+		SnapshotID: UUIDOptionToPgtype(snapshotID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list build file chunks: %w", err)
+	}
+
+	chunks := make([]*wiki.BuildFileChunk, 0, len(rows))
+	for _, row := range rows {
+		chunks = append(chunks, &wiki.BuildFileChunk{
+			SourceName: row.SourceName,
+			Path:       row.Path,
+			StartLine:  int(row.StartLine),
+			Content:    row.Content,
+		})
+	}
+	return chunks, nil
+}
+
+func (r *OnboardingRepository) ListOpsFileChunks(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID]) ([]*wiki.BuildFileChunk, error) {
+	rows, err := r.q.ListOpsFileChunks(ctx, sqlc.ListOpsFileChunksParams{
+		ProductID:  UUIDOptionToPgtype(productID),
+		SnapshotID: UUIDOptionToPgtype(snapshotID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ops file chunks: %w", err)
+	}
+
+	chunks := make([]*wiki.BuildFileChunk, 0, len(rows))
+	for _, row := range rows {
+		chunks = append(chunks, &wiki.BuildFileChunk{
+			SourceName: row.SourceName,
+			Path:       row.Path,
+			StartLine:  int(row.StartLine),
+			Content:    row.Content,
+		})
+	}
+	return chunks, nil
+}
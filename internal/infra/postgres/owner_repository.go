@@ -0,0 +1,96 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/jinford/dev-rag/internal/core/ask"
+	"github.com/jinford/dev-rag/internal/core/quality"
+	"github.com/jinford/dev-rag/internal/infra/postgres/sqlc"
+)
+
+// UpdateFileOwners はスナップショットのファイルに CODEOWNERS から判定した担当者を設定する（owners はファイルパスから担当者へのマップ）
+func (r *Repository) UpdateFileOwners(ctx context.Context, snapshotID uuid.UUID, owners map[string][]string) error {
+	if len(owners) == 0 {
+		return nil
+	}
+
+	paths := make([]string, 0, len(owners))
+	joined := make([]string, 0, len(owners))
+	for path, o := range owners {
+		paths = append(paths, path)
+		joined = append(joined, strings.Join(o, " "))
+	}
+	err := r.q.UpdateFileOwners(ctx, sqlc.UpdateFileOwnersParams{
+		SnapshotID: UUIDToPgtype(snapshotID),
+		Paths:      paths,
+		Owners:     joined,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update file owners: %w", err)
+	}
+	return nil
+}
+
+// OwnerRepository は core/ask.OwnerLookup と core/quality.CodeownersLookup を実装する PostgreSQL リポジトリ。
+type OwnerRepository struct {
+	q sqlc.Querier
+}
+
+// NewOwnerRepository は新しい OwnerRepository を返す。
+func NewOwnerRepository(q sqlc.Querier) *OwnerRepository {
+	return &OwnerRepository{q: q}
+}
+
+var (
+	_ ask.OwnerLookup          = (*OwnerRepository)(nil)
+	_ quality.CodeownersLookup = (*OwnerRepository)(nil)
+)
+
+func (r *OwnerRepository) OwnersForFiles(ctx context.Context, fileIDs []uuid.UUID) (map[uuid.UUID][]string, error) {
+	if len(fileIDs) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]pgtype.UUID, 0, len(fileIDs))
+	for _, id := range fileIDs {
+		ids = append(ids, UUIDToPgtype(id))
+	}
+	rows, err := r.q.ListFileOwners(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list file owners: %w", err)
+	}
+
+	owners := make(map[uuid.UUID][]string, len(rows))
+	for _, row := range rows {
+		owners[PgtypeToUUID(row.ID)] = row.Owners
+	}
+	return owners, nil
+}
+
+func (r *OwnerRepository) OwnersForPaths(ctx context.Context, productID uuid.UUID, paths []string) (map[string][]string, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+
+	rows, err := r.q.ListFileOwnersByPaths(ctx, sqlc.ListFileOwnersByPathsParams{
+		ProductID: UUIDToPgtype(productID),
+		Paths:     paths,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list file owners by paths: %w", err)
+	}
+
+	owners := make(map[string][]string, len(rows))
+	for _, row := range rows {
+		// 複数のソースにある場合は新しいスナップショットを優先する
+		if _, ok := owners[row.Path]; !ok {
+			owners[row.Path] = row.Owners
+		}
+	}
+	return owners, nil
+}
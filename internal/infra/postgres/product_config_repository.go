@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/jinford/dev-rag/internal/core/productconfig"
+	"github.com/jinford/dev-rag/internal/infra/postgres/sqlc"
+)
+
+// ProductConfigRepository は core/productconfig.Repository を実装する PostgreSQL リポジトリ。
+type ProductConfigRepository struct {
+	q sqlc.Querier
+}
+
+// NewProductConfigRepository は新しい ProductConfigRepository を返す。
+func NewProductConfigRepository(q sqlc.Querier) *ProductConfigRepository {
+	return &ProductConfigRepository{q: q}
+}
+
+var _ productconfig.Repository = (*ProductConfigRepository)(nil)
+
+func (r *ProductConfigRepository) List(ctx context.Context, productID uuid.UUID) ([]*productconfig.Entry, error) {
+	rows, err := r.q.ListProductConfigs(ctx, UUIDToPgtype(productID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list product configs: %w", err)
+	}
+
+	entries := make([]*productconfig.Entry, 0, len(rows))
+	for _, row := range rows {
+		entries = append(entries, convertProductConfig(row))
+	}
+	return entries, nil
+}
+
+func (r *ProductConfigRepository) Set(ctx context.Context, productID uuid.UUID, key, value string) (*productconfig.Entry, error) {
+	row, err := r.q.UpsertProductConfig(ctx, sqlc.UpsertProductConfigParams{
+		ProductID: UUIDToPgtype(productID),
+		Key:       key,
+		Value:     value,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set product config: %w", err)
+	}
+	return convertProductConfig(row), nil
+}
+
+func (r *ProductConfigRepository) Delete(ctx context.Context, productID uuid.UUID, key string) (bool, error) {
+	deleted, err := r.q.DeleteProductConfig(ctx, sqlc.DeleteProductConfigParams{
+		ProductID: UUIDToPgtype(productID),
+		Key:       key,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to delete product config: %w", err)
+	}
+	return deleted > 0, nil
+}
+
+func convertProductConfig(row sqlc.ProductConfig) *productconfig.Entry {
+	return &productconfig.Entry{
+		ProductID: PgtypeToUUID(row.ProductID),
+		Key:       row.Key,
+		Value:     row.Value,
+		UpdatedAt: PgtypeToTime(row.UpdatedAt),
+	}
+}
@@ -0,0 +1,79 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/samber/mo"
+
+	"github.com/jinford/dev-rag/internal/core/prompt"
+	"github.com/jinford/dev-rag/internal/infra/postgres/sqlc"
+)
+
+// PromptTemplateRepository は core/prompt.Repository を実装する PostgreSQL リポジトリ。
+type PromptTemplateRepository struct {
+	q sqlc.Querier
+}
+
+// NewPromptTemplateRepository は新しい PromptTemplateRepository を返す。
+func NewPromptTemplateRepository(q sqlc.Querier) *PromptTemplateRepository {
+	return &PromptTemplateRepository{q: q}
+}
+
+var _ prompt.Repository = (*PromptTemplateRepository)(nil)
+
+func (r *PromptTemplateRepository) Get(ctx context.Context, name string) (mo.Option[*prompt.Template], error) {
+	row, err := r.q.GetPromptTemplate(ctx, name)
+	if err != nil {
+		if err == pgx.ErrNoRows || err == sql.ErrNoRows {
+			return mo.None[*prompt.Template](), nil
+		}
+		return mo.None[*prompt.Template](), fmt.Errorf("failed to get prompt template: %w", err)
+	}
+	return mo.Some(convertPromptTemplate(row)), nil
+}
+
+func (r *PromptTemplateRepository) List(ctx context.Context) ([]*prompt.Template, error) {
+	rows, err := r.q.ListPromptTemplates(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list prompt templates: %w", err)
+	}
+
+	templates := make([]*prompt.Template, 0, len(rows))
+	for _, row := range rows {
+		templates = append(templates, convertPromptTemplate(row))
+	}
+	return templates, nil
+}
+
+func (r *PromptTemplateRepository) Set(ctx context.Context, name, version, body string) (*prompt.Template, error) {
+	row, err := r.q.UpsertPromptTemplate(ctx, sqlc.UpsertPromptTemplateParams{
+		Name:    name,
+		Version: version,
+		Body:    body,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set prompt template: %w", err)
+	}
+	return convertPromptTemplate(row), nil
+}
+
+func (r *PromptTemplateRepository) Delete(ctx context.Context, name string) (bool, error) {
+	deleted, err := r.q.DeletePromptTemplate(ctx, name)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete prompt template: %w", err)
+	}
+	return deleted > 0, nil
+}
+
+func convertPromptTemplate(row sqlc.PromptTemplate) *prompt.Template {
+	return &prompt.Template{
+		Name:      row.Name,
+		Version:   row.Version,
+		Body:      row.Body,
+		Source:    prompt.SourceDB,
+		UpdatedAt: PgtypeToTime(row.UpdatedAt),
+	}
+}
@@ -0,0 +1,189 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/jinford/dev-rag/internal/core/quality"
+	"github.com/jinford/dev-rag/internal/infra/postgres/sqlc"
+)
+
+// recentChangeLimit は ListRecentChanges で取得するコミット数の上限
+const recentChangeLimit = 100
+
+// QualityRepository は core/quality.Repository を実装する PostgreSQL リポジトリ。
+type QualityRepository struct {
+	q sqlc.Querier
+}
+
+// NewQualityRepository は新しい QualityRepository を返す。
+func NewQualityRepository(q sqlc.Querier) *QualityRepository {
+	return &QualityRepository{q: q}
+}
+
+var _ quality.Repository = (*QualityRepository)(nil)
+
+func (r *QualityRepository) CreateNote(ctx context.Context, note *quality.Note) (*quality.Note, error) {
+	var chunks []byte
+	if note.LinkedChunks != nil {
+		chunks, _ = json.Marshal(note.LinkedChunks)
+	}
+	row, err := r.q.CreateQualityNote(ctx, sqlc.CreateQualityNoteParams{
+		NoteID:       note.NoteID,
+		Severity:     string(note.Severity),
+		NoteText:     note.NoteText,
+		LinkedFiles:  JSONBFromStringSlice(note.LinkedFiles),
+		LinkedChunks: chunks,
+		Reviewer:     note.Reviewer,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create quality note: %w", err)
+	}
+	return convertQualityNote(row), nil
+}
+
+func (r *QualityRepository) ListNotes(ctx context.Context, filter quality.NoteFilter) ([]*quality.Note, error) {
+	rows, err := r.q.ListQualityNotes(ctx, sqlc.ListQualityNotesParams{
+		Status:      StringToNullableText(string(filter.Status)),
+		Severity:    StringToNullableText(string(filter.Severity)),
+		CreatedFrom: nullableTimestamp(filter.CreatedFrom),
+		CreatedTo:   nullableTimestamp(filter.CreatedTo),
+		RowLimit:    int32(filter.Limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list quality notes: %w", err)
+	}
+
+	notes := make([]*quality.Note, 0, len(rows))
+	for _, row := range rows {
+		notes = append(notes, convertQualityNote(row))
+	}
+	return notes, nil
+}
+
+func (r *QualityRepository) MaxNoteNumber(ctx context.Context, prefix string) (int, error) {
+	n, err := r.q.MaxQualityNoteNumber(ctx, prefix)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get max quality note number: %w", err)
+	}
+	return int(n), nil
+}
+
+func (r *QualityRepository) CreateAction(ctx context.Context, action *quality.Action) (*quality.Action, error) {
+	row, err := r.q.CreateActionBacklog(ctx, sqlc.CreateActionBacklogParams{
+		ActionID:           action.ActionID,
+		PromptVersion:      action.PromptVersion,
+		Priority:           string(action.Priority),
+		ActionType:         string(action.ActionType),
+		Title:              action.Title,
+		Description:        action.Description,
+		LinkedFiles:        JSONBFromStringSlice(action.LinkedFiles),
+		OwnerHint:          StringToNullableText(action.OwnerHint),
+		AcceptanceCriteria: action.AcceptanceCriteria,
+		Status:             string(action.Status),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create quality action: %w", err)
+	}
+	return convertActionBacklog(row), nil
+}
+
+func (r *QualityRepository) ListActions(ctx context.Context, filter quality.ActionFilter) ([]*quality.Action, error) {
+	rows, err := r.q.ListActionBacklog(ctx, sqlc.ListActionBacklogParams{
+		Status:   StringToNullableText(string(filter.Status)),
+		RowLimit: int32(filter.Limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list quality actions: %w", err)
+	}
+
+	actions := make([]*quality.Action, 0, len(rows))
+	for _, row := range rows {
+		actions = append(actions, convertActionBacklog(row))
+	}
+	return actions, nil
+}
+
+func (r *QualityRepository) MaxActionNumber(ctx context.Context, prefix string) (int, error) {
+	n, err := r.q.MaxActionNumber(ctx, prefix)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get max quality action number: %w", err)
+	}
+	return int(n), nil
+}
+
+func (r *QualityRepository) ListRecentChanges(ctx context.Context, productID uuid.UUID, paths []string, since time.Time) ([]*quality.RecentChange, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+
+	rows, err := r.q.ListRecentChangesByPaths(ctx, sqlc.ListRecentChangesByPathsParams{
+		ProductID: UUIDToPgtype(productID),
+		Since:     TimeToPgtype(since),
+		Paths:     paths,
+		RowLimit:  recentChangeLimit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list recent changes: %w", err)
+	}
+
+	changes := make([]*quality.RecentChange, 0, len(rows))
+	for _, row := range rows {
+		changes = append(changes, &quality.RecentChange{
+			Hash:         row.CommitHash,
+			FilesChanged: row.Paths,
+			MergedAt:     PgtypeToTime(row.MergedAt),
+		})
+	}
+	return changes, nil
+}
+
+// nullableTimestamp はゼロ値を NULL とする pgtype.Timestamp を返す
+func nullableTimestamp(t time.Time) pgtype.Timestamp {
+	if t.IsZero() {
+		return pgtype.Timestamp{}
+	}
+	return TimeToPgtype(t)
+}
+
+func convertQualityNote(row sqlc.QualityNote) *quality.Note {
+	var chunks []uuid.UUID
+	if row.LinkedChunks != nil {
+		_ = json.Unmarshal(row.LinkedChunks, &chunks)
+	}
+	return &quality.Note{
+		ID:           PgtypeToUUID(row.ID),
+		NoteID:       row.NoteID,
+		Severity:     quality.Severity(row.Severity),
+		NoteText:     row.NoteText,
+		LinkedFiles:  StringSliceFromJSONB(row.LinkedFiles),
+		LinkedChunks: chunks,
+		Reviewer:     row.Reviewer,
+		Status:       quality.NoteStatus(row.Status),
+		CreatedAt:    PgtypeToTime(row.CreatedAt),
+		ResolvedAt:   PgtypeToTimePtr(row.ResolvedAt),
+	}
+}
+
+func convertActionBacklog(row sqlc.ActionBacklog) *quality.Action {
+	return &quality.Action{
+		ID:                 PgtypeToUUID(row.ID),
+		ActionID:           row.ActionID,
+		PromptVersion:      row.PromptVersion,
+		Priority:           quality.Priority(row.Priority),
+		ActionType:         quality.ActionType(row.ActionType),
+		Title:              row.Title,
+		Description:        row.Description,
+		LinkedFiles:        StringSliceFromJSONB(row.LinkedFiles),
+		OwnerHint:          row.OwnerHint.String,
+		AcceptanceCriteria: row.AcceptanceCriteria,
+		Status:             quality.ActionStatus(row.Status),
+		CreatedAt:          PgtypeToTime(row.CreatedAt),
+		CompletedAt:        PgtypeToTimePtr(row.CompletedAt),
+	}
+}
@@ -0,0 +1,48 @@
+-- name: CreateAPIEndpoint :exec
+INSERT INTO api_endpoints (
+    snapshot_id,
+    file_id,
+    file_path,
+    kind,
+    service,
+    method,
+    path,
+    operation_id,
+    summary,
+    request_type,
+    response_type
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+);
+
+-- name: DeleteAPIEndpointsByFile :exec
+DELETE FROM api_endpoints
+WHERE file_id = $1;
+
+-- name: ListAPIEndpoints :many
+-- エンドポイントを、プロダクトまたはスナップショットの範囲で取得する
+-- product_id を指定した場合は各ソースの最新のインデックス済みスナップショットを対象とする
+-- keyword はパス・サービス・operationId・概要の部分一致（大文字小文字を区別しない）
+WITH scope_snapshots AS (
+    SELECT DISTINCT ON (ss.source_id) ss.id, s.name AS source_name
+    FROM source_snapshots ss
+    INNER JOIN sources s ON ss.source_id = s.id
+    WHERE ss.status = 'indexed'
+      AND (sqlc.narg(product_id)::uuid IS NULL OR s.product_id = sqlc.narg(product_id)::uuid)
+      AND (sqlc.narg(snapshot_id)::uuid IS NULL OR ss.id = sqlc.narg(snapshot_id)::uuid)
+    ORDER BY ss.source_id, ss.indexed_at DESC NULLS LAST, ss.created_at DESC
+)
+SELECT
+    ae.*,
+    sc.source_name::text AS source_name
+FROM api_endpoints ae
+INNER JOIN scope_snapshots sc ON ae.snapshot_id = sc.id
+WHERE (sqlc.narg(kind)::text IS NULL OR ae.kind = sqlc.narg(kind)::text)
+  AND (
+      sqlc.narg(keyword)::text IS NULL
+      OR ae.path ILIKE '%' || sqlc.narg(keyword)::text || '%'
+      OR ae.service ILIKE '%' || sqlc.narg(keyword)::text || '%'
+      OR ae.operation_id ILIKE '%' || sqlc.narg(keyword)::text || '%'
+      OR ae.summary ILIKE '%' || sqlc.narg(keyword)::text || '%'
+  )
+ORDER BY sc.source_name, ae.kind DESC, ae.service, ae.path, ae.method;
@@ -0,0 +1,55 @@
+-- name: HitAskCache :one
+-- 保存後に検索対象のスナップショットが変わった（再インデックスされた）回答は使用しない
+UPDATE ask_cache c
+SET hit_count = c.hit_count + 1,
+    last_hit_at = CURRENT_TIMESTAMP
+WHERE c.key = sqlc.arg(key)
+  AND c.created_at >= sqlc.arg(not_before)
+  AND c.scope_snapshot_ids = CASE
+      WHEN c.snapshot_id IS NOT NULL THEN ARRAY[c.snapshot_id]
+      ELSE (
+          SELECT COALESCE(array_agg(latest.id ORDER BY latest.id), '{}'::uuid[])
+          FROM (
+              SELECT DISTINCT ON (ss.source_id) ss.id
+              FROM source_snapshots ss
+              INNER JOIN sources s ON ss.source_id = s.id
+              WHERE ss.status = 'indexed'
+                AND s.product_id = c.product_id
+              ORDER BY ss.source_id, ss.indexed_at DESC NULLS LAST, ss.created_at DESC
+          ) latest
+      )
+  END
+RETURNING c.result;
+
+-- name: UpsertAskCache :exec
+-- snapshot_id を指定しない場合は、保存時のプロダクトの各ソースの最新のインデックス済みスナップショットを記録する
+INSERT INTO ask_cache (key, product_id, snapshot_id, scope_snapshot_ids, prompt_version, question, result)
+SELECT
+    sqlc.arg(key),
+    sqlc.narg(product_id)::uuid,
+    sqlc.narg(snapshot_id)::uuid,
+    CASE
+        WHEN sqlc.narg(snapshot_id)::uuid IS NOT NULL THEN ARRAY[sqlc.narg(snapshot_id)::uuid]
+        ELSE (
+            SELECT COALESCE(array_agg(latest.id ORDER BY latest.id), '{}'::uuid[])
+            FROM (
+                SELECT DISTINCT ON (ss.source_id) ss.id
+                FROM source_snapshots ss
+                INNER JOIN sources s ON ss.source_id = s.id
+                WHERE ss.status = 'indexed'
+                  AND s.product_id = sqlc.narg(product_id)::uuid
+                ORDER BY ss.source_id, ss.indexed_at DESC NULLS LAST, ss.created_at DESC
+            ) latest
+        )
+    END,
+    sqlc.arg(prompt_version),
+    sqlc.arg(question),
+    sqlc.arg(result)
+ON CONFLICT (key) DO UPDATE
+SET scope_snapshot_ids = EXCLUDED.scope_snapshot_ids,
+    prompt_version = EXCLUDED.prompt_version,
+    question = EXCLUDED.question,
+    result = EXCLUDED.result,
+    hit_count = 0,
+    created_at = CURRENT_TIMESTAMP,
+    last_hit_at = NULL;
@@ -0,0 +1,44 @@
+-- name: CreateAskAnswer :one
+INSERT INTO ask_answers (
+    product_id,
+    origin,
+    channel_id,
+    message_ts,
+    user_id,
+    query,
+    answer,
+    refused,
+    prompt_version
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
+)
+RETURNING *;
+
+-- name: GetAskAnswerByMessage :one
+SELECT * FROM ask_answers
+WHERE origin = $1 AND channel_id = $2 AND message_ts = $3;
+
+-- name: UpsertAskFeedback :exec
+INSERT INTO ask_feedback (answer_id, user_id, rating)
+VALUES ($1, $2, $3)
+ON CONFLICT (answer_id, user_id) DO UPDATE SET
+    rating = EXCLUDED.rating,
+    created_at = CURRENT_TIMESTAMP;
+
+-- name: DeleteAskFeedback :exec
+-- 取り消したリアクションと同じ評価の場合のみ削除する（👍 と 👎 を両方付けた後に片方を外した場合に備える）
+DELETE FROM ask_feedback
+WHERE answer_id = $1 AND user_id = $2 AND rating = $3;
+
+-- name: ListRatedAskAnswers :many
+-- 評価の付いた回答を評価の低い順に取得する
+SELECT
+    a.*,
+    COUNT(*) FILTER (WHERE f.rating > 0)::bigint AS up_count,
+    COUNT(*) FILTER (WHERE f.rating < 0)::bigint AS down_count
+FROM ask_answers a
+JOIN ask_feedback f ON f.answer_id = a.id
+WHERE a.product_id = $1
+GROUP BY a.id
+ORDER BY SUM(f.rating), a.created_at DESC
+LIMIT $2;
@@ -0,0 +1,95 @@
+-- name: UpsertChunkEmbeddingBatch :batchexec
+-- 追加のEmbeddingモデルで生成した Embedding を保存する（同じモデルで生成済みの場合は置き換える）
+INSERT INTO chunk_embeddings (chunk_id, model, vector)
+VALUES ($1, $2, $3)
+ON CONFLICT (chunk_id, model) DO UPDATE SET
+    vector = EXCLUDED.vector,
+    created_at = CURRENT_TIMESTAMP;
+
+-- name: ListChunksWithoutModelEmbedding :many
+-- 指定したモデルの Embedding がないチャンクを取得する（index embed-models で使用）
+-- content は Embedding の入力（切り詰めた embedding_context があればそれを優先）
+SELECT
+    c.id,
+    COALESCE(c.embedding_context, c.content)::text AS content
+FROM chunks c
+INNER JOIN files f ON c.file_id = f.id
+INNER JOIN source_snapshots ss ON f.snapshot_id = ss.id
+INNER JOIN sources s ON ss.source_id = s.id
+LEFT JOIN chunk_embeddings ce ON ce.chunk_id = c.id AND ce.model = sqlc.arg(model)::text
+WHERE s.product_id = sqlc.arg(product_id)
+  AND ce.chunk_id IS NULL
+ORDER BY c.id;
+
+-- name: SearchChunksByModel :many
+-- 追加のEmbeddingモデルの Embedding で検索する（モデルを選択した検索で使用）
+-- product_id / source_id / snapshot_id のうち指定されたもので範囲を限定する（snapshot_id 指定時は最新スナップショットの代わりに使用）
+-- 次元数がモデルごとに異なりHNSWインデックスを利用できないため、モデルで絞り込んだチャンクとの距離をすべて計算する
+-- 並べ替えは SearchChunksByProduct と同様に importance_weight と recency_weight で重み付けする
+WITH latest_snapshots AS (
+    SELECT DISTINCT ON (ss.source_id) ss.id, ss.source_id, s.product_id
+    FROM source_snapshots ss
+    INNER JOIN sources s ON ss.source_id = s.id
+    WHERE ss.status = 'indexed'
+      AND (sqlc.narg(snapshot_id)::uuid IS NULL OR ss.id = sqlc.narg(snapshot_id)::uuid)
+      AND (sqlc.narg(source_id)::uuid IS NULL OR ss.source_id = sqlc.narg(source_id)::uuid)
+      AND (sqlc.narg(product_id)::uuid IS NULL OR s.product_id = sqlc.narg(product_id)::uuid)
+    ORDER BY ss.source_id, ss.indexed_at DESC NULLS LAST, ss.created_at DESC
+),
+ranking AS (
+    -- 鮮度の重みは明示指定を優先し、なければプロダクトの設定を使用する
+    SELECT COALESCE(sqlc.narg(recency_weight)::float8, MAX(p.recency_weight), 0)::float8 AS recency_weight
+    FROM latest_snapshots ls
+    INNER JOIN products p ON ls.product_id = p.id
+),
+candidates AS (
+    SELECT
+        c.id AS chunk_id,
+        c.file_id,
+        f.path,
+        c.start_line,
+        c.end_line,
+        c.level,
+        c.breadcrumb,
+        c.page_start,
+        c.page_end,
+        c.content,
+        c.importance_score,
+        c.updated_at,
+        (1::float8 - (ce.vector <=> sqlc.arg(query_vector)::vector))::float8 AS similarity
+    FROM chunk_embeddings ce
+    INNER JOIN chunks c ON ce.chunk_id = c.id
+    INNER JOIN files f ON c.file_id = f.id
+    INNER JOIN latest_snapshots ls ON f.snapshot_id = ls.id
+    WHERE ce.model = sqlc.arg(model)::text
+      AND (sqlc.narg(path_prefix)::text IS NULL OR f.path LIKE (sqlc.narg(path_prefix)::text || '%'))
+      AND (cardinality(sqlc.arg(domains)::text[]) = 0 OR f.domain = ANY(sqlc.arg(domains)::text[]))
+      AND (cardinality(sqlc.arg(languages)::text[]) = 0 OR f.language = ANY(sqlc.arg(languages)::text[]))
+      AND (sqlc.narg(content_type)::text IS NULL OR f.content_type = sqlc.narg(content_type)::text)
+      AND (cardinality(sqlc.arg(file_ids)::uuid[]) = 0 OR c.file_id = ANY(sqlc.arg(file_ids)::uuid[]))
+    ORDER BY ce.vector <=> sqlc.arg(query_vector)::vector
+    LIMIT CASE
+        WHEN sqlc.arg(importance_weight)::float8 > 0 OR (SELECT recency_weight FROM ranking) > 0 THEN sqlc.arg(candidate_limit)::int
+        ELSE sqlc.arg(row_limit)::int
+    END
+)
+SELECT
+    cd.chunk_id,
+    cd.file_id,
+    cd.path,
+    cd.start_line,
+    cd.end_line,
+    cd.level,
+    cd.breadcrumb,
+    cd.page_start,
+    cd.page_end,
+    cd.content,
+    (((1::float8 - sqlc.arg(importance_weight)::float8) * cd.similarity
+        + sqlc.arg(importance_weight)::float8 * COALESCE(cd.importance_score, 0.5)::float8)
+        * (1::float8 - r.recency_weight + r.recency_weight * COALESCE(
+            power(0.5::float8, GREATEST(EXTRACT(EPOCH FROM (CURRENT_TIMESTAMP - cd.updated_at))::float8, 0) / 86400 / sqlc.arg(recency_half_life_days)::float8),
+            0.5)))::float8 AS score
+FROM candidates cd
+CROSS JOIN ranking r
+ORDER BY score DESC
+LIMIT sqlc.arg(row_limit);
@@ -58,3 +58,36 @@ WHERE parent_chunk_id = $1;
 -- name: DeleteChunkHierarchyByChild :exec
 DELETE FROM chunk_hierarchy
 WHERE child_chunk_id = $1;
+
+-- name: ListChunkHierarchyContexts :many
+-- プロンプトの階層的な組み立て用に、指定チャンクの所属ファイル・親チャンク・ファイル要約を取得する
+-- 親チャンクは chunk_hierarchy を優先し、未登録の場合は同一ファイル内で parent_name と行範囲が一致する上位レベルのチャンクとする
+SELECT
+    c.id AS chunk_id,
+    c.file_id,
+    c.level,
+    p.id AS parent_id,
+    COALESCE(p.start_line, 0)::int AS parent_start_line,
+    COALESCE(p.end_line, 0)::int AS parent_end_line,
+    COALESCE(p.content, '')::text AS parent_content,
+    s.content AS file_summary
+FROM chunks c
+JOIN files f ON f.id = c.file_id
+LEFT JOIN LATERAL (
+    SELECT pc.id, pc.start_line, pc.end_line, pc.content
+    FROM chunks pc
+    WHERE pc.file_id = c.file_id
+      AND pc.id <> c.id
+      AND pc.level < c.level
+      AND (
+          EXISTS (
+              SELECT 1 FROM chunk_hierarchy ch
+              WHERE ch.parent_chunk_id = pc.id AND ch.child_chunk_id = c.id
+          )
+          OR (pc.chunk_name = c.parent_name AND pc.start_line <= c.start_line AND pc.end_line >= c.end_line)
+      )
+    ORDER BY pc.level DESC
+    LIMIT 1
+) p ON c.level >= 3
+LEFT JOIN summaries s ON s.snapshot_id = f.snapshot_id AND s.summary_type = 'file' AND s.target_path = f.path
+WHERE c.id = ANY(sqlc.arg(chunk_ids)::uuid[]);
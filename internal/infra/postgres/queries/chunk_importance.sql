@@ -0,0 +1,98 @@
+-- name: BatchUpsertChunkImportance :exec
+-- チャンクの重要度の内訳を1回のクエリでまとめて保存する（各配列は同じ順序・同じ長さで、チャンクIDは重複させない）
+INSERT INTO chunk_importance (
+    chunk_id,
+    algorithm,
+    graph_score,
+    edit_score,
+    final_score,
+    edit_count,
+    calculated_at
+)
+SELECT
+    unnest(sqlc.arg(chunk_ids)::uuid[]),
+    unnest(sqlc.arg(algorithms)::text[]),
+    unnest(sqlc.arg(graph_scores)::float8[]),
+    unnest(sqlc.arg(edit_scores)::float8[]),
+    unnest(sqlc.arg(final_scores)::float8[]),
+    unnest(sqlc.arg(edit_counts)::int[]),
+    CURRENT_TIMESTAMP
+ON CONFLICT (chunk_id) DO UPDATE SET
+    algorithm = EXCLUDED.algorithm,
+    graph_score = EXCLUDED.graph_score,
+    edit_score = EXCLUDED.edit_score,
+    final_score = EXCLUDED.final_score,
+    edit_count = EXCLUDED.edit_count,
+    calculated_at = EXCLUDED.calculated_at;
+
+-- name: ListImportanceNodes :many
+-- プロダクトの各ソースの最新のインデックス済みスナップショットの名前を持つチャンクと、スナップショット間で内容が変更された回数を取得する
+-- 同一ソース・同一ファイル内で同じ関数/クラス名を持つチャンクを同一の系譜とみなす（ListChunkHistory と同じ）
+WITH scope_snapshots AS (
+    SELECT DISTINCT ON (ss.source_id) ss.id, ss.source_id
+    FROM source_snapshots ss
+    INNER JOIN sources s ON ss.source_id = s.id
+    WHERE ss.status = 'indexed'
+      AND s.product_id = sqlc.arg(product_id)::uuid
+    ORDER BY ss.source_id, ss.indexed_at DESC NULLS LAST, ss.created_at DESC
+),
+lineage AS (
+    SELECT
+        ss.source_id,
+        f.path,
+        c.chunk_name,
+        c.parent_name,
+        COUNT(DISTINCT c.content_hash) AS versions
+    FROM chunks c
+    INNER JOIN files f ON c.file_id = f.id
+    INNER JOIN source_snapshots ss ON f.snapshot_id = ss.id
+    WHERE ss.source_id IN (SELECT source_id FROM scope_snapshots)
+      AND c.chunk_name IS NOT NULL
+    GROUP BY ss.source_id, f.path, c.chunk_name, c.parent_name
+)
+SELECT
+    c.id,
+    GREATEST(COALESCE(l.versions, 1) - 1, 0)::int AS edit_count
+FROM chunks c
+INNER JOIN files f ON c.file_id = f.id
+INNER JOIN scope_snapshots sc ON f.snapshot_id = sc.id
+LEFT JOIN lineage l
+    ON l.source_id = sc.source_id
+   AND l.path = f.path
+   AND l.chunk_name = c.chunk_name
+   AND l.parent_name IS NOT DISTINCT FROM c.parent_name
+WHERE c.chunk_name IS NOT NULL
+ORDER BY c.id;
+
+-- name: ListImportanceEdges :many
+-- プロダクトの各ソースの最新のインデックス済みスナップショットのチャンク間の依存関係を取得する（ソースをまたぐものを含む）
+WITH scope_snapshots AS (
+    SELECT DISTINCT ON (ss.source_id) ss.id
+    FROM source_snapshots ss
+    INNER JOIN sources s ON ss.source_id = s.id
+    WHERE ss.status = 'indexed'
+      AND s.product_id = sqlc.arg(product_id)::uuid
+    ORDER BY ss.source_id, ss.indexed_at DESC NULLS LAST, ss.created_at DESC
+),
+scope_chunks AS (
+    SELECT c.id
+    FROM chunks c
+    INNER JOIN files f ON c.file_id = f.id
+    INNER JOIN scope_snapshots sc ON f.snapshot_id = sc.id
+)
+SELECT d.from_chunk_id, d.to_chunk_id, d.dep_type
+FROM chunk_dependencies d
+WHERE d.from_chunk_id IN (SELECT id FROM scope_chunks)
+  AND d.to_chunk_id IN (SELECT id FROM scope_chunks);
+
+-- name: GetChunkImportance :one
+SELECT
+    chunk_id,
+    algorithm,
+    graph_score::float8 AS graph_score,
+    edit_score::float8 AS edit_score,
+    final_score::float8 AS final_score,
+    edit_count,
+    calculated_at
+FROM chunk_importance
+WHERE chunk_id = $1;
@@ -6,20 +6,32 @@ INSERT INTO chunks (
     level, importance_score,
     standard_imports, external_imports, internal_calls, external_calls, type_dependencies,
     source_snapshot_id, git_commit_hash, author, updated_at, indexed_at,
-    file_version, is_latest, chunk_key
+    file_version, is_latest, chunk_key, embedding_truncated, breadcrumb, page_start, page_end, chunker_version
 )
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31, $32, $33)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31, $32, $33, $34, $35, $36, $37, $38)
 RETURNING *;
 
 -- name: GetChunk :one
 SELECT * FROM chunks
 WHERE id = $1;
 
+-- name: GetChunkByKey :one
+SELECT * FROM chunks
+WHERE chunk_key = $1;
+
 -- name: ListChunksByFile :many
 SELECT * FROM chunks
 WHERE file_id = $1
 ORDER BY ordinal;
 
+-- name: ListFileIDsWithOutdatedChunker :many
+-- スナップショットのファイルのうち、現在のチャンカー以外（バージョンの記録がないものを含む）で作成したチャンクがあるファイルを取得する
+SELECT DISTINCT c.file_id
+FROM chunks c
+INNER JOIN files f ON c.file_id = f.id
+WHERE f.snapshot_id = sqlc.arg(snapshot_id)
+  AND (c.chunker_version IS NULL OR NOT (c.chunker_version = ANY(sqlc.arg(current_versions)::text[])));
+
 -- name: ListChunksByOrdinalRange :many
 SELECT * FROM chunks
 WHERE file_id = $1 AND ordinal BETWEEN $2 AND $3
@@ -43,6 +55,15 @@ UPDATE chunks
 SET importance_score = $2
 WHERE id = $1;
 
+-- name: BatchUpdateChunkImportanceScores :exec
+-- チャンクの重要度スコアを1回のクエリでまとめて更新する（ID とスコアは同じ順序・同じ長さの配列で渡す）
+UPDATE chunks c
+SET importance_score = v.score
+FROM (
+    SELECT unnest(sqlc.arg(ids)::uuid[]) AS id, unnest(sqlc.arg(scores)::float8[]) AS score
+) v
+WHERE c.id = v.id;
+
 -- インデックス鮮度の監視用クエリ
 
 -- name: GetChunksWithGitInfo :many
@@ -85,3 +106,167 @@ FROM chunks c
 WHERE c.is_latest = true
   AND c.git_commit_hash IS NOT NULL
   AND c.indexed_at < NOW() - INTERVAL '1 day' * $1;
+
+-- チャンクの系譜（バージョン履歴）
+
+-- name: GetChunkLineageAnchor :one
+-- chunk_key から系譜をたどる起点となるチャンクの識別情報を取得
+SELECT
+    c.id,
+    c.ordinal,
+    c.chunk_name,
+    c.parent_name,
+    f.path AS file_path,
+    ss.source_id
+FROM chunks c
+INNER JOIN files f ON c.file_id = f.id
+INNER JOIN source_snapshots ss ON f.snapshot_id = ss.id
+WHERE c.chunk_key = $1;
+
+-- name: ListChunkHistory :many
+-- 同一ソース・同一ファイル内で同じ関数/クラス名（名前がない場合は同じ序数）を持つチャンクをスナップショット順に取得
+SELECT
+    c.id,
+    c.chunk_key,
+    c.start_line,
+    c.end_line,
+    c.content,
+    c.content_hash,
+    c.git_commit_hash,
+    c.author,
+    c.updated_at,
+    c.indexed_at,
+    c.is_latest,
+    ss.id AS snapshot_id,
+    ss.version_identifier,
+    ss.created_at AS snapshot_created_at
+FROM chunks c
+INNER JOIN files f ON c.file_id = f.id
+INNER JOIN source_snapshots ss ON f.snapshot_id = ss.id
+WHERE ss.source_id = sqlc.arg(source_id)
+  AND f.path = sqlc.arg(file_path)
+  AND (
+      (sqlc.narg(chunk_name)::text IS NOT NULL
+          AND c.chunk_name = sqlc.narg(chunk_name)::text
+          AND c.parent_name IS NOT DISTINCT FROM sqlc.narg(parent_name)::text)
+      OR (sqlc.narg(chunk_name)::text IS NULL AND c.ordinal = sqlc.arg(ordinal))
+  )
+ORDER BY ss.created_at, c.indexed_at;
+
+-- name: RefreshLatestChunks :exec
+-- ソースのチャンクの is_latest を更新する
+-- Git参照が指すスナップショットと最新のインデックス済みスナップショットのチャンクを最新とみなす
+WITH current_snapshots AS (
+    SELECT gr.snapshot_id AS id
+    FROM git_refs gr
+    WHERE gr.source_id = sqlc.arg(source_id)
+    UNION
+    (
+        SELECT s.id
+        FROM source_snapshots s
+        WHERE s.source_id = sqlc.arg(source_id)
+          AND s.status = 'indexed'
+        ORDER BY s.indexed_at DESC NULLS LAST, s.created_at DESC
+        LIMIT 1
+    )
+)
+UPDATE chunks c
+SET is_latest = (f.snapshot_id IN (SELECT id FROM current_snapshots))
+FROM files f
+INNER JOIN source_snapshots ss ON f.snapshot_id = ss.id
+WHERE c.file_id = f.id
+  AND ss.source_id = sqlc.arg(source_id)
+  AND c.is_latest <> (f.snapshot_id IN (SELECT id FROM current_snapshots));
+
+-- name: ListAPISymbols :many
+-- APIリファレンス生成用に関数・型などのチャンク（レベル2）のシグネチャとドキュメントコメントを取得する
+-- product_id 指定時はプロダクトの各ソースの最新スナップショット、snapshot_id 指定時はそのスナップショットを対象とする
+-- シグネチャを持たない宣言（型など）のため、内容の先頭部分も返す
+WITH scope_snapshots AS (
+    SELECT DISTINCT ON (ss.source_id) ss.id
+    FROM source_snapshots ss
+    INNER JOIN sources s ON ss.source_id = s.id
+    WHERE ss.status = 'indexed'
+      AND (sqlc.narg(product_id)::uuid IS NULL OR s.product_id = sqlc.narg(product_id)::uuid)
+      AND (sqlc.narg(snapshot_id)::uuid IS NULL OR ss.id = sqlc.narg(snapshot_id)::uuid)
+    ORDER BY ss.source_id, ss.indexed_at DESC NULLS LAST, ss.created_at DESC
+)
+SELECT
+    f.path,
+    f.language,
+    c.chunk_type,
+    c.chunk_name::text AS chunk_name,
+    c.parent_name,
+    c.signature,
+    c.doc_comment,
+    left(c.content, 1000)::text AS content_head,
+    c.start_line,
+    c.end_line
+FROM chunks c
+INNER JOIN files f ON f.id = c.file_id
+INNER JOIN scope_snapshots sc ON f.snapshot_id = sc.id
+WHERE c.level = 2
+  AND c.chunk_name IS NOT NULL
+  AND (cardinality(sqlc.arg(domains)::text[]) = 0 OR f.domain = ANY(sqlc.arg(domains)::text[]))
+ORDER BY f.path, c.start_line
+LIMIT sqlc.arg(row_limit);
+
+-- name: ListBuildFileChunks :many
+-- オンボーディングガイド用に、ビルド・テストのコマンドを検出する Makefile とCI設定ファイルのチャンクを取得する
+-- 対象スナップショットの決め方は ListAPISymbols と同じ
+WITH scope_snapshots AS (
+    SELECT DISTINCT ON (ss.source_id) ss.id, s.name AS source_name
+    FROM source_snapshots ss
+    INNER JOIN sources s ON ss.source_id = s.id
+    WHERE ss.status = 'indexed'
+      AND (sqlc.narg(product_id)::uuid IS NULL OR s.product_id = sqlc.narg(product_id)::uuid)
+      AND (sqlc.narg(snapshot_id)::uuid IS NULL OR ss.id = sqlc.narg(snapshot_id)::uuid)
+    ORDER BY ss.source_id, ss.indexed_at DESC NULLS LAST, ss.created_at DESC
+)
+SELECT
+    sc.source_name::text AS source_name,
+    f.path,
+    c.start_line,
+    c.content
+FROM chunks c
+INNER JOIN files f ON f.id = c.file_id
+INNER JOIN scope_snapshots sc ON f.snapshot_id = sc.id
+WHERE c.level <> 1
+  AND (
+      f.path ~ '(^|/)(Makefile|GNUmakefile|makefile)$'
+      OR f.path ~ '\.mk$'
+      OR f.path ~ '^\.github/workflows/[^/]+\.ya?ml$'
+      OR f.path = '.gitlab-ci.yml'
+  )
+ORDER BY sc.source_name, f.path, c.start_line, c.ordinal;
+
+-- name: ListOpsFileChunks :many
+-- 運用コマンドのページ用に、Makefile・CI設定ファイルと scripts/ 配下・シェルスクリプトのチャンクを取得する
+-- 対象スナップショットの決め方は ListAPISymbols と同じ
+WITH scope_snapshots AS (
+    SELECT DISTINCT ON (ss.source_id) ss.id, s.name AS source_name
+    FROM source_snapshots ss
+    INNER JOIN sources s ON ss.source_id = s.id
+    WHERE ss.status = 'indexed'
+      AND (sqlc.narg(product_id)::uuid IS NULL OR s.product_id = sqlc.narg(product_id)::uuid)
+      AND (sqlc.narg(snapshot_id)::uuid IS NULL OR ss.id = sqlc.narg(snapshot_id)::uuid)
+    ORDER BY ss.source_id, ss.indexed_at DESC NULLS LAST, ss.created_at DESC
+)
+SELECT
+    sc.source_name::text AS source_name,
+    f.path,
+    c.start_line,
+    c.content
+FROM chunks c
+INNER JOIN files f ON f.id = c.file_id
+INNER JOIN scope_snapshots sc ON f.snapshot_id = sc.id
+WHERE c.level <> 1
+  AND (
+      f.path ~ '(^|/)(Makefile|GNUmakefile|makefile)$'
+      OR f.path ~ '\.mk$'
+      OR f.path ~ '^\.github/workflows/[^/]+\.ya?ml$'
+      OR f.path = '.gitlab-ci.yml'
+      OR f.path ~ '(^|/)scripts?/[^/]+$'
+      OR f.path ~ '\.(sh|bash)$'
+  )
+ORDER BY sc.source_name, f.path, c.start_line, c.ordinal;
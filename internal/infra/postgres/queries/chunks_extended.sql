@@ -7,10 +7,84 @@ INSERT INTO chunks (
     level, importance_score,
     standard_imports, external_imports, internal_calls, external_calls, type_dependencies,
     source_snapshot_id, git_commit_hash, author, updated_at, indexed_at,
-    file_version, is_latest, chunk_key
+    file_version, is_latest, chunk_key, embedding_truncated, breadcrumb, page_start, page_end, chunker_version
 )
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31, $32, $33);
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31, $32, $33, $34, $35, $36, $37, $38, $39);
+
+-- name: UpsertChunkBatch :batchexec
+-- スナップショットの再実行（再開・--force-init）でチャンクを保存する
+-- チャンクの ID は chunk_key から決定的に生成するため、前回の実行で保存した同じチャンクは置き換える
+INSERT INTO chunks (
+    id,
+    file_id, ordinal, start_line, end_line, content, content_hash, token_count,
+    chunk_type, chunk_name, parent_name, signature, doc_comment, imports, calls,
+    lines_of_code, comment_ratio, cyclomatic_complexity, embedding_context,
+    level, importance_score,
+    standard_imports, external_imports, internal_calls, external_calls, type_dependencies,
+    source_snapshot_id, git_commit_hash, author, updated_at, indexed_at,
+    file_version, is_latest, chunk_key, embedding_truncated, breadcrumb, page_start, page_end, chunker_version
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31, $32, $33, $34, $35, $36, $37, $38, $39)
+ON CONFLICT (id) DO UPDATE SET
+    file_id = EXCLUDED.file_id,
+    ordinal = EXCLUDED.ordinal,
+    start_line = EXCLUDED.start_line,
+    end_line = EXCLUDED.end_line,
+    content = EXCLUDED.content,
+    content_hash = EXCLUDED.content_hash,
+    token_count = EXCLUDED.token_count,
+    chunk_type = EXCLUDED.chunk_type,
+    chunk_name = EXCLUDED.chunk_name,
+    parent_name = EXCLUDED.parent_name,
+    signature = EXCLUDED.signature,
+    doc_comment = EXCLUDED.doc_comment,
+    imports = EXCLUDED.imports,
+    calls = EXCLUDED.calls,
+    lines_of_code = EXCLUDED.lines_of_code,
+    comment_ratio = EXCLUDED.comment_ratio,
+    cyclomatic_complexity = EXCLUDED.cyclomatic_complexity,
+    embedding_context = EXCLUDED.embedding_context,
+    level = EXCLUDED.level,
+    importance_score = EXCLUDED.importance_score,
+    standard_imports = EXCLUDED.standard_imports,
+    external_imports = EXCLUDED.external_imports,
+    internal_calls = EXCLUDED.internal_calls,
+    external_calls = EXCLUDED.external_calls,
+    type_dependencies = EXCLUDED.type_dependencies,
+    source_snapshot_id = EXCLUDED.source_snapshot_id,
+    git_commit_hash = EXCLUDED.git_commit_hash,
+    author = EXCLUDED.author,
+    updated_at = EXCLUDED.updated_at,
+    indexed_at = EXCLUDED.indexed_at,
+    file_version = EXCLUDED.file_version,
+    is_latest = EXCLUDED.is_latest,
+    chunk_key = EXCLUDED.chunk_key,
+    embedding_truncated = EXCLUDED.embedding_truncated,
+    breadcrumb = EXCLUDED.breadcrumb,
+    page_start = EXCLUDED.page_start,
+    page_end = EXCLUDED.page_end,
+    chunker_version = EXCLUDED.chunker_version;
+
+-- name: DeleteStaleChunksByFile :exec
+-- スナップショットの再実行で、ファイルのチャンクのうち今回作成しなかったもの（keep_ids 以外）を削除する
+DELETE FROM chunks
+WHERE file_id = sqlc.arg(file_id)
+  AND NOT (id = ANY(sqlc.arg(keep_ids)::uuid[]));
 
 -- name: CreateEmbeddingBatch :batchexec
 INSERT INTO embeddings (chunk_id, vector, model)
 VALUES ($1, $2, $3);
+
+-- name: CreateEmbeddingCopy :copyfrom
+-- インデックス化で生成した Embedding を COPY でまとめて保存する（vector 型をコネクションに登録する必要がある）
+INSERT INTO embeddings (chunk_id, vector, model)
+VALUES ($1, $2, $3);
+
+-- name: UpsertEmbeddingBatch :batchexec
+-- 別のモデルで生成した Embedding は置き換える（index reembed で使用）
+INSERT INTO embeddings (chunk_id, vector, model)
+VALUES ($1, $2, $3)
+ON CONFLICT (chunk_id) DO UPDATE SET
+    vector = EXCLUDED.vector,
+    model = EXCLUDED.model,
+    created_at = CURRENT_TIMESTAMP;
@@ -0,0 +1,46 @@
+-- name: RecordCoverageHistory :exec
+-- スナップショットのドメイン別カバレッジ（snapshot_files の集計）を履歴に記録する（記録済みの場合は上書きする）
+INSERT INTO coverage_history (snapshot_id, domain, total_files, indexed_files, indexed_chunks, coverage_rate)
+SELECT
+    sf.snapshot_id,
+    COALESCE(sf.domain, 'unknown'),
+    COUNT(*)::integer,
+    COUNT(*) FILTER (WHERE sf.indexed)::integer,
+    COALESCE(SUM(chunk_counts.chunk_count), 0)::integer,
+    ROUND(COUNT(*) FILTER (WHERE sf.indexed)::numeric / COUNT(*) * 100, 2)
+FROM snapshot_files sf
+LEFT JOIN files f ON sf.snapshot_id = f.snapshot_id AND sf.file_path = f.path
+LEFT JOIN (
+    SELECT file_id, COUNT(*) AS chunk_count
+    FROM chunks
+    GROUP BY file_id
+) chunk_counts ON f.id = chunk_counts.file_id
+WHERE sf.snapshot_id = $1
+GROUP BY sf.snapshot_id, COALESCE(sf.domain, 'unknown')
+ON CONFLICT (snapshot_id, domain) DO UPDATE
+SET total_files = EXCLUDED.total_files,
+    indexed_files = EXCLUDED.indexed_files,
+    indexed_chunks = EXCLUDED.indexed_chunks,
+    coverage_rate = EXCLUDED.coverage_rate,
+    recorded_at = CURRENT_TIMESTAMP;
+
+-- name: ListCoverageHistoryByProduct :many
+-- プロダクトの各ソースのスナップショットのドメイン別カバレッジの履歴を記録日時の古い順に取得する
+-- domain を指定した場合はそのドメインのみ、since を指定した場合はそれ以降に記録したもののみを対象とする
+SELECT
+    ch.snapshot_id,
+    s.name AS source_name,
+    ss.version_identifier,
+    ch.domain,
+    ch.total_files,
+    ch.indexed_files,
+    ch.indexed_chunks,
+    ch.coverage_rate,
+    ch.recorded_at
+FROM coverage_history ch
+INNER JOIN source_snapshots ss ON ch.snapshot_id = ss.id
+INNER JOIN sources s ON ss.source_id = s.id
+WHERE s.product_id = sqlc.arg(product_id)
+  AND (sqlc.narg(domain)::text IS NULL OR ch.domain = sqlc.narg(domain)::text)
+  AND (sqlc.narg(since)::timestamp IS NULL OR ch.recorded_at >= sqlc.narg(since)::timestamp)
+ORDER BY ch.recorded_at, s.name, ch.domain;
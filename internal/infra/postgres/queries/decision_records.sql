@@ -0,0 +1,48 @@
+-- name: UpsertDecisionRecord :exec
+INSERT INTO decision_records (
+    snapshot_id,
+    file_id,
+    file_path,
+    number,
+    title,
+    status,
+    status_text,
+    decided_on,
+    decision
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
+)
+ON CONFLICT (file_id)
+DO UPDATE SET
+    file_path = EXCLUDED.file_path,
+    number = EXCLUDED.number,
+    title = EXCLUDED.title,
+    status = EXCLUDED.status,
+    status_text = EXCLUDED.status_text,
+    decided_on = EXCLUDED.decided_on,
+    decision = EXCLUDED.decision;
+
+-- name: ListDecisionRecords :many
+-- 設計判断の記録を、プロダクトまたはスナップショットの範囲で取得する
+-- product_id を指定した場合は各ソースの最新のインデックス済みスナップショットを対象とする
+WITH scope_snapshots AS (
+    SELECT DISTINCT ON (ss.source_id) ss.id, s.name AS source_name
+    FROM source_snapshots ss
+    INNER JOIN sources s ON ss.source_id = s.id
+    WHERE ss.status = 'indexed'
+      AND (sqlc.narg(product_id)::uuid IS NULL OR s.product_id = sqlc.narg(product_id)::uuid)
+      AND (sqlc.narg(snapshot_id)::uuid IS NULL OR ss.id = sqlc.narg(snapshot_id)::uuid)
+    ORDER BY ss.source_id, ss.indexed_at DESC NULLS LAST, ss.created_at DESC
+)
+SELECT
+    dr.*,
+    sc.source_name::text AS source_name
+FROM decision_records dr
+INNER JOIN scope_snapshots sc ON dr.snapshot_id = sc.id
+ORDER BY sc.source_name, dr.number NULLS LAST, dr.file_path;
+
+-- name: ListDecisionStatusesByFileIDs :many
+-- ファイルIDに対応する設計判断の記録のステータスを取得する（設計判断の記録でないファイルは含まれない）
+SELECT file_id, status
+FROM decision_records
+WHERE file_id = ANY(sqlc.arg(file_ids)::uuid[]);
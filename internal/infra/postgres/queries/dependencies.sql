@@ -38,3 +38,166 @@ WHERE to_chunk_id = $1;
 -- name: GetAllDependencies :many
 SELECT * FROM chunk_dependencies
 ORDER BY created_at DESC;
+
+-- name: ListRelatedChunks :many
+-- 指定チャンク群と依存関係で直接つながるチャンクを取得する
+-- outgoing: 指定チャンクが依存する側（呼び出し先・参照する型など）
+-- incoming: 指定チャンクに依存する側（呼び出し元など）
+SELECT
+    d.from_chunk_id AS via_chunk_id,
+    'outgoing'::text AS direction,
+    d.dep_type,
+    d.symbol,
+    c.id AS chunk_id,
+    f.path,
+    c.start_line,
+    c.end_line,
+    c.content,
+    c.token_count
+FROM chunk_dependencies d
+JOIN chunks c ON c.id = d.to_chunk_id
+JOIN files f ON f.id = c.file_id
+WHERE d.from_chunk_id = ANY(sqlc.arg(chunk_ids)::uuid[])
+  AND (cardinality(sqlc.arg(dep_types)::text[]) = 0 OR d.dep_type = ANY(sqlc.arg(dep_types)::text[]))
+UNION ALL
+SELECT
+    d.to_chunk_id AS via_chunk_id,
+    'incoming'::text AS direction,
+    d.dep_type,
+    d.symbol,
+    c.id AS chunk_id,
+    f.path,
+    c.start_line,
+    c.end_line,
+    c.content,
+    c.token_count
+FROM chunk_dependencies d
+JOIN chunks c ON c.id = d.from_chunk_id
+JOIN files f ON f.id = c.file_id
+WHERE d.to_chunk_id = ANY(sqlc.arg(chunk_ids)::uuid[])
+  AND (cardinality(sqlc.arg(dep_types)::text[]) = 0 OR d.dep_type = ANY(sqlc.arg(dep_types)::text[]))
+ORDER BY via_chunk_id, direction, dep_type, symbol;
+
+-- name: ListFileDependencyEdges :many
+-- 依存関係をファイル単位に集約して取得する（Wikiの図解生成用）
+-- product_id 指定時はプロダクトの各ソースの最新スナップショット、snapshot_id 指定時はそのスナップショットを対象とする
+-- product_id 指定時はソースをまたぐ依存関係も含む
+WITH scope_snapshots AS (
+    SELECT DISTINCT ON (ss.source_id) ss.id
+    FROM source_snapshots ss
+    INNER JOIN sources s ON ss.source_id = s.id
+    WHERE ss.status = 'indexed'
+      AND (sqlc.narg(product_id)::uuid IS NULL OR s.product_id = sqlc.narg(product_id)::uuid)
+      AND (sqlc.narg(snapshot_id)::uuid IS NULL OR ss.id = sqlc.narg(snapshot_id)::uuid)
+    ORDER BY ss.source_id, ss.indexed_at DESC NULLS LAST, ss.created_at DESC
+)
+SELECT
+    ff.path AS from_path,
+    tf.path AS to_path,
+    d.dep_type,
+    COUNT(*)::int AS edge_count
+FROM chunk_dependencies d
+INNER JOIN chunks fc ON fc.id = d.from_chunk_id
+INNER JOIN files ff ON ff.id = fc.file_id
+INNER JOIN chunks tc ON tc.id = d.to_chunk_id
+INNER JOIN files tf ON tf.id = tc.file_id
+INNER JOIN scope_snapshots sc ON ff.snapshot_id = sc.id
+INNER JOIN scope_snapshots tsc ON tf.snapshot_id = tsc.id
+WHERE ff.id <> tf.id
+GROUP BY ff.path, tf.path, d.dep_type
+ORDER BY edge_count DESC, from_path, to_path, d.dep_type
+LIMIT sqlc.arg(row_limit);
+
+-- name: ListTopChunkCallEdges :many
+-- 重要度の高い関数/クラス（レベル2）のチャンク同士の呼び出し関係を取得する（Wikiの図解生成用）
+-- 対象スナップショットの決め方は ListFileDependencyEdges と同じ
+WITH scope_snapshots AS (
+    SELECT DISTINCT ON (ss.source_id) ss.id
+    FROM source_snapshots ss
+    INNER JOIN sources s ON ss.source_id = s.id
+    WHERE ss.status = 'indexed'
+      AND (sqlc.narg(product_id)::uuid IS NULL OR s.product_id = sqlc.narg(product_id)::uuid)
+      AND (sqlc.narg(snapshot_id)::uuid IS NULL OR ss.id = sqlc.narg(snapshot_id)::uuid)
+    ORDER BY ss.source_id, ss.indexed_at DESC NULLS LAST, ss.created_at DESC
+),
+top_chunks AS (
+    SELECT c.id, c.chunk_name, c.parent_name, f.path, c.importance_score
+    FROM chunks c
+    INNER JOIN files f ON f.id = c.file_id
+    INNER JOIN scope_snapshots sc ON f.snapshot_id = sc.id
+    WHERE c.level = 2
+      AND c.chunk_name IS NOT NULL
+      AND c.importance_score IS NOT NULL
+    ORDER BY c.importance_score DESC, c.id
+    LIMIT sqlc.arg(node_limit)
+)
+SELECT DISTINCT
+    fc.id AS from_chunk_id,
+    fc.chunk_name::text AS from_name,
+    fc.parent_name AS from_parent,
+    fc.path AS from_path,
+    tc.id AS to_chunk_id,
+    tc.chunk_name::text AS to_name,
+    tc.parent_name AS to_parent,
+    tc.path AS to_path
+FROM chunk_dependencies d
+INNER JOIN top_chunks fc ON fc.id = d.from_chunk_id
+INNER JOIN top_chunks tc ON tc.id = d.to_chunk_id
+WHERE d.dep_type = 'call'
+  AND fc.id <> tc.id
+ORDER BY from_path, from_name, to_path, to_name;
+
+-- name: ListDependencyGraphEdges :many
+-- 依存関係グラフのエッジを依存元・依存先のチャンクの情報とともに取得する（graph export 用）
+-- 対象スナップショットの決め方は ListFileDependencyEdges と同じで、依存元・依存先の両方がパス・重要度の条件を満たすものに絞る
+WITH scope_snapshots AS (
+    SELECT DISTINCT ON (ss.source_id) ss.id, s.name AS source_name
+    FROM source_snapshots ss
+    INNER JOIN sources s ON ss.source_id = s.id
+    WHERE ss.status = 'indexed'
+      AND (sqlc.narg(product_id)::uuid IS NULL OR s.product_id = sqlc.narg(product_id)::uuid)
+      AND (sqlc.narg(snapshot_id)::uuid IS NULL OR ss.id = sqlc.narg(snapshot_id)::uuid)
+    ORDER BY ss.source_id, ss.indexed_at DESC NULLS LAST, ss.created_at DESC
+),
+graph_nodes AS (
+    SELECT
+        c.id,
+        c.chunk_type,
+        c.chunk_name,
+        c.parent_name,
+        c.start_line,
+        c.end_line,
+        c.importance_score AS importance,
+        f.path,
+        sc.source_name
+    FROM chunks c
+    INNER JOIN files f ON f.id = c.file_id
+    INNER JOIN scope_snapshots sc ON f.snapshot_id = sc.id
+    WHERE (sqlc.narg(path_prefix)::text IS NULL OR f.path LIKE (sqlc.narg(path_prefix)::text || '%'))
+      AND (sqlc.narg(min_importance)::float8 IS NULL OR c.importance_score >= sqlc.narg(min_importance)::float8)
+)
+SELECT
+    d.dep_type,
+    d.symbol,
+    fn.id AS from_chunk_id,
+    fn.source_name::text AS from_source_name,
+    fn.path AS from_path,
+    fn.chunk_type AS from_type,
+    fn.chunk_name AS from_name,
+    fn.parent_name AS from_parent,
+    fn.start_line AS from_start_line,
+    fn.end_line AS from_end_line,
+    fn.importance AS from_importance,
+    tn.id AS to_chunk_id,
+    tn.source_name::text AS to_source_name,
+    tn.path AS to_path,
+    tn.chunk_type AS to_type,
+    tn.chunk_name AS to_name,
+    tn.parent_name AS to_parent,
+    tn.start_line AS to_start_line,
+    tn.end_line AS to_end_line,
+    tn.importance AS to_importance
+FROM chunk_dependencies d
+INNER JOIN graph_nodes fn ON fn.id = d.from_chunk_id
+INNER JOIN graph_nodes tn ON tn.id = d.to_chunk_id
+ORDER BY fn.source_name, fn.path, fn.start_line, tn.source_name, tn.path, tn.start_line, d.dep_type, d.symbol;
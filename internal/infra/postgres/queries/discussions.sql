@@ -0,0 +1,53 @@
+-- name: UpsertDiscussion :exec
+INSERT INTO discussions (
+    snapshot_id,
+    file_id,
+    file_path,
+    kind,
+    number,
+    title,
+    state,
+    url,
+    summary,
+    paths,
+    last_activity_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+)
+ON CONFLICT (file_id)
+DO UPDATE SET
+    file_path = EXCLUDED.file_path,
+    kind = EXCLUDED.kind,
+    number = EXCLUDED.number,
+    title = EXCLUDED.title,
+    state = EXCLUDED.state,
+    url = EXCLUDED.url,
+    summary = EXCLUDED.summary,
+    paths = EXCLUDED.paths,
+    last_activity_at = EXCLUDED.last_activity_at;
+
+-- name: ListDiscussionsByPaths :many
+-- 指定したファイルパスに言及した議論を、最終更新日時の新しい順に取得する
+-- 対象はプロダクト（snapshot_id のみ指定した場合はそのスナップショットのプロダクト）の各ソースの最新のインデックス済みスナップショット
+WITH scope_snapshots AS (
+    SELECT DISTINCT ON (ss.source_id) ss.id, s.name AS source_name
+    FROM source_snapshots ss
+    INNER JOIN sources s ON ss.source_id = s.id
+    WHERE ss.status = 'indexed'
+      AND s.product_id = COALESCE(
+          sqlc.narg(product_id)::uuid,
+          (SELECT src.product_id
+           FROM source_snapshots snap
+           INNER JOIN sources src ON snap.source_id = src.id
+           WHERE snap.id = sqlc.narg(snapshot_id)::uuid)
+      )
+    ORDER BY ss.source_id, ss.indexed_at DESC NULLS LAST, ss.created_at DESC
+)
+SELECT
+    d.*,
+    sc.source_name::text AS source_name
+FROM discussions d
+INNER JOIN scope_snapshots sc ON d.snapshot_id = sc.id
+WHERE d.paths && sqlc.arg(paths)::text[]
+ORDER BY d.last_activity_at DESC NULLS LAST, d.number DESC
+LIMIT sqlc.arg(max_results);
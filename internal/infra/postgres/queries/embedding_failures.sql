@@ -0,0 +1,32 @@
+-- name: UpsertEmbeddingFailure :exec
+-- 既に記録済みのチャンクは失敗回数を加算し、エラーと最終失敗日時を更新する
+INSERT INTO embedding_failures (chunk_id, error)
+VALUES ($1, $2)
+ON CONFLICT (chunk_id) DO UPDATE SET
+    error = EXCLUDED.error,
+    attempts = embedding_failures.attempts + 1,
+    last_failed_at = CURRENT_TIMESTAMP;
+
+-- name: ListEmbeddingFailuresByProduct :many
+-- 記録後に db verify --fix などで Embedding が作成されたチャンクは除く
+-- content は Embedding の入力（切り詰めた embedding_context があればそれを優先）
+SELECT
+    ef.chunk_id,
+    COALESCE(c.embedding_context, c.content)::text AS content,
+    f.path AS file_path,
+    s.name AS source_name,
+    ef.error,
+    ef.attempts,
+    ef.last_failed_at
+FROM embedding_failures ef
+INNER JOIN chunks c ON ef.chunk_id = c.id
+INNER JOIN files f ON c.file_id = f.id
+INNER JOIN source_snapshots ss ON f.snapshot_id = ss.id
+INNER JOIN sources s ON ss.source_id = s.id
+WHERE s.product_id = $1
+  AND NOT EXISTS (SELECT 1 FROM embeddings e WHERE e.chunk_id = ef.chunk_id)
+ORDER BY ef.last_failed_at, ef.chunk_id;
+
+-- name: DeleteEmbeddingFailures :exec
+DELETE FROM embedding_failures
+WHERE chunk_id = ANY(sqlc.arg(chunk_ids)::uuid[]);
@@ -0,0 +1,38 @@
+-- name: GetRegisteredEmbeddingModel :one
+SELECT * FROM embedding_models
+WHERE model = $1;
+
+-- name: ListRegisteredEmbeddingModels :many
+-- 各モデルで生成したチャンクの Embedding の数とともに取得する
+SELECT
+    em.model,
+    em.provider,
+    em.dimension,
+    em.variable_dimension,
+    em.created_at,
+    (SELECT COUNT(*) FROM embeddings e WHERE e.model = em.model)::bigint AS embedding_count
+FROM embedding_models em
+ORDER BY em.provider, em.model;
+
+-- name: UpsertRegisteredEmbeddingModel :exec
+INSERT INTO embedding_models (model, provider, dimension, variable_dimension)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (model) DO UPDATE SET
+    provider = EXCLUDED.provider,
+    dimension = EXCLUDED.dimension,
+    variable_dimension = EXCLUDED.variable_dimension;
+
+-- name: ListChunksForReembed :many
+-- Embedding がない、または指定したモデル以外で生成したチャンクを取得する（index reembed で使用）
+-- content は Embedding の入力（切り詰めた embedding_context があればそれを優先）
+SELECT
+    c.id,
+    COALESCE(c.embedding_context, c.content)::text AS content
+FROM chunks c
+INNER JOIN files f ON c.file_id = f.id
+INNER JOIN source_snapshots ss ON f.snapshot_id = ss.id
+INNER JOIN sources s ON ss.source_id = s.id
+LEFT JOIN embeddings e ON e.chunk_id = c.id
+WHERE s.product_id = sqlc.arg(product_id)
+  AND (e.chunk_id IS NULL OR e.model <> sqlc.arg(model)::text)
+ORDER BY c.id;
@@ -7,15 +7,25 @@ RETURNING *;
 SELECT * FROM embeddings
 WHERE chunk_id = $1;
 
+-- name: GetEmbeddingModel :one
+-- ベクトルを読み込まずに、チャンクの Embedding の生成に使用したモデルを取得する
+SELECT model, created_at FROM embeddings
+WHERE chunk_id = $1;
+
+-- name: ListEmbeddingsByChunkIDs :many
+-- 再チャンク化で内容が変わらないチャンクの Embedding を再利用するために、チャンクの Embedding をまとめて取得する
+SELECT chunk_id, vector, model FROM embeddings
+WHERE chunk_id = ANY(sqlc.arg(chunk_ids)::uuid[]);
+
 -- name: SearchSimilarChunks :many
 SELECT
     e.chunk_id,
     e.vector,
     e.model,
     e.created_at,
-    1 - (e.vector <=> $1::vector) as similarity
+    1 - (e.vector <=> $1) as similarity
 FROM embeddings e
-ORDER BY e.vector <=> $1::vector
+ORDER BY e.vector <=> $1
 LIMIT $2;
 
 -- name: DeleteEmbedding :exec
@@ -23,68 +33,233 @@ DELETE FROM embeddings
 WHERE chunk_id = $1;
 
 -- name: SearchChunksByProduct :many
+-- snapshot_id 指定時は最新スナップショットの代わりにそのスナップショットのみを対象とする
+-- ベクトル距離順に候補を取得（HNSWインデックスを利用）したうえで、類似度と importance_score を
+-- importance_weight で加重平均し、チャンクの最終更新日時からの経過日数に応じて recency_weight の割合まで減衰させたスコアで並べ替える
+-- 重み付けがない場合は候補を row_limit 件に絞り、従来どおりベクトル距離順の上位を返す
 WITH latest_snapshots AS (
     SELECT DISTINCT ON (source_id) id, source_id
     FROM source_snapshots
-    WHERE indexed = TRUE
+    WHERE status = 'indexed'
+      AND (sqlc.narg(snapshot_id)::uuid IS NULL OR id = sqlc.narg(snapshot_id)::uuid)
     ORDER BY source_id, indexed_at DESC NULLS LAST, created_at DESC
+),
+ranking AS (
+    -- 鮮度の重みは明示指定を優先し、なければプロダクトの設定を使用する
+    SELECT COALESCE(sqlc.narg(recency_weight)::float8, p.recency_weight, 0)::float8 AS recency_weight
+    FROM products p
+    WHERE p.id = sqlc.arg(product_id)
+),
+candidates AS (
+    SELECT
+        c.id AS chunk_id,
+        c.file_id,
+        f.path,
+        c.start_line,
+        c.end_line,
+        c.level,
+        c.breadcrumb,
+        c.page_start,
+        c.page_end,
+        c.content,
+        c.importance_score,
+        c.updated_at,
+        (1::float8 - (e.vector <=> sqlc.arg(query_vector)))::float8 AS similarity
+    FROM embeddings e
+    INNER JOIN chunks c ON e.chunk_id = c.id
+    INNER JOIN files f ON c.file_id = f.id
+    INNER JOIN latest_snapshots ls ON f.snapshot_id = ls.id
+    INNER JOIN sources s ON ls.source_id = s.id
+    WHERE s.product_id = sqlc.arg(product_id)
+      AND (sqlc.narg(path_prefix)::text IS NULL OR f.path LIKE (sqlc.narg(path_prefix)::text || '%'))
+      AND (cardinality(sqlc.arg(domains)::text[]) = 0 OR f.domain = ANY(sqlc.arg(domains)::text[]))
+      AND (cardinality(sqlc.arg(languages)::text[]) = 0 OR f.language = ANY(sqlc.arg(languages)::text[]))
+      AND (sqlc.narg(content_type)::text IS NULL OR f.content_type = sqlc.narg(content_type)::text)
+      AND (cardinality(sqlc.arg(file_ids)::uuid[]) = 0 OR c.file_id = ANY(sqlc.arg(file_ids)::uuid[]))
+    ORDER BY e.vector <=> sqlc.arg(query_vector)
+    LIMIT CASE
+        WHEN sqlc.arg(importance_weight)::float8 > 0 OR (SELECT recency_weight FROM ranking) > 0 THEN sqlc.arg(candidate_limit)::int
+        ELSE sqlc.arg(row_limit)::int
+    END
 )
 SELECT
-    c.id AS chunk_id,
-    f.path,
-    c.start_line,
-    c.end_line,
-    c.content,
-    (1::float8 - (e.vector <=> sqlc.arg(query_vector)::vector))::float8 AS score
-FROM embeddings e
-INNER JOIN chunks c ON e.chunk_id = c.id
-INNER JOIN files f ON c.file_id = f.id
-INNER JOIN latest_snapshots ls ON f.snapshot_id = ls.id
-INNER JOIN sources s ON ls.source_id = s.id
-WHERE s.product_id = sqlc.arg(product_id)
-  AND (sqlc.narg(path_prefix)::text IS NULL OR f.path LIKE (sqlc.narg(path_prefix)::text || '%'))
-  AND (sqlc.narg(content_type)::text IS NULL OR f.content_type = sqlc.narg(content_type)::text)
-ORDER BY e.vector <=> sqlc.arg(query_vector)::vector
+    cd.chunk_id,
+    cd.file_id,
+    cd.path,
+    cd.start_line,
+    cd.end_line,
+    cd.level,
+    cd.breadcrumb,
+    cd.page_start,
+    cd.page_end,
+    cd.content,
+    (((1::float8 - sqlc.arg(importance_weight)::float8) * cd.similarity
+        + sqlc.arg(importance_weight)::float8 * COALESCE(cd.importance_score, 0.5)::float8)
+        * (1::float8 - r.recency_weight + r.recency_weight * COALESCE(
+            power(0.5::float8, GREATEST(EXTRACT(EPOCH FROM (CURRENT_TIMESTAMP - cd.updated_at))::float8, 0) / 86400 / sqlc.arg(recency_half_life_days)::float8),
+            0.5)))::float8 AS score
+FROM candidates cd
+CROSS JOIN ranking r
+ORDER BY score DESC
 LIMIT sqlc.arg(row_limit);
 
 -- name: SearchChunksBySource :many
+-- snapshot_id 指定時は最新スナップショットの代わりにそのスナップショットのみを対象とする
+-- 並べ替えは SearchChunksByProduct と同様に importance_weight と recency_weight で重み付けする
 WITH latest_snapshot AS (
     SELECT id
     FROM source_snapshots
     WHERE source_id = sqlc.arg(source_id)
-      AND indexed = TRUE
+      AND status = 'indexed'
+      AND (sqlc.narg(snapshot_id)::uuid IS NULL OR id = sqlc.narg(snapshot_id)::uuid)
     ORDER BY indexed_at DESC NULLS LAST, created_at DESC
     LIMIT 1
+),
+ranking AS (
+    -- 鮮度の重みは明示指定を優先し、なければプロダクトの設定を使用する
+    SELECT COALESCE(sqlc.narg(recency_weight)::float8, p.recency_weight, 0)::float8 AS recency_weight
+    FROM sources s
+    INNER JOIN products p ON s.product_id = p.id
+    WHERE s.id = sqlc.arg(source_id)
+),
+candidates AS (
+    SELECT
+        c.id AS chunk_id,
+        c.file_id,
+        f.path,
+        c.start_line,
+        c.end_line,
+        c.level,
+        c.breadcrumb,
+        c.page_start,
+        c.page_end,
+        c.content,
+        c.importance_score,
+        c.updated_at,
+        (1::float8 - (e.vector <=> sqlc.arg(query_vector)))::float8 AS similarity
+    FROM embeddings e
+    INNER JOIN chunks c ON e.chunk_id = c.id
+    INNER JOIN files f ON c.file_id = f.id
+    INNER JOIN latest_snapshot ls ON f.snapshot_id = ls.id
+    WHERE (sqlc.narg(path_prefix)::text IS NULL OR f.path LIKE (sqlc.narg(path_prefix)::text || '%'))
+      AND (cardinality(sqlc.arg(domains)::text[]) = 0 OR f.domain = ANY(sqlc.arg(domains)::text[]))
+      AND (cardinality(sqlc.arg(languages)::text[]) = 0 OR f.language = ANY(sqlc.arg(languages)::text[]))
+      AND (sqlc.narg(content_type)::text IS NULL OR f.content_type = sqlc.narg(content_type)::text)
+      AND (cardinality(sqlc.arg(file_ids)::uuid[]) = 0 OR c.file_id = ANY(sqlc.arg(file_ids)::uuid[]))
+    ORDER BY e.vector <=> sqlc.arg(query_vector)
+    LIMIT CASE
+        WHEN sqlc.arg(importance_weight)::float8 > 0 OR (SELECT recency_weight FROM ranking) > 0 THEN sqlc.arg(candidate_limit)::int
+        ELSE sqlc.arg(row_limit)::int
+    END
 )
 SELECT
-    c.id AS chunk_id,
-    f.path,
-    c.start_line,
-    c.end_line,
-    c.content,
-    (1::float8 - (e.vector <=> sqlc.arg(query_vector)::vector))::float8 AS score
-FROM embeddings e
-INNER JOIN chunks c ON e.chunk_id = c.id
-INNER JOIN files f ON c.file_id = f.id
-INNER JOIN latest_snapshot ls ON f.snapshot_id = ls.id
-WHERE (sqlc.narg(path_prefix)::text IS NULL OR f.path LIKE (sqlc.narg(path_prefix)::text || '%'))
-  AND (sqlc.narg(content_type)::text IS NULL OR f.content_type = sqlc.narg(content_type)::text)
-ORDER BY e.vector <=> sqlc.arg(query_vector)::vector
+    cd.chunk_id,
+    cd.file_id,
+    cd.path,
+    cd.start_line,
+    cd.end_line,
+    cd.level,
+    cd.breadcrumb,
+    cd.page_start,
+    cd.page_end,
+    cd.content,
+    (((1::float8 - sqlc.arg(importance_weight)::float8) * cd.similarity
+        + sqlc.arg(importance_weight)::float8 * COALESCE(cd.importance_score, 0.5)::float8)
+        * (1::float8 - r.recency_weight + r.recency_weight * COALESCE(
+            power(0.5::float8, GREATEST(EXTRACT(EPOCH FROM (CURRENT_TIMESTAMP - cd.updated_at))::float8, 0) / 86400 / sqlc.arg(recency_half_life_days)::float8),
+            0.5)))::float8 AS score
+FROM candidates cd
+CROSS JOIN ranking r
+ORDER BY score DESC
 LIMIT sqlc.arg(row_limit);
 
 -- name: SearchChunksBySnapshot :many
+-- 並べ替えは SearchChunksByProduct と同様に importance_weight と recency_weight で重み付けする
+WITH ranking AS (
+    -- 鮮度の重みは明示指定を優先し、なければプロダクトの設定を使用する
+    SELECT COALESCE(sqlc.narg(recency_weight)::float8, p.recency_weight, 0)::float8 AS recency_weight
+    FROM source_snapshots ss
+    INNER JOIN sources s ON ss.source_id = s.id
+    INNER JOIN products p ON s.product_id = p.id
+    WHERE ss.id = sqlc.arg(snapshot_id)
+),
+candidates AS (
+    SELECT
+        c.id AS chunk_id,
+        c.file_id,
+        f.path,
+        c.start_line,
+        c.end_line,
+        c.level,
+        c.breadcrumb,
+        c.page_start,
+        c.page_end,
+        c.content,
+        c.importance_score,
+        c.updated_at,
+        (1 - (e.vector <=> sqlc.arg(query_vector)))::float8 AS similarity
+    FROM chunks c
+    JOIN files f ON c.file_id = f.id
+    JOIN embeddings e ON c.id = e.chunk_id
+    WHERE f.snapshot_id = sqlc.arg(snapshot_id)
+      AND (sqlc.narg(path_prefix)::text IS NULL OR f.path LIKE sqlc.narg(path_prefix)::text || '%')
+      AND (cardinality(sqlc.arg(domains)::text[]) = 0 OR f.domain = ANY(sqlc.arg(domains)::text[]))
+      AND (cardinality(sqlc.arg(languages)::text[]) = 0 OR f.language = ANY(sqlc.arg(languages)::text[]))
+      AND (sqlc.narg(content_type)::text IS NULL OR f.content_type = sqlc.narg(content_type)::text)
+      AND (cardinality(sqlc.arg(file_ids)::uuid[]) = 0 OR c.file_id = ANY(sqlc.arg(file_ids)::uuid[]))
+    ORDER BY e.vector <=> sqlc.arg(query_vector)
+    LIMIT CASE
+        WHEN sqlc.arg(importance_weight)::float8 > 0 OR (SELECT recency_weight FROM ranking) > 0 THEN sqlc.arg(candidate_limit)::int
+        ELSE sqlc.arg(limit_val)::int
+    END
+)
+SELECT
+    cd.chunk_id,
+    cd.file_id,
+    cd.path,
+    cd.start_line,
+    cd.end_line,
+    cd.level,
+    cd.breadcrumb,
+    cd.page_start,
+    cd.page_end,
+    cd.content,
+    (((1::float8 - sqlc.arg(importance_weight)::float8) * cd.similarity
+        + sqlc.arg(importance_weight)::float8 * COALESCE(cd.importance_score, 0.5)::float8)
+        * (1::float8 - r.recency_weight + r.recency_weight * COALESCE(
+            power(0.5::float8, GREATEST(EXTRACT(EPOCH FROM (CURRENT_TIMESTAMP - cd.updated_at))::float8, 0) / 86400 / sqlc.arg(recency_half_life_days)::float8),
+            0.5)))::float8 AS score
+FROM candidates cd
+CROSS JOIN ranking r
+ORDER BY score DESC
+LIMIT sqlc.arg(limit_val);
+
+-- name: ListChunksAtLine :many
+-- プロダクトの最新スナップショットで、ファイルの指定行を含むチャンクを内側（行範囲の狭いもの）から順に取得する
+-- 同じパスのファイルが複数のソースにある場合は、それぞれのチャンクを含める
+WITH latest_snapshots AS (
+    SELECT DISTINCT ON (source_id) id, source_id
+    FROM source_snapshots
+    WHERE status = 'indexed'
+    ORDER BY source_id, indexed_at DESC NULLS LAST, created_at DESC
+)
 SELECT
     c.id AS chunk_id,
+    c.file_id,
     f.path,
     c.start_line,
     c.end_line,
-    c.content,
-    (1 - (e.vector <=> sqlc.arg(query_vector)::vector))::float8 AS score
+    c.level,
+    c.breadcrumb,
+    c.page_start,
+    c.page_end,
+    c.content
 FROM chunks c
-JOIN files f ON c.file_id = f.id
-JOIN embeddings e ON c.id = e.chunk_id
-WHERE f.snapshot_id = sqlc.arg(snapshot_id)
-  AND (sqlc.narg(path_prefix)::text IS NULL OR f.path LIKE sqlc.narg(path_prefix)::text || '%')
-  AND (sqlc.narg(content_type)::text IS NULL OR f.content_type = sqlc.narg(content_type)::text)
-ORDER BY e.vector <=> sqlc.arg(query_vector)::vector
-LIMIT sqlc.arg(limit_val);
+INNER JOIN files f ON c.file_id = f.id
+INNER JOIN latest_snapshots ls ON f.snapshot_id = ls.id
+INNER JOIN sources s ON ls.source_id = s.id
+WHERE s.product_id = sqlc.arg(product_id)
+  AND f.path = sqlc.arg(path)
+  AND c.start_line <= sqlc.arg(line)::int
+  AND c.end_line >= sqlc.arg(line)::int
+ORDER BY c.end_line - c.start_line, c.level DESC, c.ordinal;
@@ -0,0 +1,73 @@
+-- name: DeleteExternalDependenciesBySnapshot :exec
+DELETE FROM external_dependencies
+WHERE snapshot_id = $1;
+
+-- name: CreateExternalDependency :exec
+INSERT INTO external_dependencies (
+    snapshot_id,
+    manifest_path,
+    ecosystem,
+    name,
+    requirement,
+    kind,
+    vendored,
+    location
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8
+) ON CONFLICT (snapshot_id, manifest_path, ecosystem, name) DO NOTHING;
+
+-- name: ListExternalDependencies :many
+-- 外部の依存関係を、プロダクトまたはスナップショットの範囲で取得する
+-- product_id を指定した場合は各ソースの最新のインデックス済みスナップショットを対象とする
+WITH scope_snapshots AS (
+    SELECT DISTINCT ON (ss.source_id) ss.id, ss.version_identifier, s.name AS source_name
+    FROM source_snapshots ss
+    INNER JOIN sources s ON ss.source_id = s.id
+    WHERE ss.status = 'indexed'
+      AND (sqlc.narg(product_id)::uuid IS NULL OR s.product_id = sqlc.narg(product_id)::uuid)
+      AND (sqlc.narg(snapshot_id)::uuid IS NULL OR ss.id = sqlc.narg(snapshot_id)::uuid)
+    ORDER BY ss.source_id, ss.indexed_at DESC NULLS LAST, ss.created_at DESC
+)
+SELECT
+    ed.*,
+    sc.source_name::text AS source_name,
+    sc.version_identifier::text AS version_identifier
+FROM external_dependencies ed
+INNER JOIN scope_snapshots sc ON ed.snapshot_id = sc.id
+ORDER BY sc.source_name, ed.manifest_path, ed.name;
+
+-- name: ListExternalDependencySnapshotPairs :many
+-- 対象スナップショットと、同じソースの直前（作成日時が前）のインデックス済みスナップショットの組を取得する
+-- 対象スナップショットの決め方は ListExternalDependencies と同じ
+WITH scope_snapshots AS (
+    SELECT DISTINCT ON (ss.source_id) ss.id, ss.source_id, ss.version_identifier, ss.created_at, s.name AS source_name
+    FROM source_snapshots ss
+    INNER JOIN sources s ON ss.source_id = s.id
+    WHERE ss.status = 'indexed'
+      AND (sqlc.narg(product_id)::uuid IS NULL OR s.product_id = sqlc.narg(product_id)::uuid)
+      AND (sqlc.narg(snapshot_id)::uuid IS NULL OR ss.id = sqlc.narg(snapshot_id)::uuid)
+    ORDER BY ss.source_id, ss.indexed_at DESC NULLS LAST, ss.created_at DESC
+)
+SELECT
+    sc.source_name::text AS source_name,
+    prev.id AS from_snapshot_id,
+    prev.version_identifier::text AS from_version,
+    sc.id AS to_snapshot_id,
+    sc.version_identifier::text AS to_version
+FROM scope_snapshots sc
+INNER JOIN LATERAL (
+    SELECT p.id, p.version_identifier
+    FROM source_snapshots p
+    WHERE p.source_id = sc.source_id
+      AND p.status = 'indexed'
+      AND p.created_at < sc.created_at
+    ORDER BY p.created_at DESC
+    LIMIT 1
+) prev ON TRUE
+ORDER BY sc.source_name;
+
+-- name: ListSnapshotExternalDependencies :many
+SELECT *
+FROM external_dependencies
+WHERE snapshot_id = $1
+ORDER BY manifest_path, name;
@@ -0,0 +1,18 @@
+-- name: InsertFileBlob :exec
+-- 同じ内容は1回だけ保存する
+INSERT INTO file_blobs (content_hash, content, size)
+VALUES ($1, $2, $3)
+ON CONFLICT (content_hash) DO NOTHING;
+
+-- name: GetFileBlob :one
+SELECT content FROM file_blobs
+WHERE content_hash = $1;
+
+-- name: DeleteUnreferencedFileBlobs :execrows
+-- スナップショット・プロダクトの削除で参照する files の行がなくなった内容を削除する
+DELETE FROM file_blobs b
+WHERE NOT EXISTS (SELECT 1 FROM files f WHERE f.content_hash = b.content_hash);
+
+-- name: GetFileBlobStats :one
+SELECT COUNT(*)::bigint AS blob_count, COALESCE(SUM(size), 0)::bigint AS total_size
+FROM file_blobs;
@@ -0,0 +1,41 @@
+-- name: UpsertFileFailure :exec
+-- 既に記録済みのファイルは失敗回数を加算し、段階・エラー・内容と最終失敗日時を更新する
+INSERT INTO file_failures (snapshot_id, file_path, stage, error, content, content_hash, size)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (snapshot_id, file_path) DO UPDATE SET
+    stage = EXCLUDED.stage,
+    error = EXCLUDED.error,
+    content = EXCLUDED.content,
+    content_hash = EXCLUDED.content_hash,
+    size = EXCLUDED.size,
+    attempts = file_failures.attempts + 1,
+    last_failed_at = CURRENT_TIMESTAMP;
+
+-- name: ListFileFailuresByProduct :many
+-- 再試行に使用するため、ファイルの内容とチャンクキーの生成に必要なプロダクト名・ソース名・バージョンを含める
+SELECT
+    ff.snapshot_id,
+    ff.file_path,
+    ff.stage,
+    ff.error,
+    ff.attempts,
+    ff.content,
+    ff.content_hash,
+    ff.size,
+    ff.first_failed_at,
+    ff.last_failed_at,
+    p.name AS product_name,
+    s.name AS source_name,
+    ss.version_identifier
+FROM file_failures ff
+INNER JOIN source_snapshots ss ON ff.snapshot_id = ss.id
+INNER JOIN sources s ON ss.source_id = s.id
+INNER JOIN products p ON s.product_id = p.id
+WHERE s.product_id = $1
+ORDER BY ff.last_failed_at, ss.id, ff.file_path;
+
+-- name: DeleteResolvedFileFailures :execrows
+-- スナップショットの記録のうち、直前の処理で再び失敗したファイル以外の記録を削除する
+DELETE FROM file_failures
+WHERE snapshot_id = sqlc.arg(snapshot_id)
+  AND NOT (file_path = ANY(sqlc.arg(failed_paths)::text[]));
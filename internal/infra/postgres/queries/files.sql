@@ -1,6 +1,13 @@
 -- name: CreateFile :one
+-- スナップショットの再実行（再開・--force-init）で登録済みのファイルは、ID を変えずに内容の情報を置き換える
 INSERT INTO files (snapshot_id, path, size, content_type, content_hash, language, domain)
 VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (snapshot_id, path) DO UPDATE SET
+    size = EXCLUDED.size,
+    content_type = EXCLUDED.content_type,
+    content_hash = EXCLUDED.content_hash,
+    language = EXCLUDED.language,
+    domain = EXCLUDED.domain
 RETURNING *;
 
 -- name: GetFile :one
@@ -64,3 +71,83 @@ ORDER BY file_count DESC;
 SELECT * FROM files
 WHERE snapshot_id = $1 AND domain = $2
 ORDER BY path;
+
+-- name: ListKeyFiles :many
+-- オンボーディングガイド用に、エントリポイントと重要度の高いファイルをファイル要約とともに取得する
+-- 対象スナップショットの決め方は ListDirectoryCoverage と同じ
+-- エントリポイント（main.go・index.ts など）を先に、それ以外はチャンクの重要度の最大値の高い順に並べる
+WITH scope_snapshots AS (
+    SELECT DISTINCT ON (ss.source_id) ss.id, s.name AS source_name
+    FROM source_snapshots ss
+    INNER JOIN sources s ON ss.source_id = s.id
+    WHERE ss.status = 'indexed'
+      AND (sqlc.narg(product_id)::uuid IS NULL OR s.product_id = sqlc.narg(product_id)::uuid)
+      AND (sqlc.narg(snapshot_id)::uuid IS NULL OR ss.id = sqlc.narg(snapshot_id)::uuid)
+    ORDER BY ss.source_id, ss.indexed_at DESC NULLS LAST, ss.created_at DESC
+),
+file_importance AS (
+    SELECT
+        f.id,
+        sc.source_name,
+        f.snapshot_id,
+        f.path,
+        f.language,
+        f.domain,
+        f.owners,
+        (f.path ~ '(^|/)(main\.(go|py|rs|c|cc|cpp|java|kt|swift)|index\.(js|jsx|ts|tsx|mjs)|app\.(py|js|ts)|server\.(go|js|ts)|manage\.py|__main__\.py)$')::boolean AS is_entry_point,
+        COALESCE(MAX(c.importance_score), 0)::float8 AS importance
+    FROM files f
+    INNER JOIN scope_snapshots sc ON f.snapshot_id = sc.id
+    LEFT JOIN chunks c ON c.file_id = f.id
+    WHERE f.domain IS NULL OR f.domain <> 'tests'
+    GROUP BY f.id, sc.source_name
+)
+SELECT
+    fi.source_name::text AS source_name,
+    fi.path,
+    fi.language,
+    fi.domain,
+    fi.is_entry_point,
+    fi.importance,
+    fi.owners,
+    COALESCE(sm.content, '')::text AS summary
+FROM file_importance fi
+LEFT JOIN summaries sm
+    ON sm.snapshot_id = fi.snapshot_id
+   AND sm.summary_type = 'file'
+   AND sm.target_path = fi.path
+ORDER BY fi.is_entry_point DESC, fi.importance DESC, fi.path
+LIMIT sqlc.arg(row_limit);
+
+-- name: UpdateFileOwners :exec
+-- CODEOWNERS から判定した担当者をファイルに設定する（担当者は空白区切りで1ファイル1要素として渡す）
+UPDATE files f
+SET owners = string_to_array(v.owners, ' ')
+FROM (
+    SELECT unnest(sqlc.arg(paths)::text[]) AS path, unnest(sqlc.arg(owners)::text[]) AS owners
+) v
+WHERE f.snapshot_id = sqlc.arg(snapshot_id) AND f.path = v.path;
+
+-- name: ListFileOwners :many
+-- ファイルの担当者を取得する（担当者の定義がないファイルは除く）
+SELECT id, owners
+FROM files
+WHERE id = ANY(sqlc.arg(file_ids)::uuid[])
+  AND cardinality(owners) > 0;
+
+-- name: ListFileOwnersByPaths :many
+-- プロダクトの各ソースの最新のインデックス済みスナップショットで、指定したパスのファイルの担当者を取得する
+-- 同じパスが複数のソースにある場合は、インデックス日時の新しいスナップショットを先に返す
+WITH latest_snapshots AS (
+    SELECT DISTINCT ON (ss.source_id) ss.id, ss.indexed_at, ss.created_at
+    FROM source_snapshots ss
+    INNER JOIN sources s ON ss.source_id = s.id
+    WHERE s.product_id = sqlc.arg(product_id) AND ss.status = 'indexed'
+    ORDER BY ss.source_id, ss.indexed_at DESC NULLS LAST, ss.created_at DESC
+)
+SELECT f.path, f.owners
+FROM files f
+INNER JOIN latest_snapshots ls ON f.snapshot_id = ls.id
+WHERE f.path = ANY(sqlc.arg(paths)::text[])
+  AND cardinality(f.owners) > 0
+ORDER BY ls.indexed_at DESC NULLS LAST, ls.created_at DESC;
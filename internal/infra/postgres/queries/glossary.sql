@@ -0,0 +1,59 @@
+-- name: ListGlossaryTerms :many
+-- プロダクトの用語集を用語順に取得する
+SELECT * FROM glossary
+WHERE product_id = $1
+ORDER BY normalized_term;
+
+-- name: UpsertGlossaryTerm :one
+INSERT INTO glossary (
+    product_id,
+    term,
+    normalized_term,
+    kind,
+    definition,
+    aliases,
+    source_paths,
+    updated_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, CURRENT_TIMESTAMP
+)
+ON CONFLICT (product_id, normalized_term)
+DO UPDATE SET
+    term = EXCLUDED.term,
+    kind = EXCLUDED.kind,
+    definition = EXCLUDED.definition,
+    aliases = EXCLUDED.aliases,
+    source_paths = EXCLUDED.source_paths,
+    updated_at = EXCLUDED.updated_at
+RETURNING *;
+
+-- name: DeleteGlossaryTermsExcept :execrows
+-- 今回の抽出で見つからなかった（keep に含まれない）用語を削除する
+DELETE FROM glossary
+WHERE product_id = $1
+  AND NOT (normalized_term = ANY(sqlc.arg(keep)::text[]));
+
+-- name: ListGlossarySourceChunks :many
+-- 用語抽出の入力として、プロダクトの各ソースの最新スナップショットからドキュメントのチャンクと、
+-- ドキュメントコメントを持つ宣言を重要度順に取得する
+WITH scope_snapshots AS (
+    SELECT DISTINCT ON (ss.source_id) ss.id
+    FROM source_snapshots ss
+    INNER JOIN sources s ON ss.source_id = s.id
+    WHERE ss.status = 'indexed'
+      AND s.product_id = sqlc.arg(product_id)
+    ORDER BY ss.source_id, ss.indexed_at DESC NULLS LAST, ss.created_at DESC
+)
+SELECT
+    f.path,
+    CASE
+        WHEN f.content_type = 'text/markdown' THEN left(c.content, sqlc.arg(max_chars)::int)
+        ELSE left(concat_ws(E'\n', c.doc_comment, c.signature), sqlc.arg(max_chars)::int)
+    END::text AS text
+FROM chunks c
+INNER JOIN files f ON f.id = c.file_id
+INNER JOIN scope_snapshots sc ON f.snapshot_id = sc.id
+WHERE f.content_type = 'text/markdown'
+   OR (c.level = 2 AND c.doc_comment IS NOT NULL AND c.doc_comment <> '')
+ORDER BY (f.content_type = 'text/markdown') DESC, c.importance_score DESC NULLS LAST, f.path, c.start_line
+LIMIT sqlc.arg(row_limit);
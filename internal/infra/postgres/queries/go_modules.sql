@@ -0,0 +1,56 @@
+-- name: DeleteGoModulesBySnapshot :exec
+DELETE FROM go_modules
+WHERE snapshot_id = $1;
+
+-- name: CreateGoModule :exec
+INSERT INTO go_modules (
+    snapshot_id,
+    module_path,
+    dir
+) VALUES (
+    $1, $2, $3
+) ON CONFLICT (snapshot_id, dir) DO NOTHING;
+
+-- name: ListProductGoModules :many
+-- プロダクトの各ソースの最新のインデックス済みスナップショットに含まれる Go モジュールを取得する
+WITH scope_snapshots AS (
+    SELECT DISTINCT ON (ss.source_id) ss.id
+    FROM source_snapshots ss
+    INNER JOIN sources s ON ss.source_id = s.id
+    WHERE ss.status = 'indexed'
+      AND s.product_id = sqlc.arg(product_id)::uuid
+    ORDER BY ss.source_id, ss.indexed_at DESC NULLS LAST, ss.created_at DESC
+)
+SELECT gm.*
+FROM go_modules gm
+INNER JOIN scope_snapshots sc ON gm.snapshot_id = sc.id
+ORDER BY gm.module_path;
+
+-- name: ListProductGoLinkChunks :many
+-- チャンク間の依存関係の解決に使用する Go のチャンク（名前を持つもの）を取得する
+-- 対象スナップショットの決め方は ListProductGoModules と同じ
+WITH scope_snapshots AS (
+    SELECT DISTINCT ON (ss.source_id) ss.id
+    FROM source_snapshots ss
+    INNER JOIN sources s ON ss.source_id = s.id
+    WHERE ss.status = 'indexed'
+      AND s.product_id = sqlc.arg(product_id)::uuid
+    ORDER BY ss.source_id, ss.indexed_at DESC NULLS LAST, ss.created_at DESC
+)
+SELECT
+    c.id,
+    f.snapshot_id,
+    f.path,
+    c.chunk_type,
+    c.chunk_name::text AS chunk_name,
+    c.parent_name,
+    c.imports,
+    c.internal_calls,
+    c.external_calls,
+    c.type_dependencies
+FROM chunks c
+INNER JOIN files f ON f.id = c.file_id
+INNER JOIN scope_snapshots sc ON f.snapshot_id = sc.id
+WHERE f.path LIKE '%.go'
+  AND c.chunk_name IS NOT NULL
+ORDER BY f.snapshot_id, f.path, c.ordinal;
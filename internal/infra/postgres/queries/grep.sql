@@ -0,0 +1,33 @@
+-- name: GrepChunks :many
+-- チャンクの内容を正規表現（POSIX）で検索する
+-- snapshot_id を指定しない場合はプロダクトの各ソースの最新のインデックス済みスナップショットを対象とする
+-- ファイル要約（レベル1）はファイルの内容ではないため対象外
+WITH scope_snapshots AS (
+    SELECT DISTINCT ON (ss.source_id) ss.id, s.name AS source_name
+    FROM source_snapshots ss
+    INNER JOIN sources s ON ss.source_id = s.id
+    WHERE ss.status = 'indexed'
+      AND s.product_id = sqlc.arg(product_id)::uuid
+      AND (sqlc.narg(snapshot_id)::uuid IS NULL OR ss.id = sqlc.narg(snapshot_id)::uuid)
+    ORDER BY ss.source_id, ss.indexed_at DESC NULLS LAST, ss.created_at DESC
+)
+SELECT
+    c.id AS chunk_id,
+    c.file_id,
+    f.path,
+    c.start_line,
+    c.end_line,
+    c.content,
+    sc.source_name::text AS source_name
+FROM chunks c
+INNER JOIN files f ON c.file_id = f.id
+INNER JOIN scope_snapshots sc ON f.snapshot_id = sc.id
+WHERE c.level >= 2
+  AND (sqlc.narg(path_prefix)::text IS NULL OR f.path LIKE sqlc.narg(path_prefix)::text || '%')
+  AND (sqlc.narg(path_like)::text IS NULL OR f.path LIKE sqlc.narg(path_like)::text)
+  AND CASE WHEN sqlc.arg(ignore_case)::bool
+           THEN c.content ~* sqlc.arg(pattern)::text
+           ELSE c.content ~ sqlc.arg(pattern)::text
+      END
+ORDER BY sc.source_name, f.path, c.start_line, c.level
+LIMIT sqlc.arg(max_chunks);
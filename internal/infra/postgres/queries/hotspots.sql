@@ -0,0 +1,39 @@
+-- name: ListHotspotCandidates :many
+-- ホットスポット（リスクの高い関数・ファイル）の候補として、関数・クラス単位のチャンクの複雑度・行数・コメント比率・編集回数を取得する
+-- product_id 指定時はプロダクトの各ソースの最新のインデックス済みスナップショット、snapshot_id 指定時はそのスナップショットを対象とする
+-- 編集回数は重要度の算出時に記録した値（chunk_importance.edit_count）で、未算出のチャンクは 0 とする
+-- テストカバレッジはカバレッジレポートを取り込んだスナップショットのみ（それ以外は NULL）
+WITH scope_snapshots AS (
+    SELECT DISTINCT ON (ss.source_id) ss.id, s.name AS source_name
+    FROM source_snapshots ss
+    INNER JOIN sources s ON ss.source_id = s.id
+    WHERE ss.status = 'indexed'
+      AND (sqlc.narg(product_id)::uuid IS NULL OR s.product_id = sqlc.narg(product_id)::uuid)
+      AND (sqlc.narg(snapshot_id)::uuid IS NULL OR ss.id = sqlc.narg(snapshot_id)::uuid)
+    ORDER BY ss.source_id, ss.indexed_at DESC NULLS LAST, ss.created_at DESC
+)
+SELECT
+    c.id,
+    sc.source_name::text AS source_name,
+    f.path,
+    c.chunk_type,
+    c.chunk_name,
+    c.parent_name,
+    c.start_line,
+    c.end_line,
+    c.cyclomatic_complexity,
+    c.lines_of_code,
+    COALESCE(c.comment_ratio, 0)::float8 AS comment_ratio,
+    COALESCE(ci.edit_count, 0)::int AS edit_count,
+    tc.statements AS test_statements,
+    tc.covered_statements AS test_covered_statements
+FROM chunks c
+INNER JOIN files f ON c.file_id = f.id
+INNER JOIN scope_snapshots sc ON f.snapshot_id = sc.id
+LEFT JOIN chunk_importance ci ON ci.chunk_id = c.id
+LEFT JOIN chunk_test_coverage tc ON tc.chunk_id = c.id
+WHERE c.level = 2
+  AND c.chunk_name IS NOT NULL
+  AND (sqlc.narg(path_prefix)::text IS NULL OR f.path LIKE (sqlc.narg(path_prefix)::text || '%'))
+  AND (sqlc.arg(include_tests)::bool OR f.domain IS DISTINCT FROM 'tests')
+ORDER BY sc.source_name, f.path, c.start_line;
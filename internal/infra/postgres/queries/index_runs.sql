@@ -0,0 +1,49 @@
+-- name: CreateIndexRun :one
+-- インデックス化の実行を開始状態（running）で記録する
+INSERT INTO index_runs (product_id, source_id, ref, force_init)
+VALUES (sqlc.arg(product_id), sqlc.arg(source_id), sqlc.narg(ref), sqlc.arg(force_init))
+RETURNING id, started_at;
+
+-- name: FinishIndexRun :exec
+-- インデックス化の実行の結果を記録する（所要時間は開始日時からの経過時間）
+UPDATE index_runs
+SET status = sqlc.arg(status),
+    snapshot_id = sqlc.narg(snapshot_id),
+    ref = COALESCE(sqlc.narg(ref), ref),
+    version_identifier = sqlc.narg(version_identifier),
+    files_processed = sqlc.arg(files_processed),
+    chunks = sqlc.arg(chunks),
+    failed_files = sqlc.arg(failed_files),
+    failed_embeddings = sqlc.arg(failed_embeddings),
+    input_tokens = sqlc.arg(input_tokens),
+    output_tokens = sqlc.arg(output_tokens),
+    cost_usd = sqlc.arg(cost_usd),
+    error = sqlc.narg(error),
+    finished_at = CURRENT_TIMESTAMP,
+    duration_ms = (EXTRACT(EPOCH FROM (CURRENT_TIMESTAMP - started_at)) * 1000)::bigint
+WHERE id = sqlc.arg(id);
+
+-- name: GetIndexRun :one
+SELECT
+    r.*,
+    p.name AS product_name,
+    s.name AS source_name
+FROM index_runs r
+INNER JOIN products p ON r.product_id = p.id
+INNER JOIN sources s ON r.source_id = s.id
+WHERE r.id = $1;
+
+-- name: ListIndexRuns :many
+-- インデックス化の実行履歴を開始日時の新しい順に取得する（プロダクト・ソース名・状態で絞り込み）
+SELECT
+    r.*,
+    p.name AS product_name,
+    s.name AS source_name
+FROM index_runs r
+INNER JOIN products p ON r.product_id = p.id
+INNER JOIN sources s ON r.source_id = s.id
+WHERE (sqlc.narg(product_id)::uuid IS NULL OR r.product_id = sqlc.narg(product_id)::uuid)
+  AND (sqlc.narg(source_name)::text IS NULL OR s.name = sqlc.narg(source_name)::text)
+  AND (sqlc.narg(status)::text IS NULL OR r.status = sqlc.narg(status)::text)
+ORDER BY r.started_at DESC
+LIMIT sqlc.arg(row_limit);
@@ -0,0 +1,36 @@
+-- name: CreateInfraResource :exec
+INSERT INTO infra_resources (
+    snapshot_id,
+    file_id,
+    file_path,
+    format,
+    kind,
+    name,
+    namespace,
+    spec
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8
+);
+
+-- name: DeleteInfraResourcesByFile :exec
+DELETE FROM infra_resources
+WHERE file_id = $1;
+
+-- name: ListInfraResources :many
+-- デプロイ構成のリソースを、プロダクトまたはスナップショットの範囲で取得する
+-- product_id を指定した場合は各ソースの最新のインデックス済みスナップショットを対象とする
+WITH scope_snapshots AS (
+    SELECT DISTINCT ON (ss.source_id) ss.id, s.name AS source_name
+    FROM source_snapshots ss
+    INNER JOIN sources s ON ss.source_id = s.id
+    WHERE ss.status = 'indexed'
+      AND (sqlc.narg(product_id)::uuid IS NULL OR s.product_id = sqlc.narg(product_id)::uuid)
+      AND (sqlc.narg(snapshot_id)::uuid IS NULL OR ss.id = sqlc.narg(snapshot_id)::uuid)
+    ORDER BY ss.source_id, ss.indexed_at DESC NULLS LAST, ss.created_at DESC
+)
+SELECT
+    ir.*,
+    sc.source_name::text AS source_name
+FROM infra_resources ir
+INNER JOIN scope_snapshots sc ON ir.snapshot_id = sc.id
+ORDER BY sc.source_name, ir.file_path, ir.kind, ir.namespace, ir.name;
@@ -0,0 +1,98 @@
+-- name: ListOrphanChunks :many
+-- インデックス済みスナップショットのチャンクのうち Embedding を持たないものを取得する
+-- content は Embedding の入力（切り詰めた embedding_context があればそれを優先）
+SELECT
+    c.id,
+    f.path,
+    c.start_line,
+    c.end_line,
+    COALESCE(c.embedding_context, c.content)::text AS content
+FROM chunks c
+INNER JOIN files f ON c.file_id = f.id
+INNER JOIN source_snapshots ss ON f.snapshot_id = ss.id
+LEFT JOIN embeddings e ON e.chunk_id = c.id
+WHERE ss.status = 'indexed'
+  AND e.chunk_id IS NULL
+ORDER BY f.path, c.ordinal;
+
+-- name: ListEmptyFiles :many
+-- インデックス済みスナップショットのファイルのうちチャンクを持たないものを取得する
+SELECT
+    f.id,
+    f.snapshot_id,
+    f.path
+FROM files f
+INNER JOIN source_snapshots ss ON f.snapshot_id = ss.id
+WHERE ss.status = 'indexed'
+  AND NOT EXISTS (SELECT 1 FROM chunks c WHERE c.file_id = f.id)
+ORDER BY f.snapshot_id, f.path;
+
+-- name: ListSnapshotFileMismatches :many
+-- snapshot_files のインデックス済みフラグが、チャンクを持つファイルの有無と一致しない行を取得する
+SELECT
+    sf.snapshot_id,
+    sf.file_path,
+    sf.indexed,
+    m.has_chunks
+FROM snapshot_files sf
+INNER JOIN source_snapshots ss ON sf.snapshot_id = ss.id
+CROSS JOIN LATERAL (
+    SELECT EXISTS (
+        SELECT 1
+        FROM files f
+        INNER JOIN chunks c ON c.file_id = f.id
+        WHERE f.snapshot_id = sf.snapshot_id
+          AND f.path = sf.file_path
+    ) AS has_chunks
+) m
+WHERE ss.status = 'indexed'
+  AND sf.indexed <> m.has_chunks
+ORDER BY sf.snapshot_id, sf.file_path;
+
+-- name: ListStaleLatestSources :many
+-- 最新フラグ（is_latest）が RefreshLatestChunks の判定と一致しないチャンクの数をソースごとに集計する
+WITH current_snapshots AS (
+    SELECT gr.snapshot_id AS id
+    FROM git_refs gr
+    UNION
+    SELECT id FROM (
+        SELECT DISTINCT ON (s.source_id) s.id
+        FROM source_snapshots s
+        WHERE s.status = 'indexed'
+        ORDER BY s.source_id, s.indexed_at DESC NULLS LAST, s.created_at DESC
+    ) latest
+)
+SELECT
+    src.id AS source_id,
+    src.name AS source_name,
+    COUNT(c.id)::bigint AS stale_chunks
+FROM chunks c
+INNER JOIN files f ON c.file_id = f.id
+INNER JOIN source_snapshots ss ON f.snapshot_id = ss.id
+INNER JOIN sources src ON ss.source_id = src.id
+WHERE c.is_latest <> (f.snapshot_id IN (SELECT id FROM current_snapshots))
+GROUP BY src.id, src.name
+ORDER BY src.name;
+
+-- name: ListDanglingDependencies :many
+-- 同じソースの別のスナップショットのチャンクを結ぶ依存関係を取得する
+SELECT
+    d.id,
+    d.dep_type,
+    COALESCE(d.symbol, '')::text AS symbol,
+    ff.path AS from_path,
+    tf.path AS to_path
+FROM chunk_dependencies d
+INNER JOIN chunks fc ON d.from_chunk_id = fc.id
+INNER JOIN files ff ON fc.file_id = ff.id
+INNER JOIN source_snapshots fs ON ff.snapshot_id = fs.id
+INNER JOIN chunks tc ON d.to_chunk_id = tc.id
+INNER JOIN files tf ON tc.file_id = tf.id
+INNER JOIN source_snapshots ts ON tf.snapshot_id = ts.id
+WHERE fs.source_id = ts.source_id
+  AND fs.id <> ts.id
+ORDER BY ff.path, tf.path;
+
+-- name: DeleteDependency :exec
+DELETE FROM chunk_dependencies
+WHERE id = $1;
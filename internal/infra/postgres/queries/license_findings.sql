@@ -0,0 +1,34 @@
+-- name: DeleteLicenseFindingsBySnapshot :exec
+DELETE FROM license_findings
+WHERE snapshot_id = $1;
+
+-- name: CreateLicenseFindings :copyfrom
+INSERT INTO license_findings (
+    snapshot_id,
+    file_path,
+    kind,
+    license_id,
+    category,
+    evidence
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+);
+
+-- name: ListLicenseFindings :many
+-- 検出したライセンスを、プロダクトまたはスナップショットの範囲で取得する
+-- product_id を指定した場合は各ソースの最新のインデックス済みスナップショットを対象とする
+WITH scope_snapshots AS (
+    SELECT DISTINCT ON (ss.source_id) ss.id, s.name AS source_name
+    FROM source_snapshots ss
+    INNER JOIN sources s ON ss.source_id = s.id
+    WHERE ss.status = 'indexed'
+      AND (sqlc.narg(product_id)::uuid IS NULL OR s.product_id = sqlc.narg(product_id)::uuid)
+      AND (sqlc.narg(snapshot_id)::uuid IS NULL OR ss.id = sqlc.narg(snapshot_id)::uuid)
+    ORDER BY ss.source_id, ss.indexed_at DESC NULLS LAST, ss.created_at DESC
+)
+SELECT
+    lf.*,
+    sc.source_name::text AS source_name
+FROM license_findings lf
+INNER JOIN scope_snapshots sc ON lf.snapshot_id = sc.id
+ORDER BY sc.source_name, lf.file_path;
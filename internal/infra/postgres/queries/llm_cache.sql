@@ -0,0 +1,34 @@
+-- name: HitLLMCache :one
+UPDATE llm_cache
+SET hit_count = hit_count + 1,
+    last_hit_at = CURRENT_TIMESTAMP
+WHERE key = $1
+  AND created_at >= sqlc.arg(not_before)
+RETURNING response;
+
+-- name: UpsertLLMCache :exec
+INSERT INTO llm_cache (key, prompt_name, prompt_version, model, response)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (key) DO UPDATE
+SET response = EXCLUDED.response,
+    hit_count = 0,
+    created_at = CURRENT_TIMESTAMP,
+    last_hit_at = NULL;
+
+-- name: ListLLMCacheStats :many
+SELECT
+    prompt_name,
+    prompt_version,
+    model,
+    COUNT(*)::int AS entries,
+    COALESCE(SUM(hit_count), 0)::bigint AS hits,
+    COALESCE(SUM(LENGTH(response)), 0)::bigint AS response_bytes,
+    MAX(last_hit_at)::timestamp AS last_hit_at
+FROM llm_cache
+GROUP BY prompt_name, prompt_version, model
+ORDER BY prompt_name, prompt_version, model;
+
+-- name: DeleteLLMCache :execrows
+DELETE FROM llm_cache
+WHERE (sqlc.narg(prompt_name)::text IS NULL OR prompt_name = sqlc.narg(prompt_name))
+  AND created_at < sqlc.arg(created_before);
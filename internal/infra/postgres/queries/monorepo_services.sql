@@ -0,0 +1,35 @@
+-- name: DeleteMonorepoServicesBySnapshot :exec
+DELETE FROM monorepo_services
+WHERE snapshot_id = $1;
+
+-- name: CreateMonorepoServices :copyfrom
+INSERT INTO monorepo_services (
+    snapshot_id,
+    path,
+    name,
+    kind,
+    manifest_path,
+    targets
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+);
+
+-- name: ListMonorepoServices :many
+-- 検出したサービスを、プロダクトまたはスナップショットの範囲で取得する
+-- product_id を指定した場合は各ソースの最新のインデックス済みスナップショットを対象とする
+WITH scope_snapshots AS (
+    SELECT DISTINCT ON (ss.source_id) ss.id, ss.version_identifier, s.name AS source_name
+    FROM source_snapshots ss
+    INNER JOIN sources s ON ss.source_id = s.id
+    WHERE ss.status = 'indexed'
+      AND (sqlc.narg(product_id)::uuid IS NULL OR s.product_id = sqlc.narg(product_id)::uuid)
+      AND (sqlc.narg(snapshot_id)::uuid IS NULL OR ss.id = sqlc.narg(snapshot_id)::uuid)
+    ORDER BY ss.source_id, ss.indexed_at DESC NULLS LAST, ss.created_at DESC
+)
+SELECT
+    ms.*,
+    sc.source_name::text AS source_name,
+    sc.version_identifier::text AS version_identifier
+FROM monorepo_services ms
+INNER JOIN scope_snapshots sc ON ms.snapshot_id = sc.id
+ORDER BY sc.source_name, ms.path;
@@ -0,0 +1,16 @@
+-- name: ListProductConfigs :many
+SELECT * FROM product_configs
+WHERE product_id = $1
+ORDER BY key;
+
+-- name: UpsertProductConfig :one
+INSERT INTO product_configs (product_id, key, value)
+VALUES ($1, $2, $3)
+ON CONFLICT (product_id, key) DO UPDATE
+SET value = EXCLUDED.value,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: DeleteProductConfig :execrows
+DELETE FROM product_configs
+WHERE product_id = $1 AND key = $2;
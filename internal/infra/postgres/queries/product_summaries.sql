@@ -0,0 +1,43 @@
+-- name: GetProductSummary :one
+SELECT * FROM product_summaries
+WHERE product_id = $1;
+
+-- name: UpsertProductSummary :exec
+INSERT INTO product_summaries (product_id, content, description, source_hash, prompt_version, vector, model)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (product_id) DO UPDATE
+SET content = EXCLUDED.content,
+    description = EXCLUDED.description,
+    source_hash = EXCLUDED.source_hash,
+    prompt_version = EXCLUDED.prompt_version,
+    vector = EXCLUDED.vector,
+    model = EXCLUDED.model,
+    updated_at = CURRENT_TIMESTAMP;
+
+-- name: FillProductDescription :execrows
+-- 利用者が設定した説明は上書きしない（未設定、または前回自動で設定した説明のままの場合のみ更新する）
+UPDATE products
+SET description = sqlc.arg(description), updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)
+  AND (description IS NULL OR description = '' OR description = sqlc.arg(previous)::text)
+  AND description IS DISTINCT FROM sqlc.arg(description);
+
+-- name: ListLatestArchitectureSummariesByProduct :many
+-- プロダクトの各ソースの最新のインデックス済みスナップショットのアーキテクチャ要約（プロダクト全体の要約の入力）
+WITH latest_snapshots AS (
+    SELECT DISTINCT ON (ss.source_id) ss.id, s.name AS source_name
+    FROM source_snapshots ss
+    INNER JOIN sources s ON ss.source_id = s.id
+    WHERE ss.status = 'indexed'
+      AND s.product_id = sqlc.arg(product_id)
+    ORDER BY ss.source_id, ss.indexed_at DESC NULLS LAST, ss.created_at DESC
+)
+SELECT
+    ls.source_name::text AS source_name,
+    s.arch_type,
+    s.content,
+    s.content_hash
+FROM summaries s
+INNER JOIN latest_snapshots ls ON s.snapshot_id = ls.id
+WHERE s.summary_type = 'architecture'
+ORDER BY ls.source_name, s.arch_type;
@@ -21,6 +21,12 @@ SET name = $2, description = $3, updated_at = CURRENT_TIMESTAMP
 WHERE id = $1
 RETURNING *;
 
+-- name: UpdateProductRecencyWeight :one
+UPDATE products
+SET recency_weight = sqlc.narg(recency_weight), updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
 -- name: DeleteProduct :exec
 DELETE FROM products
 WHERE id = $1;
@@ -34,10 +40,12 @@ SELECT
     p.updated_at,
     COUNT(DISTINCT s.id)::int AS source_count,
     MAX(ss.indexed_at) AS last_indexed_at,
-    MAX(wm.generated_at) AS wiki_generated_at
+    MAX(wm.generated_at) AS wiki_generated_at,
+    ps.content AS summary
 FROM products p
 LEFT JOIN sources s ON p.id = s.product_id
-LEFT JOIN source_snapshots ss ON s.id = ss.source_id AND ss.indexed = TRUE
+LEFT JOIN source_snapshots ss ON s.id = ss.source_id AND ss.status = 'indexed'
 LEFT JOIN wiki_metadata wm ON p.id = wm.product_id
-GROUP BY p.id, p.name, p.description, p.created_at, p.updated_at
+LEFT JOIN product_summaries ps ON p.id = ps.product_id
+GROUP BY p.id, p.name, p.description, p.created_at, p.updated_at, ps.content
 ORDER BY p.name;
@@ -0,0 +1,20 @@
+-- name: GetPromptTemplate :one
+SELECT * FROM prompt_templates
+WHERE name = $1;
+
+-- name: ListPromptTemplates :many
+SELECT * FROM prompt_templates
+ORDER BY name;
+
+-- name: UpsertPromptTemplate :one
+INSERT INTO prompt_templates (name, version, body)
+VALUES ($1, $2, $3)
+ON CONFLICT (name) DO UPDATE
+SET version = EXCLUDED.version,
+    body = EXCLUDED.body,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: DeletePromptTemplate :execrows
+DELETE FROM prompt_templates
+WHERE name = $1;
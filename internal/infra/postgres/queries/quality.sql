@@ -0,0 +1,57 @@
+-- name: CreateQualityNote :one
+INSERT INTO quality_notes (note_id, severity, note_text, linked_files, linked_chunks, reviewer)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: ListQualityNotes :many
+-- 品質ノートを新しい順に取得する（ステータス・深刻度・記録日時の範囲で絞り込み）
+SELECT * FROM quality_notes
+WHERE (sqlc.narg(status)::text IS NULL OR status = sqlc.narg(status)::text)
+  AND (sqlc.narg(severity)::text IS NULL OR severity = sqlc.narg(severity)::text)
+  AND (sqlc.narg(created_from)::timestamp IS NULL OR created_at >= sqlc.narg(created_from)::timestamp)
+  AND (sqlc.narg(created_to)::timestamp IS NULL OR created_at < sqlc.narg(created_to)::timestamp)
+ORDER BY created_at DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: MaxQualityNoteNumber :one
+-- 接頭辞（例: QN-2025-）に続く番号の最大値を取得する（ない場合は 0）
+SELECT COALESCE(MAX(substring(note_id FROM '(\d+)$')::integer), 0)::integer AS max_number
+FROM quality_notes
+WHERE note_id LIKE sqlc.arg(prefix)::text || '%';
+
+-- name: CreateActionBacklog :one
+INSERT INTO action_backlog (action_id, prompt_version, priority, action_type, title, description, linked_files, owner_hint, acceptance_criteria, status)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING *;
+
+-- name: ListActionBacklog :many
+-- 品質改善アクションを優先度・作成日時の順に取得する（ステータスで絞り込み）
+SELECT * FROM action_backlog
+WHERE (sqlc.narg(status)::text IS NULL OR status = sqlc.narg(status)::text)
+ORDER BY priority, created_at DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: MaxActionNumber :one
+-- 接頭辞（例: ACT-2025-）に続く番号の最大値を取得する（ない場合は 0）
+SELECT COALESCE(MAX(substring(action_id FROM '(\d+)$')::integer), 0)::integer AS max_number
+FROM action_backlog
+WHERE action_id LIKE sqlc.arg(prefix)::text || '%';
+
+-- name: ListRecentChangesByPaths :many
+-- プロダクトの最新のチャンクから、指定したパスのファイルを変更したコミットを取得する（since 以降に更新されたもの）
+SELECT
+    c.git_commit_hash::text AS commit_hash,
+    array_agg(DISTINCT f.path ORDER BY f.path)::text[] AS paths,
+    MAX(c.updated_at)::timestamp AS merged_at
+FROM chunks c
+INNER JOIN files f ON c.file_id = f.id
+INNER JOIN source_snapshots ss ON f.snapshot_id = ss.id
+INNER JOIN sources s ON ss.source_id = s.id
+WHERE s.product_id = sqlc.arg(product_id)
+  AND c.is_latest = TRUE
+  AND c.git_commit_hash IS NOT NULL
+  AND c.updated_at >= sqlc.arg(since)::timestamp
+  AND f.path = ANY(sqlc.arg(paths)::text[])
+GROUP BY c.git_commit_hash
+ORDER BY merged_at DESC
+LIMIT sqlc.arg(row_limit);
@@ -0,0 +1,118 @@
+-- name: FindIndexedSnapshotByCommit :one
+-- リリースノートの比較対象として、ソースのコミットのインデックス済みスナップショットを取得する
+SELECT id FROM source_snapshots
+WHERE source_id = $1
+  AND version_identifier = $2
+  AND status = 'indexed'
+ORDER BY indexed_at DESC NULLS LAST
+LIMIT 1;
+
+-- name: ListReleaseChunkChanges :many
+-- 変更されたファイルの宣言（レベル2のチャンク）のうち、from と to で内容が異なるものを重要度順に取得する
+-- 同じファイル・親・名前・種類の宣言を同じ宣言とみなし、from にない宣言は added、to にない宣言は removed、両方にある宣言は modified とする
+-- from_snapshot_id が NULL の場合は to の宣言をすべて changed とする
+WITH to_chunks AS (
+    SELECT f.path, c.parent_name, c.chunk_name, c.chunk_type, c.signature, c.doc_comment, c.content_hash, c.importance_score, c.start_line
+    FROM chunks c
+    INNER JOIN files f ON f.id = c.file_id
+    WHERE f.snapshot_id = sqlc.arg(to_snapshot_id)
+      AND f.path = ANY(sqlc.arg(paths)::text[])
+      AND c.level = 2
+      AND c.chunk_name IS NOT NULL
+),
+from_chunks AS (
+    SELECT f.path, c.parent_name, c.chunk_name, c.chunk_type, c.signature, c.doc_comment, c.content_hash, c.importance_score, c.start_line
+    FROM chunks c
+    INNER JOIN files f ON f.id = c.file_id
+    WHERE f.snapshot_id = sqlc.narg(from_snapshot_id)::uuid
+      AND f.path = ANY(sqlc.arg(paths)::text[])
+      AND c.level = 2
+      AND c.chunk_name IS NOT NULL
+)
+SELECT
+    COALESCE(t.path, fr.path)::text AS path,
+    COALESCE(t.parent_name, fr.parent_name, '')::text AS parent_name,
+    COALESCE(t.chunk_name, fr.chunk_name)::text AS chunk_name,
+    COALESCE(t.chunk_type, fr.chunk_type, '')::text AS chunk_type,
+    COALESCE(t.signature, fr.signature, '')::text AS signature,
+    COALESCE(t.doc_comment, fr.doc_comment, '')::text AS doc_comment,
+    (CASE
+        WHEN sqlc.narg(from_snapshot_id)::uuid IS NULL THEN 'changed'
+        WHEN fr.path IS NULL THEN 'added'
+        WHEN t.path IS NULL THEN 'removed'
+        ELSE 'modified'
+    END)::text AS change
+FROM to_chunks t
+FULL OUTER JOIN from_chunks fr
+    ON fr.path = t.path
+   AND fr.parent_name IS NOT DISTINCT FROM t.parent_name
+   AND fr.chunk_name = t.chunk_name
+   AND fr.chunk_type IS NOT DISTINCT FROM t.chunk_type
+WHERE t.content_hash IS DISTINCT FROM fr.content_hash
+ORDER BY COALESCE(t.importance_score, fr.importance_score) DESC NULLS LAST,
+         COALESCE(t.path, fr.path),
+         COALESCE(t.start_line, fr.start_line)
+LIMIT sqlc.arg(row_limit);
+
+-- name: ListReleaseFileSummaries :many
+-- 変更されたファイルの要約を取得する
+SELECT target_path, content FROM summaries
+WHERE snapshot_id = sqlc.arg(snapshot_id)
+  AND summary_type = 'file'
+  AND target_path = ANY(sqlc.arg(paths)::text[])
+ORDER BY target_path;
+
+-- name: ListReleaseDependents :many
+-- 変更されたファイルの宣言に依存する他のファイルを、変更されたファイルごとに依存の多い順に取得する
+SELECT
+    tf.path::text AS path,
+    ff.path::text AS dependent_path,
+    count(*)::int AS edge_count,
+    COALESCE(array_agg(DISTINCT d.symbol) FILTER (WHERE d.symbol IS NOT NULL AND d.symbol <> ''), '{}')::text[] AS symbols
+FROM chunk_dependencies d
+INNER JOIN chunks tc ON tc.id = d.to_chunk_id
+INNER JOIN files tf ON tf.id = tc.file_id
+INNER JOIN chunks fc ON fc.id = d.from_chunk_id
+INNER JOIN files ff ON ff.id = fc.file_id
+WHERE tf.snapshot_id = sqlc.arg(snapshot_id)
+  AND ff.snapshot_id = sqlc.arg(snapshot_id)
+  AND tf.path = ANY(sqlc.arg(paths)::text[])
+  AND ff.path <> tf.path
+GROUP BY tf.path, ff.path
+ORDER BY tf.path, edge_count DESC, ff.path;
+
+-- name: UpsertReleaseNote :one
+INSERT INTO release_notes (
+    source_id,
+    from_ref,
+    to_ref,
+    from_commit,
+    to_commit,
+    released_at,
+    commit_count,
+    file_count,
+    content,
+    prompt_version,
+    updated_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, CURRENT_TIMESTAMP
+)
+ON CONFLICT (source_id, from_ref, to_ref)
+DO UPDATE SET
+    from_commit = EXCLUDED.from_commit,
+    to_commit = EXCLUDED.to_commit,
+    released_at = EXCLUDED.released_at,
+    commit_count = EXCLUDED.commit_count,
+    file_count = EXCLUDED.file_count,
+    content = EXCLUDED.content,
+    prompt_version = EXCLUDED.prompt_version,
+    updated_at = EXCLUDED.updated_at
+RETURNING *;
+
+-- name: ListReleaseNotesByProduct :many
+-- プロダクトのリリースノートを to のコミット日時の新しい順に取得する
+SELECT rn.*, s.name::text AS source_name
+FROM release_notes rn
+INNER JOIN sources s ON s.id = rn.source_id
+WHERE s.product_id = $1
+ORDER BY rn.released_at DESC, s.name, rn.to_ref;
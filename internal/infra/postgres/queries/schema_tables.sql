@@ -0,0 +1,34 @@
+-- name: DeleteSchemaTablesBySnapshot :exec
+DELETE FROM schema_tables
+WHERE snapshot_id = $1;
+
+-- name: CreateSchemaTable :exec
+INSERT INTO schema_tables (
+    snapshot_id,
+    name,
+    comment,
+    columns,
+    indexes,
+    source_paths
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+);
+
+-- name: ListSchemaTables :many
+-- スキーマカタログのテーブルを、プロダクトまたはスナップショットの範囲で取得する
+-- product_id を指定した場合は各ソースの最新のインデックス済みスナップショットを対象とする
+WITH scope_snapshots AS (
+    SELECT DISTINCT ON (ss.source_id) ss.id, s.name AS source_name
+    FROM source_snapshots ss
+    INNER JOIN sources s ON ss.source_id = s.id
+    WHERE ss.status = 'indexed'
+      AND (sqlc.narg(product_id)::uuid IS NULL OR s.product_id = sqlc.narg(product_id)::uuid)
+      AND (sqlc.narg(snapshot_id)::uuid IS NULL OR ss.id = sqlc.narg(snapshot_id)::uuid)
+    ORDER BY ss.source_id, ss.indexed_at DESC NULLS LAST, ss.created_at DESC
+)
+SELECT
+    st.*,
+    sc.source_name::text AS source_name
+FROM schema_tables st
+INNER JOIN scope_snapshots sc ON st.snapshot_id = sc.id
+ORDER BY sc.source_name, st.name;
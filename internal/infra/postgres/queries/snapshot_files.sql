@@ -63,3 +63,108 @@ AND (
     OR file_path IN ('package.json', 'go.mod', 'Dockerfile', 'docker-compose.yml')
 )
 ORDER BY file_path;
+
+-- name: ListDirectoryCoverage :many
+-- オンボーディングガイド用に、ソース・トップレベルディレクトリ・ドメインごとのファイル数とインデックス済みファイル数を取得する
+-- product_id 指定時はプロダクトの各ソースの最新スナップショット、snapshot_id 指定時はそのスナップショットを対象とする
+-- ルート直下のファイルのディレクトリは空文字とする
+WITH scope_snapshots AS (
+    SELECT DISTINCT ON (ss.source_id) ss.id, s.name AS source_name
+    FROM source_snapshots ss
+    INNER JOIN sources s ON ss.source_id = s.id
+    WHERE ss.status = 'indexed'
+      AND (sqlc.narg(product_id)::uuid IS NULL OR s.product_id = sqlc.narg(product_id)::uuid)
+      AND (sqlc.narg(snapshot_id)::uuid IS NULL OR ss.id = sqlc.narg(snapshot_id)::uuid)
+    ORDER BY ss.source_id, ss.indexed_at DESC NULLS LAST, ss.created_at DESC
+),
+dir_files AS (
+    SELECT
+        sc.source_name,
+        CASE WHEN strpos(sf.file_path, '/') > 0 THEN split_part(sf.file_path, '/', 1) ELSE '' END AS directory,
+        COALESCE(sf.domain, 'unknown') AS domain,
+        sf.indexed
+    FROM snapshot_files sf
+    INNER JOIN scope_snapshots sc ON sf.snapshot_id = sc.id
+)
+SELECT
+    source_name::text AS source_name,
+    directory::text AS directory,
+    domain::text AS domain,
+    COUNT(*)::bigint AS total_files,
+    COUNT(*) FILTER (WHERE indexed)::bigint AS indexed_files
+FROM dir_files
+GROUP BY source_name, directory, domain
+ORDER BY source_name, directory, domain;
+
+-- name: RecordSnapshotFiles :exec
+-- スナップショットの全ファイル（インデックス対象外を含む）を一括で記録する
+-- インデックス済みかは files テーブルにファイルがあるかで判定し、インデックスされなかったファイルの理由は skip_reasons（空文字は理由なし）とする
+INSERT INTO snapshot_files (snapshot_id, file_path, file_size, domain, indexed, skip_reason)
+SELECT
+    sqlc.arg(snapshot_id),
+    input.file_path,
+    input.file_size,
+    NULLIF(input.domain, ''),
+    EXISTS (SELECT 1 FROM files f WHERE f.snapshot_id = sqlc.arg(snapshot_id) AND f.path = input.file_path),
+    CASE
+        WHEN EXISTS (SELECT 1 FROM files f WHERE f.snapshot_id = sqlc.arg(snapshot_id) AND f.path = input.file_path) THEN NULL
+        ELSE NULLIF(input.skip_reason, '')
+    END
+FROM (
+    SELECT
+        unnest(sqlc.arg(file_paths)::text[]) AS file_path,
+        unnest(sqlc.arg(file_sizes)::bigint[]) AS file_size,
+        unnest(sqlc.arg(domains)::text[]) AS domain,
+        unnest(sqlc.arg(skip_reasons)::text[]) AS skip_reason
+) input
+ON CONFLICT (snapshot_id, file_path) DO UPDATE
+SET file_size = EXCLUDED.file_size,
+    domain = EXCLUDED.domain,
+    indexed = EXCLUDED.indexed,
+    skip_reason = EXCLUDED.skip_reason;
+
+-- name: ListSourceFileLines :many
+-- Wikiの参照チェック用に、対象スナップショットのファイルパスと行数（インデックス済みチャンクの最終行）を取得する
+-- 対象スナップショットの決め方は ListDirectoryCoverage と同じ
+-- インデックス対象外のファイルも snapshot_files から含め、チャンクを持たないファイルの行数は 0 とする
+WITH scope_snapshots AS (
+    SELECT DISTINCT ON (ss.source_id) ss.id
+    FROM source_snapshots ss
+    INNER JOIN sources s ON ss.source_id = s.id
+    WHERE ss.status = 'indexed'
+      AND (sqlc.narg(product_id)::uuid IS NULL OR s.product_id = sqlc.narg(product_id)::uuid)
+      AND (sqlc.narg(snapshot_id)::uuid IS NULL OR ss.id = sqlc.narg(snapshot_id)::uuid)
+    ORDER BY ss.source_id, ss.indexed_at DESC NULLS LAST, ss.created_at DESC
+),
+scope_paths AS (
+    SELECT sf.snapshot_id, sf.file_path AS path
+    FROM snapshot_files sf
+    INNER JOIN scope_snapshots sc ON sf.snapshot_id = sc.id
+    UNION
+    SELECT f.snapshot_id, f.path
+    FROM files f
+    INNER JOIN scope_snapshots sc ON f.snapshot_id = sc.id
+)
+SELECT
+    sp.path::text AS path,
+    COALESCE(MAX(c.end_line), 0)::int AS line_count
+FROM scope_paths sp
+LEFT JOIN files f ON f.snapshot_id = sp.snapshot_id AND f.path = sp.path
+LEFT JOIN chunks c ON c.file_id = f.id
+GROUP BY sp.path
+ORDER BY sp.path;
+
+-- name: ListScopeSnapshots :many
+-- Wikiの目次（toc.json）に記録する、生成の対象としたスナップショットをソース名順に取得する
+-- 対象スナップショットの決め方は ListDirectoryCoverage と同じ
+SELECT DISTINCT ON (s.name)
+    s.name AS source_name,
+    ss.id AS snapshot_id,
+    ss.version_identifier,
+    ss.indexed_at
+FROM source_snapshots ss
+INNER JOIN sources s ON ss.source_id = s.id
+WHERE ss.status = 'indexed'
+  AND (sqlc.narg(product_id)::uuid IS NULL OR s.product_id = sqlc.narg(product_id)::uuid)
+  AND (sqlc.narg(snapshot_id)::uuid IS NULL OR ss.id = sqlc.narg(snapshot_id)::uuid)
+ORDER BY s.name, ss.indexed_at DESC NULLS LAST, ss.created_at DESC;
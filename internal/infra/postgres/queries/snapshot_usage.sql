@@ -0,0 +1,15 @@
+-- name: AddSnapshotUsage :exec
+INSERT INTO snapshot_usage (snapshot_id, kind, model, requests, input_tokens, output_tokens, cost_usd)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (snapshot_id, kind, model)
+DO UPDATE SET
+    requests = snapshot_usage.requests + EXCLUDED.requests,
+    input_tokens = snapshot_usage.input_tokens + EXCLUDED.input_tokens,
+    output_tokens = snapshot_usage.output_tokens + EXCLUDED.output_tokens,
+    cost_usd = snapshot_usage.cost_usd + EXCLUDED.cost_usd,
+    updated_at = CURRENT_TIMESTAMP;
+
+-- name: ListSnapshotUsage :many
+SELECT * FROM snapshot_usage
+WHERE snapshot_id = $1
+ORDER BY kind, model;
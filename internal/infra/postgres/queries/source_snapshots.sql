@@ -18,21 +18,117 @@ ORDER BY created_at DESC;
 
 -- name: GetLatestIndexedSnapshot :one
 SELECT * FROM source_snapshots
-WHERE source_id = $1 AND indexed = TRUE
+WHERE source_id = $1 AND status = 'indexed'
 ORDER BY indexed_at DESC NULLS LAST, created_at DESC
 LIMIT 1;
 
 -- name: ListIndexedSnapshots :many
 SELECT * FROM source_snapshots
-WHERE indexed = TRUE
+WHERE status = 'indexed'
 ORDER BY indexed_at DESC;
 
 -- name: MarkSnapshotIndexed :one
 UPDATE source_snapshots
-SET indexed = TRUE, indexed_at = CURRENT_TIMESTAMP
+SET status = 'indexed', indexed_at = CURRENT_TIMESTAMP, failed_at = NULL, failure_reason = NULL
 WHERE id = $1
 RETURNING *;
 
+-- name: MarkSnapshotIndexing :exec
+-- スナップショットのインデックス化の開始を記録する（前回の失敗の記録は消去する）
+UPDATE source_snapshots
+SET status = 'indexing', indexing_started_at = CURRENT_TIMESTAMP, failed_at = NULL, failure_reason = NULL
+WHERE id = $1 AND status <> 'indexed';
+
+-- name: MarkSnapshotFailed :exec
+-- インデックス化に失敗・中断したスナップショットを記録する（インデックス済みのものは変更しない）
+UPDATE source_snapshots
+SET status = 'failed', failed_at = CURRENT_TIMESTAMP, failure_reason = sqlc.arg(failure_reason)
+WHERE id = sqlc.arg(id) AND status <> 'indexed';
+
+-- name: DeleteIncompleteSnapshotFiles :execrows
+-- 中断したスナップショットの再開前に、Embedding の保存が完了していないファイルを削除する（チャンク・Embedding は CASCADE で削除）
+-- チャンクの保存に失敗した可能性があるチャンクのないファイルも削除する
+-- Embedding の生成に失敗して embedding_failures に記録したチャンクは完了したものとして扱う
+DELETE FROM files f
+WHERE f.snapshot_id = $1
+  AND (
+      NOT EXISTS (SELECT 1 FROM chunks c WHERE c.file_id = f.id)
+      OR EXISTS (
+          SELECT 1 FROM chunks c
+          WHERE c.file_id = f.id
+            AND NOT EXISTS (SELECT 1 FROM embeddings e WHERE e.chunk_id = c.id)
+            AND NOT EXISTS (SELECT 1 FROM embedding_failures ef WHERE ef.chunk_id = c.id)
+      )
+  );
+
 -- name: DeleteSourceSnapshot :exec
 DELETE FROM source_snapshots
 WHERE id = $1;
+
+-- name: GetSnapshotStats :one
+-- スナップショットのファイル数・チャンク数・Embedding数を集計
+SELECT
+    (SELECT COUNT(*) FROM files f WHERE f.snapshot_id = $1)::bigint AS file_count,
+    (SELECT COUNT(*) FROM chunks c
+        JOIN files f ON c.file_id = f.id
+        WHERE f.snapshot_id = $1)::bigint AS chunk_count,
+    (SELECT COUNT(*) FROM embeddings e
+        JOIN chunks c ON e.chunk_id = c.id
+        JOIN files f ON c.file_id = f.id
+        WHERE f.snapshot_id = $1)::bigint AS embedding_count;
+
+-- name: GetSnapshotIndexHealth :one
+-- スナップショットのインデックス品質の診断に使用する件数・トークン数を集計
+-- orphan_chunk_count は Embedding を持たないチャンク、oversized_chunk_count はトークン数が上限を超えるチャンクの数
+SELECT
+    COUNT(DISTINCT f.id)::bigint AS file_count,
+    COUNT(c.id)::bigint AS chunk_count,
+    COUNT(e.chunk_id)::bigint AS embedding_count,
+    COALESCE(AVG(c.token_count), 0)::float8 AS avg_tokens,
+    COALESCE(MAX(c.token_count), 0)::int AS max_tokens,
+    COUNT(c.id) FILTER (WHERE e.chunk_id IS NULL)::bigint AS orphan_chunk_count,
+    COUNT(c.id) FILTER (WHERE c.token_count > sqlc.arg(token_limit)::int)::bigint AS oversized_chunk_count
+FROM files f
+LEFT JOIN chunks c ON c.file_id = f.id
+LEFT JOIN embeddings e ON e.chunk_id = c.id
+WHERE f.snapshot_id = sqlc.arg(snapshot_id);
+
+-- name: ListSnapshotChunkBreakdown :many
+-- スナップショット内のチャンク数をレベル・種類・ドメイン・Embeddingモデル別に集計
+SELECT 'level'::text AS dimension, c.level::text AS key, COUNT(*)::bigint AS chunk_count
+FROM chunks c
+JOIN files f ON c.file_id = f.id
+WHERE f.snapshot_id = $1
+GROUP BY c.level
+UNION ALL
+SELECT 'type'::text, COALESCE(c.chunk_type, 'unknown')::text, COUNT(*)::bigint
+FROM chunks c
+JOIN files f ON c.file_id = f.id
+WHERE f.snapshot_id = $1
+GROUP BY COALESCE(c.chunk_type, 'unknown')
+UNION ALL
+SELECT 'domain'::text, COALESCE(f.domain, 'unknown')::text, COUNT(*)::bigint
+FROM chunks c
+JOIN files f ON c.file_id = f.id
+WHERE f.snapshot_id = $1
+GROUP BY COALESCE(f.domain, 'unknown')
+UNION ALL
+SELECT 'embedding_model'::text, e.model::text, COUNT(*)::bigint
+FROM embeddings e
+JOIN chunks c ON e.chunk_id = c.id
+JOIN files f ON c.file_id = f.id
+WHERE f.snapshot_id = $1
+GROUP BY e.model
+ORDER BY dimension, chunk_count DESC, key;
+
+-- name: GetSnapshotDomainStats :many
+-- スナップショット内のドメイン別ファイル数・チャンク数を集計
+SELECT
+    COALESCE(f.domain, 'unknown')::text AS domain,
+    COUNT(DISTINCT f.id)::bigint AS file_count,
+    COUNT(c.id)::bigint AS chunk_count
+FROM files f
+LEFT JOIN chunks c ON c.file_id = f.id
+WHERE f.snapshot_id = $1
+GROUP BY COALESCE(f.domain, 'unknown')
+ORDER BY COALESCE(f.domain, 'unknown');
@@ -17,35 +17,35 @@ RETURNING *;
 DELETE FROM summary_embeddings WHERE summary_id = $1;
 
 -- name: SearchSummaryEmbeddings :many
-SELECT s.*, se.vector, (1 - (se.vector <=> $1::vector))::float8 AS score
+SELECT s.*, se.vector, (1 - (se.vector <=> $1))::float8 AS score
 FROM summaries s
 JOIN summary_embeddings se ON s.id = se.summary_id
 WHERE s.snapshot_id = $2
-ORDER BY se.vector <=> $1::vector
+ORDER BY se.vector <=> $1
 LIMIT $3;
 
 -- name: SearchFileSummaryEmbeddings :many
-SELECT s.*, se.vector, (1 - (se.vector <=> $1::vector))::float8 AS score
+SELECT s.*, se.vector, (1 - (se.vector <=> $1))::float8 AS score
 FROM summaries s
 JOIN summary_embeddings se ON s.id = se.summary_id
 WHERE s.snapshot_id = $2 AND s.summary_type = 'file'
-ORDER BY se.vector <=> $1::vector
+ORDER BY se.vector <=> $1
 LIMIT $3;
 
 -- name: SearchDirectorySummaryEmbeddings :many
-SELECT s.*, se.vector, (1 - (se.vector <=> $1::vector))::float8 AS score
+SELECT s.*, se.vector, (1 - (se.vector <=> $1))::float8 AS score
 FROM summaries s
 JOIN summary_embeddings se ON s.id = se.summary_id
 WHERE s.snapshot_id = $2 AND s.summary_type = 'directory'
-ORDER BY se.vector <=> $1::vector
+ORDER BY se.vector <=> $1
 LIMIT $3;
 
 -- name: SearchArchitectureSummaryEmbeddings :many
-SELECT s.*, se.vector, (1 - (se.vector <=> $1::vector))::float8 AS score
+SELECT s.*, se.vector, (1 - (se.vector <=> $1))::float8 AS score
 FROM summaries s
 JOIN summary_embeddings se ON s.id = se.summary_id
 WHERE s.snapshot_id = $2 AND s.summary_type = 'architecture'
-ORDER BY se.vector <=> $1::vector
+ORDER BY se.vector <=> $1
 LIMIT $3;
 
 -- name: DeleteSummaryEmbeddingsBySnapshot :exec
@@ -66,20 +66,22 @@ SELECT
     s.target_path,
     s.arch_type,
     s.content,
-    (1 - (se.vector <=> sqlc.arg(query_vector)::vector))::float8 as score
+    (1 - (se.vector <=> sqlc.arg(query_vector)))::float8 as score
 FROM summaries s
 JOIN summary_embeddings se ON s.id = se.summary_id
 WHERE s.snapshot_id = sqlc.arg(snapshot_id)
   AND (cardinality(sqlc.arg(summary_types)::text[]) = 0 OR s.summary_type = ANY(sqlc.arg(summary_types)::text[]))
   AND (sqlc.arg(path_prefix)::text IS NULL OR s.target_path LIKE sqlc.arg(path_prefix)::text || '%')
-ORDER BY se.vector <=> sqlc.arg(query_vector)::vector
+ORDER BY se.vector <=> sqlc.arg(query_vector)
 LIMIT sqlc.arg(limit_val);
 
 -- name: SearchSummariesByProduct :many
+-- snapshot_id 指定時は最新スナップショットの代わりにそのスナップショットのみを対象とする
 WITH latest_snapshots AS (
     SELECT DISTINCT ON (source_id) id, source_id
     FROM source_snapshots
-    WHERE indexed = TRUE
+    WHERE status = 'indexed'
+      AND (sqlc.narg(snapshot_id)::uuid IS NULL OR id = sqlc.narg(snapshot_id)::uuid)
     ORDER BY source_id, indexed_at DESC NULLS LAST, created_at DESC
 )
 SELECT
@@ -89,7 +91,7 @@ SELECT
     s.target_path,
     s.arch_type,
     s.content,
-    (1 - (se.vector <=> sqlc.arg(query_vector)::vector))::float8 as score
+    (1 - (se.vector <=> sqlc.arg(query_vector)))::float8 as score
 FROM summaries s
 JOIN summary_embeddings se ON s.id = se.summary_id
 JOIN latest_snapshots ls ON s.snapshot_id = ls.id
@@ -97,5 +99,67 @@ JOIN sources src ON ls.source_id = src.id
 WHERE src.product_id = sqlc.arg(product_id)
   AND (cardinality(sqlc.arg(summary_types)::text[]) = 0 OR s.summary_type = ANY(sqlc.arg(summary_types)::text[]))
   AND (sqlc.narg(path_prefix)::text IS NULL OR s.target_path LIKE sqlc.narg(path_prefix)::text || '%')
-ORDER BY se.vector <=> sqlc.arg(query_vector)::vector
+ORDER BY se.vector <=> sqlc.arg(query_vector)
+LIMIT sqlc.arg(limit_val);
+
+-- name: SearchFileSummariesByScope :many
+-- 2段階検索の1段目としてファイル要約をベクトル検索し、候補ファイルを返す
+-- product_id / source_id / snapshot_id のうち指定されたもので範囲を限定する（snapshot_id 指定時は最新スナップショットの代わりに使用）
+WITH latest_snapshots AS (
+    SELECT DISTINCT ON (source_id) id, source_id
+    FROM source_snapshots
+    WHERE status = 'indexed'
+      AND (sqlc.narg(snapshot_id)::uuid IS NULL OR id = sqlc.narg(snapshot_id)::uuid)
+      AND (sqlc.narg(source_id)::uuid IS NULL OR source_id = sqlc.narg(source_id)::uuid)
+    ORDER BY source_id, indexed_at DESC NULLS LAST, created_at DESC
+)
+SELECT
+    f.id AS file_id,
+    f.path,
+    s.content,
+    (1 - (se.vector <=> sqlc.arg(query_vector)))::float8 AS score
+FROM summaries s
+JOIN summary_embeddings se ON s.id = se.summary_id
+JOIN latest_snapshots ls ON s.snapshot_id = ls.id
+JOIN sources src ON ls.source_id = src.id
+JOIN files f ON f.snapshot_id = s.snapshot_id AND f.path = s.target_path
+WHERE s.summary_type = 'file'
+  AND (sqlc.narg(product_id)::uuid IS NULL OR src.product_id = sqlc.narg(product_id)::uuid)
+  AND (sqlc.narg(path_prefix)::text IS NULL OR f.path LIKE sqlc.narg(path_prefix)::text || '%')
+  AND (cardinality(sqlc.arg(domains)::text[]) = 0 OR f.domain = ANY(sqlc.arg(domains)::text[]))
+  AND (cardinality(sqlc.arg(languages)::text[]) = 0 OR f.language = ANY(sqlc.arg(languages)::text[]))
+  AND (sqlc.narg(content_type)::text IS NULL OR f.content_type = sqlc.narg(content_type)::text)
+ORDER BY se.vector <=> sqlc.arg(query_vector)
+LIMIT sqlc.arg(limit_val);
+
+-- name: RankProductsBySummaries :many
+-- プロダクトを指定しない質問の振り分け用
+-- 各プロダクトの最新のインデックス済みスナップショットのアーキテクチャ要約と、プロダクト全体の要約（model が主モデルのもの）の
+-- 質問との類似度の最大値で、プロダクトを順位付けする
+WITH latest_snapshots AS (
+    SELECT DISTINCT ON (source_id) id, source_id
+    FROM source_snapshots
+    WHERE status = 'indexed'
+    ORDER BY source_id, indexed_at DESC NULLS LAST, created_at DESC
+),
+candidates AS (
+    SELECT src.product_id, (1 - (se.vector <=> sqlc.arg(query_vector)))::float8 AS score
+    FROM summaries s
+    JOIN summary_embeddings se ON s.id = se.summary_id
+    JOIN latest_snapshots ls ON s.snapshot_id = ls.id
+    JOIN sources src ON ls.source_id = src.id
+    WHERE s.summary_type = 'architecture'
+    UNION ALL
+    SELECT ps.product_id, (1 - (ps.vector <=> sqlc.arg(query_vector)::vector))::float8 AS score
+    FROM product_summaries ps
+    WHERE ps.model = sqlc.arg(model)
+)
+SELECT
+    p.id AS product_id,
+    p.name AS product_name,
+    MAX(c.score)::float8 AS score
+FROM candidates c
+JOIN products p ON c.product_id = p.id
+GROUP BY p.id, p.name
+ORDER BY score DESC, p.name
 LIMIT sqlc.arg(limit_val);
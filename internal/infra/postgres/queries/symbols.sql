@@ -0,0 +1,39 @@
+-- name: DeleteSymbolsBySnapshot :exec
+DELETE FROM symbols
+WHERE snapshot_id = $1;
+
+-- name: CreateSymbolsFromChunks :execrows
+-- スナップショットのチャンクのうち、名前を持つ関数・メソッド・型などのチャンク（レベル2）をシンボルとして登録する
+-- Markdown の見出し（section）はシンボルとして扱わない
+INSERT INTO symbols (snapshot_id, chunk_id, file_id, file_path, name, kind, parent_name, start_line, end_line)
+SELECT f.snapshot_id, c.id, f.id, f.path, c.chunk_name, c.chunk_type, LTRIM(COALESCE(c.parent_name, ''), '*'), c.start_line, c.end_line
+FROM chunks c
+INNER JOIN files f ON c.file_id = f.id
+WHERE f.snapshot_id = $1
+  AND c.level = 2
+  AND c.chunk_name IS NOT NULL AND c.chunk_name <> ''
+  AND c.chunk_type IS NOT NULL AND c.chunk_type <> 'section';
+
+-- name: FindSymbols :many
+-- シンボルを名前の完全一致で検索する
+-- product_id を指定した場合は各ソースの最新のインデックス済みスナップショットを対象とする
+-- 大文字小文字まで一致するシンボルを先に、大文字小文字を区別しない一致を後に並べる
+WITH scope_snapshots AS (
+    SELECT DISTINCT ON (ss.source_id) ss.id, s.name AS source_name
+    FROM source_snapshots ss
+    INNER JOIN sources s ON ss.source_id = s.id
+    WHERE ss.status = 'indexed'
+      AND (sqlc.narg(product_id)::uuid IS NULL OR s.product_id = sqlc.narg(product_id)::uuid)
+      AND (sqlc.narg(snapshot_id)::uuid IS NULL OR ss.id = sqlc.narg(snapshot_id)::uuid)
+    ORDER BY ss.source_id, ss.indexed_at DESC NULLS LAST, ss.created_at DESC
+)
+SELECT
+    sy.*,
+    sc.source_name::text AS source_name
+FROM symbols sy
+INNER JOIN scope_snapshots sc ON sy.snapshot_id = sc.id
+WHERE LOWER(sy.name) = LOWER(sqlc.arg(name)::text)
+  AND (sqlc.narg(parent_name)::text IS NULL OR LOWER(sy.parent_name) = LOWER(sqlc.narg(parent_name)::text))
+  AND (sqlc.narg(kind)::text IS NULL OR sy.kind = sqlc.narg(kind)::text)
+ORDER BY (sy.name = sqlc.arg(name)::text) DESC, sc.source_name, sy.file_path, sy.start_line
+LIMIT sqlc.arg(max_results);
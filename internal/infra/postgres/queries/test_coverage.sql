@@ -0,0 +1,59 @@
+-- name: ListTestCoverageTargets :many
+-- カバレッジの取り込み先として、スナップショットのファイルと関数・クラス単位のチャンクの行範囲を取得する
+SELECT
+    f.id AS file_id,
+    f.path,
+    c.id AS chunk_id,
+    c.start_line,
+    c.end_line
+FROM files f
+LEFT JOIN chunks c ON c.file_id = f.id AND c.level = 2
+WHERE f.snapshot_id = $1
+ORDER BY f.path, c.start_line;
+
+-- name: DeleteFileTestCoverageBySnapshot :exec
+DELETE FROM file_test_coverage
+WHERE snapshot_id = $1;
+
+-- name: DeleteChunkTestCoverageBySnapshot :exec
+DELETE FROM chunk_test_coverage
+WHERE chunk_id IN (
+    SELECT c.id
+    FROM chunks c
+    INNER JOIN files f ON c.file_id = f.id
+    WHERE f.snapshot_id = $1
+);
+
+-- name: CreateFileTestCoverage :exec
+INSERT INTO file_test_coverage (file_id, snapshot_id, statements, covered_statements, format)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: CreateChunkTestCoverage :exec
+INSERT INTO chunk_test_coverage (chunk_id, statements, covered_statements)
+VALUES ($1, $2, $3);
+
+-- name: ListFileTestCoverage :many
+-- ファイルごとのテストカバレッジを取得する
+-- product_id 指定時はプロダクトの各ソースの最新のインデックス済みスナップショット、snapshot_id 指定時はそのスナップショットを対象とする
+WITH scope_snapshots AS (
+    SELECT DISTINCT ON (ss.source_id) ss.id, s.name AS source_name
+    FROM source_snapshots ss
+    INNER JOIN sources s ON ss.source_id = s.id
+    WHERE ss.status = 'indexed'
+      AND (sqlc.narg(product_id)::uuid IS NULL OR s.product_id = sqlc.narg(product_id)::uuid)
+      AND (sqlc.narg(snapshot_id)::uuid IS NULL OR ss.id = sqlc.narg(snapshot_id)::uuid)
+    ORDER BY ss.source_id, ss.indexed_at DESC NULLS LAST, ss.created_at DESC
+)
+SELECT
+    tc.file_id,
+    tc.snapshot_id,
+    sc.source_name::text AS source_name,
+    f.path,
+    tc.statements,
+    tc.covered_statements,
+    tc.format,
+    tc.imported_at
+FROM file_test_coverage tc
+INNER JOIN files f ON tc.file_id = f.id
+INNER JOIN scope_snapshots sc ON tc.snapshot_id = sc.id
+ORDER BY sc.source_name, f.path;
@@ -0,0 +1,34 @@
+-- name: ListWikiPublications :many
+SELECT * FROM wiki_publications
+WHERE product_id = $1 AND target = $2 AND destination = $3 AND language = $4
+ORDER BY page_path;
+
+-- name: UpsertWikiPublication :one
+INSERT INTO wiki_publications (
+    product_id,
+    target,
+    destination,
+    language,
+    page_path,
+    remote_id,
+    remote_version,
+    parent_remote_id,
+    title,
+    content_hash,
+    published_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, CURRENT_TIMESTAMP
+)
+ON CONFLICT (product_id, target, destination, language, page_path)
+DO UPDATE SET
+    remote_id = EXCLUDED.remote_id,
+    remote_version = EXCLUDED.remote_version,
+    parent_remote_id = EXCLUDED.parent_remote_id,
+    title = EXCLUDED.title,
+    content_hash = EXCLUDED.content_hash,
+    published_at = EXCLUDED.published_at
+RETURNING *;
+
+-- name: DeleteWikiPublication :exec
+DELETE FROM wiki_publications
+WHERE id = $1;
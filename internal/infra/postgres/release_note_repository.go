@@ -0,0 +1,175 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/samber/mo"
+
+	"github.com/jinford/dev-rag/internal/core/release"
+	"github.com/jinford/dev-rag/internal/infra/postgres/sqlc"
+)
+
+// ReleaseNoteRepository は core/release.Repository を実装する PostgreSQL リポジトリ。
+type ReleaseNoteRepository struct {
+	q sqlc.Querier
+}
+
+// NewReleaseNoteRepository は新しい ReleaseNoteRepository を返す。
+func NewReleaseNoteRepository(q sqlc.Querier) *ReleaseNoteRepository {
+	return &ReleaseNoteRepository{q: q}
+}
+
+var _ release.Repository = (*ReleaseNoteRepository)(nil)
+
+func (r *ReleaseNoteRepository) FindSnapshot(ctx context.Context, sourceID uuid.UUID, commit string) (mo.Option[uuid.UUID], error) {
+	id, err := r.q.FindIndexedSnapshotByCommit(ctx, sqlc.FindIndexedSnapshotByCommitParams{
+		SourceID:          UUIDToPgtype(sourceID),
+		VersionIdentifier: commit,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, sql.ErrNoRows) {
+			return mo.None[uuid.UUID](), nil
+		}
+		return mo.None[uuid.UUID](), fmt.Errorf("failed to find snapshot by commit: %w", err)
+	}
+	return mo.Some(PgtypeToUUID(id)), nil
+}
+
+func (r *ReleaseNoteRepository) ListChunkChanges(ctx context.Context, from mo.Option[uuid.UUID], to uuid.UUID, paths []string, limit int) ([]*release.ChunkChange, error) {
+	rows, err := r.q.ListReleaseChunkChanges(ctx, sqlc.ListReleaseChunkChangesParams{
+		FromSnapshotID: UUIDOptionToPgtype(from),
+		ToSnapshotID:   UUIDToPgtype(to),
+		Paths:          stringsParam(paths),
+		RowLimit:       int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list release chunk changes: %w", err)
+	}
+
+	changes := make([]*release.ChunkChange, 0, len(rows))
+	for _, row := range rows {
+		name := row.ChunkName
+		if row.ParentName != "" {
+			name = row.ParentName + "." + name
+		}
+		changes = append(changes, &release.ChunkChange{
+			Path:       row.Path,
+			Name:       name,
+			Type:       row.ChunkType,
+			Signature:  row.Signature,
+			DocComment: row.DocComment,
+			Kind:       release.ChunkChangeKind(row.Change),
+		})
+	}
+	return changes, nil
+}
+
+func (r *ReleaseNoteRepository) ListFileSummaries(ctx context.Context, snapshotID uuid.UUID, paths []string) ([]*release.FileSummary, error) {
+	rows, err := r.q.ListReleaseFileSummaries(ctx, sqlc.ListReleaseFileSummariesParams{
+		SnapshotID: UUIDToPgtype(snapshotID),
+		Paths:      stringsParam(paths),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list release file summaries: %w", err)
+	}
+
+	summaries := make([]*release.FileSummary, 0, len(rows))
+	for _, row := range rows {
+		summaries = append(summaries, &release.FileSummary{Path: row.TargetPath, Content: row.Content})
+	}
+	return summaries, nil
+}
+
+func (r *ReleaseNoteRepository) ListImpacts(ctx context.Context, snapshotID uuid.UUID, paths []string) ([]*release.Impact, error) {
+	rows, err := r.q.ListReleaseDependents(ctx, sqlc.ListReleaseDependentsParams{
+		SnapshotID: UUIDToPgtype(snapshotID),
+		Paths:      stringsParam(paths),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list release dependents: %w", err)
+	}
+
+	// 行は変更されたファイル・依存の多い順に並んでいるため、変更されたファイルごとにまとめる
+	var impacts []*release.Impact
+	var current *release.Impact
+	for _, row := range rows {
+		if current == nil || current.Path != row.Path {
+			current = &release.Impact{Path: row.Path}
+			impacts = append(impacts, current)
+		}
+		current.Dependents = append(current.Dependents, row.DependentPath)
+		for _, symbol := range row.Symbols {
+			if !slices.Contains(current.Symbols, symbol) {
+				current.Symbols = append(current.Symbols, symbol)
+			}
+		}
+	}
+	return impacts, nil
+}
+
+func (r *ReleaseNoteRepository) SaveNote(ctx context.Context, note *release.Note) (*release.Note, error) {
+	row, err := r.q.UpsertReleaseNote(ctx, sqlc.UpsertReleaseNoteParams{
+		SourceID:      UUIDToPgtype(note.SourceID),
+		FromRef:       note.FromRef,
+		ToRef:         note.ToRef,
+		FromCommit:    note.FromCommit,
+		ToCommit:      note.ToCommit,
+		ReleasedAt:    TimeToPgtype(note.ReleasedAt),
+		CommitCount:   int32(note.CommitCount),
+		FileCount:     int32(note.FileCount),
+		Content:       note.Content,
+		PromptVersion: note.PromptVersion,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save release note: %w", err)
+	}
+	return &release.Note{
+		ID:            PgtypeToUUID(row.ID),
+		SourceID:      PgtypeToUUID(row.SourceID),
+		FromRef:       row.FromRef,
+		ToRef:         row.ToRef,
+		FromCommit:    row.FromCommit,
+		ToCommit:      row.ToCommit,
+		ReleasedAt:    PgtypeToTime(row.ReleasedAt),
+		CommitCount:   int(row.CommitCount),
+		FileCount:     int(row.FileCount),
+		Content:       row.Content,
+		PromptVersion: row.PromptVersion,
+		CreatedAt:     PgtypeToTime(row.CreatedAt),
+		UpdatedAt:     PgtypeToTime(row.UpdatedAt),
+	}, nil
+}
+
+func (r *ReleaseNoteRepository) ListNotes(ctx context.Context, productID uuid.UUID) ([]*release.Note, error) {
+	rows, err := r.q.ListReleaseNotesByProduct(ctx, UUIDToPgtype(productID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list release notes: %w", err)
+	}
+
+	notes := make([]*release.Note, 0, len(rows))
+	for _, row := range rows {
+		notes = append(notes, &release.Note{
+			ID:            PgtypeToUUID(row.ID),
+			SourceID:      PgtypeToUUID(row.SourceID),
+			SourceName:    row.SourceName,
+			FromRef:       row.FromRef,
+			ToRef:         row.ToRef,
+			FromCommit:    row.FromCommit,
+			ToCommit:      row.ToCommit,
+			ReleasedAt:    PgtypeToTime(row.ReleasedAt),
+			CommitCount:   int(row.CommitCount),
+			FileCount:     int(row.FileCount),
+			Content:       row.Content,
+			PromptVersion: row.PromptVersion,
+			CreatedAt:     PgtypeToTime(row.CreatedAt),
+			UpdatedAt:     PgtypeToTime(row.UpdatedAt),
+		})
+	}
+	return notes, nil
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jinford/dev-rag/internal/core/ingestion"
+	"github.com/jinford/dev-rag/internal/core/usage"
 	"github.com/jinford/dev-rag/internal/infra/postgres/sqlc"
 	pgvector "github.com/pgvector/pgvector-go"
 	"github.com/samber/mo"
@@ -20,12 +21,32 @@ import (
 
 // Repository は ingestion.Repository インターフェースを実装する PostgreSQL リポジトリです
 type Repository struct {
-	q sqlc.Querier
+	q  sqlc.Querier
+	db TxBeginner
+}
+
+// TxBeginner はトランザクションを開始できる接続です（*pgxpool.Pool など）
+type TxBeginner interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// RepositoryOption は Repository のオプションです
+type RepositoryOption func(*Repository)
+
+// WithTxBeginner は複数の更新を1つのトランザクションで行うメソッド（ReplaceFileChunks など）で使う接続を設定します
+func WithTxBeginner(db TxBeginner) RepositoryOption {
+	return func(r *Repository) {
+		r.db = db
+	}
 }
 
 // NewRepository は新しい Repository を作成します
-func NewRepository(q sqlc.Querier) *Repository {
-	return &Repository{q: q}
+func NewRepository(q sqlc.Querier, opts ...RepositoryOption) *Repository {
+	r := &Repository{q: q}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
 }
 
 // コンパイル時の型チェック
@@ -43,11 +64,12 @@ func (r *Repository) GetProductByID(ctx context.Context, id uuid.UUID) (mo.Optio
 	}
 
 	return mo.Some(&ingestion.Product{
-		ID:          PgtypeToUUID(product.ID),
-		Name:        product.Name,
-		Description: PgtextToStringPtr(product.Description),
-		CreatedAt:   PgtypeToTime(product.CreatedAt),
-		UpdatedAt:   PgtypeToTime(product.UpdatedAt),
+		ID:            PgtypeToUUID(product.ID),
+		Name:          product.Name,
+		Description:   PgtextToStringPtr(product.Description),
+		CreatedAt:     PgtypeToTime(product.CreatedAt),
+		UpdatedAt:     PgtypeToTime(product.UpdatedAt),
+		RecencyWeight: PgFloat8ToFloat64Ptr(product.RecencyWeight),
 	}), nil
 }
 
@@ -61,11 +83,12 @@ func (r *Repository) GetProductByName(ctx context.Context, name string) (mo.Opti
 	}
 
 	return mo.Some(&ingestion.Product{
-		ID:          PgtypeToUUID(product.ID),
-		Name:        product.Name,
-		Description: PgtextToStringPtr(product.Description),
-		CreatedAt:   PgtypeToTime(product.CreatedAt),
-		UpdatedAt:   PgtypeToTime(product.UpdatedAt),
+		ID:            PgtypeToUUID(product.ID),
+		Name:          product.Name,
+		Description:   PgtextToStringPtr(product.Description),
+		CreatedAt:     PgtypeToTime(product.CreatedAt),
+		UpdatedAt:     PgtypeToTime(product.UpdatedAt),
+		RecencyWeight: PgFloat8ToFloat64Ptr(product.RecencyWeight),
 	}), nil
 }
 
@@ -78,11 +101,12 @@ func (r *Repository) ListProducts(ctx context.Context) ([]*ingestion.Product, er
 	result := make([]*ingestion.Product, 0, len(products))
 	for _, p := range products {
 		result = append(result, &ingestion.Product{
-			ID:          PgtypeToUUID(p.ID),
-			Name:        p.Name,
-			Description: PgtextToStringPtr(p.Description),
-			CreatedAt:   PgtypeToTime(p.CreatedAt),
-			UpdatedAt:   PgtypeToTime(p.UpdatedAt),
+			ID:            PgtypeToUUID(p.ID),
+			Name:          p.Name,
+			Description:   PgtextToStringPtr(p.Description),
+			CreatedAt:     PgtypeToTime(p.CreatedAt),
+			UpdatedAt:     PgtypeToTime(p.UpdatedAt),
+			RecencyWeight: PgFloat8ToFloat64Ptr(p.RecencyWeight),
 		})
 	}
 
@@ -104,6 +128,7 @@ func (r *Repository) ListProductsWithStats(ctx context.Context) ([]*ingestion.Pr
 			CreatedAt:   PgtypeToTime(row.CreatedAt),
 			UpdatedAt:   PgtypeToTime(row.UpdatedAt),
 			SourceCount: int(row.SourceCount),
+			Summary:     PgtextToStringPtr(row.Summary),
 		}
 
 		if lastIndexed, ok := row.LastIndexedAt.(pgtype.Timestamp); ok && lastIndexed.Valid {
@@ -123,11 +148,12 @@ func (r *Repository) CreateProductIfNotExists(ctx context.Context, name string,
 	existing, err := r.q.GetProductByName(ctx, name)
 	if err == nil {
 		return &ingestion.Product{
-			ID:          PgtypeToUUID(existing.ID),
-			Name:        existing.Name,
-			Description: PgtextToStringPtr(existing.Description),
-			CreatedAt:   PgtypeToTime(existing.CreatedAt),
-			UpdatedAt:   PgtypeToTime(existing.UpdatedAt),
+			ID:            PgtypeToUUID(existing.ID),
+			Name:          existing.Name,
+			Description:   PgtextToStringPtr(existing.Description),
+			CreatedAt:     PgtypeToTime(existing.CreatedAt),
+			UpdatedAt:     PgtypeToTime(existing.UpdatedAt),
+			RecencyWeight: PgFloat8ToFloat64Ptr(existing.RecencyWeight),
 		}, nil
 	}
 	if err != pgx.ErrNoRows {
@@ -143,11 +169,12 @@ func (r *Repository) CreateProductIfNotExists(ctx context.Context, name string,
 	}
 
 	return &ingestion.Product{
-		ID:          PgtypeToUUID(product.ID),
-		Name:        product.Name,
-		Description: PgtextToStringPtr(product.Description),
-		CreatedAt:   PgtypeToTime(product.CreatedAt),
-		UpdatedAt:   PgtypeToTime(product.UpdatedAt),
+		ID:            PgtypeToUUID(product.ID),
+		Name:          product.Name,
+		Description:   PgtextToStringPtr(product.Description),
+		CreatedAt:     PgtypeToTime(product.CreatedAt),
+		UpdatedAt:     PgtypeToTime(product.UpdatedAt),
+		RecencyWeight: PgFloat8ToFloat64Ptr(product.RecencyWeight),
 	}, nil
 }
 
@@ -165,11 +192,34 @@ func (r *Repository) UpdateProduct(ctx context.Context, id uuid.UUID, name strin
 	}
 
 	return &ingestion.Product{
-		ID:          PgtypeToUUID(product.ID),
-		Name:        product.Name,
-		Description: PgtextToStringPtr(product.Description),
-		CreatedAt:   PgtypeToTime(product.CreatedAt),
-		UpdatedAt:   PgtypeToTime(product.UpdatedAt),
+		ID:            PgtypeToUUID(product.ID),
+		Name:          product.Name,
+		Description:   PgtextToStringPtr(product.Description),
+		CreatedAt:     PgtypeToTime(product.CreatedAt),
+		UpdatedAt:     PgtypeToTime(product.UpdatedAt),
+		RecencyWeight: PgFloat8ToFloat64Ptr(product.RecencyWeight),
+	}, nil
+}
+
+func (r *Repository) UpdateProductRecencyWeight(ctx context.Context, id uuid.UUID, weight *float64) (*ingestion.Product, error) {
+	product, err := r.q.UpdateProductRecencyWeight(ctx, sqlc.UpdateProductRecencyWeightParams{
+		ID:            UUIDToPgtype(id),
+		RecencyWeight: Float64PtrToPgFloat8(weight),
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("product not found: %s", id)
+		}
+		return nil, fmt.Errorf("failed to update product recency weight: %w", err)
+	}
+
+	return &ingestion.Product{
+		ID:            PgtypeToUUID(product.ID),
+		Name:          product.Name,
+		Description:   PgtextToStringPtr(product.Description),
+		CreatedAt:     PgtypeToTime(product.CreatedAt),
+		UpdatedAt:     PgtypeToTime(product.UpdatedAt),
+		RecencyWeight: PgFloat8ToFloat64Ptr(product.RecencyWeight),
 	}, nil
 }
 
@@ -289,6 +339,28 @@ func (r *Repository) CreateSourceIfNotExists(ctx context.Context, name string, s
 
 // === SourceSnapshot ===
 
+func (r *Repository) GetSnapshotByID(ctx context.Context, id uuid.UUID) (mo.Option[*ingestion.SourceSnapshot], error) {
+	sqlcSnapshot, err := r.q.GetSourceSnapshot(ctx, UUIDToPgtype(id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, sql.ErrNoRows) {
+			return mo.None[*ingestion.SourceSnapshot](), nil
+		}
+		return mo.None[*ingestion.SourceSnapshot](), fmt.Errorf("failed to get snapshot: %w", err)
+	}
+
+	return mo.Some(&ingestion.SourceSnapshot{
+		ID:                PgtypeToUUID(sqlcSnapshot.ID),
+		SourceID:          PgtypeToUUID(sqlcSnapshot.SourceID),
+		VersionIdentifier: sqlcSnapshot.VersionIdentifier,
+		Status:            ingestion.SnapshotStatus(sqlcSnapshot.Status),
+		IndexedAt:         PgtypeToTimePtr(sqlcSnapshot.IndexedAt),
+		CreatedAt:         PgtypeToTime(sqlcSnapshot.CreatedAt),
+		FailedAt:          PgtypeToTimePtr(sqlcSnapshot.FailedAt),
+		FailureReason:     sqlcSnapshot.FailureReason.String,
+		IndexingStartedAt: PgtypeToTimePtr(sqlcSnapshot.IndexingStartedAt),
+	}), nil
+}
+
 func (r *Repository) GetSnapshotByVersion(ctx context.Context, sourceID uuid.UUID, versionIdentifier string) (mo.Option[*ingestion.SourceSnapshot], error) {
 	sqlcSnapshot, err := r.q.GetSourceSnapshotByVersion(ctx, sqlc.GetSourceSnapshotByVersionParams{
 		SourceID:          UUIDToPgtype(sourceID),
@@ -305,9 +377,12 @@ func (r *Repository) GetSnapshotByVersion(ctx context.Context, sourceID uuid.UUI
 		ID:                PgtypeToUUID(sqlcSnapshot.ID),
 		SourceID:          PgtypeToUUID(sqlcSnapshot.SourceID),
 		VersionIdentifier: sqlcSnapshot.VersionIdentifier,
-		Indexed:           sqlcSnapshot.Indexed,
+		Status:            ingestion.SnapshotStatus(sqlcSnapshot.Status),
 		IndexedAt:         PgtypeToTimePtr(sqlcSnapshot.IndexedAt),
 		CreatedAt:         PgtypeToTime(sqlcSnapshot.CreatedAt),
+		FailedAt:          PgtypeToTimePtr(sqlcSnapshot.FailedAt),
+		FailureReason:     sqlcSnapshot.FailureReason.String,
+		IndexingStartedAt: PgtypeToTimePtr(sqlcSnapshot.IndexingStartedAt),
 	}), nil
 }
 
@@ -324,9 +399,12 @@ func (r *Repository) GetLatestIndexedSnapshot(ctx context.Context, sourceID uuid
 		ID:                PgtypeToUUID(sqlcSnapshot.ID),
 		SourceID:          PgtypeToUUID(sqlcSnapshot.SourceID),
 		VersionIdentifier: sqlcSnapshot.VersionIdentifier,
-		Indexed:           sqlcSnapshot.Indexed,
+		Status:            ingestion.SnapshotStatus(sqlcSnapshot.Status),
 		IndexedAt:         PgtypeToTimePtr(sqlcSnapshot.IndexedAt),
 		CreatedAt:         PgtypeToTime(sqlcSnapshot.CreatedAt),
+		FailedAt:          PgtypeToTimePtr(sqlcSnapshot.FailedAt),
+		FailureReason:     sqlcSnapshot.FailureReason.String,
+		IndexingStartedAt: PgtypeToTimePtr(sqlcSnapshot.IndexingStartedAt),
 	}), nil
 }
 
@@ -342,15 +420,46 @@ func (r *Repository) ListSnapshotsBySource(ctx context.Context, sourceID uuid.UU
 			ID:                PgtypeToUUID(sqlcSnapshot.ID),
 			SourceID:          PgtypeToUUID(sqlcSnapshot.SourceID),
 			VersionIdentifier: sqlcSnapshot.VersionIdentifier,
-			Indexed:           sqlcSnapshot.Indexed,
+			Status:            ingestion.SnapshotStatus(sqlcSnapshot.Status),
 			IndexedAt:         PgtypeToTimePtr(sqlcSnapshot.IndexedAt),
 			CreatedAt:         PgtypeToTime(sqlcSnapshot.CreatedAt),
+			FailedAt:          PgtypeToTimePtr(sqlcSnapshot.FailedAt),
+			FailureReason:     sqlcSnapshot.FailureReason.String,
+			IndexingStartedAt: PgtypeToTimePtr(sqlcSnapshot.IndexingStartedAt),
 		})
 	}
 
 	return snapshots, nil
 }
 
+func (r *Repository) GetSnapshotStats(ctx context.Context, snapshotID uuid.UUID) (*ingestion.SnapshotStats, error) {
+	row, err := r.q.GetSnapshotStats(ctx, UUIDToPgtype(snapshotID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get snapshot stats: %w", err)
+	}
+
+	domainRows, err := r.q.GetSnapshotDomainStats(ctx, UUIDToPgtype(snapshotID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get snapshot domain stats: %w", err)
+	}
+
+	stats := &ingestion.SnapshotStats{
+		FileCount:      int(row.FileCount),
+		ChunkCount:     int(row.ChunkCount),
+		EmbeddingCount: int(row.EmbeddingCount),
+		Domains:        make([]*ingestion.SnapshotDomainStats, 0, len(domainRows)),
+	}
+	for _, d := range domainRows {
+		stats.Domains = append(stats.Domains, &ingestion.SnapshotDomainStats{
+			Domain:     d.Domain,
+			FileCount:  int(d.FileCount),
+			ChunkCount: int(d.ChunkCount),
+		})
+	}
+
+	return stats, nil
+}
+
 func (r *Repository) CreateSnapshot(ctx context.Context, sourceID uuid.UUID, versionIdentifier string) (*ingestion.SourceSnapshot, error) {
 	sqlcSnapshot, err := r.q.CreateSourceSnapshot(ctx, sqlc.CreateSourceSnapshotParams{
 		SourceID:          UUIDToPgtype(sourceID),
@@ -369,9 +478,12 @@ func (r *Repository) CreateSnapshot(ctx context.Context, sourceID uuid.UUID, ver
 		ID:                PgtypeToUUID(sqlcSnapshot.ID),
 		SourceID:          PgtypeToUUID(sqlcSnapshot.SourceID),
 		VersionIdentifier: sqlcSnapshot.VersionIdentifier,
-		Indexed:           sqlcSnapshot.Indexed,
+		Status:            ingestion.SnapshotStatus(sqlcSnapshot.Status),
 		IndexedAt:         PgtypeToTimePtr(sqlcSnapshot.IndexedAt),
 		CreatedAt:         PgtypeToTime(sqlcSnapshot.CreatedAt),
+		FailedAt:          PgtypeToTimePtr(sqlcSnapshot.FailedAt),
+		FailureReason:     sqlcSnapshot.FailureReason.String,
+		IndexingStartedAt: PgtypeToTimePtr(sqlcSnapshot.IndexingStartedAt),
 	}, nil
 }
 
@@ -383,6 +495,32 @@ func (r *Repository) MarkSnapshotIndexed(ctx context.Context, snapshotID uuid.UU
 	return nil
 }
 
+func (r *Repository) MarkSnapshotIndexing(ctx context.Context, snapshotID uuid.UUID) error {
+	if err := r.q.MarkSnapshotIndexing(ctx, UUIDToPgtype(snapshotID)); err != nil {
+		return fmt.Errorf("failed to mark snapshot as indexing: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) MarkSnapshotFailed(ctx context.Context, snapshotID uuid.UUID, reason string) error {
+	err := r.q.MarkSnapshotFailed(ctx, sqlc.MarkSnapshotFailedParams{
+		ID:            UUIDToPgtype(snapshotID),
+		FailureReason: StringToNullableText(reason),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to mark snapshot as failed: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) DeleteIncompleteSnapshotFiles(ctx context.Context, snapshotID uuid.UUID) (int, error) {
+	deleted, err := r.q.DeleteIncompleteSnapshotFiles(ctx, UUIDToPgtype(snapshotID))
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete incomplete snapshot files: %w", err)
+	}
+	return int(deleted), nil
+}
+
 // === GitRef ===
 
 func (r *Repository) GetGitRefByName(ctx context.Context, sourceID uuid.UUID, refName string) (mo.Option[*ingestion.GitRef], error) {
@@ -622,6 +760,23 @@ func (r *Repository) ListChunksByFile(ctx context.Context, fileID uuid.UUID) ([]
 	return chunks, nil
 }
 
+// ListFileIDsWithOutdatedChunker はスナップショットで currentVersions 以外のチャンカーで作成したチャンクがあるファイルの ID を返す
+func (r *Repository) ListFileIDsWithOutdatedChunker(ctx context.Context, snapshotID uuid.UUID, currentVersions []string) ([]uuid.UUID, error) {
+	rows, err := r.q.ListFileIDsWithOutdatedChunker(ctx, sqlc.ListFileIDsWithOutdatedChunkerParams{
+		SnapshotID:      UUIDToPgtype(snapshotID),
+		CurrentVersions: currentVersions,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files with outdated chunker: %w", err)
+	}
+
+	ids := make([]uuid.UUID, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, PgtypeToUUID(row))
+	}
+	return ids, nil
+}
+
 func (r *Repository) GetChunkContext(ctx context.Context, chunkID uuid.UUID, beforeCount int, afterCount int) ([]*ingestion.Chunk, error) {
 	target, err := r.q.GetChunk(ctx, UUIDToPgtype(chunkID))
 	if err != nil {
@@ -775,6 +930,10 @@ func (r *Repository) CreateChunk(ctx context.Context, fileID uuid.UUID, ordinal
 		CommentRatio:         Float64PtrToPgNumeric(metadata.CommentRatio),
 		CyclomaticComplexity: IntPtrToPgInt4(metadata.CyclomaticComplexity),
 		EmbeddingContext:     StringPtrToPgtext(metadata.EmbeddingContext),
+		EmbeddingTruncated:   metadata.EmbeddingTruncated,
+		Breadcrumb:           StringPtrToPgtext(metadata.Breadcrumb),
+		PageStart:            IntPtrToPgInt4(metadata.PageStart),
+		PageEnd:              IntPtrToPgInt4(metadata.PageEnd),
 		// 階層関係と重要度
 		Level:           int32(metadata.Level),
 		ImportanceScore: Float64PtrToPgNumeric(metadata.ImportanceScore),
@@ -793,6 +952,7 @@ func (r *Repository) CreateChunk(ctx context.Context, fileID uuid.UUID, ordinal
 		FileVersion:      StringPtrToPgtext(metadata.FileVersion),
 		IsLatest:         metadata.IsLatest,
 		ChunkKey:         metadata.ChunkKey,
+		ChunkerVersion:   StringToNullableText(metadata.ChunkerVersion),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create chunk: %w", err)
@@ -801,6 +961,7 @@ func (r *Repository) CreateChunk(ctx context.Context, fileID uuid.UUID, ordinal
 	return convertSQLCChunk(chunk), nil
 }
 
+// BatchCreateChunks はチャンクを全メタデータとともに COPY で1回のラウンドトリップにまとめて保存する
 func (r *Repository) BatchCreateChunks(ctx context.Context, chunks []*ingestion.Chunk) error {
 	if len(chunks) == 0 {
 		return nil
@@ -808,49 +969,7 @@ func (r *Repository) BatchCreateChunks(ctx context.Context, chunks []*ingestion.
 
 	rows := make([]sqlc.CreateChunkBatchParams, 0, len(chunks))
 	for _, chunk := range chunks {
-		imports := JSONBFromStringSlice(chunk.Imports)
-		calls := JSONBFromStringSlice(chunk.Calls)
-		standardImports := JSONBFromStringSlice(chunk.StandardImports)
-		externalImports := JSONBFromStringSlice(chunk.ExternalImports)
-		internalCalls := JSONBFromStringSlice(chunk.InternalCalls)
-		externalCalls := JSONBFromStringSlice(chunk.ExternalCalls)
-		typeDependencies := JSONBFromStringSlice(chunk.TypeDependencies)
-
-		rows = append(rows, sqlc.CreateChunkBatchParams{
-			ID:                   UUIDToPgtype(chunk.ID),
-			FileID:               UUIDToPgtype(chunk.FileID),
-			Ordinal:              int32(chunk.Ordinal),
-			StartLine:            int32(chunk.StartLine),
-			EndLine:              int32(chunk.EndLine),
-			Content:              chunk.Content,
-			ContentHash:          chunk.ContentHash,
-			TokenCount:           IntToPgtype(chunk.TokenCount),
-			ChunkType:            StringPtrToPgtext(chunk.Type),
-			ChunkName:            StringPtrToPgtext(chunk.Name),
-			ParentName:           StringPtrToPgtext(chunk.ParentName),
-			Signature:            StringPtrToPgtext(chunk.Signature),
-			DocComment:           StringPtrToPgtext(chunk.DocComment),
-			Imports:              imports,
-			Calls:                calls,
-			LinesOfCode:          IntPtrToPgInt4(chunk.LinesOfCode),
-			CommentRatio:         Float64PtrToPgNumeric(chunk.CommentRatio),
-			CyclomaticComplexity: IntPtrToPgInt4(chunk.CyclomaticComplexity),
-			EmbeddingContext:     StringPtrToPgtext(chunk.EmbeddingContext),
-			SourceSnapshotID:     UUIDPtrToPgtype(chunk.SourceSnapshotID),
-			GitCommitHash:        StringPtrToPgtext(chunk.GitCommitHash),
-			Author:               StringPtrToPgtext(chunk.Author),
-			UpdatedAt:            TimePtrToPgtype(chunk.UpdatedAt),
-			IndexedAt:            TimeToPgtype(time.Now()),
-			FileVersion:          StringPtrToPgtext(chunk.FileVersion),
-			IsLatest:             chunk.IsLatest,
-			StandardImports:      standardImports,
-			ExternalImports:      externalImports,
-			InternalCalls:        internalCalls,
-			ExternalCalls:        externalCalls,
-			TypeDependencies:     typeDependencies,
-			Level:                int32(chunk.Level),
-			ChunkKey:             chunk.ChunkKey,
-		})
+		rows = append(rows, chunkBatchParams(chunk))
 	}
 
 	if _, err := r.q.CreateChunkBatch(ctx, rows); err != nil {
@@ -860,6 +979,88 @@ func (r *Repository) BatchCreateChunks(ctx context.Context, chunks []*ingestion.
 	return nil
 }
 
+// UpsertFileChunks はファイルのチャンクを保存し、同じ ID の保存済みのチャンクは置き換え、chunks にないチャンクは削除する
+// スナップショットの再実行（再開・--force-init）で使用する（チャンクの ID は chunk_key から決定的に生成する）
+func (r *Repository) UpsertFileChunks(ctx context.Context, fileID uuid.UUID, chunks []*ingestion.Chunk) error {
+	keepIDs := make([]uuid.UUID, 0, len(chunks))
+	for _, chunk := range chunks {
+		keepIDs = append(keepIDs, chunk.ID)
+	}
+	if err := r.q.DeleteStaleChunksByFile(ctx, sqlc.DeleteStaleChunksByFileParams{
+		FileID:  UUIDToPgtype(fileID),
+		KeepIds: uuidsParam(keepIDs),
+	}); err != nil {
+		return fmt.Errorf("failed to delete stale chunks: %w", err)
+	}
+	if len(chunks) == 0 {
+		return nil
+	}
+
+	rows := make([]sqlc.UpsertChunkBatchParams, 0, len(chunks))
+	for _, chunk := range chunks {
+		rows = append(rows, sqlc.UpsertChunkBatchParams(chunkBatchParams(chunk)))
+	}
+
+	var batchErr error
+	results := r.q.UpsertChunkBatch(ctx, rows)
+	results.Exec(func(i int, err error) {
+		if err != nil && batchErr == nil {
+			batchErr = fmt.Errorf("failed to upsert chunk at index %d: %w", i, err)
+		}
+	})
+
+	if batchErr != nil {
+		return fmt.Errorf("failed to batch upsert chunks: %w", batchErr)
+	}
+
+	return nil
+}
+
+// chunkBatchParams はチャンクをバッチでの保存のパラメータに変換する
+func chunkBatchParams(chunk *ingestion.Chunk) sqlc.CreateChunkBatchParams {
+	return sqlc.CreateChunkBatchParams{
+		ID:                   UUIDToPgtype(chunk.ID),
+		FileID:               UUIDToPgtype(chunk.FileID),
+		Ordinal:              int32(chunk.Ordinal),
+		StartLine:            int32(chunk.StartLine),
+		EndLine:              int32(chunk.EndLine),
+		Content:              chunk.Content,
+		ContentHash:          chunk.ContentHash,
+		TokenCount:           IntToPgtype(chunk.TokenCount),
+		ChunkType:            StringPtrToPgtext(chunk.Type),
+		ChunkName:            StringPtrToPgtext(chunk.Name),
+		ParentName:           StringPtrToPgtext(chunk.ParentName),
+		Signature:            StringPtrToPgtext(chunk.Signature),
+		DocComment:           StringPtrToPgtext(chunk.DocComment),
+		Imports:              JSONBFromStringSlice(chunk.Imports),
+		Calls:                JSONBFromStringSlice(chunk.Calls),
+		LinesOfCode:          IntPtrToPgInt4(chunk.LinesOfCode),
+		CommentRatio:         Float64PtrToPgNumeric(chunk.CommentRatio),
+		CyclomaticComplexity: IntPtrToPgInt4(chunk.CyclomaticComplexity),
+		EmbeddingContext:     StringPtrToPgtext(chunk.EmbeddingContext),
+		EmbeddingTruncated:   chunk.EmbeddingTruncated,
+		Breadcrumb:           StringPtrToPgtext(chunk.Breadcrumb),
+		PageStart:            IntPtrToPgInt4(chunk.PageStart),
+		PageEnd:              IntPtrToPgInt4(chunk.PageEnd),
+		SourceSnapshotID:     UUIDPtrToPgtype(chunk.SourceSnapshotID),
+		GitCommitHash:        StringPtrToPgtext(chunk.GitCommitHash),
+		Author:               StringPtrToPgtext(chunk.Author),
+		UpdatedAt:            TimePtrToPgtype(chunk.UpdatedAt),
+		IndexedAt:            TimeToPgtype(time.Now()),
+		FileVersion:          StringPtrToPgtext(chunk.FileVersion),
+		IsLatest:             chunk.IsLatest,
+		StandardImports:      JSONBFromStringSlice(chunk.StandardImports),
+		ExternalImports:      JSONBFromStringSlice(chunk.ExternalImports),
+		InternalCalls:        JSONBFromStringSlice(chunk.InternalCalls),
+		ExternalCalls:        JSONBFromStringSlice(chunk.ExternalCalls),
+		TypeDependencies:     JSONBFromStringSlice(chunk.TypeDependencies),
+		Level:                int32(chunk.Level),
+		ImportanceScore:      Float64PtrToPgNumeric(chunk.ImportanceScore),
+		ChunkKey:             chunk.ChunkKey,
+		ChunkerVersion:       StringToNullableText(chunk.ChunkerVersion),
+	}
+}
+
 func (r *Repository) DeleteChunksByFileID(ctx context.Context, fileID uuid.UUID) error {
 	if err := r.q.DeleteChunksByFile(ctx, UUIDToPgtype(fileID)); err != nil {
 		return fmt.Errorf("failed to delete chunks by file: %w", err)
@@ -867,6 +1068,37 @@ func (r *Repository) DeleteChunksByFileID(ctx context.Context, fileID uuid.UUID)
 	return nil
 }
 
+// ReplaceFileChunks はファイルのチャンクを削除して新しいチャンク・Embedding・Embedding生成の失敗を保存する
+// 1つのトランザクションで行うため、途中で失敗した場合は古いチャンクと Embedding が残る
+func (r *Repository) ReplaceFileChunks(ctx context.Context, fileID uuid.UUID, chunks []*ingestion.Chunk, embeddings []*ingestion.Embedding, failures []*ingestion.EmbeddingFailure) error {
+	if r.db == nil {
+		return errors.New("failed to replace file chunks: no connection for transactions is configured")
+	}
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	txRepo := &Repository{q: sqlc.New(tx)}
+	if err := txRepo.DeleteChunksByFileID(ctx, fileID); err != nil {
+		return err
+	}
+	if err := txRepo.BatchCreateChunks(ctx, chunks); err != nil {
+		return err
+	}
+	if err := txRepo.BatchCreateEmbeddings(ctx, embeddings); err != nil {
+		return err
+	}
+	if err := txRepo.RecordEmbeddingFailures(ctx, failures); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
 func (r *Repository) AddChunkRelation(ctx context.Context, parentID, childID uuid.UUID, ordinal int) error {
 	if err := r.q.AddChunkRelation(ctx, sqlc.AddChunkRelationParams{
 		ParentChunkID: UUIDToPgtype(parentID),
@@ -889,11 +1121,83 @@ func (r *Repository) UpdateChunkImportanceScore(ctx context.Context, chunkID uui
 	return nil
 }
 
+// BatchUpdateChunkImportanceScores はチャンクの重要度スコアを importanceBatchSize 件ごとに1回のクエリで更新する
 func (r *Repository) BatchUpdateChunkImportanceScores(ctx context.Context, scores map[uuid.UUID]float64) error {
+	params := sqlc.BatchUpdateChunkImportanceScoresParams{
+		Ids:    make([]pgtype.UUID, 0, min(len(scores), importanceBatchSize)),
+		Scores: make([]float64, 0, min(len(scores), importanceBatchSize)),
+	}
+	flush := func() error {
+		if len(params.Ids) == 0 {
+			return nil
+		}
+		if err := r.q.BatchUpdateChunkImportanceScores(ctx, params); err != nil {
+			return fmt.Errorf("failed to update chunk importance scores: %w", err)
+		}
+		params.Ids = params.Ids[:0]
+		params.Scores = params.Scores[:0]
+		return nil
+	}
+
 	for chunkID, score := range scores {
-		if err := r.UpdateChunkImportanceScore(ctx, chunkID, score); err != nil {
-			return err
+		params.Ids = append(params.Ids, UUIDToPgtype(chunkID))
+		params.Scores = append(params.Scores, score)
+		if len(params.Ids) == importanceBatchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	return flush()
+}
+
+// ListChunkHistory は chunk_key で指定したチャンクの全スナップショットにおけるバージョンを古い順に返す
+// 同一ファイル内で同じ関数/クラス名（名前がない場合は同じ序数）を持つチャンクを同一の系譜とみなす
+func (r *Repository) ListChunkHistory(ctx context.Context, chunkKey string) ([]*ingestion.ChunkVersion, error) {
+	anchor, err := r.q.GetChunkLineageAnchor(ctx, chunkKey)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, sql.ErrNoRows) {
+			return []*ingestion.ChunkVersion{}, nil
 		}
+		return nil, fmt.Errorf("failed to get chunk by key: %w", err)
+	}
+
+	rows, err := r.q.ListChunkHistory(ctx, sqlc.ListChunkHistoryParams{
+		SourceID:   anchor.SourceID,
+		FilePath:   anchor.FilePath,
+		ChunkName:  anchor.ChunkName,
+		ParentName: anchor.ParentName,
+		Ordinal:    anchor.Ordinal,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list chunk history: %w", err)
+	}
+
+	versions := make([]*ingestion.ChunkVersion, 0, len(rows))
+	for _, row := range rows {
+		versions = append(versions, &ingestion.ChunkVersion{
+			ChunkID:           PgtypeToUUID(row.ID),
+			ChunkKey:          row.ChunkKey,
+			SnapshotID:        PgtypeToUUID(row.SnapshotID),
+			VersionIdentifier: row.VersionIdentifier,
+			StartLine:         int(row.StartLine),
+			EndLine:           int(row.EndLine),
+			Content:           row.Content,
+			ContentHash:       row.ContentHash,
+			GitCommitHash:     PgtextToStringPtr(row.GitCommitHash),
+			Author:            PgtextToStringPtr(row.Author),
+			UpdatedAt:         PgtypeToTimePtr(row.UpdatedAt),
+			IndexedAt:         PgtypeToTime(row.IndexedAt),
+			IsLatest:          row.IsLatest,
+		})
+	}
+
+	return versions, nil
+}
+
+func (r *Repository) RefreshLatestChunks(ctx context.Context, sourceID uuid.UUID) error {
+	if err := r.q.RefreshLatestChunks(ctx, UUIDToPgtype(sourceID)); err != nil {
+		return fmt.Errorf("failed to refresh latest chunks: %w", err)
 	}
 	return nil
 }
@@ -912,14 +1216,59 @@ func (r *Repository) CreateEmbedding(ctx context.Context, chunkID uuid.UUID, vec
 	return nil
 }
 
+// BatchCreateEmbeddings は Embedding を COPY で1回のラウンドトリップにまとめて保存する
 func (r *Repository) BatchCreateEmbeddings(ctx context.Context, embeddings []*ingestion.Embedding) error {
 	if len(embeddings) == 0 {
 		return nil
 	}
 
-	rows := make([]sqlc.CreateEmbeddingBatchParams, 0, len(embeddings))
+	rows := make([]sqlc.CreateEmbeddingCopyParams, 0, len(embeddings))
+	for _, embedding := range embeddings {
+		rows = append(rows, sqlc.CreateEmbeddingCopyParams{
+			ChunkID: UUIDToPgtype(embedding.ChunkID),
+			Vector:  pgvector.NewVector(embedding.Vector),
+			Model:   embedding.Model,
+		})
+	}
+
+	if _, err := r.q.CreateEmbeddingCopy(ctx, rows); err != nil {
+		return fmt.Errorf("failed to batch create embeddings: %w", err)
+	}
+
+	return nil
+}
+
+// ListEmbeddingsByChunkIDs はチャンクの Embedding をまとめて取得する（Embedding がないチャンクは含まない）
+func (r *Repository) ListEmbeddingsByChunkIDs(ctx context.Context, chunkIDs []uuid.UUID) ([]*ingestion.Embedding, error) {
+	if len(chunkIDs) == 0 {
+		return nil, nil
+	}
+
+	rows, err := r.q.ListEmbeddingsByChunkIDs(ctx, uuidsParam(chunkIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list embeddings by chunk ids: %w", err)
+	}
+
+	embeddings := make([]*ingestion.Embedding, 0, len(rows))
+	for _, row := range rows {
+		embeddings = append(embeddings, &ingestion.Embedding{
+			ChunkID: PgtypeToUUID(row.ChunkID),
+			Vector:  row.Vector.Slice(),
+			Model:   row.Model,
+		})
+	}
+	return embeddings, nil
+}
+
+// BatchUpsertEmbeddings は Embedding を保存し、保存済みの Embedding は置き換える
+func (r *Repository) BatchUpsertEmbeddings(ctx context.Context, embeddings []*ingestion.Embedding) error {
+	if len(embeddings) == 0 {
+		return nil
+	}
+
+	rows := make([]sqlc.UpsertEmbeddingBatchParams, 0, len(embeddings))
 	for _, embedding := range embeddings {
-		rows = append(rows, sqlc.CreateEmbeddingBatchParams{
+		rows = append(rows, sqlc.UpsertEmbeddingBatchParams{
 			ChunkID: UUIDToPgtype(embedding.ChunkID),
 			Vector:  pgvector.NewVector(embedding.Vector),
 			Model:   embedding.Model,
@@ -927,15 +1276,85 @@ func (r *Repository) BatchCreateEmbeddings(ctx context.Context, embeddings []*in
 	}
 
 	var batchErr error
-	results := r.q.CreateEmbeddingBatch(ctx, rows)
+	results := r.q.UpsertEmbeddingBatch(ctx, rows)
+	results.Exec(func(i int, err error) {
+		if err != nil && batchErr == nil {
+			batchErr = fmt.Errorf("failed to upsert embedding at index %d: %w", i, err)
+		}
+	})
+
+	if batchErr != nil {
+		return fmt.Errorf("failed to batch upsert embeddings: %w", batchErr)
+	}
+
+	return nil
+}
+
+// ListChunksForReembed は Embedding がない、または model 以外で生成したチャンクを Embedding の入力とともに取得する
+func (r *Repository) ListChunksForReembed(ctx context.Context, productID uuid.UUID, model string) ([]*ingestion.Chunk, error) {
+	rows, err := r.q.ListChunksForReembed(ctx, sqlc.ListChunksForReembedParams{
+		ProductID: UUIDToPgtype(productID),
+		Model:     model,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list chunks for reembed: %w", err)
+	}
+
+	chunks := make([]*ingestion.Chunk, 0, len(rows))
+	for _, row := range rows {
+		chunks = append(chunks, &ingestion.Chunk{
+			ID:      PgtypeToUUID(row.ID),
+			Content: row.Content,
+		})
+	}
+	return chunks, nil
+}
+
+// ListChunksWithoutModelEmbedding は追加のEmbeddingモデル model の Embedding がないチャンクを Embedding の入力とともに取得する
+func (r *Repository) ListChunksWithoutModelEmbedding(ctx context.Context, productID uuid.UUID, model string) ([]*ingestion.Chunk, error) {
+	rows, err := r.q.ListChunksWithoutModelEmbedding(ctx, sqlc.ListChunksWithoutModelEmbeddingParams{
+		ProductID: UUIDToPgtype(productID),
+		Model:     model,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list chunks without model embedding: %w", err)
+	}
+
+	chunks := make([]*ingestion.Chunk, 0, len(rows))
+	for _, row := range rows {
+		chunks = append(chunks, &ingestion.Chunk{
+			ID:      PgtypeToUUID(row.ID),
+			Content: row.Content,
+		})
+	}
+	return chunks, nil
+}
+
+// BatchUpsertModelEmbeddings は追加のEmbeddingモデルで生成した Embedding を chunk_embeddings に保存する
+func (r *Repository) BatchUpsertModelEmbeddings(ctx context.Context, embeddings []*ingestion.Embedding) error {
+	if len(embeddings) == 0 {
+		return nil
+	}
+
+	rows := make([]sqlc.UpsertChunkEmbeddingBatchParams, 0, len(embeddings))
+	for _, embedding := range embeddings {
+		rows = append(rows, sqlc.UpsertChunkEmbeddingBatchParams{
+			ChunkID: UUIDToPgtype(embedding.ChunkID),
+			Model:   embedding.Model,
+			Vector:  pgvector.NewVector(embedding.Vector),
+		})
+	}
+
+	var batchErr error
+	results := r.q.UpsertChunkEmbeddingBatch(ctx, rows)
 	results.Exec(func(i int, err error) {
 		if err != nil && batchErr == nil {
-			batchErr = fmt.Errorf("failed to insert embedding at index %d: %w", i, err)
+			batchErr = fmt.Errorf("failed to upsert model embedding at index %d: %w", i, err)
 		}
 	})
 
 	if batchErr != nil {
-		return fmt.Errorf("failed to batch create embeddings: %w", batchErr)
+		return fmt.Errorf("failed to batch upsert model embeddings: %w", batchErr)
 	}
 
 	return nil
@@ -1045,6 +1464,52 @@ func (r *Repository) GetDomainCoverageStats(ctx context.Context, snapshotID uuid
 	return coverages, nil
 }
 
+func (r *Repository) GetUnindexedImportantFiles(ctx context.Context, snapshotID uuid.UUID) ([]string, error) {
+	paths, err := r.q.GetUnindexedImportantFiles(ctx, UUIDToPgtype(snapshotID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unindexed important files: %w", err)
+	}
+	return paths, nil
+}
+
+func (r *Repository) RecordSnapshotFiles(ctx context.Context, snapshotID uuid.UUID, files []*ingestion.SnapshotFile) error {
+	if len(files) == 0 {
+		return nil
+	}
+
+	params := sqlc.RecordSnapshotFilesParams{
+		SnapshotID:  UUIDToPgtype(snapshotID),
+		FilePaths:   make([]string, 0, len(files)),
+		FileSizes:   make([]int64, 0, len(files)),
+		Domains:     make([]string, 0, len(files)),
+		SkipReasons: make([]string, 0, len(files)),
+	}
+	for _, f := range files {
+		var domain, reason string
+		if f.Domain != nil {
+			domain = *f.Domain
+		}
+		if f.SkipReason != nil {
+			reason = *f.SkipReason
+		}
+		params.FilePaths = append(params.FilePaths, f.FilePath)
+		params.FileSizes = append(params.FileSizes, f.FileSize)
+		params.Domains = append(params.Domains, domain)
+		params.SkipReasons = append(params.SkipReasons, reason)
+	}
+	if err := r.q.RecordSnapshotFiles(ctx, params); err != nil {
+		return fmt.Errorf("failed to record snapshot files: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) RecordCoverageHistory(ctx context.Context, snapshotID uuid.UUID) error {
+	if err := r.q.RecordCoverageHistory(ctx, UUIDToPgtype(snapshotID)); err != nil {
+		return fmt.Errorf("failed to record coverage history: %w", err)
+	}
+	return nil
+}
+
 func (r *Repository) CreateSnapshotFile(ctx context.Context, snapshotID uuid.UUID, filePath string, fileSize int64, domain *string, indexed bool, skipReason *string) (*ingestion.SnapshotFile, error) {
 	sf, err := r.q.CreateSnapshotFile(ctx, sqlc.CreateSnapshotFileParams{
 		SnapshotID: UUIDToPgtype(snapshotID),
@@ -1082,6 +1547,46 @@ func (r *Repository) UpdateSnapshotFileIndexed(ctx context.Context, snapshotID u
 	return nil
 }
 
+// === SnapshotUsage ===
+
+func (r *Repository) AddSnapshotUsage(ctx context.Context, snapshotID uuid.UUID, records []usage.Record) error {
+	for _, rec := range records {
+		err := r.q.AddSnapshotUsage(ctx, sqlc.AddSnapshotUsageParams{
+			SnapshotID:   UUIDToPgtype(snapshotID),
+			Kind:         string(rec.Kind),
+			Model:        rec.Model,
+			Requests:     int32(rec.Requests),
+			InputTokens:  int64(rec.InputTokens),
+			OutputTokens: int64(rec.OutputTokens),
+			CostUsd:      rec.CostUSD,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add snapshot usage: %w", err)
+		}
+	}
+	return nil
+}
+
+func (r *Repository) ListSnapshotUsage(ctx context.Context, snapshotID uuid.UUID) ([]usage.Record, error) {
+	rows, err := r.q.ListSnapshotUsage(ctx, UUIDToPgtype(snapshotID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshot usage: %w", err)
+	}
+
+	records := make([]usage.Record, 0, len(rows))
+	for _, row := range rows {
+		records = append(records, usage.Record{
+			Kind:         usage.Kind(row.Kind),
+			Model:        row.Model,
+			Requests:     int(row.Requests),
+			InputTokens:  int(row.InputTokens),
+			OutputTokens: int(row.OutputTokens),
+			CostUSD:      row.CostUsd,
+		})
+	}
+	return records, nil
+}
+
 // === Helper functions ===
 
 func convertSQLCChunk(row sqlc.Chunk) *ingestion.Chunk {
@@ -1107,6 +1612,10 @@ func convertSQLCChunk(row sqlc.Chunk) *ingestion.Chunk {
 		CommentRatio:         PgtypeToFloat64Ptr(row.CommentRatio),
 		CyclomaticComplexity: PgtypeToIntPtr(row.CyclomaticComplexity),
 		EmbeddingContext:     PgtextToStringPtr(row.EmbeddingContext),
+		EmbeddingTruncated:   row.EmbeddingTruncated,
+		Breadcrumb:           PgtextToStringPtr(row.Breadcrumb),
+		PageStart:            PgtypeToIntPtr(row.PageStart),
+		PageEnd:              PgtypeToIntPtr(row.PageEnd),
 		// 階層関係と重要度
 		Level:           int(row.Level),
 		ImportanceScore: PgtypeToFloat64Ptr(row.ImportanceScore),
@@ -1126,5 +1635,7 @@ func convertSQLCChunk(row sqlc.Chunk) *ingestion.Chunk {
 		IsLatest:         row.IsLatest,
 		// 決定的な識別子
 		ChunkKey: row.ChunkKey,
+		// チャンカーの実装とバージョン
+		ChunkerVersion: row.ChunkerVersion.String,
 	}
 }
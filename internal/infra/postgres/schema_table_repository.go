@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+
+	"github.com/jinford/dev-rag/internal/core/ask"
+	"github.com/jinford/dev-rag/internal/core/sqlschema"
+	"github.com/jinford/dev-rag/internal/infra/postgres/sqlc"
+)
+
+// ReplaceSchemaTables はスナップショットのスキーマカタログを置き換える（既存のテーブルは削除する）
+func (r *Repository) ReplaceSchemaTables(ctx context.Context, snapshotID uuid.UUID, tables []*sqlschema.Table) error {
+	if err := r.q.DeleteSchemaTablesBySnapshot(ctx, UUIDToPgtype(snapshotID)); err != nil {
+		return fmt.Errorf("failed to delete schema tables: %w", err)
+	}
+	for _, table := range tables {
+		columns, err := json.Marshal(append([]sqlschema.Column{}, table.Columns...))
+		if err != nil {
+			return fmt.Errorf("failed to marshal schema columns: %w", err)
+		}
+		indexes, err := json.Marshal(append([]sqlschema.Index{}, table.Indexes...))
+		if err != nil {
+			return fmt.Errorf("failed to marshal schema indexes: %w", err)
+		}
+
+		err = r.q.CreateSchemaTable(ctx, sqlc.CreateSchemaTableParams{
+			SnapshotID:  UUIDToPgtype(snapshotID),
+			Name:        table.Name,
+			Comment:     table.Comment,
+			Columns:     columns,
+			Indexes:     indexes,
+			SourcePaths: append([]string{}, table.SourcePaths...),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create schema table: %w", err)
+		}
+	}
+	return nil
+}
+
+// SchemaTableRepository は core/ask.SchemaLookup を実装する PostgreSQL リポジトリ。
+type SchemaTableRepository struct {
+	q sqlc.Querier
+}
+
+// NewSchemaTableRepository は新しい SchemaTableRepository を返す。
+func NewSchemaTableRepository(q sqlc.Querier) *SchemaTableRepository {
+	return &SchemaTableRepository{q: q}
+}
+
+var _ ask.SchemaLookup = (*SchemaTableRepository)(nil)
+
+func (r *SchemaTableRepository) ListTables(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID]) ([]*sqlschema.Table, error) {
+	rows, err := r.q.ListSchemaTables(ctx, sqlc.ListSchemaTablesParams{
+		ProductID:  UUIDOptionToPgtype(productID),
+		SnapshotID: UUIDOptionToPgtype(snapshotID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schema tables: %w", err)
+	}
+
+	tables := make([]*sqlschema.Table, 0, len(rows))
+	for _, row := range rows {
+		table := &sqlschema.Table{
+			ID:          PgtypeToUUID(row.ID),
+			SnapshotID:  PgtypeToUUID(row.SnapshotID),
+			SourceName:  row.SourceName,
+			Name:        row.Name,
+			Comment:     row.Comment,
+			SourcePaths: row.SourcePaths,
+		}
+		if err := json.Unmarshal(row.Columns, &table.Columns); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal schema columns: %w", err)
+		}
+		if err := json.Unmarshal(row.Indexes, &table.Indexes); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal schema indexes: %w", err)
+		}
+		tables = append(tables, table)
+	}
+	return tables, nil
+}
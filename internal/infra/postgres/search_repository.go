@@ -8,6 +8,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	pgvector "github.com/pgvector/pgvector-go"
 	"github.com/samber/mo"
 
@@ -28,12 +29,23 @@ func NewSearchRepository(q sqlc.Querier) *SearchRepository {
 var _ search.Repository = (*SearchRepository)(nil)
 
 func (r *SearchRepository) SearchByProduct(ctx context.Context, productID uuid.UUID, queryVector []float32, limit int, filters search.SearchFilter) ([]*search.SearchResult, error) {
+	if filters.EmbeddingModel != "" {
+		return r.searchByModel(ctx, mo.Some(productID), mo.None[uuid.UUID](), queryVector, limit, filters)
+	}
 	rows, err := r.q.SearchChunksByProduct(ctx, sqlc.SearchChunksByProductParams{
-		QueryVector: pgvector.NewVector(queryVector),
-		ProductID:   UUIDToPgtype(productID),
-		PathPrefix:  StringPtrToPgtext(filters.PathPrefix),
-		ContentType: StringPtrToPgtext(filters.ContentType),
-		RowLimit:    int32(limit),
+		QueryVector:         pgvector.NewVector(queryVector),
+		ProductID:           UUIDToPgtype(productID),
+		PathPrefix:          StringPtrToPgtext(filters.PathPrefix),
+		ContentType:         StringPtrToPgtext(filters.ContentType),
+		Domains:             stringsParam(filters.Domains),
+		Languages:           stringsParam(filters.Languages),
+		FileIds:             uuidsParam(filters.FileIDs),
+		SnapshotID:          UUIDPtrToPgtype(filters.SnapshotID),
+		ImportanceWeight:    importanceWeight(filters),
+		RecencyWeight:       Float64PtrToPgFloat8(filters.RecencyWeight),
+		RecencyHalfLifeDays: recencyHalfLifeDays(filters),
+		CandidateLimit:      int32(limit * rerankCandidateFactor),
+		RowLimit:            int32(limit),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to search by product: %w", err)
@@ -42,24 +54,40 @@ func (r *SearchRepository) SearchByProduct(ctx context.Context, productID uuid.U
 	results := make([]*search.SearchResult, 0, len(rows))
 	for _, row := range rows {
 		results = append(results, &search.SearchResult{
-			ChunkID:   PgtypeToUUID(row.ChunkID),
-			FilePath:  row.Path,
-			StartLine: int(row.StartLine),
-			EndLine:   int(row.EndLine),
-			Content:   row.Content,
-			Score:     row.Score,
+			ChunkID:    PgtypeToUUID(row.ChunkID),
+			FileID:     PgtypeToUUID(row.FileID),
+			FilePath:   row.Path,
+			StartLine:  int(row.StartLine),
+			EndLine:    int(row.EndLine),
+			Level:      int(row.Level),
+			Breadcrumb: PgtextToStringPtr(row.Breadcrumb),
+			PageStart:  PgtypeToIntPtr(row.PageStart),
+			PageEnd:    PgtypeToIntPtr(row.PageEnd),
+			Content:    row.Content,
+			Score:      row.Score,
 		})
 	}
 	return results, nil
 }
 
 func (r *SearchRepository) SearchBySource(ctx context.Context, sourceID uuid.UUID, queryVector []float32, limit int, filters search.SearchFilter) ([]*search.SearchResult, error) {
+	if filters.EmbeddingModel != "" {
+		return r.searchByModel(ctx, mo.None[uuid.UUID](), mo.Some(sourceID), queryVector, limit, filters)
+	}
 	rows, err := r.q.SearchChunksBySource(ctx, sqlc.SearchChunksBySourceParams{
-		QueryVector: pgvector.NewVector(queryVector),
-		SourceID:    UUIDToPgtype(sourceID),
-		PathPrefix:  StringPtrToPgtext(filters.PathPrefix),
-		ContentType: StringPtrToPgtext(filters.ContentType),
-		RowLimit:    int32(limit),
+		QueryVector:         pgvector.NewVector(queryVector),
+		SourceID:            UUIDToPgtype(sourceID),
+		PathPrefix:          StringPtrToPgtext(filters.PathPrefix),
+		ContentType:         StringPtrToPgtext(filters.ContentType),
+		Domains:             stringsParam(filters.Domains),
+		Languages:           stringsParam(filters.Languages),
+		FileIds:             uuidsParam(filters.FileIDs),
+		SnapshotID:          UUIDPtrToPgtype(filters.SnapshotID),
+		ImportanceWeight:    importanceWeight(filters),
+		RecencyWeight:       Float64PtrToPgFloat8(filters.RecencyWeight),
+		RecencyHalfLifeDays: recencyHalfLifeDays(filters),
+		CandidateLimit:      int32(limit * rerankCandidateFactor),
+		RowLimit:            int32(limit),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to search by source: %w", err)
@@ -68,38 +96,130 @@ func (r *SearchRepository) SearchBySource(ctx context.Context, sourceID uuid.UUI
 	results := make([]*search.SearchResult, 0, len(rows))
 	for _, row := range rows {
 		results = append(results, &search.SearchResult{
-			ChunkID:   PgtypeToUUID(row.ChunkID),
-			FilePath:  row.Path,
-			StartLine: int(row.StartLine),
-			EndLine:   int(row.EndLine),
-			Content:   row.Content,
-			Score:     row.Score,
+			ChunkID:    PgtypeToUUID(row.ChunkID),
+			FileID:     PgtypeToUUID(row.FileID),
+			FilePath:   row.Path,
+			StartLine:  int(row.StartLine),
+			EndLine:    int(row.EndLine),
+			Level:      int(row.Level),
+			Breadcrumb: PgtextToStringPtr(row.Breadcrumb),
+			PageStart:  PgtypeToIntPtr(row.PageStart),
+			PageEnd:    PgtypeToIntPtr(row.PageEnd),
+			Content:    row.Content,
+			Score:      row.Score,
 		})
 	}
 	return results, nil
 }
 
 func (r *SearchRepository) SearchChunksBySnapshot(ctx context.Context, snapshotID uuid.UUID, queryVector []float32, limit int, filters search.SearchFilter) ([]*search.SearchResult, error) {
+	if filters.EmbeddingModel != "" {
+		filters.SnapshotID = &snapshotID
+		return r.searchByModel(ctx, mo.None[uuid.UUID](), mo.None[uuid.UUID](), queryVector, limit, filters)
+	}
 	rows, err := r.q.SearchChunksBySnapshot(ctx, sqlc.SearchChunksBySnapshotParams{
+		QueryVector:         pgvector.NewVector(queryVector),
+		SnapshotID:          UUIDToPgtype(snapshotID),
+		PathPrefix:          StringPtrToPgtext(filters.PathPrefix),
+		ContentType:         StringPtrToPgtext(filters.ContentType),
+		Domains:             stringsParam(filters.Domains),
+		Languages:           stringsParam(filters.Languages),
+		FileIds:             uuidsParam(filters.FileIDs),
+		ImportanceWeight:    importanceWeight(filters),
+		RecencyWeight:       Float64PtrToPgFloat8(filters.RecencyWeight),
+		RecencyHalfLifeDays: recencyHalfLifeDays(filters),
+		CandidateLimit:      int32(limit * rerankCandidateFactor),
+		LimitVal:            int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search chunks by snapshot: %w", err)
+	}
+
+	results := make([]*search.SearchResult, 0, len(rows))
+	for _, row := range rows {
+		results = append(results, &search.SearchResult{
+			ChunkID:    PgtypeToUUID(row.ChunkID),
+			FileID:     PgtypeToUUID(row.FileID),
+			FilePath:   row.Path,
+			StartLine:  int(row.StartLine),
+			EndLine:    int(row.EndLine),
+			Level:      int(row.Level),
+			Breadcrumb: PgtextToStringPtr(row.Breadcrumb),
+			PageStart:  PgtypeToIntPtr(row.PageStart),
+			PageEnd:    PgtypeToIntPtr(row.PageEnd),
+			Content:    row.Content,
+			Score:      row.Score,
+		})
+	}
+	return results, nil
+}
+
+// searchByModel は追加のEmbeddingモデル（filters.EmbeddingModel）の Embedding でチャンクを検索する
+// productID / sourceID / filters.SnapshotID のうち指定されたもので範囲を限定する
+func (r *SearchRepository) searchByModel(ctx context.Context, productID, sourceID mo.Option[uuid.UUID], queryVector []float32, limit int, filters search.SearchFilter) ([]*search.SearchResult, error) {
+	rows, err := r.q.SearchChunksByModel(ctx, sqlc.SearchChunksByModelParams{
+		QueryVector:         pgvector.NewVector(queryVector),
+		Model:               filters.EmbeddingModel,
+		ProductID:           UUIDOptionToPgtype(productID),
+		SourceID:            UUIDOptionToPgtype(sourceID),
+		SnapshotID:          UUIDPtrToPgtype(filters.SnapshotID),
+		PathPrefix:          StringPtrToPgtext(filters.PathPrefix),
+		ContentType:         StringPtrToPgtext(filters.ContentType),
+		Domains:             stringsParam(filters.Domains),
+		Languages:           stringsParam(filters.Languages),
+		FileIds:             uuidsParam(filters.FileIDs),
+		ImportanceWeight:    importanceWeight(filters),
+		RecencyWeight:       Float64PtrToPgFloat8(filters.RecencyWeight),
+		RecencyHalfLifeDays: recencyHalfLifeDays(filters),
+		CandidateLimit:      int32(limit * rerankCandidateFactor),
+		RowLimit:            int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search by model %s: %w", filters.EmbeddingModel, err)
+	}
+
+	results := make([]*search.SearchResult, 0, len(rows))
+	for _, row := range rows {
+		results = append(results, &search.SearchResult{
+			ChunkID:    PgtypeToUUID(row.ChunkID),
+			FileID:     PgtypeToUUID(row.FileID),
+			FilePath:   row.Path,
+			StartLine:  int(row.StartLine),
+			EndLine:    int(row.EndLine),
+			Level:      int(row.Level),
+			Breadcrumb: PgtextToStringPtr(row.Breadcrumb),
+			PageStart:  PgtypeToIntPtr(row.PageStart),
+			PageEnd:    PgtypeToIntPtr(row.PageEnd),
+			Content:    row.Content,
+			Score:      row.Score,
+		})
+	}
+	return results, nil
+}
+
+func (r *SearchRepository) SearchFileSummaries(ctx context.Context, productID, sourceID mo.Option[uuid.UUID], queryVector []float32, limit int, filters search.SearchFilter) ([]*search.FileSummaryResult, error) {
+	rows, err := r.q.SearchFileSummariesByScope(ctx, sqlc.SearchFileSummariesByScopeParams{
 		QueryVector: pgvector.NewVector(queryVector),
-		SnapshotID:  UUIDToPgtype(snapshotID),
+		ProductID:   UUIDOptionToPgtype(productID),
+		SourceID:    UUIDOptionToPgtype(sourceID),
+		SnapshotID:  UUIDPtrToPgtype(filters.SnapshotID),
 		PathPrefix:  StringPtrToPgtext(filters.PathPrefix),
 		ContentType: StringPtrToPgtext(filters.ContentType),
+		Domains:     stringsParam(filters.Domains),
+		Languages:   stringsParam(filters.Languages),
 		LimitVal:    int32(limit),
 	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to search chunks by snapshot: %w", err)
+		return nil, fmt.Errorf("failed to search file summaries: %w", err)
 	}
 
-	results := make([]*search.SearchResult, 0, len(rows))
+	results := make([]*search.FileSummaryResult, 0, len(rows))
 	for _, row := range rows {
-		results = append(results, &search.SearchResult{
-			ChunkID:   PgtypeToUUID(row.ChunkID),
-			FilePath:  row.Path,
-			StartLine: int(row.StartLine),
-			EndLine:   int(row.EndLine),
-			Content:   row.Content,
-			Score:     row.Score,
+		results = append(results, &search.FileSummaryResult{
+			FileID:   PgtypeToUUID(row.FileID),
+			FilePath: row.Path,
+			Content:  row.Content,
+			Score:    row.Score,
 		})
 	}
 	return results, nil
@@ -252,6 +372,7 @@ func (r *SearchRepository) SearchSummariesByProduct(ctx context.Context, product
 		ProductID:    UUIDToPgtype(productID),
 		SummaryTypes: summaryTypes,
 		PathPrefix:   StringPtrToPgtext(filters.PathPrefix),
+		SnapshotID:   UUIDPtrToPgtype(filters.SnapshotID),
 		LimitVal:     int32(limit),
 	})
 	if err != nil {
@@ -272,6 +393,191 @@ func (r *SearchRepository) SearchSummariesByProduct(ctx context.Context, product
 	return results, nil
 }
 
+func (r *SearchRepository) RankProductsBySummaries(ctx context.Context, queryVector []float32, model string, limit int) ([]*search.ProductCandidate, error) {
+	rows, err := r.q.RankProductsBySummaries(ctx, sqlc.RankProductsBySummariesParams{
+		QueryVector: pgvector.NewVector(queryVector),
+		Model:       model,
+		LimitVal:    int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to rank products by summaries: %w", err)
+	}
+
+	candidates := make([]*search.ProductCandidate, 0, len(rows))
+	for _, row := range rows {
+		candidates = append(candidates, &search.ProductCandidate{
+			ProductID:   PgtypeToUUID(row.ProductID),
+			ProductName: row.ProductName,
+			Score:       row.Score,
+		})
+	}
+	return candidates, nil
+}
+
+func (r *SearchRepository) GetRelatedChunks(ctx context.Context, chunkIDs []uuid.UUID, depTypes []string) ([]*search.RelatedChunk, error) {
+	ids := make([]pgtype.UUID, 0, len(chunkIDs))
+	for _, id := range chunkIDs {
+		ids = append(ids, UUIDToPgtype(id))
+	}
+	if depTypes == nil {
+		depTypes = []string{}
+	}
+
+	rows, err := r.q.ListRelatedChunks(ctx, sqlc.ListRelatedChunksParams{
+		ChunkIds: ids,
+		DepTypes: depTypes,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list related chunks: %w", err)
+	}
+
+	results := make([]*search.RelatedChunk, 0, len(rows))
+	for _, row := range rows {
+		results = append(results, &search.RelatedChunk{
+			ViaChunkID: PgtypeToUUID(row.ViaChunkID),
+			Direction:  row.Direction,
+			DepType:    row.DepType,
+			Symbol:     PgtextToStringPtr(row.Symbol),
+			ChunkID:    PgtypeToUUID(row.ChunkID),
+			FilePath:   row.Path,
+			StartLine:  int(row.StartLine),
+			EndLine:    int(row.EndLine),
+			Content:    row.Content,
+			TokenCount: PgtypeToIntPtr(row.TokenCount),
+		})
+	}
+	return results, nil
+}
+
+func (r *SearchRepository) GetChunksAtLine(ctx context.Context, productID uuid.UUID, filePath string, line int) ([]*search.SearchResult, error) {
+	rows, err := r.q.ListChunksAtLine(ctx, sqlc.ListChunksAtLineParams{
+		ProductID: UUIDToPgtype(productID),
+		Path:      filePath,
+		Line:      int32(line),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list chunks at line: %w", err)
+	}
+
+	results := make([]*search.SearchResult, 0, len(rows))
+	for _, row := range rows {
+		results = append(results, &search.SearchResult{
+			ChunkID:    PgtypeToUUID(row.ChunkID),
+			FileID:     PgtypeToUUID(row.FileID),
+			FilePath:   row.Path,
+			StartLine:  int(row.StartLine),
+			EndLine:    int(row.EndLine),
+			Level:      int(row.Level),
+			Breadcrumb: PgtextToStringPtr(row.Breadcrumb),
+			PageStart:  PgtypeToIntPtr(row.PageStart),
+			PageEnd:    PgtypeToIntPtr(row.PageEnd),
+			Content:    row.Content,
+			Score:      1,
+		})
+	}
+	return results, nil
+}
+
+func (r *SearchRepository) GrepChunks(ctx context.Context, productID uuid.UUID, query search.GrepQuery) ([]*search.GrepChunk, error) {
+	rows, err := r.q.GrepChunks(ctx, sqlc.GrepChunksParams{
+		ProductID:  UUIDToPgtype(productID),
+		SnapshotID: UUIDOptionToPgtype(query.SnapshotID),
+		PathPrefix: StringToNullableText(query.PathPrefix),
+		PathLike:   StringToNullableText(query.PathLike),
+		IgnoreCase: query.IgnoreCase,
+		Pattern:    query.Pattern,
+		MaxChunks:  int32(query.MaxChunks),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to grep chunks: %w", err)
+	}
+
+	chunks := make([]*search.GrepChunk, 0, len(rows))
+	for _, row := range rows {
+		chunks = append(chunks, &search.GrepChunk{
+			ChunkID:    PgtypeToUUID(row.ChunkID),
+			FileID:     PgtypeToUUID(row.FileID),
+			SourceName: row.SourceName,
+			FilePath:   row.Path,
+			StartLine:  int(row.StartLine),
+			EndLine:    int(row.EndLine),
+			Content:    row.Content,
+		})
+	}
+	return chunks, nil
+}
+
+func (r *SearchRepository) GetChunkHierarchies(ctx context.Context, chunkIDs []uuid.UUID) ([]*search.ChunkHierarchy, error) {
+	ids := make([]pgtype.UUID, 0, len(chunkIDs))
+	for _, id := range chunkIDs {
+		ids = append(ids, UUIDToPgtype(id))
+	}
+
+	rows, err := r.q.ListChunkHierarchyContexts(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list chunk hierarchy contexts: %w", err)
+	}
+
+	results := make([]*search.ChunkHierarchy, 0, len(rows))
+	for _, row := range rows {
+		h := &search.ChunkHierarchy{
+			ChunkID:     PgtypeToUUID(row.ChunkID),
+			FileID:      PgtypeToUUID(row.FileID),
+			Level:       int(row.Level),
+			FileSummary: PgtextToStringPtr(row.FileSummary),
+		}
+		if row.ParentID.Valid {
+			h.Parent = &search.ChunkContext{
+				ID:        PgtypeToUUID(row.ParentID),
+				FileID:    h.FileID,
+				StartLine: int(row.ParentStartLine),
+				EndLine:   int(row.ParentEndLine),
+				Content:   row.ParentContent,
+				Level:     h.Level - 1,
+			}
+		}
+		results = append(results, h)
+	}
+	return results, nil
+}
+
+// stringsParam は絞り込み条件を sqlc の配列パラメータに変換する（未指定は空配列で絞り込みなし）
+func stringsParam(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+// rerankCandidateFactor は重要度・鮮度で並べ替える際にベクトル距離順で取得する候補数の倍率
+// 重み付けがない場合、検索クエリは候補を要求件数に絞る
+const rerankCandidateFactor = 4
+
+// importanceWeight は類似度に重要度を加味する重みを返す（未指定は 0 で類似度のみ）
+func importanceWeight(filters search.SearchFilter) float64 {
+	if filters.ImportanceWeight == nil {
+		return 0
+	}
+	return *filters.ImportanceWeight
+}
+
+// recencyHalfLifeDays は鮮度の半減期（日数）を返す（未指定は既定値）
+func recencyHalfLifeDays(filters search.SearchFilter) float64 {
+	if filters.RecencyHalfLifeDays <= 0 {
+		return search.DefaultRecencyHalfLifeDays
+	}
+	return float64(filters.RecencyHalfLifeDays)
+}
+
+// uuidsParam は絞り込み条件のIDを sqlc の配列パラメータに変換する（未指定は空配列で絞り込みなし）
+func uuidsParam(ids []uuid.UUID) []pgtype.UUID {
+	params := make([]pgtype.UUID, 0, len(ids))
+	for _, id := range ids {
+		params = append(params, UUIDToPgtype(id))
+	}
+	return params
+}
+
 // convertSearchChunk は searchsqlc.Chunk を search.ChunkContext に変換する。
 func convertSearchChunk(row sqlc.Chunk) *search.ChunkContext {
 	return &search.ChunkContext{
@@ -0,0 +1,67 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+
+	"github.com/jinford/dev-rag/internal/core/wiki"
+	"github.com/jinford/dev-rag/internal/infra/postgres/sqlc"
+)
+
+// SourceFileRepository は core/wiki.SourceFileReader と core/wiki.SnapshotReader を実装する PostgreSQL リポジトリ。
+type SourceFileRepository struct {
+	q sqlc.Querier
+}
+
+// NewSourceFileRepository は新しい SourceFileRepository を返す。
+func NewSourceFileRepository(q sqlc.Querier) *SourceFileRepository {
+	return &SourceFileRepository{q: q}
+}
+
+var (
+	_ wiki.SourceFileReader = (*SourceFileRepository)(nil)
+	_ wiki.SnapshotReader   = (*SourceFileRepository)(nil)
+)
+
+func (r *SourceFileRepository) ListSourceFileLines(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID]) ([]*wiki.SourceFileLines, error) {
+	rows, err := r.q.ListSourceFileLines(ctx, sqlc.ListSourceFileLinesParams{
+		ProductID:  UUIDOptionToPgtype(productID),
+		SnapshotID: UUIDOptionToPgtype(snapshotID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list source file lines: %w", err)
+	}
+
+	files := make([]*wiki.SourceFileLines, 0, len(rows))
+	for _, row := range rows {
+		files = append(files, &wiki.SourceFileLines{
+			Path:      row.Path,
+			LineCount: int(row.LineCount),
+		})
+	}
+	return files, nil
+}
+
+func (r *SourceFileRepository) ListScopeSnapshots(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID]) ([]*wiki.TOCSnapshot, error) {
+	rows, err := r.q.ListScopeSnapshots(ctx, sqlc.ListScopeSnapshotsParams{
+		ProductID:  UUIDOptionToPgtype(productID),
+		SnapshotID: UUIDOptionToPgtype(snapshotID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scope snapshots: %w", err)
+	}
+
+	snapshots := make([]*wiki.TOCSnapshot, 0, len(rows))
+	for _, row := range rows {
+		snapshots = append(snapshots, &wiki.TOCSnapshot{
+			SourceName:        row.SourceName,
+			SnapshotID:        PgtypeToUUID(row.SnapshotID),
+			VersionIdentifier: row.VersionIdentifier,
+			IndexedAt:         PgtypeToTimePtr(row.IndexedAt),
+		})
+	}
+	return snapshots, nil
+}
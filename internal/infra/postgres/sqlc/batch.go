@@ -69,3 +69,290 @@ func (b *CreateEmbeddingBatchBatchResults) Close() error {
 	b.closed = true
 	return b.br.Close()
 }
+
+const upsertChunkBatch = `-- name: UpsertChunkBatch :batchexec
+INSERT INTO chunks (
+    id,
+    file_id, ordinal, start_line, end_line, content, content_hash, token_count,
+    chunk_type, chunk_name, parent_name, signature, doc_comment, imports, calls,
+    lines_of_code, comment_ratio, cyclomatic_complexity, embedding_context,
+    level, importance_score,
+    standard_imports, external_imports, internal_calls, external_calls, type_dependencies,
+    source_snapshot_id, git_commit_hash, author, updated_at, indexed_at,
+    file_version, is_latest, chunk_key, embedding_truncated, breadcrumb, page_start, page_end, chunker_version
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31, $32, $33, $34, $35, $36, $37, $38, $39)
+ON CONFLICT (id) DO UPDATE SET
+    file_id = EXCLUDED.file_id,
+    ordinal = EXCLUDED.ordinal,
+    start_line = EXCLUDED.start_line,
+    end_line = EXCLUDED.end_line,
+    content = EXCLUDED.content,
+    content_hash = EXCLUDED.content_hash,
+    token_count = EXCLUDED.token_count,
+    chunk_type = EXCLUDED.chunk_type,
+    chunk_name = EXCLUDED.chunk_name,
+    parent_name = EXCLUDED.parent_name,
+    signature = EXCLUDED.signature,
+    doc_comment = EXCLUDED.doc_comment,
+    imports = EXCLUDED.imports,
+    calls = EXCLUDED.calls,
+    lines_of_code = EXCLUDED.lines_of_code,
+    comment_ratio = EXCLUDED.comment_ratio,
+    cyclomatic_complexity = EXCLUDED.cyclomatic_complexity,
+    embedding_context = EXCLUDED.embedding_context,
+    level = EXCLUDED.level,
+    importance_score = EXCLUDED.importance_score,
+    standard_imports = EXCLUDED.standard_imports,
+    external_imports = EXCLUDED.external_imports,
+    internal_calls = EXCLUDED.internal_calls,
+    external_calls = EXCLUDED.external_calls,
+    type_dependencies = EXCLUDED.type_dependencies,
+    source_snapshot_id = EXCLUDED.source_snapshot_id,
+    git_commit_hash = EXCLUDED.git_commit_hash,
+    author = EXCLUDED.author,
+    updated_at = EXCLUDED.updated_at,
+    indexed_at = EXCLUDED.indexed_at,
+    file_version = EXCLUDED.file_version,
+    is_latest = EXCLUDED.is_latest,
+    chunk_key = EXCLUDED.chunk_key,
+    embedding_truncated = EXCLUDED.embedding_truncated,
+    breadcrumb = EXCLUDED.breadcrumb,
+    page_start = EXCLUDED.page_start,
+    page_end = EXCLUDED.page_end,
+    chunker_version = EXCLUDED.chunker_version
+`
+
+type UpsertChunkBatchBatchResults struct {
+	br     pgx.BatchResults
+	tot    int
+	closed bool
+}
+
+type UpsertChunkBatchParams struct {
+	ID                   pgtype.UUID      `json:"id"`
+	FileID               pgtype.UUID      `json:"file_id"`
+	Ordinal              int32            `json:"ordinal"`
+	StartLine            int32            `json:"start_line"`
+	EndLine              int32            `json:"end_line"`
+	Content              string           `json:"content"`
+	ContentHash          string           `json:"content_hash"`
+	TokenCount           pgtype.Int4      `json:"token_count"`
+	ChunkType            pgtype.Text      `json:"chunk_type"`
+	ChunkName            pgtype.Text      `json:"chunk_name"`
+	ParentName           pgtype.Text      `json:"parent_name"`
+	Signature            pgtype.Text      `json:"signature"`
+	DocComment           pgtype.Text      `json:"doc_comment"`
+	Imports              []byte           `json:"imports"`
+	Calls                []byte           `json:"calls"`
+	LinesOfCode          pgtype.Int4      `json:"lines_of_code"`
+	CommentRatio         pgtype.Numeric   `json:"comment_ratio"`
+	CyclomaticComplexity pgtype.Int4      `json:"cyclomatic_complexity"`
+	EmbeddingContext     pgtype.Text      `json:"embedding_context"`
+	Level                int32            `json:"level"`
+	ImportanceScore      pgtype.Numeric   `json:"importance_score"`
+	StandardImports      []byte           `json:"standard_imports"`
+	ExternalImports      []byte           `json:"external_imports"`
+	InternalCalls        []byte           `json:"internal_calls"`
+	ExternalCalls        []byte           `json:"external_calls"`
+	TypeDependencies     []byte           `json:"type_dependencies"`
+	SourceSnapshotID     pgtype.UUID      `json:"source_snapshot_id"`
+	GitCommitHash        pgtype.Text      `json:"git_commit_hash"`
+	Author               pgtype.Text      `json:"author"`
+	UpdatedAt            pgtype.Timestamp `json:"updated_at"`
+	IndexedAt            pgtype.Timestamp `json:"indexed_at"`
+	FileVersion          pgtype.Text      `json:"file_version"`
+	IsLatest             bool             `json:"is_latest"`
+	ChunkKey             string           `json:"chunk_key"`
+	EmbeddingTruncated   bool             `json:"embedding_truncated"`
+	Breadcrumb           pgtype.Text      `json:"breadcrumb"`
+	PageStart            pgtype.Int4      `json:"page_start"`
+	PageEnd              pgtype.Int4      `json:"page_end"`
+	ChunkerVersion       pgtype.Text      `json:"chunker_version"`
+}
+
+// スナップショットの再実行（再開・--force-init）でチャンクを保存する
+// チャンクの ID は chunk_key から決定的に生成するため、前回の実行で保存した同じチャンクは置き換える
+func (q *Queries) UpsertChunkBatch(ctx context.Context, arg []UpsertChunkBatchParams) *UpsertChunkBatchBatchResults {
+	batch := &pgx.Batch{}
+	for _, a := range arg {
+		vals := []interface{}{
+			a.ID,
+			a.FileID,
+			a.Ordinal,
+			a.StartLine,
+			a.EndLine,
+			a.Content,
+			a.ContentHash,
+			a.TokenCount,
+			a.ChunkType,
+			a.ChunkName,
+			a.ParentName,
+			a.Signature,
+			a.DocComment,
+			a.Imports,
+			a.Calls,
+			a.LinesOfCode,
+			a.CommentRatio,
+			a.CyclomaticComplexity,
+			a.EmbeddingContext,
+			a.Level,
+			a.ImportanceScore,
+			a.StandardImports,
+			a.ExternalImports,
+			a.InternalCalls,
+			a.ExternalCalls,
+			a.TypeDependencies,
+			a.SourceSnapshotID,
+			a.GitCommitHash,
+			a.Author,
+			a.UpdatedAt,
+			a.IndexedAt,
+			a.FileVersion,
+			a.IsLatest,
+			a.ChunkKey,
+			a.EmbeddingTruncated,
+			a.Breadcrumb,
+			a.PageStart,
+			a.PageEnd,
+			a.ChunkerVersion,
+		}
+		batch.Queue(upsertChunkBatch, vals...)
+	}
+	br := q.db.SendBatch(ctx, batch)
+	return &UpsertChunkBatchBatchResults{br, len(arg), false}
+}
+
+func (b *UpsertChunkBatchBatchResults) Exec(f func(int, error)) {
+	defer b.br.Close()
+	for t := 0; t < b.tot; t++ {
+		if b.closed {
+			if f != nil {
+				f(t, ErrBatchAlreadyClosed)
+			}
+			continue
+		}
+		_, err := b.br.Exec()
+		if f != nil {
+			f(t, err)
+		}
+	}
+}
+
+func (b *UpsertChunkBatchBatchResults) Close() error {
+	b.closed = true
+	return b.br.Close()
+}
+
+const upsertChunkEmbeddingBatch = `-- name: UpsertChunkEmbeddingBatch :batchexec
+INSERT INTO chunk_embeddings (chunk_id, model, vector)
+VALUES ($1, $2, $3)
+ON CONFLICT (chunk_id, model) DO UPDATE SET
+    vector = EXCLUDED.vector,
+    created_at = CURRENT_TIMESTAMP
+`
+
+type UpsertChunkEmbeddingBatchBatchResults struct {
+	br     pgx.BatchResults
+	tot    int
+	closed bool
+}
+
+type UpsertChunkEmbeddingBatchParams struct {
+	ChunkID pgtype.UUID        `json:"chunk_id"`
+	Model   string             `json:"model"`
+	Vector  pgvector_go.Vector `json:"vector"`
+}
+
+// 追加のEmbeddingモデルで生成した Embedding を保存する（同じモデルで生成済みの場合は置き換える）
+func (q *Queries) UpsertChunkEmbeddingBatch(ctx context.Context, arg []UpsertChunkEmbeddingBatchParams) *UpsertChunkEmbeddingBatchBatchResults {
+	batch := &pgx.Batch{}
+	for _, a := range arg {
+		vals := []interface{}{
+			a.ChunkID,
+			a.Model,
+			a.Vector,
+		}
+		batch.Queue(upsertChunkEmbeddingBatch, vals...)
+	}
+	br := q.db.SendBatch(ctx, batch)
+	return &UpsertChunkEmbeddingBatchBatchResults{br, len(arg), false}
+}
+
+func (b *UpsertChunkEmbeddingBatchBatchResults) Exec(f func(int, error)) {
+	defer b.br.Close()
+	for t := 0; t < b.tot; t++ {
+		if b.closed {
+			if f != nil {
+				f(t, ErrBatchAlreadyClosed)
+			}
+			continue
+		}
+		_, err := b.br.Exec()
+		if f != nil {
+			f(t, err)
+		}
+	}
+}
+
+func (b *UpsertChunkEmbeddingBatchBatchResults) Close() error {
+	b.closed = true
+	return b.br.Close()
+}
+
+const upsertEmbeddingBatch = `-- name: UpsertEmbeddingBatch :batchexec
+INSERT INTO embeddings (chunk_id, vector, model)
+VALUES ($1, $2, $3)
+ON CONFLICT (chunk_id) DO UPDATE SET
+    vector = EXCLUDED.vector,
+    model = EXCLUDED.model,
+    created_at = CURRENT_TIMESTAMP
+`
+
+type UpsertEmbeddingBatchBatchResults struct {
+	br     pgx.BatchResults
+	tot    int
+	closed bool
+}
+
+type UpsertEmbeddingBatchParams struct {
+	ChunkID pgtype.UUID        `json:"chunk_id"`
+	Vector  pgvector_go.Vector `json:"vector"`
+	Model   string             `json:"model"`
+}
+
+// 別のモデルで生成した Embedding は置き換える（index reembed で使用）
+func (q *Queries) UpsertEmbeddingBatch(ctx context.Context, arg []UpsertEmbeddingBatchParams) *UpsertEmbeddingBatchBatchResults {
+	batch := &pgx.Batch{}
+	for _, a := range arg {
+		vals := []interface{}{
+			a.ChunkID,
+			a.Vector,
+			a.Model,
+		}
+		batch.Queue(upsertEmbeddingBatch, vals...)
+	}
+	br := q.db.SendBatch(ctx, batch)
+	return &UpsertEmbeddingBatchBatchResults{br, len(arg), false}
+}
+
+func (b *UpsertEmbeddingBatchBatchResults) Exec(f func(int, error)) {
+	defer b.br.Close()
+	for t := 0; t < b.tot; t++ {
+		if b.closed {
+			if f != nil {
+				f(t, ErrBatchAlreadyClosed)
+			}
+			continue
+		}
+		_, err := b.br.Exec()
+		if f != nil {
+			f(t, err)
+		}
+	}
+}
+
+func (b *UpsertEmbeddingBatchBatchResults) Close() error {
+	b.closed = true
+	return b.br.Close()
+}
@@ -88,7 +88,7 @@ func (q *Queries) GetChildChunkIDs(ctx context.Context, parentChunkID pgtype.UUI
 }
 
 const getChildChunks = `-- name: GetChildChunks :many
-SELECT c.id, c.file_id, c.ordinal, c.start_line, c.end_line, c.content, c.content_hash, c.token_count, c.chunk_type, c.chunk_name, c.parent_name, c.signature, c.doc_comment, c.imports, c.calls, c.lines_of_code, c.comment_ratio, c.cyclomatic_complexity, c.embedding_context, c.level, c.importance_score, c.standard_imports, c.external_imports, c.internal_calls, c.external_calls, c.type_dependencies, c.source_snapshot_id, c.git_commit_hash, c.author, c.updated_at, c.indexed_at, c.file_version, c.is_latest, c.chunk_key, c.created_at
+SELECT c.id, c.file_id, c.ordinal, c.start_line, c.end_line, c.content, c.content_hash, c.token_count, c.chunk_type, c.chunk_name, c.parent_name, c.signature, c.doc_comment, c.imports, c.calls, c.lines_of_code, c.comment_ratio, c.cyclomatic_complexity, c.embedding_context, c.embedding_truncated, c.breadcrumb, c.page_start, c.page_end, c.level, c.importance_score, c.standard_imports, c.external_imports, c.internal_calls, c.external_calls, c.type_dependencies, c.source_snapshot_id, c.git_commit_hash, c.author, c.updated_at, c.indexed_at, c.file_version, c.is_latest, c.chunk_key, c.chunker_version, c.created_at
 FROM chunks c
 INNER JOIN chunk_hierarchy ch ON c.id = ch.child_chunk_id
 WHERE ch.parent_chunk_id = $1
@@ -124,6 +124,10 @@ func (q *Queries) GetChildChunks(ctx context.Context, parentChunkID pgtype.UUID)
 			&i.CommentRatio,
 			&i.CyclomaticComplexity,
 			&i.EmbeddingContext,
+			&i.EmbeddingTruncated,
+			&i.Breadcrumb,
+			&i.PageStart,
+			&i.PageEnd,
 			&i.Level,
 			&i.ImportanceScore,
 			&i.StandardImports,
@@ -139,6 +143,7 @@ func (q *Queries) GetChildChunks(ctx context.Context, parentChunkID pgtype.UUID)
 			&i.FileVersion,
 			&i.IsLatest,
 			&i.ChunkKey,
+			&i.ChunkerVersion,
 			&i.CreatedAt,
 		); err != nil {
 			return nil, err
@@ -152,7 +157,7 @@ func (q *Queries) GetChildChunks(ctx context.Context, parentChunkID pgtype.UUID)
 }
 
 const getParentChunk = `-- name: GetParentChunk :one
-SELECT c.id, c.file_id, c.ordinal, c.start_line, c.end_line, c.content, c.content_hash, c.token_count, c.chunk_type, c.chunk_name, c.parent_name, c.signature, c.doc_comment, c.imports, c.calls, c.lines_of_code, c.comment_ratio, c.cyclomatic_complexity, c.embedding_context, c.level, c.importance_score, c.standard_imports, c.external_imports, c.internal_calls, c.external_calls, c.type_dependencies, c.source_snapshot_id, c.git_commit_hash, c.author, c.updated_at, c.indexed_at, c.file_version, c.is_latest, c.chunk_key, c.created_at
+SELECT c.id, c.file_id, c.ordinal, c.start_line, c.end_line, c.content, c.content_hash, c.token_count, c.chunk_type, c.chunk_name, c.parent_name, c.signature, c.doc_comment, c.imports, c.calls, c.lines_of_code, c.comment_ratio, c.cyclomatic_complexity, c.embedding_context, c.embedding_truncated, c.breadcrumb, c.page_start, c.page_end, c.level, c.importance_score, c.standard_imports, c.external_imports, c.internal_calls, c.external_calls, c.type_dependencies, c.source_snapshot_id, c.git_commit_hash, c.author, c.updated_at, c.indexed_at, c.file_version, c.is_latest, c.chunk_key, c.chunker_version, c.created_at
 FROM chunks c
 INNER JOIN chunk_hierarchy ch ON c.id = ch.parent_chunk_id
 WHERE ch.child_chunk_id = $1
@@ -182,6 +187,10 @@ func (q *Queries) GetParentChunk(ctx context.Context, childChunkID pgtype.UUID)
 		&i.CommentRatio,
 		&i.CyclomaticComplexity,
 		&i.EmbeddingContext,
+		&i.EmbeddingTruncated,
+		&i.Breadcrumb,
+		&i.PageStart,
+		&i.PageEnd,
 		&i.Level,
 		&i.ImportanceScore,
 		&i.StandardImports,
@@ -197,6 +206,7 @@ func (q *Queries) GetParentChunk(ctx context.Context, childChunkID pgtype.UUID)
 		&i.FileVersion,
 		&i.IsLatest,
 		&i.ChunkKey,
+		&i.ChunkerVersion,
 		&i.CreatedAt,
 	)
 	return i, err
@@ -246,6 +256,80 @@ func (q *Queries) HasParent(ctx context.Context, childChunkID pgtype.UUID) (bool
 	return has_parent, err
 }
 
+const listChunkHierarchyContexts = `-- name: ListChunkHierarchyContexts :many
+SELECT
+    c.id AS chunk_id,
+    c.file_id,
+    c.level,
+    p.id AS parent_id,
+    COALESCE(p.start_line, 0)::int AS parent_start_line,
+    COALESCE(p.end_line, 0)::int AS parent_end_line,
+    COALESCE(p.content, '')::text AS parent_content,
+    s.content AS file_summary
+FROM chunks c
+JOIN files f ON f.id = c.file_id
+LEFT JOIN LATERAL (
+    SELECT pc.id, pc.start_line, pc.end_line, pc.content
+    FROM chunks pc
+    WHERE pc.file_id = c.file_id
+      AND pc.id <> c.id
+      AND pc.level < c.level
+      AND (
+          EXISTS (
+              SELECT 1 FROM chunk_hierarchy ch
+              WHERE ch.parent_chunk_id = pc.id AND ch.child_chunk_id = c.id
+          )
+          OR (pc.chunk_name = c.parent_name AND pc.start_line <= c.start_line AND pc.end_line >= c.end_line)
+      )
+    ORDER BY pc.level DESC
+    LIMIT 1
+) p ON c.level >= 3
+LEFT JOIN summaries s ON s.snapshot_id = f.snapshot_id AND s.summary_type = 'file' AND s.target_path = f.path
+WHERE c.id = ANY($1::uuid[])
+`
+
+type ListChunkHierarchyContextsRow struct {
+	ChunkID         pgtype.UUID `json:"chunk_id"`
+	FileID          pgtype.UUID `json:"file_id"`
+	Level           int32       `json:"level"`
+	ParentID        pgtype.UUID `json:"parent_id"`
+	ParentStartLine int32       `json:"parent_start_line"`
+	ParentEndLine   int32       `json:"parent_end_line"`
+	ParentContent   string      `json:"parent_content"`
+	FileSummary     pgtype.Text `json:"file_summary"`
+}
+
+// プロンプトの階層的な組み立て用に、指定チャンクの所属ファイル・親チャンク・ファイル要約を取得する
+// 親チャンクは chunk_hierarchy を優先し、未登録の場合は同一ファイル内で parent_name と行範囲が一致する上位レベルのチャンクとする
+func (q *Queries) ListChunkHierarchyContexts(ctx context.Context, chunkIds []pgtype.UUID) ([]ListChunkHierarchyContextsRow, error) {
+	rows, err := q.db.Query(ctx, listChunkHierarchyContexts, chunkIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListChunkHierarchyContextsRow{}
+	for rows.Next() {
+		var i ListChunkHierarchyContextsRow
+		if err := rows.Scan(
+			&i.ChunkID,
+			&i.FileID,
+			&i.Level,
+			&i.ParentID,
+			&i.ParentStartLine,
+			&i.ParentEndLine,
+			&i.ParentContent,
+			&i.FileSummary,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const removeChunkRelation = `-- name: RemoveChunkRelation :exec
 DELETE FROM chunk_hierarchy
 WHERE parent_chunk_id = $1 AND child_chunk_id = $2
@@ -11,6 +11,26 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const batchUpdateChunkImportanceScores = `-- name: BatchUpdateChunkImportanceScores :exec
+UPDATE chunks c
+SET importance_score = v.score
+FROM (
+    SELECT unnest($1::uuid[]) AS id, unnest($2::float8[]) AS score
+) v
+WHERE c.id = v.id
+`
+
+type BatchUpdateChunkImportanceScoresParams struct {
+	Ids    []pgtype.UUID `json:"ids"`
+	Scores []float64     `json:"scores"`
+}
+
+// チャンクの重要度スコアを1回のクエリでまとめて更新する（ID とスコアは同じ順序・同じ長さの配列で渡す）
+func (q *Queries) BatchUpdateChunkImportanceScores(ctx context.Context, arg BatchUpdateChunkImportanceScoresParams) error {
+	_, err := q.db.Exec(ctx, batchUpdateChunkImportanceScores, arg.Ids, arg.Scores)
+	return err
+}
+
 const countStaleChunks = `-- name: CountStaleChunks :one
 SELECT COUNT(*) as stale_count
 FROM chunks c
@@ -35,10 +55,10 @@ INSERT INTO chunks (
     level, importance_score,
     standard_imports, external_imports, internal_calls, external_calls, type_dependencies,
     source_snapshot_id, git_commit_hash, author, updated_at, indexed_at,
-    file_version, is_latest, chunk_key
+    file_version, is_latest, chunk_key, embedding_truncated, breadcrumb, page_start, page_end, chunker_version
 )
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31, $32, $33)
-RETURNING id, file_id, ordinal, start_line, end_line, content, content_hash, token_count, chunk_type, chunk_name, parent_name, signature, doc_comment, imports, calls, lines_of_code, comment_ratio, cyclomatic_complexity, embedding_context, level, importance_score, standard_imports, external_imports, internal_calls, external_calls, type_dependencies, source_snapshot_id, git_commit_hash, author, updated_at, indexed_at, file_version, is_latest, chunk_key, created_at
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31, $32, $33, $34, $35, $36, $37, $38)
+RETURNING id, file_id, ordinal, start_line, end_line, content, content_hash, token_count, chunk_type, chunk_name, parent_name, signature, doc_comment, imports, calls, lines_of_code, comment_ratio, cyclomatic_complexity, embedding_context, embedding_truncated, breadcrumb, page_start, page_end, level, importance_score, standard_imports, external_imports, internal_calls, external_calls, type_dependencies, source_snapshot_id, git_commit_hash, author, updated_at, indexed_at, file_version, is_latest, chunk_key, chunker_version, created_at
 `
 
 type CreateChunkParams struct {
@@ -75,6 +95,11 @@ type CreateChunkParams struct {
 	FileVersion          pgtype.Text      `json:"file_version"`
 	IsLatest             bool             `json:"is_latest"`
 	ChunkKey             string           `json:"chunk_key"`
+	EmbeddingTruncated   bool             `json:"embedding_truncated"`
+	Breadcrumb           pgtype.Text      `json:"breadcrumb"`
+	PageStart            pgtype.Int4      `json:"page_start"`
+	PageEnd              pgtype.Int4      `json:"page_end"`
+	ChunkerVersion       pgtype.Text      `json:"chunker_version"`
 }
 
 func (q *Queries) CreateChunk(ctx context.Context, arg CreateChunkParams) (Chunk, error) {
@@ -112,6 +137,11 @@ func (q *Queries) CreateChunk(ctx context.Context, arg CreateChunkParams) (Chunk
 		arg.FileVersion,
 		arg.IsLatest,
 		arg.ChunkKey,
+		arg.EmbeddingTruncated,
+		arg.Breadcrumb,
+		arg.PageStart,
+		arg.PageEnd,
+		arg.ChunkerVersion,
 	)
 	var i Chunk
 	err := row.Scan(
@@ -134,6 +164,10 @@ func (q *Queries) CreateChunk(ctx context.Context, arg CreateChunkParams) (Chunk
 		&i.CommentRatio,
 		&i.CyclomaticComplexity,
 		&i.EmbeddingContext,
+		&i.EmbeddingTruncated,
+		&i.Breadcrumb,
+		&i.PageStart,
+		&i.PageEnd,
 		&i.Level,
 		&i.ImportanceScore,
 		&i.StandardImports,
@@ -149,6 +183,7 @@ func (q *Queries) CreateChunk(ctx context.Context, arg CreateChunkParams) (Chunk
 		&i.FileVersion,
 		&i.IsLatest,
 		&i.ChunkKey,
+		&i.ChunkerVersion,
 		&i.CreatedAt,
 	)
 	return i, err
@@ -175,7 +210,7 @@ func (q *Queries) DeleteChunksByFile(ctx context.Context, fileID pgtype.UUID) er
 }
 
 const findChunksByContentHash = `-- name: FindChunksByContentHash :many
-SELECT id, file_id, ordinal, start_line, end_line, content, content_hash, token_count, chunk_type, chunk_name, parent_name, signature, doc_comment, imports, calls, lines_of_code, comment_ratio, cyclomatic_complexity, embedding_context, level, importance_score, standard_imports, external_imports, internal_calls, external_calls, type_dependencies, source_snapshot_id, git_commit_hash, author, updated_at, indexed_at, file_version, is_latest, chunk_key, created_at FROM chunks
+SELECT id, file_id, ordinal, start_line, end_line, content, content_hash, token_count, chunk_type, chunk_name, parent_name, signature, doc_comment, imports, calls, lines_of_code, comment_ratio, cyclomatic_complexity, embedding_context, embedding_truncated, breadcrumb, page_start, page_end, level, importance_score, standard_imports, external_imports, internal_calls, external_calls, type_dependencies, source_snapshot_id, git_commit_hash, author, updated_at, indexed_at, file_version, is_latest, chunk_key, chunker_version, created_at FROM chunks
 WHERE content_hash = $1
 ORDER BY created_at DESC
 `
@@ -209,6 +244,10 @@ func (q *Queries) FindChunksByContentHash(ctx context.Context, contentHash strin
 			&i.CommentRatio,
 			&i.CyclomaticComplexity,
 			&i.EmbeddingContext,
+			&i.EmbeddingTruncated,
+			&i.Breadcrumb,
+			&i.PageStart,
+			&i.PageEnd,
 			&i.Level,
 			&i.ImportanceScore,
 			&i.StandardImports,
@@ -224,6 +263,7 @@ func (q *Queries) FindChunksByContentHash(ctx context.Context, contentHash strin
 			&i.FileVersion,
 			&i.IsLatest,
 			&i.ChunkKey,
+			&i.ChunkerVersion,
 			&i.CreatedAt,
 		); err != nil {
 			return nil, err
@@ -237,7 +277,7 @@ func (q *Queries) FindChunksByContentHash(ctx context.Context, contentHash strin
 }
 
 const getChunk = `-- name: GetChunk :one
-SELECT id, file_id, ordinal, start_line, end_line, content, content_hash, token_count, chunk_type, chunk_name, parent_name, signature, doc_comment, imports, calls, lines_of_code, comment_ratio, cyclomatic_complexity, embedding_context, level, importance_score, standard_imports, external_imports, internal_calls, external_calls, type_dependencies, source_snapshot_id, git_commit_hash, author, updated_at, indexed_at, file_version, is_latest, chunk_key, created_at FROM chunks
+SELECT id, file_id, ordinal, start_line, end_line, content, content_hash, token_count, chunk_type, chunk_name, parent_name, signature, doc_comment, imports, calls, lines_of_code, comment_ratio, cyclomatic_complexity, embedding_context, embedding_truncated, breadcrumb, page_start, page_end, level, importance_score, standard_imports, external_imports, internal_calls, external_calls, type_dependencies, source_snapshot_id, git_commit_hash, author, updated_at, indexed_at, file_version, is_latest, chunk_key, chunker_version, created_at FROM chunks
 WHERE id = $1
 `
 
@@ -264,6 +304,63 @@ func (q *Queries) GetChunk(ctx context.Context, id pgtype.UUID) (Chunk, error) {
 		&i.CommentRatio,
 		&i.CyclomaticComplexity,
 		&i.EmbeddingContext,
+		&i.EmbeddingTruncated,
+		&i.Breadcrumb,
+		&i.PageStart,
+		&i.PageEnd,
+		&i.Level,
+		&i.ImportanceScore,
+		&i.StandardImports,
+		&i.ExternalImports,
+		&i.InternalCalls,
+		&i.ExternalCalls,
+		&i.TypeDependencies,
+		&i.SourceSnapshotID,
+		&i.GitCommitHash,
+		&i.Author,
+		&i.UpdatedAt,
+		&i.IndexedAt,
+		&i.FileVersion,
+		&i.IsLatest,
+		&i.ChunkKey,
+		&i.ChunkerVersion,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getChunkByKey = `-- name: GetChunkByKey :one
+SELECT id, file_id, ordinal, start_line, end_line, content, content_hash, token_count, chunk_type, chunk_name, parent_name, signature, doc_comment, imports, calls, lines_of_code, comment_ratio, cyclomatic_complexity, embedding_context, embedding_truncated, breadcrumb, page_start, page_end, level, importance_score, standard_imports, external_imports, internal_calls, external_calls, type_dependencies, source_snapshot_id, git_commit_hash, author, updated_at, indexed_at, file_version, is_latest, chunk_key, chunker_version, created_at FROM chunks
+WHERE chunk_key = $1
+`
+
+func (q *Queries) GetChunkByKey(ctx context.Context, chunkKey string) (Chunk, error) {
+	row := q.db.QueryRow(ctx, getChunkByKey, chunkKey)
+	var i Chunk
+	err := row.Scan(
+		&i.ID,
+		&i.FileID,
+		&i.Ordinal,
+		&i.StartLine,
+		&i.EndLine,
+		&i.Content,
+		&i.ContentHash,
+		&i.TokenCount,
+		&i.ChunkType,
+		&i.ChunkName,
+		&i.ParentName,
+		&i.Signature,
+		&i.DocComment,
+		&i.Imports,
+		&i.Calls,
+		&i.LinesOfCode,
+		&i.CommentRatio,
+		&i.CyclomaticComplexity,
+		&i.EmbeddingContext,
+		&i.EmbeddingTruncated,
+		&i.Breadcrumb,
+		&i.PageStart,
+		&i.PageEnd,
 		&i.Level,
 		&i.ImportanceScore,
 		&i.StandardImports,
@@ -279,11 +376,52 @@ func (q *Queries) GetChunk(ctx context.Context, id pgtype.UUID) (Chunk, error) {
 		&i.FileVersion,
 		&i.IsLatest,
 		&i.ChunkKey,
+		&i.ChunkerVersion,
 		&i.CreatedAt,
 	)
 	return i, err
 }
 
+const getChunkLineageAnchor = `-- name: GetChunkLineageAnchor :one
+
+SELECT
+    c.id,
+    c.ordinal,
+    c.chunk_name,
+    c.parent_name,
+    f.path AS file_path,
+    ss.source_id
+FROM chunks c
+INNER JOIN files f ON c.file_id = f.id
+INNER JOIN source_snapshots ss ON f.snapshot_id = ss.id
+WHERE c.chunk_key = $1
+`
+
+type GetChunkLineageAnchorRow struct {
+	ID         pgtype.UUID `json:"id"`
+	Ordinal    int32       `json:"ordinal"`
+	ChunkName  pgtype.Text `json:"chunk_name"`
+	ParentName pgtype.Text `json:"parent_name"`
+	FilePath   string      `json:"file_path"`
+	SourceID   pgtype.UUID `json:"source_id"`
+}
+
+// チャンクの系譜（バージョン履歴）
+// chunk_key から系譜をたどる起点となるチャンクの識別情報を取得
+func (q *Queries) GetChunkLineageAnchor(ctx context.Context, chunkKey string) (GetChunkLineageAnchorRow, error) {
+	row := q.db.QueryRow(ctx, getChunkLineageAnchor, chunkKey)
+	var i GetChunkLineageAnchorRow
+	err := row.Scan(
+		&i.ID,
+		&i.Ordinal,
+		&i.ChunkName,
+		&i.ParentName,
+		&i.FilePath,
+		&i.SourceID,
+	)
+	return i, err
+}
+
 const getChunksWithGitInfo = `-- name: GetChunksWithGitInfo :many
 
 SELECT
@@ -397,8 +535,262 @@ func (q *Queries) GetStaleChunks(ctx context.Context, dollar_1 interface{}) ([]G
 	return items, nil
 }
 
+const listAPISymbols = `-- name: ListAPISymbols :many
+WITH scope_snapshots AS (
+    SELECT DISTINCT ON (ss.source_id) ss.id
+    FROM source_snapshots ss
+    INNER JOIN sources s ON ss.source_id = s.id
+    WHERE ss.status = 'indexed'
+      AND ($3::uuid IS NULL OR s.product_id = $3::uuid)
+      AND ($4::uuid IS NULL OR ss.id = $4::uuid)
+    ORDER BY ss.source_id, ss.indexed_at DESC NULLS LAST, ss.created_at DESC
+)
+SELECT
+    f.path,
+    f.language,
+    c.chunk_type,
+    c.chunk_name::text AS chunk_name,
+    c.parent_name,
+    c.signature,
+    c.doc_comment,
+    left(c.content, 1000)::text AS content_head,
+    c.start_line,
+    c.end_line
+FROM chunks c
+INNER JOIN files f ON f.id = c.file_id
+INNER JOIN scope_snapshots sc ON f.snapshot_id = sc.id
+WHERE c.level = 2
+  AND c.chunk_name IS NOT NULL
+  AND (cardinality($1::text[]) = 0 OR f.domain = ANY($1::text[]))
+ORDER BY f.path, c.start_line
+LIMIT $2
+`
+
+type ListAPISymbolsParams struct {
+	Domains    []string    `json:"domains"`
+	RowLimit   int32       `json:"row_limit"`
+	ProductID  pgtype.UUID `json:"product_id"`
+	SnapshotID pgtype.UUID `json:"snapshot_id"`
+}
+
+type ListAPISymbolsRow struct {
+	Path        string      `json:"path"`
+	Language    pgtype.Text `json:"language"`
+	ChunkType   pgtype.Text `json:"chunk_type"`
+	ChunkName   string      `json:"chunk_name"`
+	ParentName  pgtype.Text `json:"parent_name"`
+	Signature   pgtype.Text `json:"signature"`
+	DocComment  pgtype.Text `json:"doc_comment"`
+	ContentHead string      `json:"content_head"`
+	StartLine   int32       `json:"start_line"`
+	EndLine     int32       `json:"end_line"`
+}
+
+// APIリファレンス生成用に関数・型などのチャンク（レベル2）のシグネチャとドキュメントコメントを取得する
+// product_id 指定時はプロダクトの各ソースの最新スナップショット、snapshot_id 指定時はそのスナップショットを対象とする
+// シグネチャを持たない宣言（型など）のため、内容の先頭部分も返す
+func (q *Queries) ListAPISymbols(ctx context.Context, arg ListAPISymbolsParams) ([]ListAPISymbolsRow, error) {
+	rows, err := q.db.Query(ctx, listAPISymbols,
+		arg.Domains,
+		arg.RowLimit,
+		arg.ProductID,
+		arg.SnapshotID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListAPISymbolsRow{}
+	for rows.Next() {
+		var i ListAPISymbolsRow
+		if err := rows.Scan(
+			&i.Path,
+			&i.Language,
+			&i.ChunkType,
+			&i.ChunkName,
+			&i.ParentName,
+			&i.Signature,
+			&i.DocComment,
+			&i.ContentHead,
+			&i.StartLine,
+			&i.EndLine,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBuildFileChunks = `-- name: ListBuildFileChunks :many
+WITH scope_snapshots AS (
+    SELECT DISTINCT ON (ss.source_id) ss.id, s.name AS source_name
+    FROM source_snapshots ss
+    INNER JOIN sources s ON ss.source_id = s.id
+    WHERE ss.status = 'indexed'
+      AND ($1::uuid IS NULL OR s.product_id = $1::uuid)
+      AND ($2::uuid IS NULL OR ss.id = $2::uuid)
+    ORDER BY ss.source_id, ss.indexed_at DESC NULLS LAST, ss.created_at DESC
+)
+SELECT
+    sc.source_name::text AS source_name,
+    f.path,
+    c.start_line,
+    c.content
+FROM chunks c
+INNER JOIN files f ON f.id = c.file_id
+INNER JOIN scope_snapshots sc ON f.snapshot_id = sc.id
+WHERE c.level <> 1
+  AND (
+      f.path ~ '(^|/)(Makefile|GNUmakefile|makefile)$'
+      OR f.path ~ '\.mk$'
+      OR f.path ~ '^\.github/workflows/[^/]+\.ya?ml$'
+      OR f.path = '.gitlab-ci.yml'
+  )
+ORDER BY sc.source_name, f.path, c.start_line, c.ordinal
+`
+
+type ListBuildFileChunksParams struct {
+	ProductID  pgtype.UUID `json:"product_id"`
+	SnapshotID pgtype.UUID `json:"snapshot_id"`
+}
+
+type ListBuildFileChunksRow struct {
+	SourceName string `json:"source_name"`
+	Path       string `json:"path"`
+	StartLine  int32  `json:"start_line"`
+	Content    string `json:"content"`
+}
+
+// オンボーディングガイド用に、ビルド・テストのコマンドを検出する Makefile とCI設定ファイルのチャンクを取得する
+// 対象スナップショットの決め方は ListAPISymbols と同じ
+func (q *Queries) ListBuildFileChunks(ctx context.Context, arg ListBuildFileChunksParams) ([]ListBuildFileChunksRow, error) {
+	rows, err := q.db.Query(ctx, listBuildFileChunks, arg.ProductID, arg.SnapshotID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListBuildFileChunksRow{}
+	for rows.Next() {
+		var i ListBuildFileChunksRow
+		if err := rows.Scan(
+			&i.SourceName,
+			&i.Path,
+			&i.StartLine,
+			&i.Content,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listChunkHistory = `-- name: ListChunkHistory :many
+SELECT
+    c.id,
+    c.chunk_key,
+    c.start_line,
+    c.end_line,
+    c.content,
+    c.content_hash,
+    c.git_commit_hash,
+    c.author,
+    c.updated_at,
+    c.indexed_at,
+    c.is_latest,
+    ss.id AS snapshot_id,
+    ss.version_identifier,
+    ss.created_at AS snapshot_created_at
+FROM chunks c
+INNER JOIN files f ON c.file_id = f.id
+INNER JOIN source_snapshots ss ON f.snapshot_id = ss.id
+WHERE ss.source_id = $1
+  AND f.path = $2
+  AND (
+      ($3::text IS NOT NULL
+          AND c.chunk_name = $3::text
+          AND c.parent_name IS NOT DISTINCT FROM $4::text)
+      OR ($3::text IS NULL AND c.ordinal = $5)
+  )
+ORDER BY ss.created_at, c.indexed_at
+`
+
+type ListChunkHistoryParams struct {
+	SourceID   pgtype.UUID `json:"source_id"`
+	FilePath   string      `json:"file_path"`
+	ChunkName  pgtype.Text `json:"chunk_name"`
+	ParentName pgtype.Text `json:"parent_name"`
+	Ordinal    int32       `json:"ordinal"`
+}
+
+type ListChunkHistoryRow struct {
+	ID                pgtype.UUID      `json:"id"`
+	ChunkKey          string           `json:"chunk_key"`
+	StartLine         int32            `json:"start_line"`
+	EndLine           int32            `json:"end_line"`
+	Content           string           `json:"content"`
+	ContentHash       string           `json:"content_hash"`
+	GitCommitHash     pgtype.Text      `json:"git_commit_hash"`
+	Author            pgtype.Text      `json:"author"`
+	UpdatedAt         pgtype.Timestamp `json:"updated_at"`
+	IndexedAt         pgtype.Timestamp `json:"indexed_at"`
+	IsLatest          bool             `json:"is_latest"`
+	SnapshotID        pgtype.UUID      `json:"snapshot_id"`
+	VersionIdentifier string           `json:"version_identifier"`
+	SnapshotCreatedAt pgtype.Timestamp `json:"snapshot_created_at"`
+}
+
+// 同一ソース・同一ファイル内で同じ関数/クラス名（名前がない場合は同じ序数）を持つチャンクをスナップショット順に取得
+func (q *Queries) ListChunkHistory(ctx context.Context, arg ListChunkHistoryParams) ([]ListChunkHistoryRow, error) {
+	rows, err := q.db.Query(ctx, listChunkHistory,
+		arg.SourceID,
+		arg.FilePath,
+		arg.ChunkName,
+		arg.ParentName,
+		arg.Ordinal,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListChunkHistoryRow{}
+	for rows.Next() {
+		var i ListChunkHistoryRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ChunkKey,
+			&i.StartLine,
+			&i.EndLine,
+			&i.Content,
+			&i.ContentHash,
+			&i.GitCommitHash,
+			&i.Author,
+			&i.UpdatedAt,
+			&i.IndexedAt,
+			&i.IsLatest,
+			&i.SnapshotID,
+			&i.VersionIdentifier,
+			&i.SnapshotCreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listChunksByFile = `-- name: ListChunksByFile :many
-SELECT id, file_id, ordinal, start_line, end_line, content, content_hash, token_count, chunk_type, chunk_name, parent_name, signature, doc_comment, imports, calls, lines_of_code, comment_ratio, cyclomatic_complexity, embedding_context, level, importance_score, standard_imports, external_imports, internal_calls, external_calls, type_dependencies, source_snapshot_id, git_commit_hash, author, updated_at, indexed_at, file_version, is_latest, chunk_key, created_at FROM chunks
+SELECT id, file_id, ordinal, start_line, end_line, content, content_hash, token_count, chunk_type, chunk_name, parent_name, signature, doc_comment, imports, calls, lines_of_code, comment_ratio, cyclomatic_complexity, embedding_context, embedding_truncated, breadcrumb, page_start, page_end, level, importance_score, standard_imports, external_imports, internal_calls, external_calls, type_dependencies, source_snapshot_id, git_commit_hash, author, updated_at, indexed_at, file_version, is_latest, chunk_key, chunker_version, created_at FROM chunks
 WHERE file_id = $1
 ORDER BY ordinal
 `
@@ -432,6 +824,10 @@ func (q *Queries) ListChunksByFile(ctx context.Context, fileID pgtype.UUID) ([]C
 			&i.CommentRatio,
 			&i.CyclomaticComplexity,
 			&i.EmbeddingContext,
+			&i.EmbeddingTruncated,
+			&i.Breadcrumb,
+			&i.PageStart,
+			&i.PageEnd,
 			&i.Level,
 			&i.ImportanceScore,
 			&i.StandardImports,
@@ -447,6 +843,7 @@ func (q *Queries) ListChunksByFile(ctx context.Context, fileID pgtype.UUID) ([]C
 			&i.FileVersion,
 			&i.IsLatest,
 			&i.ChunkKey,
+			&i.ChunkerVersion,
 			&i.CreatedAt,
 		); err != nil {
 			return nil, err
@@ -460,7 +857,7 @@ func (q *Queries) ListChunksByFile(ctx context.Context, fileID pgtype.UUID) ([]C
 }
 
 const listChunksByOrdinalRange = `-- name: ListChunksByOrdinalRange :many
-SELECT id, file_id, ordinal, start_line, end_line, content, content_hash, token_count, chunk_type, chunk_name, parent_name, signature, doc_comment, imports, calls, lines_of_code, comment_ratio, cyclomatic_complexity, embedding_context, level, importance_score, standard_imports, external_imports, internal_calls, external_calls, type_dependencies, source_snapshot_id, git_commit_hash, author, updated_at, indexed_at, file_version, is_latest, chunk_key, created_at FROM chunks
+SELECT id, file_id, ordinal, start_line, end_line, content, content_hash, token_count, chunk_type, chunk_name, parent_name, signature, doc_comment, imports, calls, lines_of_code, comment_ratio, cyclomatic_complexity, embedding_context, embedding_truncated, breadcrumb, page_start, page_end, level, importance_score, standard_imports, external_imports, internal_calls, external_calls, type_dependencies, source_snapshot_id, git_commit_hash, author, updated_at, indexed_at, file_version, is_latest, chunk_key, chunker_version, created_at FROM chunks
 WHERE file_id = $1 AND ordinal BETWEEN $2 AND $3
 ORDER BY ordinal
 `
@@ -500,6 +897,10 @@ func (q *Queries) ListChunksByOrdinalRange(ctx context.Context, arg ListChunksBy
 			&i.CommentRatio,
 			&i.CyclomaticComplexity,
 			&i.EmbeddingContext,
+			&i.EmbeddingTruncated,
+			&i.Breadcrumb,
+			&i.PageStart,
+			&i.PageEnd,
 			&i.Level,
 			&i.ImportanceScore,
 			&i.StandardImports,
@@ -515,6 +916,7 @@ func (q *Queries) ListChunksByOrdinalRange(ctx context.Context, arg ListChunksBy
 			&i.FileVersion,
 			&i.IsLatest,
 			&i.ChunkKey,
+			&i.ChunkerVersion,
 			&i.CreatedAt,
 		); err != nil {
 			return nil, err
@@ -527,6 +929,140 @@ func (q *Queries) ListChunksByOrdinalRange(ctx context.Context, arg ListChunksBy
 	return items, nil
 }
 
+const listFileIDsWithOutdatedChunker = `-- name: ListFileIDsWithOutdatedChunker :many
+SELECT DISTINCT c.file_id
+FROM chunks c
+INNER JOIN files f ON c.file_id = f.id
+WHERE f.snapshot_id = $1
+  AND (c.chunker_version IS NULL OR NOT (c.chunker_version = ANY($2::text[])))
+`
+
+type ListFileIDsWithOutdatedChunkerParams struct {
+	SnapshotID      pgtype.UUID `json:"snapshot_id"`
+	CurrentVersions []string    `json:"current_versions"`
+}
+
+// スナップショットのファイルのうち、現在のチャンカー以外（バージョンの記録がないものを含む）で作成したチャンクがあるファイルを取得する
+func (q *Queries) ListFileIDsWithOutdatedChunker(ctx context.Context, arg ListFileIDsWithOutdatedChunkerParams) ([]pgtype.UUID, error) {
+	rows, err := q.db.Query(ctx, listFileIDsWithOutdatedChunker, arg.SnapshotID, arg.CurrentVersions)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []pgtype.UUID{}
+	for rows.Next() {
+		var file_id pgtype.UUID
+		if err := rows.Scan(&file_id); err != nil {
+			return nil, err
+		}
+		items = append(items, file_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOpsFileChunks = `-- name: ListOpsFileChunks :many
+WITH scope_snapshots AS (
+    SELECT DISTINCT ON (ss.source_id) ss.id, s.name AS source_name
+    FROM source_snapshots ss
+    INNER JOIN sources s ON ss.source_id = s.id
+    WHERE ss.status = 'indexed'
+      AND ($1::uuid IS NULL OR s.product_id = $1::uuid)
+      AND ($2::uuid IS NULL OR ss.id = $2::uuid)
+    ORDER BY ss.source_id, ss.indexed_at DESC NULLS LAST, ss.created_at DESC
+)
+SELECT
+    sc.source_name::text AS source_name,
+    f.path,
+    c.start_line,
+    c.content
+FROM chunks c
+INNER JOIN files f ON f.id = c.file_id
+INNER JOIN scope_snapshots sc ON f.snapshot_id = sc.id
+WHERE c.level <> 1
+  AND (
+      f.path ~ '(^|/)(Makefile|GNUmakefile|makefile)$'
+      OR f.path ~ '\.mk$'
+      OR f.path ~ '^\.github/workflows/[^/]+\.ya?ml$'
+      OR f.path = '.gitlab-ci.yml'
+      OR f.path ~ '(^|/)scripts?/[^/]+$'
+      OR f.path ~ '\.(sh|bash)$'
+  )
+ORDER BY sc.source_name, f.path, c.start_line, c.ordinal
+`
+
+type ListOpsFileChunksParams struct {
+	ProductID  pgtype.UUID `json:"product_id"`
+	SnapshotID pgtype.UUID `json:"snapshot_id"`
+}
+
+type ListOpsFileChunksRow struct {
+	SourceName string `json:"source_name"`
+	Path       string `json:"path"`
+	StartLine  int32  `json:"start_line"`
+	Content    string `json:"content"`
+}
+
+// 運用コマンドのページ用に、Makefile・CI設定ファイルと scripts/ 配下・シェルスクリプトのチャンクを取得する
+// 対象スナップショットの決め方は ListAPISymbols と同じ
+func (q *Queries) ListOpsFileChunks(ctx context.Context, arg ListOpsFileChunksParams) ([]ListOpsFileChunksRow, error) {
+	rows, err := q.db.Query(ctx, listOpsFileChunks, arg.ProductID, arg.SnapshotID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListOpsFileChunksRow{}
+	for rows.Next() {
+		var i ListOpsFileChunksRow
+		if err := rows.Scan(
+			&i.SourceName,
+			&i.Path,
+			&i.StartLine,
+			&i.Content,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const refreshLatestChunks = `-- name: RefreshLatestChunks :exec
+WITH current_snapshots AS (
+    SELECT gr.snapshot_id AS id
+    FROM git_refs gr
+    WHERE gr.source_id = $1
+    UNION
+    (
+        SELECT s.id
+        FROM source_snapshots s
+        WHERE s.source_id = $1
+          AND s.status = 'indexed'
+        ORDER BY s.indexed_at DESC NULLS LAST, s.created_at DESC
+        LIMIT 1
+    )
+)
+UPDATE chunks c
+SET is_latest = (f.snapshot_id IN (SELECT id FROM current_snapshots))
+FROM files f
+INNER JOIN source_snapshots ss ON f.snapshot_id = ss.id
+WHERE c.file_id = f.id
+  AND ss.source_id = $1
+  AND c.is_latest <> (f.snapshot_id IN (SELECT id FROM current_snapshots))
+`
+
+// ソースのチャンクの is_latest を更新する
+// Git参照が指すスナップショットと最新のインデックス済みスナップショットのチャンクを最新とみなす
+func (q *Queries) RefreshLatestChunks(ctx context.Context, sourceID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, refreshLatestChunks, sourceID)
+	return err
+}
+
 const updateChunkImportanceScore = `-- name: UpdateChunkImportanceScore :exec
 UPDATE chunks
 SET importance_score = $2
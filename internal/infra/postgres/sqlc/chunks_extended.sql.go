@@ -6,7 +6,10 @@
 package sqlc
 
 import (
+	"context"
+
 	"github.com/jackc/pgx/v5/pgtype"
+	pgvector_go "github.com/pgvector/pgvector-go"
 )
 
 type CreateChunkBatchParams struct {
@@ -44,4 +47,32 @@ type CreateChunkBatchParams struct {
 	FileVersion          pgtype.Text      `json:"file_version"`
 	IsLatest             bool             `json:"is_latest"`
 	ChunkKey             string           `json:"chunk_key"`
+	EmbeddingTruncated   bool             `json:"embedding_truncated"`
+	Breadcrumb           pgtype.Text      `json:"breadcrumb"`
+	PageStart            pgtype.Int4      `json:"page_start"`
+	PageEnd              pgtype.Int4      `json:"page_end"`
+	ChunkerVersion       pgtype.Text      `json:"chunker_version"`
+}
+
+type CreateEmbeddingCopyParams struct {
+	ChunkID pgtype.UUID        `json:"chunk_id"`
+	Vector  pgvector_go.Vector `json:"vector"`
+	Model   string             `json:"model"`
+}
+
+const deleteStaleChunksByFile = `-- name: DeleteStaleChunksByFile :exec
+DELETE FROM chunks
+WHERE file_id = $1
+  AND NOT (id = ANY($2::uuid[]))
+`
+
+type DeleteStaleChunksByFileParams struct {
+	FileID  pgtype.UUID   `json:"file_id"`
+	KeepIds []pgtype.UUID `json:"keep_ids"`
+}
+
+// スナップショットの再実行で、ファイルのチャンクのうち今回作成しなかったもの（keep_ids 以外）を削除する
+func (q *Queries) DeleteStaleChunksByFile(ctx context.Context, arg DeleteStaleChunksByFileParams) error {
+	_, err := q.db.Exec(ctx, deleteStaleChunksByFile, arg.FileID, arg.KeepIds)
+	return err
 }
@@ -63,6 +63,11 @@ func (r iteratorForCreateChunkBatch) Values() ([]interface{}, error) {
 		r.rows[0].FileVersion,
 		r.rows[0].IsLatest,
 		r.rows[0].ChunkKey,
+		r.rows[0].EmbeddingTruncated,
+		r.rows[0].Breadcrumb,
+		r.rows[0].PageStart,
+		r.rows[0].PageEnd,
+		r.rows[0].ChunkerVersion,
 	}, nil
 }
 
@@ -71,5 +76,114 @@ func (r iteratorForCreateChunkBatch) Err() error {
 }
 
 func (q *Queries) CreateChunkBatch(ctx context.Context, arg []CreateChunkBatchParams) (int64, error) {
-	return q.db.CopyFrom(ctx, []string{"chunks"}, []string{"id", "file_id", "ordinal", "start_line", "end_line", "content", "content_hash", "token_count", "chunk_type", "chunk_name", "parent_name", "signature", "doc_comment", "imports", "calls", "lines_of_code", "comment_ratio", "cyclomatic_complexity", "embedding_context", "level", "importance_score", "standard_imports", "external_imports", "internal_calls", "external_calls", "type_dependencies", "source_snapshot_id", "git_commit_hash", "author", "updated_at", "indexed_at", "file_version", "is_latest", "chunk_key"}, &iteratorForCreateChunkBatch{rows: arg})
+	return q.db.CopyFrom(ctx, []string{"chunks"}, []string{"id", "file_id", "ordinal", "start_line", "end_line", "content", "content_hash", "token_count", "chunk_type", "chunk_name", "parent_name", "signature", "doc_comment", "imports", "calls", "lines_of_code", "comment_ratio", "cyclomatic_complexity", "embedding_context", "level", "importance_score", "standard_imports", "external_imports", "internal_calls", "external_calls", "type_dependencies", "source_snapshot_id", "git_commit_hash", "author", "updated_at", "indexed_at", "file_version", "is_latest", "chunk_key", "embedding_truncated", "breadcrumb", "page_start", "page_end", "chunker_version"}, &iteratorForCreateChunkBatch{rows: arg})
+}
+
+// iteratorForCreateEmbeddingCopy implements pgx.CopyFromSource.
+type iteratorForCreateEmbeddingCopy struct {
+	rows                 []CreateEmbeddingCopyParams
+	skippedFirstNextCall bool
+}
+
+func (r *iteratorForCreateEmbeddingCopy) Next() bool {
+	if len(r.rows) == 0 {
+		return false
+	}
+	if !r.skippedFirstNextCall {
+		r.skippedFirstNextCall = true
+		return true
+	}
+	r.rows = r.rows[1:]
+	return len(r.rows) > 0
+}
+
+func (r iteratorForCreateEmbeddingCopy) Values() ([]interface{}, error) {
+	return []interface{}{
+		r.rows[0].ChunkID,
+		r.rows[0].Vector,
+		r.rows[0].Model,
+	}, nil
+}
+
+func (r iteratorForCreateEmbeddingCopy) Err() error {
+	return nil
+}
+
+// インデックス化で生成した Embedding を COPY でまとめて保存する（vector 型をコネクションに登録する必要がある）
+func (q *Queries) CreateEmbeddingCopy(ctx context.Context, arg []CreateEmbeddingCopyParams) (int64, error) {
+	return q.db.CopyFrom(ctx, []string{"embeddings"}, []string{"chunk_id", "vector", "model"}, &iteratorForCreateEmbeddingCopy{rows: arg})
+}
+
+// iteratorForCreateLicenseFindings implements pgx.CopyFromSource.
+type iteratorForCreateLicenseFindings struct {
+	rows                 []CreateLicenseFindingsParams
+	skippedFirstNextCall bool
+}
+
+func (r *iteratorForCreateLicenseFindings) Next() bool {
+	if len(r.rows) == 0 {
+		return false
+	}
+	if !r.skippedFirstNextCall {
+		r.skippedFirstNextCall = true
+		return true
+	}
+	r.rows = r.rows[1:]
+	return len(r.rows) > 0
+}
+
+func (r iteratorForCreateLicenseFindings) Values() ([]interface{}, error) {
+	return []interface{}{
+		r.rows[0].SnapshotID,
+		r.rows[0].FilePath,
+		r.rows[0].Kind,
+		r.rows[0].LicenseID,
+		r.rows[0].Category,
+		r.rows[0].Evidence,
+	}, nil
+}
+
+func (r iteratorForCreateLicenseFindings) Err() error {
+	return nil
+}
+
+func (q *Queries) CreateLicenseFindings(ctx context.Context, arg []CreateLicenseFindingsParams) (int64, error) {
+	return q.db.CopyFrom(ctx, []string{"license_findings"}, []string{"snapshot_id", "file_path", "kind", "license_id", "category", "evidence"}, &iteratorForCreateLicenseFindings{rows: arg})
+}
+
+// iteratorForCreateMonorepoServices implements pgx.CopyFromSource.
+type iteratorForCreateMonorepoServices struct {
+	rows                 []CreateMonorepoServicesParams
+	skippedFirstNextCall bool
+}
+
+func (r *iteratorForCreateMonorepoServices) Next() bool {
+	if len(r.rows) == 0 {
+		return false
+	}
+	if !r.skippedFirstNextCall {
+		r.skippedFirstNextCall = true
+		return true
+	}
+	r.rows = r.rows[1:]
+	return len(r.rows) > 0
+}
+
+func (r iteratorForCreateMonorepoServices) Values() ([]interface{}, error) {
+	return []interface{}{
+		r.rows[0].SnapshotID,
+		r.rows[0].Path,
+		r.rows[0].Name,
+		r.rows[0].Kind,
+		r.rows[0].ManifestPath,
+		r.rows[0].Targets,
+	}, nil
+}
+
+func (r iteratorForCreateMonorepoServices) Err() error {
+	return nil
+}
+
+func (q *Queries) CreateMonorepoServices(ctx context.Context, arg []CreateMonorepoServicesParams) (int64, error) {
+	return q.db.CopyFrom(ctx, []string{"monorepo_services"}, []string{"snapshot_id", "path", "name", "kind", "manifest_path", "targets"}, &iteratorForCreateMonorepoServices{rows: arg})
 }
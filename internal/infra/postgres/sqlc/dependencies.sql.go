@@ -208,3 +208,379 @@ func (q *Queries) GetIncomingDependencyCount(ctx context.Context, toChunkID pgty
 	err := row.Scan(&count)
 	return count, err
 }
+
+const listDependencyGraphEdges = `-- name: ListDependencyGraphEdges :many
+WITH scope_snapshots AS (
+    SELECT DISTINCT ON (ss.source_id) ss.id, s.name AS source_name
+    FROM source_snapshots ss
+    INNER JOIN sources s ON ss.source_id = s.id
+    WHERE ss.status = 'indexed'
+      AND ($1::uuid IS NULL OR s.product_id = $1::uuid)
+      AND ($2::uuid IS NULL OR ss.id = $2::uuid)
+    ORDER BY ss.source_id, ss.indexed_at DESC NULLS LAST, ss.created_at DESC
+),
+graph_nodes AS (
+    SELECT
+        c.id,
+        c.chunk_type,
+        c.chunk_name,
+        c.parent_name,
+        c.start_line,
+        c.end_line,
+        c.importance_score AS importance,
+        f.path,
+        sc.source_name
+    FROM chunks c
+    INNER JOIN files f ON f.id = c.file_id
+    INNER JOIN scope_snapshots sc ON f.snapshot_id = sc.id
+    WHERE ($3::text IS NULL OR f.path LIKE ($3::text || '%'))
+      AND ($4::float8 IS NULL OR c.importance_score >= $4::float8)
+)
+SELECT
+    d.dep_type,
+    d.symbol,
+    fn.id AS from_chunk_id,
+    fn.source_name::text AS from_source_name,
+    fn.path AS from_path,
+    fn.chunk_type AS from_type,
+    fn.chunk_name AS from_name,
+    fn.parent_name AS from_parent,
+    fn.start_line AS from_start_line,
+    fn.end_line AS from_end_line,
+    fn.importance AS from_importance,
+    tn.id AS to_chunk_id,
+    tn.source_name::text AS to_source_name,
+    tn.path AS to_path,
+    tn.chunk_type AS to_type,
+    tn.chunk_name AS to_name,
+    tn.parent_name AS to_parent,
+    tn.start_line AS to_start_line,
+    tn.end_line AS to_end_line,
+    tn.importance AS to_importance
+FROM chunk_dependencies d
+INNER JOIN graph_nodes fn ON fn.id = d.from_chunk_id
+INNER JOIN graph_nodes tn ON tn.id = d.to_chunk_id
+ORDER BY fn.source_name, fn.path, fn.start_line, tn.source_name, tn.path, tn.start_line, d.dep_type, d.symbol
+`
+
+type ListDependencyGraphEdgesParams struct {
+	ProductID     pgtype.UUID   `json:"product_id"`
+	SnapshotID    pgtype.UUID   `json:"snapshot_id"`
+	PathPrefix    pgtype.Text   `json:"path_prefix"`
+	MinImportance pgtype.Float8 `json:"min_importance"`
+}
+
+type ListDependencyGraphEdgesRow struct {
+	DepType        string         `json:"dep_type"`
+	Symbol         pgtype.Text    `json:"symbol"`
+	FromChunkID    pgtype.UUID    `json:"from_chunk_id"`
+	FromSourceName string         `json:"from_source_name"`
+	FromPath       string         `json:"from_path"`
+	FromType       pgtype.Text    `json:"from_type"`
+	FromName       pgtype.Text    `json:"from_name"`
+	FromParent     pgtype.Text    `json:"from_parent"`
+	FromStartLine  int32          `json:"from_start_line"`
+	FromEndLine    int32          `json:"from_end_line"`
+	FromImportance pgtype.Numeric `json:"from_importance"`
+	ToChunkID      pgtype.UUID    `json:"to_chunk_id"`
+	ToSourceName   string         `json:"to_source_name"`
+	ToPath         string         `json:"to_path"`
+	ToType         pgtype.Text    `json:"to_type"`
+	ToName         pgtype.Text    `json:"to_name"`
+	ToParent       pgtype.Text    `json:"to_parent"`
+	ToStartLine    int32          `json:"to_start_line"`
+	ToEndLine      int32          `json:"to_end_line"`
+	ToImportance   pgtype.Numeric `json:"to_importance"`
+}
+
+// 依存関係グラフのエッジを依存元・依存先のチャンクの情報とともに取得する（graph export 用）
+// 対象スナップショットの決め方は ListFileDependencyEdges と同じで、依存元・依存先の両方がパス・重要度の条件を満たすものに絞る
+func (q *Queries) ListDependencyGraphEdges(ctx context.Context, arg ListDependencyGraphEdgesParams) ([]ListDependencyGraphEdgesRow, error) {
+	rows, err := q.db.Query(ctx, listDependencyGraphEdges,
+		arg.ProductID,
+		arg.SnapshotID,
+		arg.PathPrefix,
+		arg.MinImportance,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListDependencyGraphEdgesRow{}
+	for rows.Next() {
+		var i ListDependencyGraphEdgesRow
+		if err := rows.Scan(
+			&i.DepType,
+			&i.Symbol,
+			&i.FromChunkID,
+			&i.FromSourceName,
+			&i.FromPath,
+			&i.FromType,
+			&i.FromName,
+			&i.FromParent,
+			&i.FromStartLine,
+			&i.FromEndLine,
+			&i.FromImportance,
+			&i.ToChunkID,
+			&i.ToSourceName,
+			&i.ToPath,
+			&i.ToType,
+			&i.ToName,
+			&i.ToParent,
+			&i.ToStartLine,
+			&i.ToEndLine,
+			&i.ToImportance,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFileDependencyEdges = `-- name: ListFileDependencyEdges :many
+WITH scope_snapshots AS (
+    SELECT DISTINCT ON (ss.source_id) ss.id
+    FROM source_snapshots ss
+    INNER JOIN sources s ON ss.source_id = s.id
+    WHERE ss.status = 'indexed'
+      AND ($2::uuid IS NULL OR s.product_id = $2::uuid)
+      AND ($3::uuid IS NULL OR ss.id = $3::uuid)
+    ORDER BY ss.source_id, ss.indexed_at DESC NULLS LAST, ss.created_at DESC
+)
+SELECT
+    ff.path AS from_path,
+    tf.path AS to_path,
+    d.dep_type,
+    COUNT(*)::int AS edge_count
+FROM chunk_dependencies d
+INNER JOIN chunks fc ON fc.id = d.from_chunk_id
+INNER JOIN files ff ON ff.id = fc.file_id
+INNER JOIN chunks tc ON tc.id = d.to_chunk_id
+INNER JOIN files tf ON tf.id = tc.file_id
+INNER JOIN scope_snapshots sc ON ff.snapshot_id = sc.id
+INNER JOIN scope_snapshots tsc ON tf.snapshot_id = tsc.id
+WHERE ff.id <> tf.id
+GROUP BY ff.path, tf.path, d.dep_type
+ORDER BY edge_count DESC, from_path, to_path, d.dep_type
+LIMIT $1
+`
+
+type ListFileDependencyEdgesParams struct {
+	RowLimit   int32       `json:"row_limit"`
+	ProductID  pgtype.UUID `json:"product_id"`
+	SnapshotID pgtype.UUID `json:"snapshot_id"`
+}
+
+type ListFileDependencyEdgesRow struct {
+	FromPath  string `json:"from_path"`
+	ToPath    string `json:"to_path"`
+	DepType   string `json:"dep_type"`
+	EdgeCount int32  `json:"edge_count"`
+}
+
+// 依存関係をファイル単位に集約して取得する（Wikiの図解生成用）
+// product_id 指定時はプロダクトの各ソースの最新スナップショット、snapshot_id 指定時はそのスナップショットを対象とする
+// product_id 指定時はソースをまたぐ依存関係も含む
+func (q *Queries) ListFileDependencyEdges(ctx context.Context, arg ListFileDependencyEdgesParams) ([]ListFileDependencyEdgesRow, error) {
+	rows, err := q.db.Query(ctx, listFileDependencyEdges, arg.RowLimit, arg.ProductID, arg.SnapshotID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListFileDependencyEdgesRow{}
+	for rows.Next() {
+		var i ListFileDependencyEdgesRow
+		if err := rows.Scan(
+			&i.FromPath,
+			&i.ToPath,
+			&i.DepType,
+			&i.EdgeCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRelatedChunks = `-- name: ListRelatedChunks :many
+SELECT
+    d.from_chunk_id AS via_chunk_id,
+    'outgoing'::text AS direction,
+    d.dep_type,
+    d.symbol,
+    c.id AS chunk_id,
+    f.path,
+    c.start_line,
+    c.end_line,
+    c.content,
+    c.token_count
+FROM chunk_dependencies d
+JOIN chunks c ON c.id = d.to_chunk_id
+JOIN files f ON f.id = c.file_id
+WHERE d.from_chunk_id = ANY($1::uuid[])
+  AND (cardinality($2::text[]) = 0 OR d.dep_type = ANY($2::text[]))
+UNION ALL
+SELECT
+    d.to_chunk_id AS via_chunk_id,
+    'incoming'::text AS direction,
+    d.dep_type,
+    d.symbol,
+    c.id AS chunk_id,
+    f.path,
+    c.start_line,
+    c.end_line,
+    c.content,
+    c.token_count
+FROM chunk_dependencies d
+JOIN chunks c ON c.id = d.from_chunk_id
+JOIN files f ON f.id = c.file_id
+WHERE d.to_chunk_id = ANY($1::uuid[])
+  AND (cardinality($2::text[]) = 0 OR d.dep_type = ANY($2::text[]))
+ORDER BY via_chunk_id, direction, dep_type, symbol
+`
+
+type ListRelatedChunksParams struct {
+	ChunkIds []pgtype.UUID `json:"chunk_ids"`
+	DepTypes []string      `json:"dep_types"`
+}
+
+type ListRelatedChunksRow struct {
+	ViaChunkID pgtype.UUID `json:"via_chunk_id"`
+	Direction  string      `json:"direction"`
+	DepType    string      `json:"dep_type"`
+	Symbol     pgtype.Text `json:"symbol"`
+	ChunkID    pgtype.UUID `json:"chunk_id"`
+	Path       string      `json:"path"`
+	StartLine  int32       `json:"start_line"`
+	EndLine    int32       `json:"end_line"`
+	Content    string      `json:"content"`
+	TokenCount pgtype.Int4 `json:"token_count"`
+}
+
+// 指定チャンク群と依存関係で直接つながるチャンクを取得する
+// outgoing: 指定チャンクが依存する側（呼び出し先・参照する型など）
+// incoming: 指定チャンクに依存する側（呼び出し元など）
+func (q *Queries) ListRelatedChunks(ctx context.Context, arg ListRelatedChunksParams) ([]ListRelatedChunksRow, error) {
+	rows, err := q.db.Query(ctx, listRelatedChunks, arg.ChunkIds, arg.DepTypes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListRelatedChunksRow{}
+	for rows.Next() {
+		var i ListRelatedChunksRow
+		if err := rows.Scan(
+			&i.ViaChunkID,
+			&i.Direction,
+			&i.DepType,
+			&i.Symbol,
+			&i.ChunkID,
+			&i.Path,
+			&i.StartLine,
+			&i.EndLine,
+			&i.Content,
+			&i.TokenCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTopChunkCallEdges = `-- name: ListTopChunkCallEdges :many
+WITH scope_snapshots AS (
+    SELECT DISTINCT ON (ss.source_id) ss.id
+    FROM source_snapshots ss
+    INNER JOIN sources s ON ss.source_id = s.id
+    WHERE ss.status = 'indexed'
+      AND ($1::uuid IS NULL OR s.product_id = $1::uuid)
+      AND ($2::uuid IS NULL OR ss.id = $2::uuid)
+    ORDER BY ss.source_id, ss.indexed_at DESC NULLS LAST, ss.created_at DESC
+),
+top_chunks AS (
+    SELECT c.id, c.chunk_name, c.parent_name, f.path, c.importance_score
+    FROM chunks c
+    INNER JOIN files f ON f.id = c.file_id
+    INNER JOIN scope_snapshots sc ON f.snapshot_id = sc.id
+    WHERE c.level = 2
+      AND c.chunk_name IS NOT NULL
+      AND c.importance_score IS NOT NULL
+    ORDER BY c.importance_score DESC, c.id
+    LIMIT $3
+)
+SELECT DISTINCT
+    fc.id AS from_chunk_id,
+    fc.chunk_name::text AS from_name,
+    fc.parent_name AS from_parent,
+    fc.path AS from_path,
+    tc.id AS to_chunk_id,
+    tc.chunk_name::text AS to_name,
+    tc.parent_name AS to_parent,
+    tc.path AS to_path
+FROM chunk_dependencies d
+INNER JOIN top_chunks fc ON fc.id = d.from_chunk_id
+INNER JOIN top_chunks tc ON tc.id = d.to_chunk_id
+WHERE d.dep_type = 'call'
+  AND fc.id <> tc.id
+ORDER BY from_path, from_name, to_path, to_name
+`
+
+type ListTopChunkCallEdgesParams struct {
+	ProductID  pgtype.UUID `json:"product_id"`
+	SnapshotID pgtype.UUID `json:"snapshot_id"`
+	NodeLimit  int32       `json:"node_limit"`
+}
+
+type ListTopChunkCallEdgesRow struct {
+	FromChunkID pgtype.UUID `json:"from_chunk_id"`
+	FromName    string      `json:"from_name"`
+	FromParent  pgtype.Text `json:"from_parent"`
+	FromPath    string      `json:"from_path"`
+	ToChunkID   pgtype.UUID `json:"to_chunk_id"`
+	ToName      string      `json:"to_name"`
+	ToParent    pgtype.Text `json:"to_parent"`
+	ToPath      string      `json:"to_path"`
+}
+
+// 重要度の高い関数/クラス（レベル2）のチャンク同士の呼び出し関係を取得する（Wikiの図解生成用）
+// 対象スナップショットの決め方は ListFileDependencyEdges と同じ
+func (q *Queries) ListTopChunkCallEdges(ctx context.Context, arg ListTopChunkCallEdgesParams) ([]ListTopChunkCallEdgesRow, error) {
+	rows, err := q.db.Query(ctx, listTopChunkCallEdges, arg.ProductID, arg.SnapshotID, arg.NodeLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListTopChunkCallEdgesRow{}
+	for rows.Next() {
+		var i ListTopChunkCallEdgesRow
+		if err := rows.Scan(
+			&i.FromChunkID,
+			&i.FromName,
+			&i.FromParent,
+			&i.FromPath,
+			&i.ToChunkID,
+			&i.ToName,
+			&i.ToParent,
+			&i.ToPath,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
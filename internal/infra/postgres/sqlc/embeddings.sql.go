@@ -63,56 +63,254 @@ func (q *Queries) GetEmbedding(ctx context.Context, chunkID pgtype.UUID) (Embedd
 	return i, err
 }
 
-const searchChunksByProduct = `-- name: SearchChunksByProduct :many
+const getEmbeddingModel = `-- name: GetEmbeddingModel :one
+SELECT model, created_at FROM embeddings
+WHERE chunk_id = $1
+`
+
+type GetEmbeddingModelRow struct {
+	Model     string           `json:"model"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+}
+
+// ベクトルを読み込まずに、チャンクの Embedding の生成に使用したモデルを取得する
+func (q *Queries) GetEmbeddingModel(ctx context.Context, chunkID pgtype.UUID) (GetEmbeddingModelRow, error) {
+	row := q.db.QueryRow(ctx, getEmbeddingModel, chunkID)
+	var i GetEmbeddingModelRow
+	err := row.Scan(&i.Model, &i.CreatedAt)
+	return i, err
+}
+
+const listChunksAtLine = `-- name: ListChunksAtLine :many
 WITH latest_snapshots AS (
     SELECT DISTINCT ON (source_id) id, source_id
     FROM source_snapshots
-    WHERE indexed = TRUE
+    WHERE status = 'indexed'
     ORDER BY source_id, indexed_at DESC NULLS LAST, created_at DESC
 )
 SELECT
     c.id AS chunk_id,
+    c.file_id,
     f.path,
     c.start_line,
     c.end_line,
-    c.content,
-    (1::float8 - (e.vector <=> $1::vector))::float8 AS score
-FROM embeddings e
-INNER JOIN chunks c ON e.chunk_id = c.id
+    c.level,
+    c.breadcrumb,
+    c.page_start,
+    c.page_end,
+    c.content
+FROM chunks c
 INNER JOIN files f ON c.file_id = f.id
 INNER JOIN latest_snapshots ls ON f.snapshot_id = ls.id
 INNER JOIN sources s ON ls.source_id = s.id
-WHERE s.product_id = $2
-  AND ($3::text IS NULL OR f.path LIKE ($3::text || '%'))
-  AND ($4::text IS NULL OR f.content_type = $4::text)
-ORDER BY e.vector <=> $1::vector
-LIMIT $5
+WHERE s.product_id = $1
+  AND f.path = $2
+  AND c.start_line <= $3::int
+  AND c.end_line >= $3::int
+ORDER BY c.end_line - c.start_line, c.level DESC, c.ordinal
+`
+
+type ListChunksAtLineParams struct {
+	ProductID pgtype.UUID `json:"product_id"`
+	Path      string      `json:"path"`
+	Line      int32       `json:"line"`
+}
+
+type ListChunksAtLineRow struct {
+	ChunkID    pgtype.UUID `json:"chunk_id"`
+	FileID     pgtype.UUID `json:"file_id"`
+	Path       string      `json:"path"`
+	StartLine  int32       `json:"start_line"`
+	EndLine    int32       `json:"end_line"`
+	Level      int32       `json:"level"`
+	Breadcrumb pgtype.Text `json:"breadcrumb"`
+	PageStart  pgtype.Int4 `json:"page_start"`
+	PageEnd    pgtype.Int4 `json:"page_end"`
+	Content    string      `json:"content"`
+}
+
+// プロダクトの最新スナップショットで、ファイルの指定行を含むチャンクを内側（行範囲の狭いもの）から順に取得する
+// 同じパスのファイルが複数のソースにある場合は、それぞれのチャンクを含める
+func (q *Queries) ListChunksAtLine(ctx context.Context, arg ListChunksAtLineParams) ([]ListChunksAtLineRow, error) {
+	rows, err := q.db.Query(ctx, listChunksAtLine, arg.ProductID, arg.Path, arg.Line)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListChunksAtLineRow{}
+	for rows.Next() {
+		var i ListChunksAtLineRow
+		if err := rows.Scan(
+			&i.ChunkID,
+			&i.FileID,
+			&i.Path,
+			&i.StartLine,
+			&i.EndLine,
+			&i.Level,
+			&i.Breadcrumb,
+			&i.PageStart,
+			&i.PageEnd,
+			&i.Content,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listEmbeddingsByChunkIDs = `-- name: ListEmbeddingsByChunkIDs :many
+SELECT chunk_id, vector, model FROM embeddings
+WHERE chunk_id = ANY($1::uuid[])
+`
+
+type ListEmbeddingsByChunkIDsRow struct {
+	ChunkID pgtype.UUID        `json:"chunk_id"`
+	Vector  pgvector_go.Vector `json:"vector"`
+	Model   string             `json:"model"`
+}
+
+// 再チャンク化で内容が変わらないチャンクの Embedding を再利用するために、チャンクの Embedding をまとめて取得する
+func (q *Queries) ListEmbeddingsByChunkIDs(ctx context.Context, chunkIds []pgtype.UUID) ([]ListEmbeddingsByChunkIDsRow, error) {
+	rows, err := q.db.Query(ctx, listEmbeddingsByChunkIDs, chunkIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListEmbeddingsByChunkIDsRow{}
+	for rows.Next() {
+		var i ListEmbeddingsByChunkIDsRow
+		if err := rows.Scan(&i.ChunkID, &i.Vector, &i.Model); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchChunksByProduct = `-- name: SearchChunksByProduct :many
+WITH latest_snapshots AS (
+    SELECT DISTINCT ON (source_id) id, source_id
+    FROM source_snapshots
+    WHERE status = 'indexed'
+      AND ($4::uuid IS NULL OR id = $4::uuid)
+    ORDER BY source_id, indexed_at DESC NULLS LAST, created_at DESC
+),
+ranking AS (
+    -- 鮮度の重みは明示指定を優先し、なければプロダクトの設定を使用する
+    SELECT COALESCE($5::float8, p.recency_weight, 0)::float8 AS recency_weight
+    FROM products p
+    WHERE p.id = $6
+),
+candidates AS (
+    SELECT
+        c.id AS chunk_id,
+        c.file_id,
+        f.path,
+        c.start_line,
+        c.end_line,
+        c.level,
+        c.breadcrumb,
+        c.page_start,
+        c.page_end,
+        c.content,
+        c.importance_score,
+        c.updated_at,
+        (1::float8 - (e.vector <=> $7))::float8 AS similarity
+    FROM embeddings e
+    INNER JOIN chunks c ON e.chunk_id = c.id
+    INNER JOIN files f ON c.file_id = f.id
+    INNER JOIN latest_snapshots ls ON f.snapshot_id = ls.id
+    INNER JOIN sources s ON ls.source_id = s.id
+    WHERE s.product_id = $6
+      AND ($8::text IS NULL OR f.path LIKE ($8::text || '%'))
+      AND (cardinality($9::text[]) = 0 OR f.domain = ANY($9::text[]))
+      AND (cardinality($10::text[]) = 0 OR f.language = ANY($10::text[]))
+      AND ($11::text IS NULL OR f.content_type = $11::text)
+      AND (cardinality($12::uuid[]) = 0 OR c.file_id = ANY($12::uuid[]))
+    ORDER BY e.vector <=> $7
+    LIMIT CASE
+        WHEN $1::float8 > 0 OR (SELECT recency_weight FROM ranking) > 0 THEN $13::int
+        ELSE $3::int
+    END
+)
+SELECT
+    cd.chunk_id,
+    cd.file_id,
+    cd.path,
+    cd.start_line,
+    cd.end_line,
+    cd.level,
+    cd.breadcrumb,
+    cd.page_start,
+    cd.page_end,
+    cd.content,
+    (((1::float8 - $1::float8) * cd.similarity
+        + $1::float8 * COALESCE(cd.importance_score, 0.5)::float8)
+        * (1::float8 - r.recency_weight + r.recency_weight * COALESCE(
+            power(0.5::float8, GREATEST(EXTRACT(EPOCH FROM (CURRENT_TIMESTAMP - cd.updated_at))::float8, 0) / 86400 / $2::float8),
+            0.5)))::float8 AS score
+FROM candidates cd
+CROSS JOIN ranking r
+ORDER BY score DESC
+LIMIT $3
 `
 
 type SearchChunksByProductParams struct {
-	QueryVector pgvector_go.Vector `json:"query_vector"`
-	ProductID   pgtype.UUID        `json:"product_id"`
-	PathPrefix  pgtype.Text        `json:"path_prefix"`
-	ContentType pgtype.Text        `json:"content_type"`
-	RowLimit    int32              `json:"row_limit"`
+	ImportanceWeight    float64            `json:"importance_weight"`
+	RecencyHalfLifeDays float64            `json:"recency_half_life_days"`
+	RowLimit            int32              `json:"row_limit"`
+	SnapshotID          pgtype.UUID        `json:"snapshot_id"`
+	RecencyWeight       pgtype.Float8      `json:"recency_weight"`
+	ProductID           pgtype.UUID        `json:"product_id"`
+	QueryVector         pgvector_go.Vector `json:"query_vector"`
+	PathPrefix          pgtype.Text        `json:"path_prefix"`
+	Domains             []string           `json:"domains"`
+	Languages           []string           `json:"languages"`
+	ContentType         pgtype.Text        `json:"content_type"`
+	FileIds             []pgtype.UUID      `json:"file_ids"`
+	CandidateLimit      int32              `json:"candidate_limit"`
 }
 
 type SearchChunksByProductRow struct {
-	ChunkID   pgtype.UUID `json:"chunk_id"`
-	Path      string      `json:"path"`
-	StartLine int32       `json:"start_line"`
-	EndLine   int32       `json:"end_line"`
-	Content   string      `json:"content"`
-	Score     float64     `json:"score"`
+	ChunkID    pgtype.UUID `json:"chunk_id"`
+	FileID     pgtype.UUID `json:"file_id"`
+	Path       string      `json:"path"`
+	StartLine  int32       `json:"start_line"`
+	EndLine    int32       `json:"end_line"`
+	Level      int32       `json:"level"`
+	Breadcrumb pgtype.Text `json:"breadcrumb"`
+	PageStart  pgtype.Int4 `json:"page_start"`
+	PageEnd    pgtype.Int4 `json:"page_end"`
+	Content    string      `json:"content"`
+	Score      float64     `json:"score"`
 }
 
+// snapshot_id 指定時は最新スナップショットの代わりにそのスナップショットのみを対象とする
+// ベクトル距離順に候補を取得（HNSWインデックスを利用）したうえで、類似度と importance_score を
+// importance_weight で加重平均し、チャンクの最終更新日時からの経過日数に応じて recency_weight の割合まで減衰させたスコアで並べ替える
+// 重み付けがない場合は候補を row_limit 件に絞り、従来どおりベクトル距離順の上位を返す
 func (q *Queries) SearchChunksByProduct(ctx context.Context, arg SearchChunksByProductParams) ([]SearchChunksByProductRow, error) {
 	rows, err := q.db.Query(ctx, searchChunksByProduct,
-		arg.QueryVector,
+		arg.ImportanceWeight,
+		arg.RecencyHalfLifeDays,
+		arg.RowLimit,
+		arg.SnapshotID,
+		arg.RecencyWeight,
 		arg.ProductID,
+		arg.QueryVector,
 		arg.PathPrefix,
+		arg.Domains,
+		arg.Languages,
 		arg.ContentType,
-		arg.RowLimit,
+		arg.FileIds,
+		arg.CandidateLimit,
 	)
 	if err != nil {
 		return nil, err
@@ -123,9 +321,14 @@ func (q *Queries) SearchChunksByProduct(ctx context.Context, arg SearchChunksByP
 		var i SearchChunksByProductRow
 		if err := rows.Scan(
 			&i.ChunkID,
+			&i.FileID,
 			&i.Path,
 			&i.StartLine,
 			&i.EndLine,
+			&i.Level,
+			&i.Breadcrumb,
+			&i.PageStart,
+			&i.PageEnd,
 			&i.Content,
 			&i.Score,
 		); err != nil {
@@ -140,47 +343,110 @@ func (q *Queries) SearchChunksByProduct(ctx context.Context, arg SearchChunksByP
 }
 
 const searchChunksBySnapshot = `-- name: SearchChunksBySnapshot :many
+WITH ranking AS (
+    -- 鮮度の重みは明示指定を優先し、なければプロダクトの設定を使用する
+    SELECT COALESCE($4::float8, p.recency_weight, 0)::float8 AS recency_weight
+    FROM source_snapshots ss
+    INNER JOIN sources s ON ss.source_id = s.id
+    INNER JOIN products p ON s.product_id = p.id
+    WHERE ss.id = $5
+),
+candidates AS (
+    SELECT
+        c.id AS chunk_id,
+        c.file_id,
+        f.path,
+        c.start_line,
+        c.end_line,
+        c.level,
+        c.breadcrumb,
+        c.page_start,
+        c.page_end,
+        c.content,
+        c.importance_score,
+        c.updated_at,
+        (1 - (e.vector <=> $6))::float8 AS similarity
+    FROM chunks c
+    JOIN files f ON c.file_id = f.id
+    JOIN embeddings e ON c.id = e.chunk_id
+    WHERE f.snapshot_id = $5
+      AND ($7::text IS NULL OR f.path LIKE $7::text || '%')
+      AND (cardinality($8::text[]) = 0 OR f.domain = ANY($8::text[]))
+      AND (cardinality($9::text[]) = 0 OR f.language = ANY($9::text[]))
+      AND ($10::text IS NULL OR f.content_type = $10::text)
+      AND (cardinality($11::uuid[]) = 0 OR c.file_id = ANY($11::uuid[]))
+    ORDER BY e.vector <=> $6
+    LIMIT CASE
+        WHEN $1::float8 > 0 OR (SELECT recency_weight FROM ranking) > 0 THEN $12::int
+        ELSE $3::int
+    END
+)
 SELECT
-    c.id AS chunk_id,
-    f.path,
-    c.start_line,
-    c.end_line,
-    c.content,
-    (1 - (e.vector <=> $1::vector))::float8 AS score
-FROM chunks c
-JOIN files f ON c.file_id = f.id
-JOIN embeddings e ON c.id = e.chunk_id
-WHERE f.snapshot_id = $2
-  AND ($3::text IS NULL OR f.path LIKE $3::text || '%')
-  AND ($4::text IS NULL OR f.content_type = $4::text)
-ORDER BY e.vector <=> $1::vector
-LIMIT $5
+    cd.chunk_id,
+    cd.file_id,
+    cd.path,
+    cd.start_line,
+    cd.end_line,
+    cd.level,
+    cd.breadcrumb,
+    cd.page_start,
+    cd.page_end,
+    cd.content,
+    (((1::float8 - $1::float8) * cd.similarity
+        + $1::float8 * COALESCE(cd.importance_score, 0.5)::float8)
+        * (1::float8 - r.recency_weight + r.recency_weight * COALESCE(
+            power(0.5::float8, GREATEST(EXTRACT(EPOCH FROM (CURRENT_TIMESTAMP - cd.updated_at))::float8, 0) / 86400 / $2::float8),
+            0.5)))::float8 AS score
+FROM candidates cd
+CROSS JOIN ranking r
+ORDER BY score DESC
+LIMIT $3
 `
 
 type SearchChunksBySnapshotParams struct {
-	QueryVector pgvector_go.Vector `json:"query_vector"`
-	SnapshotID  pgtype.UUID        `json:"snapshot_id"`
-	PathPrefix  pgtype.Text        `json:"path_prefix"`
-	ContentType pgtype.Text        `json:"content_type"`
-	LimitVal    int32              `json:"limit_val"`
+	ImportanceWeight    float64            `json:"importance_weight"`
+	RecencyHalfLifeDays float64            `json:"recency_half_life_days"`
+	LimitVal            int32              `json:"limit_val"`
+	RecencyWeight       pgtype.Float8      `json:"recency_weight"`
+	SnapshotID          pgtype.UUID        `json:"snapshot_id"`
+	QueryVector         pgvector_go.Vector `json:"query_vector"`
+	PathPrefix          pgtype.Text        `json:"path_prefix"`
+	Domains             []string           `json:"domains"`
+	Languages           []string           `json:"languages"`
+	ContentType         pgtype.Text        `json:"content_type"`
+	FileIds             []pgtype.UUID      `json:"file_ids"`
+	CandidateLimit      int32              `json:"candidate_limit"`
 }
 
 type SearchChunksBySnapshotRow struct {
-	ChunkID   pgtype.UUID `json:"chunk_id"`
-	Path      string      `json:"path"`
-	StartLine int32       `json:"start_line"`
-	EndLine   int32       `json:"end_line"`
-	Content   string      `json:"content"`
-	Score     float64     `json:"score"`
+	ChunkID    pgtype.UUID `json:"chunk_id"`
+	FileID     pgtype.UUID `json:"file_id"`
+	Path       string      `json:"path"`
+	StartLine  int32       `json:"start_line"`
+	EndLine    int32       `json:"end_line"`
+	Level      int32       `json:"level"`
+	Breadcrumb pgtype.Text `json:"breadcrumb"`
+	PageStart  pgtype.Int4 `json:"page_start"`
+	PageEnd    pgtype.Int4 `json:"page_end"`
+	Content    string      `json:"content"`
+	Score      float64     `json:"score"`
 }
 
+// 並べ替えは SearchChunksByProduct と同様に importance_weight と recency_weight で重み付けする
 func (q *Queries) SearchChunksBySnapshot(ctx context.Context, arg SearchChunksBySnapshotParams) ([]SearchChunksBySnapshotRow, error) {
 	rows, err := q.db.Query(ctx, searchChunksBySnapshot,
-		arg.QueryVector,
+		arg.ImportanceWeight,
+		arg.RecencyHalfLifeDays,
+		arg.LimitVal,
+		arg.RecencyWeight,
 		arg.SnapshotID,
+		arg.QueryVector,
 		arg.PathPrefix,
+		arg.Domains,
+		arg.Languages,
 		arg.ContentType,
-		arg.LimitVal,
+		arg.FileIds,
+		arg.CandidateLimit,
 	)
 	if err != nil {
 		return nil, err
@@ -191,9 +457,14 @@ func (q *Queries) SearchChunksBySnapshot(ctx context.Context, arg SearchChunksBy
 		var i SearchChunksBySnapshotRow
 		if err := rows.Scan(
 			&i.ChunkID,
+			&i.FileID,
 			&i.Path,
 			&i.StartLine,
 			&i.EndLine,
+			&i.Level,
+			&i.Breadcrumb,
+			&i.PageStart,
+			&i.PageEnd,
 			&i.Content,
 			&i.Score,
 		); err != nil {
@@ -211,52 +482,118 @@ const searchChunksBySource = `-- name: SearchChunksBySource :many
 WITH latest_snapshot AS (
     SELECT id
     FROM source_snapshots
-    WHERE source_id = $5
-      AND indexed = TRUE
+    WHERE source_id = $4
+      AND status = 'indexed'
+      AND ($5::uuid IS NULL OR id = $5::uuid)
     ORDER BY indexed_at DESC NULLS LAST, created_at DESC
     LIMIT 1
+),
+ranking AS (
+    -- 鮮度の重みは明示指定を優先し、なければプロダクトの設定を使用する
+    SELECT COALESCE($6::float8, p.recency_weight, 0)::float8 AS recency_weight
+    FROM sources s
+    INNER JOIN products p ON s.product_id = p.id
+    WHERE s.id = $4
+),
+candidates AS (
+    SELECT
+        c.id AS chunk_id,
+        c.file_id,
+        f.path,
+        c.start_line,
+        c.end_line,
+        c.level,
+        c.breadcrumb,
+        c.page_start,
+        c.page_end,
+        c.content,
+        c.importance_score,
+        c.updated_at,
+        (1::float8 - (e.vector <=> $7))::float8 AS similarity
+    FROM embeddings e
+    INNER JOIN chunks c ON e.chunk_id = c.id
+    INNER JOIN files f ON c.file_id = f.id
+    INNER JOIN latest_snapshot ls ON f.snapshot_id = ls.id
+    WHERE ($8::text IS NULL OR f.path LIKE ($8::text || '%'))
+      AND (cardinality($9::text[]) = 0 OR f.domain = ANY($9::text[]))
+      AND (cardinality($10::text[]) = 0 OR f.language = ANY($10::text[]))
+      AND ($11::text IS NULL OR f.content_type = $11::text)
+      AND (cardinality($12::uuid[]) = 0 OR c.file_id = ANY($12::uuid[]))
+    ORDER BY e.vector <=> $7
+    LIMIT CASE
+        WHEN $1::float8 > 0 OR (SELECT recency_weight FROM ranking) > 0 THEN $13::int
+        ELSE $3::int
+    END
 )
 SELECT
-    c.id AS chunk_id,
-    f.path,
-    c.start_line,
-    c.end_line,
-    c.content,
-    (1::float8 - (e.vector <=> $1::vector))::float8 AS score
-FROM embeddings e
-INNER JOIN chunks c ON e.chunk_id = c.id
-INNER JOIN files f ON c.file_id = f.id
-INNER JOIN latest_snapshot ls ON f.snapshot_id = ls.id
-WHERE ($2::text IS NULL OR f.path LIKE ($2::text || '%'))
-  AND ($3::text IS NULL OR f.content_type = $3::text)
-ORDER BY e.vector <=> $1::vector
-LIMIT $4
+    cd.chunk_id,
+    cd.file_id,
+    cd.path,
+    cd.start_line,
+    cd.end_line,
+    cd.level,
+    cd.breadcrumb,
+    cd.page_start,
+    cd.page_end,
+    cd.content,
+    (((1::float8 - $1::float8) * cd.similarity
+        + $1::float8 * COALESCE(cd.importance_score, 0.5)::float8)
+        * (1::float8 - r.recency_weight + r.recency_weight * COALESCE(
+            power(0.5::float8, GREATEST(EXTRACT(EPOCH FROM (CURRENT_TIMESTAMP - cd.updated_at))::float8, 0) / 86400 / $2::float8),
+            0.5)))::float8 AS score
+FROM candidates cd
+CROSS JOIN ranking r
+ORDER BY score DESC
+LIMIT $3
 `
 
 type SearchChunksBySourceParams struct {
-	QueryVector pgvector_go.Vector `json:"query_vector"`
-	PathPrefix  pgtype.Text        `json:"path_prefix"`
-	ContentType pgtype.Text        `json:"content_type"`
-	RowLimit    int32              `json:"row_limit"`
-	SourceID    pgtype.UUID        `json:"source_id"`
+	ImportanceWeight    float64            `json:"importance_weight"`
+	RecencyHalfLifeDays float64            `json:"recency_half_life_days"`
+	RowLimit            int32              `json:"row_limit"`
+	SourceID            pgtype.UUID        `json:"source_id"`
+	SnapshotID          pgtype.UUID        `json:"snapshot_id"`
+	RecencyWeight       pgtype.Float8      `json:"recency_weight"`
+	QueryVector         pgvector_go.Vector `json:"query_vector"`
+	PathPrefix          pgtype.Text        `json:"path_prefix"`
+	Domains             []string           `json:"domains"`
+	Languages           []string           `json:"languages"`
+	ContentType         pgtype.Text        `json:"content_type"`
+	FileIds             []pgtype.UUID      `json:"file_ids"`
+	CandidateLimit      int32              `json:"candidate_limit"`
 }
 
 type SearchChunksBySourceRow struct {
-	ChunkID   pgtype.UUID `json:"chunk_id"`
-	Path      string      `json:"path"`
-	StartLine int32       `json:"start_line"`
-	EndLine   int32       `json:"end_line"`
-	Content   string      `json:"content"`
-	Score     float64     `json:"score"`
+	ChunkID    pgtype.UUID `json:"chunk_id"`
+	FileID     pgtype.UUID `json:"file_id"`
+	Path       string      `json:"path"`
+	StartLine  int32       `json:"start_line"`
+	EndLine    int32       `json:"end_line"`
+	Level      int32       `json:"level"`
+	Breadcrumb pgtype.Text `json:"breadcrumb"`
+	PageStart  pgtype.Int4 `json:"page_start"`
+	PageEnd    pgtype.Int4 `json:"page_end"`
+	Content    string      `json:"content"`
+	Score      float64     `json:"score"`
 }
 
+// snapshot_id 指定時は最新スナップショットの代わりにそのスナップショットのみを対象とする
+// 並べ替えは SearchChunksByProduct と同様に importance_weight と recency_weight で重み付けする
 func (q *Queries) SearchChunksBySource(ctx context.Context, arg SearchChunksBySourceParams) ([]SearchChunksBySourceRow, error) {
 	rows, err := q.db.Query(ctx, searchChunksBySource,
+		arg.ImportanceWeight,
+		arg.RecencyHalfLifeDays,
+		arg.RowLimit,
+		arg.SourceID,
+		arg.SnapshotID,
+		arg.RecencyWeight,
 		arg.QueryVector,
 		arg.PathPrefix,
+		arg.Domains,
+		arg.Languages,
 		arg.ContentType,
-		arg.RowLimit,
-		arg.SourceID,
+		arg.FileIds,
+		arg.CandidateLimit,
 	)
 	if err != nil {
 		return nil, err
@@ -267,9 +604,14 @@ func (q *Queries) SearchChunksBySource(ctx context.Context, arg SearchChunksBySo
 		var i SearchChunksBySourceRow
 		if err := rows.Scan(
 			&i.ChunkID,
+			&i.FileID,
 			&i.Path,
 			&i.StartLine,
 			&i.EndLine,
+			&i.Level,
+			&i.Breadcrumb,
+			&i.PageStart,
+			&i.PageEnd,
 			&i.Content,
 			&i.Score,
 		); err != nil {
@@ -289,15 +631,15 @@ SELECT
     e.vector,
     e.model,
     e.created_at,
-    1 - (e.vector <=> $1::vector) as similarity
+    1 - (e.vector <=> $1) as similarity
 FROM embeddings e
-ORDER BY e.vector <=> $1::vector
+ORDER BY e.vector <=> $1
 LIMIT $2
 `
 
 type SearchSimilarChunksParams struct {
-	Column1 pgvector_go.Vector `json:"column_1"`
-	Limit   int32              `json:"limit"`
+	Vector pgvector_go.Vector `json:"vector"`
+	Limit  int32              `json:"limit"`
 }
 
 type SearchSimilarChunksRow struct {
@@ -309,7 +651,7 @@ type SearchSimilarChunksRow struct {
 }
 
 func (q *Queries) SearchSimilarChunks(ctx context.Context, arg SearchSimilarChunksParams) ([]SearchSimilarChunksRow, error) {
-	rows, err := q.db.Query(ctx, searchSimilarChunks, arg.Column1, arg.Limit)
+	rows, err := q.db.Query(ctx, searchSimilarChunks, arg.Vector, arg.Limit)
 	if err != nil {
 		return nil, err
 	}
@@ -14,7 +14,13 @@ import (
 const createFile = `-- name: CreateFile :one
 INSERT INTO files (snapshot_id, path, size, content_type, content_hash, language, domain)
 VALUES ($1, $2, $3, $4, $5, $6, $7)
-RETURNING id, snapshot_id, path, size, content_type, content_hash, language, domain, created_at
+ON CONFLICT (snapshot_id, path) DO UPDATE SET
+    size = EXCLUDED.size,
+    content_type = EXCLUDED.content_type,
+    content_hash = EXCLUDED.content_hash,
+    language = EXCLUDED.language,
+    domain = EXCLUDED.domain
+RETURNING id, snapshot_id, path, size, content_type, content_hash, language, domain, owners, created_at
 `
 
 type CreateFileParams struct {
@@ -27,6 +33,7 @@ type CreateFileParams struct {
 	Domain      pgtype.Text `json:"domain"`
 }
 
+// スナップショットの再実行（再開・--force-init）で登録済みのファイルは、ID を変えずに内容の情報を置き換える
 func (q *Queries) CreateFile(ctx context.Context, arg CreateFileParams) (File, error) {
 	row := q.db.QueryRow(ctx, createFile,
 		arg.SnapshotID,
@@ -47,6 +54,7 @@ func (q *Queries) CreateFile(ctx context.Context, arg CreateFileParams) (File, e
 		&i.ContentHash,
 		&i.Language,
 		&i.Domain,
+		&i.Owners,
 		&i.CreatedAt,
 	)
 	return i, err
@@ -88,7 +96,7 @@ func (q *Queries) DeleteFilesBySnapshot(ctx context.Context, snapshotID pgtype.U
 }
 
 const findFilesByContentHash = `-- name: FindFilesByContentHash :many
-SELECT id, snapshot_id, path, size, content_type, content_hash, language, domain, created_at FROM files
+SELECT id, snapshot_id, path, size, content_type, content_hash, language, domain, owners, created_at FROM files
 WHERE content_hash = $1
 ORDER BY created_at DESC
 `
@@ -111,6 +119,7 @@ func (q *Queries) FindFilesByContentHash(ctx context.Context, contentHash string
 			&i.ContentHash,
 			&i.Language,
 			&i.Domain,
+			&i.Owners,
 			&i.CreatedAt,
 		); err != nil {
 			return nil, err
@@ -167,7 +176,7 @@ func (q *Queries) GetDomainCoverageBySnapshot(ctx context.Context, snapshotID pg
 }
 
 const getFile = `-- name: GetFile :one
-SELECT id, snapshot_id, path, size, content_type, content_hash, language, domain, created_at FROM files
+SELECT id, snapshot_id, path, size, content_type, content_hash, language, domain, owners, created_at FROM files
 WHERE id = $1
 `
 
@@ -183,13 +192,14 @@ func (q *Queries) GetFile(ctx context.Context, id pgtype.UUID) (File, error) {
 		&i.ContentHash,
 		&i.Language,
 		&i.Domain,
+		&i.Owners,
 		&i.CreatedAt,
 	)
 	return i, err
 }
 
 const getFileByPath = `-- name: GetFileByPath :one
-SELECT id, snapshot_id, path, size, content_type, content_hash, language, domain, created_at FROM files
+SELECT id, snapshot_id, path, size, content_type, content_hash, language, domain, owners, created_at FROM files
 WHERE snapshot_id = $1 AND path = $2
 `
 
@@ -210,6 +220,7 @@ func (q *Queries) GetFileByPath(ctx context.Context, arg GetFileByPathParams) (F
 		&i.ContentHash,
 		&i.Language,
 		&i.Domain,
+		&i.Owners,
 		&i.CreatedAt,
 	)
 	return i, err
@@ -247,7 +258,7 @@ func (q *Queries) GetFileHashesBySnapshot(ctx context.Context, snapshotID pgtype
 }
 
 const getFilesByDomain = `-- name: GetFilesByDomain :many
-SELECT id, snapshot_id, path, size, content_type, content_hash, language, domain, created_at FROM files
+SELECT id, snapshot_id, path, size, content_type, content_hash, language, domain, owners, created_at FROM files
 WHERE snapshot_id = $1 AND domain = $2
 ORDER BY path
 `
@@ -276,6 +287,7 @@ func (q *Queries) GetFilesByDomain(ctx context.Context, arg GetFilesByDomainPara
 			&i.ContentHash,
 			&i.Language,
 			&i.Domain,
+			&i.Owners,
 			&i.CreatedAt,
 		); err != nil {
 			return nil, err
@@ -288,8 +300,89 @@ func (q *Queries) GetFilesByDomain(ctx context.Context, arg GetFilesByDomainPara
 	return items, nil
 }
 
+const listFileOwners = `-- name: ListFileOwners :many
+SELECT id, owners
+FROM files
+WHERE id = ANY($1::uuid[])
+  AND cardinality(owners) > 0
+`
+
+type ListFileOwnersRow struct {
+	ID     pgtype.UUID `json:"id"`
+	Owners []string    `json:"owners"`
+}
+
+// ファイルの担当者を取得する（担当者の定義がないファイルは除く）
+func (q *Queries) ListFileOwners(ctx context.Context, fileIds []pgtype.UUID) ([]ListFileOwnersRow, error) {
+	rows, err := q.db.Query(ctx, listFileOwners, fileIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListFileOwnersRow{}
+	for rows.Next() {
+		var i ListFileOwnersRow
+		if err := rows.Scan(&i.ID, &i.Owners); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFileOwnersByPaths = `-- name: ListFileOwnersByPaths :many
+WITH latest_snapshots AS (
+    SELECT DISTINCT ON (ss.source_id) ss.id, ss.indexed_at, ss.created_at
+    FROM source_snapshots ss
+    INNER JOIN sources s ON ss.source_id = s.id
+    WHERE s.product_id = $2 AND ss.status = 'indexed'
+    ORDER BY ss.source_id, ss.indexed_at DESC NULLS LAST, ss.created_at DESC
+)
+SELECT f.path, f.owners
+FROM files f
+INNER JOIN latest_snapshots ls ON f.snapshot_id = ls.id
+WHERE f.path = ANY($1::text[])
+  AND cardinality(f.owners) > 0
+ORDER BY ls.indexed_at DESC NULLS LAST, ls.created_at DESC
+`
+
+type ListFileOwnersByPathsParams struct {
+	Paths     []string    `json:"paths"`
+	ProductID pgtype.UUID `json:"product_id"`
+}
+
+type ListFileOwnersByPathsRow struct {
+	Path   string   `json:"path"`
+	Owners []string `json:"owners"`
+}
+
+// プロダクトの各ソースの最新のインデックス済みスナップショットで、指定したパスのファイルの担当者を取得する
+// 同じパスが複数のソースにある場合は、インデックス日時の新しいスナップショットを先に返す
+func (q *Queries) ListFileOwnersByPaths(ctx context.Context, arg ListFileOwnersByPathsParams) ([]ListFileOwnersByPathsRow, error) {
+	rows, err := q.db.Query(ctx, listFileOwnersByPaths, arg.Paths, arg.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListFileOwnersByPathsRow{}
+	for rows.Next() {
+		var i ListFileOwnersByPathsRow
+		if err := rows.Scan(&i.Path, &i.Owners); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listFilesByContentType = `-- name: ListFilesByContentType :many
-SELECT id, snapshot_id, path, size, content_type, content_hash, language, domain, created_at FROM files
+SELECT id, snapshot_id, path, size, content_type, content_hash, language, domain, owners, created_at FROM files
 WHERE snapshot_id = $1 AND content_type = $2
 ORDER BY path
 `
@@ -317,6 +410,7 @@ func (q *Queries) ListFilesByContentType(ctx context.Context, arg ListFilesByCon
 			&i.ContentHash,
 			&i.Language,
 			&i.Domain,
+			&i.Owners,
 			&i.CreatedAt,
 		); err != nil {
 			return nil, err
@@ -330,7 +424,7 @@ func (q *Queries) ListFilesByContentType(ctx context.Context, arg ListFilesByCon
 }
 
 const listFilesBySnapshot = `-- name: ListFilesBySnapshot :many
-SELECT id, snapshot_id, path, size, content_type, content_hash, language, domain, created_at FROM files
+SELECT id, snapshot_id, path, size, content_type, content_hash, language, domain, owners, created_at FROM files
 WHERE snapshot_id = $1
 ORDER BY path
 `
@@ -353,6 +447,7 @@ func (q *Queries) ListFilesBySnapshot(ctx context.Context, snapshotID pgtype.UUI
 			&i.ContentHash,
 			&i.Language,
 			&i.Domain,
+			&i.Owners,
 			&i.CreatedAt,
 		); err != nil {
 			return nil, err
@@ -364,3 +459,118 @@ func (q *Queries) ListFilesBySnapshot(ctx context.Context, snapshotID pgtype.UUI
 	}
 	return items, nil
 }
+
+const listKeyFiles = `-- name: ListKeyFiles :many
+WITH scope_snapshots AS (
+    SELECT DISTINCT ON (ss.source_id) ss.id, s.name AS source_name
+    FROM source_snapshots ss
+    INNER JOIN sources s ON ss.source_id = s.id
+    WHERE ss.status = 'indexed'
+      AND ($2::uuid IS NULL OR s.product_id = $2::uuid)
+      AND ($3::uuid IS NULL OR ss.id = $3::uuid)
+    ORDER BY ss.source_id, ss.indexed_at DESC NULLS LAST, ss.created_at DESC
+),
+file_importance AS (
+    SELECT
+        f.id,
+        sc.source_name,
+        f.snapshot_id,
+        f.path,
+        f.language,
+        f.domain,
+        f.owners,
+        (f.path ~ '(^|/)(main\.(go|py|rs|c|cc|cpp|java|kt|swift)|index\.(js|jsx|ts|tsx|mjs)|app\.(py|js|ts)|server\.(go|js|ts)|manage\.py|__main__\.py)$')::boolean AS is_entry_point,
+        COALESCE(MAX(c.importance_score), 0)::float8 AS importance
+    FROM files f
+    INNER JOIN scope_snapshots sc ON f.snapshot_id = sc.id
+    LEFT JOIN chunks c ON c.file_id = f.id
+    WHERE f.domain IS NULL OR f.domain <> 'tests'
+    GROUP BY f.id, sc.source_name
+)
+SELECT
+    fi.source_name::text AS source_name,
+    fi.path,
+    fi.language,
+    fi.domain,
+    fi.is_entry_point,
+    fi.importance,
+    fi.owners,
+    COALESCE(sm.content, '')::text AS summary
+FROM file_importance fi
+LEFT JOIN summaries sm
+    ON sm.snapshot_id = fi.snapshot_id
+   AND sm.summary_type = 'file'
+   AND sm.target_path = fi.path
+ORDER BY fi.is_entry_point DESC, fi.importance DESC, fi.path
+LIMIT $1
+`
+
+type ListKeyFilesParams struct {
+	RowLimit   int32       `json:"row_limit"`
+	ProductID  pgtype.UUID `json:"product_id"`
+	SnapshotID pgtype.UUID `json:"snapshot_id"`
+}
+
+type ListKeyFilesRow struct {
+	SourceName   string      `json:"source_name"`
+	Path         string      `json:"path"`
+	Language     pgtype.Text `json:"language"`
+	Domain       pgtype.Text `json:"domain"`
+	IsEntryPoint bool        `json:"is_entry_point"`
+	Importance   float64     `json:"importance"`
+	Owners       []string    `json:"owners"`
+	Summary      string      `json:"summary"`
+}
+
+// オンボーディングガイド用に、エントリポイントと重要度の高いファイルをファイル要約とともに取得する
+// 対象スナップショットの決め方は ListDirectoryCoverage と同じ
+// エントリポイント（main.go・index.ts など）を先に、それ以外はチャンクの重要度の最大値の高い順に並べる
+func (q *Queries) ListKeyFiles(ctx context.Context, arg ListKeyFilesParams) ([]ListKeyFilesRow, error) {
+	rows, err := q.db.Query(ctx, listKeyFiles, arg.RowLimit, arg.ProductID, arg.SnapshotID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListKeyFilesRow{}
+	for rows.Next() {
+		var i ListKeyFilesRow
+		if err := rows.Scan(
+			&i.SourceName,
+			&i.Path,
+			&i.Language,
+			&i.Domain,
+			&i.IsEntryPoint,
+			&i.Importance,
+			&i.Owners,
+			&i.Summary,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateFileOwners = `-- name: UpdateFileOwners :exec
+UPDATE files f
+SET owners = string_to_array(v.owners, ' ')
+FROM (
+    SELECT unnest($2::text[]) AS path, unnest($3::text[]) AS owners
+) v
+WHERE f.snapshot_id = $1 AND f.path = v.path
+`
+
+type UpdateFileOwnersParams struct {
+	SnapshotID pgtype.UUID `json:"snapshot_id"`
+	Paths      []string    `json:"paths"`
+	Owners     []string    `json:"owners"`
+}
+
+// CODEOWNERS から判定した担当者をファイルに設定する（担当者は空白区切りで1ファイル1要素として渡す）
+func (q *Queries) UpdateFileOwners(ctx context.Context, arg UpdateFileOwnersParams) error {
+	_, err := q.db.Exec(ctx, updateFileOwners, arg.SnapshotID, arg.Paths, arg.Owners)
+	return err
+}
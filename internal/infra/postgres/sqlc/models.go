@@ -39,6 +39,29 @@ type ActionBacklog struct {
 	CompletedAt pgtype.Timestamp `json:"completed_at"`
 }
 
+// OpenAPI（Swagger）・Protocol Buffers の定義から抽出したエンドポイント
+type ApiEndpoint struct {
+	ID         pgtype.UUID `json:"id"`
+	SnapshotID pgtype.UUID `json:"snapshot_id"`
+	FileID     pgtype.UUID `json:"file_id"`
+	FilePath   string      `json:"file_path"`
+	// エンドポイントの種類（http: OpenAPI または google.api.http, grpc: service の RPC）
+	Kind string `json:"kind"`
+	// OpenAPI の最初のタグ、または <package>.<service>
+	Service string `json:"service"`
+	// HTTP メソッド（GET など）または RPC 名
+	Method string `json:"method"`
+	// HTTP のパス、または /<package>.<service>/<RPC名>
+	Path        string `json:"path"`
+	OperationID string `json:"operation_id"`
+	Summary     string `json:"summary"`
+	// リクエストの型名（ストリーミングは stream を前置）
+	RequestType string `json:"request_type"`
+	// レスポンスの型名（ストリーミングは stream を前置）
+	ResponseType string           `json:"response_type"`
+	CreatedAt    pgtype.Timestamp `json:"created_at"`
+}
+
 // システム全体のアーキテクチャ要約（LLMが生成）
 type ArchitectureSummary struct {
 	// 要約の一意識別子
@@ -57,9 +80,58 @@ type ArchitectureSummary struct {
 	UpdatedAt pgtype.Timestamp `json:"updated_at"`
 }
 
+// チャットで回答した質問（リアクションによる評価の対象）
+type AskAnswer struct {
+	ID        pgtype.UUID `json:"id"`
+	ProductID pgtype.UUID `json:"product_id"`
+	// 回答した経路（slack）
+	Origin string `json:"origin"`
+	// 回答を投稿したチャンネルのID
+	ChannelID string `json:"channel_id"`
+	// 回答のメッセージの識別子（Slack のメッセージの ts）
+	MessageTs string `json:"message_ts"`
+	// 質問したユーザーのID
+	UserID string `json:"user_id"`
+	Query  string `json:"query"`
+	Answer string `json:"answer"`
+	// ガードレールにより回答を拒否したかどうか
+	Refused bool `json:"refused"`
+	// 回答の生成に使用したプロンプトテンプレートのバージョン
+	PromptVersion string           `json:"prompt_version"`
+	CreatedAt     pgtype.Timestamp `json:"created_at"`
+}
+
+// 質問応答の回答のキャッシュ（よくある質問で検索とLLMの呼び出しを繰り返さないようにする）
+type AskCache struct {
+	// 検索範囲・正規化した質問文・プロンプトテンプレートのバージョン・モデル・検索条件の SHA-256
+	Key        string      `json:"key"`
+	ProductID  pgtype.UUID `json:"product_id"`
+	SnapshotID pgtype.UUID `json:"snapshot_id"`
+	// 保存時に検索対象だったスナップショット（再インデックスで変わった回答は使用しない）
+	ScopeSnapshotIds []pgtype.UUID `json:"scope_snapshot_ids"`
+	PromptVersion    string        `json:"prompt_version"`
+	// 正規化した質問文（小文字化・空白の統一・末尾の句読点の除去）
+	Question string `json:"question"`
+	// 回答・参照したソース・引用の検証結果（JSON）
+	Result []byte `json:"result"`
+	// キャッシュした回答を使用した回数
+	HitCount  int32            `json:"hit_count"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+	LastHitAt pgtype.Timestamp `json:"last_hit_at"`
+}
+
+// 回答へのユーザーの評価（👍 は 1、👎 は -1）
+type AskFeedback struct {
+	AnswerID pgtype.UUID `json:"answer_id"`
+	UserID   string      `json:"user_id"`
+	// 評価（1: 役に立った、-1: 役に立たなかった）
+	Rating    int16            `json:"rating"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+}
+
 // ファイルを分割したチャンク
 type Chunk struct {
-	// チャンクの一意識別子
+	// チャンクの一意識別子（インデックス化では chunk_key から決定的に生成する）
 	ID pgtype.UUID `json:"id"`
 	// 所属するファイルのID
 	FileID pgtype.UUID `json:"file_id"`
@@ -97,6 +169,14 @@ type Chunk struct {
 	CyclomaticComplexity pgtype.Int4 `json:"cyclomatic_complexity"`
 	// Embedding生成用の拡張コンテキスト
 	EmbeddingContext pgtype.Text `json:"embedding_context"`
+	// Embeddingモデルの最大入力トークン数に合わせて embedding_context を切り詰めたか
+	EmbeddingTruncated bool `json:"embedding_truncated"`
+	// Markdownのチャンクが属する見出しの階層（H1 > H2 > H3 を " > " で連結）
+	Breadcrumb pgtype.Text `json:"breadcrumb"`
+	// PDF・Office文書から抽出したチャンクの開始ページ（xlsx はシートの番号）
+	PageStart pgtype.Int4 `json:"page_start"`
+	// PDF・Office文書から抽出したチャンクの終了ページ
+	PageEnd pgtype.Int4 `json:"page_end"`
 	// 階層レベル（1:ファイルサマリー, 2:関数/クラス, 3:ロジック単位）
 	Level int32 `json:"level"`
 	// 重要度スコア（0.0000〜1.0000、参照回数・中心性・編集頻度から算出）
@@ -121,8 +201,10 @@ type Chunk struct {
 	// 最新バージョンフラグ（true=最新、false=過去バージョン）
 	IsLatest bool `json:"is_latest"`
 	// 決定的な識別子（{product_name}/{source_name}/{file_path}#L{start}-L{end}@{commit_hash}）
-	ChunkKey  string           `json:"chunk_key"`
-	CreatedAt pgtype.Timestamp `json:"created_at"`
+	ChunkKey string `json:"chunk_key"`
+	// チャンクを作成したチャンカーの実装とバージョン（例: go-ast-v1, markdown-v1、記録前に作成したチャンクは NULL）
+	ChunkerVersion pgtype.Text      `json:"chunker_version"`
+	CreatedAt      pgtype.Timestamp `json:"created_at"`
 }
 
 // チャンク間の依存関係を管理するテーブル
@@ -139,6 +221,16 @@ type ChunkDependency struct {
 	CreatedAt pgtype.Timestamp `json:"created_at"`
 }
 
+// 追加のEmbeddingモデルで生成したチャンクのEmbedding（主モデルは embeddings）
+type ChunkEmbedding struct {
+	ChunkID pgtype.UUID `json:"chunk_id"`
+	// Embeddingの生成に使用したモデル（embedding_models に登録済み）
+	Model string `json:"model"`
+	// Embeddingベクトル（次元数はモデルごとに異なる）
+	Vector    pgvector_go.Vector `json:"vector"`
+	CreatedAt pgtype.Timestamp   `json:"created_at"`
+}
+
 // チャンクの親子関係を管理する中間テーブル（階層構造の単一の真実源）
 type ChunkHierarchy struct {
 	// 親チャンクのID
@@ -151,6 +243,66 @@ type ChunkHierarchy struct {
 	CreatedAt pgtype.Timestamp `json:"created_at"`
 }
 
+// チャンクの重要度（importance_score）の内訳
+type ChunkImportance struct {
+	ChunkID pgtype.UUID `json:"chunk_id"`
+	// グラフスコアの算出に使用したアルゴリズム（pagerank, betweenness, in_degree）
+	Algorithm string `json:"algorithm"`
+	// 依存関係グラフから求めたスコア
+	GraphScore pgtype.Numeric `json:"graph_score"`
+	// 編集頻度（スナップショット間で内容が変更された回数）から求めたスコア
+	EditScore pgtype.Numeric `json:"edit_score"`
+	// グラフスコアと編集頻度スコアの加重和
+	FinalScore pgtype.Numeric `json:"final_score"`
+	// スナップショット間で内容が変更された回数
+	EditCount    int32            `json:"edit_count"`
+	CalculatedAt pgtype.Timestamp `json:"calculated_at"`
+}
+
+// 関数・クラス単位のチャンクごとのテストカバレッジ（計測単位の開始行がチャンクの行範囲に含まれるものを集計）
+type ChunkTestCoverage struct {
+	ChunkID           pgtype.UUID `json:"chunk_id"`
+	Statements        int32       `json:"statements"`
+	CoveredStatements int32       `json:"covered_statements"`
+}
+
+// スナップショットごとのドメイン別カバレッジの履歴（カバレッジの推移の表示に使用）
+type CoverageHistory struct {
+	SnapshotID pgtype.UUID `json:"snapshot_id"`
+	// ドメイン分類（code, tests, ops, infra, architecture, 未分類は unknown）
+	Domain string `json:"domain"`
+	// スナップショットのファイル数（インデックス対象外を含む）
+	TotalFiles int32 `json:"total_files"`
+	// インデックス済みのファイル数
+	IndexedFiles int32 `json:"indexed_files"`
+	// インデックス済みのチャンク数
+	IndexedChunks int32 `json:"indexed_chunks"`
+	// カバレッジ率（%）
+	CoverageRate pgtype.Numeric `json:"coverage_rate"`
+	// 記録日時（インデックス完了日時）
+	RecordedAt pgtype.Timestamp `json:"recorded_at"`
+}
+
+// リポジトリ内の設計判断の記録（ADR・RFC）から抽出したメタデータ
+type DecisionRecord struct {
+	ID         pgtype.UUID `json:"id"`
+	SnapshotID pgtype.UUID `json:"snapshot_id"`
+	FileID     pgtype.UUID `json:"file_id"`
+	FilePath   string      `json:"file_path"`
+	// ファイル名またはタイトルから抽出したADR・RFCの番号
+	Number pgtype.Int4 `json:"number"`
+	Title  string      `json:"title"`
+	// 正規化したステータス（proposed, accepted, rejected, deprecated, superseded, unknown）
+	Status string `json:"status"`
+	// 記載どおりのステータス（Superseded by ADR-0007 など）
+	StatusText string `json:"status_text"`
+	// 決定日
+	DecidedOn pgtype.Date `json:"decided_on"`
+	// 決定内容のセクション（ない場合は概要）
+	Decision  string           `json:"decision"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+}
+
 // ディレクトリごとの要約（LLMが生成）
 type DirectorySummary struct {
 	// 要約の一意識別子
@@ -173,6 +325,28 @@ type DirectorySummary struct {
 	UpdatedAt pgtype.Timestamp `json:"updated_at"`
 }
 
+// GitHub・GitLab の Issue・Pull Request（Merge Request）の議論から抽出したメタデータ
+type Discussion struct {
+	ID         pgtype.UUID `json:"id"`
+	SnapshotID pgtype.UUID `json:"snapshot_id"`
+	FileID     pgtype.UUID `json:"file_id"`
+	FilePath   string      `json:"file_path"`
+	// 議論の種類（issue, pull_request: GitLab の Merge Request を含む）
+	Kind string `json:"kind"`
+	// Issue・Pull Request の番号（GitLab は iid）
+	Number int32  `json:"number"`
+	Title  string `json:"title"`
+	State  string `json:"state"`
+	Url    string `json:"url"`
+	// 説明文の冒頭
+	Summary string `json:"summary"`
+	// 議論で言及されたファイルパス（変更ファイル・レビューコメントの対象・本文中のパス）
+	Paths []string `json:"paths"`
+	// 議論の最終更新日時
+	LastActivityAt pgtype.Timestamp `json:"last_activity_at"`
+	CreatedAt      pgtype.Timestamp `json:"created_at"`
+}
+
 // チャンクのEmbeddingベクトル
 type Embedding struct {
 	// チャンクID（主キー兼外部キー）
@@ -184,6 +358,51 @@ type Embedding struct {
 	CreatedAt pgtype.Timestamp `json:"created_at"`
 }
 
+// Embedding を生成できなかったチャンク（index retry-embeddings で再試行する）
+type EmbeddingFailure struct {
+	ChunkID pgtype.UUID `json:"chunk_id"`
+	// 最後に失敗したときのエラー
+	Error string `json:"error"`
+	// 失敗した回数（インデックス化と再試行の合計）
+	Attempts int32 `json:"attempts"`
+	// 最初に失敗した日時
+	FirstFailedAt pgtype.Timestamp `json:"first_failed_at"`
+	// 最後に失敗した日時
+	LastFailedAt pgtype.Timestamp `json:"last_failed_at"`
+}
+
+// 使用を許可するEmbeddingモデルと出力するベクトルの次元数
+type EmbeddingModel struct {
+	Model    string `json:"model"`
+	Provider string `json:"provider"`
+	// モデルが出力するベクトルの次元数（variable_dimension の場合は最大の次元数）
+	Dimension int32 `json:"dimension"`
+	// dimension 以下の任意の次元数を指定して出力できるか（text-embedding-3 系など）
+	VariableDimension bool             `json:"variable_dimension"`
+	CreatedAt         pgtype.Timestamp `json:"created_at"`
+}
+
+// スナップショットの依存関係のマニフェストとサブモジュールから抽出した外部の依存関係
+type ExternalDependency struct {
+	ID         pgtype.UUID `json:"id"`
+	SnapshotID pgtype.UUID `json:"snapshot_id"`
+	// 依存関係を記述したファイルのパス（go.mod・package.json・requirements*.txt・.gitmodules）
+	ManifestPath string `json:"manifest_path"`
+	// 依存関係を管理する仕組み（go, npm, pypi, submodule）
+	Ecosystem string `json:"ecosystem"`
+	// モジュールパス・パッケージ名（サブモジュールは配置するパス）
+	Name string `json:"name"`
+	// 要求するバージョン（サブモジュールは追跡するブランチ。指定がない場合は空）
+	Requirement string `json:"requirement"`
+	// 依存関係の種類（direct, indirect, dev）
+	Kind string `json:"kind"`
+	// vendor/modules.txt でベンダリングしているか（Go のみ）
+	Vendored bool `json:"vendored"`
+	// 取得元（サブモジュールの URL、go.mod の replace の置き換え先）
+	Location  string           `json:"location"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+}
+
 // スナップショット内のファイル・ドキュメント情報
 type File struct {
 	// ファイルの一意識別子
@@ -201,10 +420,43 @@ type File struct {
 	// プログラミング言語（go-enryによる自動検出）
 	Language pgtype.Text `json:"language"`
 	// ドメイン分類（code, architecture, ops, tests, infra）
-	Domain    pgtype.Text      `json:"domain"`
+	Domain pgtype.Text `json:"domain"`
+	// CODEOWNERS で定義された担当者（チーム・ユーザー、定義がない場合は空）
+	Owners    []string         `json:"owners"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+}
+
+// ファイルの内容（content_hash ごとに1回だけ保存する）
+type FileBlob struct {
+	// files.content_hash と同じハッシュ
+	ContentHash string `json:"content_hash"`
+	// ファイルの内容（原文）
+	Content []byte `json:"content"`
+	// 内容のサイズ（バイト）
+	Size      int64            `json:"size"`
 	CreatedAt pgtype.Timestamp `json:"created_at"`
 }
 
+// インデックス化に失敗したファイル（failures retry で再試行する）
+type FileFailure struct {
+	SnapshotID pgtype.UUID `json:"snapshot_id"`
+	FilePath   string      `json:"file_path"`
+	// 失敗した段階（create: ファイルの保存、chunk: チャンク化、store: チャンクの保存）
+	Stage string `json:"stage"`
+	// 最後に失敗したときのエラー
+	Error string `json:"error"`
+	// 失敗した回数（インデックス化の再開と再試行の合計）
+	Attempts int32 `json:"attempts"`
+	// 再試行に使用するファイルの内容
+	Content     string `json:"content"`
+	ContentHash string `json:"content_hash"`
+	Size        int64  `json:"size"`
+	// 最初に失敗した日時
+	FirstFailedAt pgtype.Timestamp `json:"first_failed_at"`
+	// 最後に失敗した日時
+	LastFailedAt pgtype.Timestamp `json:"last_failed_at"`
+}
+
 // ファイルごとの要約（LLMが生成）
 type FileSummary struct {
 	// 要約の一意識別子
@@ -221,6 +473,18 @@ type FileSummary struct {
 	UpdatedAt pgtype.Timestamp `json:"updated_at"`
 }
 
+// カバレッジレポートから取り込んだファイルごとのテストカバレッジ（スナップショット単位で置き換える）
+type FileTestCoverage struct {
+	FileID     pgtype.UUID `json:"file_id"`
+	SnapshotID pgtype.UUID `json:"snapshot_id"`
+	// 計測対象のステートメント数（LCOV は計測対象の行数）
+	Statements int32 `json:"statements"`
+	// テストで1回以上実行されたステートメント数
+	CoveredStatements int32            `json:"covered_statements"`
+	Format            string           `json:"format"`
+	ImportedAt        pgtype.Timestamp `json:"imported_at"`
+}
+
 // Git専用の参照（ブランチ、タグ）管理
 type GitRef struct {
 	// Git参照の一意識別子
@@ -237,6 +501,133 @@ type GitRef struct {
 	UpdatedAt pgtype.Timestamp `json:"updated_at"`
 }
 
+// インデックス済みのドキュメント・コードからLLMで抽出したプロダクトの用語集
+type Glossary struct {
+	ID        pgtype.UUID `json:"id"`
+	ProductID pgtype.UUID `json:"product_id"`
+	Term      string      `json:"term"`
+	// 重複判定用に小文字化した用語
+	NormalizedTerm string `json:"normalized_term"`
+	// 用語の種類（term: ドメイン用語, acronym: 略語, entity: エンティティ）
+	Kind       string `json:"kind"`
+	Definition string `json:"definition"`
+	// 別名・略語の展開形
+	Aliases []string `json:"aliases"`
+	// 抽出元のファイルパス
+	SourcePaths []string         `json:"source_paths"`
+	CreatedAt   pgtype.Timestamp `json:"created_at"`
+	// 最後に抽出された日時
+	UpdatedAt pgtype.Timestamp `json:"updated_at"`
+}
+
+// スナップショットに含まれる Go モジュール（go.mod）
+type GoModule struct {
+	ID         pgtype.UUID `json:"id"`
+	SnapshotID pgtype.UUID `json:"snapshot_id"`
+	// module ディレクティブのモジュールパス
+	ModulePath string `json:"module_path"`
+	// go.mod を置いたディレクトリ（リポジトリのルートは "."）
+	Dir       string           `json:"dir"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+}
+
+// インデックス化の実行履歴（実行ごとの処理件数・失敗件数・トークン使用量）
+type IndexRun struct {
+	ID                pgtype.UUID `json:"id"`
+	ProductID         pgtype.UUID `json:"product_id"`
+	SourceID          pgtype.UUID `json:"source_id"`
+	SnapshotID        pgtype.UUID `json:"snapshot_id"`
+	Ref               pgtype.Text `json:"ref"`
+	VersionIdentifier pgtype.Text `json:"version_identifier"`
+	// 実行状態（running: 実行中または中断、succeeded: 成功、failed: 失敗）
+	Status         string `json:"status"`
+	ForceInit      bool   `json:"force_init"`
+	FilesProcessed int32  `json:"files_processed"`
+	Chunks         int32  `json:"chunks"`
+	// チャンク化・保存に失敗したファイル数
+	FailedFiles int32 `json:"failed_files"`
+	// Embeddingの生成・保存に失敗したチャンク数（embedding_failures に記録したもの）
+	FailedEmbeddings int32 `json:"failed_embeddings"`
+	// 実行中に消費した入力トークン数（Embedding・分類など、インデックス化後の要約生成は含まない）
+	InputTokens  int64 `json:"input_tokens"`
+	OutputTokens int64 `json:"output_tokens"`
+	// 単価表から算出した推定コスト（USD）
+	CostUsd    float64          `json:"cost_usd"`
+	Error      pgtype.Text      `json:"error"`
+	StartedAt  pgtype.Timestamp `json:"started_at"`
+	FinishedAt pgtype.Timestamp `json:"finished_at"`
+	DurationMs pgtype.Int8      `json:"duration_ms"`
+}
+
+// Dockerfile・Docker Compose・Kubernetes マニフェストから抽出したデプロイ構成
+type InfraResource struct {
+	ID         pgtype.UUID `json:"id"`
+	SnapshotID pgtype.UUID `json:"snapshot_id"`
+	FileID     pgtype.UUID `json:"file_id"`
+	FilePath   string      `json:"file_path"`
+	// 定義ファイルの形式（dockerfile, compose, kubernetes）
+	Format string `json:"format"`
+	// リソースの種類（Dockerfile、Docker Compose の service、Kubernetes の kind）
+	Kind string `json:"kind"`
+	// リソース名（Dockerfile はファイルパス）
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	// レプリカ数・Service の type とポート・コンテナ（イメージ・ポート・環境変数・リソース制限）・起動コマンド
+	Spec      []byte           `json:"spec"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+}
+
+// スナップショットのライセンスファイル・ライセンスヘッダーから検出したライセンス
+type LicenseFinding struct {
+	ID         pgtype.UUID `json:"id"`
+	SnapshotID pgtype.UUID `json:"snapshot_id"`
+	FilePath   string      `json:"file_path"`
+	// 検出した場所（file: LICENSE・COPYING などのライセンスファイル, header: ソースファイル冒頭のライセンスヘッダー）
+	Kind string `json:"kind"`
+	// SPDX のライセンス識別子・式（判別できないライセンスファイルは空）
+	LicenseID string `json:"license_id"`
+	// ライセンスの分類（permissive, weak_copyleft, copyleft, unknown）
+	Category string `json:"category"`
+	// 検出の根拠とした行（SPDX-License-Identifier の行、ライセンスの文言を含む行）
+	Evidence  string           `json:"evidence"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+}
+
+// LLMの応答のキャッシュ（同じ入力の要約・Wikiページの生成で再び課金されないようにする）
+type LlmCache struct {
+	// プロンプトテンプレートの名前・バージョン、モデル、最大出力トークン数、展開したプロンプトの SHA-256
+	Key string `json:"key"`
+	// プロンプトテンプレート名（summary.file, wiki.section など）
+	PromptName string `json:"prompt_name"`
+	// プロンプトテンプレートのバージョン
+	PromptVersion string `json:"prompt_version"`
+	// 応答を生成したモデル名
+	Model    string `json:"model"`
+	Response string `json:"response"`
+	// キャッシュした応答を使用した回数
+	HitCount  int32            `json:"hit_count"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+	// 最後にキャッシュした応答を使用した日時
+	LastHitAt pgtype.Timestamp `json:"last_hit_at"`
+}
+
+// スナップショットから検出したモノレポのサービスの境界
+type MonorepoService struct {
+	ID         pgtype.UUID `json:"id"`
+	SnapshotID pgtype.UUID `json:"snapshot_id"`
+	// サービスのルートディレクトリ（リポジトリのルートからのパス）
+	Path string `json:"path"`
+	// サービス名（go.mod のモジュールパス、package.json の name、Bazel パッケージのラベル）
+	Name string `json:"name"`
+	// 境界を検出した方法（go: go.mod, npm: package.json の workspaces, bazel: BUILD ファイルの実行可能なターゲット）
+	Kind string `json:"kind"`
+	// 境界の根拠としたファイル（go.mod・package.json・BUILD・BUILD.bazel）のパス
+	ManifestPath string `json:"manifest_path"`
+	// Bazel の実行可能なターゲット（*_binary・*_image）のラベル（//path:name）
+	Targets   []string         `json:"targets"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+}
+
 // プロダクト（複数のソースをまとめる単位）
 type Product struct {
 	// プロダクトの一意識別子
@@ -247,6 +638,45 @@ type Product struct {
 	Description pgtype.Text      `json:"description"`
 	CreatedAt   pgtype.Timestamp `json:"created_at"`
 	UpdatedAt   pgtype.Timestamp `json:"updated_at"`
+	// 検索スコアに鮮度（チャンクの最終更新日時）を加味する重み（0〜1、NULLは加味しない）
+	RecencyWeight pgtype.Float8 `json:"recency_weight"`
+}
+
+// プロダクトごとの設定（カバレッジアラートの閾値など、キーと値の組）
+type ProductConfig struct {
+	ProductID pgtype.UUID `json:"product_id"`
+	// 設定キー（alert.min_coverage.<ドメイン>, alert.max_unindexed_important_files など）
+	Key string `json:"key"`
+	// 設定値（キーごとに検証した文字列）
+	Value     string           `json:"value"`
+	UpdatedAt pgtype.Timestamp `json:"updated_at"`
+}
+
+// プロダクト全体の要約（インデックス化のたびに各ソースのアーキテクチャ要約から生成し、質問の振り分けに使用する）
+type ProductSummary struct {
+	ProductID pgtype.UUID `json:"product_id"`
+	Content   string      `json:"content"`
+	// 要約の冒頭の1文（products.description が未設定または前回の自動設定のままの場合に設定する）
+	Description string `json:"description"`
+	// 入力としたアーキテクチャ要約の content_hash から計算したハッシュ（変わらない場合は再生成しない）
+	SourceHash    string `json:"source_hash"`
+	PromptVersion string `json:"prompt_version"`
+	// 要約のEmbedding（振り分けでは現在の主モデルと同じ model の行のみ使用する）
+	Vector    pgvector_go.Vector `json:"vector"`
+	Model     string             `json:"model"`
+	CreatedAt pgtype.Timestamp   `json:"created_at"`
+	UpdatedAt pgtype.Timestamp   `json:"updated_at"`
+}
+
+// LLMプロンプトテンプレートの上書き（ファイル・組み込みのテンプレートより優先する）
+type PromptTemplate struct {
+	// テンプレート名（summary.file, wiki.section, ask, quality.actions など）
+	Name string `json:"name"`
+	// 生成物（要約・アクション・Wiki・回答）に記録するプロンプトのバージョン
+	Version string `json:"version"`
+	// text/template 形式のテンプレート本文
+	Body      string           `json:"body"`
+	UpdatedAt pgtype.Timestamp `json:"updated_at"`
 }
 
 // RAG回答の品質フィードバックを記録するテーブル
@@ -273,6 +703,47 @@ type QualityNote struct {
 	ResolvedAt pgtype.Timestamp `json:"resolved_at"`
 }
 
+// 2つの ref の間の変更からLLMで生成したリリースノートの下書き
+type ReleaseNote struct {
+	ID       pgtype.UUID `json:"id"`
+	SourceID pgtype.UUID `json:"source_id"`
+	// 比較元の ref（タグ・ブランチ・コミットハッシュ）
+	FromRef string `json:"from_ref"`
+	// 比較先の ref（タグ・ブランチ・コミットハッシュ）
+	ToRef string `json:"to_ref"`
+	// from_ref が指していたコミットハッシュ
+	FromCommit string `json:"from_commit"`
+	// to_ref が指していたコミットハッシュ
+	ToCommit string `json:"to_commit"`
+	// to のコミット日時（Wikiのページの並び順）
+	ReleasedAt pgtype.Timestamp `json:"released_at"`
+	// 比較範囲のコミット数
+	CommitCount int32 `json:"commit_count"`
+	// 比較範囲で変更されたファイル数
+	FileCount int32 `json:"file_count"`
+	// リリースノートの本文（Markdown）
+	Content string `json:"content"`
+	// 生成に使用したプロンプトテンプレートのバージョン
+	PromptVersion string           `json:"prompt_version"`
+	CreatedAt     pgtype.Timestamp `json:"created_at"`
+	UpdatedAt     pgtype.Timestamp `json:"updated_at"`
+}
+
+// SQLのスキーマ定義・マイグレーションファイルから構築したテーブルのカタログ
+type SchemaTable struct {
+	ID         pgtype.UUID `json:"id"`
+	SnapshotID pgtype.UUID `json:"snapshot_id"`
+	Name       string      `json:"name"`
+	Comment    string      `json:"comment"`
+	// カラムの一覧（名前・型・NOT NULL・主キー・デフォルト値・参照先・コメント）
+	Columns []byte `json:"columns"`
+	// インデックス・UNIQUE 制約の一覧（名前・対象・アクセスメソッド・部分インデックスの条件）
+	Indexes []byte `json:"indexes"`
+	// テーブルを定義・変更したファイル（適用順）
+	SourcePaths []string         `json:"source_paths"`
+	CreatedAt   pgtype.Timestamp `json:"created_at"`
+}
+
 type SnapshotFile struct {
 	ID         pgtype.UUID      `json:"id"`
 	SnapshotID pgtype.UUID      `json:"snapshot_id"`
@@ -284,6 +755,21 @@ type SnapshotFile struct {
 	CreatedAt  pgtype.Timestamp `json:"created_at"`
 }
 
+// スナップショットごとのLLM/Embedding使用量と推定コスト
+type SnapshotUsage struct {
+	SnapshotID pgtype.UUID `json:"snapshot_id"`
+	// 用途（embedding/summary/wiki/ask）
+	Kind string `json:"kind"`
+	// 使用したモデル名
+	Model        string `json:"model"`
+	Requests     int32  `json:"requests"`
+	InputTokens  int64  `json:"input_tokens"`
+	OutputTokens int64  `json:"output_tokens"`
+	// 単価表から算出した推定コスト（USD）
+	CostUsd   float64          `json:"cost_usd"`
+	UpdatedAt pgtype.Timestamp `json:"updated_at"`
+}
+
 // ドキュメント・コードのソース情報（Git、Confluence、PDFなど）
 type Source struct {
 	// ソースの一意識別子
@@ -292,7 +778,7 @@ type Source struct {
 	ProductID pgtype.UUID `json:"product_id"`
 	// ソース名（一意）
 	Name string `json:"name"`
-	// ソースタイプ（git/confluence/pdf/redmine/notion/local）
+	// ソースタイプ（git/confluence/pdf/redmine/notion/local/discussion）
 	SourceType string `json:"source_type"`
 	// ソースタイプ固有の情報（JSONBフォーマット）。例: Gitの場合 {"url": "git@github.com:...", "default_branch": "main"}、Confluenceの場合 {"base_url": "https://...", "space_key": "..."}
 	Metadata  []byte           `json:"metadata"`
@@ -308,11 +794,17 @@ type SourceSnapshot struct {
 	SourceID pgtype.UUID `json:"source_id"`
 	// バージョン識別子（Gitの場合はcommit_hash、Confluenceの場合はpage_version、PDFの場合はfile_hash等）
 	VersionIdentifier string `json:"version_identifier"`
-	// インデックス完了フラグ
-	Indexed bool `json:"indexed"`
 	// インデックス完了日時
 	IndexedAt pgtype.Timestamp `json:"indexed_at"`
 	CreatedAt pgtype.Timestamp `json:"created_at"`
+	// インデックス化に失敗・中断した日時（完了時に NULL に戻す）
+	FailedAt pgtype.Timestamp `json:"failed_at"`
+	// インデックス化に失敗・中断した理由
+	FailureReason pgtype.Text `json:"failure_reason"`
+	// インデックス化の状態（pending: 未着手、indexing: インデックス化中、indexed: 完了、failed: 失敗・中断）
+	Status string `json:"status"`
+	// 最後にインデックス化を開始した日時
+	IndexingStartedAt pgtype.Timestamp `json:"indexing_started_at"`
 }
 
 // 階層的要約（ファイル/ディレクトリ/アーキテクチャ）
@@ -344,6 +836,24 @@ type SummaryEmbedding struct {
 	CreatedAt pgtype.Timestamp   `json:"created_at"`
 }
 
+// チャンクのメタデータから抽出したシンボル（関数・メソッド・型など）の定義位置
+type Symbol struct {
+	ID         pgtype.UUID `json:"id"`
+	SnapshotID pgtype.UUID `json:"snapshot_id"`
+	ChunkID    pgtype.UUID `json:"chunk_id"`
+	FileID     pgtype.UUID `json:"file_id"`
+	FilePath   string      `json:"file_path"`
+	// シンボル名（chunks.chunk_name）
+	Name string `json:"name"`
+	// シンボルの種類（chunks.chunk_type）
+	Kind string `json:"kind"`
+	// 所属する型名など（chunks.parent_name、ポインタの * は除く）
+	ParentName string           `json:"parent_name"`
+	StartLine  int32            `json:"start_line"`
+	EndLine    int32            `json:"end_line"`
+	CreatedAt  pgtype.Timestamp `json:"created_at"`
+}
+
 // Wiki生成の実行履歴とメタデータ（プロダクト単位のみ）
 type WikiMetadatum struct {
 	// Wiki生成レコードの一意識別子
@@ -358,3 +868,27 @@ type WikiMetadatum struct {
 	GeneratedAt pgtype.Timestamp `json:"generated_at"`
 	CreatedAt   pgtype.Timestamp `json:"created_at"`
 }
+
+// 生成したWikiページと公開先のリモートページの対応
+type WikiPublication struct {
+	ID        pgtype.UUID `json:"id"`
+	ProductID pgtype.UUID `json:"product_id"`
+	// 公開先の種類（confluence）
+	Target string `json:"target"`
+	// 公開先（Confluenceのスペースキーなど）
+	Destination string `json:"destination"`
+	// Wikiの言語（ja / en など、--lang を指定せずに生成したWikiは ja）
+	Language string `json:"language"`
+	// Wiki出力ディレクトリからの相対パス（ディレクトリのページは末尾 /）
+	PagePath string `json:"page_path"`
+	// リモートのページID
+	RemoteID string `json:"remote_id"`
+	// 最後に公開したリモートのページのバージョン
+	RemoteVersion  int32       `json:"remote_version"`
+	ParentRemoteID pgtype.Text `json:"parent_remote_id"`
+	Title          string      `json:"title"`
+	// 公開した内容（タイトル・親・本文）のSHA-256ハッシュ
+	ContentHash string           `json:"content_hash"`
+	PublishedAt pgtype.Timestamp `json:"published_at"`
+	CreatedAt   pgtype.Timestamp `json:"created_at"`
+}
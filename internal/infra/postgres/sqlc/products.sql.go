@@ -14,7 +14,7 @@ import (
 const createProduct = `-- name: CreateProduct :one
 INSERT INTO products (name, description)
 VALUES ($1, $2)
-RETURNING id, name, description, created_at, updated_at
+RETURNING id, name, description, created_at, updated_at, recency_weight
 `
 
 type CreateProductParams struct {
@@ -31,6 +31,7 @@ func (q *Queries) CreateProduct(ctx context.Context, arg CreateProductParams) (P
 		&i.Description,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.RecencyWeight,
 	)
 	return i, err
 }
@@ -46,7 +47,7 @@ func (q *Queries) DeleteProduct(ctx context.Context, id pgtype.UUID) error {
 }
 
 const getProduct = `-- name: GetProduct :one
-SELECT id, name, description, created_at, updated_at FROM products
+SELECT id, name, description, created_at, updated_at, recency_weight FROM products
 WHERE id = $1
 `
 
@@ -59,12 +60,13 @@ func (q *Queries) GetProduct(ctx context.Context, id pgtype.UUID) (Product, erro
 		&i.Description,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.RecencyWeight,
 	)
 	return i, err
 }
 
 const getProductByName = `-- name: GetProductByName :one
-SELECT id, name, description, created_at, updated_at FROM products
+SELECT id, name, description, created_at, updated_at, recency_weight FROM products
 WHERE name = $1
 `
 
@@ -77,12 +79,13 @@ func (q *Queries) GetProductByName(ctx context.Context, name string) (Product, e
 		&i.Description,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.RecencyWeight,
 	)
 	return i, err
 }
 
 const listProducts = `-- name: ListProducts :many
-SELECT id, name, description, created_at, updated_at FROM products
+SELECT id, name, description, created_at, updated_at, recency_weight FROM products
 ORDER BY created_at DESC
 `
 
@@ -101,6 +104,7 @@ func (q *Queries) ListProducts(ctx context.Context) ([]Product, error) {
 			&i.Description,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.RecencyWeight,
 		); err != nil {
 			return nil, err
 		}
@@ -121,12 +125,14 @@ SELECT
     p.updated_at,
     COUNT(DISTINCT s.id)::int AS source_count,
     MAX(ss.indexed_at) AS last_indexed_at,
-    MAX(wm.generated_at) AS wiki_generated_at
+    MAX(wm.generated_at) AS wiki_generated_at,
+    ps.content AS summary
 FROM products p
 LEFT JOIN sources s ON p.id = s.product_id
-LEFT JOIN source_snapshots ss ON s.id = ss.source_id AND ss.indexed = TRUE
+LEFT JOIN source_snapshots ss ON s.id = ss.source_id AND ss.status = 'indexed'
 LEFT JOIN wiki_metadata wm ON p.id = wm.product_id
-GROUP BY p.id, p.name, p.description, p.created_at, p.updated_at
+LEFT JOIN product_summaries ps ON p.id = ps.product_id
+GROUP BY p.id, p.name, p.description, p.created_at, p.updated_at, ps.content
 ORDER BY p.name
 `
 
@@ -139,6 +145,7 @@ type ListProductsWithStatsRow struct {
 	SourceCount     int32            `json:"source_count"`
 	LastIndexedAt   interface{}      `json:"last_indexed_at"`
 	WikiGeneratedAt interface{}      `json:"wiki_generated_at"`
+	Summary         pgtype.Text      `json:"summary"`
 }
 
 func (q *Queries) ListProductsWithStats(ctx context.Context) ([]ListProductsWithStatsRow, error) {
@@ -159,6 +166,7 @@ func (q *Queries) ListProductsWithStats(ctx context.Context) ([]ListProductsWith
 			&i.SourceCount,
 			&i.LastIndexedAt,
 			&i.WikiGeneratedAt,
+			&i.Summary,
 		); err != nil {
 			return nil, err
 		}
@@ -174,7 +182,7 @@ const updateProduct = `-- name: UpdateProduct :one
 UPDATE products
 SET name = $2, description = $3, updated_at = CURRENT_TIMESTAMP
 WHERE id = $1
-RETURNING id, name, description, created_at, updated_at
+RETURNING id, name, description, created_at, updated_at, recency_weight
 `
 
 type UpdateProductParams struct {
@@ -192,6 +200,33 @@ func (q *Queries) UpdateProduct(ctx context.Context, arg UpdateProductParams) (P
 		&i.Description,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.RecencyWeight,
+	)
+	return i, err
+}
+
+const updateProductRecencyWeight = `-- name: UpdateProductRecencyWeight :one
+UPDATE products
+SET recency_weight = $1, updated_at = CURRENT_TIMESTAMP
+WHERE id = $2
+RETURNING id, name, description, created_at, updated_at, recency_weight
+`
+
+type UpdateProductRecencyWeightParams struct {
+	RecencyWeight pgtype.Float8 `json:"recency_weight"`
+	ID            pgtype.UUID   `json:"id"`
+}
+
+func (q *Queries) UpdateProductRecencyWeight(ctx context.Context, arg UpdateProductRecencyWeightParams) (Product, error) {
+	row := q.db.QueryRow(ctx, updateProductRecencyWeight, arg.RecencyWeight, arg.ID)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.RecencyWeight,
 	)
 	return i, err
 }
@@ -12,19 +12,41 @@ import (
 
 type Querier interface {
 	AddChunkRelation(ctx context.Context, arg AddChunkRelationParams) error
+	AddSnapshotUsage(ctx context.Context, arg AddSnapshotUsageParams) error
+	// チャンクの重要度スコアを1回のクエリでまとめて更新する（ID とスコアは同じ順序・同じ長さの配列で渡す）
+	BatchUpdateChunkImportanceScores(ctx context.Context, arg BatchUpdateChunkImportanceScoresParams) error
+	// チャンクの重要度の内訳を1回のクエリでまとめて保存する（各配列は同じ順序・同じ長さで、チャンクIDは重複させない）
+	BatchUpsertChunkImportance(ctx context.Context, arg BatchUpsertChunkImportanceParams) error
 	CountChildChunks(ctx context.Context, parentChunkID pgtype.UUID) (int64, error)
 	// 指定日数以上古いチャンクの数を取得
 	CountStaleChunks(ctx context.Context, dollar_1 interface{}) (int64, error)
 	CountSummariesByType(ctx context.Context, arg CountSummariesByTypeParams) (int64, error)
 	CountSummaryEmbeddingsBySnapshot(ctx context.Context, snapshotID pgtype.UUID) (int64, error)
+	CreateAPIEndpoint(ctx context.Context, arg CreateAPIEndpointParams) error
+	CreateActionBacklog(ctx context.Context, arg CreateActionBacklogParams) (ActionBacklog, error)
+	CreateAskAnswer(ctx context.Context, arg CreateAskAnswerParams) (AskAnswer, error)
 	CreateChunk(ctx context.Context, arg CreateChunkParams) (Chunk, error)
 	CreateChunkBatch(ctx context.Context, arg []CreateChunkBatchParams) (int64, error)
+	CreateChunkTestCoverage(ctx context.Context, arg CreateChunkTestCoverageParams) error
 	CreateDependency(ctx context.Context, arg CreateDependencyParams) error
 	CreateEmbedding(ctx context.Context, arg CreateEmbeddingParams) (Embedding, error)
 	CreateEmbeddingBatch(ctx context.Context, arg []CreateEmbeddingBatchParams) *CreateEmbeddingBatchBatchResults
+	// インデックス化で生成した Embedding を COPY でまとめて保存する（vector 型をコネクションに登録する必要がある）
+	CreateEmbeddingCopy(ctx context.Context, arg []CreateEmbeddingCopyParams) (int64, error)
+	CreateExternalDependency(ctx context.Context, arg CreateExternalDependencyParams) error
+	// スナップショットの再実行（再開・--force-init）で登録済みのファイルは、ID を変えずに内容の情報を置き換える
 	CreateFile(ctx context.Context, arg CreateFileParams) (File, error)
+	CreateFileTestCoverage(ctx context.Context, arg CreateFileTestCoverageParams) error
 	CreateGitRef(ctx context.Context, arg CreateGitRefParams) (GitRef, error)
+	CreateGoModule(ctx context.Context, arg CreateGoModuleParams) error
+	// インデックス化の実行を開始状態（running）で記録する
+	CreateIndexRun(ctx context.Context, arg CreateIndexRunParams) (CreateIndexRunRow, error)
+	CreateInfraResource(ctx context.Context, arg CreateInfraResourceParams) error
+	CreateLicenseFindings(ctx context.Context, arg []CreateLicenseFindingsParams) (int64, error)
+	CreateMonorepoServices(ctx context.Context, arg []CreateMonorepoServicesParams) (int64, error)
 	CreateProduct(ctx context.Context, arg CreateProductParams) (Product, error)
+	CreateQualityNote(ctx context.Context, arg CreateQualityNoteParams) (QualityNote, error)
+	CreateSchemaTable(ctx context.Context, arg CreateSchemaTableParams) error
 	// カバレッジマップ構築 - snapshot_files操作
 	CreateSnapshotFile(ctx context.Context, arg CreateSnapshotFileParams) (SnapshotFile, error)
 	CreateSource(ctx context.Context, arg CreateSourceParams) (Source, error)
@@ -32,32 +54,81 @@ type Querier interface {
 	CreateSourceSnapshot(ctx context.Context, arg CreateSourceSnapshotParams) (SourceSnapshot, error)
 	CreateSummary(ctx context.Context, arg CreateSummaryParams) (Summary, error)
 	CreateSummaryEmbedding(ctx context.Context, arg CreateSummaryEmbeddingParams) (SummaryEmbedding, error)
+	// スナップショットのチャンクのうち、名前を持つ関数・メソッド・型などのチャンク（レベル2）をシンボルとして登録する
+	// Markdown の見出し（section）はシンボルとして扱わない
+	CreateSymbolsFromChunks(ctx context.Context, snapshotID pgtype.UUID) (int64, error)
 	CreateWikiMetadata(ctx context.Context, arg CreateWikiMetadataParams) (WikiMetadatum, error)
+	DeleteAPIEndpointsByFile(ctx context.Context, fileID pgtype.UUID) error
+	// 取り消したリアクションと同じ評価の場合のみ削除する（👍 と 👎 を両方付けた後に片方を外した場合に備える）
+	DeleteAskFeedback(ctx context.Context, arg DeleteAskFeedbackParams) error
 	DeleteChunk(ctx context.Context, id pgtype.UUID) error
 	DeleteChunkHierarchyByChild(ctx context.Context, childChunkID pgtype.UUID) error
 	DeleteChunkHierarchyByParent(ctx context.Context, parentChunkID pgtype.UUID) error
+	DeleteChunkTestCoverageBySnapshot(ctx context.Context, snapshotID pgtype.UUID) error
 	DeleteChunksByFile(ctx context.Context, fileID pgtype.UUID) error
 	DeleteDependenciesByChunk(ctx context.Context, fromChunkID pgtype.UUID) error
+	DeleteDependency(ctx context.Context, id pgtype.UUID) error
 	DeleteEmbedding(ctx context.Context, chunkID pgtype.UUID) error
+	DeleteEmbeddingFailures(ctx context.Context, chunkIds []pgtype.UUID) error
+	DeleteExternalDependenciesBySnapshot(ctx context.Context, snapshotID pgtype.UUID) error
 	DeleteFile(ctx context.Context, id pgtype.UUID) error
+	DeleteFileTestCoverageBySnapshot(ctx context.Context, snapshotID pgtype.UUID) error
 	DeleteFilesByPaths(ctx context.Context, arg DeleteFilesByPathsParams) error
 	DeleteFilesBySnapshot(ctx context.Context, snapshotID pgtype.UUID) error
 	DeleteGitRef(ctx context.Context, id pgtype.UUID) error
+	// 今回の抽出で見つからなかった（keep に含まれない）用語を削除する
+	DeleteGlossaryTermsExcept(ctx context.Context, arg DeleteGlossaryTermsExceptParams) (int64, error)
+	DeleteGoModulesBySnapshot(ctx context.Context, snapshotID pgtype.UUID) error
+	// 中断したスナップショットの再開前に、Embedding の保存が完了していないファイルを削除する（チャンク・Embedding は CASCADE で削除）
+	// チャンクの保存に失敗した可能性があるチャンクのないファイルも削除する
+	// Embedding の生成に失敗して embedding_failures に記録したチャンクは完了したものとして扱う
+	DeleteIncompleteSnapshotFiles(ctx context.Context, snapshotID pgtype.UUID) (int64, error)
+	DeleteInfraResourcesByFile(ctx context.Context, fileID pgtype.UUID) error
+	DeleteLLMCache(ctx context.Context, arg DeleteLLMCacheParams) (int64, error)
+	DeleteLicenseFindingsBySnapshot(ctx context.Context, snapshotID pgtype.UUID) error
+	DeleteMonorepoServicesBySnapshot(ctx context.Context, snapshotID pgtype.UUID) error
 	DeleteProduct(ctx context.Context, id pgtype.UUID) error
+	DeleteProductConfig(ctx context.Context, arg DeleteProductConfigParams) (int64, error)
+	DeletePromptTemplate(ctx context.Context, name string) (int64, error)
+	// スナップショットの記録のうち、直前の処理で再び失敗したファイル以外の記録を削除する
+	DeleteResolvedFileFailures(ctx context.Context, arg DeleteResolvedFileFailuresParams) (int64, error)
+	DeleteSchemaTablesBySnapshot(ctx context.Context, snapshotID pgtype.UUID) error
 	DeleteSource(ctx context.Context, id pgtype.UUID) error
 	DeleteSourceSnapshot(ctx context.Context, id pgtype.UUID) error
+	// スナップショットの再実行で、ファイルのチャンクのうち今回作成しなかったもの（keep_ids 以外）を削除する
+	DeleteStaleChunksByFile(ctx context.Context, arg DeleteStaleChunksByFileParams) error
 	DeleteSummariesBySnapshot(ctx context.Context, snapshotID pgtype.UUID) error
 	DeleteSummary(ctx context.Context, id pgtype.UUID) error
 	DeleteSummaryEmbedding(ctx context.Context, summaryID pgtype.UUID) error
 	DeleteSummaryEmbeddingsBySnapshot(ctx context.Context, snapshotID pgtype.UUID) error
+	DeleteSymbolsBySnapshot(ctx context.Context, snapshotID pgtype.UUID) error
+	// スナップショット・プロダクトの削除で参照する files の行がなくなった内容を削除する
+	DeleteUnreferencedFileBlobs(ctx context.Context) (int64, error)
 	DeleteWikiMetadata(ctx context.Context, id pgtype.UUID) error
+	DeleteWikiPublication(ctx context.Context, id pgtype.UUID) error
+	// 利用者が設定した説明は上書きしない（未設定、または前回自動で設定した説明のままの場合のみ更新する）
+	FillProductDescription(ctx context.Context, arg FillProductDescriptionParams) (int64, error)
 	FindChunksByContentHash(ctx context.Context, contentHash string) ([]Chunk, error)
 	FindFilesByContentHash(ctx context.Context, contentHash string) ([]File, error)
+	// リリースノートの比較対象として、ソースのコミットのインデックス済みスナップショットを取得する
+	FindIndexedSnapshotByCommit(ctx context.Context, arg FindIndexedSnapshotByCommitParams) (pgtype.UUID, error)
+	// シンボルを名前の完全一致で検索する
+	// product_id を指定した場合は各ソースの最新のインデックス済みスナップショットを対象とする
+	// 大文字小文字まで一致するシンボルを先に、大文字小文字を区別しない一致を後に並べる
+	FindSymbols(ctx context.Context, arg FindSymbolsParams) ([]FindSymbolsRow, error)
+	// インデックス化の実行の結果を記録する（所要時間は開始日時からの経過時間）
+	FinishIndexRun(ctx context.Context, arg FinishIndexRunParams) error
 	GetAllDependencies(ctx context.Context) ([]ChunkDependency, error)
 	GetArchitectureSummary(ctx context.Context, arg GetArchitectureSummaryParams) (Summary, error)
+	GetAskAnswerByMessage(ctx context.Context, arg GetAskAnswerByMessageParams) (AskAnswer, error)
 	GetChildChunkIDs(ctx context.Context, parentChunkID pgtype.UUID) ([]pgtype.UUID, error)
 	GetChildChunks(ctx context.Context, parentChunkID pgtype.UUID) ([]Chunk, error)
 	GetChunk(ctx context.Context, id pgtype.UUID) (Chunk, error)
+	GetChunkByKey(ctx context.Context, chunkKey string) (Chunk, error)
+	GetChunkImportance(ctx context.Context, chunkID pgtype.UUID) (GetChunkImportanceRow, error)
+	// チャンクの系譜（バージョン履歴）
+	// chunk_key から系譜をたどる起点となるチャンクの識別情報を取得
+	GetChunkLineageAnchor(ctx context.Context, chunkKey string) (GetChunkLineageAnchorRow, error)
 	// インデックス鮮度の監視用クエリ
 	// 鮮度チェックのためにgit_commit_hash付きチャンクを取得
 	GetChunksWithGitInfo(ctx context.Context) ([]GetChunksWithGitInfoRow, error)
@@ -69,7 +140,11 @@ type Querier interface {
 	GetDomainCoverageBySnapshot(ctx context.Context, snapshotID pgtype.UUID) ([]GetDomainCoverageBySnapshotRow, error)
 	GetDomainCoverageStats(ctx context.Context, snapshotID pgtype.UUID) ([]GetDomainCoverageStatsRow, error)
 	GetEmbedding(ctx context.Context, chunkID pgtype.UUID) (Embedding, error)
+	// ベクトルを読み込まずに、チャンクの Embedding の生成に使用したモデルを取得する
+	GetEmbeddingModel(ctx context.Context, chunkID pgtype.UUID) (GetEmbeddingModelRow, error)
 	GetFile(ctx context.Context, id pgtype.UUID) (File, error)
+	GetFileBlob(ctx context.Context, contentHash string) ([]byte, error)
+	GetFileBlobStats(ctx context.Context) (GetFileBlobStatsRow, error)
 	GetFileByPath(ctx context.Context, arg GetFileByPathParams) (File, error)
 	GetFileHashesBySnapshot(ctx context.Context, snapshotID pgtype.UUID) ([]GetFileHashesBySnapshotRow, error)
 	GetFileSummary(ctx context.Context, arg GetFileSummaryParams) (Summary, error)
@@ -79,13 +154,24 @@ type Querier interface {
 	GetGitRefByName(ctx context.Context, arg GetGitRefByNameParams) (GitRef, error)
 	GetIncomingDependenciesByChunk(ctx context.Context, toChunkID pgtype.UUID) ([]ChunkDependency, error)
 	GetIncomingDependencyCount(ctx context.Context, toChunkID pgtype.UUID) (int64, error)
+	GetIndexRun(ctx context.Context, id pgtype.UUID) (GetIndexRunRow, error)
 	GetLatestIndexedSnapshot(ctx context.Context, sourceID pgtype.UUID) (SourceSnapshot, error)
 	GetMaxDirectoryDepth(ctx context.Context, snapshotID pgtype.UUID) (int32, error)
 	GetParentChunk(ctx context.Context, childChunkID pgtype.UUID) (Chunk, error)
 	GetParentChunkID(ctx context.Context, childChunkID pgtype.UUID) (pgtype.UUID, error)
 	GetProduct(ctx context.Context, id pgtype.UUID) (Product, error)
 	GetProductByName(ctx context.Context, name string) (Product, error)
+	GetProductSummary(ctx context.Context, productID pgtype.UUID) (ProductSummary, error)
+	GetPromptTemplate(ctx context.Context, name string) (PromptTemplate, error)
+	GetRegisteredEmbeddingModel(ctx context.Context, model string) (EmbeddingModel, error)
+	// スナップショット内のドメイン別ファイル数・チャンク数を集計
+	GetSnapshotDomainStats(ctx context.Context, snapshotID pgtype.UUID) ([]GetSnapshotDomainStatsRow, error)
 	GetSnapshotFilesBySnapshot(ctx context.Context, snapshotID pgtype.UUID) ([]SnapshotFile, error)
+	// スナップショットのインデックス品質の診断に使用する件数・トークン数を集計
+	// orphan_chunk_count は Embedding を持たないチャンク、oversized_chunk_count はトークン数が上限を超えるチャンクの数
+	GetSnapshotIndexHealth(ctx context.Context, arg GetSnapshotIndexHealthParams) (GetSnapshotIndexHealthRow, error)
+	// スナップショットのファイル数・チャンク数・Embedding数を集計
+	GetSnapshotStats(ctx context.Context, snapshotID pgtype.UUID) (GetSnapshotStatsRow, error)
 	GetSource(ctx context.Context, id pgtype.UUID) (Source, error)
 	GetSourceByName(ctx context.Context, name string) (Source, error)
 	GetSourceSnapshot(ctx context.Context, id pgtype.UUID) (SourceSnapshot, error)
@@ -97,44 +183,284 @@ type Querier interface {
 	GetUnindexedImportantFiles(ctx context.Context, snapshotID pgtype.UUID) ([]string, error)
 	GetWikiMetadata(ctx context.Context, id pgtype.UUID) (WikiMetadatum, error)
 	GetWikiMetadataByProduct(ctx context.Context, productID pgtype.UUID) (WikiMetadatum, error)
+	// チャンクの内容を正規表現（POSIX）で検索する
+	// snapshot_id を指定しない場合はプロダクトの各ソースの最新のインデックス済みスナップショットを対象とする
+	// ファイル要約（レベル1）はファイルの内容ではないため対象外
+	GrepChunks(ctx context.Context, arg GrepChunksParams) ([]GrepChunksRow, error)
 	HasChildren(ctx context.Context, parentChunkID pgtype.UUID) (bool, error)
 	HasParent(ctx context.Context, childChunkID pgtype.UUID) (bool, error)
+	// 保存後に検索対象のスナップショットが変わった（再インデックスされた）回答は使用しない
+	HitAskCache(ctx context.Context, arg HitAskCacheParams) ([]byte, error)
+	HitLLMCache(ctx context.Context, arg HitLLMCacheParams) (string, error)
+	// 同じ内容は1回だけ保存する
+	InsertFileBlob(ctx context.Context, arg InsertFileBlobParams) error
+	// エンドポイントを、プロダクトまたはスナップショットの範囲で取得する
+	// product_id を指定した場合は各ソースの最新のインデックス済みスナップショットを対象とする
+	// keyword はパス・サービス・operationId・概要の部分一致（大文字小文字を区別しない）
+	ListAPIEndpoints(ctx context.Context, arg ListAPIEndpointsParams) ([]ListAPIEndpointsRow, error)
+	// APIリファレンス生成用に関数・型などのチャンク（レベル2）のシグネチャとドキュメントコメントを取得する
+	// product_id 指定時はプロダクトの各ソースの最新スナップショット、snapshot_id 指定時はそのスナップショットを対象とする
+	// シグネチャを持たない宣言（型など）のため、内容の先頭部分も返す
+	ListAPISymbols(ctx context.Context, arg ListAPISymbolsParams) ([]ListAPISymbolsRow, error)
+	// 品質改善アクションを優先度・作成日時の順に取得する（ステータスで絞り込み）
+	ListActionBacklog(ctx context.Context, arg ListActionBacklogParams) ([]ActionBacklog, error)
 	ListArchitectureSummariesBySnapshot(ctx context.Context, snapshotID pgtype.UUID) ([]Summary, error)
+	// オンボーディングガイド用に、ビルド・テストのコマンドを検出する Makefile とCI設定ファイルのチャンクを取得する
+	// 対象スナップショットの決め方は ListAPISymbols と同じ
+	ListBuildFileChunks(ctx context.Context, arg ListBuildFileChunksParams) ([]ListBuildFileChunksRow, error)
+	// プロンプトの階層的な組み立て用に、指定チャンクの所属ファイル・親チャンク・ファイル要約を取得する
+	// 親チャンクは chunk_hierarchy を優先し、未登録の場合は同一ファイル内で parent_name と行範囲が一致する上位レベルのチャンクとする
+	ListChunkHierarchyContexts(ctx context.Context, chunkIds []pgtype.UUID) ([]ListChunkHierarchyContextsRow, error)
+	// 同一ソース・同一ファイル内で同じ関数/クラス名（名前がない場合は同じ序数）を持つチャンクをスナップショット順に取得
+	ListChunkHistory(ctx context.Context, arg ListChunkHistoryParams) ([]ListChunkHistoryRow, error)
+	// プロダクトの最新スナップショットで、ファイルの指定行を含むチャンクを内側（行範囲の狭いもの）から順に取得する
+	// 同じパスのファイルが複数のソースにある場合は、それぞれのチャンクを含める
+	ListChunksAtLine(ctx context.Context, arg ListChunksAtLineParams) ([]ListChunksAtLineRow, error)
 	ListChunksByFile(ctx context.Context, fileID pgtype.UUID) ([]Chunk, error)
 	ListChunksByOrdinalRange(ctx context.Context, arg ListChunksByOrdinalRangeParams) ([]Chunk, error)
+	// Embedding がない、または指定したモデル以外で生成したチャンクを取得する（index reembed で使用）
+	// content は Embedding の入力（切り詰めた embedding_context があればそれを優先）
+	ListChunksForReembed(ctx context.Context, arg ListChunksForReembedParams) ([]ListChunksForReembedRow, error)
+	// 指定したモデルの Embedding がないチャンクを取得する（index embed-models で使用）
+	// content は Embedding の入力（切り詰めた embedding_context があればそれを優先）
+	ListChunksWithoutModelEmbedding(ctx context.Context, arg ListChunksWithoutModelEmbeddingParams) ([]ListChunksWithoutModelEmbeddingRow, error)
+	// プロダクトの各ソースのスナップショットのドメイン別カバレッジの履歴を記録日時の古い順に取得する
+	// domain を指定した場合はそのドメインのみ、since を指定した場合はそれ以降に記録したもののみを対象とする
+	ListCoverageHistoryByProduct(ctx context.Context, arg ListCoverageHistoryByProductParams) ([]ListCoverageHistoryByProductRow, error)
+	// 同じソースの別のスナップショットのチャンクを結ぶ依存関係を取得する
+	ListDanglingDependencies(ctx context.Context) ([]ListDanglingDependenciesRow, error)
+	// 設計判断の記録を、プロダクトまたはスナップショットの範囲で取得する
+	// product_id を指定した場合は各ソースの最新のインデックス済みスナップショットを対象とする
+	ListDecisionRecords(ctx context.Context, arg ListDecisionRecordsParams) ([]ListDecisionRecordsRow, error)
+	// ファイルIDに対応する設計判断の記録のステータスを取得する（設計判断の記録でないファイルは含まれない）
+	ListDecisionStatusesByFileIDs(ctx context.Context, fileIds []pgtype.UUID) ([]ListDecisionStatusesByFileIDsRow, error)
+	// 依存関係グラフのエッジを依存元・依存先のチャンクの情報とともに取得する（graph export 用）
+	// 対象スナップショットの決め方は ListFileDependencyEdges と同じで、依存元・依存先の両方がパス・重要度の条件を満たすものに絞る
+	ListDependencyGraphEdges(ctx context.Context, arg ListDependencyGraphEdgesParams) ([]ListDependencyGraphEdgesRow, error)
+	// オンボーディングガイド用に、ソース・トップレベルディレクトリ・ドメインごとのファイル数とインデックス済みファイル数を取得する
+	// product_id 指定時はプロダクトの各ソースの最新スナップショット、snapshot_id 指定時はそのスナップショットを対象とする
+	// ルート直下のファイルのディレクトリは空文字とする
+	ListDirectoryCoverage(ctx context.Context, arg ListDirectoryCoverageParams) ([]ListDirectoryCoverageRow, error)
 	ListDirectorySummariesByDepth(ctx context.Context, arg ListDirectorySummariesByDepthParams) ([]Summary, error)
 	ListDirectorySummariesBySnapshot(ctx context.Context, snapshotID pgtype.UUID) ([]Summary, error)
+	// 指定したファイルパスに言及した議論を、最終更新日時の新しい順に取得する
+	// 対象はプロダクト（snapshot_id のみ指定した場合はそのスナップショットのプロダクト）の各ソースの最新のインデックス済みスナップショット
+	ListDiscussionsByPaths(ctx context.Context, arg ListDiscussionsByPathsParams) ([]ListDiscussionsByPathsRow, error)
+	// 記録後に db verify --fix などで Embedding が作成されたチャンクは除く
+	// content は Embedding の入力（切り詰めた embedding_context があればそれを優先）
+	ListEmbeddingFailuresByProduct(ctx context.Context, productID pgtype.UUID) ([]ListEmbeddingFailuresByProductRow, error)
+	// 再チャンク化で内容が変わらないチャンクの Embedding を再利用するために、チャンクの Embedding をまとめて取得する
+	ListEmbeddingsByChunkIDs(ctx context.Context, chunkIds []pgtype.UUID) ([]ListEmbeddingsByChunkIDsRow, error)
+	// インデックス済みスナップショットのファイルのうちチャンクを持たないものを取得する
+	ListEmptyFiles(ctx context.Context) ([]ListEmptyFilesRow, error)
+	// 外部の依存関係を、プロダクトまたはスナップショットの範囲で取得する
+	// product_id を指定した場合は各ソースの最新のインデックス済みスナップショットを対象とする
+	ListExternalDependencies(ctx context.Context, arg ListExternalDependenciesParams) ([]ListExternalDependenciesRow, error)
+	// 対象スナップショットと、同じソースの直前（作成日時が前）のインデックス済みスナップショットの組を取得する
+	// 対象スナップショットの決め方は ListExternalDependencies と同じ
+	ListExternalDependencySnapshotPairs(ctx context.Context, arg ListExternalDependencySnapshotPairsParams) ([]ListExternalDependencySnapshotPairsRow, error)
+	// 依存関係をファイル単位に集約して取得する（Wikiの図解生成用）
+	// product_id 指定時はプロダクトの各ソースの最新スナップショット、snapshot_id 指定時はそのスナップショットを対象とする
+	// product_id 指定時はソースをまたぐ依存関係も含む
+	ListFileDependencyEdges(ctx context.Context, arg ListFileDependencyEdgesParams) ([]ListFileDependencyEdgesRow, error)
+	// 再試行に使用するため、ファイルの内容とチャンクキーの生成に必要なプロダクト名・ソース名・バージョンを含める
+	ListFileFailuresByProduct(ctx context.Context, productID pgtype.UUID) ([]ListFileFailuresByProductRow, error)
+	// スナップショットのファイルのうち、現在のチャンカー以外（バージョンの記録がないものを含む）で作成したチャンクがあるファイルを取得する
+	ListFileIDsWithOutdatedChunker(ctx context.Context, arg ListFileIDsWithOutdatedChunkerParams) ([]pgtype.UUID, error)
+	// ファイルの担当者を取得する（担当者の定義がないファイルは除く）
+	ListFileOwners(ctx context.Context, fileIds []pgtype.UUID) ([]ListFileOwnersRow, error)
+	// プロダクトの各ソースの最新のインデックス済みスナップショットで、指定したパスのファイルの担当者を取得する
+	// 同じパスが複数のソースにある場合は、インデックス日時の新しいスナップショットを先に返す
+	ListFileOwnersByPaths(ctx context.Context, arg ListFileOwnersByPathsParams) ([]ListFileOwnersByPathsRow, error)
 	ListFileSummariesBySnapshot(ctx context.Context, snapshotID pgtype.UUID) ([]Summary, error)
+	// ファイルごとのテストカバレッジを取得する
+	// product_id 指定時はプロダクトの各ソースの最新のインデックス済みスナップショット、snapshot_id 指定時はそのスナップショットを対象とする
+	ListFileTestCoverage(ctx context.Context, arg ListFileTestCoverageParams) ([]ListFileTestCoverageRow, error)
 	ListFilesByContentType(ctx context.Context, arg ListFilesByContentTypeParams) ([]File, error)
 	ListFilesBySnapshot(ctx context.Context, snapshotID pgtype.UUID) ([]File, error)
 	ListGitRefsBySource(ctx context.Context, sourceID pgtype.UUID) ([]GitRef, error)
+	// 用語抽出の入力として、プロダクトの各ソースの最新スナップショットからドキュメントのチャンクと、
+	// ドキュメントコメントを持つ宣言を重要度順に取得する
+	ListGlossarySourceChunks(ctx context.Context, arg ListGlossarySourceChunksParams) ([]ListGlossarySourceChunksRow, error)
+	// プロダクトの用語集を用語順に取得する
+	ListGlossaryTerms(ctx context.Context, productID pgtype.UUID) ([]Glossary, error)
+	// ホットスポット（リスクの高い関数・ファイル）の候補として、関数・クラス単位のチャンクの複雑度・行数・コメント比率・編集回数を取得する
+	// product_id 指定時はプロダクトの各ソースの最新のインデックス済みスナップショット、snapshot_id 指定時はそのスナップショットを対象とする
+	// 編集回数は重要度の算出時に記録した値（chunk_importance.edit_count）で、未算出のチャンクは 0 とする
+	// テストカバレッジはカバレッジレポートを取り込んだスナップショットのみ（それ以外は NULL）
+	ListHotspotCandidates(ctx context.Context, arg ListHotspotCandidatesParams) ([]ListHotspotCandidatesRow, error)
+	// プロダクトの各ソースの最新のインデックス済みスナップショットのチャンク間の依存関係を取得する（ソースをまたぐものを含む）
+	ListImportanceEdges(ctx context.Context, productID pgtype.UUID) ([]ListImportanceEdgesRow, error)
+	// プロダクトの各ソースの最新のインデックス済みスナップショットの名前を持つチャンクと、スナップショット間で内容が変更された回数を取得する
+	// 同一ソース・同一ファイル内で同じ関数/クラス名を持つチャンクを同一の系譜とみなす（ListChunkHistory と同じ）
+	ListImportanceNodes(ctx context.Context, productID pgtype.UUID) ([]ListImportanceNodesRow, error)
+	// インデックス化の実行履歴を開始日時の新しい順に取得する（プロダクト・ソース名・状態で絞り込み）
+	ListIndexRuns(ctx context.Context, arg ListIndexRunsParams) ([]ListIndexRunsRow, error)
 	ListIndexedSnapshots(ctx context.Context) ([]SourceSnapshot, error)
+	// デプロイ構成のリソースを、プロダクトまたはスナップショットの範囲で取得する
+	// product_id を指定した場合は各ソースの最新のインデックス済みスナップショットを対象とする
+	ListInfraResources(ctx context.Context, arg ListInfraResourcesParams) ([]ListInfraResourcesRow, error)
+	// オンボーディングガイド用に、エントリポイントと重要度の高いファイルをファイル要約とともに取得する
+	// 対象スナップショットの決め方は ListDirectoryCoverage と同じ
+	// エントリポイント（main.go・index.ts など）を先に、それ以外はチャンクの重要度の最大値の高い順に並べる
+	ListKeyFiles(ctx context.Context, arg ListKeyFilesParams) ([]ListKeyFilesRow, error)
+	ListLLMCacheStats(ctx context.Context) ([]ListLLMCacheStatsRow, error)
+	// プロダクトの各ソースの最新のインデックス済みスナップショットのアーキテクチャ要約（プロダクト全体の要約の入力）
+	ListLatestArchitectureSummariesByProduct(ctx context.Context, productID pgtype.UUID) ([]ListLatestArchitectureSummariesByProductRow, error)
+	// 検出したライセンスを、プロダクトまたはスナップショットの範囲で取得する
+	// product_id を指定した場合は各ソースの最新のインデックス済みスナップショットを対象とする
+	ListLicenseFindings(ctx context.Context, arg ListLicenseFindingsParams) ([]ListLicenseFindingsRow, error)
+	// 検出したサービスを、プロダクトまたはスナップショットの範囲で取得する
+	// product_id を指定した場合は各ソースの最新のインデックス済みスナップショットを対象とする
+	ListMonorepoServices(ctx context.Context, arg ListMonorepoServicesParams) ([]ListMonorepoServicesRow, error)
+	// 運用コマンドのページ用に、Makefile・CI設定ファイルと scripts/ 配下・シェルスクリプトのチャンクを取得する
+	// 対象スナップショットの決め方は ListAPISymbols と同じ
+	ListOpsFileChunks(ctx context.Context, arg ListOpsFileChunksParams) ([]ListOpsFileChunksRow, error)
+	// インデックス済みスナップショットのチャンクのうち Embedding を持たないものを取得する
+	// content は Embedding の入力（切り詰めた embedding_context があればそれを優先）
+	ListOrphanChunks(ctx context.Context) ([]ListOrphanChunksRow, error)
+	ListProductConfigs(ctx context.Context, productID pgtype.UUID) ([]ProductConfig, error)
+	// チャンク間の依存関係の解決に使用する Go のチャンク（名前を持つもの）を取得する
+	// 対象スナップショットの決め方は ListProductGoModules と同じ
+	ListProductGoLinkChunks(ctx context.Context, productID pgtype.UUID) ([]ListProductGoLinkChunksRow, error)
+	// プロダクトの各ソースの最新のインデックス済みスナップショットに含まれる Go モジュールを取得する
+	ListProductGoModules(ctx context.Context, productID pgtype.UUID) ([]GoModule, error)
 	ListProducts(ctx context.Context) ([]Product, error)
 	ListProductsWithStats(ctx context.Context) ([]ListProductsWithStatsRow, error)
+	ListPromptTemplates(ctx context.Context) ([]PromptTemplate, error)
+	// 品質ノートを新しい順に取得する（ステータス・深刻度・記録日時の範囲で絞り込み）
+	ListQualityNotes(ctx context.Context, arg ListQualityNotesParams) ([]QualityNote, error)
+	// 評価の付いた回答を評価の低い順に取得する
+	ListRatedAskAnswers(ctx context.Context, arg ListRatedAskAnswersParams) ([]ListRatedAskAnswersRow, error)
+	// プロダクトの最新のチャンクから、指定したパスのファイルを変更したコミットを取得する（since 以降に更新されたもの）
+	ListRecentChangesByPaths(ctx context.Context, arg ListRecentChangesByPathsParams) ([]ListRecentChangesByPathsRow, error)
+	// 各モデルで生成したチャンクの Embedding の数とともに取得する
+	ListRegisteredEmbeddingModels(ctx context.Context) ([]ListRegisteredEmbeddingModelsRow, error)
+	// 指定チャンク群と依存関係で直接つながるチャンクを取得する
+	// outgoing: 指定チャンクが依存する側（呼び出し先・参照する型など）
+	// incoming: 指定チャンクに依存する側（呼び出し元など）
+	ListRelatedChunks(ctx context.Context, arg ListRelatedChunksParams) ([]ListRelatedChunksRow, error)
+	// 変更されたファイルの宣言（レベル2のチャンク）のうち、from と to で内容が異なるものを重要度順に取得する
+	// 同じファイル・親・名前・種類の宣言を同じ宣言とみなし、from にない宣言は added、to にない宣言は removed、両方にある宣言は modified とする
+	// from_snapshot_id が NULL の場合は to の宣言をすべて changed とする
+	ListReleaseChunkChanges(ctx context.Context, arg ListReleaseChunkChangesParams) ([]ListReleaseChunkChangesRow, error)
+	// 変更されたファイルの宣言に依存する他のファイルを、変更されたファイルごとに依存の多い順に取得する
+	ListReleaseDependents(ctx context.Context, arg ListReleaseDependentsParams) ([]ListReleaseDependentsRow, error)
+	// 変更されたファイルの要約を取得する
+	ListReleaseFileSummaries(ctx context.Context, arg ListReleaseFileSummariesParams) ([]ListReleaseFileSummariesRow, error)
+	// プロダクトのリリースノートを to のコミット日時の新しい順に取得する
+	ListReleaseNotesByProduct(ctx context.Context, productID pgtype.UUID) ([]ListReleaseNotesByProductRow, error)
+	// スキーマカタログのテーブルを、プロダクトまたはスナップショットの範囲で取得する
+	// product_id を指定した場合は各ソースの最新のインデックス済みスナップショットを対象とする
+	ListSchemaTables(ctx context.Context, arg ListSchemaTablesParams) ([]ListSchemaTablesRow, error)
+	// Wikiの目次（toc.json）に記録する、生成の対象としたスナップショットをソース名順に取得する
+	// 対象スナップショットの決め方は ListDirectoryCoverage と同じ
+	ListScopeSnapshots(ctx context.Context, arg ListScopeSnapshotsParams) ([]ListScopeSnapshotsRow, error)
+	// スナップショット内のチャンク数をレベル・種類・ドメイン・Embeddingモデル別に集計
+	ListSnapshotChunkBreakdown(ctx context.Context, snapshotID pgtype.UUID) ([]ListSnapshotChunkBreakdownRow, error)
+	ListSnapshotExternalDependencies(ctx context.Context, snapshotID pgtype.UUID) ([]ExternalDependency, error)
+	// snapshot_files のインデックス済みフラグが、チャンクを持つファイルの有無と一致しない行を取得する
+	ListSnapshotFileMismatches(ctx context.Context) ([]ListSnapshotFileMismatchesRow, error)
+	ListSnapshotUsage(ctx context.Context, snapshotID pgtype.UUID) ([]SnapshotUsage, error)
+	// Wikiの参照チェック用に、対象スナップショットのファイルパスと行数（インデックス済みチャンクの最終行）を取得する
+	// 対象スナップショットの決め方は ListDirectoryCoverage と同じ
+	// インデックス対象外のファイルも snapshot_files から含め、チャンクを持たないファイルの行数は 0 とする
+	ListSourceFileLines(ctx context.Context, arg ListSourceFileLinesParams) ([]ListSourceFileLinesRow, error)
 	ListSourceSnapshotsBySource(ctx context.Context, sourceID pgtype.UUID) ([]SourceSnapshot, error)
 	ListSourcesByProduct(ctx context.Context, productID pgtype.UUID) ([]Source, error)
 	ListSourcesByType(ctx context.Context, sourceType string) ([]Source, error)
+	// 最新フラグ（is_latest）が RefreshLatestChunks の判定と一致しないチャンクの数をソースごとに集計する
+	ListStaleLatestSources(ctx context.Context) ([]ListStaleLatestSourcesRow, error)
 	ListSummariesByType(ctx context.Context, arg ListSummariesByTypeParams) ([]Summary, error)
+	// カバレッジの取り込み先として、スナップショットのファイルと関数・クラス単位のチャンクの行範囲を取得する
+	ListTestCoverageTargets(ctx context.Context, snapshotID pgtype.UUID) ([]ListTestCoverageTargetsRow, error)
+	// 重要度の高い関数/クラス（レベル2）のチャンク同士の呼び出し関係を取得する（Wikiの図解生成用）
+	// 対象スナップショットの決め方は ListFileDependencyEdges と同じ
+	ListTopChunkCallEdges(ctx context.Context, arg ListTopChunkCallEdgesParams) ([]ListTopChunkCallEdgesRow, error)
 	ListWikiMetadata(ctx context.Context) ([]WikiMetadatum, error)
+	ListWikiPublications(ctx context.Context, arg ListWikiPublicationsParams) ([]WikiPublication, error)
+	// インデックス化に失敗・中断したスナップショットを記録する（インデックス済みのものは変更しない）
+	MarkSnapshotFailed(ctx context.Context, arg MarkSnapshotFailedParams) error
 	MarkSnapshotIndexed(ctx context.Context, id pgtype.UUID) (SourceSnapshot, error)
+	// スナップショットのインデックス化の開始を記録する（前回の失敗の記録は消去する）
+	MarkSnapshotIndexing(ctx context.Context, id pgtype.UUID) error
+	// 接頭辞（例: ACT-2025-）に続く番号の最大値を取得する（ない場合は 0）
+	MaxActionNumber(ctx context.Context, prefix string) (int32, error)
+	// 接頭辞（例: QN-2025-）に続く番号の最大値を取得する（ない場合は 0）
+	MaxQualityNoteNumber(ctx context.Context, prefix string) (int32, error)
+	// プロダクトを指定しない質問の振り分け用
+	// 各プロダクトの最新のインデックス済みスナップショットのアーキテクチャ要約と、プロダクト全体の要約（model が主モデルのもの）の
+	// 質問との類似度の最大値で、プロダクトを順位付けする
+	RankProductsBySummaries(ctx context.Context, arg RankProductsBySummariesParams) ([]RankProductsBySummariesRow, error)
+	// スナップショットのドメイン別カバレッジ（snapshot_files の集計）を履歴に記録する（記録済みの場合は上書きする）
+	RecordCoverageHistory(ctx context.Context, snapshotID pgtype.UUID) error
+	// スナップショットの全ファイル（インデックス対象外を含む）を一括で記録する
+	// インデックス済みかは files テーブルにファイルがあるかで判定し、インデックスされなかったファイルの理由は skip_reasons（空文字は理由なし）とする
+	RecordSnapshotFiles(ctx context.Context, arg RecordSnapshotFilesParams) error
+	// ソースのチャンクの is_latest を更新する
+	// Git参照が指すスナップショットと最新のインデックス済みスナップショットのチャンクを最新とみなす
+	RefreshLatestChunks(ctx context.Context, sourceID pgtype.UUID) error
 	RemoveChunkRelation(ctx context.Context, arg RemoveChunkRelationParams) error
 	SearchArchitectureSummaryEmbeddings(ctx context.Context, arg SearchArchitectureSummaryEmbeddingsParams) ([]SearchArchitectureSummaryEmbeddingsRow, error)
+	// 追加のEmbeddingモデルの Embedding で検索する（モデルを選択した検索で使用）
+	// product_id / source_id / snapshot_id のうち指定されたもので範囲を限定する（snapshot_id 指定時は最新スナップショットの代わりに使用）
+	// 次元数がモデルごとに異なりHNSWインデックスを利用できないため、モデルで絞り込んだチャンクとの距離をすべて計算する
+	// 並べ替えは SearchChunksByProduct と同様に importance_weight と recency_weight で重み付けする
+	SearchChunksByModel(ctx context.Context, arg SearchChunksByModelParams) ([]SearchChunksByModelRow, error)
+	// snapshot_id 指定時は最新スナップショットの代わりにそのスナップショットのみを対象とする
+	// ベクトル距離順に候補を取得（HNSWインデックスを利用）したうえで、類似度と importance_score を
+	// importance_weight で加重平均し、チャンクの最終更新日時からの経過日数に応じて recency_weight の割合まで減衰させたスコアで並べ替える
+	// 重み付けがない場合は候補を row_limit 件に絞り、従来どおりベクトル距離順の上位を返す
 	SearchChunksByProduct(ctx context.Context, arg SearchChunksByProductParams) ([]SearchChunksByProductRow, error)
+	// 並べ替えは SearchChunksByProduct と同様に importance_weight と recency_weight で重み付けする
 	SearchChunksBySnapshot(ctx context.Context, arg SearchChunksBySnapshotParams) ([]SearchChunksBySnapshotRow, error)
+	// snapshot_id 指定時は最新スナップショットの代わりにそのスナップショットのみを対象とする
+	// 並べ替えは SearchChunksByProduct と同様に importance_weight と recency_weight で重み付けする
 	SearchChunksBySource(ctx context.Context, arg SearchChunksBySourceParams) ([]SearchChunksBySourceRow, error)
 	SearchDirectorySummaryEmbeddings(ctx context.Context, arg SearchDirectorySummaryEmbeddingsParams) ([]SearchDirectorySummaryEmbeddingsRow, error)
+	// 2段階検索の1段目としてファイル要約をベクトル検索し、候補ファイルを返す
+	// product_id / source_id / snapshot_id のうち指定されたもので範囲を限定する（snapshot_id 指定時は最新スナップショットの代わりに使用）
+	SearchFileSummariesByScope(ctx context.Context, arg SearchFileSummariesByScopeParams) ([]SearchFileSummariesByScopeRow, error)
 	SearchFileSummaryEmbeddings(ctx context.Context, arg SearchFileSummaryEmbeddingsParams) ([]SearchFileSummaryEmbeddingsRow, error)
 	SearchSimilarChunks(ctx context.Context, arg SearchSimilarChunksParams) ([]SearchSimilarChunksRow, error)
+	// snapshot_id 指定時は最新スナップショットの代わりにそのスナップショットのみを対象とする
 	SearchSummariesByProduct(ctx context.Context, arg SearchSummariesByProductParams) ([]SearchSummariesByProductRow, error)
 	SearchSummariesBySnapshot(ctx context.Context, arg SearchSummariesBySnapshotParams) ([]SearchSummariesBySnapshotRow, error)
 	SearchSummaryEmbeddings(ctx context.Context, arg SearchSummaryEmbeddingsParams) ([]SearchSummaryEmbeddingsRow, error)
 	UpdateChunkImportanceScore(ctx context.Context, arg UpdateChunkImportanceScoreParams) error
+	// CODEOWNERS から判定した担当者をファイルに設定する（担当者は空白区切りで1ファイル1要素として渡す）
+	UpdateFileOwners(ctx context.Context, arg UpdateFileOwnersParams) error
 	UpdateGitRef(ctx context.Context, arg UpdateGitRefParams) (GitRef, error)
 	UpdateProduct(ctx context.Context, arg UpdateProductParams) (Product, error)
+	UpdateProductRecencyWeight(ctx context.Context, arg UpdateProductRecencyWeightParams) (Product, error)
 	UpdateSnapshotFileIndexed(ctx context.Context, arg UpdateSnapshotFileIndexedParams) error
 	UpdateSource(ctx context.Context, arg UpdateSourceParams) (Source, error)
 	UpdateSummary(ctx context.Context, arg UpdateSummaryParams) (Summary, error)
+	// snapshot_id を指定しない場合は、保存時のプロダクトの各ソースの最新のインデックス済みスナップショットを記録する
+	UpsertAskCache(ctx context.Context, arg UpsertAskCacheParams) error
+	UpsertAskFeedback(ctx context.Context, arg UpsertAskFeedbackParams) error
+	// スナップショットの再実行（再開・--force-init）でチャンクを保存する
+	// チャンクの ID は chunk_key から決定的に生成するため、前回の実行で保存した同じチャンクは置き換える
+	UpsertChunkBatch(ctx context.Context, arg []UpsertChunkBatchParams) *UpsertChunkBatchBatchResults
+	// 追加のEmbeddingモデルで生成した Embedding を保存する（同じモデルで生成済みの場合は置き換える）
+	UpsertChunkEmbeddingBatch(ctx context.Context, arg []UpsertChunkEmbeddingBatchParams) *UpsertChunkEmbeddingBatchBatchResults
+	UpsertDecisionRecord(ctx context.Context, arg UpsertDecisionRecordParams) error
+	UpsertDiscussion(ctx context.Context, arg UpsertDiscussionParams) error
+	// 別のモデルで生成した Embedding は置き換える（index reembed で使用）
+	UpsertEmbeddingBatch(ctx context.Context, arg []UpsertEmbeddingBatchParams) *UpsertEmbeddingBatchBatchResults
+	// 既に記録済みのチャンクは失敗回数を加算し、エラーと最終失敗日時を更新する
+	UpsertEmbeddingFailure(ctx context.Context, arg UpsertEmbeddingFailureParams) error
+	// 既に記録済みのファイルは失敗回数を加算し、段階・エラー・内容と最終失敗日時を更新する
+	UpsertFileFailure(ctx context.Context, arg UpsertFileFailureParams) error
+	UpsertGlossaryTerm(ctx context.Context, arg UpsertGlossaryTermParams) (Glossary, error)
+	UpsertLLMCache(ctx context.Context, arg UpsertLLMCacheParams) error
+	UpsertProductConfig(ctx context.Context, arg UpsertProductConfigParams) (ProductConfig, error)
+	UpsertProductSummary(ctx context.Context, arg UpsertProductSummaryParams) error
+	UpsertPromptTemplate(ctx context.Context, arg UpsertPromptTemplateParams) (PromptTemplate, error)
+	UpsertRegisteredEmbeddingModel(ctx context.Context, arg UpsertRegisteredEmbeddingModelParams) error
+	UpsertReleaseNote(ctx context.Context, arg UpsertReleaseNoteParams) (ReleaseNote, error)
 	UpsertSummaryEmbedding(ctx context.Context, arg UpsertSummaryEmbeddingParams) (SummaryEmbedding, error)
+	UpsertWikiPublication(ctx context.Context, arg UpsertWikiPublicationParams) (WikiPublication, error)
 }
 
 var _ Querier = (*Queries)(nil)
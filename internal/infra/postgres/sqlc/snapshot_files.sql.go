@@ -195,6 +195,240 @@ func (q *Queries) GetUnindexedImportantFiles(ctx context.Context, snapshotID pgt
 	return items, nil
 }
 
+const listDirectoryCoverage = `-- name: ListDirectoryCoverage :many
+WITH scope_snapshots AS (
+    SELECT DISTINCT ON (ss.source_id) ss.id, s.name AS source_name
+    FROM source_snapshots ss
+    INNER JOIN sources s ON ss.source_id = s.id
+    WHERE ss.status = 'indexed'
+      AND ($1::uuid IS NULL OR s.product_id = $1::uuid)
+      AND ($2::uuid IS NULL OR ss.id = $2::uuid)
+    ORDER BY ss.source_id, ss.indexed_at DESC NULLS LAST, ss.created_at DESC
+),
+dir_files AS (
+    SELECT
+        sc.source_name,
+        CASE WHEN strpos(sf.file_path, '/') > 0 THEN split_part(sf.file_path, '/', 1) ELSE '' END AS directory,
+        COALESCE(sf.domain, 'unknown') AS domain,
+        sf.indexed
+    FROM snapshot_files sf
+    INNER JOIN scope_snapshots sc ON sf.snapshot_id = sc.id
+)
+SELECT
+    source_name::text AS source_name,
+    directory::text AS directory,
+    domain::text AS domain,
+    COUNT(*)::bigint AS total_files,
+    COUNT(*) FILTER (WHERE indexed)::bigint AS indexed_files
+FROM dir_files
+GROUP BY source_name, directory, domain
+ORDER BY source_name, directory, domain
+`
+
+type ListDirectoryCoverageParams struct {
+	ProductID  pgtype.UUID `json:"product_id"`
+	SnapshotID pgtype.UUID `json:"snapshot_id"`
+}
+
+type ListDirectoryCoverageRow struct {
+	SourceName   string `json:"source_name"`
+	Directory    string `json:"directory"`
+	Domain       string `json:"domain"`
+	TotalFiles   int64  `json:"total_files"`
+	IndexedFiles int64  `json:"indexed_files"`
+}
+
+// オンボーディングガイド用に、ソース・トップレベルディレクトリ・ドメインごとのファイル数とインデックス済みファイル数を取得する
+// product_id 指定時はプロダクトの各ソースの最新スナップショット、snapshot_id 指定時はそのスナップショットを対象とする
+// ルート直下のファイルのディレクトリは空文字とする
+func (q *Queries) ListDirectoryCoverage(ctx context.Context, arg ListDirectoryCoverageParams) ([]ListDirectoryCoverageRow, error) {
+	rows, err := q.db.Query(ctx, listDirectoryCoverage, arg.ProductID, arg.SnapshotID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListDirectoryCoverageRow{}
+	for rows.Next() {
+		var i ListDirectoryCoverageRow
+		if err := rows.Scan(
+			&i.SourceName,
+			&i.Directory,
+			&i.Domain,
+			&i.TotalFiles,
+			&i.IndexedFiles,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listScopeSnapshots = `-- name: ListScopeSnapshots :many
+SELECT DISTINCT ON (s.name)
+    s.name AS source_name,
+    ss.id AS snapshot_id,
+    ss.version_identifier,
+    ss.indexed_at
+FROM source_snapshots ss
+INNER JOIN sources s ON ss.source_id = s.id
+WHERE ss.status = 'indexed'
+  AND ($1::uuid IS NULL OR s.product_id = $1::uuid)
+  AND ($2::uuid IS NULL OR ss.id = $2::uuid)
+ORDER BY s.name, ss.indexed_at DESC NULLS LAST, ss.created_at DESC
+`
+
+type ListScopeSnapshotsParams struct {
+	ProductID  pgtype.UUID `json:"product_id"`
+	SnapshotID pgtype.UUID `json:"snapshot_id"`
+}
+
+type ListScopeSnapshotsRow struct {
+	SourceName        string           `json:"source_name"`
+	SnapshotID        pgtype.UUID      `json:"snapshot_id"`
+	VersionIdentifier string           `json:"version_identifier"`
+	IndexedAt         pgtype.Timestamp `json:"indexed_at"`
+}
+
+// Wikiの目次（toc.json）に記録する、生成の対象としたスナップショットをソース名順に取得する
+// 対象スナップショットの決め方は ListDirectoryCoverage と同じ
+func (q *Queries) ListScopeSnapshots(ctx context.Context, arg ListScopeSnapshotsParams) ([]ListScopeSnapshotsRow, error) {
+	rows, err := q.db.Query(ctx, listScopeSnapshots, arg.ProductID, arg.SnapshotID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListScopeSnapshotsRow{}
+	for rows.Next() {
+		var i ListScopeSnapshotsRow
+		if err := rows.Scan(
+			&i.SourceName,
+			&i.SnapshotID,
+			&i.VersionIdentifier,
+			&i.IndexedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSourceFileLines = `-- name: ListSourceFileLines :many
+WITH scope_snapshots AS (
+    SELECT DISTINCT ON (ss.source_id) ss.id
+    FROM source_snapshots ss
+    INNER JOIN sources s ON ss.source_id = s.id
+    WHERE ss.status = 'indexed'
+      AND ($1::uuid IS NULL OR s.product_id = $1::uuid)
+      AND ($2::uuid IS NULL OR ss.id = $2::uuid)
+    ORDER BY ss.source_id, ss.indexed_at DESC NULLS LAST, ss.created_at DESC
+),
+scope_paths AS (
+    SELECT sf.snapshot_id, sf.file_path AS path
+    FROM snapshot_files sf
+    INNER JOIN scope_snapshots sc ON sf.snapshot_id = sc.id
+    UNION
+    SELECT f.snapshot_id, f.path
+    FROM files f
+    INNER JOIN scope_snapshots sc ON f.snapshot_id = sc.id
+)
+SELECT
+    sp.path::text AS path,
+    COALESCE(MAX(c.end_line), 0)::int AS line_count
+FROM scope_paths sp
+LEFT JOIN files f ON f.snapshot_id = sp.snapshot_id AND f.path = sp.path
+LEFT JOIN chunks c ON c.file_id = f.id
+GROUP BY sp.path
+ORDER BY sp.path
+`
+
+type ListSourceFileLinesParams struct {
+	ProductID  pgtype.UUID `json:"product_id"`
+	SnapshotID pgtype.UUID `json:"snapshot_id"`
+}
+
+type ListSourceFileLinesRow struct {
+	Path      string `json:"path"`
+	LineCount int32  `json:"line_count"`
+}
+
+// Wikiの参照チェック用に、対象スナップショットのファイルパスと行数（インデックス済みチャンクの最終行）を取得する
+// 対象スナップショットの決め方は ListDirectoryCoverage と同じ
+// インデックス対象外のファイルも snapshot_files から含め、チャンクを持たないファイルの行数は 0 とする
+func (q *Queries) ListSourceFileLines(ctx context.Context, arg ListSourceFileLinesParams) ([]ListSourceFileLinesRow, error) {
+	rows, err := q.db.Query(ctx, listSourceFileLines, arg.ProductID, arg.SnapshotID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListSourceFileLinesRow{}
+	for rows.Next() {
+		var i ListSourceFileLinesRow
+		if err := rows.Scan(&i.Path, &i.LineCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const recordSnapshotFiles = `-- name: RecordSnapshotFiles :exec
+INSERT INTO snapshot_files (snapshot_id, file_path, file_size, domain, indexed, skip_reason)
+SELECT
+    $1,
+    input.file_path,
+    input.file_size,
+    NULLIF(input.domain, ''),
+    EXISTS (SELECT 1 FROM files f WHERE f.snapshot_id = $1 AND f.path = input.file_path),
+    CASE
+        WHEN EXISTS (SELECT 1 FROM files f WHERE f.snapshot_id = $1 AND f.path = input.file_path) THEN NULL
+        ELSE NULLIF(input.skip_reason, '')
+    END
+FROM (
+    SELECT
+        unnest($2::text[]) AS file_path,
+        unnest($3::bigint[]) AS file_size,
+        unnest($4::text[]) AS domain,
+        unnest($5::text[]) AS skip_reason
+) input
+ON CONFLICT (snapshot_id, file_path) DO UPDATE
+SET file_size = EXCLUDED.file_size,
+    domain = EXCLUDED.domain,
+    indexed = EXCLUDED.indexed,
+    skip_reason = EXCLUDED.skip_reason
+`
+
+type RecordSnapshotFilesParams struct {
+	SnapshotID  pgtype.UUID `json:"snapshot_id"`
+	FilePaths   []string    `json:"file_paths"`
+	FileSizes   []int64     `json:"file_sizes"`
+	Domains     []string    `json:"domains"`
+	SkipReasons []string    `json:"skip_reasons"`
+}
+
+// スナップショットの全ファイル（インデックス対象外を含む）を一括で記録する
+// インデックス済みかは files テーブルにファイルがあるかで判定し、インデックスされなかったファイルの理由は skip_reasons（空文字は理由なし）とする
+func (q *Queries) RecordSnapshotFiles(ctx context.Context, arg RecordSnapshotFilesParams) error {
+	_, err := q.db.Exec(ctx, recordSnapshotFiles,
+		arg.SnapshotID,
+		arg.FilePaths,
+		arg.FileSizes,
+		arg.Domains,
+		arg.SkipReasons,
+	)
+	return err
+}
+
 const updateSnapshotFileIndexed = `-- name: UpdateSnapshotFileIndexed :exec
 UPDATE snapshot_files
 SET indexed = $3
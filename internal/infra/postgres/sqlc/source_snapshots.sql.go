@@ -14,7 +14,7 @@ import (
 const createSourceSnapshot = `-- name: CreateSourceSnapshot :one
 INSERT INTO source_snapshots (source_id, version_identifier)
 VALUES ($1, $2)
-RETURNING id, source_id, version_identifier, indexed, indexed_at, created_at
+RETURNING id, source_id, version_identifier, indexed_at, created_at, failed_at, failure_reason, status, indexing_started_at
 `
 
 type CreateSourceSnapshotParams struct {
@@ -29,13 +29,41 @@ func (q *Queries) CreateSourceSnapshot(ctx context.Context, arg CreateSourceSnap
 		&i.ID,
 		&i.SourceID,
 		&i.VersionIdentifier,
-		&i.Indexed,
 		&i.IndexedAt,
 		&i.CreatedAt,
+		&i.FailedAt,
+		&i.FailureReason,
+		&i.Status,
+		&i.IndexingStartedAt,
 	)
 	return i, err
 }
 
+const deleteIncompleteSnapshotFiles = `-- name: DeleteIncompleteSnapshotFiles :execrows
+DELETE FROM files f
+WHERE f.snapshot_id = $1
+  AND (
+      NOT EXISTS (SELECT 1 FROM chunks c WHERE c.file_id = f.id)
+      OR EXISTS (
+          SELECT 1 FROM chunks c
+          WHERE c.file_id = f.id
+            AND NOT EXISTS (SELECT 1 FROM embeddings e WHERE e.chunk_id = c.id)
+            AND NOT EXISTS (SELECT 1 FROM embedding_failures ef WHERE ef.chunk_id = c.id)
+      )
+  )
+`
+
+// 中断したスナップショットの再開前に、Embedding の保存が完了していないファイルを削除する（チャンク・Embedding は CASCADE で削除）
+// チャンクの保存に失敗した可能性があるチャンクのないファイルも削除する
+// Embedding の生成に失敗して embedding_failures に記録したチャンクは完了したものとして扱う
+func (q *Queries) DeleteIncompleteSnapshotFiles(ctx context.Context, snapshotID pgtype.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteIncompleteSnapshotFiles, snapshotID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const deleteSourceSnapshot = `-- name: DeleteSourceSnapshot :exec
 DELETE FROM source_snapshots
 WHERE id = $1
@@ -47,8 +75,8 @@ func (q *Queries) DeleteSourceSnapshot(ctx context.Context, id pgtype.UUID) erro
 }
 
 const getLatestIndexedSnapshot = `-- name: GetLatestIndexedSnapshot :one
-SELECT id, source_id, version_identifier, indexed, indexed_at, created_at FROM source_snapshots
-WHERE source_id = $1 AND indexed = TRUE
+SELECT id, source_id, version_identifier, indexed_at, created_at, failed_at, failure_reason, status, indexing_started_at FROM source_snapshots
+WHERE source_id = $1 AND status = 'indexed'
 ORDER BY indexed_at DESC NULLS LAST, created_at DESC
 LIMIT 1
 `
@@ -60,15 +88,130 @@ func (q *Queries) GetLatestIndexedSnapshot(ctx context.Context, sourceID pgtype.
 		&i.ID,
 		&i.SourceID,
 		&i.VersionIdentifier,
-		&i.Indexed,
 		&i.IndexedAt,
 		&i.CreatedAt,
+		&i.FailedAt,
+		&i.FailureReason,
+		&i.Status,
+		&i.IndexingStartedAt,
+	)
+	return i, err
+}
+
+const getSnapshotDomainStats = `-- name: GetSnapshotDomainStats :many
+SELECT
+    COALESCE(f.domain, 'unknown')::text AS domain,
+    COUNT(DISTINCT f.id)::bigint AS file_count,
+    COUNT(c.id)::bigint AS chunk_count
+FROM files f
+LEFT JOIN chunks c ON c.file_id = f.id
+WHERE f.snapshot_id = $1
+GROUP BY COALESCE(f.domain, 'unknown')
+ORDER BY COALESCE(f.domain, 'unknown')
+`
+
+type GetSnapshotDomainStatsRow struct {
+	Domain     string `json:"domain"`
+	FileCount  int64  `json:"file_count"`
+	ChunkCount int64  `json:"chunk_count"`
+}
+
+// スナップショット内のドメイン別ファイル数・チャンク数を集計
+func (q *Queries) GetSnapshotDomainStats(ctx context.Context, snapshotID pgtype.UUID) ([]GetSnapshotDomainStatsRow, error) {
+	rows, err := q.db.Query(ctx, getSnapshotDomainStats, snapshotID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetSnapshotDomainStatsRow{}
+	for rows.Next() {
+		var i GetSnapshotDomainStatsRow
+		if err := rows.Scan(&i.Domain, &i.FileCount, &i.ChunkCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSnapshotIndexHealth = `-- name: GetSnapshotIndexHealth :one
+SELECT
+    COUNT(DISTINCT f.id)::bigint AS file_count,
+    COUNT(c.id)::bigint AS chunk_count,
+    COUNT(e.chunk_id)::bigint AS embedding_count,
+    COALESCE(AVG(c.token_count), 0)::float8 AS avg_tokens,
+    COALESCE(MAX(c.token_count), 0)::int AS max_tokens,
+    COUNT(c.id) FILTER (WHERE e.chunk_id IS NULL)::bigint AS orphan_chunk_count,
+    COUNT(c.id) FILTER (WHERE c.token_count > $1::int)::bigint AS oversized_chunk_count
+FROM files f
+LEFT JOIN chunks c ON c.file_id = f.id
+LEFT JOIN embeddings e ON e.chunk_id = c.id
+WHERE f.snapshot_id = $2
+`
+
+type GetSnapshotIndexHealthParams struct {
+	TokenLimit int32       `json:"token_limit"`
+	SnapshotID pgtype.UUID `json:"snapshot_id"`
+}
+
+type GetSnapshotIndexHealthRow struct {
+	FileCount           int64   `json:"file_count"`
+	ChunkCount          int64   `json:"chunk_count"`
+	EmbeddingCount      int64   `json:"embedding_count"`
+	AvgTokens           float64 `json:"avg_tokens"`
+	MaxTokens           int32   `json:"max_tokens"`
+	OrphanChunkCount    int64   `json:"orphan_chunk_count"`
+	OversizedChunkCount int64   `json:"oversized_chunk_count"`
+}
+
+// スナップショットのインデックス品質の診断に使用する件数・トークン数を集計
+// orphan_chunk_count は Embedding を持たないチャンク、oversized_chunk_count はトークン数が上限を超えるチャンクの数
+func (q *Queries) GetSnapshotIndexHealth(ctx context.Context, arg GetSnapshotIndexHealthParams) (GetSnapshotIndexHealthRow, error) {
+	row := q.db.QueryRow(ctx, getSnapshotIndexHealth, arg.TokenLimit, arg.SnapshotID)
+	var i GetSnapshotIndexHealthRow
+	err := row.Scan(
+		&i.FileCount,
+		&i.ChunkCount,
+		&i.EmbeddingCount,
+		&i.AvgTokens,
+		&i.MaxTokens,
+		&i.OrphanChunkCount,
+		&i.OversizedChunkCount,
 	)
 	return i, err
 }
 
+const getSnapshotStats = `-- name: GetSnapshotStats :one
+SELECT
+    (SELECT COUNT(*) FROM files f WHERE f.snapshot_id = $1)::bigint AS file_count,
+    (SELECT COUNT(*) FROM chunks c
+        JOIN files f ON c.file_id = f.id
+        WHERE f.snapshot_id = $1)::bigint AS chunk_count,
+    (SELECT COUNT(*) FROM embeddings e
+        JOIN chunks c ON e.chunk_id = c.id
+        JOIN files f ON c.file_id = f.id
+        WHERE f.snapshot_id = $1)::bigint AS embedding_count
+`
+
+type GetSnapshotStatsRow struct {
+	FileCount      int64 `json:"file_count"`
+	ChunkCount     int64 `json:"chunk_count"`
+	EmbeddingCount int64 `json:"embedding_count"`
+}
+
+// スナップショットのファイル数・チャンク数・Embedding数を集計
+func (q *Queries) GetSnapshotStats(ctx context.Context, snapshotID pgtype.UUID) (GetSnapshotStatsRow, error) {
+	row := q.db.QueryRow(ctx, getSnapshotStats, snapshotID)
+	var i GetSnapshotStatsRow
+	err := row.Scan(&i.FileCount, &i.ChunkCount, &i.EmbeddingCount)
+	return i, err
+}
+
 const getSourceSnapshot = `-- name: GetSourceSnapshot :one
-SELECT id, source_id, version_identifier, indexed, indexed_at, created_at FROM source_snapshots
+SELECT id, source_id, version_identifier, indexed_at, created_at, failed_at, failure_reason, status, indexing_started_at FROM source_snapshots
 WHERE id = $1
 `
 
@@ -79,15 +222,18 @@ func (q *Queries) GetSourceSnapshot(ctx context.Context, id pgtype.UUID) (Source
 		&i.ID,
 		&i.SourceID,
 		&i.VersionIdentifier,
-		&i.Indexed,
 		&i.IndexedAt,
 		&i.CreatedAt,
+		&i.FailedAt,
+		&i.FailureReason,
+		&i.Status,
+		&i.IndexingStartedAt,
 	)
 	return i, err
 }
 
 const getSourceSnapshotByVersion = `-- name: GetSourceSnapshotByVersion :one
-SELECT id, source_id, version_identifier, indexed, indexed_at, created_at FROM source_snapshots
+SELECT id, source_id, version_identifier, indexed_at, created_at, failed_at, failure_reason, status, indexing_started_at FROM source_snapshots
 WHERE source_id = $1 AND version_identifier = $2
 `
 
@@ -103,16 +249,19 @@ func (q *Queries) GetSourceSnapshotByVersion(ctx context.Context, arg GetSourceS
 		&i.ID,
 		&i.SourceID,
 		&i.VersionIdentifier,
-		&i.Indexed,
 		&i.IndexedAt,
 		&i.CreatedAt,
+		&i.FailedAt,
+		&i.FailureReason,
+		&i.Status,
+		&i.IndexingStartedAt,
 	)
 	return i, err
 }
 
 const listIndexedSnapshots = `-- name: ListIndexedSnapshots :many
-SELECT id, source_id, version_identifier, indexed, indexed_at, created_at FROM source_snapshots
-WHERE indexed = TRUE
+SELECT id, source_id, version_identifier, indexed_at, created_at, failed_at, failure_reason, status, indexing_started_at FROM source_snapshots
+WHERE status = 'indexed'
 ORDER BY indexed_at DESC
 `
 
@@ -129,9 +278,12 @@ func (q *Queries) ListIndexedSnapshots(ctx context.Context) ([]SourceSnapshot, e
 			&i.ID,
 			&i.SourceID,
 			&i.VersionIdentifier,
-			&i.Indexed,
 			&i.IndexedAt,
 			&i.CreatedAt,
+			&i.FailedAt,
+			&i.FailureReason,
+			&i.Status,
+			&i.IndexingStartedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -143,8 +295,63 @@ func (q *Queries) ListIndexedSnapshots(ctx context.Context) ([]SourceSnapshot, e
 	return items, nil
 }
 
+const listSnapshotChunkBreakdown = `-- name: ListSnapshotChunkBreakdown :many
+SELECT 'level'::text AS dimension, c.level::text AS key, COUNT(*)::bigint AS chunk_count
+FROM chunks c
+JOIN files f ON c.file_id = f.id
+WHERE f.snapshot_id = $1
+GROUP BY c.level
+UNION ALL
+SELECT 'type'::text, COALESCE(c.chunk_type, 'unknown')::text, COUNT(*)::bigint
+FROM chunks c
+JOIN files f ON c.file_id = f.id
+WHERE f.snapshot_id = $1
+GROUP BY COALESCE(c.chunk_type, 'unknown')
+UNION ALL
+SELECT 'domain'::text, COALESCE(f.domain, 'unknown')::text, COUNT(*)::bigint
+FROM chunks c
+JOIN files f ON c.file_id = f.id
+WHERE f.snapshot_id = $1
+GROUP BY COALESCE(f.domain, 'unknown')
+UNION ALL
+SELECT 'embedding_model'::text, e.model::text, COUNT(*)::bigint
+FROM embeddings e
+JOIN chunks c ON e.chunk_id = c.id
+JOIN files f ON c.file_id = f.id
+WHERE f.snapshot_id = $1
+GROUP BY e.model
+ORDER BY dimension, chunk_count DESC, key
+`
+
+type ListSnapshotChunkBreakdownRow struct {
+	Dimension  string `json:"dimension"`
+	Key        string `json:"key"`
+	ChunkCount int64  `json:"chunk_count"`
+}
+
+// スナップショット内のチャンク数をレベル・種類・ドメイン・Embeddingモデル別に集計
+func (q *Queries) ListSnapshotChunkBreakdown(ctx context.Context, snapshotID pgtype.UUID) ([]ListSnapshotChunkBreakdownRow, error) {
+	rows, err := q.db.Query(ctx, listSnapshotChunkBreakdown, snapshotID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListSnapshotChunkBreakdownRow{}
+	for rows.Next() {
+		var i ListSnapshotChunkBreakdownRow
+		if err := rows.Scan(&i.Dimension, &i.Key, &i.ChunkCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listSourceSnapshotsBySource = `-- name: ListSourceSnapshotsBySource :many
-SELECT id, source_id, version_identifier, indexed, indexed_at, created_at FROM source_snapshots
+SELECT id, source_id, version_identifier, indexed_at, created_at, failed_at, failure_reason, status, indexing_started_at FROM source_snapshots
 WHERE source_id = $1
 ORDER BY created_at DESC
 `
@@ -162,9 +369,12 @@ func (q *Queries) ListSourceSnapshotsBySource(ctx context.Context, sourceID pgty
 			&i.ID,
 			&i.SourceID,
 			&i.VersionIdentifier,
-			&i.Indexed,
 			&i.IndexedAt,
 			&i.CreatedAt,
+			&i.FailedAt,
+			&i.FailureReason,
+			&i.Status,
+			&i.IndexingStartedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -176,11 +386,28 @@ func (q *Queries) ListSourceSnapshotsBySource(ctx context.Context, sourceID pgty
 	return items, nil
 }
 
+const markSnapshotFailed = `-- name: MarkSnapshotFailed :exec
+UPDATE source_snapshots
+SET status = 'failed', failed_at = CURRENT_TIMESTAMP, failure_reason = $1
+WHERE id = $2 AND status <> 'indexed'
+`
+
+type MarkSnapshotFailedParams struct {
+	FailureReason pgtype.Text `json:"failure_reason"`
+	ID            pgtype.UUID `json:"id"`
+}
+
+// インデックス化に失敗・中断したスナップショットを記録する（インデックス済みのものは変更しない）
+func (q *Queries) MarkSnapshotFailed(ctx context.Context, arg MarkSnapshotFailedParams) error {
+	_, err := q.db.Exec(ctx, markSnapshotFailed, arg.FailureReason, arg.ID)
+	return err
+}
+
 const markSnapshotIndexed = `-- name: MarkSnapshotIndexed :one
 UPDATE source_snapshots
-SET indexed = TRUE, indexed_at = CURRENT_TIMESTAMP
+SET status = 'indexed', indexed_at = CURRENT_TIMESTAMP, failed_at = NULL, failure_reason = NULL
 WHERE id = $1
-RETURNING id, source_id, version_identifier, indexed, indexed_at, created_at
+RETURNING id, source_id, version_identifier, indexed_at, created_at, failed_at, failure_reason, status, indexing_started_at
 `
 
 func (q *Queries) MarkSnapshotIndexed(ctx context.Context, id pgtype.UUID) (SourceSnapshot, error) {
@@ -190,9 +417,24 @@ func (q *Queries) MarkSnapshotIndexed(ctx context.Context, id pgtype.UUID) (Sour
 		&i.ID,
 		&i.SourceID,
 		&i.VersionIdentifier,
-		&i.Indexed,
 		&i.IndexedAt,
 		&i.CreatedAt,
+		&i.FailedAt,
+		&i.FailureReason,
+		&i.Status,
+		&i.IndexingStartedAt,
 	)
 	return i, err
 }
+
+const markSnapshotIndexing = `-- name: MarkSnapshotIndexing :exec
+UPDATE source_snapshots
+SET status = 'indexing', indexing_started_at = CURRENT_TIMESTAMP, failed_at = NULL, failure_reason = NULL
+WHERE id = $1 AND status <> 'indexed'
+`
+
+// スナップショットのインデックス化の開始を記録する（前回の失敗の記録は消去する）
+func (q *Queries) MarkSnapshotIndexing(ctx context.Context, id pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, markSnapshotIndexing, id)
+	return err
+}
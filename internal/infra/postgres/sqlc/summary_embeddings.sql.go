@@ -86,17 +86,82 @@ func (q *Queries) GetSummaryEmbedding(ctx context.Context, summaryID pgtype.UUID
 	return i, err
 }
 
+const rankProductsBySummaries = `-- name: RankProductsBySummaries :many
+WITH latest_snapshots AS (
+    SELECT DISTINCT ON (source_id) id, source_id
+    FROM source_snapshots
+    WHERE status = 'indexed'
+    ORDER BY source_id, indexed_at DESC NULLS LAST, created_at DESC
+),
+candidates AS (
+    SELECT src.product_id, (1 - (se.vector <=> $2))::float8 AS score
+    FROM summaries s
+    JOIN summary_embeddings se ON s.id = se.summary_id
+    JOIN latest_snapshots ls ON s.snapshot_id = ls.id
+    JOIN sources src ON ls.source_id = src.id
+    WHERE s.summary_type = 'architecture'
+    UNION ALL
+    SELECT ps.product_id, (1 - (ps.vector <=> $2::vector))::float8 AS score
+    FROM product_summaries ps
+    WHERE ps.model = $3
+)
+SELECT
+    p.id AS product_id,
+    p.name AS product_name,
+    MAX(c.score)::float8 AS score
+FROM candidates c
+JOIN products p ON c.product_id = p.id
+GROUP BY p.id, p.name
+ORDER BY score DESC, p.name
+LIMIT $1
+`
+
+type RankProductsBySummariesParams struct {
+	LimitVal    int32              `json:"limit_val"`
+	QueryVector pgvector_go.Vector `json:"query_vector"`
+	Model       string             `json:"model"`
+}
+
+type RankProductsBySummariesRow struct {
+	ProductID   pgtype.UUID `json:"product_id"`
+	ProductName string      `json:"product_name"`
+	Score       float64     `json:"score"`
+}
+
+// プロダクトを指定しない質問の振り分け用
+// 各プロダクトの最新のインデックス済みスナップショットのアーキテクチャ要約と、プロダクト全体の要約（model が主モデルのもの）の
+// 質問との類似度の最大値で、プロダクトを順位付けする
+func (q *Queries) RankProductsBySummaries(ctx context.Context, arg RankProductsBySummariesParams) ([]RankProductsBySummariesRow, error) {
+	rows, err := q.db.Query(ctx, rankProductsBySummaries, arg.LimitVal, arg.QueryVector, arg.Model)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []RankProductsBySummariesRow{}
+	for rows.Next() {
+		var i RankProductsBySummariesRow
+		if err := rows.Scan(&i.ProductID, &i.ProductName, &i.Score); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const searchArchitectureSummaryEmbeddings = `-- name: SearchArchitectureSummaryEmbeddings :many
-SELECT s.id, s.snapshot_id, s.summary_type, s.target_path, s.depth, s.parent_path, s.arch_type, s.content, s.content_hash, s.source_hash, s.metadata, s.created_at, s.updated_at, se.vector, (1 - (se.vector <=> $1::vector))::float8 AS score
+SELECT s.id, s.snapshot_id, s.summary_type, s.target_path, s.depth, s.parent_path, s.arch_type, s.content, s.content_hash, s.source_hash, s.metadata, s.created_at, s.updated_at, se.vector, (1 - (se.vector <=> $1))::float8 AS score
 FROM summaries s
 JOIN summary_embeddings se ON s.id = se.summary_id
 WHERE s.snapshot_id = $2 AND s.summary_type = 'architecture'
-ORDER BY se.vector <=> $1::vector
+ORDER BY se.vector <=> $1
 LIMIT $3
 `
 
 type SearchArchitectureSummaryEmbeddingsParams struct {
-	Column1    pgvector_go.Vector `json:"column_1"`
+	Vector     pgvector_go.Vector `json:"vector"`
 	SnapshotID pgtype.UUID        `json:"snapshot_id"`
 	Limit      int32              `json:"limit"`
 }
@@ -120,7 +185,7 @@ type SearchArchitectureSummaryEmbeddingsRow struct {
 }
 
 func (q *Queries) SearchArchitectureSummaryEmbeddings(ctx context.Context, arg SearchArchitectureSummaryEmbeddingsParams) ([]SearchArchitectureSummaryEmbeddingsRow, error) {
-	rows, err := q.db.Query(ctx, searchArchitectureSummaryEmbeddings, arg.Column1, arg.SnapshotID, arg.Limit)
+	rows, err := q.db.Query(ctx, searchArchitectureSummaryEmbeddings, arg.Vector, arg.SnapshotID, arg.Limit)
 	if err != nil {
 		return nil, err
 	}
@@ -156,16 +221,16 @@ func (q *Queries) SearchArchitectureSummaryEmbeddings(ctx context.Context, arg S
 }
 
 const searchDirectorySummaryEmbeddings = `-- name: SearchDirectorySummaryEmbeddings :many
-SELECT s.id, s.snapshot_id, s.summary_type, s.target_path, s.depth, s.parent_path, s.arch_type, s.content, s.content_hash, s.source_hash, s.metadata, s.created_at, s.updated_at, se.vector, (1 - (se.vector <=> $1::vector))::float8 AS score
+SELECT s.id, s.snapshot_id, s.summary_type, s.target_path, s.depth, s.parent_path, s.arch_type, s.content, s.content_hash, s.source_hash, s.metadata, s.created_at, s.updated_at, se.vector, (1 - (se.vector <=> $1))::float8 AS score
 FROM summaries s
 JOIN summary_embeddings se ON s.id = se.summary_id
 WHERE s.snapshot_id = $2 AND s.summary_type = 'directory'
-ORDER BY se.vector <=> $1::vector
+ORDER BY se.vector <=> $1
 LIMIT $3
 `
 
 type SearchDirectorySummaryEmbeddingsParams struct {
-	Column1    pgvector_go.Vector `json:"column_1"`
+	Vector     pgvector_go.Vector `json:"vector"`
 	SnapshotID pgtype.UUID        `json:"snapshot_id"`
 	Limit      int32              `json:"limit"`
 }
@@ -189,7 +254,7 @@ type SearchDirectorySummaryEmbeddingsRow struct {
 }
 
 func (q *Queries) SearchDirectorySummaryEmbeddings(ctx context.Context, arg SearchDirectorySummaryEmbeddingsParams) ([]SearchDirectorySummaryEmbeddingsRow, error) {
-	rows, err := q.db.Query(ctx, searchDirectorySummaryEmbeddings, arg.Column1, arg.SnapshotID, arg.Limit)
+	rows, err := q.db.Query(ctx, searchDirectorySummaryEmbeddings, arg.Vector, arg.SnapshotID, arg.Limit)
 	if err != nil {
 		return nil, err
 	}
@@ -224,17 +289,102 @@ func (q *Queries) SearchDirectorySummaryEmbeddings(ctx context.Context, arg Sear
 	return items, nil
 }
 
+const searchFileSummariesByScope = `-- name: SearchFileSummariesByScope :many
+WITH latest_snapshots AS (
+    SELECT DISTINCT ON (source_id) id, source_id
+    FROM source_snapshots
+    WHERE status = 'indexed'
+      AND ($8::uuid IS NULL OR id = $8::uuid)
+      AND ($9::uuid IS NULL OR source_id = $9::uuid)
+    ORDER BY source_id, indexed_at DESC NULLS LAST, created_at DESC
+)
+SELECT
+    f.id AS file_id,
+    f.path,
+    s.content,
+    (1 - (se.vector <=> $1))::float8 AS score
+FROM summaries s
+JOIN summary_embeddings se ON s.id = se.summary_id
+JOIN latest_snapshots ls ON s.snapshot_id = ls.id
+JOIN sources src ON ls.source_id = src.id
+JOIN files f ON f.snapshot_id = s.snapshot_id AND f.path = s.target_path
+WHERE s.summary_type = 'file'
+  AND ($2::uuid IS NULL OR src.product_id = $2::uuid)
+  AND ($3::text IS NULL OR f.path LIKE $3::text || '%')
+  AND (cardinality($4::text[]) = 0 OR f.domain = ANY($4::text[]))
+  AND (cardinality($5::text[]) = 0 OR f.language = ANY($5::text[]))
+  AND ($6::text IS NULL OR f.content_type = $6::text)
+ORDER BY se.vector <=> $1
+LIMIT $7
+`
+
+type SearchFileSummariesByScopeParams struct {
+	QueryVector pgvector_go.Vector `json:"query_vector"`
+	ProductID   pgtype.UUID        `json:"product_id"`
+	PathPrefix  pgtype.Text        `json:"path_prefix"`
+	Domains     []string           `json:"domains"`
+	Languages   []string           `json:"languages"`
+	ContentType pgtype.Text        `json:"content_type"`
+	LimitVal    int32              `json:"limit_val"`
+	SnapshotID  pgtype.UUID        `json:"snapshot_id"`
+	SourceID    pgtype.UUID        `json:"source_id"`
+}
+
+type SearchFileSummariesByScopeRow struct {
+	FileID  pgtype.UUID `json:"file_id"`
+	Path    string      `json:"path"`
+	Content string      `json:"content"`
+	Score   float64     `json:"score"`
+}
+
+// 2段階検索の1段目としてファイル要約をベクトル検索し、候補ファイルを返す
+// product_id / source_id / snapshot_id のうち指定されたもので範囲を限定する（snapshot_id 指定時は最新スナップショットの代わりに使用）
+func (q *Queries) SearchFileSummariesByScope(ctx context.Context, arg SearchFileSummariesByScopeParams) ([]SearchFileSummariesByScopeRow, error) {
+	rows, err := q.db.Query(ctx, searchFileSummariesByScope,
+		arg.QueryVector,
+		arg.ProductID,
+		arg.PathPrefix,
+		arg.Domains,
+		arg.Languages,
+		arg.ContentType,
+		arg.LimitVal,
+		arg.SnapshotID,
+		arg.SourceID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SearchFileSummariesByScopeRow{}
+	for rows.Next() {
+		var i SearchFileSummariesByScopeRow
+		if err := rows.Scan(
+			&i.FileID,
+			&i.Path,
+			&i.Content,
+			&i.Score,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const searchFileSummaryEmbeddings = `-- name: SearchFileSummaryEmbeddings :many
-SELECT s.id, s.snapshot_id, s.summary_type, s.target_path, s.depth, s.parent_path, s.arch_type, s.content, s.content_hash, s.source_hash, s.metadata, s.created_at, s.updated_at, se.vector, (1 - (se.vector <=> $1::vector))::float8 AS score
+SELECT s.id, s.snapshot_id, s.summary_type, s.target_path, s.depth, s.parent_path, s.arch_type, s.content, s.content_hash, s.source_hash, s.metadata, s.created_at, s.updated_at, se.vector, (1 - (se.vector <=> $1))::float8 AS score
 FROM summaries s
 JOIN summary_embeddings se ON s.id = se.summary_id
 WHERE s.snapshot_id = $2 AND s.summary_type = 'file'
-ORDER BY se.vector <=> $1::vector
+ORDER BY se.vector <=> $1
 LIMIT $3
 `
 
 type SearchFileSummaryEmbeddingsParams struct {
-	Column1    pgvector_go.Vector `json:"column_1"`
+	Vector     pgvector_go.Vector `json:"vector"`
 	SnapshotID pgtype.UUID        `json:"snapshot_id"`
 	Limit      int32              `json:"limit"`
 }
@@ -258,7 +408,7 @@ type SearchFileSummaryEmbeddingsRow struct {
 }
 
 func (q *Queries) SearchFileSummaryEmbeddings(ctx context.Context, arg SearchFileSummaryEmbeddingsParams) ([]SearchFileSummaryEmbeddingsRow, error) {
-	rows, err := q.db.Query(ctx, searchFileSummaryEmbeddings, arg.Column1, arg.SnapshotID, arg.Limit)
+	rows, err := q.db.Query(ctx, searchFileSummaryEmbeddings, arg.Vector, arg.SnapshotID, arg.Limit)
 	if err != nil {
 		return nil, err
 	}
@@ -297,7 +447,8 @@ const searchSummariesByProduct = `-- name: SearchSummariesByProduct :many
 WITH latest_snapshots AS (
     SELECT DISTINCT ON (source_id) id, source_id
     FROM source_snapshots
-    WHERE indexed = TRUE
+    WHERE status = 'indexed'
+      AND ($6::uuid IS NULL OR id = $6::uuid)
     ORDER BY source_id, indexed_at DESC NULLS LAST, created_at DESC
 )
 SELECT
@@ -307,7 +458,7 @@ SELECT
     s.target_path,
     s.arch_type,
     s.content,
-    (1 - (se.vector <=> $1::vector))::float8 as score
+    (1 - (se.vector <=> $1))::float8 as score
 FROM summaries s
 JOIN summary_embeddings se ON s.id = se.summary_id
 JOIN latest_snapshots ls ON s.snapshot_id = ls.id
@@ -315,7 +466,7 @@ JOIN sources src ON ls.source_id = src.id
 WHERE src.product_id = $2
   AND (cardinality($3::text[]) = 0 OR s.summary_type = ANY($3::text[]))
   AND ($4::text IS NULL OR s.target_path LIKE $4::text || '%')
-ORDER BY se.vector <=> $1::vector
+ORDER BY se.vector <=> $1
 LIMIT $5
 `
 
@@ -325,6 +476,7 @@ type SearchSummariesByProductParams struct {
 	SummaryTypes []string           `json:"summary_types"`
 	PathPrefix   pgtype.Text        `json:"path_prefix"`
 	LimitVal     int32              `json:"limit_val"`
+	SnapshotID   pgtype.UUID        `json:"snapshot_id"`
 }
 
 type SearchSummariesByProductRow struct {
@@ -337,6 +489,7 @@ type SearchSummariesByProductRow struct {
 	Score       float64     `json:"score"`
 }
 
+// snapshot_id 指定時は最新スナップショットの代わりにそのスナップショットのみを対象とする
 func (q *Queries) SearchSummariesByProduct(ctx context.Context, arg SearchSummariesByProductParams) ([]SearchSummariesByProductRow, error) {
 	rows, err := q.db.Query(ctx, searchSummariesByProduct,
 		arg.QueryVector,
@@ -344,6 +497,7 @@ func (q *Queries) SearchSummariesByProduct(ctx context.Context, arg SearchSummar
 		arg.SummaryTypes,
 		arg.PathPrefix,
 		arg.LimitVal,
+		arg.SnapshotID,
 	)
 	if err != nil {
 		return nil, err
@@ -378,13 +532,13 @@ SELECT
     s.target_path,
     s.arch_type,
     s.content,
-    (1 - (se.vector <=> $1::vector))::float8 as score
+    (1 - (se.vector <=> $1))::float8 as score
 FROM summaries s
 JOIN summary_embeddings se ON s.id = se.summary_id
 WHERE s.snapshot_id = $2
   AND (cardinality($3::text[]) = 0 OR s.summary_type = ANY($3::text[]))
   AND ($4::text IS NULL OR s.target_path LIKE $4::text || '%')
-ORDER BY se.vector <=> $1::vector
+ORDER BY se.vector <=> $1
 LIMIT $5
 `
 
@@ -439,16 +593,16 @@ func (q *Queries) SearchSummariesBySnapshot(ctx context.Context, arg SearchSumma
 }
 
 const searchSummaryEmbeddings = `-- name: SearchSummaryEmbeddings :many
-SELECT s.id, s.snapshot_id, s.summary_type, s.target_path, s.depth, s.parent_path, s.arch_type, s.content, s.content_hash, s.source_hash, s.metadata, s.created_at, s.updated_at, se.vector, (1 - (se.vector <=> $1::vector))::float8 AS score
+SELECT s.id, s.snapshot_id, s.summary_type, s.target_path, s.depth, s.parent_path, s.arch_type, s.content, s.content_hash, s.source_hash, s.metadata, s.created_at, s.updated_at, se.vector, (1 - (se.vector <=> $1))::float8 AS score
 FROM summaries s
 JOIN summary_embeddings se ON s.id = se.summary_id
 WHERE s.snapshot_id = $2
-ORDER BY se.vector <=> $1::vector
+ORDER BY se.vector <=> $1
 LIMIT $3
 `
 
 type SearchSummaryEmbeddingsParams struct {
-	Column1    pgvector_go.Vector `json:"column_1"`
+	Vector     pgvector_go.Vector `json:"vector"`
 	SnapshotID pgtype.UUID        `json:"snapshot_id"`
 	Limit      int32              `json:"limit"`
 }
@@ -472,7 +626,7 @@ type SearchSummaryEmbeddingsRow struct {
 }
 
 func (q *Queries) SearchSummaryEmbeddings(ctx context.Context, arg SearchSummaryEmbeddingsParams) ([]SearchSummaryEmbeddingsRow, error) {
-	rows, err := q.db.Query(ctx, searchSummaryEmbeddings, arg.Column1, arg.SnapshotID, arg.Limit)
+	rows, err := q.db.Query(ctx, searchSummaryEmbeddings, arg.Vector, arg.SnapshotID, arg.Limit)
 	if err != nil {
 		return nil, err
 	}
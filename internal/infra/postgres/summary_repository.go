@@ -297,6 +297,74 @@ func (r *SummaryRepository) GetSummaryEmbedding(ctx context.Context, summaryID u
 	}), nil
 }
 
+// === プロダクト全体の要約 ===
+
+func (r *SummaryRepository) ListLatestArchitectureSummariesByProduct(ctx context.Context, productID uuid.UUID) ([]*summary.SourceArchitectureSummary, error) {
+	rows, err := r.q.ListLatestArchitectureSummariesByProduct(ctx, UUIDToPgtype(productID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list architecture summaries by product: %w", err)
+	}
+
+	result := make([]*summary.SourceArchitectureSummary, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, &summary.SourceArchitectureSummary{
+			SourceName:  row.SourceName,
+			ArchType:    summary.ArchType(row.ArchType.String),
+			Content:     row.Content,
+			ContentHash: row.ContentHash,
+		})
+	}
+	return result, nil
+}
+
+func (r *SummaryRepository) GetProductSummary(ctx context.Context, productID uuid.UUID) (mo.Option[*summary.ProductSummary], error) {
+	row, err := r.q.GetProductSummary(ctx, UUIDToPgtype(productID))
+	if err != nil {
+		if err == pgx.ErrNoRows || err == sql.ErrNoRows {
+			return mo.None[*summary.ProductSummary](), nil
+		}
+		return mo.None[*summary.ProductSummary](), fmt.Errorf("failed to get product summary: %w", err)
+	}
+
+	return mo.Some(&summary.ProductSummary{
+		ProductID:     PgtypeToUUID(row.ProductID),
+		Content:       row.Content,
+		Description:   row.Description,
+		SourceHash:    row.SourceHash,
+		PromptVersion: row.PromptVersion,
+		Vector:        row.Vector.Slice(),
+		Model:         row.Model,
+		UpdatedAt:     PgtypeToTime(row.UpdatedAt),
+	}), nil
+}
+
+func (r *SummaryRepository) UpsertProductSummary(ctx context.Context, s *summary.ProductSummary) error {
+	if err := r.q.UpsertProductSummary(ctx, sqlc.UpsertProductSummaryParams{
+		ProductID:     UUIDToPgtype(s.ProductID),
+		Content:       s.Content,
+		Description:   s.Description,
+		SourceHash:    s.SourceHash,
+		PromptVersion: s.PromptVersion,
+		Vector:        pgvector.NewVector(s.Vector),
+		Model:         s.Model,
+	}); err != nil {
+		return fmt.Errorf("failed to upsert product summary: %w", err)
+	}
+	return nil
+}
+
+func (r *SummaryRepository) FillProductDescription(ctx context.Context, productID uuid.UUID, description, previous string) (bool, error) {
+	affected, err := r.q.FillProductDescription(ctx, sqlc.FillProductDescriptionParams{
+		Description: StringPtrToPgtext(&description),
+		ID:          UUIDToPgtype(productID),
+		Previous:    previous,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to fill product description: %w", err)
+	}
+	return affected > 0, nil
+}
+
 // === 検索 ===
 
 // SummarySearchByProductParams はプロダクト横断検索のパラメータ
@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+
+	"github.com/jinford/dev-rag/internal/core/symbol"
+	"github.com/jinford/dev-rag/internal/infra/postgres/sqlc"
+)
+
+// RebuildSymbols はスナップショットのシンボルをチャンクのメタデータから作り直し、登録したシンボル数を返す
+func (r *Repository) RebuildSymbols(ctx context.Context, snapshotID uuid.UUID) (int, error) {
+	if err := r.q.DeleteSymbolsBySnapshot(ctx, UUIDToPgtype(snapshotID)); err != nil {
+		return 0, fmt.Errorf("failed to delete symbols: %w", err)
+	}
+	count, err := r.q.CreateSymbolsFromChunks(ctx, UUIDToPgtype(snapshotID))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create symbols: %w", err)
+	}
+	return int(count), nil
+}
+
+// SymbolRepository は core/symbol.Reader を実装する PostgreSQL リポジトリ。
+type SymbolRepository struct {
+	q sqlc.Querier
+}
+
+// NewSymbolRepository は新しい SymbolRepository を返す。
+func NewSymbolRepository(q sqlc.Querier) *SymbolRepository {
+	return &SymbolRepository{q: q}
+}
+
+var _ symbol.Reader = (*SymbolRepository)(nil)
+
+func (r *SymbolRepository) FindSymbols(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID], query symbol.Query) ([]*symbol.Symbol, error) {
+	limit := query.Limit
+	if limit <= 0 {
+		limit = symbol.DefaultLimit
+	}
+	rows, err := r.q.FindSymbols(ctx, sqlc.FindSymbolsParams{
+		Name:       query.Name,
+		ParentName: StringToNullableText(query.ParentName),
+		Kind:       StringToNullableText(query.Kind),
+		MaxResults: int32(limit),
+		ProductID:  UUIDOptionToPgtype(productID),
+		SnapshotID: UUIDOptionToPgtype(snapshotID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find symbols: %w", err)
+	}
+
+	symbols := make([]*symbol.Symbol, 0, len(rows))
+	for _, row := range rows {
+		symbols = append(symbols, &symbol.Symbol{
+			ID:         PgtypeToUUID(row.ID),
+			SnapshotID: PgtypeToUUID(row.SnapshotID),
+			ChunkID:    PgtypeToUUID(row.ChunkID),
+			FileID:     PgtypeToUUID(row.FileID),
+			SourceName: row.SourceName,
+			FilePath:   row.FilePath,
+			Name:       row.Name,
+			Kind:       row.Kind,
+			ParentName: row.ParentName,
+			StartLine:  int(row.StartLine),
+			EndLine:    int(row.EndLine),
+		})
+	}
+	return symbols, nil
+}
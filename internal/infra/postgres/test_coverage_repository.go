@@ -0,0 +1,108 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/samber/mo"
+
+	"github.com/jinford/dev-rag/internal/core/testcoverage"
+	"github.com/jinford/dev-rag/internal/infra/postgres/sqlc"
+)
+
+// TestCoverageRepository は core/testcoverage.Repository を実装する PostgreSQL リポジトリ。
+type TestCoverageRepository struct {
+	q sqlc.Querier
+}
+
+// NewTestCoverageRepository は新しい TestCoverageRepository を返す。
+func NewTestCoverageRepository(q sqlc.Querier) *TestCoverageRepository {
+	return &TestCoverageRepository{q: q}
+}
+
+var _ testcoverage.Repository = (*TestCoverageRepository)(nil)
+
+func (r *TestCoverageRepository) ListCoverageTargets(ctx context.Context, snapshotID uuid.UUID) ([]*testcoverage.TargetFile, error) {
+	rows, err := r.q.ListTestCoverageTargets(ctx, UUIDToPgtype(snapshotID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list test coverage targets: %w", err)
+	}
+
+	var targets []*testcoverage.TargetFile
+	byID := make(map[uuid.UUID]*testcoverage.TargetFile)
+	for _, row := range rows {
+		fileID := PgtypeToUUID(row.FileID)
+		target, ok := byID[fileID]
+		if !ok {
+			target = &testcoverage.TargetFile{FileID: fileID, Path: row.Path}
+			byID[fileID] = target
+			targets = append(targets, target)
+		}
+		if row.ChunkID.Valid {
+			target.Chunks = append(target.Chunks, testcoverage.TargetChunk{
+				ChunkID:   PgtypeToUUID(row.ChunkID),
+				StartLine: PgtypeToInt(row.StartLine),
+				EndLine:   PgtypeToInt(row.EndLine),
+			})
+		}
+	}
+	return targets, nil
+}
+
+func (r *TestCoverageRepository) ReplaceCoverage(ctx context.Context, snapshotID uuid.UUID, files []*testcoverage.FileCoverage, chunks []*testcoverage.ChunkCoverage) error {
+	if err := r.q.DeleteChunkTestCoverageBySnapshot(ctx, UUIDToPgtype(snapshotID)); err != nil {
+		return fmt.Errorf("failed to delete chunk test coverage: %w", err)
+	}
+	if err := r.q.DeleteFileTestCoverageBySnapshot(ctx, UUIDToPgtype(snapshotID)); err != nil {
+		return fmt.Errorf("failed to delete file test coverage: %w", err)
+	}
+	for _, f := range files {
+		err := r.q.CreateFileTestCoverage(ctx, sqlc.CreateFileTestCoverageParams{
+			FileID:            UUIDToPgtype(f.FileID),
+			SnapshotID:        UUIDToPgtype(snapshotID),
+			Statements:        int32(f.Statements),
+			CoveredStatements: int32(f.Covered),
+			Format:            string(f.Format),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create file test coverage: %w", err)
+		}
+	}
+	for _, c := range chunks {
+		err := r.q.CreateChunkTestCoverage(ctx, sqlc.CreateChunkTestCoverageParams{
+			ChunkID:           UUIDToPgtype(c.ChunkID),
+			Statements:        int32(c.Statements),
+			CoveredStatements: int32(c.Covered),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create chunk test coverage: %w", err)
+		}
+	}
+	return nil
+}
+
+func (r *TestCoverageRepository) ListFileCoverage(ctx context.Context, productID, snapshotID mo.Option[uuid.UUID]) ([]*testcoverage.FileCoverage, error) {
+	rows, err := r.q.ListFileTestCoverage(ctx, sqlc.ListFileTestCoverageParams{
+		ProductID:  UUIDOptionToPgtype(productID),
+		SnapshotID: UUIDOptionToPgtype(snapshotID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list file test coverage: %w", err)
+	}
+
+	coverages := make([]*testcoverage.FileCoverage, 0, len(rows))
+	for _, row := range rows {
+		coverages = append(coverages, &testcoverage.FileCoverage{
+			FileID:     PgtypeToUUID(row.FileID),
+			SnapshotID: PgtypeToUUID(row.SnapshotID),
+			SourceName: row.SourceName,
+			FilePath:   row.Path,
+			Statements: int(row.Statements),
+			Covered:    int(row.CoveredStatements),
+			Format:     testcoverage.Format(row.Format),
+			ImportedAt: PgtypeToTime(row.ImportedAt),
+		})
+	}
+	return coverages, nil
+}
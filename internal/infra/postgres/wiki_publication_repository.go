@@ -0,0 +1,84 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/jinford/dev-rag/internal/core/wiki"
+	"github.com/jinford/dev-rag/internal/infra/postgres/sqlc"
+)
+
+// WikiPublicationRepository は core/wiki.PublicationStore を実装する PostgreSQL リポジトリ。
+type WikiPublicationRepository struct {
+	q sqlc.Querier
+}
+
+// NewWikiPublicationRepository は新しい WikiPublicationRepository を返す。
+func NewWikiPublicationRepository(q sqlc.Querier) *WikiPublicationRepository {
+	return &WikiPublicationRepository{q: q}
+}
+
+var _ wiki.PublicationStore = (*WikiPublicationRepository)(nil)
+
+func (r *WikiPublicationRepository) ListPublications(ctx context.Context, productID uuid.UUID, target, destination string, language wiki.Language) ([]*wiki.Publication, error) {
+	rows, err := r.q.ListWikiPublications(ctx, sqlc.ListWikiPublicationsParams{
+		ProductID:   UUIDToPgtype(productID),
+		Target:      target,
+		Destination: destination,
+		Language:    string(language),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list wiki publications: %w", err)
+	}
+
+	publications := make([]*wiki.Publication, 0, len(rows))
+	for _, row := range rows {
+		publications = append(publications, convertWikiPublication(row))
+	}
+	return publications, nil
+}
+
+func (r *WikiPublicationRepository) SavePublication(ctx context.Context, publication *wiki.Publication) (*wiki.Publication, error) {
+	row, err := r.q.UpsertWikiPublication(ctx, sqlc.UpsertWikiPublicationParams{
+		ProductID:      UUIDToPgtype(publication.ProductID),
+		Target:         publication.Target,
+		Destination:    publication.Destination,
+		Language:       string(publication.Language),
+		PagePath:       publication.PagePath,
+		RemoteID:       publication.RemoteID,
+		RemoteVersion:  int32(publication.RemoteVersion),
+		ParentRemoteID: StringPtrToPgtext(publication.ParentRemoteID),
+		Title:          publication.Title,
+		ContentHash:    publication.ContentHash,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save wiki publication: %w", err)
+	}
+	return convertWikiPublication(row), nil
+}
+
+func (r *WikiPublicationRepository) DeletePublication(ctx context.Context, id uuid.UUID) error {
+	if err := r.q.DeleteWikiPublication(ctx, UUIDToPgtype(id)); err != nil {
+		return fmt.Errorf("failed to delete wiki publication: %w", err)
+	}
+	return nil
+}
+
+func convertWikiPublication(row sqlc.WikiPublication) *wiki.Publication {
+	return &wiki.Publication{
+		ID:             PgtypeToUUID(row.ID),
+		ProductID:      PgtypeToUUID(row.ProductID),
+		Target:         row.Target,
+		Destination:    row.Destination,
+		Language:       wiki.Language(row.Language),
+		PagePath:       row.PagePath,
+		RemoteID:       row.RemoteID,
+		RemoteVersion:  int(row.RemoteVersion),
+		ParentRemoteID: PgtextToStringPtr(row.ParentRemoteID),
+		Title:          row.Title,
+		ContentHash:    row.ContentHash,
+		PublishedAt:    PgtypeToTime(row.PublishedAt),
+	}
+}
@@ -0,0 +1,168 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// Config はプロバイダー単位のレート制限設定
+// 0 以下の値はその制限を無効にする
+type Config struct {
+	RequestsPerMinute int // 1分あたりの最大リクエスト数（RPM）
+	TokensPerMinute   int // 1分あたりの最大トークン数（TPM）
+	MaxConcurrency    int // 同時実行数の上限
+}
+
+// Limiter はRPM/TPMのトークンバケットと同時実行数の上限を組み合わせたレートリミッター
+// 同一プロバイダーを利用する Embedder と LLM クライアントで共有する
+type Limiter struct {
+	mu       sync.Mutex
+	requests *bucket
+	tokens   *bucket
+	sem      chan struct{}
+	now      func() time.Time
+}
+
+// NewLimiter は設定から Limiter を作成する
+func NewLimiter(cfg Config) *Limiter {
+	l := &Limiter{now: time.Now}
+	start := l.now()
+
+	if cfg.RequestsPerMinute > 0 {
+		l.requests = newBucket(float64(cfg.RequestsPerMinute), start)
+	}
+	if cfg.TokensPerMinute > 0 {
+		l.tokens = newBucket(float64(cfg.TokensPerMinute), start)
+	}
+	if cfg.MaxConcurrency > 0 {
+		l.sem = make(chan struct{}, cfg.MaxConcurrency)
+	}
+
+	return l
+}
+
+// Acquire は1リクエスト分と指定トークン数の枠を確保するまで待機する
+// 戻り値の release は呼び出し完了後に必ず呼ぶこと
+func (l *Limiter) Acquire(ctx context.Context, tokens int) (release func(), err error) {
+	if l.sem != nil {
+		select {
+		case l.sem <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	release = func() {
+		if l.sem != nil {
+			<-l.sem
+		}
+	}
+
+	if err := l.wait(ctx, tokens); err != nil {
+		release()
+		return nil, err
+	}
+
+	return release, nil
+}
+
+// wait はバケットに十分な残量ができるまで待機してから消費する
+func (l *Limiter) wait(ctx context.Context, tokens int) error {
+	for {
+		delay := l.reserve(float64(tokens))
+		if delay <= 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// reserve は消費できれば消費して 0 を、できなければ必要な待機時間を返す
+func (l *Limiter) reserve(tokens float64) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	var delay time.Duration
+	if l.requests != nil {
+		delay = max(delay, l.requests.delay(1, now))
+	}
+	if l.tokens != nil {
+		delay = max(delay, l.tokens.delay(tokens, now))
+	}
+	if delay > 0 {
+		return delay
+	}
+
+	if l.requests != nil {
+		l.requests.take(1)
+	}
+	if l.tokens != nil {
+		l.tokens.take(tokens)
+	}
+	return 0
+}
+
+// bucket は1分あたりの容量で補充されるトークンバケット
+type bucket struct {
+	capacity  float64
+	available float64
+	perSecond float64
+	last      time.Time
+}
+
+func newBucket(perMinute float64, now time.Time) *bucket {
+	return &bucket{
+		capacity:  perMinute,
+		available: perMinute,
+		perSecond: perMinute / 60,
+		last:      now,
+	}
+}
+
+// delay は n を消費できるまでの待機時間を返す
+// 容量を超える要求は容量いっぱいまで補充された時点で許可する
+func (b *bucket) delay(n float64, now time.Time) time.Duration {
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.available = math.Min(b.capacity, b.available+elapsed*b.perSecond)
+		b.last = now
+	}
+
+	need := math.Min(n, b.capacity)
+	if b.available >= need {
+		return 0
+	}
+	return time.Duration((need - b.available) / b.perSecond * float64(time.Second))
+}
+
+func (b *bucket) take(n float64) {
+	// 容量超過の要求は残量を負にして、次回以降の補充で相殺する
+	b.available -= n
+}
+
+// Backoff は試行回数に応じた指数バックオフにジッターを加えた待機時間を返す
+// 複数ジョブが同時に429を受けた場合にリトライのタイミングを分散させる
+func Backoff(attempt int, base, maxDelay time.Duration) time.Duration {
+	d := time.Duration(float64(base) * math.Pow(2, float64(attempt)))
+	if d <= 0 || d > maxDelay {
+		d = maxDelay
+	}
+	half := d / 2
+	return half + time.Duration(rand.Int64N(int64(half)+1))
+}
+
+// EstimateTokens はテキストのトークン数を概算する
+// 正確なトークナイザを持たないプロバイダー向けに、UTF-8 の4バイトを1トークンとみなす
+func EstimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
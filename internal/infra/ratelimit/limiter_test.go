@@ -0,0 +1,108 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiter_Reserve(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := NewLimiter(Config{RequestsPerMinute: 60, TokensPerMinute: 600})
+	l.now = func() time.Time { return now }
+	l.requests.last, l.tokens.last = now, now
+
+	// 容量内の要求は即時に許可される
+	assert.Zero(t, l.reserve(500))
+
+	// トークン残量（100）を超える要求は補充待ちになる（10 tokens/sec）
+	delay := l.reserve(300)
+	assert.Equal(t, 20*time.Second, delay)
+
+	// 時間経過で補充されれば許可される
+	now = now.Add(20 * time.Second)
+	assert.Zero(t, l.reserve(300))
+}
+
+func TestLimiter_OversizedRequest(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := NewLimiter(Config{TokensPerMinute: 100})
+	l.now = func() time.Time { return now }
+	l.tokens.last = now
+
+	// 容量を超える要求もバケットが満タンなら許可される
+	assert.Zero(t, l.reserve(250))
+
+	// 超過分は後続の要求を遅延させる
+	assert.Greater(t, l.reserve(10), time.Minute)
+}
+
+func TestLimiter_ConcurrencyCap(t *testing.T) {
+	l := NewLimiter(Config{MaxConcurrency: 1})
+
+	release, err := l.Acquire(context.Background(), 0)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = l.Acquire(ctx, 0)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	release()
+	release2, err := l.Acquire(context.Background(), 0)
+	require.NoError(t, err)
+	release2()
+}
+
+type flakyCompleter struct {
+	failures int
+	calls    int
+}
+
+var errRateLimited = errors.New("429 too many requests")
+
+func (c *flakyCompleter) GenerateCompletion(ctx context.Context, prompt string) (string, error) {
+	c.calls++
+	if c.calls <= c.failures {
+		return "", errRateLimited
+	}
+	return "ok", nil
+}
+
+func TestLLMClient_RetriesRetryableErrors(t *testing.T) {
+	inner := &flakyCompleter{failures: 2}
+	client := NewLLMClient(inner, NewLimiter(Config{}),
+		WithRetryable(func(err error) bool { return errors.Is(err, errRateLimited) }),
+		WithBackoff(time.Millisecond, 2*time.Millisecond),
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+	)
+
+	out, err := client.GenerateCompletion(context.Background(), "hello")
+	require.NoError(t, err)
+	assert.Equal(t, "ok", out)
+	assert.Equal(t, 3, inner.calls)
+}
+
+func TestLLMClient_NonRetryableErrorReturnsImmediately(t *testing.T) {
+	inner := &flakyCompleter{failures: 5}
+	client := NewLLMClient(inner, NewLimiter(Config{}))
+
+	_, err := client.GenerateCompletion(context.Background(), "hello")
+	assert.ErrorIs(t, err, errRateLimited)
+	assert.Equal(t, 1, inner.calls)
+}
+
+func TestBackoff_Jitter(t *testing.T) {
+	for attempt := range 5 {
+		d := Backoff(attempt, time.Second, 8*time.Second)
+		upper := min(time.Second<<attempt, 8*time.Second)
+		assert.GreaterOrEqual(t, d, upper/2)
+		assert.LessOrEqual(t, d, upper)
+	}
+}
@@ -0,0 +1,33 @@
+package ratelimit
+
+import "sync"
+
+// Registry はプロバイダー名ごとに Limiter を共有するためのレジストリ
+type Registry struct {
+	mu       sync.Mutex
+	configs  map[string]Config
+	limiters map[string]*Limiter
+}
+
+// NewRegistry はプロバイダーごとの設定から Registry を作成する
+func NewRegistry(configs map[string]Config) *Registry {
+	return &Registry{
+		configs:  configs,
+		limiters: make(map[string]*Limiter),
+	}
+}
+
+// Get はプロバイダーに対応する Limiter を返す
+// 同じプロバイダー名に対しては常に同じ Limiter を返す（設定がなければ無制限）
+func (r *Registry) Get(provider string) *Limiter {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if l, ok := r.limiters[provider]; ok {
+		return l
+	}
+
+	l := NewLimiter(r.configs[provider])
+	r.limiters[provider] = l
+	return l
+}
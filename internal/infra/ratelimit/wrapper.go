@@ -0,0 +1,207 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jinford/dev-rag/internal/core/ingestion"
+)
+
+const (
+	// DefaultMaxRetries はレート制限エラー時の既定リトライ回数
+	DefaultMaxRetries = 5
+
+	// DefaultBaseBackoff はバックオフの基底時間
+	DefaultBaseBackoff = 1 * time.Second
+
+	// DefaultMaxBackoff はバックオフの最大待機時間
+	DefaultMaxBackoff = 60 * time.Second
+)
+
+// Completer はテキスト生成を行うLLMクライアントのインターフェース
+type Completer interface {
+	GenerateCompletion(ctx context.Context, prompt string) (string, error)
+}
+
+type wrapperOptions struct {
+	retryable    func(error) bool
+	countTokens  func(string) int
+	maxRetries   int
+	baseBackoff  time.Duration
+	maxBackoff   time.Duration
+	logger       *slog.Logger
+	providerName string
+}
+
+// Option はラッパーのオプション設定
+type Option func(*wrapperOptions)
+
+// WithRetryable はリトライ対象のエラーを判定する関数を設定する
+// 未設定の場合はリトライしない
+func WithRetryable(fn func(error) bool) Option {
+	return func(o *wrapperOptions) {
+		o.retryable = fn
+	}
+}
+
+// WithTokenCounter はトークン数の計算方法を設定する
+func WithTokenCounter(fn func(string) int) Option {
+	return func(o *wrapperOptions) {
+		o.countTokens = fn
+	}
+}
+
+// WithMaxRetries は最大リトライ回数を設定する
+func WithMaxRetries(n int) Option {
+	return func(o *wrapperOptions) {
+		o.maxRetries = n
+	}
+}
+
+// WithBackoff はバックオフの基底時間と最大時間を設定する
+func WithBackoff(base, maxDelay time.Duration) Option {
+	return func(o *wrapperOptions) {
+		o.baseBackoff = base
+		o.maxBackoff = maxDelay
+	}
+}
+
+// WithLogger はロガーを設定する
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *wrapperOptions) {
+		o.logger = logger
+	}
+}
+
+// WithProviderName はログ出力用のプロバイダー名を設定する
+func WithProviderName(name string) Option {
+	return func(o *wrapperOptions) {
+		o.providerName = name
+	}
+}
+
+func newWrapperOptions(opts []Option) wrapperOptions {
+	options := wrapperOptions{
+		countTokens: EstimateTokens,
+		maxRetries:  DefaultMaxRetries,
+		baseBackoff: DefaultBaseBackoff,
+		maxBackoff:  DefaultMaxBackoff,
+		logger:      slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.logger == nil {
+		options.logger = slog.Default()
+	}
+	return options
+}
+
+// do はリミッターの枠を確保して fn を実行し、リトライ対象のエラーであればバックオフ後に再試行する
+func do[T any](ctx context.Context, limiter *Limiter, opts *wrapperOptions, tokens int, op string, fn func(ctx context.Context) (T, error)) (T, error) {
+	var zero T
+	var lastErr error
+
+	for attempt := 0; attempt <= opts.maxRetries; attempt++ {
+		if attempt > 0 {
+			delay := Backoff(attempt-1, opts.baseBackoff, opts.maxBackoff)
+			opts.logger.Warn("rate limited, retrying",
+				"provider", opts.providerName,
+				"operation", op,
+				"attempt", attempt,
+				"delay", delay,
+				"error", lastErr,
+			)
+
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return zero, ctx.Err()
+			case <-timer.C:
+			}
+		}
+
+		release, err := limiter.Acquire(ctx, tokens)
+		if err != nil {
+			return zero, fmt.Errorf("failed to acquire rate limit: %w", err)
+		}
+		result, err := fn(ctx)
+		release()
+
+		if err == nil {
+			return result, nil
+		}
+		if opts.retryable == nil || !opts.retryable(err) {
+			return zero, err
+		}
+		lastErr = err
+	}
+
+	return zero, fmt.Errorf("rate limit retries exhausted (%d attempts): %w", opts.maxRetries+1, lastErr)
+}
+
+// Embedder はレート制限を適用する ingestion.Embedder のラッパー
+type Embedder struct {
+	ingestion.Embedder
+	limiter *Limiter
+	opts    wrapperOptions
+}
+
+// NewEmbedder は Embedder をレート制限付きでラップする
+func NewEmbedder(inner ingestion.Embedder, limiter *Limiter, opts ...Option) *Embedder {
+	return &Embedder{
+		Embedder: inner,
+		limiter:  limiter,
+		opts:     newWrapperOptions(opts),
+	}
+}
+
+// Embed は単一テキストの Embedding をレート制限付きで生成する
+func (e *Embedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	return do(ctx, e.limiter, &e.opts, e.opts.countTokens(text), "embed", func(ctx context.Context) ([]float32, error) {
+		return e.Embedder.Embed(ctx, text)
+	})
+}
+
+// BatchEmbed はバッチで Embedding をレート制限付きで生成する
+func (e *Embedder) BatchEmbed(ctx context.Context, texts []string) ([][]float32, error) {
+	tokens := 0
+	for _, text := range texts {
+		tokens += e.opts.countTokens(text)
+	}
+	return do(ctx, e.limiter, &e.opts, tokens, "batch_embed", func(ctx context.Context) ([][]float32, error) {
+		return e.Embedder.BatchEmbed(ctx, texts)
+	})
+}
+
+// LLMClient はレート制限を適用する LLM クライアントのラッパー
+type LLMClient struct {
+	inner   Completer
+	limiter *Limiter
+	opts    wrapperOptions
+}
+
+// NewLLMClient は LLM クライアントをレート制限付きでラップする
+func NewLLMClient(inner Completer, limiter *Limiter, opts ...Option) *LLMClient {
+	return &LLMClient{
+		inner:   inner,
+		limiter: limiter,
+		opts:    newWrapperOptions(opts),
+	}
+}
+
+// GenerateCompletion はテキスト生成をレート制限付きで実行する
+func (c *LLMClient) GenerateCompletion(ctx context.Context, prompt string) (string, error) {
+	return do(ctx, c.limiter, &c.opts, c.opts.countTokens(prompt), "completion", func(ctx context.Context) (string, error) {
+		return c.inner.GenerateCompletion(ctx, prompt)
+	})
+}
+
+// インターフェース実装の確認
+var (
+	_ ingestion.Embedder = (*Embedder)(nil)
+	_ Completer          = (*LLMClient)(nil)
+)
@@ -0,0 +1,264 @@
+package vertex
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/jinford/dev-rag/internal/core/ingestion"
+)
+
+const (
+	// DefaultLocation はリージョン未指定時のデフォルト
+	DefaultLocation = "us-central1"
+
+	// DefaultEmbeddingModel はモデル未指定時のデフォルトモデル
+	DefaultEmbeddingModel = "text-embedding-005"
+
+	// DefaultMaxInputTokens はtext-embedding-005 の最大入力トークン数
+	DefaultMaxInputTokens = 2048
+
+	// DefaultTimeout はAPI呼び出しのデフォルトタイムアウト
+	DefaultTimeout = 60 * time.Second
+
+	// maxBatchSize は1リクエストあたりのインスタンス数の上限
+	maxBatchSize = 250
+)
+
+// ErrAccessTokenNotSet はアクセストークンが設定されていない場合のエラー
+var ErrAccessTokenNotSet = errors.New("Vertex AI access token not set: please set VERTEX_ACCESS_TOKEN (e.g. gcloud auth print-access-token)")
+
+// APIError は Vertex AI API がエラーステータスを返した場合のエラー
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("vertex AI API error (status %d): %s", e.StatusCode, e.Message)
+}
+
+// IsRateLimitError はエラーがクォータ超過（HTTP 429）によるものかを判定する
+func IsRateLimitError(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests
+	}
+	return false
+}
+
+// Embedder は Vertex AI のテキスト埋め込みモデルを使用してテキストをベクトルに変換する
+type Embedder struct {
+	httpClient     *http.Client
+	endpoint       string
+	accessToken    string
+	model          string
+	dimension      int
+	maxInputTokens int
+}
+
+type embedderOptions struct {
+	location       string
+	model          string
+	dimension      int
+	maxInputTokens int
+	httpClient     *http.Client
+}
+
+// EmbedderOption は Embedder のオプション設定
+type EmbedderOption func(*embedderOptions)
+
+// WithLocation はリージョンを上書きする
+func WithLocation(location string) EmbedderOption {
+	return func(o *embedderOptions) {
+		o.location = location
+	}
+}
+
+// WithEmbeddingModel はモデル名を上書きする
+func WithEmbeddingModel(model string) EmbedderOption {
+	return func(o *embedderOptions) {
+		o.model = model
+	}
+}
+
+// WithEmbeddingDimension は出力次元（outputDimensionality）を指定する
+func WithEmbeddingDimension(dimension int) EmbedderOption {
+	return func(o *embedderOptions) {
+		o.dimension = dimension
+	}
+}
+
+// WithMaxInputTokens は1テキストあたりの最大入力トークン数を上書きする
+func WithMaxInputTokens(tokens int) EmbedderOption {
+	return func(o *embedderOptions) {
+		o.maxInputTokens = tokens
+	}
+}
+
+// WithHTTPClient は HTTP クライアントを差し替える
+func WithHTTPClient(client *http.Client) EmbedderOption {
+	return func(o *embedderOptions) {
+		o.httpClient = client
+	}
+}
+
+// NewEmbedder は新しい Embedder を作成する
+func NewEmbedder(project, accessToken string, opts ...EmbedderOption) (*Embedder, error) {
+	if project == "" {
+		return nil, fmt.Errorf("Vertex AI project not set: please set VERTEX_PROJECT")
+	}
+	if accessToken == "" {
+		return nil, ErrAccessTokenNotSet
+	}
+
+	options := embedderOptions{
+		location:       DefaultLocation,
+		model:          DefaultEmbeddingModel,
+		maxInputTokens: DefaultMaxInputTokens,
+		httpClient:     &http.Client{Timeout: DefaultTimeout},
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	endpoint := fmt.Sprintf(
+		"https://%s-aiplatform.googleapis.com/v1/projects/%s/locations/%s/publishers/google/models/%s:predict",
+		options.location, project, options.location, options.model,
+	)
+
+	return &Embedder{
+		httpClient:     options.httpClient,
+		endpoint:       endpoint,
+		accessToken:    accessToken,
+		model:          options.model,
+		dimension:      options.dimension,
+		maxInputTokens: options.maxInputTokens,
+	}, nil
+}
+
+type predictRequest struct {
+	Instances  []predictInstance  `json:"instances"`
+	Parameters *predictParameters `json:"parameters,omitempty"`
+}
+
+type predictInstance struct {
+	Content  string `json:"content"`
+	TaskType string `json:"task_type,omitempty"`
+}
+
+type predictParameters struct {
+	OutputDimensionality int `json:"outputDimensionality,omitempty"`
+}
+
+type predictResponse struct {
+	Predictions []struct {
+		Embeddings struct {
+			Values []float32 `json:"values"`
+		} `json:"embeddings"`
+	} `json:"predictions"`
+}
+
+// Embed は単一テキストの Embedding を生成する
+func (e *Embedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	embeddings, err := e.BatchEmbed(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(embeddings) == 0 {
+		return nil, fmt.Errorf("no embeddings generated")
+	}
+
+	return embeddings[0], nil
+}
+
+// BatchEmbed はバッチで Embedding を生成する
+func (e *Embedder) BatchEmbed(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return nil, fmt.Errorf("no texts provided")
+	}
+
+	if len(texts) > maxBatchSize {
+		return nil, fmt.Errorf("batch size exceeds maximum of %d", maxBatchSize)
+	}
+
+	payload := predictRequest{Instances: make([]predictInstance, 0, len(texts))}
+	for _, text := range texts {
+		payload.Instances = append(payload.Instances, predictInstance{Content: text, TaskType: "RETRIEVAL_DOCUMENT"})
+	}
+	if e.dimension > 0 {
+		payload.Parameters = &predictParameters{OutputDimensionality: e.dimension}
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+e.accessToken)
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate embeddings: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("failed to generate embeddings: %w", &APIError{StatusCode: resp.StatusCode, Message: string(msg)})
+	}
+
+	var result predictResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if len(result.Predictions) != len(texts) {
+		return nil, fmt.Errorf("embedding count mismatch: expected %d, got %d", len(texts), len(result.Predictions))
+	}
+
+	embeddings := make([][]float32, 0, len(result.Predictions))
+	for _, p := range result.Predictions {
+		vector := p.Embeddings.Values
+		if e.dimension > 0 && len(vector) != e.dimension {
+			return nil, fmt.Errorf("model %s returned %d-dimensional vectors but %d is configured", e.model, len(vector), e.dimension)
+		}
+		embeddings = append(embeddings, vector)
+	}
+
+	return embeddings, nil
+}
+
+// ModelName はプロバイダーを含むモデル名を返す
+func (e *Embedder) ModelName() string {
+	return "vertex/" + e.model
+}
+
+// Dimension はベクトル次元数を返す
+func (e *Embedder) Dimension() int {
+	return e.dimension
+}
+
+// MaxBatchSize はバッチ処理の最大サイズを返す
+func (e *Embedder) MaxBatchSize() int {
+	return maxBatchSize
+}
+
+// MaxInputTokens は1テキストあたりの最大入力トークン数を返す
+func (e *Embedder) MaxInputTokens() int {
+	return e.maxInputTokens
+}
+
+// インターフェース実装の確認
+var _ ingestion.Embedder = (*Embedder)(nil)
@@ -3,7 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
-	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
@@ -16,19 +16,210 @@ type Config struct {
 	// API認証
 	APIToken string
 
+	// スコープ・プロダクト制限付きAPIキー
+	APIKeys []APIKeyConfig
+
+	// HTTPサーバ設定
+	HTTPPort int
+
 	// OpenAI設定（Embeddings用）
 	OpenAI OpenAIConfig
 
+	// Embeddingプロバイダー設定
+	Embedding EmbeddingConfig
+
 	// Wiki生成用LLM設定
 	WikiLLM WikiLLMConfig
 
+	// ユースケース別LLM設定（キーはユースケース名）
+	LLM map[string]LLMConfig
+
 	// Git設定
 	Git GitConfig
 
 	// Wiki出力設定
 	WikiOutputDir string
+
+	// Wikiの公開先 Confluence の接続設定
+	Confluence ConfluenceConfig
+
+	// Issue・Pull Request の議論を取得する GitHub・GitLab の接続設定
+	Forge ForgeConfig
+
+	// 質問に回答する Slack ボットの接続設定
+	Slack SlackConfig
+
+	// プロバイダー別レート制限設定（キーはプロバイダー名）
+	RateLimits map[string]RateLimitConfig
+
+	// LLM/Embedding の予算設定
+	Budget BudgetConfig
+
+	// 検索のランキング設定
+	Search SearchConfig
+
+	// 質問応答の検索設定
+	Ask AskConfig
+
+	// 用語集の抽出設定
+	Glossary GlossaryConfig
+
+	// チャンクの重要度の算出設定
+	Importance ImportanceConfig
+
+	// LLMの応答のキャッシュ設定
+	LLMCache LLMCacheConfig
+
+	// LLMプロンプトテンプレートのディレクトリ（<name>.tmpl で組み込みのテンプレートを上書き、空の場合は参照しない）
+	PromptTemplateDir string
+
+	// インデックス化のパイプラインのワーカー数・バッチサイズ
+	Pipeline PipelineConfig
+
+	// チャンク化のトークン数（言語ごとのプロファイルで上書き可能）
+	Chunk ChunkConfig
+
+	// カバレッジアラートの既定の閾値（プロダクトの設定で上書き可能）
+	Alert AlertConfig
+
+	// --product を省略したコマンドで使用するプロダクト名
+	DefaultProduct string
+
+	// 読み込んだ設定ファイルのパス（設定ファイルがない場合は空）
+	File string
+
+	settings []Setting // 読み込んだ設定項目の値と取得元
+	warnings []string  // 設定ファイルの未知のキー・解釈できなかった値
 }
 
+// PipelineConfig はインデックス化のパイプラインの設定（0 の項目はパイプラインの既定値を使用）
+type PipelineConfig struct {
+	ChunkWorkers       int  // チャンク分割ワーカー数
+	EmbeddingWorkers   int  // Embedding生成ワーカー数
+	EmbeddingBatchSize int  // Embeddingのバッチサイズ（Embedderの上限でクリップされる）
+	StoreFileBlobs     bool // ファイルの内容を file_blobs に保存する（chunk show・file show での原文の表示と再チャンク化に使用）
+}
+
+// AlertConfig はカバレッジアラートの既定の閾値
+type AlertConfig struct {
+	MinCoverage                map[string]float64 // ドメインから最小カバレッジ率（%）へのマップ（組み込みの閾値を上書き・追加する）
+	MaxUnindexedImportantFiles int                // 許容する未インデックスの重要ファイル数
+}
+
+// ChunkConfig はチャンク化のトークン数の設定
+type ChunkConfig struct {
+	Default  ChunkProfile            // すべての言語に適用する設定（CHUNK_*_TOKENS）
+	Profiles map[string]ChunkProfile // 言語（小文字）ごとの設定（CHUNK_PROFILE_<LANG>_*_TOKENS、未指定の項目は Default を引き継ぐ）
+}
+
+// ChunkProfile はチャンクのトークン数
+type ChunkProfile struct {
+	TargetTokens  int
+	MaxTokens     int
+	MinTokens     int
+	OverlapTokens int
+}
+
+// chunkProfileKeys は ChunkProfile の各項目に対応するキーの接尾辞
+var chunkProfileKeys = []string{"TARGET_TOKENS", "MAX_TOKENS", "MIN_TOKENS", "OVERLAP_TOKENS"}
+
+// DefaultChunkProfile は既定のチャンクのトークン数を返します
+func DefaultChunkProfile() ChunkProfile {
+	return ChunkProfile{
+		TargetTokens:  800,
+		MaxTokens:     1600,
+		MinTokens:     100,
+		OverlapTokens: 200,
+	}
+}
+
+// ProfileFor は言語のチャンクのトークン数を返します（言語ごとの設定がない場合は Default）
+func (c ChunkConfig) ProfileFor(language string) ChunkProfile {
+	if profile, ok := c.Profiles[strings.ToLower(language)]; ok {
+		return profile
+	}
+	return c.Default
+}
+
+func (p ChunkProfile) validate() error {
+	if p.MinTokens <= 0 || p.MinTokens > p.TargetTokens || p.TargetTokens > p.MaxTokens {
+		return fmt.Errorf("expected 0 < min (%d) <= target (%d) <= max (%d)", p.MinTokens, p.TargetTokens, p.MaxTokens)
+	}
+	if p.OverlapTokens < 0 || p.OverlapTokens >= p.TargetTokens {
+		return fmt.Errorf("expected 0 <= overlap (%d) < target (%d)", p.OverlapTokens, p.TargetTokens)
+	}
+	return nil
+}
+
+// loadChunkProfile は prefix に続くキー（TARGET_TOKENS など）からチャンクのトークン数を読み込みます
+func loadChunkProfile(src *source, prefix string, defaults ChunkProfile) ChunkProfile {
+	return ChunkProfile{
+		TargetTokens:  src.getEnvAsInt(prefix+"TARGET_TOKENS", defaults.TargetTokens),
+		MaxTokens:     src.getEnvAsInt(prefix+"MAX_TOKENS", defaults.MaxTokens),
+		MinTokens:     src.getEnvAsInt(prefix+"MIN_TOKENS", defaults.MinTokens),
+		OverlapTokens: src.getEnvAsInt(prefix+"OVERLAP_TOKENS", defaults.OverlapTokens),
+	}
+}
+
+// SearchConfig はチャンク検索のランキング設定
+type SearchConfig struct {
+	ImportanceWeight    float64 // 類似度に importance_score を加味する重み（0〜1、0 の場合は類似度のみ）
+	RecencyHalfLifeDays int     // 鮮度の重み付けの半減期（日数、重みはプロダクトごとに設定）
+	Strategy            string  // チャンク検索の方式（"flat" | "two-stage"）
+	CandidateFiles      int     // two-stage の1段目でファイル要約から選ぶ候補ファイル数
+}
+
+// AskConfig は質問応答時の検索結果の展開設定
+type AskConfig struct {
+	DependencyHops        int      // 依存関係を辿るホップ数（0 の場合は展開しない）
+	DependencyMaxChunks   int      // 依存関係で追加するチャンク数の上限
+	DependencyTokenBudget int      // 依存関係で追加するチャンクの合計トークン数の上限
+	DependencyTypes       []string // 辿る依存の種類（call / import / type、空なら全種類）
+	MinRelevance          float64  // 検索結果の関連度スコアの最大値がこの値に届かない場合は回答を生成しない（0 の場合は判定しない、プロダクトの設定で上書き可能）
+	ScopeMinScore         float64  // 検索結果の関連度スコアの最大値がこの値に届かない質問はプロダクトの範囲外として拒否する（0 の場合は検索結果がない場合のみ）
+	CacheEnabled          bool     // 正規化した質問文・検索範囲・プロンプトのバージョンが同じ質問に、保存した回答を返すか
+	CacheTTLHours         int      // キャッシュした回答の有効期間（時間、0 の場合は期限なし）
+	RoutingMinScore       float64  // プロダクトを指定しない質問を振り分ける、最も関連するプロダクトの類似度の下限
+	RoutingMargin         float64  // 2番目の候補との類似度の差がこの値未満の場合は振り分けずに候補を示す
+	LatencyBudgetMs       int      // 質問応答の所要時間の予算（ミリ秒、超えた場合は補助的な処理を省略・回答の生成を打ち切る、0 の場合は制限しない）
+}
+
+// GlossaryConfig は用語集の抽出設定
+type GlossaryConfig struct {
+	ExtractOnIndex bool // インデックス化の後に用語集を抽出するか
+	MaxBatches     int  // 1回の抽出でのLLM呼び出し回数の上限
+}
+
+// ImportanceConfig はチャンクの重要度（importance_score）の算出設定
+type ImportanceConfig struct {
+	Algorithm  string  // グラフスコアのアルゴリズム（"pagerank" | "betweenness" | "in_degree"）
+	EditWeight float64 // 最終スコアに占める編集頻度スコアの重み（0〜1）
+}
+
+// LLMCacheConfig はLLMの応答のキャッシュ設定
+type LLMCacheConfig struct {
+	Enabled bool // 要約・Wikiページの生成の応答をキャッシュするか
+	TTLDays int  // キャッシュした応答の有効期間（日数、0 の場合は期限なし）
+}
+
+// BudgetConfig はインデックス化1回あたりのLLM/Embedding予算設定
+type BudgetConfig struct {
+	MaxCostUSD float64 // 推定コストの上限（0 の場合は無制限）
+	Action     string  // 超過時の動作（"abort" | "downgrade"）
+	Pricing    string  // 単価の上書き（"model=input:output;..."、100万トークンあたりUSD）
+}
+
+// RateLimitConfig はLLM/Embeddingプロバイダーのレート制限設定
+// 0 の項目は制限なしとして扱う
+type RateLimitConfig struct {
+	RequestsPerMinute int
+	TokensPerMinute   int
+	MaxConcurrency    int
+}
+
+// rateLimitProviders はレート制限設定を読み込むプロバイダー名の一覧
+var rateLimitProviders = []string{"openai", "azure", "vertex", "ollama", "anthropic"}
+
 // DatabaseConfig はデータベース接続設定
 type DatabaseConfig struct {
 	Host     string
@@ -37,6 +228,26 @@ type DatabaseConfig struct {
 	Password string
 	DBName   string
 	SSLMode  string
+
+	// 接続プールの設定（0 の場合は pgx の既定値、リードレプリカにも適用する）
+	MaxConns               int
+	MinConns               int
+	MaxConnIdleSeconds     int
+	MaxConnLifetimeSeconds int
+
+	// 検索（ask の検索を含む）の読み取りに使用するリードレプリカ（Host が空の場合は使用しない）
+	Replica DatabaseReplicaConfig
+}
+
+// DatabaseReplicaConfig はリードレプリカの接続設定（Host 以外の未設定の項目はプライマリの値を引き継ぐ）
+type DatabaseReplicaConfig struct {
+	Host          string
+	Port          int
+	User          string
+	Password      string
+	DBName        string
+	SSLMode       string
+	MaxLagSeconds int // 遅延がこの秒数を超えている間はプライマリから読み取る（0 は遅延を判定しない）
 }
 
 // OpenAIConfig はOpenAI API設定（Embeddings + LLM）
@@ -47,6 +258,40 @@ type OpenAIConfig struct {
 	LLMModel           string // LLMモデル名（ファイル要約生成等に使用）
 }
 
+// EmbeddingConfig はEmbeddingプロバイダーの選択と接続設定
+type EmbeddingConfig struct {
+	Provider        string // "openai" | "azure" | "vertex" | "ollama"
+	Model           string // モデル名（azure の場合はデプロイメント名）
+	Dimension       int    // ベクトル次元（0 の場合はDBのカラム定義から決定）
+	APIKey          string // openai / azure のAPIキー
+	BaseURL         string // azure のエンドポイント、ollama のサーバURL
+	AzureAPIVersion string // Azure OpenAI のAPIバージョン
+	VertexProject   string // Vertex AI のプロジェクトID
+	VertexLocation  string // Vertex AI のリージョン
+	VertexToken     string // Vertex AI のアクセストークン
+	MaxInputTokens  int    // 1テキストあたりの最大入力トークン数（0 の場合はプロバイダーの既定値）
+	Storage         string // ベクトルの格納型（"vector" | "halfvec"）。DBのカラム定義と一致している必要がある
+
+	// AdditionalModels は主モデルに加えてチャンクの Embedding を生成する追加のモデル（主モデルと同じプロバイダー・接続設定を使用）
+	// 検索時にモデルを選択して検索精度を比較するためのもの
+	AdditionalModels []string
+}
+
+// LLMConfig はLLMプロバイダーの選択と接続設定
+type LLMConfig struct {
+	Provider        string // "openai" | "azure" | "ollama"
+	Model           string // モデル名（azure の場合はデプロイメント名）
+	APIKey          string // openai / azure のAPIキー
+	BaseURL         string // azure のエンドポイント、ollama などOpenAI互換サーバのURL
+	AzureAPIVersion string // Azure OpenAI のAPIバージョン
+}
+
+// LLMUseCases はLLM設定をユースケースごとに切り替えられる用途の一覧
+//   - summary: ファイル/ディレクトリ/アーキテクチャ要約の生成
+//   - wiki: Wikiページの生成
+//   - ask: 質問応答
+var LLMUseCases = []string{"summary", "wiki", "ask"}
+
 // WikiLLMConfig はWiki生成用LLM設定
 type WikiLLMConfig struct {
 	Provider    string // "openai" or "anthropic"
@@ -56,6 +301,35 @@ type WikiLLMConfig struct {
 	MaxTokens   int
 }
 
+// APIKeyConfig はHTTP APIのキー設定
+type APIKeyConfig struct {
+	Name     string   // キーの識別名（ログ出力用）
+	Token    string   // Bearerトークン
+	Scopes   []string // 許可スコープ（read, index, admin）
+	Products []string // アクセス可能なプロダクト名（空なら全プロダクト）
+}
+
+// ConfluenceConfig はWikiを公開する Confluence の接続設定
+type ConfluenceConfig struct {
+	BaseURL  string // Confluence のURL（Cloud の場合は https://<site>.atlassian.net/wiki）
+	User     string // Basic認証のユーザー（Cloud のメールアドレス、空の場合はトークンをBearerとして送る）
+	APIToken string // APIトークン（Server/Data Center の場合は個人用アクセストークン）
+}
+
+// ForgeConfig は Issue・Pull Request の議論を取得する GitHub・GitLab の接続設定
+type ForgeConfig struct {
+	GitHubToken  string // GitHub のアクセストークン（公開リポジトリは省略可）
+	GitHubAPIURL string // GitHub Enterprise Server の API のURL（省略時はリポジトリのホストから決定）
+	GitLabToken  string // GitLab のアクセストークン（公開プロジェクトは省略可）
+}
+
+// SlackConfig は質問に回答する Slack ボット（Socket Mode）の接続設定
+type SlackConfig struct {
+	BotToken        string            // ボットトークン（xoxb-）
+	AppToken        string            // Socket Mode 用のアプリレベルトークン（xapp-）
+	ChannelProducts map[string]string // チャンネルIDから回答するプロダクト名への対応
+}
+
 // GitConfig はGit操作設定
 type GitConfig struct {
 	CloneDir      string
@@ -63,10 +337,20 @@ type GitConfig struct {
 	SSHPassword   string // SSH秘密鍵のパスワード（パスフレーズ）
 	SSHKnownHosts string
 	DefaultBranch string // デフォルトブランチ名（例: main, master）
+	HTTPToken     string // HTTPS のリポジトリへのプッシュに使用するアクセストークン（wiki publish git）
+	AuthorName    string // dev-rag が作成するコミットの作成者名
+	AuthorEmail   string // dev-rag が作成するコミットの作成者メールアドレス
 }
 
-// Load は環境変数または.envファイルから設定を読み込みます
+// Load は環境変数・.envファイル・設定ファイル（dev-rag.yaml）から設定を読み込みます
+// 同じ項目は 環境変数（.envファイルを含む） > 設定ファイル > 既定値 の順に優先します
 func Load(envFilePath string) (*Config, error) {
+	return LoadWithFile(envFilePath, "")
+}
+
+// LoadWithFile は設定ファイルのパスを指定して設定を読み込みます
+// configPath が空の場合は DEVRAG_CONFIG、その指定もない場合はカレントディレクトリの dev-rag.yaml（存在する場合のみ）を読み込みます
+func LoadWithFile(envFilePath, configPath string) (*Config, error) {
 	// .envファイルが存在する場合は読み込む
 	if envFilePath != "" {
 		if err := godotenv.Load(envFilePath); err != nil {
@@ -77,72 +361,383 @@ func Load(envFilePath string) (*Config, error) {
 		}
 	}
 
+	if configPath == "" {
+		path, err := FilePath()
+		if err != nil {
+			return nil, err
+		}
+		configPath = path
+	}
+	src, err := newSource(configPath)
+	if err != nil {
+		return nil, err
+	}
+
 	cfg := &Config{
+		File:           configPath,
+		DefaultProduct: src.getEnv("DEVRAG_DEFAULT_PRODUCT", ""),
 		Database: DatabaseConfig{
-			Host:     getEnv("DB_HOST", "localhost"),
-			Port:     getEnvAsInt("DB_PORT", 5432),
-			User:     getEnv("DB_USER", "devrag"),
-			Password: getEnv("DB_PASSWORD", ""),
-			DBName:   getEnv("DB_NAME", "devrag"),
-			SSLMode:  getEnv("DB_SSLMODE", "disable"),
-		},
-		APIToken: getEnv("DEVRAG_API_TOKEN", ""),
+			Host:     src.getEnv("DB_HOST", "localhost"),
+			Port:     src.getEnvAsInt("DB_PORT", 5432),
+			User:     src.getEnv("DB_USER", "devrag"),
+			Password: src.getEnv("DB_PASSWORD", ""),
+			DBName:   src.getEnv("DB_NAME", "devrag"),
+			SSLMode:  src.getEnv("DB_SSLMODE", "disable"),
+
+			MaxConns:               src.getEnvAsInt("DB_MAX_CONNS", 0),
+			MinConns:               src.getEnvAsInt("DB_MIN_CONNS", 0),
+			MaxConnIdleSeconds:     src.getEnvAsInt("DB_MAX_CONN_IDLE_SECONDS", 0),
+			MaxConnLifetimeSeconds: src.getEnvAsInt("DB_MAX_CONN_LIFETIME_SECONDS", 0),
+		},
+		APIToken: src.getEnv("DEVRAG_API_TOKEN", ""),
+		HTTPPort: src.getEnvAsInt("HTTP_PORT", 8080),
 		OpenAI: OpenAIConfig{
-			APIKey:             getEnv("OPENAI_API_KEY", ""),
-			EmbeddingModel:     getEnv("OPENAI_EMBEDDING_MODEL", "text-embedding-3-small"),
-			EmbeddingDimension: getEnvAsInt("OPENAI_EMBEDDING_DIMENSION", 1536),
-			LLMModel:           getEnv("OPENAI_LLM_MODEL", "gpt-4o-mini"), // デフォルトはgpt-4o-mini
+			APIKey:             src.getEnv("OPENAI_API_KEY", ""),
+			EmbeddingModel:     src.getEnv("OPENAI_EMBEDDING_MODEL", "text-embedding-3-small"),
+			EmbeddingDimension: src.getEnvAsInt("OPENAI_EMBEDDING_DIMENSION", 1536),
+			LLMModel:           src.getEnv("OPENAI_LLM_MODEL", "gpt-4o-mini"), // デフォルトはgpt-4o-mini
+		},
+		Embedding: EmbeddingConfig{
+			Provider:         src.getEnv("EMBEDDING_PROVIDER", "openai"),
+			Model:            src.getEnv("EMBEDDING_MODEL", ""),
+			Dimension:        src.getEnvAsInt("EMBEDDING_DIMENSION", src.getEnvAsInt("OPENAI_EMBEDDING_DIMENSION", 1536)),
+			APIKey:           src.getEnv("EMBEDDING_API_KEY", ""),
+			BaseURL:          src.getEnv("EMBEDDING_BASE_URL", ""),
+			AzureAPIVersion:  src.getEnv("AZURE_OPENAI_API_VERSION", "2024-10-21"),
+			VertexProject:    src.getEnv("VERTEX_PROJECT", ""),
+			VertexLocation:   src.getEnv("VERTEX_LOCATION", "us-central1"),
+			VertexToken:      src.getEnv("VERTEX_ACCESS_TOKEN", ""),
+			MaxInputTokens:   src.getEnvAsInt("EMBEDDING_MAX_INPUT_TOKENS", 0),
+			Storage:          src.getEnv("EMBEDDING_STORAGE", "vector"),
+			AdditionalModels: splitList(src.getEnv("EMBEDDING_ADDITIONAL_MODELS", "")),
 		},
 		WikiLLM: WikiLLMConfig{
-			Provider:    getEnv("WIKI_LLM_PROVIDER", "openai"),
-			APIKey:      getEnv("WIKI_LLM_API_KEY", ""),
-			Model:       getEnv("WIKI_LLM_MODEL", "gpt-4-turbo-preview"),
-			Temperature: getEnvAsFloat("WIKI_LLM_TEMPERATURE", 0.2),
-			MaxTokens:   getEnvAsInt("WIKI_LLM_MAX_TOKENS", 2048),
+			Provider:    src.getEnv("WIKI_LLM_PROVIDER", "openai"),
+			APIKey:      src.getEnv("WIKI_LLM_API_KEY", ""),
+			Model:       src.getEnv("WIKI_LLM_MODEL", "gpt-4-turbo-preview"),
+			Temperature: src.getEnvAsFloat("WIKI_LLM_TEMPERATURE", 0.2),
+			MaxTokens:   src.getEnvAsInt("WIKI_LLM_MAX_TOKENS", 2048),
 		},
 		Git: GitConfig{
-			CloneDir:      getEnv("GIT_CLONE_DIR", "/var/lib/dev-rag/repos"),
-			SSHKeyPath:    getEnv("GIT_SSH_KEY_PATH", "/etc/dev-rag/ssh/id_rsa"),
-			SSHPassword:   getEnv("GIT_SSH_PASSWORD", ""),
-			SSHKnownHosts: getEnv("GIT_SSH_KNOWN_HOSTS", "/etc/dev-rag/ssh/known_hosts"),
-			DefaultBranch: getEnv("GIT_DEFAULT_BRANCH", "main"),
+			CloneDir:      src.getEnv("GIT_CLONE_DIR", "/var/lib/dev-rag/repos"),
+			SSHKeyPath:    src.getEnv("GIT_SSH_KEY_PATH", "/etc/dev-rag/ssh/id_rsa"),
+			SSHPassword:   src.getEnv("GIT_SSH_PASSWORD", ""),
+			SSHKnownHosts: src.getEnv("GIT_SSH_KNOWN_HOSTS", "/etc/dev-rag/ssh/known_hosts"),
+			DefaultBranch: src.getEnv("GIT_DEFAULT_BRANCH", "main"),
+			HTTPToken:     src.getEnv("GIT_HTTP_TOKEN", ""),
+			AuthorName:    src.getEnv("GIT_AUTHOR_NAME", "dev-rag"),
+			AuthorEmail:   src.getEnv("GIT_AUTHOR_EMAIL", "dev-rag@localhost"),
+		},
+		WikiOutputDir: src.getEnv("WIKI_OUTPUT_DIR", "/var/lib/dev-rag/wikis"),
+		Confluence: ConfluenceConfig{
+			BaseURL:  src.getEnv("CONFLUENCE_BASE_URL", ""),
+			User:     src.getEnv("CONFLUENCE_USER", ""),
+			APIToken: src.getEnv("CONFLUENCE_API_TOKEN", ""),
+		},
+		Forge: ForgeConfig{
+			GitHubToken:  src.getEnv("GITHUB_TOKEN", ""),
+			GitHubAPIURL: src.getEnv("GITHUB_API_URL", ""),
+			GitLabToken:  src.getEnv("GITLAB_TOKEN", ""),
+		},
+		Slack: SlackConfig{
+			BotToken: src.getEnv("SLACK_BOT_TOKEN", ""),
+			AppToken: src.getEnv("SLACK_APP_TOKEN", ""),
+		},
+		Budget: BudgetConfig{
+			MaxCostUSD: src.getEnvAsFloat("LLM_BUDGET_USD", 0),
+			Action:     src.getEnv("LLM_BUDGET_ACTION", "downgrade"),
+			Pricing:    src.getEnv("LLM_PRICING", ""),
+		},
+		Search: SearchConfig{
+			ImportanceWeight:    src.getEnvAsFloat("SEARCH_IMPORTANCE_WEIGHT", 0),
+			RecencyHalfLifeDays: src.getEnvAsInt("SEARCH_RECENCY_HALF_LIFE_DAYS", 90),
+			Strategy:            src.getEnv("SEARCH_STRATEGY", "flat"),
+			CandidateFiles:      src.getEnvAsInt("SEARCH_CANDIDATE_FILES", 20),
+		},
+		Ask: AskConfig{
+			DependencyHops:        src.getEnvAsInt("ASK_DEPENDENCY_HOPS", 0),
+			DependencyMaxChunks:   src.getEnvAsInt("ASK_DEPENDENCY_MAX_CHUNKS", 5),
+			DependencyTokenBudget: src.getEnvAsInt("ASK_DEPENDENCY_TOKEN_BUDGET", 2000),
+			DependencyTypes:       splitList(src.getEnv("ASK_DEPENDENCY_TYPES", "")),
+			MinRelevance:          src.getEnvAsFloat("ASK_MIN_RELEVANCE", 0),
+			ScopeMinScore:         src.getEnvAsFloat("ASK_SCOPE_MIN_SCORE", 0.2),
+			CacheEnabled:          src.getEnvAsBool("ASK_CACHE_ENABLED", true),
+			CacheTTLHours:         src.getEnvAsInt("ASK_CACHE_TTL_HOURS", 24),
+			RoutingMinScore:       src.getEnvAsFloat("ASK_ROUTING_MIN_SCORE", 0.2),
+			RoutingMargin:         src.getEnvAsFloat("ASK_ROUTING_MARGIN", 0.05),
+			LatencyBudgetMs:       src.getEnvAsInt("ASK_LATENCY_BUDGET_MS", 0),
+		},
+		Glossary: GlossaryConfig{
+			ExtractOnIndex: src.getEnvAsBool("GLOSSARY_EXTRACT_ON_INDEX", true),
+			MaxBatches:     src.getEnvAsInt("GLOSSARY_MAX_BATCHES", 8),
+		},
+		Importance: ImportanceConfig{
+			Algorithm:  src.getEnv("IMPORTANCE_ALGORITHM", "pagerank"),
+			EditWeight: src.getEnvAsFloat("IMPORTANCE_EDIT_WEIGHT", 0.2),
 		},
-		WikiOutputDir: getEnv("WIKI_OUTPUT_DIR", "/var/lib/dev-rag/wikis"),
+		LLMCache: LLMCacheConfig{
+			Enabled: src.getEnvAsBool("LLM_CACHE_ENABLED", true),
+			TTLDays: src.getEnvAsInt("LLM_CACHE_TTL_DAYS", 0),
+		},
+		PromptTemplateDir: src.getEnv("PROMPT_TEMPLATE_DIR", ""),
+		Pipeline: PipelineConfig{
+			ChunkWorkers:       src.getEnvAsInt("PIPELINE_CHUNK_WORKERS", 0),
+			EmbeddingWorkers:   src.getEnvAsInt("PIPELINE_EMBEDDING_WORKERS", 0),
+			EmbeddingBatchSize: src.getEnvAsInt("PIPELINE_EMBEDDING_BATCH_SIZE", 0),
+			StoreFileBlobs:     src.getEnvAsBool("PIPELINE_STORE_FILE_BLOBS", true),
+		},
+		Chunk: ChunkConfig{
+			Default: loadChunkProfile(src, "CHUNK_", DefaultChunkProfile()),
+		},
+		Alert: AlertConfig{
+			MaxUnindexedImportantFiles: src.getEnvAsInt("ALERT_MAX_UNINDEXED_IMPORTANT_FILES", 0),
+		},
+	}
+
+	if db := cfg.Database; db.MaxConns < 0 || db.MinConns < 0 || db.MaxConnIdleSeconds < 0 || db.MaxConnLifetimeSeconds < 0 {
+		return nil, fmt.Errorf("invalid DB_MAX_CONNS / DB_MIN_CONNS / DB_MAX_CONN_*_SECONDS: expected zero (default) or a positive number")
+	}
+	if db := cfg.Database; db.MaxConns > 0 && db.MinConns > db.MaxConns {
+		return nil, fmt.Errorf("invalid DB_MIN_CONNS %d: expected a number not greater than DB_MAX_CONNS %d", db.MinConns, db.MaxConns)
+	}
+
+	// リードレプリカの未設定の項目はプライマリの値を引き継ぐ
+	cfg.Database.Replica = DatabaseReplicaConfig{
+		Host:          src.getEnv("DB_REPLICA_HOST", ""),
+		Port:          src.getEnvAsInt("DB_REPLICA_PORT", cfg.Database.Port),
+		User:          src.getEnv("DB_REPLICA_USER", cfg.Database.User),
+		Password:      src.getEnv("DB_REPLICA_PASSWORD", cfg.Database.Password),
+		DBName:        src.getEnv("DB_REPLICA_NAME", cfg.Database.DBName),
+		SSLMode:       src.getEnv("DB_REPLICA_SSLMODE", cfg.Database.SSLMode),
+		MaxLagSeconds: src.getEnvAsInt("DB_REPLICA_MAX_LAG_SECONDS", 30),
+	}
+	if cfg.Database.Replica.MaxLagSeconds < 0 {
+		return nil, fmt.Errorf("invalid DB_REPLICA_MAX_LAG_SECONDS %d: expected zero or a positive number of seconds", cfg.Database.Replica.MaxLagSeconds)
 	}
 
+	if cfg.Budget.Action != "abort" && cfg.Budget.Action != "downgrade" {
+		return nil, fmt.Errorf("invalid LLM_BUDGET_ACTION %q: expected abort or downgrade", cfg.Budget.Action)
+	}
+	if w := cfg.Search.ImportanceWeight; w < 0 || w > 1 {
+		return nil, fmt.Errorf("invalid SEARCH_IMPORTANCE_WEIGHT %v: expected a value between 0 and 1", w)
+	}
+	if cfg.Search.RecencyHalfLifeDays <= 0 {
+		return nil, fmt.Errorf("invalid SEARCH_RECENCY_HALF_LIFE_DAYS %d: expected a positive number of days", cfg.Search.RecencyHalfLifeDays)
+	}
+	if cfg.Embedding.Storage != "vector" && cfg.Embedding.Storage != "halfvec" {
+		return nil, fmt.Errorf("invalid EMBEDDING_STORAGE %q: expected vector or halfvec", cfg.Embedding.Storage)
+	}
+	if cfg.Search.Strategy != "flat" && cfg.Search.Strategy != "two-stage" {
+		return nil, fmt.Errorf("invalid SEARCH_STRATEGY %q: expected flat or two-stage", cfg.Search.Strategy)
+	}
+	if cfg.Search.CandidateFiles <= 0 {
+		return nil, fmt.Errorf("invalid SEARCH_CANDIDATE_FILES %d: expected a positive number", cfg.Search.CandidateFiles)
+	}
+	if r := cfg.Ask.MinRelevance; r < 0 || r > 1 {
+		return nil, fmt.Errorf("invalid ASK_MIN_RELEVANCE %v: expected a value between 0 and 1", r)
+	}
+	if r := cfg.Ask.ScopeMinScore; r < 0 || r > 1 {
+		return nil, fmt.Errorf("invalid ASK_SCOPE_MIN_SCORE %v: expected a value between 0 and 1", r)
+	}
+	if r := cfg.Ask.RoutingMinScore; r < 0 || r > 1 {
+		return nil, fmt.Errorf("invalid ASK_ROUTING_MIN_SCORE %v: expected a value between 0 and 1", r)
+	}
+	if r := cfg.Ask.RoutingMargin; r < 0 || r > 1 {
+		return nil, fmt.Errorf("invalid ASK_ROUTING_MARGIN %v: expected a value between 0 and 1", r)
+	}
+	if cfg.Ask.CacheTTLHours < 0 {
+		return nil, fmt.Errorf("invalid ASK_CACHE_TTL_HOURS %d: expected zero or a positive number of hours", cfg.Ask.CacheTTLHours)
+	}
+	if cfg.Ask.LatencyBudgetMs < 0 {
+		return nil, fmt.Errorf("invalid ASK_LATENCY_BUDGET_MS %d: expected zero or a positive number of milliseconds", cfg.Ask.LatencyBudgetMs)
+	}
+	if cfg.LLMCache.TTLDays < 0 {
+		return nil, fmt.Errorf("invalid LLM_CACHE_TTL_DAYS %d: expected zero or a positive number of days", cfg.LLMCache.TTLDays)
+	}
+	if cfg.Glossary.MaxBatches <= 0 {
+		return nil, fmt.Errorf("invalid GLOSSARY_MAX_BATCHES %d: expected a positive number", cfg.Glossary.MaxBatches)
+	}
+
+	if cfg.Pipeline.ChunkWorkers < 0 || cfg.Pipeline.EmbeddingWorkers < 0 || cfg.Pipeline.EmbeddingBatchSize < 0 {
+		return nil, fmt.Errorf("invalid PIPELINE_* %+v: expected zero (default) or a positive number", cfg.Pipeline)
+	}
+	if cfg.Alert.MaxUnindexedImportantFiles < 0 {
+		return nil, fmt.Errorf("invalid ALERT_MAX_UNINDEXED_IMPORTANT_FILES %d: expected zero or a positive number", cfg.Alert.MaxUnindexedImportantFiles)
+	}
+	if err := cfg.Chunk.Default.validate(); err != nil {
+		return nil, fmt.Errorf("invalid CHUNK_*: %w", err)
+	}
+	cfg.Chunk.Profiles = make(map[string]ChunkProfile)
+	for _, language := range src.names("CHUNK_PROFILE_", chunkProfileKeys) {
+		profile := loadChunkProfile(src, "CHUNK_PROFILE_"+language+"_", cfg.Chunk.Default)
+		if err := profile.validate(); err != nil {
+			return nil, fmt.Errorf("invalid CHUNK_PROFILE_%s_*: %w", language, err)
+		}
+		cfg.Chunk.Profiles[strings.ToLower(language)] = profile
+	}
+	cfg.Alert.MinCoverage = make(map[string]float64)
+	for _, domain := range src.names("ALERT_MIN_COVERAGE_", nil) {
+		v := src.getEnvAsFloat("ALERT_MIN_COVERAGE_"+domain, -1)
+		if v < 0 || v > 100 {
+			return nil, fmt.Errorf("invalid ALERT_MIN_COVERAGE_%s: expected a percentage between 0 and 100", domain)
+		}
+		cfg.Alert.MinCoverage[strings.ToLower(domain)] = v
+	}
+
+	switch cfg.Importance.Algorithm {
+	case "pagerank", "betweenness", "in_degree":
+	default:
+		return nil, fmt.Errorf("invalid IMPORTANCE_ALGORITHM %q: expected pagerank, betweenness or in_degree", cfg.Importance.Algorithm)
+	}
+	if w := cfg.Importance.EditWeight; w < 0 || w > 1 {
+		return nil, fmt.Errorf("invalid IMPORTANCE_EDIT_WEIGHT %v: expected a value between 0 and 1", w)
+	}
+
+	// OpenAIプロバイダーの場合は従来の OPENAI_* 設定を引き継ぐ
+	if cfg.Embedding.Provider == "openai" {
+		if cfg.Embedding.Model == "" {
+			cfg.Embedding.Model = cfg.OpenAI.EmbeddingModel
+		}
+		if cfg.Embedding.APIKey == "" {
+			cfg.Embedding.APIKey = cfg.OpenAI.APIKey
+		}
+	}
+
+	cfg.LLM = loadLLMConfigs(src, LLMUseCases, LLMConfig{
+		Provider:        src.getEnv("LLM_PROVIDER", "openai"),
+		Model:           src.getEnv("LLM_MODEL", cfg.OpenAI.LLMModel),
+		APIKey:          src.getEnv("LLM_API_KEY", ""),
+		BaseURL:         src.getEnv("LLM_BASE_URL", ""),
+		AzureAPIVersion: cfg.Embedding.AzureAPIVersion,
+	}, cfg.OpenAI.APIKey)
+
+	cfg.RateLimits = loadRateLimits(src, rateLimitProviders)
+
+	apiKeys, err := parseAPIKeys(src.getEnv("DEVRAG_API_KEYS", ""))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse DEVRAG_API_KEYS: %w", err)
+	}
+	cfg.APIKeys = apiKeys
+
+	channelProducts, err := parseChannelProducts(src.getEnv("SLACK_CHANNEL_PRODUCTS", ""))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse SLACK_CHANNEL_PRODUCTS: %w", err)
+	}
+	cfg.Slack.ChannelProducts = channelProducts
+
+	cfg.settings = src.settingList()
+	cfg.warnings = src.warnings()
 	return cfg, nil
 }
 
-// getEnv は環境変数を取得し、存在しない場合はデフォルト値を返します
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
+// parseChannelProducts は SLACK_CHANNEL_PRODUCTS の値をパースします
+// 形式: "チャンネルID=プロダクト名" を "," 区切りで列挙（例: "C0123ABCD=ecommerce,C0456EFGH=payments"）
+func parseChannelProducts(value string) (map[string]string, error) {
+	channels := make(map[string]string)
+	for _, entry := range splitList(value) {
+		channel, product, ok := strings.Cut(entry, "=")
+		channel, product = strings.TrimSpace(channel), strings.TrimSpace(product)
+		if !ok || channel == "" || product == "" {
+			return nil, fmt.Errorf("invalid channel entry %q: expected channel=product", entry)
+		}
+		if existing, dup := channels[channel]; dup && existing != product {
+			return nil, fmt.Errorf("channel %s is mapped to both %s and %s", channel, existing, product)
+		}
+		channels[channel] = product
 	}
-	return defaultValue
+	return channels, nil
 }
 
-// getEnvAsInt は環境変数を整数として取得します
-func getEnvAsInt(key string, defaultValue int) int {
-	valueStr := os.Getenv(key)
-	if valueStr == "" {
-		return defaultValue
+// parseAPIKeys は DEVRAG_API_KEYS の値をパースします
+// 形式: "名前:トークン:スコープ[,スコープ...]:プロダクト[,プロダクト...]" を ";" 区切りで列挙
+// プロダクトを省略または "*" とした場合は全プロダクトへのアクセスを許可します
+func parseAPIKeys(value string) ([]APIKeyConfig, error) {
+	var keys []APIKeyConfig
+	for _, entry := range strings.Split(value, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		fields := strings.Split(entry, ":")
+		if len(fields) < 3 || len(fields) > 4 {
+			return nil, fmt.Errorf("invalid api key entry %q: expected name:token:scopes[:products]", entry)
+		}
+
+		key := APIKeyConfig{
+			Name:   strings.TrimSpace(fields[0]),
+			Token:  strings.TrimSpace(fields[1]),
+			Scopes: splitList(fields[2]),
+		}
+		if key.Name == "" || key.Token == "" {
+			return nil, fmt.Errorf("invalid api key entry %q: name and token are required", entry)
+		}
+		if len(key.Scopes) == 0 {
+			return nil, fmt.Errorf("invalid api key entry %q: at least one scope is required", key.Name)
+		}
+		if len(fields) == 4 && strings.TrimSpace(fields[3]) != "*" {
+			key.Products = splitList(fields[3])
+		}
+
+		keys = append(keys, key)
 	}
-	value, err := strconv.Atoi(valueStr)
-	if err != nil {
-		return defaultValue
+	return keys, nil
+}
+
+// LLMFor はユースケースに対応するLLM設定を返します
+// 未知のユースケースの場合は既定値（LLM_*）相当の設定を返します
+func (c *Config) LLMFor(useCase string) LLMConfig {
+	if llm, ok := c.LLM[useCase]; ok {
+		return llm
 	}
-	return value
+	return c.LLM[""]
 }
 
-// getEnvAsFloat は環境変数を浮動小数点数として取得します
-func getEnvAsFloat(key string, defaultValue float64) float64 {
-	valueStr := os.Getenv(key)
-	if valueStr == "" {
-		return defaultValue
+// loadLLMConfigs はユースケースごとのLLM設定を環境変数から読み込みます
+// 例: LLM_WIKI_PROVIDER, LLM_WIKI_MODEL, LLM_WIKI_API_KEY, LLM_WIKI_BASE_URL
+// 指定のない項目は既定値（LLM_*）を引き継ぎ、キー "" に既定値そのものを格納します
+func loadLLMConfigs(src *source, useCases []string, defaults LLMConfig, openaiAPIKey string) map[string]LLMConfig {
+	configs := make(map[string]LLMConfig, len(useCases)+1)
+	for _, useCase := range append([]string{""}, useCases...) {
+		llm := defaults
+		if useCase != "" {
+			prefix := "LLM_" + strings.ToUpper(useCase) + "_"
+			llm.Provider = src.getEnv(prefix+"PROVIDER", llm.Provider)
+			llm.Model = src.getEnv(prefix+"MODEL", llm.Model)
+			llm.APIKey = src.getEnv(prefix+"API_KEY", llm.APIKey)
+			llm.BaseURL = src.getEnv(prefix+"BASE_URL", llm.BaseURL)
+		}
+		// OpenAIプロバイダーの場合は従来の OPENAI_API_KEY を引き継ぐ
+		if llm.Provider == "openai" && llm.APIKey == "" {
+			llm.APIKey = openaiAPIKey
+		}
+		configs[useCase] = llm
 	}
-	value, err := strconv.ParseFloat(valueStr, 64)
-	if err != nil {
-		return defaultValue
+	return configs
+}
+
+// loadRateLimits はプロバイダーごとのレート制限を環境変数から読み込みます
+// 例: RATE_LIMIT_OPENAI_RPM, RATE_LIMIT_OPENAI_TPM, RATE_LIMIT_OPENAI_CONCURRENCY
+func loadRateLimits(src *source, providers []string) map[string]RateLimitConfig {
+	limits := make(map[string]RateLimitConfig, len(providers))
+	for _, provider := range providers {
+		prefix := "RATE_LIMIT_" + strings.ToUpper(provider) + "_"
+		limits[provider] = RateLimitConfig{
+			RequestsPerMinute: src.getEnvAsInt(prefix+"RPM", 0),
+			TokensPerMinute:   src.getEnvAsInt(prefix+"TPM", 0),
+			MaxConcurrency:    src.getEnvAsInt(prefix+"CONCURRENCY", 0),
+		}
+	}
+	return limits
+}
+
+// splitList はカンマ区切りの文字列を空要素を除いて分割します
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
 	}
-	return value
+	return items
 }
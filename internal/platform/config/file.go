@@ -0,0 +1,277 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// DefaultFile はカレントディレクトリから読み込む設定ファイルの名前
+	DefaultFile = "dev-rag.yaml"
+	// FileEnvVar は設定ファイルのパスを指定する環境変数
+	FileEnvVar = "DEVRAG_CONFIG"
+)
+
+// Origin は設定項目の値の取得元
+type Origin string
+
+const (
+	OriginEnv     Origin = "env"     // 環境変数（.envファイルを含む）
+	OriginFile    Origin = "file"    // 設定ファイル
+	OriginDefault Origin = "default" // 既定値
+)
+
+// Setting は読み込んだ設定項目の値と取得元
+type Setting struct {
+	Key    string // 環境変数名（設定ファイルのキーは大文字にして "_" で連結した名前に対応する）
+	Value  string // 空の場合は未設定
+	Origin Origin
+}
+
+// Settings は読み込んだ設定項目をキーの順に返します
+func (c *Config) Settings() []Setting {
+	return c.settings
+}
+
+// Warnings は設定ファイルの未知のキーや解釈できなかった値の警告を返します
+func (c *Config) Warnings() []string {
+	return c.warnings
+}
+
+// FilePath は読み込む設定ファイルのパスを返します
+// DEVRAG_CONFIG が指定されている場合はそのパス（存在しない場合はエラー）、
+// 指定がない場合はカレントディレクトリの dev-rag.yaml（存在しない場合は空）を返します
+func FilePath() (string, error) {
+	if path := os.Getenv(FileEnvVar); path != "" {
+		if _, err := os.Stat(path); err != nil {
+			return "", fmt.Errorf("failed to stat config file %s: %w", path, err)
+		}
+		return path, nil
+	}
+	if _, err := os.Stat(DefaultFile); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to stat config file %s: %w", DefaultFile, err)
+	}
+	return DefaultFile, nil
+}
+
+// DefaultProductName はコマンドの引数を解析する前に既定のプロダクト名（DEVRAG_DEFAULT_PRODUCT）を返します
+// 環境変数・.envファイル・設定ファイルの順に参照し、読み込めない場合は空を返します（エラーは設定の読み込み時に報告する）
+func DefaultProductName(envFilePath string) string {
+	const key = "DEVRAG_DEFAULT_PRODUCT"
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	if values, err := godotenv.Read(envFilePath); err == nil && values[key] != "" {
+		return values[key]
+	}
+	path, err := FilePath()
+	if err != nil || path == "" {
+		return ""
+	}
+	values, err := loadFile(path)
+	if err != nil {
+		return ""
+	}
+	return values[key]
+}
+
+// loadFile は設定ファイルを読み込み、キーを環境変数名に変換した値のマップを返します
+// ネストしたキーは大文字にして "_" で連結し（db.host → DB_HOST）、配列はカンマ区切りの文字列にします
+func loadFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+	var root map[string]any
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	values := make(map[string]string)
+	if err := flattenFile("", root, values); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	return values, nil
+}
+
+func flattenFile(prefix string, node map[string]any, values map[string]string) error {
+	for k, v := range node {
+		key := strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(k))
+		if prefix != "" {
+			key = prefix + "_" + key
+		}
+		switch v := v.(type) {
+		case map[string]any:
+			if err := flattenFile(key, v, values); err != nil {
+				return err
+			}
+		case []any:
+			items := make([]string, 0, len(v))
+			for _, item := range v {
+				if _, ok := item.(map[string]any); ok {
+					return fmt.Errorf("%s: list items must be scalars", key)
+				}
+				items = append(items, fmt.Sprint(item))
+			}
+			values[key] = strings.Join(items, ",")
+		case nil:
+			// 値のないキーは未設定として扱う
+		default:
+			values[key] = fmt.Sprint(v)
+		}
+	}
+	return nil
+}
+
+// source は環境変数・設定ファイル・既定値の順に設定項目を解決し、読み込んだ項目を記録します
+type source struct {
+	file     map[string]string
+	settings map[string]Setting
+	invalid  []string
+}
+
+// newSource は設定ファイルを読み込んだ source を返します（path が空の場合は環境変数のみ）
+func newSource(path string) (*source, error) {
+	src := &source{
+		file:     map[string]string{},
+		settings: map[string]Setting{},
+	}
+	if path != "" {
+		values, err := loadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		src.file = values
+	}
+	return src, nil
+}
+
+// lookup は設定項目の値と取得元を返します（値がない場合は空文字列）
+func (s *source) lookup(key string) (string, Origin) {
+	if value := os.Getenv(key); value != "" {
+		return value, OriginEnv
+	}
+	if value := s.file[key]; value != "" {
+		return value, OriginFile
+	}
+	return "", OriginDefault
+}
+
+func (s *source) record(key, value string, origin Origin) {
+	s.settings[key] = Setting{Key: key, Value: value, Origin: origin}
+}
+
+// getEnv は設定項目を取得し、存在しない場合はデフォルト値を返します
+func (s *source) getEnv(key, defaultValue string) string {
+	value, origin := s.lookup(key)
+	if value == "" {
+		value = defaultValue
+	}
+	s.record(key, value, origin)
+	return value
+}
+
+// getEnvAsInt は設定項目を整数として取得します
+func (s *source) getEnvAsInt(key string, defaultValue int) int {
+	return getEnvAs(s, key, defaultValue, strconv.Atoi)
+}
+
+// getEnvAsFloat は設定項目を浮動小数点数として取得します
+func (s *source) getEnvAsFloat(key string, defaultValue float64) float64 {
+	return getEnvAs(s, key, defaultValue, func(v string) (float64, error) {
+		return strconv.ParseFloat(v, 64)
+	})
+}
+
+// getEnvAsBool は設定項目を真偽値として取得します
+func (s *source) getEnvAsBool(key string, defaultValue bool) bool {
+	return getEnvAs(s, key, defaultValue, strconv.ParseBool)
+}
+
+// getEnvAs は設定項目を parse で変換して取得します（変換できない場合は警告を記録してデフォルト値を返します）
+func getEnvAs[T any](s *source, key string, defaultValue T, parse func(string) (T, error)) T {
+	valueStr, origin := s.lookup(key)
+	if valueStr == "" {
+		s.record(key, fmt.Sprint(defaultValue), origin)
+		return defaultValue
+	}
+	value, err := parse(valueStr)
+	if err != nil {
+		s.invalid = append(s.invalid, fmt.Sprintf("%s: 値 %q を解釈できないため既定値 %v を使用します（%s）", key, valueStr, defaultValue, origin))
+		s.record(key, fmt.Sprint(defaultValue), OriginDefault)
+		return defaultValue
+	}
+	s.record(key, valueStr, origin)
+	return value
+}
+
+// names は prefix で始まる設定項目（環境変数・設定ファイル）から、prefix と suffixes のいずれかを除いた名前を重複なく返します
+// suffixes が空の場合は prefix 以降のすべてを名前とします（例: ALERT_MIN_COVERAGE_<DOMAIN>）
+func (s *source) names(prefix string, suffixes []string) []string {
+	keys := make([]string, 0, len(s.file))
+	for key := range s.file {
+		keys = append(keys, key)
+	}
+	for _, env := range os.Environ() {
+		if key, _, ok := strings.Cut(env, "="); ok {
+			keys = append(keys, key)
+		}
+	}
+
+	seen := make(map[string]bool)
+	var names []string
+	for _, key := range keys {
+		rest, ok := strings.CutPrefix(key, prefix)
+		if !ok {
+			continue
+		}
+		name := rest
+		if len(suffixes) > 0 {
+			name = ""
+			for _, suffix := range suffixes {
+				if n, ok := strings.CutSuffix(rest, "_"+suffix); ok {
+					name = n
+					break
+				}
+			}
+		}
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// settingList は読み込んだ設定項目をキーの順に返します
+func (s *source) settingList() []Setting {
+	settings := make([]Setting, 0, len(s.settings))
+	for _, setting := range s.settings {
+		settings = append(settings, setting)
+	}
+	sort.Slice(settings, func(i, j int) bool { return settings[i].Key < settings[j].Key })
+	return settings
+}
+
+// warnings は設定ファイルの未知のキー（読み込まれなかったキー）と解釈できなかった値の警告を返します
+func (s *source) warnings() []string {
+	var unknown []string
+	for key := range s.file {
+		if _, ok := s.settings[key]; !ok {
+			unknown = append(unknown, fmt.Sprintf("%s: 設定ファイルの未知のキーです", key))
+		}
+	}
+	sort.Strings(unknown)
+	return append(unknown, s.invalid...)
+}
@@ -4,20 +4,46 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/pkoukk/tiktoken-go"
+	"github.com/samber/mo"
 
+	"github.com/jinford/dev-rag/internal/core/apispec"
 	coreask "github.com/jinford/dev-rag/internal/core/ask"
+	"github.com/jinford/dev-rag/internal/core/cicheck"
+	"github.com/jinford/dev-rag/internal/core/coverage"
+	"github.com/jinford/dev-rag/internal/core/drift"
+	coreglossary "github.com/jinford/dev-rag/internal/core/glossary"
+	"github.com/jinford/dev-rag/internal/core/graph"
+	"github.com/jinford/dev-rag/internal/core/hotspot"
+	"github.com/jinford/dev-rag/internal/core/importance"
 	coreingestion "github.com/jinford/dev-rag/internal/core/ingestion"
 	"github.com/jinford/dev-rag/internal/core/ingestion/chunk"
 	"github.com/jinford/dev-rag/internal/core/ingestion/summary"
+	"github.com/jinford/dev-rag/internal/core/integrity"
+	"github.com/jinford/dev-rag/internal/core/license"
+	"github.com/jinford/dev-rag/internal/core/llm"
+	"github.com/jinford/dev-rag/internal/core/monorepo"
+	"github.com/jinford/dev-rag/internal/core/productconfig"
+	"github.com/jinford/dev-rag/internal/core/prompt"
+	corequality "github.com/jinford/dev-rag/internal/core/quality"
+	"github.com/jinford/dev-rag/internal/core/release"
 	coresearch "github.com/jinford/dev-rag/internal/core/search"
+	"github.com/jinford/dev-rag/internal/core/symbol"
+	"github.com/jinford/dev-rag/internal/core/testcoverage"
+	"github.com/jinford/dev-rag/internal/core/thirdparty"
+	"github.com/jinford/dev-rag/internal/core/usage"
 	corewiki "github.com/jinford/dev-rag/internal/core/wiki"
+	"github.com/jinford/dev-rag/internal/infra/completion"
+	"github.com/jinford/dev-rag/internal/infra/embedding"
+	"github.com/jinford/dev-rag/internal/infra/forge"
 	"github.com/jinford/dev-rag/internal/infra/git"
-	"github.com/jinford/dev-rag/internal/infra/openai"
+	"github.com/jinford/dev-rag/internal/infra/localfs"
 	"github.com/jinford/dev-rag/internal/infra/postgres"
 	indexsqlc "github.com/jinford/dev-rag/internal/infra/postgres/sqlc"
+	"github.com/jinford/dev-rag/internal/infra/ratelimit"
 	"github.com/jinford/dev-rag/internal/platform/config"
 	"github.com/jinford/dev-rag/internal/platform/database"
 )
@@ -25,16 +51,47 @@ import (
 // ServiceContainer は新アーキテクチャ(core/infra/pkg)の依存関係を保持する。
 // 既存の container.New とは独立に動作し、移行期間の併存を前提とする。
 type ServiceContainer struct {
-	IndexService      *coreingestion.IndexService
-	SummaryService    *summary.SummaryService
-	SearchService     *coresearch.SearchService
-	WikiService       *corewiki.WikiService
-	AskService        *coreask.AskService
-	IngestionRepo     coreingestion.Repository // プロダクト/ソース/スナップショット操作用
-	SummaryRepository summary.Repository       // 要約操作用
+	IndexService           *coreingestion.IndexService
+	LocalIndexService      *coreingestion.IndexService // ローカルディレクトリのインデックス化用
+	DiscussionIndexService *coreingestion.IndexService // GitHub・GitLab の Issue・Pull Request のインデックス化用
+	SummaryService         *summary.SummaryService
+	SearchService          *coresearch.SearchService
+	WikiService            *corewiki.WikiService
+	AskService             *coreask.AskService
+	GlossaryService        *coreglossary.GlossaryService
+	QualityService         *corequality.Service                   // 品質ノートの記録と品質改善アクションの生成用
+	CoverageAlerts         *coverage.AlertGenerator               // スナップショットのカバレッジアラートの生成用
+	CoverageTrend          *coverage.TrendService                 // スナップショットごとのカバレッジの推移の参照用
+	ProductConfig          productconfig.Repository               // プロダクトごとの設定（アラートの閾値など）の読み書き用
+	Prompts                *prompt.Registry                       // LLMプロンプトテンプレートの参照・上書き用
+	LLMCache               llm.CacheRepository                    // LLMの応答のキャッシュの集計・削除用
+	EmbeddingModels        coreingestion.EmbeddingModelRepository // 使用を許可するEmbeddingモデルの参照・登録用
+	IndexRuns              coreingestion.IndexRunRepository       // インデックス化の実行履歴の参照用
+	APIEndpoints           apispec.Reader                         // API定義ファイルから抽出したエンドポイントの参照用
+	Symbols                symbol.Reader                          // チャンクのメタデータから抽出したシンボルの定義位置の参照用
+	DependencyGraph        graph.Reader                           // チャンクの依存関係グラフの参照用
+	ArchitectureDrift      *drift.Service                         // 連続するスナップショットの依存関係グラフの比較（アーキテクチャのドリフトの検出）用
+	Hotspots               *hotspot.Service                       // 変更のリスクが高い関数・ファイル（ホットスポット）の順位付け用
+	ExternalDependencies   *thirdparty.Service                    // マニフェスト・サブモジュールから抽出した外部の依存関係とバージョンの変化の参照用
+	Licenses               *license.Service                       // ライセンスファイル・ライセンスヘッダーから検出したライセンスの集計用
+	MonorepoServices       *monorepo.Catalog                      // モノレポから検出したサービスの境界の参照用
+	TestCoverage           *testcoverage.Service                  // カバレッジレポート（Go の coverage.out・LCOV）の取り込み用
+	CIChecks               *cicheck.Service                       // CIのパイプラインでのインデックス・カバレッジ・Wikiのチェック用
+	Integrity              *integrity.Checker                     // インデックスの不整合の検出・修復用
+	ReleaseNotes           *release.Service                       // 2つの ref の間の変更からのリリースノートの生成用
+	IngestionRepo          coreingestion.Repository               // プロダクト/ソース/スナップショット操作用
+	SummaryRepository      summary.Repository                     // 要約操作用
+	WikiPublications       corewiki.PublicationStore              // Wikiの公開先ページの記録用
+	AskFeedback            coreask.FeedbackStore                  // チャットで回答した質問とリアクションによる評価の記録用
+	AdditionalEmbedders    map[string]coreingestion.Embedder      // 追加のEmbeddingモデル（EMBEDDING_ADDITIONAL_MODELS）のモデル名ごとの Embedder
 
 	logger   *slog.Logger
 	database *database.Database
+	pricing  usage.Pricing
+	budget   usage.Budget
+
+	glossaryOnIndex bool
+	embeddingModel  string // 設定した Embedding モデル（Embedder を注入した場合は空）
 }
 
 type containerOptions struct {
@@ -47,6 +104,8 @@ type containerOptions struct {
 	llmClient        corewiki.LLMClient
 	wikiRepo         corewiki.Repository
 	wikiFileReader   corewiki.FileReader
+
+	skipEmbeddingModelCheck bool
 }
 
 // ContainerOption は ServiceContainer 構築時のオプション
@@ -66,6 +125,14 @@ func WithContainerEmbedder(embedder coreingestion.Embedder) ContainerOption {
 	}
 }
 
+// WithoutEmbeddingModelCheck は起動時の Embedding モデルとベクトルカラムの次元数・格納型の検証を省略する
+// 検証に失敗する状態を解消するコマンド（embedding register、db vector-storage など）で使用する
+func WithoutEmbeddingModelCheck() ContainerOption {
+	return func(opts *containerOptions) {
+		opts.skipEmbeddingModelCheck = true
+	}
+}
+
 // WithContainerSourceProvider は SourceProvider を差し替える
 func WithContainerSourceProvider(provider coreingestion.SourceProvider) ContainerOption {
 	return func(opts *containerOptions) {
@@ -117,19 +184,128 @@ func WithContainerWikiFileReader(reader corewiki.FileReader) ContainerOption {
 
 // NewContainer は設定からコンテナを生成する。
 func NewContainer(ctx context.Context, cfg *config.Config, opts ...ContainerOption) (*ServiceContainer, error) {
-	db, err := database.New(ctx, database.ConnectionParams{
-		Host:     cfg.Database.Host,
-		Port:     cfg.Database.Port,
-		User:     cfg.Database.User,
-		Password: cfg.Database.Password,
-		DBName:   cfg.Database.DBName,
-		SSLMode:  cfg.Database.SSLMode,
-	})
+	params := connectionParams(cfg.Database)
+	var dbOpts []database.Option
+	if replica := cfg.Database.Replica; replica.Host != "" {
+		// 検索（SearchRepository）の読み取りをリードレプリカに振り分ける（接続プールの設定はプライマリと同じ）
+		replicaParams := params
+		replicaParams.Host = replica.Host
+		replicaParams.Port = replica.Port
+		replicaParams.User = replica.User
+		replicaParams.Password = replica.Password
+		replicaParams.DBName = replica.DBName
+		replicaParams.SSLMode = replica.SSLMode
+		dbOpts = append(dbOpts, database.WithReplica(replicaParams, time.Duration(replica.MaxLagSeconds)*time.Second))
+	}
+	db, err := database.New(ctx, params, dbOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("データベース初期化に失敗しました: %w", err)
 	}
 
-	return NewContainerWithDB(cfg, db, opts...)
+	// ベクトル次元を DB のカラム定義と突き合わせる（呼び出し元の設定は変更しない）
+	stored, err := db.VectorDimension(ctx, "embeddings", "vector")
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("ベクトル次元の取得に失敗しました: %w", err)
+	}
+	dimension, err := embedding.ResolveDimension(cfg.Embedding.Dimension, stored)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	resolved := *cfg
+	resolved.Embedding.Dimension = dimension
+
+	svc, err := NewContainerWithDB(&resolved, db, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	options := containerOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if !options.skipEmbeddingModelCheck && svc.embeddingModel != "" {
+		if err := svc.checkEmbeddingModel(ctx, dimension, stored); err != nil {
+			svc.Close()
+			return nil, err
+		}
+	}
+	if !options.skipEmbeddingModelCheck {
+		if err := svc.checkVectorStorage(ctx, cfg.Embedding.Storage); err != nil {
+			svc.Close()
+			return nil, err
+		}
+	}
+	return svc, nil
+}
+
+// connectionParams はデータベースの設定から接続パラメータを作成する
+func connectionParams(cfg config.DatabaseConfig) database.ConnectionParams {
+	return database.ConnectionParams{
+		Host:            cfg.Host,
+		Port:            cfg.Port,
+		User:            cfg.User,
+		Password:        cfg.Password,
+		DBName:          cfg.DBName,
+		SSLMode:         cfg.SSLMode,
+		MaxConns:        int32(cfg.MaxConns),
+		MinConns:        int32(cfg.MinConns),
+		MaxConnIdleTime: time.Duration(cfg.MaxConnIdleSeconds) * time.Second,
+		MaxConnLifetime: time.Duration(cfg.MaxConnLifetimeSeconds) * time.Second,
+	}
+}
+
+// checkVectorStorage はベクトルカラムの格納型が設定（EMBEDDING_STORAGE）と一致するかを検証する
+// 一致しない場合はクエリベクトルの型がカラムと合わずインデックスが使われないため、起動時に検出する
+func (c *ServiceContainer) checkVectorStorage(ctx context.Context, name string) error {
+	want, err := database.ParseVectorStorage(name)
+	if err != nil {
+		return err
+	}
+	for _, table := range []string{"embeddings", "summary_embeddings"} {
+		target := database.VectorIndexTargets[table]
+		got, err := c.database.VectorStorageOf(ctx, target.Table, target.Column)
+		if err != nil {
+			return fmt.Errorf("ベクトルの格納型の取得に失敗しました: %w", err)
+		}
+		if got != want {
+			return fmt.Errorf("%s.%s の格納型 %s が EMBEDDING_STORAGE=%s と一致しません（`dev-rag db vector-storage` で変換してください）",
+				target.Table, target.Column, got, want)
+		}
+	}
+	return nil
+}
+
+// checkEmbeddingModel は設定した Embedding モデルが登録済みで、出力する次元数がベクトルカラムの次元数と一致するかを検証する
+func (c *ServiceContainer) checkEmbeddingModel(ctx context.Context, dimension, stored int) error {
+	summaryStored, err := c.database.VectorDimension(ctx, "summary_embeddings", "vector")
+	if err != nil {
+		return fmt.Errorf("ベクトル次元の取得に失敗しました: %w", err)
+	}
+	registered, err := c.EmbeddingModels.GetEmbeddingModel(ctx, c.embeddingModel)
+	if err != nil {
+		return fmt.Errorf("Embeddingモデルの取得に失敗しました: %w", err)
+	}
+	if err := coreingestion.ValidateEmbeddingModel(registered, c.embeddingModel, dimension, []coreingestion.VectorColumn{
+		{Table: "embeddings", Dimension: stored},
+		{Table: "summary_embeddings", Dimension: summaryStored},
+	}); err != nil {
+		return err
+	}
+
+	// 追加のモデルは chunk_embeddings の外部キーで登録済みであることが必要になる
+	for model := range c.AdditionalEmbedders {
+		registered, err := c.EmbeddingModels.GetEmbeddingModel(ctx, model)
+		if err != nil {
+			return fmt.Errorf("Embeddingモデルの取得に失敗しました: %w", err)
+		}
+		if registered.IsAbsent() {
+			return fmt.Errorf("%w: %s（`dev-rag embedding register --model %s --dimension <次元数>` で登録してください）",
+				coreingestion.ErrEmbeddingModelNotRegistered, model, model)
+		}
+	}
+	return nil
 }
 
 // NewContainerWithDB は既存の Database を受け取りコンテナを生成する。
@@ -142,31 +318,65 @@ func NewContainerWithDB(cfg *config.Config, db *database.Database, opts ...Conta
 		options.logger = slog.Default()
 	}
 
-	// Embedder (OpenAI)
+	// プロバイダー単位のレートリミッター（Embedder と LLM で共有）
+	rateLimits := make(map[string]ratelimit.Config, len(cfg.RateLimits))
+	for provider, rl := range cfg.RateLimits {
+		rateLimits[provider] = ratelimit.Config{
+			RequestsPerMinute: rl.RequestsPerMinute,
+			TokensPerMinute:   rl.TokensPerMinute,
+			MaxConcurrency:    rl.MaxConcurrency,
+		}
+	}
+	limiters := ratelimit.NewRegistry(rateLimits)
+
+	// Embedder（EMBEDDING_PROVIDER で選択）
 	embedder := options.embedder
+	var embeddingModel string
+	// 追加のEmbeddingモデル（EMBEDDING_ADDITIONAL_MODELS、Embedder を注入した場合は使用しない）
+	additionalEmbedders := make(map[string]coreingestion.Embedder)
 	if embedder == nil {
-		embedder = openai.NewEmbedder(
-			cfg.OpenAI.APIKey,
-			openai.WithEmbeddingModel(cfg.OpenAI.EmbeddingModel),
-			openai.WithEmbeddingDimension(cfg.OpenAI.EmbeddingDimension),
-		)
+		var err error
+		embedder, embeddingModel, err = newEmbedder(cfg.Embedding, cfg.Embedding.Model, cfg.Embedding.Dimension, limiters, options.logger)
+		if err != nil {
+			return nil, fmt.Errorf("Embedder 初期化に失敗しました: %w", err)
+		}
+		for _, model := range cfg.Embedding.AdditionalModels {
+			// 次元数はモデルの既定値とする（モデルごとに chunk_embeddings に保存するため主モデルと揃える必要はない）
+			additional, name, err := newEmbedder(cfg.Embedding, model, 0, limiters, options.logger)
+			if err != nil {
+				return nil, fmt.Errorf("Embedder 初期化に失敗しました (%s): %w", model, err)
+			}
+			if name == embeddingModel {
+				continue
+			}
+			additionalEmbedders[name] = usage.NewMeteredEmbedder(additional)
+		}
+	}
+	// 使用量の計測（コンテキストに Tracker が設定された実行のみ記録）
+	embedder = usage.NewMeteredEmbedder(embedder)
+
+	// 単価表（LLM_PRICING で上書き）
+	pricing, err := usage.ParsePricing(cfg.Budget.Pricing)
+	if err != nil {
+		return nil, fmt.Errorf("LLM_PRICING の解析に失敗しました: %w", err)
 	}
 
 	// SourceProvider (Git)
+	// リリースノートの生成でGitの履歴を読むため、ソースプロバイダーを注入した場合も Git の Provider を作成する
+	gitProvider := git.NewProvider(git.NewClient(cfg.Git.SSHKeyPath, cfg.Git.SSHPassword), cfg.Git.CloneDir, cfg.Git.DefaultBranch)
 	sourceProvider := options.sourceProvider
 	if sourceProvider == nil {
-		gitClient := git.NewClient(cfg.Git.SSHKeyPath, cfg.Git.SSHPassword)
-		sourceProvider = git.NewProvider(gitClient, cfg.Git.CloneDir, cfg.Git.DefaultBranch)
+		sourceProvider = gitProvider
 	}
 
 	// Chunker / Detector / TokenCounter
 	chunkerFactory := options.chunkerFactory
 	if chunkerFactory == nil {
-		defaultChunker, err := chunk.NewDefaultChunker()
+		factory, err := newDefaultChunkerFactory(cfg.Chunk)
 		if err != nil {
 			return nil, fmt.Errorf("Chunker 初期化に失敗しました: %w", err)
 		}
-		chunkerFactory = &defaultChunkerFactory{base: defaultChunker}
+		chunkerFactory = factory
 	}
 
 	langDetector := options.languageDetector
@@ -185,22 +395,50 @@ func NewContainerWithDB(cfg *config.Config, db *database.Database, opts ...Conta
 
 	// Repository (PostgreSQL)
 	indexQueries := indexsqlc.New(db.Pool)
-	indexRepo := postgres.NewRepository(indexQueries)
+	indexRepo := postgres.NewRepository(indexQueries, postgres.WithTxBeginner(db.Pool))
 
 	// SummaryRepository
 	summaryRepo := postgres.NewSummaryRepository(indexQueries)
 
-	// LLMClient (OpenAI)
-	llmClient := options.llmClient
-	if llmClient == nil {
-		openaiLLMClient, err := openai.NewClientWithAPIKey(cfg.OpenAI.APIKey, cfg.OpenAI.LLMModel)
+	// LLMの応答のキャッシュ（要約・Wikiページの生成など、同じ入力の呼び出しで再び課金しない）
+	llmCacheRepo := postgres.NewLLMCacheRepository(indexQueries)
+
+	// LLMClient（ユースケースごとに LLM_<USECASE>_* で選択）
+	llmClients := make(map[string]corewiki.LLMClient, len(config.LLMUseCases))
+	for _, useCase := range config.LLMUseCases {
+		if options.llmClient != nil {
+			llmClients[useCase] = options.llmClient
+			continue
+		}
+		llmCfg := cfg.LLMFor(useCase)
+		client, err := newLLMClient(llmCfg, limiters, options.logger)
 		if err != nil {
-			return nil, fmt.Errorf("OpenAI LLMクライアント初期化に失敗しました: %w", err)
+			return nil, fmt.Errorf("LLMクライアント初期化に失敗しました (%s): %w", useCase, err)
+		}
+		llmClients[useCase] = usage.NewMeteredLLM(client, usage.Kind(useCase), llmCfg.Model)
+		if cfg.LLMCache.Enabled {
+			// キャッシュした応答は使用量・予算に計上しないため、MeteredLLM の外側に置く
+			llmClients[useCase] = llm.NewCachedLLM(llmClients[useCase], llmCacheRepo, llmCfg.Model,
+				llm.WithCacheTTL(time.Duration(cfg.LLMCache.TTLDays)*24*time.Hour),
+				llm.WithCacheLogger(options.logger),
+			)
 		}
-		llmClient = openaiLLMClient
 	}
 
-	// IndexService
+	// IndexService（ソースタイプごとに SourceProvider のみ異なる）
+	indexRunRepo := postgres.NewIndexRunRepository(indexQueries)
+	indexOpts := []coreingestion.IndexServiceOption{
+		coreingestion.WithIndexLogger(options.logger),
+		coreingestion.WithIndexRunRepository(indexRunRepo),
+		coreingestion.WithIndexChunkerConfig(chunkerConfig(cfg.Chunk.Default)),
+		coreingestion.WithIndexPipelineConfig(pipelineConfig(cfg.Pipeline)),
+		coreingestion.WithIndexImportanceCalculator(importance.NewImportanceCalculator(
+			postgres.NewImportanceRepository(indexQueries),
+			importance.WithImportanceAlgorithm(importance.Algorithm(cfg.Importance.Algorithm)),
+			importance.WithImportanceEditWeight(cfg.Importance.EditWeight),
+			importance.WithImportanceLogger(options.logger),
+		)),
+	}
 	indexService := coreingestion.NewIndexService(
 		indexRepo,
 		sourceProvider,
@@ -208,22 +446,111 @@ func NewContainerWithDB(cfg *config.Config, db *database.Database, opts ...Conta
 		chunkerFactory,
 		langDetector,
 		tokenCounter,
-		coreingestion.WithIndexLogger(options.logger),
+		indexOpts...,
+	)
+	localIndexService := coreingestion.NewIndexService(
+		indexRepo,
+		localfs.NewProvider(),
+		embedder,
+		chunkerFactory,
+		langDetector,
+		tokenCounter,
+		indexOpts...,
+	)
+	discussionIndexService := coreingestion.NewIndexService(
+		indexRepo,
+		forge.NewProvider(forge.Config{
+			GitHubToken:  cfg.Forge.GitHubToken,
+			GitHubAPIURL: cfg.Forge.GitHubAPIURL,
+			GitLabToken:  cfg.Forge.GitLabToken,
+		}),
+		embedder,
+		chunkerFactory,
+		langDetector,
+		tokenCounter,
+		indexOpts...,
+	)
+
+	// LLMプロンプトテンプレート（データベースの上書き → テンプレートディレクトリ → 組み込みの順に解決）
+	prompts := prompt.NewRegistry(
+		prompt.WithRepository(postgres.NewPromptTemplateRepository(indexQueries)),
+		prompt.WithTemplateDir(cfg.PromptTemplateDir),
+		prompt.WithRegistryLogger(options.logger),
 	)
 
 	// SummaryService
 	summaryService := summary.NewSummaryService(
 		indexRepo,
 		summaryRepo,
-		llmClient,
+		llmClients["summary"],
 		embedder,
 		summary.WithSummaryLogger(options.logger),
+		summary.WithSummaryPrompts(prompts),
 	)
 
 	// SearchService（新コア用リポジトリ）
+	// 検索はリードレプリカを設定した場合はレプリカから読み取る（インデックス化の書き込みはプライマリ）
 	searchQueries := indexsqlc.New(db.Pool)
-	searchRepo := postgres.NewSearchRepository(searchQueries)
-	searchService := coresearch.NewSearchService(searchRepo, embedder, coresearch.WithSearchLogger(options.logger))
+	searchRepo := postgres.NewSearchRepository(indexsqlc.New(db.Reader()))
+	searchOpts := []coresearch.SearchServiceOption{
+		coresearch.WithSearchLogger(options.logger),
+		coresearch.WithSearchImportanceWeight(cfg.Search.ImportanceWeight),
+		coresearch.WithSearchRecencyHalfLife(cfg.Search.RecencyHalfLifeDays),
+		coresearch.WithSearchStrategy(coresearch.RetrievalStrategy(cfg.Search.Strategy)),
+		coresearch.WithSearchCandidateFiles(cfg.Search.CandidateFiles),
+		coresearch.WithSearchPrimaryModel(embeddingModel),
+	}
+	for model, additional := range additionalEmbedders {
+		searchOpts = append(searchOpts, coresearch.WithSearchModelEmbedder(model, additional))
+	}
+	searchService := coresearch.NewSearchService(searchRepo, embedder, searchOpts...)
+
+	// GlossaryService
+	glossaryRepo := postgres.NewGlossaryRepository(searchQueries)
+	glossaryService := coreglossary.NewGlossaryService(glossaryRepo, llmClients["summary"],
+		coreglossary.WithGlossaryLogger(options.logger),
+		coreglossary.WithGlossaryMaxBatches(cfg.Glossary.MaxBatches),
+		coreglossary.WithGlossaryPrompts(prompts),
+	)
+
+	// CODEOWNERS から記録したファイルの担当者の読み取り（質問応答の参照ソースと品質改善アクションの担当者のヒントで共有）
+	ownerRepo := postgres.NewOwnerRepository(searchQueries)
+	actionGenerator := corequality.NewActionGenerator(llmClients["summary"],
+		corequality.WithCodeownersLookup(ownerRepo),
+		corequality.WithActionGeneratorLogger(options.logger),
+		corequality.WithActionPrompts(prompts),
+	)
+	qualityService := corequality.NewService(postgres.NewQualityRepository(searchQueries), actionGenerator,
+		corequality.WithServiceLogger(options.logger),
+	)
+
+	// プロダクトごとの設定（カバレッジアラートの閾値など）
+	productConfigRepo := postgres.NewProductConfigRepository(searchQueries)
+	coverageAlerts := coverage.NewAlertGenerator(indexRepo,
+		coverage.WithProductConfig(productConfigRepo),
+		coverage.WithDefaultThresholds(alertThresholds(cfg.Alert)),
+		coverage.WithAlertGeneratorLogger(options.logger),
+	)
+
+	// 設計判断の記録（ADR・RFC）の読み取り（Wikiの設計判断ページと質問応答の優先付けで共有）
+	decisionRepo := postgres.NewDecisionRepository(searchQueries)
+	apiEndpointRepo := postgres.NewAPIEndpointRepository(searchQueries)
+	schemaTableRepo := postgres.NewSchemaTableRepository(searchQueries)
+	infraResourceRepo := postgres.NewInfraResourceRepository(searchQueries)
+	dependencyGraphRepo := postgres.NewDependencyGraphRepository(searchQueries)
+	symbolRepo := postgres.NewSymbolRepository(searchQueries)
+
+	// アーキテクチャのドリフトの検出（インデックス後の品質ノートの記録とWikiの最近の変更ページで共有）
+	architectureDrift := drift.NewService(&driftSnapshotAdapter{repo: indexRepo}, dependencyGraphRepo,
+		drift.WithNoteRecorder(qualityService),
+		drift.WithServiceLogger(options.logger),
+	)
+	hotspotService := hotspot.NewService(postgres.NewHotspotRepository(searchQueries))
+	externalDependencies := thirdparty.NewService(postgres.NewExternalDependencyRepository(searchQueries))
+	licenses := license.NewService(postgres.NewLicenseRepository(searchQueries))
+	monorepoServices := monorepo.NewCatalog(postgres.NewMonorepoRepository(searchQueries))
+	testCoverageRepo := postgres.NewTestCoverageRepository(searchQueries)
+	testCoverageService := testcoverage.NewService(testCoverageRepo, testcoverage.WithServiceLogger(options.logger))
 
 	// WikiService（実際のOpenAIクライアントを使用）
 	wikiRepo := options.wikiRepo
@@ -234,24 +561,183 @@ func NewContainerWithDB(cfg *config.Config, db *database.Database, opts ...Conta
 	if wikiReader == nil {
 		wikiReader = &wikiFileReaderStub{}
 	}
-	wikiService := corewiki.NewWikiService(searchService, wikiRepo, llmClient, wikiReader, corewiki.WithWikiLogger(options.logger))
+	sourceFileRepo := postgres.NewSourceFileRepository(searchQueries)
+	onboardingRepo := postgres.NewOnboardingRepository(searchQueries)
+	wikiService := corewiki.NewWikiService(searchService, wikiRepo, llmClients["wiki"], wikiReader,
+		corewiki.WithWikiLogger(options.logger),
+		corewiki.WithWikiDependencyGraph(dependencyGraphRepo),
+		corewiki.WithWikiAPISymbols(postgres.NewAPISymbolRepository(searchQueries)),
+		corewiki.WithWikiGlossary(glossaryRepo),
+		corewiki.WithWikiOnboarding(onboardingRepo),
+		corewiki.WithWikiRunbook(onboardingRepo),
+		corewiki.WithWikiDecisions(decisionRepo),
+		corewiki.WithWikiAPIEndpoints(apiEndpointRepo),
+		corewiki.WithWikiInfraResources(infraResourceRepo),
+		corewiki.WithWikiArchitectureDrift(architectureDrift),
+		corewiki.WithWikiHotspots(hotspotService),
+		corewiki.WithWikiExternalDependencies(externalDependencies),
+		corewiki.WithWikiLicenses(licenses),
+		corewiki.WithWikiSourceFiles(sourceFileRepo),
+		corewiki.WithWikiSnapshots(sourceFileRepo),
+		corewiki.WithWikiPrompts(prompts),
+	)
 
 	// AskService
-	askService := coreask.NewAskService(searchService, llmClient, coreask.WithAskLogger(options.logger))
+	askOpts := []coreask.AskServiceOption{
+		coreask.WithAskLogger(options.logger),
+		coreask.WithAskGuardrail(coreask.NewGuardrail(
+			coreask.WithGuardrailLogger(options.logger),
+			coreask.WithGuardrailScopeMinScore(cfg.Ask.ScopeMinScore),
+		)),
+		coreask.WithAskGlossary(glossaryService),
+		coreask.WithAskDecisions(decisionRepo),
+		coreask.WithAskSchema(schemaTableRepo),
+		coreask.WithAskInfra(infraResourceRepo),
+		coreask.WithAskDiscussions(postgres.NewDiscussionRepository(searchQueries)),
+		coreask.WithAskOwners(ownerRepo),
+		coreask.WithAskCoverage(testCoverageRepo),
+		coreask.WithAskSymbols(symbolRepo),
+		coreask.WithAskMinRelevance(cfg.Ask.MinRelevance),
+		coreask.WithAskRouting(coreask.RoutingPolicy{MinScore: cfg.Ask.RoutingMinScore, Margin: cfg.Ask.RoutingMargin}),
+		coreask.WithAskProductConfig(productConfigRepo),
+		coreask.WithAskPrompts(prompts),
+		coreask.WithAskLatencyBudget(time.Duration(cfg.Ask.LatencyBudgetMs) * time.Millisecond),
+		coreask.WithAskDependencyExpansion(coresearch.DependencyExpansion{
+			MaxHops:     cfg.Ask.DependencyHops,
+			MaxChunks:   cfg.Ask.DependencyMaxChunks,
+			TokenBudget: cfg.Ask.DependencyTokenBudget,
+			DepTypes:    cfg.Ask.DependencyTypes,
+		}),
+	}
+	if cfg.Ask.CacheEnabled {
+		// よくある質問で、検索とLLMの呼び出しを繰り返さないよう回答をキャッシュする
+		askOpts = append(askOpts, coreask.WithAskAnswerCache(postgres.NewAskCacheRepository(indexQueries), time.Duration(cfg.Ask.CacheTTLHours)*time.Hour))
+	}
+	askService := coreask.NewAskService(searchService, llmClients["ask"], askOpts...)
+
+	// リリースノート（Wikiのドキュメントと同じLLMで生成する）
+	releaseNotes := release.NewService(postgres.NewReleaseNoteRepository(searchQueries), gitProvider, llmClients["wiki"],
+		release.WithServiceLogger(options.logger),
+		release.WithPrompts(prompts),
+	)
+
+	// 不整合の修復で Embedding を再生成するため、インデックス化と同じ Embedder を使用する
+	integrityChecker := integrity.NewChecker(postgres.NewIntegrityRepository(indexQueries), embedder,
+		integrity.WithCheckerLogger(options.logger),
+	)
+
+	// CIのチェック（ドライランは再取得できる Git・ローカルディレクトリのソースのみ）
+	ciChecks := cicheck.NewService(indexRepo,
+		cicheck.WithIndexPlanner(coreingestion.SourceTypeGit, indexService),
+		cicheck.WithIndexPlanner(coreingestion.SourceTypeLocal, localIndexService),
+		cicheck.WithCoverageAlerter(coverageAlerts),
+		cicheck.WithTestCoverage(testCoverageRepo),
+		cicheck.WithSourceFiles(sourceFileRepo),
+		cicheck.WithSummaryRefresher(summaryService),
+		cicheck.WithServiceLogger(options.logger),
+	)
 
 	return &ServiceContainer{
-		IndexService:      indexService,
-		SummaryService:    summaryService,
-		SearchService:     searchService,
-		WikiService:       wikiService,
-		AskService:        askService,
-		IngestionRepo:     indexRepo,
-		SummaryRepository: summaryRepo,
-		logger:            options.logger,
-		database:          db,
+		IndexService:           indexService,
+		LocalIndexService:      localIndexService,
+		DiscussionIndexService: discussionIndexService,
+		SummaryService:         summaryService,
+		SearchService:          searchService,
+		WikiService:            wikiService,
+		AskService:             askService,
+		GlossaryService:        glossaryService,
+		QualityService:         qualityService,
+		CoverageAlerts:         coverageAlerts,
+		CoverageTrend:          coverage.NewTrendService(postgres.NewCoverageHistoryRepository(searchQueries)),
+		ProductConfig:          productConfigRepo,
+		Prompts:                prompts,
+		LLMCache:               llmCacheRepo,
+		EmbeddingModels:        postgres.NewEmbeddingModelRepository(indexQueries),
+		IndexRuns:              indexRunRepo,
+		APIEndpoints:           apiEndpointRepo,
+		Symbols:                symbolRepo,
+		DependencyGraph:        dependencyGraphRepo,
+		ArchitectureDrift:      architectureDrift,
+		Hotspots:               hotspotService,
+		ExternalDependencies:   externalDependencies,
+		Licenses:               licenses,
+		MonorepoServices:       monorepoServices,
+		TestCoverage:           testCoverageService,
+		CIChecks:               ciChecks,
+		Integrity:              integrityChecker,
+		ReleaseNotes:           releaseNotes,
+		IngestionRepo:          indexRepo,
+		SummaryRepository:      summaryRepo,
+		WikiPublications:       postgres.NewWikiPublicationRepository(searchQueries),
+		AskFeedback:            postgres.NewAskFeedbackRepository(searchQueries),
+		AdditionalEmbedders:    additionalEmbedders,
+		logger:                 options.logger,
+		database:               db,
+		glossaryOnIndex:        cfg.Glossary.ExtractOnIndex,
+		embeddingModel:         embeddingModel,
+		pricing:                pricing,
+		budget: usage.Budget{
+			MaxCostUSD: cfg.Budget.MaxCostUSD,
+			Action:     usage.BudgetAction(cfg.Budget.Action),
+		},
 	}, nil
 }
 
+// newEmbedder は設定されたプロバイダーの model の Embedder をレート制限付きで生成し、モデル名とともに返す。
+func newEmbedder(cfg config.EmbeddingConfig, model string, dimension int, limiters *ratelimit.Registry, logger *slog.Logger) (coreingestion.Embedder, string, error) {
+	provider, err := embedding.Lookup(cfg.Provider)
+	if err != nil {
+		return nil, "", err
+	}
+	base, err := provider.Factory(embedding.Config{
+		Provider:        cfg.Provider,
+		Model:           model,
+		Dimension:       dimension,
+		APIKey:          cfg.APIKey,
+		BaseURL:         cfg.BaseURL,
+		AzureAPIVersion: cfg.AzureAPIVersion,
+		VertexProject:   cfg.VertexProject,
+		VertexLocation:  cfg.VertexLocation,
+		VertexToken:     cfg.VertexToken,
+		MaxInputTokens:  cfg.MaxInputTokens,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return ratelimit.NewEmbedder(
+		base,
+		limiters.Get(cfg.Provider),
+		ratelimit.WithProviderName(cfg.Provider),
+		ratelimit.WithRetryable(provider.Retryable),
+		ratelimit.WithLogger(logger),
+	), base.ModelName(), nil
+}
+
+// newLLMClient は設定されたプロバイダーの LLM クライアントをレート制限付きで生成する。
+func newLLMClient(cfg config.LLMConfig, limiters *ratelimit.Registry, logger *slog.Logger) (corewiki.LLMClient, error) {
+	provider, err := completion.Lookup(cfg.Provider)
+	if err != nil {
+		return nil, err
+	}
+	client, err := provider.Factory(completion.Config{
+		Provider:        cfg.Provider,
+		Model:           cfg.Model,
+		APIKey:          cfg.APIKey,
+		BaseURL:         cfg.BaseURL,
+		AzureAPIVersion: cfg.AzureAPIVersion,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ratelimit.NewLLMClient(
+		client,
+		limiters.Get(cfg.Provider),
+		ratelimit.WithProviderName(cfg.Provider),
+		ratelimit.WithRetryable(provider.Retryable),
+		ratelimit.WithLogger(logger),
+	), nil
+}
+
 // Close は内部リソースを解放する。
 func (c *ServiceContainer) Close() {
 	if c != nil && c.database != nil {
@@ -259,6 +745,40 @@ func (c *ServiceContainer) Close() {
 	}
 }
 
+// NewUsageTracker は設定済みの単価表と予算で、1回の実行分の使用量 Tracker を作成する。
+func (c *ServiceContainer) NewUsageTracker() *usage.Tracker {
+	return usage.NewTracker(c.pricing, c.budget)
+}
+
+// NewUsageTrackerWithMaxCost は推定コストの上限を上書きした使用量の Tracker を生成する（超過時の動作は LLM_BUDGET_ACTION）
+func (c *ServiceContainer) NewUsageTrackerWithMaxCost(maxCostUSD float64) *usage.Tracker {
+	budget := c.budget
+	budget.MaxCostUSD = maxCostUSD
+	return usage.NewTracker(c.pricing, budget)
+}
+
+// ExtractGlossaryOnIndex はインデックス化の後処理としてプロダクトの用語集を抽出する。
+// GLOSSARY_EXTRACT_ON_INDEX=false の場合は何もせず nil を返す。
+func (c *ServiceContainer) ExtractGlossaryOnIndex(ctx context.Context, productName string) (*coreglossary.ExtractResult, error) {
+	if !c.glossaryOnIndex {
+		return nil, nil
+	}
+	productOpt, err := c.IngestionRepo.GetProductByName(ctx, productName)
+	if err != nil {
+		return nil, fmt.Errorf("プロダクト取得に失敗しました: %w", err)
+	}
+	product, ok := productOpt.Get()
+	if !ok {
+		return nil, fmt.Errorf("プロダクトが見つかりません: %s", productName)
+	}
+	return c.GlossaryService.Extract(ctx, product.ID)
+}
+
+// EmbeddingModelName は設定した Embedding モデル名を返す（Embedder を注入した場合は空）
+func (c *ServiceContainer) EmbeddingModelName() string {
+	return c.embeddingModel
+}
+
 // Logger はロガーを返す。
 func (c *ServiceContainer) Logger() *slog.Logger {
 	if c == nil || c.logger == nil {
@@ -289,18 +809,136 @@ func (a *languageDetectorAdapter) DetectLanguage(path string, content []byte) (s
 	return a.detector.DetectContentType(path, content), nil
 }
 
-// defaultChunkerFactory は単一の DefaultChunker を使い回すファクトリ。
+// driftSnapshotAdapter は ingestion.Repository を drift.SnapshotReader に適合させる。
+type driftSnapshotAdapter struct {
+	repo coreingestion.Repository
+}
+
+func (a *driftSnapshotAdapter) GetSnapshot(ctx context.Context, id uuid.UUID) (mo.Option[*drift.Snapshot], error) {
+	snapshotOpt, err := a.repo.GetSnapshotByID(ctx, id)
+	if err != nil {
+		return mo.None[*drift.Snapshot](), err
+	}
+	snapshot, ok := snapshotOpt.Get()
+	if !ok {
+		return mo.None[*drift.Snapshot](), nil
+	}
+	sourceOpt, err := a.repo.GetSourceByID(ctx, snapshot.SourceID)
+	if err != nil {
+		return mo.None[*drift.Snapshot](), err
+	}
+	source, ok := sourceOpt.Get()
+	if !ok {
+		return mo.None[*drift.Snapshot](), nil
+	}
+	return mo.Some(toDriftSnapshot(snapshot, source.Name)), nil
+}
+
+func (a *driftSnapshotAdapter) ListSnapshots(ctx context.Context, sourceID uuid.UUID) ([]*drift.Snapshot, error) {
+	snapshots, err := a.repo.ListSnapshotsBySource(ctx, sourceID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*drift.Snapshot, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		result = append(result, toDriftSnapshot(snapshot, ""))
+	}
+	return result, nil
+}
+
+func (a *driftSnapshotAdapter) ListSourceIDs(ctx context.Context, productID uuid.UUID) ([]uuid.UUID, error) {
+	sources, err := a.repo.ListSourcesByProductID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uuid.UUID, 0, len(sources))
+	for _, source := range sources {
+		ids = append(ids, source.ID)
+	}
+	return ids, nil
+}
+
+func toDriftSnapshot(snapshot *coreingestion.SourceSnapshot, sourceName string) *drift.Snapshot {
+	return &drift.Snapshot{
+		ID:         snapshot.ID,
+		SourceID:   snapshot.SourceID,
+		SourceName: sourceName,
+		Version:    snapshot.VersionIdentifier,
+		Indexed:    snapshot.IsIndexed(),
+		CreatedAt:  snapshot.CreatedAt,
+	}
+}
+
+// defaultChunkerFactory は DefaultChunker を使い回すファクトリ。
+// チャンクのトークン数を言語ごとに設定している場合は、その言語のみ別の DefaultChunker を使用する。
 type defaultChunkerFactory struct {
-	base *chunk.DefaultChunker
+	base     *chunk.DefaultChunker
+	profiles map[string]*chunk.DefaultChunker
+}
+
+func newDefaultChunkerFactory(cfg config.ChunkConfig) (*defaultChunkerFactory, error) {
+	base, err := chunk.NewDefaultChunkerWithConfig(chunkerConfig(cfg.Default))
+	if err != nil {
+		return nil, err
+	}
+	profiles := make(map[string]*chunk.DefaultChunker, len(cfg.Profiles))
+	for language, profile := range cfg.Profiles {
+		chunker, err := chunk.NewDefaultChunkerWithConfig(chunkerConfig(profile))
+		if err != nil {
+			return nil, err
+		}
+		profiles[language] = chunker
+	}
+	return &defaultChunkerFactory{base: base, profiles: profiles}, nil
 }
 
 func (f *defaultChunkerFactory) GetChunker(language string) (chunk.Chunker, error) {
+	base := f.base
+	if chunker, ok := f.profiles[coreingestion.LanguageFromContentType(language)]; ok {
+		base = chunker
+	}
 	return &defaultChunkerAdapter{
-		base:        f.base,
+		base:        base,
 		contentType: language,
 	}, nil
 }
 
+// chunkerConfig は設定のチャンクのトークン数を ChunkerConfig に変換する。
+func chunkerConfig(profile config.ChunkProfile) *chunk.ChunkerConfig {
+	c := chunk.DefaultChunkerConfig()
+	c.TargetTokens = profile.TargetTokens
+	c.MaxTokens = profile.MaxTokens
+	c.MinTokens = profile.MinTokens
+	c.Overlap = profile.OverlapTokens
+	return c
+}
+
+// alertThresholds は設定の閾値で組み込みのカバレッジアラートの閾値を上書きする。
+func alertThresholds(cfg config.AlertConfig) coverage.Thresholds {
+	t := coverage.DefaultThresholds()
+	for domain, v := range cfg.MinCoverage {
+		t.MinCoverage[domain] = v
+	}
+	t.MaxUnindexedImportantFiles = cfg.MaxUnindexedImportantFiles
+	return t
+}
+
+// pipelineConfig は設定のワーカー数・バッチサイズでパイプラインの既定値を上書きする。
+func pipelineConfig(cfg config.PipelineConfig) *coreingestion.PipelineConfig {
+	c := coreingestion.DefaultPipelineConfig()
+	if cfg.ChunkWorkers > 0 {
+		c.ChunkWorkerCount = cfg.ChunkWorkers
+	}
+	if cfg.EmbeddingWorkers > 0 {
+		c.EmbeddingWorkerCount = cfg.EmbeddingWorkers
+	}
+	if cfg.EmbeddingBatchSize > 0 {
+		c.EmbeddingBatchSize = cfg.EmbeddingBatchSize
+	}
+	c.StoreFileBlobs = cfg.StoreFileBlobs
+	return c
+}
+
 // defaultChunkerAdapter は DefaultChunker を Chunker インターフェースに適合させる。
 type defaultChunkerAdapter struct {
 	base        *chunk.DefaultChunker
@@ -5,25 +5,16 @@ import (
 	"crypto/sha256"
 	"fmt"
 
-	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 // AdvisoryLock はPostgreSQLのアドバイザリロックを管理します
+// セッションスコープのロック（pg_advisory_lock）を、ロック専用に確保したプールの接続で保持します
 type AdvisoryLock struct {
-	tx     pgx.Tx
+	conn   *pgxpool.Conn
 	lockID int64
 }
 
-// Manager はアドバイザリロックの取得を仲介します
-type Manager struct {
-	tx pgx.Tx
-}
-
-// NewManager はトランザクションからロックマネージャーを生成します
-func NewManager(tx pgx.Tx) *Manager {
-	return &Manager{tx: tx}
-}
-
 // GenerateLockID は文字列からロックIDを生成します
 func GenerateLockID(parts ...string) int64 {
 	h := sha256.New()
@@ -42,32 +33,50 @@ func GenerateLockID(parts ...string) int64 {
 }
 
 // Acquire はPostgreSQLアドバイザリロックを取得します
-// トランザクションスコープのロックを使用（pg_advisory_xact_lock）
-func Acquire(ctx context.Context, tx pgx.Tx, lockID int64) (*AdvisoryLock, error) {
-	return acquire(ctx, tx, lockID)
-}
-
-// Acquire はManager経由でも呼び出せます
-func (m *Manager) Acquire(ctx context.Context, lockID int64) (*AdvisoryLock, error) {
-	return acquire(ctx, m.tx, lockID)
-}
-
-func acquire(ctx context.Context, tx pgx.Tx, lockID int64) (*AdvisoryLock, error) {
-	_, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock($1)", lockID)
+// トランザクションを開かずに保持するため、長時間のインデックス化でも VACUUM や WAL の回収を妨げません
+func Acquire(ctx context.Context, pool *pgxpool.Pool, lockID int64) (*AdvisoryLock, error) {
+	conn, err := pool.Acquire(ctx)
 	if err != nil {
+		return nil, fmt.Errorf("failed to acquire advisory lock connection: %w", err)
+	}
+
+	if _, err := conn.Exec(ctx, "SELECT pg_advisory_lock($1)", lockID); err != nil {
+		conn.Release()
 		return nil, fmt.Errorf("failed to acquire advisory lock: %w", err)
 	}
 
 	return &AdvisoryLock{
-		tx:     tx,
+		conn:   conn,
 		lockID: lockID,
 	}, nil
 }
 
-// Release はアドバイザリロックを解放します
-// 注: トランザクションスコープのロック（pg_advisory_xact_lock）を使用しているため、
-// トランザクション終了時に自動的に解放されます。このメソッドは明示的な解放が不要です。
+// Release はアドバイザリロックを解放し、接続をプールに返却します
+// 解放に失敗した接続はロックを保持したまま再利用されないよう破棄します
 func (l *AdvisoryLock) Release(ctx context.Context) error {
-	// トランザクションスコープのロックは自動解放されるため、何もしない
+	defer l.conn.Release()
+
+	if _, err := l.conn.Exec(ctx, "SELECT pg_advisory_unlock($1)", l.lockID); err != nil {
+		// 接続を閉じるとセッションのロックも解放される
+		_ = l.conn.Conn().Close(ctx)
+		return fmt.Errorf("failed to release advisory lock: %w", err)
+	}
 	return nil
 }
+
+// WithAdvisoryLock はアドバイザリロックを取得した状態で fn を実行します
+// 他のプロセスが同じロックIDを保持している場合は解放されるまで待機します
+// fn の書き込みはロックとは別の接続でファイルごとにコミットされるため、ロックの保持中もトランザクションは開いたままになりません
+func (db *Database) WithAdvisoryLock(ctx context.Context, lockID int64, fn func(ctx context.Context) error) error {
+	lock, err := Acquire(ctx, db.Pool, lockID)
+	if err != nil {
+		return err
+	}
+
+	fnErr := fn(ctx)
+	// キャンセルされた場合もロックは確実に解放する
+	if err := lock.Release(context.WithoutCancel(ctx)); err != nil && fnErr == nil {
+		return err
+	}
+	return fnErr
+}
@@ -3,13 +3,21 @@ package database
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	pgxvec "github.com/pgvector/pgvector-go/pgx"
 )
 
 // Database はデータベース接続プールを保持します
 type Database struct {
 	Pool *pgxpool.Pool
+
+	// Replica はリードレプリカの接続プール（WithReplica を指定しない場合は nil）
+	Replica *pgxpool.Pool
+
+	reader *ReadRouter
 }
 
 // ConnectionParams はデータベース接続パラメータ
@@ -20,10 +28,59 @@ type ConnectionParams struct {
 	Password string
 	DBName   string
 	SSLMode  string
+
+	// 接続プールの設定（0 の場合は pgx の既定値）
+	MaxConns        int32         // 最大接続数（既定は max(4, CPU数)）
+	MinConns        int32         // 維持する最小接続数
+	MaxConnIdleTime time.Duration // アイドルの接続を閉じるまでの時間（既定は30分）
+	MaxConnLifetime time.Duration // 接続を作り直すまでの時間（既定は1時間）
+}
+
+type options struct {
+	replica       *ConnectionParams
+	replicaMaxLag time.Duration
+}
+
+// Option は New のオプション
+type Option func(*options)
+
+// WithReplica は読み取り専用のクエリ（Reader）の接続先にリードレプリカを追加します
+// レプリカの遅延が maxLag を超えている場合や、最後にインデックス化したスナップショットがまだ反映されていない場合はプライマリを使用します
+func WithReplica(params ConnectionParams, maxLag time.Duration) Option {
+	return func(opts *options) {
+		opts.replica = &params
+		opts.replicaMaxLag = maxLag
+	}
 }
 
 // New は新しいデータベース接続を作成します
-func New(ctx context.Context, params ConnectionParams) (*Database, error) {
+func New(ctx context.Context, params ConnectionParams, opts ...Option) (*Database, error) {
+	options := options{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	pool, err := connect(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	db := &Database{Pool: pool}
+
+	if options.replica != nil {
+		replica, err := connect(ctx, *options.replica)
+		if err != nil {
+			pool.Close()
+			return nil, fmt.Errorf("failed to connect to replica: %w", err)
+		}
+		db.Replica = replica
+	}
+	db.reader = newReadRouter(db.Pool, db.Replica, options.replicaMaxLag)
+
+	return db, nil
+}
+
+// connect は接続プールを作成し、接続を確認します
+func connect(ctx context.Context, params ConnectionParams) (*pgxpool.Pool, error) {
 	connString := fmt.Sprintf(
 		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
 		params.Host,
@@ -33,7 +90,25 @@ func New(ctx context.Context, params ConnectionParams) (*Database, error) {
 		params.DBName,
 		params.SSLMode,
 	)
-	pool, err := pgxpool.New(ctx, connString)
+	poolConfig, err := pgxpool.ParseConfig(connString)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse connection string: %w", err)
+	}
+	poolConfig.AfterConnect = registerVectorTypes
+	if params.MaxConns > 0 {
+		poolConfig.MaxConns = params.MaxConns
+	}
+	if params.MinConns > 0 {
+		poolConfig.MinConns = params.MinConns
+	}
+	if params.MaxConnIdleTime > 0 {
+		poolConfig.MaxConnIdleTime = params.MaxConnIdleTime
+	}
+	if params.MaxConnLifetime > 0 {
+		poolConfig.MaxConnLifetime = params.MaxConnLifetime
+	}
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create connection pool: %w", err)
 	}
@@ -44,10 +119,58 @@ func New(ctx context.Context, params ConnectionParams) (*Database, error) {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	return &Database{Pool: pool}, nil
+	return pool, nil
+}
+
+// Reader は検索・質問応答などの読み取り専用のクエリの接続先を返します
+// レプリカを設定していない場合は常にプライマリに接続します
+func (db *Database) Reader() *ReadRouter {
+	if db.reader == nil {
+		db.reader = newReadRouter(db.Pool, nil, 0)
+	}
+	return db.reader
+}
+
+// registerVectorTypes は pgvector の型をコネクションに登録します
+// COPY はバイナリ形式で送信するため、Embedding を COPY で保存するには vector 型のコーデックが必要になる
+// 拡張機能がインストールされていない場合は登録しない（doctor で検出する）
+func registerVectorTypes(ctx context.Context, conn *pgx.Conn) error {
+	var installed bool
+	if err := conn.QueryRow(ctx, "SELECT to_regtype('vector') IS NOT NULL").Scan(&installed); err != nil {
+		return fmt.Errorf("failed to check vector type: %w", err)
+	}
+	if !installed {
+		return nil
+	}
+	if err := pgxvec.RegisterTypes(ctx, conn); err != nil {
+		return fmt.Errorf("failed to register vector types: %w", err)
+	}
+	return nil
 }
 
 // Close はデータベース接続を閉じます
 func (db *Database) Close() {
 	db.Pool.Close()
+	if db.Replica != nil {
+		db.Replica.Close()
+	}
+}
+
+// VectorDimension は pgvector のカラムに定義された次元数を返します
+// 次元が定義されていない場合は 0 を返します
+func (db *Database) VectorDimension(ctx context.Context, table, column string) (int, error) {
+	var typmod int
+	err := db.Pool.QueryRow(ctx,
+		"SELECT atttypmod FROM pg_attribute WHERE attrelid = $1::regclass AND attname = $2 AND NOT attisdropped",
+		table, column,
+	).Scan(&typmod)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get vector dimension of %s.%s: %w", table, column, err)
+	}
+
+	// pgvector は atttypmod に次元数をそのまま格納する（未指定の場合は -1）
+	if typmod < 0 {
+		return 0, nil
+	}
+	return typmod, nil
 }
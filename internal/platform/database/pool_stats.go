@@ -0,0 +1,50 @@
+package database
+
+import "github.com/jackc/pgx/v5/pgxpool"
+
+// PoolStats は接続プールの統計
+type PoolStats struct {
+	Name                 string `json:"name"` // primary / replica
+	MaxConns             int32  `json:"maxConns"`
+	TotalConns           int32  `json:"totalConns"`
+	AcquiredConns        int32  `json:"acquiredConns"`
+	IdleConns            int32  `json:"idleConns"`
+	ConstructingConns    int32  `json:"constructingConns"`
+	AcquireCount         int64  `json:"acquireCount"`
+	EmptyAcquireCount    int64  `json:"emptyAcquireCount"`    // 空きの接続がなく待機した取得の回数
+	CanceledAcquireCount int64  `json:"canceledAcquireCount"` // 待機中にキャンセルされた取得の回数
+	AcquireDurationMs    int64  `json:"acquireDurationMs"`    // 取得にかかった時間の累計
+	EmptyAcquireWaitMs   int64  `json:"emptyAcquireWaitMs"`   // 空きの接続を待った時間の累計
+	NewConnsCount        int64  `json:"newConnsCount"`
+	MaxLifetimeDestroyed int64  `json:"maxLifetimeDestroyed"` // MaxConnLifetime を超えて閉じた接続数
+	MaxIdleDestroyed     int64  `json:"maxIdleDestroyed"`     // MaxConnIdleTime を超えて閉じた接続数
+}
+
+// PoolStats はプライマリと（設定した場合は）リードレプリカの接続プールの統計を返します
+func (db *Database) PoolStats() []PoolStats {
+	stats := []PoolStats{poolStats("primary", db.Pool)}
+	if db.Replica != nil {
+		stats = append(stats, poolStats("replica", db.Replica))
+	}
+	return stats
+}
+
+func poolStats(name string, pool *pgxpool.Pool) PoolStats {
+	stat := pool.Stat()
+	return PoolStats{
+		Name:                 name,
+		MaxConns:             stat.MaxConns(),
+		TotalConns:           stat.TotalConns(),
+		AcquiredConns:        stat.AcquiredConns(),
+		IdleConns:            stat.IdleConns(),
+		ConstructingConns:    stat.ConstructingConns(),
+		AcquireCount:         stat.AcquireCount(),
+		EmptyAcquireCount:    stat.EmptyAcquireCount(),
+		CanceledAcquireCount: stat.CanceledAcquireCount(),
+		AcquireDurationMs:    stat.AcquireDuration().Milliseconds(),
+		EmptyAcquireWaitMs:   stat.EmptyAcquireWaitTime().Milliseconds(),
+		NewConnsCount:        stat.NewConnsCount(),
+		MaxLifetimeDestroyed: stat.MaxLifetimeDestroyCount(),
+		MaxIdleDestroyed:     stat.MaxIdleDestroyCount(),
+	}
+}
@@ -0,0 +1,135 @@
+package database
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// replicaCheckInterval はレプリカの遅延を確認し直す間隔
+// クエリごとに確認しないよう、確認した結果をこの間隔の間使い回す
+const replicaCheckInterval = 5 * time.Second
+
+// replicaCheckTimeout はレプリカの遅延の確認の上限時間
+// 確認はクエリの呼び出し元のコンテキストとは独立して行い、レプリカが応答しない場合もこの時間で打ち切る
+const replicaCheckTimeout = 2 * time.Second
+
+// ReadRouter は読み取り専用のクエリをリードレプリカとプライマリに振り分けます（sqlc の DBTX を満たします）
+// 書き込みを含み得る Exec・CopyFrom・SendBatch は常にプライマリに送ります
+type ReadRouter struct {
+	primary *pgxpool.Pool
+	replica *pgxpool.Pool
+	maxLag  time.Duration
+
+	// check はレプリカの状態を確認する（テストで差し替える）
+	check func(ctx context.Context) (replicaStatus, error)
+
+	checking   atomic.Bool  // 確認中か（確認は1つの呼び出し元だけが行う）
+	checkedAt  atomic.Int64 // 最後に確認した日時（UnixNano）
+	useReplica atomic.Bool  // 最後に確認した結果、レプリカに振り分けるか
+}
+
+// replicaStatus はレプリカの遅延と、最後にインデックス化したスナップショットの反映状況
+type replicaStatus struct {
+	Lag                  time.Duration // レプリカの再生の遅延
+	PrimaryLatestIndexed *time.Time    // プライマリで最後にインデックス化したスナップショットの日時
+	ReplicaLatestIndexed *time.Time    // レプリカで最後にインデックス化したスナップショットの日時
+}
+
+func newReadRouter(primary, replica *pgxpool.Pool, maxLag time.Duration) *ReadRouter {
+	r := &ReadRouter{primary: primary, replica: replica, maxLag: maxLag}
+	r.check = r.checkReplica
+	return r
+}
+
+// Exec はクエリをプライマリで実行します
+func (r *ReadRouter) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	return r.primary.Exec(ctx, sql, args...)
+}
+
+// Query はクエリを振り分け先で実行します
+func (r *ReadRouter) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	return r.pool(ctx).Query(ctx, sql, args...)
+}
+
+// QueryRow はクエリを振り分け先で実行します
+func (r *ReadRouter) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	return r.pool(ctx).QueryRow(ctx, sql, args...)
+}
+
+// CopyFrom は COPY をプライマリで実行します
+func (r *ReadRouter) CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error) {
+	return r.primary.CopyFrom(ctx, tableName, columnNames, rowSrc)
+}
+
+// SendBatch はバッチをプライマリで実行します
+func (r *ReadRouter) SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults {
+	return r.primary.SendBatch(ctx, b)
+}
+
+// pool はクエリの振り分け先を返します
+// 確認の間隔が過ぎている場合は1つの呼び出し元だけがレプリカの状態を確認し直し、他の呼び出し元は前回の確認結果（未確認の場合はプライマリ）を使用します
+func (r *ReadRouter) pool(ctx context.Context) *pgxpool.Pool {
+	if r.replica == nil {
+		return r.primary
+	}
+
+	if time.Since(time.Unix(0, r.checkedAt.Load())) >= replicaCheckInterval && r.checking.CompareAndSwap(false, true) {
+		r.refresh(ctx)
+	}
+	if r.useReplica.Load() {
+		return r.replica
+	}
+	return r.primary
+}
+
+// refresh はレプリカの状態を確認し、振り分け先を更新します
+// 呼び出し元のキャンセルで確認が中断されないよう、呼び出し元とは独立したタイムアウトで確認します
+func (r *ReadRouter) refresh(ctx context.Context) {
+	defer r.checking.Store(false)
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), replicaCheckTimeout)
+	defer cancel()
+	status, err := r.check(ctx)
+	// 確認に失敗した場合はレプリカに接続できない可能性があるため、次の確認までプライマリを使用する
+	r.useReplica.Store(err == nil && replicaUsable(status, r.maxLag))
+	r.checkedAt.Store(time.Now().UnixNano())
+}
+
+// checkReplica はレプリカの遅延と、プライマリ・レプリカで最後にインデックス化したスナップショットの日時を取得します
+func (r *ReadRouter) checkReplica(ctx context.Context) (replicaStatus, error) {
+	var status replicaStatus
+	var lagSeconds float64
+	// 受信した WAL をすべて再生済みの場合は、プライマリで更新がないだけのため遅延なしとする
+	err := r.replica.QueryRow(ctx, `
+		SELECT
+			COALESCE(CASE WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+				ELSE EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()) END, 0)::float8,
+			(SELECT max(indexed_at) FROM source_snapshots)`,
+	).Scan(&lagSeconds, &status.ReplicaLatestIndexed)
+	if err != nil {
+		return status, err
+	}
+	status.Lag = time.Duration(lagSeconds * float64(time.Second))
+
+	if err := r.primary.QueryRow(ctx, "SELECT max(indexed_at) FROM source_snapshots").Scan(&status.PrimaryLatestIndexed); err != nil {
+		return status, err
+	}
+	return status, nil
+}
+
+// replicaUsable はレプリカに読み取りを振り分けられるかを判定します
+// 遅延が maxLag を超えている場合（maxLag が 0 の場合は判定しない）と、プライマリで最後にインデックス化したスナップショットがレプリカにまだ反映されていない場合は振り分けない
+func replicaUsable(status replicaStatus, maxLag time.Duration) bool {
+	if maxLag > 0 && status.Lag > maxLag {
+		return false
+	}
+	if status.PrimaryLatestIndexed == nil {
+		return true
+	}
+	return status.ReplicaLatestIndexed != nil && !status.ReplicaLatestIndexed.Before(*status.PrimaryLatestIndexed)
+}
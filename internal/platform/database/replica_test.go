@@ -0,0 +1,58 @@
+package database
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplicaUsable(t *testing.T) {
+	indexed := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	earlier := indexed.Add(-time.Hour)
+
+	assert.True(t, replicaUsable(replicaStatus{Lag: time.Second, PrimaryLatestIndexed: &indexed, ReplicaLatestIndexed: &indexed}, 30*time.Second))
+	assert.False(t, replicaUsable(replicaStatus{Lag: time.Minute, PrimaryLatestIndexed: &indexed, ReplicaLatestIndexed: &indexed}, 30*time.Second), "遅延が上限を超えている")
+	assert.True(t, replicaUsable(replicaStatus{Lag: time.Minute, PrimaryLatestIndexed: &indexed, ReplicaLatestIndexed: &indexed}, 0), "上限が 0 の場合は遅延を判定しない")
+
+	// 最後にインデックス化したスナップショットがレプリカに反映されていない
+	assert.False(t, replicaUsable(replicaStatus{PrimaryLatestIndexed: &indexed, ReplicaLatestIndexed: &earlier}, 30*time.Second))
+	assert.False(t, replicaUsable(replicaStatus{PrimaryLatestIndexed: &indexed}, 30*time.Second))
+
+	// インデックス化したスナップショットがない
+	assert.True(t, replicaUsable(replicaStatus{}, 30*time.Second))
+}
+
+func TestReadRouterPool(t *testing.T) {
+	primary, replica := &pgxpool.Pool{}, &pgxpool.Pool{}
+	r := newReadRouter(primary, replica, 30*time.Second)
+
+	var calls atomic.Int32
+	started, release := make(chan struct{}), make(chan struct{})
+	r.check = func(ctx context.Context) (replicaStatus, error) {
+		calls.Add(1)
+		close(started)
+		<-release
+		assert.NoError(t, ctx.Err(), "呼び出し元のキャンセルで確認を中断しない")
+		_, ok := ctx.Deadline()
+		assert.True(t, ok, "確認にはタイムアウトを設定する")
+		return replicaStatus{Lag: time.Second}, nil
+	}
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	done := make(chan *pgxpool.Pool)
+	go func() { done <- r.pool(canceled) }()
+	<-started
+
+	// 確認中の他の呼び出し元は確認を待たずに前回の確認結果（未確認の場合はプライマリ）を使用する
+	assert.Same(t, primary, r.pool(context.Background()))
+
+	close(release)
+	assert.Same(t, replica, <-done)
+	assert.Same(t, replica, r.pool(context.Background()), "確認の間隔の間は確認結果を使い回す")
+	assert.Equal(t, int32(1), calls.Load())
+}
@@ -0,0 +1,94 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// SchemaMarker はマイグレーションを適用したかを判定するためのテーブル・カラム
+// スキーマは schema/ の SQL を直接適用するためバージョンを記録するテーブルがなく、マイグレーションで追加したオブジェクトの有無で判定する
+type SchemaMarker struct {
+	Version int    // マイグレーションの番号（schema/migrations/<version>_*.up.sql）
+	Name    string // マイグレーションの名前
+	Table   string
+	Column  string // 空の場合はテーブルの有無で判定する
+}
+
+// SchemaMarkers は直近のマイグレーションの判定に使うテーブル・カラム（番号の昇順、マイグレーションを追加したら末尾に追加する）
+var SchemaMarkers = []SchemaMarker{
+	{Version: 36, Name: "add_product_summaries", Table: "product_summaries"},
+	{Version: 37, Name: "add_test_coverage", Table: "file_test_coverage"},
+	{Version: 38, Name: "add_index_runs", Table: "index_runs"},
+	{Version: 39, Name: "add_snapshot_failure", Table: "source_snapshots", Column: "failed_at"},
+	{Version: 40, Name: "add_snapshot_status", Table: "source_snapshots", Column: "status"},
+	{Version: 41, Name: "add_file_failures", Table: "file_failures"},
+	{Version: 42, Name: "add_file_blobs", Table: "file_blobs"},
+	{Version: 43, Name: "add_chunk_chunker_version", Table: "chunks", Column: "chunker_version"},
+	{Version: 44, Name: "add_wiki_publication_language", Table: "wiki_publications", Column: "language"},
+	{Version: 45, Name: "add_release_notes", Table: "release_notes"},
+	{Version: 46, Name: "add_external_dependencies", Table: "external_dependencies"},
+	{Version: 47, Name: "add_license_findings", Table: "license_findings"},
+	{Version: 48, Name: "add_monorepo_services", Table: "monorepo_services"},
+}
+
+// LatestSchemaVersion はアプリケーションが前提とするマイグレーションの番号を返します
+func LatestSchemaVersion() int {
+	return SchemaMarkers[len(SchemaMarkers)-1].Version
+}
+
+// MissingSchemaMarkers は適用されていないマイグレーションを番号の昇順で返します
+func (db *Database) MissingSchemaMarkers(ctx context.Context) ([]SchemaMarker, error) {
+	var missing []SchemaMarker
+	for _, m := range SchemaMarkers {
+		exists, err := db.schemaObjectExists(ctx, m.Table, m.Column)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			missing = append(missing, m)
+		}
+	}
+	return missing, nil
+}
+
+func (db *Database) schemaObjectExists(ctx context.Context, table, column string) (bool, error) {
+	var exists bool
+	var err error
+	if column == "" {
+		err = db.Pool.QueryRow(ctx, "SELECT to_regclass($1) IS NOT NULL", table).Scan(&exists)
+	} else {
+		err = db.Pool.QueryRow(ctx,
+			"SELECT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_schema = current_schema() AND table_name = $1 AND column_name = $2)",
+			table, column,
+		).Scan(&exists)
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to check schema object %s.%s: %w", table, column, err)
+	}
+	return exists, nil
+}
+
+// ExtensionVersion はインストールされている拡張機能のバージョンを返します（インストールされていない場合は空）
+func (db *Database) ExtensionVersion(ctx context.Context, name string) (string, error) {
+	var version string
+	err := db.Pool.QueryRow(ctx, "SELECT extversion FROM pg_extension WHERE extname = $1", name).Scan(&version)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get extension version of %s: %w", name, err)
+	}
+	return version, nil
+}
+
+// ServerVersion は PostgreSQL のバージョンを返します
+func (db *Database) ServerVersion(ctx context.Context) (string, error) {
+	var version string
+	if err := db.Pool.QueryRow(ctx, "SHOW server_version").Scan(&version); err != nil {
+		return "", fmt.Errorf("failed to get server version: %w", err)
+	}
+	return version, nil
+}
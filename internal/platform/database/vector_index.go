@@ -0,0 +1,334 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// VectorIndexMethod は pgvector のインデックス方式
+type VectorIndexMethod string
+
+const (
+	VectorIndexHNSW    VectorIndexMethod = "hnsw"
+	VectorIndexIVFFlat VectorIndexMethod = "ivfflat"
+)
+
+// VectorIndexTarget はベクトルインデックスの対象カラム
+type VectorIndexTarget struct {
+	Table     string
+	Column    string
+	IndexName string
+}
+
+// VectorIndexTargets はベクトル検索に使用するテーブルとインデックス名の一覧
+// インデックス名は schema.sql の定義と一致させる
+var VectorIndexTargets = map[string]VectorIndexTarget{
+	"embeddings":         {Table: "embeddings", Column: "vector", IndexName: "idx_embeddings_vector_cosine"},
+	"summary_embeddings": {Table: "summary_embeddings", Column: "vector", IndexName: "idx_summary_embeddings_vector"},
+}
+
+// VectorIndexParams はインデックス作成パラメータ
+type VectorIndexParams struct {
+	Method         VectorIndexMethod
+	M              int  // HNSW: ノードあたりの接続数
+	EfConstruction int  // HNSW: 構築時の候補リストサイズ
+	Lists          int  // IVFFlat: クラスタ数（0 の場合は行数から自動決定）
+	Concurrently   bool // CREATE INDEX CONCURRENTLY を使用する
+
+	// Storage はカラムの格納型（演算子クラスの選択に使用。空の場合は vector）
+	Storage VectorStorage
+}
+
+// VectorIndexBenchmarkParams は再現率・レイテンシ計測のパラメータ
+type VectorIndexBenchmarkParams struct {
+	Samples  int // サンプルクエリ数
+	TopK     int // 上位何件で再現率を計測するか
+	EfSearch int // HNSW: 検索時の候補リストサイズ（0 の場合はサーバ既定値）
+	Probes   int // IVFFlat: 検索するクラスタ数（0 の場合はサーバ既定値）
+}
+
+// VectorIndexBenchmark は計測結果
+type VectorIndexBenchmark struct {
+	Samples       int
+	TopK          int
+	Recall        float64       // 厳密検索に対する平均再現率
+	AvgLatency    time.Duration // インデックス検索の平均レイテンシ
+	P95Latency    time.Duration // インデックス検索の95パーセンタイル
+	ExactLatency  time.Duration // 厳密検索（シーケンシャルスキャン）の平均レイテンシ
+	UsesIndexScan bool          // 実行計画でインデックスが使われたか
+}
+
+// BuildVectorIndexSQL はインデックス作成用のDDLを組み立てます
+func BuildVectorIndexSQL(target VectorIndexTarget, params VectorIndexParams) (string, error) {
+	var with string
+	switch params.Method {
+	case VectorIndexHNSW:
+		if params.M <= 0 || params.EfConstruction <= 0 {
+			return "", fmt.Errorf("hnsw requires positive m and ef_construction")
+		}
+		if params.EfConstruction < 2*params.M {
+			return "", fmt.Errorf("ef_construction (%d) must be at least 2 * m (%d)", params.EfConstruction, 2*params.M)
+		}
+		with = fmt.Sprintf("m = %d, ef_construction = %d", params.M, params.EfConstruction)
+	case VectorIndexIVFFlat:
+		if params.Lists <= 0 {
+			return "", fmt.Errorf("ivfflat requires positive lists")
+		}
+		with = fmt.Sprintf("lists = %d", params.Lists)
+	default:
+		return "", fmt.Errorf("unsupported vector index method %q (hnsw or ivfflat)", params.Method)
+	}
+
+	concurrently := ""
+	if params.Concurrently {
+		concurrently = "CONCURRENTLY "
+	}
+
+	return fmt.Sprintf("CREATE INDEX %s%s ON %s USING %s (%s %s) WITH (%s)",
+		concurrently,
+		pgx.Identifier{target.IndexName}.Sanitize(),
+		pgx.Identifier{target.Table}.Sanitize(),
+		params.Method,
+		pgx.Identifier{target.Column}.Sanitize(),
+		params.Storage.OpClass(),
+		with,
+	), nil
+}
+
+// RecommendedIVFFlatLists は pgvector 推奨の lists 値を返します
+// 100万行以下は rows/1000、それ以上は sqrt(rows)
+func RecommendedIVFFlatLists(rows int64) int {
+	if rows <= 0 {
+		return 1
+	}
+	if rows <= 1_000_000 {
+		return max(1, int(rows/1000))
+	}
+	return int(math.Sqrt(float64(rows)))
+}
+
+// CountRows はテーブルの行数を返します
+func (db *Database) CountRows(ctx context.Context, table string) (int64, error) {
+	var count int64
+	query := "SELECT COUNT(*) FROM " + pgx.Identifier{table}.Sanitize()
+	if err := db.Pool.QueryRow(ctx, query).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count rows of %s: %w", table, err)
+	}
+	return count, nil
+}
+
+// RebuildVectorIndex は既存のベクトルインデックスを削除し、指定パラメータで作り直します
+// 演算子クラスはカラムの現在の格納型に合わせます
+func (db *Database) RebuildVectorIndex(ctx context.Context, target VectorIndexTarget, params VectorIndexParams) error {
+	storage, err := db.VectorStorageOf(ctx, target.Table, target.Column)
+	if err != nil {
+		return err
+	}
+	params.Storage = storage
+
+	if params.Method == VectorIndexIVFFlat && params.Lists <= 0 {
+		rows, err := db.CountRows(ctx, target.Table)
+		if err != nil {
+			return err
+		}
+		params.Lists = RecommendedIVFFlatLists(rows)
+	}
+
+	createSQL, err := BuildVectorIndexSQL(target, params)
+	if err != nil {
+		return err
+	}
+
+	concurrently := ""
+	if params.Concurrently {
+		concurrently = "CONCURRENTLY "
+	}
+	dropSQL := fmt.Sprintf("DROP INDEX %sIF EXISTS %s", concurrently, pgx.Identifier{target.IndexName}.Sanitize())
+
+	if _, err := db.Pool.Exec(ctx, dropSQL); err != nil {
+		return fmt.Errorf("failed to drop index %s: %w", target.IndexName, err)
+	}
+	if _, err := db.Pool.Exec(ctx, createSQL); err != nil {
+		return fmt.Errorf("failed to create index %s: %w", target.IndexName, err)
+	}
+	if _, err := db.Pool.Exec(ctx, "ANALYZE "+pgx.Identifier{target.Table}.Sanitize()); err != nil {
+		return fmt.Errorf("failed to analyze %s: %w", target.Table, err)
+	}
+	return nil
+}
+
+// BenchmarkVectorIndex はテーブル内のベクトルをサンプルクエリとして、
+// インデックス検索の再現率とレイテンシを厳密検索と比較して計測します
+func (db *Database) BenchmarkVectorIndex(ctx context.Context, target VectorIndexTarget, params VectorIndexBenchmarkParams) (*VectorIndexBenchmark, error) {
+	if params.Samples <= 0 {
+		params.Samples = 20
+	}
+	if params.TopK <= 0 {
+		params.TopK = 10
+	}
+
+	storage, err := db.VectorStorageOf(ctx, target.Table, target.Column)
+	if err != nil {
+		return nil, err
+	}
+
+	table := pgx.Identifier{target.Table}.Sanitize()
+	column := pgx.Identifier{target.Column}.Sanitize()
+
+	rows, err := db.Pool.Query(ctx,
+		fmt.Sprintf("SELECT %s::text FROM %s ORDER BY random() LIMIT $1", column, table),
+		params.Samples,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sample query vectors: %w", err)
+	}
+	queries, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("failed to sample query vectors: %w", err)
+	}
+	if len(queries) == 0 {
+		return nil, fmt.Errorf("table %s has no vectors to benchmark", target.Table)
+	}
+
+	// クエリベクトルをカラムと同じ型にキャストしないとインデックスが使われない
+	searchSQL := fmt.Sprintf("SELECT ctid::text FROM %s ORDER BY %s <=> $1::%s LIMIT $2", table, column, storage)
+
+	result := &VectorIndexBenchmark{Samples: len(queries), TopK: params.TopK}
+	latencies := make([]time.Duration, 0, len(queries))
+	var exactTotal time.Duration
+	var recallTotal float64
+
+	for _, q := range queries {
+		exact, elapsed, err := db.runVectorQuery(ctx, searchSQL, q, params, true)
+		if err != nil {
+			return nil, err
+		}
+		exactTotal += elapsed
+
+		approx, elapsed, err := db.runVectorQuery(ctx, searchSQL, q, params, false)
+		if err != nil {
+			return nil, err
+		}
+		latencies = append(latencies, elapsed)
+		recallTotal += Recall(exact, approx)
+	}
+
+	plan, err := db.explainVectorQuery(ctx, searchSQL, queries[0], params)
+	if err != nil {
+		return nil, err
+	}
+	result.UsesIndexScan = strings.Contains(plan, target.IndexName)
+
+	result.Recall = recallTotal / float64(len(queries))
+	result.ExactLatency = exactTotal / time.Duration(len(queries))
+	result.AvgLatency, result.P95Latency = latencyStats(latencies)
+	return result, nil
+}
+
+// runVectorQuery は1件の近傍検索を実行し、結果の行IDと所要時間を返します
+// exact が true の場合はインデックスを無効化して厳密検索を行います
+func (db *Database) runVectorQuery(ctx context.Context, query, vector string, params VectorIndexBenchmarkParams, exact bool) ([]string, time.Duration, error) {
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := applySearchSettings(ctx, tx, params, exact); err != nil {
+		return nil, 0, err
+	}
+
+	start := time.Now()
+	rows, err := tx.Query(ctx, query, vector, params.TopK)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to run vector query: %w", err)
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to run vector query: %w", err)
+	}
+	return ids, time.Since(start), nil
+}
+
+// explainVectorQuery はインデックス検索時の実行計画を返します
+func (db *Database) explainVectorQuery(ctx context.Context, query, vector string, params VectorIndexBenchmarkParams) (string, error) {
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := applySearchSettings(ctx, tx, params, false); err != nil {
+		return "", err
+	}
+
+	rows, err := tx.Query(ctx, "EXPLAIN "+query, vector, params.TopK)
+	if err != nil {
+		return "", fmt.Errorf("failed to explain vector query: %w", err)
+	}
+	lines, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return "", fmt.Errorf("failed to explain vector query: %w", err)
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+func applySearchSettings(ctx context.Context, tx pgx.Tx, params VectorIndexBenchmarkParams, exact bool) error {
+	var settings []string
+	if exact {
+		settings = append(settings, "SET LOCAL enable_indexscan = off")
+	} else {
+		if params.EfSearch > 0 {
+			settings = append(settings, fmt.Sprintf("SET LOCAL hnsw.ef_search = %d", params.EfSearch))
+		}
+		if params.Probes > 0 {
+			settings = append(settings, fmt.Sprintf("SET LOCAL ivfflat.probes = %d", params.Probes))
+		}
+	}
+	for _, stmt := range settings {
+		if _, err := tx.Exec(ctx, stmt); err != nil {
+			return fmt.Errorf("failed to apply %q: %w", stmt, err)
+		}
+	}
+	return nil
+}
+
+// Recall は厳密検索の結果に対する近似検索結果の再現率を返します
+func Recall(exact, approx []string) float64 {
+	if len(exact) == 0 {
+		return 1
+	}
+	found := make(map[string]struct{}, len(approx))
+	for _, id := range approx {
+		found[id] = struct{}{}
+	}
+	hits := 0
+	for _, id := range exact {
+		if _, ok := found[id]; ok {
+			hits++
+		}
+	}
+	return float64(hits) / float64(len(exact))
+}
+
+// latencyStats は平均と95パーセンタイルを返します
+func latencyStats(latencies []time.Duration) (avg, p95 time.Duration) {
+	if len(latencies) == 0 {
+		return 0, 0
+	}
+	sorted := slices.Clone(latencies)
+	slices.Sort(sorted)
+
+	var total time.Duration
+	for _, l := range sorted {
+		total += l
+	}
+	idx := int(math.Ceil(0.95*float64(len(sorted)))) - 1
+	return total / time.Duration(len(sorted)), sorted[max(idx, 0)]
+}
@@ -0,0 +1,46 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildVectorIndexSQL(t *testing.T) {
+	target := VectorIndexTargets["embeddings"]
+
+	sql, err := BuildVectorIndexSQL(target, VectorIndexParams{Method: VectorIndexHNSW, M: 16, EfConstruction: 64})
+	require.NoError(t, err)
+	assert.Equal(t, `CREATE INDEX "idx_embeddings_vector_cosine" ON "embeddings" USING hnsw ("vector" vector_cosine_ops) WITH (m = 16, ef_construction = 64)`, sql)
+
+	sql, err = BuildVectorIndexSQL(target, VectorIndexParams{Method: VectorIndexIVFFlat, Lists: 200, Concurrently: true})
+	require.NoError(t, err)
+	assert.Equal(t, `CREATE INDEX CONCURRENTLY "idx_embeddings_vector_cosine" ON "embeddings" USING ivfflat ("vector" vector_cosine_ops) WITH (lists = 200)`, sql)
+
+	sql, err = BuildVectorIndexSQL(target, VectorIndexParams{Method: VectorIndexHNSW, M: 16, EfConstruction: 64, Storage: VectorStorageHalfvec})
+	require.NoError(t, err)
+	assert.Equal(t, `CREATE INDEX "idx_embeddings_vector_cosine" ON "embeddings" USING hnsw ("vector" halfvec_cosine_ops) WITH (m = 16, ef_construction = 64)`, sql)
+
+	_, err = BuildVectorIndexSQL(target, VectorIndexParams{Method: VectorIndexHNSW, M: 32, EfConstruction: 40})
+	assert.Error(t, err)
+
+	_, err = BuildVectorIndexSQL(target, VectorIndexParams{Method: "flat"})
+	assert.Error(t, err)
+}
+
+func TestRecommendedIVFFlatLists(t *testing.T) {
+	assert.Equal(t, 1, RecommendedIVFFlatLists(500))
+	assert.Equal(t, 100, RecommendedIVFFlatLists(100_000))
+	assert.Equal(t, 2000, RecommendedIVFFlatLists(4_000_000))
+}
+
+func TestRecallAndLatencyStats(t *testing.T) {
+	assert.InDelta(t, 0.5, Recall([]string{"a", "b", "c", "d"}, []string{"a", "c", "x", "y"}), 1e-9)
+	assert.Equal(t, 1.0, Recall(nil, []string{"a"}))
+
+	avg, p95 := latencyStats([]time.Duration{4, 1, 3, 2})
+	assert.Equal(t, time.Duration(2), avg)
+	assert.Equal(t, time.Duration(4), p95)
+}
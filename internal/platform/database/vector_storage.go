@@ -0,0 +1,136 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// VectorStorage は pgvector のベクトルの格納型
+type VectorStorage string
+
+const (
+	// VectorStorageVector は単精度浮動小数点（1次元あたり4バイト）で格納する
+	VectorStorageVector VectorStorage = "vector"
+	// VectorStorageHalfvec は半精度浮動小数点（1次元あたり2バイト）で格納する
+	// 格納サイズとインデックスが半分になり、再現率の低下はわずか
+	VectorStorageHalfvec VectorStorage = "halfvec"
+)
+
+// ParseVectorStorage は格納型の名前を解析します（空文字の場合は vector）
+func ParseVectorStorage(name string) (VectorStorage, error) {
+	switch VectorStorage(name) {
+	case "", VectorStorageVector:
+		return VectorStorageVector, nil
+	case VectorStorageHalfvec:
+		return VectorStorageHalfvec, nil
+	default:
+		return "", fmt.Errorf("unsupported vector storage %q (vector or halfvec)", name)
+	}
+}
+
+// OpClass はコサイン距離のインデックスに使用する演算子クラスを返します
+func (s VectorStorage) OpClass() string {
+	if s == VectorStorageHalfvec {
+		return "halfvec_cosine_ops"
+	}
+	return "vector_cosine_ops"
+}
+
+// MaxIndexDimension は HNSW/IVFFlat インデックスを作成できる最大次元数を返します
+func (s VectorStorage) MaxIndexDimension() int {
+	if s == VectorStorageHalfvec {
+		return 4000
+	}
+	return 2000
+}
+
+// VectorStorageOf はカラムの格納型を返します
+func (db *Database) VectorStorageOf(ctx context.Context, table, column string) (VectorStorage, error) {
+	var typeName string
+	err := db.Pool.QueryRow(ctx,
+		"SELECT t.typname FROM pg_attribute a JOIN pg_type t ON t.oid = a.atttypid WHERE a.attrelid = $1::regclass AND a.attname = $2 AND NOT a.attisdropped",
+		table, column,
+	).Scan(&typeName)
+	if err != nil {
+		return "", fmt.Errorf("failed to get vector storage of %s.%s: %w", table, column, err)
+	}
+
+	storage, err := ParseVectorStorage(typeName)
+	if err != nil {
+		return "", fmt.Errorf("column %s.%s is not a vector column: %w", table, column, err)
+	}
+	return storage, nil
+}
+
+// BuildConvertVectorStorageSQL はカラムの格納型を変換するDDLを組み立てます
+func BuildConvertVectorStorageSQL(target VectorIndexTarget, storage VectorStorage, dimension int) (string, error) {
+	if dimension <= 0 {
+		return "", fmt.Errorf("column %s.%s has no dimension", target.Table, target.Column)
+	}
+	column := pgx.Identifier{target.Column}.Sanitize()
+	return fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s TYPE %s(%d) USING %s::%s(%d)",
+		pgx.Identifier{target.Table}.Sanitize(),
+		column,
+		storage, dimension,
+		column,
+		storage, dimension,
+	), nil
+}
+
+// ConvertVectorStorage はカラムの格納型を変換し、ベクトルインデックスを変換後の型の演算子クラスで作り直します
+// 変換中はテーブルへの書き込みがブロックされます
+func (db *Database) ConvertVectorStorage(ctx context.Context, target VectorIndexTarget, storage VectorStorage, params VectorIndexParams) error {
+	dimension, err := db.VectorDimension(ctx, target.Table, target.Column)
+	if err != nil {
+		return err
+	}
+	if dimension > storage.MaxIndexDimension() {
+		return fmt.Errorf("%s supports indexes up to %d dimensions, but %s.%s has %d", storage, storage.MaxIndexDimension(), target.Table, target.Column, dimension)
+	}
+	if params.Method == VectorIndexIVFFlat && params.Lists <= 0 {
+		rows, err := db.CountRows(ctx, target.Table)
+		if err != nil {
+			return err
+		}
+		params.Lists = RecommendedIVFFlatLists(rows)
+	}
+
+	alterSQL, err := BuildConvertVectorStorageSQL(target, storage, dimension)
+	if err != nil {
+		return err
+	}
+	// 型の変換はテーブルを書き換えるため、トランザクション内では CONCURRENTLY を使わない
+	params.Storage = storage
+	params.Concurrently = false
+	createSQL, err := BuildVectorIndexSQL(target, params)
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// 既存のインデックスは変換前の型の演算子クラスで作られているため、先に削除する
+	if _, err := tx.Exec(ctx, "DROP INDEX IF EXISTS "+pgx.Identifier{target.IndexName}.Sanitize()); err != nil {
+		return fmt.Errorf("failed to drop index %s: %w", target.IndexName, err)
+	}
+	if _, err := tx.Exec(ctx, alterSQL); err != nil {
+		return fmt.Errorf("failed to convert %s.%s to %s: %w", target.Table, target.Column, storage, err)
+	}
+	if _, err := tx.Exec(ctx, createSQL); err != nil {
+		return fmt.Errorf("failed to create index %s: %w", target.IndexName, err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	if _, err := db.Pool.Exec(ctx, "ANALYZE "+pgx.Identifier{target.Table}.Sanitize()); err != nil {
+		return fmt.Errorf("failed to analyze %s: %w", target.Table, err)
+	}
+	return nil
+}
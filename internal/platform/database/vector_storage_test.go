@@ -0,0 +1,36 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVectorStorage(t *testing.T) {
+	storage, err := ParseVectorStorage("")
+	require.NoError(t, err)
+	assert.Equal(t, VectorStorageVector, storage)
+
+	storage, err = ParseVectorStorage("halfvec")
+	require.NoError(t, err)
+	assert.Equal(t, VectorStorageHalfvec, storage)
+
+	_, err = ParseVectorStorage("int8")
+	assert.Error(t, err)
+}
+
+func TestBuildConvertVectorStorageSQL(t *testing.T) {
+	target := VectorIndexTargets["summary_embeddings"]
+
+	sql, err := BuildConvertVectorStorageSQL(target, VectorStorageHalfvec, 1536)
+	require.NoError(t, err)
+	assert.Equal(t, `ALTER TABLE "summary_embeddings" ALTER COLUMN "vector" TYPE halfvec(1536) USING "vector"::halfvec(1536)`, sql)
+
+	sql, err = BuildConvertVectorStorageSQL(target, VectorStorageVector, 1536)
+	require.NoError(t, err)
+	assert.Equal(t, `ALTER TABLE "summary_embeddings" ALTER COLUMN "vector" TYPE vector(1536) USING "vector"::vector(1536)`, sql)
+
+	_, err = BuildConvertVectorStorageSQL(target, VectorStorageHalfvec, 0)
+	assert.Error(t, err)
+}
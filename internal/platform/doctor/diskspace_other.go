@@ -0,0 +1,10 @@
+//go:build !linux && !darwin
+
+package doctor
+
+import "errors"
+
+// freeBytes は空き容量の取得に対応していないプラットフォームではエラーを返す
+func freeBytes(path string) (uint64, error) {
+	return 0, errors.New("unsupported platform")
+}
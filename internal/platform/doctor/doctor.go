@@ -0,0 +1,517 @@
+package doctor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	coreingestion "github.com/jinford/dev-rag/internal/core/ingestion"
+	"github.com/jinford/dev-rag/internal/infra/completion"
+	"github.com/jinford/dev-rag/internal/infra/embedding"
+	"github.com/jinford/dev-rag/internal/infra/git"
+	"github.com/jinford/dev-rag/internal/platform/config"
+	"github.com/jinford/dev-rag/internal/platform/container"
+	"github.com/jinford/dev-rag/internal/platform/database"
+)
+
+const (
+	// DefaultTimeout は1つの診断項目の既定のタイムアウト
+	DefaultTimeout = 30 * time.Second
+
+	// minFreeBytes はクローン先の空き容量がこれを下回ると失敗とする
+	minFreeBytes = 1 << 30 // 1GiB
+	// lowFreeBytes はクローン先の空き容量がこれを下回ると警告する
+	lowFreeBytes = 10 << 30 // 10GiB
+)
+
+// Status は診断項目の結果
+type Status string
+
+const (
+	StatusOK   Status = "ok"   // 問題なし
+	StatusWarn Status = "warn" // 動作するが対処を推奨
+	StatusFail Status = "fail" // 対処が必要
+	StatusSkip Status = "skip" // 前提の項目の失敗などにより診断していない
+)
+
+// Check は1つの診断項目の結果
+type Check struct {
+	Name   string `json:"name"`
+	Status Status `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	Hint   string `json:"hint,omitempty"` // 問題を解消するための対処
+}
+
+// Report は診断結果
+type Report struct {
+	Checks []Check `json:"checks"`
+}
+
+// Count は指定した結果の診断項目の数を返す
+func (r *Report) Count(status Status) int {
+	n := 0
+	for _, c := range r.Checks {
+		if c.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+func (r *Report) add(c Check) {
+	r.Checks = append(r.Checks, c)
+}
+
+// Doctor は dev-rag を動作させる環境（データベース・外部API・Git・ディスク）を診断する
+type Doctor struct {
+	cfg     *config.Config
+	skipAPI bool
+	timeout time.Duration
+	logger  *slog.Logger
+}
+
+// Option は Doctor のオプション設定
+type Option func(*Doctor)
+
+// WithSkipAPI は Embedding・LLM の API を呼び出す診断を省略する（設定からクライアントを生成できるかのみ確認する）
+func WithSkipAPI(skip bool) Option {
+	return func(d *Doctor) {
+		d.skipAPI = skip
+	}
+}
+
+// WithTimeout は1つの診断項目のタイムアウトを設定する
+func WithTimeout(timeout time.Duration) Option {
+	return func(d *Doctor) {
+		d.timeout = timeout
+	}
+}
+
+// WithLogger は Doctor にロガーを設定する
+func WithLogger(logger *slog.Logger) Option {
+	return func(d *Doctor) {
+		d.logger = logger
+	}
+}
+
+// New は新しい Doctor を作成する
+func New(cfg *config.Config, opts ...Option) *Doctor {
+	d := &Doctor{
+		cfg:     cfg,
+		timeout: DefaultTimeout,
+		logger:  slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	if d.logger == nil {
+		d.logger = slog.Default()
+	}
+	if d.timeout <= 0 {
+		d.timeout = DefaultTimeout
+	}
+	return d
+}
+
+// Run はすべての項目を診断する（失敗した項目があっても残りの項目を診断する）
+func (d *Doctor) Run(ctx context.Context) *Report {
+	report := &Report{}
+	report.add(d.checkConfig())
+
+	db, check := d.checkDatabase(ctx)
+	report.add(check)
+	var stored int
+	if db != nil {
+		defer db.Close()
+		report.add(d.checkPgvector(ctx, db))
+		report.add(d.checkSchema(ctx, db))
+		stored, check = d.checkVectorColumn(ctx, db)
+		report.add(check)
+		report.add(d.checkContainer(ctx))
+	} else {
+		for _, name := range []string{"pgvector", "スキーマ", "ベクトルカラム", "アプリケーションの初期化"} {
+			report.add(Check{Name: name, Status: StatusSkip, Detail: "データベースに接続できないため省略"})
+		}
+	}
+
+	report.add(d.checkEmbedding(ctx, stored))
+	for _, check := range d.checkLLMs(ctx) {
+		report.add(check)
+	}
+	report.add(d.checkGitAuth())
+	report.add(d.checkCloneDir())
+	return report
+}
+
+// checkConfig は設定ファイルの未知のキーや解釈できない値を確認する
+func (d *Doctor) checkConfig() Check {
+	source := "環境変数のみ"
+	if d.cfg.File != "" {
+		source = d.cfg.File
+	}
+	if warnings := d.cfg.Warnings(); len(warnings) > 0 {
+		return Check{
+			Name:   "設定",
+			Status: StatusWarn,
+			Detail: fmt.Sprintf("%s（%d 件の警告: %s）", source, len(warnings), warnings[0]),
+			Hint:   "`dev-rag config validate` ですべての警告を確認してください",
+		}
+	}
+	return Check{Name: "設定", Status: StatusOK, Detail: source}
+}
+
+func (d *Doctor) checkDatabase(ctx context.Context) (*database.Database, Check) {
+	ctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	dbCfg := d.cfg.Database
+	target := fmt.Sprintf("%s@%s:%d/%s", dbCfg.User, dbCfg.Host, dbCfg.Port, dbCfg.DBName)
+	db, err := database.New(ctx, database.ConnectionParams{
+		Host:     dbCfg.Host,
+		Port:     dbCfg.Port,
+		User:     dbCfg.User,
+		Password: dbCfg.Password,
+		DBName:   dbCfg.DBName,
+		SSLMode:  dbCfg.SSLMode,
+	})
+	if err != nil {
+		return nil, Check{
+			Name:   "データベース接続",
+			Status: StatusFail,
+			Detail: fmt.Sprintf("%s: %v", target, err),
+			Hint:   "DB_HOST・DB_PORT・DB_USER・DB_PASSWORD・DB_NAME・DB_SSLMODE を確認してください（ローカルでは `make db-up` でデータベースを起動できます）",
+		}
+	}
+	version, err := db.ServerVersion(ctx)
+	if err != nil {
+		version = "不明"
+	}
+	return db, Check{Name: "データベース接続", Status: StatusOK, Detail: fmt.Sprintf("%s（PostgreSQL %s）", target, version)}
+}
+
+func (d *Doctor) checkPgvector(ctx context.Context, db *database.Database) Check {
+	ctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	version, err := db.ExtensionVersion(ctx, "vector")
+	if err != nil {
+		return Check{Name: "pgvector", Status: StatusFail, Detail: err.Error()}
+	}
+	if version == "" {
+		return Check{
+			Name:   "pgvector",
+			Status: StatusFail,
+			Detail: "拡張機能 vector がインストールされていません",
+			Hint:   "pgvector を導入した PostgreSQL（例: pgvector/pgvector イメージ）で `CREATE EXTENSION vector;` を実行してください",
+		}
+	}
+	storage, err := database.ParseVectorStorage(d.cfg.Embedding.Storage)
+	if err == nil && storage == database.VectorStorageHalfvec && compareVersion(version, "0.7.0") < 0 {
+		return Check{
+			Name:   "pgvector",
+			Status: StatusFail,
+			Detail: fmt.Sprintf("バージョン %s は halfvec に対応していません", version),
+			Hint:   "pgvector を 0.7.0 以降に更新（`ALTER EXTENSION vector UPDATE;`）するか、EMBEDDING_STORAGE=vector を指定してください",
+		}
+	}
+	return Check{Name: "pgvector", Status: StatusOK, Detail: "バージョン " + version}
+}
+
+func (d *Doctor) checkSchema(ctx context.Context, db *database.Database) Check {
+	ctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	missing, err := db.MissingSchemaMarkers(ctx)
+	if err != nil {
+		return Check{Name: "スキーマ", Status: StatusFail, Detail: err.Error()}
+	}
+	if len(missing) > 0 {
+		names := make([]string, 0, len(missing))
+		for _, m := range missing {
+			names = append(names, fmt.Sprintf("%03d_%s", m.Version, m.Name))
+		}
+		return Check{
+			Name:   "スキーマ",
+			Status: StatusFail,
+			Detail: fmt.Sprintf("未適用のマイグレーション: %s", strings.Join(names, ", ")),
+			Hint:   fmt.Sprintf("schema/migrations の %03d 以降の *.up.sql を番号順に適用してください", missing[0].Version),
+		}
+	}
+	return Check{Name: "スキーマ", Status: StatusOK, Detail: fmt.Sprintf("マイグレーション %03d まで適用済み", database.LatestSchemaVersion())}
+}
+
+// checkVectorColumn はベクトルカラムの次元数を確認し、その次元数を返す（取得できない場合は 0）
+func (d *Doctor) checkVectorColumn(ctx context.Context, db *database.Database) (int, Check) {
+	ctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	stored, err := db.VectorDimension(ctx, "embeddings", "vector")
+	if err != nil {
+		return 0, Check{Name: "ベクトルカラム", Status: StatusFail, Detail: err.Error()}
+	}
+	if _, err := embedding.ResolveDimension(d.cfg.Embedding.Dimension, stored); err != nil {
+		return stored, Check{
+			Name:   "ベクトルカラム",
+			Status: StatusFail,
+			Detail: fmt.Sprintf("embeddings.vector の次元数 %d: %v", stored, err),
+			Hint:   "EMBEDDING_DIMENSION をベクトルカラムの次元数に合わせるか、カラムを `VECTOR(n)` に変更した後に `dev-rag index reembed` で Embedding を再生成してください",
+		}
+	}
+	return stored, Check{Name: "ベクトルカラム", Status: StatusOK, Detail: fmt.Sprintf("embeddings.vector の次元数 %d", stored)}
+}
+
+// checkContainer は起動時と同じ検証（Embeddingモデルの登録・ベクトルの格納型など）でアプリケーションを初期化できるかを確認する
+func (d *Doctor) checkContainer(ctx context.Context) Check {
+	ctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	c, err := container.NewContainer(ctx, d.cfg, container.WithContainerLogger(d.logger))
+	if err != nil {
+		check := Check{Name: "アプリケーションの初期化", Status: StatusFail, Detail: err.Error()}
+		if errors.Is(err, coreingestion.ErrEmbeddingModelNotRegistered) {
+			check.Hint = "`dev-rag embedding register --model <モデル> --dimension <次元数>` でモデルを登録してください"
+		}
+		return check
+	}
+	c.Close()
+	return Check{Name: "アプリケーションの初期化", Status: StatusOK, Detail: "Embeddingモデルの登録・ベクトルの格納型を確認済み"}
+}
+
+// checkEmbedding は Embedding の API を呼び出して認証情報とモデルを確認する
+func (d *Doctor) checkEmbedding(ctx context.Context, stored int) Check {
+	ctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	cfg := d.cfg.Embedding
+	name := fmt.Sprintf("Embedding (%s)", cfg.Provider)
+	embedder, err := embedding.New(embedding.Config{
+		Provider:        cfg.Provider,
+		Model:           cfg.Model,
+		Dimension:       cfg.Dimension,
+		APIKey:          cfg.APIKey,
+		BaseURL:         cfg.BaseURL,
+		AzureAPIVersion: cfg.AzureAPIVersion,
+		VertexProject:   cfg.VertexProject,
+		VertexLocation:  cfg.VertexLocation,
+		VertexToken:     cfg.VertexToken,
+		MaxInputTokens:  cfg.MaxInputTokens,
+	})
+	if err != nil {
+		return Check{Name: name, Status: StatusFail, Detail: err.Error(), Hint: embeddingHint(cfg.Provider)}
+	}
+	if d.skipAPI {
+		return Check{Name: name, Status: StatusSkip, Detail: fmt.Sprintf("モデル %s（API の呼び出しは省略）", embedder.ModelName())}
+	}
+
+	vector, err := embedder.Embed(ctx, "dev-rag doctor")
+	if err != nil {
+		return Check{Name: name, Status: StatusFail, Detail: fmt.Sprintf("モデル %s: %v", embedder.ModelName(), err), Hint: embeddingHint(cfg.Provider)}
+	}
+	if stored > 0 && len(vector) != stored {
+		return Check{
+			Name:   name,
+			Status: StatusFail,
+			Detail: fmt.Sprintf("モデル %s の次元数 %d がベクトルカラムの次元数 %d と一致しません", embedder.ModelName(), len(vector), stored),
+			Hint:   "EMBEDDING_MODEL・EMBEDDING_DIMENSION を確認してください",
+		}
+	}
+	return Check{Name: name, Status: StatusOK, Detail: fmt.Sprintf("モデル %s（%d 次元）", embedder.ModelName(), len(vector))}
+}
+
+// checkLLMs はユースケースの LLM の API を呼び出して認証情報とモデルを確認する（同じ設定のユースケースはまとめて1回確認する）
+func (d *Doctor) checkLLMs(ctx context.Context) []Check {
+	var checks []Check
+	for _, group := range groupLLMConfigs(d.cfg) {
+		checks = append(checks, d.checkLLM(ctx, group.useCases, group.cfg))
+	}
+	return checks
+}
+
+func (d *Doctor) checkLLM(ctx context.Context, useCases []string, cfg config.LLMConfig) Check {
+	ctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	name := fmt.Sprintf("LLM %s (%s)", strings.Join(useCases, ", "), cfg.Provider)
+	client, err := completion.New(completion.Config{
+		Provider:        cfg.Provider,
+		Model:           cfg.Model,
+		APIKey:          cfg.APIKey,
+		BaseURL:         cfg.BaseURL,
+		AzureAPIVersion: cfg.AzureAPIVersion,
+	})
+	if err != nil {
+		return Check{Name: name, Status: StatusFail, Detail: err.Error(), Hint: llmHint(useCases)}
+	}
+	if d.skipAPI {
+		return Check{Name: name, Status: StatusSkip, Detail: fmt.Sprintf("モデル %s（API の呼び出しは省略）", cfg.Model)}
+	}
+	if _, err := client.GenerateCompletion(ctx, "Reply with OK."); err != nil {
+		return Check{Name: name, Status: StatusFail, Detail: fmt.Sprintf("モデル %s: %v", cfg.Model, err), Hint: llmHint(useCases)}
+	}
+	return Check{Name: name, Status: StatusOK, Detail: "モデル " + cfg.Model}
+}
+
+type llmConfigGroup struct {
+	useCases []string
+	cfg      config.LLMConfig
+}
+
+// groupLLMConfigs はプロバイダー・モデル・接続先・APIキーが同じユースケースをまとめる（ユースケースの順）
+func groupLLMConfigs(cfg *config.Config) []llmConfigGroup {
+	var groups []llmConfigGroup
+	for _, useCase := range config.LLMUseCases {
+		llm := cfg.LLMFor(useCase)
+		found := false
+		for i := range groups {
+			g := groups[i].cfg
+			if g.Provider == llm.Provider && g.Model == llm.Model && g.BaseURL == llm.BaseURL && g.APIKey == llm.APIKey {
+				groups[i].useCases = append(groups[i].useCases, useCase)
+				found = true
+				break
+			}
+		}
+		if !found {
+			groups = append(groups, llmConfigGroup{useCases: []string{useCase}, cfg: llm})
+		}
+	}
+	return groups
+}
+
+// checkGitAuth は SSH のリポジトリの取得に使う SSH 鍵を読み込めるかを確認する
+func (d *Doctor) checkGitAuth() Check {
+	keyPath := d.cfg.Git.SSHKeyPath
+	ok, err := git.NewClient(keyPath, d.cfg.Git.SSHPassword).CheckSSHAuth()
+	if err != nil {
+		return Check{
+			Name:   "Git SSH鍵",
+			Status: StatusFail,
+			Detail: fmt.Sprintf("%s: %v", keyPath, err),
+			Hint:   "GIT_SSH_KEY_PATH の秘密鍵の形式とパスフレーズ（GIT_SSH_PASSWORD）を確認してください",
+		}
+	}
+	if !ok {
+		return Check{
+			Name:   "Git SSH鍵",
+			Status: StatusWarn,
+			Detail: fmt.Sprintf("SSH 鍵 %s がありません（SSH のリポジトリは取得できません）", valueOr(keyPath, "（未設定）")),
+			Hint:   "SSH のリポジトリをインデックス化する場合は GIT_SSH_KEY_PATH に秘密鍵を配置してください",
+		}
+	}
+	return Check{Name: "Git SSH鍵", Status: StatusOK, Detail: keyPath}
+}
+
+// checkCloneDir はリポジトリのクローン先に書き込めるか、空き容量が十分かを確認する
+func (d *Doctor) checkCloneDir() Check {
+	dir := d.cfg.Git.CloneDir
+	// クローン先がまだない場合は、作成される親ディレクトリを確認する
+	existing := dir
+	for {
+		if _, err := os.Stat(existing); err == nil {
+			break
+		}
+		parent := filepath.Dir(existing)
+		if parent == existing {
+			break
+		}
+		existing = parent
+	}
+
+	f, err := os.CreateTemp(existing, ".dev-rag-doctor-*")
+	if err != nil {
+		return Check{
+			Name:   "クローン先",
+			Status: StatusFail,
+			Detail: fmt.Sprintf("%s に書き込めません: %v", existing, err),
+			Hint:   "GIT_CLONE_DIR を実行ユーザーが書き込めるディレクトリに変更するか、権限を付与してください",
+		}
+	}
+	f.Close()
+	os.Remove(f.Name())
+
+	free, err := freeBytes(existing)
+	if err != nil {
+		return Check{Name: "クローン先", Status: StatusWarn, Detail: fmt.Sprintf("%s の空き容量を取得できません: %v", dir, err)}
+	}
+	return evaluateFreeSpace(dir, free)
+}
+
+// evaluateFreeSpace はクローン先の空き容量を閾値と比較する
+func evaluateFreeSpace(dir string, free uint64) Check {
+	detail := fmt.Sprintf("%s（空き容量 %s）", dir, formatBytes(free))
+	switch {
+	case free < minFreeBytes:
+		return Check{
+			Name:   "クローン先",
+			Status: StatusFail,
+			Detail: detail,
+			Hint:   fmt.Sprintf("リポジトリのクローンには %s 以上の空き容量が必要です。不要なクローンを削除するか GIT_CLONE_DIR を変更してください", formatBytes(minFreeBytes)),
+		}
+	case free < lowFreeBytes:
+		return Check{
+			Name:   "クローン先",
+			Status: StatusWarn,
+			Detail: detail,
+			Hint:   fmt.Sprintf("大きなリポジトリのクローンには %s 以上の空き容量を推奨します", formatBytes(lowFreeBytes)),
+		}
+	}
+	return Check{Name: "クローン先", Status: StatusOK, Detail: detail}
+}
+
+func embeddingHint(provider string) string {
+	switch provider {
+	case "azure":
+		return "EMBEDDING_API_KEY・EMBEDDING_BASE_URL・AZURE_OPENAI_API_VERSION・EMBEDDING_MODEL（デプロイ名）を確認してください"
+	case "vertex":
+		return "VERTEX_PROJECT・VERTEX_LOCATION・VERTEX_ACCESS_TOKEN を確認してください"
+	case "ollama":
+		return "EMBEDDING_BASE_URL の Ollama に接続できること、EMBEDDING_MODEL を取得済み（`ollama pull`）であることを確認してください"
+	default:
+		return "EMBEDDING_API_KEY（未設定の場合は OPENAI_API_KEY）と EMBEDDING_MODEL を確認してください"
+	}
+}
+
+func llmHint(useCases []string) string {
+	keys := make([]string, 0, len(useCases))
+	for _, useCase := range useCases {
+		keys = append(keys, "LLM_"+strings.ToUpper(useCase)+"_*")
+	}
+	return fmt.Sprintf("%s・LLM_PROVIDER・LLM_MODEL・LLM_API_KEY（未設定の場合は OPENAI_API_KEY）を確認してください", strings.Join(keys, "・"))
+}
+
+// compareVersion は "0.7.0" 形式のバージョンを比較する（a < b なら負、a > b なら正）
+func compareVersion(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < max(len(as), len(bs)); i++ {
+		var x, y int
+		if i < len(as) {
+			fmt.Sscanf(as[i], "%d", &x)
+		}
+		if i < len(bs) {
+			fmt.Sscanf(bs[i], "%d", &y)
+		}
+		if x != y {
+			return x - y
+		}
+	}
+	return 0
+}
+
+func formatBytes(n uint64) string {
+	const gib = 1 << 30
+	if n >= gib {
+		return fmt.Sprintf("%.1fGiB", float64(n)/gib)
+	}
+	return fmt.Sprintf("%.0fMiB", float64(n)/(1<<20))
+}
+
+func valueOr(s, fallback string) string {
+	if s == "" {
+		return fallback
+	}
+	return s
+}
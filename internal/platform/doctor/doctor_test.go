@@ -0,0 +1,53 @@
+package doctor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jinford/dev-rag/internal/platform/config"
+)
+
+func TestEvaluateFreeSpace(t *testing.T) {
+	assert.Equal(t, StatusFail, evaluateFreeSpace("/repos", 512<<20).Status)
+	assert.Equal(t, StatusWarn, evaluateFreeSpace("/repos", 5<<30).Status)
+
+	check := evaluateFreeSpace("/repos", 20<<30)
+	assert.Equal(t, StatusOK, check.Status)
+	assert.Equal(t, "/repos（空き容量 20.0GiB）", check.Detail)
+}
+
+func TestCompareVersion(t *testing.T) {
+	assert.Negative(t, compareVersion("0.6.2", "0.7.0"))
+	assert.Zero(t, compareVersion("0.7.0", "0.7"))
+	assert.Positive(t, compareVersion("0.10.0", "0.7.0"))
+}
+
+func TestGroupLLMConfigs(t *testing.T) {
+	shared := config.LLMConfig{Provider: "openai", Model: "gpt-4o-mini", APIKey: "sk-test"}
+	cfg := &config.Config{
+		LLM: map[string]config.LLMConfig{
+			"summary": shared,
+			"wiki":    {Provider: "openai", Model: "gpt-4o", APIKey: "sk-test"},
+			"ask":     shared,
+		},
+	}
+
+	groups := groupLLMConfigs(cfg)
+	require.Len(t, groups, 2)
+	assert.Equal(t, []string{"summary", "ask"}, groups[0].useCases)
+	assert.Equal(t, []string{"wiki"}, groups[1].useCases)
+	assert.Equal(t, "gpt-4o", groups[1].cfg.Model)
+}
+
+func TestReportCount(t *testing.T) {
+	report := &Report{}
+	report.add(Check{Name: "a", Status: StatusOK})
+	report.add(Check{Name: "b", Status: StatusFail})
+	report.add(Check{Name: "c", Status: StatusFail})
+
+	assert.Equal(t, 1, report.Count(StatusOK))
+	assert.Equal(t, 2, report.Count(StatusFail))
+	assert.Zero(t, report.Count(StatusWarn))
+}
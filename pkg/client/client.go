@@ -0,0 +1,275 @@
+// Package client は dev-rag の HTTP API（/api/v1）の Go クライアント
+//
+// 社内ツールから質問応答・検索・インデックス更新を呼び出す際に、認証・リトライ・
+// エラーレスポンスの解釈・ジョブの完了待ちを個別に実装せずに済むようにする。
+//
+//	c, err := client.New("https://dev-rag.example.com", client.WithToken(os.Getenv("DEVRAG_API_TOKEN")))
+//	res, err := c.Ask(ctx, &client.AskRequest{Product: "ecommerce", Query: "決済のリトライ方針は？"})
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultMaxRetries は一時的なエラーで再試行する既定の回数
+	DefaultMaxRetries = 3
+	// DefaultRetryBackoff は最初の再試行までの既定の待機時間（再試行ごとに2倍、上限 maxRetryBackoff）
+	DefaultRetryBackoff = 500 * time.Millisecond
+
+	maxRetryBackoff = 30 * time.Second
+	apiPrefix       = "/api/v1"
+)
+
+// Client は dev-rag の HTTP API クライアント
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	token      string
+	userAgent  string
+	maxRetries int
+	backoff    time.Duration
+}
+
+// Option は Client のオプション設定
+type Option func(*Client)
+
+// WithToken は Authorization ヘッダに付与する Bearer トークン（APIキー）を設定する
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+// WithHTTPClient は使用する http.Client を設定する（タイムアウトやプロキシの設定用）
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithUserAgent は User-Agent ヘッダを設定する（サーバのログで呼び出し元を識別するため）
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithRetry は一時的なエラー（接続エラー・429・502・503・504）で再試行する回数と最初の待機時間を設定する
+// maxRetries が 0 の場合は再試行しない
+func WithRetry(maxRetries int, backoff time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.backoff = backoff
+	}
+}
+
+// New は baseURL（例: https://dev-rag.example.com）の API を呼び出すクライアントを作成する
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(strings.TrimRight(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base url: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid base url %q: scheme must be http or https", baseURL)
+	}
+
+	c := &Client{
+		baseURL:    u,
+		httpClient: http.DefaultClient,
+		userAgent:  "dev-rag-go-client",
+		maxRetries: DefaultMaxRetries,
+		backoff:    DefaultRetryBackoff,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.httpClient == nil {
+		c.httpClient = http.DefaultClient
+	}
+	return c, nil
+}
+
+// WhoAmI は呼び出し元のAPIキーの識別名・スコープ・アクセス可能なプロダクトを返す
+func (c *Client) WhoAmI(ctx context.Context) (*WhoAmI, error) {
+	var out WhoAmI
+	if err := c.do(ctx, http.MethodGet, "/auth/whoami", nil, &out, true); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListProducts はアクセス可能なプロダクトの一覧を返す
+func (c *Client) ListProducts(ctx context.Context) ([]*Product, error) {
+	var out []*Product
+	if err := c.do(ctx, http.MethodGet, "/products", nil, &out, true); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Ask はプロダクトに関する質問にRAGで回答する
+func (c *Client) Ask(ctx context.Context, req *AskRequest) (*AskResponse, error) {
+	var out AskResponse
+	// 質問応答はサーバの状態を変更しないため、一時的なエラーで再試行する
+	if err := c.do(ctx, http.MethodPost, "/ask", req, &out, true); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Search はプロダクトの最新スナップショットからクエリに近いチャンクを返す
+func (c *Client) Search(ctx context.Context, req *SearchRequest) ([]*SearchResult, error) {
+	var out struct {
+		Results []*SearchResult `json:"results"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/search", req, &out, true); err != nil {
+		return nil, err
+	}
+	return out.Results, nil
+}
+
+// Context はエディタのカーソル位置を含むチャンクと、質問に関連するチャンク・依存関係でつながるチャンクを返す
+func (c *Client) Context(ctx context.Context, req *ContextRequest) (*ContextResponse, error) {
+	var out ContextResponse
+	if err := c.do(ctx, http.MethodPost, "/context", req, &out, true); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// IndexGit はGitソースのインデックス更新ジョブを開始し、受け付けたジョブを返す
+// ジョブの重複を避けるため再試行しない（完了は WaitForJob で待つ）
+func (c *Client) IndexGit(ctx context.Context, req *IndexGitRequest) (*Job, error) {
+	var out Job
+	if err := c.do(ctx, http.MethodPost, "/index/git", req, &out, false); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// IndexStatus はジョブの現在の状態を返す
+func (c *Client) IndexStatus(ctx context.Context, jobID string) (*Job, error) {
+	var out Job
+	if err := c.do(ctx, http.MethodGet, "/jobs/"+url.PathEscape(jobID), nil, &out, true); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// do はリクエストを送信し、成功した場合はレスポンスボディを out にデコードする
+// retryable の場合は一時的なエラーで指数バックオフ（Retry-After があればその秒数）で再試行する
+func (c *Client) do(ctx context.Context, method, path string, in, out any, retryable bool) error {
+	var body []byte
+	if in != nil {
+		var err error
+		if body, err = json.Marshal(in); err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+	}
+
+	maxRetries := 0
+	if retryable {
+		maxRetries = max(c.maxRetries, 0)
+	}
+
+	var lastErr error
+	for attempt := 0; ; attempt++ {
+		retryAfter, err := c.send(ctx, method, path, body, out)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if attempt >= maxRetries || !isRetryable(ctx, err) {
+			return lastErr
+		}
+
+		delay := retryAfter
+		if delay <= 0 {
+			delay = min(c.backoff<<min(attempt, 30), maxRetryBackoff)
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// send はリクエストを1回送信する。エラーの場合はサーバが指定した再試行までの待機時間（Retry-After）も返す
+func (c *Client) send(ctx context.Context, method, path string, body []byte, out any) (time.Duration, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL.String()+apiPrefix+path, reader)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return parseRetryAfter(resp.Header.Get("Retry-After")), newAPIError(resp)
+	}
+	if out == nil {
+		return 0, nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return 0, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return 0, nil
+}
+
+// isRetryable は再試行すべきエラー（接続エラー・429・502・503・504）かどうかを判定する
+func isRetryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+	// リクエストの作成・レスポンスのデコードの失敗は再試行しても解消しない
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
+
+// parseRetryAfter は Retry-After ヘッダ（秒数）を解釈する（日時形式・不正な値は 0）
+func parseRetryAfter(value string) time.Duration {
+	seconds, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	return min(time.Duration(seconds)*time.Second, maxRetryBackoff)
+}
@@ -0,0 +1,165 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc, opts ...Option) *Client {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	opts = append([]Option{WithToken("test-token"), WithRetry(2, time.Millisecond)}, opts...)
+	c, err := New(srv.URL+"/", opts...)
+	require.NoError(t, err)
+	return c
+}
+
+func TestNew_InvalidBaseURL(t *testing.T) {
+	_, err := New("localhost:8080")
+	assert.Error(t, err)
+}
+
+func TestClient_Ask(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/v1/ask", r.URL.Path)
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+
+		var req map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "ecommerce", req["product"])
+		assert.Equal(t, true, req["verifyCitations"])
+
+		_, _ = w.Write([]byte(`{
+			"answer": "決済は3回まで再試行します",
+			"sources": [{"FilePath": "payment/retry.go", "StartLine": 10, "EndLine": 42, "Score": 0.91}],
+			"refused": false,
+			"citations": [{"Sentence": "決済は3回まで再試行します", "FilePath": "payment/retry.go", "Verdict": "supported", "Confidence": 0.9}],
+			"promptVersion": "1.2"
+		}`))
+	})
+
+	res, err := c.Ask(context.Background(), &AskRequest{Product: "ecommerce", Query: "決済のリトライ方針は？", VerifyCitations: true})
+	require.NoError(t, err)
+	assert.Equal(t, "決済は3回まで再試行します", res.Answer)
+	require.Len(t, res.Sources, 1)
+	assert.Equal(t, "payment/retry.go", res.Sources[0].FilePath)
+	assert.Equal(t, 42, res.Sources[0].EndLine)
+	require.Len(t, res.Citations, 1)
+	assert.Equal(t, "supported", res.Citations[0].Verdict)
+	assert.Equal(t, "1.2", res.PromptVersion)
+}
+
+func TestClient_RetriesTransientErrors(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"results": [{"chunkID": "c1", "filePath": "main.go", "score": 0.8}]}`))
+	})
+
+	results, err := c.Search(context.Background(), &SearchRequest{Product: "ecommerce", Query: "main"})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "main.go", results[0].FilePath)
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestClient_Context(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/context", r.URL.Path)
+
+		var req map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "payment/retry.go", req["filePath"])
+		assert.Equal(t, float64(25), req["line"])
+
+		_, _ = w.Write([]byte(`{
+			"focus": {"chunkID": "c1", "filePath": "payment/retry.go", "startLine": 10, "endLine": 42, "score": 1},
+			"related": [],
+			"dependencies": [{"chunkID": "c2", "filePath": "payment/gateway.go", "score": 0.5, "relation": {"viaChunkID": "c1", "direction": "outgoing", "depType": "call", "hop": 1}}]
+		}`))
+	})
+
+	res, err := c.Context(context.Background(), &ContextRequest{Product: "ecommerce", FilePath: "payment/retry.go", Line: 25})
+	require.NoError(t, err)
+	require.NotNil(t, res.Focus)
+	assert.Equal(t, 42, res.Focus.EndLine)
+	require.Len(t, res.Dependencies, 1)
+	assert.Equal(t, "call", res.Dependencies[0].Relation.DepType)
+}
+
+func TestClient_DoesNotRetryIndexGit(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	_, err := c.IndexGit(context.Background(), &IndexGitRequest{URL: "git@example.com:org/repo.git", Product: "ecommerce"})
+	require.Error(t, err)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestClient_APIError(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error": "プロダクトが見つかりません: unknown", "code": "PRODUCT_NOT_FOUND"}`))
+	})
+
+	_, err := c.Ask(context.Background(), &AskRequest{Product: "unknown", Query: "q"})
+	require.Error(t, err)
+
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	assert.Equal(t, ErrCodeProductNotFound, apiErr.Code)
+	assert.True(t, IsNotFound(err))
+	assert.Equal(t, int32(1), calls.Load(), "4xx errors are not retried")
+}
+
+func TestClient_WatchJob(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/jobs/job-1", r.URL.Path)
+		status := JobStatusRunning
+		if calls.Add(1) >= 3 {
+			status = JobStatusCompleted
+		}
+		_ = json.NewEncoder(w).Encode(Job{JobID: "job-1", Status: status})
+	})
+
+	var changes []JobStatus
+	job, err := c.WatchJob(context.Background(), "job-1", time.Millisecond, func(j *Job) {
+		changes = append(changes, j.Status)
+	})
+	require.NoError(t, err)
+	assert.True(t, job.Done())
+	assert.Equal(t, []JobStatus{JobStatusRunning, JobStatusCompleted}, changes)
+}
+
+func TestClient_WaitForJobFailed(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(Job{JobID: "job-1", Status: JobStatusFailed, Error: "rate limit exceeded"})
+	})
+
+	job, err := c.WaitForJob(context.Background(), "job-1", time.Millisecond)
+	require.ErrorIs(t, err, ErrJobFailed)
+	assert.Contains(t, err.Error(), "rate limit exceeded")
+	assert.Equal(t, JobStatusFailed, job.Status)
+}
@@ -0,0 +1,71 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// エラーコード（docs/api-interface.md 4.9）
+const (
+	ErrCodeProductNotFound = "PRODUCT_NOT_FOUND"
+	ErrCodeSourceNotFound  = "SOURCE_NOT_FOUND"
+	ErrCodeJobNotFound     = "JOB_NOT_FOUND"
+	ErrCodeInvalidRequest  = "INVALID_REQUEST"
+	ErrCodeUnauthorized    = "UNAUTHORIZED"
+	ErrCodeForbidden       = "FORBIDDEN"
+	ErrCodeInternal        = "INTERNAL_ERROR"
+
+	ErrCodeAmbiguousProduct = "AMBIGUOUS_PRODUCT" // Product を省略した質問の振り分け先を特定できない（409）
+)
+
+// ErrJobFailed はジョブが失敗したことを表す（WaitForJob・WatchJob が返す）
+var ErrJobFailed = errors.New("job failed")
+
+// APIError はAPIが返したエラーレスポンス
+type APIError struct {
+	StatusCode int
+	Code       string // エラーコード（ErrCode*、エラーレスポンスの形式でない場合は空）
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("dev-rag api: status %d: %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("dev-rag api: status %d (%s): %s", e.StatusCode, e.Code, e.Message)
+}
+
+// IsNotFound はプロダクト・ソース・ジョブが見つからないエラーかどうかを返す
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// maxErrorBodySize はエラーメッセージとして読み込むレスポンスボディの上限
+const maxErrorBodySize = 64 << 10
+
+// newAPIError はエラーレスポンス（{"error": ..., "code": ...}）を APIError に変換する
+// 形式が異なる場合（プロキシのエラーページなど）はボディをそのままメッセージとする
+func newAPIError(resp *http.Response) *APIError {
+	apiErr := &APIError{StatusCode: resp.StatusCode}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+
+	var payload struct {
+		Error string `json:"error"`
+		Code  string `json:"code"`
+	}
+	if err := json.Unmarshal(body, &payload); err == nil && payload.Error != "" {
+		apiErr.Code = payload.Code
+		apiErr.Message = payload.Error
+		return apiErr
+	}
+
+	apiErr.Message = string(body)
+	if apiErr.Message == "" {
+		apiErr.Message = http.StatusText(resp.StatusCode)
+	}
+	return apiErr
+}
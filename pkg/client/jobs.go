@@ -0,0 +1,53 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DefaultPollInterval はジョブの状態を取得する既定の間隔
+const DefaultPollInterval = 2 * time.Second
+
+// WatchJob はジョブが終了するまで interval ごとに状態を取得し、状態が変わるたびに onChange を呼び出す
+// ジョブが完了した場合は最終的な状態を返し、失敗した場合は ErrJobFailed をラップしたエラーとともに返す
+// サーバはジョブの状態をストリーミングしないため、ポーリングで変化を通知する
+func (c *Client) WatchJob(ctx context.Context, jobID string, interval time.Duration, onChange func(*Job)) (*Job, error) {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+
+	var last JobStatus
+	for {
+		job, err := c.IndexStatus(ctx, jobID)
+		if err != nil {
+			return nil, err
+		}
+		if job.Status != last {
+			last = job.Status
+			if onChange != nil {
+				onChange(job)
+			}
+		}
+
+		switch job.Status {
+		case JobStatusCompleted:
+			return job, nil
+		case JobStatusFailed:
+			return job, fmt.Errorf("%w: %s", ErrJobFailed, job.Error)
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return job, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// WaitForJob はジョブが終了するまで待ち、最終的な状態を返す（失敗した場合は ErrJobFailed）
+func (c *Client) WaitForJob(ctx context.Context, jobID string, interval time.Duration) (*Job, error) {
+	return c.WatchJob(ctx, jobID, interval, nil)
+}
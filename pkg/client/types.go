@@ -0,0 +1,177 @@
+package client
+
+import "time"
+
+// WhoAmI は呼び出し元のAPIキーの情報
+type WhoAmI struct {
+	Name     string   `json:"name"`
+	Scopes   []string `json:"scopes"`
+	Products []string `json:"products"` // 空の場合は全プロダクト
+}
+
+// Product はプロダクトと統計情報
+type Product struct {
+	ID              string     `json:"id"`
+	Name            string     `json:"name"`
+	Description     *string    `json:"description,omitempty"`
+	CreatedAt       time.Time  `json:"createdAt"`
+	UpdatedAt       time.Time  `json:"updatedAt"`
+	SourceCount     int        `json:"sourceCount"`
+	LastIndexedAt   *time.Time `json:"lastIndexedAt,omitempty"`
+	WikiGeneratedAt *time.Time `json:"wikiGeneratedAt,omitempty"`
+	Summary         *string    `json:"summary,omitempty"` // インデックスから生成したプロダクト全体の要約
+}
+
+// AskRequest は質問応答のリクエスト
+type AskRequest struct {
+	Product      string   `json:"product,omitempty"` // 省略時はサーバが質問をプロダクトの要約との類似度から振り分ける
+	Query        string   `json:"query"`
+	ChunkLimit   int      `json:"chunkLimit"`
+	SummaryLimit int      `json:"summaryLimit"`
+	Model        string   `json:"model,omitempty"`
+	Source       string   `json:"source,omitempty"`
+	Ref          string   `json:"ref,omitempty"`      // ブランチ・タグ名（Snapshot と同時に指定できない）
+	Snapshot     string   `json:"snapshot,omitempty"` // スナップショットのバージョン識別子
+	Domains      []string `json:"domains,omitempty"`
+	Languages    []string `json:"languages,omitempty"`
+	Strategy     string   `json:"strategy,omitempty"` // flat / two-stage（省略時はサーバの既定値）
+
+	EmbeddingModel  string `json:"embeddingModel,omitempty"` // チャンク検索に使用する追加のEmbeddingモデル（省略時はサーバの主モデル）
+	DependencyHops  *int   `json:"dependencyHops,omitempty"`
+	VerifyCitations bool   `json:"verifyCitations,omitempty"`
+	NoCache         bool   `json:"noCache,omitempty"` // キャッシュした回答を使わずに検索・回答を生成する
+}
+
+// AskResponse は質問応答の結果
+type AskResponse struct {
+	Answer        string            `json:"answer"`
+	Sources       []SourceReference `json:"sources"`
+	Refused       bool              `json:"refused"`
+	RefusalReason string            `json:"refusalReason,omitempty"`
+	Citations     []CitationCheck   `json:"citations,omitempty"`
+	PromptVersion string            `json:"promptVersion,omitempty"`
+	Cached        bool              `json:"cached,omitempty"`   // キャッシュした回答を返したかどうか
+	Degraded      []string          `json:"degraded,omitempty"` // 所要時間の予算を超えたために省略・打ち切った処理
+	Product       string            `json:"product"`            // 回答したプロダクト（Product を省略した場合は振り分け先）
+}
+
+// SourceReference は回答の根拠となったソース
+// サーバはフィールド名をそのままキーとして返すため、タグもフィールド名と同じにしている
+type SourceReference struct {
+	FilePath   string   `json:"FilePath"`
+	StartLine  int      `json:"StartLine"`
+	EndLine    int      `json:"EndLine"`
+	Breadcrumb *string  `json:"Breadcrumb"`
+	PageStart  *int     `json:"PageStart"`
+	PageEnd    *int     `json:"PageEnd"`
+	Owners     []string `json:"Owners"`
+	Score      float64  `json:"Score"`
+}
+
+// CitationCheck は回答の引用の検証結果（AskRequest.VerifyCitations 指定時のみ）
+type CitationCheck struct {
+	Sentence   string  `json:"Sentence"`
+	FilePath   string  `json:"FilePath"`
+	StartLine  int     `json:"StartLine"`
+	EndLine    int     `json:"EndLine"`
+	Verdict    string  `json:"Verdict"` // supported / partial / unsupported
+	Confidence float64 `json:"Confidence"`
+	Reason     string  `json:"Reason"`
+}
+
+// SearchRequest はチャンク検索のリクエスト
+type SearchRequest struct {
+	Product    string   `json:"product"`
+	Query      string   `json:"query"`
+	Limit      int      `json:"limit"` // 0 の場合はサーバの既定値（10）
+	PathPrefix string   `json:"pathPrefix,omitempty"`
+	Domains    []string `json:"domains,omitempty"`
+	Languages  []string `json:"languages,omitempty"`
+	Strategy   string   `json:"strategy,omitempty"`
+
+	EmbeddingModel string `json:"embeddingModel,omitempty"` // チャンク検索に使用する追加のEmbeddingモデル（省略時はサーバの主モデル）
+}
+
+// SearchResult は検索でヒットしたチャンク
+type SearchResult struct {
+	ChunkID     string         `json:"chunkID"`
+	FileID      string         `json:"fileID"`
+	FilePath    string         `json:"filePath"`
+	StartLine   int            `json:"startLine"`
+	EndLine     int            `json:"endLine"`
+	Level       int            `json:"level,omitempty"`
+	Breadcrumb  *string        `json:"breadcrumb,omitempty"`
+	PageStart   *int           `json:"pageStart,omitempty"`
+	PageEnd     *int           `json:"pageEnd,omitempty"`
+	Content     string         `json:"content"`
+	Score       float64        `json:"score"`
+	PrevContent *string        `json:"prevContent,omitempty"`
+	NextContent *string        `json:"nextContent,omitempty"`
+	Relation    *ChunkRelation `json:"relation,omitempty"`
+}
+
+// ContextRequest はエディタのカーソル位置の文脈取得のリクエスト
+type ContextRequest struct {
+	Product         string `json:"product"`
+	FilePath        string `json:"filePath"`           // ソースのルートからの相対パス
+	Line            int    `json:"line"`               // カーソルの行（1始まり）
+	Question        string `json:"question,omitempty"` // 省略時はカーソル位置のチャンクの内容で検索
+	Limit           int    `json:"limit"`              // 0 の場合はサーバの既定値（10）
+	DependencyHops  *int   `json:"dependencyHops,omitempty"`
+	MaxDependencies int    `json:"maxDependencies"` // 0 の場合はサーバの既定値（10）
+}
+
+// ContextResponse はカーソル位置に関連するチャンク
+type ContextResponse struct {
+	Focus        *SearchResult   `json:"focus"` // カーソル位置を含むチャンク（インデックスにない場合は nil）
+	Related      []*SearchResult `json:"related"`
+	Dependencies []*SearchResult `json:"dependencies"`
+}
+
+// ChunkRelation は依存関係の展開で追加されたチャンクの起点との関係
+type ChunkRelation struct {
+	ViaChunkID   string  `json:"viaChunkID"`
+	ViaFilePath  string  `json:"viaFilePath"`
+	ViaStartLine int     `json:"viaStartLine"`
+	ViaEndLine   int     `json:"viaEndLine"`
+	Direction    string  `json:"direction"`
+	DepType      string  `json:"depType"`
+	Symbol       *string `json:"symbol,omitempty"`
+	Hop          int     `json:"hop"`
+}
+
+// IndexGitRequest はGitソースのインデックス更新のリクエスト
+type IndexGitRequest struct {
+	URL          string `json:"url"`
+	Product      string `json:"product"`
+	Ref          string `json:"ref"`
+	SinceCommit  string `json:"sinceCommit,omitempty"` // インデックス化済みのコミットからの差分だけをインデックス化する
+	ForceInit    bool   `json:"forceInit"`
+	GenerateWiki bool   `json:"generateWiki"` // 未対応（true の場合はサーバが 400 を返す）
+}
+
+// JobStatus はジョブの状態
+type JobStatus string
+
+const (
+	JobStatusRunning   JobStatus = "running"
+	JobStatusCompleted JobStatus = "completed"
+	JobStatusFailed    JobStatus = "failed"
+)
+
+// Job は非同期ジョブの状態
+type Job struct {
+	JobID      string     `json:"jobID"`
+	TargetType string     `json:"targetType"`
+	TargetName string     `json:"targetName"`
+	JobType    string     `json:"jobType"`
+	Status     JobStatus  `json:"status"`
+	StartedAt  time.Time  `json:"startedAt"`
+	EndedAt    *time.Time `json:"endedAt,omitempty"`
+	Error      string     `json:"error"`
+}
+
+// Done はジョブが終了（完了・失敗）したかどうかを返す
+func (j *Job) Done() bool {
+	return j.Status == JobStatusCompleted || j.Status == JobStatusFailed
+}
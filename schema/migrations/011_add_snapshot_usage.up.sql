@@ -0,0 +1,18 @@
+-- LLM/Embeddingのトークン使用量と推定コストをスナップショット単位で記録する
+-- 用途（embedding, summary, wiki, ask）とモデルごとに累積する
+
+CREATE TABLE snapshot_usage (
+    snapshot_id UUID NOT NULL REFERENCES source_snapshots(id) ON DELETE CASCADE,
+    kind VARCHAR(50) NOT NULL,          -- 用途 (embedding, summary, wiki, ask)
+    model VARCHAR(100) NOT NULL,        -- 使用したモデル名
+    requests INTEGER NOT NULL DEFAULT 0,
+    input_tokens BIGINT NOT NULL DEFAULT 0,
+    output_tokens BIGINT NOT NULL DEFAULT 0,
+    cost_usd DOUBLE PRECISION NOT NULL DEFAULT 0,  -- 推定コスト（USD）
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (snapshot_id, kind, model)
+);
+
+COMMENT ON TABLE snapshot_usage IS 'スナップショットごとのLLM/Embedding使用量と推定コスト';
+COMMENT ON COLUMN snapshot_usage.kind IS '用途（embedding/summary/wiki/ask）';
+COMMENT ON COLUMN snapshot_usage.cost_usd IS '単価表から算出した推定コスト（USD）';
@@ -0,0 +1,3 @@
+-- プロダクトの鮮度の重みのロールバック
+
+ALTER TABLE products DROP COLUMN IF EXISTS recency_weight;
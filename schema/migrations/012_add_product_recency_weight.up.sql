@@ -0,0 +1,5 @@
+-- 検索時の鮮度（chunks.updated_at）による重み付けをプロダクトごとに設定する
+
+ALTER TABLE products ADD COLUMN recency_weight DOUBLE PRECISION;  -- 0〜1、NULL の場合は鮮度を加味しない
+
+COMMENT ON COLUMN products.recency_weight IS '検索スコアに鮮度（チャンクの最終更新日時）を加味する重み（0〜1、NULLは加味しない）';
@@ -0,0 +1,3 @@
+-- Wiki公開状況の記録のロールバック
+
+DROP TABLE IF EXISTS wiki_publications;
@@ -0,0 +1,28 @@
+-- 生成したWikiの外部サービス（Confluence等）への公開状況を記録する
+-- 再公開時に同じリモートページを更新するため、ローカルのページとリモートのページIDを対応付ける
+
+CREATE TABLE IF NOT EXISTS wiki_publications (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    target VARCHAR(50) NOT NULL,          -- 公開先の種類（confluence）
+    destination VARCHAR(255) NOT NULL,    -- 公開先（Confluenceのスペースキーなど）
+    page_path VARCHAR(512) NOT NULL,      -- Wiki出力ディレクトリからの相対パス（ディレクトリのページは末尾 /）
+    remote_id VARCHAR(255) NOT NULL,      -- リモートのページID
+    remote_version INTEGER NOT NULL,      -- 最後に公開したリモートのページのバージョン
+    parent_remote_id VARCHAR(255),        -- 親ページのリモートID
+    title VARCHAR(255) NOT NULL,
+    content_hash VARCHAR(64) NOT NULL,    -- 公開した内容（タイトル・親・本文）のハッシュ
+    published_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_wiki_publications_page UNIQUE (product_id, target, destination, page_path)
+);
+
+CREATE INDEX IF NOT EXISTS idx_wiki_publications_product ON wiki_publications(product_id, target, destination);
+
+COMMENT ON TABLE wiki_publications IS '生成したWikiページと公開先のリモートページの対応';
+COMMENT ON COLUMN wiki_publications.target IS '公開先の種類（confluence）';
+COMMENT ON COLUMN wiki_publications.destination IS '公開先（Confluenceのスペースキーなど）';
+COMMENT ON COLUMN wiki_publications.page_path IS 'Wiki出力ディレクトリからの相対パス（ディレクトリのページは末尾 /）';
+COMMENT ON COLUMN wiki_publications.remote_id IS 'リモートのページID';
+COMMENT ON COLUMN wiki_publications.remote_version IS '最後に公開したリモートのページのバージョン';
+COMMENT ON COLUMN wiki_publications.content_hash IS '公開した内容（タイトル・親・本文）のSHA-256ハッシュ';
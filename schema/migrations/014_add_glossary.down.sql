@@ -0,0 +1,3 @@
+-- 用語集のロールバック
+
+DROP TABLE IF EXISTS glossary;
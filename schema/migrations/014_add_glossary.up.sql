@@ -0,0 +1,26 @@
+-- インデックス済みのドキュメント・コードから抽出した用語集をプロダクト単位で保持する
+-- 質問応答のプロンプトへの定義の注入と、用語集のWikiページの生成に使用する
+
+CREATE TABLE IF NOT EXISTS glossary (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    term VARCHAR(255) NOT NULL,
+    normalized_term VARCHAR(255) NOT NULL,    -- 重複判定用に小文字化した用語
+    kind VARCHAR(20) NOT NULL,                -- term / acronym / entity
+    definition TEXT NOT NULL,
+    aliases TEXT[] NOT NULL DEFAULT '{}',     -- 別名・略語の展開形
+    source_paths TEXT[] NOT NULL DEFAULT '{}', -- 抽出元のファイルパス
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_glossary_product_term UNIQUE (product_id, normalized_term),
+    CONSTRAINT chk_glossary_kind CHECK (kind IN ('term', 'acronym', 'entity'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_glossary_product ON glossary(product_id);
+
+COMMENT ON TABLE glossary IS 'インデックス済みのドキュメント・コードからLLMで抽出したプロダクトの用語集';
+COMMENT ON COLUMN glossary.normalized_term IS '重複判定用に小文字化した用語';
+COMMENT ON COLUMN glossary.kind IS '用語の種類（term: ドメイン用語, acronym: 略語, entity: エンティティ）';
+COMMENT ON COLUMN glossary.aliases IS '別名・略語の展開形';
+COMMENT ON COLUMN glossary.source_paths IS '抽出元のファイルパス';
+COMMENT ON COLUMN glossary.updated_at IS '最後に抽出された日時';
@@ -0,0 +1,3 @@
+-- 設計判断の記録のロールバック
+
+DROP TABLE IF EXISTS decision_records;
@@ -0,0 +1,27 @@
+-- リポジトリ内の設計判断の記録（ADR・RFC）から抽出したメタデータをファイル単位で保持する
+-- 設計判断のWikiページの生成と、質問応答での採用済みの決定の優先付けに使用する
+
+CREATE TABLE IF NOT EXISTS decision_records (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    snapshot_id UUID NOT NULL REFERENCES source_snapshots(id) ON DELETE CASCADE,
+    file_id UUID NOT NULL REFERENCES files(id) ON DELETE CASCADE,
+    file_path TEXT NOT NULL,
+    number INTEGER,                           -- ADR・RFC の番号
+    title TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL,              -- proposed / accepted / rejected / deprecated / superseded / unknown
+    status_text TEXT NOT NULL DEFAULT '',     -- 記載どおりのステータス
+    decided_on DATE,
+    decision TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_decision_records_file UNIQUE (file_id),
+    CONSTRAINT chk_decision_records_status CHECK (status IN ('proposed', 'accepted', 'rejected', 'deprecated', 'superseded', 'unknown'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_decision_records_snapshot ON decision_records(snapshot_id);
+
+COMMENT ON TABLE decision_records IS 'リポジトリ内の設計判断の記録（ADR・RFC）から抽出したメタデータ';
+COMMENT ON COLUMN decision_records.number IS 'ファイル名またはタイトルから抽出したADR・RFCの番号';
+COMMENT ON COLUMN decision_records.status IS '正規化したステータス（proposed, accepted, rejected, deprecated, superseded, unknown）';
+COMMENT ON COLUMN decision_records.status_text IS '記載どおりのステータス（Superseded by ADR-0007 など）';
+COMMENT ON COLUMN decision_records.decided_on IS '決定日';
+COMMENT ON COLUMN decision_records.decision IS '決定内容のセクション（ない場合は概要）';
@@ -0,0 +1,3 @@
+-- APIエンドポイントのロールバック
+
+DROP TABLE IF EXISTS api_endpoints;
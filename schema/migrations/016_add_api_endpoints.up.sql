@@ -0,0 +1,30 @@
+-- インデックス化時に OpenAPI（Swagger）・Protocol Buffers の定義から抽出したエンドポイントをファイル単位で保持する
+-- api list コマンドとAPIカタログのWikiページの生成に使用する
+
+CREATE TABLE IF NOT EXISTS api_endpoints (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    snapshot_id UUID NOT NULL REFERENCES source_snapshots(id) ON DELETE CASCADE,
+    file_id UUID NOT NULL REFERENCES files(id) ON DELETE CASCADE,
+    file_path TEXT NOT NULL,
+    kind VARCHAR(10) NOT NULL,                -- http / grpc
+    service TEXT NOT NULL DEFAULT '',         -- OpenAPI のタグ、または <package>.<service>
+    method VARCHAR(255) NOT NULL,             -- HTTP メソッドまたは RPC 名
+    path TEXT NOT NULL,
+    operation_id TEXT NOT NULL DEFAULT '',
+    summary TEXT NOT NULL DEFAULT '',
+    request_type TEXT NOT NULL DEFAULT '',
+    response_type TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT chk_api_endpoints_kind CHECK (kind IN ('http', 'grpc'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_endpoints_snapshot ON api_endpoints(snapshot_id);
+CREATE INDEX IF NOT EXISTS idx_api_endpoints_file ON api_endpoints(file_id);
+
+COMMENT ON TABLE api_endpoints IS 'OpenAPI（Swagger）・Protocol Buffers の定義から抽出したエンドポイント';
+COMMENT ON COLUMN api_endpoints.kind IS 'エンドポイントの種類（http: OpenAPI または google.api.http, grpc: service の RPC）';
+COMMENT ON COLUMN api_endpoints.service IS 'OpenAPI の最初のタグ、または <package>.<service>';
+COMMENT ON COLUMN api_endpoints.method IS 'HTTP メソッド（GET など）または RPC 名';
+COMMENT ON COLUMN api_endpoints.path IS 'HTTP のパス、または /<package>.<service>/<RPC名>';
+COMMENT ON COLUMN api_endpoints.request_type IS 'リクエストの型名（ストリーミングは stream を前置）';
+COMMENT ON COLUMN api_endpoints.response_type IS 'レスポンスの型名（ストリーミングは stream を前置）';
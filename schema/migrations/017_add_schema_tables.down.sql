@@ -0,0 +1,3 @@
+-- スキーマカタログのロールバック
+
+DROP TABLE IF EXISTS schema_tables;
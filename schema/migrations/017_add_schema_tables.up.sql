@@ -0,0 +1,19 @@
+-- インデックス化時にSQLのスキーマ定義・マイグレーションファイルから構築したテーブルのカタログをスナップショット単位で保持する
+-- ask でテーブル・カラム・インデックスについての質問に構造から回答するために使用する
+
+CREATE TABLE IF NOT EXISTS schema_tables (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    snapshot_id UUID NOT NULL REFERENCES source_snapshots(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,                       -- スキーマ修飾付き（public スキーマは省略）
+    comment TEXT NOT NULL DEFAULT '',
+    columns JSONB NOT NULL DEFAULT '[]'::jsonb,
+    indexes JSONB NOT NULL DEFAULT '[]'::jsonb,
+    source_paths TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_schema_tables_snapshot_name UNIQUE (snapshot_id, name)
+);
+
+COMMENT ON TABLE schema_tables IS 'SQLのスキーマ定義・マイグレーションファイルから構築したテーブルのカタログ';
+COMMENT ON COLUMN schema_tables.columns IS 'カラムの一覧（名前・型・NOT NULL・主キー・デフォルト値・参照先・コメント）';
+COMMENT ON COLUMN schema_tables.indexes IS 'インデックス・UNIQUE 制約の一覧（名前・対象・アクセスメソッド・部分インデックスの条件）';
+COMMENT ON COLUMN schema_tables.source_paths IS 'テーブルを定義・変更したファイル（適用順）';
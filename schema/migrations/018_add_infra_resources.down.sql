@@ -0,0 +1,3 @@
+-- デプロイ構成のロールバック
+
+DROP TABLE IF EXISTS infra_resources;
@@ -0,0 +1,25 @@
+-- インデックス化時に Dockerfile・Docker Compose・Kubernetes マニフェストから抽出したデプロイ構成をファイル単位で保持する
+-- デプロイ構成のWikiページの生成と、ask のインフラに関する質問のコンテキストに使用する
+
+CREATE TABLE IF NOT EXISTS infra_resources (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    snapshot_id UUID NOT NULL REFERENCES source_snapshots(id) ON DELETE CASCADE,
+    file_id UUID NOT NULL REFERENCES files(id) ON DELETE CASCADE,
+    file_path TEXT NOT NULL,
+    format VARCHAR(20) NOT NULL,              -- dockerfile / compose / kubernetes
+    kind VARCHAR(100) NOT NULL,               -- Dockerfile、service、Deployment など
+    name TEXT NOT NULL,
+    namespace TEXT NOT NULL DEFAULT '',
+    spec JSONB NOT NULL DEFAULT '{}'::jsonb,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT chk_infra_resources_format CHECK (format IN ('dockerfile', 'compose', 'kubernetes'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_infra_resources_snapshot ON infra_resources(snapshot_id);
+CREATE INDEX IF NOT EXISTS idx_infra_resources_file ON infra_resources(file_id);
+
+COMMENT ON TABLE infra_resources IS 'Dockerfile・Docker Compose・Kubernetes マニフェストから抽出したデプロイ構成';
+COMMENT ON COLUMN infra_resources.format IS '定義ファイルの形式（dockerfile, compose, kubernetes）';
+COMMENT ON COLUMN infra_resources.kind IS 'リソースの種類（Dockerfile、Docker Compose の service、Kubernetes の kind）';
+COMMENT ON COLUMN infra_resources.name IS 'リソース名（Dockerfile はファイルパス）';
+COMMENT ON COLUMN infra_resources.spec IS 'レプリカ数・Service の type とポート・コンテナ（イメージ・ポート・環境変数・リソース制限）・起動コマンド';
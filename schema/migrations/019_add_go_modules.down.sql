@@ -0,0 +1,3 @@
+-- Go モジュールのロールバック
+
+DROP TABLE IF EXISTS go_modules;
@@ -0,0 +1,17 @@
+-- インデックス化時に go.mod から抽出した Go モジュールをスナップショット単位で保持する
+-- プロダクト内の他のソースからのインポートパスを解決し、ソースをまたぐ依存関係（chunk_dependencies）を構築するために使用する
+
+CREATE TABLE IF NOT EXISTS go_modules (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    snapshot_id UUID NOT NULL REFERENCES source_snapshots(id) ON DELETE CASCADE,
+    module_path TEXT NOT NULL,
+    dir TEXT NOT NULL,                        -- go.mod を置いたディレクトリ（ルートは "."）
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_go_modules_snapshot_dir UNIQUE (snapshot_id, dir)
+);
+
+CREATE INDEX IF NOT EXISTS idx_go_modules_snapshot ON go_modules(snapshot_id);
+
+COMMENT ON TABLE go_modules IS 'スナップショットに含まれる Go モジュール（go.mod）';
+COMMENT ON COLUMN go_modules.module_path IS 'module ディレクティブのモジュールパス';
+COMMENT ON COLUMN go_modules.dir IS 'go.mod を置いたディレクトリ（リポジトリのルートは "."）';
@@ -0,0 +1,3 @@
+-- チャンクの重要度の内訳のロールバック
+
+DROP TABLE IF EXISTS chunk_importance;
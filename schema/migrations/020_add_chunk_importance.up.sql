@@ -0,0 +1,20 @@
+-- チャンクの重要度（importance_score）を、依存関係グラフのスコア・編集頻度のスコア・最終スコアの内訳とともに保持する
+-- chunk show などで重要度の根拠を説明するために使用する
+
+CREATE TABLE IF NOT EXISTS chunk_importance (
+    chunk_id UUID PRIMARY KEY REFERENCES chunks(id) ON DELETE CASCADE,
+    algorithm VARCHAR(20) NOT NULL,           -- pagerank / betweenness / in_degree
+    graph_score NUMERIC(5,4) NOT NULL,        -- 0.0000〜1.0000
+    edit_score NUMERIC(5,4) NOT NULL,         -- 0.0000〜1.0000
+    final_score NUMERIC(5,4) NOT NULL,        -- 0.0000〜1.0000（chunks.importance_score と同じ値）
+    edit_count INTEGER NOT NULL DEFAULT 0,
+    calculated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT chk_chunk_importance_algorithm CHECK (algorithm IN ('pagerank', 'betweenness', 'in_degree'))
+);
+
+COMMENT ON TABLE chunk_importance IS 'チャンクの重要度（importance_score）の内訳';
+COMMENT ON COLUMN chunk_importance.algorithm IS 'グラフスコアの算出に使用したアルゴリズム（pagerank, betweenness, in_degree）';
+COMMENT ON COLUMN chunk_importance.graph_score IS '依存関係グラフから求めたスコア';
+COMMENT ON COLUMN chunk_importance.edit_score IS '編集頻度（スナップショット間で内容が変更された回数）から求めたスコア';
+COMMENT ON COLUMN chunk_importance.final_score IS 'グラフスコアと編集頻度スコアの加重和';
+COMMENT ON COLUMN chunk_importance.edit_count IS 'スナップショット間で内容が変更された回数';
@@ -0,0 +1,3 @@
+-- Embedding の生成に失敗したチャンクの記録のロールバック
+
+DROP TABLE IF EXISTS embedding_failures;
@@ -0,0 +1,16 @@
+-- 再試行とバッチの分割でも Embedding を生成できなかったチャンクを記録する
+-- index retry-embeddings で記録したチャンクの Embedding を再生成するために使用する
+
+CREATE TABLE IF NOT EXISTS embedding_failures (
+    chunk_id UUID PRIMARY KEY REFERENCES chunks(id) ON DELETE CASCADE,
+    error TEXT NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 1,
+    first_failed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_failed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON TABLE embedding_failures IS 'Embedding を生成できなかったチャンク（index retry-embeddings で再試行する）';
+COMMENT ON COLUMN embedding_failures.error IS '最後に失敗したときのエラー';
+COMMENT ON COLUMN embedding_failures.attempts IS '失敗した回数（インデックス化と再試行の合計）';
+COMMENT ON COLUMN embedding_failures.first_failed_at IS '最初に失敗した日時';
+COMMENT ON COLUMN embedding_failures.last_failed_at IS '最後に失敗した日時';
@@ -0,0 +1,3 @@
+-- チャンクの Embedding 入力の切り詰めフラグのロールバック
+
+ALTER TABLE chunks DROP COLUMN IF EXISTS embedding_truncated;
@@ -0,0 +1,6 @@
+-- Embeddingモデルの最大入力トークン数を超えるチャンクは、シグネチャ・ドキュメントコメントを残して
+-- 切り詰めた入力を embedding_context に保存し、切り詰めたことを記録する
+
+ALTER TABLE chunks ADD COLUMN IF NOT EXISTS embedding_truncated BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN chunks.embedding_truncated IS 'Embeddingモデルの最大入力トークン数に合わせて embedding_context を切り詰めたか';
@@ -0,0 +1,3 @@
+-- チャンクの見出しの階層のロールバック
+
+ALTER TABLE chunks DROP COLUMN IF EXISTS breadcrumb;
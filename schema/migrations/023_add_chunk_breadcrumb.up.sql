@@ -0,0 +1,5 @@
+-- Markdownのチャンクに見出しの階層（H1 > H2 > H3）を記録し、Embeddingの入力と検索結果・回答の参照ソースの表示に使用する
+
+ALTER TABLE chunks ADD COLUMN IF NOT EXISTS breadcrumb TEXT;
+
+COMMENT ON COLUMN chunks.breadcrumb IS 'Markdownのチャンクが属する見出しの階層（H1 > H2 > H3 を " > " で連結）';
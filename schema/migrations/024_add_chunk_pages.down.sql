@@ -0,0 +1,4 @@
+-- チャンクのページ番号のロールバック
+
+ALTER TABLE chunks DROP COLUMN IF EXISTS page_end;
+ALTER TABLE chunks DROP COLUMN IF EXISTS page_start;
@@ -0,0 +1,7 @@
+-- PDF・Office文書（docx/xlsx）から抽出したチャンクにページ番号を記録し、参照ソースの表示に使用する
+
+ALTER TABLE chunks ADD COLUMN IF NOT EXISTS page_start INTEGER;
+ALTER TABLE chunks ADD COLUMN IF NOT EXISTS page_end INTEGER;
+
+COMMENT ON COLUMN chunks.page_start IS 'PDF・Office文書から抽出したチャンクの開始ページ（xlsx はシートの番号）';
+COMMENT ON COLUMN chunks.page_end IS 'PDF・Office文書から抽出したチャンクの終了ページ';
@@ -0,0 +1,5 @@
+-- 議論のメタデータのロールバック
+
+DROP TABLE IF EXISTS discussions;
+
+COMMENT ON COLUMN sources.source_type IS 'ソースタイプ（git/confluence/pdf/redmine/notion/local）';
@@ -0,0 +1,32 @@
+-- GitHub・GitLab の Issue・Pull Request（Merge Request）の議論をソースとしてインデックス化し、議論で言及されたファイルパスを保持する
+-- 質問応答で、関連コードのファイルに言及した議論（変更の経緯）を示すために使用する
+
+CREATE TABLE IF NOT EXISTS discussions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    snapshot_id UUID NOT NULL REFERENCES source_snapshots(id) ON DELETE CASCADE,
+    file_id UUID NOT NULL REFERENCES files(id) ON DELETE CASCADE,
+    file_path TEXT NOT NULL,
+    kind VARCHAR(20) NOT NULL,                -- issue / pull_request
+    number INTEGER NOT NULL,
+    title TEXT NOT NULL,
+    state VARCHAR(20) NOT NULL DEFAULT '',    -- open / closed / merged
+    url TEXT NOT NULL DEFAULT '',
+    summary TEXT NOT NULL DEFAULT '',
+    paths TEXT[] NOT NULL DEFAULT '{}',
+    last_activity_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_discussions_file UNIQUE (file_id),
+    CONSTRAINT chk_discussions_kind CHECK (kind IN ('issue', 'pull_request'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_discussions_snapshot ON discussions(snapshot_id);
+CREATE INDEX IF NOT EXISTS idx_discussions_paths ON discussions USING GIN (paths);
+
+COMMENT ON TABLE discussions IS 'GitHub・GitLab の Issue・Pull Request（Merge Request）の議論から抽出したメタデータ';
+COMMENT ON COLUMN discussions.kind IS '議論の種類（issue, pull_request: GitLab の Merge Request を含む）';
+COMMENT ON COLUMN discussions.number IS 'Issue・Pull Request の番号（GitLab は iid）';
+COMMENT ON COLUMN discussions.summary IS '説明文の冒頭';
+COMMENT ON COLUMN discussions.paths IS '議論で言及されたファイルパス（変更ファイル・レビューコメントの対象・本文中のパス）';
+COMMENT ON COLUMN discussions.last_activity_at IS '議論の最終更新日時';
+
+COMMENT ON COLUMN sources.source_type IS 'ソースタイプ（git/confluence/pdf/redmine/notion/local/discussion）';
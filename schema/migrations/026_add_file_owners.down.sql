@@ -0,0 +1,3 @@
+-- ファイルの担当者のロールバック
+
+ALTER TABLE files DROP COLUMN IF EXISTS owners;
@@ -0,0 +1,5 @@
+-- CODEOWNERS から判定したファイルの担当者を記録し、回答の参照ソース・Wiki・品質改善アクションの担当者のヒントに使用する
+
+ALTER TABLE files ADD COLUMN IF NOT EXISTS owners TEXT[] NOT NULL DEFAULT '{}';
+
+COMMENT ON COLUMN files.owners IS 'CODEOWNERS で定義された担当者（チーム・ユーザー、定義がない場合は空）';
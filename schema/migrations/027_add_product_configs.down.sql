@@ -0,0 +1,3 @@
+-- プロダクトごとの設定のロールバック
+
+DROP TABLE IF EXISTS product_configs;
@@ -0,0 +1,13 @@
+-- プロダクトごとの設定（カバレッジアラートの閾値など）をキーと値の組で保持し、`dev-rag product config set` で編集する
+
+CREATE TABLE IF NOT EXISTS product_configs (
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    key VARCHAR(255) NOT NULL,                -- 設定キー（例: alert.min_coverage.tests）
+    value TEXT NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (product_id, key)
+);
+
+COMMENT ON TABLE product_configs IS 'プロダクトごとの設定（カバレッジアラートの閾値など、キーと値の組）';
+COMMENT ON COLUMN product_configs.key IS '設定キー（alert.min_coverage.<ドメイン>, alert.max_unindexed_important_files など）';
+COMMENT ON COLUMN product_configs.value IS '設定値（キーごとに検証した文字列）';
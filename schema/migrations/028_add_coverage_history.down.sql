@@ -0,0 +1,3 @@
+-- カバレッジの履歴のロールバック
+
+DROP TABLE IF EXISTS coverage_history;
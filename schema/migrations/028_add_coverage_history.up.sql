@@ -0,0 +1,22 @@
+-- インデックス化のたびにスナップショットのドメイン別カバレッジを記録し、`dev-rag coverage trend` でカバレッジの推移を表示する
+
+CREATE TABLE IF NOT EXISTS coverage_history (
+    snapshot_id UUID NOT NULL REFERENCES source_snapshots(id) ON DELETE CASCADE,
+    domain VARCHAR(50) NOT NULL,              -- ドメイン分類（未分類は unknown）
+    total_files INTEGER NOT NULL,
+    indexed_files INTEGER NOT NULL,
+    indexed_chunks INTEGER NOT NULL,
+    coverage_rate NUMERIC(5, 2) NOT NULL,     -- カバレッジ率（%）
+    recorded_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (snapshot_id, domain)
+);
+
+CREATE INDEX IF NOT EXISTS idx_coverage_history_recorded_at ON coverage_history(recorded_at);
+
+COMMENT ON TABLE coverage_history IS 'スナップショットごとのドメイン別カバレッジの履歴（カバレッジの推移の表示に使用）';
+COMMENT ON COLUMN coverage_history.domain IS 'ドメイン分類（code, tests, ops, infra, architecture, 未分類は unknown）';
+COMMENT ON COLUMN coverage_history.total_files IS 'スナップショットのファイル数（インデックス対象外を含む）';
+COMMENT ON COLUMN coverage_history.indexed_files IS 'インデックス済みのファイル数';
+COMMENT ON COLUMN coverage_history.indexed_chunks IS 'インデックス済みのチャンク数';
+COMMENT ON COLUMN coverage_history.coverage_rate IS 'カバレッジ率（%）';
+COMMENT ON COLUMN coverage_history.recorded_at IS '記録日時（インデックス完了日時）';
@@ -0,0 +1,3 @@
+-- LLMプロンプトテンプレートの上書きのロールバック
+
+DROP TABLE IF EXISTS prompt_templates;
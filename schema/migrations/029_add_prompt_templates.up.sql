@@ -0,0 +1,14 @@
+-- LLMプロンプトテンプレートの上書きを保存する
+-- `dev-rag prompt set` で保存したテンプレートは、PROMPT_TEMPLATE_DIR のファイル・組み込みのテンプレートより優先する
+
+CREATE TABLE IF NOT EXISTS prompt_templates (
+    name VARCHAR(100) PRIMARY KEY,            -- テンプレート名（例: summary.file）
+    version VARCHAR(10) NOT NULL,             -- 生成物に記録するバージョン
+    body TEXT NOT NULL,                       -- text/template 形式のテンプレート本文
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON TABLE prompt_templates IS 'LLMプロンプトテンプレートの上書き（ファイル・組み込みのテンプレートより優先する）';
+COMMENT ON COLUMN prompt_templates.name IS 'テンプレート名（summary.file, wiki.section, ask, quality.actions など）';
+COMMENT ON COLUMN prompt_templates.version IS '生成物（要約・アクション・Wiki・回答）に記録するプロンプトのバージョン';
+COMMENT ON COLUMN prompt_templates.body IS 'text/template 形式のテンプレート本文';
@@ -0,0 +1,3 @@
+-- LLMの応答のキャッシュのロールバック
+
+DROP TABLE IF EXISTS llm_cache;
@@ -0,0 +1,24 @@
+-- LLMの応答のキャッシュを保存する
+-- 入力が変わらないファイルの再インデックスや Wiki の再生成で、同じ応答に再び課金されないようにする
+
+CREATE TABLE IF NOT EXISTS llm_cache (
+    key CHAR(64) PRIMARY KEY,                 -- プロンプトテンプレート・モデル・プロンプトのハッシュ
+    prompt_name VARCHAR(100) NOT NULL,        -- プロンプトテンプレート名（例: summary.file）
+    prompt_version VARCHAR(10) NOT NULL,
+    model VARCHAR(100) NOT NULL,
+    response TEXT NOT NULL,
+    hit_count INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_hit_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_llm_cache_prompt ON llm_cache(prompt_name, prompt_version);
+CREATE INDEX IF NOT EXISTS idx_llm_cache_created_at ON llm_cache(created_at);
+
+COMMENT ON TABLE llm_cache IS 'LLMの応答のキャッシュ（同じ入力の要約・Wikiページの生成で再び課金されないようにする）';
+COMMENT ON COLUMN llm_cache.key IS 'プロンプトテンプレートの名前・バージョン、モデル、最大出力トークン数、展開したプロンプトの SHA-256';
+COMMENT ON COLUMN llm_cache.prompt_name IS 'プロンプトテンプレート名（summary.file, wiki.section など）';
+COMMENT ON COLUMN llm_cache.prompt_version IS 'プロンプトテンプレートのバージョン';
+COMMENT ON COLUMN llm_cache.model IS '応答を生成したモデル名';
+COMMENT ON COLUMN llm_cache.hit_count IS 'キャッシュした応答を使用した回数';
+COMMENT ON COLUMN llm_cache.last_hit_at IS '最後にキャッシュした応答を使用した日時';
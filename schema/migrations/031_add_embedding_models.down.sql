@@ -0,0 +1,3 @@
+-- Embeddingモデルの登録のロールバック
+
+DROP TABLE IF EXISTS embedding_models;
@@ -0,0 +1,26 @@
+-- 使用を許可するEmbeddingモデルと次元数を登録する
+-- 設定したモデルとベクトルカラムの次元数が一致しない場合は起動時にエラーとし、不正なベクトルを保存しないようにする
+
+CREATE TABLE IF NOT EXISTS embedding_models (
+    model VARCHAR(100) PRIMARY KEY,            -- EMBEDDING_MODEL に指定するモデル名（Azure の場合はデプロイメント名）
+    provider VARCHAR(50) NOT NULL,             -- openai, azure, vertex, ollama
+    dimension INTEGER NOT NULL CHECK (dimension > 0),
+    variable_dimension BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON TABLE embedding_models IS '使用を許可するEmbeddingモデルと出力するベクトルの次元数';
+COMMENT ON COLUMN embedding_models.dimension IS 'モデルが出力するベクトルの次元数（variable_dimension の場合は最大の次元数）';
+COMMENT ON COLUMN embedding_models.variable_dimension IS 'dimension 以下の任意の次元数を指定して出力できるか（text-embedding-3 系など）';
+
+INSERT INTO embedding_models (model, provider, dimension, variable_dimension) VALUES
+    ('text-embedding-3-small', 'openai', 1536, TRUE),
+    ('text-embedding-3-large', 'openai', 3072, TRUE),
+    ('text-embedding-ada-002', 'openai', 1536, FALSE),
+    ('text-embedding-005', 'vertex', 768, TRUE),
+    ('text-multilingual-embedding-002', 'vertex', 768, TRUE),
+    ('gemini-embedding-001', 'vertex', 3072, TRUE),
+    ('nomic-embed-text', 'ollama', 768, FALSE),
+    ('mxbai-embed-large', 'ollama', 1024, FALSE),
+    ('bge-m3', 'ollama', 1024, FALSE)
+ON CONFLICT (model) DO NOTHING;
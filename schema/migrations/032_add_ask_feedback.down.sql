@@ -0,0 +1,4 @@
+-- 回答と評価のロールバック
+
+DROP TABLE IF EXISTS ask_feedback;
+DROP TABLE IF EXISTS ask_answers;
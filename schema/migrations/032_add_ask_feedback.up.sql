@@ -0,0 +1,37 @@
+-- チャット（Slack など）で回答した質問と、回答へのリアクションによる評価を保存する
+-- 評価の低い回答を見直し、プロンプトや検索の改善に使う
+
+CREATE TABLE IF NOT EXISTS ask_answers (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    origin VARCHAR(20) NOT NULL,              -- 回答した経路（slack）
+    channel_id VARCHAR(100) NOT NULL,         -- 回答を投稿したチャンネル
+    message_ts VARCHAR(50) NOT NULL,          -- 回答のメッセージの識別子（Slack の ts）
+    user_id VARCHAR(100) NOT NULL,            -- 質問したユーザー
+    query TEXT NOT NULL,
+    answer TEXT NOT NULL,
+    refused BOOLEAN NOT NULL DEFAULT FALSE,
+    prompt_version VARCHAR(10) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_ask_answers_message UNIQUE (origin, channel_id, message_ts)
+);
+
+CREATE INDEX IF NOT EXISTS idx_ask_answers_product ON ask_answers(product_id, created_at);
+
+CREATE TABLE IF NOT EXISTS ask_feedback (
+    answer_id UUID NOT NULL REFERENCES ask_answers(id) ON DELETE CASCADE,
+    user_id VARCHAR(100) NOT NULL,            -- 評価したユーザー
+    rating SMALLINT NOT NULL CHECK (rating IN (-1, 1)),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (answer_id, user_id)
+);
+
+COMMENT ON TABLE ask_answers IS 'チャットで回答した質問（リアクションによる評価の対象）';
+COMMENT ON COLUMN ask_answers.origin IS '回答した経路（slack）';
+COMMENT ON COLUMN ask_answers.channel_id IS '回答を投稿したチャンネルのID';
+COMMENT ON COLUMN ask_answers.message_ts IS '回答のメッセージの識別子（Slack のメッセージの ts）';
+COMMENT ON COLUMN ask_answers.user_id IS '質問したユーザーのID';
+COMMENT ON COLUMN ask_answers.refused IS 'ガードレールにより回答を拒否したかどうか';
+COMMENT ON COLUMN ask_answers.prompt_version IS '回答の生成に使用したプロンプトテンプレートのバージョン';
+COMMENT ON TABLE ask_feedback IS '回答へのユーザーの評価（👍 は 1、👎 は -1）';
+COMMENT ON COLUMN ask_feedback.rating IS '評価（1: 役に立った、-1: 役に立たなかった）';
@@ -0,0 +1,3 @@
+-- 追加のEmbeddingモデルのEmbeddingのロールバック
+
+DROP TABLE IF EXISTS chunk_embeddings;
@@ -0,0 +1,17 @@
+-- 主モデル（embeddings）以外のEmbeddingモデルで生成したチャンクのEmbeddingを保存する
+-- 複数のモデルのEmbeddingを併存させ、検索時にモデルを選んで検索精度を比較できるようにする
+
+CREATE TABLE IF NOT EXISTS chunk_embeddings (
+    chunk_id UUID NOT NULL REFERENCES chunks(id) ON DELETE CASCADE,
+    model VARCHAR(100) NOT NULL REFERENCES embedding_models(model),
+    vector VECTOR NOT NULL,                   -- 次元数はモデルごとに異なるため固定しない
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (chunk_id, model)
+);
+
+-- 次元数が固定されないためHNSWインデックスは作成できず、モデルで絞り込んだ全件の距離計算で検索する
+CREATE INDEX IF NOT EXISTS idx_chunk_embeddings_model ON chunk_embeddings(model);
+
+COMMENT ON TABLE chunk_embeddings IS '追加のEmbeddingモデルで生成したチャンクのEmbedding（主モデルは embeddings）';
+COMMENT ON COLUMN chunk_embeddings.model IS 'Embeddingの生成に使用したモデル（embedding_models に登録済み）';
+COMMENT ON COLUMN chunk_embeddings.vector IS 'Embeddingベクトル（次元数はモデルごとに異なる）';
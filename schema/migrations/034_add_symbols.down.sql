@@ -0,0 +1,3 @@
+-- シンボルの定義位置のロールバック
+
+DROP TABLE IF EXISTS symbols;
@@ -0,0 +1,33 @@
+-- チャンクのメタデータ（chunk_name・chunk_type）から関数・メソッド・型などのシンボルの定義位置を抽出する
+-- 「関数 X はどこで定義されているか」にベクトル検索を使わず完全一致で答えるためのもの
+
+CREATE TABLE IF NOT EXISTS symbols (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    snapshot_id UUID NOT NULL REFERENCES source_snapshots(id) ON DELETE CASCADE,
+    chunk_id UUID NOT NULL REFERENCES chunks(id) ON DELETE CASCADE,
+    file_id UUID NOT NULL REFERENCES files(id) ON DELETE CASCADE,
+    file_path TEXT NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    kind VARCHAR(50) NOT NULL,                -- function / method / struct / interface など（chunks.chunk_type）
+    parent_name VARCHAR(255) NOT NULL DEFAULT '', -- メソッドのレシーバの型名など（ポインタの * は除く）
+    start_line INTEGER NOT NULL,
+    end_line INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_symbols_snapshot ON symbols(snapshot_id);
+CREATE INDEX IF NOT EXISTS idx_symbols_lower_name ON symbols(LOWER(name));
+
+COMMENT ON TABLE symbols IS 'チャンクのメタデータから抽出したシンボル（関数・メソッド・型など）の定義位置';
+COMMENT ON COLUMN symbols.name IS 'シンボル名（chunks.chunk_name）';
+COMMENT ON COLUMN symbols.kind IS 'シンボルの種類（chunks.chunk_type）';
+COMMENT ON COLUMN symbols.parent_name IS '所属する型名など（chunks.parent_name、ポインタの * は除く）';
+
+-- インデックス化済みのチャンクから抽出する（以降はインデックス化のたびにスナップショット単位で作り直す）
+INSERT INTO symbols (snapshot_id, chunk_id, file_id, file_path, name, kind, parent_name, start_line, end_line)
+SELECT f.snapshot_id, c.id, f.id, f.path, c.chunk_name, c.chunk_type, LTRIM(COALESCE(c.parent_name, ''), '*'), c.start_line, c.end_line
+FROM chunks c
+INNER JOIN files f ON c.file_id = f.id
+WHERE c.level = 2
+  AND c.chunk_name IS NOT NULL AND c.chunk_name <> ''
+  AND c.chunk_type IS NOT NULL AND c.chunk_type <> 'section';
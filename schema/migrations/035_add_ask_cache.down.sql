@@ -0,0 +1,3 @@
+-- 質問応答の回答のキャッシュのロールバック
+
+DROP TABLE IF EXISTS ask_cache;
@@ -0,0 +1,25 @@
+-- 質問応答の回答のキャッシュ
+
+-- ask_cacheテーブル: 質問応答の回答のキャッシュ（正規化した質問文・検索範囲・プロンプトテンプレートのバージョンをキーとする）
+CREATE TABLE IF NOT EXISTS ask_cache (
+    key CHAR(64) PRIMARY KEY,                 -- 検索範囲・正規化した質問文・プロンプトのバージョン・モデルなどのハッシュ
+    product_id UUID REFERENCES products(id) ON DELETE CASCADE,
+    snapshot_id UUID REFERENCES source_snapshots(id) ON DELETE CASCADE, -- ref/snapshot 指定時のみ
+    scope_snapshot_ids UUID[] NOT NULL,       -- 保存時に検索対象だったスナップショット
+    prompt_version VARCHAR(10) NOT NULL,
+    question TEXT NOT NULL,                   -- 正規化した質問文
+    result JSONB NOT NULL,
+    hit_count INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_hit_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_ask_cache_product ON ask_cache(product_id);
+CREATE INDEX IF NOT EXISTS idx_ask_cache_created_at ON ask_cache(created_at);
+
+COMMENT ON TABLE ask_cache IS '質問応答の回答のキャッシュ（よくある質問で検索とLLMの呼び出しを繰り返さないようにする）';
+COMMENT ON COLUMN ask_cache.key IS '検索範囲・正規化した質問文・プロンプトテンプレートのバージョン・モデル・検索条件の SHA-256';
+COMMENT ON COLUMN ask_cache.scope_snapshot_ids IS '保存時に検索対象だったスナップショット（再インデックスで変わった回答は使用しない）';
+COMMENT ON COLUMN ask_cache.question IS '正規化した質問文（小文字化・空白の統一・末尾の句読点の除去）';
+COMMENT ON COLUMN ask_cache.result IS '回答・参照したソース・引用の検証結果（JSON）';
+COMMENT ON COLUMN ask_cache.hit_count IS 'キャッシュした回答を使用した回数';
@@ -0,0 +1,3 @@
+-- プロダクト全体の要約のロールバック
+
+DROP TABLE IF EXISTS product_summaries;
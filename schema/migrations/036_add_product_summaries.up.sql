@@ -0,0 +1,20 @@
+-- プロダクト全体の要約（目的・技術スタック・主要なサービス）とEmbedding
+-- プロダクトを指定しない質問の振り分けと、products.description の自動設定に使用する
+
+-- product_summariesテーブル: 各ソースのアーキテクチャ要約から生成したプロダクト全体の要約
+CREATE TABLE IF NOT EXISTS product_summaries (
+    product_id UUID PRIMARY KEY REFERENCES products(id) ON DELETE CASCADE,
+    content TEXT NOT NULL,                    -- 目的・技術スタック・主要なサービス
+    description TEXT NOT NULL,                -- 要約の冒頭の1文（products.description の自動設定に使用）
+    source_hash CHAR(64) NOT NULL,            -- 入力としたアーキテクチャ要約の content_hash から計算したハッシュ
+    prompt_version VARCHAR(10) NOT NULL,
+    vector VECTOR NOT NULL,                   -- 要約のEmbedding（次元数はモデルごとに異なる）
+    model VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON TABLE product_summaries IS 'プロダクト全体の要約（インデックス化のたびに各ソースのアーキテクチャ要約から生成し、質問の振り分けに使用する）';
+COMMENT ON COLUMN product_summaries.description IS '要約の冒頭の1文（products.description が未設定または前回の自動設定のままの場合に設定する）';
+COMMENT ON COLUMN product_summaries.source_hash IS '入力としたアーキテクチャ要約の content_hash から計算したハッシュ（変わらない場合は再生成しない）';
+COMMENT ON COLUMN product_summaries.vector IS '要約のEmbedding（振り分けでは現在の主モデルと同じ model の行のみ使用する）';
@@ -0,0 +1,4 @@
+-- テストカバレッジのロールバック
+
+DROP TABLE IF EXISTS chunk_test_coverage;
+DROP TABLE IF EXISTS file_test_coverage;
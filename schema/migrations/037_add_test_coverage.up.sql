@@ -0,0 +1,28 @@
+-- テストのカバレッジレポート（Go の coverage.out・LCOV）から取り込んだファイル・チャンクごとのカバレッジ
+-- ホットスポットのレポートと、テストされていない箇所についての質問への回答に使用する
+
+-- file_test_coverageテーブル: スナップショットのファイルごとのテストカバレッジ
+CREATE TABLE IF NOT EXISTS file_test_coverage (
+    file_id UUID PRIMARY KEY REFERENCES files(id) ON DELETE CASCADE,
+    snapshot_id UUID NOT NULL REFERENCES source_snapshots(id) ON DELETE CASCADE,
+    statements INTEGER NOT NULL,              -- 計測対象のステートメント数（LCOV は行数）
+    covered_statements INTEGER NOT NULL,      -- テストで実行されたステートメント数
+    format VARCHAR(10) NOT NULL,              -- go / lcov
+    imported_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT chk_file_test_coverage_format CHECK (format IN ('go', 'lcov'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_file_test_coverage_snapshot ON file_test_coverage(snapshot_id);
+
+COMMENT ON TABLE file_test_coverage IS 'カバレッジレポートから取り込んだファイルごとのテストカバレッジ（スナップショット単位で置き換える）';
+COMMENT ON COLUMN file_test_coverage.statements IS '計測対象のステートメント数（LCOV は計測対象の行数）';
+COMMENT ON COLUMN file_test_coverage.covered_statements IS 'テストで1回以上実行されたステートメント数';
+
+-- chunk_test_coverageテーブル: 関数・クラス単位のチャンクごとのテストカバレッジ
+CREATE TABLE IF NOT EXISTS chunk_test_coverage (
+    chunk_id UUID PRIMARY KEY REFERENCES chunks(id) ON DELETE CASCADE,
+    statements INTEGER NOT NULL,
+    covered_statements INTEGER NOT NULL
+);
+
+COMMENT ON TABLE chunk_test_coverage IS '関数・クラス単位のチャンクごとのテストカバレッジ（計測単位の開始行がチャンクの行範囲に含まれるものを集計）';
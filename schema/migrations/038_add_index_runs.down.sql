@@ -0,0 +1,3 @@
+-- インデックス化の実行履歴のロールバック
+
+DROP TABLE IF EXISTS index_runs;
@@ -0,0 +1,36 @@
+-- インデックス化の実行履歴（ソース・参照・所要時間・処理件数・失敗件数・トークン使用量）
+-- ログに残らない過去の実行を run list・run show と HTTP API で確認するために使用する
+
+-- index_runsテーブル: インデックス化の実行ごとの結果
+CREATE TABLE IF NOT EXISTS index_runs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    source_id UUID NOT NULL REFERENCES sources(id) ON DELETE CASCADE,
+    snapshot_id UUID REFERENCES source_snapshots(id) ON DELETE SET NULL,
+    ref VARCHAR(255),                          -- インデックス化した参照（ブランチ・タグ、参照を持たないソースでは NULL）
+    version_identifier VARCHAR(255),           -- コミットハッシュなど（ドキュメントの取得前に失敗した場合は NULL）
+    status VARCHAR(20) NOT NULL DEFAULT 'running', -- running / succeeded / failed
+    force_init BOOLEAN NOT NULL DEFAULT FALSE,
+    files_processed INTEGER NOT NULL DEFAULT 0,
+    chunks INTEGER NOT NULL DEFAULT 0,
+    failed_files INTEGER NOT NULL DEFAULT 0,
+    failed_embeddings INTEGER NOT NULL DEFAULT 0,
+    input_tokens BIGINT NOT NULL DEFAULT 0,
+    output_tokens BIGINT NOT NULL DEFAULT 0,
+    cost_usd DOUBLE PRECISION NOT NULL DEFAULT 0,
+    error TEXT,
+    started_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMP,
+    duration_ms BIGINT,
+    CONSTRAINT chk_index_runs_status CHECK (status IN ('running', 'succeeded', 'failed'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_index_runs_product_started ON index_runs(product_id, started_at DESC);
+CREATE INDEX IF NOT EXISTS idx_index_runs_source_started ON index_runs(source_id, started_at DESC);
+
+COMMENT ON TABLE index_runs IS 'インデックス化の実行履歴（実行ごとの処理件数・失敗件数・トークン使用量）';
+COMMENT ON COLUMN index_runs.status IS '実行状態（running: 実行中または中断、succeeded: 成功、failed: 失敗）';
+COMMENT ON COLUMN index_runs.failed_files IS 'チャンク化・保存に失敗したファイル数';
+COMMENT ON COLUMN index_runs.failed_embeddings IS 'Embeddingの生成・保存に失敗したチャンク数（embedding_failures に記録したもの）';
+COMMENT ON COLUMN index_runs.input_tokens IS '実行中に消費した入力トークン数（Embedding・分類など、インデックス化後の要約生成は含まない）';
+COMMENT ON COLUMN index_runs.cost_usd IS '単価表から算出した推定コスト（USD）';
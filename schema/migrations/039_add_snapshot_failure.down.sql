@@ -0,0 +1,4 @@
+-- スナップショットの失敗の記録のロールバック
+
+ALTER TABLE source_snapshots DROP COLUMN IF EXISTS failure_reason;
+ALTER TABLE source_snapshots DROP COLUMN IF EXISTS failed_at;
@@ -0,0 +1,8 @@
+-- インデックス化に失敗・中断したスナップショットの記録
+-- 途中までのファイルを残したまま indexed = FALSE で放置されたスナップショットと区別し、再実行時の再開に使用する
+
+ALTER TABLE source_snapshots ADD COLUMN failed_at TIMESTAMP;     -- インデックス化に失敗・中断した日時（完了時に NULL に戻す）
+ALTER TABLE source_snapshots ADD COLUMN failure_reason TEXT;     -- 失敗・中断の理由
+
+COMMENT ON COLUMN source_snapshots.failed_at IS 'インデックス化に失敗・中断した日時（完了時に NULL に戻す）';
+COMMENT ON COLUMN source_snapshots.failure_reason IS 'インデックス化に失敗・中断した理由';
@@ -0,0 +1,11 @@
+-- スナップショットの状態のロールバック
+
+ALTER TABLE source_snapshots ADD COLUMN indexed BOOLEAN NOT NULL DEFAULT FALSE;
+UPDATE source_snapshots SET indexed = (status = 'indexed');
+CREATE INDEX IF NOT EXISTS idx_source_snapshots_indexed ON source_snapshots(indexed) WHERE indexed = TRUE;
+COMMENT ON COLUMN source_snapshots.indexed IS 'インデックス完了フラグ';
+
+DROP INDEX IF EXISTS idx_source_snapshots_status;
+ALTER TABLE source_snapshots DROP CONSTRAINT IF EXISTS chk_source_snapshots_status;
+ALTER TABLE source_snapshots DROP COLUMN IF EXISTS indexing_started_at;
+ALTER TABLE source_snapshots DROP COLUMN IF EXISTS status;
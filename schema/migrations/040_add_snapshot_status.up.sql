@@ -0,0 +1,24 @@
+-- スナップショットの状態（pending / indexing / indexed / failed）
+-- indexed フラグでは区別できなかった「インデックス化中のまま停止した実行」と「失敗・中断した実行」を区別するために使用する
+
+ALTER TABLE source_snapshots ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'pending'; -- pending / indexing / indexed / failed
+ALTER TABLE source_snapshots ADD COLUMN indexing_started_at TIMESTAMP;                -- 最後にインデックス化を開始した日時
+
+-- 既存のスナップショットの状態を indexed フラグと失敗の記録から設定する
+UPDATE source_snapshots
+SET status = CASE
+        WHEN indexed THEN 'indexed'
+        WHEN failed_at IS NOT NULL THEN 'failed'
+        ELSE 'pending'
+    END;
+
+ALTER TABLE source_snapshots ADD CONSTRAINT chk_source_snapshots_status
+    CHECK (status IN ('pending', 'indexing', 'indexed', 'failed'));
+
+DROP INDEX IF EXISTS idx_source_snapshots_indexed;
+ALTER TABLE source_snapshots DROP COLUMN indexed;
+
+CREATE INDEX IF NOT EXISTS idx_source_snapshots_status ON source_snapshots(status) WHERE status = 'indexed';
+
+COMMENT ON COLUMN source_snapshots.status IS 'インデックス化の状態（pending: 未着手、indexing: インデックス化中、indexed: 完了、failed: 失敗・中断）';
+COMMENT ON COLUMN source_snapshots.indexing_started_at IS '最後にインデックス化を開始した日時';
@@ -0,0 +1,3 @@
+-- ファイルの失敗の記録のロールバック
+
+DROP TABLE IF EXISTS file_failures;
@@ -0,0 +1,25 @@
+-- インデックス化に失敗したファイル（ファイルの保存・チャンク化・チャンクの保存の失敗）を記録する
+-- ログに残るだけで失われていた失敗を failures list で確認し、failures retry で記録した内容から再試行するために使用する
+
+CREATE TABLE IF NOT EXISTS file_failures (
+    snapshot_id UUID NOT NULL REFERENCES source_snapshots(id) ON DELETE CASCADE,
+    file_path TEXT NOT NULL,
+    stage VARCHAR(20) NOT NULL,          -- create / chunk / store
+    error TEXT NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 1,
+    content TEXT NOT NULL,               -- 再試行に使用するファイルの内容
+    content_hash VARCHAR(64) NOT NULL,
+    size BIGINT NOT NULL DEFAULT 0,
+    first_failed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_failed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (snapshot_id, file_path),
+    CONSTRAINT chk_file_failures_stage CHECK (stage IN ('create', 'chunk', 'store'))
+);
+
+COMMENT ON TABLE file_failures IS 'インデックス化に失敗したファイル（failures retry で再試行する）';
+COMMENT ON COLUMN file_failures.stage IS '失敗した段階（create: ファイルの保存、chunk: チャンク化、store: チャンクの保存）';
+COMMENT ON COLUMN file_failures.error IS '最後に失敗したときのエラー';
+COMMENT ON COLUMN file_failures.attempts IS '失敗した回数（インデックス化の再開と再試行の合計）';
+COMMENT ON COLUMN file_failures.content IS '再試行に使用するファイルの内容';
+COMMENT ON COLUMN file_failures.first_failed_at IS '最初に失敗した日時';
+COMMENT ON COLUMN file_failures.last_failed_at IS '最後に失敗した日時';
@@ -0,0 +1,3 @@
+-- ファイルの内容の保存のロールバック
+
+DROP TABLE IF EXISTS file_blobs;
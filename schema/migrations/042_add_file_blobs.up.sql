@@ -0,0 +1,15 @@
+-- ファイルの内容を content_hash ごとに1回だけ保存する（コンテンツアドレス方式）
+-- chunk show・file show での原文の表示と、リポジトリを再クローンせずに行う再チャンク化に使用する
+-- 参照する files の行がなくなった内容は db gc-blobs で削除する（スナップショット・プロダクトの削除に追従する）
+
+CREATE TABLE IF NOT EXISTS file_blobs (
+    content_hash VARCHAR(64) PRIMARY KEY,
+    content BYTEA NOT NULL,              -- バイナリ（PDF・Office文書）を含むため BYTEA で保存する
+    size BIGINT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON TABLE file_blobs IS 'ファイルの内容（content_hash ごとに1回だけ保存する）';
+COMMENT ON COLUMN file_blobs.content_hash IS 'files.content_hash と同じハッシュ';
+COMMENT ON COLUMN file_blobs.content IS 'ファイルの内容（原文）';
+COMMENT ON COLUMN file_blobs.size IS '内容のサイズ（バイト）';
@@ -0,0 +1,4 @@
+-- チャンカーのバージョンの記録のロールバック
+
+DROP INDEX IF EXISTS idx_chunks_chunker_version;
+ALTER TABLE chunks DROP COLUMN IF EXISTS chunker_version;
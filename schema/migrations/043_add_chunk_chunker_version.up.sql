@@ -0,0 +1,8 @@
+-- チャンクを作成したチャンカーの実装とバージョンを記録する
+-- 検索品質の低下の原因をチャンカーの変更に特定し、古いバージョンのチャンクだけを再チャンク化（index rechunk --outdated）するために使用する
+
+ALTER TABLE chunks ADD COLUMN IF NOT EXISTS chunker_version VARCHAR(50);
+
+CREATE INDEX IF NOT EXISTS idx_chunks_chunker_version ON chunks(chunker_version);
+
+COMMENT ON COLUMN chunks.chunker_version IS 'チャンクを作成したチャンカーの実装とバージョン（例: go-ast-v1, markdown-v1、記録前に作成したチャンクは NULL）';
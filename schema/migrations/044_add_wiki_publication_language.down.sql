@@ -0,0 +1,11 @@
+-- Wikiの公開先ページの言語のロールバック（ja 以外の言語の公開状況は削除する）
+
+DELETE FROM wiki_publications WHERE language <> 'ja';
+
+DROP INDEX IF EXISTS idx_wiki_publications_product;
+CREATE INDEX IF NOT EXISTS idx_wiki_publications_product ON wiki_publications(product_id, target, destination);
+
+ALTER TABLE wiki_publications DROP CONSTRAINT IF EXISTS uq_wiki_publications_page;
+ALTER TABLE wiki_publications ADD CONSTRAINT uq_wiki_publications_page UNIQUE (product_id, target, destination, page_path);
+
+ALTER TABLE wiki_publications DROP COLUMN IF EXISTS language;
@@ -0,0 +1,12 @@
+-- Wikiの公開先ページの対応に言語を追加する
+-- wiki generate --lang で言語ごとに生成したWikiを同じ公開先に公開できるよう、言語をページのキーに含める
+
+ALTER TABLE wiki_publications ADD COLUMN IF NOT EXISTS language VARCHAR(16) NOT NULL DEFAULT 'ja';
+
+ALTER TABLE wiki_publications DROP CONSTRAINT IF EXISTS uq_wiki_publications_page;
+ALTER TABLE wiki_publications ADD CONSTRAINT uq_wiki_publications_page UNIQUE (product_id, target, destination, language, page_path);
+
+DROP INDEX IF EXISTS idx_wiki_publications_product;
+CREATE INDEX IF NOT EXISTS idx_wiki_publications_product ON wiki_publications(product_id, target, destination, language);
+
+COMMENT ON COLUMN wiki_publications.language IS 'Wikiの言語（ja / en など、--lang を指定せずに生成したWikiは ja）';
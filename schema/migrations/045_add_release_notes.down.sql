@@ -0,0 +1,3 @@
+-- リリースノートの記録のロールバック
+
+DROP TABLE IF EXISTS release_notes;
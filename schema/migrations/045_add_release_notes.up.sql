@@ -0,0 +1,32 @@
+-- リリースノートの下書きを記録する
+-- release-notes で2つの ref の間のGitの履歴・変更された宣言・依存関係の影響範囲から生成したリリースノートを保存し、Wikiのページにまとめる
+
+CREATE TABLE IF NOT EXISTS release_notes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    source_id UUID NOT NULL REFERENCES sources(id) ON DELETE CASCADE,
+    from_ref VARCHAR(255) NOT NULL,       -- 比較元の ref（タグ・ブランチ・コミットハッシュ）
+    to_ref VARCHAR(255) NOT NULL,         -- 比較先の ref
+    from_commit VARCHAR(40) NOT NULL,
+    to_commit VARCHAR(40) NOT NULL,
+    released_at TIMESTAMP NOT NULL,       -- to のコミット日時
+    commit_count INTEGER NOT NULL,
+    file_count INTEGER NOT NULL,
+    content TEXT NOT NULL,                -- リリースノートの本文（Markdown）
+    prompt_version VARCHAR(10) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_release_notes_range UNIQUE (source_id, from_ref, to_ref)
+);
+
+CREATE INDEX IF NOT EXISTS idx_release_notes_source ON release_notes(source_id, released_at DESC);
+
+COMMENT ON TABLE release_notes IS '2つの ref の間の変更からLLMで生成したリリースノートの下書き';
+COMMENT ON COLUMN release_notes.from_ref IS '比較元の ref（タグ・ブランチ・コミットハッシュ）';
+COMMENT ON COLUMN release_notes.to_ref IS '比較先の ref（タグ・ブランチ・コミットハッシュ）';
+COMMENT ON COLUMN release_notes.from_commit IS 'from_ref が指していたコミットハッシュ';
+COMMENT ON COLUMN release_notes.to_commit IS 'to_ref が指していたコミットハッシュ';
+COMMENT ON COLUMN release_notes.released_at IS 'to のコミット日時（Wikiのページの並び順）';
+COMMENT ON COLUMN release_notes.commit_count IS '比較範囲のコミット数';
+COMMENT ON COLUMN release_notes.file_count IS '比較範囲で変更されたファイル数';
+COMMENT ON COLUMN release_notes.content IS 'リリースノートの本文（Markdown）';
+COMMENT ON COLUMN release_notes.prompt_version IS '生成に使用したプロンプトテンプレートのバージョン';
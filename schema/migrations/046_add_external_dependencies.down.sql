@@ -0,0 +1,3 @@
+-- 外部の依存関係のロールバック
+
+DROP TABLE IF EXISTS external_dependencies;
@@ -0,0 +1,29 @@
+-- インデックス化時に依存関係のマニフェスト（go.mod・package.json・requirements*.txt）と .gitmodules から抽出した外部の依存関係をスナップショット単位で保持する
+-- deps list と Wikiの外部依存関係のページで、スナップショット間のバージョンの変化を追跡するために使用する
+
+CREATE TABLE IF NOT EXISTS external_dependencies (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    snapshot_id UUID NOT NULL REFERENCES source_snapshots(id) ON DELETE CASCADE,
+    manifest_path TEXT NOT NULL,              -- 依存関係を記述したファイルのパス
+    ecosystem VARCHAR(20) NOT NULL,           -- go / npm / pypi / submodule
+    name TEXT NOT NULL,                       -- モジュールパス・パッケージ名（サブモジュールは配置するパス）
+    requirement TEXT NOT NULL DEFAULT '',     -- 要求するバージョン（サブモジュールは追跡するブランチ）
+    kind VARCHAR(20) NOT NULL,                -- direct / indirect / dev
+    vendored BOOLEAN NOT NULL DEFAULT FALSE,
+    location TEXT NOT NULL DEFAULT '',        -- 取得元（サブモジュールの URL、go.mod の replace の置き換え先）
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_external_dependencies_snapshot_name UNIQUE (snapshot_id, manifest_path, ecosystem, name),
+    CONSTRAINT chk_external_dependencies_ecosystem CHECK (ecosystem IN ('go', 'npm', 'pypi', 'submodule')),
+    CONSTRAINT chk_external_dependencies_kind CHECK (kind IN ('direct', 'indirect', 'dev'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_external_dependencies_snapshot ON external_dependencies(snapshot_id);
+
+COMMENT ON TABLE external_dependencies IS 'スナップショットの依存関係のマニフェストとサブモジュールから抽出した外部の依存関係';
+COMMENT ON COLUMN external_dependencies.manifest_path IS '依存関係を記述したファイルのパス（go.mod・package.json・requirements*.txt・.gitmodules）';
+COMMENT ON COLUMN external_dependencies.ecosystem IS '依存関係を管理する仕組み（go, npm, pypi, submodule）';
+COMMENT ON COLUMN external_dependencies.name IS 'モジュールパス・パッケージ名（サブモジュールは配置するパス）';
+COMMENT ON COLUMN external_dependencies.requirement IS '要求するバージョン（サブモジュールは追跡するブランチ。指定がない場合は空）';
+COMMENT ON COLUMN external_dependencies.kind IS '依存関係の種類（direct, indirect, dev）';
+COMMENT ON COLUMN external_dependencies.vendored IS 'vendor/modules.txt でベンダリングしているか（Go のみ）';
+COMMENT ON COLUMN external_dependencies.location IS '取得元（サブモジュールの URL、go.mod の replace の置き換え先）';
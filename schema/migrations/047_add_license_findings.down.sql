@@ -0,0 +1,3 @@
+-- ライセンスの検出結果のロールバック
+
+DROP TABLE IF EXISTS license_findings;
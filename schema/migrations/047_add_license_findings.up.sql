@@ -0,0 +1,24 @@
+-- インデックス化時にライセンスファイルとソースファイル冒頭のライセンスヘッダーから検出したライセンスをスナップショット単位で保持する
+-- report licenses と Wikiのライセンスのページで、コピーレフト・判別できないライセンスを確認するために使用する
+
+CREATE TABLE IF NOT EXISTS license_findings (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    snapshot_id UUID NOT NULL REFERENCES source_snapshots(id) ON DELETE CASCADE,
+    file_path TEXT NOT NULL,
+    kind VARCHAR(10) NOT NULL,                -- file / header
+    license_id TEXT NOT NULL DEFAULT '',      -- SPDX のライセンス識別子・式（判別できない場合は空）
+    category VARCHAR(20) NOT NULL,            -- permissive / weak_copyleft / copyleft / unknown
+    evidence TEXT NOT NULL DEFAULT '',        -- 検出の根拠とした行
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_license_findings_snapshot_path UNIQUE (snapshot_id, file_path),
+    CONSTRAINT chk_license_findings_kind CHECK (kind IN ('file', 'header')),
+    CONSTRAINT chk_license_findings_category CHECK (category IN ('permissive', 'weak_copyleft', 'copyleft', 'unknown'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_license_findings_snapshot ON license_findings(snapshot_id);
+
+COMMENT ON TABLE license_findings IS 'スナップショットのライセンスファイル・ライセンスヘッダーから検出したライセンス';
+COMMENT ON COLUMN license_findings.kind IS '検出した場所（file: LICENSE・COPYING などのライセンスファイル, header: ソースファイル冒頭のライセンスヘッダー）';
+COMMENT ON COLUMN license_findings.license_id IS 'SPDX のライセンス識別子・式（判別できないライセンスファイルは空）';
+COMMENT ON COLUMN license_findings.category IS 'ライセンスの分類（permissive, weak_copyleft, copyleft, unknown）';
+COMMENT ON COLUMN license_findings.evidence IS '検出の根拠とした行（SPDX-License-Identifier の行、ライセンスの文言を含む行）';
@@ -0,0 +1,3 @@
+-- モノレポのサービスの境界のロールバック
+
+DROP TABLE IF EXISTS monorepo_services;
@@ -0,0 +1,24 @@
+-- インデックス化時に go.mod・package.json の workspaces・Bazel の BUILD ファイルから検出したモノレポのサービスの境界をスナップショット単位で保持する
+-- services list・services map でサービスのサブツリーを別のプロダクトとしてインデックス化し、ask --service で検索範囲をサービスに限定するために使用する
+
+CREATE TABLE IF NOT EXISTS monorepo_services (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    snapshot_id UUID NOT NULL REFERENCES source_snapshots(id) ON DELETE CASCADE,
+    path TEXT NOT NULL,                       -- サービスのルートディレクトリ
+    name TEXT NOT NULL,                       -- モジュールパス・パッケージ名・Bazel パッケージのラベル
+    kind VARCHAR(10) NOT NULL,                -- go / npm / bazel
+    manifest_path TEXT NOT NULL,              -- 境界の根拠としたファイルのパス
+    targets TEXT[] NOT NULL DEFAULT '{}',     -- Bazel の実行可能なターゲットのラベル
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_monorepo_services_snapshot_path UNIQUE (snapshot_id, path),
+    CONSTRAINT chk_monorepo_services_kind CHECK (kind IN ('go', 'npm', 'bazel'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_monorepo_services_snapshot ON monorepo_services(snapshot_id);
+
+COMMENT ON TABLE monorepo_services IS 'スナップショットから検出したモノレポのサービスの境界';
+COMMENT ON COLUMN monorepo_services.path IS 'サービスのルートディレクトリ（リポジトリのルートからのパス）';
+COMMENT ON COLUMN monorepo_services.name IS 'サービス名（go.mod のモジュールパス、package.json の name、Bazel パッケージのラベル）';
+COMMENT ON COLUMN monorepo_services.kind IS '境界を検出した方法（go: go.mod, npm: package.json の workspaces, bazel: BUILD ファイルの実行可能なターゲット）';
+COMMENT ON COLUMN monorepo_services.manifest_path IS '境界の根拠としたファイル（go.mod・package.json・BUILD・BUILD.bazel）のパス';
+COMMENT ON COLUMN monorepo_services.targets IS 'Bazel の実行可能なターゲット（*_binary・*_image）のラベル（//path:name）';
@@ -7,7 +7,8 @@ CREATE TABLE IF NOT EXISTS products (
     name VARCHAR(255) NOT NULL UNIQUE,
     description TEXT,
     created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    recency_weight DOUBLE PRECISION
 );
 
 CREATE INDEX IF NOT EXISTS idx_products_name ON products(name);
@@ -16,6 +17,20 @@ COMMENT ON TABLE products IS 'プロダクト（複数のソースをまとめ
 COMMENT ON COLUMN products.id IS 'プロダクトの一意識別子';
 COMMENT ON COLUMN products.name IS 'プロダクト名（一意）';
 COMMENT ON COLUMN products.description IS 'プロダクトの説明';
+COMMENT ON COLUMN products.recency_weight IS '検索スコアに鮮度（チャンクの最終更新日時）を加味する重み（0〜1、NULLは加味しない）';
+
+-- product_configsテーブル: プロダクトごとの設定（カバレッジアラートの閾値など）
+CREATE TABLE IF NOT EXISTS product_configs (
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    key VARCHAR(255) NOT NULL,                -- 設定キー（例: alert.min_coverage.tests）
+    value TEXT NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (product_id, key)
+);
+
+COMMENT ON TABLE product_configs IS 'プロダクトごとの設定（カバレッジアラートの閾値など、キーと値の組）';
+COMMENT ON COLUMN product_configs.key IS '設定キー（alert.min_coverage.<ドメイン>, alert.max_unindexed_important_files など）';
+COMMENT ON COLUMN product_configs.value IS '設定値（キーごとに検証した文字列）';
 
 -- sourcesテーブル（repositoriesを抽象化）
 CREATE TABLE IF NOT EXISTS sources (
@@ -36,7 +51,7 @@ COMMENT ON TABLE sources IS 'ドキュメント・コードのソース情報（
 COMMENT ON COLUMN sources.id IS 'ソースの一意識別子';
 COMMENT ON COLUMN sources.product_id IS '所属するプロダクトのID（必須）';
 COMMENT ON COLUMN sources.name IS 'ソース名（一意）';
-COMMENT ON COLUMN sources.source_type IS 'ソースタイプ（git/confluence/pdf/redmine/notion/local）';
+COMMENT ON COLUMN sources.source_type IS 'ソースタイプ（git/confluence/pdf/redmine/notion/local/discussion）';
 COMMENT ON COLUMN sources.metadata IS 'ソースタイプ固有の情報（JSONBフォーマット）。例: Gitの場合 {"url": "git@github.com:...", "default_branch": "main"}、Confluenceの場合 {"base_url": "https://...", "space_key": "..."}';
 
 -- source_snapshotsテーブル（snapshotsを抽象化）
@@ -44,22 +59,29 @@ CREATE TABLE IF NOT EXISTS source_snapshots (
     id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
     source_id UUID NOT NULL REFERENCES sources(id) ON DELETE CASCADE,
     version_identifier TEXT NOT NULL,
-    indexed BOOLEAN NOT NULL DEFAULT FALSE,
     indexed_at TIMESTAMP,
     created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
-    CONSTRAINT uq_source_snapshots_source_version UNIQUE (source_id, version_identifier)
+    failed_at TIMESTAMP,
+    failure_reason TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    indexing_started_at TIMESTAMP,
+    CONSTRAINT uq_source_snapshots_source_version UNIQUE (source_id, version_identifier),
+    CONSTRAINT chk_source_snapshots_status CHECK (status IN ('pending', 'indexing', 'indexed', 'failed'))
 );
 
 CREATE INDEX IF NOT EXISTS idx_source_snapshots_source_id ON source_snapshots(source_id);
 CREATE INDEX IF NOT EXISTS idx_source_snapshots_version ON source_snapshots(version_identifier);
-CREATE INDEX IF NOT EXISTS idx_source_snapshots_indexed ON source_snapshots(indexed) WHERE indexed = TRUE;
+CREATE INDEX IF NOT EXISTS idx_source_snapshots_status ON source_snapshots(status) WHERE status = 'indexed';
 
 COMMENT ON TABLE source_snapshots IS 'ソースの特定バージョン時点のスナップショット';
 COMMENT ON COLUMN source_snapshots.id IS 'スナップショットの一意識別子';
 COMMENT ON COLUMN source_snapshots.source_id IS '対象ソースのID';
 COMMENT ON COLUMN source_snapshots.version_identifier IS 'バージョン識別子（Gitの場合はcommit_hash、Confluenceの場合はpage_version、PDFの場合はfile_hash等）';
-COMMENT ON COLUMN source_snapshots.indexed IS 'インデックス完了フラグ';
 COMMENT ON COLUMN source_snapshots.indexed_at IS 'インデックス完了日時';
+COMMENT ON COLUMN source_snapshots.failed_at IS 'インデックス化に失敗・中断した日時（完了時に NULL に戻す）';
+COMMENT ON COLUMN source_snapshots.failure_reason IS 'インデックス化に失敗・中断した理由';
+COMMENT ON COLUMN source_snapshots.status IS 'インデックス化の状態（pending: 未着手、indexing: インデックス化中、indexed: 完了、failed: 失敗・中断）';
+COMMENT ON COLUMN source_snapshots.indexing_started_at IS '最後にインデックス化を開始した日時';
 
 -- git_refsテーブル（Git専用の参照管理）
 CREATE TABLE IF NOT EXISTS git_refs (
@@ -94,6 +116,7 @@ CREATE TABLE IF NOT EXISTS files (
     content_hash VARCHAR(64) NOT NULL,
     language VARCHAR(50),
     domain VARCHAR(50),
+    owners TEXT[] NOT NULL DEFAULT '{}',
     created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
     CONSTRAINT uq_files_snapshot_path UNIQUE (snapshot_id, path)
 );
@@ -114,6 +137,7 @@ COMMENT ON COLUMN files.content_type IS 'MIMEタイプ形式のコンテンツ
 COMMENT ON COLUMN files.content_hash IS 'ファイル内容のSHA-256ハッシュ';
 COMMENT ON COLUMN files.language IS 'プログラミング言語（go-enryによる自動検出）';
 COMMENT ON COLUMN files.domain IS 'ドメイン分類（code, architecture, ops, tests, infra）';
+COMMENT ON COLUMN files.owners IS 'CODEOWNERS で定義された担当者（チーム・ユーザー、定義がない場合は空）';
 
 -- chunksテーブル
 CREATE TABLE IF NOT EXISTS chunks (
@@ -136,6 +160,10 @@ CREATE TABLE IF NOT EXISTS chunks (
     comment_ratio NUMERIC(3,2),
     cyclomatic_complexity INTEGER,
     embedding_context TEXT,
+    embedding_truncated BOOLEAN NOT NULL DEFAULT FALSE,
+    breadcrumb TEXT,                   -- Markdownの見出しの階層（例: Architecture > Deployment > Rollback）
+    page_start INTEGER,                -- PDF・Office文書の開始ページ
+    page_end INTEGER,                  -- PDF・Office文書の終了ページ
     level INTEGER NOT NULL DEFAULT 2,  -- 1:ファイルサマリー, 2:関数/クラス, 3:ロジック単位
     importance_score NUMERIC(5,4),     -- 0.0000〜1.0000
     standard_imports JSONB,            -- 標準ライブラリのインポート
@@ -151,6 +179,7 @@ CREATE TABLE IF NOT EXISTS chunks (
     file_version VARCHAR(100),
     is_latest BOOLEAN NOT NULL DEFAULT true,
     chunk_key VARCHAR(512) NOT NULL DEFAULT '',
+    chunker_version VARCHAR(50),       -- チャンカーの実装とバージョン（例: go-ast-v1）
     created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
     CONSTRAINT uq_chunks_file_ordinal UNIQUE (file_id, ordinal),
     CONSTRAINT uq_chunks_chunk_key UNIQUE (chunk_key),
@@ -168,9 +197,10 @@ CREATE INDEX IF NOT EXISTS idx_chunks_updated_at ON chunks(updated_at);
 CREATE INDEX IF NOT EXISTS idx_chunks_chunk_type ON chunks(chunk_type);
 CREATE INDEX IF NOT EXISTS idx_chunks_level ON chunks(level);
 CREATE INDEX IF NOT EXISTS idx_chunks_importance_score ON chunks(importance_score);
+CREATE INDEX IF NOT EXISTS idx_chunks_chunker_version ON chunks(chunker_version);
 
 COMMENT ON TABLE chunks IS 'ファイルを分割したチャンク';
-COMMENT ON COLUMN chunks.id IS 'チャンクの一意識別子';
+COMMENT ON COLUMN chunks.id IS 'チャンクの一意識別子（インデックス化では chunk_key から決定的に生成する）';
 COMMENT ON COLUMN chunks.file_id IS '所属するファイルのID';
 COMMENT ON COLUMN chunks.ordinal IS 'ファイル内でのチャンク序数（0始まり）';
 COMMENT ON COLUMN chunks.start_line IS 'チャンクの開始行番号';
@@ -189,6 +219,10 @@ COMMENT ON COLUMN chunks.lines_of_code IS 'コード行数（コメント・空
 COMMENT ON COLUMN chunks.comment_ratio IS 'コメント比率（0.00〜1.00）';
 COMMENT ON COLUMN chunks.cyclomatic_complexity IS '循環的複雑度（McCabe複雑度）';
 COMMENT ON COLUMN chunks.embedding_context IS 'Embedding生成用の拡張コンテキスト';
+COMMENT ON COLUMN chunks.embedding_truncated IS 'Embeddingモデルの最大入力トークン数に合わせて embedding_context を切り詰めたか';
+COMMENT ON COLUMN chunks.breadcrumb IS 'Markdownのチャンクが属する見出しの階層（H1 > H2 > H3 を " > " で連結）';
+COMMENT ON COLUMN chunks.page_start IS 'PDF・Office文書から抽出したチャンクの開始ページ（xlsx はシートの番号）';
+COMMENT ON COLUMN chunks.page_end IS 'PDF・Office文書から抽出したチャンクの終了ページ';
 COMMENT ON COLUMN chunks.level IS '階層レベル（1:ファイルサマリー, 2:関数/クラス, 3:ロジック単位）';
 COMMENT ON COLUMN chunks.importance_score IS '重要度スコア（0.0000〜1.0000、参照回数・中心性・編集頻度から算出）';
 COMMENT ON COLUMN chunks.source_snapshot_id IS '所属するスナップショットID（トレーサビリティ用）';
@@ -199,6 +233,7 @@ COMMENT ON COLUMN chunks.indexed_at IS 'インデックス作成日時';
 COMMENT ON COLUMN chunks.file_version IS 'ファイルバージョン識別子（オプション）';
 COMMENT ON COLUMN chunks.is_latest IS '最新バージョンフラグ（true=最新、false=過去バージョン）';
 COMMENT ON COLUMN chunks.chunk_key IS '決定的な識別子（{product_name}/{source_name}/{file_path}#L{start}-L{end}@{commit_hash}）';
+COMMENT ON COLUMN chunks.chunker_version IS 'チャンクを作成したチャンカーの実装とバージョン（例: go-ast-v1, markdown-v1、記録前に作成したチャンクは NULL）';
 
 -- embeddingsテーブル
 CREATE TABLE IF NOT EXISTS embeddings (
@@ -260,6 +295,310 @@ COMMENT ON COLUMN wiki_metadata.output_path IS 'Wikiファイルの出力先パ
 COMMENT ON COLUMN wiki_metadata.file_count IS '生成されたWikiファイル数';
 COMMENT ON COLUMN wiki_metadata.generated_at IS 'Wiki生成完了日時';
 
+-- wiki_publicationsテーブル: 生成したWikiページと公開先（Confluence等）のリモートページの対応
+CREATE TABLE IF NOT EXISTS wiki_publications (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    target VARCHAR(50) NOT NULL,          -- 公開先の種類（confluence）
+    destination VARCHAR(255) NOT NULL,    -- 公開先（Confluenceのスペースキーなど）
+    language VARCHAR(16) NOT NULL DEFAULT 'ja', -- Wikiの言語
+    page_path VARCHAR(512) NOT NULL,      -- Wiki出力ディレクトリからの相対パス（ディレクトリのページは末尾 /）
+    remote_id VARCHAR(255) NOT NULL,      -- リモートのページID
+    remote_version INTEGER NOT NULL,      -- 最後に公開したリモートのページのバージョン
+    parent_remote_id VARCHAR(255),        -- 親ページのリモートID
+    title VARCHAR(255) NOT NULL,
+    content_hash VARCHAR(64) NOT NULL,    -- 公開した内容（タイトル・親・本文）のハッシュ
+    published_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_wiki_publications_page UNIQUE (product_id, target, destination, language, page_path)
+);
+
+CREATE INDEX IF NOT EXISTS idx_wiki_publications_product ON wiki_publications(product_id, target, destination, language);
+
+COMMENT ON TABLE wiki_publications IS '生成したWikiページと公開先のリモートページの対応';
+COMMENT ON COLUMN wiki_publications.target IS '公開先の種類（confluence）';
+COMMENT ON COLUMN wiki_publications.destination IS '公開先（Confluenceのスペースキーなど）';
+COMMENT ON COLUMN wiki_publications.language IS 'Wikiの言語（ja / en など、--lang を指定せずに生成したWikiは ja）';
+COMMENT ON COLUMN wiki_publications.page_path IS 'Wiki出力ディレクトリからの相対パス（ディレクトリのページは末尾 /）';
+COMMENT ON COLUMN wiki_publications.remote_id IS 'リモートのページID';
+COMMENT ON COLUMN wiki_publications.remote_version IS '最後に公開したリモートのページのバージョン';
+COMMENT ON COLUMN wiki_publications.content_hash IS '公開した内容（タイトル・親・本文）のSHA-256ハッシュ';
+
+-- glossaryテーブル: インデックス済みのドキュメント・コードから抽出したプロダクトの用語集
+CREATE TABLE IF NOT EXISTS glossary (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    term VARCHAR(255) NOT NULL,
+    normalized_term VARCHAR(255) NOT NULL,    -- 重複判定用に小文字化した用語
+    kind VARCHAR(20) NOT NULL,                -- term / acronym / entity
+    definition TEXT NOT NULL,
+    aliases TEXT[] NOT NULL DEFAULT '{}',     -- 別名・略語の展開形
+    source_paths TEXT[] NOT NULL DEFAULT '{}', -- 抽出元のファイルパス
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_glossary_product_term UNIQUE (product_id, normalized_term),
+    CONSTRAINT chk_glossary_kind CHECK (kind IN ('term', 'acronym', 'entity'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_glossary_product ON glossary(product_id);
+
+COMMENT ON TABLE glossary IS 'インデックス済みのドキュメント・コードからLLMで抽出したプロダクトの用語集';
+COMMENT ON COLUMN glossary.normalized_term IS '重複判定用に小文字化した用語';
+COMMENT ON COLUMN glossary.kind IS '用語の種類（term: ドメイン用語, acronym: 略語, entity: エンティティ）';
+COMMENT ON COLUMN glossary.aliases IS '別名・略語の展開形';
+COMMENT ON COLUMN glossary.source_paths IS '抽出元のファイルパス';
+COMMENT ON COLUMN glossary.updated_at IS '最後に抽出された日時';
+
+-- decision_recordsテーブル: リポジトリ内の設計判断の記録（ADR・RFC）から抽出したメタデータ
+CREATE TABLE IF NOT EXISTS decision_records (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    snapshot_id UUID NOT NULL REFERENCES source_snapshots(id) ON DELETE CASCADE,
+    file_id UUID NOT NULL REFERENCES files(id) ON DELETE CASCADE,
+    file_path TEXT NOT NULL,
+    number INTEGER,                           -- ADR・RFC の番号
+    title TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL,              -- proposed / accepted / rejected / deprecated / superseded / unknown
+    status_text TEXT NOT NULL DEFAULT '',     -- 記載どおりのステータス
+    decided_on DATE,
+    decision TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_decision_records_file UNIQUE (file_id),
+    CONSTRAINT chk_decision_records_status CHECK (status IN ('proposed', 'accepted', 'rejected', 'deprecated', 'superseded', 'unknown'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_decision_records_snapshot ON decision_records(snapshot_id);
+
+COMMENT ON TABLE decision_records IS 'リポジトリ内の設計判断の記録（ADR・RFC）から抽出したメタデータ';
+COMMENT ON COLUMN decision_records.number IS 'ファイル名またはタイトルから抽出したADR・RFCの番号';
+COMMENT ON COLUMN decision_records.status IS '正規化したステータス（proposed, accepted, rejected, deprecated, superseded, unknown）';
+COMMENT ON COLUMN decision_records.status_text IS '記載どおりのステータス（Superseded by ADR-0007 など）';
+COMMENT ON COLUMN decision_records.decided_on IS '決定日';
+COMMENT ON COLUMN decision_records.decision IS '決定内容のセクション（ない場合は概要）';
+
+-- api_endpointsテーブル: OpenAPI（Swagger）・Protocol Buffers の定義から抽出したエンドポイント
+CREATE TABLE IF NOT EXISTS api_endpoints (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    snapshot_id UUID NOT NULL REFERENCES source_snapshots(id) ON DELETE CASCADE,
+    file_id UUID NOT NULL REFERENCES files(id) ON DELETE CASCADE,
+    file_path TEXT NOT NULL,
+    kind VARCHAR(10) NOT NULL,                -- http / grpc
+    service TEXT NOT NULL DEFAULT '',         -- OpenAPI のタグ、または <package>.<service>
+    method VARCHAR(255) NOT NULL,             -- HTTP メソッドまたは RPC 名
+    path TEXT NOT NULL,
+    operation_id TEXT NOT NULL DEFAULT '',
+    summary TEXT NOT NULL DEFAULT '',
+    request_type TEXT NOT NULL DEFAULT '',
+    response_type TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT chk_api_endpoints_kind CHECK (kind IN ('http', 'grpc'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_endpoints_snapshot ON api_endpoints(snapshot_id);
+CREATE INDEX IF NOT EXISTS idx_api_endpoints_file ON api_endpoints(file_id);
+
+COMMENT ON TABLE api_endpoints IS 'OpenAPI（Swagger）・Protocol Buffers の定義から抽出したエンドポイント';
+COMMENT ON COLUMN api_endpoints.kind IS 'エンドポイントの種類（http: OpenAPI または google.api.http, grpc: service の RPC）';
+COMMENT ON COLUMN api_endpoints.service IS 'OpenAPI の最初のタグ、または <package>.<service>';
+COMMENT ON COLUMN api_endpoints.method IS 'HTTP メソッド（GET など）または RPC 名';
+COMMENT ON COLUMN api_endpoints.path IS 'HTTP のパス、または /<package>.<service>/<RPC名>';
+COMMENT ON COLUMN api_endpoints.request_type IS 'リクエストの型名（ストリーミングは stream を前置）';
+COMMENT ON COLUMN api_endpoints.response_type IS 'レスポンスの型名（ストリーミングは stream を前置）';
+
+-- schema_tablesテーブル: SQLのスキーマ定義・マイグレーションファイルから構築したテーブルのカタログ
+CREATE TABLE IF NOT EXISTS schema_tables (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    snapshot_id UUID NOT NULL REFERENCES source_snapshots(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,                       -- スキーマ修飾付き（public スキーマは省略）
+    comment TEXT NOT NULL DEFAULT '',
+    columns JSONB NOT NULL DEFAULT '[]'::jsonb,
+    indexes JSONB NOT NULL DEFAULT '[]'::jsonb,
+    source_paths TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_schema_tables_snapshot_name UNIQUE (snapshot_id, name)
+);
+
+COMMENT ON TABLE schema_tables IS 'SQLのスキーマ定義・マイグレーションファイルから構築したテーブルのカタログ';
+COMMENT ON COLUMN schema_tables.columns IS 'カラムの一覧（名前・型・NOT NULL・主キー・デフォルト値・参照先・コメント）';
+COMMENT ON COLUMN schema_tables.indexes IS 'インデックス・UNIQUE 制約の一覧（名前・対象・アクセスメソッド・部分インデックスの条件）';
+COMMENT ON COLUMN schema_tables.source_paths IS 'テーブルを定義・変更したファイル（適用順）';
+
+-- infra_resourcesテーブル: Dockerfile・Docker Compose・Kubernetes マニフェストから抽出したデプロイ構成
+CREATE TABLE IF NOT EXISTS infra_resources (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    snapshot_id UUID NOT NULL REFERENCES source_snapshots(id) ON DELETE CASCADE,
+    file_id UUID NOT NULL REFERENCES files(id) ON DELETE CASCADE,
+    file_path TEXT NOT NULL,
+    format VARCHAR(20) NOT NULL,              -- dockerfile / compose / kubernetes
+    kind VARCHAR(100) NOT NULL,               -- Dockerfile、service、Deployment など
+    name TEXT NOT NULL,
+    namespace TEXT NOT NULL DEFAULT '',
+    spec JSONB NOT NULL DEFAULT '{}'::jsonb,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT chk_infra_resources_format CHECK (format IN ('dockerfile', 'compose', 'kubernetes'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_infra_resources_snapshot ON infra_resources(snapshot_id);
+CREATE INDEX IF NOT EXISTS idx_infra_resources_file ON infra_resources(file_id);
+
+COMMENT ON TABLE infra_resources IS 'Dockerfile・Docker Compose・Kubernetes マニフェストから抽出したデプロイ構成';
+COMMENT ON COLUMN infra_resources.format IS '定義ファイルの形式（dockerfile, compose, kubernetes）';
+COMMENT ON COLUMN infra_resources.kind IS 'リソースの種類（Dockerfile、Docker Compose の service、Kubernetes の kind）';
+COMMENT ON COLUMN infra_resources.name IS 'リソース名（Dockerfile はファイルパス）';
+COMMENT ON COLUMN infra_resources.spec IS 'レプリカ数・Service の type とポート・コンテナ（イメージ・ポート・環境変数・リソース制限）・起動コマンド';
+
+-- go_modulesテーブル: go.mod から抽出した Go モジュール（ソースをまたぐ依存関係の解決に使用）
+CREATE TABLE IF NOT EXISTS go_modules (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    snapshot_id UUID NOT NULL REFERENCES source_snapshots(id) ON DELETE CASCADE,
+    module_path TEXT NOT NULL,
+    dir TEXT NOT NULL,                        -- go.mod を置いたディレクトリ（ルートは "."）
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_go_modules_snapshot_dir UNIQUE (snapshot_id, dir)
+);
+
+CREATE INDEX IF NOT EXISTS idx_go_modules_snapshot ON go_modules(snapshot_id);
+
+COMMENT ON TABLE go_modules IS 'スナップショットに含まれる Go モジュール（go.mod）';
+COMMENT ON COLUMN go_modules.module_path IS 'module ディレクティブのモジュールパス';
+COMMENT ON COLUMN go_modules.dir IS 'go.mod を置いたディレクトリ（リポジトリのルートは "."）';
+
+-- external_dependenciesテーブル: 依存関係のマニフェストとサブモジュールから抽出した外部の依存関係（スナップショット間のバージョンの追跡に使用）
+CREATE TABLE IF NOT EXISTS external_dependencies (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    snapshot_id UUID NOT NULL REFERENCES source_snapshots(id) ON DELETE CASCADE,
+    manifest_path TEXT NOT NULL,              -- 依存関係を記述したファイルのパス
+    ecosystem VARCHAR(20) NOT NULL,           -- go / npm / pypi / submodule
+    name TEXT NOT NULL,                       -- モジュールパス・パッケージ名（サブモジュールは配置するパス）
+    requirement TEXT NOT NULL DEFAULT '',     -- 要求するバージョン（サブモジュールは追跡するブランチ）
+    kind VARCHAR(20) NOT NULL,                -- direct / indirect / dev
+    vendored BOOLEAN NOT NULL DEFAULT FALSE,
+    location TEXT NOT NULL DEFAULT '',        -- 取得元（サブモジュールの URL、go.mod の replace の置き換え先）
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_external_dependencies_snapshot_name UNIQUE (snapshot_id, manifest_path, ecosystem, name),
+    CONSTRAINT chk_external_dependencies_ecosystem CHECK (ecosystem IN ('go', 'npm', 'pypi', 'submodule')),
+    CONSTRAINT chk_external_dependencies_kind CHECK (kind IN ('direct', 'indirect', 'dev'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_external_dependencies_snapshot ON external_dependencies(snapshot_id);
+
+COMMENT ON TABLE external_dependencies IS 'スナップショットの依存関係のマニフェストとサブモジュールから抽出した外部の依存関係';
+COMMENT ON COLUMN external_dependencies.manifest_path IS '依存関係を記述したファイルのパス（go.mod・package.json・requirements*.txt・.gitmodules）';
+COMMENT ON COLUMN external_dependencies.ecosystem IS '依存関係を管理する仕組み（go, npm, pypi, submodule）';
+COMMENT ON COLUMN external_dependencies.name IS 'モジュールパス・パッケージ名（サブモジュールは配置するパス）';
+COMMENT ON COLUMN external_dependencies.requirement IS '要求するバージョン（サブモジュールは追跡するブランチ。指定がない場合は空）';
+COMMENT ON COLUMN external_dependencies.kind IS '依存関係の種類（direct, indirect, dev）';
+COMMENT ON COLUMN external_dependencies.vendored IS 'vendor/modules.txt でベンダリングしているか（Go のみ）';
+COMMENT ON COLUMN external_dependencies.location IS '取得元（サブモジュールの URL、go.mod の replace の置き換え先）';
+
+-- license_findingsテーブル: ライセンスファイル・ライセンスヘッダーから検出したライセンス（コンプライアンスの確認に使用）
+CREATE TABLE IF NOT EXISTS license_findings (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    snapshot_id UUID NOT NULL REFERENCES source_snapshots(id) ON DELETE CASCADE,
+    file_path TEXT NOT NULL,
+    kind VARCHAR(10) NOT NULL,                -- file / header
+    license_id TEXT NOT NULL DEFAULT '',      -- SPDX のライセンス識別子・式（判別できない場合は空）
+    category VARCHAR(20) NOT NULL,            -- permissive / weak_copyleft / copyleft / unknown
+    evidence TEXT NOT NULL DEFAULT '',        -- 検出の根拠とした行
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_license_findings_snapshot_path UNIQUE (snapshot_id, file_path),
+    CONSTRAINT chk_license_findings_kind CHECK (kind IN ('file', 'header')),
+    CONSTRAINT chk_license_findings_category CHECK (category IN ('permissive', 'weak_copyleft', 'copyleft', 'unknown'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_license_findings_snapshot ON license_findings(snapshot_id);
+
+COMMENT ON TABLE license_findings IS 'スナップショットのライセンスファイル・ライセンスヘッダーから検出したライセンス';
+COMMENT ON COLUMN license_findings.kind IS '検出した場所（file: LICENSE・COPYING などのライセンスファイル, header: ソースファイル冒頭のライセンスヘッダー）';
+COMMENT ON COLUMN license_findings.license_id IS 'SPDX のライセンス識別子・式（判別できないライセンスファイルは空）';
+COMMENT ON COLUMN license_findings.category IS 'ライセンスの分類（permissive, weak_copyleft, copyleft, unknown）';
+COMMENT ON COLUMN license_findings.evidence IS '検出の根拠とした行（SPDX-License-Identifier の行、ライセンスの文言を含む行）';
+
+-- monorepo_servicesテーブル: go.mod・package.json の workspaces・Bazel のターゲットから検出したモノレポのサービスの境界
+CREATE TABLE IF NOT EXISTS monorepo_services (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    snapshot_id UUID NOT NULL REFERENCES source_snapshots(id) ON DELETE CASCADE,
+    path TEXT NOT NULL,                       -- サービスのルートディレクトリ
+    name TEXT NOT NULL,                       -- モジュールパス・パッケージ名・Bazel パッケージのラベル
+    kind VARCHAR(10) NOT NULL,                -- go / npm / bazel
+    manifest_path TEXT NOT NULL,              -- 境界の根拠としたファイルのパス
+    targets TEXT[] NOT NULL DEFAULT '{}',     -- Bazel の実行可能なターゲットのラベル
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_monorepo_services_snapshot_path UNIQUE (snapshot_id, path),
+    CONSTRAINT chk_monorepo_services_kind CHECK (kind IN ('go', 'npm', 'bazel'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_monorepo_services_snapshot ON monorepo_services(snapshot_id);
+
+COMMENT ON TABLE monorepo_services IS 'スナップショットから検出したモノレポのサービスの境界';
+COMMENT ON COLUMN monorepo_services.path IS 'サービスのルートディレクトリ（リポジトリのルートからのパス）';
+COMMENT ON COLUMN monorepo_services.name IS 'サービス名（go.mod のモジュールパス、package.json の name、Bazel パッケージのラベル）';
+COMMENT ON COLUMN monorepo_services.kind IS '境界を検出した方法（go: go.mod, npm: package.json の workspaces, bazel: BUILD ファイルの実行可能なターゲット）';
+COMMENT ON COLUMN monorepo_services.manifest_path IS '境界の根拠としたファイル（go.mod・package.json・BUILD・BUILD.bazel）のパス';
+COMMENT ON COLUMN monorepo_services.targets IS 'Bazel の実行可能なターゲット（*_binary・*_image）のラベル（//path:name）';
+
+-- chunk_importanceテーブル: チャンクの重要度（importance_score）の内訳
+CREATE TABLE IF NOT EXISTS chunk_importance (
+    chunk_id UUID PRIMARY KEY REFERENCES chunks(id) ON DELETE CASCADE,
+    algorithm VARCHAR(20) NOT NULL,           -- pagerank / betweenness / in_degree
+    graph_score NUMERIC(5,4) NOT NULL,        -- 0.0000〜1.0000
+    edit_score NUMERIC(5,4) NOT NULL,         -- 0.0000〜1.0000
+    final_score NUMERIC(5,4) NOT NULL,        -- 0.0000〜1.0000（chunks.importance_score と同じ値）
+    edit_count INTEGER NOT NULL DEFAULT 0,
+    calculated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT chk_chunk_importance_algorithm CHECK (algorithm IN ('pagerank', 'betweenness', 'in_degree'))
+);
+
+COMMENT ON TABLE chunk_importance IS 'チャンクの重要度（importance_score）の内訳';
+COMMENT ON COLUMN chunk_importance.algorithm IS 'グラフスコアの算出に使用したアルゴリズム（pagerank, betweenness, in_degree）';
+COMMENT ON COLUMN chunk_importance.graph_score IS '依存関係グラフから求めたスコア';
+COMMENT ON COLUMN chunk_importance.edit_score IS '編集頻度（スナップショット間で内容が変更された回数）から求めたスコア';
+COMMENT ON COLUMN chunk_importance.final_score IS 'グラフスコアと編集頻度スコアの加重和';
+COMMENT ON COLUMN chunk_importance.edit_count IS 'スナップショット間で内容が変更された回数';
+
+-- embedding_failuresテーブル: 再試行とバッチの分割でも Embedding を生成できなかったチャンク
+CREATE TABLE IF NOT EXISTS embedding_failures (
+    chunk_id UUID PRIMARY KEY REFERENCES chunks(id) ON DELETE CASCADE,
+    error TEXT NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 1,
+    first_failed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_failed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON TABLE embedding_failures IS 'Embedding を生成できなかったチャンク（index retry-embeddings で再試行する）';
+COMMENT ON COLUMN embedding_failures.error IS '最後に失敗したときのエラー';
+COMMENT ON COLUMN embedding_failures.attempts IS '失敗した回数（インデックス化と再試行の合計）';
+COMMENT ON COLUMN embedding_failures.first_failed_at IS '最初に失敗した日時';
+COMMENT ON COLUMN embedding_failures.last_failed_at IS '最後に失敗した日時';
+
+-- discussionsテーブル: GitHub・GitLab の Issue・Pull Request（Merge Request）の議論から抽出したメタデータ
+CREATE TABLE IF NOT EXISTS discussions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    snapshot_id UUID NOT NULL REFERENCES source_snapshots(id) ON DELETE CASCADE,
+    file_id UUID NOT NULL REFERENCES files(id) ON DELETE CASCADE,
+    file_path TEXT NOT NULL,
+    kind VARCHAR(20) NOT NULL,                -- issue / pull_request
+    number INTEGER NOT NULL,
+    title TEXT NOT NULL,
+    state VARCHAR(20) NOT NULL DEFAULT '',    -- open / closed / merged
+    url TEXT NOT NULL DEFAULT '',
+    summary TEXT NOT NULL DEFAULT '',
+    paths TEXT[] NOT NULL DEFAULT '{}',
+    last_activity_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_discussions_file UNIQUE (file_id),
+    CONSTRAINT chk_discussions_kind CHECK (kind IN ('issue', 'pull_request'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_discussions_snapshot ON discussions(snapshot_id);
+CREATE INDEX IF NOT EXISTS idx_discussions_paths ON discussions USING GIN (paths);
+
+COMMENT ON TABLE discussions IS 'GitHub・GitLab の Issue・Pull Request（Merge Request）の議論から抽出したメタデータ';
+COMMENT ON COLUMN discussions.kind IS '議論の種類（issue, pull_request: GitLab の Merge Request を含む）';
+COMMENT ON COLUMN discussions.number IS 'Issue・Pull Request の番号（GitLab は iid）';
+COMMENT ON COLUMN discussions.summary IS '説明文の冒頭';
+COMMENT ON COLUMN discussions.paths IS '議論で言及されたファイルパス（変更ファイル・レビューコメントの対象・本文中のパス）';
+COMMENT ON COLUMN discussions.last_activity_at IS '議論の最終更新日時';
+
 -- カバレッジマップ構築のためのsnapshot_filesテーブル
 -- 全ファイルリスト（インデックス対象外含む）を永続化して正確なカバレッジ率を計算可能にする
 CREATE TABLE IF NOT EXISTS snapshot_files (
@@ -279,6 +618,90 @@ CREATE INDEX IF NOT EXISTS idx_snapshot_files_snapshot ON snapshot_files(snapsho
 CREATE INDEX IF NOT EXISTS idx_snapshot_files_domain ON snapshot_files(domain);
 CREATE INDEX IF NOT EXISTS idx_snapshot_files_indexed ON snapshot_files(indexed);
 
+-- coverage_historyテーブル: スナップショットごとのドメイン別カバレッジの履歴
+CREATE TABLE IF NOT EXISTS coverage_history (
+    snapshot_id UUID NOT NULL REFERENCES source_snapshots(id) ON DELETE CASCADE,
+    domain VARCHAR(50) NOT NULL,              -- ドメイン分類（未分類は unknown）
+    total_files INTEGER NOT NULL,
+    indexed_files INTEGER NOT NULL,
+    indexed_chunks INTEGER NOT NULL,
+    coverage_rate NUMERIC(5, 2) NOT NULL,     -- カバレッジ率（%）
+    recorded_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (snapshot_id, domain)
+);
+
+CREATE INDEX IF NOT EXISTS idx_coverage_history_recorded_at ON coverage_history(recorded_at);
+
+COMMENT ON TABLE coverage_history IS 'スナップショットごとのドメイン別カバレッジの履歴（カバレッジの推移の表示に使用）';
+COMMENT ON COLUMN coverage_history.domain IS 'ドメイン分類（code, tests, ops, infra, architecture, 未分類は unknown）';
+COMMENT ON COLUMN coverage_history.total_files IS 'スナップショットのファイル数（インデックス対象外を含む）';
+COMMENT ON COLUMN coverage_history.indexed_files IS 'インデックス済みのファイル数';
+COMMENT ON COLUMN coverage_history.indexed_chunks IS 'インデックス済みのチャンク数';
+COMMENT ON COLUMN coverage_history.coverage_rate IS 'カバレッジ率（%）';
+COMMENT ON COLUMN coverage_history.recorded_at IS '記録日時（インデックス完了日時）';
+
+-- prompt_templatesテーブル: LLMプロンプトテンプレートの上書き（組み込みのテンプレートより優先する）
+CREATE TABLE IF NOT EXISTS prompt_templates (
+    name VARCHAR(100) PRIMARY KEY,            -- テンプレート名（例: summary.file）
+    version VARCHAR(10) NOT NULL,             -- 生成物に記録するバージョン
+    body TEXT NOT NULL,                       -- text/template 形式のテンプレート本文
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON TABLE prompt_templates IS 'LLMプロンプトテンプレートの上書き（ファイル・組み込みのテンプレートより優先する）';
+COMMENT ON COLUMN prompt_templates.name IS 'テンプレート名（summary.file, wiki.section, ask, quality.actions など）';
+COMMENT ON COLUMN prompt_templates.version IS '生成物（要約・アクション・Wiki・回答）に記録するプロンプトのバージョン';
+COMMENT ON COLUMN prompt_templates.body IS 'text/template 形式のテンプレート本文';
+
+-- llm_cacheテーブル: LLMの応答のキャッシュ（プロンプトテンプレートのバージョンと入力のハッシュをキーとする）
+CREATE TABLE IF NOT EXISTS llm_cache (
+    key CHAR(64) PRIMARY KEY,                 -- プロンプトテンプレート・モデル・プロンプトのハッシュ
+    prompt_name VARCHAR(100) NOT NULL,        -- プロンプトテンプレート名（例: summary.file）
+    prompt_version VARCHAR(10) NOT NULL,
+    model VARCHAR(100) NOT NULL,
+    response TEXT NOT NULL,
+    hit_count INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_hit_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_llm_cache_prompt ON llm_cache(prompt_name, prompt_version);
+CREATE INDEX IF NOT EXISTS idx_llm_cache_created_at ON llm_cache(created_at);
+
+COMMENT ON TABLE llm_cache IS 'LLMの応答のキャッシュ（同じ入力の要約・Wikiページの生成で再び課金されないようにする）';
+COMMENT ON COLUMN llm_cache.key IS 'プロンプトテンプレートの名前・バージョン、モデル、最大出力トークン数、展開したプロンプトの SHA-256';
+COMMENT ON COLUMN llm_cache.prompt_name IS 'プロンプトテンプレート名（summary.file, wiki.section など）';
+COMMENT ON COLUMN llm_cache.prompt_version IS 'プロンプトテンプレートのバージョン';
+COMMENT ON COLUMN llm_cache.model IS '応答を生成したモデル名';
+COMMENT ON COLUMN llm_cache.hit_count IS 'キャッシュした応答を使用した回数';
+COMMENT ON COLUMN llm_cache.last_hit_at IS '最後にキャッシュした応答を使用した日時';
+
+-- embedding_modelsテーブル: 使用を許可するEmbeddingモデルと出力するベクトルの次元数
+-- 起動時に設定したモデルとベクトルカラムの次元数を突き合わせ、不一致のままベクトルを保存しないようにする
+CREATE TABLE IF NOT EXISTS embedding_models (
+    model VARCHAR(100) PRIMARY KEY,            -- EMBEDDING_MODEL に指定するモデル名（Azure の場合はデプロイメント名）
+    provider VARCHAR(50) NOT NULL,             -- openai, azure, vertex, ollama
+    dimension INTEGER NOT NULL CHECK (dimension > 0),
+    variable_dimension BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON TABLE embedding_models IS '使用を許可するEmbeddingモデルと出力するベクトルの次元数';
+COMMENT ON COLUMN embedding_models.dimension IS 'モデルが出力するベクトルの次元数（variable_dimension の場合は最大の次元数）';
+COMMENT ON COLUMN embedding_models.variable_dimension IS 'dimension 以下の任意の次元数を指定して出力できるか（text-embedding-3 系など）';
+
+INSERT INTO embedding_models (model, provider, dimension, variable_dimension) VALUES
+    ('text-embedding-3-small', 'openai', 1536, TRUE),
+    ('text-embedding-3-large', 'openai', 3072, TRUE),
+    ('text-embedding-ada-002', 'openai', 1536, FALSE),
+    ('text-embedding-005', 'vertex', 768, TRUE),
+    ('text-multilingual-embedding-002', 'vertex', 768, TRUE),
+    ('gemini-embedding-001', 'vertex', 3072, TRUE),
+    ('nomic-embed-text', 'ollama', 768, FALSE),
+    ('mxbai-embed-large', 'ollama', 1024, FALSE),
+    ('bge-m3', 'ollama', 1024, FALSE)
+ON CONFLICT (model) DO NOTHING;
+
 -- 依存グラフの構築
 -- チャンク間の依存関係を管理するテーブル
 CREATE TABLE IF NOT EXISTS chunk_dependencies (
@@ -579,3 +1002,267 @@ CREATE INDEX IF NOT EXISTS idx_summary_embeddings_vector ON summary_embeddings
 USING ivfflat (vector vector_cosine_ops) WITH (lists = 100);
 
 COMMENT ON TABLE summary_embeddings IS '要約のEmbeddingベクトル';
+
+-- スナップショットごとのLLM/Embedding使用量テーブル
+CREATE TABLE IF NOT EXISTS snapshot_usage (
+    snapshot_id UUID NOT NULL REFERENCES source_snapshots(id) ON DELETE CASCADE,
+    kind VARCHAR(50) NOT NULL,
+    model VARCHAR(100) NOT NULL,
+    requests INTEGER NOT NULL DEFAULT 0,
+    input_tokens BIGINT NOT NULL DEFAULT 0,
+    output_tokens BIGINT NOT NULL DEFAULT 0,
+    cost_usd DOUBLE PRECISION NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (snapshot_id, kind, model)
+);
+
+COMMENT ON TABLE snapshot_usage IS 'スナップショットごとのLLM/Embedding使用量と推定コスト';
+COMMENT ON COLUMN snapshot_usage.kind IS '用途（embedding/summary/wiki/ask）';
+COMMENT ON COLUMN snapshot_usage.model IS '使用したモデル名';
+COMMENT ON COLUMN snapshot_usage.cost_usd IS '単価表から算出した推定コスト（USD）';
+
+-- ask_answersテーブル: チャット（Slack など）で回答した質問
+CREATE TABLE IF NOT EXISTS ask_answers (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    origin VARCHAR(20) NOT NULL,              -- 回答した経路（slack）
+    channel_id VARCHAR(100) NOT NULL,         -- 回答を投稿したチャンネル
+    message_ts VARCHAR(50) NOT NULL,          -- 回答のメッセージの識別子（Slack の ts）
+    user_id VARCHAR(100) NOT NULL,            -- 質問したユーザー
+    query TEXT NOT NULL,
+    answer TEXT NOT NULL,
+    refused BOOLEAN NOT NULL DEFAULT FALSE,
+    prompt_version VARCHAR(10) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_ask_answers_message UNIQUE (origin, channel_id, message_ts)
+);
+
+CREATE INDEX IF NOT EXISTS idx_ask_answers_product ON ask_answers(product_id, created_at);
+
+-- ask_feedbackテーブル: 回答へのリアクションによる評価
+CREATE TABLE IF NOT EXISTS ask_feedback (
+    answer_id UUID NOT NULL REFERENCES ask_answers(id) ON DELETE CASCADE,
+    user_id VARCHAR(100) NOT NULL,            -- 評価したユーザー
+    rating SMALLINT NOT NULL CHECK (rating IN (-1, 1)),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (answer_id, user_id)
+);
+
+COMMENT ON TABLE ask_answers IS 'チャットで回答した質問（リアクションによる評価の対象）';
+COMMENT ON COLUMN ask_answers.origin IS '回答した経路（slack）';
+COMMENT ON COLUMN ask_answers.channel_id IS '回答を投稿したチャンネルのID';
+COMMENT ON COLUMN ask_answers.message_ts IS '回答のメッセージの識別子（Slack のメッセージの ts）';
+COMMENT ON COLUMN ask_answers.user_id IS '質問したユーザーのID';
+COMMENT ON COLUMN ask_answers.refused IS 'ガードレールにより回答を拒否したかどうか';
+COMMENT ON COLUMN ask_answers.prompt_version IS '回答の生成に使用したプロンプトテンプレートのバージョン';
+COMMENT ON TABLE ask_feedback IS '回答へのユーザーの評価（👍 は 1、👎 は -1）';
+COMMENT ON COLUMN ask_feedback.rating IS '評価（1: 役に立った、-1: 役に立たなかった）';
+
+-- chunk_embeddingsテーブル: 追加のEmbeddingモデルで生成したチャンクのEmbedding
+CREATE TABLE IF NOT EXISTS chunk_embeddings (
+    chunk_id UUID NOT NULL REFERENCES chunks(id) ON DELETE CASCADE,
+    model VARCHAR(100) NOT NULL REFERENCES embedding_models(model),
+    vector VECTOR NOT NULL,                   -- 次元数はモデルごとに異なるため固定しない
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (chunk_id, model)
+);
+
+-- 次元数が固定されないためHNSWインデックスは作成できず、モデルで絞り込んだ全件の距離計算で検索する
+CREATE INDEX IF NOT EXISTS idx_chunk_embeddings_model ON chunk_embeddings(model);
+
+COMMENT ON TABLE chunk_embeddings IS '追加のEmbeddingモデルで生成したチャンクのEmbedding（主モデルは embeddings）';
+COMMENT ON COLUMN chunk_embeddings.model IS 'Embeddingの生成に使用したモデル（embedding_models に登録済み）';
+COMMENT ON COLUMN chunk_embeddings.vector IS 'Embeddingベクトル（次元数はモデルごとに異なる）';
+
+-- symbolsテーブル: チャンクのメタデータから抽出したシンボルの定義位置
+CREATE TABLE IF NOT EXISTS symbols (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    snapshot_id UUID NOT NULL REFERENCES source_snapshots(id) ON DELETE CASCADE,
+    chunk_id UUID NOT NULL REFERENCES chunks(id) ON DELETE CASCADE,
+    file_id UUID NOT NULL REFERENCES files(id) ON DELETE CASCADE,
+    file_path TEXT NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    kind VARCHAR(50) NOT NULL,                -- function / method / struct / interface など（chunks.chunk_type）
+    parent_name VARCHAR(255) NOT NULL DEFAULT '', -- メソッドのレシーバの型名など（ポインタの * は除く）
+    start_line INTEGER NOT NULL,
+    end_line INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_symbols_snapshot ON symbols(snapshot_id);
+CREATE INDEX IF NOT EXISTS idx_symbols_lower_name ON symbols(LOWER(name));
+
+COMMENT ON TABLE symbols IS 'チャンクのメタデータから抽出したシンボル（関数・メソッド・型など）の定義位置';
+COMMENT ON COLUMN symbols.name IS 'シンボル名（chunks.chunk_name）';
+COMMENT ON COLUMN symbols.kind IS 'シンボルの種類（chunks.chunk_type）';
+COMMENT ON COLUMN symbols.parent_name IS '所属する型名など（chunks.parent_name、ポインタの * は除く）';
+
+-- ask_cacheテーブル: 質問応答の回答のキャッシュ（正規化した質問文・検索範囲・プロンプトテンプレートのバージョンをキーとする）
+CREATE TABLE IF NOT EXISTS ask_cache (
+    key CHAR(64) PRIMARY KEY,                 -- 検索範囲・正規化した質問文・プロンプトのバージョン・モデルなどのハッシュ
+    product_id UUID REFERENCES products(id) ON DELETE CASCADE,
+    snapshot_id UUID REFERENCES source_snapshots(id) ON DELETE CASCADE, -- ref/snapshot 指定時のみ
+    scope_snapshot_ids UUID[] NOT NULL,       -- 保存時に検索対象だったスナップショット
+    prompt_version VARCHAR(10) NOT NULL,
+    question TEXT NOT NULL,                   -- 正規化した質問文
+    result JSONB NOT NULL,
+    hit_count INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_hit_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_ask_cache_product ON ask_cache(product_id);
+CREATE INDEX IF NOT EXISTS idx_ask_cache_created_at ON ask_cache(created_at);
+
+COMMENT ON TABLE ask_cache IS '質問応答の回答のキャッシュ（よくある質問で検索とLLMの呼び出しを繰り返さないようにする）';
+COMMENT ON COLUMN ask_cache.key IS '検索範囲・正規化した質問文・プロンプトテンプレートのバージョン・モデル・検索条件の SHA-256';
+COMMENT ON COLUMN ask_cache.scope_snapshot_ids IS '保存時に検索対象だったスナップショット（再インデックスで変わった回答は使用しない）';
+COMMENT ON COLUMN ask_cache.question IS '正規化した質問文（小文字化・空白の統一・末尾の句読点の除去）';
+COMMENT ON COLUMN ask_cache.result IS '回答・参照したソース・引用の検証結果（JSON）';
+COMMENT ON COLUMN ask_cache.hit_count IS 'キャッシュした回答を使用した回数';
+
+-- product_summariesテーブル: 各ソースのアーキテクチャ要約から生成したプロダクト全体の要約
+CREATE TABLE IF NOT EXISTS product_summaries (
+    product_id UUID PRIMARY KEY REFERENCES products(id) ON DELETE CASCADE,
+    content TEXT NOT NULL,                    -- 目的・技術スタック・主要なサービス
+    description TEXT NOT NULL,                -- 要約の冒頭の1文（products.description の自動設定に使用）
+    source_hash CHAR(64) NOT NULL,            -- 入力としたアーキテクチャ要約の content_hash から計算したハッシュ
+    prompt_version VARCHAR(10) NOT NULL,
+    vector VECTOR NOT NULL,                   -- 要約のEmbedding（次元数はモデルごとに異なる）
+    model VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON TABLE product_summaries IS 'プロダクト全体の要約（インデックス化のたびに各ソースのアーキテクチャ要約から生成し、質問の振り分けに使用する）';
+COMMENT ON COLUMN product_summaries.description IS '要約の冒頭の1文（products.description が未設定または前回の自動設定のままの場合に設定する）';
+COMMENT ON COLUMN product_summaries.source_hash IS '入力としたアーキテクチャ要約の content_hash から計算したハッシュ（変わらない場合は再生成しない）';
+COMMENT ON COLUMN product_summaries.vector IS '要約のEmbedding（振り分けでは現在の主モデルと同じ model の行のみ使用する）';
+
+-- file_test_coverageテーブル: スナップショットのファイルごとのテストカバレッジ
+CREATE TABLE IF NOT EXISTS file_test_coverage (
+    file_id UUID PRIMARY KEY REFERENCES files(id) ON DELETE CASCADE,
+    snapshot_id UUID NOT NULL REFERENCES source_snapshots(id) ON DELETE CASCADE,
+    statements INTEGER NOT NULL,              -- 計測対象のステートメント数（LCOV は行数）
+    covered_statements INTEGER NOT NULL,      -- テストで実行されたステートメント数
+    format VARCHAR(10) NOT NULL,              -- go / lcov
+    imported_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT chk_file_test_coverage_format CHECK (format IN ('go', 'lcov'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_file_test_coverage_snapshot ON file_test_coverage(snapshot_id);
+
+COMMENT ON TABLE file_test_coverage IS 'カバレッジレポートから取り込んだファイルごとのテストカバレッジ（スナップショット単位で置き換える）';
+COMMENT ON COLUMN file_test_coverage.statements IS '計測対象のステートメント数（LCOV は計測対象の行数）';
+COMMENT ON COLUMN file_test_coverage.covered_statements IS 'テストで1回以上実行されたステートメント数';
+
+-- chunk_test_coverageテーブル: 関数・クラス単位のチャンクごとのテストカバレッジ
+CREATE TABLE IF NOT EXISTS chunk_test_coverage (
+    chunk_id UUID PRIMARY KEY REFERENCES chunks(id) ON DELETE CASCADE,
+    statements INTEGER NOT NULL,
+    covered_statements INTEGER NOT NULL
+);
+
+COMMENT ON TABLE chunk_test_coverage IS '関数・クラス単位のチャンクごとのテストカバレッジ（計測単位の開始行がチャンクの行範囲に含まれるものを集計）';
+
+-- index_runsテーブル: インデックス化の実行ごとの結果
+CREATE TABLE IF NOT EXISTS index_runs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    source_id UUID NOT NULL REFERENCES sources(id) ON DELETE CASCADE,
+    snapshot_id UUID REFERENCES source_snapshots(id) ON DELETE SET NULL,
+    ref VARCHAR(255),                          -- インデックス化した参照（ブランチ・タグ、参照を持たないソースでは NULL）
+    version_identifier VARCHAR(255),           -- コミットハッシュなど（ドキュメントの取得前に失敗した場合は NULL）
+    status VARCHAR(20) NOT NULL DEFAULT 'running', -- running / succeeded / failed
+    force_init BOOLEAN NOT NULL DEFAULT FALSE,
+    files_processed INTEGER NOT NULL DEFAULT 0,
+    chunks INTEGER NOT NULL DEFAULT 0,
+    failed_files INTEGER NOT NULL DEFAULT 0,
+    failed_embeddings INTEGER NOT NULL DEFAULT 0,
+    input_tokens BIGINT NOT NULL DEFAULT 0,
+    output_tokens BIGINT NOT NULL DEFAULT 0,
+    cost_usd DOUBLE PRECISION NOT NULL DEFAULT 0,
+    error TEXT,
+    started_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMP,
+    duration_ms BIGINT,
+    CONSTRAINT chk_index_runs_status CHECK (status IN ('running', 'succeeded', 'failed'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_index_runs_product_started ON index_runs(product_id, started_at DESC);
+CREATE INDEX IF NOT EXISTS idx_index_runs_source_started ON index_runs(source_id, started_at DESC);
+
+COMMENT ON TABLE index_runs IS 'インデックス化の実行履歴（実行ごとの処理件数・失敗件数・トークン使用量）';
+COMMENT ON COLUMN index_runs.status IS '実行状態（running: 実行中または中断、succeeded: 成功、failed: 失敗）';
+COMMENT ON COLUMN index_runs.failed_files IS 'チャンク化・保存に失敗したファイル数';
+COMMENT ON COLUMN index_runs.failed_embeddings IS 'Embeddingの生成・保存に失敗したチャンク数（embedding_failures に記録したもの）';
+COMMENT ON COLUMN index_runs.input_tokens IS '実行中に消費した入力トークン数（Embedding・分類など、インデックス化後の要約生成は含まない）';
+COMMENT ON COLUMN index_runs.cost_usd IS '単価表から算出した推定コスト（USD）';
+
+-- file_failuresテーブル: インデックス化に失敗したファイル
+CREATE TABLE IF NOT EXISTS file_failures (
+    snapshot_id UUID NOT NULL REFERENCES source_snapshots(id) ON DELETE CASCADE,
+    file_path TEXT NOT NULL,
+    stage VARCHAR(20) NOT NULL,          -- create / chunk / store
+    error TEXT NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 1,
+    content TEXT NOT NULL,               -- 再試行に使用するファイルの内容
+    content_hash VARCHAR(64) NOT NULL,
+    size BIGINT NOT NULL DEFAULT 0,
+    first_failed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_failed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (snapshot_id, file_path),
+    CONSTRAINT chk_file_failures_stage CHECK (stage IN ('create', 'chunk', 'store'))
+);
+
+COMMENT ON TABLE file_failures IS 'インデックス化に失敗したファイル（failures retry で再試行する）';
+COMMENT ON COLUMN file_failures.stage IS '失敗した段階（create: ファイルの保存、chunk: チャンク化、store: チャンクの保存）';
+COMMENT ON COLUMN file_failures.error IS '最後に失敗したときのエラー';
+COMMENT ON COLUMN file_failures.attempts IS '失敗した回数（インデックス化の再開と再試行の合計）';
+COMMENT ON COLUMN file_failures.content IS '再試行に使用するファイルの内容';
+COMMENT ON COLUMN file_failures.first_failed_at IS '最初に失敗した日時';
+COMMENT ON COLUMN file_failures.last_failed_at IS '最後に失敗した日時';
+
+-- file_blobsテーブル: ファイルの内容（コンテンツアドレス方式）
+CREATE TABLE IF NOT EXISTS file_blobs (
+    content_hash VARCHAR(64) PRIMARY KEY,
+    content BYTEA NOT NULL,              -- バイナリ（PDF・Office文書）を含むため BYTEA で保存する
+    size BIGINT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON TABLE file_blobs IS 'ファイルの内容（content_hash ごとに1回だけ保存する）';
+COMMENT ON COLUMN file_blobs.content_hash IS 'files.content_hash と同じハッシュ';
+COMMENT ON COLUMN file_blobs.content IS 'ファイルの内容（原文）';
+COMMENT ON COLUMN file_blobs.size IS '内容のサイズ（バイト）';
+
+-- release_notesテーブル: 2つの ref の間の変更から生成したリリースノートの下書き
+CREATE TABLE IF NOT EXISTS release_notes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    source_id UUID NOT NULL REFERENCES sources(id) ON DELETE CASCADE,
+    from_ref VARCHAR(255) NOT NULL,       -- 比較元の ref（タグ・ブランチ・コミットハッシュ）
+    to_ref VARCHAR(255) NOT NULL,         -- 比較先の ref
+    from_commit VARCHAR(40) NOT NULL,
+    to_commit VARCHAR(40) NOT NULL,
+    released_at TIMESTAMP NOT NULL,       -- to のコミット日時
+    commit_count INTEGER NOT NULL,
+    file_count INTEGER NOT NULL,
+    content TEXT NOT NULL,                -- リリースノートの本文（Markdown）
+    prompt_version VARCHAR(10) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_release_notes_range UNIQUE (source_id, from_ref, to_ref)
+);
+
+CREATE INDEX IF NOT EXISTS idx_release_notes_source ON release_notes(source_id, released_at DESC);
+
+COMMENT ON TABLE release_notes IS '2つの ref の間の変更からLLMで生成したリリースノートの下書き';
+COMMENT ON COLUMN release_notes.from_ref IS '比較元の ref（タグ・ブランチ・コミットハッシュ）';
+COMMENT ON COLUMN release_notes.to_ref IS '比較先の ref（タグ・ブランチ・コミットハッシュ）';
+COMMENT ON COLUMN release_notes.from_commit IS 'from_ref が指していたコミットハッシュ';
+COMMENT ON COLUMN release_notes.to_commit IS 'to_ref が指していたコミットハッシュ';
+COMMENT ON COLUMN release_notes.released_at IS 'to のコミット日時（Wikiのページの並び順）';
+COMMENT ON COLUMN release_notes.commit_count IS '比較範囲のコミット数';
+COMMENT ON COLUMN release_notes.file_count IS '比較範囲で変更されたファイル数';
+COMMENT ON COLUMN release_notes.content IS 'リリースノートの本文（Markdown）';
+COMMENT ON COLUMN release_notes.prompt_version IS '生成に使用したプロンプトテンプレートのバージョン';